@@ -0,0 +1,173 @@
+// Package actions define o contrato das ações que o agent-orchestrator pede ao
+// canal da conversa (transferir, encerrar a chamada, executar uma tool). O
+// orchestrator só emite ações deste esquema e o voice-gateway valida cada uma
+// antes de executá-la, rejeitando tipos desconhecidos ou parâmetros inválidos.
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Type representa o tipo de uma ação
+type Type string
+
+const (
+	// TypeTransfer transfere o cliente para um humano
+	TypeTransfer Type = "transfer"
+	// TypeEndCall encerra a chamada
+	TypeEndCall Type = "end_call"
+	// TypeToolCall executa uma tool em nome do agente
+	TypeToolCall Type = "tool_call"
+)
+
+// Types lista todos os tipos de ação conhecidos
+var Types = []Type{TypeTransfer, TypeEndCall, TypeToolCall}
+
+// Tipos de transferência
+const (
+	TransferQueue    = "queue"
+	TransferAgent    = "agent"
+	TransferExternal = "external"
+)
+
+var (
+	// ErrUnknownType indica um tipo de ação fora do esquema
+	ErrUnknownType = errors.New("unknown action type")
+	// ErrInvalidParams indica parâmetros ausentes ou inválidos para o tipo
+	ErrInvalidParams = errors.New("invalid action parameters")
+)
+
+// toolNamePattern restringe os nomes de tool aceitos
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]{0,63}$`)
+
+// Action representa uma ação tipada. Apenas os parâmetros do seu tipo podem
+// estar preenchidos.
+type Action struct {
+	Type     Type            `json:"type"`
+	Transfer *TransferParams `json:"transfer,omitempty"`
+	EndCall  *EndCallParams  `json:"end_call,omitempty"`
+	ToolCall *ToolCallParams `json:"tool_call,omitempty"`
+}
+
+// TransferParams são os parâmetros de uma transferência. Sem destino, o canal
+// usa o seu destino padrão de escalonamento.
+type TransferParams struct {
+	TransferType string `json:"transfer_type,omitempty"` // queue, agent, external
+	Target       string `json:"target,omitempty"`
+	Reason       string `json:"reason"`
+	Initiated    bool   `json:"initiated"` // a transferência já foi pedida ao canal
+}
+
+// EndCallParams são os parâmetros de um encerramento de chamada
+type EndCallParams struct {
+	Reason string `json:"reason"`
+}
+
+// ToolCallParams são os parâmetros de uma chamada de tool
+type ToolCallParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"` // objeto JSON
+}
+
+// Transfer cria uma ação de transferência
+func Transfer(p TransferParams) *Action {
+	return &Action{Type: TypeTransfer, Transfer: &p}
+}
+
+// EndCall cria uma ação de encerramento de chamada
+func EndCall(reason string) *Action {
+	return &Action{Type: TypeEndCall, EndCall: &EndCallParams{Reason: reason}}
+}
+
+// ToolCall cria uma ação de chamada de tool
+func ToolCall(name string, arguments json.RawMessage) *Action {
+	return &Action{Type: TypeToolCall, ToolCall: &ToolCallParams{Name: name, Arguments: arguments}}
+}
+
+// Validate verifica se a ação segue o esquema: tipo conhecido, parâmetros do
+// próprio tipo presentes e válidos, e nenhum parâmetro de outro tipo.
+func (a *Action) Validate() error {
+	if a == nil {
+		return fmt.Errorf("%w: empty action", ErrInvalidParams)
+	}
+
+	set := 0
+	for _, p := range []bool{a.Transfer != nil, a.EndCall != nil, a.ToolCall != nil} {
+		if p {
+			set++
+		}
+	}
+
+	var err error
+	switch a.Type {
+	case TypeTransfer:
+		if a.Transfer == nil {
+			return missing(a.Type)
+		}
+		err = a.Transfer.validate()
+	case TypeEndCall:
+		if a.EndCall == nil {
+			return missing(a.Type)
+		}
+		err = a.EndCall.validate()
+	case TypeToolCall:
+		if a.ToolCall == nil {
+			return missing(a.Type)
+		}
+		err = a.ToolCall.validate()
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownType, a.Type)
+	}
+	if err != nil {
+		return err
+	}
+	if set > 1 {
+		return fmt.Errorf("%w: %s with parameters of another type", ErrInvalidParams, a.Type)
+	}
+	return nil
+}
+
+func (p *TransferParams) validate() error {
+	switch p.TransferType {
+	case "", TransferQueue, TransferAgent, TransferExternal:
+	default:
+		return fmt.Errorf("%w: unknown transfer_type %q", ErrInvalidParams, p.TransferType)
+	}
+	if p.Reason == "" {
+		return fmt.Errorf("%w: transfer without reason", ErrInvalidParams)
+	}
+	if p.TransferType != "" && p.Target == "" {
+		return fmt.Errorf("%w: transfer_type %s without target", ErrInvalidParams, p.TransferType)
+	}
+	if p.Initiated && p.Target == "" {
+		return fmt.Errorf("%w: initiated transfer without target", ErrInvalidParams)
+	}
+	return nil
+}
+
+func (p *EndCallParams) validate() error {
+	if p.Reason == "" {
+		return fmt.Errorf("%w: end_call without reason", ErrInvalidParams)
+	}
+	return nil
+}
+
+func (p *ToolCallParams) validate() error {
+	if !toolNamePattern.MatchString(p.Name) {
+		return fmt.Errorf("%w: invalid tool name %q", ErrInvalidParams, p.Name)
+	}
+	if len(p.Arguments) > 0 {
+		var args map[string]json.RawMessage
+		if err := json.Unmarshal(p.Arguments, &args); err != nil || args == nil {
+			return fmt.Errorf("%w: arguments of tool %s is not a JSON object", ErrInvalidParams, p.Name)
+		}
+	}
+	return nil
+}
+
+func missing(t Type) error {
+	return fmt.Errorf("%w: %s without parameters", ErrInvalidParams, t)
+}
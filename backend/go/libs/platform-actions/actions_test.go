@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestValidate_Transfer(t *testing.T) {
+	tests := []struct {
+		name   string
+		params TransferParams
+		err    error
+	}{
+		{"fila", TransferParams{TransferType: TransferQueue, Target: "support", Reason: "intent: speak_to_human", Initiated: true}, nil},
+		{"ramal externo", TransferParams{TransferType: TransferExternal, Target: "+5511999990000", Reason: "forbidden_topic"}, nil},
+		{"destino padrão", TransferParams{Reason: "moderation"}, nil},
+		{"sem reason", TransferParams{TransferType: TransferAgent, Target: "ana"}, ErrInvalidParams},
+		{"tipo desconhecido", TransferParams{TransferType: "sip", Target: "x", Reason: "r"}, ErrInvalidParams},
+		{"tipo sem target", TransferParams{TransferType: TransferQueue, Reason: "r"}, ErrInvalidParams},
+		{"iniciada sem target", TransferParams{Reason: "r", Initiated: true}, ErrInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Transfer(tt.params).Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidate_EndCall(t *testing.T) {
+	if err := EndCall("max_turns").Validate(); err != nil {
+		t.Errorf("Validate() = %v, esperado nil", err)
+	}
+	if err := EndCall("").Validate(); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Validate() = %v, esperado ErrInvalidParams", err)
+	}
+}
+
+func TestValidate_ToolCall(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      string
+		arguments string
+		err       error
+	}{
+		{"com argumentos", "calendar.book", `{"date":"2026-10-16","slot":"09:00"}`, nil},
+		{"sem argumentos", "lookup_order", "", nil},
+		{"nome vazio", "", `{}`, ErrInvalidParams},
+		{"nome inválido", "rm -rf", `{}`, ErrInvalidParams},
+		{"argumentos não objeto", "lookup_order", `["a"]`, ErrInvalidParams},
+		{"argumentos null", "lookup_order", `null`, ErrInvalidParams},
+		{"argumentos malformados", "lookup_order", `{"a":`, ErrInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args json.RawMessage
+			if tt.arguments != "" {
+				args = json.RawMessage(tt.arguments)
+			}
+			if err := ToolCall(tt.tool, args).Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidate_Schema(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{"tipo desconhecido", `{"type":"launch_missiles"}`, ErrUnknownType},
+		{"tipo vazio", `{"transfer":{"reason":"r"}}`, ErrUnknownType},
+		{"sem parâmetros", `{"type":"transfer"}`, ErrInvalidParams},
+		{"parâmetros de outro tipo", `{"type":"end_call","transfer":{"reason":"r"}}`, ErrInvalidParams},
+		{"parâmetros extras", `{"type":"end_call","end_call":{"reason":"r"},"tool_call":{"name":"x"}}`, ErrInvalidParams},
+		{"válida", `{"type":"end_call","end_call":{"reason":"inactivity"}}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Action
+			if err := json.Unmarshal([]byte(tt.body), &a); err != nil {
+				t.Fatalf("Unmarshal falhou: %v", err)
+			}
+			if err := a.Validate(); !errors.Is(err, tt.err) {
+				t.Errorf("Validate() = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+
+	var nilAction *Action
+	if err := nilAction.Validate(); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Validate() de ação nil = %v, esperado ErrInvalidParams", err)
+	}
+}
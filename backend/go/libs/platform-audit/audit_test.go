@@ -0,0 +1,240 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	type tenant struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Plan   string `json:"plan,omitempty"`
+	}
+
+	changes := Diff(
+		tenant{Name: "Acme", Status: "active"},
+		tenant{Name: "Acme", Status: "suspended", Plan: "starter"},
+	)
+
+	if len(changes) != 2 {
+		t.Fatalf("Esperadas 2 alterações, obtidas %d: %v", len(changes), changes)
+	}
+	if string(changes["status"].Before) != `"active"` || string(changes["status"].After) != `"suspended"` {
+		t.Errorf("Alteração de status inesperada: %+v", changes["status"])
+	}
+	if changes["plan"].Before != nil || string(changes["plan"].After) != `"starter"` {
+		t.Errorf("Campo criado deveria ter apenas After: %+v", changes["plan"])
+	}
+	if _, ok := changes["name"]; ok {
+		t.Error("Campos iguais não deveriam aparecer no diff")
+	}
+
+	if Diff(tenant{Name: "x"}, tenant{Name: "x"}) != nil {
+		t.Error("Diff sem alterações deveria ser nil")
+	}
+	if removed := Diff(tenant{Name: "x"}, nil); removed["name"].After != nil {
+		t.Errorf("Campo removido deveria ter apenas Before: %+v", removed["name"])
+	}
+}
+
+func TestLoggerRecord(t *testing.T) {
+	store := NewMemoryStore()
+	logger := NewLogger(store, Options{})
+
+	ctx := WithActor(context.Background(), Actor{ID: "user-1", Role: "admin"})
+	logger.Record(ctx, Entry{
+		Action:     ActionTenantSuspend,
+		TargetType: "tenant",
+		TargetID:   "tenant-1",
+		TenantID:   "tenant-1",
+	})
+
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatalf("Close falhou: %v", err)
+	}
+
+	entries, _ := store.Query(context.Background(), Filter{})
+	if len(entries) != 1 {
+		t.Fatalf("Esperada 1 entrada, obtidas %d", len(entries))
+	}
+	e := entries[0]
+	if e.ID == "" || e.Timestamp.IsZero() {
+		t.Errorf("ID e Timestamp deveriam ser preenchidos: %+v", e)
+	}
+	if e.Actor != "user-1" || e.ActorRole != "admin" {
+		t.Errorf("Ator deveria vir do contexto: %+v", e)
+	}
+
+	// Após Close, Record não deve entrar em pânico
+	logger.Record(ctx, Entry{Action: ActionTenantDelete})
+
+	var nilLogger *Logger
+	nilLogger.Record(ctx, Entry{Action: ActionTenantDelete})
+}
+
+func TestLoggerRecordImpersonatedActor(t *testing.T) {
+	store := NewMemoryStore()
+	logger := NewLogger(store, Options{})
+
+	metadata := map[string]string{"reason": "ticket #42"}
+	ctx := WithActor(context.Background(), Actor{ID: "user-1", Role: "admin", ImpersonatedBy: "support-1"})
+	logger.Record(ctx, Entry{Action: ActionUserRoleChange, TargetType: "user", TargetID: "user-2", Metadata: metadata})
+
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatalf("Close falhou: %v", err)
+	}
+
+	entries, _ := store.Query(context.Background(), Filter{})
+	if len(entries) != 1 {
+		t.Fatalf("Esperada 1 entrada, obtidas %d", len(entries))
+	}
+	if got := entries[0].Metadata[MetadataImpersonatedBy]; got != "support-1" {
+		t.Errorf("impersonated_by = %q, esperado support-1", got)
+	}
+	if entries[0].Metadata["reason"] != "ticket #42" {
+		t.Errorf("Metadata original deveria ser mantido: %v", entries[0].Metadata)
+	}
+	if _, ok := metadata[MetadataImpersonatedBy]; ok {
+		t.Error("O mapa de Metadata do chamador não deveria ser alterado")
+	}
+}
+
+// blockingStore segura Append até release ser fechado
+type blockingStore struct {
+	MemoryStore
+	release chan struct{}
+}
+
+func (s *blockingStore) Append(ctx context.Context, entry Entry) error {
+	<-s.release
+	return s.MemoryStore.Append(ctx, entry)
+}
+
+func TestLoggerNeverBlocks(t *testing.T) {
+	store := &blockingStore{release: make(chan struct{})}
+
+	var mu sync.Mutex
+	var dropped int
+	logger := NewLogger(store, Options{
+		BufferSize: 1,
+		OnError: func(_ Entry, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, ErrBufferFull) {
+				dropped++
+			}
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			logger.Record(context.Background(), Entry{Action: ActionUserRoleChange})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record bloqueou com o store lento")
+	}
+
+	close(store.release)
+	logger.Close(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if dropped == 0 {
+		t.Error("Entradas excedentes deveriam ser reportadas como descartadas")
+	}
+}
+
+func TestLoggerReportsStoreErrors(t *testing.T) {
+	failing := errors.New("connection refused")
+	errs := make(chan error, 1)
+	logger := NewLogger(failingStore{err: failing}, Options{
+		OnError: func(_ Entry, err error) { errs <- err },
+	})
+
+	logger.Record(context.Background(), Entry{Action: ActionAPIKeyRevoke})
+	logger.Close(context.Background())
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, failing) {
+			t.Errorf("Erro inesperado: %v", err)
+		}
+	default:
+		t.Error("Falha do store deveria chegar em OnError")
+	}
+}
+
+type failingStore struct{ err error }
+
+func (s failingStore) Append(context.Context, Entry) error { return s.err }
+
+func (s failingStore) Query(context.Context, Filter) ([]Entry, error) { return nil, s.err }
+
+func TestParseFilter(t *testing.T) {
+	f, err := ParseFilter(url.Values{
+		"tenant_id": {"t1"},
+		"actor":     {"u1"},
+		"from":      {"2026-01-01T00:00:00Z"},
+		"limit":     {"5000"},
+	})
+	if err != nil {
+		t.Fatalf("ParseFilter falhou: %v", err)
+	}
+	if f.TenantID != "t1" || f.Actor != "u1" || f.From.Year() != 2026 {
+		t.Errorf("Filtro inesperado: %+v", f)
+	}
+	if f.Limit != MaxLimit {
+		t.Errorf("Limit deveria ser limitado a %d, obtido %d", MaxLimit, f.Limit)
+	}
+
+	for _, values := range []url.Values{
+		{"limit": {"0"}},
+		{"to": {"ontem"}},
+	} {
+		if _, err := ParseFilter(values); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("ParseFilter(%v) deveria retornar ErrInvalidFilter, obtido %v", values, err)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	store := NewMemoryStore()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.Append(context.Background(), Entry{ID: "1", Actor: "u1", TenantID: "t1", Timestamp: base})
+	store.Append(context.Background(), Entry{ID: "2", Actor: "u2", TenantID: "t1", Timestamp: base.Add(time.Hour)})
+	store.Append(context.Background(), Entry{ID: "3", Actor: "u1", TenantID: "t2", Timestamp: base.Add(2 * time.Hour)})
+
+	rec := httptest.NewRecorder()
+	Handler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit?tenant_id=t1&actor=u1", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status inesperado: %d", rec.Code)
+	}
+	var resp QueryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Resposta inválida: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != "1" {
+		t.Errorf("Esperada apenas a entrada 1, obtido %+v", resp.Items)
+	}
+
+	rec = httptest.NewRecorder()
+	Handler(store).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit?limit=abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Filtro inválido deveria retornar 400, obtido %d", rec.Code)
+	}
+}
@@ -0,0 +1,131 @@
+// Package audit registra ações privilegiadas (suspender tenant, alterar papel,
+// revogar chaves, purgar dados) em um armazenamento append-only para revisão
+// de compliance: quem fez, o quê, sobre qual alvo, em qual tenant, quando e
+// com qual diferença entre o estado anterior e o posterior.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Ações privilegiadas registradas pelos serviços
+const (
+	ActionTenantSuspend       = "tenant.suspend"
+	ActionTenantActivate      = "tenant.activate"
+	ActionTenantDelete        = "tenant.delete"
+	ActionTenantPlanChange    = "tenant.plan_change"
+	ActionUserRoleChange      = "user.role_change"
+	ActionSessionsRevoke      = "user.sessions_revoke"
+	ActionAPIKeyRevoke        = "api_key.revoke"
+	ActionTenantDataPurge     = "tenant.purge"
+	ActionUserImpersonate     = "user.impersonate"
+	ActionServiceClientCreate = "service_client.create"
+	ActionServiceClientRevoke = "service_client.revoke"
+	ActionUserInvite          = "user.invite"
+	ActionUserDeactivate      = "user.deactivate"
+	ActionUserReactivate      = "user.reactivate"
+)
+
+// Entry é um registro de auditoria imutável
+type Entry struct {
+	ID         string            `json:"id"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Actor      string            `json:"actor"`
+	ActorRole  string            `json:"actor_role,omitempty"`
+	Action     string            `json:"action"`
+	TargetType string            `json:"target_type"`
+	TargetID   string            `json:"target_id"`
+	TenantID   string            `json:"tenant_id,omitempty"`
+	Changes    map[string]Change `json:"changes,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// Change guarda o valor de um campo antes e depois da ação, em JSON.
+// Before vazio indica campo criado; After vazio, campo removido.
+type Change struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Diff compara before e after campo a campo (pela serialização JSON de
+// primeiro nível) e retorna apenas os campos alterados. Valores que não
+// serializam como objeto JSON (ou nil) são tratados como objeto vazio.
+func Diff(before, after any) map[string]Change {
+	b := fields(before)
+	a := fields(after)
+
+	changes := make(map[string]Change)
+	for key, old := range b {
+		if cur, ok := a[key]; !ok || !bytes.Equal(old, cur) {
+			changes[key] = Change{Before: old, After: cur}
+		}
+	}
+	for key, cur := range a {
+		if _, ok := b[key]; !ok {
+			changes[key] = Change{After: cur}
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// fields serializa v e retorna seus campos de primeiro nível
+func fields(v any) map[string]json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// Actor identifica quem executa a ação. ImpersonatedBy é o ID do superadmin
+// quando a requisição usa um token de impersonação.
+type Actor struct {
+	ID             string
+	Role           string
+	TenantID       string
+	ImpersonatedBy string
+}
+
+// MetadataImpersonatedBy é a chave de Metadata com o ID de quem impersonava o
+// ator
+const MetadataImpersonatedBy = "impersonated_by"
+
+// actorKey é a chave do Actor no contexto
+type actorKey struct{}
+
+// WithActor retorna um contexto com o ator da requisição, usado por
+// Logger.Record quando a entrada não informa Actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext retorna o ator gravado por WithActor
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+// newID gera um UUID v4 para a entrada
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,3 @@
+module github.com/serphona/serphona/backend/go/libs/platform-audit
+
+go 1.21
@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// QueryResponse é o corpo de resposta de GET /api/v1/audit
+type QueryResponse struct {
+	Items []Entry `json:"items"`
+}
+
+// Handler expõe a consulta de auditoria (GET /api/v1/audit) com os filtros de
+// ParseFilter. Não faz autorização: o serviço deve protegê-lo para papéis de
+// administração e, se necessário, restringir tenant_id ao tenant do ator.
+func Handler(store Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter, err := ParseFilter(r.URL.Query())
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error":   "invalid_filter",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		ServeQuery(w, r, store, filter)
+	})
+}
+
+// ServeQuery executa a consulta e escreve a resposta. Serviços que ajustam o
+// filtro antes da consulta (ex.: forçar o tenant do ator) chamam ServeQuery
+// diretamente após ParseFilter.
+func ServeQuery(w http.ResponseWriter, r *http.Request, store Store, filter Filter) {
+	entries, err := store.Query(r.Context(), filter)
+	if err != nil {
+		status, code := http.StatusInternalServerError, "internal_error"
+		if errors.Is(err, ErrInvalidFilter) {
+			status, code = http.StatusBadRequest, "invalid_filter"
+		}
+		writeJSON(w, status, map[string]string{
+			"error":   code,
+			"message": "failed to query audit log",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, QueryResponse{Items: entries})
+}
+
+// writeJSON escreve data como JSON com o status informado
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBufferFull indica que a entrada foi descartada porque o buffer estava cheio
+var ErrBufferFull = errors.New("audit buffer full")
+
+const (
+	// DefaultBufferSize é a capacidade padrão da fila de entradas pendentes
+	DefaultBufferSize = 1024
+	// DefaultWriteTimeout é o prazo padrão de cada gravação no Store
+	DefaultWriteTimeout = 5 * time.Second
+)
+
+// Options configura o Logger
+type Options struct {
+	// BufferSize é a capacidade da fila; padrão DefaultBufferSize
+	BufferSize int
+	// WriteTimeout limita cada Append; padrão DefaultWriteTimeout
+	WriteTimeout time.Duration
+	// OnError é chamado quando uma entrada não pôde ser gravada ou foi
+	// descartada. Use para logar a falha; a ação principal nunca é afetada.
+	OnError func(entry Entry, err error)
+}
+
+// Logger grava entradas de auditoria de forma assíncrona e best-effort:
+// Record nunca bloqueia nem falha a ação auditada. Falhas de gravação e
+// descartes por buffer cheio são reportados em Options.OnError.
+type Logger struct {
+	store   Store
+	opts    Options
+	entries chan Entry
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewLogger cria um Logger e inicia a goroutine que grava no store.
+// Chame Close no desligamento para gravar as entradas pendentes.
+func NewLogger(store Store, opts Options) *Logger {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = DefaultBufferSize
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = DefaultWriteTimeout
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(Entry, error) {}
+	}
+
+	l := &Logger{
+		store:   store,
+		opts:    opts,
+		entries: make(chan Entry, opts.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Record enfileira a entrada para gravação. ID e Timestamp são preenchidos se
+// vazios, e Actor/ActorRole vêm de WithActor quando não informados (com
+// impersonated_by em Metadata se o ator estiver sendo impersonado). Um Logger
+// nil ignora a chamada, para que serviços sem auditoria configurada funcionem.
+func (l *Logger) Record(ctx context.Context, entry Entry) {
+	if l == nil {
+		return
+	}
+
+	if entry.ID == "" {
+		entry.ID = newID()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	if entry.Actor == "" {
+		if actor, ok := ActorFromContext(ctx); ok {
+			entry.Actor = actor.ID
+			entry.ActorRole = actor.Role
+			if actor.ImpersonatedBy != "" {
+				entry.Metadata = withMetadata(entry.Metadata, MetadataImpersonatedBy, actor.ImpersonatedBy)
+			}
+		}
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		l.opts.OnError(entry, errors.New("audit logger closed"))
+		return
+	}
+
+	select {
+	case l.entries <- entry:
+	default:
+		l.opts.OnError(entry, ErrBufferFull)
+	}
+}
+
+// Close para de aceitar entradas e espera a gravação das pendentes até o
+// prazo de ctx.
+func (l *Logger) Close(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.entries)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run grava as entradas da fila, desacoplado do contexto da requisição
+func (l *Logger) run() {
+	defer close(l.done)
+	for entry := range l.entries {
+		ctx, cancel := context.WithTimeout(context.Background(), l.opts.WriteTimeout)
+		if err := l.store.Append(ctx, entry); err != nil {
+			l.opts.OnError(entry, err)
+		}
+		cancel()
+	}
+}
+
+// withMetadata retorna uma cópia de metadata com key=value, sem alterar o mapa
+// do chamador
+func withMetadata(metadata map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
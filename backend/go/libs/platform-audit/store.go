@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLimit é o número de entradas retornado quando limit não é informado
+	DefaultLimit = 100
+	// MaxLimit é o maior número de entradas retornado por consulta
+	MaxLimit = 1000
+)
+
+// ErrInvalidFilter indica parâmetros de consulta inválidos
+var ErrInvalidFilter = errors.New("invalid audit filter")
+
+// Store é o armazenamento append-only das entradas. Implementações não devem
+// oferecer atualização nem remoção.
+type Store interface {
+	// Append grava uma nova entrada
+	Append(ctx context.Context, entry Entry) error
+	// Query retorna as entradas que casam com o filtro, das mais recentes
+	// para as mais antigas
+	Query(ctx context.Context, filter Filter) ([]Entry, error)
+}
+
+// Filter restringe uma consulta. Campos vazios não filtram.
+type Filter struct {
+	TenantID string
+	Actor    string
+	Action   string
+	TargetID string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+// Matches informa se a entrada satisfaz o filtro (exceto Limit)
+func (f Filter) Matches(e Entry) bool {
+	switch {
+	case f.TenantID != "" && e.TenantID != f.TenantID:
+		return false
+	case f.Actor != "" && e.Actor != f.Actor:
+		return false
+	case f.Action != "" && e.Action != f.Action:
+		return false
+	case f.TargetID != "" && e.TargetID != f.TargetID:
+		return false
+	case !f.From.IsZero() && e.Timestamp.Before(f.From):
+		return false
+	case !f.To.IsZero() && !e.Timestamp.Before(f.To):
+		return false
+	}
+	return true
+}
+
+// ParseFilter lê tenant_id, actor, action, target_id, from, to (RFC 3339) e
+// limit da query string. Limit fica entre 1 e MaxLimit, com DefaultLimit
+// quando omitido.
+func ParseFilter(values url.Values) (Filter, error) {
+	f := Filter{
+		TenantID: values.Get("tenant_id"),
+		Actor:    values.Get("actor"),
+		Action:   values.Get("action"),
+		TargetID: values.Get("target_id"),
+		Limit:    DefaultLimit,
+	}
+
+	var err error
+	if f.From, err = parseTime(values, "from"); err != nil {
+		return Filter{}, err
+	}
+	if f.To, err = parseTime(values, "to"); err != nil {
+		return Filter{}, err
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return Filter{}, fmt.Errorf("%w: limit must be a positive integer", ErrInvalidFilter)
+		}
+		f.Limit = min(limit, MaxLimit)
+	}
+
+	return f, nil
+}
+
+// parseTime lê um parâmetro RFC 3339 opcional
+func parseTime(values url.Values, key string) (time.Time, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", ErrInvalidFilter, key)
+	}
+	return t, nil
+}
+
+// MemoryStore é um Store em memória, para testes e desenvolvimento local
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append grava a entrada no fim do log
+func (s *MemoryStore) Append(_ context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Query percorre o log do fim para o início aplicando o filtro
+func (s *MemoryStore) Query(_ context.Context, filter Filter) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+
+	result := make([]Entry, 0)
+	for i := len(s.entries) - 1; i >= 0 && len(result) < limit; i-- {
+		if filter.Matches(s.entries[i]) {
+			result = append(result, s.entries[i])
+		}
+	}
+	return result, nil
+}
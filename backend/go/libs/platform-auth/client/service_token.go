@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
+)
+
+// tokenRefreshMargin renova o token de serviço antes de ele expirar, para
+// que uma chamada em andamento não chegue com o token vencido
+const tokenRefreshMargin = 30 * time.Second
+
+// ServiceTokenSource obtém tokens de serviço do auth-gateway via client
+// credentials e os reaproveita até perto da expiração. É seguro para uso
+// concorrente.
+type ServiceTokenSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scope        string
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewServiceTokenSource cria um ServiceTokenSource para o auth-gateway em
+// baseURL. Sem escopos, o token recebe todos os escopos do cliente.
+func NewServiceTokenSource(baseURL, clientID, clientSecret string, scopes ...string) *ServiceTokenSource {
+	return &ServiceTokenSource{
+		tokenURL:     strings.TrimSuffix(baseURL, "/") + "/api/v1/oauth/token",
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scope:        strings.Join(scopes, " "),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Token retorna um token de serviço válido, buscando um novo quando o atual
+// está para expirar
+func (s *ServiceTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiresAt.Add(-tokenRefreshMargin)) {
+		return s.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if s.scope != "" {
+		form.Set("scope", s.scope)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.clientID, s.clientSecret)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", autherrors.ErrInvalidCredentials
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+
+	s.token = tokenResp.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// Transport retorna um http.RoundTripper que envia o token de serviço no
+// header Authorization de cada requisição. base nil usa
+// http.DefaultTransport.
+func (s *ServiceTokenSource) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &serviceTokenTransport{source: s, base: base}
+}
+
+// serviceTokenTransport injeta o token de serviço nas requisições
+type serviceTokenTransport struct {
+	source *ServiceTokenSource
+	base   http.RoundTripper
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *serviceTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain service token: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
@@ -33,6 +33,12 @@ var (
 
 	// ErrInvalidRole indica que a role é inválida
 	ErrInvalidRole = errors.New("invalid role")
+
+	// ErrInsufficientScope indica que o token de serviço não concede o escopo exigido
+	ErrInsufficientScope = errors.New("insufficient scope")
+
+	// ErrInvalidAudience indica que o token não foi emitido para este serviço
+	ErrInvalidAudience = errors.New("invalid token audience")
 )
 
 // AuthError representa um erro de autenticação com código e mensagem
@@ -76,4 +82,9 @@ const (
 	CodeUserInactive            = "USER_INACTIVE"
 	CodeUserNotVerified         = "USER_NOT_VERIFIED"
 	CodeInvalidRole             = "INVALID_ROLE"
+	CodeImpersonationForbidden  = "IMPERSONATION_FORBIDDEN"
+	CodeInsufficientScope       = "INSUFFICIENT_SCOPE"
+	CodeInvalidAudience         = "INVALID_AUDIENCE"
+	CodeTenantSuspended         = "TENANT_SUSPENDED"
+	CodeTenantStatusUnavailable = "TENANT_STATUS_UNAVAILABLE"
 )
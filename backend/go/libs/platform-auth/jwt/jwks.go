@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Intervalos padrão do cache de chaves do JWKSClient
+const (
+	// DefaultJWKSMaxAge é a idade máxima do cache antes de buscar de novo
+	DefaultJWKSMaxAge = time.Hour
+	// DefaultJWKSMinRefresh é o intervalo mínimo entre buscas disparadas por
+	// um kid desconhecido, para que tokens forjados não virem carga no
+	// auth-gateway
+	DefaultJWKSMinRefresh = time.Minute
+)
+
+// publicKey é uma chave do JWKS já decodificada
+type publicKey struct {
+	alg string
+	key interface{}
+}
+
+// JWKSClient busca e mantém em cache as chaves públicas publicadas pelo
+// auth-gateway em /.well-known/jwks.json. Um kid desconhecido (chave recém
+// rotacionada) força nova busca, respeitando DefaultJWKSMinRefresh.
+type JWKSClient struct {
+	url        string
+	httpClient *http.Client
+	maxAge     time.Duration
+	minRefresh time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+	now       func() time.Time
+}
+
+// NewJWKSClient cria um cliente para a URL do JWKS, ex.:
+// http://auth-gateway:8080/.well-known/jwks.json. httpClient nil usa um
+// cliente com timeout de 10s.
+func NewJWKSClient(url string, httpClient *http.Client) *JWKSClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &JWKSClient{
+		url:        url,
+		httpClient: httpClient,
+		maxAge:     DefaultJWKSMaxAge,
+		minRefresh: DefaultJWKSMinRefresh,
+		keys:       map[string]publicKey{},
+		now:        time.Now,
+	}
+}
+
+// key retorna a chave pública do kid e seu algoritmo
+func (c *JWKSClient) key(ctx context.Context, kid string) (publicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, ok := c.keys[kid]
+	age := c.now().Sub(c.fetchedAt)
+
+	if age >= c.maxAge || (!ok && age >= c.minRefresh) {
+		if err := c.refresh(ctx); err != nil && !ok {
+			return publicKey{}, err
+		}
+		key, ok = c.keys[kid]
+	}
+
+	if !ok {
+		return publicKey{}, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// refresh busca o JWKS e substitui o cache. Chaves de tipo ou algoritmo não
+// suportado são ignoradas.
+func (c *JWKSClient) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: unexpected status code: %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if pub, ok := k.publicKey(); ok && k.Kid != "" {
+			keys[k.Kid] = pub
+		}
+	}
+
+	c.keys = keys
+	c.fetchedAt = c.now()
+	return nil
+}
+
+// jwk é uma chave pública no formato JSON Web Key (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey decodifica a chave, exigindo que alg e tipo de chave combinem
+func (k jwk) publicKey() (publicKey, bool) {
+	switch {
+	case k.Kty == "RSA" && k.Alg == "RS256":
+		n, errN := decodeBigInt(k.N)
+		e, errE := decodeBigInt(k.E)
+		if errN != nil || errE != nil || !e.IsInt64() {
+			return publicKey{}, false
+		}
+		return publicKey{alg: k.Alg, key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, true
+
+	case k.Kty == "EC" && k.Alg == "ES256" && k.Crv == "P-256":
+		x, errX := decodeBigInt(k.X)
+		y, errY := decodeBigInt(k.Y)
+		if errX != nil || errY != nil || !elliptic.P256().IsOnCurve(x, y) {
+			return publicKey{}, false
+		}
+		return publicKey{alg: k.Alg, key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, true
+	}
+
+	return publicKey{}, false
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -1,15 +1,18 @@
 package jwt
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
 	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
 	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
 )
 
-var jwtSecret string
+var (
+	jwtSecret string
+	verifier  *Verifier
+)
 
 // SetSecret configura o secret JWT para validação
 func SetSecret(secret string) {
@@ -21,8 +24,18 @@ func GetSecret() string {
 	return jwtSecret
 }
 
+// SetVerifier configura o Verifier usado por ValidateToken no lugar do
+// secret, para validar tokens assimétricos via JWKS
+func SetVerifier(v *Verifier) {
+	verifier = v
+}
+
 // ValidateToken valida um token JWT e retorna as claims
 func ValidateToken(tokenString string) (*types.Claims, error) {
+	if verifier != nil {
+		return verifier.Validate(context.Background(), tokenString)
+	}
+
 	if jwtSecret == "" {
 		return nil, fmt.Errorf("JWT secret not configured")
 	}
@@ -35,30 +48,28 @@ func ValidateTokenWithSecret(tokenString, secret string) (*types.Claims, error)
 	if tokenString == "" {
 		return nil, autherrors.ErrMissingToken
 	}
+	if secret == "" {
+		return nil, fmt.Errorf("JWT secret not configured")
+	}
 
-	// Parse e valida o token
-	token, err := jwt.ParseWithClaims(tokenString, &types.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verifica o método de assinatura
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+	return (&Verifier{secret: []byte(secret)}).Validate(context.Background(), tokenString)
+}
 
-	if err != nil {
-		// Verifica se o token expirou
-		if strings.Contains(err.Error(), "token is expired") {
-			return nil, autherrors.ErrTokenExpired
-		}
-		return nil, autherrors.ErrInvalidToken
+// ValidateServiceToken valida um token de serviço com o Verifier configurado
+// ou, na falta dele, com o secret
+func ValidateServiceToken(tokenString string) (*types.ServiceClaims, error) {
+	if verifier != nil {
+		return verifier.ValidateService(context.Background(), tokenString)
 	}
 
-	// Extrai as claims
-	if claims, ok := token.Claims.(*types.Claims); ok && token.Valid {
-		return claims, nil
+	if tokenString == "" {
+		return nil, autherrors.ErrMissingToken
+	}
+	if jwtSecret == "" {
+		return nil, fmt.Errorf("JWT secret not configured")
 	}
 
-	return nil, autherrors.ErrInvalidToken
+	return (&Verifier{secret: []byte(jwtSecret)}).ValidateService(context.Background(), tokenString)
 }
 
 // ExtractTokenFromHeader extrai o token do header Authorization
@@ -91,3 +102,14 @@ func ValidateTokenFromHeader(authHeader string) (*types.Claims, error) {
 
 	return ValidateToken(token)
 }
+
+// ValidateServiceTokenFromHeader valida um token de serviço extraído do
+// header Authorization
+func ValidateServiceTokenFromHeader(authHeader string) (*types.ServiceClaims, error) {
+	token, err := ExtractTokenFromHeader(authHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateServiceToken(token)
+}
@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
+)
+
+// Verifier valida tokens HS256 com o secret compartilhado e/ou RS256 e ES256
+// com as chaves públicas do JWKS. O algoritmo do token só escolhe a fonte da
+// chave: tokens HS256 nunca são verificados com uma chave pública e tokens
+// assimétricos precisam usar exatamente o algoritmo da chave do seu kid.
+type Verifier struct {
+	secret   []byte
+	jwks     *JWKSClient
+	audience string
+}
+
+// VerifierOption configura um Verifier
+type VerifierOption func(*Verifier)
+
+// WithHMACSecret aceita tokens HS256 assinados com secret
+func WithHMACSecret(secret string) VerifierOption {
+	return func(v *Verifier) { v.secret = []byte(secret) }
+}
+
+// WithJWKS aceita tokens RS256 e ES256 assinados por chaves do JWKS
+func WithJWKS(client *JWKSClient) VerifierOption {
+	return func(v *Verifier) { v.jwks = client }
+}
+
+// WithAudience exige que o claim aud dos tokens inclua audience (ex.: o nome
+// do serviço), para que um token emitido para outro serviço não seja aceito
+// aqui. Sem esta opção o aud não é verificado.
+func WithAudience(audience string) VerifierOption {
+	return func(v *Verifier) { v.audience = audience }
+}
+
+// NewVerifier cria um Verifier. Durante a migração de HS256 para RS256 use as
+// duas opções, até os tokens HS256 expirarem.
+func NewVerifier(opts ...VerifierOption) (*Verifier, error) {
+	v := &Verifier{}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if len(v.secret) == 0 && v.jwks == nil {
+		return nil, errors.New("verifier needs an HMAC secret or a JWKS client")
+	}
+	return v, nil
+}
+
+// Validate valida um token de usuário e retorna as claims. Tokens de serviço
+// são recusados: não agem como nenhum usuário.
+func (v *Verifier) Validate(ctx context.Context, tokenString string) (*types.Claims, error) {
+	claims := &types.Claims{}
+	if err := v.parse(ctx, tokenString, claims); err != nil {
+		return nil, err
+	}
+	if types.IsServiceSubject(claims.Subject) {
+		return nil, autherrors.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// ValidateService valida um token de serviço (client credentials) e retorna
+// as claims. Tokens de usuário são recusados.
+func (v *Verifier) ValidateService(ctx context.Context, tokenString string) (*types.ServiceClaims, error) {
+	claims := &types.ServiceClaims{}
+	if err := v.parse(ctx, tokenString, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// parse verifica a assinatura e as claims do token
+func (v *Verifier) parse(ctx context.Context, tokenString string, claims jwt.Claims) error {
+	if tokenString == "" {
+		return autherrors.ErrMissingToken
+	}
+
+	var methods []string
+	if len(v.secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if v.jwks != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods)}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return v.keyFor(ctx, token)
+	}, options...)
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return autherrors.ErrTokenExpired
+		}
+		// aud é o único claim obrigatório: ausente conta como de outro serviço
+		if errors.Is(err, jwt.ErrTokenInvalidAudience) || errors.Is(err, jwt.ErrTokenRequiredClaimMissing) {
+			return autherrors.ErrInvalidAudience
+		}
+		return autherrors.ErrInvalidToken
+	}
+	if !token.Valid {
+		return autherrors.ErrInvalidToken
+	}
+
+	return nil
+}
+
+// keyFor escolhe a chave de verificação do token
+func (v *Verifier) keyFor(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	if alg == jwt.SigningMethodHS256.Alg() {
+		return v.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("%s token without kid", alg)
+	}
+
+	key, err := v.jwks.key(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.alg != alg {
+		return nil, fmt.Errorf("key %q is %s, token is %s", kid, key.alg, alg)
+	}
+	return key.key, nil
+}
@@ -0,0 +1,323 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
+)
+
+const testSecret = "test-secret-with-at-least-32-chars!!"
+
+// jwksServer publica as chaves no formato do auth-gateway e conta as buscas
+type jwksServer struct {
+	*httptest.Server
+	keys    []jwk
+	fetches atomic.Int32
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string][]jwk{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) addRSA(kid string, pub *rsa.PublicKey) {
+	s.keys = append(s.keys, jwk{
+		Kty: "RSA", Kid: kid, Alg: "RS256",
+		N: b64(pub.N.Bytes()), E: b64(big.NewInt(int64(pub.E)).Bytes()),
+	})
+}
+
+func (s *jwksServer) addEC(kid string, pub *ecdsa.PublicKey) {
+	s.keys = append(s.keys, jwk{
+		Kty: "EC", Kid: kid, Alg: "ES256", Crv: "P-256",
+		X: b64(pub.X.FillBytes(make([]byte, 32))), Y: b64(pub.Y.FillBytes(make([]byte, 32))),
+	})
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, ttl time.Duration) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, types.Claims{
+		UserID: "5f0c6d5e-7d2b-4a8f-9b1e-2c3d4e5f6a7b",
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	return key
+}
+
+func TestVerifier_HMACRejectsRSToken(t *testing.T) {
+	v, err := NewVerifier(WithHMACSecret(testSecret))
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	hs := sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), time.Hour)
+	if claims, err := v.Validate(context.Background(), hs); err != nil || claims.Role != "admin" {
+		t.Fatalf("token HS256 deveria ser aceito: claims %+v, erro %v", claims, err)
+	}
+
+	rs := sign(t, jwt.SigningMethodRS256, "rsa-1", newRSAKey(t), time.Hour)
+	if _, err := v.Validate(context.Background(), rs); !errors.Is(err, autherrors.ErrInvalidToken) {
+		t.Errorf("verifier HS256 aceitou token RS256: %v", err)
+	}
+}
+
+func TestVerifier_JWKSRejectsHSToken(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	server := newJWKSServer(t)
+	server.addRSA("rsa-1", &rsaKey.PublicKey)
+
+	v, err := NewVerifier(WithJWKS(NewJWKSClient(server.URL, nil)))
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	rs := sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, time.Hour)
+	if _, err := v.Validate(context.Background(), rs); err != nil {
+		t.Fatalf("token RS256 deveria ser aceito: %v", err)
+	}
+
+	// Ataque clássico: HS256 assinado com a chave pública, que é pública
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	for name, token := range map[string]string{
+		"HS256 com a chave pública":    sign(t, jwt.SigningMethodHS256, "rsa-1", pubPEM, time.Hour),
+		"HS256 com o módulo RSA":       sign(t, jwt.SigningMethodHS256, "rsa-1", rsaKey.N.Bytes(), time.Hour),
+		"HS256 sem secret no verifier": sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), time.Hour),
+	} {
+		if _, err := v.Validate(context.Background(), token); !errors.Is(err, autherrors.ErrInvalidToken) {
+			t.Errorf("%s: verifier JWKS aceitou o token: %v", name, err)
+		}
+	}
+}
+
+func TestVerifier_KidMustMatchKeyAlgorithm(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+	rsaKey := newRSAKey(t)
+
+	server := newJWKSServer(t)
+	server.addEC("ec-1", &ecKey.PublicKey)
+	server.addRSA("rsa-1", &rsaKey.PublicKey)
+
+	v, _ := NewVerifier(WithJWKS(NewJWKSClient(server.URL, nil)))
+
+	es := sign(t, jwt.SigningMethodES256, "ec-1", ecKey, time.Hour)
+	if _, err := v.Validate(context.Background(), es); err != nil {
+		t.Fatalf("token ES256 deveria ser aceito: %v", err)
+	}
+
+	// Token RS256 apontando para o kid de uma chave EC
+	mismatched := sign(t, jwt.SigningMethodRS256, "ec-1", rsaKey, time.Hour)
+	if _, err := v.Validate(context.Background(), mismatched); !errors.Is(err, autherrors.ErrInvalidToken) {
+		t.Errorf("token com algoritmo diferente da chave do kid foi aceito: %v", err)
+	}
+}
+
+func TestVerifier_AcceptsBothDuringMigration(t *testing.T) {
+	rsaKey := newRSAKey(t)
+	server := newJWKSServer(t)
+	server.addRSA("rsa-1", &rsaKey.PublicKey)
+
+	v, _ := NewVerifier(WithHMACSecret(testSecret), WithJWKS(NewJWKSClient(server.URL, nil)))
+
+	for name, token := range map[string]string{
+		"HS256": sign(t, jwt.SigningMethodHS256, "primary", []byte(testSecret), time.Hour),
+		"RS256": sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, time.Hour),
+	} {
+		if _, err := v.Validate(context.Background(), token); err != nil {
+			t.Errorf("%s deveria ser aceito: %v", name, err)
+		}
+	}
+
+	expired := sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, -time.Minute)
+	if _, err := v.Validate(context.Background(), expired); !errors.Is(err, autherrors.ErrTokenExpired) {
+		t.Errorf("esperado ErrTokenExpired, obtido %v", err)
+	}
+}
+
+func TestJWKSClient_RefetchesOnUnknownKid(t *testing.T) {
+	oldKey, newKey := newRSAKey(t), newRSAKey(t)
+	server := newJWKSServer(t)
+	server.addRSA("2026-04", &oldKey.PublicKey)
+
+	client := NewJWKSClient(server.URL, nil)
+	now := time.Now()
+	client.now = func() time.Time { return now }
+	v, _ := NewVerifier(WithJWKS(client))
+
+	if _, err := v.Validate(context.Background(), sign(t, jwt.SigningMethodRS256, "2026-04", oldKey, time.Hour)); err != nil {
+		t.Fatalf("token da chave atual deveria ser aceito: %v", err)
+	}
+
+	// auth-gateway rotaciona a chave
+	server.addRSA("2026-10", &newKey.PublicKey)
+	rotated := sign(t, jwt.SigningMethodRS256, "2026-10", newKey, time.Hour)
+
+	// Dentro do intervalo mínimo o kid desconhecido não dispara nova busca
+	if _, err := v.Validate(context.Background(), rotated); err == nil {
+		t.Fatal("kid desconhecido não deveria ser aceito antes do intervalo mínimo")
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Fatalf("buscas = %d, esperado 1", got)
+	}
+
+	now = now.Add(DefaultJWKSMinRefresh)
+	if _, err := v.Validate(context.Background(), rotated); err != nil {
+		t.Fatalf("token da chave nova deveria ser aceito após nova busca: %v", err)
+	}
+	if got := server.fetches.Load(); got != 2 {
+		t.Errorf("buscas = %d, esperado 2", got)
+	}
+}
+
+func TestNewVerifier_RequiresKeySource(t *testing.T) {
+	if _, err := NewVerifier(); err == nil {
+		t.Error("Verifier sem secret nem JWKS deveria ser rejeitado")
+	}
+}
+
+func signService(t *testing.T, subject, scope string, ttl time.Duration) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, types.ServiceClaims{
+		Scope: scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	})
+
+	signed, err := token.SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestVerifier_ServiceAndUserTokensAreNotInterchangeable(t *testing.T) {
+	v, err := NewVerifier(WithHMACSecret(testSecret))
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	service := signService(t, "service:voice-gateway", "agent-orchestrator:turns.write", time.Hour)
+	claims, err := v.ValidateService(context.Background(), service)
+	if err != nil {
+		t.Fatalf("token de serviço deveria ser aceito: %v", err)
+	}
+	if claims.Service() != "voice-gateway" || !claims.HasScope("agent-orchestrator:turns.write") {
+		t.Errorf("claims inesperadas: %+v", claims)
+	}
+	if _, err := v.Validate(context.Background(), service); !errors.Is(err, autherrors.ErrInvalidToken) {
+		t.Errorf("token de serviço aceito como token de usuário: %v", err)
+	}
+
+	user := sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), time.Hour)
+	if _, err := v.ValidateService(context.Background(), user); !errors.Is(err, autherrors.ErrInvalidToken) {
+		t.Errorf("token de usuário aceito como token de serviço: %v", err)
+	}
+
+	expired := signService(t, "service:voice-gateway", "agent-orchestrator:turns.write", -time.Minute)
+	if _, err := v.ValidateService(context.Background(), expired); !errors.Is(err, autherrors.ErrTokenExpired) {
+		t.Errorf("esperado ErrTokenExpired, obtido %v", err)
+	}
+}
+
+func signAudience(t *testing.T, aud ...string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, types.Claims{
+		UserID: "5f0c6d5e-7d2b-4a8f-9b1e-2c3d4e5f6a7b",
+		Role:   "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  aud,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	signed, err := token.SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestVerifier_WithAudienceRejectsOtherAudiences(t *testing.T) {
+	v, err := NewVerifier(WithHMACSecret(testSecret), WithAudience("voice-gateway"))
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"outro serviço": signAudience(t, "frontend"),
+		"sem aud":       signAudience(t),
+	} {
+		if _, err := v.Validate(context.Background(), token); !errors.Is(err, autherrors.ErrInvalidAudience) {
+			t.Errorf("%s: esperado ErrInvalidAudience, obtido %v", name, err)
+		}
+	}
+
+	if _, err := v.Validate(context.Background(), signAudience(t, "serphona-api", "voice-gateway")); err != nil {
+		t.Errorf("token com o aud do serviço deveria ser aceito: %v", err)
+	}
+
+	// Sem WithAudience o aud não é verificado
+	open, _ := NewVerifier(WithHMACSecret(testSecret))
+	if _, err := open.Validate(context.Background(), signAudience(t, "frontend")); err != nil {
+		t.Errorf("Verifier sem audience deveria aceitar qualquer aud: %v", err)
+	}
+}
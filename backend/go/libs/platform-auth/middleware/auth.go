@@ -4,13 +4,36 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
 	authjwt "github.com/serphona/serphona/backend/go/libs/platform-auth/jwt"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
 	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
 )
 
+// AuthOption configura RequireAuth
+type AuthOption func(*authOptions)
+
+type authOptions struct {
+	tenantStatus *tenantstatus.Cache
+}
+
+// WithTenantStatus recusa com 403 tokens de tenants suspensos ou removidos,
+// que continuam válidos até expirar. Se o status não puder ser consultado e
+// o cache não aceitar falhas (tenantstatus.Config.FailOpen), responde 503.
+func WithTenantStatus(cache *tenantstatus.Cache) AuthOption {
+	return func(o *authOptions) {
+		o.tenantStatus = cache
+	}
+}
+
 // RequireAuth é um middleware que valida JWT e injeta claims no contexto
-func RequireAuth() gin.HandlerFunc {
+func RequireAuth(opts ...AuthOption) gin.HandlerFunc {
+	var options authOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return func(c *gin.Context) {
 		// Extrai token do header Authorization
 		authHeader := c.GetHeader("Authorization")
@@ -33,6 +56,9 @@ func RequireAuth() gin.HandlerFunc {
 			case autherrors.ErrTokenExpired:
 				errorCode = autherrors.CodeTokenExpired
 				errorMessage = "Authentication token has expired"
+			case autherrors.ErrInvalidAudience:
+				errorCode = autherrors.CodeInvalidAudience
+				errorMessage = "Authentication token is not meant for this service"
 			}
 
 			c.JSON(statusCode, gin.H{
@@ -46,6 +72,37 @@ func RequireAuth() gin.HandlerFunc {
 		// Verifica se o usuário está ativo
 		// (esta validação pode ser feita aqui ou no auth-gateway)
 
+		if options.tenantStatus != nil {
+			tenantID, err := uuid.Parse(claims.TenantID)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "Invalid authentication token",
+					"code":  autherrors.CodeInvalidToken,
+				})
+				c.Abort()
+				return
+			}
+
+			blocked, err := options.tenantStatus.Blocked(c.Request.Context(), tenantID)
+			if blocked {
+				statusCode := http.StatusForbidden
+				errorCode := autherrors.CodeTenantSuspended
+				errorMessage := "Tenant is suspended"
+				if err != nil {
+					statusCode = http.StatusServiceUnavailable
+					errorCode = autherrors.CodeTenantStatusUnavailable
+					errorMessage = "Tenant status is unavailable"
+				}
+
+				c.JSON(statusCode, gin.H{
+					"error": errorMessage,
+					"code":  errorCode,
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		// Injeta claims no contexto
 		c.Set("claims", claims)
 		c.Set("userID", claims.UserID)
@@ -54,6 +111,9 @@ func RequireAuth() gin.HandlerFunc {
 		c.Set("role", claims.Role)
 		c.Set("tenantID", claims.TenantID)
 		c.Set("sessionID", claims.SessionID)
+		if claims.IsImpersonated() {
+			c.Set("impersonatedBy", claims.ImpersonatedBy)
+		}
 
 		c.Next()
 	}
@@ -139,6 +199,67 @@ func RequireSuperAdmin() gin.HandlerFunc {
 	}
 }
 
+// DenyImpersonated bloqueia operações destrutivas para tokens de impersonação.
+// Deve ser registrado depois de RequireAuth.
+func DenyImpersonated() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := GetClaimsFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+				"code":  autherrors.CodeUnauthorized,
+			})
+			c.Abort()
+			return
+		}
+
+		if claims.IsImpersonated() {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Operation not allowed while impersonating",
+				"code":  autherrors.CodeImpersonationForbidden,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireAudience exige que o token, de usuário (RequireAuth) ou de serviço
+// (RequireScope), tenha sido emitido para audience. Serve para proteger rotas
+// específicas; para o serviço inteiro, use authjwt.WithAudience no Verifier.
+func RequireAudience(audience string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var aud []string
+		if claims, err := GetClaimsFromContext(c); err == nil {
+			aud = claims.Audience
+		} else if claims, err := GetServiceClaimsFromContext(c); err == nil {
+			aud = claims.Audience
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+				"code":  autherrors.CodeUnauthorized,
+			})
+			c.Abort()
+			return
+		}
+
+		for _, a := range aud {
+			if a == audience {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Authentication token is not meant for this service",
+			"code":  autherrors.CodeInvalidAudience,
+		})
+		c.Abort()
+	}
+}
+
 // GetClaimsFromContext extrai as claims do contexto da request
 func GetClaimsFromContext(c *gin.Context) (*types.Claims, error) {
 	claimsValue, exists := c.Get("claims")
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
+	authjwt "github.com/serphona/serphona/backend/go/libs/platform-auth/jwt"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
+)
+
+// RequireScope protege endpoints internos: exige um token de serviço
+// (client credentials do auth-gateway) que conceda todos os escopos
+// informados. Tokens de usuário são recusados com 401. Injeta as claims em
+// "serviceClaims" e o nome do serviço chamador em "service".
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := authjwt.ValidateServiceTokenFromHeader(c.GetHeader("Authorization"))
+		if err != nil {
+			errorCode := autherrors.CodeInvalidToken
+			errorMessage := "Invalid service token"
+
+			switch err {
+			case autherrors.ErrMissingToken:
+				errorCode = autherrors.CodeMissingToken
+				errorMessage = "Missing service token"
+			case autherrors.ErrTokenExpired:
+				errorCode = autherrors.CodeTokenExpired
+				errorMessage = "Service token has expired"
+			case autherrors.ErrInvalidAudience:
+				errorCode = autherrors.CodeInvalidAudience
+				errorMessage = "Service token is not meant for this service"
+			}
+
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": errorMessage,
+				"code":  errorCode,
+			})
+			c.Abort()
+			return
+		}
+
+		if missing := claims.MissingScopes(scopes...); len(missing) > 0 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Service token lacks required scope: " + strings.Join(missing, " "),
+				"code":  autherrors.CodeInsufficientScope,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("serviceClaims", claims)
+		c.Set("service", claims.Service())
+
+		c.Next()
+	}
+}
+
+// GetServiceClaimsFromContext extrai as claims de serviço do contexto
+// (injetadas por RequireScope)
+func GetServiceClaimsFromContext(c *gin.Context) (*types.ServiceClaims, error) {
+	claimsValue, exists := c.Get("serviceClaims")
+	if !exists {
+		return nil, autherrors.ErrUnauthorized
+	}
+
+	claims, ok := claimsValue.(*types.ServiceClaims)
+	if !ok {
+		return nil, autherrors.ErrUnauthorized
+	}
+
+	return claims, nil
+}
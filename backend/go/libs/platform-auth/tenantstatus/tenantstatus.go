@@ -0,0 +1,88 @@
+// Package tenantstatus diz se um tenant pode usar a plataforma, consultando
+// o status dele no tenant-manager. Os middlewares de autenticação o usam para
+// recusar tokens ainda válidos de tenants suspensos ou removidos.
+package tenantstatus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status de um tenant no tenant-manager. Um tenant que o tenant-manager não
+// retorna mais foi removido.
+const (
+	StatusActive    = "active"
+	StatusPending   = "pending"
+	StatusSuspended = "suspended"
+	StatusDeleted   = "deleted"
+)
+
+// Source consulta o status de um tenant
+type Source interface {
+	TenantStatus(ctx context.Context, tenantID uuid.UUID) (string, error)
+}
+
+// Config configura um Cache
+type Config struct {
+	// TTL é por quanto tempo um status é reaproveitado; uma suspensão passa
+	// a valer dentro dele
+	TTL time.Duration
+
+	// FailOpen aceita o tenant quando o status não pode ser consultado, para
+	// que o tenant-manager fora do ar não bloqueie todos os tenants. Sem ele o
+	// tenant é recusado até a consulta voltar a funcionar.
+	FailOpen bool
+}
+
+// Cache responde se um tenant está bloqueado, guardando cada status por
+// Config.TTL para que uma request não custe uma consulta
+type Cache struct {
+	source Source
+	config Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]cachedStatus
+}
+
+// cachedStatus é um status consultado e quando ele expira
+type cachedStatus struct {
+	status    string
+	expiresAt time.Time
+}
+
+// NewCache cria um Cache sobre source
+func NewCache(source Source, config Config) *Cache {
+	return &Cache{
+		source:  source,
+		config:  config,
+		now:     time.Now,
+		entries: make(map[uuid.UUID]cachedStatus),
+	}
+}
+
+// Blocked informa se o tenant está suspenso ou removido. Quando a consulta
+// falha, o erro é retornado com a decisão de Config.FailOpen e não é
+// guardado, para que a próxima request consulte de novo.
+func (c *Cache) Blocked(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[tenantID]
+	c.mu.Unlock()
+
+	if !ok || c.now().After(entry.expiresAt) {
+		status, err := c.source.TenantStatus(ctx, tenantID)
+		if err != nil {
+			return !c.config.FailOpen, err
+		}
+		entry = cachedStatus{status: status, expiresAt: c.now().Add(c.config.TTL)}
+
+		c.mu.Lock()
+		c.entries[tenantID] = entry
+		c.mu.Unlock()
+	}
+
+	return entry.status == StatusSuspended || entry.status == StatusDeleted, nil
+}
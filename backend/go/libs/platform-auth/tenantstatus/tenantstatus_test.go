@@ -0,0 +1,92 @@
+package tenantstatus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeSource responde status fixos e conta as consultas
+type fakeSource struct {
+	statuses map[uuid.UUID]string
+	err      error
+	calls    int
+}
+
+func (f *fakeSource) TenantStatus(_ context.Context, tenantID uuid.UUID) (string, error) {
+	f.calls++
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.statuses[tenantID], nil
+}
+
+func TestCache_Blocked(t *testing.T) {
+	active, suspended, deleted := uuid.New(), uuid.New(), uuid.New()
+	source := &fakeSource{statuses: map[uuid.UUID]string{
+		active:    StatusActive,
+		suspended: StatusSuspended,
+		deleted:   StatusDeleted,
+	}}
+	cache := NewCache(source, Config{TTL: time.Minute})
+
+	for tenantID, want := range map[uuid.UUID]bool{active: false, suspended: true, deleted: true} {
+		blocked, err := cache.Blocked(context.Background(), tenantID)
+		if err != nil {
+			t.Fatalf("Blocked: %v", err)
+		}
+		if blocked != want {
+			t.Errorf("Blocked(%s) = %v, esperado %v", source.statuses[tenantID], blocked, want)
+		}
+	}
+}
+
+func TestCache_ReusesStatusUntilTTL(t *testing.T) {
+	tenantID := uuid.New()
+	source := &fakeSource{statuses: map[uuid.UUID]string{tenantID: StatusActive}}
+	cache := NewCache(source, Config{TTL: time.Minute})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.Blocked(context.Background(), tenantID)
+	source.statuses[tenantID] = StatusSuspended
+
+	if blocked, _ := cache.Blocked(context.Background(), tenantID); blocked || source.calls != 1 {
+		t.Errorf("status dentro do TTL deveria vir do cache: bloqueado %v, %d consultas", blocked, source.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if blocked, _ := cache.Blocked(context.Background(), tenantID); !blocked || source.calls != 2 {
+		t.Errorf("suspensão deveria valer depois do TTL: bloqueado %v, %d consultas", blocked, source.calls)
+	}
+}
+
+func TestCache_LookupFailure(t *testing.T) {
+	down := errors.New("connection refused")
+
+	for _, failOpen := range []bool{true, false} {
+		source := &fakeSource{err: down}
+		cache := NewCache(source, Config{TTL: time.Minute, FailOpen: failOpen})
+
+		blocked, err := cache.Blocked(context.Background(), uuid.New())
+		if !errors.Is(err, down) {
+			t.Errorf("FailOpen %v: erro = %v, esperado %v", failOpen, err, down)
+		}
+		if blocked == failOpen {
+			t.Errorf("FailOpen %v: bloqueado = %v", failOpen, blocked)
+		}
+	}
+
+	// Falhas não são guardadas: a próxima request consulta de novo
+	tenantID := uuid.New()
+	source := &fakeSource{err: down, statuses: map[uuid.UUID]string{tenantID: StatusActive}}
+	cache := NewCache(source, Config{TTL: time.Minute})
+	cache.Blocked(context.Background(), tenantID)
+	source.err = nil
+	if blocked, err := cache.Blocked(context.Background(), tenantID); blocked || err != nil || source.calls != 2 {
+		t.Errorf("consulta após falha: bloqueado %v, erro %v, %d consultas", blocked, err, source.calls)
+	}
+}
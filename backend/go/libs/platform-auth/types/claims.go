@@ -13,6 +13,9 @@ type Claims struct {
 	Role      string `json:"role"` // user, admin, superadmin
 	TenantID  string `json:"tenantId"`
 	SessionID string `json:"sessionId"`
+	// ImpersonatedBy é o ID do superadmin que emitiu o token agindo como o
+	// usuário (suporte); vazio em tokens normais
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -65,3 +68,8 @@ func (c *Claims) IsAdmin() bool {
 func (c *Claims) IsSuperAdmin() bool {
 	return c.Role == "superadmin"
 }
+
+// IsImpersonated verifica se o token foi emitido por impersonação
+func (c *Claims) IsImpersonated() bool {
+	return c.ImpersonatedBy != ""
+}
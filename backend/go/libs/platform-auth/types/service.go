@@ -0,0 +1,64 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ServiceSubjectPrefix prefixa o subject dos tokens de serviço, seguido do
+// nome do serviço (ex.: "service:voice-gateway")
+const ServiceSubjectPrefix = "service:"
+
+// ServiceClaims representa as claims de um token de serviço, emitido pelo
+// auth-gateway via client credentials para chamadas entre serviços. Scope é
+// delimitado por espaços.
+type ServiceClaims struct {
+	Scope string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// Validate recusa tokens sem subject de serviço, como tokens de usuário.
+// Chamado pelo parser do jwt depois das validações de tempo.
+func (c *ServiceClaims) Validate() error {
+	if !IsServiceSubject(c.Subject) || c.Service() == "" {
+		return jwt.ErrTokenInvalidClaims
+	}
+	return nil
+}
+
+// Service retorna o nome do serviço dono do token
+func (c *ServiceClaims) Service() string {
+	return strings.TrimPrefix(c.Subject, ServiceSubjectPrefix)
+}
+
+// Scopes retorna os escopos concedidos
+func (c *ServiceClaims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope verifica se o token concede o escopo
+func (c *ServiceClaims) HasScope(scope string) bool {
+	for _, s := range c.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingScopes retorna os escopos exigidos que o token não concede
+func (c *ServiceClaims) MissingScopes(required ...string) []string {
+	var missing []string
+	for _, s := range required {
+		if !c.HasScope(s) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// IsServiceSubject verifica se o subject identifica um serviço
+func IsServiceSubject(subject string) bool {
+	return strings.HasPrefix(subject, ServiceSubjectPrefix)
+}
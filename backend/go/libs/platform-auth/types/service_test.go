@@ -0,0 +1,38 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServiceClaims_MissingScopes(t *testing.T) {
+	claims := &ServiceClaims{Scope: "agent-orchestrator:sessions.write agent-orchestrator:turns.write"}
+	claims.Subject = "service:voice-gateway"
+
+	if missing := claims.MissingScopes("agent-orchestrator:turns.write"); len(missing) != 0 {
+		t.Errorf("escopo concedido reportado como ausente: %v", missing)
+	}
+	if missing := claims.MissingScopes(); len(missing) != 0 {
+		t.Errorf("nenhum escopo exigido deveria passar, obtido %v", missing)
+	}
+
+	missing := claims.MissingScopes("agent-orchestrator:turns.write", "billing:invoices.write", "agent-orchestrator:turns")
+	if want := []string{"billing:invoices.write", "agent-orchestrator:turns"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("ausentes = %v, esperado %v", missing, want)
+	}
+}
+
+func TestServiceClaims_ValidateRequiresServiceSubject(t *testing.T) {
+	for subject, valid := range map[string]bool{
+		"service:voice-gateway":                true,
+		"service:":                             false,
+		"5f0c6d5e-7d2b-4a8f-9b1e-2c3d4e5f6a7b": false,
+		"":                                     false,
+	} {
+		claims := &ServiceClaims{}
+		claims.Subject = subject
+		if err := claims.Validate(); (err == nil) != valid {
+			t.Errorf("subject %q: válido = %v, erro %v", subject, valid, err)
+		}
+	}
+}
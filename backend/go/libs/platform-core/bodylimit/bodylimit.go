@@ -0,0 +1,153 @@
+// Package bodylimit limita o tamanho do corpo das requisições, para que um
+// POST grande demais não esgote a memória do serviço. O limite vale para o
+// serviço todo e pode ser trocado por rota (prefixo do caminho). Corpos acima
+// do limite recebem 413 antes de o handler rodar: um Content-Length maior que
+// o limite é recusado na hora, e um corpo sem Content-Length (chunked) é lido
+// até o limite antes de seguir.
+package bodylimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBytes é o limite quando Config.MaxBytes é zero
+const DefaultMaxBytes int64 = 1 << 20
+
+// Config define os limites de um Limiter
+type Config struct {
+	// MaxBytes é o limite das rotas sem limite próprio; zero usa
+	// DefaultMaxBytes e um valor negativo desativa o limite
+	MaxBytes int64
+	// Routes define limites por prefixo de caminho, como "/api/v1/tools";
+	// vale o prefixo mais longo. Um valor negativo desativa o limite da rota,
+	// para uploads lidos em streaming
+	Routes map[string]int64
+}
+
+// Limiter aplica os limites de uma Config
+type Limiter struct {
+	maxBytes int64
+	routes   map[string]int64
+	prefixes []string // do mais longo para o mais curto
+}
+
+// New cria um Limiter
+func New(config Config) *Limiter {
+	l := &Limiter{maxBytes: config.MaxBytes, routes: make(map[string]int64, len(config.Routes))}
+	if l.maxBytes == 0 {
+		l.maxBytes = DefaultMaxBytes
+	}
+	for prefix, limit := range config.Routes {
+		l.routes[prefix] = limit
+		l.prefixes = append(l.prefixes, prefix)
+	}
+	sort.Slice(l.prefixes, func(i, j int) bool { return len(l.prefixes[i]) > len(l.prefixes[j]) })
+	return l
+}
+
+// Limit retorna o limite de r em bytes; negativo quando não há limite
+func (l *Limiter) Limit(r *http.Request) int64 {
+	for _, prefix := range l.prefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return l.routes[prefix]
+		}
+	}
+	return l.maxBytes
+}
+
+// Bind aplica o limite ao corpo de r. Se o corpo passa do limite, responde
+// 413 e retorna false, e a requisição não deve seguir. É a base de
+// Middleware e dos middlewares de frameworks como o Gin.
+func (l *Limiter) Bind(w http.ResponseWriter, r *http.Request) bool {
+	limit := l.Limit(r)
+	if limit < 0 || r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+	if r.ContentLength > limit {
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorBody(limit))
+		return false
+	}
+
+	// Com Content-Length dentro do limite, o leitor só protege de um
+	// cabeçalho mentiroso
+	body := http.MaxBytesReader(w, r.Body, limit)
+	if r.ContentLength >= 0 {
+		r.Body = body
+		return true
+	}
+
+	// Sem Content-Length, o corpo é lido agora para recusar o excesso antes
+	// do handler
+	data, err := io.ReadAll(body)
+	body.Close()
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorBody(limit))
+		return false
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":   "invalid_request",
+			"message": "failed to read request body",
+		})
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	r.ContentLength = int64(len(data))
+	return true
+}
+
+// Middleware aplica Bind a cada requisição
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.Bind(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ErrorBody é o corpo das respostas 413
+func ErrorBody(limit int64) map[string]string {
+	return map[string]string{
+		"error":   "request_too_large",
+		"message": fmt.Sprintf("request body exceeds the limit of %d bytes", limit),
+	}
+}
+
+// ParseRoutes lê limites por rota no formato "prefixo=bytes,prefixo=bytes",
+// como em variáveis de ambiente. Um valor -1 desativa o limite da rota.
+func ParseRoutes(s string) (map[string]int64, error) {
+	routes := make(map[string]int64)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, value, ok := strings.Cut(entry, "=")
+		prefix = strings.TrimSpace(prefix)
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("limite de rota inválido %q: esperado /prefixo=bytes", entry)
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || limit == 0 || limit < -1 {
+			return nil, fmt.Errorf("limite de rota inválido %q: bytes deve ser positivo ou -1", entry)
+		}
+		routes[prefix] = limit
+	}
+	return routes, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
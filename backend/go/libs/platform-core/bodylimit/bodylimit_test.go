@@ -0,0 +1,112 @@
+package bodylimit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echo devolve o tamanho do corpo lido e conta as chamadas
+type echo struct{ calls int }
+
+func (e *echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.calls++
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(strings.Repeat("x", len(data))))
+}
+
+func post(h http.Handler, path, body string, chunked bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if chunked {
+		req.ContentLength = -1
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_OversizedBodyRejectedBeforeHandler(t *testing.T) {
+	for _, chunked := range []bool{false, true} {
+		next := &echo{}
+		h := New(Config{MaxBytes: 10}).Middleware(next)
+
+		rec := post(h, "/api/v1/tenants", strings.Repeat("a", 11), chunked)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("chunked=%v: status = %d, esperado 413", chunked, rec.Code)
+		}
+		if next.calls != 0 {
+			t.Errorf("chunked=%v: o handler rodou %d vez(es) com o corpo acima do limite", chunked, next.calls)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body["error"] != "request_too_large" || !strings.Contains(body["message"], "10 bytes") {
+			t.Errorf("chunked=%v: corpo = %v, esperado request_too_large com o limite", chunked, body)
+		}
+
+		rec = post(h, "/api/v1/tenants", strings.Repeat("a", 10), chunked)
+		if rec.Code != http.StatusOK || rec.Body.Len() != 10 {
+			t.Errorf("chunked=%v: status = %d com %d bytes lidos, esperado 200 com 10 no limite", chunked, rec.Code, rec.Body.Len())
+		}
+	}
+}
+
+func TestLimit_RoutePrefixes(t *testing.T) {
+	l := New(Config{
+		MaxBytes: 100,
+		Routes: map[string]int64{
+			"/api/v1/tools":         1000,
+			"/api/v1/tools/upload":  -1,
+			"/api/v1/tools/execute": 10,
+		},
+	})
+
+	tests := map[string]int64{
+		"/api/v1/tenants":              100,
+		"/api/v1/tools/search":         1000,
+		"/api/v1/tools/execute/x":      10,
+		"/api/v1/tools/upload/big.wav": -1,
+	}
+	for path, want := range tests {
+		if got := l.Limit(httptest.NewRequest(http.MethodPost, path, nil)); got != want {
+			t.Errorf("Limit(%s) = %d, esperado %d", path, got, want)
+		}
+	}
+
+	next := &echo{}
+	h := l.Middleware(next)
+	if rec := post(h, "/api/v1/tools/upload/big.wav", strings.Repeat("a", 5000), false); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, esperado 200 numa rota sem limite", rec.Code)
+	}
+}
+
+func TestNew_Defaults(t *testing.T) {
+	if got := New(Config{}).Limit(httptest.NewRequest(http.MethodPost, "/", nil)); got != DefaultMaxBytes {
+		t.Errorf("Limit = %d, esperado DefaultMaxBytes", got)
+	}
+	if got := New(Config{MaxBytes: -1}).Limit(httptest.NewRequest(http.MethodPost, "/", nil)); got >= 0 {
+		t.Errorf("Limit = %d, esperado sem limite", got)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes(" /api/v1/tools=2097152, /media=-1 ,")
+	if err != nil {
+		t.Fatalf("ParseRoutes falhou: %v", err)
+	}
+	if len(routes) != 2 || routes["/api/v1/tools"] != 2097152 || routes["/media"] != -1 {
+		t.Errorf("routes = %v", routes)
+	}
+
+	for _, invalid := range []string{"api=10", "/api", "/api=abc", "/api=0", "/api=-2"} {
+		if _, err := ParseRoutes(invalid); err == nil {
+			t.Errorf("ParseRoutes(%q): esperava erro", invalid)
+		}
+	}
+}
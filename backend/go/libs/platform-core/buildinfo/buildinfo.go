@@ -0,0 +1,74 @@
+// Package buildinfo expõe a versão em execução de um serviço: versão, commit
+// e horário do build, injetados via -ldflags, e a versão do Go. Ela é servida
+// no endpoint /version e na métrica serphona_build_info.
+//
+// Exemplo de build:
+//
+//	go build -ldflags "\
+//	  -X github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo.Version=1.4.0 \
+//	  -X github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+//	  ./cmd/server
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Preenchidas no build via -ldflags -X
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info descreve o build de um serviço
+type Info struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get retorna o build do serviço
+func Get(service string) Info {
+	return Info{
+		Service:   service,
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// Handler responde com o build do serviço em JSON, para o endpoint /version
+func Handler(service string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Get(service))
+	})
+}
+
+// Register registra em reg o gauge serphona_build_info, sempre 1, com o
+// build do serviço nos labels
+func Register(reg prometheus.Registerer, service string) {
+	info := Get(service)
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "serphona_build_info",
+		Help: "Build em execução do serviço: versão, commit, horário do build e versão do Go",
+		ConstLabels: prometheus.Labels{
+			"service":    info.Service,
+			"version":    info.Version,
+			"commit":     info.Commit,
+			"build_time": info.BuildTime,
+			"go_version": info.GoVersion,
+		},
+	})
+	gauge.Set(1)
+	reg.MustRegister(gauge)
+}
@@ -0,0 +1,66 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// inject simula os valores de -ldflags -X durante o teste
+func inject(t *testing.T) {
+	t.Helper()
+	version, commit, buildTime := Version, Commit, BuildTime
+	Version, Commit, BuildTime = "1.4.0", "abc1234", "2026-03-01T10:00:00Z"
+	t.Cleanup(func() { Version, Commit, BuildTime = version, commit, buildTime })
+}
+
+func TestHandler_ReturnsInjectedBuild(t *testing.T) {
+	inject(t)
+
+	rec := httptest.NewRecorder()
+	Handler("billing-service").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperado 200", rec.Code)
+	}
+	var got Info
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("resposta inválida: %v", err)
+	}
+	want := Info{Service: "billing-service", Version: "1.4.0", Commit: "abc1234", BuildTime: "2026-03-01T10:00:00Z", GoVersion: runtime.Version()}
+	if got != want {
+		t.Errorf("build = %+v, esperado %+v", got, want)
+	}
+}
+
+func TestRegister_BuildInfoGauge(t *testing.T) {
+	inject(t)
+
+	reg := prometheus.NewRegistry()
+	Register(reg, "voice-gateway")
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	if len(families) != 1 || families[0].GetName() != "serphona_build_info" {
+		t.Fatalf("métricas = %v, esperado serphona_build_info", families)
+	}
+	metric := families[0].GetMetric()[0]
+	if metric.GetGauge().GetValue() != 1 {
+		t.Errorf("valor = %v, esperado 1", metric.GetGauge().GetValue())
+	}
+	labels := map[string]string{}
+	for _, label := range metric.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+	for name, want := range map[string]string{"service": "voice-gateway", "version": "1.4.0", "commit": "abc1234"} {
+		if labels[name] != want {
+			t.Errorf("label %s = %q, esperado %q", name, labels[name], want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package businesshours avalia o horário de atendimento de um tenant. É a
+// implementação única usada pelo tenant-manager e pelo voice-gateway.
+package businesshours
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Days são as chaves aceitas em Schedule.Weekly
+var Days = []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}
+
+// Schedule representa o horário de atendimento semanal de um tenant
+type Schedule struct {
+	Enabled  bool                   `json:"enabled"`
+	Timezone string                 `json:"timezone"`           // nome IANA, UTC quando vazio
+	Weekly   map[string][]TimeRange `json:"weekly"`             // "mon" -> intervalos
+	Holidays []string               `json:"holidays,omitempty"` // "YYYY-MM-DD" no fuso do tenant
+}
+
+// TimeRange representa um intervalo de atendimento que começa no dia em que
+// está cadastrado. End é exclusivo e pode ser "24:00" (meia-noite). Quando End
+// não é maior que Start o intervalo atravessa a meia-noite, ex. 22:00-06:00
+// cadastrado na segunda vai até as 06:00 de terça.
+type TimeRange struct {
+	Start string `json:"start"` // "HH:MM"
+	End   string `json:"end"`   // "HH:MM"
+}
+
+// IsOpen indica se o instante está dentro do horário de atendimento. Horário
+// desabilitado está sempre aberto; um Schedule inválido (que Validate rejeita)
+// é tratado como fechado.
+func (s Schedule) IsOpen(at time.Time) bool {
+	if !s.Enabled {
+		return true
+	}
+
+	loc, err := s.location()
+	if err != nil {
+		return false
+	}
+	local := at.In(loc)
+	minutes := local.Hour()*60 + local.Minute()
+
+	// Intervalos do dia que começam hoje
+	if !s.isHoliday(local) {
+		for _, r := range s.Weekly[dayKey(local.Weekday())] {
+			start, end, ok := r.bounds()
+			if !ok {
+				continue
+			}
+			// Intervalos que atravessam a meia-noite vão até o fim do dia
+			if minutes >= start && (end <= start || minutes < end) {
+				return true
+			}
+		}
+	}
+
+	// Intervalos do dia anterior que atravessam a meia-noite; o feriado vale
+	// para o dia em que o intervalo começou
+	yesterday := local.AddDate(0, 0, -1)
+	if s.isHoliday(yesterday) {
+		return false
+	}
+	for _, r := range s.Weekly[dayKey(yesterday.Weekday())] {
+		start, end, ok := r.bounds()
+		if !ok {
+			continue
+		}
+		if end <= start && minutes < end { // continuação após a meia-noite
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate verifica o fuso horário, os dias da semana, os intervalos e os
+// feriados. Deve ser chamado antes de salvar as configurações do tenant.
+func (s Schedule) Validate() error {
+	if _, err := s.location(); err != nil {
+		return fmt.Errorf("invalid timezone %q", s.Timezone)
+	}
+
+	for day, ranges := range s.Weekly {
+		if !validDay(day) {
+			return fmt.Errorf("invalid day %q, expected one of %s", day, strings.Join(Days, ", "))
+		}
+		for _, r := range ranges {
+			start, ok := ParseClock(r.Start)
+			if !ok || start == 24*60 {
+				return fmt.Errorf("invalid start time %q on %s, expected HH:MM", r.Start, day)
+			}
+			end, ok := ParseClock(r.End)
+			if !ok {
+				return fmt.Errorf("invalid end time %q on %s, expected HH:MM", r.End, day)
+			}
+			if start == end {
+				return fmt.Errorf("empty range %s-%s on %s", r.Start, r.End, day)
+			}
+		}
+	}
+
+	for _, holiday := range s.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday); err != nil {
+			return fmt.Errorf("invalid holiday %q, expected YYYY-MM-DD", holiday)
+		}
+	}
+
+	return nil
+}
+
+// ParseClock converte "HH:MM" em minutos desde a meia-noite. "24:00" é aceito
+// como fim do dia.
+func ParseClock(s string) (int, bool) {
+	if s == "24:00" {
+		return 24 * 60, true
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func (s Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+func (s Schedule) isHoliday(local time.Time) bool {
+	date := local.Format("2006-01-02")
+	for _, holiday := range s.Holidays {
+		if holiday == date {
+			return true
+		}
+	}
+	return false
+}
+
+func (r TimeRange) bounds() (start, end int, ok bool) {
+	if start, ok = ParseClock(r.Start); !ok {
+		return 0, 0, false
+	}
+	if end, ok = ParseClock(r.End); !ok {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+func dayKey(d time.Weekday) string {
+	return strings.ToLower(d.String()[:3])
+}
+
+func validDay(day string) bool {
+	for _, d := range Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
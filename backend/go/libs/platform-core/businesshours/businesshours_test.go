@@ -0,0 +1,158 @@
+package businesshours
+
+import (
+	"testing"
+	"time"
+)
+
+func weekdays(timezone string, ranges ...TimeRange) Schedule {
+	return Schedule{
+		Enabled:  true,
+		Timezone: timezone,
+		Weekly: map[string][]TimeRange{
+			"mon": ranges,
+			"tue": ranges,
+			"wed": ranges,
+			"thu": ranges,
+			"fri": ranges,
+		},
+		Holidays: []string{"2025-12-25"},
+	}
+}
+
+func TestIsOpenTimezones(t *testing.T) {
+	// quarta-feira 2025-01-08 14:00 UTC
+	at := time.Date(2025, 1, 8, 14, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		timezone string
+		expected bool
+	}{
+		{"UTC", true},                  // 14:00
+		{"America/Sao_Paulo", true},    // 11:00
+		{"America/New_York", true},     // 09:00, minuto de abertura
+		{"America/Los_Angeles", false}, // 06:00
+		{"Asia/Tokyo", false},          // 23:00
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			s := weekdays(tt.timezone, TimeRange{Start: "09:00", End: "18:00"})
+			if got := s.IsOpen(at); got != tt.expected {
+				t.Errorf("IsOpen = %v em %s, esperado %v", got, tt.timezone, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOpenDST(t *testing.T) {
+	s := weekdays("America/New_York", TimeRange{Start: "09:00", End: "18:00"})
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{"08:30 EST antes do horário de verão", time.Date(2025, 3, 7, 13, 30, 0, 0, time.UTC), false},
+		{"09:30 EDT após o início do horário de verão", time.Date(2025, 3, 10, 13, 30, 0, 0, time.UTC), true},
+		{"17:30 EDT antes do fim do horário de verão", time.Date(2025, 10, 31, 21, 30, 0, 0, time.UTC), true},
+		{"17:30 EST após o fim do horário de verão", time.Date(2025, 11, 3, 22, 30, 0, 0, time.UTC), true},
+		{"18:00 EST fechamento", time.Date(2025, 11, 3, 23, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsOpen(tt.at); got != tt.expected {
+				t.Errorf("IsOpen = %v, esperado %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOpenHolidayInTenantTimezone(t *testing.T) {
+	s := weekdays("America/Sao_Paulo", TimeRange{Start: "09:00", End: "23:00"})
+
+	if s.IsOpen(time.Date(2025, 12, 25, 15, 0, 0, 0, time.UTC)) {
+		t.Error("deveria estar fechado no feriado")
+	}
+	// 2025-12-26 01:00 UTC ainda é 2025-12-25 22:00 em São Paulo
+	if s.IsOpen(time.Date(2025, 12, 26, 1, 0, 0, 0, time.UTC)) {
+		t.Error("o feriado deve ser avaliado no fuso do tenant")
+	}
+	if !s.IsOpen(time.Date(2025, 12, 26, 15, 0, 0, 0, time.UTC)) {
+		t.Error("deveria estar aberto no dia seguinte ao feriado")
+	}
+}
+
+func TestIsOpenOvernight(t *testing.T) {
+	s := Schedule{
+		Enabled: true,
+		Weekly: map[string][]TimeRange{
+			"fri": {{Start: "22:00", End: "06:00"}},
+		},
+		Holidays: []string{"2025-01-17"},
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{"sexta 21:59", time.Date(2025, 1, 10, 21, 59, 0, 0, time.UTC), false},
+		{"sexta 22:00", time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC), true},
+		{"sábado 03:00", time.Date(2025, 1, 11, 3, 0, 0, 0, time.UTC), true},
+		{"sábado 06:00", time.Date(2025, 1, 11, 6, 0, 0, 0, time.UTC), false},
+		{"quinta 03:00", time.Date(2025, 1, 9, 3, 0, 0, 0, time.UTC), false},
+		{"sábado 03:00 após sexta feriado", time.Date(2025, 1, 18, 3, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.IsOpen(tt.at); got != tt.expected {
+				t.Errorf("IsOpen = %v, esperado %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOpenDisabled(t *testing.T) {
+	if !(Schedule{}).IsOpen(time.Date(2025, 1, 11, 3, 0, 0, 0, time.UTC)) {
+		t.Error("horário desabilitado deve estar sempre aberto")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ranges  []TimeRange
+		mutate  func(s *Schedule)
+		wantErr bool
+	}{
+		{name: "válido", ranges: []TimeRange{{Start: "09:00", End: "18:00"}}},
+		{name: "fim do dia", ranges: []TimeRange{{Start: "18:00", End: "24:00"}}},
+		{name: "atravessa a meia-noite", ranges: []TimeRange{{Start: "22:00", End: "06:00"}}},
+		{name: "fuso inválido", mutate: func(s *Schedule) { s.Timezone = "America/Nowhere" }, wantErr: true},
+		{name: "dia inválido", mutate: func(s *Schedule) { s.Weekly["monday"] = nil }, wantErr: true},
+		{name: "hora inválida", ranges: []TimeRange{{Start: "25:00", End: "26:00"}}, wantErr: true},
+		{name: "formato inválido", ranges: []TimeRange{{Start: "9h", End: "18:00"}}, wantErr: true},
+		{name: "início 24:00", ranges: []TimeRange{{Start: "24:00", End: "06:00"}}, wantErr: true},
+		{name: "intervalo vazio", ranges: []TimeRange{{Start: "09:00", End: "09:00"}}, wantErr: true},
+		{name: "feriado inválido", mutate: func(s *Schedule) { s.Holidays = []string{"25/12/2025"} }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := tt.ranges
+			if ranges == nil {
+				ranges = []TimeRange{{Start: "09:00", End: "18:00"}}
+			}
+			s := weekdays("America/Sao_Paulo", ranges...)
+			if tt.mutate != nil {
+				tt.mutate(&s)
+			}
+			if err := s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
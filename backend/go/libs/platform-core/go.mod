@@ -3,6 +3,7 @@ module github.com/serphona/serphona/backend/go/libs/platform-core
 go 1.21
 
 require (
+	github.com/prometheus/client_golang v1.17.0
 	go.uber.org/zap v1.26.0
 	github.com/spf13/viper v1.18.1
 	gorm.io/gorm v1.25.5
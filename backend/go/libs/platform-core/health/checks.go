@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Pinger é implementado por clientes com Ping(ctx) error, como *pgxpool.Pool
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingCheck verifica uma dependência via Ping
+func PingCheck(p Pinger) CheckFunc {
+	return p.Ping
+}
+
+// HTTPCheck faz um GET em url e considera a dependência disponível se ela
+// responder com status abaixo de 500. Respostas 401/404 ainda indicam que o
+// serviço está de pé, o que basta para a prontidão. Se client for nil, usa
+// http.DefaultClient.
+func HTTPCheck(client *http.Client, url string) CheckFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// DialCheck verifica se ao menos um dos endereços aceita conexão TCP. Útil
+// para brokers Kafka, onde basta um broker acessível para o cliente se
+// recuperar.
+func DialCheck(addrs ...string) CheckFunc {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		var errs []error
+		for _, addr := range addrs {
+			conn, err := d.DialContext(ctx, "tcp", addr)
+			if err == nil {
+				conn.Close()
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) == 0 {
+			return errors.New("no addresses configured")
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,194 @@
+// Package health agrega as verificações de dependências de um serviço
+// (banco, Redis, Kafka, serviços vizinhos) em um relatório único para o
+// endpoint /health/ready.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout é o prazo de cada verificação quando nenhum é informado
+const DefaultTimeout = 2 * time.Second
+
+// Status é o estado de uma verificação ou do serviço como um todo
+type Status string
+
+const (
+	// StatusUp indica verificação bem-sucedida
+	StatusUp Status = "up"
+	// StatusDown indica verificação com falha
+	StatusDown Status = "down"
+
+	// StatusHealthy indica que todas as verificações passaram
+	StatusHealthy Status = "healthy"
+	// StatusDegraded indica falha apenas em verificações não críticas
+	StatusDegraded Status = "degraded"
+	// StatusUnhealthy indica falha em ao menos uma verificação crítica
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// CheckFunc verifica uma dependência. Deve respeitar o prazo de ctx.
+type CheckFunc func(ctx context.Context) error
+
+// Option configura uma verificação registrada
+type Option func(*check)
+
+// Critical marca a verificação como crítica: se falhar, o serviço fica
+// unhealthy e /health/ready responde 503
+func Critical() Option {
+	return func(c *check) { c.critical = true }
+}
+
+// WithTimeout define o prazo da verificação
+func WithTimeout(d time.Duration) Option {
+	return func(c *check) { c.timeout = d }
+}
+
+// check é uma verificação registrada
+type check struct {
+	name     string
+	fn       CheckFunc
+	critical bool
+	timeout  time.Duration
+}
+
+// CheckResult é o resultado de uma verificação
+type CheckResult struct {
+	Status    Status `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report é o relatório agregado retornado por /health/ready
+type Report struct {
+	Status  Status                 `json:"status"`
+	Service string                 `json:"service,omitempty"`
+	Checks  map[string]CheckResult `json:"checks"`
+}
+
+// Checker executa as verificações registradas
+type Checker struct {
+	service string
+
+	mu     sync.RWMutex
+	checks []check
+}
+
+// NewChecker cria um Checker para o serviço informado
+func NewChecker(service string) *Checker {
+	return &Checker{service: service}
+}
+
+// Register adiciona uma verificação. Por padrão ela não é crítica e tem
+// prazo DefaultTimeout.
+func (c *Checker) Register(name string, fn CheckFunc, opts ...Option) {
+	ch := check{name: name, fn: fn, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(&ch)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, ch)
+}
+
+// Run executa todas as verificações em paralelo, cada uma com seu prazo, e
+// retorna o relatório. Uma verificação que ignora o contexto é abandonada
+// ao fim do prazo e reportada como down.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make([]check, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func(i int, ch check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, ch)
+		}(i, ch)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:  StatusHealthy,
+		Service: c.service,
+		Checks:  make(map[string]CheckResult, len(checks)),
+	}
+	for i, ch := range checks {
+		res := results[i]
+		report.Checks[ch.name] = res
+		if res.Status == StatusUp {
+			continue
+		}
+		if res.Critical {
+			report.Status = StatusUnhealthy
+		} else if report.Status == StatusHealthy {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// runCheck executa uma verificação dentro do seu prazo
+func runCheck(ctx context.Context, ch check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, ch.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- ch.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("timeout after %s", ch.timeout)
+	}
+
+	res := CheckResult{
+		Status:    StatusUp,
+		Critical:  ch.critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// ServeHTTP responde com o relatório em JSON: 200 quando healthy ou
+// degraded e 503 apenas quando uma verificação crítica falha
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+
+	status := http.StatusOK
+	if report.Status == StatusUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
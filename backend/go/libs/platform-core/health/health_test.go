@@ -0,0 +1,164 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func ok(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("connection refused") }
+
+func TestRunAggregatesStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(c *Checker)
+		expected Status
+	}{
+		{
+			name: "todas passam",
+			register: func(c *Checker) {
+				c.Register("database", ok, Critical())
+				c.Register("redis", ok)
+			},
+			expected: StatusHealthy,
+		},
+		{
+			name: "não crítica falha",
+			register: func(c *Checker) {
+				c.Register("database", ok, Critical())
+				c.Register("redis", failing)
+			},
+			expected: StatusDegraded,
+		},
+		{
+			name: "crítica falha",
+			register: func(c *Checker) {
+				c.Register("database", failing, Critical())
+				c.Register("redis", failing)
+			},
+			expected: StatusUnhealthy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChecker("test")
+			tt.register(c)
+
+			report := c.Run(context.Background())
+			if report.Status != tt.expected {
+				t.Errorf("Status = %s, esperado %s", report.Status, tt.expected)
+			}
+			if len(report.Checks) != 2 {
+				t.Errorf("Esperado 2 verificações no relatório, obtido %d", len(report.Checks))
+			}
+		})
+	}
+}
+
+func TestRunAppliesPerCheckTimeoutConcurrently(t *testing.T) {
+	c := NewChecker("test")
+	stuck := make(chan struct{})
+	defer close(stuck)
+
+	// Ignora o contexto: deve ser abandonada ao fim do prazo
+	c.Register("stuck", func(context.Context) error { <-stuck; return nil }, WithTimeout(50*time.Millisecond))
+	c.Register("slow", func(ctx context.Context) error {
+		select {
+		case <-time.After(40 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, WithTimeout(time.Second))
+
+	start := time.Now()
+	report := c.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Verificações deveriam rodar em paralelo, levou %s", elapsed)
+	}
+
+	if got := report.Checks["stuck"]; got.Status != StatusDown || got.Error != "timeout after 50ms" {
+		t.Errorf("stuck = %+v, esperado down por timeout", got)
+	}
+	if got := report.Checks["slow"]; got.Status != StatusUp {
+		t.Errorf("slow = %+v, esperado up", got)
+	}
+	if report.Status != StatusDegraded {
+		t.Errorf("Status = %s, esperado degraded", report.Status)
+	}
+}
+
+func TestServeHTTPStatusCodes(t *testing.T) {
+	degraded := NewChecker("voice-gateway")
+	degraded.Register("agent-orchestrator", failing)
+
+	rec := httptest.NewRecorder()
+	degraded.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Degraded deveria responder 200, obtido %d", rec.Code)
+	}
+
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("Resposta inválida: %v", err)
+	}
+	if report.Service != "voice-gateway" || report.Checks["agent-orchestrator"].Error != "connection refused" {
+		t.Errorf("Relatório inesperado: %+v", report)
+	}
+
+	unhealthy := NewChecker("voice-gateway")
+	unhealthy.Register("asterisk-ari", failing, Critical())
+
+	rec = httptest.NewRecorder()
+	unhealthy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Unhealthy deveria responder 503, obtido %d", rec.Code)
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	status := http.StatusUnauthorized
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	check := HTTPCheck(nil, srv.URL)
+	if err := check(context.Background()); err != nil {
+		t.Errorf("401 indica serviço de pé, obtido %v", err)
+	}
+
+	status = http.StatusBadGateway
+	if err := check(context.Background()); err == nil {
+		t.Error("502 deveria falhar a verificação")
+	}
+}
+
+func TestDialCheck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen falhou: %v", err)
+	}
+	addr := ln.Addr().String()
+
+	// Basta um endereço acessível
+	if err := DialCheck("127.0.0.1:1", addr)(context.Background()); err != nil {
+		t.Errorf("Esperado sucesso com um broker acessível, obtido %v", err)
+	}
+
+	ln.Close()
+	if err := DialCheck(addr)(context.Background()); err == nil {
+		t.Error("Esperado erro sem brokers acessíveis")
+	}
+	if err := DialCheck()(context.Background()); err == nil {
+		t.Error("Esperado erro sem endereços")
+	}
+}
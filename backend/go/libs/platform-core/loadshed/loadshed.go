@@ -0,0 +1,228 @@
+// Package loadshed recusa trabalho novo quando o serviço está sobrecarregado,
+// em vez de deixar todas as requisições ficarem lentas. Um Shedder limita as
+// requisições em andamento; acima do teto, uma requisição nova espera por uma
+// vaga até um tempo máximo de fila e, se não conseguir, recebe 503 com
+// Retry-After. Requisições críticas (health checks, chamadas em andamento)
+// nunca são recusadas, e as que já começaram sempre terminam.
+package loadshed
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Reason indica por que uma requisição foi recusada
+type Reason string
+
+const (
+	// ReasonOverloaded: o teto foi atingido e não há fila
+	ReasonOverloaded Reason = "overloaded"
+	// ReasonQueueTimeout: a requisição esperou o tempo máximo de fila
+	ReasonQueueTimeout Reason = "queue_timeout"
+)
+
+// DefaultRetryAfter é o Retry-After quando Config.RetryAfter é zero
+const DefaultRetryAfter = time.Second
+
+// Config define os limites do Shedder
+type Config struct {
+	// MaxInFlight é o teto de requisições em andamento; zero desativa o shedder
+	MaxInFlight int
+	// MaxQueueWait é quanto uma requisição nova espera por uma vaga acima do
+	// teto; zero recusa na hora
+	MaxQueueWait time.Duration
+	// RetryAfter é sugerido aos clientes recusados
+	RetryAfter time.Duration
+}
+
+// Stats é o estado atual do Shedder
+type Stats struct {
+	InFlight int
+	Queued   int
+}
+
+// Shedder conta as requisições em andamento e decide quais entram
+type Shedder struct {
+	mu       sync.Mutex
+	config   Config
+	inFlight int
+	queue    *list.List // de chan struct{}, em ordem de chegada
+
+	critical func(r *http.Request) bool
+	exempt   func(r *http.Request) bool
+	onShed   func(r *http.Request, reason Reason)
+}
+
+// Option configura um Shedder
+type Option func(*Shedder)
+
+// WithCritical marca as requisições que nunca são recusadas. Elas contam no
+// teto, mas entram mesmo acima dele.
+func WithCritical(fn func(r *http.Request) bool) Option {
+	return func(s *Shedder) { s.critical = fn }
+}
+
+// WithExempt marca as requisições que passam direto, sem contar no teto:
+// streams de longa duração que ocupariam uma vaga para sempre
+func WithExempt(fn func(r *http.Request) bool) Option {
+	return func(s *Shedder) { s.exempt = fn }
+}
+
+// WithShedHandler registra uma função chamada a cada requisição recusada,
+// tipicamente para log e métricas
+func WithShedHandler(fn func(r *http.Request, reason Reason)) Option {
+	return func(s *Shedder) { s.onShed = fn }
+}
+
+// New cria um Shedder com os limites de config
+func New(config Config, opts ...Option) *Shedder {
+	s := &Shedder{config: config, queue: list.New()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SetConfig troca os limites sem afetar as requisições em andamento, para
+// recarregar a configuração
+func (s *Shedder) SetConfig(config Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = config
+	// Um teto maior (ou desativado) libera quem está na fila
+	for s.queue.Len() > 0 && (config.MaxInFlight <= 0 || s.inFlight < config.MaxInFlight) {
+		s.admitNext()
+		s.inFlight++
+	}
+}
+
+// Stats retorna as requisições em andamento e na fila
+func (s *Shedder) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Stats{InFlight: s.inFlight, Queued: s.queue.Len()}
+}
+
+// Acquire reserva uma vaga para uma requisição. Retorna a função que a
+// libera, ou a razão da recusa. Se ctx terminar na fila, retorna o erro de
+// ctx sem razão.
+func (s *Shedder) Acquire(ctx context.Context, critical bool) (release func(), reason Reason, err error) {
+	s.mu.Lock()
+	if critical || s.config.MaxInFlight <= 0 || s.inFlight < s.config.MaxInFlight {
+		s.inFlight++
+		s.mu.Unlock()
+		return s.release, "", nil
+	}
+	wait := s.config.MaxQueueWait
+	if wait <= 0 {
+		s.mu.Unlock()
+		return nil, ReasonOverloaded, nil
+	}
+	admitted := make(chan struct{})
+	elem := s.queue.PushBack(admitted)
+	s.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-admitted:
+		return s.release, "", nil
+	case <-timer.C:
+		reason = ReasonQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-admitted:
+		// A vaga chegou junto com o fim da espera
+		return s.release, "", nil
+	default:
+		s.queue.Remove(elem)
+		return nil, reason, err
+	}
+}
+
+// release libera uma vaga, passando-a ao primeiro da fila
+func (s *Shedder) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queue.Len() > 0 && (s.config.MaxInFlight <= 0 || s.inFlight <= s.config.MaxInFlight) {
+		s.admitNext()
+		return
+	}
+	s.inFlight--
+}
+
+// admitNext tira o primeiro da fila e o deixa entrar; chamado com mu
+func (s *Shedder) admitNext() {
+	front := s.queue.Front()
+	s.queue.Remove(front)
+	close(front.Value.(chan struct{}))
+}
+
+// RetryAfterSeconds arredonda o Retry-After configurado para cima em
+// segundos, no mínimo 1
+func (s *Shedder) RetryAfterSeconds() int {
+	s.mu.Lock()
+	retryAfter := s.config.RetryAfter
+	s.mu.Unlock()
+	if retryAfter <= 0 {
+		retryAfter = DefaultRetryAfter
+	}
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// Middleware aplica o Shedder às requisições, respondendo 503 com
+// Retry-After às recusadas
+func (s *Shedder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.exempt != nil && s.exempt(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		critical := s.critical != nil && s.critical(r)
+		release, reason, err := s.Acquire(r.Context(), critical)
+		if err != nil {
+			// O cliente desistiu na fila; não há a quem responder
+			return
+		}
+		if release == nil {
+			if s.onShed != nil {
+				s.onShed(r, reason)
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(s.RetryAfterSeconds()))
+			writeJSON(w, http.StatusServiceUnavailable, ErrorBody())
+			return
+		}
+		defer release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ErrorBody é o corpo das respostas 503 do Middleware
+func ErrorBody() map[string]string {
+	return map[string]string{
+		"error":   "service_overloaded",
+		"message": "service is overloaded, retry later",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
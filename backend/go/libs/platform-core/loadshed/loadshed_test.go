@@ -0,0 +1,192 @@
+package loadshed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingHandler segura as requisições até unblock ser fechado
+type blockingHandler struct {
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{started: make(chan struct{}, 16), unblock: make(chan struct{})}
+}
+
+func (h *blockingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.started <- struct{}{}
+	<-h.unblock
+	w.WriteHeader(http.StatusOK)
+}
+
+func serve(h http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+// fill ocupa n vagas com requisições que só terminam quando o handler libera
+func fill(t *testing.T, h http.Handler, blocking *blockingHandler, n int) *sync.WaitGroup {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serve(h, "/work")
+		}()
+		<-blocking.started
+	}
+	return &wg
+}
+
+func TestMiddleware_BelowCeilingPasses(t *testing.T) {
+	shedder := New(Config{MaxInFlight: 2})
+	h := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 5; i++ {
+		if rec := serve(h, "/work"); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, esperado 200", rec.Code)
+		}
+	}
+	if stats := shedder.Stats(); stats.InFlight != 0 {
+		t.Errorf("InFlight = %d, esperado 0 após as requisições", stats.InFlight)
+	}
+}
+
+func TestMiddleware_AboveCeilingShed(t *testing.T) {
+	var shed []Reason
+	blocking := newBlockingHandler()
+	shedder := New(Config{MaxInFlight: 2, RetryAfter: 1500 * time.Millisecond},
+		WithShedHandler(func(r *http.Request, reason Reason) { shed = append(shed, reason) }),
+		WithCritical(func(r *http.Request) bool { return r.URL.Path == "/health" }),
+	)
+	mux := http.NewServeMux()
+	mux.Handle("/work", blocking)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := shedder.Middleware(mux)
+
+	inFlight := fill(t, h, blocking, 2)
+
+	rec := serve(h, "/work")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, esperado 503 acima do teto", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, esperado 2", got)
+	}
+	if len(shed) != 1 || shed[0] != ReasonOverloaded {
+		t.Errorf("recusas = %v, esperado [overloaded]", shed)
+	}
+
+	// Requisições críticas entram mesmo acima do teto
+	if rec := serve(h, "/health"); rec.Code != http.StatusOK {
+		t.Errorf("status do health = %d, esperado 200", rec.Code)
+	}
+
+	// As requisições em andamento terminam normalmente
+	close(blocking.unblock)
+	inFlight.Wait()
+	if rec := serve(h, "/health"); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, esperado 200 após liberar", rec.Code)
+	}
+}
+
+func TestAcquire_QueueWaitsForSlot(t *testing.T) {
+	shedder := New(Config{MaxInFlight: 1, MaxQueueWait: time.Second})
+	release, _, _ := shedder.Acquire(context.Background(), false)
+
+	admitted := make(chan func(), 1)
+	go func() {
+		next, _, _ := shedder.Acquire(context.Background(), false)
+		admitted <- next
+	}()
+	for shedder.Stats().Queued == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	release()
+	select {
+	case next := <-admitted:
+		if next == nil {
+			t.Fatal("esperava a vaga passada ao primeiro da fila")
+		}
+		if stats := shedder.Stats(); stats.InFlight != 1 || stats.Queued != 0 {
+			t.Errorf("Stats = %+v, esperado 1 em andamento e fila vazia", stats)
+		}
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("a requisição da fila não entrou")
+	}
+}
+
+func TestAcquire_QueueTimeout(t *testing.T) {
+	shedder := New(Config{MaxInFlight: 1, MaxQueueWait: 10 * time.Millisecond})
+	release, _, _ := shedder.Acquire(context.Background(), false)
+	defer release()
+
+	next, reason, err := shedder.Acquire(context.Background(), false)
+	if next != nil || err != nil || reason != ReasonQueueTimeout {
+		t.Errorf("Acquire = %v, %q, %v; esperado recusa por queue_timeout", next != nil, reason, err)
+	}
+	if stats := shedder.Stats(); stats.Queued != 0 {
+		t.Errorf("Queued = %d, esperado 0 após o timeout", stats.Queued)
+	}
+}
+
+func TestSetConfig_RaisedCeilingAdmitsQueue(t *testing.T) {
+	shedder := New(Config{MaxInFlight: 1, MaxQueueWait: time.Second})
+	release, _, _ := shedder.Acquire(context.Background(), false)
+	defer release()
+
+	admitted := make(chan func(), 1)
+	go func() {
+		next, _, _ := shedder.Acquire(context.Background(), false)
+		admitted <- next
+	}()
+	for shedder.Stats().Queued == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	shedder.SetConfig(Config{MaxInFlight: 2, MaxQueueWait: time.Second})
+	select {
+	case next := <-admitted:
+		if next == nil {
+			t.Fatal("esperava a fila liberada pelo teto maior")
+		}
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("a requisição da fila não entrou")
+	}
+}
+
+func TestMiddleware_ExemptNotCounted(t *testing.T) {
+	blocking := newBlockingHandler()
+	shedder := New(Config{MaxInFlight: 1}, WithExempt(func(r *http.Request) bool { return r.URL.Path == "/watch" }))
+	mux := http.NewServeMux()
+	mux.Handle("/watch", blocking)
+	mux.HandleFunc("/work", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	h := shedder.Middleware(mux)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serve(h, "/watch")
+	}()
+	<-blocking.started
+
+	if rec := serve(h, "/work"); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, esperado 200 com só um stream isento aberto", rec.Code)
+	}
+	close(blocking.unblock)
+	wg.Wait()
+}
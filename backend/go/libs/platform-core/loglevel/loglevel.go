@@ -0,0 +1,144 @@
+// Package loglevel troca o nível de log de um serviço em execução, sem
+// reiniciá-lo. Uma troca pode ter prazo: ao fim dele o nível anterior volta,
+// para que um debug ligado durante um incidente não fique ligado para sempre.
+package loglevel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MaxTTL é o maior prazo aceito para uma troca de nível
+const MaxTTL = 24 * time.Hour
+
+// Controller troca o nível de um zap.AtomicLevel, com retorno opcional ao
+// nível anterior
+type Controller struct {
+	level  zap.AtomicLevel
+	logger *zap.Logger
+
+	mu         sync.Mutex
+	timer      *time.Timer
+	generation uint64
+	baseline   zapcore.Level // nível restaurado quando o prazo vence
+	expiresAt  time.Time
+}
+
+// New cria um controlador para level. logger registra as trocas.
+func New(level zap.AtomicLevel, logger *zap.Logger) *Controller {
+	return &Controller{
+		level:  level,
+		logger: logger,
+	}
+}
+
+// Set muda o nível. Com ttl > 0, o nível anterior à primeira troca com prazo
+// volta depois de ttl; uma nova troca substitui o prazo pendente. Retorna
+// quando o nível volta, ou zero se a troca não tem prazo.
+func (c *Controller) Set(level zapcore.Level, ttl time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	} else {
+		c.baseline = c.level.Level()
+	}
+	c.generation++
+	c.expiresAt = time.Time{}
+
+	previous := c.level.Level()
+	c.level.SetLevel(level)
+
+	if ttl > 0 {
+		generation := c.generation
+		c.expiresAt = time.Now().Add(ttl)
+		c.timer = time.AfterFunc(ttl, func() { c.revert(generation) })
+	}
+
+	c.logger.Info("log level changed",
+		zap.Stringer("level", level),
+		zap.Stringer("previous_level", previous),
+		zap.Duration("ttl", ttl),
+	)
+	return c.expiresAt
+}
+
+// revert restaura o nível de base, se nenhuma troca posterior ocorreu
+func (c *Controller) revert(generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	c.timer = nil
+	c.expiresAt = time.Time{}
+	c.level.SetLevel(c.baseline)
+
+	c.logger.Info("log level reverted", zap.Stringer("level", c.baseline))
+}
+
+// Request é o corpo de PUT /admin/log-level
+type Request struct {
+	Level string `json:"level"`
+	// TTL é opcional, no formato de time.ParseDuration (ex.: "15m")
+	TTL string `json:"ttl,omitempty"`
+}
+
+// Response descreve o nível em vigor
+type Response struct {
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ServeHTTP atende PUT /admin/log-level. A autorização (superadmin) fica a
+// cargo do serviço, antes deste handler.
+func (c *Controller) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.Header().Set("Allow", http.MethodPut)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	level, err := zapcore.ParseLevel(req.Level)
+	if err != nil || req.Level == "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid level %q", req.Level))
+		return
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 || ttl > MaxTTL {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("ttl must be a positive duration up to %s", MaxTTL))
+			return
+		}
+	}
+
+	resp := Response{Level: level.String()}
+	if expiresAt := c.Set(level, ttl); !expiresAt.IsZero() {
+		resp.ExpiresAt = &expiresAt
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,106 @@
+package loglevel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func put(c *Controller, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(body)))
+	return rec
+}
+
+func TestPutEnablesDebugLogs(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	core, logs := observer.New(level)
+	logger := zap.New(core)
+	c := New(level, zap.NewNop())
+
+	logger.Debug("antes")
+	if logs.FilterMessage("antes").Len() != 0 {
+		t.Fatal("debug registrado com nível info")
+	}
+
+	rec := put(c, `{"level":"debug"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, esperado 200: %s", rec.Code, rec.Body)
+	}
+
+	logger.Debug("depois")
+	if logs.FilterMessage("depois").Len() != 1 {
+		t.Error("debug não registrado após trocar o nível")
+	}
+	if strings.Contains(rec.Body.String(), "expires_at") {
+		t.Errorf("troca sem prazo não deveria expirar: %s", rec.Body)
+	}
+}
+
+func TestTTLRevertsLevel(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.WarnLevel)
+	c := New(level, zap.NewNop())
+
+	c.Set(zapcore.DebugLevel, 20*time.Millisecond)
+	// Nova troca antes do prazo: volta ao nível original, não ao debug
+	expiresAt := c.Set(zapcore.InfoLevel, 20*time.Millisecond)
+	if expiresAt.IsZero() {
+		t.Fatal("troca com prazo deveria informar quando expira")
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Fatalf("nível = %s, esperado info", level.Level())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for level.Level() != zapcore.WarnLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("nível = %s após o prazo, esperado warn", level.Level())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSetWithoutTTLCancelsPendingRevert(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	c := New(level, zap.NewNop())
+
+	c.Set(zapcore.DebugLevel, 10*time.Millisecond)
+	c.Set(zapcore.ErrorLevel, 0)
+	time.Sleep(30 * time.Millisecond)
+
+	if level.Level() != zapcore.ErrorLevel {
+		t.Errorf("nível = %s, esperado error sem retorno", level.Level())
+	}
+}
+
+func TestPutRejectsInvalidRequests(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	c := New(level, zap.NewNop())
+
+	for _, body := range []string{
+		`{"level":"loud"}`,
+		`{}`,
+		`{"level":"debug","ttl":"ever"}`,
+		`{"level":"debug","ttl":"48h"}`,
+		`not json`,
+	} {
+		if rec := put(c, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, esperado 400", body, rec.Code)
+		}
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("nível alterado por requisição inválida: %s", level.Level())
+	}
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status = %d, esperado 405", rec.Code)
+	}
+}
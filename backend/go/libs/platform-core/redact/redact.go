@@ -0,0 +1,204 @@
+// Package redact remove dados pessoais (PII) de transcrições e eventos antes
+// de serem gravados, trocando os trechos sensíveis por marcadores do tipo,
+// ex. "[CARD_NUMBER]". As regras são configuradas por tenant em Policy.
+package redact
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Type é o tipo de um dado sensível
+type Type string
+
+// Tipos reconhecidos pelas regras padrão. TypePersonName só é encontrado por
+// um Detector.
+const (
+	TypeCardNumber Type = "card_number"
+	TypeEmail      Type = "email"
+	TypePhone      Type = "phone"
+	TypeCPF        Type = "cpf"
+	TypePersonName Type = "person_name"
+)
+
+// Rule é uma expressão regular que encontra dados de um tipo
+type Rule struct {
+	Type    Type   `json:"type"`
+	Pattern string `json:"pattern"`
+}
+
+// Policy é a configuração de redação de um tenant
+type Policy struct {
+	Enabled bool `json:"enabled"`
+	// Tipos redigidos; vazio redige todos
+	Types []Type `json:"types,omitempty"`
+	// Regras do tenant, aplicadas junto com as padrão
+	Rules []Rule `json:"rules,omitempty"`
+	// Guarda o original cifrado, visível só para admins
+	KeepOriginal bool `json:"keep_original"`
+}
+
+// Validate verifica as regras do tenant
+func (p Policy) Validate() error {
+	for _, rule := range p.Rules {
+		if rule.Type == "" {
+			return fmt.Errorf("regra de redação sem tipo")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("regra de redação %s inválida: %w", rule.Type, err)
+		}
+	}
+	return nil
+}
+
+func (p Policy) redacts(t Type) bool {
+	if len(p.Types) == 0 {
+		return true
+	}
+	for _, allowed := range p.Types {
+		if allowed == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Span é um trecho sensível de um texto, em bytes
+type Span struct {
+	Type  Type
+	Start int
+	End   int
+}
+
+// Detector encontra entidades que expressões regulares não pegam, como nomes
+type Detector interface {
+	Detect(ctx context.Context, text string) ([]Span, error)
+}
+
+type compiledRule struct {
+	typ   Type
+	re    *regexp.Regexp
+	valid func(string) bool // descarta falsos positivos, opcional
+}
+
+// defaultRules vêm antes das do tenant; em trechos sobrepostos vale a primeira
+var defaultRules = []compiledRule{
+	{typ: TypeCardNumber, re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhn},
+	{typ: TypeEmail, re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{typ: TypeCPF, re: regexp.MustCompile(`\b\d{3}\.?\d{3}\.?\d{3}-?\d{2}\b`)},
+	{typ: TypePhone, re: regexp.MustCompile(`\+?\(?\d{2,3}\)?[ .-]?\d{4,5}[ .-]?\d{4}\b`)},
+}
+
+// Redactor aplica as regras padrão, as do tenant e o Detector opcional
+type Redactor struct {
+	detector Detector
+}
+
+// New cria um Redactor. detector pode ser nil.
+func New(detector Detector) *Redactor {
+	return &Redactor{detector: detector}
+}
+
+// Redact devolve text com os trechos sensíveis trocados por marcadores, e os
+// trechos encontrados no texto original. Com a política desabilitada text é
+// devolvido como está. Se o Detector falhar, o texto já redigido pelas regras
+// é devolvido junto com o erro, e pode ser gravado.
+func (r *Redactor) Redact(ctx context.Context, policy Policy, text string) (string, []Span, error) {
+	if !policy.Enabled || text == "" {
+		return text, nil, nil
+	}
+
+	rules := defaultRules
+	for _, rule := range policy.Rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return "", nil, fmt.Errorf("regra de redação %s inválida: %w", rule.Type, err)
+		}
+		rules = append(rules[:len(rules):len(rules)], compiledRule{typ: rule.Type, re: re})
+	}
+
+	var spans []Span
+	for _, rule := range rules {
+		if !policy.redacts(rule.typ) {
+			continue
+		}
+		for _, loc := range rule.re.FindAllStringIndex(text, -1) {
+			if rule.valid != nil && !rule.valid(text[loc[0]:loc[1]]) {
+				continue
+			}
+			spans = append(spans, Span{Type: rule.typ, Start: loc[0], End: loc[1]})
+		}
+	}
+
+	var detectErr error
+	if r.detector != nil {
+		detected, err := r.detector.Detect(ctx, text)
+		if err != nil {
+			detectErr = fmt.Errorf("detector de entidades falhou: %w", err)
+		}
+		for _, s := range detected {
+			if policy.redacts(s.Type) && s.Start >= 0 && s.End <= len(text) && s.Start < s.End {
+				spans = append(spans, s)
+			}
+		}
+	}
+
+	spans = dropOverlaps(spans)
+	return replace(text, spans), spans, detectErr
+}
+
+// Placeholder é o marcador que substitui dados do tipo t
+func Placeholder(t Type) string {
+	return "[" + strings.ToUpper(string(t)) + "]"
+}
+
+// dropOverlaps ordena os trechos e descarta os que se sobrepõem a um anterior
+func dropOverlaps(spans []Span) []Span {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	kept := spans[:0]
+	end := -1
+	for _, s := range spans {
+		if s.Start < end {
+			continue
+		}
+		kept = append(kept, s)
+		end = s.End
+	}
+	return kept
+}
+
+func replace(text string, spans []Span) string {
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		b.WriteString(text[last:s.Start])
+		b.WriteString(Placeholder(s.Type))
+		last = s.End
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// luhn verifica o dígito de controle de números de cartão
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
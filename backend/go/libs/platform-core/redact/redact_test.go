@@ -0,0 +1,86 @@
+package redact
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// nameDetector acha os nomes conhecidos
+type nameDetector struct {
+	names []string
+	err   error
+}
+
+func (d nameDetector) Detect(_ context.Context, text string) ([]Span, error) {
+	var spans []Span
+	for _, name := range d.names {
+		if i := strings.Index(text, name); i >= 0 {
+			spans = append(spans, Span{Type: TypePersonName, Start: i, End: i + len(name)})
+		}
+	}
+	return spans, d.err
+}
+
+func TestRedact(t *testing.T) {
+	r := New(nameDetector{names: []string{"Maria Silva"}})
+	policy := Policy{Enabled: true}
+
+	got, spans, err := r.Redact(context.Background(), policy,
+		"sou Maria Silva, meu cartão é 4111 1111 1111 1111 e o email maria@example.com")
+	if err != nil {
+		t.Fatalf("Redact falhou: %v", err)
+	}
+	want := "sou [PERSON_NAME], meu cartão é [CARD_NUMBER] e o email [EMAIL]"
+	if got != want {
+		t.Errorf("obtido %q, esperado %q", got, want)
+	}
+	if len(spans) != 3 {
+		t.Errorf("esperados 3 trechos, obtidos %+v", spans)
+	}
+
+	// Números que não passam no Luhn não são cartões
+	if got, _, _ := r.Redact(context.Background(), Policy{Enabled: true, Types: []Type{TypeCardNumber}}, "pedido 1234 5678 9012 3456"); got != "pedido 1234 5678 9012 3456" {
+		t.Errorf("número sem Luhn redigido: %q", got)
+	}
+
+	if got, _, _ := r.Redact(context.Background(), Policy{}, "4111111111111111"); got != "4111111111111111" {
+		t.Errorf("política desabilitada não deveria redigir: %q", got)
+	}
+}
+
+func TestRedact_TenantRulesAndDetectorFailure(t *testing.T) {
+	r := New(nameDetector{err: errors.New("indisponível")})
+	policy := Policy{Enabled: true, Rules: []Rule{{Type: "order_id", Pattern: `PED-\d+`}}}
+
+	got, _, err := r.Redact(context.Background(), policy, "pedido PED-991 no cartão 4111111111111111")
+	if err == nil {
+		t.Error("esperado erro do detector")
+	}
+	if got != "pedido [ORDER_ID] no cartão [CARD_NUMBER]" {
+		t.Errorf("as regras deveriam valer mesmo com o detector fora: %q", got)
+	}
+
+	if err := (Policy{Rules: []Rule{{Type: "x", Pattern: "("}}}).Validate(); err == nil {
+		t.Error("esperado erro para regra inválida")
+	}
+}
+
+func TestSealer(t *testing.T) {
+	s, err := NewSealer(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	if err != nil {
+		t.Fatalf("NewSealer falhou: %v", err)
+	}
+	sealed, err := s.Seal("tenant-1", "4111 1111 1111 1111")
+	if err != nil {
+		t.Fatalf("Seal falhou: %v", err)
+	}
+	if got, err := s.Open("tenant-1", sealed); err != nil || got != "4111 1111 1111 1111" {
+		t.Errorf("Open = %q, %v", got, err)
+	}
+	if _, err := s.Open("tenant-2", sealed); err == nil {
+		t.Error("outro tenant não deveria abrir o original")
+	}
+}
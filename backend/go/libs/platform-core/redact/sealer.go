@@ -0,0 +1,69 @@
+package redact
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ElevatedRoles são as roles que podem ver o original de um texto redigido
+var ElevatedRoles = map[string]bool{
+	"admin": true,
+}
+
+// Sealer cifra os originais dos textos redigidos com AES-256-GCM
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// NewSealer cria um Sealer a partir de uma chave de 32 bytes em base64
+func NewSealer(encodedKey string) (*Sealer, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("chave de redação inválida: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("chave de redação deve ter 32 bytes, tem %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+// Seal cifra o original, preso ao tenant para não poder ser aberto em nome
+// de outro, e devolve em base64
+func (s *Sealer) Seal(tenantID, original string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(original), []byte(tenantID))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decifra um original produzido por Seal para tenantID
+func (s *Sealer) Open(tenantID, sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < s.aead.NonceSize() {
+		return "", errors.New("original cifrado muito curto")
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, []byte(tenantID))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,94 @@
+// Package reload recarrega a configuração dos serviços sem reiniciá-los: ao
+// receber SIGHUP, o serviço relê o ambiente (e, opcionalmente, um arquivo
+// .env) e aplica o que é seguro trocar em execução. O que exige reinício, como
+// portas e DSNs, apenas é informado.
+package reload
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// Func relê e aplica a configuração. Um erro mantém a configuração anterior.
+type Func func() error
+
+// Watch chama fn a cada SIGHUP até ctx terminar. Os erros de fn vão para
+// onError, se informado; o serviço segue com a configuração anterior.
+func Watch(ctx context.Context, fn Func, onError func(error)) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				if err := fn(); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// LoadEnvFile define as variáveis listadas em um arquivo .env (KEY=VALUE por
+// linha), sobrescrevendo as atuais. Linhas vazias e comentários (#) são
+// ignorados. Variáveis removidas do arquivo mantêm o último valor até o
+// próximo reinício.
+func LoadEnvFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("%s:%d: esperado KEY=VALUE", path, n)
+		}
+		if err := os.Setenv(key, unquote(strings.TrimSpace(value))); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Setting é uma configuração que só vale após reiniciar o serviço.
+type Setting struct {
+	Name     string
+	Old, New string
+}
+
+// Changed retorna os nomes das configurações cujo valor mudou.
+func Changed(settings []Setting) []string {
+	var names []string
+	for _, s := range settings {
+		if s.Old != s.New {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// unquote remove aspas simples ou duplas em volta do valor.
+func unquote(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
@@ -0,0 +1,81 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchCallsFuncOnSIGHUP(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloaded := make(chan struct{}, 1)
+	Watch(ctx, func() error {
+		reloaded <- struct{}{}
+		return nil
+	}, nil)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("SIGHUP não disparou o reload")
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.env")
+	content := "# comentário\n\nRELOAD_TEST_LEVEL=debug\nexport RELOAD_TEST_URL=\"http://a:8080\"\nRELOAD_TEST_EMPTY=\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RELOAD_TEST_LEVEL", "info")
+	t.Setenv("RELOAD_TEST_URL", "")
+	t.Setenv("RELOAD_TEST_EMPTY", "x")
+
+	if err := LoadEnvFile(path); err != nil {
+		t.Fatalf("LoadEnvFile: %v", err)
+	}
+
+	want := map[string]string{
+		"RELOAD_TEST_LEVEL": "debug",
+		"RELOAD_TEST_URL":   "http://a:8080",
+		"RELOAD_TEST_EMPTY": "",
+	}
+	for k, v := range want {
+		if got := os.Getenv(k); got != v {
+			t.Errorf("%s = %q, esperado %q", k, got, v)
+		}
+	}
+}
+
+func TestLoadEnvFileRejectsMalformedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.env")
+	if err := os.WriteFile(path, []byte("LOG_LEVEL\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadEnvFile(path); err == nil {
+		t.Fatal("esperado erro para linha sem =")
+	}
+}
+
+func TestChanged(t *testing.T) {
+	got := Changed([]Setting{
+		{Name: "SERVER_PORT", Old: "8080", New: "9090"},
+		{Name: "DATABASE_URL", Old: "postgres://a", New: "postgres://a"},
+		{Name: "REDIS_URL", Old: "redis://a", New: "redis://b"},
+	})
+
+	if want := []string{"SERVER_PORT", "REDIS_URL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed = %v, esperado %v", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+// Package requestid propaga o ID de correlação de uma requisição entre os
+// serviços. O ID chega no cabeçalho X-Request-ID (ou é gerado na borda), fica
+// no contexto da requisição sob uma chave própria do pacote e é repassado
+// nas chamadas de saída, para que os logs de todos os serviços tocados por
+// uma requisição tenham o mesmo ID.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// Header é o cabeçalho que carrega o ID
+const Header = "X-Request-ID"
+
+// maxLength limita IDs recebidos, que vão para logs e respostas
+const maxLength = 128
+
+// contextKey é a chave do ID no contexto; um tipo não exportado não colide
+// com chaves de outros pacotes
+type contextKey struct{}
+
+// WithID retorna uma cópia de ctx com o ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext retorna o ID do contexto, ou vazio se não houver
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New gera um ID (UUID v4)
+func New() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Bind lê o ID do cabeçalho de r, ou gera um se ele faltar ou for inválido,
+// devolve-o no cabeçalho da resposta e retorna r com o ID no contexto. É a
+// base de Middleware e dos middlewares de frameworks como o Gin.
+func Bind(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := r.Header.Get(Header)
+	if !valid(id) {
+		id = New()
+	}
+	w.Header().Set(Header, id)
+	return r.WithContext(WithID(r.Context(), id))
+}
+
+// Middleware aplica Bind a cada requisição
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, Bind(w, r))
+	})
+}
+
+// Transport repassa o ID do contexto de cada requisição no cabeçalho
+// X-Request-ID, a menos que a requisição já tenha um
+type Transport struct {
+	// Base faz as requisições; nil usa http.DefaultTransport
+	Base http.RoundTripper
+}
+
+// NewTransport envolve base com Transport. Tem a assinatura de
+// httpclient.Config.Wrap.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	return &Transport{Base: base}
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return base.RoundTrip(req)
+	}
+	// Um RoundTripper não deve alterar a requisição recebida
+	out := req.Clone(req.Context())
+	out.Header.Set(Header, id)
+	return base.RoundTrip(out)
+}
+
+// valid informa se um ID recebido pode ser usado: não vazio, curto e só com
+// caracteres ASCII visíveis, para não injetar nada em logs
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
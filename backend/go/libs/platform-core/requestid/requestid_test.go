@@ -0,0 +1,120 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestMiddleware_KeepsIncomingID(t *testing.T) {
+	var seen string
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Header, "req-123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if seen != "req-123" {
+		t.Errorf("ID no contexto = %q, esperado req-123", seen)
+	}
+	if got := rec.Header().Get(Header); got != "req-123" {
+		t.Errorf("ID na resposta = %q, esperado req-123", got)
+	}
+}
+
+func TestMiddleware_GeneratesMissingOrInvalidID(t *testing.T) {
+	for _, incoming := range []string{"", "com espaço", "quebra\nde linha", strings.Repeat("a", maxLength+1)} {
+		var seen string
+		h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = FromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(Header, incoming)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if !uuidPattern.MatchString(seen) {
+			t.Errorf("ID recebido %q: gerado %q, esperado um UUID", incoming, seen)
+		}
+		if rec.Header().Get(Header) != seen {
+			t.Errorf("ID recebido %q: resposta %q, contexto %q", incoming, rec.Header().Get(Header), seen)
+		}
+	}
+}
+
+// TestIDSurvivesHop encadeia dois serviços: o ID recebido pelo primeiro
+// chega ao segundo pela chamada feita com Transport
+func TestIDSurvivesHop(t *testing.T) {
+	var downstream string
+	second := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstream = FromContext(r.Context())
+	})))
+	defer second.Close()
+
+	client := &http.Client{Transport: NewTransport(nil)}
+	first := httptest.NewServer(Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, second.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("chamada ao segundo serviço: %v", err)
+			return
+		}
+		resp.Body.Close()
+	})))
+	defer first.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, first.URL, nil)
+	req.Header.Set(Header, "hop-42")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("chamada ao primeiro serviço: %v", err)
+	}
+	resp.Body.Close()
+
+	if downstream != "hop-42" {
+		t.Errorf("ID no segundo serviço = %q, esperado hop-42", downstream)
+	}
+}
+
+func TestTransport_KeepsExplicitHeader(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(Header)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequestWithContext(WithID(context.Background(), "from-context"), http.MethodGet, srv.URL, nil)
+	req.Header.Set(Header, "explicit")
+	resp, err := (&http.Client{Transport: NewTransport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if got != "explicit" {
+		t.Errorf("cabeçalho = %q, esperado explicit", got)
+	}
+	if req.Header.Get(Header) != "explicit" {
+		t.Error("a requisição original foi alterada")
+	}
+}
+
+func TestFromContext_Empty(t *testing.T) {
+	if id := FromContext(context.Background()); id != "" {
+		t.Errorf("FromContext sem ID = %q", id)
+	}
+	// Uma chave string "request_id" de outro pacote não é confundida com o ID
+	ctx := context.WithValue(context.Background(), "request_id", "other")
+	if id := FromContext(ctx); id != "" {
+		t.Errorf("FromContext com chave string = %q", id)
+	}
+}
@@ -0,0 +1,102 @@
+// Package residency define as regiões de residência de dados de um tenant e
+// escolhe o backend de armazenamento de cada região. É usado pelo
+// tenant-manager, que guarda a região do tenant, e pelos serviços que gravam
+// dados do tenant (gravações, transcrições, analytics).
+package residency
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Region é uma região de residência de dados
+type Region string
+
+// Regiões suportadas. Tenants sem região usam o backend padrão.
+const (
+	RegionUnset Region = ""
+	RegionUS    Region = "us"
+	RegionEU    Region = "eu"
+	RegionBR    Region = "br"
+)
+
+// Regions lista as regiões suportadas
+var Regions = []Region{RegionUS, RegionEU, RegionBR}
+
+// ErrCrossBoundary indica uma configuração que levaria dados do tenant para
+// fora da sua região
+var ErrCrossBoundary = errors.New("configuration would move data across residency regions")
+
+// Parse converte s em Region. String vazia é RegionUnset.
+func Parse(s string) (Region, error) {
+	r := Region(strings.ToLower(strings.TrimSpace(s)))
+	if r == RegionUnset {
+		return r, nil
+	}
+	for _, known := range Regions {
+		if r == known {
+			return r, nil
+		}
+	}
+	return RegionUnset, fmt.Errorf("invalid data residency region %q, use one of: us, eu, br", s)
+}
+
+// ValidateChange verifica se um tenant pode passar de from para to. A região
+// pode ser definida uma vez; trocá-la depois deixaria os dados já gravados na
+// região antiga ou exigiria movê-los, então é rejeitada.
+func ValidateChange(from, to Region) error {
+	if from == RegionUnset || from == to {
+		return nil
+	}
+	return fmt.Errorf("%w: tenant is in %s and cannot move to %s", ErrCrossBoundary, from, regionName(to))
+}
+
+func regionName(r Region) string {
+	if r == RegionUnset {
+		return "the default region"
+	}
+	return string(r)
+}
+
+// Router escolhe o backend T de cada região
+type Router[T any] struct {
+	fallback T
+	sinks    map[Region]T
+}
+
+// NewRouter cria um Router que usa fallback para tenants sem região
+func NewRouter[T any](fallback T) *Router[T] {
+	return &Router[T]{fallback: fallback, sinks: make(map[Region]T)}
+}
+
+// Add registra o backend de uma região
+func (r *Router[T]) Add(region Region, sink T) *Router[T] {
+	r.sinks[region] = sink
+	return r
+}
+
+// For retorna o backend da região. Uma região sem backend retorna
+// ErrCrossBoundary em vez do padrão, para nunca gravar fora da região.
+func (r *Router[T]) For(region Region) (T, error) {
+	if region == RegionUnset {
+		return r.fallback, nil
+	}
+	if sink, ok := r.sinks[region]; ok {
+		return sink, nil
+	}
+	var zero T
+	return zero, fmt.Errorf("%w: no backend configured for %s", ErrCrossBoundary, region)
+}
+
+// All retorna o backend padrão e os das regiões, por exemplo para consultas
+// que percorrem todos
+func (r *Router[T]) All() []T {
+	all := []T{r.fallback}
+	for _, region := range Regions {
+		if sink, ok := r.sinks[region]; ok {
+			all = append(all, sink)
+		}
+	}
+	return all
+}
@@ -0,0 +1,47 @@
+package residency
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]Region{"": RegionUnset, "EU": RegionEU, " br ": RegionBR} {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %q, %v; esperado %q", in, got, err, want)
+		}
+	}
+	if _, err := Parse("mars"); err == nil {
+		t.Error("esperado erro para região desconhecida")
+	}
+}
+
+func TestValidateChange(t *testing.T) {
+	if err := ValidateChange(RegionUnset, RegionEU); err != nil {
+		t.Errorf("definir a região deveria ser aceito: %v", err)
+	}
+	if err := ValidateChange(RegionEU, RegionEU); err != nil {
+		t.Errorf("manter a região deveria ser aceito: %v", err)
+	}
+	for _, to := range []Region{RegionUS, RegionUnset} {
+		if err := ValidateChange(RegionEU, to); !errors.Is(err, ErrCrossBoundary) {
+			t.Errorf("EU -> %q: esperado ErrCrossBoundary, obtido %v", to, err)
+		}
+	}
+}
+
+func TestRouter(t *testing.T) {
+	r := NewRouter("default").Add(RegionEU, "eu")
+
+	if sink, err := r.For(RegionEU); err != nil || sink != "eu" {
+		t.Errorf("EU: obtido %q, %v", sink, err)
+	}
+	if sink, err := r.For(RegionUnset); err != nil || sink != "default" {
+		t.Errorf("sem região: obtido %q, %v", sink, err)
+	}
+	// Sem backend na região o padrão não é usado
+	if _, err := r.For(RegionBR); !errors.Is(err, ErrCrossBoundary) {
+		t.Errorf("BR: esperado ErrCrossBoundary, obtido %v", err)
+	}
+}
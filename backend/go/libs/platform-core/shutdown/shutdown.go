@@ -0,0 +1,142 @@
+// Package shutdown coordena o desligamento gracioso dos serviços: o main
+// registra cada recurso em uma fase e, ao receber o sinal, chama Shutdown,
+// que fecha tudo na ordem correta dentro do prazo do contexto.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Phase define a ordem de fechamento. Fases menores fecham primeiro.
+type Phase int
+
+const (
+	// PhaseServers para de aceitar tráfego (HTTP, gRPC, WebSocket)
+	PhaseServers Phase = iota
+	// PhaseConsumers para os consumers, terminando as mensagens em processamento
+	PhaseConsumers
+	// PhasePublishers envia as mensagens pendentes e fecha os publishers
+	PhasePublishers
+	// PhaseStorage fecha pools de banco, Redis e afins
+	PhaseStorage
+	// PhaseTelemetry envia spans e métricas pendentes
+	PhaseTelemetry
+)
+
+// String retorna o nome da fase para logs
+func (p Phase) String() string {
+	switch p {
+	case PhaseServers:
+		return "servers"
+	case PhaseConsumers:
+		return "consumers"
+	case PhasePublishers:
+		return "publishers"
+	case PhaseStorage:
+		return "storage"
+	case PhaseTelemetry:
+		return "telemetry"
+	default:
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+}
+
+// Func fecha um recurso respeitando o prazo de ctx
+type Func func(ctx context.Context) error
+
+// Closer é um recurso com Close sem contexto, como o publisher do
+// platform-events, *pgxpool.Pool (via adaptador) ou *redis.Client
+type Closer interface {
+	Close() error
+}
+
+// entry é um recurso registrado
+type entry struct {
+	phase Phase
+	name  string
+	fn    Func
+}
+
+// Registry guarda os recursos a fechar no desligamento
+type Registry struct {
+	mu      sync.Mutex
+	entries []entry
+	once    sync.Once
+	err     error
+
+	// OnClose, se definido, é chamado após cada recurso, para log
+	OnClose func(phase Phase, name string, err error)
+}
+
+// NewRegistry cria um Registry vazio
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adiciona fn à fase. Dentro de uma fase os recursos fecham na ordem
+// inversa do registro, como defer: o que foi criado por último fecha primeiro.
+func (r *Registry) Register(phase Phase, name string, fn Func) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry{phase: phase, name: name, fn: fn})
+}
+
+// RegisterCloser adiciona um Closer à fase
+func (r *Registry) RegisterCloser(phase Phase, name string, c Closer) {
+	r.Register(phase, name, func(context.Context) error { return c.Close() })
+}
+
+// Shutdown fecha os recursos fase a fase. Um recurso que não termina dentro
+// do prazo de ctx é abandonado com erro e os seguintes continuam sendo
+// fechados, para que um consumer travado não impeça o flush do publisher.
+// Retorna todos os erros combinados; chamadas seguintes retornam o mesmo
+// resultado sem fechar nada de novo.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.once.Do(func() {
+		r.mu.Lock()
+		entries := make([]entry, len(r.entries))
+		copy(entries, r.entries)
+		r.mu.Unlock()
+
+		var errs []error
+		for _, e := range ordered(entries) {
+			err := closeWithin(ctx, e.fn)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", e.phase, e.name, err))
+			}
+			if r.OnClose != nil {
+				r.OnClose(e.phase, e.name, err)
+			}
+		}
+		r.err = errors.Join(errs...)
+	})
+	return r.err
+}
+
+// ordered retorna as entradas por fase crescente e, dentro da fase, na ordem
+// inversa do registro
+func ordered(entries []entry) []entry {
+	out := make([]entry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		out = append(out, entries[i])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].phase < out[j].phase })
+	return out
+}
+
+// closeWithin executa fn e desiste quando o prazo de ctx acaba
+func closeWithin(ctx context.Context, fn Func) error {
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
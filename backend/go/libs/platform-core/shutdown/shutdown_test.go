@@ -0,0 +1,116 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder registra a ordem em que os fakes foram fechados
+type recorder struct {
+	mu     sync.Mutex
+	closed []string
+}
+
+func (r *recorder) fake(name string, err error) Func {
+	return func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.closed = append(r.closed, name)
+		return err
+	}
+}
+
+// fakeCloser simula um recurso com Close() error
+type fakeCloser struct {
+	rec  *recorder
+	name string
+}
+
+func (c fakeCloser) Close() error { return c.rec.fake(c.name, nil)(context.Background()) }
+
+func TestShutdownOrder(t *testing.T) {
+	rec := &recorder{}
+	reg := NewRegistry()
+
+	// Registrados na ordem em que o main cria os recursos
+	reg.RegisterCloser(PhaseStorage, "postgres", fakeCloser{rec, "postgres"})
+	reg.RegisterCloser(PhaseStorage, "redis", fakeCloser{rec, "redis"})
+	reg.Register(PhaseTelemetry, "tracing", rec.fake("tracing", nil))
+	reg.RegisterCloser(PhasePublishers, "kafka-publisher", fakeCloser{rec, "kafka-publisher"})
+	reg.RegisterCloser(PhaseConsumers, "kafka-consumer", fakeCloser{rec, "kafka-consumer"})
+	reg.Register(PhaseServers, "http", rec.fake("http", nil))
+
+	if err := reg.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown falhou: %v", err)
+	}
+
+	expected := []string{"http", "kafka-consumer", "kafka-publisher", "redis", "postgres", "tracing"}
+	if !reflect.DeepEqual(rec.closed, expected) {
+		t.Errorf("Ordem de fechamento = %v, esperado %v", rec.closed, expected)
+	}
+
+	// Segunda chamada não fecha nada de novo
+	reg.Shutdown(context.Background())
+	if len(rec.closed) != len(expected) {
+		t.Errorf("Shutdown repetido fechou recursos novamente: %v", rec.closed)
+	}
+}
+
+func TestShutdownContinuesAfterErrors(t *testing.T) {
+	rec := &recorder{}
+	reg := NewRegistry()
+
+	reg.Register(PhaseConsumers, "consumer", rec.fake("consumer", errors.New("rebalance in progress")))
+	reg.Register(PhasePublishers, "publisher", rec.fake("publisher", nil))
+
+	var logged []string
+	reg.OnClose = func(phase Phase, name string, err error) {
+		logged = append(logged, phase.String()+"/"+name)
+	}
+
+	err := reg.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "consumers consumer: rebalance in progress") {
+		t.Errorf("Erro deveria identificar fase e recurso, obtido %v", err)
+	}
+	if !reflect.DeepEqual(rec.closed, []string{"consumer", "publisher"}) {
+		t.Errorf("Publisher deveria ser fechado mesmo com erro no consumer: %v", rec.closed)
+	}
+	if !reflect.DeepEqual(logged, []string{"consumers/consumer", "publishers/publisher"}) {
+		t.Errorf("OnClose inesperado: %v", logged)
+	}
+}
+
+func TestShutdownAbandonsStuckResources(t *testing.T) {
+	reg := NewRegistry()
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	reg.Register(PhaseConsumers, "stuck-consumer", func(context.Context) error {
+		<-stuck
+		return nil
+	})
+	called := make(chan struct{})
+	reg.Register(PhasePublishers, "publisher", func(context.Context) error {
+		close(called)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := reg.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Esperado DeadlineExceeded, obtido %v", err)
+	}
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Error("Publisher deveria ser chamado após o consumer travado")
+	}
+}
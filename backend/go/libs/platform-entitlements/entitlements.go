@@ -0,0 +1,191 @@
+// Package entitlements define o que cada plano do Serphona permite: features
+// habilitadas e limites numéricos. É a fonte única de verdade para
+// tenant-manager, billing e os serviços que precisam aplicar restrições.
+package entitlements
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Plan representa um plano de assinatura
+type Plan string
+
+const (
+	PlanStarter      Plan = "starter"
+	PlanProfessional Plan = "professional"
+	PlanEnterprise   Plan = "enterprise"
+)
+
+// Feature representa uma funcionalidade habilitada por plano
+type Feature string
+
+const (
+	// FeatureCallRecording permite gravar chamadas e correio de voz
+	FeatureCallRecording Feature = "call_recording"
+	// FeatureTranscription permite transcrever gravações
+	FeatureTranscription Feature = "transcription"
+	// FeatureCallQueue permite fila de espera quando não há capacidade
+	FeatureCallQueue Feature = "call_queue"
+	// FeatureCustomTools permite cadastrar tools próprias no tools-gateway
+	FeatureCustomTools Feature = "custom_tools"
+	// FeatureMFAEnforced obriga MFA para todos os usuários do tenant
+	FeatureMFAEnforced Feature = "mfa_enforced"
+)
+
+// LimitKey representa um limite numérico por plano
+type LimitKey string
+
+const (
+	LimitMaxConcurrentCalls LimitKey = "max_concurrent_calls"
+	LimitMaxTools           LimitKey = "max_tools"
+	LimitMaxUsers           LimitKey = "max_users"
+	LimitMaxAPIKeys         LimitKey = "max_api_keys"
+	LimitMaxCallsPerMonth   LimitKey = "max_calls_per_month"
+	LimitMaxMinutesPerMonth LimitKey = "max_minutes_per_month"
+	LimitMaxStorageGB       LimitKey = "max_storage_gb"
+	// LimitMaxConcurrentLLMRequests limita as respostas do agente geradas ao
+	// mesmo tempo no agent-orchestrator
+	LimitMaxConcurrentLLMRequests LimitKey = "max_concurrent_llm_requests"
+	// LimitAnalyticsQueriesPerMinute limita as consultas de dashboards e
+	// relatórios no analytics-query-service
+	LimitAnalyticsQueriesPerMinute LimitKey = "analytics_queries_per_minute"
+	// LimitMaxConcurrentAnalyticsQueries limita as consultas ao ClickHouse
+	// em andamento ao mesmo tempo
+	LimitMaxConcurrentAnalyticsQueries LimitKey = "max_concurrent_analytics_queries"
+)
+
+// Unlimited indica que o limite não se aplica
+const Unlimited = -1
+
+// Entitlements representa as features e limites de um plano
+type Entitlements struct {
+	Features map[Feature]bool
+	Limits   map[LimitKey]int
+}
+
+// plans é a matriz de entitlements de cada plano
+var plans = map[Plan]Entitlements{
+	PlanStarter: {
+		Features: map[Feature]bool{
+			FeatureCallRecording: false,
+			FeatureTranscription: false,
+			FeatureCallQueue:     false,
+			FeatureCustomTools:   false,
+			FeatureMFAEnforced:   false,
+		},
+		Limits: map[LimitKey]int{
+			LimitMaxConcurrentCalls:            5,
+			LimitMaxTools:                      3,
+			LimitMaxUsers:                      5,
+			LimitMaxAPIKeys:                    2,
+			LimitMaxCallsPerMonth:              1000,
+			LimitMaxMinutesPerMonth:            2000,
+			LimitMaxStorageGB:                  5,
+			LimitMaxConcurrentLLMRequests:      4,
+			LimitAnalyticsQueriesPerMinute:     60,
+			LimitMaxConcurrentAnalyticsQueries: 2,
+		},
+	},
+	PlanProfessional: {
+		Features: map[Feature]bool{
+			FeatureCallRecording: true,
+			FeatureTranscription: true,
+			FeatureCallQueue:     true,
+			FeatureCustomTools:   true,
+			FeatureMFAEnforced:   false,
+		},
+		Limits: map[LimitKey]int{
+			LimitMaxConcurrentCalls:            25,
+			LimitMaxTools:                      20,
+			LimitMaxUsers:                      25,
+			LimitMaxAPIKeys:                    10,
+			LimitMaxCallsPerMonth:              10000,
+			LimitMaxMinutesPerMonth:            20000,
+			LimitMaxStorageGB:                  50,
+			LimitMaxConcurrentLLMRequests:      16,
+			LimitAnalyticsQueriesPerMinute:     300,
+			LimitMaxConcurrentAnalyticsQueries: 5,
+		},
+	},
+	PlanEnterprise: {
+		Features: map[Feature]bool{
+			FeatureCallRecording: true,
+			FeatureTranscription: true,
+			FeatureCallQueue:     true,
+			FeatureCustomTools:   true,
+			FeatureMFAEnforced:   true,
+		},
+		Limits: map[LimitKey]int{
+			LimitMaxConcurrentCalls:            200,
+			LimitMaxTools:                      Unlimited,
+			LimitMaxUsers:                      Unlimited,
+			LimitMaxAPIKeys:                    50,
+			LimitMaxCallsPerMonth:              Unlimited,
+			LimitMaxMinutesPerMonth:            Unlimited,
+			LimitMaxStorageGB:                  500,
+			LimitMaxConcurrentLLMRequests:      64,
+			LimitAnalyticsQueriesPerMinute:     1200,
+			LimitMaxConcurrentAnalyticsQueries: 20,
+		},
+	},
+}
+
+// Plans retorna os planos conhecidos, do menor para o maior
+func Plans() []Plan {
+	return []Plan{PlanStarter, PlanProfessional, PlanEnterprise}
+}
+
+// ParsePlan converte uma string no plano correspondente (case-insensitive)
+func ParsePlan(s string) (Plan, error) {
+	plan := Plan(strings.ToLower(strings.TrimSpace(s)))
+	if !IsValidPlan(plan) {
+		return "", fmt.Errorf("invalid plan: %q", s)
+	}
+	return plan, nil
+}
+
+// IsValidPlan verifica se o plano existe
+func IsValidPlan(plan Plan) bool {
+	_, ok := plans[plan]
+	return ok
+}
+
+// Can verifica se o plano permite a feature. Planos desconhecidos não permitem nada.
+func Can(plan Plan, feature Feature) bool {
+	return plans[plan].Features[feature]
+}
+
+// Limit retorna o limite do plano para a chave, Unlimited se não houver limite
+// ou 0 para planos ou chaves desconhecidos.
+func Limit(plan Plan, key LimitKey) int {
+	return plans[plan].Limits[key]
+}
+
+// Within verifica se usar mais uma unidade ainda respeita o limite, dado o uso atual
+func Within(plan Plan, key LimitKey, current int) bool {
+	limit := Limit(plan, key)
+	if limit == Unlimited {
+		return true
+	}
+	return current < limit
+}
+
+// Get retorna uma cópia dos entitlements do plano
+func Get(plan Plan) (Entitlements, bool) {
+	e, ok := plans[plan]
+	if !ok {
+		return Entitlements{}, false
+	}
+
+	features := make(map[Feature]bool, len(e.Features))
+	for k, v := range e.Features {
+		features[k] = v
+	}
+	limits := make(map[LimitKey]int, len(e.Limits))
+	for k, v := range e.Limits {
+		limits[k] = v
+	}
+
+	return Entitlements{Features: features, Limits: limits}, true
+}
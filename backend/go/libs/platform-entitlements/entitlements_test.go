@@ -0,0 +1,168 @@
+package entitlements
+
+import "testing"
+
+func TestCan_PlanMatrix(t *testing.T) {
+	tests := []struct {
+		plan     Plan
+		feature  Feature
+		expected bool
+	}{
+		{PlanStarter, FeatureCallRecording, false},
+		{PlanStarter, FeatureTranscription, false},
+		{PlanStarter, FeatureCallQueue, false},
+		{PlanStarter, FeatureCustomTools, false},
+		{PlanStarter, FeatureMFAEnforced, false},
+
+		{PlanProfessional, FeatureCallRecording, true},
+		{PlanProfessional, FeatureTranscription, true},
+		{PlanProfessional, FeatureCallQueue, true},
+		{PlanProfessional, FeatureCustomTools, true},
+		{PlanProfessional, FeatureMFAEnforced, false},
+
+		{PlanEnterprise, FeatureCallRecording, true},
+		{PlanEnterprise, FeatureTranscription, true},
+		{PlanEnterprise, FeatureCallQueue, true},
+		{PlanEnterprise, FeatureCustomTools, true},
+		{PlanEnterprise, FeatureMFAEnforced, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.plan)+"/"+string(tt.feature), func(t *testing.T) {
+			if got := Can(tt.plan, tt.feature); got != tt.expected {
+				t.Errorf("Can(%s, %s) = %v, esperado %v", tt.plan, tt.feature, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLimit_PlanMatrix(t *testing.T) {
+	tests := []struct {
+		plan     Plan
+		key      LimitKey
+		expected int
+	}{
+		{PlanStarter, LimitMaxConcurrentCalls, 5},
+		{PlanStarter, LimitMaxTools, 3},
+		{PlanStarter, LimitMaxUsers, 5},
+		{PlanStarter, LimitMaxAPIKeys, 2},
+		{PlanStarter, LimitMaxCallsPerMonth, 1000},
+		{PlanStarter, LimitMaxMinutesPerMonth, 2000},
+		{PlanStarter, LimitMaxStorageGB, 5},
+		{PlanStarter, LimitMaxConcurrentLLMRequests, 4},
+		{PlanStarter, LimitAnalyticsQueriesPerMinute, 60},
+		{PlanStarter, LimitMaxConcurrentAnalyticsQueries, 2},
+
+		{PlanProfessional, LimitMaxConcurrentCalls, 25},
+		{PlanProfessional, LimitMaxTools, 20},
+		{PlanProfessional, LimitMaxUsers, 25},
+		{PlanProfessional, LimitMaxAPIKeys, 10},
+		{PlanProfessional, LimitMaxCallsPerMonth, 10000},
+		{PlanProfessional, LimitMaxMinutesPerMonth, 20000},
+		{PlanProfessional, LimitMaxStorageGB, 50},
+		{PlanProfessional, LimitMaxConcurrentLLMRequests, 16},
+		{PlanProfessional, LimitAnalyticsQueriesPerMinute, 300},
+		{PlanProfessional, LimitMaxConcurrentAnalyticsQueries, 5},
+
+		{PlanEnterprise, LimitMaxConcurrentCalls, 200},
+		{PlanEnterprise, LimitMaxTools, Unlimited},
+		{PlanEnterprise, LimitMaxUsers, Unlimited},
+		{PlanEnterprise, LimitMaxAPIKeys, 50},
+		{PlanEnterprise, LimitMaxCallsPerMonth, Unlimited},
+		{PlanEnterprise, LimitMaxMinutesPerMonth, Unlimited},
+		{PlanEnterprise, LimitMaxStorageGB, 500},
+		{PlanEnterprise, LimitMaxConcurrentLLMRequests, 64},
+		{PlanEnterprise, LimitAnalyticsQueriesPerMinute, 1200},
+		{PlanEnterprise, LimitMaxConcurrentAnalyticsQueries, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.plan)+"/"+string(tt.key), func(t *testing.T) {
+			if got := Limit(tt.plan, tt.key); got != tt.expected {
+				t.Errorf("Limit(%s, %s) = %d, esperado %d", tt.plan, tt.key, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPlans_CoverAllFeaturesAndLimits(t *testing.T) {
+	features := []Feature{FeatureCallRecording, FeatureTranscription, FeatureCallQueue, FeatureCustomTools, FeatureMFAEnforced}
+	keys := []LimitKey{
+		LimitMaxConcurrentCalls, LimitMaxTools, LimitMaxUsers, LimitMaxAPIKeys,
+		LimitMaxCallsPerMonth, LimitMaxMinutesPerMonth, LimitMaxStorageGB,
+		LimitMaxConcurrentLLMRequests, LimitAnalyticsQueriesPerMinute, LimitMaxConcurrentAnalyticsQueries,
+	}
+
+	for _, plan := range Plans() {
+		e, ok := Get(plan)
+		if !ok {
+			t.Fatalf("Plano %s sem entitlements", plan)
+		}
+		for _, f := range features {
+			if _, ok := e.Features[f]; !ok {
+				t.Errorf("Plano %s não define a feature %s", plan, f)
+			}
+		}
+		for _, k := range keys {
+			if _, ok := e.Limits[k]; !ok {
+				t.Errorf("Plano %s não define o limite %s", plan, k)
+			}
+		}
+	}
+}
+
+func TestUnknownPlan(t *testing.T) {
+	plan := Plan("free")
+
+	if IsValidPlan(plan) {
+		t.Error("Plano desconhecido não deveria ser válido")
+	}
+	if Can(plan, FeatureCallRecording) {
+		t.Error("Plano desconhecido não deveria permitir features")
+	}
+	if Limit(plan, LimitMaxConcurrentCalls) != 0 {
+		t.Error("Plano desconhecido deveria ter limite 0")
+	}
+	if Within(plan, LimitMaxConcurrentCalls, 0) {
+		t.Error("Plano desconhecido não deveria permitir uso")
+	}
+}
+
+func TestParsePlan(t *testing.T) {
+	plan, err := ParsePlan(" Professional ")
+	if err != nil {
+		t.Fatalf("ParsePlan falhou: %v", err)
+	}
+	if plan != PlanProfessional {
+		t.Errorf("Esperado %s, obtido %s", PlanProfessional, plan)
+	}
+
+	if _, err := ParsePlan("pro"); err == nil {
+		t.Error("ParsePlan deveria falhar para plano desconhecido")
+	}
+}
+
+func TestWithin(t *testing.T) {
+	if !Within(PlanStarter, LimitMaxTools, 2) {
+		t.Error("Starter deveria permitir a 3ª tool")
+	}
+	if Within(PlanStarter, LimitMaxTools, 3) {
+		t.Error("Starter não deveria permitir a 4ª tool")
+	}
+	if !Within(PlanEnterprise, LimitMaxTools, 100000) {
+		t.Error("Enterprise não deveria ter limite de tools")
+	}
+}
+
+func TestGet_ReturnsCopy(t *testing.T) {
+	e, _ := Get(PlanStarter)
+	e.Features[FeatureCallRecording] = true
+	e.Limits[LimitMaxTools] = 1000
+
+	if Can(PlanStarter, FeatureCallRecording) {
+		t.Error("Alterar a cópia não deveria alterar a matriz")
+	}
+	if Limit(PlanStarter, LimitMaxTools) != 3 {
+		t.Error("Alterar a cópia não deveria alterar os limites")
+	}
+}
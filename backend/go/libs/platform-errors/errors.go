@@ -0,0 +1,163 @@
+// Package errors define os erros de aplicação compartilhados pelos serviços
+// do Serphona: códigos tipados, construtores, wrapping compatível com
+// errors.Is/errors.As e o mapeamento de cada código para status HTTP e gRPC.
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode representa a categoria de um erro de aplicação
+type ErrorCode string
+
+const (
+	ErrInternal       ErrorCode = "internal_error"
+	ErrNotFound       ErrorCode = "not_found"
+	ErrConflict       ErrorCode = "conflict"
+	ErrValidation     ErrorCode = "validation_error"
+	ErrUnauthorized   ErrorCode = "unauthorized"
+	ErrForbidden      ErrorCode = "forbidden"
+	ErrBadRequest     ErrorCode = "bad_request"
+	ErrTooManyReqs    ErrorCode = "too_many_requests"
+	ErrServiceUnavail ErrorCode = "service_unavailable"
+)
+
+// AppError representa um erro de aplicação.
+// Code define os status HTTP/gRPC; Reason, quando preenchido, é o código
+// específico exposto ao cliente (ex.: "call_not_found") no lugar de Code.
+type AppError struct {
+	Code    ErrorCode
+	Reason  string
+	Message string
+	Err     error
+}
+
+// Error implementa a interface error
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap retorna o erro encapsulado, permitindo errors.Is e errors.As na cadeia
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatus retorna o status HTTP correspondente ao código do erro
+func (e *AppError) HTTPStatus() int {
+	return e.Code.HTTPStatus()
+}
+
+// GRPCStatus retorna o código gRPC correspondente ao código do erro
+func (e *AppError) GRPCStatus() GRPCCode {
+	return e.Code.GRPCStatus()
+}
+
+// WithReason retorna uma cópia do erro com o código específico exposto ao cliente
+func (e *AppError) WithReason(reason string) *AppError {
+	c := *e
+	c.Reason = reason
+	return &c
+}
+
+// NewAppError cria um novo erro de aplicação
+func NewAppError(code ErrorCode, message string, err error) *AppError {
+	return &AppError{
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+// Wrap encapsula err em um erro de aplicação com código e mensagem.
+// Retorna nil se err for nil.
+func Wrap(err error, code ErrorCode, message string) error {
+	if err == nil {
+		return nil
+	}
+	return NewAppError(code, message, err)
+}
+
+// NewInternalError cria um erro interno
+func NewInternalError(message string) *AppError {
+	return NewAppError(ErrInternal, message, nil)
+}
+
+// NewNotFoundError cria um erro de recurso não encontrado
+func NewNotFoundError(message string) *AppError {
+	return NewAppError(ErrNotFound, message, nil)
+}
+
+// NewConflictError cria um erro de conflito
+func NewConflictError(message string) *AppError {
+	return NewAppError(ErrConflict, message, nil)
+}
+
+// NewValidationError cria um erro de validação
+func NewValidationError(message string) *AppError {
+	return NewAppError(ErrValidation, message, nil)
+}
+
+// NewUnauthorizedError cria um erro de autenticação
+func NewUnauthorizedError(message string) *AppError {
+	return NewAppError(ErrUnauthorized, message, nil)
+}
+
+// NewForbiddenError cria um erro de autorização
+func NewForbiddenError(message string) *AppError {
+	return NewAppError(ErrForbidden, message, nil)
+}
+
+// NewBadRequestError cria um erro de requisição inválida
+func NewBadRequestError(message string) *AppError {
+	return NewAppError(ErrBadRequest, message, nil)
+}
+
+// NewTooManyRequestsError cria um erro de limite excedido
+func NewTooManyRequestsError(message string) *AppError {
+	return NewAppError(ErrTooManyReqs, message, nil)
+}
+
+// NewServiceUnavailableError cria um erro de serviço indisponível
+func NewServiceUnavailableError(message string) *AppError {
+	return NewAppError(ErrServiceUnavail, message, nil)
+}
+
+// As retorna o primeiro AppError da cadeia de err
+func As(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// CodeOf retorna o código do primeiro AppError da cadeia, ou ErrInternal
+func CodeOf(err error) ErrorCode {
+	if appErr, ok := As(err); ok {
+		return appErr.Code
+	}
+	return ErrInternal
+}
+
+// ReasonOf retorna o código exposto ao cliente: Reason do AppError, se houver,
+// senão seu Code. Erros que não são AppError retornam ErrInternal.
+func ReasonOf(err error) string {
+	if appErr, ok := As(err); ok && appErr.Reason != "" {
+		return appErr.Reason
+	}
+	return string(CodeOf(err))
+}
+
+// HTTPStatus retorna o status HTTP de err; erros desconhecidos são 500
+func HTTPStatus(err error) int {
+	return CodeOf(err).HTTPStatus()
+}
+
+// GRPCStatus retorna o código gRPC de err; erros desconhecidos são Internal
+func GRPCStatus(err error) GRPCCode {
+	return CodeOf(err).GRPCStatus()
+}
@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestStatusMappings(t *testing.T) {
+	tests := []struct {
+		code ErrorCode
+		http int
+		grpc GRPCCode
+	}{
+		{ErrNotFound, http.StatusNotFound, GRPCNotFound},
+		{ErrConflict, http.StatusConflict, GRPCAlreadyExists},
+		{ErrValidation, http.StatusBadRequest, GRPCInvalidArgument},
+		{ErrBadRequest, http.StatusBadRequest, GRPCInvalidArgument},
+		{ErrUnauthorized, http.StatusUnauthorized, GRPCUnauthenticated},
+		{ErrForbidden, http.StatusForbidden, GRPCPermissionDenied},
+		{ErrTooManyReqs, http.StatusTooManyRequests, GRPCResourceExhausted},
+		{ErrServiceUnavail, http.StatusServiceUnavailable, GRPCUnavailable},
+		{ErrInternal, http.StatusInternalServerError, GRPCInternal},
+		{ErrorCode("desconhecido"), http.StatusInternalServerError, GRPCInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			err := NewAppError(tt.code, "falha", nil)
+			if got := err.HTTPStatus(); got != tt.http {
+				t.Errorf("HTTPStatus() = %d, esperado %d", got, tt.http)
+			}
+			if got := err.GRPCStatus(); got != tt.grpc {
+				t.Errorf("GRPCStatus() = %d, esperado %d", got, tt.grpc)
+			}
+		})
+	}
+}
+
+func TestWrappedErrorsKeepStatus(t *testing.T) {
+	cause := errors.New("no rows in result set")
+	err := fmt.Errorf("get tenant: %w", Wrap(cause, ErrNotFound, "tenant not found"))
+
+	if HTTPStatus(err) != http.StatusNotFound || GRPCStatus(err) != GRPCNotFound {
+		t.Errorf("Status inesperados para erro encapsulado: %d/%d", HTTPStatus(err), GRPCStatus(err))
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is deveria encontrar a causa original")
+	}
+
+	appErr, ok := As(err)
+	if !ok || appErr.Message != "tenant not found" {
+		t.Errorf("As deveria encontrar o AppError, obtido %+v", appErr)
+	}
+	if appErr.Error() != "tenant not found: no rows in result set" {
+		t.Errorf("Mensagem inesperada: %q", appErr.Error())
+	}
+
+	var target *AppError
+	if !errors.As(err, &target) || target.Code != ErrNotFound {
+		t.Error("errors.As deveria encontrar o AppError")
+	}
+}
+
+func TestUnknownErrorsAreInternal(t *testing.T) {
+	err := errors.New("connection refused")
+
+	if HTTPStatus(err) != http.StatusInternalServerError || GRPCStatus(err) != GRPCInternal {
+		t.Error("Erros desconhecidos deveriam ser internos")
+	}
+	if CodeOf(err) != ErrInternal || ReasonOf(err) != string(ErrInternal) {
+		t.Errorf("Código inesperado: %s/%s", CodeOf(err), ReasonOf(err))
+	}
+	if Wrap(nil, ErrNotFound, "x") != nil {
+		t.Error("Wrap(nil) deveria retornar nil")
+	}
+}
+
+func TestReason(t *testing.T) {
+	sentinel := NewNotFoundError("call not found").WithReason("call_not_found")
+	err := fmt.Errorf("channel abc: %w", sentinel)
+
+	if !errors.Is(err, sentinel) {
+		t.Error("errors.Is deveria reconhecer o sentinel")
+	}
+	if ReasonOf(err) != "call_not_found" {
+		t.Errorf("ReasonOf = %q, esperado call_not_found", ReasonOf(err))
+	}
+	if ReasonOf(NewConflictError("x")) != string(ErrConflict) {
+		t.Error("Sem Reason, ReasonOf deveria retornar o código")
+	}
+	if err.Error() != "channel abc: call not found" {
+		t.Errorf("Mensagem inesperada: %q", err.Error())
+	}
+}
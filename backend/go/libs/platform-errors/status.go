@@ -0,0 +1,63 @@
+package errors
+
+import "net/http"
+
+// GRPCCode espelha google.golang.org/grpc/codes.Code com os mesmos valores,
+// para que serviços sem gRPC não dependam do módulo. Converta com
+// codes.Code(c) no interceptor.
+type GRPCCode uint32
+
+const (
+	GRPCInvalidArgument   GRPCCode = 3
+	GRPCNotFound          GRPCCode = 5
+	GRPCAlreadyExists     GRPCCode = 6
+	GRPCPermissionDenied  GRPCCode = 7
+	GRPCResourceExhausted GRPCCode = 8
+	GRPCInternal          GRPCCode = 13
+	GRPCUnavailable       GRPCCode = 14
+	GRPCUnauthenticated   GRPCCode = 16
+)
+
+// HTTPStatus retorna o status HTTP do código; códigos desconhecidos são 500
+func (c ErrorCode) HTTPStatus() int {
+	switch c {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrConflict:
+		return http.StatusConflict
+	case ErrValidation, ErrBadRequest:
+		return http.StatusBadRequest
+	case ErrUnauthorized:
+		return http.StatusUnauthorized
+	case ErrForbidden:
+		return http.StatusForbidden
+	case ErrTooManyReqs:
+		return http.StatusTooManyRequests
+	case ErrServiceUnavail:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GRPCStatus retorna o código gRPC do código; códigos desconhecidos são Internal
+func (c ErrorCode) GRPCStatus() GRPCCode {
+	switch c {
+	case ErrNotFound:
+		return GRPCNotFound
+	case ErrConflict:
+		return GRPCAlreadyExists
+	case ErrValidation, ErrBadRequest:
+		return GRPCInvalidArgument
+	case ErrUnauthorized:
+		return GRPCUnauthenticated
+	case ErrForbidden:
+		return GRPCPermissionDenied
+	case ErrTooManyReqs:
+		return GRPCResourceExhausted
+	case ErrServiceUnavail:
+		return GRPCUnavailable
+	default:
+		return GRPCInternal
+	}
+}
@@ -0,0 +1,231 @@
+package codec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/internal/protowire"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// transcriptSegment é um payload de alto volume com schema Protobuf:
+//
+//	message TranscriptSegment {
+//	  string speaker = 1;
+//	  string text = 2;
+//	  uint64 offset_ms = 3;
+//	}
+type transcriptSegment struct {
+	Speaker  string `json:"speaker"`
+	Text     string `json:"text"`
+	OffsetMs uint64 `json:"offset_ms"`
+}
+
+func (s *transcriptSegment) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = protowire.AppendString(b, 1, s.Speaker)
+	b = protowire.AppendString(b, 2, s.Text)
+	b = protowire.AppendVarintField(b, 3, s.OffsetMs)
+	return b, nil
+}
+
+func (s *transcriptSegment) UnmarshalProto(data []byte) error {
+	r := protowire.NewReader(data)
+	for !r.Done() {
+		num, typ, err := r.Tag()
+		if err != nil {
+			return err
+		}
+		switch {
+		case num == 3 && typ == protowire.WireVarint:
+			if s.OffsetMs, err = r.Varint(); err != nil {
+				return err
+			}
+		case typ == protowire.WireBytes:
+			v, err := r.Bytes()
+			if err != nil {
+				return err
+			}
+			if num == 1 {
+				s.Speaker = string(v)
+			} else if num == 2 {
+				s.Text = string(v)
+			}
+		default:
+			if err := r.Skip(typ); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newTranscriptEvent() (*types.Event, *transcriptSegment) {
+	segment := &transcriptSegment{Speaker: "customer", Text: "Quero cancelar meu plano", OffsetMs: 12840}
+	event := types.NewEvent("conversation.transcript.segment", "voice-gateway", segment).
+		WithTenantID("tenant-a").
+		WithUserID("user-1").
+		WithMetadata("channel", "voice").
+		WithTrace("trace-1", "span-1")
+	event.Timestamp = time.Date(2026, 10, 16, 14, 30, 5, 123456789, time.UTC)
+	return event, segment
+}
+
+func TestCodecs_RoundTripSameEventType(t *testing.T) {
+	for _, c := range []Codec{JSON, Protobuf} {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			event, segment := newTranscriptEvent()
+
+			data, err := c.Marshal(event)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			// O consumer resolve o codec pelo header
+			resolved, err := ForContentType(c.ContentType())
+			if err != nil {
+				t.Fatalf("ForContentType: %v", err)
+			}
+			got, err := resolved.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			if got.ID != event.ID || got.Type != event.Type || got.Source != event.Source ||
+				got.TenantID != event.TenantID || got.UserID != event.UserID ||
+				got.TraceID != event.TraceID || got.SpanID != event.SpanID || got.Version != event.Version {
+				t.Errorf("envelope diferente:\n got %+v\nwant %+v", got, event)
+			}
+			if !got.Timestamp.Equal(event.Timestamp) {
+				t.Errorf("timestamp = %s, esperado %s", got.Timestamp, event.Timestamp)
+			}
+			if !reflect.DeepEqual(got.Metadata, event.Metadata) {
+				t.Errorf("metadata = %v, esperado %v", got.Metadata, event.Metadata)
+			}
+
+			var decoded transcriptSegment
+			if err := DecodeData(got, &decoded); err != nil {
+				t.Fatalf("DecodeData: %v", err)
+			}
+			if decoded != *segment {
+				t.Errorf("payload = %+v, esperado %+v", decoded, *segment)
+			}
+		})
+	}
+}
+
+func TestProtobuf_RoundTripConversationEvents(t *testing.T) {
+	startedAt := time.Date(2026, 10, 16, 14, 30, 5, 123456789, time.UTC)
+	tests := []struct {
+		name    string
+		payload ProtoMarshaler
+		decoded ProtoMessage
+	}{
+		{
+			name: topics.ConversationStarted,
+			payload: events.ConversationStartedEvent{
+				ConversationID: "conv-1",
+				AgentID:        "agent-1",
+				TenantID:       "tenant-a",
+				CustomerID:     "customer-1",
+				Channel:        "voice",
+				Language:       "pt-BR",
+				StartedAt:      startedAt,
+			},
+			decoded: &events.ConversationStartedEvent{},
+		},
+		{
+			name: topics.ConversationEnded,
+			payload: &events.ConversationEndedEvent{
+				ConversationID: "conv-1",
+				AgentID:        "agent-1",
+				TenantID:       "tenant-a",
+				Duration:       4*time.Minute + 12*time.Second + 345*time.Millisecond,
+				MessageCount:   18,
+				Resolution:     "resolved",
+				CustomerRating: 5,
+				EndedAt:        startedAt.Add(4 * time.Minute),
+			},
+			decoded: &events.ConversationEndedEvent{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Publicado por valor ou por ponteiro, como fazem os serviços
+			data, err := Protobuf.Marshal(events.NewEvent(tt.name, "agent-orchestrator", tt.payload).WithTenantID("tenant-a"))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			got, err := Protobuf.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if err := DecodeData(got, tt.decoded); err != nil {
+				t.Fatalf("DecodeData: %v", err)
+			}
+
+			want := reflect.Indirect(reflect.ValueOf(tt.payload)).Interface()
+			if decoded := reflect.Indirect(reflect.ValueOf(tt.decoded)).Interface(); !reflect.DeepEqual(decoded, want) {
+				t.Errorf("payload = %+v, esperado %+v", decoded, want)
+			}
+		})
+	}
+}
+
+func TestProtobuf_IsSmallerThanJSON(t *testing.T) {
+	event, _ := newTranscriptEvent()
+
+	jsonData, _ := JSON.Marshal(event)
+	protoData, err := Protobuf.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if len(protoData) >= len(jsonData) {
+		t.Errorf("protobuf (%d bytes) deveria ser menor que JSON (%d bytes)", len(protoData), len(jsonData))
+	}
+}
+
+func TestProtobuf_SkipsUnknownFields(t *testing.T) {
+	event, _ := newTranscriptEvent()
+	data, _ := Protobuf.Marshal(event)
+
+	// Campos de uma versão futura do schema: varint, fixed32, fixed64 e bytes
+	data = protowire.AppendVarintField(data, 20, 7)
+	data = protowire.AppendTag(data, 21, protowire.WireFixed32)
+	data = append(data, 1, 2, 3, 4)
+	data = protowire.AppendTag(data, 22, protowire.WireFixed64)
+	data = append(data, 1, 2, 3, 4, 5, 6, 7, 8)
+	data = protowire.AppendString(data, 23, "novo")
+
+	got, err := Protobuf.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.ID != event.ID {
+		t.Errorf("ID = %s, esperado %s", got.ID, event.ID)
+	}
+}
+
+func TestProtobuf_Errors(t *testing.T) {
+	event := types.NewEvent("tenant.created", "tenant-manager", map[string]string{"name": "Acme"})
+	if _, err := Protobuf.Marshal(event); !errors.Is(err, ErrDataNotProto) {
+		t.Errorf("esperado ErrDataNotProto, obtido %v", err)
+	}
+
+	valid, _ := Protobuf.Marshal(types.NewEvent("tenant.created", "tenant-manager", nil))
+	if _, err := Protobuf.Unmarshal(valid[:len(valid)-1]); !errors.Is(err, ErrMalformedProtobuf) {
+		t.Errorf("esperado ErrMalformedProtobuf para mensagem truncada, obtido %v", err)
+	}
+
+	if _, err := ForContentType("application/avro"); !errors.Is(err, ErrUnknownContentType) {
+		t.Errorf("esperado ErrUnknownContentType, obtido %v", err)
+	}
+	if c, err := ForContentType(""); err != nil || c != JSON {
+		t.Errorf("mensagem sem content-type deveria usar JSON, obtido %v, %v", c, err)
+	}
+}
@@ -0,0 +1,176 @@
+package codec
+
+import (
+	"fmt"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/internal/protowire"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// ProtoMessage é implementado pelos payloads de eventos publicados com o codec
+// Protobuf, como os eventos de conversa do pacote events. Tipos gerados pelo
+// protoc o implementam delegando para proto.Marshal e proto.Unmarshal.
+type ProtoMessage interface {
+	ProtoMarshaler
+	UnmarshalProto(data []byte) error
+}
+
+// ProtoMarshaler é a metade de ProtoMessage usada na publicação. Basta que o
+// payload a implemente, o que permite publicar eventos por valor quando
+// UnmarshalProto tem receiver ponteiro.
+type ProtoMarshaler interface {
+	MarshalProto() ([]byte, error)
+}
+
+// Números dos campos de event.proto
+const (
+	fieldID        = 1
+	fieldType      = 2
+	fieldSource    = 3
+	fieldTimestamp = 4
+	fieldTenantID  = 5
+	fieldUserID    = 6
+	fieldData      = 7
+	fieldMetadata  = 8
+	fieldTraceID   = 9
+	fieldSpanID    = 10
+	fieldVersion   = 11
+)
+
+// protobufCodec serializa o envelope conforme event.proto. O payload (Data)
+// precisa implementar ProtoMarshaler, ou já ser []byte; na leitura, Data chega
+// como []byte para ser convertido com DecodeData.
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (protobufCodec) Marshal(event *types.Event) ([]byte, error) {
+	var data []byte
+	switch d := event.Data.(type) {
+	case nil:
+	case ProtoMarshaler:
+		var err error
+		if data, err = d.MarshalProto(); err != nil {
+			return nil, fmt.Errorf("failed to marshal event data: %w", err)
+		}
+	case []byte:
+		data = d
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrDataNotProto, event.Data)
+	}
+
+	var b []byte
+	b = protowire.AppendString(b, fieldID, event.ID)
+	b = protowire.AppendString(b, fieldType, event.Type)
+	b = protowire.AppendString(b, fieldSource, event.Source)
+	b = protowire.AppendTimestamp(b, fieldTimestamp, event.Timestamp)
+	b = protowire.AppendString(b, fieldTenantID, event.TenantID)
+	b = protowire.AppendString(b, fieldUserID, event.UserID)
+	b = protowire.AppendBytes(b, fieldData, data)
+	for k, v := range event.Metadata {
+		var entry []byte
+		entry = protowire.AppendString(entry, 1, k)
+		entry = protowire.AppendString(entry, 2, v)
+		b = protowire.AppendTag(b, fieldMetadata, protowire.WireBytes)
+		b = protowire.AppendVarint(b, uint64(len(entry)))
+		b = append(b, entry...)
+	}
+	b = protowire.AppendString(b, fieldTraceID, event.TraceID)
+	b = protowire.AppendString(b, fieldSpanID, event.SpanID)
+	b = protowire.AppendString(b, fieldVersion, event.Version)
+
+	return b, nil
+}
+
+func (protobufCodec) Unmarshal(data []byte) (*types.Event, error) {
+	event := &types.Event{}
+	r := protowire.NewReader(data)
+
+	for !r.Done() {
+		num, typ, err := r.Tag()
+		if err != nil {
+			return nil, err
+		}
+
+		// Campos desconhecidos são ignorados, permitindo evoluir o schema
+		if typ != protowire.WireBytes {
+			if err := r.Skip(typ); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		value, err := r.Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		switch num {
+		case fieldID:
+			event.ID = string(value)
+		case fieldType:
+			event.Type = string(value)
+		case fieldSource:
+			event.Source = string(value)
+		case fieldTimestamp:
+			if event.Timestamp, err = protowire.DecodeTimestamp(value); err != nil {
+				return nil, err
+			}
+		case fieldTenantID:
+			event.TenantID = string(value)
+		case fieldUserID:
+			event.UserID = string(value)
+		case fieldData:
+			event.Data = append([]byte(nil), value...)
+		case fieldMetadata:
+			k, v, err := decodeMapEntry(value)
+			if err != nil {
+				return nil, err
+			}
+			if event.Metadata == nil {
+				event.Metadata = make(map[string]string)
+			}
+			event.Metadata[k] = v
+		case fieldTraceID:
+			event.TraceID = string(value)
+		case fieldSpanID:
+			event.SpanID = string(value)
+		case fieldVersion:
+			event.Version = string(value)
+		}
+	}
+
+	return event, nil
+}
+
+// decodeMapEntry lê uma entrada de map<string, string>
+func decodeMapEntry(data []byte) (string, string, error) {
+	var key, value string
+	r := protowire.NewReader(data)
+
+	for !r.Done() {
+		num, typ, err := r.Tag()
+		if err != nil {
+			return "", "", err
+		}
+		if typ != protowire.WireBytes {
+			if err := r.Skip(typ); err != nil {
+				return "", "", err
+			}
+			continue
+		}
+
+		b, err := r.Bytes()
+		if err != nil {
+			return "", "", err
+		}
+		switch num {
+		case 1:
+			key = string(b)
+		case 2:
+			value = string(b)
+		}
+	}
+
+	return key, value, nil
+}
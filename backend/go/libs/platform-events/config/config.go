@@ -21,11 +21,23 @@ type Config struct {
 	PublisherBatchTimeout  time.Duration
 	PublisherMaxRetries    int
 	PublisherRetryInterval time.Duration
+	// PublisherCompression é o codec das mensagens: none, gzip, snappy, lz4
+	// ou zstd. Vazio usa snappy.
+	PublisherCompression string
+	// PublisherMaxMessageBytes limita o tamanho de cada evento serializado;
+	// zero usa DefaultMaxMessageBytes. Deve ficar abaixo do
+	// max.message.bytes dos tópicos.
+	PublisherMaxMessageBytes int
 
 	// Consumer configuration
 	ConsumerMaxRetries    int
 	ConsumerRetryInterval time.Duration
 	ConsumerConcurrency   int
+	// ConsumerStartOffset define onde um consumer group sem offsets
+	// commitados começa a ler: earliest, latest ou um timestamp RFC 3339.
+	// Grupos que já commitaram offsets sempre retomam do último commit.
+	// Vazio usa latest.
+	ConsumerStartOffset string
 
 	// General configuration
 	ServiceName string
@@ -33,6 +45,25 @@ type Config struct {
 	Debug       bool
 }
 
+// Codecs de compressão aceitos em PublisherCompression
+const (
+	CompressionNone   = "none"
+	CompressionGzip   = "gzip"
+	CompressionSnappy = "snappy"
+	CompressionLz4    = "lz4"
+	CompressionZstd   = "zstd"
+)
+
+// Posições iniciais aceitas em ConsumerStartOffset, além de um timestamp
+const (
+	StartOffsetEarliest = "earliest"
+	StartOffsetLatest   = "latest"
+)
+
+// DefaultMaxMessageBytes é o limite padrão de tamanho de um evento, o mesmo
+// max.message.bytes padrão dos brokers Kafka
+const DefaultMaxMessageBytes = 1048576
+
 // DefaultConfig retorna uma configuração padrão
 func DefaultConfig() *Config {
 	return &Config{
@@ -45,9 +76,11 @@ func DefaultConfig() *Config {
 		PublisherBatchTimeout:  100 * time.Millisecond,
 		PublisherMaxRetries:    3,
 		PublisherRetryInterval: 1 * time.Second,
+		PublisherCompression:   CompressionSnappy,
 		ConsumerMaxRetries:     3,
 		ConsumerRetryInterval:  1 * time.Second,
 		ConsumerConcurrency:    5,
+		ConsumerStartOffset:    StartOffsetLatest,
 		ServiceName:            "unknown",
 		Environment:            "development",
 		Debug:                  false,
@@ -110,6 +143,18 @@ func LoadFromEnv() *Config {
 		}
 	}
 
+	// Publisher compression
+	if compression := os.Getenv("KAFKA_COMPRESSION"); compression != "" {
+		cfg.PublisherCompression = strings.ToLower(compression)
+	}
+
+	// Publisher max message size
+	if maxBytes := os.Getenv("KAFKA_MAX_MESSAGE_BYTES"); maxBytes != "" {
+		if n, err := strconv.Atoi(maxBytes); err == nil {
+			cfg.PublisherMaxMessageBytes = n
+		}
+	}
+
 	// Consumer concurrency
 	if concurrency := os.Getenv("KAFKA_CONSUMER_CONCURRENCY"); concurrency != "" {
 		if c, err := strconv.Atoi(concurrency); err == nil {
@@ -117,9 +162,28 @@ func LoadFromEnv() *Config {
 		}
 	}
 
+	// Consumer start offset
+	if startOffset := os.Getenv("KAFKA_CONSUMER_START_OFFSET"); startOffset != "" {
+		cfg.ConsumerStartOffset = startOffset
+	}
+
 	return cfg
 }
 
+// ConsumerStartTime retorna o timestamp de ConsumerStartOffset, se for um
+func (c *Config) ConsumerStartTime() (time.Time, bool) {
+	switch c.ConsumerStartOffset {
+	case "", StartOffsetEarliest, StartOffsetLatest:
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339, c.ConsumerStartOffset)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 // parseBool converte string para bool
 func parseBool(s string) bool {
 	s = strings.ToLower(s)
@@ -140,6 +204,24 @@ func (c *Config) Validate() error {
 		return ErrNoServiceName
 	}
 
+	switch c.PublisherCompression {
+	case "", CompressionNone, CompressionGzip, CompressionSnappy, CompressionLz4, CompressionZstd:
+	default:
+		return ErrInvalidCompression
+	}
+
+	if c.PublisherMaxMessageBytes < 0 {
+		return ErrInvalidMaxMessageBytes
+	}
+
+	switch c.ConsumerStartOffset {
+	case "", StartOffsetEarliest, StartOffsetLatest:
+	default:
+		if _, ok := c.ConsumerStartTime(); !ok {
+			return ErrInvalidStartOffset
+		}
+	}
+
 	return nil
 }
 
@@ -148,6 +230,10 @@ var (
 	ErrNoBrokers     = &ConfigError{Message: "no Kafka brokers configured"}
 	ErrNoGroupID     = &ConfigError{Message: "no group ID configured"}
 	ErrNoServiceName = &ConfigError{Message: "no service name configured"}
+
+	ErrInvalidCompression     = &ConfigError{Message: "compression must be none, gzip, snappy, lz4 or zstd"}
+	ErrInvalidMaxMessageBytes = &ConfigError{Message: "max message bytes must not be negative"}
+	ErrInvalidStartOffset     = &ConfigError{Message: "consumer start offset must be earliest, latest or an RFC 3339 timestamp"}
 )
 
 // ConfigError representa um erro de configuração
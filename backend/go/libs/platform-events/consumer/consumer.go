@@ -4,28 +4,93 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
 	"github.com/serphona/serphona/backend/go/libs/platform-events/config"
 	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
 )
 
+// Offsets especiais aceitos por NewReplay
+const (
+	FirstOffset = kafka.FirstOffset
+	LastOffset  = kafka.LastOffset
+)
+
+// messageReader é o subconjunto de *kafka.Reader usado pelo consumer
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	SetOffsetAt(ctx context.Context, t time.Time) error
+	Close() error
+	Stats() kafka.ReaderStats
+}
+
+// Decoder converte o valor de uma mensagem Kafka em evento. Mensagens com
+// header content-type usam o codec correspondente; as demais usam o Decoder,
+// que por padrão é types.FromJSON. Serviços que consomem tópicos com outro
+// envelope podem trocá-lo com SetDecoder.
+type Decoder func(data []byte) (*types.Event, error)
+
+// DeadLetterFunc recebe o evento cujo handler esgotou as tentativas, junto com
+// a mensagem original e o último erro (ErrHandlerPanic se o handler entrou em
+// panic). Um erro retornado é apenas registrado em log.
+type DeadLetterFunc func(ctx context.Context, msg kafka.Message, event *types.Event, err error) error
+
+// Metrics são contadores acumulados desde a criação do consumer, para
+// exportação pelo serviço (por exemplo, como counters Prometheus)
+type Metrics struct {
+	HandlerErrors uint64 // execuções de handler que falharam, incluindo panics
+	HandlerPanics uint64 // execuções de handler que entraram em panic
+	DeadLettered  uint64 // eventos entregues ao DeadLetterFunc
+}
+
+// EventConsumer é o contrato de assinatura implementado por Consumer.
+// Serviços que dependem dele podem ser testados com
+// platformeventstest.FakeConsumer, sem Kafka.
+type EventConsumer interface {
+	Subscribe(eventType string, handler types.EventHandler)
+	SubscribeWithFilter(eventType string, filter types.EventFilter, handler types.EventHandler)
+	Start() error
+	Close() error
+}
+
+var _ EventConsumer = (*Consumer)(nil)
+
 // Consumer é responsável por consumir eventos do Kafka
 type Consumer struct {
-	reader   *kafka.Reader
+	reader   messageReader
 	config   *config.Config
 	handlers map[string][]types.EventHandler
 	filters  map[string][]types.EventFilter
-	mu       sync.RWMutex
-	wg       sync.WaitGroup
-	ctx      context.Context
-	cancel   context.CancelFunc
-	closed   bool
+	decode   Decoder
+	dead     DeadLetterFunc
+	replay   bool
+	// tenants restringe o consumo aos tenants de SetTenantScope; nil aceita
+	// todos
+	tenants map[string]struct{}
+	// Contadores expostos por Metrics
+	handlerErrors atomic.Uint64
+	handlerPanics atomic.Uint64
+	deadLettered  atomic.Uint64
+	// startTime descarta mensagens anteriores a ele quando
+	// ConsumerStartOffset é um timestamp
+	startTime time.Time
+	mu        sync.RWMutex
+	wg        sync.WaitGroup
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closed    bool
 }
 
-// New cria um novo consumer
+// New cria um novo consumer. cfg.ConsumerStartOffset só vale para um consumer
+// group sem offsets commitados; um grupo existente retoma do último commit.
+// Com um timestamp, o grupo começa do início dos tópicos e descarta (e
+// commita) as mensagens anteriores a ele.
 func New(cfg *config.Config, topics []string) (*Consumer, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
@@ -43,25 +108,153 @@ func New(cfg *config.Config, topics []string) (*Consumer, error) {
 		MaxBytes:       10e6, // 10MB
 		MaxWait:        1 * time.Second,
 		SessionTimeout: cfg.SessionTimeout,
-		StartOffset:    kafka.LastOffset,
+		StartOffset:    startOffset(cfg),
+	})
+
+	c := newConsumer(cfg, reader)
+	if t, ok := cfg.ConsumerStartTime(); ok {
+		c.startTime = t
+	}
+
+	if cfg.Debug {
+		log.Printf("[platform-events] Consumer initialized for topics: %v", topics)
+	}
+
+	return c, nil
+}
+
+// NewReplay cria um consumer que relê uma partição a partir de offset, sem
+// consumer group e sem commitar offsets, para backfills. offset pode ser
+// FirstOffset para reler a partição inteira. Os handlers devem ser
+// idempotentes, pois os eventos relidos já podem ter sido processados.
+func NewReplay(cfg *config.Config, topic string, partition int, offset int64) (*Consumer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if topic == "" {
+		return nil, ErrNoTopics
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   cfg.Brokers,
+		Topic:     topic,
+		Partition: partition,
+		MinBytes:  10e3, // 10KB
+		MaxBytes:  10e6, // 10MB
+		MaxWait:   1 * time.Second,
 	})
+	if err := reader.SetOffset(offset); err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to set replay offset: %w", err)
+	}
+
+	c := newConsumer(cfg, reader)
+	c.replay = true
+
+	if cfg.Debug {
+		log.Printf("[platform-events] Replay consumer initialized for %s[%d] from offset %d", topic, partition, offset)
+	}
+
+	return c, nil
+}
 
+// startOffset converte cfg.ConsumerStartOffset no offset inicial do grupo
+func startOffset(cfg *config.Config) int64 {
+	if cfg.ConsumerStartOffset == config.StartOffsetEarliest {
+		return kafka.FirstOffset
+	}
+	if _, ok := cfg.ConsumerStartTime(); ok {
+		return kafka.FirstOffset
+	}
+	return kafka.LastOffset
+}
+
+// newConsumer cria o consumer sobre um reader já configurado
+func newConsumer(cfg *config.Config, reader messageReader) *Consumer {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	c := &Consumer{
+	return &Consumer{
 		reader:   reader,
 		config:   cfg,
 		handlers: make(map[string][]types.EventHandler),
 		filters:  make(map[string][]types.EventFilter),
+		decode:   types.FromJSON,
 		ctx:      ctx,
 		cancel:   cancel,
 	}
+}
 
-	if cfg.Debug {
-		log.Printf("[platform-events] Consumer initialized for topics: %v", topics)
+// SetDecoder troca o decodificador das mensagens sem header content-type. Deve
+// ser chamado antes de Start.
+func (c *Consumer) SetDecoder(decode Decoder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.decode = decode
+}
+
+// SeekToTimestamp reposiciona um consumer de replay na primeira mensagem
+// publicada em ts ou depois, para reprocessar eventos a partir de um ponto no
+// tempo (por exemplo, reconstruir rollups após um bug). Pode ser chamado antes
+// de Start ou durante o consumo.
+//
+// Consumers de grupo retornam ErrSeekRequiresReplay: seus offsets commitados
+// pertencem ao grupo e seriam sobrescritos pelo próximo commit de qualquer
+// membro. Para reprocessar com um grupo, use um GroupID novo com
+// ConsumerStartOffset igual ao timestamp.
+func (c *Consumer) SeekToTimestamp(ctx context.Context, ts time.Time) error {
+	if !c.replay {
+		return ErrSeekRequiresReplay
 	}
 
-	return c, nil
+	if err := c.reader.SetOffsetAt(ctx, ts); err != nil {
+		return fmt.Errorf("failed to seek to %s: %w", ts.Format(time.RFC3339), err)
+	}
+
+	if c.config.Debug {
+		log.Printf("[platform-events] Replay consumer seeked to %s", ts.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// SetTenantScope restringe o consumer aos eventos dos tenants informados,
+// descartando (e commitando) os demais antes de qualquer handler. Mensagens
+// com header tenant_id fora do escopo nem são desserializadas, o que torna o
+// consumo barato para serviços que só atendem poucos tenants; mensagens sem o
+// header são desserializadas e verificadas pelo TenantID do evento. Eventos
+// sem tenant ficam fora do escopo. Deve ser chamado antes de Start.
+//
+// Para restringir só alguns handlers, use SubscribeWithFilter com
+// types.TenantFilter.
+func (c *Consumer) SetTenantScope(tenantIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tenants = make(map[string]struct{}, len(tenantIDs))
+	for _, id := range tenantIDs {
+		c.tenants[id] = struct{}{}
+	}
+}
+
+// SetDeadLetter define para onde vão os eventos cujo handler esgotou as
+// tentativas. Sem ele, a falha é apenas registrada em log e a mensagem é
+// commitada. Deve ser chamado antes de Start.
+func (c *Consumer) SetDeadLetter(dead DeadLetterFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dead = dead
+}
+
+// Metrics retorna os contadores de falhas de handlers
+func (c *Consumer) Metrics() Metrics {
+	return Metrics{
+		HandlerErrors: c.handlerErrors.Load(),
+		HandlerPanics: c.handlerPanics.Load(),
+		DeadLettered:  c.deadLettered.Load(),
+	}
 }
 
 // Subscribe registra um handler para um tipo de evento específico
@@ -144,6 +337,11 @@ func (c *Consumer) worker(id int) {
 				continue
 			}
 
+			// Replays não usam consumer group, então não há offset a commitar
+			if c.replay {
+				continue
+			}
+
 			// Commitar mensagem
 			if err := c.reader.CommitMessages(c.ctx, msg); err != nil {
 				log.Printf("[platform-events] Worker %d: error committing message: %v", id, err)
@@ -154,12 +352,49 @@ func (c *Consumer) worker(id int) {
 
 // processMessage processa uma mensagem do Kafka
 func (c *Consumer) processMessage(msg kafka.Message) error {
+	// Anterior ao timestamp inicial: commitar sem processar
+	if !c.startTime.IsZero() && msg.Time.Before(c.startTime) {
+		return nil
+	}
+
+	c.mu.RLock()
+	decode := c.decode
+	tenants := c.tenants
+	c.mu.RUnlock()
+
+	// Fast path: descartar pelo header sem desserializar
+	if tenants != nil {
+		if tenantID, ok := headerValue(msg, "tenant_id"); ok {
+			if _, in := tenants[string(tenantID)]; !in {
+				return nil
+			}
+		}
+	}
+
+	// O header content-type, quando presente, define o codec
+	if contentType, ok := headerValue(msg, codec.HeaderContentType); ok {
+		c, err := codec.ForContentType(string(contentType))
+		if err != nil {
+			return err
+		}
+		decode = c.Unmarshal
+	}
+
 	// Desserializar evento
-	event, err := types.FromJSON(msg.Value)
+	event, err := decode(msg.Value)
 	if err != nil {
 		return fmt.Errorf("failed to deserialize event: %w", err)
 	}
 
+	if tenants != nil {
+		if _, in := tenants[event.TenantID]; !in {
+			if c.config.Debug {
+				log.Printf("[platform-events] Event %s outside tenant scope", event.ID)
+			}
+			return nil
+		}
+	}
+
 	if c.config.Debug {
 		log.Printf("[platform-events] Processing event: type=%s, id=%s, topic=%s",
 			event.Type, event.ID, msg.Topic)
@@ -193,6 +428,7 @@ func (c *Consumer) processMessage(msg kafka.Message) error {
 		// Executar handler com retry
 		if err := c.executeWithRetry(handler, event); err != nil {
 			log.Printf("[platform-events] Handler error for event %s: %v", event.ID, err)
+			c.deadLetter(msg, event, err)
 			// Continuar executando outros handlers
 		}
 	}
@@ -200,6 +436,16 @@ func (c *Consumer) processMessage(msg kafka.Message) error {
 	return nil
 }
 
+// headerValue retorna o valor do header key da mensagem
+func headerValue(msg kafka.Message, key string) ([]byte, bool) {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return h.Value, true
+		}
+	}
+	return nil, false
+}
+
 // executeWithRetry executa um handler com retry
 func (c *Consumer) executeWithRetry(handler types.EventHandler, event *types.Event) error {
 	var lastErr error
@@ -209,11 +455,12 @@ func (c *Consumer) executeWithRetry(handler types.EventHandler, event *types.Eve
 			time.Sleep(c.config.ConsumerRetryInterval)
 		}
 
-		err := handler(event)
+		err := c.runHandler(handler, event)
 		if err == nil {
 			return nil
 		}
 
+		c.handlerErrors.Add(1)
 		lastErr = err
 		if c.config.Debug {
 			log.Printf("[platform-events] Handler retry %d/%d for event %s: %v",
@@ -225,6 +472,38 @@ func (c *Consumer) executeWithRetry(handler types.EventHandler, event *types.Eve
 		c.config.ConsumerMaxRetries, lastErr)
 }
 
+// runHandler executa o handler convertendo um panic em ErrHandlerPanic, para
+// que ele siga o caminho de retry e DLQ sem derrubar o worker
+func (c *Consumer) runHandler(handler types.EventHandler, event *types.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.handlerPanics.Add(1)
+			log.Printf("[platform-events] Handler panic for event %s (type=%s): %v\n%s",
+				event.ID, event.Type, r, debug.Stack())
+			err = fmt.Errorf("%w: %v", ErrHandlerPanic, r)
+		}
+	}()
+
+	return handler(event)
+}
+
+// deadLetter entrega ao DeadLetterFunc um evento que esgotou as tentativas
+func (c *Consumer) deadLetter(msg kafka.Message, event *types.Event, cause error) {
+	c.mu.RLock()
+	dead := c.dead
+	c.mu.RUnlock()
+
+	if dead == nil {
+		return
+	}
+
+	if err := dead(c.ctx, msg, event, cause); err != nil {
+		log.Printf("[platform-events] Failed to dead-letter event %s: %v", event.ID, err)
+		return
+	}
+	c.deadLettered.Add(1)
+}
+
 // Close fecha o consumer
 func (c *Consumer) Close() error {
 	c.mu.Lock()
@@ -262,4 +541,7 @@ func (c *Consumer) Stats() kafka.ReaderStats {
 var (
 	ErrNoTopics       = fmt.Errorf("no topics configured")
 	ErrConsumerClosed = fmt.Errorf("consumer is closed")
+
+	ErrSeekRequiresReplay = fmt.Errorf("seek is only supported by replay consumers")
+	ErrHandlerPanic       = fmt.Errorf("event handler panicked")
 )
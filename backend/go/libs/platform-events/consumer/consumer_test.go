@@ -0,0 +1,397 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/config"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// memoryReader simula uma partição Kafka em memória
+type memoryReader struct {
+	mu        sync.Mutex
+	messages  []kafka.Message
+	next      int
+	committed []int64
+	closed    chan struct{}
+}
+
+func newMemoryReader(messages ...kafka.Message) *memoryReader {
+	for i := range messages {
+		messages[i].Offset = int64(i)
+	}
+	return &memoryReader{messages: messages, closed: make(chan struct{})}
+}
+
+func (r *memoryReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.mu.Lock()
+	if r.next < len(r.messages) {
+		msg := r.messages[r.next]
+		r.next++
+		r.mu.Unlock()
+		return msg, nil
+	}
+	r.mu.Unlock()
+
+	<-ctx.Done()
+	return kafka.Message{}, ctx.Err()
+}
+
+func (r *memoryReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range msgs {
+		r.committed = append(r.committed, m.Offset)
+	}
+	return nil
+}
+
+func (r *memoryReader) SetOffsetAt(ctx context.Context, t time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next = len(r.messages)
+	for i, m := range r.messages {
+		if !m.Time.Before(t) {
+			r.next = i
+			break
+		}
+	}
+	return nil
+}
+
+func (r *memoryReader) Close() error             { return nil }
+func (r *memoryReader) Stats() kafka.ReaderStats { return kafka.ReaderStats{} }
+
+// eventMessage serializa um evento como publicado em at
+func eventMessage(t *testing.T, id string, at time.Time) kafka.Message {
+	t.Helper()
+
+	event := types.NewEvent("call.ended", "voice-gateway", nil)
+	event.ID = id
+	value, err := event.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+	return kafka.Message{Topic: "serphona.call.ended", Value: value, Time: at}
+}
+
+// consumeAll inicia o consumer e espera want eventos
+func consumeAll(t *testing.T, c *Consumer, want int) []string {
+	t.Helper()
+
+	var mu sync.Mutex
+	var got []string
+	done := make(chan struct{})
+	c.Subscribe("call.ended", func(e *types.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e.ID)
+		if len(got) == want {
+			close(done)
+		}
+		return nil
+	})
+
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("esperados %d eventos, recebidos %v", want, got)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(got)
+	return got
+}
+
+func testConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.ConsumerConcurrency = 1
+	return cfg
+}
+
+func TestSeekToTimestamp_ReplaysFromPointInTime(t *testing.T) {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	reader := newMemoryReader(
+		eventMessage(t, "evt-1", base),
+		eventMessage(t, "evt-2", base.Add(time.Hour)),
+		eventMessage(t, "evt-3", base.Add(2*time.Hour)),
+		eventMessage(t, "evt-4", base.Add(3*time.Hour)),
+	)
+	c := newConsumer(testConfig(), reader)
+	c.replay = true
+
+	if err := c.SeekToTimestamp(context.Background(), base.Add(90*time.Minute)); err != nil {
+		t.Fatalf("SeekToTimestamp: %v", err)
+	}
+
+	got := consumeAll(t, c, 2)
+	if len(got) != 2 || got[0] != "evt-3" || got[1] != "evt-4" {
+		t.Errorf("eventos reprocessados = %v, esperado [evt-3 evt-4]", got)
+	}
+	// Replays não commitam offsets
+	if len(reader.committed) != 0 {
+		t.Errorf("replay não deveria commitar, commitou %v", reader.committed)
+	}
+}
+
+func TestSeekToTimestamp_RejectsGroupConsumer(t *testing.T) {
+	c := newConsumer(testConfig(), newMemoryReader())
+
+	if err := c.SeekToTimestamp(context.Background(), time.Now()); !errors.Is(err, ErrSeekRequiresReplay) {
+		t.Errorf("esperado ErrSeekRequiresReplay, obtido %v", err)
+	}
+}
+
+func TestStartOffsetTimestamp_SkipsAndCommitsOlderMessages(t *testing.T) {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	cfg := testConfig()
+	cfg.ConsumerStartOffset = base.Add(30 * time.Minute).Format(time.RFC3339)
+
+	reader := newMemoryReader(
+		eventMessage(t, "evt-1", base),
+		eventMessage(t, "evt-2", base.Add(time.Hour)),
+	)
+	c := newConsumer(cfg, reader)
+	c.startTime, _ = cfg.ConsumerStartTime()
+
+	got := consumeAll(t, c, 1)
+	if len(got) != 1 || got[0] != "evt-2" {
+		t.Errorf("eventos processados = %v, esperado [evt-2]", got)
+	}
+	// A mensagem descartada também é commitada para o grupo seguir adiante
+	if len(reader.committed) != 2 {
+		t.Errorf("esperados 2 commits, obtidos %v", reader.committed)
+	}
+}
+
+func TestStartOffset(t *testing.T) {
+	tests := map[string]int64{
+		"":                     kafka.LastOffset,
+		"latest":               kafka.LastOffset,
+		"earliest":             kafka.FirstOffset,
+		"2026-10-01T12:00:00Z": kafka.FirstOffset,
+	}
+	for value, want := range tests {
+		cfg := testConfig()
+		cfg.ConsumerStartOffset = value
+		if got := startOffset(cfg); got != want {
+			t.Errorf("startOffset(%q) = %d, esperado %d", value, got, want)
+		}
+	}
+
+	cfg := testConfig()
+	cfg.ConsumerStartOffset = "ontem"
+	if err := cfg.Validate(); !errors.Is(err, config.ErrInvalidStartOffset) {
+		t.Errorf("esperado ErrInvalidStartOffset, obtido %v", err)
+	}
+}
+
+func TestHandlerPanic_WorkerSurvivesAndEventIsDeadLettered(t *testing.T) {
+	cfg := testConfig()
+	cfg.ConsumerRetryInterval = 0
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	reader := newMemoryReader(eventMessage(t, "evt-panic", base), eventMessage(t, "evt-ok", base))
+	c := newConsumer(cfg, reader)
+
+	dead := make(chan error, 1)
+	c.SetDeadLetter(func(ctx context.Context, msg kafka.Message, event *types.Event, err error) error {
+		if event.ID != "evt-panic" || msg.Offset != 0 {
+			t.Errorf("DLQ recebeu %s (offset %d), esperado evt-panic", event.ID, msg.Offset)
+		}
+		dead <- err
+		return nil
+	})
+
+	processed := make(chan string, 1)
+	c.Subscribe("call.ended", func(e *types.Event) error {
+		if e.ID == "evt-panic" {
+			panic("nil map")
+		}
+		processed <- e.ID
+		return nil
+	})
+
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer c.Close()
+
+	select {
+	case err := <-dead:
+		if !errors.Is(err, ErrHandlerPanic) {
+			t.Errorf("esperado ErrHandlerPanic, obtido %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("evento com panic não chegou à DLQ")
+	}
+
+	// O único worker continua vivo e processa a mensagem seguinte
+	select {
+	case id := <-processed:
+		if id != "evt-ok" {
+			t.Errorf("evento processado = %s, esperado evt-ok", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker não sobreviveu ao panic")
+	}
+
+	m := c.Metrics()
+	if m.HandlerPanics != uint64(cfg.ConsumerMaxRetries) || m.HandlerErrors != m.HandlerPanics || m.DeadLettered != 1 {
+		t.Errorf("métricas inesperadas: %+v", m)
+	}
+}
+
+// tenantMessage serializa um evento de tenantID com o header tenant_id, como o
+// publisher faz
+func tenantMessage(tb testing.TB, tenantID string, withHeader bool) kafka.Message {
+	tb.Helper()
+
+	event := types.NewEvent("call.ended", "voice-gateway", map[string]interface{}{
+		"transcript": strings.Repeat("Olá, gostaria de falar com o suporte. ", 50),
+		"duration":   184,
+	}).WithTenantID(tenantID)
+	value, err := event.ToJSON()
+	if err != nil {
+		tb.Fatalf("ToJSON: %v", err)
+	}
+
+	msg := kafka.Message{Topic: "serphona.call.ended", Value: value}
+	if withHeader {
+		msg.Headers = []kafka.Header{{Key: "tenant_id", Value: []byte(tenantID)}}
+	}
+	return msg
+}
+
+func TestSetTenantScope_SkipsOtherTenantsBeforeDecoding(t *testing.T) {
+	c := newConsumer(testConfig(), newMemoryReader())
+	c.SetTenantScope("tenant-a")
+
+	decoded := 0
+	c.SetDecoder(func(data []byte) (*types.Event, error) {
+		decoded++
+		return types.FromJSON(data)
+	})
+	var handled []string
+	c.Subscribe("call.ended", func(e *types.Event) error {
+		handled = append(handled, e.TenantID)
+		return nil
+	})
+
+	for _, msg := range []kafka.Message{
+		tenantMessage(t, "tenant-b", true),  // descartada pelo header
+		tenantMessage(t, "tenant-b", false), // sem header: desserializada e descartada
+		tenantMessage(t, "tenant-a", true),
+	} {
+		if err := c.processMessage(msg); err != nil {
+			t.Fatalf("processMessage: %v", err)
+		}
+	}
+
+	if decoded != 2 {
+		t.Errorf("esperadas 2 desserializações, obtidas %d", decoded)
+	}
+	if len(handled) != 1 || handled[0] != "tenant-a" {
+		t.Errorf("eventos processados = %v, esperado [tenant-a]", handled)
+	}
+}
+
+func TestTenantFilter_WithSubscribeWithFilter(t *testing.T) {
+	c := newConsumer(testConfig(), newMemoryReader())
+
+	var handled []string
+	c.SubscribeWithFilter("call.ended", types.TenantFilter("tenant-a", "tenant-c"), func(e *types.Event) error {
+		handled = append(handled, e.TenantID)
+		return nil
+	})
+
+	for _, tenant := range []string{"tenant-a", "tenant-b", "tenant-c", ""} {
+		if err := c.processMessage(tenantMessage(t, tenant, true)); err != nil {
+			t.Fatalf("processMessage: %v", err)
+		}
+	}
+
+	if len(handled) != 2 || handled[0] != "tenant-a" || handled[1] != "tenant-c" {
+		t.Errorf("eventos processados = %v, esperado [tenant-a tenant-c]", handled)
+	}
+}
+
+// BenchmarkTenantFiltering compara o descarte de eventos de outro tenant pelo
+// header (SetTenantScope) com o filtro aplicado após o parse completo do JSON
+// (SubscribeWithFilter + TenantFilter)
+func BenchmarkTenantFiltering(b *testing.B) {
+	msg := tenantMessage(b, "tenant-b", true)
+	handler := func(*types.Event) error { return nil }
+
+	b.Run("header_fast_path", func(b *testing.B) {
+		c := newConsumer(testConfig(), newMemoryReader())
+		c.SetTenantScope("tenant-a")
+		c.Subscribe("call.ended", handler)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := c.processMessage(msg); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("filter_after_decode", func(b *testing.B) {
+		c := newConsumer(testConfig(), newMemoryReader())
+		c.SubscribeWithFilter("call.ended", types.TenantFilter("tenant-a"), handler)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := c.processMessage(msg); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestProcessMessage_NegotiatesCodecByContentType(t *testing.T) {
+	c := newConsumer(testConfig(), newMemoryReader())
+
+	var got *types.Event
+	c.Subscribe("call.ended", func(e *types.Event) error {
+		got = e
+		return nil
+	})
+
+	event := types.NewEvent("call.ended", "voice-gateway", []byte{0x08, 0x01}).WithTenantID("tenant-a")
+	value, err := codec.Protobuf.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	msg := kafka.Message{
+		Value:   value,
+		Headers: []kafka.Header{{Key: codec.HeaderContentType, Value: []byte(codec.ContentTypeProtobuf)}},
+	}
+
+	if err := c.processMessage(msg); err != nil {
+		t.Fatalf("processMessage: %v", err)
+	}
+	if got == nil || got.ID != event.ID || got.TenantID != "tenant-a" {
+		t.Errorf("evento decodificado = %+v, esperado %s", got, event.ID)
+	}
+
+	msg.Headers[0].Value = []byte("application/avro")
+	if err := c.processMessage(msg); !errors.Is(err, codec.ErrUnknownContentType) {
+		t.Errorf("esperado ErrUnknownContentType, obtido %v", err)
+	}
+}
@@ -52,6 +52,27 @@ type SubscriptionCreatedEvent struct {
 	Currency       string    `json:"currency"`
 }
 
+// TrialEventData representa o período de teste de uma assinatura, nos eventos
+// de início e de fim próximo do teste
+type TrialEventData struct {
+	SubscriptionID string    `json:"subscription_id"`
+	TenantID       string    `json:"tenant_id"`
+	Plan           string    `json:"plan"`
+	TrialDays      int       `json:"trial_days"`
+	TrialEndsAt    time.Time `json:"trial_ends_at"`
+}
+
+// BillingReconciledEvent representa a correção de uma assinatura local que
+// divergia do Stripe; Previous e Changes trazem os valores antigos e novos
+// dos campos corrigidos
+type BillingReconciledEvent struct {
+	SubscriptionID string            `json:"subscription_id"`
+	TenantID       string            `json:"tenant_id"`
+	Previous       map[string]string `json:"previous"`
+	Changes        map[string]string `json:"changes"`
+	ReconciledAt   time.Time         `json:"reconciled_at"`
+}
+
 // PaymentSucceededEvent representa um evento de pagamento bem-sucedido
 type PaymentSucceededEvent struct {
 	PaymentID      string    `json:"payment_id"`
@@ -118,6 +139,19 @@ type ConversationEndedEvent struct {
 	EndedAt        time.Time     `json:"ended_at"`
 }
 
+// ConversationSummarizedEvent representa o resumo gerado ao fim de uma conversa
+type ConversationSummarizedEvent struct {
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	CallID         string    `json:"call_id,omitempty"`
+	Summary        string    `json:"summary"`
+	Variant        string    `json:"variant,omitempty"` // variante do agente em teste A/B
+	Disposition    string    `json:"disposition"`
+	Model          string    `json:"model"`
+	SummarizedAt   time.Time `json:"summarized_at"`
+}
+
 // MessageSentEvent representa um evento de mensagem enviada
 type MessageSentEvent struct {
 	MessageID      string                 `json:"message_id"`
@@ -130,6 +164,98 @@ type MessageSentEvent struct {
 	SentAt         time.Time              `json:"sent_at"`
 }
 
+// TransferDecidedEvent representa a decisão de transferir uma conversa para um humano
+type TransferDecidedEvent struct {
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	CallID         string    `json:"call_id,omitempty"`
+	Trigger        string    `json:"trigger"`
+	Intent         string    `json:"intent,omitempty"`
+	TransferType   string    `json:"transfer_type,omitempty"`
+	Target         string    `json:"target,omitempty"`
+	Reason         string    `json:"reason"`
+	Transferred    bool      `json:"transferred"`
+	Error          string    `json:"error,omitempty"`
+	DecidedAt      time.Time `json:"decided_at"`
+}
+
+// GraphTransitionEvent representa a passagem de um turno entre nós do grafo de agentes
+type GraphTransitionEvent struct {
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	From           string    `json:"from"`
+	To             string    `json:"to"`
+	Reason         string    `json:"reason"` // loop_guard quando a passagem foi recusada
+	Intent         string    `json:"intent,omitempty"`
+	Hop            int       `json:"hop"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// TurnCancelledEvent representa um turno interrompido antes da resposta, p.ex. quando quem ligou desliga
+type TurnCancelledEvent struct {
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	Reason         string    `json:"reason"` // requested ou disconnected
+	CancelledAt    time.Time `json:"cancelled_at"`
+}
+
+// LLMCostEvent representa o custo de uma chamada a um LLM em uma conversa
+type LLMCostEvent struct {
+	ConversationID   string    `json:"conversation_id"`
+	TenantID         string    `json:"tenant_id"`
+	AgentID          string    `json:"agent_id"`
+	Variant          string    `json:"variant,omitempty"` // variante do agente em teste A/B
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	Cost             float64   `json:"cost"`
+	Currency         string    `json:"currency"`
+	OccurredAt       time.Time `json:"occurred_at"`
+}
+
+// LLMFailoverEvent representa uma chamada a um LLM atendida por um provedor de reserva
+type LLMFailoverEvent struct {
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	FromProvider   string    `json:"from_provider"`
+	FromModel      string    `json:"from_model"`
+	ToProvider     string    `json:"to_provider"`
+	ToModel        string    `json:"to_model"` // pode ser um modelo mais barato
+	Reason         string    `json:"reason"`   // error ou unhealthy
+	Error          string    `json:"error,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// ComplianceViolationEvent representa a violação de uma regra de segurança em uma conversa
+type ComplianceViolationEvent struct {
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	Kind           string    `json:"kind"`
+	Topic          string    `json:"topic,omitempty"`
+	Role           string    `json:"role,omitempty"`
+	Source         string    `json:"source,omitempty"`
+	Action         string    `json:"action"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// ModerationFlaggedEvent representa uma mensagem de uma conversa barrada pela
+// moderação. Não carrega o texto, apenas as categorias detectadas.
+type ModerationFlaggedEvent struct {
+	ConversationID string    `json:"conversation_id"`
+	TenantID       string    `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	Role           string    `json:"role"`
+	Categories     []string  `json:"categories"`
+	Provider       string    `json:"provider"`
+	Action         string    `json:"action"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
 // ToolInvokedEvent representa um evento de invocação de ferramenta
 type ToolInvokedEvent struct {
 	ToolID         string                 `json:"tool_id"`
@@ -153,6 +279,21 @@ type ToolCompletedEvent struct {
 	CompletedAt    time.Time              `json:"completed_at"`
 }
 
+// MetricAnomalyEvent representa uma métrica de chamadas de um tenant fora do
+// esperado em relação à linha de base
+type MetricAnomalyEvent struct {
+	TenantID    string    `json:"tenant_id"`
+	Metric      string    `json:"metric"`
+	Value       float64   `json:"value"`
+	Baseline    float64   `json:"baseline"`
+	StdDev      float64   `json:"stddev"`
+	ZScore      float64   `json:"z_score"`
+	Threshold   string    `json:"threshold"` // "z_score" ou "absolute"
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
 // SystemErrorEvent representa um evento de erro do sistema
 type SystemErrorEvent struct {
 	ErrorID    string                 `json:"error_id"`
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"github.com/serphona/serphona/backend/go/libs/platform-events/internal/protowire"
+)
+
+// MarshalProto serializa o evento conforme ConversationStarted em events.proto
+func (e ConversationStartedEvent) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = protowire.AppendString(b, 1, e.ConversationID)
+	b = protowire.AppendString(b, 2, e.AgentID)
+	b = protowire.AppendString(b, 3, e.TenantID)
+	b = protowire.AppendString(b, 4, e.CustomerID)
+	b = protowire.AppendString(b, 5, e.Channel)
+	b = protowire.AppendString(b, 6, e.Language)
+	b = protowire.AppendTimestamp(b, 7, e.StartedAt)
+	return b, nil
+}
+
+// UnmarshalProto lê um ConversationStarted de events.proto
+func (e *ConversationStartedEvent) UnmarshalProto(data []byte) error {
+	*e = ConversationStartedEvent{}
+	return readFields(data, func(r *protowire.Reader, num, typ int) (bool, error) {
+		if typ != protowire.WireBytes {
+			return false, nil
+		}
+		value, err := r.Bytes()
+		if err != nil {
+			return true, err
+		}
+		switch num {
+		case 1:
+			e.ConversationID = string(value)
+		case 2:
+			e.AgentID = string(value)
+		case 3:
+			e.TenantID = string(value)
+		case 4:
+			e.CustomerID = string(value)
+		case 5:
+			e.Channel = string(value)
+		case 6:
+			e.Language = string(value)
+		case 7:
+			e.StartedAt, err = protowire.DecodeTimestamp(value)
+		}
+		return true, err
+	})
+}
+
+// MarshalProto serializa o evento conforme ConversationEnded em events.proto
+func (e ConversationEndedEvent) MarshalProto() ([]byte, error) {
+	var b []byte
+	b = protowire.AppendString(b, 1, e.ConversationID)
+	b = protowire.AppendString(b, 2, e.AgentID)
+	b = protowire.AppendString(b, 3, e.TenantID)
+	b = protowire.AppendDuration(b, 4, e.Duration)
+	b = protowire.AppendVarintField(b, 5, uint64(int64(e.MessageCount)))
+	b = protowire.AppendString(b, 6, e.Resolution)
+	b = protowire.AppendVarintField(b, 7, uint64(int64(e.CustomerRating)))
+	b = protowire.AppendTimestamp(b, 8, e.EndedAt)
+	return b, nil
+}
+
+// UnmarshalProto lê um ConversationEnded de events.proto
+func (e *ConversationEndedEvent) UnmarshalProto(data []byte) error {
+	*e = ConversationEndedEvent{}
+	return readFields(data, func(r *protowire.Reader, num, typ int) (bool, error) {
+		switch {
+		case typ == protowire.WireVarint && (num == 5 || num == 7):
+			v, err := r.Varint()
+			if num == 5 {
+				e.MessageCount = int(int64(v))
+			} else {
+				e.CustomerRating = int(int64(v))
+			}
+			return true, err
+		case typ == protowire.WireBytes:
+			value, err := r.Bytes()
+			if err != nil {
+				return true, err
+			}
+			switch num {
+			case 1:
+				e.ConversationID = string(value)
+			case 2:
+				e.AgentID = string(value)
+			case 3:
+				e.TenantID = string(value)
+			case 4:
+				e.Duration, err = protowire.DecodeDuration(value)
+			case 6:
+				e.Resolution = string(value)
+			case 8:
+				e.EndedAt, err = protowire.DecodeTimestamp(value)
+			}
+			return true, err
+		}
+		return false, nil
+	})
+}
+
+// readFields percorre os campos da mensagem chamando field para cada um.
+// Campos que field não consome (retorna false) são ignorados, permitindo
+// evoluir o schema.
+func readFields(data []byte, field func(r *protowire.Reader, num, typ int) (bool, error)) error {
+	r := protowire.NewReader(data)
+	for !r.Done() {
+		num, typ, err := r.Tag()
+		if err != nil {
+			return err
+		}
+		consumed, err := field(r, num, typ)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			if err := r.Skip(typ); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Package protowire codifica e lê o wire format do Protobuf. É usado pelo
+// envelope do codec Protobuf e pelos payloads de eventos que têm schema
+// Protobuf, sem depender do código gerado pelo protoc.
+package protowire
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Wire types do Protobuf
+const (
+	WireVarint  = 0
+	WireFixed64 = 1
+	WireBytes   = 2
+	WireFixed32 = 5
+)
+
+// ErrMalformed é retornado ao ler uma mensagem inválida
+var ErrMalformed = errors.New("malformed protobuf message")
+
+// AppendVarint codifica v como varint
+func AppendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// AppendTag codifica o número e o wire type de um campo
+func AppendTag(b []byte, num, typ int) []byte {
+	return AppendVarint(b, uint64(num)<<3|uint64(typ))
+}
+
+// AppendVarintField codifica um campo varint; zero é omitido, como no proto3
+func AppendVarintField(b []byte, num int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = AppendTag(b, num, WireVarint)
+	return AppendVarint(b, v)
+}
+
+// AppendBool codifica um campo bool; false é omitido
+func AppendBool(b []byte, num int, v bool) []byte {
+	if !v {
+		return b
+	}
+	return AppendVarintField(b, num, 1)
+}
+
+// AppendBytes codifica um campo length-delimited; vazio é omitido
+func AppendBytes(b []byte, num int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = AppendTag(b, num, WireBytes)
+	b = AppendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendString codifica um campo string; vazio é omitido
+func AppendString(b []byte, num int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = AppendTag(b, num, WireBytes)
+	b = AppendVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// AppendTimestamp codifica um google.protobuf.Timestamp; o instante zero é
+// omitido
+func AppendTimestamp(b []byte, num int, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	return AppendBytes(b, num, appendSecondsNanos(nil, t.Unix(), int32(t.Nanosecond())))
+}
+
+// AppendDuration codifica um google.protobuf.Duration; zero é omitido
+func AppendDuration(b []byte, num int, d time.Duration) []byte {
+	if d == 0 {
+		return b
+	}
+	return AppendBytes(b, num, appendSecondsNanos(nil, int64(d/time.Second), int32(d%time.Second)))
+}
+
+func appendSecondsNanos(b []byte, seconds int64, nanos int32) []byte {
+	b = AppendVarintField(b, 1, uint64(seconds))
+	return AppendVarintField(b, 2, uint64(int64(nanos)))
+}
+
+// DecodeTimestamp lê um google.protobuf.Timestamp
+func DecodeTimestamp(data []byte) (time.Time, error) {
+	seconds, nanos, err := decodeSecondsNanos(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, int64(nanos)).UTC(), nil
+}
+
+// DecodeDuration lê um google.protobuf.Duration
+func DecodeDuration(data []byte) (time.Duration, error) {
+	seconds, nanos, err := decodeSecondsNanos(data)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds)*time.Second + time.Duration(nanos), nil
+}
+
+func decodeSecondsNanos(data []byte) (int64, int32, error) {
+	var seconds, nanos uint64
+	r := NewReader(data)
+
+	for !r.Done() {
+		num, typ, err := r.Tag()
+		if err != nil {
+			return 0, 0, err
+		}
+		if typ != WireVarint {
+			if err := r.Skip(typ); err != nil {
+				return 0, 0, err
+			}
+			continue
+		}
+
+		v, err := r.Varint()
+		if err != nil {
+			return 0, 0, err
+		}
+		switch num {
+		case 1:
+			seconds = v
+		case 2:
+			nanos = v
+		}
+	}
+
+	return int64(seconds), int32(nanos), nil
+}
+
+// Reader percorre os campos de uma mensagem Protobuf
+type Reader struct {
+	buf []byte
+	pos int
+}
+
+// NewReader cria um Reader para a mensagem
+func NewReader(data []byte) *Reader {
+	return &Reader{buf: data}
+}
+
+// Done indica se todos os campos foram lidos
+func (r *Reader) Done() bool { return r.pos >= len(r.buf) }
+
+// Varint lê um valor varint
+func (r *Reader) Varint() (uint64, error) {
+	var v uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if r.pos >= len(r.buf) {
+			return 0, fmt.Errorf("%w: truncated varint", ErrMalformed)
+		}
+		c := r.buf[r.pos]
+		r.pos++
+		v |= uint64(c&0x7f) << shift
+		if c < 0x80 {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: varint overflow", ErrMalformed)
+}
+
+// Tag lê o número e o wire type do próximo campo
+func (r *Reader) Tag() (num, typ int, err error) {
+	v, err := r.Varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	num, typ = int(v>>3), int(v&7)
+	if num <= 0 {
+		return 0, 0, fmt.Errorf("%w: invalid field number %d", ErrMalformed, num)
+	}
+	return num, typ, nil
+}
+
+// Bytes lê um valor length-delimited. O slice retornado aponta para a
+// mensagem original.
+func (r *Reader) Bytes() ([]byte, error) {
+	n, err := r.Varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.buf)-r.pos) {
+		return nil, fmt.Errorf("%w: truncated field", ErrMalformed)
+	}
+	b := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+// Skip descarta o valor de um campo desconhecido
+func (r *Reader) Skip(typ int) error {
+	switch typ {
+	case WireVarint:
+		_, err := r.Varint()
+		return err
+	case WireBytes:
+		_, err := r.Bytes()
+		return err
+	case WireFixed64, WireFixed32:
+		n := 8
+		if typ == WireFixed32 {
+			n = 4
+		}
+		if len(r.buf)-r.pos < n {
+			return fmt.Errorf("%w: truncated field", ErrMalformed)
+		}
+		r.pos += n
+		return nil
+	default:
+		return fmt.Errorf("%w: unsupported wire type %d", ErrMalformed, typ)
+	}
+}
@@ -0,0 +1,127 @@
+package platformeventstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// AssertPublished falha o teste se nenhum evento do tipo foi publicado com os
+// campos esperados em Data, e devolve o primeiro que casa. Os campos são
+// comparados depois de passar por JSON, então structs, ponteiros e números de
+// qualquer tipo comparam pelo valor serializado. Campos não citados são
+// ignorados; fields nil só exige o tipo.
+func (p *FakePublisher) AssertPublished(t testing.TB, eventType string, fields map[string]any) *types.Event {
+	t.Helper()
+
+	want, err := normalize(fields)
+	if err != nil {
+		t.Fatalf("platformeventstest: invalid expected fields: %v", err)
+	}
+
+	candidates := p.EventsOfType(eventType)
+	var seen []string
+	for _, event := range candidates {
+		data, err := dataFields(event)
+		if err != nil {
+			seen = append(seen, fmt.Sprintf("  %s: %v", event.ID, err))
+			continue
+		}
+		mismatch := diffFields(want, data)
+		if mismatch == "" {
+			return event
+		}
+		seen = append(seen, fmt.Sprintf("  %s: %s", event.ID, mismatch))
+	}
+
+	if len(candidates) == 0 {
+		t.Fatalf("no %s event published; published types: %v", eventType, p.publishedTypes())
+	}
+	t.Fatalf("no %s event published with fields %v; candidates:\n%s", eventType, want, strings.Join(seen, "\n"))
+	return nil
+}
+
+// AssertNotPublished falha o teste se algum evento do tipo foi publicado
+func (p *FakePublisher) AssertNotPublished(t testing.TB, eventType string) {
+	t.Helper()
+
+	if events := p.EventsOfType(eventType); len(events) > 0 {
+		t.Fatalf("expected no %s event, got %d", eventType, len(events))
+	}
+}
+
+// AssertPublishedCount falha o teste se o número de eventos do tipo difere
+func (p *FakePublisher) AssertPublishedCount(t testing.TB, eventType string, count int) {
+	t.Helper()
+
+	if events := p.EventsOfType(eventType); len(events) != count {
+		t.Fatalf("expected %d %s events, got %d", count, eventType, len(events))
+	}
+}
+
+// publishedTypes lista os tipos publicados, sem repetição
+func (p *FakePublisher) publishedTypes() []string {
+	set := make(map[string]struct{})
+	for _, msg := range p.Messages() {
+		set[msg.Event.Type] = struct{}{}
+	}
+	eventTypes := make([]string, 0, len(set))
+	for eventType := range set {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Strings(eventTypes)
+	return eventTypes
+}
+
+// dataFields converte event.Data em um objeto JSON genérico
+func dataFields(event *types.Event) (map[string]any, error) {
+	raw, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("data is not serializable: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("data is not an object: %s", raw)
+	}
+	return fields, nil
+}
+
+// normalize passa os campos esperados por JSON
+func normalize(fields map[string]any) (map[string]any, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	err = json.Unmarshal(raw, &normalized)
+	return normalized, err
+}
+
+// diffFields descreve o primeiro campo esperado que não confere, em ordem
+// alfabética; vazio quando todos conferem
+func diffFields(want, got map[string]any) string {
+	keys := make([]string, 0, len(want))
+	for key := range want {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := got[key]
+		if !ok {
+			return fmt.Sprintf("missing %q", key)
+		}
+		if !reflect.DeepEqual(value, want[key]) {
+			return fmt.Sprintf("%q = %v, want %v", key, value, want[key])
+		}
+	}
+	return ""
+}
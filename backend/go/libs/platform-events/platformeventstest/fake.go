@@ -0,0 +1,280 @@
+// Package platformeventstest fornece um publisher e um consumer em memória
+// para testar a lógica de eventos dos serviços sem Kafka.
+//
+//	pub, cons := platformeventstest.NewPair()
+//	service := NewService(pub)          // depende de publisher.EventPublisher
+//	RegisterHandlers(cons)              // depende de consumer.EventConsumer
+//
+//	service.DoSomething(ctx)
+//	pub.AssertPublished(t, "tenant.created", map[string]any{"name": "Acme"})
+package platformeventstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/consumer"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/publisher"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+var (
+	_ publisher.EventPublisher = (*FakePublisher)(nil)
+	_ consumer.EventConsumer   = (*FakeConsumer)(nil)
+)
+
+// Message é um evento capturado pelo FakePublisher
+type Message struct {
+	Topic string
+	Event *types.Event
+}
+
+// FakePublisher captura os eventos publicados em memória. Cada evento passa
+// pelo codec (JSON por padrão), como no Publisher real, então dados que não
+// serializam falham aqui também; os consumers conectados recebem a cópia
+// decodificada, com Data nos mesmos tipos genéricos que chegam do Kafka.
+type FakePublisher struct {
+	mu        sync.Mutex
+	messages  []Message
+	codec     codec.Codec
+	err       error
+	closed    bool
+	consumers []*FakeConsumer
+}
+
+// NewFakePublisher cria um publisher em memória
+func NewFakePublisher() *FakePublisher {
+	return &FakePublisher{codec: codec.JSON}
+}
+
+// NewPair cria um publisher conectado a um consumer: cada evento publicado é
+// entregue de forma síncrona aos handlers do consumer
+func NewPair() (*FakePublisher, *FakeConsumer) {
+	pub := NewFakePublisher()
+	cons := NewFakeConsumer()
+	pub.Connect(cons)
+	return pub, cons
+}
+
+// Connect entrega os próximos eventos publicados também ao consumer
+func (p *FakePublisher) Connect(c *FakeConsumer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.consumers = append(p.consumers, c)
+}
+
+// SetCodec troca o codec usado para serializar os eventos
+func (p *FakePublisher) SetCodec(c codec.Codec) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.codec = c
+}
+
+// FailWith faz as próximas publicações falharem com err; nil volta ao normal
+func (p *FakePublisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
+// Publish implementa publisher.EventPublisher
+func (p *FakePublisher) Publish(ctx context.Context, topic string, event *types.Event) error {
+	return p.PublishBatch(ctx, topic, []*types.Event{event})
+}
+
+// PublishBatch implementa publisher.EventPublisher. Como no Publisher real,
+// o batch é recusado inteiro se algum evento não serializa.
+func (p *FakePublisher) PublishBatch(ctx context.Context, topic string, events []*types.Event) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return publisher.ErrPublisherClosed
+	}
+	if p.err != nil {
+		err := p.err
+		p.mu.Unlock()
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	decoded := make([]*types.Event, 0, len(events))
+	for _, event := range events {
+		copied, err := p.roundTrip(event)
+		if err != nil {
+			p.mu.Unlock()
+			return err
+		}
+		decoded = append(decoded, copied)
+	}
+	for _, event := range events {
+		p.messages = append(p.messages, Message{Topic: topic, Event: event})
+	}
+	consumers := append([]*FakeConsumer(nil), p.consumers...)
+	p.mu.Unlock()
+
+	// Handlers rodam fora do lock e podem publicar novos eventos
+	var errs []error
+	for _, c := range consumers {
+		for _, event := range decoded {
+			if err := c.Deliver(event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("published, but a connected handler failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// roundTrip serializa e desserializa o evento com o codec atual
+func (p *FakePublisher) roundTrip(event *types.Event) (*types.Event, error) {
+	data, err := p.codec.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize event %s: %w", event.ID, err)
+	}
+	decoded, err := p.codec.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize event %s: %w", event.ID, err)
+	}
+	return decoded, nil
+}
+
+// Close implementa publisher.EventPublisher
+func (p *FakePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	return nil
+}
+
+// Messages retorna os eventos publicados, em ordem
+func (p *FakePublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Message(nil), p.messages...)
+}
+
+// Events retorna os eventos publicados em um tópico, em ordem
+func (p *FakePublisher) Events(topic string) []*types.Event {
+	var events []*types.Event
+	for _, msg := range p.Messages() {
+		if msg.Topic == topic {
+			events = append(events, msg.Event)
+		}
+	}
+	return events
+}
+
+// EventsOfType retorna os eventos publicados de um tipo, em qualquer tópico
+func (p *FakePublisher) EventsOfType(eventType string) []*types.Event {
+	var events []*types.Event
+	for _, msg := range p.Messages() {
+		if msg.Event.Type == eventType {
+			events = append(events, msg.Event)
+		}
+	}
+	return events
+}
+
+// Reset descarta os eventos capturados
+func (p *FakePublisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = nil
+}
+
+// subscription é um handler registrado no FakeConsumer
+type subscription struct {
+	eventType string
+	filter    types.EventFilter
+	handler   types.EventHandler
+}
+
+// FakeConsumer guarda os handlers registrados e os executa de forma síncrona
+// em Deliver, sem retentativas nem dead letter
+type FakeConsumer struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	started       bool
+	closed        bool
+}
+
+// NewFakeConsumer cria um consumer em memória
+func NewFakeConsumer() *FakeConsumer {
+	return &FakeConsumer{}
+}
+
+// Subscribe implementa consumer.EventConsumer
+func (c *FakeConsumer) Subscribe(eventType string, handler types.EventHandler) {
+	c.SubscribeWithFilter(eventType, nil, handler)
+}
+
+// SubscribeWithFilter implementa consumer.EventConsumer
+func (c *FakeConsumer) SubscribeWithFilter(eventType string, filter types.EventFilter, handler types.EventHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscriptions = append(c.subscriptions, subscription{eventType: eventType, filter: filter, handler: handler})
+}
+
+// Start implementa consumer.EventConsumer
+func (c *FakeConsumer) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return consumer.ErrConsumerClosed
+	}
+	c.started = true
+	return nil
+}
+
+// Started informa se Start foi chamado
+func (c *FakeConsumer) Started() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.started
+}
+
+// Close implementa consumer.EventConsumer
+func (c *FakeConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	return nil
+}
+
+// Deliver executa os handlers do tipo do evento cujo filtro o aceita e
+// devolve os erros deles. Não exige Start, para que handlers possam ser
+// testados isoladamente; um consumer fechado recusa a entrega.
+func (c *FakeConsumer) Deliver(event *types.Event) error {
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return consumer.ErrConsumerClosed
+	}
+	subscriptions := append([]subscription(nil), c.subscriptions...)
+	c.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if s.eventType != event.Type || (s.filter != nil && !s.filter(event)) {
+			continue
+		}
+		if err := s.handler(event); err != nil {
+			errs = append(errs, fmt.Errorf("handler for %s failed: %w", event.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,206 @@
+package platformeventstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/consumer"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/publisher"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// recordingTB captura a falha de um helper de asserção
+type recordingTB struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// runAssertion roda a asserção como em um teste e informa se falhou
+func runAssertion(assert func(t testing.TB)) *recordingTB {
+	tb := &recordingTB{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert(tb)
+	}()
+	<-done
+	return tb
+}
+
+type tenantCreated struct {
+	TenantID string `json:"tenant_id"`
+	Name     string `json:"name"`
+	Seats    int    `json:"seats"`
+}
+
+func TestFakePublisher_CapturesAndAsserts(t *testing.T) {
+	pub := NewFakePublisher()
+	ctx := context.Background()
+
+	event := types.NewEvent("tenant.created", "tenant-manager", tenantCreated{TenantID: "t-1", Name: "Acme", Seats: 5})
+	if err := pub.Publish(ctx, "tenants", event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	pub.PublishBatch(ctx, "audit", []*types.Event{
+		types.NewEvent("audit.logged", "tenant-manager", map[string]string{"action": "create"}),
+	})
+
+	got := pub.AssertPublished(t, "tenant.created", map[string]any{"name": "Acme", "seats": 5})
+	if got != event {
+		t.Error("AssertPublished deveria devolver o evento publicado")
+	}
+	pub.AssertPublished(t, "audit.logged", nil)
+	pub.AssertPublishedCount(t, "tenant.created", 1)
+	pub.AssertNotPublished(t, "tenant.deleted")
+
+	if len(pub.Events("tenants")) != 1 || len(pub.Events("audit")) != 1 || len(pub.Messages()) != 2 {
+		t.Errorf("mensagens capturadas = %+v", pub.Messages())
+	}
+
+	pub.Reset()
+	if len(pub.Messages()) != 0 {
+		t.Error("Reset deveria descartar os eventos")
+	}
+}
+
+func TestFakePublisher_AssertionFailures(t *testing.T) {
+	pub := NewFakePublisher()
+	pub.Publish(context.Background(), "tenants", types.NewEvent("tenant.created", "test", tenantCreated{Name: "Acme"}))
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		want   string
+	}{
+		{"tipo ausente", func(t testing.TB) { pub.AssertPublished(t, "tenant.deleted", nil) }, "published types: [tenant.created]"},
+		{"campo diferente", func(t testing.TB) { pub.AssertPublished(t, "tenant.created", map[string]any{"name": "Globex"}) }, `"name" = Acme, want Globex`},
+		{"campo ausente", func(t testing.TB) { pub.AssertPublished(t, "tenant.created", map[string]any{"plan": "pro"}) }, `missing "plan"`},
+		{"publicado", func(t testing.TB) { pub.AssertNotPublished(t, "tenant.created") }, "expected no tenant.created"},
+		{"contagem", func(t testing.TB) { pub.AssertPublishedCount(t, "tenant.created", 2) }, "expected 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := runAssertion(tt.assert)
+			if !tb.failed {
+				t.Fatal("esperada falha da asserção")
+			}
+			if !strings.Contains(tb.message, tt.want) {
+				t.Errorf("mensagem = %q, esperado conter %q", tb.message, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakePublisher_Errors(t *testing.T) {
+	pub := NewFakePublisher()
+	ctx := context.Background()
+
+	unavailable := errors.New("broker indisponível")
+	pub.FailWith(unavailable)
+	if err := pub.Publish(ctx, "tenants", types.NewEvent("tenant.created", "test", nil)); !errors.Is(err, unavailable) {
+		t.Errorf("Publish = %v, esperado %v", err, unavailable)
+	}
+	pub.FailWith(nil)
+
+	// Dados que não serializam falham como no Publisher real
+	bad := types.NewEvent("tenant.created", "test", map[string]any{"callback": func() {}})
+	if err := pub.Publish(ctx, "tenants", bad); err == nil {
+		t.Error("esperado erro de serialização")
+	}
+	if len(pub.Messages()) != 0 {
+		t.Errorf("nenhum evento deveria ser capturado, obtido %d", len(pub.Messages()))
+	}
+
+	pub.Close()
+	if err := pub.Publish(ctx, "tenants", types.NewEvent("tenant.created", "test", nil)); !errors.Is(err, publisher.ErrPublisherClosed) {
+		t.Errorf("Publish após Close = %v, esperado ErrPublisherClosed", err)
+	}
+}
+
+func TestPair_DeliversToHandlers(t *testing.T) {
+	pub, cons := NewPair()
+	ctx := context.Background()
+
+	var received []tenantCreated
+	cons.Subscribe("tenant.created", func(event *types.Event) error {
+		var data tenantCreated
+		if err := codec.DecodeData(event, &data); err != nil {
+			return err
+		}
+		received = append(received, data)
+		return nil
+	})
+	var filtered int
+	cons.SubscribeWithFilter("tenant.created", types.TenantFilter("t-2"), func(event *types.Event) error {
+		filtered++
+		return nil
+	})
+	if err := cons.Start(); err != nil || !cons.Started() {
+		t.Fatalf("Start = %v", err)
+	}
+
+	pub.Publish(ctx, "tenants", types.NewEvent("tenant.created", "test", tenantCreated{TenantID: "t-1", Seats: 3}).WithTenantID("t-1"))
+	pub.Publish(ctx, "tenants", types.NewEvent("tenant.created", "test", tenantCreated{TenantID: "t-2", Seats: 7}).WithTenantID("t-2"))
+	pub.Publish(ctx, "tenants", types.NewEvent("tenant.deleted", "test", nil))
+
+	if len(received) != 2 || received[0].Seats != 3 || received[1].Seats != 7 {
+		t.Errorf("handler recebeu %+v", received)
+	}
+	if filtered != 1 {
+		t.Errorf("handler com filtro recebeu %d eventos, esperado 1", filtered)
+	}
+}
+
+func TestPair_HandlerErrorReachesPublisher(t *testing.T) {
+	pub, cons := NewPair()
+	rejected := errors.New("tenant inválido")
+	cons.Subscribe("tenant.created", func(event *types.Event) error { return rejected })
+
+	err := pub.Publish(context.Background(), "tenants", types.NewEvent("tenant.created", "test", nil))
+	if !errors.Is(err, rejected) {
+		t.Errorf("Publish = %v, esperado o erro do handler", err)
+	}
+	// O evento foi publicado mesmo assim
+	pub.AssertPublishedCount(t, "tenant.created", 1)
+
+	cons.Close()
+	if err := cons.Deliver(types.NewEvent("tenant.created", "test", nil)); !errors.Is(err, consumer.ErrConsumerClosed) {
+		t.Errorf("Deliver após Close = %v, esperado ErrConsumerClosed", err)
+	}
+}
+
+func TestFakePublisher_PlatformEventPayload(t *testing.T) {
+	pub, cons := NewPair()
+
+	var got events.LLMCostEvent
+	cons.Subscribe("llm.cost", func(event *types.Event) error {
+		return codec.DecodeData(event, &got)
+	})
+
+	event := types.NewEvent("llm.cost", "agent-orchestrator", events.LLMCostEvent{TenantID: "t-1", Provider: "openai"})
+	if err := pub.Publish(context.Background(), topics.LLMCost, event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if got.TenantID != "t-1" || got.Provider != "openai" {
+		t.Errorf("handler decodificou %+v", got)
+	}
+	pub.AssertPublished(t, "llm.cost", map[string]any{"provider": "openai"})
+}
@@ -2,22 +2,38 @@ package publisher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
 	"github.com/serphona/serphona/backend/go/libs/platform-events/config"
 	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
 )
 
+// EventPublisher é o contrato de publicação implementado por Publisher.
+// Serviços que dependem dele podem ser testados com
+// platformeventstest.FakePublisher, sem Kafka.
+type EventPublisher interface {
+	Publish(ctx context.Context, topic string, event *types.Event) error
+	PublishBatch(ctx context.Context, topic string, events []*types.Event) error
+	Close() error
+}
+
+var _ EventPublisher = (*Publisher)(nil)
+
 // Publisher é responsável por publicar eventos no Kafka
 type Publisher struct {
-	writer *kafka.Writer
-	config *config.Config
-	mu     sync.RWMutex
-	closed bool
+	writer          *kafka.Writer
+	config          *config.Config
+	maxMessageBytes int
+	codec           codec.Codec
+	topicCodecs     map[string]codec.Codec
+	mu              sync.RWMutex
+	closed          bool
 }
 
 // New cria um novo publisher
@@ -26,31 +42,64 @@ func New(cfg *config.Config) (*Publisher, error) {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	compression, err := compressionCodec(cfg.PublisherCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	maxMessageBytes := cfg.PublisherMaxMessageBytes
+	if maxMessageBytes == 0 {
+		maxMessageBytes = config.DefaultMaxMessageBytes
+	}
+
 	writer := &kafka.Writer{
 		Addr:         kafka.TCP(cfg.Brokers...),
 		Balancer:     &kafka.LeastBytes{},
 		BatchSize:    cfg.PublisherBatchSize,
 		BatchTimeout: cfg.PublisherBatchTimeout,
+		BatchBytes:   int64(maxMessageBytes),
 		MaxAttempts:  cfg.PublisherMaxRetries,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		RequiredAcks: kafka.RequireOne,
 		Async:        false,
-		Compression:  kafka.Snappy,
+		Compression:  compression,
 	}
 
 	p := &Publisher{
-		writer: writer,
-		config: cfg,
+		writer:          writer,
+		config:          cfg,
+		maxMessageBytes: maxMessageBytes,
+		codec:           codec.JSON,
+		topicCodecs:     make(map[string]codec.Codec),
 	}
 
 	if cfg.Debug {
-		log.Printf("[platform-events] Publisher initialized with brokers: %v", cfg.Brokers)
+		log.Printf("[platform-events] Publisher initialized with brokers: %v, compression: %s, max message bytes: %d",
+			cfg.Brokers, compressionName(cfg.PublisherCompression), maxMessageBytes)
 	}
 
 	return p, nil
 }
 
+// SetCodec troca o codec padrão dos eventos publicados (JSON por padrão)
+func (p *Publisher) SetCodec(c codec.Codec) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.codec = c
+}
+
+// SetTopicCodec usa c para os eventos de topic, por exemplo Protobuf para
+// tópicos de alto volume. Consumers reconhecem o formato pelo header
+// content-type, então a troca não exige atualizá-los antes.
+func (p *Publisher) SetTopicCodec(topic string, c codec.Codec) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.topicCodecs[topic] = c
+}
+
 // Publish publica um evento em um tópico específico
 func (p *Publisher) Publish(ctx context.Context, topic string, event *types.Event) error {
 	p.mu.RLock()
@@ -60,38 +109,9 @@ func (p *Publisher) Publish(ctx context.Context, topic string, event *types.Even
 		return ErrPublisherClosed
 	}
 
-	// Serializar evento
-	data, err := event.ToJSON()
+	msg, err := p.newMessage(topic, event)
 	if err != nil {
-		return fmt.Errorf("failed to serialize event: %w", err)
-	}
-
-	// Criar mensagem Kafka
-	msg := kafka.Message{
-		Topic: topic,
-		Key:   []byte(event.ID),
-		Value: data,
-		Headers: []kafka.Header{
-			{Key: "event_type", Value: []byte(event.Type)},
-			{Key: "source", Value: []byte(event.Source)},
-			{Key: "version", Value: []byte(event.Version)},
-		},
-		Time: event.Timestamp,
-	}
-
-	// Adicionar headers opcionais
-	if event.TenantID != "" {
-		msg.Headers = append(msg.Headers, kafka.Header{
-			Key:   "tenant_id",
-			Value: []byte(event.TenantID),
-		})
-	}
-
-	if event.TraceID != "" {
-		msg.Headers = append(msg.Headers, kafka.Header{
-			Key:   "trace_id",
-			Value: []byte(event.TraceID),
-		})
+		return err
 	}
 
 	// Publicar no Kafka
@@ -124,37 +144,10 @@ func (p *Publisher) PublishBatch(ctx context.Context, topic string, events []*ty
 	// Criar mensagens Kafka
 	messages := make([]kafka.Message, 0, len(events))
 	for _, event := range events {
-		data, err := event.ToJSON()
+		msg, err := p.newMessage(topic, event)
 		if err != nil {
-			return fmt.Errorf("failed to serialize event %s: %w", event.ID, err)
+			return err
 		}
-
-		msg := kafka.Message{
-			Topic: topic,
-			Key:   []byte(event.ID),
-			Value: data,
-			Headers: []kafka.Header{
-				{Key: "event_type", Value: []byte(event.Type)},
-				{Key: "source", Value: []byte(event.Source)},
-				{Key: "version", Value: []byte(event.Version)},
-			},
-			Time: event.Timestamp,
-		}
-
-		if event.TenantID != "" {
-			msg.Headers = append(msg.Headers, kafka.Header{
-				Key:   "tenant_id",
-				Value: []byte(event.TenantID),
-			})
-		}
-
-		if event.TraceID != "" {
-			msg.Headers = append(msg.Headers, kafka.Header{
-				Key:   "trace_id",
-				Value: []byte(event.TraceID),
-			})
-		}
-
 		messages = append(messages, msg)
 	}
 
@@ -171,6 +164,97 @@ func (p *Publisher) PublishBatch(ctx context.Context, topic string, events []*ty
 	return nil
 }
 
+// newMessage serializa o evento em uma mensagem Kafka, recusando eventos
+// acima do limite de tamanho antes de qualquer envio
+func (p *Publisher) newMessage(topic string, event *types.Event) (kafka.Message, error) {
+	enc := p.codec
+	if c, ok := p.topicCodecs[topic]; ok {
+		enc = c
+	}
+
+	data, err := enc.Marshal(event)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("failed to serialize event %s: %w", event.ID, err)
+	}
+
+	msg := kafka.Message{
+		Topic: topic,
+		Key:   []byte(event.ID),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: "event_type", Value: []byte(event.Type)},
+			{Key: "source", Value: []byte(event.Source)},
+			{Key: "version", Value: []byte(event.Version)},
+			{Key: codec.HeaderContentType, Value: []byte(enc.ContentType())},
+		},
+		Time: event.Timestamp,
+	}
+
+	// Adicionar headers opcionais
+	if event.TenantID != "" {
+		msg.Headers = append(msg.Headers, kafka.Header{
+			Key:   "tenant_id",
+			Value: []byte(event.TenantID),
+		})
+	}
+
+	if event.TraceID != "" {
+		msg.Headers = append(msg.Headers, kafka.Header{
+			Key:   "trace_id",
+			Value: []byte(event.TraceID),
+		})
+	}
+
+	if size := messageSize(msg); size > p.maxMessageBytes {
+		return kafka.Message{}, &MessageTooLargeError{
+			Topic:     topic,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Size:      size,
+			Limit:     p.maxMessageBytes,
+		}
+	}
+
+	return msg, nil
+}
+
+// messageSize é o tamanho da mensagem antes da compressão: chave, valor e
+// headers. É o que o writer compara com BatchBytes; medir antes da
+// compressão mantém o limite independente do codec.
+func messageSize(msg kafka.Message) int {
+	size := len(msg.Key) + len(msg.Value)
+	for _, h := range msg.Headers {
+		size += len(h.Key) + len(h.Value)
+	}
+	return size
+}
+
+// compressionCodec converte o nome do codec configurado
+func compressionCodec(name string) (kafka.Compression, error) {
+	switch name {
+	case "", config.CompressionSnappy:
+		return kafka.Snappy, nil
+	case config.CompressionNone:
+		return 0, nil
+	case config.CompressionGzip:
+		return kafka.Gzip, nil
+	case config.CompressionLz4:
+		return kafka.Lz4, nil
+	case config.CompressionZstd:
+		return kafka.Zstd, nil
+	default:
+		return 0, fmt.Errorf("invalid config: %w", config.ErrInvalidCompression)
+	}
+}
+
+// compressionName retorna o nome efetivo do codec, para logs
+func compressionName(name string) string {
+	if name == "" {
+		return config.CompressionSnappy
+	}
+	return name
+}
+
 // Close fecha o publisher
 func (p *Publisher) Close() error {
 	p.mu.Lock()
@@ -201,4 +285,26 @@ func (p *Publisher) Stats() kafka.WriterStats {
 // Errors
 var (
 	ErrPublisherClosed = fmt.Errorf("publisher is closed")
+	ErrMessageTooLarge = errors.New("event exceeds max message size")
 )
+
+// MessageTooLargeError indica um evento maior que PublisherMaxMessageBytes.
+// Nada é enviado: o batch inteiro é recusado antes de chegar ao broker.
+type MessageTooLargeError struct {
+	Topic     string
+	EventID   string
+	EventType string
+	Size      int
+	Limit     int
+}
+
+func (e *MessageTooLargeError) Error() string {
+	return fmt.Sprintf("event %s (%s) for topic %s is %d bytes, over the %d byte limit (KAFKA_MAX_MESSAGE_BYTES); "+
+		"move large payloads to object storage and publish a reference",
+		e.EventID, e.EventType, e.Topic, e.Size, e.Limit)
+}
+
+// Unwrap permite errors.Is(err, ErrMessageTooLarge)
+func (e *MessageTooLargeError) Unwrap() error {
+	return ErrMessageTooLarge
+}
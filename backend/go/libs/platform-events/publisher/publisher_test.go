@@ -0,0 +1,144 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/config"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// newTestPublisher cria um publisher apontando para um broker inexistente:
+// qualquer tentativa real de envio falharia com erro de conexão
+func newTestPublisher(t *testing.T, maxBytes int) *Publisher {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.Brokers = []string{"127.0.0.1:1"}
+	cfg.PublisherMaxRetries = 1
+	cfg.PublisherMaxMessageBytes = maxBytes
+
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestPublish_OversizedEventFailsBeforeSending(t *testing.T) {
+	p := newTestPublisher(t, 1024)
+	event := types.NewEvent("agent.message.sent", "agent-orchestrator", map[string]string{
+		"transcript": strings.Repeat("a", 4096),
+	}).WithTenantID("tenant-a")
+
+	err := p.Publish(context.Background(), "agent.message.sent", event)
+
+	var tooLarge *MessageTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("esperado *MessageTooLargeError, obtido %v", err)
+	}
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Error("erro deveria casar com ErrMessageTooLarge")
+	}
+	if tooLarge.Limit != 1024 || tooLarge.Size <= 4096 || tooLarge.EventID != event.ID {
+		t.Errorf("detalhes inesperados: %+v", tooLarge)
+	}
+	for _, want := range []string{event.ID, "agent.message.sent", "1024 byte limit", "KAFKA_MAX_MESSAGE_BYTES"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("mensagem deveria citar %q: %v", want, err)
+		}
+	}
+}
+
+func TestPublishBatch_RejectsWholeBatchWithOversizedEvent(t *testing.T) {
+	p := newTestPublisher(t, 1024)
+	small := types.NewEvent("tool.invoked", "tool-service", map[string]string{"tool": "crm"})
+	large := types.NewEvent("tool.completed", "tool-service", map[string]string{"result": strings.Repeat("b", 2048)})
+
+	err := p.PublishBatch(context.Background(), "tool.events", []*types.Event{small, large})
+
+	var tooLarge *MessageTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.EventID != large.ID {
+		t.Fatalf("esperado *MessageTooLargeError para %s, obtido %v", large.ID, err)
+	}
+}
+
+func TestNewMessage_CountsKeyValueAndHeaders(t *testing.T) {
+	p := newTestPublisher(t, 0)
+	if p.maxMessageBytes != config.DefaultMaxMessageBytes {
+		t.Errorf("limite padrão = %d, esperado %d", p.maxMessageBytes, config.DefaultMaxMessageBytes)
+	}
+
+	event := types.NewEvent("tenant.created", "tenant-manager", nil).WithTenantID("tenant-a")
+	msg, err := p.newMessage("tenant.created", event)
+	if err != nil {
+		t.Fatalf("newMessage: %v", err)
+	}
+
+	want := len(msg.Key) + len(msg.Value)
+	for _, h := range msg.Headers {
+		want += len(h.Key) + len(h.Value)
+	}
+	if got := messageSize(msg); got != want || got <= len(msg.Value) {
+		t.Errorf("messageSize = %d, esperado %d", got, want)
+	}
+}
+
+func TestCompressionCodec(t *testing.T) {
+	tests := map[string]kafka.Compression{
+		"":       kafka.Snappy,
+		"none":   0,
+		"gzip":   kafka.Gzip,
+		"snappy": kafka.Snappy,
+		"lz4":    kafka.Lz4,
+		"zstd":   kafka.Zstd,
+	}
+	for name, want := range tests {
+		got, err := compressionCodec(name)
+		if err != nil || got != want {
+			t.Errorf("compressionCodec(%q) = %v, %v; esperado %v", name, got, err, want)
+		}
+	}
+
+	if _, err := compressionCodec("brotli"); !errors.Is(err, config.ErrInvalidCompression) {
+		t.Errorf("codec desconhecido deveria falhar, obtido %v", err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.PublisherCompression = "brotli"
+	if _, err := New(cfg); err == nil {
+		t.Error("New deveria recusar codec desconhecido")
+	}
+}
+
+func TestNewMessage_UsesTopicCodec(t *testing.T) {
+	p := newTestPublisher(t, 0)
+	p.SetTopicCodec("conversation.transcripts", codec.Protobuf)
+	event := types.NewEvent("conversation.transcript.segment", "voice-gateway", []byte{0x0a, 0x01, 0x61})
+
+	tests := map[string]string{
+		"conversation.transcripts": codec.ContentTypeProtobuf,
+		"tenant.events":            codec.ContentTypeJSON,
+	}
+	for topic, want := range tests {
+		msg, err := p.newMessage(topic, event)
+		if err != nil {
+			t.Fatalf("newMessage(%s): %v", topic, err)
+		}
+
+		var got string
+		for _, h := range msg.Headers {
+			if h.Key == codec.HeaderContentType {
+				got = string(h.Value)
+			}
+		}
+		if got != want {
+			t.Errorf("content-type de %s = %q, esperado %q", topic, got, want)
+		}
+	}
+}
@@ -24,29 +24,45 @@ const (
 	SubscriptionCreated   = "billing.subscription.created"
 	SubscriptionUpdated   = "billing.subscription.updated"
 	SubscriptionCancelled = "billing.subscription.cancelled"
+	TrialStarted          = "billing.subscription.trial_started"
+	TrialEndingSoon       = "billing.trial.ending_soon"
 	PaymentSucceeded      = "billing.payment.succeeded"
 	PaymentFailed         = "billing.payment.failed"
 	CreditsPurchased      = "billing.credits.purchased"
 	CreditsConsumed       = "billing.credits.consumed"
 	InvoiceGenerated      = "billing.invoice.generated"
+	BillingReconciled     = "billing.reconciled"
 
 	// Agent events
-	AgentCreated        = "agent.created"
-	AgentUpdated        = "agent.updated"
-	AgentDeleted        = "agent.deleted"
-	AgentDeployed       = "agent.deployed"
-	AgentStarted        = "agent.started"
-	AgentStopped        = "agent.stopped"
-	ConversationStarted = "agent.conversation.started"
-	ConversationEnded   = "agent.conversation.ended"
-	MessageSent         = "agent.message.sent"
-	MessageReceived     = "agent.message.received"
+	AgentCreated           = "agent.created"
+	AgentUpdated           = "agent.updated"
+	AgentDeleted           = "agent.deleted"
+	AgentDeployed          = "agent.deployed"
+	AgentStarted           = "agent.started"
+	AgentStopped           = "agent.stopped"
+	ConversationStarted    = "agent.conversation.started"
+	ConversationEnded      = "agent.conversation.ended"
+	ConversationSummarized = "agent.conversation.summarized"
+	MessageSent            = "agent.message.sent"
+	MessageReceived        = "agent.message.received"
+	TransferDecided        = "agent.transfer.decided"
+	GraphTransition        = "agent.graph.transition"
+	TurnCancelled          = "agent.turn.cancelled"
+
+	// LLM events
+	LLMCost     = "llm.cost"
+	LLMFailover = "llm.failover"
+
+	// Compliance events
+	ComplianceViolation = "compliance.violation"
+	ModerationFlagged   = "moderation.flagged"
 
 	// Analytics events
 	InteractionLogged = "analytics.interaction.logged"
 	MetricRecorded    = "analytics.metric.recorded"
 	ReportGenerated   = "analytics.report.generated"
 	DataExported      = "analytics.data.exported"
+	MetricAnomaly     = "analytics.metric.anomaly"
 
 	// Tool events
 	ToolRegistered = "tool.registered"
@@ -55,10 +71,11 @@ const (
 	ToolFailed     = "tool.failed"
 
 	// System events
-	SystemHealthCheck    = "system.health.check"
-	SystemError          = "system.error"
-	SystemAlert          = "system.alert"
-	ConfigurationUpdated = "system.configuration.updated"
+	SystemHealthCheck     = "system.health.check"
+	SystemError           = "system.error"
+	SystemAlert           = "system.alert"
+	ConfigurationUpdated  = "system.configuration.updated"
+	WebhookDeliveryFailed = "system.webhook.delivery_failed"
 )
 
 // TopicGroups agrupa tópicos por categoria
@@ -85,11 +102,14 @@ var TopicGroups = map[string][]string{
 		SubscriptionCreated,
 		SubscriptionUpdated,
 		SubscriptionCancelled,
+		TrialStarted,
+		TrialEndingSoon,
 		PaymentSucceeded,
 		PaymentFailed,
 		CreditsPurchased,
 		CreditsConsumed,
 		InvoiceGenerated,
+		BillingReconciled,
 	},
 	"agent": {
 		AgentCreated,
@@ -100,14 +120,27 @@ var TopicGroups = map[string][]string{
 		AgentStopped,
 		ConversationStarted,
 		ConversationEnded,
+		ConversationSummarized,
 		MessageSent,
 		MessageReceived,
+		TransferDecided,
+		GraphTransition,
+		TurnCancelled,
+	},
+	"llm": {
+		LLMCost,
+		LLMFailover,
+	},
+	"compliance": {
+		ComplianceViolation,
+		ModerationFlagged,
 	},
 	"analytics": {
 		InteractionLogged,
 		MetricRecorded,
 		ReportGenerated,
 		DataExported,
+		MetricAnomaly,
 	},
 	"tool": {
 		ToolRegistered,
@@ -120,6 +153,7 @@ var TopicGroups = map[string][]string{
 		SystemError,
 		SystemAlert,
 		ConfigurationUpdated,
+		WebhookDeliveryFailed,
 	},
 }
 
@@ -83,3 +83,17 @@ type EventHandler func(*Event) error
 
 // EventFilter permite filtrar eventos antes de processar
 type EventFilter func(*Event) bool
+
+// TenantFilter aceita apenas eventos de um dos tenants informados. Eventos sem
+// tenant são recusados.
+func TenantFilter(tenantIDs ...string) EventFilter {
+	allowed := make(map[string]struct{}, len(tenantIDs))
+	for _, id := range tenantIDs {
+		allowed[id] = struct{}{}
+	}
+
+	return func(e *Event) bool {
+		_, ok := allowed[e.TenantID]
+		return ok
+	}
+}
@@ -0,0 +1,395 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// Valores padrão de Options
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = 1 * time.Second
+	DefaultMaxBackoff     = 1 * time.Minute
+	DefaultDisableAfter   = 10
+	DefaultTimeout        = 10 * time.Second
+)
+
+// userAgent identifica as entregas
+const userAgent = "Serphona-Webhooks/1.0"
+
+// Subscriber registra handlers por tipo de evento; *consumer.Consumer o
+// implementa
+type Subscriber interface {
+	Subscribe(eventType string, handler types.EventHandler)
+}
+
+// Options configura o Bridge. Campos zerados usam os valores padrão.
+type Options struct {
+	// HTTPClient faz as entregas; o padrão tem timeout de DefaultTimeout
+	HTTPClient *http.Client
+	// MaxAttempts é o número de tentativas por entrega, incluindo a primeira
+	MaxAttempts int
+	// InitialBackoff é a espera antes da segunda tentativa; dobra a cada
+	// tentativa até MaxBackoff. Retry-After do endpoint é respeitado até
+	// MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// DisableAfter desativa a assinatura após esse número de entregas
+	// seguidas enviadas à DLQ; negativo nunca desativa
+	DisableAfter int
+	// DLQ recebe as entregas que esgotaram as tentativas ou foram recusadas
+	// em definitivo (4xx). Sem DLQ, elas são apenas registradas em log.
+	DLQ DeadLetterQueue
+	// Debug registra cada entrega em log
+	Debug bool
+}
+
+// Bridge assina tipos de evento em um consumer e encaminha cada evento às
+// assinaturas que casam com ele. A entrega é síncrona dentro do handler do
+// consumer (em paralelo entre assinaturas), então o offset só avança depois
+// que cada entrega terminou com sucesso ou foi para a DLQ.
+type Bridge struct {
+	store  Store
+	opts   Options
+	client *http.Client
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	mu       sync.Mutex
+	failures map[string]int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewBridge cria um Bridge sobre o Store de assinaturas
+func NewBridge(store Store, opts Options) *Bridge {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = DefaultInitialBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.DisableAfter == 0 {
+		opts.DisableAfter = DefaultDisableAfter
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bridge{
+		store:    store,
+		opts:     opts,
+		client:   client,
+		sleep:    sleepContext,
+		failures: make(map[string]int),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Attach registra o Bridge no consumer para os tipos de evento informados.
+// Assinaturas só recebem eventos desses tipos, mesmo que usem "*".
+func (b *Bridge) Attach(sub Subscriber, eventTypes ...string) {
+	for _, eventType := range eventTypes {
+		sub.Subscribe(eventType, b.Handle)
+	}
+}
+
+// Handle entrega o evento a todas as assinaturas que casam com ele. Só
+// retorna erro se uma entrega falhou e não pôde ser gravada na DLQ.
+func (b *Bridge) Handle(event *types.Event) error {
+	subs, err := b.store.List(b.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	body, err := event.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, sub := range subs {
+		if !sub.Matches(event) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(sub Subscription) {
+			defer wg.Done()
+			if err := b.forward(sub, event, body); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(sub)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// Close interrompe as esperas entre tentativas; entregas em andamento
+// terminam sem ir para a DLQ
+func (b *Bridge) Close() {
+	b.cancel()
+}
+
+// forward entrega o evento a uma assinatura e trata o resultado
+func (b *Bridge) forward(sub Subscription, event *types.Event, body []byte) error {
+	result := b.deliver(b.ctx, sub, event, body)
+	if result.err == nil {
+		b.recordSuccess(sub.ID)
+		return nil
+	}
+	if b.ctx.Err() != nil {
+		return b.ctx.Err()
+	}
+
+	dead := DeadLetter{
+		SubscriptionID: sub.ID,
+		URL:            sub.URL,
+		Event:          event,
+		Attempts:       result.attempts,
+		StatusCode:     result.status,
+		Error:          result.err.Error(),
+		FailedAt:       time.Now().UTC(),
+	}
+	log.Printf("[platform-events] Webhook delivery failed: subscription=%s, event=%s, attempts=%d: %v",
+		sub.ID, event.ID, result.attempts, result.err)
+
+	b.recordFailure(sub)
+
+	if b.opts.DLQ == nil {
+		return nil
+	}
+	if err := b.opts.DLQ.Push(b.ctx, dead); err != nil {
+		return fmt.Errorf("failed to dead-letter event %s for subscription %s: %w", event.ID, sub.ID, err)
+	}
+	return nil
+}
+
+// deliveryResult é o resultado final de uma entrega
+type deliveryResult struct {
+	attempts int
+	status   int
+	err      error
+}
+
+// deliver envia o evento com retries. Erros de rede, 408, 429 e 5xx são
+// tentados de novo; outros 4xx são definitivos.
+func (b *Bridge) deliver(ctx context.Context, sub Subscription, event *types.Event, body []byte) deliveryResult {
+	deliveryID := uuid.New().String()
+
+	var result deliveryResult
+	for attempt := 1; attempt <= b.opts.MaxAttempts; attempt++ {
+		result.attempts = attempt
+
+		status, retryAfter, err := b.post(ctx, sub, event, deliveryID, body)
+		result.status = status
+		if err == nil {
+			if b.opts.Debug {
+				log.Printf("[platform-events] Webhook delivered: subscription=%s, event=%s, status=%d, attempt=%d",
+					sub.ID, event.ID, status, attempt)
+			}
+			result.err = nil
+			return result
+		}
+		result.err = err
+
+		if status != 0 && !retryable(status) {
+			return result
+		}
+		if attempt == b.opts.MaxAttempts {
+			break
+		}
+
+		wait := b.backoff(attempt)
+		if retryAfter > 0 && retryAfter < b.opts.MaxBackoff {
+			wait = retryAfter
+		}
+		if err := b.sleep(ctx, wait); err != nil {
+			result.err = err
+			return result
+		}
+	}
+
+	result.err = fmt.Errorf("giving up after %d attempts: %w", result.attempts, result.err)
+	return result
+}
+
+// post faz uma tentativa de entrega, assinada no momento do envio
+func (b *Bridge) post(ctx context.Context, sub Subscription, event *types.Event, deliveryID string, body []byte) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEventID, event.ID)
+	req.Header.Set(HeaderEventType, event.Type)
+	req.Header.Set(HeaderDeliveryID, deliveryID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, time.Now(), body))
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, 0, nil
+	}
+
+	var retryAfter time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	return resp.StatusCode, retryAfter, fmt.Errorf("endpoint answered %d", resp.StatusCode)
+}
+
+// backoff retorna a espera após a tentativa attempt
+func (b *Bridge) backoff(attempt int) time.Duration {
+	wait := b.opts.InitialBackoff
+	for i := 1; i < attempt && wait < b.opts.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > b.opts.MaxBackoff {
+		wait = b.opts.MaxBackoff
+	}
+	return wait
+}
+
+// retryable informa se um status HTTP justifica nova tentativa
+func retryable(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
+
+// recordSuccess zera as falhas seguidas da assinatura
+func (b *Bridge) recordSuccess(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.failures, id)
+}
+
+// recordFailure conta uma entrega perdida e desativa a assinatura quando o
+// endpoint falha em definitivo
+func (b *Bridge) recordFailure(sub Subscription) {
+	b.mu.Lock()
+	b.failures[sub.ID]++
+	disable := b.opts.DisableAfter > 0 && b.failures[sub.ID] >= b.opts.DisableAfter
+	if disable {
+		delete(b.failures, sub.ID)
+	}
+	b.mu.Unlock()
+
+	if !disable {
+		return
+	}
+	if _, err := b.SetActive(b.ctx, sub.ID, false); err != nil {
+		log.Printf("[platform-events] Failed to disable webhook subscription %s: %v", sub.ID, err)
+		return
+	}
+	log.Printf("[platform-events] Webhook subscription %s disabled after %d consecutive failed deliveries",
+		sub.ID, b.opts.DisableAfter)
+}
+
+// AddSubscription valida e grava uma nova assinatura ativa. Sem Secret, um
+// segredo aleatório é gerado; a assinatura retornada é a única que o expõe.
+func (b *Bridge) AddSubscription(ctx context.Context, sub Subscription) (Subscription, error) {
+	if sub.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return Subscription{}, err
+		}
+		sub.Secret = "whsec_" + hex.EncodeToString(secret)
+	}
+	if err := sub.Validate(); err != nil {
+		return Subscription{}, err
+	}
+
+	sub.ID = uuid.New().String()
+	sub.Active = true
+	sub.CreatedAt = time.Now().UTC()
+	if err := b.store.Save(ctx, sub); err != nil {
+		return Subscription{}, err
+	}
+	return sub, nil
+}
+
+// ListSubscriptions lista as assinaturas sem os segredos
+func (b *Bridge) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	subs, err := b.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		subs[i] = subs[i].redacted()
+	}
+	return subs, nil
+}
+
+// SetActive ativa ou desativa uma assinatura. Reativar zera a contagem de
+// falhas, depois que o endpoint foi corrigido.
+func (b *Bridge) SetActive(ctx context.Context, id string, active bool) (Subscription, error) {
+	sub, err := b.store.Get(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	sub.Active = active
+	if err := b.store.Save(ctx, sub); err != nil {
+		return Subscription{}, err
+	}
+	if active {
+		b.recordSuccess(id)
+	}
+	return sub.redacted(), nil
+}
+
+// RemoveSubscription apaga uma assinatura
+func (b *Bridge) RemoveSubscription(ctx context.Context, id string) error {
+	if err := b.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	b.recordSuccess(id)
+	return nil
+}
+
+// sleepContext espera d ou até o contexto ser cancelado
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
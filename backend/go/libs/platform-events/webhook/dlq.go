@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// DeadLetter é uma entrega que não chegou ao endpoint
+type DeadLetter struct {
+	SubscriptionID string       `json:"subscription_id"`
+	URL            string       `json:"url"`
+	Event          *types.Event `json:"event"`
+	Attempts       int          `json:"attempts"`
+	// StatusCode é o último status recebido; zero para erro de rede
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+// DeadLetterQueue guarda as entregas perdidas para análise e reenvio
+type DeadLetterQueue interface {
+	Push(ctx context.Context, dead DeadLetter) error
+}
+
+// MemoryDLQ é uma DeadLetterQueue em memória, para testes
+type MemoryDLQ struct {
+	mu    sync.Mutex
+	items []DeadLetter
+}
+
+// NewMemoryDLQ cria uma MemoryDLQ vazia
+func NewMemoryDLQ() *MemoryDLQ {
+	return &MemoryDLQ{}
+}
+
+// Push guarda a entrega perdida
+func (q *MemoryDLQ) Push(_ context.Context, dead DeadLetter) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.items = append(q.items, dead)
+	return nil
+}
+
+// Items retorna as entregas perdidas, na ordem em que chegaram
+func (q *MemoryDLQ) Items() []DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]DeadLetter(nil), q.items...)
+}
+
+// EventPublisher publica eventos; *publisher.Publisher o implementa
+type EventPublisher interface {
+	Publish(ctx context.Context, topic string, event *types.Event) error
+}
+
+// TopicDLQ publica as entregas perdidas como eventos
+// system.webhook.delivery_failed, no tópico de mesmo nome
+type TopicDLQ struct {
+	publisher EventPublisher
+	source    string
+}
+
+// NewTopicDLQ cria uma TopicDLQ; source identifica o serviço que roda o Bridge
+func NewTopicDLQ(publisher EventPublisher, source string) *TopicDLQ {
+	return &TopicDLQ{publisher: publisher, source: source}
+}
+
+// Push publica a entrega perdida, no tenant do evento original
+func (q *TopicDLQ) Push(ctx context.Context, dead DeadLetter) error {
+	event := types.NewEvent(topics.WebhookDeliveryFailed, q.source, dead).
+		WithMetadata("subscription_id", dead.SubscriptionID)
+	if dead.Event != nil {
+		event.WithTenantID(dead.Event.TenantID)
+	}
+	return q.publisher.Publish(ctx, topics.WebhookDeliveryFailed, event)
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// subscriptionRequest é o corpo de POST /subscriptions
+type subscriptionRequest struct {
+	URL        string            `json:"url"`
+	Secret     string            `json:"secret"`
+	EventTypes []string          `json:"event_types"`
+	TenantID   string            `json:"tenant_id"`
+	Metadata   map[string]string `json:"metadata"`
+}
+
+// ListResponse é o corpo de resposta de GET /subscriptions
+type ListResponse struct {
+	Items []Subscription `json:"items"`
+}
+
+// Handler expõe a gestão de assinaturas:
+//
+//	GET    /subscriptions              lista (sem segredos)
+//	POST   /subscriptions              cria; a resposta traz o segredo
+//	POST   /subscriptions/{id}/enable  reativa após correção do endpoint
+//	POST   /subscriptions/{id}/disable desativa
+//	DELETE /subscriptions/{id}         apaga
+//
+// Não faz autorização: o serviço deve montá-lo atrás da autenticação de
+// administradores (ex.: com http.StripPrefix).
+func (b *Bridge) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		subs, err := b.ListSubscriptions(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, ListResponse{Items: subs})
+	})
+
+	mux.HandleFunc("POST /subscriptions", func(w http.ResponseWriter, r *http.Request) {
+		var req subscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error":   "invalid_request",
+				"message": "invalid request body",
+			})
+			return
+		}
+
+		sub, err := b.AddSubscription(r.Context(), Subscription{
+			URL:        req.URL,
+			Secret:     req.Secret,
+			EventTypes: req.EventTypes,
+			TenantID:   req.TenantID,
+			Metadata:   req.Metadata,
+		})
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, sub)
+	})
+
+	mux.HandleFunc("POST /subscriptions/{id}/enable", func(w http.ResponseWriter, r *http.Request) {
+		sub, err := b.SetActive(r.Context(), r.PathValue("id"), true)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, sub)
+	})
+
+	mux.HandleFunc("POST /subscriptions/{id}/disable", func(w http.ResponseWriter, r *http.Request) {
+		sub, err := b.SetActive(r.Context(), r.PathValue("id"), false)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, sub)
+	})
+
+	mux.HandleFunc("DELETE /subscriptions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := b.RemoveSubscription(r.Context(), r.PathValue("id")); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+// writeError mapeia erros de assinatura para respostas HTTP
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidSubscription):
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":   "invalid_subscription",
+			"message": err.Error(),
+		})
+	case errors.Is(err, ErrSubscriptionNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			"error":   "not_found",
+			"message": err.Error(),
+		})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			"error":   "internal_error",
+			"message": "failed to manage webhook subscriptions",
+		})
+	}
+}
+
+// writeJSON escreve data como JSON com o status informado
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,159 @@
+// Package webhook encaminha eventos do Kafka como HTTP POST assinados para
+// endpoints registrados, para consumidores que não conseguem rodar um
+// consumer Kafka (terceiros, funções serverless).
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// Erros de assinaturas
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrInvalidSubscription  = errors.New("invalid webhook subscription")
+)
+
+// Subscription é um endpoint que recebe os eventos que casam com seus
+// filtros. Campos de filtro vazios não filtram.
+type Subscription struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Secret assina as entregas (HMAC-SHA256); nunca é listado
+	Secret string `json:"secret,omitempty"`
+	// EventTypes aceita tipos exatos ("billing.payment.failed"), prefixos
+	// ("billing.*") ou "*"
+	EventTypes []string `json:"event_types"`
+	// TenantID restringe a assinatura aos eventos de um tenant
+	TenantID string `json:"tenant_id,omitempty"`
+	// Metadata exige que o evento tenha esses pares de metadata
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Filter é um filtro programático adicional; não é persistido
+	Filter    types.EventFilter `json:"-"`
+	Active    bool              `json:"active"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Validate verifica URL, segredo e tipos de evento
+func (s *Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidSubscription)
+	}
+	if s.Secret == "" {
+		return fmt.Errorf("%w: secret is required", ErrInvalidSubscription)
+	}
+	if len(s.EventTypes) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidSubscription)
+	}
+	return nil
+}
+
+// Matches informa se o evento deve ser entregue à assinatura
+func (s *Subscription) Matches(event *types.Event) bool {
+	if !s.Active || !matchesType(s.EventTypes, event.Type) {
+		return false
+	}
+	if s.TenantID != "" && event.TenantID != s.TenantID {
+		return false
+	}
+	for k, v := range s.Metadata {
+		if event.Metadata[k] != v {
+			return false
+		}
+	}
+	return s.Filter == nil || s.Filter(event)
+}
+
+// matchesType compara o tipo do evento com os padrões da assinatura
+func matchesType(patterns []string, eventType string) bool {
+	for _, p := range patterns {
+		switch {
+		case p == "*" || p == eventType:
+			return true
+		case strings.HasSuffix(p, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(p, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// redacted retorna uma cópia sem o segredo, para listagens
+func (s Subscription) redacted() Subscription {
+	s.Secret = ""
+	return s
+}
+
+// Store guarda as assinaturas
+type Store interface {
+	Save(ctx context.Context, sub Subscription) error
+	Get(ctx context.Context, id string) (Subscription, error)
+	List(ctx context.Context) ([]Subscription, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore é um Store em memória, para testes e serviços com assinaturas
+// configuradas no boot
+type MemoryStore struct {
+	mu   sync.RWMutex
+	subs map[string]Subscription
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{subs: make(map[string]Subscription)}
+}
+
+// Save cria ou substitui uma assinatura
+func (m *MemoryStore) Save(_ context.Context, sub Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subs[sub.ID] = sub
+	return nil
+}
+
+// Get retorna uma assinatura pelo ID
+func (m *MemoryStore) Get(_ context.Context, id string) (Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sub, ok := m.subs[id]
+	if !ok {
+		return Subscription{}, ErrSubscriptionNotFound
+	}
+	return sub, nil
+}
+
+// List retorna as assinaturas em ordem de criação
+func (m *MemoryStore) List(_ context.Context) ([]Subscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	subs := make([]Subscription, 0, len(m.subs))
+	for _, sub := range m.subs {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].CreatedAt.Before(subs[j].CreatedAt) })
+	return subs, nil
+}
+
+// Delete remove uma assinatura
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.subs[id]; !ok {
+		return ErrSubscriptionNotFound
+	}
+	delete(m.subs, id)
+	return nil
+}
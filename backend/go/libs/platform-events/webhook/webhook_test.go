@@ -0,0 +1,306 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// endpoint registra as entregas recebidas e responde com os status da fila
+type endpoint struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bodies   [][]byte
+	headers  []http.Header
+	statuses []int
+}
+
+func newEndpoint(t *testing.T, statuses ...int) *endpoint {
+	t.Helper()
+
+	e := &endpoint{statuses: statuses}
+	e.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		e.mu.Lock()
+		e.bodies = append(e.bodies, body)
+		e.headers = append(e.headers, r.Header.Clone())
+		status := http.StatusOK
+		if len(e.statuses) > 0 {
+			status, e.statuses = e.statuses[0], e.statuses[1:]
+		}
+		e.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(e.Close)
+	return e
+}
+
+func (e *endpoint) calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.bodies)
+}
+
+func newTestBridge(t *testing.T, opts Options) (*Bridge, *MemoryDLQ) {
+	t.Helper()
+
+	dlq := NewMemoryDLQ()
+	opts.DLQ = dlq
+	b := NewBridge(NewMemoryStore(), opts)
+	b.sleep = func(context.Context, time.Duration) error { return nil }
+	t.Cleanup(b.Close)
+	return b, dlq
+}
+
+func addSubscription(t *testing.T, b *Bridge, sub Subscription) Subscription {
+	t.Helper()
+
+	sub, err := b.AddSubscription(context.Background(), sub)
+	if err != nil {
+		t.Fatalf("AddSubscription: %v", err)
+	}
+	return sub
+}
+
+func TestSubscription_Matches(t *testing.T) {
+	event := types.NewEvent("billing.payment.failed", "billing-service", nil).
+		WithTenantID("tenant-a").
+		WithMetadata("plan", "pro")
+
+	tests := []struct {
+		name string
+		sub  Subscription
+		want bool
+	}{
+		{"tipo exato", Subscription{EventTypes: []string{"billing.payment.failed"}}, true},
+		{"prefixo", Subscription{EventTypes: []string{"billing.*"}}, true},
+		{"curinga", Subscription{EventTypes: []string{"*"}}, true},
+		{"outro tipo", Subscription{EventTypes: []string{"billing.payment.succeeded"}}, false},
+		{"prefixo parcial não casa", Subscription{EventTypes: []string{"bill.*"}}, false},
+		{"mesmo tenant", Subscription{EventTypes: []string{"*"}, TenantID: "tenant-a"}, true},
+		{"outro tenant", Subscription{EventTypes: []string{"*"}, TenantID: "tenant-b"}, false},
+		{"metadata presente", Subscription{EventTypes: []string{"*"}, Metadata: map[string]string{"plan": "pro"}}, true},
+		{"metadata diferente", Subscription{EventTypes: []string{"*"}, Metadata: map[string]string{"plan": "free"}}, false},
+		{"filtro programático", Subscription{EventTypes: []string{"*"}, Filter: func(e *types.Event) bool { return e.Source == "other" }}, false},
+	}
+	for _, tt := range tests {
+		tt.sub.Active = true
+		if got := tt.sub.Matches(event); got != tt.want {
+			t.Errorf("%s: Matches = %v, esperado %v", tt.name, got, tt.want)
+		}
+	}
+
+	inactive := Subscription{EventTypes: []string{"*"}}
+	if inactive.Matches(event) {
+		t.Error("assinatura inativa não deveria receber eventos")
+	}
+}
+
+func TestBridge_ForwardsOnlyMatchingSubscriptions(t *testing.T) {
+	b, _ := newTestBridge(t, Options{})
+	tenantA := newEndpoint(t)
+	tenantB := newEndpoint(t)
+	agents := newEndpoint(t)
+
+	addSubscription(t, b, Subscription{URL: tenantA.URL, EventTypes: []string{"billing.*"}, TenantID: "tenant-a"})
+	addSubscription(t, b, Subscription{URL: tenantB.URL, EventTypes: []string{"billing.*"}, TenantID: "tenant-b"})
+	addSubscription(t, b, Subscription{URL: agents.URL, EventTypes: []string{"agent.*"}})
+
+	event := types.NewEvent("billing.payment.failed", "billing-service", map[string]any{"amount": 42}).WithTenantID("tenant-a")
+	if err := b.Handle(event); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if tenantA.calls() != 1 || tenantB.calls() != 0 || agents.calls() != 0 {
+		t.Errorf("entregas = %d/%d/%d, esperado 1/0/0", tenantA.calls(), tenantB.calls(), agents.calls())
+	}
+	if got := tenantA.headers[0].Get(HeaderEventType); got != "billing.payment.failed" {
+		t.Errorf("%s = %q", HeaderEventType, got)
+	}
+	if got := tenantA.headers[0].Get(HeaderEventID); got != event.ID {
+		t.Errorf("%s = %q, esperado %q", HeaderEventID, got, event.ID)
+	}
+}
+
+func TestBridge_SignsDeliveries(t *testing.T) {
+	b, _ := newTestBridge(t, Options{})
+	ep := newEndpoint(t)
+	sub := addSubscription(t, b, Subscription{URL: ep.URL, EventTypes: []string{"*"}})
+
+	if !strings.HasPrefix(sub.Secret, "whsec_") {
+		t.Fatalf("segredo gerado inesperado: %q", sub.Secret)
+	}
+
+	if err := b.Handle(types.NewEvent("agent.created", "agent-service", map[string]string{"name": "Ana"})); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if ep.calls() != 1 {
+		t.Fatalf("esperada 1 entrega, obtidas %d", ep.calls())
+	}
+
+	header, body := ep.headers[0].Get(HeaderSignature), ep.bodies[0]
+	if err := Verify(sub.Secret, header, body, DefaultTolerance); err != nil {
+		t.Errorf("assinatura da entrega deveria ser válida: %v", err)
+	}
+	if err := Verify("outro-segredo", header, body, DefaultTolerance); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("segredo errado deveria falhar, obtido %v", err)
+	}
+	tampered := append([]byte(nil), body...)
+	tampered[len(tampered)-2] ^= 1
+	if err := Verify(sub.Secret, header, tampered, DefaultTolerance); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("corpo alterado deveria falhar, obtido %v", err)
+	}
+}
+
+func TestSign_KnownVectorAndTolerance(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	body := []byte(`{"id":"evt_1"}`)
+
+	// echo -n '1700000000.{"id":"evt_1"}' | openssl dgst -sha256 -hmac secret
+	want := "t=1700000000,v1=" + hexHMAC(t, "secret", `1700000000.{"id":"evt_1"}`)
+	if got := Sign("secret", at, body); got != want {
+		t.Errorf("Sign = %q, esperado %q", got, want)
+	}
+
+	header := Sign("secret", at, body)
+	if err := verifyAt("secret", header, body, DefaultTolerance, at.Add(4*time.Minute)); err != nil {
+		t.Errorf("assinatura dentro da tolerância: %v", err)
+	}
+	if err := verifyAt("secret", header, body, DefaultTolerance, at.Add(6*time.Minute)); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("assinatura antiga deveria expirar, obtido %v", err)
+	}
+	for _, bad := range []string{"", "v1=abc", "t=1700000000", "t=abc,v1=00"} {
+		if err := verifyAt("secret", bad, body, DefaultTolerance, at); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("header %q deveria ser inválido, obtido %v", bad, err)
+		}
+	}
+}
+
+func TestBridge_RetriesWithBackoffThenDelivers(t *testing.T) {
+	b, dlq := newTestBridge(t, Options{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second})
+	var waits []time.Duration
+	b.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	ep := newEndpoint(t, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusInternalServerError, http.StatusOK)
+	addSubscription(t, b, Subscription{URL: ep.URL, EventTypes: []string{"*"}})
+
+	if err := b.Handle(types.NewEvent("tool.invoked", "tool-service", nil)); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	if ep.calls() != 4 {
+		t.Errorf("esperadas 4 tentativas, obtidas %d", ep.calls())
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}; len(waits) != 3 || waits[0] != want[0] || waits[1] != want[1] || waits[2] != want[2] {
+		t.Errorf("esperas = %v, esperado %v", waits, want)
+	}
+	if len(dlq.Items()) != 0 {
+		t.Errorf("entrega bem-sucedida não deveria ir para a DLQ: %+v", dlq.Items())
+	}
+	deliveryIDs := map[string]bool{}
+	for _, h := range ep.headers {
+		deliveryIDs[h.Get(HeaderDeliveryID)] = true
+	}
+	if len(deliveryIDs) != 1 {
+		t.Errorf("tentativas da mesma entrega deveriam compartilhar %s, obtidos %v", HeaderDeliveryID, deliveryIDs)
+	}
+}
+
+func TestBridge_DeadLettersAndDisablesFailingEndpoint(t *testing.T) {
+	b, dlq := newTestBridge(t, Options{MaxAttempts: 3, DisableAfter: 2})
+	down := newEndpoint(t, 500, 500, 500, 500, 500, 500)
+	gone := newEndpoint(t, http.StatusGone)
+	downSub := addSubscription(t, b, Subscription{URL: down.URL, EventTypes: []string{"*"}})
+	addSubscription(t, b, Subscription{URL: gone.URL, EventTypes: []string{"*"}})
+
+	first := types.NewEvent("agent.created", "agent-service", nil)
+	if err := b.Handle(first); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	// 4xx é definitivo: uma tentativa só
+	if gone.calls() != 1 {
+		t.Errorf("410 não deveria ser repetido, obtidas %d tentativas", gone.calls())
+	}
+	if down.calls() != 3 {
+		t.Errorf("esperadas 3 tentativas no endpoint fora do ar, obtidas %d", down.calls())
+	}
+
+	items := dlq.Items()
+	if len(items) != 2 {
+		t.Fatalf("esperadas 2 entregas na DLQ, obtidas %d", len(items))
+	}
+	for _, dead := range items {
+		if dead.Event.ID != first.ID {
+			t.Errorf("evento na DLQ = %s, esperado %s", dead.Event.ID, first.ID)
+		}
+		if dead.SubscriptionID == downSub.ID && (dead.Attempts != 3 || dead.StatusCode != 500) {
+			t.Errorf("entrega perdida inesperada: %+v", dead)
+		}
+	}
+
+	// Segunda entrega perdida seguida desativa a assinatura
+	if err := b.Handle(types.NewEvent("agent.updated", "agent-service", nil)); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	sub, err := b.store.Get(context.Background(), downSub.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if sub.Active {
+		t.Error("assinatura deveria ser desativada após falhas seguidas")
+	}
+
+	calls := down.calls()
+	if err := b.Handle(types.NewEvent("agent.deleted", "agent-service", nil)); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if down.calls() != calls {
+		t.Error("assinatura desativada não deveria receber entregas")
+	}
+}
+
+func TestBridge_ListSubscriptionsHidesSecrets(t *testing.T) {
+	b, _ := newTestBridge(t, Options{})
+	addSubscription(t, b, Subscription{URL: "https://hooks.example.com/serphona", Secret: "s3cret", EventTypes: []string{"*"}})
+
+	subs, err := b.ListSubscriptions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSubscriptions: %v", err)
+	}
+	if len(subs) != 1 || subs[0].Secret != "" {
+		t.Errorf("listagem não deveria expor segredos: %+v", subs)
+	}
+
+	for _, bad := range []Subscription{
+		{URL: "ftp://example.com", EventTypes: []string{"*"}},
+		{URL: "https://hooks.example.com"},
+	} {
+		if _, err := b.AddSubscription(context.Background(), bad); !errors.Is(err, ErrInvalidSubscription) {
+			t.Errorf("%+v: esperado ErrInvalidSubscription, obtido %v", bad, err)
+		}
+	}
+}
+
+func hexHMAC(t *testing.T, secret, payload string) string {
+	t.Helper()
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
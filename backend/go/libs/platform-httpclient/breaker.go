@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerConfig configura o circuit breaker. Campos zerados usam o valor de
+// DefaultBreakerConfig.
+type BreakerConfig struct {
+	// FailureThreshold é o número de falhas seguidas que abre o circuito
+	FailureThreshold int
+	// Cooldown é quanto o circuito fica aberto antes de deixar passar uma
+	// requisição de teste
+	Cooldown time.Duration
+}
+
+// DefaultBreakerConfig são os valores usados para campos zerados de
+// BreakerConfig
+var DefaultBreakerConfig = BreakerConfig{
+	FailureThreshold: 5,
+	Cooldown:         30 * time.Second,
+}
+
+// breakerState é o estado do circuito
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // requisições seguem normalmente
+	breakerOpen                         // requisições falham na hora até o fim do cooldown
+	breakerHalfOpen                     // uma requisição de teste em andamento
+)
+
+// breaker para de chamar um serviço após falhas seguidas, para que uma queda
+// custe uma decisão local por requisição em vez de um timeout. Após o
+// cooldown, uma requisição de teste decide se o circuito fecha.
+type breaker struct {
+	config BreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func newBreaker(config BreakerConfig) *breaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultBreakerConfig.FailureThreshold
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultBreakerConfig.Cooldown
+	}
+	return &breaker{config: config, now: time.Now}
+}
+
+// allow informa se uma requisição pode seguir
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.config.Cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// record registra o resultado de uma requisição
+func (b *breaker) record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ok {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// abandon devolve ao estado aberto um circuito cuja requisição de teste
+// terminou sem resposta do serviço, para que o próximo cooldown libere outra
+func (b *breaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,217 @@
+// Package httpclient é o cliente HTTP das chamadas entre serviços: prazo por
+// tentativa, novas tentativas com backoff para métodos idempotentes e
+// respostas 5xx, pool de conexões, cancelamento pelo contexto, propagação do
+// cabeçalho de trace e, opcionalmente, circuit breaker.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ErrCircuitOpen é retornado sem chamar o serviço enquanto o circuito está
+// aberto
+var ErrCircuitOpen = errors.New("httpclient: circuito aberto")
+
+// Config configura um Client. Campos zerados usam o valor de DefaultConfig.
+type Config struct {
+	// Timeout é o prazo de cada tentativa, incluindo a leitura do corpo
+	Timeout time.Duration
+	// MaxRetries é o número de novas tentativas; negativo desliga
+	MaxRetries int
+	// RetryBackoff é a espera antes da primeira nova tentativa, dobrada a
+	// cada tentativa até MaxRetryBackoff, com jitter
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// MaxIdleConnsPerHost e IdleConnTimeout configuram o pool de conexões
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// Breaker liga o circuit breaker; nil desliga
+	Breaker *BreakerConfig
+	// Wrap envolve o transport com pool, por exemplo para criar spans de
+	// cliente (tracing.Transport)
+	Wrap func(http.RoundTripper) http.RoundTripper
+}
+
+// DefaultConfig são os valores usados para campos zerados de Config
+var DefaultConfig = Config{
+	Timeout:             10 * time.Second,
+	MaxRetries:          2,
+	RetryBackoff:        100 * time.Millisecond,
+	MaxRetryBackoff:     2 * time.Second,
+	MaxIdleConnsPerHost: 32,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// Client executa requisições HTTP com novas tentativas e circuit breaker.
+// É seguro para uso concorrente.
+type Client struct {
+	http    *http.Client
+	config  Config
+	breaker *breaker
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// New cria um Client
+func New(config Config) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultConfig.Timeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultConfig.MaxRetries
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultConfig.RetryBackoff
+	}
+	if config.MaxRetryBackoff <= 0 {
+		config.MaxRetryBackoff = DefaultConfig.MaxRetryBackoff
+	}
+	if config.MaxIdleConnsPerHost <= 0 {
+		config.MaxIdleConnsPerHost = DefaultConfig.MaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeout <= 0 {
+		config.IdleConnTimeout = DefaultConfig.IdleConnTimeout
+	}
+
+	var transport http.RoundTripper = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          config.MaxIdleConnsPerHost * 4,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+	if config.Wrap != nil {
+		transport = config.Wrap(transport)
+	}
+
+	c := &Client{
+		http:   &http.Client{Timeout: config.Timeout, Transport: transport},
+		config: config,
+		sleep:  sleep,
+	}
+	if config.Breaker != nil {
+		c.breaker = newBreaker(*config.Breaker)
+	}
+	return c
+}
+
+// Do envia req. Requisições idempotentes (GET, HEAD, OPTIONS, PUT, DELETE, ou
+// com cabeçalho Idempotency-Key) são repetidas após erros de rede e
+// respostas 5xx; a última resposta 5xx é retornada ao chamador. O contexto
+// de req cancela a requisição e as esperas entre tentativas.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.breaker != nil && !c.breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	retries := 0
+	if retryable(req) {
+		retries = c.config.MaxRetries
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		resp, err = c.attempt(req, attempt)
+		if attempt >= retries || !shouldRetry(req.Context(), resp, err) {
+			break
+		}
+
+		// A resposta descartada libera a conexão para o pool
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if sleepErr := c.sleep(req.Context(), c.backoff(attempt)); sleepErr != nil {
+			resp, err = nil, sleepErr
+			break
+		}
+	}
+
+	if c.breaker != nil {
+		// Um cancelamento do chamador não diz nada sobre o serviço
+		if req.Context().Err() != nil {
+			c.breaker.abandon()
+		} else {
+			c.breaker.record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+		}
+	}
+	return resp, err
+}
+
+// attempt envia uma tentativa de req, com o corpo relido a partir da segunda
+func (c *Client) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if attempt > 0 && req.Body != nil {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("httpclient: corpo da requisição não pode ser reenviado")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		out.Body = body
+	}
+	otel.GetTextMapPropagator().Inject(out.Context(), propagation.HeaderCarrier(out.Header))
+	return c.http.Do(out)
+}
+
+// backoff é a espera antes da tentativa seguinte a attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.config.RetryBackoff << attempt
+	if d <= 0 || d > c.config.MaxRetryBackoff {
+		d = c.config.MaxRetryBackoff
+	}
+	// Jitter de até metade da espera evita tentativas sincronizadas
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryable informa se req pode ser repetida sem efeitos duplicados
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+// shouldRetry informa se a tentativa falhou de forma transitória
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+// sleep espera d ou até ctx terminar
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,211 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// noSleep elimina as esperas entre tentativas nos testes
+func noSleep(ctx context.Context, d time.Duration) error { return ctx.Err() }
+
+// flakyServer responde 503 nas primeiras failures requisições
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newTestClient(config Config) *Client {
+	c := New(config)
+	c.sleep = noSleep
+	return c
+}
+
+func TestRetriesIdempotentRequests(t *testing.T) {
+	srv, calls := flakyServer(t, 2)
+	c := newTestClient(Config{MaxRetries: 2})
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("payload"))
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do falhou: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "payload" {
+		t.Errorf("resposta = %d %q, esperado 200 com o corpo reenviado", resp.StatusCode, body)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("chamadas = %d, esperado 3", calls.Load())
+	}
+}
+
+func TestReturnsLast5xxWhenRetriesRunOut(t *testing.T) {
+	srv, calls := flakyServer(t, 10)
+	c := newTestClient(Config{MaxRetries: 2})
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do falhou: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 3 {
+		t.Errorf("resposta = %d após %d chamadas, esperado 503 após 3", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	srv, calls := flakyServer(t, 1)
+	c := newTestClient(Config{MaxRetries: 2})
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("{}"))
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do falhou: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("POST repetido: %d chamadas", calls.Load())
+	}
+
+	// Com Idempotency-Key o POST pode ser repetido
+	calls.Store(0)
+	req, _ = http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("{}"))
+	req.Header.Set("Idempotency-Key", "turn-1")
+	resp, err = c.Do(req)
+	if err != nil {
+		t.Fatalf("Do falhou: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("resposta = %d após %d chamadas, esperado 200 após 2", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestCircuitOpensAfterFailures(t *testing.T) {
+	srv, calls := flakyServer(t, 3)
+	c := newTestClient(Config{MaxRetries: -1, Breaker: &BreakerConfig{FailureThreshold: 3, Cooldown: time.Minute}})
+	now := time.Now()
+	c.breaker.now = func() time.Time { return now }
+
+	get := func() (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := c.Do(req)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := get(); err != nil {
+			t.Fatalf("Do falhou: %v", err)
+		}
+	}
+	if _, err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("erro = %v, esperado ErrCircuitOpen", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("circuito aberto chamou o serviço: %d chamadas", calls.Load())
+	}
+
+	// Após o cooldown, uma requisição de teste bem-sucedida fecha o circuito
+	now = now.Add(time.Minute)
+	if resp, err := get(); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("requisição de teste = %v, %v; esperado 200", resp, err)
+	}
+	if _, err := get(); err != nil {
+		t.Errorf("circuito deveria estar fechado: %v", err)
+	}
+}
+
+func TestFailedProbeReopensCircuit(t *testing.T) {
+	srv, _ := flakyServer(t, 10)
+	c := newTestClient(Config{MaxRetries: -1, Breaker: &BreakerConfig{FailureThreshold: 1, Cooldown: time.Minute}})
+	now := time.Now()
+	c.breaker.now = func() time.Time { return now }
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, _ := c.Do(req)
+	resp.Body.Close()
+
+	now = now.Add(time.Minute)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("requisição de teste falhou: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := c.Do(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("erro = %v, esperado o circuito reaberto", err)
+	}
+}
+
+func TestContextCancelStopsRetries(t *testing.T) {
+	srv, calls := flakyServer(t, 10)
+	c := New(Config{MaxRetries: 5, RetryBackoff: time.Hour, MaxRetryBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	if _, err := c.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("erro = %v, esperado context.DeadlineExceeded", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("chamadas = %d, esperado 1", calls.Load())
+	}
+}
+
+func TestPropagatesTraceHeader(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	resp, err := newTestClient(Config{}).Do(req)
+	if err != nil {
+		t.Fatalf("Do falhou: %v", err)
+	}
+	resp.Body.Close()
+
+	if !strings.Contains(traceparent, sc.TraceID().String()) {
+		t.Errorf("traceparent = %q, esperado o trace %s", traceparent, sc.TraceID())
+	}
+}
@@ -0,0 +1,262 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Config configura um Client. Campos zerados usam o valor de DefaultConfig.
+type Config struct {
+	// Workers é o número de entregas simultâneas
+	Workers int
+	// QueueSize é o número de mensagens aguardando entrega; com a fila cheia
+	// os envios falham com ErrQueueFull
+	QueueSize int
+	// MaxAttempts é o número de tentativas de cada entrega
+	MaxAttempts int
+	// Backoff é a espera antes da segunda tentativa, dobrada a cada
+	// tentativa até MaxBackoff, com jitter
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Senders resolve o remetente de cada tenant; nil usa só Sender
+	Senders SenderResolver
+	// Sender é o remetente quando Senders é nil
+	Sender Sender
+	// OnFailure é chamado quando uma entrega é abandonada, depois da última
+	// tentativa ou de um erro permanente; opcional
+	OnFailure func(d Delivery, err error)
+}
+
+// DefaultConfig são os valores usados para campos zerados de Config
+var DefaultConfig = Config{
+	Workers:     2,
+	QueueSize:   256,
+	MaxAttempts: 5,
+	Backoff:     time.Second,
+	MaxBackoff:  time.Minute,
+}
+
+// EmailRequest pede o envio de um email a partir de um template
+type EmailRequest struct {
+	TenantID string // remetente do tenant; vazio usa o da plataforma
+	To       string
+	Template string
+	Data     any
+}
+
+// SMSRequest pede o envio de um SMS a partir de um template
+type SMSRequest struct {
+	TenantID string
+	To       string
+	Template string
+	Data     any
+}
+
+// Delivery descreve uma entrega, para OnFailure
+type Delivery struct {
+	Channel  string // "email" ou "sms"
+	TenantID string
+	To       string
+	Template string
+	Attempts int
+}
+
+type job struct {
+	delivery Delivery
+	send     func(ctx context.Context) error
+}
+
+// Client monta as notificações e as entrega em segundo plano. É seguro para
+// uso concorrente.
+type Client struct {
+	providers Providers
+	templates *Templates
+	config    Config
+
+	queue   chan job
+	mu      sync.RWMutex // protege closed e o envio na fila
+	closed  bool
+	workers sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// New cria um Client e inicia as entregas; encerre com Close
+func New(providers Providers, templates *Templates, config Config) *Client {
+	if config.Workers <= 0 {
+		config.Workers = DefaultConfig.Workers
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultConfig.QueueSize
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultConfig.MaxAttempts
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultConfig.Backoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultConfig.MaxBackoff
+	}
+	if config.Senders == nil {
+		config.Senders = StaticSenders{Default: config.Sender}
+	}
+
+	c := &Client{
+		providers: providers,
+		templates: templates,
+		config:    config,
+		queue:     make(chan job, config.QueueSize),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	for i := 0; i < config.Workers; i++ {
+		c.workers.Add(1)
+		go c.work()
+	}
+	return c
+}
+
+// SendEmail monta o email e o coloca na fila de entrega. Erros de template,
+// de remetente e de fila são retornados na hora; falhas de entrega vão para
+// OnFailure.
+func (c *Client) SendEmail(ctx context.Context, req EmailRequest) error {
+	if c.providers.Email == nil {
+		return fmt.Errorf("%w: email", ErrNoProvider)
+	}
+	r, err := c.templates.Render(req.Template, req.Data)
+	if err != nil {
+		return err
+	}
+	sender, err := c.config.Senders.Sender(ctx, req.TenantID)
+	if err != nil {
+		return fmt.Errorf("notifications: remetente do tenant %s: %w", req.TenantID, err)
+	}
+
+	email := Email{From: sender.Email, To: req.To, Subject: r.Subject, Text: r.Text, HTML: r.HTML}
+	return c.enqueue(job{
+		delivery: Delivery{Channel: "email", TenantID: req.TenantID, To: req.To, Template: req.Template},
+		send: func(ctx context.Context) error {
+			return c.providers.Email.SendEmail(ctx, email)
+		},
+	})
+}
+
+// SendSMS monta o SMS e o coloca na fila de entrega, como SendEmail
+func (c *Client) SendSMS(ctx context.Context, req SMSRequest) error {
+	if c.providers.SMS == nil {
+		return fmt.Errorf("%w: sms", ErrNoProvider)
+	}
+	r, err := c.templates.Render(req.Template, req.Data)
+	if err != nil {
+		return err
+	}
+	sender, err := c.config.Senders.Sender(ctx, req.TenantID)
+	if err != nil {
+		return fmt.Errorf("notifications: remetente do tenant %s: %w", req.TenantID, err)
+	}
+
+	sms := SMS{From: sender.SMS, To: req.To, Body: r.Text}
+	return c.enqueue(job{
+		delivery: Delivery{Channel: "sms", TenantID: req.TenantID, To: req.To, Template: req.Template},
+		send: func(ctx context.Context) error {
+			return c.providers.SMS.SendSMS(ctx, sms)
+		},
+	})
+}
+
+func (c *Client) enqueue(j job) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+	select {
+	case c.queue <- j:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close para de aceitar mensagens e espera a fila ser entregue. Se ctx
+// terminar antes, as entregas pendentes são abandonadas e o erro de ctx é
+// retornado.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		c.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		c.cancel()
+		return nil
+	case <-ctx.Done():
+		c.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (c *Client) work() {
+	defer c.workers.Done()
+	for j := range c.queue {
+		c.deliver(j)
+	}
+}
+
+// deliver tenta a entrega até MaxAttempts vezes, com backoff exponencial
+func (c *Client) deliver(j job) {
+	var err error
+	for attempt := 1; attempt <= c.config.MaxAttempts; attempt++ {
+		j.delivery.Attempts = attempt
+		if err = j.send(c.ctx); err == nil {
+			return
+		}
+		if IsPermanent(err) || attempt == c.config.MaxAttempts {
+			break
+		}
+
+		if !c.wait(c.backoff(attempt)) {
+			err = fmt.Errorf("%w (abandonada no encerramento)", err)
+			break
+		}
+	}
+
+	if c.config.OnFailure != nil {
+		c.config.OnFailure(j.delivery, err)
+	}
+}
+
+// wait espera d; retorna false se o cliente for encerrado antes
+func (c *Client) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
+// backoff é a espera após a tentativa attempt: Backoff dobrado a cada
+// tentativa, limitado a MaxBackoff, com jitter de até metade do valor
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.config.Backoff << (attempt - 1)
+	if d <= 0 || d > c.config.MaxBackoff {
+		d = c.config.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
@@ -0,0 +1,182 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testTemplates() *Templates {
+	return NewTemplates().
+		MustRegister("welcome", Template{Subject: "Bem-vindo", Text: "Olá {{.Name}}"}).
+		MustRegister("code", Template{Text: "Código: {{.Code}}"})
+}
+
+func closeClient(t *testing.T, c *Client) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.Close(ctx); err != nil {
+		t.Fatalf("Close retornou erro: %v", err)
+	}
+}
+
+func TestClient_NoopProvider(t *testing.T) {
+	noop := NewNoopProvider()
+	c := New(Providers{Email: noop, SMS: noop}, testTemplates(), Config{
+		Sender: Sender{Email: "Serphona <no-reply@serphona.com>", SMS: "+5511900000000"},
+	})
+
+	ctx := context.Background()
+	if err := c.SendEmail(ctx, EmailRequest{To: "ana@acme.com", Template: "welcome", Data: map[string]string{"Name": "Ana"}}); err != nil {
+		t.Fatalf("SendEmail retornou erro: %v", err)
+	}
+	if err := c.SendSMS(ctx, SMSRequest{To: "+5511999998888", Template: "code", Data: map[string]string{"Code": "123456"}}); err != nil {
+		t.Fatalf("SendSMS retornou erro: %v", err)
+	}
+	closeClient(t, c)
+
+	emails := noop.Emails()
+	if len(emails) != 1 {
+		t.Fatalf("emails = %d, esperado 1", len(emails))
+	}
+	if emails[0] != (Email{From: "Serphona <no-reply@serphona.com>", To: "ana@acme.com", Subject: "Bem-vindo", Text: "Olá Ana"}) {
+		t.Errorf("email = %+v", emails[0])
+	}
+
+	sms := noop.SMS()
+	if len(sms) != 1 {
+		t.Fatalf("sms = %d, esperado 1", len(sms))
+	}
+	if sms[0] != (SMS{From: "+5511900000000", To: "+5511999998888", Body: "Código: 123456"}) {
+		t.Errorf("sms = %+v", sms[0])
+	}
+}
+
+func TestClient_TenantSender(t *testing.T) {
+	noop := NewNoopProvider()
+	c := New(Providers{Email: noop}, testTemplates(), Config{
+		Senders: StaticSenders{
+			Default: Sender{Email: "no-reply@serphona.com"},
+			Tenants: map[string]Sender{"tenant-1": {Email: "Acme <avisos@acme.com>"}},
+		},
+	})
+
+	ctx := context.Background()
+	data := map[string]string{"Name": "Ana"}
+	c.SendEmail(ctx, EmailRequest{TenantID: "tenant-1", To: "a@acme.com", Template: "welcome", Data: data})
+	c.SendEmail(ctx, EmailRequest{TenantID: "tenant-2", To: "b@other.com", Template: "welcome", Data: data})
+	closeClient(t, c)
+
+	from := map[string]string{}
+	for _, e := range noop.Emails() {
+		from[e.To] = e.From
+	}
+	if from["a@acme.com"] != "Acme <avisos@acme.com>" {
+		t.Errorf("remetente do tenant-1 = %q", from["a@acme.com"])
+	}
+	if from["b@other.com"] != "no-reply@serphona.com" {
+		t.Errorf("remetente do tenant-2 = %q, esperado o padrão", from["b@other.com"])
+	}
+}
+
+// flakyProvider falha as primeiras failures entregas
+type flakyProvider struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	calls    int
+}
+
+func (p *flakyProvider) SendEmail(ctx context.Context, email Email) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.calls <= p.failures {
+		return p.err
+	}
+	return nil
+}
+
+func TestClient_RetriesUntilDelivered(t *testing.T) {
+	provider := &flakyProvider{failures: 2, err: errors.New("timeout")}
+	var failed []Delivery
+	c := New(Providers{Email: provider}, testTemplates(), Config{
+		Backoff:   time.Millisecond,
+		OnFailure: func(d Delivery, err error) { failed = append(failed, d) },
+	})
+
+	c.SendEmail(context.Background(), EmailRequest{To: "a@acme.com", Template: "welcome", Data: map[string]string{"Name": "Ana"}})
+	closeClient(t, c)
+
+	if provider.calls != 3 {
+		t.Errorf("tentativas = %d, esperado 3", provider.calls)
+	}
+	if len(failed) != 0 {
+		t.Errorf("OnFailure chamado para uma entrega bem-sucedida: %+v", failed)
+	}
+}
+
+func TestClient_GivesUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		attempts int
+	}{
+		{"erro temporário", errors.New("timeout"), 3},
+		{"erro permanente", Permanent(errors.New("destinatário inválido")), 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &flakyProvider{failures: 10, err: tt.err}
+			var mu sync.Mutex
+			var failed []Delivery
+			c := New(Providers{Email: provider}, testTemplates(), Config{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+				OnFailure: func(d Delivery, err error) {
+					mu.Lock()
+					defer mu.Unlock()
+					failed = append(failed, d)
+				},
+			})
+
+			c.SendEmail(context.Background(), EmailRequest{To: "a@acme.com", Template: "welcome", Data: map[string]string{"Name": "Ana"}})
+			closeClient(t, c)
+
+			if len(failed) != 1 {
+				t.Fatalf("OnFailure chamado %d vezes, esperado 1", len(failed))
+			}
+			if failed[0].Attempts != tt.attempts || provider.calls != tt.attempts {
+				t.Errorf("tentativas = %d (provedor %d), esperado %d", failed[0].Attempts, provider.calls, tt.attempts)
+			}
+			if failed[0].Channel != "email" || failed[0].To != "a@acme.com" || failed[0].Template != "welcome" {
+				t.Errorf("Delivery = %+v", failed[0])
+			}
+		})
+	}
+}
+
+func TestClient_RejectsSynchronously(t *testing.T) {
+	noop := NewNoopProvider()
+	c := New(Providers{Email: noop}, testTemplates(), Config{})
+	ctx := context.Background()
+
+	if err := c.SendEmail(ctx, EmailRequest{To: "a@acme.com", Template: "missing"}); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("template desconhecido: erro = %v", err)
+	}
+	if err := c.SendSMS(ctx, SMSRequest{To: "+5511999998888", Template: "code", Data: map[string]string{"Code": "1"}}); !errors.Is(err, ErrNoProvider) {
+		t.Errorf("SMS sem provedor: erro = %v", err)
+	}
+
+	closeClient(t, c)
+	if err := c.SendEmail(ctx, EmailRequest{To: "a@acme.com", Template: "welcome", Data: map[string]string{"Name": "Ana"}}); !errors.Is(err, ErrClosed) {
+		t.Errorf("envio depois de Close: erro = %v", err)
+	}
+	if len(noop.Emails()) != 0 {
+		t.Errorf("emails = %d, esperado nenhum", len(noop.Emails()))
+	}
+}
@@ -0,0 +1,49 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+)
+
+// NoopProvider não entrega nada: guarda as mensagens em memória. É o
+// provedor de desenvolvimento e de testes, para os dois canais.
+type NoopProvider struct {
+	mu     sync.Mutex
+	emails []Email
+	sms    []SMS
+}
+
+// NewNoopProvider cria um NoopProvider
+func NewNoopProvider() *NoopProvider {
+	return &NoopProvider{}
+}
+
+// SendEmail implementa EmailProvider
+func (p *NoopProvider) SendEmail(ctx context.Context, email Email) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.emails = append(p.emails, email)
+	return nil
+}
+
+// SendSMS implementa SMSProvider
+func (p *NoopProvider) SendSMS(ctx context.Context, sms SMS) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sms = append(p.sms, sms)
+	return nil
+}
+
+// Emails retorna os emails recebidos, na ordem de envio
+func (p *NoopProvider) Emails() []Email {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Email(nil), p.emails...)
+}
+
+// SMS retorna os SMS recebidos, na ordem de envio
+func (p *NoopProvider) SMS() []SMS {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]SMS(nil), p.sms...)
+}
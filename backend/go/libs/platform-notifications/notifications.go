@@ -0,0 +1,107 @@
+// Package notifications envia emails e SMS da plataforma (convites, reset de
+// senha, avisos de quota, cobrança) por um provedor plugável (SendGrid,
+// Twilio ou o NoopProvider de desenvolvimento). As mensagens são montadas a
+// partir de templates e do remetente do tenant e entregues em segundo plano,
+// com novas tentativas.
+package notifications
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrUnknownTemplate indica um template que não foi registrado
+	ErrUnknownTemplate = errors.New("notifications: template desconhecido")
+	// ErrNoProvider indica um canal (email ou SMS) sem provedor configurado
+	ErrNoProvider = errors.New("notifications: canal sem provedor")
+	// ErrQueueFull indica que a fila de entrega está cheia
+	ErrQueueFull = errors.New("notifications: fila de entrega cheia")
+	// ErrClosed indica um envio depois de Close
+	ErrClosed = errors.New("notifications: cliente encerrado")
+)
+
+// Email é um email pronto para o provedor
+type Email struct {
+	From    string // "Nome <endereco@dominio>" ou só o endereço
+	To      string
+	Subject string
+	Text    string
+	HTML    string // opcional
+}
+
+// SMS é um SMS pronto para o provedor
+type SMS struct {
+	From string // número E.164 ou sender ID
+	To   string // número E.164
+	Body string
+}
+
+// EmailProvider entrega emails
+type EmailProvider interface {
+	SendEmail(ctx context.Context, email Email) error
+}
+
+// SMSProvider entrega SMS
+type SMSProvider interface {
+	SendSMS(ctx context.Context, sms SMS) error
+}
+
+// Providers reúne o provedor de cada canal. Um canal sem provedor recusa os
+// envios com ErrNoProvider.
+type Providers struct {
+	Email EmailProvider
+	SMS   SMSProvider
+}
+
+// Sender é o remetente das mensagens de um tenant
+type Sender struct {
+	Email string // remetente dos emails, como "Acme <no-reply@acme.com>"
+	SMS   string // número ou sender ID dos SMS
+}
+
+// SenderResolver retorna o remetente de um tenant. tenantID vazio pede o
+// remetente da plataforma.
+type SenderResolver interface {
+	Sender(ctx context.Context, tenantID string) (Sender, error)
+}
+
+// StaticSenders é um SenderResolver com remetentes fixos. Tenants sem
+// remetente próprio, ou com campos vazios, usam Default.
+type StaticSenders struct {
+	Default Sender
+	Tenants map[string]Sender
+}
+
+// Sender implementa SenderResolver
+func (s StaticSenders) Sender(ctx context.Context, tenantID string) (Sender, error) {
+	sender := s.Tenants[tenantID]
+	if sender.Email == "" {
+		sender.Email = s.Default.Email
+	}
+	if sender.SMS == "" {
+		sender.SMS = s.Default.SMS
+	}
+	return sender, nil
+}
+
+// permanentError marca um erro que não adianta tentar de novo
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marca err como definitivo, como um destinatário inválido: a
+// entrega não é tentada de novo
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent informa se err foi marcado com Permanent
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// SendGrid entrega emails pela API v3 do SendGrid
+type SendGrid struct {
+	apiKey  string
+	baseURL string
+	http    *http.Client
+}
+
+// NewSendGrid cria um provedor SendGrid; client nil usa http.DefaultClient
+func NewSendGrid(apiKey string, client *http.Client) *SendGrid {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &SendGrid{apiKey: apiKey, baseURL: "https://api.sendgrid.com", http: client}
+}
+
+// SendEmail implementa EmailProvider
+func (s *SendGrid) SendEmail(ctx context.Context, email Email) error {
+	from, err := mail.ParseAddress(email.From)
+	if err != nil {
+		return Permanent(fmt.Errorf("sendgrid: remetente %q: %w", email.From, err))
+	}
+
+	type address struct {
+		Email string `json:"email"`
+		Name  string `json:"name,omitempty"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	body := struct {
+		Personalizations []struct {
+			To []address `json:"to"`
+		} `json:"personalizations"`
+		From    address   `json:"from"`
+		Subject string    `json:"subject"`
+		Content []content `json:"content"`
+	}{
+		From:    address{Email: from.Address, Name: from.Name},
+		Subject: email.Subject,
+		Content: []content{{Type: "text/plain", Value: email.Text}},
+	}
+	body.Personalizations = append(body.Personalizations, struct {
+		To []address `json:"to"`
+	}{To: []address{{Email: email.To}}})
+	if email.HTML != "" {
+		body.Content = append(body.Content, content{Type: "text/html", Value: email.HTML})
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return Permanent(fmt.Errorf("sendgrid: %w", err))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/v3/mail/send", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	return do(s.http, req, "sendgrid")
+}
+
+// Twilio entrega SMS pela API de mensagens da Twilio
+type Twilio struct {
+	accountSID string
+	authToken  string
+	baseURL    string
+	http       *http.Client
+}
+
+// NewTwilio cria um provedor Twilio; client nil usa http.DefaultClient
+func NewTwilio(accountSID, authToken string, client *http.Client) *Twilio {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Twilio{accountSID: accountSID, authToken: authToken, baseURL: "https://api.twilio.com", http: client}
+}
+
+// SendSMS implementa SMSProvider
+func (t *Twilio) SendSMS(ctx context.Context, sms SMS) error {
+	form := url.Values{"From": {sms.From}, "To": {sms.To}, "Body": {sms.Body}}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", t.baseURL, url.PathEscape(t.accountSID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("twilio: %w", err)
+	}
+	req.SetBasicAuth(t.accountSID, t.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return do(t.http, req, "twilio")
+}
+
+// do executa req e traduz a resposta: 2xx é sucesso; 4xx, exceto 408 e 429,
+// é erro permanente; o resto pode ser tentado de novo
+func do(client *http.Client, req *http.Request, provider string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("%s: status %d: %s", provider, resp.StatusCode, bytes.TrimSpace(detail))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
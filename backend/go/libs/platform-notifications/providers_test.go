@@ -0,0 +1,64 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendGrid_SendEmail(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/mail/send" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("requisição inesperada: %s %s", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sg := NewSendGrid("key", server.Client())
+	sg.baseURL = server.URL
+
+	err := sg.SendEmail(context.Background(), Email{From: "Acme <no-reply@acme.com>", To: "ana@acme.com", Subject: "Oi", Text: "texto"})
+	if err != nil {
+		t.Fatalf("SendEmail retornou erro: %v", err)
+	}
+	from, _ := body["from"].(map[string]any)
+	if from["email"] != "no-reply@acme.com" || from["name"] != "Acme" {
+		t.Errorf("from = %v", body["from"])
+	}
+}
+
+func TestProviders_StatusClassification(t *testing.T) {
+	tests := []struct {
+		status    int
+		permanent bool
+	}{
+		{http.StatusBadRequest, true},
+		{http.StatusUnauthorized, true},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		tw := NewTwilio("AC123", "token", server.Client())
+		tw.baseURL = server.URL
+		err := tw.SendSMS(context.Background(), SMS{From: "+5511900000000", To: "+5511999998888", Body: "oi"})
+		server.Close()
+
+		if err == nil {
+			t.Errorf("status %d: esperado erro", tt.status)
+			continue
+		}
+		if IsPermanent(err) != tt.permanent {
+			t.Errorf("status %d: IsPermanent = %v, esperado %v", tt.status, IsPermanent(err), tt.permanent)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// Template é o conteúdo de uma notificação, na sintaxe de text/template.
+// Emails usam Subject, Text e, opcionalmente, HTML, que é escapado como
+// html/template; SMS usam só Text.
+type Template struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Rendered é um template preenchido com os dados de uma mensagem
+type Rendered struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type parsedTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Templates guarda os templates por nome. É seguro para uso concorrente.
+type Templates struct {
+	mu        sync.RWMutex
+	templates map[string]parsedTemplate
+}
+
+// NewTemplates cria um registro de templates vazio
+func NewTemplates() *Templates {
+	return &Templates{templates: make(map[string]parsedTemplate)}
+}
+
+// Register valida e registra tpl como name, substituindo um template de
+// mesmo nome. Campos usados no template que faltarem nos dados são erro na
+// renderização, para nunca enviar "<no value>".
+func (t *Templates) Register(name string, tpl Template) error {
+	var p parsedTemplate
+	var err error
+	if p.subject, err = texttemplate.New(name + ".subject").Option("missingkey=error").Parse(tpl.Subject); err != nil {
+		return fmt.Errorf("notifications: assunto do template %q: %w", name, err)
+	}
+	if p.text, err = texttemplate.New(name + ".text").Option("missingkey=error").Parse(tpl.Text); err != nil {
+		return fmt.Errorf("notifications: texto do template %q: %w", name, err)
+	}
+	if tpl.HTML != "" {
+		if p.html, err = htmltemplate.New(name + ".html").Option("missingkey=error").Parse(tpl.HTML); err != nil {
+			return fmt.Errorf("notifications: HTML do template %q: %w", name, err)
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.templates[name] = p
+	return nil
+}
+
+// MustRegister é Register para templates fixos no código; entra em pânico
+// se o template for inválido
+func (t *Templates) MustRegister(name string, tpl Template) *Templates {
+	if err := t.Register(name, tpl); err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render preenche o template name com data
+func (t *Templates) Render(name string, data any) (Rendered, error) {
+	t.mu.RLock()
+	p, ok := t.templates[name]
+	t.mu.RUnlock()
+	if !ok {
+		return Rendered{}, fmt.Errorf("%w: %q", ErrUnknownTemplate, name)
+	}
+
+	var r Rendered
+	var err error
+	if r.Subject, err = execute(p.subject, data); err != nil {
+		return Rendered{}, fmt.Errorf("notifications: template %q: %w", name, err)
+	}
+	// Quebras de linha no assunto viram cabeçalhos extras em alguns provedores
+	r.Subject = strings.Join(strings.Fields(r.Subject), " ")
+	if r.Text, err = execute(p.text, data); err != nil {
+		return Rendered{}, fmt.Errorf("notifications: template %q: %w", name, err)
+	}
+	if p.html != nil {
+		var b strings.Builder
+		if err := p.html.Execute(&b, data); err != nil {
+			return Rendered{}, fmt.Errorf("notifications: template %q: %w", name, err)
+		}
+		r.HTML = b.String()
+	}
+	return r, nil
+}
+
+func execute(tpl *texttemplate.Template, data any) (string, error) {
+	var b strings.Builder
+	if err := tpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
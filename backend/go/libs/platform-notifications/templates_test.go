@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	templates := NewTemplates().MustRegister("welcome", Template{
+		Subject: "Bem-vindo, {{.Name}}\n",
+		Text:    "Olá {{.Name}}, sua conta em {{.Tenant}} está pronta.",
+		HTML:    "<p>Olá {{.Name}}</p>",
+	})
+
+	r, err := templates.Render("welcome", map[string]string{"Name": "Ana <b>", "Tenant": "Acme"})
+	if err != nil {
+		t.Fatalf("Render retornou erro: %v", err)
+	}
+	if r.Subject != "Bem-vindo, Ana <b>" {
+		t.Errorf("Subject = %q", r.Subject)
+	}
+	if r.Text != "Olá Ana <b>, sua conta em Acme está pronta." {
+		t.Errorf("Text = %q", r.Text)
+	}
+	if r.HTML != "<p>Olá Ana &lt;b&gt;</p>" {
+		t.Errorf("HTML = %q, esperado o nome escapado", r.HTML)
+	}
+}
+
+func TestTemplates_RenderWithoutHTML(t *testing.T) {
+	templates := NewTemplates().MustRegister("code", Template{Text: "Código: {{.Code}}"})
+
+	r, err := templates.Render("code", struct{ Code string }{"123456"})
+	if err != nil {
+		t.Fatalf("Render retornou erro: %v", err)
+	}
+	if r.Text != "Código: 123456" || r.HTML != "" {
+		t.Errorf("Render = %+v", r)
+	}
+}
+
+func TestTemplates_MissingKey(t *testing.T) {
+	templates := NewTemplates().MustRegister("welcome", Template{Text: "Olá {{.Name}}"})
+
+	if _, err := templates.Render("welcome", map[string]string{}); err == nil {
+		t.Error("Render sem o campo Name deveria falhar")
+	}
+}
+
+func TestTemplates_UnknownTemplate(t *testing.T) {
+	if _, err := NewTemplates().Render("missing", nil); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("erro = %v, esperado ErrUnknownTemplate", err)
+	}
+}
+
+func TestTemplates_RegisterInvalid(t *testing.T) {
+	err := NewTemplates().Register("broken", Template{Text: "{{.Name"})
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("erro = %v, esperado erro de parse citando o template", err)
+	}
+}
@@ -0,0 +1,216 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// DefaultClassificationTimeout limita os classificadores pós-chamada quando
+// config.ClassificationTimeout não é definido
+const DefaultClassificationTimeout = 2 * time.Second
+
+// Classification é o resultado de um classificador pós-chamada: tags e
+// metadata mesclados à conversação antes do evento conversation.ended
+type Classification struct {
+	Tags     []string
+	Metadata map[string]any
+}
+
+// Classifier classifica uma conversação encerrada (tópico, disposição,
+// previsão de CSAT...). Classify recebe uma cópia da conversação com os dados
+// do fim já preenchidos e deve respeitar o cancelamento do contexto.
+type Classifier interface {
+	Name() string
+	Classify(ctx context.Context, conversation types.Conversation) (Classification, error)
+}
+
+// classifierFunc adapta uma função a Classifier
+type classifierFunc struct {
+	name string
+	fn   func(ctx context.Context, conversation types.Conversation) (Classification, error)
+}
+
+func (c classifierFunc) Name() string { return c.name }
+
+func (c classifierFunc) Classify(ctx context.Context, conversation types.Conversation) (Classification, error) {
+	return c.fn(ctx, conversation)
+}
+
+// NewClassifier cria um classificador a partir de uma função
+func NewClassifier(name string, fn func(ctx context.Context, conversation types.Conversation) (Classification, error)) Classifier {
+	return classifierFunc{name: name, fn: fn}
+}
+
+// NewKeywordClassifier marca a conversação com "topic:<tópico>" para cada
+// tópico cujas palavras-chave aparecem nas falas do cliente
+func NewKeywordClassifier(topics map[string][]string) Classifier {
+	return NewClassifier("topic", func(ctx context.Context, conversation types.Conversation) (Classification, error) {
+		var text strings.Builder
+		for _, interaction := range conversation.Interactions {
+			if interaction.Speaker == "customer" {
+				text.WriteString(strings.ToLower(interaction.Content))
+				text.WriteByte('\n')
+			}
+		}
+		content := text.String()
+
+		names := make([]string, 0, len(topics))
+		for topic := range topics {
+			names = append(names, topic)
+		}
+		slices.Sort(names)
+
+		var result Classification
+		for _, topic := range names {
+			for _, keyword := range topics[topic] {
+				if strings.Contains(content, strings.ToLower(keyword)) {
+					result.Tags = append(result.Tags, "topic:"+topic)
+					break
+				}
+			}
+		}
+		return result, nil
+	})
+}
+
+// RegisterClassifier registra um classificador para as conversações de todos
+// os tenants
+func (o *Observer) RegisterClassifier(c Classifier) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.classifiers = append(o.classifiers, c)
+}
+
+// RegisterTenantClassifier registra um classificador apenas para as
+// conversações de um tenant; roda depois dos classificadores globais
+func (o *Observer) RegisterTenantClassifier(tenantID string, c Classifier) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.tenantClassifiers == nil {
+		o.tenantClassifiers = make(map[string][]Classifier)
+	}
+	o.tenantClassifiers[tenantID] = append(o.tenantClassifiers[tenantID], c)
+}
+
+// classificationResult junta o resultado de todos os classificadores
+type classificationResult struct {
+	tags     []string
+	metadata map[string]any
+	failures map[string]string // nome do classificador -> erro
+}
+
+// classifyConversation roda os classificadores sobre uma cópia da conversação,
+// sem segurar o lock durante a classificação
+func (o *Observer) classifyConversation(ctx context.Context, conversationID string, end types.ConversationEnd) (classificationResult, error) {
+	o.mu.RLock()
+	conversation, exists := o.conversations[conversationID]
+	if !exists {
+		o.mu.RUnlock()
+		return classificationResult{}, fmt.Errorf("conversation %s not found", conversationID)
+	}
+
+	classifiers := append(slices.Clone(o.classifiers), o.tenantClassifiers[conversation.TenantID]...)
+	if len(classifiers) == 0 {
+		o.mu.RUnlock()
+		return classificationResult{}, nil
+	}
+
+	snapshot := *conversation
+	snapshot.Interactions = slices.Clone(conversation.Interactions)
+	snapshot.Decisions = slices.Clone(conversation.Decisions)
+	snapshot.ComplianceChecks = slices.Clone(conversation.ComplianceChecks)
+	snapshot.Metadata = maps.Clone(conversation.Metadata)
+	o.mu.RUnlock()
+
+	snapshot.EndTime = end.EndTime
+	snapshot.Duration = end.EndTime.Sub(snapshot.StartTime)
+	snapshot.Resolution = end.Resolution
+	snapshot.Rating = end.Rating
+	snapshot.Tags = slices.Clone(end.Tags)
+	for k, v := range end.Metadata {
+		snapshot.Metadata[k] = v
+	}
+
+	timeout := DefaultClassificationTimeout
+	if o.config != nil && o.config.ClassificationTimeout > 0 {
+		timeout = o.config.ClassificationTimeout
+	}
+
+	return runClassifiers(ctx, snapshot, classifiers, timeout), nil
+}
+
+// runClassifiers roda os classificadores em paralelo. Erros, panics e
+// estouros do timeout ficam isolados em failures; os resultados são mesclados
+// na ordem de registro, o último vencendo em chaves repetidas de metadata.
+func runClassifiers(ctx context.Context, conversation types.Conversation, classifiers []Classifier, timeout time.Duration) classificationResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		index          int
+		classification Classification
+		err            error
+	}
+
+	// Buffer para todos: um classificador atrasado termina sem bloquear
+	outcomes := make(chan outcome, len(classifiers))
+	for i, c := range classifiers {
+		go func(i int, c Classifier) {
+			defer func() {
+				if r := recover(); r != nil {
+					outcomes <- outcome{index: i, err: fmt.Errorf("panic: %v", r)}
+				}
+			}()
+
+			classification, err := c.Classify(ctx, conversation)
+			outcomes <- outcome{index: i, classification: classification, err: err}
+		}(i, c)
+	}
+
+	done := make([]*outcome, len(classifiers))
+collect:
+	for pending := len(classifiers); pending > 0; pending-- {
+		select {
+		case o := <-outcomes:
+			done[o.index] = &o
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	result := classificationResult{metadata: make(map[string]any), failures: make(map[string]string)}
+	for i, o := range done {
+		name := classifiers[i].Name()
+		switch {
+		case o == nil:
+			result.failures[name] = ctx.Err().Error()
+		case o.err != nil:
+			result.failures[name] = o.err.Error()
+		default:
+			result.tags = mergeTags(result.tags, o.classification.Tags)
+			for k, v := range o.classification.Metadata {
+				result.metadata[k] = v
+			}
+		}
+	}
+
+	return result
+}
+
+// mergeTags acrescenta as tags que ainda não existem, mantendo a ordem
+func mergeTags(tags []string, more []string) []string {
+	for _, tag := range more {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,161 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// tagClassifier adiciona uma tag fixa e a resolução vista no fim
+func tagClassifier(name, tag string) Classifier {
+	return NewClassifier(name, func(ctx context.Context, conversation types.Conversation) (Classification, error) {
+		return Classification{
+			Tags:     []string{tag},
+			Metadata: map[string]any{name: conversation.Resolution},
+		}, nil
+	})
+}
+
+// endedEvent encerra a conversação e devolve o evento conversation.ended
+func endedEvent(t *testing.T, o *Observer, tenantID string, end types.ConversationEnd) (types.ConversationExport, *types.InteractionEvent) {
+	t.Helper()
+
+	ctx := context.Background()
+	sink := &captureSink{}
+	o.SetExportSink(sink)
+
+	events, unsubscribe := o.Subscribe(tenantID, WithBuffer(16))
+	defer unsubscribe()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: tenantID})
+	o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer", Content: "Quero cancelar meu plano"})
+	if err := o.EndConversation(ctx, id, end); err != nil {
+		t.Fatalf("EndConversation: %v", err)
+	}
+
+	for {
+		event := nextEvent(t, events)
+		if event.Type == "conversation.ended" {
+			return sink.exports[0], event.Interaction
+		}
+	}
+}
+
+func TestEndConversation_ClassifierAddsTag(t *testing.T) {
+	o := newTestObserver(t)
+	o.RegisterClassifier(tagClassifier("disposition", "disposition:retention"))
+
+	export, event := endedEvent(t, o, "tenant-a", types.ConversationEnd{Resolution: "solved", Tags: []string{"vip"}})
+
+	want := []string{"vip", "disposition:retention"}
+	if !slices.Equal(export.Tags, want) {
+		t.Errorf("tags = %v, esperado %v", export.Tags, want)
+	}
+	if got := event.Metadata["tags"].([]string); !slices.Equal(got, want) {
+		t.Errorf("tags do evento = %v, esperado %v", got, want)
+	}
+
+	// O classificador vê a conversação já com os dados do fim
+	classification := event.Metadata["classification"].(map[string]any)
+	if classification["disposition"] != "solved" {
+		t.Errorf("classification = %v", classification)
+	}
+	if export.Metadata["disposition"] != "solved" {
+		t.Errorf("metadata = %v", export.Metadata)
+	}
+}
+
+func TestEndConversation_KeywordClassifier(t *testing.T) {
+	o := newTestObserver(t)
+	o.RegisterClassifier(NewKeywordClassifier(map[string][]string{
+		"cancelamento": {"cancelar", "encerrar conta"},
+		"cobranca":     {"fatura", "boleto"},
+	}))
+
+	export, _ := endedEvent(t, o, "tenant-a", types.ConversationEnd{})
+
+	if !slices.Equal(export.Tags, []string{"topic:cancelamento"}) {
+		t.Errorf("tags = %v", export.Tags)
+	}
+}
+
+func TestEndConversation_ClassifierFailuresAreIsolated(t *testing.T) {
+	o := newObserver(&config.Config{ClassificationTimeout: 50 * time.Millisecond})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+
+	o.RegisterClassifier(NewClassifier("failing", func(ctx context.Context, conversation types.Conversation) (Classification, error) {
+		return Classification{Tags: []string{"nunca"}}, errors.New("modelo indisponível")
+	}))
+	o.RegisterClassifier(NewClassifier("panicking", func(ctx context.Context, conversation types.Conversation) (Classification, error) {
+		panic("bug no classificador")
+	}))
+	o.RegisterClassifier(NewClassifier("slow", func(ctx context.Context, conversation types.Conversation) (Classification, error) {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond) // ignora o cancelamento por um tempo
+		return Classification{Tags: []string{"atrasada"}}, nil
+	}))
+	o.RegisterClassifier(tagClassifier("disposition", "disposition:solved"))
+
+	start := time.Now()
+	export, event := endedEvent(t, o, "tenant-a", types.ConversationEnd{})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("EndConversation levou %v, esperado respeitar o timeout", elapsed)
+	}
+
+	if !slices.Equal(export.Tags, []string{"disposition:solved"}) {
+		t.Errorf("tags = %v, esperado apenas a do classificador saudável", export.Tags)
+	}
+
+	failures := event.Metadata["classification_errors"].(map[string]string)
+	if len(failures) != 3 {
+		t.Fatalf("classification_errors = %v, esperado 3 falhas", failures)
+	}
+	if failures["failing"] != "modelo indisponível" {
+		t.Errorf("failing = %q", failures["failing"])
+	}
+	if failures["panicking"] != "panic: bug no classificador" {
+		t.Errorf("panicking = %q", failures["panicking"])
+	}
+	if failures["slow"] != context.DeadlineExceeded.Error() {
+		t.Errorf("slow = %q", failures["slow"])
+	}
+}
+
+func TestEndConversation_TenantClassifier(t *testing.T) {
+	o := newTestObserver(t)
+	o.RegisterTenantClassifier("tenant-a", tagClassifier("csat", "csat:alto"))
+
+	exportA, _ := endedEvent(t, o, "tenant-a", types.ConversationEnd{})
+	if !slices.Equal(exportA.Tags, []string{"csat:alto"}) {
+		t.Errorf("tenant-a tags = %v", exportA.Tags)
+	}
+
+	exportB, eventB := endedEvent(t, o, "tenant-b", types.ConversationEnd{})
+	if len(exportB.Tags) != 0 {
+		t.Errorf("tenant-b tags = %v, esperado nenhuma", exportB.Tags)
+	}
+	if _, ok := eventB.Metadata["classification"]; ok {
+		t.Error("tenant-b não deveria ter classification")
+	}
+}
+
+func TestEndConversation_UnknownConversationSkipsClassifiers(t *testing.T) {
+	o := newTestObserver(t)
+	called := false
+	o.RegisterClassifier(NewClassifier("spy", func(ctx context.Context, conversation types.Conversation) (Classification, error) {
+		called = true
+		return Classification{}, nil
+	}))
+
+	if err := o.EndConversation(context.Background(), "inexistente", types.ConversationEnd{}); err == nil {
+		t.Error("esperado erro para conversação inexistente")
+	}
+	if called {
+		t.Error("classificador não deveria rodar para conversação inexistente")
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -41,6 +42,29 @@ type Config struct {
 	ConversationTracking bool
 	ComplianceChecking   bool
 	SentimentAnalysis    bool
+	// ExportOnEnd anexa o export completo da conversação (timeline e
+	// métricas) ao evento conversation.ended, em metadata["export"]
+	ExportOnEnd bool
+	// ClassificationTimeout limita os classificadores pós-chamada de cada
+	// conversação encerrada
+	ClassificationTimeout time.Duration
+	// InteractionDedupWindow ignora uma interação igual (mesmo falante e
+	// conteúdo) a outra aceita há menos que a janela; zero desativa, já que
+	// repetições legítimas existem
+	InteractionDedupWindow time.Duration
+	// EventSampling define a fração (0 a 1) dos eventos de cada tipo enviada
+	// aos sinks, como {"interaction.*": 0.1, "decision.made": 1}; vale o
+	// tipo exato, depois o prefixo "a.*" mais longo, depois "*". Tipos sem
+	// entrada são todos mantidos, assim como início, fim e erros das
+	// conversações. Assinantes ao vivo recebem todos os eventos.
+	EventSampling map[string]float64
+	// EventWALPath é o arquivo onde os eventos esperam um sink fora do ar
+	// (veja observability.NewWALSink); vazio desativa
+	EventWALPath string
+	// EventWALMaxBytes limita o WAL; além dele os eventos são perdidos
+	EventWALMaxBytes int64
+	// EventWALReplayInterval é o intervalo entre tentativas de reenvio do WAL
+	EventWALReplayInterval time.Duration
 }
 
 // LoadFromEnv carrega configuração das variáveis de ambiente
@@ -79,6 +103,14 @@ func LoadFromEnv() *Config {
 		ConversationTracking: getEnvBool("CONVERSATION_TRACKING", true),
 		ComplianceChecking:   getEnvBool("COMPLIANCE_CHECKING", true),
 		SentimentAnalysis:    getEnvBool("SENTIMENT_ANALYSIS", true),
+		ExportOnEnd:          getEnvBool("CONVERSATION_EXPORT_ON_END", false),
+
+		ClassificationTimeout:  getEnvDuration("CONVERSATION_CLASSIFICATION_TIMEOUT", 2*time.Second),
+		InteractionDedupWindow: getEnvDuration("INTERACTION_DEDUP_WINDOW", 0),
+		EventSampling:          getEnvRates("EVENT_SAMPLING"),
+		EventWALPath:           getEnv("EVENT_WAL_PATH", ""),
+		EventWALMaxBytes:       int64(getEnvInt("EVENT_WAL_MAX_BYTES", 100<<20)),
+		EventWALReplayInterval: getEnvDuration("EVENT_WAL_REPLAY_INTERVAL", 10*time.Second),
 	}
 }
 
@@ -117,6 +149,24 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+// getEnvRates lê frações no formato "tipo=fração,tipo=fração", ignorando
+// entradas inválidas ou fora de [0, 1]
+func getEnvRates(key string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			continue
+		}
+		rates[strings.TrimSpace(name)] = rate
+	}
+	return rates
+}
+
 func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if d, err := time.ParseDuration(value); err == nil {
@@ -0,0 +1,72 @@
+package observability
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// interactionKey identifica uma fala: conversação, falante e conteúdo
+type interactionKey [sha256.Size]byte
+
+func newInteractionKey(conversationID string, interaction types.Interaction) interactionKey {
+	h := sha256.New()
+	for _, part := range []string{conversationID, interaction.Speaker, interaction.Content} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	var key interactionKey
+	h.Sum(key[:0])
+	return key
+}
+
+// SetLogger define o logger do observador; o padrão descarta tudo
+func (o *Observer) SetLogger(logger *zap.Logger) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.logger = logger
+}
+
+// isDuplicate informa se a interação repete, dentro de
+// config.InteractionDedupWindow, uma fala já aceita na conversação, como nas
+// retentativas do pipeline de voz. Em vez de arredondar o timestamp, compara a
+// distância até a última ocorrência aceita, então repetições na virada de um
+// intervalo também são pegas. Deve ser chamado com o.mu travado.
+func (o *Observer) isDuplicate(conversationID string, interaction types.Interaction) bool {
+	window := o.config.InteractionDedupWindow
+	if window <= 0 {
+		return false
+	}
+
+	seen := o.recentInteractions[conversationID]
+	if seen == nil {
+		seen = make(map[interactionKey]time.Time)
+		o.recentInteractions[conversationID] = seen
+	}
+
+	key := newInteractionKey(conversationID, interaction)
+	if last, ok := seen[key]; ok {
+		if distance := interaction.Timestamp.Sub(last); distance < window && distance > -window {
+			o.logger.Debug("duplicate interaction ignored",
+				zap.String("conversation_id", conversationID),
+				zap.String("speaker", interaction.Speaker),
+				zap.Duration("since_last", distance),
+			)
+			return true
+		}
+	}
+
+	// Só falas dentro da janela podem ser repetições
+	for k, ts := range seen {
+		if interaction.Timestamp.Sub(ts) >= window {
+			delete(seen, k)
+		}
+	}
+	seen[key] = interaction.Timestamp
+	return false
+}
@@ -0,0 +1,109 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+func newDedupObserver(t *testing.T, window time.Duration) *Observer {
+	t.Helper()
+
+	o := newObserver(&config.Config{InteractionDedupWindow: window})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+	return o
+}
+
+func TestTrackInteraction_DedupWithinWindow(t *testing.T) {
+	o := newDedupObserver(t, 2*time.Second)
+	ctx := context.Background()
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	events, unsubscribe := o.Subscribe("tenant-a", WithBuffer(16))
+	defer unsubscribe()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", StartTime: start})
+	utterance := func(content string, offset time.Duration) types.Interaction {
+		return types.Interaction{Speaker: "customer", Content: content, Timestamp: start.Add(offset)}
+	}
+
+	// Retentativa do pipeline de voz 300ms depois: ignorada
+	for _, interaction := range []types.Interaction{
+		utterance("quero falar com um atendente", time.Second),
+		utterance("quero falar com um atendente", 1300*time.Millisecond),
+	} {
+		if err := o.TrackInteraction(ctx, id, interaction); err != nil {
+			t.Fatalf("TrackInteraction: %v", err)
+		}
+	}
+
+	// Mesma frase do agente não é repetição da fala do cliente
+	o.TrackInteraction(ctx, id, types.Interaction{Speaker: "agent", Content: "quero falar com um atendente", Timestamp: start.Add(1400 * time.Millisecond)})
+
+	// O cliente repete a frase mais tarde: conta de novo
+	o.TrackInteraction(ctx, id, utterance("quero falar com um atendente", 10*time.Second))
+
+	conversation, _ := o.GetConversation(id)
+	if conversation.InteractionCount != 3 || len(conversation.Interactions) != 3 {
+		t.Fatalf("InteractionCount = %d, esperado 3", conversation.InteractionCount)
+	}
+
+	var emitted int
+	for {
+		select {
+		case event := <-events:
+			if event.Interaction != nil && event.Type != "conversation.started" {
+				emitted++
+			}
+			continue
+		default:
+		}
+		break
+	}
+	if emitted != 3 {
+		t.Errorf("eventos de interação = %d, esperado 3", emitted)
+	}
+}
+
+func TestTrackInteraction_DedupDisabledByDefault(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer", Content: "sim", Timestamp: now})
+	}
+
+	conversation, _ := o.GetConversation(id)
+	if conversation.InteractionCount != 2 {
+		t.Errorf("InteractionCount = %d, esperado 2 sem dedup", conversation.InteractionCount)
+	}
+}
+
+func TestTrackInteraction_DedupIsPerConversation(t *testing.T) {
+	o := newDedupObserver(t, time.Minute)
+	ctx := context.Background()
+	now := time.Now()
+
+	first := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	second := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	for _, id := range []string{first, second} {
+		o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer", Content: "sim", Timestamp: now})
+	}
+
+	for _, id := range []string{first, second} {
+		conversation, _ := o.GetConversation(id)
+		if conversation.InteractionCount != 1 {
+			t.Errorf("%s: InteractionCount = %d, esperado 1", id, conversation.InteractionCount)
+		}
+	}
+
+	o.EndConversation(ctx, first, types.ConversationEnd{})
+	if _, ok := o.recentInteractions[first]; ok {
+		t.Error("hashes da conversação encerrada deveriam ser descartados")
+	}
+}
@@ -0,0 +1,66 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// ExportSink recebe o export de cada conversação encerrada. Como
+// EndConversation remove a conversação da memória, o sink é o lugar para
+// guardar timelines de pesquisa e QA.
+type ExportSink interface {
+	WriteExport(ctx context.Context, export types.ConversationExport) error
+}
+
+// SetExportSink define o sink chamado por EndConversation; nil desativa
+func (o *Observer) SetExportSink(sink ExportSink) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.exportSink = sink
+}
+
+// ExportConversation exporta a timeline completa de uma conversação ativa
+func ExportConversation(conversationID string) (types.ConversationExport, error) {
+	return GetObserver().ExportConversation(conversationID)
+}
+
+// ExportConversation exporta a timeline completa de uma conversação ativa:
+// interações, decisões e verificações de compliance em ordem cronológica,
+// com métricas derivadas. Conversações encerradas não estão mais em memória;
+// use SetExportSink ou config.ExportOnEnd para capturá-las no fim.
+func (o *Observer) ExportConversation(conversationID string) (types.ConversationExport, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	conversation, exists := o.conversations[conversationID]
+	if !exists {
+		return types.ConversationExport{}, fmt.Errorf("conversation %s not found", conversationID)
+	}
+
+	return types.NewConversationExport(conversation), nil
+}
+
+// WriterSink grava cada export como JSON Lines em um io.Writer (um arquivo,
+// por exemplo), serializando as escritas concorrentes
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink cria um sink JSONL sobre w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// WriteExport implementa ExportSink
+func (s *WriterSink) WriteExport(ctx context.Context, export types.ConversationExport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return export.WriteJSONL(s.w)
+}
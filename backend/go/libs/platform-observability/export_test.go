@@ -0,0 +1,158 @@
+package observability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// captureSink guarda os exports recebidos
+type captureSink struct {
+	exports []types.ConversationExport
+	err     error
+}
+
+func (s *captureSink) WriteExport(ctx context.Context, export types.ConversationExport) error {
+	s.exports = append(s.exports, export)
+	return s.err
+}
+
+func newTestObserver(t *testing.T) *Observer {
+	t.Helper()
+
+	o := newObserver(&config.Config{})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+	return o
+}
+
+func TestExportConversation_ActiveConversation(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", AgentID: "agent-1", StartTime: start})
+	o.TrackDecision(ctx, id, types.Decision{DecisionType: "offer", Timestamp: start.Add(20 * time.Second)})
+	o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer", Content: "Oi", Timestamp: start.Add(5 * time.Second)})
+	o.TrackComplianceCheck(ctx, id, types.ComplianceCheck{PolicyID: "greeting", Passed: true, Timestamp: start.Add(10 * time.Second)})
+
+	export, err := o.ExportConversation(id)
+	if err != nil {
+		t.Fatalf("ExportConversation: %v", err)
+	}
+
+	kinds := []types.TimelineEntryKind{
+		types.TimelineConversationStarted,
+		types.TimelineInteraction,
+		types.TimelineComplianceCheck,
+		types.TimelineDecision,
+	}
+	if len(export.Timeline) != len(kinds) {
+		t.Fatalf("timeline = %+v, esperado %v", export.Timeline, kinds)
+	}
+	for i, kind := range kinds {
+		if export.Timeline[i].Kind != kind {
+			t.Errorf("timeline[%d] = %s, esperado %s", i, export.Timeline[i].Kind, kind)
+		}
+	}
+	if check := export.Timeline[2].ComplianceCheck; check.TenantID != "tenant-a" || check.CheckID == "" {
+		t.Errorf("verificação não foi associada à conversação: %+v", check)
+	}
+}
+
+func TestEndConversation_CapturesExportInSink(t *testing.T) {
+	o := newTestObserver(t)
+	sink := &captureSink{}
+	o.SetExportSink(sink)
+	ctx := context.Background()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	o.TrackInteraction(ctx, id, types.Interaction{Speaker: "agent", Content: "Olá"})
+	if err := o.EndConversation(ctx, id, types.ConversationEnd{Resolution: "solved"}); err != nil {
+		t.Fatalf("EndConversation: %v", err)
+	}
+
+	if len(sink.exports) != 1 {
+		t.Fatalf("sink recebeu %d exports, esperado 1", len(sink.exports))
+	}
+	export := sink.exports[0]
+	if export.Resolution != "solved" || export.Timeline[len(export.Timeline)-1].Kind != types.TimelineConversationEnded {
+		t.Errorf("export incompleto: %+v", export)
+	}
+
+	// Após o fim, a conversação só existe no sink
+	if _, err := o.ExportConversation(id); err == nil {
+		t.Error("conversação encerrada não deveria estar em memória")
+	}
+
+	sink.err = errors.New("disco cheio")
+	id = o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	if err := o.EndConversation(ctx, id, types.ConversationEnd{}); err == nil || !strings.Contains(err.Error(), "export failed") {
+		t.Errorf("esperado erro do sink, obtido %v", err)
+	}
+}
+
+func TestWriterSink_WritesJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+	export := types.NewConversationExport(&types.Conversation{ConversationID: "conv-1", StartTime: time.Now()})
+
+	if err := sink.WriteExport(context.Background(), export); err != nil {
+		t.Fatalf("WriteExport: %v", err)
+	}
+	// Cabeçalho + conversation.started
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("%d linhas gravadas, esperado 2", lines)
+	}
+}
+
+func TestSentimentTrajectory_ActiveAndEndEvent(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	events, unsubscribe := o.Subscribe("tenant-a", WithBuffer(16))
+	defer unsubscribe()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", StartTime: start})
+	for i, sentiment := range []string{"positive", "neutral", "negative", "negative"} {
+		o.TrackInteraction(ctx, id, types.Interaction{
+			Speaker:   "customer",
+			Sentiment: sentiment,
+			Timestamp: start.Add(time.Duration(i+1) * time.Second),
+		})
+	}
+
+	trajectory, err := o.SentimentTrajectory(id)
+	if err != nil {
+		t.Fatalf("SentimentTrajectory: %v", err)
+	}
+	if trajectory.Trend != types.SentimentDeclining || len(trajectory.Points) != 4 {
+		t.Errorf("trajetória = %+v, esperado declining com 4 pontos", trajectory)
+	}
+	if trajectory.LargestDrop == nil || trajectory.LargestDrop.To.Timestamp != start.Add(2*time.Second) {
+		t.Errorf("LargestDrop = %+v, esperado a primeira queda", trajectory.LargestDrop)
+	}
+
+	o.EndConversation(ctx, id, types.ConversationEnd{})
+	if _, err := o.SentimentTrajectory(id); err == nil {
+		t.Error("esperado erro após o fim da conversação")
+	}
+
+	for {
+		event := nextEvent(t, events)
+		if event.Type != "conversation.ended" {
+			continue
+		}
+		ended, ok := event.Interaction.Metadata["sentiment_trajectory"].(types.SentimentTrajectory)
+		if !ok || ended.Trend != types.SentimentDeclining {
+			t.Errorf("sentiment_trajectory = %+v, esperado declining", event.Interaction.Metadata["sentiment_trajectory"])
+		}
+		return
+	}
+}
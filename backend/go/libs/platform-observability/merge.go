@@ -0,0 +1,186 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// MergeConversations junta uma conversação a outra
+func MergeConversations(ctx context.Context, primaryID, secondaryID string) error {
+	return GetObserver().MergeConversations(ctx, primaryID, secondaryID)
+}
+
+// MergeConversations junta a conversação secundária à primária, para um
+// cliente que liga e continua pelo chat ter uma só conversação. Interações,
+// decisões e verificações de compliance das duas ficam na primária em ordem
+// cronológica, e a secundária sai da memória. Daí em diante o ID secundário
+// aponta para a primária: o que for rastreado nele vai para ela, e encerrá-lo
+// não encerra a primária. Uma interação com o ID de outra da primária é
+// descartada se for a mesma fala e ganha um novo ID se não for, guardando o
+// original em Metadata["merged_from_id"]. Emite conversation.merged, para as
+// análises reapontarem o ID secundário.
+func (o *Observer) MergeConversations(ctx context.Context, primaryID, secondaryID string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if primaryID == secondaryID {
+		return fmt.Errorf("cannot merge conversation %s into itself", primaryID)
+	}
+	primary, exists := o.conversations[primaryID]
+	if !exists {
+		return fmt.Errorf("conversation %s not found", primaryID)
+	}
+	secondary, exists := o.conversations[secondaryID]
+	if !exists {
+		return fmt.Errorf("conversation %s not found", secondaryID)
+	}
+	if primary.TenantID != secondary.TenantID {
+		return fmt.Errorf("conversations %s and %s belong to different tenants", primaryID, secondaryID)
+	}
+
+	interactions, duplicates := mergeInteractions(primaryID, primary.Interactions, secondary.Interactions)
+	primary.Interactions = interactions
+	primary.InteractionCount += secondary.InteractionCount - duplicates
+	primary.Decisions = mergeDecisions(primaryID, primary.Decisions, secondary.Decisions)
+	for _, check := range secondary.ComplianceChecks {
+		check.ConversationID = primaryID
+		primary.ComplianceChecks = append(primary.ComplianceChecks, check)
+	}
+	sort.SliceStable(primary.ComplianceChecks, func(i, j int) bool {
+		return primary.ComplianceChecks[i].Timestamp.Before(primary.ComplianceChecks[j].Timestamp)
+	})
+
+	if secondary.StartTime.Before(primary.StartTime) {
+		primary.StartTime = secondary.StartTime
+	}
+	for k, v := range secondary.Metadata {
+		if _, ok := primary.Metadata[k]; !ok {
+			primary.Metadata[k] = v
+		}
+	}
+	merged, _ := primary.Metadata["merged_conversations"].([]string)
+	primary.Metadata["merged_conversations"] = append(merged, secondaryID)
+	channels, _ := primary.Metadata["channels"].([]string)
+	if len(channels) == 0 {
+		channels = []string{primary.Channel}
+	}
+	if !slices.Contains(channels, secondary.Channel) {
+		channels = append(channels, secondary.Channel)
+	}
+	primary.Metadata["channels"] = channels
+
+	// Aliases da secundária passam a apontar para a primária
+	for alias, target := range o.aliases {
+		if target == secondaryID {
+			o.aliases[alias] = primaryID
+		}
+	}
+	o.aliases[secondaryID] = primaryID
+	delete(o.conversations, secondaryID)
+	delete(o.recentInteractions, secondaryID)
+
+	o.emit(types.InteractionEvent{
+		ConversationID: primaryID,
+		TenantID:       primary.TenantID,
+		AgentID:        primary.AgentID,
+		CustomerID:     primary.CustomerID,
+		EventType:      "conversation.merged",
+		Timestamp:      time.Now(),
+		Channel:        primary.Channel,
+		Metadata: map[string]any{
+			"merged_conversation_id": secondaryID,
+			"merged_channel":         secondary.Channel,
+			"interaction_count":      primary.InteractionCount,
+			"decision_count":         len(primary.Decisions),
+			"duplicate_interactions": duplicates,
+			"channels":               slices.Clone(channels),
+		},
+	})
+
+	return nil
+}
+
+// resolve segue os aliases deixados por MergeConversations; deve ser chamado
+// com o.mu travado
+func (o *Observer) resolve(conversationID string) string {
+	if target, ok := o.aliases[conversationID]; ok {
+		return target
+	}
+	return conversationID
+}
+
+// mergeInteractions junta as interações da secundária às da primária, em
+// ordem cronológica, e retorna quantas eram repetições descartadas
+func mergeInteractions(primaryID string, primary, secondary []types.Interaction) ([]types.Interaction, int) {
+	byID := make(map[string]types.Interaction, len(primary))
+	for _, interaction := range primary {
+		byID[interaction.InteractionID] = interaction
+	}
+
+	merged := slices.Clone(primary)
+	var duplicates int
+	for _, interaction := range secondary {
+		if existing, ok := byID[interaction.InteractionID]; ok {
+			if sameUtterance(existing, interaction) {
+				duplicates++
+				continue
+			}
+			interaction.Metadata = cloneWith(interaction.Metadata, "merged_from_id", interaction.InteractionID)
+			interaction.InteractionID = uuid.New().String()
+		}
+		interaction.ConversationID = primaryID
+		byID[interaction.InteractionID] = interaction
+		merged = append(merged, interaction)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	return merged, duplicates
+}
+
+// mergeDecisions junta as decisões da secundária às da primária, em ordem
+// cronológica; IDs repetidos ganham um novo, como nas interações
+func mergeDecisions(primaryID string, primary, secondary []types.Decision) []types.Decision {
+	ids := make(map[string]bool, len(primary))
+	for _, decision := range primary {
+		ids[decision.DecisionID] = true
+	}
+
+	merged := slices.Clone(primary)
+	for _, decision := range secondary {
+		if ids[decision.DecisionID] {
+			decision.Metadata = cloneWith(decision.Metadata, "merged_from_id", decision.DecisionID)
+			decision.DecisionID = uuid.New().String()
+		}
+		decision.ConversationID = primaryID
+		ids[decision.DecisionID] = true
+		merged = append(merged, decision)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	return merged
+}
+
+// sameUtterance informa se duas interações com o mesmo ID são a mesma fala
+func sameUtterance(a, b types.Interaction) bool {
+	return a.Speaker == b.Speaker && a.Content == b.Content && a.Timestamp.Equal(b.Timestamp)
+}
+
+func cloneWith(m map[string]string, key, value string) map[string]string {
+	clone := make(map[string]string, len(m)+1)
+	for k, v := range m {
+		clone[k] = v
+	}
+	clone[key] = value
+	return clone
+}
@@ -0,0 +1,162 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+func TestMergeConversations_ChronologicalAndSummed(t *testing.T) {
+	o := newObserver(&config.Config{})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	voice := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", Channel: "voice", StartTime: base.Add(time.Minute)})
+	chat := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", Channel: "chat", StartTime: base})
+
+	for _, i := range []types.Interaction{
+		{InteractionID: "v1", Speaker: "customer", Content: "quero cancelar", Timestamp: base.Add(1 * time.Minute)},
+		{InteractionID: "v2", Speaker: "agent", Content: "posso ajudar", Timestamp: base.Add(3 * time.Minute)},
+	} {
+		o.TrackInteraction(ctx, voice, i)
+	}
+	for _, i := range []types.Interaction{
+		{InteractionID: "c1", Speaker: "customer", Content: "oi", Timestamp: base},
+		{InteractionID: "c2", Speaker: "customer", Content: "continuo aqui", Timestamp: base.Add(2 * time.Minute)},
+		{InteractionID: "c3", Speaker: "agent", Content: "certo", Timestamp: base.Add(4 * time.Minute)},
+	} {
+		o.TrackInteraction(ctx, chat, i)
+	}
+	o.TrackDecision(ctx, voice, types.Decision{DecisionType: "intent", Option: "cancel", Timestamp: base.Add(90 * time.Second)})
+	o.TrackDecision(ctx, chat, types.Decision{DecisionType: "intent", Option: "greeting", Timestamp: base.Add(30 * time.Second)})
+
+	if err := o.MergeConversations(ctx, voice, chat); err != nil {
+		t.Fatalf("MergeConversations: %v", err)
+	}
+
+	conversation, err := o.GetConversation(voice)
+	if err != nil {
+		t.Fatalf("GetConversation: %v", err)
+	}
+	if conversation.InteractionCount != 5 || len(conversation.Interactions) != 5 {
+		t.Fatalf("InteractionCount = %d, %d interações, esperado 5", conversation.InteractionCount, len(conversation.Interactions))
+	}
+	want := []string{"c1", "v1", "c2", "v2", "c3"}
+	for i, interaction := range conversation.Interactions {
+		if interaction.InteractionID != want[i] {
+			t.Errorf("interação %d = %s, esperado %s", i, interaction.InteractionID, want[i])
+		}
+		if interaction.ConversationID != voice {
+			t.Errorf("interação %s na conversação %s, esperado %s", interaction.InteractionID, interaction.ConversationID, voice)
+		}
+	}
+	if len(conversation.Decisions) != 2 || conversation.Decisions[0].Option != "greeting" {
+		t.Errorf("decisões = %+v, esperado greeting antes de cancel", conversation.Decisions)
+	}
+	if !conversation.StartTime.Equal(base) {
+		t.Errorf("StartTime = %v, esperado o início do chat %v", conversation.StartTime, base)
+	}
+	if channels, _ := conversation.Metadata["channels"].([]string); len(channels) != 2 {
+		t.Errorf("channels = %v, esperado voice e chat", conversation.Metadata["channels"])
+	}
+
+	if _, err := o.GetConversation(chat); err == nil {
+		t.Error("conversação secundária ainda em memória")
+	}
+}
+
+func TestMergeConversations_InteractionIDConflicts(t *testing.T) {
+	o := newObserver(&config.Config{})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	primary := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", Channel: "voice"})
+	secondary := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", Channel: "chat"})
+
+	same := types.Interaction{InteractionID: "i1", Speaker: "customer", Content: "oi", Timestamp: base}
+	o.TrackInteraction(ctx, primary, same)
+	o.TrackInteraction(ctx, secondary, same)
+	o.TrackInteraction(ctx, secondary, types.Interaction{InteractionID: "i1", Speaker: "customer", Content: "outra fala", Timestamp: base.Add(time.Second)})
+
+	if err := o.MergeConversations(ctx, primary, secondary); err != nil {
+		t.Fatalf("MergeConversations: %v", err)
+	}
+
+	conversation, _ := o.GetConversation(primary)
+	if conversation.InteractionCount != 2 || len(conversation.Interactions) != 2 {
+		t.Fatalf("InteractionCount = %d, %d interações, esperado 2 (a repetida descartada)", conversation.InteractionCount, len(conversation.Interactions))
+	}
+	renamed := conversation.Interactions[1]
+	if renamed.InteractionID == "i1" || renamed.Metadata["merged_from_id"] != "i1" {
+		t.Errorf("interação em conflito = %s, metadata %v; esperado novo ID e merged_from_id=i1", renamed.InteractionID, renamed.Metadata)
+	}
+}
+
+func TestMergeConversations_RedirectsSecondaryID(t *testing.T) {
+	o := newObserver(&config.Config{})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+	ctx := context.Background()
+
+	events, unsubscribe := o.Subscribe("tenant-a", WithBuffer(16))
+	defer unsubscribe()
+
+	primary := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", Channel: "voice"})
+	secondary := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", Channel: "chat"})
+	if err := o.MergeConversations(ctx, primary, secondary); err != nil {
+		t.Fatalf("MergeConversations: %v", err)
+	}
+
+	var merged *Event
+	for merged == nil {
+		select {
+		case event := <-events:
+			if event.Type == "conversation.merged" {
+				merged = &event
+			}
+		case <-time.After(time.Second):
+			t.Fatal("assinante não recebeu conversation.merged")
+		}
+	}
+	if merged.ConversationID != primary || merged.Interaction.Metadata["merged_conversation_id"] != secondary {
+		t.Errorf("evento = %+v, esperado a secundária mesclada na primária", merged.Interaction)
+	}
+
+	// O ID secundário continua valendo, apontando para a primária
+	if err := o.TrackInteraction(ctx, secondary, types.Interaction{Speaker: "customer", Content: "voltei"}); err != nil {
+		t.Fatalf("TrackInteraction pelo ID secundário: %v", err)
+	}
+	if conversation, _ := o.GetConversation(primary); conversation.InteractionCount != 1 {
+		t.Errorf("InteractionCount = %d, esperado 1 na primária", conversation.InteractionCount)
+	}
+	if err := o.EndConversation(ctx, secondary, types.ConversationEnd{}); err != nil {
+		t.Fatalf("EndConversation pelo ID secundário: %v", err)
+	}
+	if _, err := o.GetConversation(primary); err != nil {
+		t.Error("encerrar o ID secundário encerrou a primária")
+	}
+}
+
+func TestMergeConversations_Rejects(t *testing.T) {
+	o := newObserver(&config.Config{})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+	ctx := context.Background()
+
+	a := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	b := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-b"})
+
+	tests := map[string][2]string{
+		"mesma conversação":  {a, a},
+		"tenants diferentes": {a, b},
+		"inexistente":        {a, "nope"},
+	}
+	for name, ids := range tests {
+		if err := o.MergeConversations(ctx, ids[0], ids[1]); err == nil {
+			t.Errorf("%s: esperado erro", name)
+		}
+	}
+}
@@ -3,22 +3,39 @@ package observability
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
+
 	"github.com/serphona/backend/go/libs/platform-observability/config"
 	"github.com/serphona/backend/go/libs/platform-observability/types"
 )
 
 // Observer é a interface principal para observabilidade
 type Observer struct {
-	config        *config.Config
-	conversations map[string]*types.Conversation
-	mu            sync.RWMutex
-	eventChan     chan interface{}
-	shutdownChan  chan struct{}
-	wg            sync.WaitGroup
+	config             *config.Config
+	conversations      map[string]*types.Conversation
+	mu                 sync.RWMutex
+	eventChan          chan interface{}
+	shutdownChan       chan struct{}
+	wg                 sync.WaitGroup
+	exportSink         ExportSink
+	classifiers        []Classifier
+	tenantClassifiers  map[string][]Classifier
+	sampler            *sampler
+	eventSink          EventSink
+	sinkLogger         *zap.Logger
+	sinkMu             sync.RWMutex
+	recentInteractions map[string]map[interactionKey]time.Time
+	aliases            map[string]string
+	logger             *zap.Logger
+	subscribers        map[*subscriber]struct{}
+	subMu              sync.RWMutex
+	subsClosed         bool
 }
 
 var (
@@ -30,21 +47,34 @@ var (
 func Init(cfg *config.Config) (*Observer, error) {
 	var err error
 	once.Do(func() {
-		globalObserver = &Observer{
-			config:        cfg,
-			conversations: make(map[string]*types.Conversation),
-			eventChan:     make(chan interface{}, 1000),
-			shutdownChan:  make(chan struct{}),
-		}
-
-		// Iniciar processador de eventos
-		globalObserver.wg.Add(1)
-		go globalObserver.processEvents()
+		globalObserver = newObserver(cfg)
 	})
 
 	return globalObserver, err
 }
 
+// newObserver cria um observador e inicia o processador de eventos
+func newObserver(cfg *config.Config) *Observer {
+	o := &Observer{
+		config:             cfg,
+		conversations:      make(map[string]*types.Conversation),
+		eventChan:          make(chan interface{}, 1000),
+		shutdownChan:       make(chan struct{}),
+		recentInteractions: make(map[string]map[interactionKey]time.Time),
+		aliases:            make(map[string]string),
+		sampler:            newSampler(nil),
+		logger:             zap.NewNop(),
+	}
+	if cfg != nil {
+		o.sampler = newSampler(cfg.EventSampling)
+	}
+
+	o.wg.Add(1)
+	go o.processEvents()
+
+	return o
+}
+
 // GetObserver retorna o observador global
 func GetObserver() *Observer {
 	if globalObserver == nil {
@@ -93,7 +123,7 @@ func (o *Observer) StartConversation(ctx context.Context, start types.Conversati
 	o.conversations[start.ConversationID] = conversation
 
 	// Emitir evento
-	o.eventChan <- types.InteractionEvent{
+	o.emit(types.InteractionEvent{
 		ConversationID: start.ConversationID,
 		TenantID:       start.TenantID,
 		AgentID:        start.AgentID,
@@ -102,8 +132,8 @@ func (o *Observer) StartConversation(ctx context.Context, start types.Conversati
 		Timestamp:      start.StartTime,
 		Channel:        start.Channel,
 		Language:       start.Language,
-		Metadata:       conversation.Metadata,
-	}
+		Metadata:       maps.Clone(conversation.Metadata), // assinantes leem fora do lock
+	})
 
 	return start.ConversationID
 }
@@ -113,11 +143,13 @@ func TrackInteraction(ctx context.Context, conversationID string, interaction ty
 	return GetObserver().TrackInteraction(ctx, conversationID, interaction)
 }
 
-// TrackInteraction rastreia uma interação
+// TrackInteraction rastreia uma interação. Com config.InteractionDedupWindow,
+// uma repetição da mesma fala dentro da janela é ignorada sem erro.
 func (o *Observer) TrackInteraction(ctx context.Context, conversationID string, interaction types.Interaction) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
+	conversationID = o.resolve(conversationID)
 	conversation, exists := o.conversations[conversationID]
 	if !exists {
 		return fmt.Errorf("conversation %s not found", conversationID)
@@ -129,6 +161,9 @@ func (o *Observer) TrackInteraction(ctx context.Context, conversationID string,
 	if interaction.Timestamp.IsZero() {
 		interaction.Timestamp = time.Now()
 	}
+	if o.isDuplicate(conversationID, interaction) {
+		return nil
+	}
 
 	interaction.ConversationID = conversationID
 	interaction.TenantID = conversation.TenantID
@@ -143,7 +178,7 @@ func (o *Observer) TrackInteraction(ctx context.Context, conversationID string,
 		duration = interaction.Timestamp.Sub(lastInteraction.Timestamp)
 	}
 
-	o.eventChan <- types.InteractionEvent{
+	o.emit(types.InteractionEvent{
 		InteractionID:  interaction.InteractionID,
 		ConversationID: conversationID,
 		TenantID:       conversation.TenantID,
@@ -159,7 +194,7 @@ func (o *Observer) TrackInteraction(ctx context.Context, conversationID string,
 		Intent:         interaction.Intent,
 		Confidence:     interaction.Confidence,
 		Duration:       duration,
-	}
+	})
 
 	return nil
 }
@@ -174,6 +209,7 @@ func (o *Observer) TrackDecision(ctx context.Context, conversationID string, dec
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
+	conversationID = o.resolve(conversationID)
 	conversation, exists := o.conversations[conversationID]
 	if !exists {
 		return fmt.Errorf("conversation %s not found", conversationID)
@@ -195,7 +231,7 @@ func (o *Observer) TrackDecision(ctx context.Context, conversationID string, dec
 	conversation.Decisions = append(conversation.Decisions, decision)
 
 	// Emitir evento
-	o.eventChan <- types.DecisionEvent{
+	o.emit(types.DecisionEvent{
 		DecisionID:     decision.DecisionID,
 		ConversationID: conversationID,
 		TenantID:       conversation.TenantID,
@@ -206,8 +242,39 @@ func (o *Observer) TrackDecision(ctx context.Context, conversationID string, dec
 		Option:         decision.Option,
 		Reason:         decision.Reason,
 		Timestamp:      decision.Timestamp,
+	})
+
+	return nil
+}
+
+// TrackComplianceCheck registra uma verificação de compliance
+func TrackComplianceCheck(ctx context.Context, conversationID string, check types.ComplianceCheck) error {
+	return GetObserver().TrackComplianceCheck(ctx, conversationID, check)
+}
+
+// TrackComplianceCheck registra uma verificação de compliance
+func (o *Observer) TrackComplianceCheck(ctx context.Context, conversationID string, check types.ComplianceCheck) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	conversationID = o.resolve(conversationID)
+	conversation, exists := o.conversations[conversationID]
+	if !exists {
+		return fmt.Errorf("conversation %s not found", conversationID)
 	}
 
+	if check.CheckID == "" {
+		check.CheckID = uuid.New().String()
+	}
+	if check.Timestamp.IsZero() {
+		check.Timestamp = time.Now()
+	}
+
+	check.ConversationID = conversationID
+	check.TenantID = conversation.TenantID
+
+	conversation.ComplianceChecks = append(conversation.ComplianceChecks, check)
+
 	return nil
 }
 
@@ -218,31 +285,75 @@ func EndConversation(ctx context.Context, conversationID string, end types.Conve
 
 // EndConversation finaliza uma conversação
 func (o *Observer) EndConversation(ctx context.Context, conversationID string, end types.ConversationEnd) error {
+	if end.EndTime.IsZero() {
+		end.EndTime = time.Now()
+	}
+
+	// Encerrar uma conversação já mesclada só desfaz o alias
+	o.mu.Lock()
+	if _, merged := o.aliases[conversationID]; merged {
+		delete(o.aliases, conversationID)
+		o.mu.Unlock()
+		return nil
+	}
+	o.mu.Unlock()
+
+	// Classificadores rodam antes do evento de fim e fora do lock
+	classification, err := o.classifyConversation(ctx, conversationID, end)
+	if err != nil {
+		return err
+	}
+
 	o.mu.Lock()
-	defer o.mu.Unlock()
 
 	conversation, exists := o.conversations[conversationID]
 	if !exists {
+		o.mu.Unlock()
 		return fmt.Errorf("conversation %s not found", conversationID)
 	}
 
-	if end.EndTime.IsZero() {
-		end.EndTime = time.Now()
-	}
-
 	conversation.EndTime = end.EndTime
 	conversation.Duration = end.EndTime.Sub(conversation.StartTime)
 	conversation.Resolution = end.Resolution
 	conversation.Rating = end.Rating
-	conversation.Tags = end.Tags
+	conversation.Tags = mergeTags(slices.Clone(end.Tags), classification.tags)
 
 	// Copiar metadata adicional
 	for k, v := range end.Metadata {
 		conversation.Metadata[k] = v
 	}
+	for k, v := range classification.metadata {
+		conversation.Metadata[k] = v
+	}
+	if len(classification.failures) > 0 {
+		conversation.Metadata["classification_errors"] = classification.failures
+	}
+
+	// A conversação sai da memória a seguir: o export é capturado antes
+	var export *types.ConversationExport
+	if o.config.ExportOnEnd || o.exportSink != nil {
+		e := types.NewConversationExport(conversation)
+		export = &e
+	}
 
 	// Emitir evento
-	o.eventChan <- types.InteractionEvent{
+	metadata := map[string]any{
+		"resolution":           end.Resolution,
+		"rating":               end.Rating,
+		"interaction_count":    conversation.InteractionCount,
+		"tags":                 conversation.Tags,
+		"sentiment_trajectory": conversation.SentimentTrajectory(),
+	}
+	if len(classification.metadata) > 0 {
+		metadata["classification"] = classification.metadata
+	}
+	if len(classification.failures) > 0 {
+		metadata["classification_errors"] = classification.failures
+	}
+	if o.config.ExportOnEnd {
+		metadata["export"] = *export
+	}
+	o.emit(types.InteractionEvent{
 		ConversationID: conversationID,
 		TenantID:       conversation.TenantID,
 		AgentID:        conversation.AgentID,
@@ -251,16 +362,26 @@ func (o *Observer) EndConversation(ctx context.Context, conversationID string, e
 		Timestamp:      end.EndTime,
 		Channel:        conversation.Channel,
 		Duration:       conversation.Duration,
-		Metadata: map[string]any{
-			"resolution":        end.Resolution,
-			"rating":            end.Rating,
-			"interaction_count": conversation.InteractionCount,
-			"tags":              end.Tags,
-		},
-	}
+		Metadata:       metadata,
+	})
 
 	// Remover da memória após processamento
 	delete(o.conversations, conversationID)
+	delete(o.recentInteractions, conversationID)
+	for alias, target := range o.aliases {
+		if target == conversationID {
+			delete(o.aliases, alias)
+		}
+	}
+	sink := o.exportSink
+	o.mu.Unlock()
+
+	// O sink pode fazer I/O, então roda fora do lock
+	if sink != nil {
+		if err := sink.WriteExport(ctx, *export); err != nil {
+			return fmt.Errorf("conversation %s ended but export failed: %w", conversationID, err)
+		}
+	}
 
 	return nil
 }
@@ -278,6 +399,27 @@ func (o *Observer) GetConversation(conversationID string) (*types.Conversation,
 	return conversation, nil
 }
 
+// SentimentTrajectory retorna a trajetória de sentimento de uma conversação ativa
+func SentimentTrajectory(conversationID string) (types.SentimentTrajectory, error) {
+	return GetObserver().SentimentTrajectory(conversationID)
+}
+
+// SentimentTrajectory retorna os scores de sentimento em ordem cronológica, a
+// tendência (melhorando, piorando ou estável) e a maior queda, útil para achar
+// onde a chamada deu errado. Também vai no evento conversation.ended, em
+// metadata["sentiment_trajectory"].
+func (o *Observer) SentimentTrajectory(conversationID string) (types.SentimentTrajectory, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	conversation, exists := o.conversations[conversationID]
+	if !exists {
+		return types.SentimentTrajectory{}, fmt.Errorf("conversation %s not found", conversationID)
+	}
+
+	return conversation.SentimentTrajectory(), nil
+}
+
 // processEvents processa eventos de forma assíncrona
 func (o *Observer) processEvents() {
 	defer o.wg.Done()
@@ -285,21 +427,25 @@ func (o *Observer) processEvents() {
 	for {
 		select {
 		case event := <-o.eventChan:
-			// Aqui você pode processar eventos:
-			// - Enviar para ClickHouse
-			// - Enviar para Loki (logs)
-			// - Criar spans OpenTelemetry
-			// - Atualizar métricas Prometheus
-			_ = event
+			o.deliver(event)
 
 		case <-o.shutdownChan:
-			return
+			// Entrega o que já foi emitido antes de sair
+			for {
+				select {
+				case event := <-o.eventChan:
+					o.deliver(event)
+				default:
+					return
+				}
+			}
 		}
 	}
 }
 
 // Shutdown encerra o observador
 func (o *Observer) Shutdown(ctx context.Context) error {
+	o.CloseSubscriptions()
 	close(o.shutdownChan)
 	o.wg.Wait()
 	close(o.eventChan)
@@ -0,0 +1,86 @@
+package observability
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// sampler decide quais eventos seguem para os sinks, com a fração de
+// config.EventSampling do tipo de cada evento. A decisão sai de um hash da
+// conversação, então uma conversação amostrada mantém todos os seus eventos
+// de um tipo, e também os de tipos com fração maior. Início, fim e erros nunca
+// são descartados.
+type sampler struct {
+	rates map[string]float64
+}
+
+func newSampler(rates map[string]float64) *sampler {
+	return &sampler{rates: rates}
+}
+
+// keep informa se o evento segue para os sinks
+func (s *sampler) keep(event any) bool {
+	var eventType, conversationID string
+	switch e := event.(type) {
+	case types.InteractionEvent:
+		eventType, conversationID = e.EventType, e.ConversationID
+	case types.DecisionEvent:
+		eventType, conversationID = e.EventType, e.ConversationID
+	default:
+		return true
+	}
+	if len(s.rates) == 0 || alwaysKept(eventType) {
+		return true
+	}
+
+	rate := s.rate(eventType)
+	switch {
+	case rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	}
+	return conversationFraction(conversationID) < rate
+}
+
+// rate retorna a fração do tipo: a entrada exata, depois o prefixo
+// "a.b.*" mais longo, depois "*"; sem entrada, o evento é mantido
+func (s *sampler) rate(eventType string) float64 {
+	if rate, ok := s.rates[eventType]; ok {
+		return rate
+	}
+	for prefix := eventType; ; {
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+		if rate, ok := s.rates[prefix+".*"]; ok {
+			return rate
+		}
+	}
+	if rate, ok := s.rates["*"]; ok {
+		return rate
+	}
+	return 1
+}
+
+// alwaysKept informa se o tipo marca o início, o fim, a mesclagem ou um erro da
+// conversação, sem os quais ela não pode ser reconstruída
+func alwaysKept(eventType string) bool {
+	switch eventType {
+	case "conversation.started", "conversation.ended", "conversation.merged":
+		return true
+	}
+	return strings.HasSuffix(eventType, ".error") || strings.HasSuffix(eventType, ".failed")
+}
+
+// conversationFraction mapeia a conversação em [0, 1) de forma estável entre
+// réplicas e reinícios
+func conversationFraction(conversationID string) float64 {
+	sum := sha256.Sum256([]byte(conversationID))
+	return float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+}
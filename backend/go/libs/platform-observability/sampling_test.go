@@ -0,0 +1,117 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+func interactionEvent(conversationID, eventType string) types.InteractionEvent {
+	return types.InteractionEvent{ConversationID: conversationID, EventType: eventType}
+}
+
+func TestSampler_AlwaysKeepsStartEndAndErrors(t *testing.T) {
+	s := newSampler(map[string]float64{"*": 0})
+
+	for _, eventType := range []string{"conversation.started", "conversation.ended", "tool.error", "transfer.failed"} {
+		for i := 0; i < 50; i++ {
+			if !s.keep(interactionEvent(fmt.Sprintf("conv-%d", i), eventType)) {
+				t.Fatalf("%s descartado com fração 0", eventType)
+			}
+		}
+	}
+	if s.keep(interactionEvent("conv-1", "interaction.customer")) {
+		t.Error("interaction.customer mantido com fração 0")
+	}
+	if s.keep(types.DecisionEvent{ConversationID: "conv-1", EventType: "decision.made"}) {
+		t.Error("decision.made mantido com fração 0")
+	}
+}
+
+func TestSampler_DeterministicPerConversation(t *testing.T) {
+	s := newSampler(map[string]float64{
+		"interaction.*": 0.1,
+		"decision.made": 0.5,
+	})
+
+	const conversations = 2000
+	var sampled int
+	for i := 0; i < conversations; i++ {
+		id := fmt.Sprintf("conv-%d", i)
+		first := s.keep(interactionEvent(id, "interaction.customer"))
+
+		// Todos os eventos da conversação têm a mesma decisão
+		for _, eventType := range []string{"interaction.customer", "interaction.agent", "interaction.system"} {
+			for j := 0; j < 3; j++ {
+				if s.keep(interactionEvent(id, eventType)) != first {
+					t.Fatalf("%s: decisão diferente para %s", id, eventType)
+				}
+			}
+		}
+		// Uma conversação com falas mantidas mantém também as decisões, de
+		// fração maior
+		if first {
+			sampled++
+			if !s.keep(types.DecisionEvent{ConversationID: id, EventType: "decision.made"}) {
+				t.Errorf("%s: falas mantidas mas decisão descartada", id)
+			}
+		}
+	}
+
+	if sampled < conversations/20 || sampled > conversations/5 {
+		t.Errorf("%d de %d conversações amostradas, esperado perto de 10%%", sampled, conversations)
+	}
+}
+
+func TestSampler_Rate(t *testing.T) {
+	s := newSampler(map[string]float64{
+		"interaction.*":              0.1,
+		"interaction.customer.*":     0.2,
+		"interaction.customer.final": 1,
+		"*":                          0.5,
+	})
+
+	tests := map[string]float64{
+		"interaction.agent":            0.1,
+		"interaction.customer.interim": 0.2,
+		"interaction.customer.final":   1,
+		"decision.made":                0.5,
+	}
+	for eventType, want := range tests {
+		if got := s.rate(eventType); got != want {
+			t.Errorf("rate(%s) = %v, esperado %v", eventType, got, want)
+		}
+	}
+	if got := newSampler(map[string]float64{"decision.made": 0}).rate("interaction.agent"); got != 1 {
+		t.Errorf("rate sem entrada = %v, esperado 1", got)
+	}
+}
+
+func TestEmit_SamplingSparesSubscribers(t *testing.T) {
+	o := newObserver(&config.Config{EventSampling: map[string]float64{"interaction.*": 0}})
+	t.Cleanup(func() { o.Shutdown(context.Background()) })
+	ctx := context.Background()
+
+	events, unsubscribe := o.Subscribe("tenant-a", WithBuffer(16))
+	defer unsubscribe()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	if err := o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer", Content: "oi"}); err != nil {
+		t.Fatalf("TrackInteraction: %v", err)
+	}
+
+	for _, want := range []string{"conversation.started", "interaction.customer"} {
+		select {
+		case event := <-events:
+			if event.Type != want {
+				t.Errorf("evento = %s, esperado %s", event.Type, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("assinante não recebeu %s", want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package observability
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// EventSink recebe os eventos de conversação que passam pela amostragem, em
+// ordem, como um produtor Kafka ou o ClickHouse. Um erro perde o evento; para
+// sobreviver a quedas do destino, envolva o sink com NewWALSink.
+type EventSink interface {
+	WriteEvent(ctx context.Context, event Event) error
+}
+
+// EventSinkFunc adapta uma função a EventSink
+type EventSinkFunc func(ctx context.Context, event Event) error
+
+// WriteEvent implementa EventSink
+func (f EventSinkFunc) WriteEvent(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// SetEventSink define o sink dos eventos; nil desativa. Falhas do sink são
+// registradas em logger.
+func (o *Observer) SetEventSink(sink EventSink, logger *zap.Logger) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	o.sinkMu.Lock()
+	defer o.sinkMu.Unlock()
+
+	o.eventSink = sink
+	o.sinkLogger = logger
+}
+
+// deliver entrega um evento ao sink. Roda no processador de eventos, que não
+// pode travar o.mu: quem emite o segura enquanto espera vaga no canal.
+func (o *Observer) deliver(raw any) {
+	event, ok := toEvent(raw)
+	if !ok {
+		return
+	}
+
+	o.sinkMu.RLock()
+	sink, logger := o.eventSink, o.sinkLogger
+	o.sinkMu.RUnlock()
+	if sink == nil {
+		return
+	}
+
+	if err := sink.WriteEvent(context.Background(), event); err != nil {
+		logger.Warn("observability event dropped",
+			zap.String("type", event.Type),
+			zap.String("conversation_id", event.ConversationID),
+			zap.Error(err),
+		)
+	}
+}
+
+// toEvent converte um evento emitido no Event entregue a sinks e assinantes
+func toEvent(raw any) (Event, bool) {
+	switch e := raw.(type) {
+	case types.InteractionEvent:
+		return Event{
+			Type:           e.EventType,
+			ConversationID: e.ConversationID,
+			TenantID:       e.TenantID,
+			AgentID:        e.AgentID,
+			Timestamp:      e.Timestamp,
+			Interaction:    &e,
+		}, true
+	case types.DecisionEvent:
+		return Event{
+			Type:           e.EventType,
+			ConversationID: e.ConversationID,
+			TenantID:       e.TenantID,
+			AgentID:        e.AgentID,
+			Timestamp:      e.Timestamp,
+			Decision:       &e,
+		}, true
+	}
+	return Event{}, false
+}
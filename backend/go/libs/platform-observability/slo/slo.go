@@ -0,0 +1,116 @@
+// Package slo define os SLIs (indicadores de nível de serviço) da plataforma
+// e os exporta como métricas Prometheus. Cada SLI conta os eventos totais
+// (denominador) e os eventos bons (numerador), de onde saem os SLOs e os
+// orçamentos de erro nas recording rules.
+package slo
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Definition define um SLI. Threshold é o limite de latência de um evento
+// bom, zero para SLIs que não são de latência. Objective é a fração de
+// eventos bons almejada, de 0 a 1.
+type Definition struct {
+	Name        string
+	Description string
+	Threshold   time.Duration
+	Objective   float64
+}
+
+// SLIs da plataforma.
+var (
+	// TurnLatency mede os turnos de conversa respondidos dentro de 1,5s.
+	TurnLatency = Definition{
+		Name:        "turn_latency",
+		Description: "Turnos respondidos em até 1,5s",
+		Threshold:   1500 * time.Millisecond,
+		Objective:   0.95,
+	}
+
+	// STTLatency mede as transcrições concluídas dentro de 500ms.
+	STTLatency = Definition{
+		Name:        "stt_latency",
+		Description: "Transcrições concluídas em até 500ms",
+		Threshold:   500 * time.Millisecond,
+		Objective:   0.95,
+	}
+
+	// AnswerSuccess mede as chamadas atendidas com sucesso.
+	AnswerSuccess = Definition{
+		Name:        "call_answer",
+		Description: "Chamadas atendidas com sucesso",
+		Objective:   0.99,
+	}
+)
+
+// Definitions retorna todos os SLIs da plataforma.
+func Definitions() []Definition {
+	return []Definition{TurnLatency, STTLatency, AnswerSuccess}
+}
+
+// Recorder registra os eventos dos SLIs de um serviço nas métricas:
+//
+//	serphona_sli_events_total{service,sli}      eventos (denominador)
+//	serphona_sli_good_events_total{service,sli} eventos bons (numerador)
+//	serphona_slo_objective{service,sli}         objetivo do SLI
+//
+// As séries de todos os SLIs começam em zero, para que as taxas existam
+// antes do primeiro evento.
+type Recorder struct {
+	service string
+	events  *prometheus.CounterVec
+	good    *prometheus.CounterVec
+}
+
+// NewRecorder cria um Recorder para o serviço e registra suas métricas em reg.
+func NewRecorder(reg prometheus.Registerer, service string) *Recorder {
+	r := &Recorder{
+		service: service,
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "serphona",
+			Subsystem: "sli",
+			Name:      "events_total",
+			Help:      "Eventos de cada SLI, por serviço",
+		}, []string{"service", "sli"}),
+		good: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "serphona",
+			Subsystem: "sli",
+			Name:      "good_events_total",
+			Help:      "Eventos bons de cada SLI, por serviço",
+		}, []string{"service", "sli"}),
+	}
+	objective := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "serphona",
+		Subsystem: "slo",
+		Name:      "objective",
+		Help:      "Fração de eventos bons almejada de cada SLI",
+	}, []string{"service", "sli"})
+	reg.MustRegister(r.events, r.good, objective)
+
+	for _, def := range Definitions() {
+		r.events.WithLabelValues(service, def.Name)
+		r.good.WithLabelValues(service, def.Name)
+		objective.WithLabelValues(service, def.Name).Set(def.Objective)
+	}
+	return r
+}
+
+// ObserveLatency registra um evento de um SLI de latência, bom quando d não
+// passa do limite do SLI.
+func (r *Recorder) ObserveLatency(def Definition, d time.Duration) {
+	r.Observe(def, d <= def.Threshold)
+}
+
+// Observe registra um evento de um SLI.
+func (r *Recorder) Observe(def Definition, good bool) {
+	if r == nil {
+		return
+	}
+	r.events.WithLabelValues(r.service, def.Name).Inc()
+	if good {
+		r.good.WithLabelValues(r.service, def.Name).Inc()
+	}
+}
@@ -0,0 +1,84 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// value retorna o valor da métrica name do SLI sli, ou -1 se não existir.
+func value(t *testing.T, reg *prometheus.Registry, name, sli string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "sli" && label.GetValue() == sli {
+					if m.GetCounter() != nil {
+						return m.GetCounter().GetValue()
+					}
+					return m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func TestRecorder_FastAndSlowTurns(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r := NewRecorder(reg, "voice-gateway")
+
+	r.ObserveLatency(TurnLatency, 800*time.Millisecond)
+	r.ObserveLatency(TurnLatency, TurnLatency.Threshold)
+	r.ObserveLatency(TurnLatency, 2*time.Second)
+
+	if got := value(t, reg, "serphona_sli_events_total", "turn_latency"); got != 3 {
+		t.Errorf("eventos = %v, esperado 3", got)
+	}
+	if got := value(t, reg, "serphona_sli_good_events_total", "turn_latency"); got != 2 {
+		t.Errorf("eventos bons = %v, esperado 2 (o turno lento não conta)", got)
+	}
+}
+
+func TestRecorder_Observe(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r := NewRecorder(reg, "voice-gateway")
+
+	r.Observe(AnswerSuccess, true)
+	r.Observe(AnswerSuccess, false)
+
+	if got := value(t, reg, "serphona_sli_events_total", "call_answer"); got != 2 {
+		t.Errorf("eventos = %v, esperado 2", got)
+	}
+	if got := value(t, reg, "serphona_sli_good_events_total", "call_answer"); got != 1 {
+		t.Errorf("eventos bons = %v, esperado 1", got)
+	}
+}
+
+func TestNewRecorder_InitializesSeries(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	NewRecorder(reg, "agent-orchestrator")
+
+	for _, def := range Definitions() {
+		if got := value(t, reg, "serphona_sli_events_total", def.Name); got != 0 {
+			t.Errorf("%s: eventos = %v, esperado a série em 0", def.Name, got)
+		}
+		if got := value(t, reg, "serphona_slo_objective", def.Name); got != def.Objective {
+			t.Errorf("%s: objetivo = %v, esperado %v", def.Name, got, def.Objective)
+		}
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var r *Recorder
+	r.ObserveLatency(TurnLatency, time.Second)
+	r.Observe(AnswerSuccess, true)
+}
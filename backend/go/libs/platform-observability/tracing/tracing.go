@@ -0,0 +1,114 @@
+// Package tracing configura o tracing distribuído com OpenTelemetry: exportação
+// de spans via OTLP, propagação W3C traceparent em servidores e clientes HTTP
+// e acesso aos IDs do span atual para logs e eventos.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/serphona/backend/go/libs/platform-observability/config"
+)
+
+// instrumentationName identifica os spans criados por este pacote
+const instrumentationName = "github.com/serphona/backend/go/libs/platform-observability/tracing"
+
+// ShutdownFunc envia os spans pendentes e encerra o exportador
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup configura o TracerProvider global exportando spans via OTLP/gRPC para
+// cfg.TracingEndpoint, com amostragem cfg.TracingSampler respeitando a decisão
+// do span pai. O propagador W3C (traceparent e baggage) é sempre registrado,
+// mesmo com o tracing desabilitado, para que o contexto continue fluindo
+// entre serviços.
+func Setup(ctx context.Context, cfg *config.Config) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.TracingEnabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	endpoint, insecure, err := parseEndpoint(cfg.TracingEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.ServiceName),
+			semconv.ServiceVersion(cfg.ServiceVersion),
+			semconv.DeploymentEnvironment(cfg.Environment),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampler))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer retorna o tracer global com o nome informado
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// IDs retorna os IDs de trace e span do span atual, em hexadecimal, ou strings
+// vazias se o contexto não tiver um span válido. O formato casa com
+// Event.WithTrace do platform-events:
+//
+//	event.WithTrace(tracing.IDs(ctx))
+func IDs(ctx context.Context) (traceID, spanID string) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", ""
+	}
+	return sc.TraceID().String(), sc.SpanID().String()
+}
+
+// parseEndpoint converte "http://tempo:4317" ou "tempo:4317" em host:porta.
+// Endpoints http:// ou sem esquema usam conexão sem TLS.
+func parseEndpoint(endpoint string) (string, bool, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		// Sem esquema, ex.: "tempo:4317"
+		return endpoint, true, nil
+	}
+	switch u.Scheme {
+	case "http":
+		return u.Host, true, nil
+	case "https":
+		return u.Host, false, nil
+	default:
+		return "", false, fmt.Errorf("invalid tracing endpoint %q", endpoint)
+	}
+}
@@ -27,22 +27,23 @@ type ConversationEnd struct {
 
 // Conversation representa uma conversação completa
 type Conversation struct {
-	ConversationID   string         `json:"conversation_id"`
-	TenantID         string         `json:"tenant_id"`
-	AgentID          string         `json:"agent_id"`
-	CustomerID       string         `json:"customer_id"`
-	Channel          string         `json:"channel"`
-	Language         string         `json:"language"`
-	StartTime        time.Time      `json:"start_time"`
-	EndTime          time.Time      `json:"end_time"`
-	Duration         time.Duration  `json:"duration"`
-	InteractionCount int            `json:"interaction_count"`
-	Interactions     []Interaction  `json:"interactions"`
-	Decisions        []Decision     `json:"decisions"`
-	Resolution       string         `json:"resolution"`
-	Rating           int            `json:"rating"`
-	Tags             []string       `json:"tags"`
-	Metadata         map[string]any `json:"metadata,omitempty"`
+	ConversationID   string            `json:"conversation_id"`
+	TenantID         string            `json:"tenant_id"`
+	AgentID          string            `json:"agent_id"`
+	CustomerID       string            `json:"customer_id"`
+	Channel          string            `json:"channel"`
+	Language         string            `json:"language"`
+	StartTime        time.Time         `json:"start_time"`
+	EndTime          time.Time         `json:"end_time"`
+	Duration         time.Duration     `json:"duration"`
+	InteractionCount int               `json:"interaction_count"`
+	Interactions     []Interaction     `json:"interactions"`
+	Decisions        []Decision        `json:"decisions"`
+	ComplianceChecks []ComplianceCheck `json:"compliance_checks,omitempty"`
+	Resolution       string            `json:"resolution"`
+	Rating           int               `json:"rating"`
+	Tags             []string          `json:"tags"`
+	Metadata         map[string]any    `json:"metadata,omitempty"`
 }
 
 // ConversationMetrics representa métricas agregadas de conversações
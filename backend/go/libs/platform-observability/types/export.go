@@ -0,0 +1,186 @@
+package types
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// TimelineEntryKind identifica o tipo de uma entrada da timeline
+type TimelineEntryKind string
+
+const (
+	TimelineConversationStarted TimelineEntryKind = "conversation.started"
+	TimelineInteraction         TimelineEntryKind = "interaction"
+	TimelineDecision            TimelineEntryKind = "decision"
+	TimelineComplianceCheck     TimelineEntryKind = "compliance_check"
+	TimelineConversationEnded   TimelineEntryKind = "conversation.ended"
+)
+
+// TimelineEntry é um item da timeline; apenas o campo do Kind é preenchido
+type TimelineEntry struct {
+	Kind            TimelineEntryKind `json:"kind"`
+	Timestamp       time.Time         `json:"timestamp"`
+	Interaction     *Interaction      `json:"interaction,omitempty"`
+	Decision        *Decision         `json:"decision,omitempty"`
+	ComplianceCheck *ComplianceCheck  `json:"compliance_check,omitempty"`
+}
+
+// SentimentPoint é o sentimento de uma interação na trajetória da conversa
+type SentimentPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Speaker   string    `json:"speaker"`
+	Sentiment string    `json:"sentiment"`
+	Score     float64   `json:"score"` // positive = 1, neutral = 0, negative = -1
+}
+
+// ExportMetrics são métricas derivadas da timeline
+type ExportMetrics struct {
+	Duration            time.Duration    `json:"duration"`
+	TurnCount           int              `json:"turn_count"` // trocas de falante entre agente e cliente
+	InteractionCount    int              `json:"interaction_count"`
+	DecisionCount       int              `json:"decision_count"`
+	ComplianceChecks    int              `json:"compliance_checks"`
+	ComplianceFailures  int              `json:"compliance_failures"`
+	SentimentTrajectory []SentimentPoint `json:"sentiment_trajectory"`
+	SentimentTrend      SentimentTrend   `json:"sentiment_trend"`
+}
+
+// ConversationExport é a timeline completa de uma conversação, ordenada
+// cronologicamente, para pesquisa e QA
+type ConversationExport struct {
+	ConversationID string          `json:"conversation_id"`
+	TenantID       string          `json:"tenant_id"`
+	AgentID        string          `json:"agent_id"`
+	CustomerID     string          `json:"customer_id"`
+	Channel        string          `json:"channel"`
+	Language       string          `json:"language"`
+	StartTime      time.Time       `json:"start_time"`
+	EndTime        time.Time       `json:"end_time"`
+	Resolution     string          `json:"resolution,omitempty"`
+	Rating         int             `json:"rating,omitempty"`
+	Tags           []string        `json:"tags,omitempty"`
+	Metadata       map[string]any  `json:"metadata,omitempty"`
+	Metrics        ExportMetrics   `json:"metrics"`
+	Timeline       []TimelineEntry `json:"timeline"`
+}
+
+// NewConversationExport monta o export de c. A timeline começa em
+// conversation.started, termina em conversation.ended (se a conversação já
+// terminou) e ordena interações, decisões e verificações de compliance pelo
+// timestamp, preservando a ordem de registro em empates.
+func NewConversationExport(c *Conversation) ConversationExport {
+	export := ConversationExport{
+		ConversationID: c.ConversationID,
+		TenantID:       c.TenantID,
+		AgentID:        c.AgentID,
+		CustomerID:     c.CustomerID,
+		Channel:        c.Channel,
+		Language:       c.Language,
+		StartTime:      c.StartTime,
+		EndTime:        c.EndTime,
+		Resolution:     c.Resolution,
+		Rating:         c.Rating,
+		Tags:           append([]string(nil), c.Tags...),
+		Metadata:       make(map[string]any, len(c.Metadata)),
+	}
+	for k, v := range c.Metadata {
+		export.Metadata[k] = v
+	}
+
+	var entries []TimelineEntry
+	for i := range c.Interactions {
+		interaction := c.Interactions[i]
+		entries = append(entries, TimelineEntry{Kind: TimelineInteraction, Timestamp: interaction.Timestamp, Interaction: &interaction})
+	}
+	for i := range c.Decisions {
+		decision := c.Decisions[i]
+		entries = append(entries, TimelineEntry{Kind: TimelineDecision, Timestamp: decision.Timestamp, Decision: &decision})
+	}
+	for i := range c.ComplianceChecks {
+		check := c.ComplianceChecks[i]
+		entries = append(entries, TimelineEntry{Kind: TimelineComplianceCheck, Timestamp: check.Timestamp, ComplianceCheck: &check})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	export.Timeline = make([]TimelineEntry, 0, len(entries)+2)
+	export.Timeline = append(export.Timeline, TimelineEntry{Kind: TimelineConversationStarted, Timestamp: c.StartTime})
+	export.Timeline = append(export.Timeline, entries...)
+	if !c.EndTime.IsZero() {
+		export.Timeline = append(export.Timeline, TimelineEntry{Kind: TimelineConversationEnded, Timestamp: c.EndTime})
+	}
+
+	export.Metrics = deriveMetrics(c, export.Timeline)
+	return export
+}
+
+// deriveMetrics calcula as métricas a partir da timeline ordenada
+func deriveMetrics(c *Conversation, timeline []TimelineEntry) ExportMetrics {
+	metrics := ExportMetrics{
+		InteractionCount:    len(c.Interactions),
+		DecisionCount:       len(c.Decisions),
+		ComplianceChecks:    len(c.ComplianceChecks),
+		SentimentTrajectory: []SentimentPoint{},
+	}
+
+	// Conversações em andamento vão até o último evento registrado
+	end := c.EndTime
+	if end.IsZero() {
+		end = timeline[len(timeline)-1].Timestamp
+	}
+	metrics.Duration = end.Sub(c.StartTime)
+
+	lastSpeaker := ""
+	for _, entry := range timeline {
+		switch {
+		case entry.Interaction != nil:
+			i := entry.Interaction
+			if i.Speaker != "system" && i.Speaker != lastSpeaker {
+				metrics.TurnCount++
+				lastSpeaker = i.Speaker
+			}
+			if point, ok := sentimentPoint(*i); ok {
+				metrics.SentimentTrajectory = append(metrics.SentimentTrajectory, point)
+			}
+		case entry.ComplianceCheck != nil:
+			if !entry.ComplianceCheck.Passed {
+				metrics.ComplianceFailures++
+			}
+		}
+	}
+	metrics.SentimentTrend = NewSentimentTrajectory(metrics.SentimentTrajectory).Trend
+
+	return metrics
+}
+
+// WriteJSON grava o export como um único documento JSON
+func (e ConversationExport) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(e)
+}
+
+// exportHeader é a primeira linha do JSONL: o export sem a timeline
+type exportHeader struct {
+	Kind string `json:"kind"`
+	ConversationExport
+	Timeline []TimelineEntry `json:"timeline,omitempty"`
+}
+
+// WriteJSONL grava o export em JSON Lines: uma linha de cabeçalho com os dados
+// da conversação e as métricas (kind "conversation"), seguida de uma linha por
+// entrada da timeline, em ordem cronológica
+func (e ConversationExport) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(exportHeader{Kind: "conversation", ConversationExport: e}); err != nil {
+		return err
+	}
+
+	for _, entry := range e.Timeline {
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func mixedConversation(start time.Time) *Conversation {
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	// Registrados fora de ordem e com tipos intercalados
+	return &Conversation{
+		ConversationID: "conv-1",
+		TenantID:       "tenant-a",
+		AgentID:        "agent-1",
+		StartTime:      start,
+		EndTime:        at(60),
+		Resolution:     "transferred",
+		Interactions: []Interaction{
+			{InteractionID: "i1", Speaker: "agent", Content: "Olá!", Sentiment: "neutral", Timestamp: at(1)},
+			{InteractionID: "i2", Speaker: "customer", Content: "Minha fatura veio errada", Sentiment: "negative", Timestamp: at(5)},
+			{InteractionID: "i4", Speaker: "agent", Content: "Vou transferir você", Timestamp: at(30)},
+			{InteractionID: "i3", Speaker: "system", Content: "Cliente em espera", Timestamp: at(12)},
+			{InteractionID: "i5", Speaker: "customer", Content: "Obrigado", Sentiment: "positive", Timestamp: at(40)},
+		},
+		Decisions: []Decision{
+			{DecisionID: "d1", DecisionType: "transfer", Option: "billing", Timestamp: at(31)},
+		},
+		ComplianceChecks: []ComplianceCheck{
+			{CheckID: "c2", PolicyID: "lgpd-consent", Passed: false, Timestamp: at(20)},
+			{CheckID: "c1", PolicyID: "greeting", Passed: true, Timestamp: at(2)},
+		},
+	}
+}
+
+// entryID identifica uma entrada da timeline para as asserções
+func entryID(e TimelineEntry) string {
+	switch {
+	case e.Interaction != nil:
+		return e.Interaction.InteractionID
+	case e.Decision != nil:
+		return e.Decision.DecisionID
+	case e.ComplianceCheck != nil:
+		return e.ComplianceCheck.CheckID
+	default:
+		return string(e.Kind)
+	}
+}
+
+func TestNewConversationExport_OrdersMixedEventsChronologically(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	export := NewConversationExport(mixedConversation(start))
+
+	want := []string{"conversation.started", "i1", "c1", "i2", "i3", "c2", "i4", "d1", "i5", "conversation.ended"}
+	if len(export.Timeline) != len(want) {
+		t.Fatalf("timeline com %d entradas, esperado %d", len(export.Timeline), len(want))
+	}
+	for i, entry := range export.Timeline {
+		if got := entryID(entry); got != want[i] {
+			t.Errorf("timeline[%d] = %s, esperado %s", i, got, want[i])
+		}
+		if i > 0 && entry.Timestamp.Before(export.Timeline[i-1].Timestamp) {
+			t.Errorf("timeline[%d] fora de ordem: %s antes de %s", i, entry.Timestamp, export.Timeline[i-1].Timestamp)
+		}
+	}
+}
+
+func TestNewConversationExport_DerivedMetrics(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	m := NewConversationExport(mixedConversation(start)).Metrics
+
+	if m.Duration != time.Minute {
+		t.Errorf("Duration = %s, esperado 1m", m.Duration)
+	}
+	// agent, customer, (system ignorado), agent, customer
+	if m.TurnCount != 4 {
+		t.Errorf("TurnCount = %d, esperado 4", m.TurnCount)
+	}
+	if m.InteractionCount != 5 || m.DecisionCount != 1 || m.ComplianceChecks != 2 || m.ComplianceFailures != 1 {
+		t.Errorf("contagens inesperadas: %+v", m)
+	}
+
+	scores := []float64{0, -1, 1}
+	if len(m.SentimentTrajectory) != len(scores) {
+		t.Fatalf("trajetória = %+v, esperados %d pontos", m.SentimentTrajectory, len(scores))
+	}
+	for i, p := range m.SentimentTrajectory {
+		if p.Score != scores[i] {
+			t.Errorf("trajetória[%d].Score = %g, esperado %g", i, p.Score, scores[i])
+		}
+	}
+}
+
+func TestNewConversationExport_ActiveConversation(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	c := mixedConversation(start)
+	c.EndTime = time.Time{}
+
+	export := NewConversationExport(c)
+	if last := export.Timeline[len(export.Timeline)-1]; last.Kind == TimelineConversationEnded {
+		t.Error("conversação ativa não deveria ter conversation.ended")
+	}
+	// Vai até o último evento registrado (i5, aos 40s)
+	if export.Metrics.Duration != 40*time.Second {
+		t.Errorf("Duration = %s, esperado 40s", export.Metrics.Duration)
+	}
+}
+
+func TestConversationExport_WriteJSONL(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	export := NewConversationExport(mixedConversation(start))
+
+	var buf bytes.Buffer
+	if err := export.WriteJSONL(&buf); err != nil {
+		t.Fatalf("WriteJSONL: %v", err)
+	}
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("linha inválida %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) != len(export.Timeline)+1 {
+		t.Fatalf("%d linhas, esperado cabeçalho + %d entradas", len(lines), len(export.Timeline))
+	}
+	header := lines[0]
+	if header["kind"] != "conversation" || header["conversation_id"] != "conv-1" || header["metrics"] == nil {
+		t.Errorf("cabeçalho inesperado: %v", header)
+	}
+	if _, ok := header["timeline"]; ok {
+		t.Error("cabeçalho não deveria repetir a timeline")
+	}
+	if lines[1]["kind"] != string(TimelineConversationStarted) || lines[len(lines)-1]["kind"] != string(TimelineConversationEnded) {
+		t.Errorf("primeira/última entrada inesperadas: %v / %v", lines[1]["kind"], lines[len(lines)-1]["kind"])
+	}
+}
@@ -0,0 +1,126 @@
+package types
+
+import "sort"
+
+// SentimentTrend é a tendência do sentimento ao longo da conversação
+type SentimentTrend string
+
+const (
+	SentimentImproving SentimentTrend = "improving"
+	SentimentDeclining SentimentTrend = "declining"
+	SentimentStable    SentimentTrend = "stable"
+)
+
+// sentimentScores converte o rótulo de sentimento em score
+var sentimentScores = map[string]float64{
+	"positive": 1,
+	"neutral":  0,
+	"negative": -1,
+}
+
+// trendThreshold é a variação projetada de score, do início ao fim da
+// conversação, a partir da qual a tendência deixa de ser estável
+const trendThreshold = 0.5
+
+// SentimentSwing é a variação de sentimento entre dois pontos consecutivos
+type SentimentSwing struct {
+	From  SentimentPoint `json:"from"`
+	To    SentimentPoint `json:"to"`
+	Delta float64        `json:"delta"`
+}
+
+// SentimentTrajectory é a evolução do sentimento de uma conversação
+type SentimentTrajectory struct {
+	Points []SentimentPoint `json:"points"`
+	Trend  SentimentTrend   `json:"trend"`
+	// LargestDrop é a maior queda entre pontos consecutivos, onde a conversa
+	// mais piorou; nil quando o sentimento nunca cai
+	LargestDrop *SentimentSwing `json:"largest_drop,omitempty"`
+}
+
+// NewSentimentTrajectory calcula a tendência e a maior queda de pontos em
+// ordem cronológica. Quando há pontos do cliente, apenas eles contam: o
+// sentimento do agente tende a ser neutro e diluiria a tendência.
+func NewSentimentTrajectory(points []SentimentPoint) SentimentTrajectory {
+	trajectory := SentimentTrajectory{
+		Points: append([]SentimentPoint{}, points...),
+		Trend:  SentimentStable,
+	}
+
+	series := make([]SentimentPoint, 0, len(points))
+	for _, p := range points {
+		if p.Speaker == "customer" {
+			series = append(series, p)
+		}
+	}
+	if len(series) == 0 {
+		series = points
+	}
+	if len(series) < 2 {
+		return trajectory
+	}
+
+	// Inclinação por mínimos quadrados sobre a posição de cada ponto
+	n := float64(len(series))
+	meanX := (n - 1) / 2
+	var meanY float64
+	for _, p := range series {
+		meanY += p.Score
+	}
+	meanY /= n
+
+	var cov, variance float64
+	for i, p := range series {
+		dx := float64(i) - meanX
+		cov += dx * (p.Score - meanY)
+		variance += dx * dx
+	}
+	change := cov / variance * (n - 1)
+
+	switch {
+	case change >= trendThreshold:
+		trajectory.Trend = SentimentImproving
+	case change <= -trendThreshold:
+		trajectory.Trend = SentimentDeclining
+	}
+
+	for i := 1; i < len(series); i++ {
+		delta := series[i].Score - series[i-1].Score
+		if delta < 0 && (trajectory.LargestDrop == nil || delta < trajectory.LargestDrop.Delta) {
+			trajectory.LargestDrop = &SentimentSwing{From: series[i-1], To: series[i], Delta: delta}
+		}
+	}
+
+	return trajectory
+}
+
+// SentimentTrajectory calcula a trajetória de sentimento das interações da
+// conversação, ordenadas pelo timestamp
+func (c *Conversation) SentimentTrajectory() SentimentTrajectory {
+	interactions := append([]Interaction{}, c.Interactions...)
+	sort.SliceStable(interactions, func(i, j int) bool {
+		return interactions[i].Timestamp.Before(interactions[j].Timestamp)
+	})
+
+	points := []SentimentPoint{}
+	for _, i := range interactions {
+		if point, ok := sentimentPoint(i); ok {
+			points = append(points, point)
+		}
+	}
+	return NewSentimentTrajectory(points)
+}
+
+// sentimentPoint converte uma interação com sentimento em ponto da trajetória
+func sentimentPoint(i Interaction) (SentimentPoint, bool) {
+	score, ok := sentimentScores[i.Sentiment]
+	if !ok {
+		return SentimentPoint{}, false
+	}
+	return SentimentPoint{
+		Timestamp: i.Timestamp,
+		Speaker:   i.Speaker,
+		Sentiment: i.Sentiment,
+		Score:     score,
+	}, true
+}
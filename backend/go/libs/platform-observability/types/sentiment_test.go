@@ -0,0 +1,123 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+// customerScript monta pontos do cliente a partir de rótulos de sentimento
+func customerScript(labels ...string) []SentimentPoint {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	points := make([]SentimentPoint, len(labels))
+	for i, label := range labels {
+		points[i] = SentimentPoint{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Speaker:   "customer",
+			Sentiment: label,
+			Score:     sentimentScores[label],
+		}
+	}
+	return points
+}
+
+func TestNewSentimentTrajectory_Trend(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		want   SentimentTrend
+	}{
+		{"melhora", []string{"negative", "negative", "neutral", "positive", "positive"}, SentimentImproving},
+		{"piora", []string{"positive", "neutral", "positive", "negative", "negative"}, SentimentDeclining},
+		{"oscila", []string{"neutral", "positive", "neutral", "positive", "neutral"}, SentimentStable},
+		{"constante", []string{"negative", "negative", "negative"}, SentimentStable},
+		{"recuperação no fim", []string{"negative", "negative", "negative", "positive"}, SentimentImproving},
+		{"um ponto", []string{"negative"}, SentimentStable},
+		{"sem pontos", nil, SentimentStable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trajectory := NewSentimentTrajectory(customerScript(tt.labels...))
+			if trajectory.Trend != tt.want {
+				t.Errorf("Trend = %s, esperado %s", trajectory.Trend, tt.want)
+			}
+			if len(trajectory.Points) != len(tt.labels) {
+				t.Errorf("Points = %d, esperado %d", len(trajectory.Points), len(tt.labels))
+			}
+		})
+	}
+}
+
+func TestNewSentimentTrajectory_LargestDrop(t *testing.T) {
+	points := customerScript("positive", "neutral", "positive", "negative", "negative")
+
+	trajectory := NewSentimentTrajectory(points)
+
+	drop := trajectory.LargestDrop
+	if drop == nil {
+		t.Fatal("esperada uma queda")
+	}
+	if drop.Delta != -2 || drop.From != points[2] || drop.To != points[3] {
+		t.Errorf("LargestDrop = %+v, esperado de %v para %v", drop, points[2], points[3])
+	}
+
+	// Em empate vale a primeira queda
+	tie := customerScript("positive", "neutral", "positive", "neutral")
+	if drop := NewSentimentTrajectory(tie).LargestDrop; drop == nil || drop.To != tie[1] {
+		t.Errorf("LargestDrop = %+v, esperado a primeira queda", drop)
+	}
+
+	if drop := NewSentimentTrajectory(customerScript("negative", "neutral", "positive")).LargestDrop; drop != nil {
+		t.Errorf("LargestDrop = %+v, esperado nil sem quedas", drop)
+	}
+}
+
+func TestNewSentimentTrajectory_PrefersCustomer(t *testing.T) {
+	points := customerScript("positive", "neutral", "negative")
+	for i := range points {
+		agent := points[i]
+		agent.Speaker = "agent"
+		agent.Sentiment = "positive"
+		agent.Score = 1
+		points = append(points, agent)
+	}
+
+	trajectory := NewSentimentTrajectory(points)
+	if trajectory.Trend != SentimentDeclining {
+		t.Errorf("Trend = %s, esperado declining pelo cliente", trajectory.Trend)
+	}
+	if len(trajectory.Points) != 6 {
+		t.Errorf("Points = %d, esperado todos os 6 pontos", len(trajectory.Points))
+	}
+
+	// Sem pontos do cliente, vale o que houver
+	agentOnly := points[3:]
+	for i := range agentOnly {
+		agentOnly[i].Score = float64(i) - 1
+	}
+	if trend := NewSentimentTrajectory(agentOnly).Trend; trend != SentimentImproving {
+		t.Errorf("Trend = %s, esperado improving pelo agente", trend)
+	}
+}
+
+func TestConversation_SentimentTrajectory(t *testing.T) {
+	c := mixedConversation(time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC))
+
+	trajectory := c.SentimentTrajectory()
+
+	want := []string{"neutral", "negative", "positive"}
+	if len(trajectory.Points) != len(want) {
+		t.Fatalf("Points = %+v, esperado %v", trajectory.Points, want)
+	}
+	for i, label := range want {
+		if trajectory.Points[i].Sentiment != label {
+			t.Errorf("Points[%d] = %s, esperado %s", i, trajectory.Points[i].Sentiment, label)
+		}
+	}
+	if trajectory.Trend != SentimentImproving {
+		t.Errorf("Trend = %s, esperado improving", trajectory.Trend)
+	}
+	if trajectory.LargestDrop != nil {
+		t.Errorf("LargestDrop = %+v, esperado nil", trajectory.LargestDrop)
+	}
+}
@@ -0,0 +1,234 @@
+package observability
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Padrões de WALConfig
+const (
+	DefaultWALMaxBytes       int64 = 100 << 20
+	DefaultWALReplayInterval       = 10 * time.Second
+)
+
+// ErrWALFull é retornado quando o sink falha e o WAL já está no limite; o
+// evento é perdido
+var ErrWALFull = errors.New("observability WAL is full")
+
+// WALConfig configura um WALSink
+type WALConfig struct {
+	// Path é o arquivo do WAL, criado se não existir
+	Path string
+	// MaxBytes limita o tamanho do WAL; zero usa DefaultWALMaxBytes
+	MaxBytes int64
+	// ReplayInterval é o intervalo entre tentativas de reenvio; zero usa
+	// DefaultWALReplayInterval
+	ReplayInterval time.Duration
+}
+
+// WALSink envolve um EventSink com um WAL local: quando o sink falha (o
+// broker fora do ar, por exemplo), o evento é gravado no arquivo, e um
+// replayer em segundo plano o reenvia quando o sink volta. Enquanto o WAL
+// tem eventos, os novos também vão para ele, mantendo a ordem. Um WAL
+// deixado por uma execução anterior é reenviado da mesma forma.
+type WALSink struct {
+	sink     EventSink
+	path     string
+	maxBytes int64
+	logger   *zap.Logger
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWALSink abre o WAL e inicia o replayer; Close o encerra
+func NewWALSink(sink EventSink, cfg WALConfig, logger *zap.Logger) (*WALSink, error) {
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultWALMaxBytes
+	}
+	if cfg.ReplayInterval <= 0 {
+		cfg.ReplayInterval = DefaultWALReplayInterval
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	file, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open observability WAL: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat observability WAL: %w", err)
+	}
+
+	w := &WALSink{
+		sink:     sink,
+		path:     cfg.Path,
+		maxBytes: cfg.MaxBytes,
+		logger:   logger,
+		file:     file,
+		size:     info.Size(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run(cfg.ReplayInterval)
+	return w, nil
+}
+
+// WriteEvent implementa EventSink. Só retorna erro quando o evento não coube
+// no WAL nem foi aceito pelo sink.
+func (w *WALSink) WriteEvent(ctx context.Context, event Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size == 0 {
+		err := w.sink.WriteEvent(ctx, event)
+		if err == nil {
+			return nil
+		}
+		w.logger.Warn("observability sink failed, writing to WAL", zap.Error(err))
+	}
+	return w.append(event)
+}
+
+// Pending retorna o tamanho em bytes dos eventos aguardando reenvio
+func (w *WALSink) Pending() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.size
+}
+
+// Replay reenvia os eventos do WAL, em ordem, até o sink falhar; os que
+// faltam ficam para a próxima tentativa. O replayer o chama periodicamente.
+func (w *WALSink) Replay(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return fmt.Errorf("failed to read observability WAL: %w", err)
+	}
+
+	var sent int
+	var sendErr error
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), int(w.maxBytes)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			// Linha truncada por uma queda no meio da escrita
+			w.logger.Warn("skipping corrupt observability WAL entry", zap.Error(err))
+		} else if sendErr = w.sink.WriteEvent(ctx, event); sendErr != nil {
+			break
+		}
+		sent += len(line) + 1
+	}
+	if sent > len(data) {
+		sent = len(data)
+	}
+	if sent > 0 {
+		if err := w.rewrite(data[sent:]); err != nil {
+			return err
+		}
+		w.logger.Info("replayed observability WAL", zap.Int("bytes", sent), zap.Int64("pending_bytes", w.size))
+	}
+	return sendErr
+}
+
+// Close encerra o replayer e fecha o WAL; eventos pendentes ficam no arquivo
+// para a próxima execução
+func (w *WALSink) Close() error {
+	close(w.stop)
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.file.Close()
+}
+
+func (w *WALSink) run(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Replay(context.Background()); err != nil {
+				w.logger.Debug("observability WAL replay deferred", zap.Error(err))
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// append grava o evento no fim do WAL; deve ser chamado com w.mu travado
+func (w *WALSink) append(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode observability event: %w", err)
+	}
+	line = append(line, '\n')
+	if w.size+int64(len(line)) > w.maxBytes {
+		w.logger.Error("observability WAL full, event dropped",
+			zap.String("type", event.Type),
+			zap.String("conversation_id", event.ConversationID),
+			zap.Int64("max_bytes", w.maxBytes),
+		)
+		return ErrWALFull
+	}
+
+	if _, err := w.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write observability WAL: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync observability WAL: %w", err)
+	}
+	w.size += int64(len(line))
+	return nil
+}
+
+// rewrite troca o conteúdo do WAL pelos eventos ainda pendentes, por um
+// arquivo temporário renomeado, para uma queda no meio não perder nenhum;
+// deve ser chamado com w.mu travado
+func (w *WALSink) rewrite(pending []byte) error {
+	tmp := w.path + ".tmp"
+	if err := os.WriteFile(tmp, pending, 0o600); err != nil {
+		return fmt.Errorf("failed to write observability WAL: %w", err)
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return fmt.Errorf("failed to replace observability WAL: %w", err)
+	}
+
+	file, err := os.OpenFile(w.path, os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen observability WAL: %w", err)
+	}
+	w.file.Close()
+	w.file = file
+	w.size = int64(len(pending))
+	return nil
+}
@@ -0,0 +1,241 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// flakySink aceita eventos enquanto não estiver fora do ar
+type flakySink struct {
+	mu     sync.Mutex
+	down   bool
+	events []Event
+}
+
+func (s *flakySink) WriteEvent(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		return errors.New("broker unavailable")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *flakySink) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *flakySink) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, len(s.events))
+	for i, e := range s.events {
+		ids[i] = e.Interaction.InteractionID
+	}
+	return ids
+}
+
+func walEvent(id string) Event {
+	return Event{
+		Type:           "interaction.customer",
+		ConversationID: "conv-1",
+		Interaction:    &types.InteractionEvent{InteractionID: id, EventType: "interaction.customer", Content: "oi"},
+	}
+}
+
+func newTestWAL(t *testing.T, sink EventSink, cfg WALConfig) *WALSink {
+	t.Helper()
+	if cfg.Path == "" {
+		cfg.Path = filepath.Join(t.TempDir(), "events.wal")
+	}
+	if cfg.ReplayInterval == 0 {
+		cfg.ReplayInterval = time.Hour // os testes chamam Replay
+	}
+	w, err := NewWALSink(sink, cfg, nil)
+	if err != nil {
+		t.Fatalf("NewWALSink: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestWALSink_WritesToWALOnSinkFailure(t *testing.T) {
+	sink := &flakySink{}
+	w := newTestWAL(t, sink, WALConfig{})
+	ctx := context.Background()
+
+	if err := w.WriteEvent(ctx, walEvent("e1")); err != nil || w.Pending() != 0 {
+		t.Fatalf("com o sink no ar: err = %v, pendente = %d", err, w.Pending())
+	}
+
+	sink.setDown(true)
+	for _, id := range []string{"e2", "e3"} {
+		if err := w.WriteEvent(ctx, walEvent(id)); err != nil {
+			t.Fatalf("WriteEvent(%s) = %v, esperado gravar no WAL", id, err)
+		}
+	}
+	if w.Pending() == 0 {
+		t.Fatal("WAL vazio depois das falhas do sink")
+	}
+	if err := w.Replay(ctx); err == nil {
+		t.Error("Replay com o sink fora do ar deveria falhar")
+	}
+
+	if got := sink.received(); len(got) != 1 || got[0] != "e1" {
+		t.Errorf("sink recebeu %v, esperado só e1", got)
+	}
+}
+
+func TestWALSink_ReplaysOnRecovery(t *testing.T) {
+	sink := &flakySink{down: true}
+	w := newTestWAL(t, sink, WALConfig{ReplayInterval: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	w.WriteEvent(ctx, walEvent("e1"))
+	w.WriteEvent(ctx, walEvent("e2"))
+	sink.setDown(false)
+
+	// Com o WAL pendente, novos eventos entram atrás dele, mantendo a ordem
+	w.WriteEvent(ctx, walEvent("e3"))
+	if got := sink.received(); len(got) != 0 {
+		t.Fatalf("sink recebeu %v antes do reenvio do WAL", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for w.Pending() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if w.Pending() != 0 {
+		t.Fatalf("WAL com %d bytes pendentes depois da volta do sink", w.Pending())
+	}
+	if got := sink.received(); fmt.Sprint(got) != "[e1 e2 e3]" {
+		t.Errorf("sink recebeu %v, esperado [e1 e2 e3]", got)
+	}
+
+	// WAL vazio: direto para o sink
+	w.WriteEvent(ctx, walEvent("e4"))
+	if got := sink.received(); len(got) != 4 {
+		t.Errorf("sink recebeu %v, esperado e4 direto", got)
+	}
+}
+
+func TestWALSink_PartialReplayKeepsRemainder(t *testing.T) {
+	var mu sync.Mutex
+	var accepted []string
+	var budget int // eventos que o sink ainda aceita
+	sink := EventSinkFunc(func(_ context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if budget == 0 {
+			return errors.New("broker unavailable")
+		}
+		budget--
+		accepted = append(accepted, event.Interaction.InteractionID)
+		return nil
+	})
+	w := newTestWAL(t, sink, WALConfig{})
+	ctx := context.Background()
+	for _, id := range []string{"e1", "e2", "e3"} {
+		w.WriteEvent(ctx, walEvent(id))
+	}
+
+	// O sink volta por um evento só
+	mu.Lock()
+	budget = 1
+	mu.Unlock()
+	if err := w.Replay(ctx); err == nil {
+		t.Fatal("Replay deveria falhar no segundo evento")
+	}
+
+	mu.Lock()
+	budget = 10
+	mu.Unlock()
+	if err := w.Replay(ctx); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if fmt.Sprint(accepted) != "[e1 e2 e3]" {
+		t.Errorf("aceitos %v, esperado cada evento uma vez, em ordem", accepted)
+	}
+}
+
+func TestWALSink_BoundedByMaxBytes(t *testing.T) {
+	sink := &flakySink{down: true}
+	w := newTestWAL(t, sink, WALConfig{MaxBytes: 300})
+	ctx := context.Background()
+
+	var err error
+	for i := 0; i < 10 && err == nil; i++ {
+		err = w.WriteEvent(ctx, walEvent(fmt.Sprintf("e%d", i)))
+	}
+	if !errors.Is(err, ErrWALFull) {
+		t.Fatalf("err = %v, esperado ErrWALFull", err)
+	}
+	if w.Pending() > 300 {
+		t.Errorf("WAL com %d bytes, limite 300", w.Pending())
+	}
+}
+
+func TestWALSink_ReplaysWALLeftByPreviousRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.wal")
+	ctx := context.Background()
+
+	down := &flakySink{down: true}
+	w, err := NewWALSink(down, WALConfig{Path: path, ReplayInterval: time.Hour}, nil)
+	if err != nil {
+		t.Fatalf("NewWALSink: %v", err)
+	}
+	w.WriteEvent(ctx, walEvent("e1"))
+	w.Close()
+
+	sink := &flakySink{}
+	w = newTestWAL(t, sink, WALConfig{Path: path})
+	if err := w.Replay(ctx); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if got := sink.received(); len(got) != 1 || got[0] != "e1" {
+		t.Errorf("sink recebeu %v, esperado e1 da execução anterior", got)
+	}
+}
+
+func TestObserver_EventSinkWithWAL(t *testing.T) {
+	sink := &flakySink{down: true}
+	w := newTestWAL(t, sink, WALConfig{})
+	o := newObserver(&config.Config{})
+	o.SetEventSink(w, nil)
+	ctx := context.Background()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	o.TrackInteraction(ctx, id, types.Interaction{InteractionID: "i1", Speaker: "customer", Content: "oi"})
+	o.TrackDecision(ctx, id, types.Decision{DecisionType: "transfer", Option: "sales"})
+	// Shutdown entrega os eventos ainda no canal
+	o.Shutdown(ctx)
+
+	sink.setDown(false)
+	if err := w.Replay(ctx); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	var got []string
+	for _, e := range sink.events {
+		got = append(got, e.Type)
+	}
+	if fmt.Sprint(got) != "[conversation.started interaction.customer decision.made]" {
+		t.Fatalf("sink recebeu %v", got)
+	}
+	if d := sink.events[2].Decision; d == nil || d.Option != "sales" {
+		t.Errorf("decisão reenviada = %+v", d)
+	}
+}
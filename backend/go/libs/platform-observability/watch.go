@@ -0,0 +1,164 @@
+package observability
+
+import (
+	"sync"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// DefaultSubscriptionBuffer é o tamanho padrão do buffer de uma assinatura
+const DefaultSubscriptionBuffer = 256
+
+// Event é um evento de conversação entregue aos assinantes em tempo real.
+// Apenas um entre Interaction e Decision vem preenchido.
+type Event struct {
+	Type           string                  `json:"type"` // conversation.started, interaction.*, decision.made, conversation.ended
+	ConversationID string                  `json:"conversation_id"`
+	TenantID       string                  `json:"tenant_id"`
+	AgentID        string                  `json:"agent_id"`
+	Timestamp      time.Time               `json:"timestamp"`
+	Interaction    *types.InteractionEvent `json:"interaction,omitempty"`
+	Decision       *types.DecisionEvent    `json:"decision,omitempty"`
+}
+
+// SubscribeOption configura uma assinatura
+type SubscribeOption func(*subscriber)
+
+// WithAgent entrega apenas eventos das conversações de um agente
+func WithAgent(agentID string) SubscribeOption {
+	return func(s *subscriber) { s.agentID = agentID }
+}
+
+// WithConversation entrega apenas eventos de uma conversação
+func WithConversation(conversationID string) SubscribeOption {
+	return func(s *subscriber) { s.conversationID = conversationID }
+}
+
+// WithBuffer define quantos eventos podem aguardar leitura na assinatura
+func WithBuffer(size int) SubscribeOption {
+	return func(s *subscriber) {
+		if size > 0 {
+			s.buffer = size
+		}
+	}
+}
+
+// subscriber é uma assinatura ativa de eventos de um tenant
+type subscriber struct {
+	tenantID       string
+	agentID        string
+	conversationID string
+	buffer         int
+	ch             chan Event
+}
+
+func (s *subscriber) matches(event Event) bool {
+	if event.TenantID != s.tenantID {
+		return false
+	}
+	if s.agentID != "" && event.AgentID != s.agentID {
+		return false
+	}
+	if s.conversationID != "" && event.ConversationID != s.conversationID {
+		return false
+	}
+	return true
+}
+
+// Subscribe assina os eventos das conversações ativas de um tenant
+func Subscribe(tenantID string, opts ...SubscribeOption) (<-chan Event, func()) {
+	return GetObserver().Subscribe(tenantID, opts...)
+}
+
+// Subscribe assina os eventos das conversações ativas de um tenant. Os
+// eventos chegam pelo canal retornado à medida que são rastreados; a função
+// retornada cancela a assinatura e fecha o canal, e pode ser chamada mais de
+// uma vez. O rastreamento nunca espera um assinante lento: com o buffer cheio,
+// o evento é descartado para essa assinatura.
+func (o *Observer) Subscribe(tenantID string, opts ...SubscribeOption) (<-chan Event, func()) {
+	s := &subscriber{tenantID: tenantID, buffer: DefaultSubscriptionBuffer}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.ch = make(chan Event, s.buffer)
+
+	o.subMu.Lock()
+	if o.subscribers == nil {
+		o.subscribers = make(map[*subscriber]struct{})
+	}
+	if o.subsClosed {
+		// Observador encerrado: a assinatura nasce fechada
+		close(s.ch)
+	} else {
+		o.subscribers[s] = struct{}{}
+	}
+	o.subMu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			o.subMu.Lock()
+			defer o.subMu.Unlock()
+
+			if _, ok := o.subscribers[s]; ok {
+				delete(o.subscribers, s)
+				close(s.ch)
+			}
+		})
+	}
+
+	return s.ch, unsubscribe
+}
+
+// SubscriberCount retorna o número de assinaturas ativas
+func (o *Observer) SubscriberCount() int {
+	o.subMu.RLock()
+	defer o.subMu.RUnlock()
+
+	return len(o.subscribers)
+}
+
+// emit envia o evento ao processador e às assinaturas interessadas
+func (o *Observer) emit(event any) {
+	if o.sampler.keep(event) {
+		o.eventChan <- event
+	}
+	o.broadcast(event)
+}
+
+// broadcast entrega o evento sem bloquear; o canal só é fechado com subMu
+// travado para escrita, então nunca recebe envio depois de fechado
+func (o *Observer) broadcast(raw any) {
+	event, ok := toEvent(raw)
+	if !ok {
+		return
+	}
+
+	o.subMu.RLock()
+	defer o.subMu.RUnlock()
+
+	for s := range o.subscribers {
+		if !s.matches(event) {
+			continue
+		}
+		select {
+		case s.ch <- event:
+		default:
+		}
+	}
+}
+
+// CloseSubscriptions fecha todas as assinaturas, atuais e futuras. Shutdown
+// já o faz; chamado antes, libera streams presos a um servidor em drenagem
+// enquanto o rastreamento continua.
+func (o *Observer) CloseSubscriptions() {
+	o.subMu.Lock()
+	defer o.subMu.Unlock()
+
+	for s := range o.subscribers {
+		delete(o.subscribers, s)
+		close(s.ch)
+	}
+	o.subsClosed = true
+}
@@ -0,0 +1,234 @@
+package observability
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+)
+
+// nextEvent lê um evento da assinatura ou falha após um tempo
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatal("assinatura fechada inesperadamente")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("nenhum evento recebido")
+	}
+	return Event{}
+}
+
+// assertNoEvent garante que a assinatura não recebeu nada
+func assertNoEvent(t *testing.T, events <-chan Event) {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		t.Fatalf("evento inesperado: %+v", event)
+	default:
+	}
+}
+
+func TestSubscribe_FanOutToMultipleSubscribers(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+
+	first, unsubscribeFirst := o.Subscribe("tenant-a")
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := o.Subscribe("tenant-a")
+	defer unsubscribeSecond()
+	other, unsubscribeOther := o.Subscribe("tenant-b")
+	defer unsubscribeOther()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", AgentID: "agent-1"})
+	o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer", Content: "Oi"})
+	o.TrackDecision(ctx, id, types.Decision{DecisionType: "offer", Option: "plano-b"})
+	o.EndConversation(ctx, id, types.ConversationEnd{Resolution: "solved"})
+
+	want := []string{"conversation.started", "interaction.customer", "decision.made", "conversation.ended"}
+	for _, events := range []<-chan Event{first, second} {
+		for _, eventType := range want {
+			event := nextEvent(t, events)
+			if event.Type != eventType {
+				t.Fatalf("evento = %s, esperado %s", event.Type, eventType)
+			}
+			if event.ConversationID != id || event.TenantID != "tenant-a" || event.AgentID != "agent-1" {
+				t.Errorf("evento com identificação errada: %+v", event)
+			}
+		}
+	}
+
+	assertNoEvent(t, other)
+}
+
+func TestSubscribe_EventPayload(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+
+	events, unsubscribe := o.Subscribe("tenant-a")
+	defer unsubscribe()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", AgentID: "agent-1"})
+	o.TrackInteraction(ctx, id, types.Interaction{Speaker: "agent", Content: "Como posso ajudar?"})
+	o.TrackDecision(ctx, id, types.Decision{DecisionType: "offer", Option: "plano-b"})
+
+	nextEvent(t, events)
+
+	interaction := nextEvent(t, events)
+	if interaction.Interaction == nil || interaction.Decision != nil {
+		t.Fatalf("esperado apenas Interaction, obtido %+v", interaction)
+	}
+	if interaction.Interaction.Content != "Como posso ajudar?" {
+		t.Errorf("Content = %q", interaction.Interaction.Content)
+	}
+
+	decision := nextEvent(t, events)
+	if decision.Decision == nil || decision.Interaction != nil {
+		t.Fatalf("esperado apenas Decision, obtido %+v", decision)
+	}
+	if decision.Decision.Option != "plano-b" {
+		t.Errorf("Option = %q", decision.Decision.Option)
+	}
+}
+
+func TestSubscribe_Filters(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+
+	byAgent, unsubscribeAgent := o.Subscribe("tenant-a", WithAgent("agent-2"))
+	defer unsubscribeAgent()
+
+	first := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", AgentID: "agent-1"})
+	second := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a", AgentID: "agent-2"})
+
+	byConversation, unsubscribeConversation := o.Subscribe("tenant-a", WithConversation(first))
+	defer unsubscribeConversation()
+
+	o.TrackInteraction(ctx, first, types.Interaction{Speaker: "customer", Content: "primeira"})
+	o.TrackInteraction(ctx, second, types.Interaction{Speaker: "customer", Content: "segunda"})
+
+	if event := nextEvent(t, byAgent); event.ConversationID != second || event.Type != "conversation.started" {
+		t.Errorf("filtro por agente recebeu %+v", event)
+	}
+	if event := nextEvent(t, byAgent); event.ConversationID != second || event.Interaction.Content != "segunda" {
+		t.Errorf("filtro por agente recebeu %+v", event)
+	}
+	assertNoEvent(t, byAgent)
+
+	if event := nextEvent(t, byConversation); event.ConversationID != first || event.Interaction.Content != "primeira" {
+		t.Errorf("filtro por conversação recebeu %+v", event)
+	}
+	assertNoEvent(t, byConversation)
+}
+
+func TestSubscribe_SlowSubscriberDoesNotBlockTracking(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+
+	events, unsubscribe := o.Subscribe("tenant-a", WithBuffer(1))
+	defer unsubscribe()
+
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer"})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("rastreamento bloqueado por assinante lento")
+	}
+
+	if event := nextEvent(t, events); event.Type != "conversation.started" {
+		t.Errorf("evento = %s, esperado o primeiro que coube no buffer", event.Type)
+	}
+	assertNoEvent(t, events)
+}
+
+func TestSubscribe_UnsubscribeClosesChannel(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+
+	events, unsubscribe := o.Subscribe("tenant-a")
+	if o.SubscriberCount() != 1 {
+		t.Fatalf("SubscriberCount = %d, esperado 1", o.SubscriberCount())
+	}
+
+	unsubscribe()
+	unsubscribe() // idempotente
+
+	if _, ok := <-events; ok {
+		t.Error("canal deveria estar fechado após cancelar a assinatura")
+	}
+	if o.SubscriberCount() != 0 {
+		t.Errorf("SubscriberCount = %d, esperado 0", o.SubscriberCount())
+	}
+
+	// Eventos após o cancelamento não podem causar panic
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+	o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer"})
+}
+
+func TestSubscribe_NoGoroutineLeak(t *testing.T) {
+	o := newTestObserver(t)
+	ctx := context.Background()
+	id := o.StartConversation(ctx, types.ConversationStart{TenantID: "tenant-a"})
+
+	before := runtime.NumGoroutine()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		events, unsubscribe := o.Subscribe("tenant-a")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range events {
+			}
+		}()
+		o.TrackInteraction(ctx, id, types.Interaction{Speaker: "customer"})
+		unsubscribe()
+	}
+	wg.Wait()
+
+	if o.SubscriberCount() != 0 {
+		t.Errorf("SubscriberCount = %d, esperado 0", o.SubscriberCount())
+	}
+
+	// Dá tempo ao runtime para recolher as goroutines encerradas
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines = %d, esperado no máximo %d", after, before)
+	}
+}
+
+func TestSubscribe_ShutdownClosesSubscriptions(t *testing.T) {
+	o := newObserver(nil)
+
+	events, unsubscribe := o.Subscribe("tenant-a")
+	o.Shutdown(context.Background())
+	unsubscribe()
+
+	if _, ok := <-events; ok {
+		t.Error("canal deveria estar fechado após Shutdown")
+	}
+
+	late, _ := o.Subscribe("tenant-a")
+	if _, ok := <-late; ok {
+		t.Error("assinatura após Shutdown deveria nascer fechada")
+	}
+}
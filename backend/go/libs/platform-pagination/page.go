@@ -0,0 +1,127 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Page é o envelope de resposta de uma listagem paginada.
+// Page e TotalPages só são preenchidos em paginação por offset; NextCursor
+// aponta para a próxima página e fica vazio na última.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PageSize   int    `json:"page_size"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage monta uma página por offset a partir dos itens e do total
+func NewPage[T any](items []T, total int64, req Request) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	return Page[T]{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PageSize:   req.PageSize,
+		TotalPages: TotalPages(total, req.PageSize),
+	}
+}
+
+// NewCursorPage monta uma página por keyset. items deve ter sido buscado com
+// limite PageSize+1 (veja KeysetLimit): o item excedente indica que há uma
+// próxima página e é descartado. cursorOf extrai o cursor do último item.
+func NewCursorPage[T any](items []T, total int64, req Request, cursorOf func(T) Cursor) (Page[T], error) {
+	if items == nil {
+		items = []T{}
+	}
+	p := Page[T]{Items: items, Total: total, PageSize: req.PageSize}
+
+	if req.PageSize > 0 && len(items) > req.PageSize {
+		p.Items = items[:req.PageSize]
+		next, err := cursorOf(p.Items[len(p.Items)-1]).Encode()
+		if err != nil {
+			return Page[T]{}, err
+		}
+		p.NextCursor = next
+	}
+
+	return p, nil
+}
+
+// WithCursor preenche NextCursor de uma página por offset a partir do último
+// item, permitindo ao cliente continuar por keyset. Na última página não há cursor.
+func (p Page[T]) WithCursor(cursorOf func(T) Cursor) (Page[T], error) {
+	if len(p.Items) == 0 || p.Page >= p.TotalPages {
+		return p, nil
+	}
+	next, err := cursorOf(p.Items[len(p.Items)-1]).Encode()
+	if err != nil {
+		return Page[T]{}, err
+	}
+	p.NextCursor = next
+	return p, nil
+}
+
+// Map converte os itens da página mantendo os metadados de paginação
+func Map[T, U any](p Page[T], f func(T) U) Page[U] {
+	items := make([]U, len(p.Items))
+	for i, item := range p.Items {
+		items[i] = f(item)
+	}
+	return Page[U]{
+		Items:      items,
+		Total:      p.Total,
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+		TotalPages: p.TotalPages,
+		NextCursor: p.NextCursor,
+	}
+}
+
+// TotalPages calcula o número de páginas para o total e o tamanho de página
+func TotalPages(total int64, pageSize int) int {
+	if total <= 0 || pageSize <= 0 {
+		return 0
+	}
+	return int((total + int64(pageSize) - 1) / int64(pageSize))
+}
+
+// Cursor identifica a posição do último item de uma página na ordenação.
+// Sort guarda a coluna ordenada para recusar cursores reutilizados com outra
+// ordenação; ID desempata itens com o mesmo valor.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// Encode serializa o cursor em base64 URL-safe, opaco para o cliente
+func (c Cursor) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor desserializa um cursor gerado por Encode
+func DecodeCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+	return c, nil
+}
@@ -0,0 +1,238 @@
+package pagination
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+var tenantSort = Sort{
+	Columns: map[string]string{
+		"name":       "name",
+		"created_at": "created_at",
+	},
+	Default:    "created_at",
+	Tiebreaker: "id",
+}
+
+func TestParseRequest_Defaults(t *testing.T) {
+	req, err := ParseRequest(url.Values{}, Options{DefaultSort: "created_at"})
+	if err != nil {
+		t.Fatalf("ParseRequest falhou: %v", err)
+	}
+	if req.Page != 1 || req.PageSize != DefaultPageSize || req.SortBy != "created_at" || req.SortOrder != OrderDesc {
+		t.Errorf("Request padrão inesperado: %+v", req)
+	}
+	if req.IsCursor() || req.Offset() != 0 {
+		t.Errorf("Request padrão deveria ser offset na primeira página: %+v", req)
+	}
+}
+
+func TestParseRequest_OversizedPageSize(t *testing.T) {
+	req, err := ParseRequest(url.Values{"page_size": {"5000"}}, Options{})
+	if err != nil {
+		t.Fatalf("ParseRequest falhou: %v", err)
+	}
+	if req.PageSize != MaxPageSize {
+		t.Errorf("page_size = %d, esperado %d", req.PageSize, MaxPageSize)
+	}
+
+	req, err = ParseRequest(url.Values{"page_size": {"80"}}, Options{MaxPageSize: 50})
+	if err != nil {
+		t.Fatalf("ParseRequest falhou: %v", err)
+	}
+	if req.PageSize != 50 {
+		t.Errorf("page_size = %d, esperado o máximo configurado 50", req.PageSize)
+	}
+}
+
+func TestParseRequest_Invalid(t *testing.T) {
+	tests := []url.Values{
+		{"page": {"0"}},
+		{"page": {"abc"}},
+		{"page_size": {"-1"}},
+		{"sort_order": {"sideways"}},
+		{"page": {"2"}, "cursor": {"abc"}},
+	}
+
+	for _, q := range tests {
+		if _, err := ParseRequest(q, Options{}); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("ParseRequest(%v) erro = %v, esperado ErrInvalidRequest", q, err)
+		}
+	}
+}
+
+func TestParseRequest_Offset(t *testing.T) {
+	req, err := ParseRequest(url.Values{"page": {"3"}, "page_size": {"10"}, "sort_order": {"ASC"}}, Options{})
+	if err != nil {
+		t.Fatalf("ParseRequest falhou: %v", err)
+	}
+	if req.Offset() != 20 || req.SortOrder != OrderAsc {
+		t.Errorf("Request inesperado: %+v", req)
+	}
+}
+
+func TestNewPage_Empty(t *testing.T) {
+	p := NewPage[string](nil, 0, Request{Page: 1, PageSize: 20})
+	if p.Items == nil || len(p.Items) != 0 {
+		t.Error("Página vazia deveria ter Items não nulo")
+	}
+	if p.TotalPages != 0 || p.Total != 0 {
+		t.Errorf("Página vazia inesperada: %+v", p)
+	}
+}
+
+func TestNewPage_LastPage(t *testing.T) {
+	p := NewPage([]int{41, 42}, 42, Request{Page: 3, PageSize: 20})
+	if p.TotalPages != 3 || p.Page != 3 || len(p.Items) != 2 {
+		t.Errorf("Última página inesperada: %+v", p)
+	}
+	if p.NextCursor != "" {
+		t.Error("Paginação por offset não deveria ter cursor")
+	}
+}
+
+func TestPage_WithCursor(t *testing.T) {
+	req := Request{Page: 1, PageSize: 2, SortBy: "name"}
+	cursorOf := func(s string) Cursor { return tenantSort.CursorFor(req, s, s) }
+
+	p, err := NewPage([]string{"a", "b"}, 3, req).WithCursor(cursorOf)
+	if err != nil {
+		t.Fatalf("WithCursor falhou: %v", err)
+	}
+	if p.NextCursor == "" {
+		t.Error("Página com sucessora deveria ter cursor")
+	}
+
+	req.Page = 2
+	p, err = NewPage([]string{"c"}, 3, req).WithCursor(cursorOf)
+	if err != nil || p.NextCursor != "" {
+		t.Errorf("Última página não deveria ter cursor: %+v, %v", p, err)
+	}
+}
+
+func TestTotalPages(t *testing.T) {
+	tests := []struct {
+		total    int64
+		size     int
+		expected int
+	}{
+		{0, 20, 0},
+		{1, 20, 1},
+		{20, 20, 1},
+		{21, 20, 2},
+		{10, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := TotalPages(tt.total, tt.size); got != tt.expected {
+			t.Errorf("TotalPages(%d, %d) = %d, esperado %d", tt.total, tt.size, got, tt.expected)
+		}
+	}
+}
+
+func TestNewCursorPage(t *testing.T) {
+	req := Request{PageSize: 2, SortBy: "name"}
+	cursorOf := func(s string) Cursor { return tenantSort.CursorFor(req, s, "id-"+s) }
+
+	// Um item a mais que o tamanho da página: há próxima página
+	p, err := NewCursorPage([]string{"a", "b", "c"}, 3, req, cursorOf)
+	if err != nil {
+		t.Fatalf("NewCursorPage falhou: %v", err)
+	}
+	if len(p.Items) != 2 || p.NextCursor == "" {
+		t.Fatalf("Esperado 2 itens e cursor, obtido %+v", p)
+	}
+	c, err := DecodeCursor(p.NextCursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor falhou: %v", err)
+	}
+	if c.Value != "b" || c.ID != "id-b" || c.Sort != "name" {
+		t.Errorf("Cursor inesperado: %+v", c)
+	}
+
+	// Última página: sem cursor
+	p, err = NewCursorPage([]string{"c"}, 3, req, cursorOf)
+	if err != nil {
+		t.Fatalf("NewCursorPage falhou: %v", err)
+	}
+	if len(p.Items) != 1 || p.NextCursor != "" {
+		t.Errorf("Última página não deveria ter cursor: %+v", p)
+	}
+
+	// Vazia
+	p, err = NewCursorPage[string](nil, 0, req, cursorOf)
+	if err != nil || p.Items == nil || p.NextCursor != "" {
+		t.Errorf("Página vazia inesperada: %+v, %v", p, err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	p := Map(NewPage([]int{1, 2}, 2, Request{Page: 1, PageSize: 10}), func(i int) string {
+		return strings.Repeat("x", i)
+	})
+	if p.Items[1] != "xx" || p.Total != 2 || p.TotalPages != 1 {
+		t.Errorf("Map inesperado: %+v", p)
+	}
+}
+
+func TestSort_OrderByAllowlist(t *testing.T) {
+	tests := []struct {
+		req      Request
+		expected string
+	}{
+		{Request{SortBy: "name", SortOrder: OrderAsc}, "name ASC, id ASC"},
+		{Request{SortBy: "created_at"}, "created_at DESC, id DESC"},
+		{Request{SortBy: "name; DROP TABLE tenants"}, "created_at DESC, id DESC"},
+	}
+
+	for _, tt := range tests {
+		if got := tenantSort.OrderBy(tt.req); got != tt.expected {
+			t.Errorf("OrderBy(%+v) = %q, esperado %q", tt.req, got, tt.expected)
+		}
+	}
+}
+
+func TestOffsetClause(t *testing.T) {
+	clause, args := OffsetClause(Request{Page: 2, PageSize: 25}, 3)
+	if clause != "LIMIT $3 OFFSET $4" {
+		t.Errorf("Cláusula inesperada: %q", clause)
+	}
+	if args[0] != 25 || args[1] != 25 {
+		t.Errorf("Argumentos inesperados: %v", args)
+	}
+}
+
+func TestSort_Keyset(t *testing.T) {
+	cursor, _ := tenantSort.CursorFor(Request{SortBy: "name"}, "acme", "42").Encode()
+
+	condition, args, err := tenantSort.Keyset(Request{SortBy: "name", SortOrder: OrderAsc, Cursor: cursor}, 2)
+	if err != nil {
+		t.Fatalf("Keyset falhou: %v", err)
+	}
+	if condition != "(name, id) > ($2, $3)" {
+		t.Errorf("Condição inesperada: %q", condition)
+	}
+	if args[0] != "acme" || args[1] != "42" {
+		t.Errorf("Argumentos inesperados: %v", args)
+	}
+
+	limit, limitArgs := KeysetLimit(Request{PageSize: 10}, 4)
+	if limit != "LIMIT $4" || limitArgs[0] != 11 {
+		t.Errorf("Limite inesperado: %q %v", limit, limitArgs)
+	}
+}
+
+func TestSort_KeysetRejectsCursor(t *testing.T) {
+	cursor, _ := tenantSort.CursorFor(Request{SortBy: "name"}, "acme", "42").Encode()
+
+	// Cursor gerado com outra ordenação
+	if _, _, err := tenantSort.Keyset(Request{SortBy: "created_at", Cursor: cursor}, 1); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Esperado ErrInvalidRequest para cursor de outra ordenação, obtido %v", err)
+	}
+	// Cursor adulterado
+	if _, _, err := tenantSort.Keyset(Request{Cursor: "not-a-cursor!"}, 1); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Esperado ErrInvalidRequest para cursor malformado, obtido %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package pagination padroniza a paginação das APIs do Serphona: leitura dos
+// parâmetros da requisição (page/page_size ou cursor), o envelope de resposta
+// Page[T] e helpers SQL para paginação por offset e por keyset.
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPageSize é o tamanho de página usado quando page_size não é informado
+	DefaultPageSize = 20
+	// MaxPageSize é o maior tamanho de página aceito por padrão
+	MaxPageSize = 100
+)
+
+// ErrInvalidRequest indica parâmetros de paginação inválidos
+var ErrInvalidRequest = errors.New("invalid pagination request")
+
+// Order representa a direção da ordenação
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// Request representa os parâmetros de paginação de uma requisição.
+// Quando Cursor está preenchido a paginação é por keyset e Page é ignorado.
+type Request struct {
+	Page      int
+	PageSize  int
+	Cursor    string
+	SortBy    string
+	SortOrder Order
+}
+
+// Options configura a leitura dos parâmetros de paginação
+type Options struct {
+	DefaultPageSize int    // padrão DefaultPageSize
+	MaxPageSize     int    // padrão MaxPageSize; valores maiores são reduzidos a ele
+	DefaultSort     string // nome de ordenação usado quando sort_by é vazio
+	DefaultOrder    Order  // padrão OrderDesc
+}
+
+// withDefaults preenche as opções não informadas
+func (o Options) withDefaults() Options {
+	if o.DefaultPageSize <= 0 {
+		o.DefaultPageSize = DefaultPageSize
+	}
+	if o.MaxPageSize <= 0 {
+		o.MaxPageSize = MaxPageSize
+	}
+	if o.DefaultPageSize > o.MaxPageSize {
+		o.DefaultPageSize = o.MaxPageSize
+	}
+	if o.DefaultOrder == "" {
+		o.DefaultOrder = OrderDesc
+	}
+	return o
+}
+
+// ParseRequest lê page, page_size, cursor, sort_by e sort_order da query string.
+// page_size acima do máximo é reduzido ao máximo; valores não numéricos ou
+// menores que 1, ordem desconhecida ou page e cursor juntos retornam
+// ErrInvalidRequest.
+func ParseRequest(query url.Values, opts Options) (Request, error) {
+	opts = opts.withDefaults()
+
+	req := Request{
+		Page:      1,
+		PageSize:  opts.DefaultPageSize,
+		Cursor:    strings.TrimSpace(query.Get("cursor")),
+		SortBy:    strings.TrimSpace(query.Get("sort_by")),
+		SortOrder: opts.DefaultOrder,
+	}
+
+	if v := query.Get("page"); v != "" {
+		if req.Cursor != "" {
+			return Request{}, fmt.Errorf("%w: page and cursor are mutually exclusive", ErrInvalidRequest)
+		}
+		page, err := parsePositive(v)
+		if err != nil {
+			return Request{}, fmt.Errorf("%w: page %s", ErrInvalidRequest, err)
+		}
+		req.Page = page
+	}
+
+	if v := query.Get("page_size"); v != "" {
+		size, err := parsePositive(v)
+		if err != nil {
+			return Request{}, fmt.Errorf("%w: page_size %s", ErrInvalidRequest, err)
+		}
+		req.PageSize = size
+	}
+	if req.PageSize > opts.MaxPageSize {
+		req.PageSize = opts.MaxPageSize
+	}
+
+	if req.SortBy == "" {
+		req.SortBy = opts.DefaultSort
+	}
+
+	if v := query.Get("sort_order"); v != "" {
+		order, err := ParseOrder(v)
+		if err != nil {
+			return Request{}, err
+		}
+		req.SortOrder = order
+	}
+
+	return req, nil
+}
+
+// ParseOrder converte uma string na direção de ordenação (case-insensitive)
+func ParseOrder(s string) (Order, error) {
+	switch Order(strings.ToLower(strings.TrimSpace(s))) {
+	case OrderAsc:
+		return OrderAsc, nil
+	case OrderDesc:
+		return OrderDesc, nil
+	default:
+		return "", fmt.Errorf("%w: sort_order must be asc or desc", ErrInvalidRequest)
+	}
+}
+
+// IsCursor indica se a requisição usa paginação por keyset
+func (r Request) IsCursor() bool {
+	return r.Cursor != ""
+}
+
+// Offset retorna quantos itens pular na paginação por offset
+func (r Request) Offset() int {
+	if r.Page <= 1 {
+		return 0
+	}
+	return (r.Page - 1) * r.PageSize
+}
+
+// parsePositive converte um inteiro maior que zero
+func parsePositive(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, errors.New("must be a number")
+	}
+	if n < 1 {
+		return 0, errors.New("must be greater than 0")
+	}
+	return n, nil
+}
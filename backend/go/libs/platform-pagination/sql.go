@@ -0,0 +1,88 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sort define as colunas pelas quais uma listagem pode ser ordenada.
+// Apenas nomes presentes em Columns chegam ao SQL, evitando SQL injection via
+// sort_by. Os placeholders gerados seguem o formato do PostgreSQL ($1, $2...).
+type Sort struct {
+	Columns    map[string]string // nome público -> coluna SQL
+	Default    string            // nome público usado quando sort_by é vazio ou desconhecido
+	Tiebreaker string            // coluna única usada para desempate, ex.: "id"
+}
+
+// Column retorna a coluna SQL permitida para o nome, ou a coluna padrão
+func (s Sort) Column(name string) string {
+	if col, ok := s.Columns[name]; ok {
+		return col
+	}
+	return s.Columns[s.Default]
+}
+
+// name retorna o nome público efetivo da ordenação
+func (s Sort) name(name string) string {
+	if _, ok := s.Columns[name]; ok {
+		return name
+	}
+	return s.Default
+}
+
+// OrderBy monta a cláusula ORDER BY (sem a palavra-chave) para a requisição
+func (s Sort) OrderBy(req Request) string {
+	dir := direction(req.SortOrder)
+	order := s.Column(req.SortBy) + " " + dir
+	if s.Tiebreaker != "" {
+		order += ", " + s.Tiebreaker + " " + dir
+	}
+	return order
+}
+
+// Keyset monta a condição WHERE que posiciona a consulta após o cursor,
+// ex.: "(created_at, id) < ($3, $4)". argIndex é o próximo placeholder livre.
+func (s Sort) Keyset(req Request, argIndex int) (string, []interface{}, error) {
+	c, err := DecodeCursor(req.Cursor)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.Sort != s.name(req.SortBy) {
+		return "", nil, fmt.Errorf("%w: cursor does not match sort_by", ErrInvalidRequest)
+	}
+	if s.Tiebreaker == "" {
+		return "", nil, errors.New("keyset pagination requires a tiebreaker column")
+	}
+
+	op := "<"
+	if req.SortOrder == OrderAsc {
+		op = ">"
+	}
+
+	condition := fmt.Sprintf("(%s, %s) %s ($%d, $%d)", s.Column(req.SortBy), s.Tiebreaker, op, argIndex, argIndex+1)
+	return condition, []interface{}{c.Value, c.ID}, nil
+}
+
+// CursorFor monta o cursor de um item a partir do valor da coluna ordenada e do ID
+func (s Sort) CursorFor(req Request, value, id string) Cursor {
+	return Cursor{Sort: s.name(req.SortBy), Value: value, ID: id}
+}
+
+// OffsetClause monta "LIMIT $n OFFSET $n+1" e os argumentos correspondentes
+func OffsetClause(req Request, argIndex int) (string, []interface{}) {
+	return fmt.Sprintf("LIMIT $%d OFFSET $%d", argIndex, argIndex+1), []interface{}{req.PageSize, req.Offset()}
+}
+
+// KeysetLimit monta "LIMIT $n" buscando um item a mais para detectar a próxima página
+func KeysetLimit(req Request, argIndex int) (string, []interface{}) {
+	return fmt.Sprintf("LIMIT $%d", argIndex), []interface{}{req.PageSize + 1}
+}
+
+// direction converte a ordem na palavra-chave SQL, DESC por padrão
+func direction(o Order) string {
+	if strings.EqualFold(string(o), string(OrderAsc)) {
+		return "ASC"
+	}
+	return "DESC"
+}
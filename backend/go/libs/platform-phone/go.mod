@@ -0,0 +1,10 @@
+module github.com/serphona/serphona/backend/go/libs/platform-phone
+
+go 1.21
+
+require github.com/nyaruka/phonenumbers v1.4.0
+
+require (
+	golang.org/x/text v0.15.0 // indirect
+	google.golang.org/protobuf v1.34.1 // indirect
+)
@@ -0,0 +1,162 @@
+// Package phone normaliza e valida números de telefone no formato E.164
+// (+<código do país><número nacional>). É usado onde números entram na
+// plataforma, como o voice-gateway ao receber chamadas e o tenant-manager ao
+// registrar DIDs, para que o mesmo número seja sempre gravado e comparado
+// da mesma forma.
+//
+// As regras de cada país (prefixos, tamanhos, planos de numeração e códigos
+// compartilhados) vêm dos metadados do libphonenumber, pelo port
+// github.com/nyaruka/phonenumbers.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nyaruka/phonenumbers"
+)
+
+var (
+	// ErrInvalid indica um número que não pode ser normalizado para E.164
+	ErrInvalid = errors.New("invalid phone number")
+	// ErrUnknownRegion indica uma região padrão que a biblioteca não conhece
+	ErrUnknownRegion = errors.New("unknown phone region")
+)
+
+// Normalize converte raw para E.164.
+//
+// Números com "+" ou com o prefixo internacional "00" são lidos como
+// internacionais. Os demais são lidos no formato nacional de region (código
+// ISO 3166-1 alfa-2, como "BR"): o prefixo de longa distância, o prefixo
+// internacional da região ("011" nos EUA) e, no Brasil, o código da
+// operadora são tratados. Um número nacional que já começa com o código do
+// país de region também é aceito. Com region vazio, só números
+// internacionais são aceitos.
+//
+// Espaços, hífens, pontos, barras e parênteses são ignorados; letras e
+// ramais não são aceitos.
+func Normalize(raw, region string) (string, error) {
+	digits, international, err := clean(raw)
+	if err != nil {
+		return "", err
+	}
+
+	defaultRegion := phonenumbers.UNKNOWN_REGION
+	if international {
+		digits = "+" + digits
+	} else {
+		if region == "" {
+			return "", fmt.Errorf("%w: %q has no country code and no region was given", ErrInvalid, raw)
+		}
+		defaultRegion = strings.ToUpper(strings.TrimSpace(region))
+		if _, ok := CallingCode(defaultRegion); !ok {
+			return "", fmt.Errorf("%w: %q", ErrUnknownRegion, region)
+		}
+	}
+
+	number, err := parse(digits, defaultRegion)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q %s", ErrInvalid, raw, err)
+	}
+	return phonenumbers.Format(number, phonenumbers.E164), nil
+}
+
+// Valid informa se raw pode ser normalizado com Normalize
+func Valid(raw, region string) bool {
+	_, err := Normalize(raw, region)
+	return err == nil
+}
+
+// Country retorna a região (ISO 3166-1 alfa-2) de um número já em E.164.
+// Códigos compartilhados são resolvidos pelo número: no +1, o código de área
+// separa EUA, Canadá e Caribe; no +7, Rússia e Cazaquistão.
+func Country(e164 string) (string, error) {
+	if !strings.HasPrefix(e164, "+") || !allDigits(e164[1:]) {
+		return "", fmt.Errorf("%w: %q is not in E.164", ErrInvalid, e164)
+	}
+	number, err := parse(e164, phonenumbers.UNKNOWN_REGION)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q %s", ErrInvalid, e164, err)
+	}
+	return phonenumbers.GetRegionCodeForNumber(number), nil
+}
+
+// CallingCode retorna o código de país de region, sem o "+"
+func CallingCode(region string) (string, bool) {
+	code := phonenumbers.GetCountryCodeForRegion(strings.ToUpper(strings.TrimSpace(region)))
+	if code == 0 {
+		return "", false
+	}
+	return strconv.Itoa(code), true
+}
+
+// clean remove a formatação de raw e informa se ele foi escrito no formato
+// internacional.
+func clean(raw string) (digits string, international bool, err error) {
+	s := strings.TrimSpace(raw)
+	// "+44 (0)20 ..." marca o prefixo nacional que não se disca de fora
+	s = strings.Replace(s, "(0)", "", 1)
+
+	var b strings.Builder
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case c == '+' && i == 0:
+			international = true
+		case strings.ContainsRune(" \t-./()", c):
+		default:
+			return "", false, fmt.Errorf("%w: %q contains the character %q", ErrInvalid, raw, c)
+		}
+	}
+
+	digits = b.String()
+	if digits == "" {
+		return "", false, fmt.Errorf("%w: %q contains no digits", ErrInvalid, raw)
+	}
+	if !international && strings.HasPrefix(digits, "00") {
+		digits, international = digits[2:], true
+	}
+	return digits, international, nil
+}
+
+// parse lê e valida um número só com dígitos, com "+" quando internacional.
+// O erro completa uma frase que começa pelo número.
+func parse(digits, defaultRegion string) (*phonenumbers.PhoneNumber, error) {
+	number, err := phonenumbers.Parse(digits, defaultRegion)
+	if err != nil {
+		return nil, errors.New("is not a phone number")
+	}
+
+	code := int(number.GetCountryCode())
+	switch phonenumbers.GetRegionCodeForCountryCode(code) {
+	case phonenumbers.UNKNOWN_REGION, phonenumbers.REGION_CODE_FOR_NON_GEO_ENTITY:
+		// Redes globais, satélite e serviços internacionais (+800, +882...)
+		// não são de nenhum país
+		return nil, errors.New("does not start with a known country code")
+	}
+
+	switch phonenumbers.IsPossibleNumberWithReason(number) {
+	case phonenumbers.TOO_SHORT:
+		return nil, fmt.Errorf("is too short for a +%d number", code)
+	case phonenumbers.TOO_LONG:
+		return nil, fmt.Errorf("is too long for a +%d number", code)
+	case phonenumbers.INVALID_LENGTH:
+		return nil, fmt.Errorf("has the wrong length for a +%d number", code)
+	}
+	if !phonenumbers.IsValidNumber(number) {
+		return nil, fmt.Errorf("is not a valid +%d number", code)
+	}
+	return number, nil
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,144 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize_NationalFormats(t *testing.T) {
+	tests := []struct {
+		raw      string
+		region   string
+		expected string
+	}{
+		{"(11) 99999-8888", "BR", "+5511999998888"},
+		{"11 3333-4444", "BR", "+551133334444"},
+		{"011 99999 8888", "BR", "+5511999998888"},
+		{"0 21 11 99999-8888", "BR", "+5511999998888"},
+		{"55 99999-8888", "BR", "+5555999998888"},
+		{"5511999998888", "BR", "+5511999998888"},
+		{"(415) 555-0100", "US", "+14155550100"},
+		{"1-415-555-0100", "us", "+14155550100"},
+		{"415.555.0100", "US", "+14155550100"},
+		{"011 44 20 7946 0000", "US", "+442079460000"},
+		{"020 7946 0000", "GB", "+442079460000"},
+		{"06 1234 5678", "IT", "+390612345678"},
+		{"912 345 678", "PT", "+351912345678"},
+		{"8 (916) 123-45-67", "RU", "+79161234567"},
+		{"00 55 11 99999 8888", "PT", "+5511999998888"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region+"/"+tt.raw, func(t *testing.T) {
+			got, err := Normalize(tt.raw, tt.region)
+			if err != nil {
+				t.Fatalf("Normalize(%q, %q) retornou erro: %v", tt.raw, tt.region, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Normalize(%q, %q) = %q, esperado %q", tt.raw, tt.region, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalize_InternationalFormats(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"+55 11 99999-8888", "+5511999998888"},
+		{"+5511999998888", "+5511999998888"},
+		{"+1 (415) 555-0100", "+14155550100"},
+		{"+44 (0)20 7946 0000", "+442079460000"},
+		{"0044 20 7946 0000", "+442079460000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := Normalize(tt.raw, "")
+			if err != nil {
+				t.Fatalf("Normalize(%q) retornou erro: %v", tt.raw, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Normalize(%q) = %q, esperado %q", tt.raw, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		region string
+	}{
+		{"vazio", "", "BR"},
+		{"letras", "0800-FLOWERS", "US"},
+		{"ramal", "+55 11 3333-4444 x12", ""},
+		{"nacional sem região", "(11) 99999-8888", ""},
+		{"curto demais", "+55 11 9999", ""},
+		{"longo demais", "+55 11 99999 8888 7777", ""},
+		{"código de país desconhecido", "+999 1234 5678", ""},
+		{"rede global", "+882 1234 5678", ""},
+		{"código de área NANP com 0", "+1 015 555 0100", ""},
+		{"tamanho fixo", "+351 91234567", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.raw, tt.region)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Normalize(%q, %q) = %q, %v, esperado ErrInvalid", tt.raw, tt.region, got, err)
+			}
+		})
+	}
+}
+
+func TestNormalize_UnknownRegion(t *testing.T) {
+	if _, err := Normalize("99999-8888", "ZZ"); !errors.Is(err, ErrUnknownRegion) {
+		t.Errorf("erro = %v, esperado ErrUnknownRegion", err)
+	}
+}
+
+func TestCountry(t *testing.T) {
+	tests := []struct {
+		e164     string
+		expected string
+	}{
+		{"+5511999998888", "BR"},
+		{"+14155550100", "US"},
+		{"+14165550100", "CA"},
+		{"+18765550100", "JM"},
+		{"+442079460000", "GB"},
+		{"+79161234567", "RU"},
+		{"+77011234567", "KZ"},
+		{"+351912345678", "PT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.e164, func(t *testing.T) {
+			got, err := Country(tt.e164)
+			if err != nil {
+				t.Fatalf("Country(%q) retornou erro: %v", tt.e164, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Country(%q) = %q, esperado %q", tt.e164, got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := Country("11 99999-8888"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Country de número fora de E.164: erro = %v, esperado ErrInvalid", err)
+	}
+}
+
+func TestCallingCode(t *testing.T) {
+	for region, expected := range map[string]string{"BR": "55", "us": "1", "JM": "1", "KZ": "7"} {
+		if got, ok := CallingCode(region); !ok || got != expected {
+			t.Errorf("CallingCode(%q) = %q, %v, esperado %q", region, got, ok, expected)
+		}
+	}
+	if _, ok := CallingCode("ZZ"); ok {
+		t.Error("CallingCode(\"ZZ\") deveria falhar")
+	}
+}
@@ -0,0 +1,62 @@
+// Package ginratelimit adapta o Enforcer de platform-ratelimit para Gin, com
+// chaves por IP, tenant e usuário.
+package ginratelimit
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	ratelimit "github.com/serphona/serphona/backend/go/libs/platform-ratelimit"
+)
+
+// KeyFunc extrai a chave de limitação do contexto Gin. Chave vazia não é
+// limitada.
+type KeyFunc func(c *gin.Context) string
+
+// ByIP chaveia por c.ClientIP(), que respeita os proxies confiáveis
+// configurados no engine
+func ByIP() KeyFunc {
+	return func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	}
+}
+
+// ByTenant chaveia pelo tenantID injetado pelo middleware RequireAuth de
+// platform-auth; deve ser registrado depois dele
+func ByTenant() KeyFunc {
+	return byContextString("tenantID", "tenant:")
+}
+
+// ByUser chaveia pelo userID injetado pelo middleware RequireAuth de
+// platform-auth; deve ser registrado depois dele
+func ByUser() KeyFunc {
+	return byContextString("userID", "user:")
+}
+
+func byContextString(ctxKey, prefix string) KeyFunc {
+	return func(c *gin.Context) string {
+		value := c.GetString(ctxKey)
+		if value == "" {
+			return ""
+		}
+		return prefix + value
+	}
+}
+
+// Middleware limita as requisições por key, abortando com 429 e Retry-After
+// quando o limite é excedido e com 503 quando o Limiter falha em fail-closed
+func Middleware(e *ratelimit.Enforcer, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := e.Check(c.Request.Context(), key(c))
+		ratelimit.WriteHeaders(c.Writer.Header(), res)
+
+		switch {
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, ratelimit.ErrorBody(err))
+		case !res.Allowed:
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, ratelimit.ErrorBody(nil))
+		default:
+			c.Next()
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package ratelimit implementa limitação de taxa compartilhada entre réplicas:
+// um Limiter com janela deslizante em Redis, taxas de rajada e sustentada e
+// middlewares HTTP que chaveiam por IP, tenant ou usuário.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rate é um limite de requisições por janela
+type Rate struct {
+	Limit  int
+	Window time.Duration
+}
+
+// PerSecond cria uma Rate de n requisições por segundo
+func PerSecond(n int) Rate { return Rate{Limit: n, Window: time.Second} }
+
+// PerMinute cria uma Rate de n requisições por minuto
+func PerMinute(n int) Rate { return Rate{Limit: n, Window: time.Minute} }
+
+// PerHour cria uma Rate de n requisições por hora
+func PerHour(n int) Rate { return Rate{Limit: n, Window: time.Hour} }
+
+// String formata a Rate como "100/1m0s"
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.Limit, r.Window)
+}
+
+// Policy combina uma taxa sustentada com uma taxa de rajada opcional. A
+// requisição só passa se estiver dentro das duas: a rajada limita picos curtos
+// (ex.: 20/s) e a sustentada o volume ao longo do tempo (ex.: 600/min).
+type Policy struct {
+	Sustained Rate
+	Burst     Rate
+}
+
+// ErrInvalidPolicy indica uma Policy mal configurada
+var ErrInvalidPolicy = errors.New("ratelimit: invalid policy")
+
+// Validate verifica se as taxas têm limite e janela positivos. Burst é
+// opcional (zero desabilita), mas quando presente precisa de janela menor que
+// a sustentada.
+func (p Policy) Validate() error {
+	if p.Sustained.Limit <= 0 || p.Sustained.Window < time.Millisecond {
+		return fmt.Errorf("%w: sustained rate must be positive", ErrInvalidPolicy)
+	}
+
+	if p.Burst == (Rate{}) {
+		return nil
+	}
+
+	if p.Burst.Limit <= 0 || p.Burst.Window < time.Millisecond {
+		return fmt.Errorf("%w: burst rate must be positive", ErrInvalidPolicy)
+	}
+
+	if p.Burst.Window >= p.Sustained.Window {
+		return fmt.Errorf("%w: burst window must be shorter than sustained window", ErrInvalidPolicy)
+	}
+
+	return nil
+}
+
+// rates retorna as taxas ativas, rajada primeiro
+func (p Policy) rates() []Rate {
+	if p.Burst == (Rate{}) {
+		return []Rate{p.Sustained}
+	}
+	return []Rate{p.Burst, p.Sustained}
+}
+
+// Result é a decisão para uma requisição. Limit e Remaining se referem à taxa
+// mais restritiva no momento; RetryAfter só é preenchido quando Allowed é false.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Limiter decide se uma requisição identificada por key pode prosseguir,
+// contabilizando-a quando permitida. Erros indicam falha do backend (ex.:
+// Redis indisponível); a política de fail-open fica a cargo do Enforcer.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// Enforcer aplica um Limiter e decide o que fazer quando o backend falha. Por
+// padrão é fail-open: uma queda do Redis não pode derrubar a API junto.
+type Enforcer struct {
+	limiter    Limiter
+	failClosed bool
+	onError    func(key string, err error)
+}
+
+// Option configura um Enforcer
+type Option func(*Enforcer)
+
+// WithFailClosed recusa as requisições quando o Limiter falha, para rotas em
+// que exceder o limite é pior que ficar indisponível (ex.: envio de SMS)
+func WithFailClosed() Option {
+	return func(e *Enforcer) { e.failClosed = true }
+}
+
+// WithErrorHandler registra uma função chamada a cada falha do Limiter,
+// tipicamente para log e métricas
+func WithErrorHandler(fn func(key string, err error)) Option {
+	return func(e *Enforcer) { e.onError = fn }
+}
+
+// NewEnforcer cria um Enforcer para o Limiter
+func NewEnforcer(limiter Limiter, opts ...Option) *Enforcer {
+	e := &Enforcer{limiter: limiter}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Check consulta o Limiter para a chave. Chave vazia não é limitada. Se o
+// Limiter falhar, a requisição é permitida sem informações de limite
+// (Result.Limit zero); com WithFailClosed, o erro é retornado.
+func (e *Enforcer) Check(ctx context.Context, key string) (Result, error) {
+	if key == "" {
+		return Result{Allowed: true}, nil
+	}
+
+	res, err := e.limiter.Allow(ctx, key)
+	if err == nil {
+		return res, nil
+	}
+
+	if e.onError != nil {
+		e.onError(key, err)
+	}
+
+	if e.failClosed {
+		return Result{}, err
+	}
+	return Result{Allowed: true}, nil
+}
+
+// WriteHeaders escreve X-RateLimit-Limit, X-RateLimit-Remaining e, em
+// requisições recusadas, Retry-After em segundos
+func WriteHeaders(h http.Header, res Result) {
+	if res.Limit == 0 {
+		return
+	}
+
+	h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(RetryAfterSeconds(res)))
+	}
+}
+
+// RetryAfterSeconds arredonda RetryAfter para cima em segundos, no mínimo 1
+func RetryAfterSeconds(res Result) int {
+	seconds := int(math.Ceil(res.RetryAfter.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// ErrorBody é o corpo das respostas 429 e 503 dos middlewares
+func ErrorBody(err error) map[string]string {
+	if err != nil {
+		return map[string]string{
+			"error":   "service_unavailable",
+			"message": "rate limiter unavailable",
+		}
+	}
+	return map[string]string{
+		"error":   "too_many_requests",
+		"message": "rate limit exceeded",
+	}
+}
+
+// KeyFunc extrai a chave de limitação de uma requisição. Chave vazia não é
+// limitada.
+type KeyFunc func(r *http.Request) string
+
+// ByIP chaveia pelo IP de RemoteAddr. Atrás de proxy, RemoteAddr é o do proxy:
+// use um KeyFunc que leia o cabeçalho confiável do seu ambiente.
+func ByIP() KeyFunc {
+	return func(r *http.Request) string {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		return "ip:" + host
+	}
+}
+
+// ByHeader chaveia pelo valor de um cabeçalho (ex.: X-Tenant-ID), com o nome
+// do cabeçalho como namespace. Requisições sem o cabeçalho não são limitadas.
+func ByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		value := r.Header.Get(name)
+		if value == "" {
+			return ""
+		}
+		return http.CanonicalHeaderKey(name) + ":" + value
+	}
+}
+
+// Middleware limita as requisições por key, respondendo 429 com Retry-After
+// quando o limite é excedido e 503 quando o Limiter falha em fail-closed
+func (e *Enforcer) Middleware(key KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := e.Check(r.Context(), key(r))
+			WriteHeaders(w.Header(), res)
+
+			switch {
+			case err != nil:
+				writeJSON(w, http.StatusServiceUnavailable, ErrorBody(err))
+			case !res.Allowed:
+				writeJSON(w, http.StatusTooManyRequests, ErrorBody(nil))
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
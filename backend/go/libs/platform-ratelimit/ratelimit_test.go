@@ -0,0 +1,318 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeRedis reproduz o script de janela deslizante em memória
+type fakeRedis struct {
+	values map[string]int64
+	err    error
+	calls  int
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{values: map[string]int64{}}
+}
+
+func (f *fakeRedis) eval(_ context.Context, keys []string, args ...interface{}) ([]int64, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	n := len(keys) / 2
+	allowed := int64(1)
+	out := make([]int64, 1+2*n)
+	for i := 0; i < n; i++ {
+		curr, prev := f.values[keys[2*i]], f.values[keys[2*i+1]]
+		limit := args[3*i].(int)
+		weight, _ := strconv.ParseFloat(args[3*i+1].(string), 64)
+		if float64(prev)*weight+float64(curr)+1 > float64(limit) {
+			allowed = 0
+		}
+		out[1+2*i], out[2+2*i] = prev, curr
+	}
+
+	if allowed == 1 {
+		for i := 0; i < n; i++ {
+			f.values[keys[2*i]]++
+		}
+	}
+	out[0] = allowed
+	return out, nil
+}
+
+// start é alinhado ao minuto, para que as janelas fixas comecem nele
+var start = time.Unix(1_700_000_040, 0)
+
+func newTestLimiter(t *testing.T, policy Policy) (*RedisLimiter, *fakeRedis, *time.Time) {
+	t.Helper()
+
+	redis := newFakeRedis()
+	l, err := newRedisLimiter(redis.eval, policy, "")
+	if err != nil {
+		t.Fatalf("newRedisLimiter: %v", err)
+	}
+
+	now := start
+	l.now = func() time.Time { return now }
+	return l, redis, &now
+}
+
+func allow(t *testing.T, l Limiter, key string) Result {
+	t.Helper()
+
+	res, err := l.Allow(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	return res
+}
+
+func TestRedisLimiter_SustainedLimit(t *testing.T) {
+	l, _, _ := newTestLimiter(t, Policy{Sustained: PerMinute(3)})
+
+	for i, want := range []int{2, 1, 0} {
+		res := allow(t, l, "tenant:a")
+		if !res.Allowed || res.Limit != 3 || res.Remaining != want {
+			t.Fatalf("requisição %d: %+v, esperado permitida com Remaining %d", i+1, res, want)
+		}
+	}
+
+	res := allow(t, l, "tenant:a")
+	if res.Allowed {
+		t.Fatal("quarta requisição deveria ser recusada")
+	}
+	// Virada do minuto (60s) e a janela, já como anterior, pesar no máximo 2 (20s)
+	if res.RetryAfter != 80*time.Second {
+		t.Errorf("RetryAfter = %v, esperado 80s", res.RetryAfter)
+	}
+
+	if res := allow(t, l, "tenant:b"); !res.Allowed {
+		t.Error("outra chave não deveria ser afetada")
+	}
+}
+
+func TestRedisLimiter_SlidingWindowWeighsPreviousWindow(t *testing.T) {
+	l, _, now := newTestLimiter(t, Policy{Sustained: PerMinute(4)})
+
+	*now = start.Add(30 * time.Second)
+	for i := 0; i < 4; i++ {
+		allow(t, l, "k")
+	}
+
+	// 10s na janela seguinte: a anterior ainda pesa 4*50/60 ≈ 3.3 de 4
+	*now = start.Add(70 * time.Second)
+	res := allow(t, l, "k")
+	if res.Allowed {
+		t.Fatal("requisição deveria ser recusada pelo peso da janela anterior")
+	}
+	// Aos 15s a anterior pesa 3 e cabe mais uma
+	if res.RetryAfter != 5*time.Second {
+		t.Errorf("RetryAfter = %v, esperado 5s", res.RetryAfter)
+	}
+
+	// Na espera sugerida a requisição passa
+	*now = now.Add(res.RetryAfter)
+	if res := allow(t, l, "k"); !res.Allowed {
+		t.Fatalf("após RetryAfter a requisição deveria passar: %+v", res)
+	}
+
+	// 45s na janela: 4*0.25 + 1 = 2, cabem mais 2
+	*now = start.Add(105 * time.Second)
+	if res := allow(t, l, "k"); !res.Allowed || res.Remaining != 1 {
+		t.Errorf("resultado = %+v, esperado permitida com Remaining 1", res)
+	}
+}
+
+func TestRedisLimiter_RejectedRequestsAreNotCounted(t *testing.T) {
+	l, redis, _ := newTestLimiter(t, Policy{Sustained: PerMinute(2)})
+
+	for i := 0; i < 5; i++ {
+		allow(t, l, "k")
+	}
+
+	var total int64
+	for _, v := range redis.values {
+		total += v
+	}
+	if total != 2 {
+		t.Errorf("contagem = %d, esperado 2 (recusadas não contam)", total)
+	}
+}
+
+func TestRedisLimiter_BurstAndSustained(t *testing.T) {
+	l, redis, now := newTestLimiter(t, Policy{Burst: PerSecond(2), Sustained: PerMinute(5)})
+
+	allow(t, l, "k")
+	res := allow(t, l, "k")
+	if !res.Allowed || res.Limit != 2 || res.Remaining != 0 {
+		t.Fatalf("resultado = %+v, esperado a rajada como taxa mais restritiva", res)
+	}
+
+	res = allow(t, l, "k")
+	if res.Allowed || res.Limit != 2 {
+		t.Fatalf("resultado = %+v, esperado recusada pela rajada", res)
+	}
+	if res.RetryAfter <= 0 || res.RetryAfter > 2*time.Second {
+		t.Errorf("RetryAfter = %v, esperado dentro de duas janelas de rajada", res.RetryAfter)
+	}
+
+	// Uma recusa pela rajada não consome a taxa sustentada
+	if len(redis.values) != 2 {
+		t.Fatalf("chaves = %v, esperado uma janela de rajada e uma sustentada", redis.values)
+	}
+
+	for i := 1; i <= 3; i++ {
+		*now = start.Add(time.Duration(2*i) * time.Second)
+		if res := allow(t, l, "k"); !res.Allowed {
+			t.Fatalf("requisição %d na taxa sustentada deveria passar: %+v", 2+i, res)
+		}
+	}
+
+	*now = start.Add(10 * time.Second)
+	res = allow(t, l, "k")
+	if res.Allowed || res.Limit != 5 {
+		t.Fatalf("resultado = %+v, esperado recusada pela taxa sustentada", res)
+	}
+	if res.RetryAfter < 50*time.Second {
+		t.Errorf("RetryAfter = %v, esperado ao menos o resto do minuto", res.RetryAfter)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	rate := PerMinute(10)
+
+	tests := []struct {
+		name    string
+		c       counts
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		// 10 na atual: espera a virada (30s) e a anterior pesar < 9 (6s)
+		{"janela atual cheia", counts{curr: 10}, 30 * time.Second, 36 * time.Second},
+		// 10*(60-20-t)/60 + 5 <= 9 → t >= 16s
+		{"janela anterior pesando", counts{prev: 10, curr: 5}, 20 * time.Second, 16 * time.Second},
+		{"já cabe", counts{prev: 2, curr: 1}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.c, rate, tt.elapsed); got != tt.want {
+				t.Errorf("retryAfter = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		valid  bool
+	}{
+		{"só sustentada", Policy{Sustained: PerMinute(60)}, true},
+		{"rajada e sustentada", Policy{Burst: PerSecond(10), Sustained: PerMinute(60)}, true},
+		{"sem sustentada", Policy{Burst: PerSecond(10)}, false},
+		{"rajada com janela maior", Policy{Burst: PerHour(10), Sustained: PerMinute(60)}, false},
+		{"limite zero", Policy{Sustained: Rate{Window: time.Minute}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, esperado válido=%v", err, tt.valid)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPolicy) {
+				t.Errorf("erro %v deveria ser ErrInvalidPolicy", err)
+			}
+		})
+	}
+}
+
+func TestEnforcer_FailsOpenOnRedisError(t *testing.T) {
+	l, redis, _ := newTestLimiter(t, Policy{Sustained: PerMinute(1)})
+	redis.err = errors.New("connection refused")
+
+	var reported error
+	e := NewEnforcer(l, WithErrorHandler(func(key string, err error) { reported = err }))
+
+	called := 0
+	h := e.Middleware(ByIP())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called++
+	}))
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, esperado 200 em fail-open", rec.Code)
+		}
+		if rec.Header().Get("X-RateLimit-Limit") != "" {
+			t.Error("sem resposta do Limiter não deveria haver cabeçalhos de limite")
+		}
+	}
+
+	if called != 3 {
+		t.Errorf("handler chamado %d vezes, esperado 3", called)
+	}
+	if reported == nil {
+		t.Error("falha do Redis deveria ser reportada ao error handler")
+	}
+}
+
+func TestEnforcer_FailClosed(t *testing.T) {
+	l, redis, _ := newTestLimiter(t, Policy{Sustained: PerMinute(1)})
+	redis.err = errors.New("connection refused")
+
+	h := NewEnforcer(l, WithFailClosed()).Middleware(ByIP())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler não deveria ser chamado em fail-closed")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, esperado 503", rec.Code)
+	}
+}
+
+func TestMiddleware_RejectsWithRetryAfter(t *testing.T) {
+	l, _, _ := newTestLimiter(t, Policy{Sustained: PerMinute(1)})
+	h := NewEnforcer(l).Middleware(ByHeader("X-Tenant-ID"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tenant != "" {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve("t1"); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("primeira requisição: status %d, remaining %q", rec.Code, rec.Header().Get("X-RateLimit-Remaining"))
+	}
+
+	rec := serve("t1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, esperado 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "120" {
+		t.Errorf("Retry-After = %q, esperado 120", rec.Header().Get("Retry-After"))
+	}
+
+	if rec := serve(""); rec.Code != http.StatusOK {
+		t.Errorf("requisição sem tenant não deveria ser limitada, status %d", rec.Code)
+	}
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix é o prefixo padrão das chaves no Redis
+const DefaultPrefix = "ratelimit"
+
+// slidingWindowScript lê as contagens das janelas atual e anterior de cada
+// taxa e, se a requisição couber em todas, incrementa as janelas atuais. Tudo
+// num único script para que réplicas concorrentes não ultrapassem o limite.
+//
+// KEYS: pares (atual, anterior) por taxa
+// ARGV: trincas (limite, peso da anterior, TTL em ms) por taxa
+// Retorno: {permitido, anterior_1, atual_1, anterior_2, atual_2, ...}
+var slidingWindowScript = redis.NewScript(`
+local n = #KEYS / 2
+local allowed = 1
+local out = {}
+for i = 1, n do
+  local curr = tonumber(redis.call('GET', KEYS[2*i-1]) or '0')
+  local prev = tonumber(redis.call('GET', KEYS[2*i]) or '0')
+  local limit = tonumber(ARGV[3*i-2])
+  local weight = tonumber(ARGV[3*i-1])
+  if prev * weight + curr + 1 > limit then
+    allowed = 0
+  end
+  out[2*i] = prev
+  out[2*i+1] = curr
+end
+if allowed == 1 then
+  for i = 1, n do
+    redis.call('INCR', KEYS[2*i-1])
+    redis.call('PEXPIRE', KEYS[2*i-1], ARGV[3*i])
+  end
+end
+out[1] = allowed
+return out
+`)
+
+// evalFunc executa o script de janela deslizante
+type evalFunc func(ctx context.Context, keys []string, args ...interface{}) ([]int64, error)
+
+// RedisLimiter é um Limiter com janela deslizante em Redis, compartilhado por
+// todas as réplicas que usam o mesmo prefixo
+type RedisLimiter struct {
+	policy Policy
+	prefix string
+	eval   evalFunc
+	now    func() time.Time
+}
+
+// NewRedisLimiter cria um RedisLimiter. Serviços com várias políticas (ex.: uma
+// por rota) usam um prefixo por política para que as contagens não se misturem.
+func NewRedisLimiter(client redis.Scripter, policy Policy, prefix string) (*RedisLimiter, error) {
+	eval := func(ctx context.Context, keys []string, args ...interface{}) ([]int64, error) {
+		return slidingWindowScript.Run(ctx, client, keys, args...).Int64Slice()
+	}
+	return newRedisLimiter(eval, policy, prefix)
+}
+
+func newRedisLimiter(eval evalFunc, policy Policy, prefix string) (*RedisLimiter, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+
+	return &RedisLimiter{
+		policy: policy,
+		prefix: prefix,
+		eval:   eval,
+		now:    time.Now,
+	}, nil
+}
+
+// Allow implementa Limiter
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.now()
+	rates := l.policy.rates()
+
+	keys := make([]string, 0, 2*len(rates))
+	args := make([]interface{}, 0, 3*len(rates))
+	for _, rate := range rates {
+		index, elapsed := position(rate, now)
+		keys = append(keys, l.windowKey(key, rate, index), l.windowKey(key, rate, index-1))
+		args = append(args,
+			rate.Limit,
+			// Precisão total para o script decidir exatamente como decide()
+			strconv.FormatFloat(prevWeight(rate, elapsed), 'g', -1, 64),
+			(2*rate.Window + time.Second).Milliseconds(),
+		)
+	}
+
+	reply, err := l.eval(ctx, keys, args...)
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: redis: %w", err)
+	}
+
+	if len(reply) != 1+2*len(rates) {
+		return Result{}, fmt.Errorf("ratelimit: unexpected script reply of length %d", len(reply))
+	}
+
+	all := make([]counts, len(rates))
+	for i := range rates {
+		all[i] = counts{prev: reply[1+2*i], curr: reply[2+2*i]}
+	}
+
+	return decide(rates, all, now), nil
+}
+
+// windowKey é a chave do contador de uma janela fixa. A hash tag {key} mantém
+// todas as janelas de uma chave no mesmo slot do Redis Cluster, exigência para
+// o script acessá-las juntas.
+func (l *RedisLimiter) windowKey(key string, rate Rate, index int64) string {
+	return fmt.Sprintf("%s:{%s}:%d:%d", l.prefix, key, rate.Window.Milliseconds(), index)
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// A janela deslizante é aproximada por duas janelas fixas consecutivas: a
+// contagem da janela anterior entra com peso proporcional ao quanto dela ainda
+// cabe na janela deslizante. Isso usa dois contadores por taxa em vez de um
+// registro por requisição e erra no máximo pela distribuição das requisições
+// dentro da janela anterior.
+
+// counts são as contagens das janelas fixas anterior e atual de uma taxa
+type counts struct {
+	prev int64
+	curr int64
+}
+
+// position localiza now na grade de janelas fixas da taxa: o índice da janela
+// atual e quanto dela já passou
+func position(rate Rate, now time.Time) (index int64, elapsed time.Duration) {
+	window := rate.Window.Milliseconds()
+	ms := now.UnixMilli()
+	index = ms / window
+	return index, time.Duration(ms-index*window) * time.Millisecond
+}
+
+// prevWeight é o peso da janela anterior na janela deslizante
+func prevWeight(rate Rate, elapsed time.Duration) float64 {
+	return float64(rate.Window-elapsed) / float64(rate.Window)
+}
+
+// weighted é a contagem estimada na janela deslizante que termina agora
+func weighted(c counts, rate Rate, elapsed time.Duration) float64 {
+	return float64(c.prev)*prevWeight(rate, elapsed) + float64(c.curr)
+}
+
+// admits informa se mais uma requisição cabe na taxa
+func admits(c counts, rate Rate, elapsed time.Duration) bool {
+	return weighted(c, rate, elapsed)+1 <= float64(rate.Limit)
+}
+
+// retryAfter estima quanto falta para a próxima requisição caber na taxa,
+// supondo que nenhuma outra seja contabilizada até lá
+func retryAfter(c counts, rate Rate, elapsed time.Duration) time.Duration {
+	window := float64(rate.Window)
+	rest := rate.Window - elapsed
+	room := float64(rate.Limit - 1)
+
+	if float64(c.curr) > room {
+		// A janela atual sozinha já estourou: é preciso esperar a virada e
+		// que ela, agora como anterior, perca peso suficiente.
+		wait := window * (1 - room/float64(c.curr))
+		return roundUp(rest + time.Duration(wait))
+	}
+
+	if c.prev == 0 {
+		return 0
+	}
+
+	// prev*(W-elapsed-t)/W + curr <= limit-1
+	wait := float64(rest) - (room-float64(c.curr))*window/float64(c.prev)
+	if wait < 0 {
+		return 0
+	}
+	return roundUp(time.Duration(math.Min(wait, float64(rest))))
+}
+
+// roundUp arredonda para o milissegundo seguinte, a resolução das janelas
+func roundUp(d time.Duration) time.Duration {
+	return (d + time.Millisecond - 1).Truncate(time.Millisecond)
+}
+
+// decide monta o Result a partir das contagens de cada taxa, lidas antes de
+// contabilizar a requisição. Quando permitida, Remaining já desconta a própria
+// requisição.
+func decide(rates []Rate, all []counts, now time.Time) Result {
+	res := Result{Allowed: true, Remaining: math.MaxInt}
+
+	for i, rate := range rates {
+		_, elapsed := position(rate, now)
+		c := all[i]
+
+		if !admits(c, rate, elapsed) {
+			// Com as duas taxas estouradas, vale a espera mais longa
+			if wait := retryAfter(c, rate, elapsed); res.Allowed || wait > res.RetryAfter {
+				res = Result{Limit: rate.Limit, RetryAfter: wait}
+			}
+			continue
+		}
+
+		if !res.Allowed {
+			continue
+		}
+
+		remaining := rate.Limit - int(math.Ceil(weighted(c, rate, elapsed))) - 1
+		if remaining < 0 {
+			remaining = 0
+		}
+		if remaining < res.Remaining {
+			res.Limit = rate.Limit
+			res.Remaining = remaining
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,385 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// userAgent identifica as entregas
+const userAgent = "Serphona-Webhooks/1.0"
+
+// Config configura um Dispatcher. Campos zerados usam o valor de
+// DefaultConfig.
+type Config struct {
+	// HTTPClient faz as entregas; o padrão tem timeout de Timeout
+	HTTPClient *http.Client
+	Timeout    time.Duration
+	// Workers é o número de entregas simultâneas
+	Workers int
+	// PollInterval é a espera entre buscas de entregas vencidas quando a
+	// fila está vazia
+	PollInterval time.Duration
+	// BatchSize é o número de entregas reservadas por busca
+	BatchSize int
+	// Lease é por quanto tempo uma entrega reservada fica invisível a
+	// outros Dispatchers; deve passar de Timeout
+	Lease time.Duration
+	// MaxAttempts é o número de tentativas, incluindo a primeira, antes de
+	// a entrega ir para a DLQ
+	MaxAttempts int
+	// Backoff é a espera antes da segunda tentativa; dobra a cada tentativa
+	// até MaxBackoff, com jitter. Retry-After do endpoint é respeitado até
+	// MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// OnDead é chamado quando uma entrega vai para a DLQ; opcional
+	OnDead func(d Delivery)
+}
+
+// DefaultConfig são os valores usados para campos zerados de Config. Com
+// eles, uma entrega é tentada por duas a quatro horas antes da DLQ.
+var DefaultConfig = Config{
+	Timeout:      10 * time.Second,
+	Workers:      4,
+	PollInterval: time.Second,
+	BatchSize:    32,
+	Lease:        time.Minute,
+	MaxAttempts:  10,
+	Backoff:      30 * time.Second,
+	MaxBackoff:   4 * time.Hour,
+}
+
+// Dispatcher registra endpoints, enfileira eventos e faz as entregas
+type Dispatcher struct {
+	store  Store
+	config Config
+	client *http.Client
+
+	now    func() time.Time
+	jitter func() float64 // em [0, 1)
+}
+
+// New cria um Dispatcher sobre store; as entregas começam com Run
+func New(store Store, config Config) *Dispatcher {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultConfig.Timeout
+	}
+	if config.Workers <= 0 {
+		config.Workers = DefaultConfig.Workers
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultConfig.PollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultConfig.BatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = DefaultConfig.Lease
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultConfig.MaxAttempts
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DefaultConfig.Backoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = DefaultConfig.MaxBackoff
+	}
+
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &Dispatcher{
+		store:  store,
+		config: config,
+		client: client,
+		now:    time.Now,
+		jitter: rand.Float64,
+	}
+}
+
+// RegisterEndpoint valida e grava um endpoint ativo. Sem Secret, um segredo
+// aleatório é gerado; o endpoint retornado é o único que o expõe.
+func (d *Dispatcher) RegisterEndpoint(ctx context.Context, endpoint Endpoint) (Endpoint, error) {
+	if err := endpoint.Validate(); err != nil {
+		return Endpoint{}, err
+	}
+	if endpoint.Secret == "" {
+		endpoint.Secret = newID("whsec_")
+	}
+	endpoint.ID = newID("we_")
+	endpoint.Active = true
+	endpoint.CreatedAt = d.now().UTC()
+
+	if err := d.store.SaveEndpoint(ctx, endpoint); err != nil {
+		return Endpoint{}, err
+	}
+	return endpoint, nil
+}
+
+// Endpoints lista os endpoints do tenant, sem os segredos
+func (d *Dispatcher) Endpoints(ctx context.Context, tenantID string) ([]Endpoint, error) {
+	endpoints, err := d.store.ListEndpoints(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		endpoints[i] = endpoints[i].redacted()
+	}
+	return endpoints, nil
+}
+
+// SetEndpointActive ativa ou desativa um endpoint. Entregas já enfileiradas
+// para um endpoint desativado vão para a DLQ na próxima tentativa.
+func (d *Dispatcher) SetEndpointActive(ctx context.Context, id string, active bool) (Endpoint, error) {
+	endpoint, err := d.store.GetEndpoint(ctx, id)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	endpoint.Active = active
+	if err := d.store.SaveEndpoint(ctx, endpoint); err != nil {
+		return Endpoint{}, err
+	}
+	return endpoint.redacted(), nil
+}
+
+// RemoveEndpoint apaga um endpoint
+func (d *Dispatcher) RemoveEndpoint(ctx context.Context, id string) error {
+	return d.store.DeleteEndpoint(ctx, id)
+}
+
+// Enqueue grava uma entrega do evento para cada endpoint ativo do tenant que
+// o recebe. payload é serializado em JSON uma vez, e o mesmo corpo vai a
+// todos os endpoints. Retorna as entregas criadas, nenhuma se nenhum
+// endpoint assina o tipo.
+func (d *Dispatcher) Enqueue(ctx context.Context, tenantID, eventType string, payload any) ([]Delivery, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("serialize webhook payload: %w", err)
+	}
+	endpoints, err := d.store.ListEndpoints(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook endpoints: %w", err)
+	}
+
+	now := d.now().UTC()
+	var deliveries []Delivery
+	for _, e := range endpoints {
+		if !e.Active || !e.Matches(eventType) {
+			continue
+		}
+		deliveries = append(deliveries, Delivery{
+			ID:            newID("wd_"),
+			EndpointID:    e.ID,
+			TenantID:      tenantID,
+			EventType:     eventType,
+			Payload:       body,
+			Status:        StatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	if err := d.store.Enqueue(ctx, deliveries...); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Delivery retorna o estado de uma entrega
+func (d *Dispatcher) Delivery(ctx context.Context, id string) (Delivery, error) {
+	return d.store.GetDelivery(ctx, id)
+}
+
+// Deliveries consulta as entregas; Filter{Status: StatusDead} lista a DLQ
+func (d *Dispatcher) Deliveries(ctx context.Context, filter Filter) ([]Delivery, error) {
+	return d.store.ListDeliveries(ctx, filter)
+}
+
+// Redeliver devolve uma entrega da DLQ à fila, com as tentativas zeradas,
+// depois que o endpoint foi corrigido
+func (d *Dispatcher) Redeliver(ctx context.Context, id string) (Delivery, error) {
+	delivery, err := d.store.GetDelivery(ctx, id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	if delivery.Status != StatusDead {
+		return Delivery{}, ErrNotDead
+	}
+
+	now := d.now().UTC()
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+	if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+		return Delivery{}, err
+	}
+	return delivery, nil
+}
+
+// Run faz as entregas vencidas até ctx terminar. Entregas interrompidas
+// voltam à fila quando a reserva expira.
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		n, err := d.process(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[platform-webhooks] Failed to claim deliveries: %v", err)
+		}
+		if n == d.config.BatchSize {
+			continue
+		}
+
+		timer := time.NewTimer(d.config.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// process reserva um lote de entregas vencidas e as tenta, com até Workers
+// em paralelo. Retorna o tamanho do lote.
+func (d *Dispatcher) process(ctx context.Context) (int, error) {
+	batch, err := d.store.Claim(ctx, d.now().UTC(), d.config.Lease, d.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sem := make(chan struct{}, d.config.Workers)
+	var wg sync.WaitGroup
+	for _, delivery := range batch {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(delivery Delivery) {
+			defer func() { <-sem; wg.Done() }()
+			d.attempt(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+	return len(batch), nil
+}
+
+// attempt faz uma tentativa de entrega e grava o resultado: sucesso, nova
+// tentativa agendada ou DLQ
+func (d *Dispatcher) attempt(ctx context.Context, delivery Delivery) {
+	delivery.Attempts++
+
+	endpoint, err := d.store.GetEndpoint(ctx, delivery.EndpointID)
+	var status int
+	var retryAfter time.Duration
+	permanent := false
+	switch {
+	case errors.Is(err, ErrNotFound):
+		err, permanent = fmt.Errorf("endpoint %s was removed", delivery.EndpointID), true
+	case err != nil:
+		// Erro do Store: a reserva expira e a entrega volta à fila
+		log.Printf("[platform-webhooks] Failed to load endpoint %s: %v", delivery.EndpointID, err)
+		return
+	case !endpoint.Active:
+		err, permanent = fmt.Errorf("endpoint %s is disabled", endpoint.ID), true
+	default:
+		status, retryAfter, err = d.post(ctx, endpoint, delivery)
+		permanent = status != 0 && !retryable(status)
+	}
+	if ctx.Err() != nil {
+		// Encerramento: a tentativa não conta
+		return
+	}
+
+	now := d.now().UTC()
+	delivery.UpdatedAt = now
+	delivery.LastStatusCode = status
+	switch {
+	case err == nil:
+		delivery.Status = StatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case permanent || delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = StatusDead
+		delivery.LastError = err.Error()
+	default:
+		wait := d.backoff(delivery.Attempts)
+		if retryAfter > wait && retryAfter <= d.config.MaxBackoff {
+			wait = retryAfter
+		}
+		delivery.NextAttemptAt = now.Add(wait)
+		delivery.LastError = err.Error()
+	}
+
+	if err := d.store.UpdateDelivery(ctx, delivery); err != nil {
+		log.Printf("[platform-webhooks] Failed to record delivery %s: %v", delivery.ID, err)
+		return
+	}
+	if delivery.Status == StatusDead {
+		log.Printf("[platform-webhooks] Webhook delivery dead-lettered: delivery=%s, endpoint=%s, attempts=%d: %s",
+			delivery.ID, delivery.EndpointID, delivery.Attempts, delivery.LastError)
+		if d.config.OnDead != nil {
+			d.config.OnDead(delivery)
+		}
+	}
+}
+
+// post envia a entrega, assinada no momento do envio
+func (d *Dispatcher) post(ctx context.Context, endpoint Endpoint, delivery Delivery) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderDeliveryID, delivery.ID)
+	req.Header.Set(HeaderAttempt, strconv.Itoa(delivery.Attempts))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, d.now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, 0, nil
+	}
+
+	var retryAfter time.Duration
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	return resp.StatusCode, retryAfter, fmt.Errorf("endpoint answered %d", resp.StatusCode)
+}
+
+// backoff retorna a espera após a tentativa attempt: Backoff dobrado a cada
+// tentativa, limitado a MaxBackoff, com jitter que sorteia entre metade e o
+// valor inteiro, para que endpoints que voltam não recebam tudo de uma vez
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	wait := d.config.Backoff
+	for i := 1; i < attempt && wait < d.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.config.MaxBackoff {
+		wait = d.config.MaxBackoff
+	}
+	return wait/2 + time.Duration(d.jitter()*float64(wait/2))
+}
+
+// retryable informa se um status HTTP justifica nova tentativa
+func retryable(status int) bool {
+	return status == http.StatusRequestTimeout || status == http.StatusTooManyRequests || status >= 500
+}
@@ -0,0 +1,244 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock é um relógio controlado pelo teste
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestDispatcher(t *testing.T, config Config) (*Dispatcher, *MemoryStore, *fakeClock) {
+	t.Helper()
+	store := NewMemoryStore()
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	d := New(store, config)
+	d.now = clock.Now
+	d.jitter = func() float64 { return 0 }
+	return d, store, clock
+}
+
+// receiver é um endpoint que responde com status e registra as entregas
+func receiver(t *testing.T, status int, calls *atomic.Int32, check func(r *http.Request, body []byte)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if check != nil {
+			check(r, body)
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBackoffSchedule(t *testing.T) {
+	d, _, _ := newTestDispatcher(t, Config{Backoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	// Sem jitter, a espera é metade do valor exponencial
+	expected := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := d.backoff(i + 1); got != want {
+			t.Errorf("backoff(%d) = %s, esperado %s", i+1, got, want)
+		}
+	}
+
+	// Com jitter máximo, chega perto do valor inteiro, sem passar de MaxBackoff
+	d.jitter = func() float64 { return 0.999999 }
+	for attempt := 1; attempt <= 10; attempt++ {
+		base := time.Second << (attempt - 1)
+		if base > 10*time.Second {
+			base = 10 * time.Second
+		}
+		if got := d.backoff(attempt); got < base/2 || got > base {
+			t.Errorf("backoff(%d) = %s, esperado entre %s e %s", attempt, got, base/2, base)
+		}
+	}
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	d, _, clock := newTestDispatcher(t, Config{})
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	var secret string
+	srv := receiver(t, http.StatusOK, &calls, func(r *http.Request, body []byte) {
+		if err := verifyAt(secret, r.Header.Get(HeaderSignature), body, DefaultTolerance, clock.Now()); err != nil {
+			t.Errorf("assinatura inválida: %v", err)
+		}
+		if r.Header.Get(HeaderEventType) != "call.ended" || r.Header.Get(HeaderAttempt) != "1" {
+			t.Errorf("headers inesperados: %v", r.Header)
+		}
+		if string(body) != `{"call_id":"c1"}` {
+			t.Errorf("corpo inesperado: %s", body)
+		}
+	})
+
+	endpoint, err := d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: srv.URL, EventTypes: []string{"call.*"}})
+	if err != nil {
+		t.Fatalf("RegisterEndpoint: %v", err)
+	}
+	secret = endpoint.Secret
+
+	deliveries, err := d.Enqueue(ctx, "tenant-1", "call.ended", map[string]string{"call_id": "c1"})
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("Enqueue = %d entregas, %v", len(deliveries), err)
+	}
+	if _, err := d.process(ctx); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+
+	got, err := d.Delivery(ctx, deliveries[0].ID)
+	if err != nil {
+		t.Fatalf("Delivery: %v", err)
+	}
+	if got.Status != StatusSucceeded || got.Attempts != 1 || got.LastStatusCode != http.StatusOK || got.DeliveredAt == nil {
+		t.Errorf("entrega = %+v", got)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("chamadas = %d, esperado 1", calls.Load())
+	}
+}
+
+func TestDispatcher_EnqueueMatchesEndpoints(t *testing.T) {
+	d, _, _ := newTestDispatcher(t, Config{})
+	ctx := context.Background()
+
+	calls, _ := d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: "https://a.example.com", EventTypes: []string{"call.*"}})
+	d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: "https://b.example.com", EventTypes: []string{"tenant.updated"}})
+	d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-2", URL: "https://c.example.com"})
+	disabled, _ := d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: "https://d.example.com"})
+	d.SetEndpointActive(ctx, disabled.ID, false)
+
+	deliveries, err := d.Enqueue(ctx, "tenant-1", "call.started", map[string]string{})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].EndpointID != calls.ID {
+		t.Errorf("entregas = %+v, esperado só o endpoint de call.*", deliveries)
+	}
+
+	if _, err := d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: "ftp://x"}); !errors.Is(err, ErrInvalidEndpoint) {
+		t.Errorf("URL inválida: erro = %v", err)
+	}
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	var dead []Delivery
+	d, _, clock := newTestDispatcher(t, Config{
+		MaxAttempts: 3,
+		Backoff:     time.Minute,
+		OnDead:      func(dl Delivery) { dead = append(dead, dl) },
+	})
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	srv := receiver(t, http.StatusServiceUnavailable, &calls, nil)
+	d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: srv.URL})
+	deliveries, _ := d.Enqueue(ctx, "tenant-1", "call.ended", map[string]string{})
+	id := deliveries[0].ID
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if n, _ := d.process(ctx); n != 1 {
+			t.Fatalf("tentativa %d: %d entregas reservadas, esperado 1", attempt, n)
+		}
+		got, _ := d.Delivery(ctx, id)
+		if got.Attempts != attempt {
+			t.Fatalf("tentativas = %d, esperado %d", got.Attempts, attempt)
+		}
+		if attempt < 3 {
+			if got.Status != StatusPending {
+				t.Fatalf("status após a tentativa %d = %s, esperado pending", attempt, got.Status)
+			}
+			// A próxima tentativa só vence depois do backoff
+			if n, _ := d.process(ctx); n != 0 {
+				t.Fatalf("entrega tentada antes do backoff")
+			}
+			clock.Advance(d.backoff(attempt))
+		}
+	}
+
+	got, _ := d.Delivery(ctx, id)
+	if got.Status != StatusDead || got.LastStatusCode != http.StatusServiceUnavailable || got.LastError == "" {
+		t.Errorf("entrega = %+v, esperado dead com o último erro", got)
+	}
+	if len(dead) != 1 || dead[0].ID != id {
+		t.Errorf("OnDead = %+v", dead)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("chamadas = %d, esperado 3", calls.Load())
+	}
+
+	// A DLQ é consultável e a entrega pode ser reenviada
+	dlq, _ := d.Deliveries(ctx, Filter{TenantID: "tenant-1", Status: StatusDead})
+	if len(dlq) != 1 {
+		t.Fatalf("DLQ = %d entregas, esperado 1", len(dlq))
+	}
+	redelivered, err := d.Redeliver(ctx, id)
+	if err != nil || redelivered.Status != StatusPending || redelivered.Attempts != 0 {
+		t.Errorf("Redeliver = %+v, %v", redelivered, err)
+	}
+	if _, err := d.Redeliver(ctx, id); !errors.Is(err, ErrNotDead) {
+		t.Errorf("Redeliver de entrega pendente: erro = %v", err)
+	}
+}
+
+func TestDispatcher_PermanentFailureSkipsRetries(t *testing.T) {
+	d, _, _ := newTestDispatcher(t, Config{MaxAttempts: 5})
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	srv := receiver(t, http.StatusGone, &calls, nil)
+	d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: srv.URL})
+	deliveries, _ := d.Enqueue(ctx, "tenant-1", "call.ended", map[string]string{})
+
+	d.process(ctx)
+
+	got, _ := d.Delivery(ctx, deliveries[0].ID)
+	if got.Status != StatusDead || got.Attempts != 1 {
+		t.Errorf("entrega = %+v, esperado dead após 1 tentativa", got)
+	}
+}
+
+func TestDispatcher_RespectsRetryAfter(t *testing.T) {
+	d, _, clock := newTestDispatcher(t, Config{Backoff: time.Second})
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	d.RegisterEndpoint(ctx, Endpoint{TenantID: "tenant-1", URL: srv.URL})
+	deliveries, _ := d.Enqueue(ctx, "tenant-1", "call.ended", map[string]string{})
+
+	d.process(ctx)
+
+	got, _ := d.Delivery(ctx, deliveries[0].ID)
+	if want := clock.Now().Add(120 * time.Second); !got.NextAttemptAt.Equal(want) {
+		t.Errorf("próxima tentativa = %s, esperado %s", got.NextAttemptAt, want)
+	}
+}
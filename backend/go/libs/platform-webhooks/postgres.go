@@ -0,0 +1,266 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PostgresSchema cria as tabelas do PostgresStore. Os serviços o aplicam nas
+// suas migrations ou com PostgresStore.Migrate.
+const PostgresSchema = `
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id          TEXT PRIMARY KEY,
+    tenant_id   TEXT NOT NULL,
+    url         TEXT NOT NULL,
+    secret      TEXT NOT NULL,
+    event_types JSONB NOT NULL DEFAULT '[]',
+    active      BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_tenant ON webhook_endpoints (tenant_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id               TEXT PRIMARY KEY,
+    endpoint_id      TEXT NOT NULL,
+    tenant_id        TEXT NOT NULL,
+    event_type       TEXT NOT NULL,
+    payload          JSONB NOT NULL,
+    status           TEXT NOT NULL,
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL,
+    delivered_at     TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_tenant ON webhook_deliveries (tenant_id, created_at DESC);
+`
+
+// PostgresStore é um Store durável sobre PostgreSQL. Recebe um *sql.DB já
+// aberto; o driver fica a cargo do serviço. Vários Dispatchers podem usar o
+// mesmo banco: Claim usa FOR UPDATE SKIP LOCKED.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore cria um PostgresStore
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Migrate aplica PostgresSchema
+func (s *PostgresStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, PostgresSchema)
+	return err
+}
+
+// SaveEndpoint implementa Store
+func (s *PostgresStore) SaveEndpoint(ctx context.Context, e Endpoint) error {
+	types, err := json.Marshal(e.EventTypes)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO webhook_endpoints (id, tenant_id, url, secret, event_types, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			url = EXCLUDED.url, secret = EXCLUDED.secret,
+			event_types = EXCLUDED.event_types, active = EXCLUDED.active`,
+		e.ID, e.TenantID, e.URL, e.Secret, string(types), e.Active, e.CreatedAt)
+	return err
+}
+
+// GetEndpoint implementa Store
+func (s *PostgresStore) GetEndpoint(ctx context.Context, id string) (Endpoint, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, tenant_id, url, secret, event_types, active, created_at
+		FROM webhook_endpoints WHERE id = $1`, id)
+	e, err := scanEndpoint(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Endpoint{}, ErrNotFound
+	}
+	return e, err
+}
+
+// ListEndpoints implementa Store
+func (s *PostgresStore) ListEndpoints(ctx context.Context, tenantID string) ([]Endpoint, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tenant_id, url, secret, event_types, active, created_at
+		FROM webhook_endpoints WHERE $1 = '' OR tenant_id = $1
+		ORDER BY created_at`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []Endpoint
+	for rows.Next() {
+		e, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, rows.Err()
+}
+
+// DeleteEndpoint implementa Store
+func (s *PostgresStore) DeleteEndpoint(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue implementa Store
+func (s *PostgresStore) Enqueue(ctx context.Context, deliveries ...Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, d := range deliveries {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO webhook_deliveries
+				(id, endpoint_id, tenant_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			d.ID, d.EndpointID, d.TenantID, d.EventType, string(d.Payload), d.Status, d.Attempts,
+			d.NextAttemptAt, d.CreatedAt, d.UpdatedAt); err != nil {
+			return fmt.Errorf("enqueue webhook delivery: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// deliveryColumns são as colunas lidas por scanDelivery
+const deliveryColumns = `id, endpoint_id, tenant_id, event_type, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, created_at, updated_at, delivered_at`
+
+// Claim implementa Store
+func (s *PostgresStore) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+// UpdateDelivery implementa Store
+func (s *PostgresStore) UpdateDelivery(ctx context.Context, d Delivery) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET
+			status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5,
+			last_error = $6, updated_at = $7, delivered_at = $8
+		WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastStatusCode, d.LastError, d.UpdatedAt, d.DeliveredAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetDelivery implementa Store
+func (s *PostgresStore) GetDelivery(ctx context.Context, id string) (Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return Delivery{}, err
+	}
+	if len(deliveries) == 0 {
+		return Delivery{}, ErrNotFound
+	}
+	return deliveries[0], nil
+}
+
+// ListDeliveries implementa Store
+func (s *PostgresStore) ListDeliveries(ctx context.Context, f Filter) ([]Delivery, error) {
+	var where []string
+	var args []any
+	add := func(column, value string) {
+		if value != "" {
+			args = append(args, value)
+			where = append(where, fmt.Sprintf("%s = $%d", column, len(args)))
+		}
+	}
+	add("tenant_id", f.TenantID)
+	add("endpoint_id", f.EndpointID)
+	add("event_type", f.EventType)
+	add("status", string(f.Status))
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	args = append(args, f.limit())
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT $%d`, len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanDeliveries(rows)
+}
+
+// scanner é *sql.Row ou *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEndpoint(row scanner) (Endpoint, error) {
+	var e Endpoint
+	var types string
+	if err := row.Scan(&e.ID, &e.TenantID, &e.URL, &e.Secret, &types, &e.Active, &e.CreatedAt); err != nil {
+		return Endpoint{}, err
+	}
+	if err := json.Unmarshal([]byte(types), &e.EventTypes); err != nil {
+		return Endpoint{}, fmt.Errorf("webhook endpoint %s event_types: %w", e.ID, err)
+	}
+	return e, nil
+}
+
+func scanDeliveries(rows *sql.Rows) ([]Delivery, error) {
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		var payload string
+		if err := rows.Scan(&d.ID, &d.EndpointID, &d.TenantID, &d.EventType, &payload, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.UpdatedAt, &d.DeliveredAt); err != nil {
+			return nil, err
+		}
+		d.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
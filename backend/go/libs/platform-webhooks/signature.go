@@ -0,0 +1,94 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers enviados em cada entrega. São os mesmos do bridge de eventos do
+// platform-events, então um receptor verifica as duas origens do mesmo jeito.
+const (
+	HeaderSignature  = "X-Serphona-Signature"
+	HeaderEventType  = "X-Serphona-Event-Type"
+	HeaderDeliveryID = "X-Serphona-Delivery"
+	HeaderAttempt    = "X-Serphona-Attempt"
+)
+
+// DefaultTolerance é a idade máxima de uma assinatura aceita por Verify,
+// contra replay de entregas capturadas
+const DefaultTolerance = 5 * time.Minute
+
+// Erros de verificação de assinatura
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrSignatureExpired = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// Sign assina o corpo de uma entrega. O header tem o formato
+// "t=<unix>,v1=<hex>", onde v1 é o HMAC-SHA256 de "<unix>.<corpo>" com o
+// segredo do endpoint.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify confere o header X-Serphona-Signature de uma entrega recebida.
+// Receptores devem usar o corpo cru, antes de qualquer desserialização.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	return verifyAt(secret, header, body, tolerance, time.Now())
+}
+
+// verifyAt é Verify com o relógio injetado
+func verifyAt(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	expected := mac(secret, ts, body)
+	valid := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	if age := now.Sub(time.Unix(unix, 0)); tolerance > 0 && (age > tolerance || age < -tolerance) {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// mac calcula o HMAC-SHA256 de "<ts>.<corpo>"
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
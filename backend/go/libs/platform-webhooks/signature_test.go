@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"call_id":"c1"}`)
+	now := time.Unix(1767225600, 0)
+	header := Sign("whsec_test", now, body)
+
+	if header != "t=1767225600,v1="+hexMAC("whsec_test", "1767225600", body) {
+		t.Fatalf("header inesperado: %s", header)
+	}
+	if err := verifyAt("whsec_test", header, body, DefaultTolerance, now.Add(time.Minute)); err != nil {
+		t.Errorf("assinatura válida rejeitada: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		now    time.Time
+		err    error
+	}{
+		{"segredo errado", "whsec_other", header, body, now, ErrInvalidSignature},
+		{"corpo alterado", "whsec_test", header, []byte(`{"call_id":"c2"}`), now, ErrInvalidSignature},
+		{"header malformado", "whsec_test", "v1=abc", body, now, ErrInvalidSignature},
+		{"assinatura antiga", "whsec_test", header, body, now.Add(DefaultTolerance + time.Second), ErrSignatureExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyAt(tt.secret, tt.header, tt.body, DefaultTolerance, tt.now); !errors.Is(err, tt.err) {
+				t.Errorf("erro = %v, esperado %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestVerify_AcceptsAnyOfSeveralSignatures(t *testing.T) {
+	body := []byte(`{}`)
+	now := time.Now()
+	header := Sign("new", now, body) + ",v1=" + hexMAC("old", "0", body)
+
+	if err := Verify("new", header, body, DefaultTolerance); err != nil {
+		t.Errorf("erro = %v", err)
+	}
+}
+
+func hexMAC(secret, ts string, body []byte) string {
+	return hex.EncodeToString(mac(secret, ts, body))
+}
@@ -0,0 +1,206 @@
+package webhooks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLimit é o número de entregas retornado quando Limit não é informado
+	DefaultLimit = 100
+	// MaxLimit é o maior número de entregas retornado por consulta
+	MaxLimit = 1000
+)
+
+// Store guarda endpoints e a fila de entregas. Deve ser durável em
+// produção: uma entrega enfileirada sobrevive a reinícios do serviço.
+type Store interface {
+	SaveEndpoint(ctx context.Context, endpoint Endpoint) error
+	// GetEndpoint retorna ErrNotFound para endpoints desconhecidos
+	GetEndpoint(ctx context.Context, id string) (Endpoint, error)
+	// ListEndpoints lista os endpoints do tenant; vazio lista todos
+	ListEndpoints(ctx context.Context, tenantID string) ([]Endpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+
+	// Enqueue grava novas entregas
+	Enqueue(ctx context.Context, deliveries ...Delivery) error
+	// Claim reserva até limit entregas pendentes com NextAttemptAt até now,
+	// adiando NextAttemptAt para now+lease. Uma entrega reservada não é
+	// retornada de novo antes do fim da reserva, nem a outro Dispatcher.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+	// UpdateDelivery grava o resultado de uma tentativa
+	UpdateDelivery(ctx context.Context, delivery Delivery) error
+	// GetDelivery retorna ErrNotFound para entregas desconhecidas
+	GetDelivery(ctx context.Context, id string) (Delivery, error)
+	// ListDeliveries retorna as entregas do filtro, das mais recentes para
+	// as mais antigas
+	ListDeliveries(ctx context.Context, filter Filter) ([]Delivery, error)
+}
+
+// Filter restringe uma consulta de entregas. Campos vazios não filtram.
+type Filter struct {
+	TenantID   string
+	EndpointID string
+	EventType  string
+	Status     Status
+	Limit      int
+}
+
+// Matches informa se a entrega satisfaz o filtro (exceto Limit)
+func (f Filter) Matches(d Delivery) bool {
+	switch {
+	case f.TenantID != "" && d.TenantID != f.TenantID:
+		return false
+	case f.EndpointID != "" && d.EndpointID != f.EndpointID:
+		return false
+	case f.EventType != "" && d.EventType != f.EventType:
+		return false
+	case f.Status != "" && d.Status != f.Status:
+		return false
+	}
+	return true
+}
+
+// limit retorna o Limit efetivo
+func (f Filter) limit() int {
+	switch {
+	case f.Limit <= 0:
+		return DefaultLimit
+	case f.Limit > MaxLimit:
+		return MaxLimit
+	}
+	return f.Limit
+}
+
+// MemoryStore é um Store em memória, para testes e desenvolvimento
+type MemoryStore struct {
+	mu         sync.Mutex
+	endpoints  map[string]Endpoint
+	deliveries map[string]Delivery
+}
+
+// NewMemoryStore cria um MemoryStore vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		endpoints:  make(map[string]Endpoint),
+		deliveries: make(map[string]Delivery),
+	}
+}
+
+// SaveEndpoint implementa Store
+func (s *MemoryStore) SaveEndpoint(_ context.Context, endpoint Endpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+// GetEndpoint implementa Store
+func (s *MemoryStore) GetEndpoint(_ context.Context, id string) (Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	endpoint, ok := s.endpoints[id]
+	if !ok {
+		return Endpoint{}, ErrNotFound
+	}
+	return endpoint, nil
+}
+
+// ListEndpoints implementa Store
+func (s *MemoryStore) ListEndpoints(_ context.Context, tenantID string) ([]Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var endpoints []Endpoint
+	for _, e := range s.endpoints {
+		if tenantID == "" || e.TenantID == tenantID {
+			endpoints = append(endpoints, e)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt) })
+	return endpoints, nil
+}
+
+// DeleteEndpoint implementa Store
+func (s *MemoryStore) DeleteEndpoint(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.endpoints[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.endpoints, id)
+	return nil
+}
+
+// Enqueue implementa Store
+func (s *MemoryStore) Enqueue(_ context.Context, deliveries ...Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range deliveries {
+		s.deliveries[d.ID] = d
+	}
+	return nil
+}
+
+// Claim implementa Store
+func (s *MemoryStore) Claim(_ context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Delivery
+	for _, d := range s.deliveries {
+		if d.Status == StatusPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		s.deliveries[due[i].ID] = due[i]
+	}
+	return due, nil
+}
+
+// UpdateDelivery implementa Store
+func (s *MemoryStore) UpdateDelivery(_ context.Context, delivery Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.deliveries[delivery.ID]; !ok {
+		return ErrNotFound
+	}
+	s.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+// GetDelivery implementa Store
+func (s *MemoryStore) GetDelivery(_ context.Context, id string) (Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		return Delivery{}, ErrNotFound
+	}
+	return d, nil
+}
+
+// ListDeliveries implementa Store
+func (s *MemoryStore) ListDeliveries(_ context.Context, filter Filter) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deliveries []Delivery
+	for _, d := range s.deliveries {
+		if filter.Matches(d) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	if limit := filter.limit(); len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,117 @@
+// Package webhooks entrega webhooks de saída com garantia: cada entrega é
+// gravada numa fila durável antes do envio, assinada com HMAC, tentada de
+// novo com backoff exponencial e jitter e, esgotadas as tentativas, mantida
+// como dead letter até ser reenviada. O estado de cada entrega pode ser
+// consultado.
+//
+// Os serviços registram endpoints por tenant e enfileiram eventos; um
+// Dispatcher por serviço (ou vários, sobre o mesmo Store) faz as entregas.
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Erros do pacote
+var (
+	ErrNotFound        = errors.New("webhook not found")
+	ErrInvalidEndpoint = errors.New("invalid webhook endpoint")
+	ErrNotDead         = errors.New("webhook delivery is not dead-lettered")
+)
+
+// Endpoint é um destino de webhooks de um tenant
+type Endpoint struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id"`
+	URL      string `json:"url"`
+	// Secret assina as entregas; nunca é retornado depois do registro
+	Secret string `json:"secret,omitempty"`
+	// EventTypes são os tipos entregues; vazio ou "*" recebe todos, e
+	// "call.*" recebe os tipos com esse prefixo
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Validate confere URL e tenant do endpoint
+func (e Endpoint) Validate() error {
+	if e.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidEndpoint)
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidEndpoint)
+	}
+	return nil
+}
+
+// Matches informa se o endpoint recebe eventos de eventType
+func (e Endpoint) Matches(eventType string) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, pattern := range e.EventTypes {
+		switch {
+		case pattern == "*", pattern == eventType:
+			return true
+		case strings.HasSuffix(pattern, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// redacted retorna o endpoint sem o segredo
+func (e Endpoint) redacted() Endpoint {
+	e.Secret = ""
+	return e
+}
+
+// Status é o estado de uma entrega
+type Status string
+
+const (
+	// StatusPending aguarda a próxima tentativa, em NextAttemptAt
+	StatusPending Status = "pending"
+	// StatusSucceeded foi aceita pelo endpoint (2xx)
+	StatusSucceeded Status = "succeeded"
+	// StatusDead esgotou as tentativas ou foi recusada em definitivo; fica
+	// na DLQ até Redeliver
+	StatusDead Status = "dead"
+)
+
+// Delivery é a entrega de um evento a um endpoint
+type Delivery struct {
+	ID         string          `json:"id"`
+	EndpointID string          `json:"endpoint_id"`
+	TenantID   string          `json:"tenant_id"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+	Status     Status          `json:"status"`
+	Attempts   int             `json:"attempts"`
+	// NextAttemptAt é quando a entrega pendente pode ser tentada; enquanto
+	// uma tentativa está em andamento, é o fim da reserva do Dispatcher
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	// LastStatusCode é o último status HTTP recebido; zero para erro de rede
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// newID gera um identificador aleatório com prefixo
+func newID(prefix string) string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return prefix + hex.EncodeToString(b)
+}
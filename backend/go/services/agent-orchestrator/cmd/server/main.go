@@ -12,26 +12,406 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	authjwt "github.com/serphona/serphona/backend/go/libs/platform-auth/jwt"
+	authmiddleware "github.com/serphona/serphona/backend/go/libs/platform-auth/middleware"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/health"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loadshed"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/shutdown"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+	eventsconfig "github.com/serphona/serphona/backend/go/libs/platform-events/config"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/publisher"
+
+	observability "github.com/serphona/backend/go/libs/platform-observability"
+	obsconfig "github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/events"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/http/handler"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/http/middleware"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/observer"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/postgres"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/redis"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/tenant"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/toolsgateway"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/voicegateway"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/admission"
+	costservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/cost"
+	failoverservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/failover"
+	graphservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/graph"
+	guardrailservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/guardrail"
+	llmlogservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/llmlog"
+	moderationservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/moderation"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/registry"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/replay"
+	routingservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/routing"
+	sessionservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/session"
+	summaryservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/summary"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/failover"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/llmlog"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/moderation"
 )
 
 func main() {
 	// Initialize logger
 	log.Println("Starting Agent Orchestrator Service...")
 
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	// Distributed tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), &obsconfig.Config{
+		ServiceName:     "agent-orchestrator",
+		ServiceVersion:  getEnv("SERVICE_VERSION", "1.0.0"),
+		Environment:     getEnv("ENV", "development"),
+		TracingEnabled:  getEnv("TRACING_ENABLED", "true") == "true",
+		TracingEndpoint: getEnv("TRACING_ENDPOINT", "http://tempo:4317"),
+		TracingSampler:  getEnvFloat("TRACING_SAMPLER", 1.0),
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
+	// Resources closed in order on shutdown
+	closers := shutdown.NewRegistry()
+	closers.OnClose = func(phase shutdown.Phase, name string, err error) {
+		if err != nil {
+			logger.Error("Shutdown error", zap.Stringer("phase", phase), zap.String("resource", name), zap.Error(err))
+		}
+	}
+	closers.Register(shutdown.PhaseTelemetry, "tracing", shutdown.Func(shutdownTracing))
+
+	// Infrastructure
+	redisClient := goredis.NewClient(&goredis.Options{
+		Addr:     getEnv("REDIS_HOST", "localhost") + ":" + getEnv("REDIS_PORT", "6379"),
+		Password: getEnv("REDIS_PASSWORD", ""),
+		DB:       getEnvInt("REDIS_DB", 2),
+	})
+	closers.RegisterCloser(shutdown.PhaseStorage, "redis", redisClient)
+
+	eventsCfg := eventsconfig.LoadFromEnv()
+	eventsCfg.ServiceName = "agent-orchestrator"
+	eventPublisher, err := publisher.New(eventsCfg)
+	if err != nil {
+		logger.Fatal("Failed to create event publisher", zap.Error(err))
+	}
+	closers.RegisterCloser(shutdown.PhasePublishers, "kafka-publisher", eventPublisher)
+	eventsPublisher := events.NewPublisher(eventPublisher)
+
+	tenantManagerURL := getEnv("TENANT_MANAGER_URL", "http://localhost:8081")
+	tenantClient := tenant.NewClient(tenantManagerURL, getEnvDuration("TENANT_MANAGER_TIMEOUT", 10*time.Second), logger)
+
+	// Sessions live in Redis so any replica can continue them; each replica
+	// caches the ones it serves
+	sessionRepo := sessionservice.NewCachedRepository(
+		redis.NewSessionRepository(redisClient, getEnvDuration("AGENT_CONVERSATION_TIMEOUT", 30*time.Minute)),
+		getEnvInt("SESSION_CACHE_SIZE", sessionservice.DefaultCacheSize),
+	)
+	llmLogRepo := redis.NewLLMLogRepository(redisClient, getEnvDuration("LLM_LOG_RETENTION", 7*24*time.Hour))
+
+	// Prompts and responses are logged redacted, sampled, for opted-in tenants
+	redactor, err := llmlog.NewRedactor(strings.Split(getEnv("LLM_LOG_REDACT", "email,phone,card"), ","))
+	if err != nil {
+		logger.Fatal("Invalid LLM log redaction patterns", zap.Error(err))
+	}
+	var llmClient llm.Client = llm.NewOpenAIClient(getEnv("OPENAI_API_KEY", ""), getEnv("OPENAI_BASE_URL", ""), logger)
+
+	// A secondary OpenAI-compatible provider takes the calls of tenants whose
+	// provider is down, following chains per tenant or plan
+	var failoverClient *failoverservice.Client
+	var llmProviders []string
+	if secondary := getEnv("LLM_SECONDARY_PROVIDER", ""); secondary != "" {
+		llmProviders = []string{llmClient.Provider(), secondary}
+		providers := map[string]llm.Client{
+			llmClient.Provider(): llmClient,
+			secondary:            llm.NewOpenAICompatibleClient(secondary, getEnv("LLM_SECONDARY_API_KEY", ""), getEnv("LLM_SECONDARY_BASE_URL", ""), logger),
+		}
+		chains, err := failover.ParseChains(getEnv("LLM_FAILOVER_CHAINS", ""), llmProviders)
+		if err != nil {
+			logger.Fatal("Invalid LLM failover chains", zap.Error(err))
+		}
+		failoverClient = failoverservice.NewClient(providers, chains, tenantClient, eventsPublisher, failoverservice.Config{
+			FailureThreshold: getEnvInt("LLM_PROVIDER_FAILURE_THRESHOLD", failoverservice.DefaultFailureThreshold),
+			Cooldown:         getEnvDuration("LLM_PROVIDER_COOLDOWN", failoverservice.DefaultCooldown),
+			PlanTTL:          getEnvDuration("TENANT_PLAN_CACHE_TTL", failoverservice.DefaultPlanTTL),
+		}, logger)
+		llmClient = failoverClient
+	}
+
+	// Deterministic completions for QA, recorded once and then replayed;
+	// test environments only
+	cassetteDir := getEnv("LLM_CASSETTE_DIR", "")
+	if cassetteDir != "" {
+		if getEnv("ENV", "development") == "production" {
+			logger.Fatal("LLM cassettes are not allowed in production")
+		}
+		llmClient, err = llm.NewCassetteClient(llmClient, cassetteDir, getEnv("LLM_DETERMINISTIC", "false") == "true")
+		if err != nil {
+			logger.Fatal("Failed to open LLM cassettes", zap.Error(err))
+		}
+		logger.Warn("Deterministic LLM mode available", zap.String("cassette_dir", cassetteDir))
+	}
+	// Replays call the model directly, kept out of the LLM logs
+	replayClient := llmClient
+	llmClient = llmlogservice.NewLoggingClient(llmClient, llmLogRepo, redactor, llmlogservice.Config{
+		Enabled:    getEnv("LLM_LOG_ENABLED", "false") == "true",
+		SampleRate: getEnvFloat("LLM_LOG_SAMPLE_RATE", 0.1),
+	}, logger)
+	// Times the model calls of each turn for the turn metrics
+	llmClient = sessionservice.InstrumentLLM(llmClient)
+
+	// Application
+	model := getEnv("OPENAI_MODEL", "gpt-4")
+	contextConfig := sessionservice.ContextConfig{
+		MaxTokens:       getEnvInt("CONTEXT_MAX_TOKENS", 6000),
+		Threshold:       getEnvFloat("CONTEXT_SUMMARY_THRESHOLD", 0.8),
+		KeepRecentTurns: getEnvInt("CONTEXT_KEEP_RECENT_TURNS", 6),
+		SummaryPrompt:   getEnv("CONTEXT_SUMMARY_PROMPT", ""),
+		SummaryModel:    getEnv("CONTEXT_SUMMARY_MODEL", model),
+	}
+	contextWindow := sessionservice.NewContextWindow(llmClient, contextConfig, logger)
+
+	sessionConfig := sessionservice.Config{
+		Model:           model,
+		MaxTokens:       getEnvInt("OPENAI_MAX_TOKENS", 2000),
+		Temperature:     getEnvFloat("OPENAI_TEMPERATURE", 0.7),
+		SystemPrompt:    getEnv("AGENT_SYSTEM_PROMPT", ""),
+		SpendCap:        getEnvFloat("CONVERSATION_SPEND_CAP", 0),
+		SpendCapMessage: getEnv("CONVERSATION_SPEND_CAP_MESSAGE", ""),
+		TransferMessage: getEnv("AGENT_TRANSFER_MESSAGE", ""),
+	}
+	sessionService := sessionservice.NewService(sessionRepo, llmClient, contextWindow, sessionConfig, logger)
+
+	// Cost accounting
+	prices, err := billing.ParsePriceTable(getEnv("LLM_PRICE_TABLE", ""))
+	if err != nil {
+		logger.Fatal("Invalid LLM price table", zap.Error(err))
+	}
+	tenantSpendRepo := redis.NewTenantSpendRepository(redisClient)
+	accountant := costservice.NewAccountant(prices, tenantSpendRepo, eventsPublisher, logger)
+	sessionService.SetCharger(accountant)
+
+	// Live conversation tracking for supervisors
+	obsCfg := obsconfig.LoadFromEnv()
+	obsCfg.ServiceName = "agent-orchestrator"
+	conversationObserver, err := observability.Init(obsCfg)
+	if err != nil {
+		logger.Fatal("Failed to initialize conversation observer", zap.Error(err))
+	}
+	conversationObserver.SetLogger(logger)
+	closers.Register(shutdown.PhaseTelemetry, "conversation-observer", shutdown.Func(conversationObserver.Shutdown))
+	sessionService.SetTracker(observer.NewTracker(conversationObserver, logger))
+
+	// Guardrails from the agent SafetyConfig
+	sessionService.SetAgentConfigs(tenantClient)
+
+	// Turn latency, tokens and errors, and active conversations
+	metricsRegistry := prometheus.NewRegistry()
+	metricsRegistry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	buildinfo.Register(metricsRegistry, "agent-orchestrator")
+	sessionService.SetMetrics(sessionservice.NewMetrics(metricsRegistry))
+
+	// Per-tenant LLM concurrency, limited by plan entitlements
+	sessionService.SetAdmission(admission.NewScheduler(tenantClient, admission.Config{
+		Capacity:    getEnvInt("LLM_MAX_CONCURRENCY", 64),
+		DefaultPlan: entitlements.Plan(getEnv("LLM_DEFAULT_PLAN", string(entitlements.PlanStarter))),
+		PlanTTL:     getEnvDuration("TENANT_PLAN_CACHE_TTL", admission.DefaultPlanTTL),
+	}, admission.NewMetrics(metricsRegistry), logger))
+
+	var classifier guardrailservice.Classifier
+	if getEnv("GUARDRAIL_CLASSIFIER_ENABLED", "false") == "true" {
+		classifier = guardrailservice.NewLLMClassifier(llmClient, getEnv("GUARDRAIL_CLASSIFIER_MODEL", model))
+	}
+	sessionService.SetGuard(guardrailservice.NewGuard(classifier, eventsPublisher, guardrailservice.Config{
+		Action:          guardrail.ParseAction(getEnv("GUARDRAIL_ACTION", "refuse")),
+		RefusalMessage:  getEnv("GUARDRAIL_REFUSAL_MESSAGE", ""),
+		TransferMessage: getEnv("GUARDRAIL_TRANSFER_MESSAGE", ""),
+		EndMessage:      getEnv("GUARDRAIL_END_MESSAGE", ""),
+	}, logger))
+
+	// Moderation of user input, and optionally model output
+	if getEnv("MODERATION_ENABLED", "false") == "true" {
+		var provider moderationservice.Provider
+		switch getEnv("MODERATION_PROVIDER", "rules") {
+		case "llm":
+			provider = moderationservice.NewLLMProvider(llmClient, getEnv("MODERATION_MODEL", model))
+		default:
+			var blocklist []string
+			if list := getEnv("MODERATION_BLOCKLIST", ""); list != "" {
+				blocklist = strings.Split(list, ",")
+			}
+			provider, err = moderationservice.NewRulesProvider(moderationservice.DefaultRules, blocklist)
+			if err != nil {
+				logger.Fatal("Invalid moderation blocklist", zap.Error(err))
+			}
+		}
+		sessionService.SetModerator(moderationservice.NewModerator(provider, eventsPublisher, moderationservice.Config{
+			Action:            moderation.ParseAction(getEnv("MODERATION_ACTION", "refuse")),
+			CheckOutput:       getEnv("MODERATION_CHECK_OUTPUT", "false") == "true",
+			RefusalMessage:    getEnv("MODERATION_REFUSAL_MESSAGE", ""),
+			EscalationMessage: getEnv("MODERATION_ESCALATION_MESSAGE", ""),
+		}, logger))
+	}
+
+	// Automatic transfers from the agent RoutingConfig
+	var intentDetector routingservice.IntentDetector
+	if getEnv("INTENT_DETECTION_ENABLED", "false") == "true" {
+		intentDetector = routingservice.NewLLMIntentDetector(llmClient, getEnv("INTENT_DETECTION_MODEL", model))
+	}
+	voiceGatewayURL := getEnv("VOICE_GATEWAY_URL", "http://localhost:8080")
+	voiceGateway := voicegateway.NewClient(voiceGatewayURL, getEnvDuration("VOICE_GATEWAY_TIMEOUT", 5*time.Second), logger)
+	sessionService.SetRouter(routingservice.NewRouter(intentDetector, voiceGateway, eventsPublisher, logger))
+
+	// Post-call summaries, unless the tenant turned them off in its settings
+	summaryRepo := redis.NewSummaryRepository(redisClient, getEnvDuration("SUMMARY_RETENTION", 30*24*time.Hour))
+	summarizer := summaryservice.NewSummarizer(llmClient, summaryRepo, tenantClient, eventsPublisher, summaryservice.Config{
+		Enabled: getEnv("SUMMARIZATION_ENABLED", "true") == "true",
+		Model:   getEnv("SUMMARY_MODEL", model),
+		Prompt:  getEnv("SUMMARY_PROMPT", ""),
+		Timeout: getEnvDuration("SUMMARY_TIMEOUT", time.Minute),
+	}, logger)
+	summarizer.SetCharger(accountant)
+	sessionService.SetSummarizer(summarizer)
+	sessionService.SetLastCallSummaries(summaryRepo)
+	closers.Register(shutdown.PhaseConsumers, "summarizer", summarizer.Shutdown)
+
+	// Agents with a graph route each turn from a router to specialists
+	graphConfig := graphservice.Config{
+		NodeTimeout: getEnvDuration("AGENT_GRAPH_NODE_TIMEOUT", graphservice.DefaultNodeTimeout),
+		LoopMessage: getEnv("AGENT_GRAPH_LOOP_MESSAGE", ""),
+		MaxTokens:   getEnvInt("OPENAI_MAX_TOKENS", 2000),
+		Temperature: getEnvFloat("OPENAI_TEMPERATURE", 0.7),
+	}
+	sessionService.SetGraph(graphservice.NewExecutor(llmClient, eventsPublisher, graphConfig, logger))
+
+	// Idle conversations end at the agent inactivity timeout
+	sessionService.SetInactivityClose(eventsPublisher)
+
+	// Turns cancelled mid-reply, e.g. on caller hangup, are announced
+	sessionService.SetCancelPublisher(eventsPublisher)
+	closers.Register(shutdown.PhaseConsumers, "inactivity-timers", sessionService.StopInactivityTimers)
+
+	// Agent registry in Postgres, when configured; agents otherwise come
+	// from tenant-manager only
+	checker := health.NewChecker("agent-orchestrator")
+	var agentHandler *handler.AgentHandler
+	var agents replay.Agents
+	if databaseURL := getEnv("DATABASE_URL", ""); databaseURL != "" {
+		pool, err := postgres.NewConnection(context.Background(), databaseURL, getEnvInt("DATABASE_MAX_CONNS", 10))
+		if err != nil {
+			logger.Fatal("Failed to connect to database", zap.Error(err))
+		}
+		closers.Register(shutdown.PhaseStorage, "postgres", shutdown.Func(func(ctx context.Context) error {
+			pool.Close()
+			return nil
+		}))
+		checker.Register("postgres", pool.Ping, health.Critical())
+
+		toolsGateway := toolsgateway.NewClient(getEnv("TOOLS_GATEWAY_URL", "http://localhost:8081"), getEnvDuration("TOOLS_GATEWAY_TIMEOUT", 5*time.Second))
+		models := strings.Split(getEnv("AGENT_MODELS", model), ",")
+		agentRegistry := registry.NewService(postgres.NewAgentRepository(pool), toolsGateway, models, logger)
+		agentHandler = handler.NewAgentHandler(agentRegistry, logger)
+		agents = agentRegistry
+	} else {
+		logger.Warn("DATABASE_URL not set, agent registry disabled")
+	}
+
+	// Readiness checks: sessions live in Redis and need the agent config from
+	// tenant-manager; voice-gateway is only needed for transfers
+	checker.Register("redis", func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	}, health.Critical())
+	checker.Register("tenant-manager", health.HTTPCheck(nil, tenantManagerURL+"/health/live"), health.Critical())
+	checker.Register("voice-gateway", health.HTTPCheck(nil, voiceGatewayURL+"/health/live"))
+	checker.Register("kafka", health.DialCheck(eventsCfg.Brokers...))
+	for _, provider := range llmProviders {
+		checker.Register("llm-"+provider, failoverClient.Check(provider))
+	}
+
+	// The API only accepts service tokens issued by auth-gateway
+	tokenVerifier, err := newTokenVerifier(getEnv("JWT_SECRET", ""), getEnv("JWKS_URL", ""), getEnv("JWT_AUDIENCE", "agent-orchestrator"))
+	if err != nil {
+		log.Fatalf("Failed to configure token verification (set JWT_SECRET or JWKS_URL): %v", err)
+	}
+	authjwt.SetVerifier(tokenVerifier)
+
 	// Setup router
-	router := setupRouter()
+	metricsHandler := promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+	router := setupRouter(
+		checker,
+		metricsHandler,
+		handler.NewSessionHandler(sessionService, logger),
+		handler.NewLLMLogHandler(llmLogRepo, logger),
+		handler.NewCostHandler(tenantSpendRepo, logger),
+		handler.NewSummaryHandler(summaryRepo, logger),
+		handler.NewWatchHandler(conversationObserver, getEnvDuration("WATCH_HEARTBEAT_INTERVAL", handler.DefaultWatchHeartbeat), logger),
+		agentHandler,
+		handler.NewReplayHandler(replay.NewReplayer(sessionService, agents, replayClient, replay.Config{
+			Session: sessionConfig,
+			Context: contextConfig,
+			Graph:   graphConfig,
+		}, logger), logger),
+	)
 
-	// Server configuration
+	// Server configuration; overloaded replicas refuse new work instead of
+	// slowing every conversation down
+	shedder := middleware.NewShedder(loadshed.Config{
+		MaxInFlight:  getEnvInt("LOAD_SHED_MAX_IN_FLIGHT", 256),
+		MaxQueueWait: getEnvDuration("LOAD_SHED_MAX_QUEUE_WAIT", 250*time.Millisecond),
+		RetryAfter:   getEnvDuration("LOAD_SHED_RETRY_AFTER", 2*time.Second),
+	}, metricsRegistry)
+	// Agent definitions and conversation messages stay well under 1 MiB
+	limiter := bodyLimiter(1 << 20)
+	var httpHandler http.Handler = shedder.Middleware(limiter.Middleware(router))
+	if cassetteDir != "" {
+		httpHandler = deterministicRequests(httpHandler)
+	}
 	srv := &http.Server{
 		Addr:         getEnv("HTTP_ADDR", ":8080"),
-		Handler:      router,
+		Handler:      tracing.Middleware()(httpHandler),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
+	// Watch streams never end on their own; close them so the server can drain
+	srv.RegisterOnShutdown(conversationObserver.CloseSubscriptions)
+	closers.Register(shutdown.PhaseServers, "http-server", srv.Shutdown)
+
+	// Metrics are also served on their own port, kept off the public API
+	if getEnv("ENABLE_METRICS", "true") == "true" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle(getEnv("METRICS_PATH", "/metrics"), metricsHandler)
+		metricsSrv := &http.Server{
+			Addr:    ":" + getEnv("METRICS_PORT", "9093"),
+			Handler: metricsMux,
+		}
+		closers.Register(shutdown.PhaseServers, "metrics-server", metricsSrv.Shutdown)
+
+		go func() {
+			log.Printf("Metrics server listening on %s", metricsSrv.Addr)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to start metrics server: %v", err)
+			}
+		}()
+	}
 
 	// Start server in goroutine
 	go func() {
@@ -46,35 +426,82 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// Stop HTTP, flush the publisher, then close Redis and tracing
 	log.Println("Shutting down server...")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), getEnvDuration("SHUTDOWN_TIMEOUT", 10*time.Second))
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err := closers.Shutdown(ctx); err != nil {
+		log.Printf("Shutdown completed with errors: %v", err)
 	}
 
 	log.Println("Server exited")
 }
 
-func setupRouter() *gin.Engine {
+func setupRouter(
+	readiness http.Handler,
+	metrics http.Handler,
+	sessionHandler *handler.SessionHandler,
+	llmLogHandler *handler.LLMLogHandler,
+	costHandler *handler.CostHandler,
+	summaryHandler *handler.SummaryHandler,
+	watchHandler *handler.WatchHandler,
+	agentHandler *handler.AgentHandler,
+	replayHandler *handler.ReplayHandler,
+) *gin.Engine {
 	router := gin.Default()
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy", "service": "agent-orchestrator"})
 	})
+	router.GET("/health/live", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "alive"})
+	})
+	router.GET("/health/ready", gin.WrapH(readiness))
+	router.GET("/version", gin.WrapH(buildinfo.Handler("agent-orchestrator")))
+	router.GET("/metrics", gin.WrapH(metrics))
 
-	// API v1 routes
-	v1 := router.Group("/api/v1")
+	// API v1 routes, for services holding a token with the sessions scope
+	v1 := router.Group("/api/v1", authmiddleware.RequireScope("agent-orchestrator:sessions.write"))
 	{
 		// Session management
 		sessions := v1.Group("/sessions")
 		{
-			sessions.POST("", createSession)
-			sessions.GET("/:id", getSession)
-			sessions.DELETE("/:id", endSession)
-			sessions.POST("/:id/messages", sendMessage)
+			sessions.POST("", sessionHandler.CreateSession)
+			sessions.GET("/:id", sessionHandler.GetSession)
+			sessions.DELETE("/:id", sessionHandler.EndSession)
+			sessions.POST("/:id/resume", sessionHandler.ResumeSession)
+			sessions.POST("/:id/messages", sessionHandler.SendMessage)
+			sessions.GET("/:id/usage", sessionHandler.GetUsage)
+			sessions.GET("/:id/llm-logs", llmLogHandler.ListSessionLogs)
+		}
+
+		// Conversation accounting (a conversation is a session)
+		conversations := v1.Group("/conversations", authmiddleware.RequireScope("agent-orchestrator:turns.write"))
+		{
+			conversations.GET("/:id/cost", sessionHandler.GetCost)
+			conversations.POST("/:id/turns/batch", sessionHandler.SendBatch)
+			conversations.POST("/:id/cancel", sessionHandler.CancelTurn)
+			conversations.GET("/:id/summary", summaryHandler.GetSummary)
+			conversations.POST("/:id/replay", replayHandler.Replay)
+		}
+
+		// Tenant settings
+		tenants := v1.Group("/tenants")
+		{
+			tenants.PUT("/:id/llm-logging", llmLogHandler.SetTenantLogging)
+			tenants.GET("/:id/cost", costHandler.GetTenantCost)
+			tenants.GET("/:id/conversations/watch", watchHandler.WatchConversations)
+
+			// Agent registry
+			if agentHandler != nil {
+				tenants.POST("/:id/agents", agentHandler.CreateAgent)
+				tenants.GET("/:id/agents", agentHandler.ListAgents)
+				tenants.GET("/:id/agents/:agent_id", agentHandler.GetAgent)
+				tenants.PUT("/:id/agents/:agent_id", agentHandler.UpdateAgent)
+				tenants.DELETE("/:id/agents/:agent_id", agentHandler.DeleteAgent)
+			}
 		}
 
 		// Agent routing
@@ -92,47 +519,6 @@ func setupRouter() *gin.Engine {
 // Handlers
 // ==============================================================================
 
-func createSession(c *gin.Context) {
-	// TODO: Implement session creation
-	// - Generate session ID
-	// - Initialize context in Redis
-	// - Return session info
-	c.JSON(http.StatusCreated, gin.H{
-		"session_id": "sess_placeholder",
-		"created_at": time.Now().UTC(),
-	})
-}
-
-func getSession(c *gin.Context) {
-	sessionID := c.Param("id")
-	// TODO: Fetch session from Redis
-	c.JSON(http.StatusOK, gin.H{
-		"session_id": sessionID,
-		"status":     "active",
-	})
-}
-
-func endSession(c *gin.Context) {
-	sessionID := c.Param("id")
-	// TODO: Clean up session from Redis
-	c.JSON(http.StatusOK, gin.H{
-		"session_id": sessionID,
-		"status":     "ended",
-	})
-}
-
-func sendMessage(c *gin.Context) {
-	sessionID := c.Param("id")
-	// TODO: Process message through agent pipeline
-	// - Route to appropriate agent
-	// - Manage context
-	// - Emit events to Kafka
-	c.JSON(http.StatusOK, gin.H{
-		"session_id": sessionID,
-		"response":   "Message processed",
-	})
-}
-
 func invokeAgent(c *gin.Context) {
 	agentID := c.Param("id")
 	// TODO: Invoke specific agent
@@ -155,9 +541,71 @@ func delegateToAgent(c *gin.Context) {
 // Helpers
 // ==============================================================================
 
+// newTokenVerifier verifies service tokens with the shared secret, the JWKS,
+// or both while migrating.
+func newTokenVerifier(secret, jwksURL, audience string) (*authjwt.Verifier, error) {
+	var opts []authjwt.VerifierOption
+	if audience != "" {
+		opts = append(opts, authjwt.WithAudience(audience))
+	}
+	if secret != "" {
+		opts = append(opts, authjwt.WithHMACSecret(secret))
+	}
+	if jwksURL != "" {
+		opts = append(opts, authjwt.WithJWKS(authjwt.NewJWKSClient(jwksURL, nil)))
+	}
+	return authjwt.NewVerifier(opts...)
+}
+
+// deterministicRequests runs requests carrying the X-Deterministic header with
+// recorded completions.
+func deterministicRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(llm.DeterministicHeader) == "true" {
+			r = r.WithContext(llm.WithDeterministic(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+// bodyLimiter refuses request bodies above MAX_REQUEST_BODY_BYTES with 413.
+// MAX_REQUEST_BODY_ROUTES ("/prefix=bytes,...") sets other limits by path
+// prefix.
+func bodyLimiter(maxBytes int) *bodylimit.Limiter {
+	routes, err := bodylimit.ParseRoutes(os.Getenv("MAX_REQUEST_BODY_ROUTES"))
+	if err != nil {
+		log.Fatalf("Invalid MAX_REQUEST_BODY_ROUTES: %v", err)
+	}
+	return bodylimit.New(bodylimit.Config{
+		MaxBytes: int64(getEnvInt("MAX_REQUEST_BODY_BYTES", maxBytes)),
+		Routes:   routes,
+	})
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	authjwt "github.com/serphona/serphona/backend/go/libs/platform-auth/jwt"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
+)
+
+const testSecret = "test-secret"
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	verifier, err := newTokenVerifier(testSecret, "", "agent-orchestrator")
+	if err != nil {
+		t.Fatalf("newTokenVerifier: %v", err)
+	}
+	authjwt.SetVerifier(verifier)
+	t.Cleanup(func() { authjwt.SetVerifier(nil) })
+
+	return setupRouter(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+}
+
+// serviceToken signs a token auth-gateway would issue to voice-gateway.
+func serviceToken(t *testing.T, scope string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, types.ServiceClaims{
+		Scope: scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "service:voice-gateway",
+			Audience:  jwt.ClaimStrings{"agent-orchestrator"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	signed, err := token.SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestRouter_RequiresScopedServiceToken(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"no token", "/api/v1/sessions/s-1", "", http.StatusUnauthorized},
+		{"unscoped token", "/api/v1/sessions/s-1", serviceToken(t, ""), http.StatusForbidden},
+		{"other service's scope", "/api/v1/sessions/s-1", serviceToken(t, "tools-gateway:tools.read"), http.StatusForbidden},
+		{"sessions scope without turns", "/api/v1/conversations/c-1/cost", serviceToken(t, "agent-orchestrator:sessions.write"), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestRouter_HealthNeedsNoToken(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestRouter(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+}
@@ -1,11 +1,32 @@
 module github.com/serphona/serphona/backend/go/services/agent-orchestrator
 
-go 1.21
+go 1.23
 
 require (
 	github.com/gin-gonic/gin v1.9.1
 	github.com/google/uuid v1.4.0
-	github.com/segmentio/kafka-go v0.4.45
+	github.com/jackc/pgx/v5 v5.5.3
+	github.com/prometheus/client_golang v1.23.2
+	github.com/segmentio/kafka-go v0.4.49
 	go.uber.org/zap v1.26.0
 	github.com/redis/go-redis/v9 v9.3.0
+	github.com/golang-jwt/jwt/v5 v5.2.0
+	github.com/serphona/serphona/backend/go/libs/platform-actions v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-auth v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-core v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-entitlements v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-events v0.0.0
+	github.com/serphona/backend/go/libs/platform-observability v0.0.0
 )
+
+replace github.com/serphona/serphona/backend/go/libs/platform-actions => ../../libs/platform-actions
+
+replace github.com/serphona/serphona/backend/go/libs/platform-auth => ../../libs/platform-auth
+
+replace github.com/serphona/serphona/backend/go/libs/platform-entitlements => ../../libs/platform-entitlements
+
+replace github.com/serphona/serphona/backend/go/libs/platform-events => ../../libs/platform-events
+
+replace github.com/serphona/backend/go/libs/platform-observability => ../../libs/platform-observability
+
+replace github.com/serphona/serphona/backend/go/libs/platform-core => ../../libs/platform-core
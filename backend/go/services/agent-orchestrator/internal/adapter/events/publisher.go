@@ -0,0 +1,237 @@
+// Package events provides Kafka event publishing via platform-events.
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+
+	platformevents "github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/publisher"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/failover"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/moderation"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/summary"
+)
+
+// source identifies this service in published events.
+const source = "agent-orchestrator"
+
+// Publisher publishes agent-orchestrator events.
+type Publisher struct {
+	publisher *publisher.Publisher
+}
+
+// NewPublisher creates a new event publisher.
+func NewPublisher(p *publisher.Publisher) *Publisher {
+	return &Publisher{publisher: p}
+}
+
+// PublishLLMCost publishes the cost of an LLM call for billing.
+func (p *Publisher) PublishLLMCost(ctx context.Context, charge *billing.Charge) error {
+	event := platformevents.NewEvent(topics.LLMCost, source, platformevents.LLMCostEvent{
+		ConversationID:   charge.ConversationID.String(),
+		TenantID:         charge.TenantID.String(),
+		AgentID:          charge.AgentID,
+		Variant:          charge.Variant,
+		Provider:         charge.Provider,
+		Model:            charge.Model,
+		PromptTokens:     charge.PromptTokens,
+		CompletionTokens: charge.CompletionTokens,
+		Cost:             charge.Cost,
+		Currency:         billing.Currency,
+		OccurredAt:       charge.CreatedAt,
+	}).WithTenantID(charge.TenantID.String()).WithTrace(tracing.IDs(ctx))
+
+	if err := p.publisher.Publish(ctx, topics.LLMCost, event); err != nil {
+		return fmt.Errorf("failed to publish llm cost: %w", err)
+	}
+	return nil
+}
+
+// PublishComplianceViolation publishes a guardrail violation.
+func (p *Publisher) PublishComplianceViolation(ctx context.Context, v *guardrail.Violation) error {
+	event := platformevents.NewEvent(topics.ComplianceViolation, source, platformevents.ComplianceViolationEvent{
+		ConversationID: v.ConversationID.String(),
+		TenantID:       v.TenantID.String(),
+		AgentID:        v.AgentID,
+		Kind:           string(v.Kind),
+		Topic:          v.Topic,
+		Role:           v.Role,
+		Source:         v.Source,
+		Action:         string(v.Action),
+		OccurredAt:     v.OccurredAt,
+	}).WithTenantID(v.TenantID.String()).WithTrace(tracing.IDs(ctx))
+
+	if err := p.publisher.Publish(ctx, topics.ComplianceViolation, event); err != nil {
+		return fmt.Errorf("failed to publish compliance violation: %w", err)
+	}
+	return nil
+}
+
+// PublishModerationFlagged publishes a message flagged by moderation.
+func (p *Publisher) PublishModerationFlagged(ctx context.Context, f *moderation.Flag) error {
+	categories := make([]string, len(f.Categories))
+	for i, c := range f.Categories {
+		categories[i] = string(c)
+	}
+
+	event := platformevents.NewEvent(topics.ModerationFlagged, source, platformevents.ModerationFlaggedEvent{
+		ConversationID: f.ConversationID.String(),
+		TenantID:       f.TenantID.String(),
+		AgentID:        f.AgentID,
+		Role:           f.Role,
+		Categories:     categories,
+		Provider:       f.Provider,
+		Action:         string(f.Action),
+		OccurredAt:     f.OccurredAt,
+	}).WithTenantID(f.TenantID.String()).WithTrace(tracing.IDs(ctx))
+
+	if err := p.publisher.Publish(ctx, topics.ModerationFlagged, event); err != nil {
+		return fmt.Errorf("failed to publish moderation flag: %w", err)
+	}
+	return nil
+}
+
+// PublishTransferDecision publishes an automatic transfer decision.
+func (p *Publisher) PublishTransferDecision(ctx context.Context, d *routing.Decision) error {
+	data := platformevents.TransferDecidedEvent{
+		ConversationID: d.ConversationID.String(),
+		TenantID:       d.TenantID.String(),
+		AgentID:        d.AgentID,
+		Trigger:        string(d.Trigger),
+		Intent:         d.Intent,
+		TransferType:   d.TransferType,
+		Target:         d.Target,
+		Reason:         d.Reason,
+		Transferred:    d.Transferred,
+		Error:          d.Error,
+		DecidedAt:      d.DecidedAt,
+	}
+	if d.CallID != uuid.Nil {
+		data.CallID = d.CallID.String()
+	}
+
+	event := platformevents.NewEvent(topics.TransferDecided, source, data).
+		WithTenantID(d.TenantID.String()).
+		WithTrace(tracing.IDs(ctx))
+	if err := p.publisher.Publish(ctx, topics.TransferDecided, event); err != nil {
+		return fmt.Errorf("failed to publish transfer decision: %w", err)
+	}
+	return nil
+}
+
+// PublishConversationSummarized publishes the post-call summary of a conversation.
+func (p *Publisher) PublishConversationSummarized(ctx context.Context, s *summary.Summary) error {
+	data := platformevents.ConversationSummarizedEvent{
+		ConversationID: s.ConversationID.String(),
+		TenantID:       s.TenantID.String(),
+		AgentID:        s.AgentID,
+		Variant:        s.Variant,
+		Summary:        s.Summary,
+		Disposition:    string(s.Disposition),
+		Model:          s.Model,
+		SummarizedAt:   s.CreatedAt,
+	}
+	if s.CallID != uuid.Nil {
+		data.CallID = s.CallID.String()
+	}
+
+	event := platformevents.NewEvent(topics.ConversationSummarized, source, data).
+		WithTenantID(s.TenantID.String()).
+		WithTrace(tracing.IDs(ctx))
+	if err := p.publisher.Publish(ctx, topics.ConversationSummarized, event); err != nil {
+		return fmt.Errorf("failed to publish conversation summary: %w", err)
+	}
+	return nil
+}
+
+// PublishConversationEnded publishes the end of a conversation, with the end
+// reason as its resolution.
+func (p *Publisher) PublishConversationEnded(ctx context.Context, s *session.Session) error {
+	data := platformevents.ConversationEndedEvent{
+		ConversationID: s.ID.String(),
+		AgentID:        s.AgentID,
+		TenantID:       s.TenantID.String(),
+		MessageCount:   len(s.Turns) + s.SummarizedTurns,
+		Resolution:     s.EndReason,
+	}
+	if s.EndedAt != nil {
+		data.EndedAt = *s.EndedAt
+		data.Duration = s.EndedAt.Sub(s.CreatedAt)
+	}
+
+	event := platformevents.NewEvent(topics.ConversationEnded, source, data).
+		WithTenantID(s.TenantID.String()).
+		WithTrace(tracing.IDs(ctx))
+	if err := p.publisher.Publish(ctx, topics.ConversationEnded, event); err != nil {
+		return fmt.Errorf("failed to publish conversation end: %w", err)
+	}
+	return nil
+}
+
+// PublishGraphTransition publishes a turn moving between agent graph nodes.
+func (p *Publisher) PublishGraphTransition(ctx context.Context, t *graph.Transition) error {
+	event := platformevents.NewEvent(topics.GraphTransition, source, platformevents.GraphTransitionEvent{
+		ConversationID: t.ConversationID.String(),
+		TenantID:       t.TenantID.String(),
+		AgentID:        t.AgentID,
+		From:           t.From,
+		To:             t.To,
+		Reason:         string(t.Reason),
+		Intent:         t.Intent,
+		Hop:            t.Hop,
+		OccurredAt:     t.OccurredAt,
+	}).WithTenantID(t.TenantID.String()).WithTrace(tracing.IDs(ctx))
+
+	if err := p.publisher.Publish(ctx, topics.GraphTransition, event); err != nil {
+		return fmt.Errorf("failed to publish graph transition: %w", err)
+	}
+	return nil
+}
+
+// PublishTurnCancelled publishes a turn cancelled before its reply.
+func (p *Publisher) PublishTurnCancelled(ctx context.Context, s *session.Session, reason string) error {
+	event := platformevents.NewEvent(topics.TurnCancelled, source, platformevents.TurnCancelledEvent{
+		ConversationID: s.ID.String(),
+		TenantID:       s.TenantID.String(),
+		AgentID:        s.AgentID,
+		Reason:         reason,
+		CancelledAt:    time.Now().UTC(),
+	}).WithTenantID(s.TenantID.String()).WithTrace(tracing.IDs(ctx))
+
+	if err := p.publisher.Publish(ctx, topics.TurnCancelled, event); err != nil {
+		return fmt.Errorf("failed to publish turn cancellation: %w", err)
+	}
+	return nil
+}
+
+// PublishLLMFailover publishes an LLM call served by a fallback provider.
+func (p *Publisher) PublishLLMFailover(ctx context.Context, e *failover.Event) error {
+	event := platformevents.NewEvent(topics.LLMFailover, source, platformevents.LLMFailoverEvent{
+		ConversationID: e.SessionID.String(),
+		TenantID:       e.TenantID.String(),
+		FromProvider:   e.From.Provider,
+		FromModel:      e.From.Model,
+		ToProvider:     e.To.Provider,
+		ToModel:        e.To.Model,
+		Reason:         string(e.Reason),
+		Error:          e.Error,
+		OccurredAt:     e.OccurredAt,
+	}).WithTenantID(e.TenantID.String()).WithTrace(tracing.IDs(ctx))
+
+	if err := p.publisher.Publish(ctx, topics.LLMFailover, event); err != nil {
+		return fmt.Errorf("failed to publish llm failover: %w", err)
+	}
+	return nil
+}
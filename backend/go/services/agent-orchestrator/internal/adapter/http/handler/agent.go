@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/registry"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+)
+
+// AgentHandler handles the agent registry of a tenant.
+type AgentHandler struct {
+	registry *registry.Service
+	logger   *zap.Logger
+}
+
+// NewAgentHandler creates a new agent handler.
+func NewAgentHandler(registry *registry.Service, logger *zap.Logger) *AgentHandler {
+	return &AgentHandler{
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// AgentRequest represents an agent definition.
+type AgentRequest struct {
+	Name         string              `json:"name" binding:"required"`
+	Description  string              `json:"description"`
+	Model        string              `json:"model" binding:"required"`
+	SystemPrompt string              `json:"system_prompt"`
+	Tools        []string            `json:"tools"`
+	Routing      agent.RoutingConfig `json:"routing"`
+	Safety       agent.SafetyConfig  `json:"safety"`
+	Graph        *graph.Graph        `json:"graph"`
+}
+
+func (r AgentRequest) params() registry.Params {
+	return registry.Params{
+		Name:         r.Name,
+		Description:  r.Description,
+		Model:        r.Model,
+		SystemPrompt: r.SystemPrompt,
+		Tools:        r.Tools,
+		Routing:      r.Routing,
+		Safety:       r.Safety,
+		Graph:        r.Graph,
+	}
+}
+
+// CreateAgent handles POST /api/v1/tenants/:id/agents
+func (h *AgentHandler) CreateAgent(c *gin.Context) {
+	tenantID, ok := tenantParam(c)
+	if !ok {
+		return
+	}
+
+	var req AgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	d, err := h.registry.Create(c.Request.Context(), tenantID, req.params())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, d)
+}
+
+// ListAgents handles GET /api/v1/tenants/:id/agents
+func (h *AgentHandler) ListAgents(c *gin.Context) {
+	tenantID, ok := tenantParam(c)
+	if !ok {
+		return
+	}
+
+	agents, err := h.registry.List(c.Request.Context(), tenantID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if agents == nil {
+		agents = []*agent.Definition{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"agents": agents})
+}
+
+// GetAgent handles GET /api/v1/tenants/:id/agents/:agent_id
+func (h *AgentHandler) GetAgent(c *gin.Context) {
+	tenantID, agentID, ok := agentParams(c)
+	if !ok {
+		return
+	}
+
+	d, err := h.registry.Get(c.Request.Context(), tenantID, agentID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
+
+// UpdateAgent handles PUT /api/v1/tenants/:id/agents/:agent_id
+func (h *AgentHandler) UpdateAgent(c *gin.Context) {
+	tenantID, agentID, ok := agentParams(c)
+	if !ok {
+		return
+	}
+
+	var req AgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	d, err := h.registry.Update(c.Request.Context(), tenantID, agentID, req.params())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, d)
+}
+
+// DeleteAgent handles DELETE /api/v1/tenants/:id/agents/:agent_id
+func (h *AgentHandler) DeleteAgent(c *gin.Context) {
+	tenantID, agentID, ok := agentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.registry.Delete(c.Request.Context(), tenantID, agentID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AgentHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, agent.ErrDefinitionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "agent not found"})
+	case errors.Is(err, agent.ErrNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, registry.ErrInvalidDefinition):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("agent registry request failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+func tenantParam(c *gin.Context) (uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant id"})
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+func agentParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, ok := tenantParam(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	agentID, err := uuid.Parse(c.Param("agent_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, agentID, true
+}
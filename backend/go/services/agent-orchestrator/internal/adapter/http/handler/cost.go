@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/redis"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+)
+
+// CostHandler handles tenant spend requests.
+type CostHandler struct {
+	repo   *redis.TenantSpendRepository
+	logger *zap.Logger
+}
+
+// NewCostHandler creates a new cost handler.
+func NewCostHandler(repo *redis.TenantSpendRepository, logger *zap.Logger) *CostHandler {
+	return &CostHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetTenantCost handles GET /api/v1/tenants/:id/cost?month=YYYY-MM
+func (h *CostHandler) GetTenantCost(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant id"})
+		return
+	}
+
+	month := c.DefaultQuery("month", billing.MonthOf(time.Now()))
+	if _, err := time.Parse("2006-01", month); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid month, expected YYYY-MM"})
+		return
+	}
+
+	spend, err := h.repo.Get(c.Request.Context(), tenantID, month)
+	if err != nil {
+		h.logger.Error("failed to get tenant spend", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, spend)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/redis"
+)
+
+// LLMLogHandler handles LLM prompt/response log requests.
+type LLMLogHandler struct {
+	repo   *redis.LLMLogRepository
+	logger *zap.Logger
+}
+
+// NewLLMLogHandler creates a new LLM log handler.
+func NewLLMLogHandler(repo *redis.LLMLogRepository, logger *zap.Logger) *LLMLogHandler {
+	return &LLMLogHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// LLMLoggingRequest toggles LLM logging for a tenant.
+type LLMLoggingRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// SetTenantLogging handles PUT /api/v1/tenants/:id/llm-logging
+func (h *LLMLogHandler) SetTenantLogging(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant id"})
+		return
+	}
+
+	var req LLMLoggingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := h.repo.SetTenantEnabled(c.Request.Context(), tenantID, *req.Enabled); err != nil {
+		h.logger.Error("failed to set llm logging", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tenant_id": tenantID,
+		"enabled":   *req.Enabled,
+	})
+}
+
+// ListSessionLogs handles GET /api/v1/sessions/:id/llm-logs
+func (h *LLMLogHandler) ListSessionLogs(c *gin.Context) {
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return
+	}
+
+	records, err := h.repo.ListBySession(c.Request.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("failed to list llm logs", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"logs":       records,
+	})
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/redis"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/replay"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+)
+
+// ReplayHandler handles replays of stored conversations.
+type ReplayHandler struct {
+	replayer *replay.Replayer
+	logger   *zap.Logger
+}
+
+// NewReplayHandler creates a new replay handler.
+func NewReplayHandler(replayer *replay.Replayer, logger *zap.Logger) *ReplayHandler {
+	return &ReplayHandler{
+		replayer: replayer,
+		logger:   logger,
+	}
+}
+
+// ReplayRequest selects the agent version a conversation is replayed
+// against; an empty request replays it against its own agent.
+type ReplayRequest struct {
+	AgentID      string `json:"agent_id"` // registered agent
+	SystemPrompt string `json:"system_prompt"`
+	Model        string `json:"model"`
+}
+
+// Replay handles POST /api/v1/conversations/:id/replay
+func (h *ReplayHandler) Replay(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	var req ReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+	params := replay.Params{SystemPrompt: req.SystemPrompt, Model: req.Model}
+	if req.AgentID != "" {
+		if params.AgentID, err = uuid.Parse(req.AgentID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agent id"})
+			return
+		}
+	}
+
+	result, err := h.replayer.Replay(c.Request.Context(), id, params)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ReplayHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, redis.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+	case errors.Is(err, agent.ErrDefinitionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "agent not found"})
+	case errors.Is(err, replay.ErrInvalidReplay):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error("conversation replay failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
@@ -0,0 +1,300 @@
+// Package handler provides HTTP handlers.
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/redis"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/admission"
+	sessionservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// SessionHandler handles session-related HTTP requests.
+type SessionHandler struct {
+	sessionService *sessionservice.Service
+	logger         *zap.Logger
+}
+
+// NewSessionHandler creates a new session handler.
+func NewSessionHandler(sessionService *sessionservice.Service, logger *zap.Logger) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		logger:         logger,
+	}
+}
+
+// CreateSessionRequest represents a session creation request.
+type CreateSessionRequest struct {
+	TenantID uuid.UUID `json:"tenant_id" binding:"required"`
+	AgentID  string    `json:"agent_id" binding:"required"`
+	CallID   uuid.UUID `json:"call_id"`                   // voice call carrying the session, if any
+	SpendCap float64   `json:"spend_cap" binding:"min=0"` // USD, overrides the default cap
+
+	// CustomerID identifies a returning customer, who keeps the agent variant
+	// they were first assigned
+	CustomerID string `json:"customer_id"`
+
+	// InitialState holds the conversation context variables available to
+	// the agent's system prompt, e.g. caller_number and tenant_name
+	InitialState map[string]string `json:"initial_state"`
+}
+
+// SendMessageRequest represents a user message. Context variables are
+// merged into the session before it is answered.
+type SendMessageRequest struct {
+	Content string            `json:"content" binding:"required"`
+	Context map[string]string `json:"context"`
+}
+
+// SendBatchRequest represents an ordered batch of turns. With record the
+// turns are a historical transcript, added without calling the model.
+type SendBatchRequest struct {
+	Turns  []sessionservice.BatchTurn `json:"turns" binding:"required"`
+	Record bool                       `json:"record"`
+}
+
+// CreateSession handles POST /api/v1/sessions
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	var req CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	sess, err := h.sessionService.CreateSession(c.Request.Context(), sessionservice.CreateParams{
+		TenantID:   req.TenantID,
+		AgentID:    req.AgentID,
+		CallID:     req.CallID,
+		SpendCap:   req.SpendCap,
+		CustomerID: req.CustomerID,
+		Variables:  req.InitialState,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"session_id": sess.ID,
+		"spend_cap":  sess.SpendCap,
+		"created_at": sess.CreatedAt,
+	})
+}
+
+// GetSession handles GET /api/v1/sessions/:id
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	sess, err := h.sessionService.GetSession(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sess.ID,
+		"tenant_id":  sess.TenantID,
+		"agent_id":   sess.AgentID,
+		"status":     sess.Status,
+		"created_at": sess.CreatedAt,
+	})
+}
+
+// ResumeSession handles POST /api/v1/sessions/:id/resume
+func (h *SessionHandler) ResumeSession(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	sess, err := h.sessionService.ResumeSession(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sess.ID,
+		"tenant_id":  sess.TenantID,
+		"agent_id":   sess.AgentID,
+		"status":     sess.Status,
+		"version":    sess.Version,
+		"turns":      len(sess.Turns) + sess.SummarizedTurns,
+		"created_at": sess.CreatedAt,
+	})
+}
+
+// EndSession handles DELETE /api/v1/sessions/:id
+func (h *SessionHandler) EndSession(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	sess, err := h.sessionService.EndSession(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sess.ID,
+		"status":     sess.Status,
+	})
+}
+
+// SendMessage handles POST /api/v1/sessions/:id/messages
+func (h *SessionHandler) SendMessage(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	var req SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if len(req.Context) > 0 {
+		if _, err := h.sessionService.SetVariables(c.Request.Context(), id, req.Context); err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
+
+	reply, err := h.sessionService.SendMessage(c.Request.Context(), id, req.Content)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reply)
+}
+
+// CancelTurn handles POST /api/v1/conversations/:id/cancel, sent e.g. when
+// the caller hangs up mid-turn.
+func (h *SessionHandler) CancelTurn(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	cancelled, err := h.sessionService.CancelTurn(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": id,
+		"cancelled":  cancelled,
+	})
+}
+
+// SendBatch handles POST /api/v1/conversations/:id/turns/batch
+func (h *SessionHandler) SendBatch(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	var req SendBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	result, err := h.sessionService.SendBatch(c.Request.Context(), id, sessionservice.BatchParams{
+		Turns:  req.Turns,
+		Record: req.Record,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetUsage handles GET /api/v1/sessions/:id/usage
+func (h *SessionHandler) GetUsage(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	usage, err := h.sessionService.GetUsage(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// GetCost handles GET /api/v1/conversations/:id/cost
+func (h *SessionHandler) GetCost(c *gin.Context) {
+	id, ok := h.sessionID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.sessionService.GetCost(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// sessionID parses the session ID path parameter, responding on failure.
+func (h *SessionHandler) sessionID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// handleError maps service errors to HTTP responses.
+func (h *SessionHandler) handleError(c *gin.Context, err error) {
+	if errors.Is(err, redis.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if errors.Is(err, sessionservice.ErrSessionEnded) {
+		c.JSON(http.StatusConflict, gin.H{"error": "session has ended"})
+		return
+	}
+	if errors.Is(err, session.ErrVersionConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": "session was updated concurrently, retry"})
+		return
+	}
+	if errors.Is(err, sessionservice.ErrTurnCancelled) {
+		c.JSON(http.StatusConflict, gin.H{"error": "turn was cancelled"})
+		return
+	}
+	if errors.Is(err, sessionservice.ErrInvalidBatch) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, admission.ErrTenantBusy) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "too many concurrent requests for tenant"})
+		return
+	}
+
+	h.logger.Error("session request failed", zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/redis"
+)
+
+// SummaryHandler handles conversation summary requests.
+type SummaryHandler struct {
+	repo   *redis.SummaryRepository
+	logger *zap.Logger
+}
+
+// NewSummaryHandler creates a new summary handler.
+func NewSummaryHandler(repo *redis.SummaryRepository, logger *zap.Logger) *SummaryHandler {
+	return &SummaryHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetSummary handles GET /api/v1/conversations/:id/summary
+func (h *SummaryHandler) GetSummary(c *gin.Context) {
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+		return
+	}
+
+	summary, err := h.repo.Get(c.Request.Context(), conversationID)
+	if errors.Is(err, redis.ErrSummaryNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "summary not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get summary", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	observability "github.com/serphona/backend/go/libs/platform-observability"
+)
+
+// DefaultWatchHeartbeat keeps idle watch streams open through proxies.
+const DefaultWatchHeartbeat = 15 * time.Second
+
+// WatchHandler streams live conversation events to supervisors.
+type WatchHandler struct {
+	observer  *observability.Observer
+	heartbeat time.Duration
+	logger    *zap.Logger
+}
+
+// NewWatchHandler creates a new watch handler.
+func NewWatchHandler(observer *observability.Observer, heartbeat time.Duration, logger *zap.Logger) *WatchHandler {
+	if heartbeat <= 0 {
+		heartbeat = DefaultWatchHeartbeat
+	}
+	return &WatchHandler{
+		observer:  observer,
+		heartbeat: heartbeat,
+		logger:    logger,
+	}
+}
+
+// WatchConversations handles
+// GET /api/v1/tenants/:id/conversations/watch?agent_id=&conversation_id=
+// as a Server-Sent Events stream of the tenant's active conversations.
+func (h *WatchHandler) WatchConversations(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant id"})
+		return
+	}
+
+	var opts []observability.SubscribeOption
+	if agentID := c.Query("agent_id"); agentID != "" {
+		opts = append(opts, observability.WithAgent(agentID))
+	}
+	if conversationID := c.Query("conversation_id"); conversationID != "" {
+		if _, err := uuid.Parse(conversationID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation id"})
+			return
+		}
+		opts = append(opts, observability.WithConversation(conversationID))
+	}
+
+	events, unsubscribe := h.observer.Subscribe(tenantID.String(), opts...)
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		h.logger.Warn("failed to clear watch stream write deadline", zap.Error(err))
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			c.SSEvent(event.Type, event)
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,73 @@
+// Package middleware provides the HTTP middleware of the orchestrator.
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loadshed"
+)
+
+// NewShedder creates the API load shedder and registers its metrics with reg.
+// Turns of conversations in progress are critical, so an overloaded replica
+// refuses new conversations and back-office work but lets calls finish.
+// Watch streams stay open for as long as they are watched and aren't counted.
+func NewShedder(config loadshed.Config, reg prometheus.Registerer) *loadshed.Shedder {
+	factory := promauto.With(reg)
+	shed := factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "agent_orchestrator",
+		Subsystem: "http",
+		Name:      "requests_shed_total",
+		Help:      "API requests refused with 503 under overload, by reason",
+	}, []string{"reason"})
+
+	shedder := loadshed.New(config,
+		loadshed.WithCritical(criticalRequest),
+		loadshed.WithExempt(streamRequest),
+		loadshed.WithShedHandler(func(r *http.Request, reason loadshed.Reason) {
+			shed.WithLabelValues(string(reason)).Inc()
+		}),
+	)
+
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "agent_orchestrator",
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "API requests being served",
+	}, func() float64 { return float64(shedder.Stats().InFlight) })
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "agent_orchestrator",
+		Subsystem: "http",
+		Name:      "requests_queued",
+		Help:      "API requests waiting for a slot under overload",
+	}, func() float64 { return float64(shedder.Stats().Queued) })
+	return shedder
+}
+
+// criticalRequest reports whether a request serves a conversation in
+// progress or the health of the service.
+func criticalRequest(r *http.Request) bool {
+	path := r.URL.Path
+	if strings.HasPrefix(path, "/health") || path == "/metrics" {
+		return true
+	}
+
+	const sessions, conversations = "/api/v1/sessions/", "/api/v1/conversations/"
+	switch {
+	case strings.HasPrefix(path, sessions):
+		rest := strings.TrimPrefix(path, sessions)
+		return (r.Method == http.MethodDelete && !strings.Contains(rest, "/")) ||
+			(r.Method == http.MethodPost && (strings.HasSuffix(rest, "/messages") || strings.HasSuffix(rest, "/resume")))
+	case strings.HasPrefix(path, conversations):
+		return r.Method == http.MethodPost && strings.HasSuffix(path, "/cancel")
+	}
+	return false
+}
+
+// streamRequest reports whether a request opens a long-lived stream.
+func streamRequest(r *http.Request) bool {
+	return strings.HasSuffix(r.URL.Path, "/conversations/watch")
+}
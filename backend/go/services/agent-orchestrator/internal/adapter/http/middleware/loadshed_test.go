@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loadshed"
+)
+
+func TestCriticalRequest(t *testing.T) {
+	tests := []struct {
+		method, path string
+		critical     bool
+	}{
+		{http.MethodGet, "/health/ready", true},
+		{http.MethodPost, "/api/v1/sessions/abc/messages", true},
+		{http.MethodPost, "/api/v1/sessions/abc/resume", true},
+		{http.MethodDelete, "/api/v1/sessions/abc", true},
+		{http.MethodPost, "/api/v1/conversations/abc/cancel", true},
+		{http.MethodPost, "/api/v1/sessions", false},
+		{http.MethodGet, "/api/v1/sessions/abc/llm-logs", false},
+		{http.MethodPost, "/api/v1/conversations/abc/replay", false},
+		{http.MethodPost, "/api/v1/tenants/abc/agents", false},
+	}
+	for _, tt := range tests {
+		if got := criticalRequest(httptest.NewRequest(tt.method, tt.path, nil)); got != tt.critical {
+			t.Errorf("%s %s: expected critical=%v, got %v", tt.method, tt.path, tt.critical, got)
+		}
+	}
+}
+
+func TestShedder_ShedsNewWorkAboveCeiling(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	shedder := NewShedder(loadshed.Config{MaxInFlight: 1}, reg)
+	release, _, _ := shedder.Acquire(context.Background(), false)
+	defer release()
+
+	h := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, "/api/v1/sessions"); rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected a new session shed with Retry-After, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/api/v1/sessions/abc/messages"); rec.Code != http.StatusOK {
+		t.Errorf("Expected a turn of a conversation in progress to pass, got %d", rec.Code)
+	}
+	expected := `
+# HELP agent_orchestrator_http_requests_shed_total API requests refused with 503 under overload, by reason
+# TYPE agent_orchestrator_http_requests_shed_total counter
+agent_orchestrator_http_requests_shed_total{reason="overloaded"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "agent_orchestrator_http_requests_shed_total"); err != nil {
+		t.Errorf("Unexpected shed count: %v", err)
+	}
+}
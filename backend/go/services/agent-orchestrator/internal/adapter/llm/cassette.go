@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DeterministicHeader turns deterministic mode on for one request in test
+// environments.
+const DeterministicHeader = "X-Deterministic"
+
+type deterministicKey struct{}
+
+// WithDeterministic marks ctx for deterministic completions.
+func WithDeterministic(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deterministicKey{}, true)
+}
+
+// IsDeterministic reports whether ctx was marked for deterministic
+// completions.
+func IsDeterministic(ctx context.Context) bool {
+	on, _ := ctx.Value(deterministicKey{}).(bool)
+	return on
+}
+
+// fixture is a recorded completion, stored as indented JSON so cassettes can
+// be reviewed and committed as golden files.
+type fixture struct {
+	Key       string    `json:"key"`
+	Model     string    `json:"model"`
+	MaxTokens int       `json:"max_tokens"`
+	Messages  []Message `json:"messages"`
+	Response  struct {
+		Content      string `json:"content"`
+		Model        string `json:"model"`
+		FinishReason string `json:"finish_reason"`
+		Usage        Usage  `json:"usage"`
+	} `json:"response"`
+}
+
+// CassetteClient makes completions reproducible for QA and regression tests.
+// In deterministic mode requests run at temperature 0; the first completion
+// of a prompt is recorded in the cassette directory and later runs replay it,
+// keyed by a hash of the prompt. Other requests pass through untouched.
+type CassetteClient struct {
+	next   Client
+	dir    string
+	always bool // deterministic for every request, not only marked ones
+
+	mu sync.Mutex // serializes recording
+}
+
+// NewCassetteClient creates a cassette around an LLM client, creating dir if
+// needed. always makes every request deterministic; otherwise only requests
+// whose context is marked with WithDeterministic are.
+func NewCassetteClient(next Client, dir string, always bool) (*CassetteClient, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return &CassetteClient{next: next, dir: dir, always: always}, nil
+}
+
+// Provider returns the wrapped provider name.
+func (c *CassetteClient) Provider() string {
+	return c.next.Provider()
+}
+
+// Complete replays the recorded completion of the prompt, recording it first
+// if there is none.
+func (c *CassetteClient) Complete(ctx context.Context, req CompletionRequest) (*Completion, error) {
+	if !c.always && !IsDeterministic(ctx) {
+		return c.next.Complete(ctx, req)
+	}
+	req.Temperature = 0
+
+	key := CassetteKey(req)
+	path := filepath.Join(c.dir, key+".json")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var f fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("failed to read cassette %s: %w", key, err)
+		}
+		return &Completion{
+			Content:      f.Response.Content,
+			Model:        f.Response.Model,
+			FinishReason: f.Response.FinishReason,
+			Usage:        f.Response.Usage,
+		}, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read cassette %s: %w", key, err)
+	}
+
+	completion, err := c.next.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.record(path, key, req, completion); err != nil {
+		return nil, err
+	}
+	return completion, nil
+}
+
+// record writes a fixture atomically, so a crash never leaves a partial one.
+func (c *CassetteClient) record(path, key string, req CompletionRequest, completion *Completion) error {
+	f := fixture{Key: key, Model: req.Model, MaxTokens: req.MaxTokens, Messages: req.Messages}
+	f.Response.Content = completion.Content
+	f.Response.Model = completion.Model
+	f.Response.FinishReason = completion.FinishReason
+	f.Response.Usage = completion.Usage
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette %s: %w", key, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write cassette %s: %w", key, err)
+	}
+	return nil
+}
+
+// CassetteKey hashes what determines a completion: the model, the token
+// limit and the messages. The tenant and session are left out so the same
+// conversation replays in any session.
+func CassetteKey(req CompletionRequest) string {
+	h := sha256.New()
+	json.NewEncoder(h).Encode(struct {
+		Model     string    `json:"model"`
+		MaxTokens int       `json:"max_tokens"`
+		Messages  []Message `json:"messages"`
+	}{req.Model, req.MaxTokens, req.Messages})
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// countingClient answers each call differently, like a sampled model.
+type countingClient struct {
+	calls        int
+	temperatures []float64
+	err          error
+}
+
+func (c *countingClient) Complete(ctx context.Context, req CompletionRequest) (*Completion, error) {
+	c.calls++
+	c.temperatures = append(c.temperatures, req.Temperature)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &Completion{Content: fmt.Sprintf("answer %d", c.calls), Model: req.Model, Usage: Usage{PromptTokens: 10, CompletionTokens: 5}}, nil
+}
+
+func (c *countingClient) Provider() string { return "counting" }
+
+func TestCassetteClient_RecordsThenReplays(t *testing.T) {
+	dir := t.TempDir()
+	req := CompletionRequest{
+		Model:       "test-model",
+		Messages:    []Message{{Role: "user", Content: "hello"}},
+		Temperature: 0.7,
+	}
+
+	recorder := &countingClient{}
+	c, err := NewCassetteClient(recorder, dir, true)
+	if err != nil {
+		t.Fatalf("NewCassetteClient failed: %v", err)
+	}
+	first, err := c.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if recorder.temperatures[0] != 0 {
+		t.Errorf("Expected temperature 0 in deterministic mode, got %v", recorder.temperatures[0])
+	}
+
+	// Another run replays without reaching the model
+	offline := &countingClient{err: errors.New("model unavailable")}
+	c, _ = NewCassetteClient(offline, dir, true)
+	replayed, err := c.Complete(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if *replayed != *first || offline.calls != 0 {
+		t.Errorf("Expected %+v replayed offline, got %+v after %d calls", first, replayed, offline.calls)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected one cassette file, got %d", len(entries))
+	}
+}
+
+func TestCassetteClient_OnlyMarkedRequestsWhenNotAlways(t *testing.T) {
+	next := &countingClient{}
+	c, _ := NewCassetteClient(next, t.TempDir(), false)
+	req := CompletionRequest{Model: "test-model", Messages: []Message{{Role: "user", Content: "hello"}}, Temperature: 0.7}
+
+	c.Complete(context.Background(), req)
+	c.Complete(context.Background(), req)
+	if next.calls != 2 || next.temperatures[0] != 0.7 {
+		t.Errorf("Expected unmarked requests to pass through, got %d calls at %v", next.calls, next.temperatures)
+	}
+
+	ctx := WithDeterministic(context.Background())
+	a, _ := c.Complete(ctx, req)
+	b, _ := c.Complete(ctx, req)
+	if next.calls != 3 || a.Content != b.Content {
+		t.Errorf("Expected marked requests to replay, got %q and %q after %d calls", a.Content, b.Content, next.calls)
+	}
+}
+
+func TestCassetteKey_IgnoresConversationIdentity(t *testing.T) {
+	req := CompletionRequest{Model: "test-model", Messages: []Message{{Role: "user", Content: "hello"}}}
+	other := req
+	other.Temperature = 0.9
+
+	if CassetteKey(req) != CassetteKey(other) {
+		t.Error("Expected the key to ignore temperature")
+	}
+	other.Messages = []Message{{Role: "user", Content: "hello!"}}
+	if CassetteKey(req) == CassetteKey(other) {
+		t.Error("Expected different prompts to have different keys")
+	}
+}
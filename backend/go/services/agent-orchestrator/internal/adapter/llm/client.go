@@ -0,0 +1,58 @@
+// Package llm provides Large Language Model client implementations.
+package llm
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Client defines the interface for LLM providers.
+type Client interface {
+	// Complete generates a completion for the conversation.
+	Complete(ctx context.Context, req CompletionRequest) (*Completion, error)
+
+	// Provider returns the provider name.
+	Provider() string
+}
+
+// Message is a chat message sent to the model.
+type Message struct {
+	Role    string `json:"role"` // system, user, assistant
+	Content string `json:"content"`
+}
+
+// CompletionRequest contains the input of a completion.
+type CompletionRequest struct {
+	Model       string
+	Messages    []Message
+	MaxTokens   int
+	Temperature float64
+
+	// TenantID and SessionID identify the conversation for logging and accounting.
+	TenantID  uuid.UUID
+	SessionID uuid.UUID
+}
+
+// Usage reports the tokens consumed by a completion.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Total returns prompt plus completion tokens.
+func (u Usage) Total() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// Completion is the model output.
+type Completion struct {
+	Content      string
+	Model        string
+	FinishReason string
+	Usage        Usage
+
+	// Provider names the provider that served the completion when the client
+	// routes between several; empty means the client Provider
+	Provider string
+}
@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIClient implements Client using the OpenAI chat completions API.
+type OpenAIClient struct {
+	name       string
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewOpenAIClient creates a new OpenAI client. An empty baseURL uses the public API.
+func NewOpenAIClient(apiKey, baseURL string, logger *zap.Logger) *OpenAIClient {
+	return NewOpenAICompatibleClient("openai", apiKey, baseURL, logger)
+}
+
+// NewOpenAICompatibleClient creates a client for another provider serving the
+// OpenAI chat completions API, reported under its own name.
+func NewOpenAICompatibleClient(name, apiKey, baseURL string, logger *zap.Logger) *OpenAIClient {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	return &OpenAIClient{
+		name:    name,
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Provider returns the provider name.
+func (c *OpenAIClient) Provider() string {
+	return c.name
+}
+
+type openAIRequest struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Temperature float64   `json:"temperature"`
+}
+
+type openAIResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message      Message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	Usage Usage `json:"usage"`
+}
+
+// Complete generates a chat completion.
+// POST /chat/completions
+func (c *OpenAIClient) Complete(ctx context.Context, req CompletionRequest) (*Completion, error) {
+	body, err := json.Marshal(openAIRequest{
+		Model:       req.Model,
+		Messages:    req.Messages,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var out openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(out.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in response")
+	}
+
+	c.logger.Debug("completion generated",
+		zap.String("model", out.Model),
+		zap.Int("prompt_tokens", out.Usage.PromptTokens),
+		zap.Int("completion_tokens", out.Usage.CompletionTokens),
+	)
+
+	return &Completion{
+		Content:      out.Choices[0].Message.Content,
+		Model:        out.Model,
+		FinishReason: out.Choices[0].FinishReason,
+		Usage:        out.Usage,
+	}, nil
+}
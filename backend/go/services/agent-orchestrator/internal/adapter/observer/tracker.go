@@ -0,0 +1,133 @@
+// Package observer tracks sessions on the platform-observability Observer so
+// supervisors can watch conversations live.
+package observer
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	observability "github.com/serphona/backend/go/libs/platform-observability"
+	"github.com/serphona/backend/go/libs/platform-observability/types"
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	sessionservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// channel is the conversation channel reported for sessions.
+const channel = "chat"
+
+// Speakers of tracked interactions.
+const (
+	speakerCustomer = "customer"
+	speakerAgent    = "agent"
+)
+
+// Tracker records sessions as observed conversations. The conversation ID is
+// the session ID.
+type Tracker struct {
+	observer *observability.Observer
+	logger   *zap.Logger
+}
+
+// NewTracker creates a new session tracker.
+func NewTracker(observer *observability.Observer, logger *zap.Logger) *Tracker {
+	return &Tracker{observer: observer, logger: logger}
+}
+
+// Started tracks a new session.
+func (t *Tracker) Started(ctx context.Context, s *session.Session) {
+	start := types.ConversationStart{
+		ConversationID: s.ID.String(),
+		TenantID:       s.TenantID.String(),
+		AgentID:        s.AgentID,
+		CustomerID:     s.CustomerID,
+		Channel:        channel,
+		StartTime:      s.CreatedAt,
+	}
+	// Sentiment and resolution can then be compared per agent variant
+	if s.Variant != "" {
+		start.Metadata = map[string]string{"variant": s.Variant}
+	}
+	t.observer.StartConversation(ctx, start)
+}
+
+// Exchanged tracks a user message and the reply it got, including the
+// transfer or guardrail decision behind it and the end of the session.
+func (t *Tracker) Exchanged(ctx context.Context, s *session.Session, message string, reply *sessionservice.Reply) {
+	id := t.ensure(ctx, s)
+
+	t.track(t.observer.TrackInteraction(ctx, id, types.Interaction{Speaker: speakerCustomer, Content: message}), s)
+	if reply.Action != nil {
+		t.track(t.observer.TrackDecision(ctx, id, decision(reply.Action)), s)
+	} else if reply.Blocked {
+		t.track(t.observer.TrackDecision(ctx, id, types.Decision{
+			DecisionType: "guardrail",
+			Option:       "refuse",
+		}), s)
+	}
+	t.track(t.observer.TrackInteraction(ctx, id, types.Interaction{Speaker: speakerAgent, Content: reply.Content}), s)
+
+	if reply.Ended {
+		t.Ended(ctx, s)
+	}
+}
+
+// Ended tracks the end of a session.
+func (t *Tracker) Ended(ctx context.Context, s *session.Session) {
+	id := t.ensure(ctx, s)
+
+	end := types.ConversationEnd{Resolution: resolution(s.EndReason)}
+	if s.EndReason != "" {
+		end.Metadata = map[string]string{"end_reason": s.EndReason}
+	}
+	if s.EndedAt != nil {
+		end.EndTime = *s.EndedAt
+	}
+	t.track(t.observer.EndConversation(ctx, id, end), s)
+}
+
+// ensure starts tracking a session created before this instance was running.
+func (t *Tracker) ensure(ctx context.Context, s *session.Session) string {
+	id := s.ID.String()
+	if _, err := t.observer.GetConversation(id); err != nil {
+		t.Started(ctx, s)
+	}
+	return id
+}
+
+func (t *Tracker) track(err error, s *session.Session) {
+	if err != nil {
+		t.logger.Warn("failed to track session",
+			zap.String("session_id", s.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// resolution maps a session end reason to a conversation resolution.
+func resolution(endReason string) string {
+	switch endReason {
+	case "":
+		return "solved"
+	case session.EndReasonTransfer:
+		return "transferred"
+	default:
+		return "abandoned"
+	}
+}
+
+// decision describes a channel action as an observed decision.
+func decision(a *actions.Action) types.Decision {
+	d := types.Decision{DecisionType: string(a.Type)}
+	switch {
+	case a.Transfer != nil:
+		d.Option, d.Reason = a.Transfer.Target, a.Transfer.Reason
+	case a.EndCall != nil:
+		d.Reason = a.EndCall.Reason
+	case a.ToolCall != nil:
+		d.Option = a.ToolCall.Name
+	}
+	return d
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+)
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint
+// violation.
+const uniqueViolation = "23505"
+
+// agentColumns are the columns scanned by scanAgent, in order.
+const agentColumns = `id, tenant_id, name, description, model, system_prompt, tools, routing, safety, graph, created_at, updated_at`
+
+// AgentRepository implements registry.Repository on the agents table.
+type AgentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAgentRepository creates a new AgentRepository.
+func NewAgentRepository(pool *pgxpool.Pool) *AgentRepository {
+	return &AgentRepository{pool: pool}
+}
+
+// Create persists a new agent.
+func (r *AgentRepository) Create(ctx context.Context, d *agent.Definition) error {
+	tools, routing, safety, graph, err := marshalAgent(d)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO agents (`+agentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, d.ID, d.TenantID, d.Name, d.Description, d.Model, d.SystemPrompt, tools, routing, safety, graph, d.CreatedAt, d.UpdatedAt)
+	if isUniqueViolation(err) {
+		return agent.ErrNameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create agent: %w", err)
+	}
+	return nil
+}
+
+// Get retrieves an agent of the tenant.
+func (r *AgentRepository) Get(ctx context.Context, tenantID, id uuid.UUID) (*agent.Definition, error) {
+	d, err := scanAgent(r.pool.QueryRow(ctx, `
+		SELECT `+agentColumns+`
+		FROM agents
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, agent.ErrDefinitionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan agent: %w", err)
+	}
+	return d, nil
+}
+
+// List returns the agents of the tenant by name.
+func (r *AgentRepository) List(ctx context.Context, tenantID uuid.UUID) ([]*agent.Definition, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+agentColumns+`
+		FROM agents
+		WHERE tenant_id = $1
+		ORDER BY name
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer rows.Close()
+
+	var agents []*agent.Definition
+	for rows.Next() {
+		d, err := scanAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	return agents, nil
+}
+
+// Update saves the editable fields of an agent.
+func (r *AgentRepository) Update(ctx context.Context, d *agent.Definition) error {
+	tools, routing, safety, graph, err := marshalAgent(d)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE agents
+		SET name = $3, description = $4, model = $5, system_prompt = $6,
+			tools = $7, routing = $8, safety = $9, graph = $10, updated_at = $11
+		WHERE tenant_id = $1 AND id = $2
+	`, d.TenantID, d.ID, d.Name, d.Description, d.Model, d.SystemPrompt, tools, routing, safety, graph, d.UpdatedAt)
+	if isUniqueViolation(err) {
+		return agent.ErrNameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update agent: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return agent.ErrDefinitionNotFound
+	}
+	return nil
+}
+
+// Delete removes an agent of the tenant.
+func (r *AgentRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM agents
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete agent: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return agent.ErrDefinitionNotFound
+	}
+	return nil
+}
+
+// marshalAgent encodes the JSONB columns of an agent; graph is NULL for
+// agents without one.
+func marshalAgent(d *agent.Definition) (tools, routing, safety, graph []byte, err error) {
+	if tools, err = json.Marshal(d.Tools); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to marshal agent tools: %w", err)
+	}
+	if routing, err = json.Marshal(d.Routing); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to marshal agent routing: %w", err)
+	}
+	if safety, err = json.Marshal(d.Safety); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("failed to marshal agent safety: %w", err)
+	}
+	if d.Graph != nil {
+		if graph, err = json.Marshal(d.Graph); err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("failed to marshal agent graph: %w", err)
+		}
+	}
+	return tools, routing, safety, graph, nil
+}
+
+func scanAgent(row pgx.Row) (*agent.Definition, error) {
+	var d agent.Definition
+	var tools, routing, safety, graph []byte
+	if err := row.Scan(&d.ID, &d.TenantID, &d.Name, &d.Description, &d.Model, &d.SystemPrompt,
+		&tools, &routing, &safety, &graph, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tools, &d.Tools); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent tools: %w", err)
+	}
+	if err := json.Unmarshal(routing, &d.Routing); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent routing: %w", err)
+	}
+	if err := json.Unmarshal(safety, &d.Safety); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal agent safety: %w", err)
+	}
+	if graph != nil {
+		if err := json.Unmarshal(graph, &d.Graph); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal agent graph: %w", err)
+		}
+	}
+	return &d, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
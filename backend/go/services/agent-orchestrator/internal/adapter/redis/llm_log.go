@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/llmlog"
+)
+
+// LLMLogRepository stores redacted LLM exchanges for a retention period.
+type LLMLogRepository struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewLLMLogRepository creates a new Redis-based LLM log repository.
+func NewLLMLogRepository(client *redis.Client, retention time.Duration) *LLMLogRepository {
+	return &LLMLogRepository{
+		client:    client,
+		retention: retention,
+	}
+}
+
+// IsTenantEnabled reports whether a tenant opted in to LLM logging.
+func (r *LLMLogRepository) IsTenantEnabled(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	n, err := r.client.Exists(ctx, tenantLoggingKey(tenantID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to get llm logging toggle: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SetTenantEnabled turns LLM logging on or off for a tenant.
+func (r *LLMLogRepository) SetTenantEnabled(ctx context.Context, tenantID uuid.UUID, enabled bool) error {
+	var err error
+	if enabled {
+		err = r.client.Set(ctx, tenantLoggingKey(tenantID), "1", 0).Err()
+	} else {
+		err = r.client.Del(ctx, tenantLoggingKey(tenantID)).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set llm logging toggle: %w", err)
+	}
+	return nil
+}
+
+// Save stores a record and indexes it by session. Both expire after the
+// retention period.
+func (r *LLMLogRepository) Save(ctx context.Context, record *llmlog.Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal llm log: %w", err)
+	}
+
+	index := sessionLogsKey(record.SessionID)
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, llmLogKey(record.ID), data, r.retention)
+	pipe.RPush(ctx, index, record.ID.String())
+	pipe.Expire(ctx, index, r.retention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save llm log: %w", err)
+	}
+
+	return nil
+}
+
+// ListBySession returns the retained records of a session, oldest first.
+func (r *LLMLogRepository) ListBySession(ctx context.Context, sessionID uuid.UUID) ([]*llmlog.Record, error) {
+	ids, err := r.client.LRange(ctx, sessionLogsKey(sessionID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list llm logs: %w", err)
+	}
+
+	records := make([]*llmlog.Record, 0, len(ids))
+	for _, raw := range ids {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			continue
+		}
+		data, err := r.client.Get(ctx, llmLogKey(id)).Bytes()
+		if err == redis.Nil {
+			continue // expired
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get llm log: %w", err)
+		}
+
+		var record llmlog.Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal llm log: %w", err)
+		}
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+func llmLogKey(id uuid.UUID) string {
+	return fmt.Sprintf("llmlog:%s", id)
+}
+
+func sessionLogsKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("llmlog:session:%s", sessionID)
+}
+
+func tenantLoggingKey(tenantID uuid.UUID) string {
+	return fmt.Sprintf("llmlog:tenant:%s:enabled", tenantID)
+}
@@ -0,0 +1,122 @@
+// Package redis provides Redis-based implementations.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// ErrSessionNotFound is returned when a session does not exist or expired.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionRepository implements session persistence using Redis.
+type SessionRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewSessionRepository creates a new Redis-based session repository.
+func NewSessionRepository(client *redis.Client, ttl time.Duration) *SessionRepository {
+	return &SessionRepository{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// Save stores a session in Redis, refreshing its TTL, and increments its
+// version. It fails with session.ErrVersionConflict when the stored session
+// is at another version than s, i.e. another request or replica saved it
+// since s was loaded.
+func (r *SessionRepository) Save(ctx context.Context, s *session.Session) error {
+	key, versionKey := sessionKey(s.ID), sessionVersionKey(s.ID)
+
+	saved := *s
+	saved.Version++
+	data, err := json.Marshal(&saved)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	err = r.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := tx.Get(ctx, versionKey).Int64()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if stored != s.Version {
+			return session.ErrVersionConflict
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, r.ttl)
+			pipe.Set(ctx, versionKey, saved.Version, r.ttl)
+			return nil
+		})
+		return err
+	}, versionKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		err = session.ErrVersionConflict
+	}
+	if errors.Is(err, session.ErrVersionConflict) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	s.Version = saved.Version
+	return nil
+}
+
+// Version returns the stored version of a session without loading it.
+func (r *SessionRepository) Version(ctx context.Context, id uuid.UUID) (int64, error) {
+	version, err := r.client.Get(ctx, sessionVersionKey(id)).Int64()
+	if err == redis.Nil {
+		return 0, ErrSessionNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get session version: %w", err)
+	}
+	return version, nil
+}
+
+// Get retrieves a session by ID.
+func (r *SessionRepository) Get(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	data, err := r.client.Get(ctx, sessionKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	var s session.Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	return &s, nil
+}
+
+// Delete removes a session.
+func (r *SessionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.client.Del(ctx, sessionKey(id), sessionVersionKey(id)).Err(); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func sessionKey(id uuid.UUID) string {
+	return fmt.Sprintf("session:%s", id)
+}
+
+func sessionVersionKey(id uuid.UUID) string {
+	return fmt.Sprintf("session:%s:version", id)
+}
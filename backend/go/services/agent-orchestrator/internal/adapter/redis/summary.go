@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/summary"
+)
+
+// ErrSummaryNotFound is returned when a conversation has no summary.
+var ErrSummaryNotFound = errors.New("summary not found")
+
+// SummaryRepository stores conversation summaries for a retention period.
+type SummaryRepository struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewSummaryRepository creates a new Redis-based summary repository.
+func NewSummaryRepository(client *redis.Client, retention time.Duration) *SummaryRepository {
+	return &SummaryRepository{
+		client:    client,
+		retention: retention,
+	}
+}
+
+// Save stores the summary of a conversation. The summary of a known customer
+// also becomes their latest one.
+func (r *SummaryRepository) Save(ctx context.Context, s *summary.Summary) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, summaryKey(s.ConversationID), data, r.retention)
+	if s.CustomerID != "" {
+		pipe.Set(ctx, customerSummaryKey(s.TenantID, s.CustomerID), s.ConversationID.String(), r.retention)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the summary of a conversation.
+func (r *SummaryRepository) Get(ctx context.Context, conversationID uuid.UUID) (*summary.Summary, error) {
+	data, err := r.client.Get(ctx, summaryKey(conversationID)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrSummaryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get summary: %w", err)
+	}
+
+	var s summary.Summary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal summary: %w", err)
+	}
+
+	return &s, nil
+}
+
+// LatestForCustomer retrieves the summary of a customer's last conversation,
+// or nil when there is none within the retention period.
+func (r *SummaryRepository) LatestForCustomer(ctx context.Context, tenantID uuid.UUID, customerID string) (*summary.Summary, error) {
+	id, err := r.client.Get(ctx, customerSummaryKey(tenantID, customerID)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer summary: %w", err)
+	}
+
+	conversationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid customer summary reference %q: %w", id, err)
+	}
+	s, err := r.Get(ctx, conversationID)
+	if errors.Is(err, ErrSummaryNotFound) {
+		return nil, nil
+	}
+	return s, err
+}
+
+func customerSummaryKey(tenantID uuid.UUID, customerID string) string {
+	return fmt.Sprintf("summary:customer:%s:%s", tenantID, customerID)
+}
+
+func summaryKey(conversationID uuid.UUID) string {
+	return fmt.Sprintf("summary:%s", conversationID)
+}
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+)
+
+// microsPerUnit stores costs as integer millionths of a dollar so concurrent
+// increments stay exact.
+const microsPerUnit = 1_000_000
+
+// TenantSpendRepository accumulates monthly LLM spend per tenant.
+type TenantSpendRepository struct {
+	client *redis.Client
+}
+
+// NewTenantSpendRepository creates a new Redis-based tenant spend repository.
+func NewTenantSpendRepository(client *redis.Client) *TenantSpendRepository {
+	return &TenantSpendRepository{client: client}
+}
+
+// Add adds a charge to the tenant total of its month.
+func (r *TenantSpendRepository) Add(ctx context.Context, tenantID uuid.UUID, charge *billing.Charge) error {
+	key := tenantSpendKey(tenantID, billing.MonthOf(charge.CreatedAt))
+
+	pipe := r.client.TxPipeline()
+	pipe.HIncrBy(ctx, key, "prompt_tokens", int64(charge.PromptTokens))
+	pipe.HIncrBy(ctx, key, "completion_tokens", int64(charge.CompletionTokens))
+	pipe.HIncrBy(ctx, key, "cost_micros", int64(math.Round(charge.Cost*microsPerUnit)))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to add tenant spend: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the tenant spend of a month (YYYY-MM).
+func (r *TenantSpendRepository) Get(ctx context.Context, tenantID uuid.UUID, month string) (*billing.TenantSpend, error) {
+	fields, err := r.client.HGetAll(ctx, tenantSpendKey(tenantID, month)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant spend: %w", err)
+	}
+
+	spend := &billing.TenantSpend{
+		TenantID: tenantID,
+		Month:    month,
+		Currency: billing.Currency,
+	}
+	spend.PromptTokens, _ = strconv.ParseInt(fields["prompt_tokens"], 10, 64)
+	spend.CompletionTokens, _ = strconv.ParseInt(fields["completion_tokens"], 10, 64)
+	micros, _ := strconv.ParseInt(fields["cost_micros"], 10, 64)
+	spend.Cost = float64(micros) / microsPerUnit
+
+	return spend, nil
+}
+
+func tenantSpendKey(tenantID uuid.UUID, month string) string {
+	return fmt.Sprintf("spend:tenant:%s:%s", tenantID, month)
+}
@@ -0,0 +1,135 @@
+// Package tenant provides tenant manager client.
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+)
+
+// Client is an HTTP client for tenant-manager service.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new tenant manager client.
+func NewClient(baseURL string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: tracing.Transport(nil),
+		},
+		logger: logger,
+	}
+}
+
+// GetAgentConfig retrieves agent configuration for a tenant.
+// GET /api/v1/tenants/{tenant_id}/agent-config
+func (c *Client) GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*agent.Config, error) {
+	url := fmt.Sprintf("%s/api/v1/tenants/%s/agent-config", c.baseURL, tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var config agent.Config
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	c.logger.Debug("agent config retrieved",
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("agent_id", config.AgentID),
+	)
+
+	return &config, nil
+}
+
+// SummarizationEnabled reports whether a tenant has post-call summaries
+// enabled in its AI agent settings.
+// GET /api/v1/tenants/{tenant_id}
+func (c *Client) SummarizationEnabled(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	url := fmt.Sprintf("%s/api/v1/tenants/%s", c.baseURL, tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var tenant struct {
+		Settings struct {
+			AIAgent struct {
+				EnableSummarization bool `json:"enable_summarization"`
+			} `json:"ai_agent"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tenant); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return tenant.Settings.AIAgent.EnableSummarization, nil
+}
+
+// GetPlan retrieves the subscription plan of a tenant.
+// GET /api/v1/tenants/{tenant_id}
+func (c *Client) GetPlan(ctx context.Context, tenantID uuid.UUID) (entitlements.Plan, error) {
+	url := fmt.Sprintf("%s/api/v1/tenants/%s", c.baseURL, tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var tenant struct {
+		Plan string `json:"plan"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tenant); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return entitlements.ParsePlan(tenant.Plan)
+}
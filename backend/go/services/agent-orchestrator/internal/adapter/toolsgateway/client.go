@@ -0,0 +1,58 @@
+// Package toolsgateway provides a tools-gateway client.
+package toolsgateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+)
+
+// Client is an HTTP client for tools-gateway service.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new tools-gateway client.
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: tracing.Transport(nil),
+		},
+	}
+}
+
+// ToolExists reports whether the tenant has a tool by the ID.
+// GET /api/v1/tools/{id}
+func (c *Client) ToolExists(ctx context.Context, tenantID uuid.UUID, toolID string) (bool, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/tools/%s", c.baseURL, url.PathEscape(toolID))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Tenant-ID", tenantID.String())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+}
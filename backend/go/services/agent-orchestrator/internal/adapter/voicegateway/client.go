@@ -0,0 +1,77 @@
+// Package voicegateway provides a voice-gateway client.
+package voicegateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+)
+
+// Client is an HTTP client for voice-gateway service.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewClient creates a new voice-gateway client.
+func NewClient(baseURL string, timeout time.Duration, logger *zap.Logger) *Client {
+	return &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: tracing.Transport(nil),
+		},
+		logger: logger,
+	}
+}
+
+// transferRequest is the voice-gateway transfer request body.
+type transferRequest struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TransferCall transfers a call to a queue, agent or external number.
+// POST /api/v1/calls/{call_id}/transfer
+func (c *Client) TransferCall(ctx context.Context, callID uuid.UUID, transferType, target, reason string) error {
+	url := fmt.Sprintf("%s/api/v1/calls/%s/transfer", c.baseURL, callID)
+
+	body, err := json.Marshal(transferRequest{Type: transferType, Target: target, Reason: reason})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	c.logger.Info("call transfer requested",
+		zap.String("call_id", callID.String()),
+		zap.String("type", transferType),
+		zap.String("target", target),
+	)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package admission
+
+import (
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics reports the requests each tenant has in flight and queued.
+type Metrics struct {
+	inFlight *prometheus.GaugeVec
+	queued   *prometheus.GaugeVec
+	rejects  *prometheus.CounterVec
+}
+
+// NewMetrics registers the scheduler metrics with reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	return &Metrics{
+		inFlight: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "agent_orchestrator",
+			Subsystem: "llm",
+			Name:      "in_flight",
+			Help:      "LLM requests holding a worker slot, by tenant",
+		}, []string{"tenant_id"}),
+		queued: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "agent_orchestrator",
+			Subsystem: "llm",
+			Name:      "queued",
+			Help:      "LLM requests waiting for a worker slot, by tenant",
+		}, []string{"tenant_id"}),
+		rejects: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "agent_orchestrator",
+			Subsystem: "llm",
+			Name:      "rejected_total",
+			Help:      "LLM requests turned away at the tenant's concurrency limit, by tenant",
+		}, []string{"tenant_id"}),
+	}
+}
+
+func (m *Metrics) setInFlight(tenantID uuid.UUID, n int) {
+	if m != nil {
+		m.inFlight.WithLabelValues(tenantID.String()).Set(float64(n))
+	}
+}
+
+func (m *Metrics) setQueued(tenantID uuid.UUID, n int) {
+	if m != nil {
+		m.queued.WithLabelValues(tenantID.String()).Set(float64(n))
+	}
+}
+
+func (m *Metrics) rejected(tenantID uuid.UUID) {
+	if m != nil {
+		m.rejects.WithLabelValues(tenantID.String()).Inc()
+	}
+}
@@ -0,0 +1,288 @@
+// Package admission shares the orchestrator's LLM throughput fairly between
+// tenants.
+package admission
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+// ErrTenantBusy is returned when a tenant already has as many requests in
+// flight and queued as its plan allows.
+var ErrTenantBusy = errors.New("tenant concurrency limit reached")
+
+// DefaultPlanTTL is how long a tenant's plan is reused before it is fetched
+// again.
+const DefaultPlanTTL = 5 * time.Minute
+
+// PlanProvider provides the plan of a tenant.
+type PlanProvider interface {
+	GetPlan(ctx context.Context, tenantID uuid.UUID) (entitlements.Plan, error)
+}
+
+// Config configures the scheduler.
+type Config struct {
+	Capacity    int               // worker slots shared by all tenants
+	DefaultPlan entitlements.Plan // used when a tenant's plan can't be fetched
+	PlanTTL     time.Duration
+}
+
+// cachedPlan is a fetched plan and when it expires.
+type cachedPlan struct {
+	plan      entitlements.Plan
+	expiresAt time.Time
+}
+
+// waiter is a request queued for a worker slot.
+type waiter struct {
+	granted chan struct{}
+}
+
+// tenantState is the requests of a tenant holding or waiting for a slot.
+type tenantState struct {
+	limit    int
+	inFlight int
+	queue    []*waiter
+}
+
+// Scheduler admits LLM requests into a fixed number of worker slots. Each
+// tenant may hold at most its plan's LimitMaxConcurrentLLMRequests requests
+// in flight or queued, so a burst from one tenant is turned away with
+// ErrTenantBusy instead of queuing ahead of everyone else. When slots are
+// scarce, a freed slot goes to the waiting tenant using the smallest share of
+// its limit, which weighs tenants by plan.
+type Scheduler struct {
+	plans   PlanProvider
+	config  Config
+	metrics *Metrics
+	now     func() time.Time
+	logger  *zap.Logger
+
+	mu       sync.Mutex
+	busy     int
+	tenants  map[uuid.UUID]*tenantState
+	planByID map[uuid.UUID]cachedPlan
+}
+
+// NewScheduler creates a scheduler. metrics may be nil.
+func NewScheduler(plans PlanProvider, config Config, metrics *Metrics, logger *zap.Logger) *Scheduler {
+	if config.Capacity <= 0 {
+		config.Capacity = 64
+	}
+	if !entitlements.IsValidPlan(config.DefaultPlan) {
+		config.DefaultPlan = entitlements.PlanStarter
+	}
+	if config.PlanTTL <= 0 {
+		config.PlanTTL = DefaultPlanTTL
+	}
+	return &Scheduler{
+		plans:    plans,
+		config:   config,
+		metrics:  metrics,
+		now:      time.Now,
+		logger:   logger,
+		tenants:  make(map[uuid.UUID]*tenantState),
+		planByID: make(map[uuid.UUID]cachedPlan),
+	}
+}
+
+// Acquire waits for a worker slot for a tenant's request and returns the
+// function that frees it. It fails with ErrTenantBusy when the tenant is at
+// its limit, or with the context error if ctx ends while waiting.
+func (s *Scheduler) Acquire(ctx context.Context, tenantID uuid.UUID) (func(), error) {
+	limit := s.limit(ctx, tenantID)
+
+	s.mu.Lock()
+	t := s.tenants[tenantID]
+	if t == nil {
+		t = &tenantState{}
+		s.tenants[tenantID] = t
+	}
+	t.limit = limit
+	if limit != entitlements.Unlimited && t.inFlight+len(t.queue) >= limit {
+		s.forget(tenantID, t)
+		s.mu.Unlock()
+		s.metrics.rejected(tenantID)
+		return nil, ErrTenantBusy
+	}
+
+	if s.busy < s.config.Capacity && !s.anyQueued() {
+		s.grant(tenantID, t)
+		s.mu.Unlock()
+		return s.releaser(tenantID), nil
+	}
+
+	w := &waiter{granted: make(chan struct{})}
+	t.queue = append(t.queue, w)
+	s.metrics.setQueued(tenantID, len(t.queue))
+	s.mu.Unlock()
+
+	select {
+	case <-w.granted:
+		return s.releaser(tenantID), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-w.granted:
+			// Granted while giving up: hand the slot on
+			s.release(tenantID)
+		default:
+			t.queue = removeWaiter(t.queue, w)
+			s.metrics.setQueued(tenantID, len(t.queue))
+			s.forget(tenantID, t)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// InFlight returns the number of requests of a tenant holding a slot.
+func (s *Scheduler) InFlight(tenantID uuid.UUID) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t := s.tenants[tenantID]; t != nil {
+		return t.inFlight
+	}
+	return 0
+}
+
+// releaser returns a function that frees a slot of the tenant once.
+func (s *Scheduler) releaser(tenantID uuid.UUID) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.release(tenantID)
+		})
+	}
+}
+
+// release frees a slot of the tenant and hands free slots to waiting
+// tenants. Callers hold s.mu.
+func (s *Scheduler) release(tenantID uuid.UUID) {
+	t := s.tenants[tenantID]
+	t.inFlight--
+	s.busy--
+	s.metrics.setInFlight(tenantID, t.inFlight)
+	s.forget(tenantID, t)
+
+	for s.busy < s.config.Capacity {
+		nextID, next := s.next()
+		if next == nil {
+			return
+		}
+		w := next.queue[0]
+		next.queue = next.queue[1:]
+		s.metrics.setQueued(nextID, len(next.queue))
+		s.grant(nextID, next)
+		close(w.granted)
+	}
+}
+
+// next returns the waiting tenant using the smallest share of its limit.
+// Callers hold s.mu.
+func (s *Scheduler) next() (uuid.UUID, *tenantState) {
+	var bestID uuid.UUID
+	var best *tenantState
+	for id, t := range s.tenants {
+		if len(t.queue) == 0 {
+			continue
+		}
+		if best == nil || share(t) < share(best) {
+			bestID, best = id, t
+		}
+	}
+	return bestID, best
+}
+
+// grant gives a slot to the tenant. Callers hold s.mu.
+func (s *Scheduler) grant(tenantID uuid.UUID, t *tenantState) {
+	t.inFlight++
+	s.busy++
+	s.metrics.setInFlight(tenantID, t.inFlight)
+}
+
+// anyQueued reports whether a request is waiting for a slot. Callers hold
+// s.mu.
+func (s *Scheduler) anyQueued() bool {
+	for _, t := range s.tenants {
+		if len(t.queue) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// forget drops the state of an idle tenant. Callers hold s.mu.
+func (s *Scheduler) forget(tenantID uuid.UUID, t *tenantState) {
+	if t.inFlight == 0 && len(t.queue) == 0 {
+		delete(s.tenants, tenantID)
+	}
+}
+
+// limit returns the concurrency limit of the tenant's plan. A plan that
+// can't be fetched falls back to the last known plan, then the default plan.
+func (s *Scheduler) limit(ctx context.Context, tenantID uuid.UUID) int {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.planByID[tenantID]
+	s.mu.Unlock()
+
+	plan := cached.plan
+	if !ok || !now.Before(cached.expiresAt) {
+		fetched, err := s.plans.GetPlan(ctx, tenantID)
+		switch {
+		case err == nil:
+			plan = fetched
+			s.mu.Lock()
+			s.planByID[tenantID] = cachedPlan{plan: plan, expiresAt: now.Add(s.config.PlanTTL)}
+			s.mu.Unlock()
+		case !ok:
+			plan = s.config.DefaultPlan
+			s.logger.Warn("failed to get tenant plan, using default plan",
+				zap.String("tenant_id", tenantID.String()),
+				zap.String("plan", string(plan)),
+				zap.Error(err),
+			)
+		default:
+			s.logger.Warn("failed to refresh tenant plan, using cached plan",
+				zap.String("tenant_id", tenantID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	limit := entitlements.Limit(plan, entitlements.LimitMaxConcurrentLLMRequests)
+	if limit == 0 {
+		limit = entitlements.Limit(s.config.DefaultPlan, entitlements.LimitMaxConcurrentLLMRequests)
+	}
+	return limit
+}
+
+// share is the fraction of its limit a tenant uses; unlimited tenants weigh
+// like a large plan.
+func share(t *tenantState) float64 {
+	limit := t.limit
+	if limit == entitlements.Unlimited {
+		limit = 1 << 10
+	}
+	return float64(t.inFlight) / float64(limit)
+}
+
+func removeWaiter(queue []*waiter, w *waiter) []*waiter {
+	for i, q := range queue {
+		if q == w {
+			return append(queue[:i], queue[i+1:]...)
+		}
+	}
+	return queue
+}
@@ -0,0 +1,163 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+type staticPlans map[uuid.UUID]entitlements.Plan
+
+func (p staticPlans) GetPlan(ctx context.Context, tenantID uuid.UUID) (entitlements.Plan, error) {
+	plan, ok := p[tenantID]
+	if !ok {
+		return "", errors.New("tenant not found")
+	}
+	return plan, nil
+}
+
+// acquireAll takes n slots for a tenant, failing the test otherwise.
+func acquireAll(t *testing.T, s *Scheduler, tenantID uuid.UUID, n int) []func() {
+	t.Helper()
+	var releases []func()
+	for i := 0; i < n; i++ {
+		release, err := s.Acquire(context.Background(), tenantID)
+		if err != nil {
+			t.Fatalf("Acquire %d failed: %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+	return releases
+}
+
+func TestScheduler_TenantAtLimitDoesNotBlockOthers(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	metrics := NewMetrics(prometheus.NewRegistry())
+	s := NewScheduler(staticPlans{a: entitlements.PlanStarter, b: entitlements.PlanStarter}, Config{Capacity: 100}, metrics, zap.NewNop())
+
+	limit := entitlements.Limit(entitlements.PlanStarter, entitlements.LimitMaxConcurrentLLMRequests)
+	acquireAll(t, s, a, limit)
+
+	if _, err := s.Acquire(context.Background(), a); !errors.Is(err, ErrTenantBusy) {
+		t.Fatalf("Expected ErrTenantBusy for tenant A at its limit, got %v", err)
+	}
+
+	release, err := s.Acquire(context.Background(), b)
+	if err != nil {
+		t.Fatalf("Expected tenant B to proceed, got %v", err)
+	}
+	release()
+
+	if got := testutil.ToFloat64(metrics.inFlight.WithLabelValues(a.String())); got != float64(limit) {
+		t.Errorf("Expected %d requests in flight for tenant A, got %v", limit, got)
+	}
+	if got := testutil.ToFloat64(metrics.rejects.WithLabelValues(a.String())); got != 1 {
+		t.Errorf("Expected 1 rejection for tenant A, got %v", got)
+	}
+}
+
+func TestScheduler_ReleaseFreesTenantSlot(t *testing.T) {
+	a := uuid.New()
+	s := NewScheduler(staticPlans{a: entitlements.PlanStarter}, Config{Capacity: 100}, nil, zap.NewNop())
+
+	limit := entitlements.Limit(entitlements.PlanStarter, entitlements.LimitMaxConcurrentLLMRequests)
+	releases := acquireAll(t, s, a, limit)
+	releases[0]()
+	releases[0]() // releasing twice frees one slot
+
+	if s.InFlight(a) != limit-1 {
+		t.Fatalf("Expected %d in flight, got %d", limit-1, s.InFlight(a))
+	}
+	if _, err := s.Acquire(context.Background(), a); err != nil {
+		t.Errorf("Expected a freed slot to be reusable, got %v", err)
+	}
+}
+
+func TestScheduler_FreedSlotGoesToLeastLoadedTenant(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	s := NewScheduler(staticPlans{a: entitlements.PlanEnterprise, b: entitlements.PlanStarter}, Config{Capacity: 3}, nil, zap.NewNop())
+
+	releases := acquireAll(t, s, a, 3)
+
+	// Both tenants wait for the full capacity
+	grantedA, grantedB := make(chan struct{}), make(chan struct{})
+	go func() {
+		if _, err := s.Acquire(context.Background(), a); err == nil {
+			close(grantedA)
+		}
+	}()
+	waitQueued(t, s, a)
+	go func() {
+		if _, err := s.Acquire(context.Background(), b); err == nil {
+			close(grantedB)
+		}
+	}()
+	waitQueued(t, s, b)
+
+	releases[0]()
+
+	select {
+	case <-grantedB:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the freed slot to go to tenant B, which has none")
+	}
+	select {
+	case <-grantedA:
+		t.Error("Tenant A should still be waiting")
+	default:
+	}
+}
+
+func TestScheduler_WaitEndsWithContext(t *testing.T) {
+	a := uuid.New()
+	s := NewScheduler(staticPlans{a: entitlements.PlanEnterprise}, Config{Capacity: 1}, nil, zap.NewNop())
+	release := acquireAll(t, s, a, 1)[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, a); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	release()
+	if _, err := s.Acquire(context.Background(), a); err != nil {
+		t.Errorf("Expected the abandoned wait to leave no queued request, got %v", err)
+	}
+}
+
+func TestScheduler_UnknownPlanUsesDefault(t *testing.T) {
+	s := NewScheduler(staticPlans{}, Config{Capacity: 100, DefaultPlan: entitlements.PlanStarter}, nil, zap.NewNop())
+
+	tenantID := uuid.New()
+	limit := entitlements.Limit(entitlements.PlanStarter, entitlements.LimitMaxConcurrentLLMRequests)
+	acquireAll(t, s, tenantID, limit)
+
+	if _, err := s.Acquire(context.Background(), tenantID); !errors.Is(err, ErrTenantBusy) {
+		t.Errorf("Expected the default plan limit, got %v", err)
+	}
+}
+
+// waitQueued waits until a tenant has a request queued.
+func waitQueued(t *testing.T, s *Scheduler, tenantID uuid.UUID) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		ts := s.tenants[tenantID]
+		queued := ts != nil && len(ts.queue) > 0
+		s.mu.Unlock()
+		if queued {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("request was never queued")
+}
@@ -0,0 +1,89 @@
+// Package cost accounts LLM token spend per conversation and tenant.
+package cost
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// TenantSpendStore accumulates spend per tenant.
+type TenantSpendStore interface {
+	Add(ctx context.Context, tenantID uuid.UUID, charge *billing.Charge) error
+}
+
+// Publisher publishes charges for billing.
+type Publisher interface {
+	PublishLLMCost(ctx context.Context, charge *billing.Charge) error
+}
+
+// Accountant prices LLM calls and records their spend on the session, the
+// tenant totals and the billing event stream.
+type Accountant struct {
+	prices    billing.PriceTable
+	tenants   TenantSpendStore
+	publisher Publisher
+	logger    *zap.Logger
+}
+
+// NewAccountant creates a new accountant.
+func NewAccountant(prices billing.PriceTable, tenants TenantSpendStore, publisher Publisher, logger *zap.Logger) *Accountant {
+	return &Accountant{
+		prices:    prices,
+		tenants:   tenants,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Charge records the spend of a completion on the session. Tenant totals and
+// events are best effort; failures are logged so the conversation continues.
+func (a *Accountant) Charge(ctx context.Context, s *session.Session, provider string, completion *llm.Completion) *billing.Charge {
+	if completion.Provider != "" {
+		provider = completion.Provider
+	}
+	charge := &billing.Charge{
+		ConversationID:   s.ID,
+		TenantID:         s.TenantID,
+		AgentID:          s.AgentID,
+		Variant:          s.Variant,
+		Provider:         provider,
+		Model:            completion.Model,
+		PromptTokens:     completion.Usage.PromptTokens,
+		CompletionTokens: completion.Usage.CompletionTokens,
+		CreatedAt:        time.Now().UTC(),
+	}
+
+	if price, ok := a.prices.Lookup(provider, completion.Model); ok {
+		charge.Cost = price.Cost(charge.PromptTokens, charge.CompletionTokens)
+		charge.Priced = true
+	} else {
+		a.logger.Warn("no price for model, tokens counted at zero cost",
+			zap.String("provider", provider),
+			zap.String("model", completion.Model),
+		)
+	}
+
+	s.AddSpend(charge.PromptTokens, charge.CompletionTokens, charge.Cost)
+
+	if err := a.tenants.Add(ctx, s.TenantID, charge); err != nil {
+		a.logger.Error("failed to add tenant spend",
+			zap.String("tenant_id", s.TenantID.String()),
+			zap.Error(err),
+		)
+	}
+	if err := a.publisher.PublishLLMCost(ctx, charge); err != nil {
+		a.logger.Error("failed to publish llm cost",
+			zap.String("session_id", s.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	return charge
+}
@@ -0,0 +1,67 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+type failingStore struct{ calls int }
+
+func (f *failingStore) Add(ctx context.Context, tenantID uuid.UUID, charge *billing.Charge) error {
+	f.calls++
+	return errors.New("redis down")
+}
+
+type failingPublisher struct{ calls int }
+
+func (f *failingPublisher) PublishLLMCost(ctx context.Context, charge *billing.Charge) error {
+	f.calls++
+	return errors.New("kafka down")
+}
+
+func TestAccountant_Charge(t *testing.T) {
+	store, pub := &failingStore{}, &failingPublisher{}
+	a := NewAccountant(billing.DefaultPrices, store, pub, zap.NewNop())
+	s := session.New(uuid.New(), "agent-receptionist")
+
+	charge := a.Charge(context.Background(), s, "openai", &llm.Completion{
+		Model: "gpt-4o-2024-08-06",
+		Usage: llm.Usage{PromptTokens: 2000, CompletionTokens: 1000},
+	})
+
+	// 2000 x 2.5 + 1000 x 10 per million
+	if diff := charge.Cost - 0.015; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected cost 0.015, got %f", charge.Cost)
+	}
+	if s.Spend.Cost != charge.Cost || s.Spend.TotalTokens() != 3000 {
+		t.Errorf("Session spend not updated: %+v", s.Spend)
+	}
+	if store.calls != 1 || pub.calls != 1 {
+		t.Error("Tenant store and publisher should be called even if they fail")
+	}
+}
+
+func TestAccountant_UnpricedModel(t *testing.T) {
+	a := NewAccountant(billing.DefaultPrices, &failingStore{}, &failingPublisher{}, zap.NewNop())
+	s := session.New(uuid.New(), "agent-receptionist")
+
+	charge := a.Charge(context.Background(), s, "acme", &llm.Completion{
+		Model: "mystery",
+		Usage: llm.Usage{PromptTokens: 10, CompletionTokens: 5},
+	})
+
+	if charge.Priced || charge.Cost != 0 {
+		t.Errorf("Unpriced model should cost zero, got %+v", charge)
+	}
+	if s.Spend.TotalTokens() != 15 {
+		t.Error("Tokens should still be counted")
+	}
+}
@@ -0,0 +1,282 @@
+// Package failover moves LLM calls to a fallback provider when the one a
+// tenant uses is down.
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/failover"
+)
+
+// Defaults of the provider health gate.
+const (
+	DefaultFailureThreshold = 3
+	DefaultCooldown         = 30 * time.Second
+	DefaultPlanTTL          = 5 * time.Minute
+)
+
+// ErrProvidersFailed is returned when every provider of the chain failed.
+var ErrProvidersFailed = errors.New("all LLM providers failed")
+
+// PlanProvider provides the plan of a tenant.
+type PlanProvider interface {
+	GetPlan(ctx context.Context, tenantID uuid.UUID) (entitlements.Plan, error)
+}
+
+// Publisher publishes the calls that failed over.
+type Publisher interface {
+	PublishLLMFailover(ctx context.Context, e *failover.Event) error
+}
+
+// Config configures the health gate.
+type Config struct {
+	// A provider failing FailureThreshold calls in a row is skipped for the
+	// Cooldown, then tried again by the next call
+	FailureThreshold int
+	Cooldown         time.Duration
+	PlanTTL          time.Duration
+}
+
+// providerHealth tracks the recent failures of a provider.
+type providerHealth struct {
+	failures  int
+	downUntil time.Time
+	lastError string
+}
+
+// cachedPlan is a fetched plan and when it expires.
+type cachedPlan struct {
+	plan      entitlements.Plan
+	expiresAt time.Time
+}
+
+// Client is an llm.Client that tries the providers of the tenant chain in
+// order. Providers down after repeated failures are tried last, so calls
+// don't wait on them while a healthy fallback is available.
+type Client struct {
+	providers map[string]llm.Client
+	chains    failover.Chains
+	plans     PlanProvider
+	publisher Publisher
+	config    Config
+	now       func() time.Time
+	logger    *zap.Logger
+
+	mu       sync.Mutex
+	health   map[string]*providerHealth
+	planByID map[uuid.UUID]cachedPlan
+}
+
+// NewClient creates a failover client over the named providers. plans and
+// publisher may be nil; without plans the plan chains never apply.
+func NewClient(providers map[string]llm.Client, chains failover.Chains, plans PlanProvider, publisher Publisher, config Config, logger *zap.Logger) *Client {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultCooldown
+	}
+	if config.PlanTTL <= 0 {
+		config.PlanTTL = DefaultPlanTTL
+	}
+	return &Client{
+		providers: providers,
+		chains:    chains,
+		plans:     plans,
+		publisher: publisher,
+		config:    config,
+		now:       time.Now,
+		logger:    logger,
+		health:    make(map[string]*providerHealth),
+		planByID:  make(map[uuid.UUID]cachedPlan),
+	}
+}
+
+// Provider returns the primary provider of the default chain. Completions
+// name the provider that actually served them.
+func (c *Client) Provider() string {
+	return c.chains.Default[0].Provider
+}
+
+// Complete runs the completion on the first provider of the tenant chain
+// that serves it. A call served by another than the primary is announced.
+func (c *Client) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	chain := c.chains.Resolve(req.TenantID, c.plan(ctx, req.TenantID))
+	primary := chain[0]
+
+	var lastErr, primaryErr error
+	for _, link := range c.order(chain) {
+		linkReq := req
+		if link.Model != "" {
+			linkReq.Model = link.Model
+		}
+
+		completion, err := c.providers[link.Provider].Complete(ctx, linkReq)
+		if err == nil {
+			c.succeeded(link.Provider)
+			completion.Provider = link.Provider
+			if link != primary {
+				c.failedOver(ctx, req, primary, failover.Link{Provider: link.Provider, Model: linkReq.Model}, primaryErr)
+			}
+			return completion, nil
+		}
+		// The caller gave up, no provider is to blame
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		c.failed(link.Provider, err)
+		lastErr = err
+		if link == primary {
+			primaryErr = err
+		}
+		c.logger.Warn("LLM provider failed",
+			zap.String("provider", link.Provider),
+			zap.String("model", linkReq.Model),
+			zap.String("tenant_id", req.TenantID.String()),
+			zap.Error(err),
+		)
+	}
+	return nil, fmt.Errorf("%w: %w", ErrProvidersFailed, lastErr)
+}
+
+// Check returns an error while a provider is down, for readiness checks.
+func (c *Client) Check(provider string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if h := c.health[provider]; h != nil && c.down(h) {
+			return fmt.Errorf("provider %s is down: %s", provider, h.lastError)
+		}
+		return nil
+	}
+}
+
+// order puts the links of healthy providers first, keeping the chain order.
+func (c *Client) order(chain failover.Chain) failover.Chain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ordered := make(failover.Chain, 0, len(chain))
+	var down failover.Chain
+	for _, link := range chain {
+		if h := c.health[link.Provider]; h != nil && c.down(h) {
+			down = append(down, link)
+			continue
+		}
+		ordered = append(ordered, link)
+	}
+	return append(ordered, down...)
+}
+
+func (c *Client) down(h *providerHealth) bool {
+	return c.now().Before(h.downUntil)
+}
+
+func (c *Client) succeeded(provider string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.health, provider)
+}
+
+func (c *Client) failed(provider string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h := c.health[provider]
+	if h == nil {
+		h = &providerHealth{}
+		c.health[provider] = h
+	}
+	h.failures++
+	h.lastError = err.Error()
+	if h.failures >= c.config.FailureThreshold {
+		if !c.down(h) {
+			c.logger.Error("LLM provider marked down",
+				zap.String("provider", provider),
+				zap.Int("failures", h.failures),
+				zap.Duration("cooldown", c.config.Cooldown),
+			)
+		}
+		h.downUntil = c.now().Add(c.config.Cooldown)
+	}
+}
+
+// failedOver publishes a call served by a fallback link. primaryErr is the
+// failure of the primary, nil when it was skipped as down.
+func (c *Client) failedOver(ctx context.Context, req llm.CompletionRequest, primary, to failover.Link, primaryErr error) {
+	e := &failover.Event{
+		TenantID:   req.TenantID,
+		SessionID:  req.SessionID,
+		From:       failover.Link{Provider: primary.Provider, Model: req.Model},
+		To:         to,
+		Reason:     failover.ReasonError,
+		OccurredAt: c.now().UTC(),
+	}
+	if primary.Model != "" {
+		e.From.Model = primary.Model
+	}
+	if primaryErr != nil {
+		e.Error = primaryErr.Error()
+	} else {
+		e.Reason = failover.ReasonUnhealthy
+		c.mu.Lock()
+		if h := c.health[primary.Provider]; h != nil {
+			e.Error = h.lastError
+		}
+		c.mu.Unlock()
+	}
+
+	c.logger.Warn("LLM call failed over",
+		zap.String("tenant_id", req.TenantID.String()),
+		zap.String("from", e.From.Provider),
+		zap.String("to", e.To.Provider),
+		zap.String("model", e.To.Model),
+	)
+
+	if c.publisher == nil {
+		return
+	}
+	if err := c.publisher.PublishLLMFailover(ctx, e); err != nil {
+		c.logger.Warn("failed to publish LLM failover", zap.Error(err))
+	}
+}
+
+// plan returns the cached plan of a tenant, or an empty plan when there are
+// no plan chains or it can't be fetched.
+func (c *Client) plan(ctx context.Context, tenantID uuid.UUID) entitlements.Plan {
+	if c.plans == nil || len(c.chains.Plans) == 0 || tenantID == uuid.Nil {
+		return ""
+	}
+
+	c.mu.Lock()
+	cached, ok := c.planByID[tenantID]
+	c.mu.Unlock()
+	if ok && c.now().Before(cached.expiresAt) {
+		return cached.plan
+	}
+
+	plan, err := c.plans.GetPlan(ctx, tenantID)
+	if err != nil {
+		c.logger.Warn("failed to get tenant plan, using the default LLM chain",
+			zap.String("tenant_id", tenantID.String()),
+			zap.Error(err),
+		)
+		return ""
+	}
+
+	c.mu.Lock()
+	c.planByID[tenantID] = cachedPlan{plan: plan, expiresAt: c.now().Add(c.config.PlanTTL)}
+	c.mu.Unlock()
+	return plan
+}
@@ -0,0 +1,174 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/failover"
+)
+
+// stubProvider fails while down and records the models it was asked for.
+type stubProvider struct {
+	down   bool
+	models []string
+}
+
+func (p *stubProvider) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	p.models = append(p.models, req.Model)
+	if p.down {
+		return nil, errors.New("503 service unavailable")
+	}
+	return &llm.Completion{Content: "Hello", Model: req.Model}, nil
+}
+
+func (p *stubProvider) Provider() string { return "stub" }
+
+type eventRecorder struct {
+	events []*failover.Event
+}
+
+func (r *eventRecorder) PublishLLMFailover(ctx context.Context, e *failover.Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+type fixedPlans map[uuid.UUID]entitlements.Plan
+
+func (f fixedPlans) GetPlan(ctx context.Context, tenantID uuid.UUID) (entitlements.Plan, error) {
+	return f[tenantID], nil
+}
+
+func newTestClient(chains failover.Chains, plans PlanProvider) (*Client, *stubProvider, *stubProvider, *eventRecorder) {
+	primary, secondary := &stubProvider{}, &stubProvider{}
+	recorder := &eventRecorder{}
+	client := NewClient(map[string]llm.Client{"openai": primary, "azure": secondary}, chains, plans, recorder, Config{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+	}, zap.NewNop())
+	return client, primary, secondary, recorder
+}
+
+var degradingChains = failover.Chains{Default: failover.Chain{{Provider: "openai"}, {Provider: "azure", Model: "gpt-4o-mini"}}}
+
+func request(tenantID uuid.UUID) llm.CompletionRequest {
+	return llm.CompletionRequest{Model: "gpt-4", TenantID: tenantID, SessionID: uuid.New()}
+}
+
+func TestClient_FallsBackToHealthySecondary(t *testing.T) {
+	client, primary, secondary, recorder := newTestClient(degradingChains, nil)
+	primary.down = true
+
+	completion, err := client.Complete(context.Background(), request(uuid.New()))
+	if err != nil {
+		t.Fatalf("Expected the secondary to answer, got %v", err)
+	}
+	if completion.Provider != "azure" || completion.Model != "gpt-4o-mini" {
+		t.Errorf("Expected azure degraded to gpt-4o-mini, got %s %s", completion.Provider, completion.Model)
+	}
+	if len(secondary.models) != 1 || secondary.models[0] != "gpt-4o-mini" {
+		t.Errorf("Expected the cheaper model asked of the secondary, got %v", secondary.models)
+	}
+
+	if len(recorder.events) != 1 {
+		t.Fatalf("Expected 1 failover event, got %d", len(recorder.events))
+	}
+	e := recorder.events[0]
+	if e.From.Provider != "openai" || e.From.Model != "gpt-4" || e.To.Provider != "azure" || e.Reason != failover.ReasonError || e.Error == "" {
+		t.Errorf("Unexpected failover event: %+v", e)
+	}
+}
+
+func TestClient_SkipsDownPrimaryUntilCooldown(t *testing.T) {
+	client, primary, _, recorder := newTestClient(degradingChains, nil)
+	now := time.Now()
+	client.now = func() time.Time { return now }
+	primary.down = true
+	ctx := context.Background()
+
+	// Two failures in a row mark the primary down
+	for i := 0; i < 2; i++ {
+		if _, err := client.Complete(ctx, request(uuid.New())); err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+	}
+	if err := client.Check("openai")(ctx); err == nil {
+		t.Error("Expected the primary reported down")
+	}
+
+	if _, err := client.Complete(ctx, request(uuid.New())); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if len(primary.models) != 2 {
+		t.Errorf("Expected the down primary skipped, got %d calls", len(primary.models))
+	}
+	if last := recorder.events[len(recorder.events)-1]; last.Reason != failover.ReasonUnhealthy {
+		t.Errorf("Expected an unhealthy failover, got %s", last.Reason)
+	}
+
+	// After the cooldown the recovered primary is tried again
+	primary.down = false
+	now = now.Add(2 * time.Minute)
+	completion, err := client.Complete(ctx, request(uuid.New()))
+	if err != nil || completion.Provider != "openai" {
+		t.Fatalf("Expected the primary back, got %+v, %v", completion, err)
+	}
+	if err := client.Check("openai")(ctx); err != nil {
+		t.Errorf("Expected the primary healthy, got %v", err)
+	}
+}
+
+func TestClient_ChainsPerTenantAndPlan(t *testing.T) {
+	enterprise, pinned := uuid.New(), uuid.New()
+	chains := failover.Chains{
+		Default: failover.Chain{{Provider: "openai"}},
+		Plans:   map[entitlements.Plan]failover.Chain{entitlements.PlanEnterprise: {{Provider: "openai"}, {Provider: "azure"}}},
+		Tenants: map[uuid.UUID]failover.Chain{pinned: {{Provider: "azure"}}},
+	}
+	plans := fixedPlans{enterprise: entitlements.PlanEnterprise, pinned: entitlements.PlanEnterprise}
+	client, primary, _, _ := newTestClient(chains, plans)
+	primary.down = true
+	ctx := context.Background()
+
+	if _, err := client.Complete(ctx, request(uuid.New())); !errors.Is(err, ErrProvidersFailed) {
+		t.Errorf("Expected a starter tenant without fallback to fail, got %v", err)
+	}
+	if completion, err := client.Complete(ctx, request(enterprise)); err != nil || completion.Provider != "azure" {
+		t.Errorf("Expected the enterprise chain to fall back, got %+v, %v", completion, err)
+	}
+
+	calls := len(primary.models)
+	if completion, err := client.Complete(ctx, request(pinned)); err != nil || completion.Provider != "azure" {
+		t.Errorf("Expected the tenant chain, got %+v, %v", completion, err)
+	}
+	if len(primary.models) != calls {
+		t.Error("Expected the tenant chain to leave out the primary")
+	}
+}
+
+func TestClient_CallerCancellationIsNotAFailure(t *testing.T) {
+	client, primary, secondary, recorder := newTestClient(degradingChains, nil)
+	primary.down = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		if _, err := client.Complete(ctx, request(uuid.New())); err == nil {
+			t.Fatal("Expected the cancelled call to fail")
+		}
+	}
+
+	if len(secondary.models) != 0 || len(recorder.events) != 0 {
+		t.Error("Expected no failover for a cancelled call")
+	}
+	if err := client.Check("openai")(context.Background()); err != nil {
+		t.Errorf("Expected the primary not blamed, got %v", err)
+	}
+}
@@ -0,0 +1,235 @@
+// Package graph runs the turns of a conversation through an agent graph.
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// DefaultNodeTimeout bounds the model call of a node that sets no timeout.
+const DefaultNodeTimeout = 20 * time.Second
+
+// DefaultLoopMessage answers a turn stopped by the loop guard.
+const DefaultLoopMessage = "I'm sorry, I couldn't find the right way to help with that. Could you rephrase your request?"
+
+// routerPrompt asks a router node to pick one of its intents.
+const routerPrompt = "You route caller messages between the specialists of a customer service team. " +
+	"Decide whether the caller message expresses one of these intents: %s. " +
+	"Answer with the exact intent name, or NONE if it expresses none of them."
+
+// ErrNodeTimeout is returned when a node takes longer than its timeout.
+var ErrNodeTimeout = errors.New("agent graph node timed out")
+
+// Publisher publishes the transitions of turns between nodes.
+type Publisher interface {
+	PublishGraphTransition(ctx context.Context, t *graph.Transition) error
+}
+
+// Config configures the execution of agent graphs.
+type Config struct {
+	NodeTimeout time.Duration // nodes without their own timeout
+	LoopMessage string
+	MaxTokens   int
+	Temperature float64
+}
+
+// Executor walks a turn through the nodes of a graph, from the entry to the
+// node that answers it.
+type Executor struct {
+	llmClient llm.Client
+	publisher Publisher
+	config    Config
+	logger    *zap.Logger
+}
+
+// NewExecutor creates a new graph executor.
+func NewExecutor(llmClient llm.Client, publisher Publisher, config Config, logger *zap.Logger) *Executor {
+	if config.NodeTimeout <= 0 {
+		config.NodeTimeout = DefaultNodeTimeout
+	}
+	if config.LoopMessage == "" {
+		config.LoopMessage = DefaultLoopMessage
+	}
+	return &Executor{
+		llmClient: llmClient,
+		publisher: publisher,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// Request is a turn to run through a graph.
+type Request struct {
+	Session *session.Session
+	Graph   *graph.Graph
+	Message string // the user message of the turn, already in the history
+	Model   string // default of nodes without a model
+
+	// SystemPrompt is the default of nodes without a prompt, and Messages
+	// builds the conversation sent to the answering node under its prompt
+	SystemPrompt string
+	Messages     func(systemPrompt string) []llm.Message
+}
+
+// Result is the answer of a turn and the nodes it went through.
+type Result struct {
+	Content     string
+	Node        string   // the node that answered
+	Path        []string // every node visited, from the entry
+	LoopGuard   bool     // the loop guard stopped the turn
+	Completions []*llm.Completion
+}
+
+// Run routes the turn from the entry until a node answers it. A turn that
+// would revisit a node, or take more transitions than the graph allows, is
+// stopped by the loop guard and answered with the loop message.
+func (e *Executor) Run(ctx context.Context, req Request) (*Result, error) {
+	g := req.Graph
+	node := g.Node(g.Entry)
+	if node == nil {
+		return nil, fmt.Errorf("%w: entry %q is not a node", graph.ErrInvalidGraph, g.Entry)
+	}
+
+	result := &Result{Path: []string{node.ID}}
+	visited := map[string]bool{node.ID: true}
+	for hop := 1; ; hop++ {
+		edge, intent, reason, err := e.route(ctx, req, node, result)
+		if err != nil {
+			return nil, err
+		}
+		if edge == nil {
+			break
+		}
+
+		t := e.transition(req.Session, node.ID, edge.To, reason, intent, hop)
+		if visited[edge.To] || hop > g.Hops() {
+			t.Reason = graph.ReasonLoopGuard
+			e.publish(ctx, t)
+			e.logger.Warn("agent graph loop guard triggered",
+				zap.String("session_id", req.Session.ID.String()),
+				zap.Strings("path", result.Path),
+				zap.String("to", edge.To),
+			)
+			result.Content = e.config.LoopMessage
+			result.Node = node.ID
+			result.LoopGuard = true
+			return result, nil
+		}
+		e.publish(ctx, t)
+
+		node = g.Node(edge.To)
+		visited[node.ID] = true
+		result.Path = append(result.Path, node.ID)
+	}
+
+	completion, err := e.complete(ctx, req, node, req.Messages(fallback(node.SystemPrompt, req.SystemPrompt)), e.config.MaxTokens)
+	if err != nil {
+		return nil, err
+	}
+	result.Completions = append(result.Completions, completion)
+	result.Content = completion.Content
+	result.Node = node.ID
+	return result, nil
+}
+
+// route picks the edge the turn leaves the node by: keywords first, then
+// the intent detected by the model, then the fallback. A nil edge means the
+// node answers.
+func (e *Executor) route(ctx context.Context, req Request, node *graph.Node, result *Result) (*graph.Edge, string, graph.Reason, error) {
+	if len(node.Edges) == 0 {
+		return nil, "", "", nil
+	}
+	if edge := node.MatchKeywords(req.Message); edge != nil {
+		return edge, "", graph.ReasonKeyword, nil
+	}
+
+	if intents := node.Intents(); len(intents) > 0 {
+		completion, err := e.complete(ctx, req, node, []llm.Message{
+			{Role: string(session.RoleSystem), Content: fmt.Sprintf(routerPrompt, strings.Join(intents, "; "))},
+			{Role: string(session.RoleUser), Content: req.Message},
+		}, 20)
+		if err != nil {
+			return nil, "", "", err
+		}
+		result.Completions = append(result.Completions, completion)
+
+		intent := strings.Trim(strings.TrimSpace(completion.Content), `."'`)
+		if edge := node.MatchIntent(intent); edge != nil {
+			return edge, edge.Intent, graph.ReasonIntent, nil
+		}
+	}
+
+	if edge := node.Fallback(); edge != nil {
+		return edge, "", graph.ReasonDefault, nil
+	}
+	return nil, "", "", nil
+}
+
+// complete calls the model of a node within its timeout.
+func (e *Executor) complete(ctx context.Context, req Request, node *graph.Node, messages []llm.Message, maxTokens int) (*llm.Completion, error) {
+	timeout := e.config.NodeTimeout
+	if node.Timeout > 0 {
+		timeout = time.Duration(node.Timeout) * time.Second
+	}
+	nodeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	completion, err := e.llmClient.Complete(nodeCtx, llm.CompletionRequest{
+		Model:       fallback(node.Model, req.Model),
+		Messages:    messages,
+		MaxTokens:   maxTokens,
+		Temperature: e.config.Temperature,
+		TenantID:    req.Session.TenantID,
+		SessionID:   req.Session.ID,
+	})
+	if errors.Is(nodeCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, fmt.Errorf("%w: node %q after %s", ErrNodeTimeout, node.ID, timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("node %q failed: %w", node.ID, err)
+	}
+	return completion, nil
+}
+
+func (e *Executor) transition(sess *session.Session, from, to string, reason graph.Reason, intent string, hop int) *graph.Transition {
+	return &graph.Transition{
+		ConversationID: sess.ID,
+		TenantID:       sess.TenantID,
+		AgentID:        sess.AgentID,
+		From:           from,
+		To:             to,
+		Reason:         reason,
+		Intent:         intent,
+		Hop:            hop,
+		OccurredAt:     time.Now().UTC(),
+	}
+}
+
+// publish publishes a transition; a failure doesn't stop the turn.
+func (e *Executor) publish(ctx context.Context, t *graph.Transition) {
+	if e.publisher == nil {
+		return
+	}
+	if err := e.publisher.PublishGraphTransition(ctx, t); err != nil {
+		e.logger.Warn("failed to publish graph transition",
+			zap.String("session_id", t.ConversationID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+func fallback(value, def string) string {
+	if value != "" {
+		return value
+	}
+	return def
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// scriptedLLM answers by the system prompt of the request.
+type scriptedLLM struct {
+	answer   func(system string) string
+	delay    time.Duration
+	requests []llm.CompletionRequest
+}
+
+func (s *scriptedLLM) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	s.requests = append(s.requests, req)
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &llm.Completion{Content: s.answer(req.Messages[0].Content), Model: req.Model}, nil
+}
+
+func (s *scriptedLLM) Provider() string { return "test" }
+
+type transitionRecorder struct {
+	transitions []*graph.Transition
+}
+
+func (r *transitionRecorder) PublishGraphTransition(ctx context.Context, t *graph.Transition) error {
+	r.transitions = append(r.transitions, t)
+	return nil
+}
+
+func run(t *testing.T, client *scriptedLLM, g *graph.Graph, message string) (*Result, *transitionRecorder, error) {
+	t.Helper()
+	recorder := &transitionRecorder{}
+	executor := NewExecutor(client, recorder, Config{}, zap.NewNop())
+
+	sess := session.New(uuid.New(), "agent-receptionist")
+	sess.AddTurn(session.RoleUser, message)
+	result, err := executor.Run(context.Background(), Request{
+		Session:      sess,
+		Graph:        g,
+		Message:      message,
+		Model:        "default-model",
+		SystemPrompt: "You are the receptionist.",
+		Messages: func(systemPrompt string) []llm.Message {
+			return []llm.Message{{Role: "system", Content: systemPrompt}, {Role: "user", Content: message}}
+		},
+	})
+	return result, recorder, err
+}
+
+func TestExecutor_RouterToSpecialist(t *testing.T) {
+	client := &scriptedLLM{answer: func(system string) string {
+		switch {
+		case strings.Contains(system, "route caller messages"):
+			return "billing."
+		case system == "You handle invoices.":
+			return "Your invoice was sent on Monday."
+		}
+		return "unexpected prompt: " + system
+	}}
+	g := &graph.Graph{
+		Entry: "router",
+		Nodes: []graph.Node{
+			{ID: "router", Edges: []graph.Edge{
+				{To: "billing", Intent: "billing"},
+				{To: "support", Intent: "support"},
+			}},
+			{ID: "billing", Model: "billing-model", SystemPrompt: "You handle invoices."},
+			{ID: "support", SystemPrompt: "You fix problems."},
+		},
+	}
+
+	result, recorder, err := run(t, client, g, "Where is my invoice?")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Content != "Your invoice was sent on Monday." || result.Node != "billing" || result.LoopGuard {
+		t.Errorf("Expected the billing specialist to answer, got %+v", result)
+	}
+	if strings.Join(result.Path, ">") != "router>billing" {
+		t.Errorf("Expected path router>billing, got %v", result.Path)
+	}
+	// The routing and the answer are both model calls to charge
+	if len(result.Completions) != 2 || client.requests[1].Model != "billing-model" || client.requests[0].Model != "default-model" {
+		t.Errorf("Expected the router on the default model and the specialist on its own, got %+v", client.requests)
+	}
+
+	if len(recorder.transitions) != 1 {
+		t.Fatalf("Expected one transition, got %d", len(recorder.transitions))
+	}
+	if tr := recorder.transitions[0]; tr.From != "router" || tr.To != "billing" || tr.Reason != graph.ReasonIntent || tr.Intent != "billing" || tr.Hop != 1 {
+		t.Errorf("Unexpected transition: %+v", tr)
+	}
+}
+
+func TestExecutor_KeywordAndFallback(t *testing.T) {
+	client := &scriptedLLM{answer: func(system string) string { return "Answered by " + system }}
+	g := &graph.Graph{
+		Entry: "router",
+		Nodes: []graph.Node{
+			{ID: "router", Edges: []graph.Edge{
+				{To: "cancellations", Keywords: []string{"cancel"}},
+				{To: "general"},
+			}},
+			{ID: "cancellations", SystemPrompt: "cancellations"},
+			{ID: "general", SystemPrompt: "general"},
+		},
+	}
+
+	result, recorder, err := run(t, client, g, "I want to CANCEL my plan")
+	if err != nil || result.Node != "cancellations" || recorder.transitions[0].Reason != graph.ReasonKeyword {
+		t.Errorf("Expected a keyword transition to cancellations, got %+v, %v", result, err)
+	}
+	if len(client.requests) != 1 {
+		t.Errorf("A keyword match needs no routing call, got %d calls", len(client.requests))
+	}
+
+	result, recorder, err = run(t, &scriptedLLM{answer: client.answer}, g, "Hello")
+	if err != nil || result.Node != "general" || recorder.transitions[0].Reason != graph.ReasonDefault {
+		t.Errorf("Expected the fallback to general, got %+v, %v", result, err)
+	}
+}
+
+func TestExecutor_LoopGuard(t *testing.T) {
+	client := &scriptedLLM{answer: func(system string) string { return "NONE" }}
+	// Each node hands the turn back to the other
+	g := &graph.Graph{
+		Entry: "triage",
+		Nodes: []graph.Node{
+			{ID: "triage", Edges: []graph.Edge{{To: "sales"}}},
+			{ID: "sales", Edges: []graph.Edge{{To: "triage"}}},
+		},
+	}
+
+	result, recorder, err := run(t, client, g, "Hi")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !result.LoopGuard || result.Content != DefaultLoopMessage {
+		t.Errorf("Expected the loop guard to answer, got %+v", result)
+	}
+	if len(client.requests) != 0 {
+		t.Errorf("Expected no model call, got %d", len(client.requests))
+	}
+	if len(recorder.transitions) != 2 {
+		t.Fatalf("Expected the transition and the refused one, got %d", len(recorder.transitions))
+	}
+	if last := recorder.transitions[1]; last.Reason != graph.ReasonLoopGuard || last.From != "sales" || last.To != "triage" {
+		t.Errorf("Expected the refused transition back to triage, got %+v", last)
+	}
+}
+
+func TestExecutor_MaxHops(t *testing.T) {
+	client := &scriptedLLM{answer: func(system string) string { return "Hi" }}
+	g := &graph.Graph{
+		Entry:   "a",
+		MaxHops: 1,
+		Nodes: []graph.Node{
+			{ID: "a", Edges: []graph.Edge{{To: "b"}}},
+			{ID: "b", Edges: []graph.Edge{{To: "c"}}},
+			{ID: "c"},
+		},
+	}
+
+	result, _, err := run(t, client, g, "Hi")
+	if err != nil || !result.LoopGuard || strings.Join(result.Path, ">") != "a>b" {
+		t.Errorf("Expected the guard after one hop, got %+v, %v", result, err)
+	}
+}
+
+func TestExecutor_NodeTimeout(t *testing.T) {
+	client := &scriptedLLM{answer: func(system string) string { return "late" }, delay: 2 * time.Second}
+	g := &graph.Graph{Entry: "slow", Nodes: []graph.Node{{ID: "slow", Timeout: 1}}}
+
+	started := time.Now()
+	if _, _, err := run(t, client, g, "Hi"); !errors.Is(err, ErrNodeTimeout) {
+		t.Errorf("Expected ErrNodeTimeout, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 1500*time.Millisecond {
+		t.Errorf("Expected the node cut at its timeout, took %s", elapsed)
+	}
+}
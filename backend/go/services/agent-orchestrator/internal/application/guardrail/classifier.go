@@ -0,0 +1,51 @@
+package guardrail
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+)
+
+// classifierPrompt asks the model to pick one of the forbidden topics.
+const classifierPrompt = "You are a compliance classifier for a customer service agent. " +
+	"Decide whether the message is about one of these forbidden topics: %s. " +
+	"Answer with the exact topic name, or NONE if it is about none of them."
+
+// LLMClassifier classifies messages into forbidden topics with an LLM.
+type LLMClassifier struct {
+	llmClient llm.Client
+	model     string
+}
+
+// NewLLMClassifier creates a new LLM-based topic classifier.
+func NewLLMClassifier(llmClient llm.Client, model string) *LLMClassifier {
+	return &LLMClassifier{
+		llmClient: llmClient,
+		model:     model,
+	}
+}
+
+// Classify returns the forbidden topic text is about, or "".
+func (c *LLMClassifier) Classify(ctx context.Context, text string, topics []string) (string, error) {
+	completion, err := c.llmClient.Complete(ctx, llm.CompletionRequest{
+		Model: c.model,
+		Messages: []llm.Message{
+			{Role: "system", Content: fmt.Sprintf(classifierPrompt, strings.Join(topics, "; "))},
+			{Role: "user", Content: text},
+		},
+		MaxTokens: 20,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to classify message: %w", err)
+	}
+
+	answer := strings.Trim(strings.TrimSpace(completion.Content), `."'`)
+	for _, topic := range topics {
+		if strings.EqualFold(answer, topic) {
+			return topic, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,165 @@
+// Package guardrail enforces the agent safety configuration on conversations.
+package guardrail
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// Default messages spoken when a guardrail blocks the conversation.
+const (
+	DefaultRefusalMessage  = "I'm sorry, I can't help with that topic. Is there anything else I can do for you?"
+	DefaultTransferMessage = "I'll transfer you to one of our team members who can help you further."
+	DefaultEndMessage      = "This conversation has ended. Thank you for contacting us."
+)
+
+// Classifier detects forbidden topics that keywords miss.
+type Classifier interface {
+	// Classify returns the topic text is about, or "" if it matches none.
+	Classify(ctx context.Context, text string, topics []string) (string, error)
+}
+
+// Publisher publishes compliance violations.
+type Publisher interface {
+	PublishComplianceViolation(ctx context.Context, v *guardrail.Violation) error
+}
+
+// Config configures guardrail enforcement.
+type Config struct {
+	Action          guardrail.Action // how forbidden topics and max turns are handled
+	RefusalMessage  string
+	TransferMessage string
+	EndMessage      string
+}
+
+// Guard checks sessions and messages against the agent SafetyConfig.
+type Guard struct {
+	classifier Classifier // optional
+	publisher  Publisher
+	config     Config
+	now        func() time.Time
+	logger     *zap.Logger
+}
+
+// NewGuard creates a new guard. The classifier may be nil.
+func NewGuard(classifier Classifier, publisher Publisher, config Config, logger *zap.Logger) *Guard {
+	if config.Action == "" {
+		config.Action = guardrail.ActionRefuse
+	}
+	if config.RefusalMessage == "" {
+		config.RefusalMessage = DefaultRefusalMessage
+	}
+	if config.TransferMessage == "" {
+		config.TransferMessage = DefaultTransferMessage
+	}
+	if config.EndMessage == "" {
+		config.EndMessage = DefaultEndMessage
+	}
+	return &Guard{
+		classifier: classifier,
+		publisher:  publisher,
+		config:     config,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// CheckSession checks the turn and inactivity limits before a new user
+// message is accepted.
+func (g *Guard) CheckSession(s *session.Session) *guardrail.Violation {
+	if s.Agent == nil {
+		return nil
+	}
+	safety := s.Agent.Safety
+
+	if safety.InactivityTimeout > 0 && g.now().Sub(s.UpdatedAt) > time.Duration(safety.InactivityTimeout)*time.Second {
+		return g.violation(s, guardrail.KindInactivity, guardrail.ActionEnd)
+	}
+	if safety.MaxTurns > 0 && s.UserTurns >= safety.MaxTurns {
+		action := guardrail.ActionEnd
+		if g.config.Action == guardrail.ActionTransfer {
+			action = guardrail.ActionTransfer
+		}
+		return g.violation(s, guardrail.KindMaxTurns, action)
+	}
+	return nil
+}
+
+// CheckMessage checks a user or assistant message against the forbidden
+// topics, by keyword first and then with the classifier, if any. A failing
+// classifier does not block the message.
+func (g *Guard) CheckMessage(ctx context.Context, s *session.Session, role session.Role, text string) *guardrail.Violation {
+	if s.Agent == nil || len(s.Agent.Safety.ForbiddenTopics) == 0 {
+		return nil
+	}
+	topics := s.Agent.Safety.ForbiddenTopics
+
+	topic, ok := guardrail.MatchTopic(text, topics)
+	source := guardrail.SourceKeyword
+	if !ok && g.classifier != nil {
+		classified, err := g.classifier.Classify(ctx, text, topics)
+		if err != nil {
+			g.logger.Warn("topic classifier failed",
+				zap.String("session_id", s.ID.String()),
+				zap.Error(err),
+			)
+		}
+		topic, ok, source = classified, classified != "", guardrail.SourceClassifier
+	}
+	if !ok {
+		return nil
+	}
+
+	v := g.violation(s, guardrail.KindForbiddenTopic, g.config.Action)
+	v.Topic = topic
+	v.Role = string(role)
+	v.Source = source
+	return v
+}
+
+// Record logs and publishes a violation. Publishing is best effort.
+func (g *Guard) Record(ctx context.Context, v *guardrail.Violation) {
+	g.logger.Warn("guardrail violation",
+		zap.String("session_id", v.ConversationID.String()),
+		zap.String("tenant_id", v.TenantID.String()),
+		zap.String("kind", string(v.Kind)),
+		zap.String("topic", v.Topic),
+		zap.String("role", v.Role),
+		zap.String("action", string(v.Action)),
+	)
+
+	if err := g.publisher.PublishComplianceViolation(ctx, v); err != nil {
+		g.logger.Error("failed to publish compliance violation",
+			zap.String("session_id", v.ConversationID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// Message returns what the agent says when a violation is enforced.
+func (g *Guard) Message(v *guardrail.Violation) string {
+	switch v.Action {
+	case guardrail.ActionTransfer:
+		return g.config.TransferMessage
+	case guardrail.ActionEnd:
+		return g.config.EndMessage
+	default:
+		return g.config.RefusalMessage
+	}
+}
+
+func (g *Guard) violation(s *session.Session, kind guardrail.Kind, action guardrail.Action) *guardrail.Violation {
+	return &guardrail.Violation{
+		ConversationID: s.ID,
+		TenantID:       s.TenantID,
+		AgentID:        s.AgentID,
+		Kind:           kind,
+		Action:         action,
+		OccurredAt:     g.now().UTC(),
+	}
+}
@@ -0,0 +1,143 @@
+package guardrail
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+type fakeClassifier struct {
+	topic string
+	err   error
+	calls int
+}
+
+func (f *fakeClassifier) Classify(ctx context.Context, text string, topics []string) (string, error) {
+	f.calls++
+	return f.topic, f.err
+}
+
+type memoryPublisher struct {
+	violations []*guardrail.Violation
+}
+
+func (m *memoryPublisher) PublishComplianceViolation(ctx context.Context, v *guardrail.Violation) error {
+	m.violations = append(m.violations, v)
+	return nil
+}
+
+func newSafeSession(safety agent.SafetyConfig) *session.Session {
+	s := session.New(uuid.New(), "agent-receptionist")
+	s.Agent = &agent.Config{Safety: safety}
+	return s
+}
+
+func TestGuard_ForbiddenTopicKeyword(t *testing.T) {
+	classifier := &fakeClassifier{}
+	g := NewGuard(classifier, &memoryPublisher{}, Config{Action: guardrail.ActionTransfer}, zap.NewNop())
+	s := newSafeSession(agent.SafetyConfig{ForbiddenTopics: []string{"política"}})
+
+	v := g.CheckMessage(context.Background(), s, session.RoleUser, "Em quem votar? Fale de politica.")
+	if v == nil {
+		t.Fatal("Expected forbidden topic violation")
+	}
+	if v.Kind != guardrail.KindForbiddenTopic || v.Topic != "política" || v.Source != guardrail.SourceKeyword {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+	if v.Action != guardrail.ActionTransfer || v.Role != "user" || v.ConversationID != s.ID {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+	if classifier.calls != 0 {
+		t.Error("Classifier should not run when a keyword matches")
+	}
+	if g.Message(v) != DefaultTransferMessage {
+		t.Errorf("Unexpected message %q", g.Message(v))
+	}
+}
+
+func TestGuard_ForbiddenTopicClassifier(t *testing.T) {
+	s := newSafeSession(agent.SafetyConfig{ForbiddenTopics: []string{"investment advice"}})
+
+	g := NewGuard(&fakeClassifier{topic: "investment advice"}, &memoryPublisher{}, Config{}, zap.NewNop())
+	v := g.CheckMessage(context.Background(), s, session.RoleAssistant, "You should buy more shares now.")
+	if v == nil || v.Source != guardrail.SourceClassifier || v.Action != guardrail.ActionRefuse {
+		t.Fatalf("Expected classifier refusal, got %+v", v)
+	}
+
+	// A failing classifier lets the message through
+	g = NewGuard(&fakeClassifier{err: errors.New("timeout")}, &memoryPublisher{}, Config{}, zap.NewNop())
+	if v := g.CheckMessage(context.Background(), s, session.RoleUser, "shares?"); v != nil {
+		t.Errorf("Expected no violation on classifier failure, got %+v", v)
+	}
+}
+
+func TestGuard_NoSafetyConfig(t *testing.T) {
+	g := NewGuard(&fakeClassifier{topic: "anything"}, &memoryPublisher{}, Config{}, zap.NewNop())
+	s := session.New(uuid.New(), "agent-receptionist")
+
+	if v := g.CheckMessage(context.Background(), s, session.RoleUser, "politics"); v != nil {
+		t.Error("Sessions without agent config should not be checked")
+	}
+	if v := g.CheckSession(s); v != nil {
+		t.Error("Sessions without agent config have no limits")
+	}
+}
+
+func TestGuard_MaxTurns(t *testing.T) {
+	s := newSafeSession(agent.SafetyConfig{MaxTurns: 2})
+	s.AddTurn(session.RoleUser, "one")
+
+	g := NewGuard(nil, &memoryPublisher{}, Config{}, zap.NewNop())
+	if v := g.CheckSession(s); v != nil {
+		t.Fatalf("Unexpected violation after one turn: %+v", v)
+	}
+
+	s.AddTurn(session.RoleUser, "two")
+	v := g.CheckSession(s)
+	if v == nil || v.Kind != guardrail.KindMaxTurns || v.Action != guardrail.ActionEnd {
+		t.Fatalf("Expected max turns end, got %+v", v)
+	}
+
+	g = NewGuard(nil, &memoryPublisher{}, Config{Action: guardrail.ActionTransfer}, zap.NewNop())
+	if v := g.CheckSession(s); v == nil || v.Action != guardrail.ActionTransfer {
+		t.Errorf("Expected max turns transfer, got %+v", v)
+	}
+}
+
+func TestGuard_Inactivity(t *testing.T) {
+	s := newSafeSession(agent.SafetyConfig{InactivityTimeout: 60})
+	g := NewGuard(nil, &memoryPublisher{}, Config{}, zap.NewNop())
+
+	g.now = func() time.Time { return s.UpdatedAt.Add(59 * time.Second) }
+	if v := g.CheckSession(s); v != nil {
+		t.Fatalf("Unexpected violation within timeout: %+v", v)
+	}
+
+	g.now = func() time.Time { return s.UpdatedAt.Add(61 * time.Second) }
+	v := g.CheckSession(s)
+	if v == nil || v.Kind != guardrail.KindInactivity || v.Action != guardrail.ActionEnd {
+		t.Errorf("Expected inactivity end, got %+v", v)
+	}
+	if g.Message(v) != DefaultEndMessage {
+		t.Errorf("Unexpected message %q", g.Message(v))
+	}
+}
+
+func TestGuard_Record(t *testing.T) {
+	pub := &memoryPublisher{}
+	g := NewGuard(nil, pub, Config{}, zap.NewNop())
+	s := newSafeSession(agent.SafetyConfig{ForbiddenTopics: []string{"crypto"}})
+
+	g.Record(context.Background(), g.CheckMessage(context.Background(), s, session.RoleUser, "crypto tips?"))
+	if len(pub.violations) != 1 || pub.violations[0].Topic != "crypto" {
+		t.Errorf("Expected published violation, got %+v", pub.violations)
+	}
+}
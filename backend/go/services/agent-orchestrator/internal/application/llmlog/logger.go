@@ -0,0 +1,120 @@
+// Package llmlog records redacted LLM prompts and responses.
+package llmlog
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/llmlog"
+)
+
+// Store persists logged exchanges and the per-tenant logging toggle.
+type Store interface {
+	IsTenantEnabled(ctx context.Context, tenantID uuid.UUID) (bool, error)
+	Save(ctx context.Context, record *llmlog.Record) error
+}
+
+// Config configures prompt/response logging.
+type Config struct {
+	Enabled    bool
+	SampleRate float64 // fraction of exchanges logged, 0 to 1
+}
+
+// LoggingClient wraps an LLM client and stores a redacted copy of sampled
+// exchanges for tenants that opted in. Logging never fails a completion.
+type LoggingClient struct {
+	next     llm.Client
+	store    Store
+	redactor *llmlog.Redactor
+	config   Config
+	sample   func() float64
+	logger   *zap.Logger
+}
+
+// NewLoggingClient creates a logging decorator around an LLM client.
+func NewLoggingClient(next llm.Client, store Store, redactor *llmlog.Redactor, config Config, logger *zap.Logger) *LoggingClient {
+	return &LoggingClient{
+		next:     next,
+		store:    store,
+		redactor: redactor,
+		config:   config,
+		sample:   rand.Float64,
+		logger:   logger,
+	}
+}
+
+// Provider returns the wrapped provider name.
+func (c *LoggingClient) Provider() string {
+	return c.next.Provider()
+}
+
+// Complete runs the completion and logs it when sampled.
+func (c *LoggingClient) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	start := time.Now()
+	completion, err := c.next.Complete(ctx, req)
+
+	if c.shouldLog(ctx, req.TenantID) {
+		c.save(ctx, req, completion, err, time.Since(start))
+	}
+
+	return completion, err
+}
+
+// shouldLog reports whether the exchange is sampled for an opted-in tenant.
+func (c *LoggingClient) shouldLog(ctx context.Context, tenantID uuid.UUID) bool {
+	if !c.config.Enabled || c.config.SampleRate <= 0 || tenantID == uuid.Nil {
+		return false
+	}
+	if c.sample() >= c.config.SampleRate {
+		return false
+	}
+
+	enabled, err := c.store.IsTenantEnabled(ctx, tenantID)
+	if err != nil {
+		c.logger.Warn("failed to check llm logging toggle",
+			zap.String("tenant_id", tenantID.String()),
+			zap.Error(err),
+		)
+		return false
+	}
+	return enabled
+}
+
+func (c *LoggingClient) save(ctx context.Context, req llm.CompletionRequest, completion *llm.Completion, callErr error, latency time.Duration) {
+	record := &llmlog.Record{
+		ID:        uuid.New(),
+		TenantID:  req.TenantID,
+		SessionID: req.SessionID,
+		Provider:  c.next.Provider(),
+		Model:     req.Model,
+		Messages:  make([]llmlog.Message, 0, len(req.Messages)),
+		LatencyMs: latency.Milliseconds(),
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, m := range req.Messages {
+		record.Messages = append(record.Messages, llmlog.Message{
+			Role:    m.Role,
+			Content: c.redactor.Redact(m.Content),
+		})
+	}
+	if completion != nil {
+		record.Response = c.redactor.Redact(completion.Content)
+		record.PromptTokens = completion.Usage.PromptTokens
+		record.CompletionTokens = completion.Usage.CompletionTokens
+	}
+	if callErr != nil {
+		record.Error = callErr.Error()
+	}
+
+	if err := c.store.Save(ctx, record); err != nil {
+		c.logger.Warn("failed to store llm log",
+			zap.String("session_id", req.SessionID.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,142 @@
+package llmlog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/llmlog"
+)
+
+type fakeLLM struct {
+	content string
+	err     error
+}
+
+func (f *fakeLLM) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &llm.Completion{
+		Content: f.content,
+		Model:   req.Model,
+		Usage:   llm.Usage{PromptTokens: 12, CompletionTokens: 5},
+	}, nil
+}
+
+func (f *fakeLLM) Provider() string { return "fake" }
+
+type memoryStore struct {
+	enabled map[uuid.UUID]bool
+	records []*llmlog.Record
+	saveErr error
+}
+
+func (s *memoryStore) IsTenantEnabled(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	return s.enabled[tenantID], nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, record *llmlog.Record) error {
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func newTestClient(t *testing.T, next llm.Client, store Store, sample float64) *LoggingClient {
+	t.Helper()
+	redactor, err := llmlog.NewRedactor([]string{llmlog.PatternEmail, llmlog.PatternPhone, llmlog.PatternCard})
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+	c := NewLoggingClient(next, store, redactor, Config{Enabled: true, SampleRate: 0.5}, zap.NewNop())
+	c.sample = func() float64 { return sample }
+	return c
+}
+
+func newRequest(tenantID uuid.UUID, content string) llm.CompletionRequest {
+	return llm.CompletionRequest{
+		Model:     "gpt-4",
+		Messages:  []llm.Message{{Role: "user", Content: content}},
+		TenantID:  tenantID,
+		SessionID: uuid.New(),
+	}
+}
+
+func TestLoggingClient_RedactsBeforeStoring(t *testing.T) {
+	tenantID := uuid.New()
+	store := &memoryStore{enabled: map[uuid.UUID]bool{tenantID: true}}
+	c := newTestClient(t, &fakeLLM{content: "Charged card 4111-1111-1111-1111."}, store, 0.1)
+
+	completion, err := c.Complete(context.Background(), newRequest(tenantID, "Pay with 4111 1111 1111 1111 please"))
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if completion.Content != "Charged card 4111-1111-1111-1111." {
+		t.Error("The caller should get the unredacted completion")
+	}
+
+	if len(store.records) != 1 {
+		t.Fatalf("Expected 1 stored record, got %d", len(store.records))
+	}
+	record := store.records[0]
+	if strings.Contains(record.Messages[0].Content, "4111") || strings.Contains(record.Response, "4111") {
+		t.Errorf("Card number stored unredacted: %+v", record)
+	}
+	if record.Messages[0].Content != "Pay with [CARD] please" {
+		t.Errorf("Unexpected stored prompt %q", record.Messages[0].Content)
+	}
+	if record.TenantID != tenantID || record.Provider != "fake" || record.PromptTokens != 12 {
+		t.Errorf("Unexpected record metadata %+v", record)
+	}
+}
+
+func TestLoggingClient_Sampling(t *testing.T) {
+	tenantID := uuid.New()
+	store := &memoryStore{enabled: map[uuid.UUID]bool{tenantID: true}}
+	c := newTestClient(t, &fakeLLM{content: "ok"}, store, 0.7) // above the 0.5 rate
+
+	if _, err := c.Complete(context.Background(), newRequest(tenantID, "hi")); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if len(store.records) != 0 {
+		t.Error("Unsampled exchange should not be stored")
+	}
+}
+
+func TestLoggingClient_TenantToggle(t *testing.T) {
+	store := &memoryStore{enabled: map[uuid.UUID]bool{}}
+	c := newTestClient(t, &fakeLLM{content: "ok"}, store, 0)
+
+	if _, err := c.Complete(context.Background(), newRequest(uuid.New(), "hi")); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if len(store.records) != 0 {
+		t.Error("Exchanges of tenants that did not opt in should not be stored")
+	}
+}
+
+func TestLoggingClient_RecordsErrorsAndIgnoresStoreFailures(t *testing.T) {
+	tenantID := uuid.New()
+	store := &memoryStore{enabled: map[uuid.UUID]bool{tenantID: true}}
+	c := newTestClient(t, &fakeLLM{err: errors.New("rate limited")}, store, 0)
+
+	if _, err := c.Complete(context.Background(), newRequest(tenantID, "hi")); err == nil {
+		t.Fatal("Expected provider error to be returned")
+	}
+	if len(store.records) != 1 || store.records[0].Error != "rate limited" {
+		t.Errorf("Expected failed exchange to be logged, got %+v", store.records)
+	}
+
+	store.saveErr = errors.New("redis down")
+	c.next = &fakeLLM{content: "ok"}
+	if _, err := c.Complete(context.Background(), newRequest(tenantID, "hi")); err != nil {
+		t.Errorf("Store failure should not fail the completion: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package moderation screens conversation messages before they reach the
+// LLM, and optionally the replies before they reach the caller.
+package moderation
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/moderation"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// Default messages spoken when moderation blocks a message.
+const (
+	DefaultRefusalMessage    = "I'm sorry, I can't help with that. Is there anything else I can do for you?"
+	DefaultEscalationMessage = "I'll transfer you to one of our team members."
+)
+
+// Provider decides whether text hits a moderation policy.
+type Provider interface {
+	Name() string
+	Moderate(ctx context.Context, text string) (*moderation.Result, error)
+}
+
+// Publisher publishes moderation flags.
+type Publisher interface {
+	PublishModerationFlagged(ctx context.Context, f *moderation.Flag) error
+}
+
+// Config configures moderation.
+type Config struct {
+	Action            moderation.Action // how flagged messages are handled
+	CheckOutput       bool              // moderate model replies too
+	RefusalMessage    string
+	EscalationMessage string
+}
+
+// Moderator screens messages with a pluggable provider and records what it
+// flags.
+type Moderator struct {
+	provider  Provider
+	publisher Publisher
+	config    Config
+	now       func() time.Time
+	logger    *zap.Logger
+}
+
+// NewModerator creates a new moderator.
+func NewModerator(provider Provider, publisher Publisher, config Config, logger *zap.Logger) *Moderator {
+	if config.Action == "" {
+		config.Action = moderation.ActionRefuse
+	}
+	if config.RefusalMessage == "" {
+		config.RefusalMessage = DefaultRefusalMessage
+	}
+	if config.EscalationMessage == "" {
+		config.EscalationMessage = DefaultEscalationMessage
+	}
+	return &Moderator{
+		provider:  provider,
+		publisher: publisher,
+		config:    config,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// ChecksOutput reports whether model replies are moderated too.
+func (m *Moderator) ChecksOutput() bool {
+	return m.config.CheckOutput
+}
+
+// Check moderates a message and returns the text to continue with and, when
+// the message was flagged, the recorded flag. A sanitized message comes back
+// without its flagged parts; when nothing is left of it, or the provider
+// can't sanitize, it is refused instead. A failing provider does not block
+// the message.
+func (m *Moderator) Check(ctx context.Context, s *session.Session, role session.Role, text string) (string, *moderation.Flag) {
+	result, err := m.provider.Moderate(ctx, text)
+	if err != nil {
+		m.logger.Warn("moderation provider failed",
+			zap.String("session_id", s.ID.String()),
+			zap.String("provider", m.provider.Name()),
+			zap.Error(err),
+		)
+		return text, nil
+	}
+	if !result.Flagged() {
+		return text, nil
+	}
+
+	action := m.config.Action
+	if action == moderation.ActionSanitize && result.Sanitized == "" {
+		action = moderation.ActionRefuse
+	}
+	f := &moderation.Flag{
+		ConversationID: s.ID,
+		TenantID:       s.TenantID,
+		AgentID:        s.AgentID,
+		Role:           string(role),
+		Categories:     result.Categories,
+		Provider:       m.provider.Name(),
+		Action:         action,
+		OccurredAt:     m.now().UTC(),
+	}
+	m.record(ctx, f)
+
+	if action == moderation.ActionSanitize {
+		return result.Sanitized, f
+	}
+	return text, f
+}
+
+// Message returns what the agent says when a flagged message is blocked.
+func (m *Moderator) Message(f *moderation.Flag) string {
+	if f.Action == moderation.ActionEscalate {
+		return m.config.EscalationMessage
+	}
+	return m.config.RefusalMessage
+}
+
+// record logs and publishes a flag. Publishing is best effort.
+func (m *Moderator) record(ctx context.Context, f *moderation.Flag) {
+	categories := make([]string, len(f.Categories))
+	for i, c := range f.Categories {
+		categories[i] = string(c)
+	}
+	m.logger.Warn("message flagged by moderation",
+		zap.String("session_id", f.ConversationID.String()),
+		zap.String("tenant_id", f.TenantID.String()),
+		zap.String("role", f.Role),
+		zap.Strings("categories", categories),
+		zap.String("provider", f.Provider),
+		zap.String("action", string(f.Action)),
+	)
+
+	if err := m.publisher.PublishModerationFlagged(ctx, f); err != nil {
+		m.logger.Error("failed to publish moderation flag",
+			zap.String("session_id", f.ConversationID.String()),
+			zap.Error(err),
+		)
+	}
+}
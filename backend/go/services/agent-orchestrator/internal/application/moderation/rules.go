@@ -0,0 +1,70 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/moderation"
+)
+
+// Rule flags text matching a pattern as a category.
+type Rule struct {
+	Category moderation.Category
+	Pattern  *regexp.Regexp
+}
+
+// DefaultRules catch common prompt-injection phrasings, in English and
+// Portuguese.
+var DefaultRules = []Rule{
+	{moderation.CategoryPromptInjection, regexp.MustCompile(`(?i)\b(ignore|disregard|forget)\s+(all\s+|any\s+)?(the\s+|your\s+)?(previous|prior|above|earlier)\s+(instructions|prompts?|rules)\b`)},
+	{moderation.CategoryPromptInjection, regexp.MustCompile(`(?i)\b(reveal|show|print|repeat)\s+(me\s+)?(your|the)\s+(system\s+prompt|instructions)\b`)},
+	{moderation.CategoryPromptInjection, regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(in\s+)?(developer|dan|jailbreak|unrestricted)\b`)},
+	{moderation.CategoryPromptInjection, regexp.MustCompile(`(?i)\b(ignore|esqueça|esqueca|desconsidere)\s+(todas\s+)?(as\s+)?(instruções|instrucoes|regras)\s+(anteriores|acima)\b`)},
+}
+
+// RulesProvider moderates text with regular expressions. Flagged matches are
+// removed from the sanitized text.
+type RulesProvider struct {
+	rules []Rule
+}
+
+// NewRulesProvider creates a rules provider. blocklist adds abusive words or
+// phrases, matched as whole words ignoring case.
+func NewRulesProvider(rules []Rule, blocklist []string) (*RulesProvider, error) {
+	all := append([]Rule{}, rules...)
+	for _, term := range blocklist {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(term) + `\b`)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist term %q: %w", term, err)
+		}
+		all = append(all, Rule{Category: moderation.CategoryAbuse, Pattern: pattern})
+	}
+	return &RulesProvider{rules: all}, nil
+}
+
+// Name implements Provider.
+func (p *RulesProvider) Name() string { return "rules" }
+
+// Moderate implements Provider.
+func (p *RulesProvider) Moderate(ctx context.Context, text string) (*moderation.Result, error) {
+	result := &moderation.Result{Sanitized: text}
+	seen := make(map[moderation.Category]bool)
+	for _, rule := range p.rules {
+		if !rule.Pattern.MatchString(result.Sanitized) {
+			continue
+		}
+		if !seen[rule.Category] {
+			seen[rule.Category] = true
+			result.Categories = append(result.Categories, rule.Category)
+		}
+		result.Sanitized = rule.Pattern.ReplaceAllString(result.Sanitized, "")
+	}
+	result.Sanitized = strings.Join(strings.Fields(result.Sanitized), " ")
+	return result, nil
+}
@@ -0,0 +1,221 @@
+// Package registry manages the agents tenants register in the orchestrator.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+)
+
+// MaxNameLength bounds the name of an agent.
+const MaxNameLength = 100
+
+// ErrInvalidDefinition is returned for an agent that can't be run as
+// defined, e.g. with an unknown model or tool.
+var ErrInvalidDefinition = errors.New("invalid agent definition")
+
+// Repository persists agent definitions, scoped by tenant.
+type Repository interface {
+	Create(ctx context.Context, d *agent.Definition) error
+	Get(ctx context.Context, tenantID, id uuid.UUID) (*agent.Definition, error)
+	List(ctx context.Context, tenantID uuid.UUID) ([]*agent.Definition, error)
+	Update(ctx context.Context, d *agent.Definition) error
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// ToolCatalog resolves the tools a tenant can give its agents.
+type ToolCatalog interface {
+	ToolExists(ctx context.Context, tenantID uuid.UUID, toolID string) (bool, error)
+}
+
+// Params holds the editable fields of an agent.
+type Params struct {
+	Name         string
+	Description  string
+	Model        string
+	SystemPrompt string
+	Tools        []string
+	Routing      agent.RoutingConfig
+	Safety       agent.SafetyConfig
+	Graph        *graph.Graph
+}
+
+// Service manages agent definitions. Models must be in the configured list
+// and tools must exist in the tenant catalog.
+type Service struct {
+	repo   Repository
+	tools  ToolCatalog
+	models map[string]bool
+	logger *zap.Logger
+}
+
+// NewService creates a new registry allowing the given models.
+func NewService(repo Repository, tools ToolCatalog, models []string, logger *zap.Logger) *Service {
+	allowed := make(map[string]bool, len(models))
+	for _, m := range models {
+		if m = strings.TrimSpace(m); m != "" {
+			allowed[m] = true
+		}
+	}
+	return &Service{
+		repo:   repo,
+		tools:  tools,
+		models: allowed,
+		logger: logger,
+	}
+}
+
+// Create registers a new agent for a tenant.
+func (s *Service) Create(ctx context.Context, tenantID uuid.UUID, params Params) (*agent.Definition, error) {
+	params = normalize(params)
+	if err := s.validate(ctx, tenantID, params); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	d := &agent.Definition{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	apply(d, params)
+	if err := s.repo.Create(ctx, d); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("agent registered",
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("agent_id", d.ID.String()),
+		zap.String("model", d.Model),
+	)
+
+	return d, nil
+}
+
+// Get retrieves an agent of a tenant.
+func (s *Service) Get(ctx context.Context, tenantID, id uuid.UUID) (*agent.Definition, error) {
+	return s.repo.Get(ctx, tenantID, id)
+}
+
+// List returns the agents of a tenant.
+func (s *Service) List(ctx context.Context, tenantID uuid.UUID) ([]*agent.Definition, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+// Update replaces the editable fields of an agent.
+func (s *Service) Update(ctx context.Context, tenantID, id uuid.UUID, params Params) (*agent.Definition, error) {
+	params = normalize(params)
+	if err := s.validate(ctx, tenantID, params); err != nil {
+		return nil, err
+	}
+
+	d, err := s.repo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	apply(d, params)
+	d.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, d); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("agent updated",
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("agent_id", id.String()),
+	)
+
+	return d, nil
+}
+
+// Delete removes an agent of a tenant.
+func (s *Service) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("agent deleted",
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("agent_id", id.String()),
+	)
+
+	return nil
+}
+
+// validate checks an agent can run: a name, allowed models, existing tools,
+// sane safety limits and a well-formed graph.
+func (s *Service) validate(ctx context.Context, tenantID uuid.UUID, params Params) error {
+	switch {
+	case params.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidDefinition)
+	case len(params.Name) > MaxNameLength:
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidDefinition, MaxNameLength)
+	case params.Model == "":
+		return fmt.Errorf("%w: model is required", ErrInvalidDefinition)
+	case !s.models[params.Model]:
+		return fmt.Errorf("%w: unknown model %q", ErrInvalidDefinition, params.Model)
+	case params.Safety.MaxTurns < 0 || params.Safety.InactivityTimeout < 0:
+		return fmt.Errorf("%w: safety limits can't be negative", ErrInvalidDefinition)
+	}
+
+	if g := params.Graph; g != nil {
+		if err := g.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidDefinition, err)
+		}
+		for _, n := range g.Nodes {
+			if n.Model != "" && !s.models[n.Model] {
+				return fmt.Errorf("%w: node %q has unknown model %q", ErrInvalidDefinition, n.ID, n.Model)
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(params.Tools))
+	for _, toolID := range params.Tools {
+		if toolID == "" {
+			return fmt.Errorf("%w: empty tool reference", ErrInvalidDefinition)
+		}
+		if seen[toolID] {
+			return fmt.Errorf("%w: tool %q is listed twice", ErrInvalidDefinition, toolID)
+		}
+		seen[toolID] = true
+
+		exists, err := s.tools.ToolExists(ctx, tenantID, toolID)
+		if err != nil {
+			return fmt.Errorf("failed to look up tool %q: %w", toolID, err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: unknown tool %q", ErrInvalidDefinition, toolID)
+		}
+	}
+	return nil
+}
+
+func normalize(params Params) Params {
+	params.Name = strings.TrimSpace(params.Name)
+	params.Model = strings.TrimSpace(params.Model)
+	tools := make([]string, len(params.Tools))
+	for i, t := range params.Tools {
+		tools[i] = strings.TrimSpace(t)
+	}
+	params.Tools = tools
+	return params
+}
+
+func apply(d *agent.Definition, params Params) {
+	d.Name = params.Name
+	d.Description = params.Description
+	d.Model = params.Model
+	d.SystemPrompt = params.SystemPrompt
+	d.Tools = params.Tools
+	d.Routing = params.Routing
+	d.Safety = params.Safety
+	d.Graph = params.Graph
+}
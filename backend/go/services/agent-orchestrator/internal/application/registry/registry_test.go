@@ -0,0 +1,206 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+)
+
+type memoryRepo struct {
+	agents map[uuid.UUID]*agent.Definition
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{agents: make(map[uuid.UUID]*agent.Definition)}
+}
+
+func (r *memoryRepo) Create(ctx context.Context, d *agent.Definition) error {
+	for _, other := range r.agents {
+		if other.TenantID == d.TenantID && other.Name == d.Name {
+			return agent.ErrNameTaken
+		}
+	}
+	stored := *d
+	r.agents[d.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepo) Get(ctx context.Context, tenantID, id uuid.UUID) (*agent.Definition, error) {
+	d, ok := r.agents[id]
+	if !ok || d.TenantID != tenantID {
+		return nil, agent.ErrDefinitionNotFound
+	}
+	stored := *d
+	return &stored, nil
+}
+
+func (r *memoryRepo) List(ctx context.Context, tenantID uuid.UUID) ([]*agent.Definition, error) {
+	var agents []*agent.Definition
+	for _, d := range r.agents {
+		if d.TenantID == tenantID {
+			stored := *d
+			agents = append(agents, &stored)
+		}
+	}
+	return agents, nil
+}
+
+func (r *memoryRepo) Update(ctx context.Context, d *agent.Definition) error {
+	if _, err := r.Get(ctx, d.TenantID, d.ID); err != nil {
+		return err
+	}
+	stored := *d
+	r.agents[d.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepo) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	if _, err := r.Get(ctx, tenantID, id); err != nil {
+		return err
+	}
+	delete(r.agents, id)
+	return nil
+}
+
+// fixedTools knows the same tools for every tenant.
+type fixedTools map[string]bool
+
+func (f fixedTools) ToolExists(ctx context.Context, tenantID uuid.UUID, toolID string) (bool, error) {
+	return f[toolID], nil
+}
+
+func newTestService() *Service {
+	return NewService(newMemoryRepo(), fixedTools{"crm-lookup": true, "create-ticket": true}, []string{"gpt-4", "gpt-4o-mini"}, zap.NewNop())
+}
+
+func receptionist() Params {
+	return Params{
+		Name:         "Receptionist",
+		Model:        "gpt-4",
+		SystemPrompt: "You answer calls for the clinic.",
+		Tools:        []string{"crm-lookup"},
+		Safety:       agent.SafetyConfig{MaxTurns: 20},
+	}
+}
+
+func TestService_CRUD(t *testing.T) {
+	service := newTestService()
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	created, err := service.Create(ctx, tenantID, receptionist())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.ID == uuid.Nil || created.TenantID != tenantID || created.Model != "gpt-4" {
+		t.Fatalf("Unexpected agent: %+v", created)
+	}
+
+	got, err := service.Get(ctx, tenantID, created.ID)
+	if err != nil || got.Name != "Receptionist" || len(got.Tools) != 1 {
+		t.Fatalf("Get returned %+v, %v", got, err)
+	}
+
+	params := receptionist()
+	params.Model = "gpt-4o-mini"
+	params.Tools = []string{"crm-lookup", "create-ticket"}
+	updated, err := service.Update(ctx, tenantID, created.ID, params)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.Model != "gpt-4o-mini" || len(updated.Tools) != 2 || !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Unexpected updated agent: %+v", updated)
+	}
+
+	list, err := service.List(ctx, tenantID)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List returned %d agents, %v", len(list), err)
+	}
+
+	if err := service.Delete(ctx, tenantID, created.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := service.Get(ctx, tenantID, created.ID); !errors.Is(err, agent.ErrDefinitionNotFound) {
+		t.Errorf("Expected ErrDefinitionNotFound after delete, got %v", err)
+	}
+}
+
+func TestService_ScopedByTenant(t *testing.T) {
+	service := newTestService()
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, uuid.New(), receptionist())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	other := uuid.New()
+	if _, err := service.Get(ctx, other, created.ID); !errors.Is(err, agent.ErrDefinitionNotFound) {
+		t.Errorf("Expected another tenant not to see the agent, got %v", err)
+	}
+	if err := service.Delete(ctx, other, created.ID); !errors.Is(err, agent.ErrDefinitionNotFound) {
+		t.Errorf("Expected another tenant not to delete the agent, got %v", err)
+	}
+	// The name is only taken within the tenant
+	if _, err := service.Create(ctx, other, receptionist()); err != nil {
+		t.Errorf("Expected the name free for another tenant, got %v", err)
+	}
+}
+
+func TestService_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+	}{
+		{name: "unknown tool", modify: func(p *Params) { p.Tools = []string{"crm-lookup", "send-fax"} }},
+		{name: "duplicate tool", modify: func(p *Params) { p.Tools = []string{"crm-lookup", "crm-lookup"} }},
+		{name: "unknown model", modify: func(p *Params) { p.Model = "gpt-2" }},
+		{name: "no model", modify: func(p *Params) { p.Model = " " }},
+		{name: "no name", modify: func(p *Params) { p.Name = "" }},
+		{name: "negative max turns", modify: func(p *Params) { p.Safety.MaxTurns = -1 }},
+		{name: "graph to unknown node", modify: func(p *Params) {
+			p.Graph = &graph.Graph{Entry: "router", Nodes: []graph.Node{{ID: "router", Edges: []graph.Edge{{To: "billing"}}}}}
+		}},
+		{name: "graph node with unknown model", modify: func(p *Params) {
+			p.Graph = &graph.Graph{Entry: "router", Nodes: []graph.Node{{ID: "router", Model: "gpt-2"}}}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService()
+			params := receptionist()
+			tt.modify(&params)
+
+			if _, err := service.Create(context.Background(), uuid.New(), params); !errors.Is(err, ErrInvalidDefinition) {
+				t.Errorf("Expected ErrInvalidDefinition, got %v", err)
+			}
+		})
+	}
+}
+
+func TestService_UpdateValidatesTools(t *testing.T) {
+	service := newTestService()
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	created, err := service.Create(ctx, tenantID, receptionist())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	params := receptionist()
+	params.Tools = []string{"send-fax"}
+	if _, err := service.Update(ctx, tenantID, created.ID, params); !errors.Is(err, ErrInvalidDefinition) {
+		t.Errorf("Expected ErrInvalidDefinition, got %v", err)
+	}
+	if got, _ := service.Get(ctx, tenantID, created.ID); got.Tools[0] != "crm-lookup" {
+		t.Errorf("Expected the agent unchanged, got tools %v", got.Tools)
+	}
+}
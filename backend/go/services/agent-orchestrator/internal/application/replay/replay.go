@@ -0,0 +1,282 @@
+// Package replay re-runs stored conversations against another agent
+// configuration, for debugging.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	graphservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/graph"
+	sessionservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// ErrInvalidReplay is returned for a replay that can't be run, e.g. of a
+// conversation without user turns.
+var ErrInvalidReplay = errors.New("invalid replay")
+
+// Conversations reads stored conversations.
+type Conversations interface {
+	GetSession(ctx context.Context, id uuid.UUID) (*session.Session, error)
+}
+
+// Agents reads the agents registered by a tenant.
+type Agents interface {
+	Get(ctx context.Context, tenantID, id uuid.UUID) (*agent.Definition, error)
+}
+
+// Config holds the defaults of replayed conversations, as for live ones.
+type Config struct {
+	Session sessionservice.Config
+	Context sessionservice.ContextConfig
+	Graph   graphservice.Config
+}
+
+// Params selects what a conversation is replayed against. Without an agent
+// the agent of the conversation is used; the prompt and model override it.
+type Params struct {
+	AgentID      uuid.UUID // registered agent of the conversation tenant
+	SystemPrompt string
+	Model        string
+}
+
+// Turn pairs a user message with the original reply and the replayed one.
+type Turn struct {
+	User     string `json:"user"`
+	Original string `json:"original"`
+	Replayed string `json:"replayed"`
+	Blocked  bool   `json:"blocked,omitempty"` // a guardrail replaced the replayed reply
+	Ended    bool   `json:"ended,omitempty"`   // the replayed conversation ended here
+}
+
+// Result is a conversation replayed side by side with the original.
+type Result struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	AgentID        string    `json:"agent_id"`
+	Model          string    `json:"model"`
+	Turns          []Turn    `json:"turns"`
+	// SkippedTurns counts the turns folded into the conversation summary,
+	// which can't be replayed
+	SkippedTurns int    `json:"skipped_turns,omitempty"`
+	EndReason    string `json:"end_reason,omitempty"`
+}
+
+// Replayer re-runs the user turns of stored conversations in a sandbox: a
+// session service of its own, kept in memory, with no cost accounting,
+// tracking, summaries, events nor transfers. Nothing of the replay is
+// stored and the original conversation is left as it is.
+type Replayer struct {
+	conversations Conversations
+	agents        Agents
+	llmClient     llm.Client
+	config        Config
+	logger        *zap.Logger
+}
+
+// NewReplayer creates a new replayer. agents may be nil when the agent
+// registry is disabled.
+func NewReplayer(conversations Conversations, agents Agents, llmClient llm.Client, config Config, logger *zap.Logger) *Replayer {
+	return &Replayer{
+		conversations: conversations,
+		agents:        agents,
+		llmClient:     llmClient,
+		config:        config,
+		logger:        logger,
+	}
+}
+
+// Replay re-runs the user turns of a conversation in order and returns the
+// new replies beside the original ones. A replayed conversation that ends
+// early, e.g. at the agent MaxTurns, leaves the later turns unreplayed.
+func (r *Replayer) Replay(ctx context.Context, id uuid.UUID, params Params) (*Result, error) {
+	stored, err := r.conversations.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	turns := pairTurns(stored.Turns)
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("%w: the conversation has no user turns", ErrInvalidReplay)
+	}
+
+	version, err := r.version(ctx, stored, params)
+	if err != nil {
+		return nil, err
+	}
+
+	sandbox := r.sandbox(version)
+	sess, err := sandbox.CreateSession(ctx, sessionservice.CreateParams{TenantID: stored.TenantID, AgentID: stored.AgentID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start replay: %w", err)
+	}
+
+	result := &Result{
+		ConversationID: stored.ID,
+		AgentID:        stored.AgentID,
+		Model:          version.model,
+		SkippedTurns:   stored.SummarizedTurns,
+	}
+	for i := range turns {
+		reply, err := sandbox.SendMessage(ctx, sess.ID, turns[i].User)
+		if errors.Is(err, sessionservice.ErrSessionEnded) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay turn %d: %w", i, err)
+		}
+		turns[i].Replayed = reply.Content
+		turns[i].Blocked = reply.Blocked
+		turns[i].Ended = reply.Ended
+		result.Turns = append(result.Turns, turns[i])
+		if reply.Ended {
+			result.EndReason = reply.EndReason
+			break
+		}
+	}
+
+	r.logger.Info("conversation replayed",
+		zap.String("session_id", id.String()),
+		zap.String("model", version.model),
+		zap.Int("turns", len(result.Turns)),
+	)
+
+	return result, nil
+}
+
+// agentVersion is the agent a conversation is replayed against.
+type agentVersion struct {
+	config       *agent.Config
+	model        string
+	systemPrompt string
+}
+
+// version resolves the agent version of the replay. The stored agent keeps
+// the variant the conversation was assigned.
+func (r *Replayer) version(ctx context.Context, stored *session.Session, params Params) (*agentVersion, error) {
+	version := &agentVersion{
+		config:       stored.Agent,
+		model:        r.config.Session.Model,
+		systemPrompt: r.config.Session.SystemPrompt,
+	}
+	if v := stored.AgentVariant(); v != nil {
+		if v.Model != "" {
+			version.model = v.Model
+		}
+		if v.SystemPrompt != "" {
+			version.systemPrompt = v.SystemPrompt
+		}
+	}
+
+	if params.AgentID != uuid.Nil {
+		if r.agents == nil {
+			return nil, fmt.Errorf("%w: the agent registry is not enabled", ErrInvalidReplay)
+		}
+		d, err := r.agents.Get(ctx, stored.TenantID, params.AgentID)
+		if err != nil {
+			return nil, err
+		}
+		version.config, version.model = d.Config(), d.Model
+		if d.SystemPrompt != "" {
+			version.systemPrompt = d.SystemPrompt
+		}
+	}
+	if params.Model != "" {
+		version.model = params.Model
+	}
+	if params.SystemPrompt != "" {
+		version.systemPrompt = params.SystemPrompt
+	}
+	return version, nil
+}
+
+// sandbox creates a session service that keeps nothing and reaches nothing
+// but the model.
+func (r *Replayer) sandbox(version *agentVersion) *sessionservice.Service {
+	config := r.config.Session
+	config.Model, config.SystemPrompt = version.model, version.systemPrompt
+	config.SpendCap = 0
+
+	window := sessionservice.NewContextWindow(r.llmClient, r.config.Context, r.logger)
+	sandbox := sessionservice.NewService(newMemoryRepository(), r.llmClient, window, config, r.logger)
+	if version.config != nil {
+		// The version is already resolved, the replay isn't assigned another
+		replayed := *version.config
+		replayed.Variants = nil
+		sandbox.SetAgentConfigs(fixedAgent{config: &replayed})
+		sandbox.SetGraph(graphservice.NewExecutor(r.llmClient, nil, r.config.Graph, r.logger))
+	}
+	return sandbox
+}
+
+// pairTurns pairs each user turn with the assistant turns answering it.
+func pairTurns(history []session.Turn) []Turn {
+	var turns []Turn
+	var original []string
+	flush := func() {
+		if len(turns) > 0 {
+			turns[len(turns)-1].Original = strings.Join(original, "\n\n")
+		}
+		original = nil
+	}
+	for _, t := range history {
+		switch t.Role {
+		case session.RoleUser:
+			flush()
+			turns = append(turns, Turn{User: t.Content})
+		case session.RoleAssistant:
+			original = append(original, t.Content)
+		}
+	}
+	flush()
+	return turns
+}
+
+// fixedAgent gives every replayed session the same agent configuration.
+type fixedAgent struct {
+	config *agent.Config
+}
+
+func (f fixedAgent) GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*agent.Config, error) {
+	return f.config, nil
+}
+
+// memoryRepository keeps the sandbox session for the replay only.
+type memoryRepository struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*session.Session
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{sessions: make(map[uuid.UUID]*session.Session)}
+}
+
+func (m *memoryRepository) Save(ctx context.Context, s *session.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = s
+	return nil
+}
+
+func (m *memoryRepository) Get(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("replay session %s not found", id)
+	}
+	return s, nil
+}
+
+func (m *memoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
@@ -0,0 +1,170 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	sessionservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// echoLLM answers every message with the system prompt and the last user
+// message, and records the requests.
+type echoLLM struct {
+	requests []llm.CompletionRequest
+}
+
+func (e *echoLLM) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	e.requests = append(e.requests, req)
+	var prompt, last string
+	for _, m := range req.Messages {
+		switch m.Role {
+		case string(session.RoleSystem):
+			if prompt == "" {
+				prompt = m.Content
+			}
+		case string(session.RoleUser):
+			last = m.Content
+		}
+	}
+	return &llm.Completion{Content: prompt + ": " + last, Usage: llm.Usage{PromptTokens: 10, CompletionTokens: 5}}, nil
+}
+
+func (e *echoLLM) Provider() string { return "fake" }
+
+// storedConversations serves stored sessions.
+type storedConversations struct {
+	sessions map[uuid.UUID]*session.Session
+}
+
+func (s *storedConversations) GetSession(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	return sess, nil
+}
+
+type fixedDefinitions map[uuid.UUID]*agent.Definition
+
+func (f fixedDefinitions) Get(ctx context.Context, tenantID, id uuid.UUID) (*agent.Definition, error) {
+	d, ok := f[id]
+	if !ok || d.TenantID != tenantID {
+		return nil, agent.ErrDefinitionNotFound
+	}
+	return d, nil
+}
+
+func storedConversation() *session.Session {
+	sess := session.New(uuid.New(), "agent-1")
+	sess.AddTurn(session.RoleUser, "I need to reschedule")
+	sess.AddTurn(session.RoleAssistant, "Sure, for when?")
+	sess.AddTurn(session.RoleUser, "Next Monday")
+	sess.AddTurn(session.RoleAssistant, "Done, see you Monday.")
+	return sess
+}
+
+func newTestReplayer(stored *session.Session, agents Agents) (*Replayer, *echoLLM) {
+	client := &echoLLM{}
+	config := Config{
+		Session: sessionservice.Config{Model: "gpt-4", SystemPrompt: "live prompt", SpendCap: 0.01},
+		Context: sessionservice.ContextConfig{MaxTokens: 8000, Threshold: 0.8, KeepRecentTurns: 4},
+	}
+	conversations := &storedConversations{sessions: map[uuid.UUID]*session.Session{stored.ID: stored}}
+	return NewReplayer(conversations, agents, client, config, zap.NewNop()), client
+}
+
+func TestReplayer_ReplaysAgainstNewPrompt(t *testing.T) {
+	stored := storedConversation()
+	replayer, client := newTestReplayer(stored, nil)
+
+	result, err := replayer.Replay(context.Background(), stored.ID, Params{SystemPrompt: "new prompt", Model: "gpt-4o-mini"})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	if len(result.Turns) != 2 {
+		t.Fatalf("Expected 2 replayed turns, got %d", len(result.Turns))
+	}
+	first := result.Turns[0]
+	if first.User != "I need to reschedule" || first.Original != "Sure, for when?" {
+		t.Errorf("Unexpected original turn: %+v", first)
+	}
+	if first.Replayed != "new prompt: I need to reschedule" {
+		t.Errorf("Expected the reply under the new prompt, got %q", first.Replayed)
+	}
+	if result.Turns[1].Original != "Done, see you Monday." || result.Turns[1].Replayed != "new prompt: Next Monday" {
+		t.Errorf("Unexpected second turn: %+v", result.Turns[1])
+	}
+	for _, req := range client.requests {
+		if req.Model != "gpt-4o-mini" {
+			t.Errorf("Expected the replay on gpt-4o-mini, got %s", req.Model)
+		}
+	}
+}
+
+func TestReplayer_LeavesOriginalUntouched(t *testing.T) {
+	stored := storedConversation()
+	version := stored.Version
+	replayer, _ := newTestReplayer(stored, nil)
+
+	result, err := replayer.Replay(context.Background(), stored.ID, Params{})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	// A spend cap on live conversations doesn't stop a replay
+	if len(result.Turns) != 2 || result.EndReason != "" {
+		t.Fatalf("Expected the whole conversation replayed, got %+v", result)
+	}
+	if len(stored.Turns) != 4 || stored.Version != version || stored.Spend.Cost != 0 || stored.Spend.PromptTokens != 0 {
+		t.Errorf("Expected the stored conversation unchanged, got %d turns, version %d, spend %+v", len(stored.Turns), stored.Version, stored.Spend)
+	}
+	if !strings.HasPrefix(result.Turns[0].Replayed, "live prompt") {
+		t.Errorf("Expected the live prompt without overrides, got %q", result.Turns[0].Replayed)
+	}
+}
+
+func TestReplayer_RegisteredAgent(t *testing.T) {
+	stored := storedConversation()
+	d := &agent.Definition{ID: uuid.New(), TenantID: stored.TenantID, Name: "Scheduler", Model: "gpt-4o", SystemPrompt: "registered prompt"}
+	replayer, client := newTestReplayer(stored, fixedDefinitions{d.ID: d})
+
+	result, err := replayer.Replay(context.Background(), stored.ID, Params{AgentID: d.ID})
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Model != "gpt-4o" || result.Turns[0].Replayed != "registered prompt: I need to reschedule" {
+		t.Errorf("Expected the registered agent to answer, got %+v", result)
+	}
+	if client.requests[0].Model != "gpt-4o" {
+		t.Errorf("Expected the registered model, got %s", client.requests[0].Model)
+	}
+
+	// Another tenant's agent isn't found
+	if _, err := replayer.Replay(context.Background(), stored.ID, Params{AgentID: uuid.New()}); !errors.Is(err, agent.ErrDefinitionNotFound) {
+		t.Errorf("Expected ErrDefinitionNotFound, got %v", err)
+	}
+}
+
+func TestReplayer_Invalid(t *testing.T) {
+	empty := session.New(uuid.New(), "agent-1")
+	replayer, _ := newTestReplayer(empty, nil)
+
+	if _, err := replayer.Replay(context.Background(), empty.ID, Params{}); !errors.Is(err, ErrInvalidReplay) {
+		t.Errorf("Expected ErrInvalidReplay without user turns, got %v", err)
+	}
+
+	stored := storedConversation()
+	replayer, _ = newTestReplayer(stored, nil)
+	if _, err := replayer.Replay(context.Background(), stored.ID, Params{AgentID: uuid.New()}); !errors.Is(err, ErrInvalidReplay) {
+		t.Errorf("Expected ErrInvalidReplay without a registry, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+)
+
+// detectorPrompt asks the model to pick one of the transfer intents.
+const detectorPrompt = "You detect caller intents for a customer service agent. " +
+	"Decide whether the caller message expresses one of these intents: %s. " +
+	"Answer with the exact intent name, or NONE if it expresses none of them."
+
+// LLMIntentDetector detects intents with an LLM.
+type LLMIntentDetector struct {
+	llmClient llm.Client
+	model     string
+}
+
+// NewLLMIntentDetector creates a new LLM-based intent detector.
+func NewLLMIntentDetector(llmClient llm.Client, model string) *LLMIntentDetector {
+	return &LLMIntentDetector{
+		llmClient: llmClient,
+		model:     model,
+	}
+}
+
+// Detect returns the intent text expresses, or "".
+func (d *LLMIntentDetector) Detect(ctx context.Context, text string, intents []string) (string, error) {
+	completion, err := d.llmClient.Complete(ctx, llm.CompletionRequest{
+		Model: d.model,
+		Messages: []llm.Message{
+			{Role: "system", Content: fmt.Sprintf(detectorPrompt, strings.Join(intents, "; "))},
+			{Role: "user", Content: text},
+		},
+		MaxTokens: 20,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to detect intent: %w", err)
+	}
+
+	answer := strings.Trim(strings.TrimSpace(completion.Content), `."'`)
+	for _, intent := range intents {
+		if strings.EqualFold(answer, intent) {
+			return intent, nil
+		}
+	}
+	return "", nil
+}
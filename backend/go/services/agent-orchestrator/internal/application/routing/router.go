@@ -0,0 +1,127 @@
+// Package routing decides and initiates transfers from the agent RoutingConfig.
+package routing
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// IntentDetector detects the intent of a user message.
+type IntentDetector interface {
+	// Detect returns the intent text expresses, or "" if it matches none.
+	Detect(ctx context.Context, text string, intents []string) (string, error)
+}
+
+// CallTransferrer transfers voice calls.
+type CallTransferrer interface {
+	TransferCall(ctx context.Context, callID uuid.UUID, transferType, target, reason string) error
+}
+
+// Publisher publishes transfer decisions.
+type Publisher interface {
+	PublishTransferDecision(ctx context.Context, d *routing.Decision) error
+}
+
+// Router turns transfer intents and escalation phrases into transfers.
+type Router struct {
+	detector  IntentDetector // optional
+	calls     CallTransferrer
+	publisher Publisher
+	logger    *zap.Logger
+}
+
+// NewRouter creates a new router. The detector may be nil, in which case
+// intents are matched by keyword only.
+func NewRouter(detector IntentDetector, calls CallTransferrer, publisher Publisher, logger *zap.Logger) *Router {
+	return &Router{
+		detector:  detector,
+		calls:     calls,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// Detect returns a transfer decision when the user message contains the
+// escalation trigger or expresses a transfer intent. Agents that cannot route
+// or have no allowed target never transfer.
+func (r *Router) Detect(ctx context.Context, s *session.Session, text string) *routing.Decision {
+	if s.Agent == nil || !s.Agent.Routing.CanRoute || len(s.Agent.Routing.AllowedTargets) == 0 {
+		return nil
+	}
+	cfg := s.Agent.Routing
+
+	if cfg.EscalationTrigger != "" {
+		if _, ok := guardrail.MatchTopic(text, []string{cfg.EscalationTrigger}); ok {
+			return r.Decision(s, routing.TriggerEscalation, "escalation requested")
+		}
+	}
+
+	if len(cfg.TransferIntents) == 0 {
+		return nil
+	}
+	intent, ok := guardrail.MatchTopic(text, cfg.TransferIntents)
+	if !ok && r.detector != nil {
+		detected, err := r.detector.Detect(ctx, text, cfg.TransferIntents)
+		if err != nil {
+			r.logger.Warn("intent detection failed",
+				zap.String("session_id", s.ID.String()),
+				zap.Error(err),
+			)
+		}
+		intent, ok = detected, detected != ""
+	}
+	if !ok {
+		return nil
+	}
+
+	d := r.Decision(s, routing.TriggerIntent, "intent: "+intent)
+	d.Intent = intent
+	return d
+}
+
+// Decision builds a transfer decision to the first allowed target.
+func (r *Router) Decision(s *session.Session, trigger routing.Trigger, reason string) *routing.Decision {
+	d := &routing.Decision{
+		ConversationID: s.ID,
+		TenantID:       s.TenantID,
+		AgentID:        s.AgentID,
+		CallID:         s.CallID,
+		Trigger:        trigger,
+		Reason:         reason,
+		DecidedAt:      time.Now().UTC(),
+	}
+	if s.Agent != nil && len(s.Agent.Routing.AllowedTargets) > 0 {
+		d.TransferType, d.Target = routing.ParseTarget(s.Agent.Routing.AllowedTargets[0])
+	}
+	return d
+}
+
+// Transfer initiates the transfer through voice-gateway when the session is
+// carried by a call, then publishes the decision. Chat sessions have no call;
+// the channel acts on the transfer action of the reply instead.
+func (r *Router) Transfer(ctx context.Context, d *routing.Decision) error {
+	var err error
+	if d.CallID != uuid.Nil && d.Target != "" {
+		err = r.calls.TransferCall(ctx, d.CallID, d.TransferType, d.Target, d.Reason)
+		d.Transferred = err == nil
+		if err != nil {
+			d.Error = err.Error()
+		}
+	}
+
+	if pubErr := r.publisher.PublishTransferDecision(ctx, d); pubErr != nil {
+		r.logger.Error("failed to publish transfer decision",
+			zap.String("session_id", d.ConversationID.String()),
+			zap.Error(pubErr),
+		)
+	}
+
+	return err
+}
@@ -0,0 +1,173 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+type fakeDetector struct {
+	intent string
+	err    error
+	calls  int
+}
+
+func (f *fakeDetector) Detect(ctx context.Context, text string, intents []string) (string, error) {
+	f.calls++
+	return f.intent, f.err
+}
+
+type transferCall struct {
+	callID               uuid.UUID
+	transferType, target string
+	reason               string
+}
+
+type fakeCalls struct {
+	err       error
+	transfers []transferCall
+}
+
+func (f *fakeCalls) TransferCall(ctx context.Context, callID uuid.UUID, transferType, target, reason string) error {
+	f.transfers = append(f.transfers, transferCall{callID, transferType, target, reason})
+	return f.err
+}
+
+type memoryPublisher struct {
+	decisions []*routing.Decision
+}
+
+func (m *memoryPublisher) PublishTransferDecision(ctx context.Context, d *routing.Decision) error {
+	m.decisions = append(m.decisions, d)
+	return nil
+}
+
+func newRoutedSession(cfg agent.RoutingConfig) *session.Session {
+	s := session.New(uuid.New(), "agent-receptionist")
+	s.Agent = &agent.Config{Routing: cfg}
+	return s
+}
+
+var billingRouting = agent.RoutingConfig{
+	CanRoute:          true,
+	AllowedTargets:    []string{"queue:billing", "agent:maria"},
+	TransferIntents:   []string{"billing_dispute", "cancel_subscription"},
+	EscalationTrigger: "falar com um atendente",
+}
+
+func TestRouter_DetectIntent(t *testing.T) {
+	detector := &fakeDetector{intent: "billing_dispute"}
+	r := NewRouter(detector, &fakeCalls{}, &memoryPublisher{}, zap.NewNop())
+	s := newRoutedSession(billingRouting)
+
+	d := r.Detect(context.Background(), s, "I was charged twice this month")
+	if d == nil {
+		t.Fatal("Expected a transfer decision")
+	}
+	if d.Trigger != routing.TriggerIntent || d.Intent != "billing_dispute" {
+		t.Errorf("Unexpected decision %+v", d)
+	}
+	if d.TransferType != routing.TransferQueue || d.Target != "billing" {
+		t.Errorf("Expected first allowed target, got %s:%s", d.TransferType, d.Target)
+	}
+}
+
+func TestRouter_DetectIntentKeyword(t *testing.T) {
+	detector := &fakeDetector{}
+	r := NewRouter(detector, &fakeCalls{}, &memoryPublisher{}, zap.NewNop())
+	s := newRoutedSession(billingRouting)
+
+	d := r.Detect(context.Background(), s, "I want to cancel subscription today")
+	if d == nil || d.Intent != "cancel_subscription" {
+		t.Fatalf("Expected keyword intent match, got %+v", d)
+	}
+	if detector.calls != 0 {
+		t.Error("Detector should not run when a keyword matches")
+	}
+}
+
+func TestRouter_DetectEscalationTrigger(t *testing.T) {
+	r := NewRouter(nil, &fakeCalls{}, &memoryPublisher{}, zap.NewNop())
+	s := newRoutedSession(billingRouting)
+
+	d := r.Detect(context.Background(), s, "Quero FALAR com um atendente, por favor")
+	if d == nil || d.Trigger != routing.TriggerEscalation {
+		t.Fatalf("Expected escalation decision, got %+v", d)
+	}
+}
+
+func TestRouter_NoTransfer(t *testing.T) {
+	s := newRoutedSession(billingRouting)
+
+	r := NewRouter(&fakeDetector{}, &fakeCalls{}, &memoryPublisher{}, zap.NewNop())
+	if d := r.Detect(context.Background(), s, "What time do you open?"); d != nil {
+		t.Errorf("Unexpected decision %+v", d)
+	}
+
+	r = NewRouter(&fakeDetector{err: errors.New("timeout")}, &fakeCalls{}, &memoryPublisher{}, zap.NewNop())
+	if d := r.Detect(context.Background(), s, "What time do you open?"); d != nil {
+		t.Errorf("Detector failure should not transfer, got %+v", d)
+	}
+
+	cannotRoute := billingRouting
+	cannotRoute.CanRoute = false
+	r = NewRouter(&fakeDetector{intent: "billing_dispute"}, &fakeCalls{}, &memoryPublisher{}, zap.NewNop())
+	if d := r.Detect(context.Background(), newRoutedSession(cannotRoute), "charged twice"); d != nil {
+		t.Errorf("Agents that cannot route should not transfer, got %+v", d)
+	}
+}
+
+func TestRouter_Transfer(t *testing.T) {
+	calls, pub := &fakeCalls{}, &memoryPublisher{}
+	r := NewRouter(nil, calls, pub, zap.NewNop())
+	s := newRoutedSession(billingRouting)
+	s.CallID = uuid.New()
+
+	d := r.Decision(s, routing.TriggerIntent, "intent: billing_dispute")
+	if err := r.Transfer(context.Background(), d); err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+
+	if len(calls.transfers) != 1 {
+		t.Fatalf("Expected one voice-gateway transfer, got %d", len(calls.transfers))
+	}
+	got := calls.transfers[0]
+	if got.callID != s.CallID || got.transferType != "queue" || got.target != "billing" || got.reason != "intent: billing_dispute" {
+		t.Errorf("Unexpected transfer %+v", got)
+	}
+	if !d.Transferred || len(pub.decisions) != 1 {
+		t.Errorf("Expected published successful decision, got %+v", d)
+	}
+}
+
+func TestRouter_TransferFailureAndChat(t *testing.T) {
+	calls, pub := &fakeCalls{err: errors.New("call not found")}, &memoryPublisher{}
+	r := NewRouter(nil, calls, pub, zap.NewNop())
+	s := newRoutedSession(billingRouting)
+	s.CallID = uuid.New()
+
+	d := r.Decision(s, routing.TriggerEscalation, "escalation requested")
+	if err := r.Transfer(context.Background(), d); err == nil {
+		t.Fatal("Expected transfer error")
+	}
+	if d.Transferred || d.Error == "" || len(pub.decisions) != 1 {
+		t.Errorf("Expected failed decision to be published, got %+v", d)
+	}
+
+	// Chat sessions have no call to transfer
+	chat := newRoutedSession(billingRouting)
+	calls.err = nil
+	if err := r.Transfer(context.Background(), r.Decision(chat, routing.TriggerIntent, "intent")); err != nil {
+		t.Errorf("Chat transfer should not fail: %v", err)
+	}
+	if len(calls.transfers) != 1 {
+		t.Error("Voice-gateway should not be called without a call")
+	}
+}
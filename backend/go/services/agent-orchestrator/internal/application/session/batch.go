@@ -0,0 +1,162 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// MaxBatchTurns bounds the turns of one batch.
+const MaxBatchTurns = 200
+
+// ErrInvalidBatch is returned for an empty or oversized batch, or one with
+// turns its mode can't take.
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// BatchTurn is a turn of a batch. Only recorded batches take assistant
+// turns; the role defaults to user.
+type BatchTurn struct {
+	Role    session.Role `json:"role"`
+	Content string       `json:"content"`
+}
+
+// BatchParams holds an ordered list of turns for a session. A recorded batch
+// replays a historical transcript: its turns are added to the history as they
+// are, without calling the model, the guardrails or the routing.
+type BatchParams struct {
+	Turns  []BatchTurn
+	Record bool
+}
+
+// BatchResult is the session after a batch. Replies holds the agent reply to
+// each user turn, in order, unless the batch was recorded. Processed counts
+// the turns the session took; the turns after it ended are dropped.
+type BatchResult struct {
+	SessionID uuid.UUID      `json:"session_id"`
+	Processed int            `json:"processed"`
+	Replies   []*Reply       `json:"replies,omitempty"`
+	Turns     []session.Turn `json:"turns"`
+	Summary   string         `json:"summary,omitempty"`
+	Usage     session.Usage  `json:"usage"`
+	Spend     session.Spend  `json:"spend"`
+	Status    session.Status `json:"status"`
+	EndReason string         `json:"end_reason,omitempty"`
+}
+
+// SendBatch runs the turns of a batch through the session in order, as if
+// sent one by one, and returns the resulting context. The agent MaxTurns
+// applies across the batch: at the first user turn over the limit the
+// session ends, through the guard when one is set, and the remaining turns
+// are dropped.
+func (s *Service) SendBatch(ctx context.Context, id uuid.UUID, params BatchParams) (*BatchResult, error) {
+	if err := validateBatch(params); err != nil {
+		return nil, err
+	}
+
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.IsActive() {
+		return nil, ErrSessionEnded
+	}
+	if s.admission != nil {
+		release, err := s.admission.Acquire(ctx, sess.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	result := &BatchResult{SessionID: sess.ID}
+	for _, turn := range params.Turns {
+		if !sess.IsActive() {
+			break
+		}
+		role := turnRole(turn)
+		// A guard answers the turn over the limit as it does single messages
+		if role == session.RoleUser && turnLimitReached(sess) && (params.Record || s.guard == nil) {
+			sess.EndWithReason(session.EndReasonMaxTurns)
+			if err := s.repo.Save(ctx, sess); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		result.Processed++
+		if params.Record {
+			sess.AddTurn(role, turn.Content)
+			continue
+		}
+
+		reply, err := s.respond(ctx, sess, turn.Content)
+		if err != nil {
+			return nil, err
+		}
+		if s.tracker != nil {
+			s.tracker.Exchanged(ctx, sess, turn.Content, reply)
+		}
+		result.Replies = append(result.Replies, reply)
+	}
+
+	if params.Record && sess.IsActive() {
+		if err := s.repo.Save(ctx, sess); err != nil {
+			return nil, err
+		}
+	}
+
+	s.touch(sess)
+
+	s.logger.Info("batch processed",
+		zap.String("session_id", sess.ID.String()),
+		zap.Int("turns", len(params.Turns)),
+		zap.Int("processed", result.Processed),
+		zap.Bool("record", params.Record),
+	)
+
+	result.Turns = sess.Turns
+	result.Summary = sess.Summary
+	result.Usage = sess.Usage(s.window.MaxTokens())
+	result.Spend = sess.Spend
+	result.Status = sess.Status
+	result.EndReason = sess.EndReason
+	return result, nil
+}
+
+// validateBatch checks the size of a batch and the roles of its turns.
+func validateBatch(params BatchParams) error {
+	if len(params.Turns) == 0 {
+		return fmt.Errorf("%w: no turns", ErrInvalidBatch)
+	}
+	if len(params.Turns) > MaxBatchTurns {
+		return fmt.Errorf("%w: at most %d turns are allowed", ErrInvalidBatch, MaxBatchTurns)
+	}
+	for i, turn := range params.Turns {
+		switch role := turnRole(turn); {
+		case turn.Content == "":
+			return fmt.Errorf("%w: turn %d has no content", ErrInvalidBatch, i)
+		case role == session.RoleAssistant && !params.Record:
+			return fmt.Errorf("%w: turn %d: assistant turns are only recorded", ErrInvalidBatch, i)
+		case role != session.RoleUser && role != session.RoleAssistant:
+			return fmt.Errorf("%w: turn %d has invalid role %q", ErrInvalidBatch, i, role)
+		}
+	}
+	return nil
+}
+
+func turnRole(turn BatchTurn) session.Role {
+	if turn.Role == "" {
+		return session.RoleUser
+	}
+	return turn.Role
+}
+
+// turnLimitReached reports whether the session used up the agent MaxTurns.
+func turnLimitReached(sess *session.Session) bool {
+	return sess.Agent != nil && sess.Agent.Safety.MaxTurns > 0 && sess.UserTurns >= sess.Agent.Safety.MaxTurns
+}
@@ -0,0 +1,158 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	domainguardrail "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+func newBatchService(client *fakeLLM, maxTurns int) (*Service, uuid.UUID) {
+	repo := newMemoryRepo()
+	window := NewContextWindow(client, ContextConfig{MaxTokens: 6000, Threshold: 0.8, KeepRecentTurns: 6}, zap.NewNop())
+	service := NewService(repo, client, window, Config{Model: "test-model"}, zap.NewNop())
+	service.SetAgentConfigs(fixedAgents{config: &agent.Config{Safety: agent.SafetyConfig{MaxTurns: maxTurns}}})
+
+	sess, _ := service.CreateSession(context.Background(), CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	return service, sess.ID
+}
+
+func userTurns(contents ...string) []BatchTurn {
+	turns := make([]BatchTurn, len(contents))
+	for i, content := range contents {
+		turns[i] = BatchTurn{Content: content}
+	}
+	return turns
+}
+
+func TestSendBatch_ProcessesInOrder(t *testing.T) {
+	client := &fakeLLM{content: "Sure."}
+	service, id := newBatchService(client, 0)
+
+	result, err := service.SendBatch(context.Background(), id, BatchParams{Turns: userTurns("first", "second", "third")})
+	if err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+	if result.Processed != 3 || len(result.Replies) != 3 || len(client.requests) != 3 {
+		t.Fatalf("Expected 3 turns answered, got %d processed, %d replies, %d completions", result.Processed, len(result.Replies), len(client.requests))
+	}
+
+	// Each completion sees the messages before it
+	for i, want := range []string{"first", "second", "third"} {
+		messages := client.requests[i].Messages
+		if last := messages[len(messages)-1]; last.Content != want {
+			t.Errorf("Completion %d answered %q, want %q", i, last.Content, want)
+		}
+	}
+	var history []string
+	for _, turn := range result.Turns {
+		history = append(history, string(turn.Role)+":"+turn.Content)
+	}
+	want := []string{"user:first", "assistant:Sure.", "user:second", "assistant:Sure.", "user:third", "assistant:Sure."}
+	if len(history) != len(want) {
+		t.Fatalf("Expected history %v, got %v", want, history)
+	}
+	for i := range want {
+		if history[i] != want[i] {
+			t.Errorf("Turn %d: expected %s, got %s", i, want[i], history[i])
+		}
+	}
+	if result.Status != session.StatusActive {
+		t.Errorf("Expected the session to stay active, got %s", result.Status)
+	}
+}
+
+func TestSendBatch_MaxTurnsMidBatch(t *testing.T) {
+	client := &fakeLLM{content: "Sure."}
+	service, id := newBatchService(client, 2)
+
+	result, err := service.SendBatch(context.Background(), id, BatchParams{Turns: userTurns("one", "two", "three", "four")})
+	if err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+	if result.Processed != 2 || len(client.requests) != 2 {
+		t.Errorf("Expected 2 turns within the limit, got %d processed and %d completions", result.Processed, len(client.requests))
+	}
+	if result.Status != session.StatusEnded || result.EndReason != session.EndReasonMaxTurns {
+		t.Errorf("Expected the session ended at max turns, got %s (%s)", result.Status, result.EndReason)
+	}
+
+	if _, err := service.SendBatch(context.Background(), id, BatchParams{Turns: userTurns("five")}); !errors.Is(err, ErrSessionEnded) {
+		t.Errorf("Expected ErrSessionEnded after the limit, got %v", err)
+	}
+}
+
+func TestSendBatch_MaxTurnsThroughGuard(t *testing.T) {
+	client := &fakeLLM{content: "Sure."}
+	service, id := newBatchService(client, 1)
+	recorder := &violationRecorder{}
+	service.SetGuard(guardrail.NewGuard(nil, recorder, guardrail.Config{EndMessage: "Bye."}, zap.NewNop()))
+
+	result, err := service.SendBatch(context.Background(), id, BatchParams{Turns: userTurns("one", "two", "three")})
+	if err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+	if len(client.requests) != 1 || len(result.Replies) != 2 {
+		t.Fatalf("Expected one answer and the guard end message, got %d completions and %d replies", len(client.requests), len(result.Replies))
+	}
+	if last := result.Replies[1]; !last.Blocked || last.Content != "Bye." || last.EndReason != session.EndReasonMaxTurns {
+		t.Errorf("Expected the guard to end the session, got %+v", last)
+	}
+	if len(recorder.violations) != 1 || recorder.violations[0].Kind != domainguardrail.KindMaxTurns {
+		t.Errorf("Expected a max turns violation, got %+v", recorder.violations)
+	}
+}
+
+func TestSendBatch_Record(t *testing.T) {
+	client := &fakeLLM{content: "Sure."}
+	service, id := newBatchService(client, 2)
+
+	result, err := service.SendBatch(context.Background(), id, BatchParams{Record: true, Turns: []BatchTurn{
+		{Role: session.RoleUser, Content: "Hi"},
+		{Role: session.RoleAssistant, Content: "Hello, how can I help?"},
+		{Role: session.RoleUser, Content: "Opening hours?"},
+		{Role: session.RoleAssistant, Content: "9 to 5."},
+		{Role: session.RoleUser, Content: "Thanks"},
+	}})
+	if err != nil {
+		t.Fatalf("SendBatch failed: %v", err)
+	}
+	if len(client.requests) != 0 || len(result.Replies) != 0 {
+		t.Errorf("A recorded batch should not call the model, got %d completions", len(client.requests))
+	}
+	if result.Processed != 4 || len(result.Turns) != 4 || result.Turns[3].Content != "9 to 5." {
+		t.Errorf("Expected the 4 turns within the limit recorded, got %d: %+v", result.Processed, result.Turns)
+	}
+	if result.EndReason != session.EndReasonMaxTurns {
+		t.Errorf("Expected the session ended at max turns, got %q", result.EndReason)
+	}
+}
+
+func TestSendBatch_Invalid(t *testing.T) {
+	service, id := newBatchService(&fakeLLM{content: "Sure."}, 0)
+
+	tests := []struct {
+		name   string
+		params BatchParams
+	}{
+		{name: "empty", params: BatchParams{}},
+		{name: "assistant turn when live", params: BatchParams{Turns: []BatchTurn{{Role: session.RoleAssistant, Content: "Hi"}}}},
+		{name: "system turn", params: BatchParams{Record: true, Turns: []BatchTurn{{Role: session.RoleSystem, Content: "Hi"}}}},
+		{name: "no content", params: BatchParams{Turns: []BatchTurn{{Content: ""}}}},
+		{name: "too many", params: BatchParams{Turns: make([]BatchTurn, MaxBatchTurns+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.SendBatch(context.Background(), id, tt.params); !errors.Is(err, ErrInvalidBatch) {
+				t.Errorf("Expected ErrInvalidBatch, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// DefaultCacheSize is how many sessions a replica keeps in memory.
+const DefaultCacheSize = 10000
+
+// VersionedRepository persists sessions and reports the stored version of a
+// session cheaply.
+type VersionedRepository interface {
+	Repository
+	Version(ctx context.Context, id uuid.UUID) (int64, error)
+}
+
+// cachedSession is a session as last saved or loaded by this replica.
+type cachedSession struct {
+	version  int64
+	data     []byte
+	lastUsed time.Time
+}
+
+// CachedRepository is a write-through cache in front of the shared session
+// store. Every save goes to the store first, so a session survives a restart
+// and can be served by any replica; reads only load the full session from the
+// store when its stored version differs from the cached one, i.e. when
+// another replica updated it. It is safe for concurrent use.
+type CachedRepository struct {
+	store   VersionedRepository
+	maxSize int
+	now     func() time.Time
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*cachedSession
+}
+
+// NewCachedRepository creates a cache of at most maxSize sessions in front of
+// store. A zero maxSize means DefaultCacheSize.
+func NewCachedRepository(store VersionedRepository, maxSize int) *CachedRepository {
+	if maxSize <= 0 {
+		maxSize = DefaultCacheSize
+	}
+	return &CachedRepository{
+		store:    store,
+		maxSize:  maxSize,
+		now:      time.Now,
+		sessions: make(map[uuid.UUID]*cachedSession),
+	}
+}
+
+// Save writes a session to the store, then caches it. A version conflict
+// evicts the cached copy so the next read loads the newer session.
+func (r *CachedRepository) Save(ctx context.Context, s *session.Session) error {
+	if err := r.store.Save(ctx, s); err != nil {
+		if errors.Is(err, session.ErrVersionConflict) {
+			r.Evict(s.ID)
+		}
+		return err
+	}
+	r.put(s)
+	return nil
+}
+
+// Get returns a session, from the cache when it is at the stored version.
+// Each call returns its own copy.
+func (r *CachedRepository) Get(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	version, err := r.store.Version(ctx, id)
+	if err != nil {
+		r.Evict(id)
+		return nil, err
+	}
+
+	r.mu.Lock()
+	cached, ok := r.sessions[id]
+	var data []byte
+	if ok && cached.version == version {
+		cached.lastUsed = r.now()
+		data = cached.data
+	}
+	r.mu.Unlock()
+
+	if data != nil {
+		var s session.Session
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cached session: %w", err)
+		}
+		return &s, nil
+	}
+
+	s, err := r.store.Get(ctx, id)
+	if err != nil {
+		r.Evict(id)
+		return nil, err
+	}
+	r.put(s)
+	return s, nil
+}
+
+// Delete removes a session from the store and the cache.
+func (r *CachedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.Evict(id)
+	return r.store.Delete(ctx, id)
+}
+
+// Evict drops the cached copy of a session.
+func (r *CachedRepository) Evict(id uuid.UUID) {
+	r.mu.Lock()
+	delete(r.sessions, id)
+	r.mu.Unlock()
+}
+
+// put caches a copy of s, evicting the least recently used session when the
+// cache is full.
+func (r *CachedRepository) put(s *session.Session) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		r.Evict(s.ID)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[s.ID]; !ok && len(r.sessions) >= r.maxSize {
+		var oldestID uuid.UUID
+		var oldest time.Time
+		for id, c := range r.sessions {
+			if oldest.IsZero() || c.lastUsed.Before(oldest) {
+				oldestID, oldest = id, c.lastUsed
+			}
+		}
+		delete(r.sessions, oldestID)
+	}
+	r.sessions[s.ID] = &cachedSession{version: s.Version, data: data, lastUsed: r.now()}
+}
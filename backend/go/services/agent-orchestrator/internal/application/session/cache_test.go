@@ -0,0 +1,203 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// sharedStore is the session store shared by replicas, with the same
+// optimistic locking as the Redis repository.
+type sharedStore struct {
+	mu    sync.Mutex
+	data  map[uuid.UUID][]byte
+	loads int
+}
+
+func newSharedStore() *sharedStore {
+	return &sharedStore{data: make(map[uuid.UUID][]byte)}
+}
+
+func (s *sharedStore) Save(ctx context.Context, sess *session.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.data[sess.ID]; ok {
+		var current session.Session
+		json.Unmarshal(stored, &current)
+		if current.Version != sess.Version {
+			return session.ErrVersionConflict
+		}
+	}
+	saved := *sess
+	saved.Version++
+	s.data[sess.ID], _ = json.Marshal(&saved)
+	sess.Version = saved.Version
+	return nil
+}
+
+func (s *sharedStore) Get(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.data[id]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	s.loads++
+	var sess session.Session
+	err := json.Unmarshal(data, &sess)
+	return &sess, err
+}
+
+func (s *sharedStore) Delete(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, id)
+	return nil
+}
+
+func (s *sharedStore) Version(ctx context.Context, id uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.data[id]
+	if !ok {
+		return 0, errors.New("session not found")
+	}
+	var stored struct {
+		Version int64 `json:"version"`
+	}
+	err := json.Unmarshal(data, &stored)
+	return stored.Version, err
+}
+
+// newReplica creates a session service with its own cache over store.
+func newReplica(store *sharedStore, client *fakeLLM, cacheSize int) (*Service, *CachedRepository) {
+	repo := NewCachedRepository(store, cacheSize)
+	window := NewContextWindow(client, ContextConfig{MaxTokens: 6000, Threshold: 0.8, KeepRecentTurns: 6}, zap.NewNop())
+	return NewService(repo, client, window, Config{Model: "test-model"}, zap.NewNop()), repo
+}
+
+func TestCachedRepository_ServesUnchangedSessionsFromMemory(t *testing.T) {
+	store := newSharedStore()
+	service, _ := newReplica(store, &fakeLLM{content: "Sure."}, 0)
+	ctx := context.Background()
+
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	for i := 0; i < 3; i++ {
+		if _, err := service.SendMessage(ctx, sess.ID, "hello"); err != nil {
+			t.Fatalf("SendMessage failed: %v", err)
+		}
+	}
+
+	if store.loads != 0 {
+		t.Errorf("Expected no full loads from the store, got %d", store.loads)
+	}
+}
+
+func TestCachedRepository_ResumeAfterEviction(t *testing.T) {
+	store := newSharedStore()
+	client := &fakeLLM{content: "Sure."}
+	service, repo := newReplica(store, client, 1)
+	ctx := context.Background()
+
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	if _, err := service.SendMessage(ctx, sess.ID, "first"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// Another session pushes the first one out of the one-entry cache
+	service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	repo.mu.Lock()
+	_, cached := repo.sessions[sess.ID]
+	repo.mu.Unlock()
+	if cached {
+		t.Fatal("Expected the first session to be evicted")
+	}
+
+	resumed, err := service.ResumeSession(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if len(resumed.Turns) != 2 || resumed.Turns[0].Content != "first" {
+		t.Errorf("Expected the persisted turns, got %+v", resumed.Turns)
+	}
+
+	// A restarted replica continues the conversation with its context
+	restarted, _ := newReplica(store, client, 0)
+	if _, err := restarted.SendMessage(ctx, sess.ID, "second"); err != nil {
+		t.Fatalf("SendMessage after restart failed: %v", err)
+	}
+	last := client.requests[len(client.requests)-1].Messages
+	if len(last) < 3 || last[len(last)-3].Content != "first" {
+		t.Errorf("Expected the resumed history in the prompt, got %+v", last)
+	}
+}
+
+func TestCachedRepository_CrossReplicaContinuity(t *testing.T) {
+	store := newSharedStore()
+	client := &fakeLLM{content: "Sure."}
+	a, _ := newReplica(store, client, 0)
+	b, _ := newReplica(store, client, 0)
+	ctx := context.Background()
+
+	sess, _ := a.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	for i, replica := range []*Service{a, b, a, b} {
+		if _, err := replica.SendMessage(ctx, sess.ID, string(rune('1'+i))); err != nil {
+			t.Fatalf("SendMessage %d failed: %v", i, err)
+		}
+	}
+
+	final, err := b.GetSession(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if len(final.Turns) != 8 {
+		t.Fatalf("Expected 8 turns, got %d", len(final.Turns))
+	}
+	for i := 0; i < 4; i++ {
+		if turn := final.Turns[2*i]; turn.Role != session.RoleUser || turn.Content != string(rune('1'+i)) {
+			t.Errorf("Turn %d = %s %q, expected user message %d", 2*i, turn.Role, turn.Content, i+1)
+		}
+	}
+	if final.Version != 5 {
+		t.Errorf("Expected version 5 after create and 4 messages, got %d", final.Version)
+	}
+}
+
+func TestCachedRepository_StaleSaveConflicts(t *testing.T) {
+	store := newSharedStore()
+	a, repoA := newReplica(store, &fakeLLM{content: "Sure."}, 0)
+	repoB := NewCachedRepository(store, 0)
+	ctx := context.Background()
+
+	sess, _ := a.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	stale, _ := repoA.Get(ctx, sess.ID)
+
+	fresh, _ := repoB.Get(ctx, sess.ID)
+	fresh.AddTurn(session.RoleUser, "from b")
+	if err := repoB.Save(ctx, fresh); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	stale.AddTurn(session.RoleUser, "from a")
+	if err := repoA.Save(ctx, stale); !errors.Is(err, session.ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got %v", err)
+	}
+
+	current, err := repoA.Get(ctx, sess.ID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(current.Turns) != 1 || current.Turns[0].Content != "from b" {
+		t.Errorf("Expected the turn saved by the other replica, got %+v", current.Turns)
+	}
+}
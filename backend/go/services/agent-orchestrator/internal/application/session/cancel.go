@@ -0,0 +1,119 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// ErrTurnCancelled is returned for a turn cancelled before its reply, e.g.
+// because the caller hung up. Nothing of the turn is kept in the session.
+var ErrTurnCancelled = errors.New("turn cancelled")
+
+// Reasons of a cancelled turn.
+const (
+	CancelReasonRequested    = "requested"    // through CancelTurn
+	CancelReasonDisconnected = "disconnected" // the client went away mid-turn
+)
+
+// CancelPublisher announces the turns cancelled before their reply.
+type CancelPublisher interface {
+	PublishTurnCancelled(ctx context.Context, s *session.Session, reason string) error
+}
+
+// inflight holds the turns this replica is answering, by session. Turns
+// answered by another replica are cancelled there when the client drops the
+// request.
+type inflight struct {
+	mu    sync.Mutex
+	turns map[uuid.UUID]map[*inflightTurn]bool
+}
+
+// inflightTurn is a turn being answered; its address tells it from another
+// turn of the same session.
+type inflightTurn struct {
+	cancel context.CancelCauseFunc
+}
+
+// SetCancelPublisher publishes the turns cancelled before their reply.
+func (s *Service) SetCancelPublisher(publisher CancelPublisher) {
+	s.cancelPublisher = publisher
+}
+
+// CancelTurn aborts the turns of a session in flight on this replica, along
+// with their model calls. It reports whether there was any.
+func (s *Service) CancelTurn(ctx context.Context, id uuid.UUID) (bool, error) {
+	if _, err := s.repo.Get(ctx, id); err != nil {
+		return false, err
+	}
+
+	s.inflight.mu.Lock()
+	defer s.inflight.mu.Unlock()
+	for t := range s.inflight.turns[id] {
+		t.cancel(ErrTurnCancelled)
+	}
+	return len(s.inflight.turns[id]) > 0, nil
+}
+
+// startTurn derives the context of a turn, cancelled by CancelTurn as well
+// as by the caller.
+func (s *Service) startTurn(ctx context.Context, id uuid.UUID) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	t := &inflightTurn{cancel: cancel}
+
+	s.inflight.mu.Lock()
+	if s.inflight.turns == nil {
+		s.inflight.turns = make(map[uuid.UUID]map[*inflightTurn]bool)
+	}
+	if s.inflight.turns[id] == nil {
+		s.inflight.turns[id] = make(map[*inflightTurn]bool)
+	}
+	s.inflight.turns[id][t] = true
+	s.inflight.mu.Unlock()
+
+	return ctx, func() {
+		s.inflight.mu.Lock()
+		delete(s.inflight.turns[id], t)
+		if len(s.inflight.turns[id]) == 0 {
+			delete(s.inflight.turns, id)
+		}
+		s.inflight.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancelled reports whether the turn context was cancelled, rather than
+// failing on its own, and why.
+func cancelled(turnCtx context.Context) (string, bool) {
+	if !errors.Is(turnCtx.Err(), context.Canceled) {
+		return "", false
+	}
+	if errors.Is(context.Cause(turnCtx), ErrTurnCancelled) {
+		return CancelReasonRequested, true
+	}
+	return CancelReasonDisconnected, true
+}
+
+// turnCancelled publishes a turn cancelled before its reply.
+func (s *Service) turnCancelled(ctx context.Context, sess *session.Session, reason string) error {
+	s.logger.Info("turn cancelled",
+		zap.String("session_id", sess.ID.String()),
+		zap.String("reason", reason),
+	)
+
+	if s.cancelPublisher != nil {
+		// The request context may be the one that was cancelled
+		if err := s.cancelPublisher.PublishTurnCancelled(context.WithoutCancel(ctx), sess, reason); err != nil {
+			s.logger.Warn("failed to publish turn cancellation",
+				zap.String("session_id", sess.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+	return ErrTurnCancelled
+}
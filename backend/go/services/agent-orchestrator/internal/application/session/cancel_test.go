@@ -0,0 +1,178 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// snapshotRepo stores copies of sessions, so turns added to a session and
+// never saved are lost as with Redis.
+type snapshotRepo struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]session.Session
+}
+
+func (r *snapshotRepo) Save(ctx context.Context, s *session.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *s
+	stored.Turns = append([]session.Turn(nil), s.Turns...)
+	r.sessions[s.ID] = stored
+	return nil
+}
+
+func (r *snapshotRepo) Get(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New("session not found")
+	}
+	stored.Turns = append([]session.Turn(nil), stored.Turns...)
+	return &stored, nil
+}
+
+func (r *snapshotRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.sessions, id)
+	return nil
+}
+
+// hangingLLM blocks until its request is cancelled, like a model still
+// generating, and reports the cancellation of the upstream call.
+type hangingLLM struct {
+	started chan struct{}
+	aborted chan error
+}
+
+func (h *hangingLLM) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	close(h.started)
+	<-ctx.Done()
+	h.aborted <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func (h *hangingLLM) Provider() string { return "fake" }
+
+type cancelRecorder struct {
+	mu      sync.Mutex
+	reasons []string
+}
+
+func (r *cancelRecorder) PublishTurnCancelled(ctx context.Context, s *session.Session, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reasons = append(r.reasons, reason)
+	return nil
+}
+
+func newCancelService(client llm.Client) (*Service, *snapshotRepo, *cancelRecorder, uuid.UUID) {
+	repo := &snapshotRepo{sessions: make(map[uuid.UUID]session.Session)}
+	window := NewContextWindow(client, ContextConfig{MaxTokens: 6000, Threshold: 0.8, KeepRecentTurns: 6}, zap.NewNop())
+	service := NewService(repo, client, window, Config{Model: "test-model"}, zap.NewNop())
+	recorder := &cancelRecorder{}
+	service.SetCancelPublisher(recorder)
+
+	sess, _ := service.CreateSession(context.Background(), CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	return service, repo, recorder, sess.ID
+}
+
+func TestService_CancelTurnAbortsModelCall(t *testing.T) {
+	client := &hangingLLM{started: make(chan struct{}), aborted: make(chan error, 1)}
+	service, repo, recorder, id := newCancelService(client)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := service.SendMessage(context.Background(), id, "Hello?")
+		errs <- err
+	}()
+	<-client.started
+
+	ok, err := service.CancelTurn(context.Background(), id)
+	if err != nil || !ok {
+		t.Fatalf("Expected the in-flight turn cancelled, got %v, %v", ok, err)
+	}
+
+	select {
+	case err := <-client.aborted:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the model call cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the model call aborted")
+	}
+	if err := <-errs; !errors.Is(err, ErrTurnCancelled) {
+		t.Errorf("Expected ErrTurnCancelled, got %v", err)
+	}
+
+	sess, _ := repo.Get(context.Background(), id)
+	if len(sess.Turns) != 0 {
+		t.Errorf("Expected nothing of the turn kept, got %+v", sess.Turns)
+	}
+	if len(recorder.reasons) != 1 || recorder.reasons[0] != CancelReasonRequested {
+		t.Errorf("Expected a requested turn.cancelled, got %v", recorder.reasons)
+	}
+
+	// The next turn isn't affected
+	if ok, _ := service.CancelTurn(context.Background(), id); ok {
+		t.Error("Expected no turn in flight after the cancellation")
+	}
+}
+
+func TestService_ClientDisconnectCancelsTurn(t *testing.T) {
+	client := &hangingLLM{started: make(chan struct{}), aborted: make(chan error, 1)}
+	service, _, recorder, id := newCancelService(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := service.SendMessage(ctx, id, "Hello?")
+		errs <- err
+	}()
+	<-client.started
+	cancel()
+
+	if err := <-errs; !errors.Is(err, ErrTurnCancelled) {
+		t.Errorf("Expected ErrTurnCancelled, got %v", err)
+	}
+	if len(recorder.reasons) != 1 || recorder.reasons[0] != CancelReasonDisconnected {
+		t.Errorf("Expected a disconnected turn.cancelled, got %v", recorder.reasons)
+	}
+}
+
+// lateCancelLLM answers even though the turn is cancelled while it runs.
+type lateCancelLLM struct {
+	cancel func()
+}
+
+func (l *lateCancelLLM) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	l.cancel()
+	return &llm.Completion{Content: "Partial answer"}, nil
+}
+
+func (l *lateCancelLLM) Provider() string { return "fake" }
+
+func TestService_CancelledTurnDiscardsReply(t *testing.T) {
+	client := &lateCancelLLM{}
+	service, repo, _, id := newCancelService(client)
+	client.cancel = func() { service.CancelTurn(context.Background(), id) }
+
+	if _, err := service.SendMessage(context.Background(), id, "Hello?"); !errors.Is(err, ErrTurnCancelled) {
+		t.Fatalf("Expected ErrTurnCancelled, got %v", err)
+	}
+
+	sess, _ := repo.Get(context.Background(), id)
+	if len(sess.Turns) != 0 {
+		t.Errorf("Expected the reply discarded, got %+v", sess.Turns)
+	}
+}
@@ -0,0 +1,140 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// DefaultSummaryPrompt instructs the model how to summarize older turns.
+const DefaultSummaryPrompt = "Summarize the conversation below for a customer service agent who will continue it. " +
+	"Keep names, numbers, commitments, open questions and the caller's goal. " +
+	"Merge it with the previous summary, if any. Answer with the summary only."
+
+// ContextConfig configures context-window management.
+type ContextConfig struct {
+	MaxTokens       int     // token budget for summary and history
+	Threshold       float64 // fraction of MaxTokens that triggers summarization, e.g. 0.8
+	KeepRecentTurns int     // turns kept verbatim after summarization
+	SummaryPrompt   string  // system prompt used to summarize
+	SummaryModel    string  // model used to summarize
+}
+
+// ContextWindow keeps session history within the model context window by
+// summarizing older turns into a running summary.
+type ContextWindow struct {
+	llmClient llm.Client
+	config    ContextConfig
+	charger   Charger
+	logger    *zap.Logger
+}
+
+// NewContextWindow creates a new context-window manager.
+func NewContextWindow(llmClient llm.Client, config ContextConfig, logger *zap.Logger) *ContextWindow {
+	if config.SummaryPrompt == "" {
+		config.SummaryPrompt = DefaultSummaryPrompt
+	}
+	return &ContextWindow{
+		llmClient: llmClient,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// MaxTokens returns the token budget of a session.
+func (w *ContextWindow) MaxTokens() int {
+	return w.config.MaxTokens
+}
+
+// TriggerTokens returns the token count at which older turns are summarized.
+func (w *ContextWindow) TriggerTokens() int {
+	return int(float64(w.config.MaxTokens) * w.config.Threshold)
+}
+
+// NeedsCompaction reports whether the session reached the summarization
+// threshold and has turns older than the ones kept verbatim.
+func (w *ContextWindow) NeedsCompaction(s *session.Session) bool {
+	if w.config.MaxTokens <= 0 {
+		return false
+	}
+	return s.TokenCount() >= w.TriggerTokens() && len(s.Turns) > w.config.KeepRecentTurns
+}
+
+// Compact summarizes older turns when the session reached the threshold.
+// It returns true if the session was compacted.
+func (w *ContextWindow) Compact(ctx context.Context, s *session.Session) (bool, error) {
+	if !w.NeedsCompaction(s) {
+		return false, nil
+	}
+
+	before := s.TokenCount()
+	older := s.Turns[:len(s.Turns)-w.config.KeepRecentTurns]
+
+	completion, err := w.llmClient.Complete(ctx, llm.CompletionRequest{
+		Model: w.config.SummaryModel,
+		Messages: []llm.Message{
+			{Role: string(session.RoleSystem), Content: w.config.SummaryPrompt},
+			{Role: string(session.RoleUser), Content: summaryInput(s.Summary, older)},
+		},
+		TenantID:  s.TenantID,
+		SessionID: s.ID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to summarize session: %w", err)
+	}
+
+	if w.charger != nil {
+		w.charger.Charge(ctx, s, w.llmClient.Provider(), completion)
+	}
+	s.Compact(strings.TrimSpace(completion.Content), w.config.KeepRecentTurns)
+
+	w.logger.Info("session context summarized",
+		zap.String("session_id", s.ID.String()),
+		zap.Int("tokens_before", before),
+		zap.Int("tokens_after", s.TokenCount()),
+		zap.Int("summarized_turns", s.SummarizedTurns),
+	)
+
+	return true, nil
+}
+
+// Messages builds the model input from the agent prompt, the running summary
+// and the recent turns.
+func (w *ContextWindow) Messages(s *session.Session, systemPrompt string) []llm.Message {
+	messages := make([]llm.Message, 0, len(s.Turns)+2)
+
+	if systemPrompt != "" {
+		messages = append(messages, llm.Message{Role: string(session.RoleSystem), Content: systemPrompt})
+	}
+	if s.Summary != "" {
+		messages = append(messages, llm.Message{
+			Role:    string(session.RoleSystem),
+			Content: "Summary of the conversation so far: " + s.Summary,
+		})
+	}
+	for _, t := range s.Turns {
+		messages = append(messages, llm.Message{Role: string(t.Role), Content: t.Content})
+	}
+
+	return messages
+}
+
+// summaryInput renders the previous summary and the turns to summarize.
+func summaryInput(previous string, turns []session.Turn) string {
+	var b strings.Builder
+	if previous != "" {
+		b.WriteString("Previous summary:\n")
+		b.WriteString(previous)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Conversation:\n")
+	for _, t := range turns {
+		fmt.Fprintf(&b, "%s: %s\n", t.Role, t.Content)
+	}
+	return b.String()
+}
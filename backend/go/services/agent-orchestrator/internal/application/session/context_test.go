@@ -0,0 +1,207 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// fakeLLM returns a fixed completion and records the requests.
+type fakeLLM struct {
+	content  string
+	err      error
+	usage    llm.Usage
+	requests []llm.CompletionRequest
+}
+
+func (f *fakeLLM) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &llm.Completion{Content: f.content, Model: req.Model, Usage: f.usage}, nil
+}
+
+func (f *fakeLLM) Provider() string { return "fake" }
+
+// fourTokens is a message estimated at exactly 4 tokens.
+const fourTokens = "abcdefghijklmnop"
+
+func newTestWindow(client llm.Client) *ContextWindow {
+	return NewContextWindow(client, ContextConfig{
+		MaxTokens:       40,
+		Threshold:       0.5, // triggers at 20 tokens
+		KeepRecentTurns: 2,
+		SummaryPrompt:   "Summarize.",
+		SummaryModel:    "gpt-4o-mini",
+	}, zap.NewNop())
+}
+
+func newSessionWithTurns(n int) *session.Session {
+	s := session.New(uuid.New(), "agent-receptionist")
+	for i := 0; i < n; i++ {
+		role := session.RoleUser
+		if i%2 == 1 {
+			role = session.RoleAssistant
+		}
+		s.AddTurn(role, fourTokens)
+	}
+	return s
+}
+
+func TestContextWindow_BelowThreshold(t *testing.T) {
+	client := &fakeLLM{content: "summary"}
+	w := newTestWindow(client)
+
+	// 4 turns x 4 tokens = 16, one token-sized turn short of the trigger
+	s := newSessionWithTurns(4)
+	s.AddTurn(session.RoleUser, "abc") // 1 token -> 17
+	if s.TokenCount() >= w.TriggerTokens() {
+		t.Fatalf("Test setup: expected %d tokens below trigger %d", s.TokenCount(), w.TriggerTokens())
+	}
+
+	compacted, err := w.Compact(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if compacted {
+		t.Error("Session below the threshold should not be compacted")
+	}
+	if len(client.requests) != 0 {
+		t.Error("LLM should not be called below the threshold")
+	}
+	if len(s.Turns) != 5 {
+		t.Errorf("Expected history to be kept, got %d turns", len(s.Turns))
+	}
+}
+
+func TestContextWindow_AtThreshold(t *testing.T) {
+	client := &fakeLLM{content: "  Caller wants to reschedule.  "}
+	w := newTestWindow(client)
+
+	// 5 turns x 4 tokens = 20, exactly the trigger
+	s := newSessionWithTurns(5)
+	recent := s.Turns[3:]
+	if s.TokenCount() != w.TriggerTokens() {
+		t.Fatalf("Test setup: expected %d tokens, got %d", w.TriggerTokens(), s.TokenCount())
+	}
+
+	compacted, err := w.Compact(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if !compacted {
+		t.Fatal("Session at the threshold should be compacted")
+	}
+
+	if s.Summary != "Caller wants to reschedule." {
+		t.Errorf("Unexpected summary %q", s.Summary)
+	}
+	if len(s.Turns) != 2 {
+		t.Fatalf("Expected 2 recent turns kept verbatim, got %d", len(s.Turns))
+	}
+	for i, turn := range s.Turns {
+		if turn != recent[i] {
+			t.Errorf("Recent turn %d was modified", i)
+		}
+	}
+	if s.SummarizedTurns != 3 {
+		t.Errorf("Expected 3 summarized turns, got %d", s.SummarizedTurns)
+	}
+	if s.TokenCount() >= w.TriggerTokens() {
+		t.Errorf("Expected tokens below trigger after compaction, got %d", s.TokenCount())
+	}
+
+	req := client.requests[0]
+	if req.Model != "gpt-4o-mini" {
+		t.Errorf("Expected summary model, got %s", req.Model)
+	}
+	if req.Messages[0].Content != "Summarize." {
+		t.Errorf("Expected configured summary prompt, got %q", req.Messages[0].Content)
+	}
+	if strings.Count(req.Messages[1].Content, fourTokens) != 3 {
+		t.Error("Only the older turns should be sent for summarization")
+	}
+}
+
+func TestContextWindow_MergesPreviousSummary(t *testing.T) {
+	client := &fakeLLM{content: "merged summary"}
+	w := newTestWindow(client)
+
+	s := newSessionWithTurns(5)
+	s.Compact("first summary", 5) // summary only, history untouched
+	s.AddTurn(session.RoleUser, fourTokens)
+
+	if _, err := w.Compact(context.Background(), s); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	if !strings.Contains(client.requests[0].Messages[1].Content, "first summary") {
+		t.Error("Previous summary should be merged into the new one")
+	}
+	if s.Summary != "merged summary" {
+		t.Errorf("Unexpected summary %q", s.Summary)
+	}
+}
+
+func TestContextWindow_OnlyRecentTurns(t *testing.T) {
+	client := &fakeLLM{content: "summary"}
+	w := NewContextWindow(client, ContextConfig{
+		MaxTokens:       8,
+		Threshold:       0.5,
+		KeepRecentTurns: 2,
+	}, zap.NewNop())
+
+	// Over the threshold, but nothing older than the kept turns
+	s := newSessionWithTurns(2)
+
+	compacted, err := w.Compact(context.Background(), s)
+	if err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if compacted {
+		t.Error("Recent turns should never be summarized")
+	}
+}
+
+func TestContextWindow_SummaryFailureKeepsHistory(t *testing.T) {
+	client := &fakeLLM{err: errors.New("rate limited")}
+	w := newTestWindow(client)
+
+	s := newSessionWithTurns(6)
+
+	if _, err := w.Compact(context.Background(), s); err == nil {
+		t.Fatal("Expected summarization error")
+	}
+	if len(s.Turns) != 6 || s.Summary != "" {
+		t.Error("History should be kept when summarization fails")
+	}
+}
+
+func TestContextWindow_Messages(t *testing.T) {
+	w := newTestWindow(&fakeLLM{})
+
+	s := newSessionWithTurns(3)
+	s.Compact("earlier summary", 1)
+
+	messages := w.Messages(s, "You are a receptionist.")
+	if len(messages) != 3 {
+		t.Fatalf("Expected system prompt, summary and 1 turn, got %d messages", len(messages))
+	}
+	if messages[0].Content != "You are a receptionist." {
+		t.Errorf("Expected agent prompt first, got %q", messages[0].Content)
+	}
+	if !strings.Contains(messages[1].Content, "earlier summary") {
+		t.Errorf("Expected summary message, got %q", messages[1].Content)
+	}
+	if messages[2].Role != string(session.RoleUser) {
+		t.Errorf("Expected last turn role user, got %s", messages[2].Role)
+	}
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/flow"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// advanceFlow applies the user message to the confirmation steps and returns
+// the instruction for the model. When retries run out on an agent with
+// handoff enabled, it transfers the caller and returns the decision instead.
+func (s *Service) advanceFlow(ctx context.Context, sess *session.Session, content string) (string, *routing.Decision) {
+	if sess.Flow == nil || !sess.Flow.Active() {
+		return "", nil
+	}
+
+	step := sess.Flow.CurrentStep().Name
+	outcome := sess.Flow.Advance(content)
+
+	s.logger.Debug("confirmation flow advanced",
+		zap.String("session_id", sess.ID.String()),
+		zap.String("step", step),
+		zap.String("outcome", string(outcome)),
+	)
+
+	if outcome == flow.OutcomeExhausted && sess.Agent.ConversationFlow.Handoff && s.router != nil {
+		d := s.router.Decision(sess, routing.TriggerFlow, "confirmation failed: "+step)
+		if err := s.transferCall(ctx, d); err == nil {
+			return "", d
+		}
+	}
+
+	return flowInstruction(outcome, sess.Flow, step), nil
+}
+
+// flowInstruction tells the model how to handle the current confirmation step.
+func flowInstruction(outcome flow.Outcome, state *flow.State, step string) string {
+	switch outcome {
+	case flow.OutcomeAsk:
+		return fmt.Sprintf("Before continuing, ask the caller to confirm their %s with a yes or no question.", state.CurrentStep().Name)
+	case flow.OutcomeReprompt:
+		return fmt.Sprintf("The caller's answer about their %s was unclear. Ask again for a clear yes or no confirmation.", step)
+	case flow.OutcomeCorrect:
+		return fmt.Sprintf("The caller said their %s is not correct. Ask for the correct %s.", step, step)
+	case flow.OutcomeCompleted:
+		return "The caller confirmed every detail. Thank them and continue."
+	case flow.OutcomeExhausted:
+		return fmt.Sprintf("The caller could not confirm their %s. Apologize and continue helping without it.", step)
+	default:
+		return ""
+	}
+}
+
+func flowStatus(sess *session.Session) *flow.Status {
+	if sess.Flow == nil {
+		return nil
+	}
+	status := sess.Flow.Status()
+	return &status
+}
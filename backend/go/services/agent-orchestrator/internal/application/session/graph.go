@@ -0,0 +1,57 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	graphservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// SetGraph runs the turns of agents with a graph through its nodes.
+func (s *Service) SetGraph(executor *graphservice.Executor) {
+	s.graph = executor
+}
+
+// agentGraph returns the graph of the session agent, if it has one and
+// graphs are enabled.
+func (s *Service) agentGraph(sess *session.Session) *graph.Graph {
+	if s.graph == nil || sess.Agent == nil {
+		return nil
+	}
+	return sess.Agent.Graph
+}
+
+// runGraph answers a user message through the agent graph, charging every
+// model call made on the way.
+func (s *Service) runGraph(ctx context.Context, sess *session.Session, g *graph.Graph, content, model, systemPrompt string, messages func(string) []llm.Message) (string, error) {
+	result, err := s.graph.Run(ctx, graphservice.Request{
+		Session:      sess,
+		Graph:        g,
+		Message:      content,
+		Model:        model,
+		SystemPrompt: systemPrompt,
+		Messages:     messages,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate reply: %w", err)
+	}
+
+	if s.charger != nil {
+		for _, completion := range result.Completions {
+			s.charger.Charge(ctx, sess, s.llmClient.Provider(), completion)
+		}
+	}
+
+	s.logger.Debug("turn answered by agent graph",
+		zap.String("session_id", sess.ID.String()),
+		zap.Strings("path", result.Path),
+		zap.Bool("loop_guard", result.LoopGuard),
+	)
+
+	return result.Content, nil
+}
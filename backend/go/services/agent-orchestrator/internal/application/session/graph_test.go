@@ -0,0 +1,44 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	graphservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+)
+
+func TestService_AnswersThroughGraph(t *testing.T) {
+	client := &fakeLLM{content: "Your invoice is on its way."}
+	repo := newMemoryRepo()
+	window := NewContextWindow(client, ContextConfig{MaxTokens: 6000, Threshold: 0.8, KeepRecentTurns: 6}, zap.NewNop())
+	service := NewService(repo, client, window, Config{Model: "test-model", SystemPrompt: "You are the receptionist."}, zap.NewNop())
+	service.SetGraph(graphservice.NewExecutor(client, nil, graphservice.Config{}, zap.NewNop()))
+	service.SetAgentConfigs(fixedAgents{config: &agent.Config{Graph: &graph.Graph{
+		Entry: "router",
+		Nodes: []graph.Node{
+			{ID: "router", Edges: []graph.Edge{{To: "billing", Keywords: []string{"invoice"}}}},
+			{ID: "billing", SystemPrompt: "You handle invoices."},
+		},
+	}}})
+	ctx := context.Background()
+
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	reply, err := service.SendMessage(ctx, sess.ID, "Where is my invoice?")
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if reply.Content != "Your invoice is on its way." {
+		t.Errorf("Unexpected reply: %q", reply.Content)
+	}
+	if len(client.requests) != 1 || client.requests[0].Messages[0].Content != "You handle invoices." {
+		t.Errorf("Expected the billing specialist to answer, got %+v", client.requests)
+	}
+	if turns := repo.sessions[sess.ID].Turns; len(turns) != 2 || turns[1].Content != reply.Content {
+		t.Errorf("Expected the answer kept in the history, got %+v", turns)
+	}
+}
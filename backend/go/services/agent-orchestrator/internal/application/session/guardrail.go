@@ -0,0 +1,65 @@
+package session
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/guardrail"
+	domainguardrail "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// SetGuard enables guardrail enforcement from the agent SafetyConfig.
+func (s *Service) SetGuard(guard *guardrail.Guard) {
+	s.guard = guard
+}
+
+// enforce records a violation and answers with the guard message instead of
+// a model reply. Refusals keep the session going; transfers and limits end it.
+func (s *Service) enforce(ctx context.Context, sess *session.Session, v *domainguardrail.Violation) (*Reply, error) {
+	s.guard.Record(ctx, v)
+
+	message := s.guard.Message(v)
+	sess.AddTurn(session.RoleAssistant, message)
+
+	var action *actions.Action
+	switch v.Action {
+	case domainguardrail.ActionTransfer:
+		action = actions.Transfer(actions.TransferParams{Reason: string(v.Kind)})
+		if s.router != nil {
+			// Blocked either way; a failed call transfer is only logged
+			d := s.router.Decision(sess, routing.TriggerGuardrail, string(v.Kind))
+			if err := s.transferCall(ctx, d); err != nil {
+				s.logger.Warn("guardrail transfer failed",
+					zap.String("session_id", sess.ID.String()),
+					zap.Error(err),
+				)
+			}
+			action = transferAction(d)
+		}
+		sess.EndWithReason(session.EndReasonTransfer)
+	case domainguardrail.ActionEnd:
+		sess.EndWithReason(endReason(v.Kind))
+		action = actions.EndCall(sess.EndReason)
+	}
+
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+
+	reply := s.reply(sess, message)
+	reply.Blocked = true
+	s.act(reply, action)
+	return reply, nil
+}
+
+func endReason(kind domainguardrail.Kind) string {
+	if kind == domainguardrail.KindInactivity {
+		return session.EndReasonInactivity
+	}
+	return session.EndReasonMaxTurns
+}
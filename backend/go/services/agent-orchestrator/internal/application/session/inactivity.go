@@ -0,0 +1,172 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// inactivityCloseTimeout bounds the work of closing an idle session.
+const inactivityCloseTimeout = 30 * time.Second
+
+// EndPublisher announces the conversations the service ends on its own.
+type EndPublisher interface {
+	PublishConversationEnded(ctx context.Context, s *session.Session) error
+}
+
+// inactivity holds a timer per active session of this replica. Each turn
+// rearms the timer of its session; an explicit end stops it.
+type inactivity struct {
+	publisher EndPublisher
+	timeout   func(s *session.Session) time.Duration
+
+	mu     sync.Mutex
+	timers map[uuid.UUID]*idleTimer
+}
+
+// idleTimer is the pending close of a session; its address tells a stale
+// timer from the one that replaced it.
+type idleTimer struct {
+	timer *time.Timer
+}
+
+// SetInactivityClose ends sessions that get no turn within the agent
+// InactivityTimeout, instead of waiting for the next message to find out,
+// and publishes their end.
+func (s *Service) SetInactivityClose(publisher EndPublisher) {
+	s.idle = &inactivity{
+		publisher: publisher,
+		timeout:   inactivityTimeout,
+		timers:    make(map[uuid.UUID]*idleTimer),
+	}
+}
+
+// StopInactivityTimers cancels the pending closes. Sessions left behind are
+// closed by the next message through the guard, or by another replica.
+func (s *Service) StopInactivityTimers(ctx context.Context) error {
+	if s.idle == nil {
+		return nil
+	}
+	s.idle.mu.Lock()
+	defer s.idle.mu.Unlock()
+	for id, t := range s.idle.timers {
+		t.timer.Stop()
+		delete(s.idle.timers, id)
+	}
+	return nil
+}
+
+func inactivityTimeout(s *session.Session) time.Duration {
+	if s.Agent == nil {
+		return 0
+	}
+	return time.Duration(s.Agent.Safety.InactivityTimeout) * time.Second
+}
+
+// touch rearms the inactivity timer of a session after a turn, or stops it
+// once the session ended.
+func (s *Service) touch(sess *session.Session) {
+	s.metrics.seen(sess)
+	if s.idle == nil {
+		return
+	}
+	if !sess.IsActive() {
+		s.idle.stop(sess.ID)
+		return
+	}
+	if timeout := s.idle.timeout(sess); timeout > 0 {
+		s.armIdle(sess.ID, timeout)
+	}
+}
+
+func (s *Service) armIdle(id uuid.UUID, after time.Duration) {
+	t := &idleTimer{}
+	s.idle.mu.Lock()
+	defer s.idle.mu.Unlock()
+	if previous, ok := s.idle.timers[id]; ok {
+		previous.timer.Stop()
+	}
+	t.timer = time.AfterFunc(after, func() { s.closeIdle(id, t) })
+	s.idle.timers[id] = t
+}
+
+// stop cancels the inactivity timer of a session.
+func (i *inactivity) stop(id uuid.UUID) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if t, ok := i.timers[id]; ok {
+		t.timer.Stop()
+		delete(i.timers, id)
+	}
+}
+
+// current reports whether t is the pending timer of a session.
+func (i *inactivity) current(id uuid.UUID, t *idleTimer) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.timers[id] == t
+}
+
+// forget drops the timer of a session unless a newer one replaced it.
+func (i *inactivity) forget(id uuid.UUID, t *idleTimer) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.timers[id] == t {
+		delete(i.timers, id)
+	}
+}
+
+// closeIdle ends a session whose timer expired. The session may have had a
+// turn on another replica meanwhile, so the stored one decides: the timer is
+// rearmed for what is left of its window if it is still in use.
+func (s *Service) closeIdle(id uuid.UUID, t *idleTimer) {
+	if !s.idle.current(id, t) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), inactivityCloseTimeout)
+	defer cancel()
+
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil || !sess.IsActive() {
+		s.idle.forget(id, t)
+		return
+	}
+	timeout := s.idle.timeout(sess)
+	if remaining := timeout - time.Since(sess.UpdatedAt); timeout > 0 && remaining > 0 {
+		s.armIdle(id, remaining)
+		return
+	}
+	s.idle.forget(id, t)
+
+	sess.EndWithReason(session.EndReasonInactivity)
+	if err := s.repo.Save(ctx, sess); err != nil {
+		s.logger.Warn("failed to close idle session",
+			zap.String("session_id", id.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	s.metrics.seen(sess)
+	if s.tracker != nil {
+		s.tracker.Ended(ctx, sess)
+	}
+	if s.summarizer != nil {
+		s.summarizer.Ended(ctx, sess)
+	}
+	if err := s.idle.publisher.PublishConversationEnded(ctx, sess); err != nil {
+		s.logger.Warn("failed to publish conversation end",
+			zap.String("session_id", id.String()),
+			zap.Error(err),
+		)
+	}
+
+	s.logger.Info("idle session closed",
+		zap.String("session_id", id.String()),
+		zap.Duration("timeout", timeout),
+	)
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// lockedRepo lets the inactivity timers share the memory repo with the test.
+type lockedRepo struct {
+	mu   sync.Mutex
+	repo *memoryRepo
+}
+
+func (r *lockedRepo) Save(ctx context.Context, s *session.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.repo.Save(ctx, s)
+}
+
+func (r *lockedRepo) Get(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.repo.Get(ctx, id)
+}
+
+func (r *lockedRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.repo.Delete(ctx, id)
+}
+
+type endRecorder struct {
+	ended chan *session.Session
+}
+
+func (r *endRecorder) PublishConversationEnded(ctx context.Context, s *session.Session) error {
+	r.ended <- s
+	return nil
+}
+
+// newIdleService closes sessions after timeout instead of the agent seconds.
+func newIdleService(timeout time.Duration) (*Service, *endRecorder) {
+	client := &fakeLLM{content: "Sure."}
+	repo := &lockedRepo{repo: newMemoryRepo()}
+	window := NewContextWindow(client, ContextConfig{MaxTokens: 6000, Threshold: 0.8, KeepRecentTurns: 6}, zap.NewNop())
+	service := NewService(repo, client, window, Config{Model: "test-model"}, zap.NewNop())
+	service.SetAgentConfigs(fixedAgents{config: &agent.Config{Safety: agent.SafetyConfig{InactivityTimeout: 1}}})
+
+	recorder := &endRecorder{ended: make(chan *session.Session, 1)}
+	service.SetInactivityClose(recorder)
+	service.idle.timeout = func(s *session.Session) time.Duration {
+		return time.Duration(s.Agent.Safety.InactivityTimeout) * timeout
+	}
+	return service, recorder
+}
+
+func TestInactivity_ClosesIdleSession(t *testing.T) {
+	service, recorder := newIdleService(20 * time.Millisecond)
+	tracker := &recordingTracker{}
+	service.SetTracker(tracker)
+
+	sess, _ := service.CreateSession(context.Background(), CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+
+	select {
+	case ended := <-recorder.ended:
+		if ended.ID != sess.ID || ended.IsActive() || ended.EndReason != session.EndReasonInactivity {
+			t.Errorf("Expected the session ended for inactivity, got %s (%s)", ended.Status, ended.EndReason)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the idle session to be closed")
+	}
+
+	if _, err := service.SendMessage(context.Background(), sess.ID, "still there?"); err != ErrSessionEnded {
+		t.Errorf("Expected ErrSessionEnded after the close, got %v", err)
+	}
+	if tracker.ended == nil {
+		t.Error("Expected the tracker to see the session end")
+	}
+}
+
+func TestInactivity_TurnsResetTimer(t *testing.T) {
+	service, recorder := newIdleService(100 * time.Millisecond)
+	ctx := context.Background()
+
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+
+	// Four turns span twice the timeout, none of them idle for long
+	for i := 0; i < 4; i++ {
+		time.Sleep(50 * time.Millisecond)
+		if _, err := service.SendMessage(ctx, sess.ID, "hello"); err != nil {
+			t.Fatalf("Turn %d: SendMessage failed: %v", i, err)
+		}
+	}
+
+	select {
+	case ended := <-recorder.ended:
+		if ended.EndReason != session.EndReasonInactivity {
+			t.Errorf("Expected the inactivity end reason, got %q", ended.EndReason)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the session to be closed once idle")
+	}
+}
+
+func TestInactivity_ExplicitEndCancelsTimer(t *testing.T) {
+	service, recorder := newIdleService(20 * time.Millisecond)
+	ctx := context.Background()
+
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	if _, err := service.EndSession(ctx, sess.ID); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+
+	select {
+	case <-recorder.ended:
+		t.Error("Expected no inactivity close after an explicit end")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if len(service.idle.timers) != 0 {
+		t.Errorf("Expected no pending timers, got %d", len(service.idle.timers))
+	}
+}
+
+func TestInactivity_NoTimeout(t *testing.T) {
+	service, _ := newIdleService(20 * time.Millisecond)
+	service.SetAgentConfigs(fixedAgents{config: &agent.Config{}})
+
+	if _, err := service.CreateSession(context.Background(), CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"}); err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if len(service.idle.timers) != 0 {
+		t.Errorf("Expected no timer without an inactivity timeout, got %d", len(service.idle.timers))
+	}
+}
@@ -0,0 +1,303 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/serphona/backend/go/libs/platform-observability/slo"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	graphservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// Label bounds: tenants and models past them are reported as OtherLabel, so a
+// flood of new values can't grow the series without limit.
+const (
+	MaxTenantLabels = 500
+	MaxModelLabels  = 20
+	OtherLabel      = "other"
+)
+
+// activeStaleAfter drops conversations without activity from the active
+// gauge, for sessions that expire without being ended.
+const activeStaleAfter = 30 * time.Minute
+
+// Turn error types.
+const (
+	ErrorTypeRejected  = "rejected"  // admission turned the turn away
+	ErrorTypeCancelled = "cancelled" // cancelled or the caller hung up
+	ErrorTypeTimeout   = "timeout"
+	ErrorTypeLLM       = "llm"
+	ErrorTypeOther     = "other"
+)
+
+// Metrics reports the latency, tokens and errors of turns and the
+// conversations active, by tenant and model.
+type Metrics struct {
+	turnDuration  *prometheus.HistogramVec
+	stageDuration *prometheus.HistogramVec
+	turnTokens    *prometheus.HistogramVec
+	errors        *prometheus.CounterVec
+	active        *activeConversations
+	slis          *slo.Recorder
+
+	tenants *labelSet
+	models  *labelSet
+}
+
+// NewMetrics registers the conversation metrics with reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	m := &Metrics{
+		turnDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "agent_orchestrator",
+			Subsystem: "turn",
+			Name:      "duration_seconds",
+			Help:      "Time to answer a user message, by tenant and model",
+			Buckets:   []float64{0.25, 0.5, 1, 2, 3, 5, 8, 13, 20, 30},
+		}, []string{"tenant_id", "model"}),
+		stageDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "agent_orchestrator",
+			Subsystem: "turn",
+			Name:      "stage_duration_seconds",
+			Help:      "Time a turn spent in LLM calls and in tool calls, by tenant, model and stage",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 3, 5, 8, 13, 20},
+		}, []string{"tenant_id", "model", "stage"}),
+		turnTokens: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "agent_orchestrator",
+			Subsystem: "turn",
+			Name:      "tokens",
+			Help:      "LLM tokens used to answer a user message, by tenant and model",
+			Buckets:   prometheus.ExponentialBuckets(100, 2, 10),
+		}, []string{"tenant_id", "model"}),
+		errors: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "agent_orchestrator",
+			Subsystem: "turn",
+			Name:      "errors_total",
+			Help:      "Turns that failed, by tenant and error type",
+		}, []string{"tenant_id", "type"}),
+		slis:    slo.NewRecorder(reg, "agent-orchestrator"),
+		tenants: newLabelSet(MaxTenantLabels),
+		models:  newLabelSet(MaxModelLabels),
+	}
+	m.active = newActiveConversations(m.tenants)
+	reg.MustRegister(m.active)
+	return m
+}
+
+// turn records a turn answered in elapsed.
+func (m *Metrics) turn(sess *session.Session, model string, elapsed time.Duration, clock *turnClock) {
+	if m == nil {
+		return
+	}
+	tenant, model := m.tenants.label(sess.TenantID.String()), m.models.label(model)
+	m.turnDuration.WithLabelValues(tenant, model).Observe(elapsed.Seconds())
+	m.slis.ObserveLatency(slo.TurnLatency, elapsed)
+
+	llmTime, toolTime, tokens := clock.totals()
+	m.stageDuration.WithLabelValues(tenant, model, "llm").Observe(llmTime.Seconds())
+	if toolTime > 0 {
+		m.stageDuration.WithLabelValues(tenant, model, "tool").Observe(toolTime.Seconds())
+	}
+	m.turnTokens.WithLabelValues(tenant, model).Observe(float64(tokens))
+}
+
+// failed counts a turn that failed with an error of the given type. Turns
+// cancelled or hung up on don't count against the turn SLI.
+func (m *Metrics) failed(sess *session.Session, errorType string) {
+	if m == nil {
+		return
+	}
+	m.errors.WithLabelValues(m.tenants.label(sess.TenantID.String()), errorType).Inc()
+	if errorType != ErrorTypeCancelled {
+		m.slis.Observe(slo.TurnLatency, false)
+	}
+}
+
+// seen updates the active gauge with the state of a session.
+func (m *Metrics) seen(sess *session.Session) {
+	if m != nil {
+		m.active.seen(sess)
+	}
+}
+
+// errorType classifies the failure of a turn.
+func errorType(err error, clock *turnClock) string {
+	switch {
+	case errors.Is(err, ErrTurnCancelled), errors.Is(err, context.Canceled):
+		return ErrorTypeCancelled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, graphservice.ErrNodeTimeout):
+		return ErrorTypeTimeout
+	case clock.llmFailed():
+		return ErrorTypeLLM
+	}
+	return ErrorTypeOther
+}
+
+// labelSet bounds the values of a label to the first max seen.
+type labelSet struct {
+	mu   sync.Mutex
+	max  int
+	seen map[string]struct{}
+}
+
+func newLabelSet(max int) *labelSet {
+	return &labelSet{max: max, seen: make(map[string]struct{})}
+}
+
+// label returns the label value for value.
+func (l *labelSet) label(value string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[value]; ok {
+		return value
+	}
+	if len(l.seen) >= l.max {
+		return OtherLabel
+	}
+	l.seen[value] = struct{}{}
+	return value
+}
+
+// activeConversations counts the active sessions of each tenant when
+// scraped. Sessions leave it when they end or go quiet.
+type activeConversations struct {
+	desc    *prometheus.Desc
+	tenants *labelSet
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]activeSession
+}
+
+type activeSession struct {
+	tenant   string
+	lastSeen time.Time
+}
+
+func newActiveConversations(tenants *labelSet) *activeConversations {
+	return &activeConversations{
+		desc: prometheus.NewDesc(
+			"agent_orchestrator_conversations_active",
+			"Conversations in progress, by tenant",
+			[]string{"tenant_id"}, nil,
+		),
+		tenants:  tenants,
+		sessions: make(map[uuid.UUID]activeSession),
+	}
+}
+
+func (a *activeConversations) seen(sess *session.Session) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !sess.IsActive() {
+		delete(a.sessions, sess.ID)
+		return
+	}
+	a.sessions[sess.ID] = activeSession{tenant: a.tenants.label(sess.TenantID.String()), lastSeen: time.Now()}
+}
+
+// Describe implements prometheus.Collector.
+func (a *activeConversations) Describe(ch chan<- *prometheus.Desc) {
+	ch <- a.desc
+}
+
+// Collect implements prometheus.Collector.
+func (a *activeConversations) Collect(ch chan<- prometheus.Metric) {
+	a.mu.Lock()
+	counts := make(map[string]int)
+	for id, s := range a.sessions {
+		if time.Since(s.lastSeen) > activeStaleAfter {
+			delete(a.sessions, id)
+			continue
+		}
+		counts[s.tenant]++
+	}
+	a.mu.Unlock()
+
+	for tenant, n := range counts {
+		ch <- prometheus.MustNewConstMetric(a.desc, prometheus.GaugeValue, float64(n), tenant)
+	}
+}
+
+// turnClock accumulates the time a turn spends in LLM and tool calls, and
+// the tokens it uses. It travels with the turn context.
+type turnClock struct {
+	mu        sync.Mutex
+	llm       time.Duration
+	tool      time.Duration
+	tokens    int
+	llmErrors int
+}
+
+type turnClockKey struct{}
+
+func withTurnClock(ctx context.Context) (context.Context, *turnClock) {
+	clock := &turnClock{}
+	return context.WithValue(ctx, turnClockKey{}, clock), clock
+}
+
+func turnClockFrom(ctx context.Context) *turnClock {
+	clock, _ := ctx.Value(turnClockKey{}).(*turnClock)
+	return clock
+}
+
+func (c *turnClock) addLLM(elapsed time.Duration, completion *llm.Completion, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.llm += elapsed
+	if err != nil {
+		c.llmErrors++
+		return
+	}
+	if completion != nil {
+		c.tokens += completion.Usage.Total()
+	}
+}
+
+func (c *turnClock) addTool(elapsed time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tool += elapsed
+}
+
+func (c *turnClock) totals() (llmTime, toolTime time.Duration, tokens int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.llm, c.tool, c.tokens
+}
+
+func (c *turnClock) llmFailed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.llmErrors > 0
+}
+
+// InstrumentLLM times the calls of client made during turns, for the turn
+// metrics. Wrap the client shared by the session service, its context
+// window and its guardrails so every model call of a turn is counted.
+func InstrumentLLM(client llm.Client) llm.Client {
+	return &timedClient{next: client}
+}
+
+type timedClient struct {
+	next llm.Client
+}
+
+func (t *timedClient) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	start := time.Now()
+	completion, err := t.next.Complete(ctx, req)
+	if clock := turnClockFrom(ctx); clock != nil {
+		clock.addLLM(time.Since(start), completion, err)
+	}
+	return completion, err
+}
+
+func (t *timedClient) Provider() string {
+	return t.next.Provider()
+}
@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+)
+
+func newMeteredService(client llm.Client) (*Service, *prometheus.Registry) {
+	reg := prometheus.NewRegistry()
+	client = InstrumentLLM(client)
+	window := NewContextWindow(client, ContextConfig{MaxTokens: 6000, Threshold: 0.8, KeepRecentTurns: 6}, zap.NewNop())
+	service := NewService(newMemoryRepo(), client, window, Config{Model: "test-model"}, zap.NewNop())
+	service.SetMetrics(NewMetrics(reg))
+	return service, reg
+}
+
+// histogram returns the histogram of a metric family for a tenant.
+func histogram(t *testing.T, reg *prometheus.Registry, name string, tenantID uuid.UUID, labels map[string]string) *dto.Histogram {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			want := map[string]string{"tenant_id": tenantID.String()}
+			for k, v := range labels {
+				want[k] = v
+			}
+			for _, label := range m.GetLabel() {
+				if v, ok := want[label.GetName()]; ok && v != label.GetValue() {
+					continue metrics
+				}
+			}
+			return m.GetHistogram()
+		}
+	}
+	return nil
+}
+
+func TestMetrics_CompletedTurnRecordsLatency(t *testing.T) {
+	client := &fakeLLM{content: "Hello!", usage: llm.Usage{PromptTokens: 40, CompletionTokens: 10}}
+	service, reg := newMeteredService(client)
+	ctx := context.Background()
+
+	tenantID := uuid.New()
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: tenantID, AgentID: "agent-receptionist"})
+	if _, err := service.SendMessage(ctx, sess.ID, "Hi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	latency := histogram(t, reg, "agent_orchestrator_turn_duration_seconds", tenantID, map[string]string{"model": "test-model"})
+	if latency == nil || latency.GetSampleCount() != 1 {
+		t.Fatalf("Expected one turn latency observed, got %v", latency)
+	}
+	llmTime := histogram(t, reg, "agent_orchestrator_turn_stage_duration_seconds", tenantID, map[string]string{"stage": "llm"})
+	if llmTime == nil || llmTime.GetSampleCount() != 1 {
+		t.Errorf("Expected the LLM time of the turn observed, got %v", llmTime)
+	}
+	tokens := histogram(t, reg, "agent_orchestrator_turn_tokens", tenantID, nil)
+	if tokens == nil || tokens.GetSampleSum() != 50 {
+		t.Errorf("Expected 50 tokens for the turn, got %v", tokens)
+	}
+
+	if n := testutil.ToFloat64(service.metrics.active); n != 1 {
+		t.Errorf("Expected 1 active conversation, got %v", n)
+	}
+	service.EndSession(ctx, sess.ID)
+	if n := testutil.CollectAndCount(service.metrics.active); n != 0 {
+		t.Errorf("Expected no active conversation after the end, got %d", n)
+	}
+}
+
+func TestMetrics_FailedTurnCountsError(t *testing.T) {
+	service, reg := newMeteredService(&fakeLLM{err: errors.New("upstream 500")})
+	ctx := context.Background()
+
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	if _, err := service.SendMessage(ctx, sess.ID, "Hi"); err == nil {
+		t.Fatal("Expected the turn to fail")
+	}
+
+	errs := service.metrics.errors.WithLabelValues(sess.TenantID.String(), ErrorTypeLLM)
+	if n := testutil.ToFloat64(errs); n != 1 {
+		t.Errorf("Expected 1 llm error, got %v", n)
+	}
+	if n := testutil.CollectAndCount(reg, "agent_orchestrator_turn_duration_seconds"); n != 0 {
+		t.Errorf("Expected no latency for a failed turn, got %d series", n)
+	}
+}
+
+func TestLabelSet_Bounded(t *testing.T) {
+	labels := newLabelSet(2)
+	labels.label("a")
+	labels.label("b")
+	if got := labels.label("c"); got != OtherLabel {
+		t.Errorf("Expected %q past the bound, got %q", OtherLabel, got)
+	}
+	if got := labels.label("a"); got != "a" {
+		t.Errorf("Expected a known value kept, got %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/moderation"
+	domainmoderation "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/moderation"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// SetModerator screens user messages, and optionally replies, before they
+// reach the model or the caller.
+func (s *Service) SetModerator(moderator *moderation.Moderator) {
+	s.moderator = moderator
+}
+
+// blocks reports whether a flag stops the message; sanitized messages go on.
+func blocks(f *domainmoderation.Flag) bool {
+	return f != nil && f.Action != domainmoderation.ActionSanitize
+}
+
+// blockFlagged answers a flagged message with the moderation message instead
+// of a model reply. Refusals keep the session going; escalations transfer the
+// caller to a human and end it.
+func (s *Service) blockFlagged(ctx context.Context, sess *session.Session, f *domainmoderation.Flag) (*Reply, error) {
+	message := s.moderator.Message(f)
+	sess.AddTurn(session.RoleAssistant, message)
+
+	var action *actions.Action
+	if f.Action == domainmoderation.ActionEscalate {
+		action = actions.Transfer(actions.TransferParams{Reason: string(routing.TriggerModeration)})
+		if s.router != nil {
+			// Blocked either way; a failed call transfer is only logged
+			d := s.router.Decision(sess, routing.TriggerModeration, string(routing.TriggerModeration))
+			if err := s.transferCall(ctx, d); err != nil {
+				s.logger.Warn("moderation transfer failed",
+					zap.String("session_id", sess.ID.String()),
+					zap.Error(err),
+				)
+			}
+			action = transferAction(d)
+		}
+		sess.EndWithReason(session.EndReasonTransfer)
+	}
+
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+
+	reply := s.reply(sess, message)
+	reply.Blocked = true
+	s.act(reply, action)
+	return reply, nil
+}
@@ -0,0 +1,565 @@
+// Package session provides conversation session use cases.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	graphservice "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/graph"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/guardrail"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/moderation"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/flow"
+	domainmoderation "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/moderation"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// ErrSessionEnded is returned when a message is sent to an ended session.
+var ErrSessionEnded = errors.New("session has ended")
+
+// DefaultSpendCapMessage closes a conversation that reached its spend cap.
+const DefaultSpendCapMessage = "I'm sorry, I can't continue this conversation right now. Thank you for contacting us."
+
+// DefaultTransferMessage is spoken before an automatic transfer.
+const DefaultTransferMessage = "Let me transfer you to someone who can help you with that."
+
+// Repository persists sessions.
+type Repository interface {
+	Save(ctx context.Context, s *session.Session) error
+	Get(ctx context.Context, id uuid.UUID) (*session.Session, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AgentConfigProvider provides the agent configuration of a tenant.
+type AgentConfigProvider interface {
+	GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*agent.Config, error)
+}
+
+// Charger records the spend of a completion on a session.
+type Charger interface {
+	Charge(ctx context.Context, s *session.Session, provider string, completion *llm.Completion) *billing.Charge
+}
+
+// Tracker observes conversations live as they happen.
+type Tracker interface {
+	Started(ctx context.Context, s *session.Session)
+	Exchanged(ctx context.Context, s *session.Session, message string, reply *Reply)
+	Ended(ctx context.Context, s *session.Session)
+}
+
+// Summarizer summarizes conversations once they end.
+type Summarizer interface {
+	Ended(ctx context.Context, s *session.Session)
+}
+
+// Admission shares LLM throughput between tenants.
+type Admission interface {
+	Acquire(ctx context.Context, tenantID uuid.UUID) (release func(), err error)
+}
+
+// Config represents the model settings used for agent replies.
+type Config struct {
+	Model        string
+	MaxTokens    int // completion tokens per reply
+	Temperature  float64
+	SystemPrompt string
+
+	SpendCap        float64 // default per-conversation cap in USD, 0 means no cap
+	SpendCapMessage string  // closing message when the cap is reached
+	TransferMessage string  // spoken before an automatic transfer
+}
+
+// Reply is the agent response to a user message.
+type Reply struct {
+	SessionID uuid.UUID       `json:"session_id"`
+	Content   string          `json:"response"`
+	Usage     session.Usage   `json:"usage"`
+	Spend     session.Spend   `json:"spend"`
+	Blocked   bool            `json:"blocked,omitempty"` // a guardrail replaced the reply
+	Flow      *flow.Status    `json:"flow,omitempty"`    // confirmation step progress
+	Action    *actions.Action `json:"action,omitempty"`
+	Ended     bool            `json:"ended,omitempty"`
+	EndReason string          `json:"end_reason,omitempty"`
+}
+
+// CostReport is the token and cost accounting of a conversation.
+type CostReport struct {
+	ConversationID   uuid.UUID      `json:"conversation_id"`
+	TenantID         uuid.UUID      `json:"tenant_id"`
+	Status           session.Status `json:"status"`
+	PromptTokens     int            `json:"prompt_tokens"`
+	CompletionTokens int            `json:"completion_tokens"`
+	TotalTokens      int            `json:"total_tokens"`
+	Cost             float64        `json:"cost"`
+	Currency         string         `json:"currency"`
+	SpendCap         float64        `json:"spend_cap,omitempty"`
+	EndReason        string         `json:"end_reason,omitempty"`
+}
+
+// Service manages sessions and runs user messages through the LLM.
+type Service struct {
+	repo       Repository
+	llmClient  llm.Client
+	window     *ContextWindow
+	charger    Charger
+	guard      *guardrail.Guard
+	moderator  *moderation.Moderator
+	router     *routing.Router
+	agents     AgentConfigProvider
+	tracker    Tracker
+	summarizer Summarizer
+	lastCalls  LastCallSummaries
+	admission  Admission
+	idle       *inactivity
+	inflight   inflight
+	graph      *graphservice.Executor
+	metrics    *Metrics
+	config     Config
+	logger     *zap.Logger
+
+	cancelPublisher CancelPublisher
+}
+
+// NewService creates a new session service.
+func NewService(
+	repo Repository,
+	llmClient llm.Client,
+	window *ContextWindow,
+	config Config,
+	logger *zap.Logger,
+) *Service {
+	if config.SpendCapMessage == "" {
+		config.SpendCapMessage = DefaultSpendCapMessage
+	}
+	if config.TransferMessage == "" {
+		config.TransferMessage = DefaultTransferMessage
+	}
+	return &Service{
+		repo:      repo,
+		llmClient: llmClient,
+		window:    window,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// SetCharger enables cost accounting of replies and summaries.
+func (s *Service) SetCharger(charger Charger) {
+	s.charger = charger
+	s.window.charger = charger
+}
+
+// SetAgentConfigs loads the tenant agent configuration into new sessions.
+func (s *Service) SetAgentConfigs(agents AgentConfigProvider) {
+	s.agents = agents
+}
+
+// SetMetrics reports turn latency, tokens, errors and active conversations.
+func (s *Service) SetMetrics(metrics *Metrics) {
+	s.metrics = metrics
+}
+
+// SetTracker streams session activity to a live tracker.
+func (s *Service) SetTracker(tracker Tracker) {
+	s.tracker = tracker
+}
+
+// SetSummarizer summarizes sessions once they end.
+func (s *Service) SetSummarizer(summarizer Summarizer) {
+	s.summarizer = summarizer
+}
+
+// SetAdmission limits the replies each tenant generates at once.
+func (s *Service) SetAdmission(admission Admission) {
+	s.admission = admission
+}
+
+// CreateParams holds the inputs of a new session.
+type CreateParams struct {
+	TenantID uuid.UUID
+	AgentID  string
+	CallID   uuid.UUID // voice call carrying the session, if any
+	SpendCap float64   // USD, zero uses the configured default
+
+	// CustomerID identifies a returning customer for sticky variant
+	// assignment, if known
+	CustomerID string
+
+	// Variables are the conversation context the system prompt can
+	// reference, e.g. the caller number and the tenant name
+	Variables map[string]string
+}
+
+// CreateSession starts a new session for a tenant agent.
+func (s *Service) CreateSession(ctx context.Context, params CreateParams) (*session.Session, error) {
+	tenantID, agentID := params.TenantID, params.AgentID
+
+	sess := session.New(tenantID, agentID)
+	sess.CallID = params.CallID
+	sess.CustomerID = params.CustomerID
+	sess.SetVariables(params.Variables)
+	s.recallLastCall(ctx, sess)
+	sess.SpendCap = s.config.SpendCap
+	if params.SpendCap > 0 {
+		sess.SpendCap = params.SpendCap
+	}
+	if s.agents != nil {
+		config, err := s.agents.GetAgentConfig(ctx, tenantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get agent config: %w", err)
+		}
+		sess.Agent = config
+		sess.AssignVariant()
+		if steps := config.ConversationFlow.ConfirmationSteps; len(steps) > 0 {
+			sess.Flow = flow.New(steps, config.ConversationFlow.MaxRetries)
+		}
+	}
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+	if s.tracker != nil {
+		s.tracker.Started(ctx, sess)
+	}
+	s.touch(sess)
+
+	s.logger.Info("session created",
+		zap.String("session_id", sess.ID.String()),
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("agent_id", agentID),
+		zap.String("variant", sess.Variant),
+	)
+
+	return sess, nil
+}
+
+// GetSession retrieves a session.
+func (s *Service) GetSession(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// ResumeSession loads an active session from the shared store, e.g. after a
+// restart or when another replica served it until now, so that this replica
+// continues it with its full context.
+func (s *Service) ResumeSession(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.IsActive() {
+		return nil, ErrSessionEnded
+	}
+	s.touch(sess)
+
+	s.logger.Info("session resumed",
+		zap.String("session_id", id.String()),
+		zap.Int64("version", sess.Version),
+		zap.Int("turns", len(sess.Turns)+sess.SummarizedTurns),
+	)
+
+	return sess, nil
+}
+
+// EndSession ends a session and removes its context.
+func (s *Service) EndSession(ctx context.Context, id uuid.UUID) (*session.Session, error) {
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sess.End()
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	s.touch(sess)
+	if s.tracker != nil {
+		s.tracker.Ended(ctx, sess)
+	}
+	if s.summarizer != nil {
+		s.summarizer.Ended(ctx, sess)
+	}
+
+	s.logger.Info("session ended",
+		zap.String("session_id", id.String()),
+		zap.Int("turns", len(sess.Turns)+sess.SummarizedTurns),
+	)
+
+	return sess, nil
+}
+
+// SendMessage adds a user message to the session and returns the agent reply.
+// Older turns are summarized first when the session nears its context budget.
+// A session that reaches its spend cap is ended with a closing message, and
+// messages that break a guardrail are refused or transferred.
+func (s *Service) SendMessage(ctx context.Context, id uuid.UUID, content string) (*Reply, error) {
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.IsActive() {
+		return nil, ErrSessionEnded
+	}
+	if s.admission != nil {
+		release, err := s.admission.Acquire(ctx, sess.TenantID)
+		if err != nil {
+			s.metrics.failed(sess, ErrorTypeRejected)
+			return nil, err
+		}
+		defer release()
+	}
+
+	turnCtx, done := s.startTurn(ctx, sess.ID)
+	defer done()
+	turnCtx, clock := withTurnClock(turnCtx)
+	start := time.Now()
+
+	reply, err := s.respond(turnCtx, sess, content)
+	if err != nil {
+		if reason, ok := cancelled(turnCtx); ok {
+			s.metrics.failed(sess, ErrorTypeCancelled)
+			return nil, s.turnCancelled(ctx, sess, reason)
+		}
+		s.metrics.failed(sess, errorType(err, clock))
+		return nil, err
+	}
+	model, _ := s.version(sess)
+	s.metrics.turn(sess, model, time.Since(start), clock)
+	s.touch(sess)
+	if s.tracker != nil {
+		s.tracker.Exchanged(ctx, sess, content, reply)
+	}
+	return reply, nil
+}
+
+// respond runs a user message of an active session through the guardrails,
+// the transfer routing and the LLM.
+func (s *Service) respond(ctx context.Context, sess *session.Session, content string) (*Reply, error) {
+	if s.guard != nil {
+		if v := s.guard.CheckSession(sess); v != nil {
+			return s.enforce(ctx, sess, v)
+		}
+	}
+
+	if s.moderator != nil {
+		var flag *domainmoderation.Flag
+		if content, flag = s.moderator.Check(ctx, sess, session.RoleUser, content); blocks(flag) {
+			sess.AddTurn(session.RoleUser, domainmoderation.Placeholder)
+			return s.blockFlagged(ctx, sess, flag)
+		}
+	}
+
+	sess.AddTurn(session.RoleUser, content)
+
+	if s.guard != nil {
+		if v := s.guard.CheckMessage(ctx, sess, session.RoleUser, content); v != nil {
+			return s.enforce(ctx, sess, v)
+		}
+	}
+
+	if s.router != nil {
+		if d := s.router.Detect(ctx, sess, content); d != nil {
+			if err := s.transferCall(ctx, d); err == nil {
+				return s.transfer(ctx, sess, d, s.config.TransferMessage)
+			}
+			// The agent keeps the caller when the transfer fails
+			s.logger.Warn("automatic transfer failed, continuing conversation",
+				zap.String("session_id", sess.ID.String()),
+				zap.String("reason", d.Reason),
+			)
+		}
+	}
+
+	instruction, handoff := s.advanceFlow(ctx, sess, content)
+	if handoff != nil {
+		return s.transfer(ctx, sess, handoff, s.config.TransferMessage)
+	}
+
+	// A failed summary keeps the full history; the reply can still fit
+	if _, err := s.window.Compact(ctx, sess); err != nil {
+		s.logger.Warn("failed to compact session context",
+			zap.String("session_id", sess.ID.String()),
+			zap.Error(err),
+		)
+	}
+
+	// The summary may have used up the remaining budget
+	if sess.SpendCapReached() {
+		return s.endForSpendCap(ctx, sess, "")
+	}
+
+	model, systemPrompt := s.version(sess)
+	systemPrompt = s.renderPrompt(sess, systemPrompt)
+
+	messages := func(systemPrompt string) []llm.Message {
+		messages := s.window.Messages(sess, systemPrompt)
+		if instruction != "" {
+			messages = append(messages, llm.Message{Role: string(session.RoleSystem), Content: instruction})
+		}
+		return messages
+	}
+
+	var answer string
+	if g := s.agentGraph(sess); g != nil {
+		var err error
+		if answer, err = s.runGraph(ctx, sess, g, content, model, systemPrompt, messages); err != nil {
+			return nil, err
+		}
+	} else {
+		completion, err := s.llmClient.Complete(ctx, llm.CompletionRequest{
+			Model:       model,
+			Messages:    messages(systemPrompt),
+			MaxTokens:   s.config.MaxTokens,
+			Temperature: s.config.Temperature,
+			TenantID:    sess.TenantID,
+			SessionID:   sess.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate reply: %w", err)
+		}
+
+		if s.charger != nil {
+			s.charger.Charge(ctx, sess, s.llmClient.Provider(), completion)
+		}
+		answer = completion.Content
+	}
+
+	if s.moderator != nil && s.moderator.ChecksOutput() {
+		var flag *domainmoderation.Flag
+		if answer, flag = s.moderator.Check(ctx, sess, session.RoleAssistant, answer); blocks(flag) {
+			return s.blockFlagged(ctx, sess, flag)
+		}
+	}
+
+	// A reply on a forbidden topic is never sent nor kept in the history
+	if s.guard != nil {
+		if v := s.guard.CheckMessage(ctx, sess, session.RoleAssistant, answer); v != nil {
+			return s.enforce(ctx, sess, v)
+		}
+	}
+
+	// A turn cancelled while the reply was generated keeps nothing of it
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sess.AddTurn(session.RoleAssistant, answer)
+
+	if sess.SpendCapReached() {
+		return s.endForSpendCap(ctx, sess, answer)
+	}
+
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+
+	return s.reply(sess, answer), nil
+}
+
+// version returns the model and system prompt that answer a session: the
+// ones of its agent variant, if any, else the configured ones.
+func (s *Service) version(sess *session.Session) (model, systemPrompt string) {
+	model, systemPrompt = s.config.Model, s.config.SystemPrompt
+	if v := sess.AgentVariant(); v != nil {
+		if v.Model != "" {
+			model = v.Model
+		}
+		if v.SystemPrompt != "" {
+			systemPrompt = v.SystemPrompt
+		}
+	}
+	return model, systemPrompt
+}
+
+// endForSpendCap closes the session after the last reply, if any. The ended
+// session is kept until it expires so its cost can still be queried.
+func (s *Service) endForSpendCap(ctx context.Context, sess *session.Session, lastReply string) (*Reply, error) {
+	sess.AddTurn(session.RoleAssistant, s.config.SpendCapMessage)
+	sess.EndWithReason(session.EndReasonSpendCap)
+
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("session ended at spend cap",
+		zap.String("session_id", sess.ID.String()),
+		zap.String("tenant_id", sess.TenantID.String()),
+		zap.Float64("cost", sess.Spend.Cost),
+		zap.Float64("spend_cap", sess.SpendCap),
+	)
+
+	content := s.config.SpendCapMessage
+	if lastReply != "" {
+		content = lastReply + "\n\n" + content
+	}
+	return s.reply(sess, content), nil
+}
+
+func (s *Service) reply(sess *session.Session, content string) *Reply {
+	return &Reply{
+		SessionID: sess.ID,
+		Content:   content,
+		Usage:     sess.Usage(s.window.MaxTokens()),
+		Spend:     sess.Spend,
+		Flow:      flowStatus(sess),
+		Ended:     !sess.IsActive(),
+		EndReason: sess.EndReason,
+	}
+}
+
+// act attaches a channel action to a reply. An action outside the platform
+// schema is dropped, so the channel is never asked for one it can't run.
+func (s *Service) act(reply *Reply, action *actions.Action) {
+	if action == nil {
+		return
+	}
+	if err := action.Validate(); err != nil {
+		s.logger.Error("dropping invalid channel action",
+			zap.String("session_id", reply.SessionID.String()),
+			zap.String("action", string(action.Type)),
+			zap.Error(err),
+		)
+		return
+	}
+	reply.Action = action
+}
+
+// GetUsage returns the current token usage of a session.
+func (s *Service) GetUsage(ctx context.Context, id uuid.UUID) (*session.Usage, error) {
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := sess.Usage(s.window.MaxTokens())
+	return &usage, nil
+}
+
+// GetCost returns the token and cost accounting of a conversation.
+func (s *Service) GetCost(ctx context.Context, id uuid.UUID) (*CostReport, error) {
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CostReport{
+		ConversationID:   sess.ID,
+		TenantID:         sess.TenantID,
+		Status:           sess.Status,
+		PromptTokens:     sess.Spend.PromptTokens,
+		CompletionTokens: sess.Spend.CompletionTokens,
+		TotalTokens:      sess.Spend.TotalTokens(),
+		Cost:             sess.Spend.Cost,
+		Currency:         billing.Currency,
+		SpendCap:         sess.SpendCap,
+		EndReason:        sess.EndReason,
+	}, nil
+}
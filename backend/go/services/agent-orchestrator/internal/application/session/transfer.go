@@ -0,0 +1,61 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/application/routing"
+	domainrouting "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/routing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+)
+
+// SetRouter enables automatic transfers from the agent RoutingConfig.
+func (s *Service) SetRouter(router *routing.Router) {
+	s.router = router
+}
+
+// transferCall asks the channel to transfer the call, counting the time as
+// tool time of the turn.
+func (s *Service) transferCall(ctx context.Context, d *domainrouting.Decision) error {
+	start := time.Now()
+	err := s.router.Transfer(ctx, d)
+	if clock := turnClockFrom(ctx); clock != nil {
+		clock.addTool(time.Since(start))
+	}
+	return err
+}
+
+// transfer ends the session handed to a human and tells the channel where.
+func (s *Service) transfer(ctx context.Context, sess *session.Session, d *domainrouting.Decision, message string) (*Reply, error) {
+	sess.AddTurn(session.RoleAssistant, message)
+	sess.EndWithReason(session.EndReasonTransfer)
+
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("session transferred",
+		zap.String("session_id", sess.ID.String()),
+		zap.String("trigger", string(d.Trigger)),
+		zap.String("target", d.Target),
+		zap.Bool("call_transferred", d.Transferred),
+	)
+
+	reply := s.reply(sess, message)
+	s.act(reply, transferAction(d))
+	return reply, nil
+}
+
+// transferAction is the channel instruction for a transfer decision.
+func transferAction(d *domainrouting.Decision) *actions.Action {
+	return actions.Transfer(actions.TransferParams{
+		TransferType: d.TransferType,
+		Target:       d.Target,
+		Reason:       d.Reason,
+		Initiated:    d.Transferred,
+	})
+}
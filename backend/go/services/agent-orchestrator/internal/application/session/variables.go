@@ -0,0 +1,92 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/summary"
+)
+
+// VariableLastCallSummary holds the summary of a returning customer's last
+// conversation.
+const VariableLastCallSummary = "last_call_summary"
+
+// LastCallSummaries finds the summary of a customer's last conversation; nil
+// when the customer has none.
+type LastCallSummaries interface {
+	LatestForCustomer(ctx context.Context, tenantID uuid.UUID, customerID string) (*summary.Summary, error)
+}
+
+// SetLastCallSummaries gives sessions of returning customers the summary of
+// their last conversation, as the last_call_summary variable.
+func (s *Service) SetLastCallSummaries(summaries LastCallSummaries) {
+	s.lastCalls = summaries
+}
+
+// SetVariables merges context variables into an active session, for its
+// next replies. An empty value removes the variable.
+func (s *Service) SetVariables(ctx context.Context, id uuid.UUID, vars map[string]string) (*session.Session, error) {
+	sess, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !sess.IsActive() {
+		return nil, ErrSessionEnded
+	}
+
+	sess.SetVariables(vars)
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// recallLastCall sets the last call summary of a returning customer, unless
+// the caller already provided one. The conversation goes on without it when
+// there is none or it can't be read.
+func (s *Service) recallLastCall(ctx context.Context, sess *session.Session) {
+	if s.lastCalls == nil || sess.CustomerID == "" || sess.Variables[VariableLastCallSummary] != "" {
+		return
+	}
+
+	last, err := s.lastCalls.LatestForCustomer(ctx, sess.TenantID, sess.CustomerID)
+	if err != nil {
+		s.logger.Warn("failed to get last call summary",
+			zap.String("session_id", sess.ID.String()),
+			zap.String("customer_id", sess.CustomerID),
+			zap.Error(err),
+		)
+		return
+	}
+	if last != nil {
+		sess.SetVariables(map[string]string{VariableLastCallSummary: last.Summary})
+	}
+}
+
+// renderPrompt fills the session variables into a system prompt written as a
+// text/template, e.g. "Você atende a {{.tenant_name}}". Variables the session
+// lacks render empty; a prompt that isn't a valid template is used as written.
+func (s *Service) renderPrompt(sess *session.Session, prompt string) string {
+	if !strings.Contains(prompt, "{{") {
+		return prompt
+	}
+
+	var rendered strings.Builder
+	tmpl, err := template.New("system_prompt").Option("missingkey=zero").Parse(prompt)
+	if err == nil {
+		err = tmpl.Execute(&rendered, sess.Variables)
+	}
+	if err != nil {
+		s.logger.Warn("failed to render system prompt, using it as written",
+			zap.String("session_id", sess.ID.String()),
+			zap.Error(err),
+		)
+		return prompt
+	}
+	return rendered.String()
+}
@@ -0,0 +1,130 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/summary"
+)
+
+// customerSummaries serves the last call summary of known customers.
+type customerSummaries struct {
+	byCustomer map[string]string
+	err        error
+}
+
+func (c *customerSummaries) LatestForCustomer(ctx context.Context, tenantID uuid.UUID, customerID string) (*summary.Summary, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	text, ok := c.byCustomer[customerID]
+	if !ok {
+		return nil, nil
+	}
+	return &summary.Summary{TenantID: tenantID, Summary: text}, nil
+}
+
+const contextPrompt = "Você atende pela {{.tenant_name}}. O cliente liga de {{.caller_number}}." +
+	"{{with .last_call_summary}} Última ligação: {{.}}{{end}}"
+
+func newPromptService(client *fakeLLM, prompt string) (*Service, *memoryRepo) {
+	repo := newMemoryRepo()
+	service := NewService(repo, client, newTestWindow(client), Config{Model: "test-model", SystemPrompt: prompt}, zap.NewNop())
+	return service, repo
+}
+
+// systemPrompt returns the system prompt of the last completion request.
+func systemPrompt(t *testing.T, client *fakeLLM) string {
+	t.Helper()
+	if len(client.requests) == 0 {
+		t.Fatal("Expected a completion request")
+	}
+	return client.requests[len(client.requests)-1].Messages[0].Content
+}
+
+func TestService_PromptRendersContextVariables(t *testing.T) {
+	client := &fakeLLM{content: "Olá!"}
+	service, _ := newPromptService(client, contextPrompt)
+	service.SetLastCallSummaries(&customerSummaries{byCustomer: map[string]string{"customer-1": "Pediu a segunda via do boleto."}})
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		customerID string
+		want       string
+	}{
+		{"", "Você atende pela Clínica Sorriso. O cliente liga de +5511999887766."},
+		{"customer-1", "Você atende pela Clínica Sorriso. O cliente liga de +5511999887766. Última ligação: Pediu a segunda via do boleto."},
+	} {
+		sess, err := service.CreateSession(ctx, CreateParams{
+			TenantID:   uuid.New(),
+			AgentID:    "agent-receptionist",
+			CustomerID: tt.customerID,
+			Variables:  map[string]string{"caller_number": "+5511999887766", "tenant_name": "Clínica Sorriso"},
+		})
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		if _, err := service.SendMessage(ctx, sess.ID, "Oi"); err != nil {
+			t.Fatalf("SendMessage failed: %v", err)
+		}
+
+		if got := systemPrompt(t, client); got != tt.want {
+			t.Errorf("customer %q: system prompt %q, want %q", tt.customerID, got, tt.want)
+		}
+	}
+}
+
+func TestService_SetVariablesBeforeNextReply(t *testing.T) {
+	client := &fakeLLM{content: "Olá!"}
+	service, _ := newPromptService(client, contextPrompt)
+	ctx := context.Background()
+
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	if _, err := service.SendMessage(ctx, sess.ID, "Oi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if got, want := systemPrompt(t, client), "Você atende pela . O cliente liga de ."; got != want {
+		t.Errorf("Expected missing variables to render empty, got %q", got)
+	}
+
+	if _, err := service.SetVariables(ctx, sess.ID, map[string]string{"caller_number": "+5511999887766", "tenant_name": "Clínica Sorriso"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	if _, err := service.SendMessage(ctx, sess.ID, "Quero remarcar"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if got, want := systemPrompt(t, client), "Você atende pela Clínica Sorriso. O cliente liga de +5511999887766."; got != want {
+		t.Errorf("system prompt %q, want %q", got, want)
+	}
+}
+
+func TestService_PromptFallbacks(t *testing.T) {
+	ctx := context.Background()
+
+	// A prompt that isn't a valid template is sent as written
+	client := &fakeLLM{content: "Olá!"}
+	service, _ := newPromptService(client, "Responda em JSON: {{ \"ok\": true }}")
+	sess, _ := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist"})
+	if _, err := service.SendMessage(ctx, sess.ID, "Oi"); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if got := systemPrompt(t, client); got != "Responda em JSON: {{ \"ok\": true }}" {
+		t.Errorf("Expected the invalid template as written, got %q", got)
+	}
+
+	// A failed summary lookup leaves the conversation without it
+	client = &fakeLLM{content: "Olá!"}
+	service, _ = newPromptService(client, contextPrompt)
+	service.SetLastCallSummaries(&customerSummaries{err: errors.New("redis unavailable")})
+	sess, err := service.CreateSession(ctx, CreateParams{TenantID: uuid.New(), AgentID: "agent-receptionist", CustomerID: "customer-1"})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if _, ok := sess.Variables[VariableLastCallSummary]; ok {
+		t.Errorf("Expected no last call summary, got %v", sess.Variables)
+	}
+}
@@ -0,0 +1,226 @@
+// Package summary summarizes conversations once they end.
+package summary
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/billing"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/summary"
+)
+
+// DefaultPrompt asks for a concise summary and a disposition as JSON.
+const DefaultPrompt = `You summarize finished customer service calls for the business that took them.
+Reply only with a JSON object with two fields:
+"summary": two to four sentences on why the customer called, what was done and any promised follow-up;
+"disposition": one of resolved, unresolved, follow_up, transferred, abandoned, other.`
+
+// Store persists conversation summaries.
+type Store interface {
+	Save(ctx context.Context, s *summary.Summary) error
+	Get(ctx context.Context, conversationID uuid.UUID) (*summary.Summary, error)
+}
+
+// SettingsProvider reports whether a tenant has summarization enabled.
+type SettingsProvider interface {
+	SummarizationEnabled(ctx context.Context, tenantID uuid.UUID) (bool, error)
+}
+
+// Publisher publishes conversation summaries.
+type Publisher interface {
+	PublishConversationSummarized(ctx context.Context, s *summary.Summary) error
+}
+
+// Charger records the spend of a completion on a session.
+type Charger interface {
+	Charge(ctx context.Context, s *session.Session, provider string, completion *llm.Completion) *billing.Charge
+}
+
+// Config configures post-call summarization.
+type Config struct {
+	Enabled bool // global switch; tenants opt out through their settings
+	Model   string
+	Prompt  string
+	Timeout time.Duration
+}
+
+// Summarizer summarizes ended conversations in the background, stores the
+// summary and publishes it.
+type Summarizer struct {
+	llmClient llm.Client
+	store     Store
+	settings  SettingsProvider
+	publisher Publisher
+	charger   Charger
+	config    Config
+	logger    *zap.Logger
+	running   sync.WaitGroup
+}
+
+// NewSummarizer creates a new summarizer.
+func NewSummarizer(llmClient llm.Client, store Store, settings SettingsProvider, publisher Publisher, config Config, logger *zap.Logger) *Summarizer {
+	if config.Prompt == "" {
+		config.Prompt = DefaultPrompt
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Minute
+	}
+	return &Summarizer{
+		llmClient: llmClient,
+		store:     store,
+		settings:  settings,
+		publisher: publisher,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// SetCharger accounts the spend of summary completions.
+func (s *Summarizer) SetCharger(charger Charger) {
+	s.charger = charger
+}
+
+// Ended summarizes an ended session in the background.
+func (s *Summarizer) Ended(ctx context.Context, ended *session.Session) {
+	if !s.config.Enabled {
+		return
+	}
+
+	// Charging the summary updates the session spend; work on a copy
+	sess := *ended
+	sess.Turns = append([]session.Turn(nil), ended.Turns...)
+
+	// The request that ended the session may finish before the summary
+	ctx = context.WithoutCancel(ctx)
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+
+		ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+		defer cancel()
+
+		if _, err := s.Summarize(ctx, &sess); err != nil {
+			s.logger.Warn("failed to summarize conversation",
+				zap.String("session_id", sess.ID.String()),
+				zap.String("tenant_id", sess.TenantID.String()),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// Summarize summarizes a session, stores the summary and publishes it. It
+// returns nil without error when the tenant disabled summarization or the
+// conversation has nothing to summarize.
+func (s *Summarizer) Summarize(ctx context.Context, sess *session.Session) (*summary.Summary, error) {
+	if sess.Summary == "" && len(sess.Turns) == 0 {
+		return nil, nil
+	}
+
+	enabled, err := s.settings.SummarizationEnabled(ctx, sess.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant settings: %w", err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	completion, err := s.llmClient.Complete(ctx, llm.CompletionRequest{
+		Model: s.config.Model,
+		Messages: []llm.Message{
+			{Role: string(session.RoleSystem), Content: s.config.Prompt},
+			{Role: string(session.RoleUser), Content: transcript(sess)},
+		},
+		TenantID:  sess.TenantID,
+		SessionID: sess.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete summary: %w", err)
+	}
+	if s.charger != nil {
+		s.charger.Charge(ctx, sess, s.llmClient.Provider(), completion)
+	}
+
+	text, disposition := summary.Parse(completion.Content)
+	result := &summary.Summary{
+		ConversationID: sess.ID,
+		TenantID:       sess.TenantID,
+		AgentID:        sess.AgentID,
+		CallID:         sess.CallID,
+		CustomerID:     sess.CustomerID,
+		Variant:        sess.Variant,
+		Summary:        text,
+		Disposition:    disposition,
+		Model:          completion.Model,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := s.store.Save(ctx, result); err != nil {
+		return nil, fmt.Errorf("failed to save summary: %w", err)
+	}
+
+	if s.publisher != nil {
+		if err := s.publisher.PublishConversationSummarized(ctx, result); err != nil {
+			s.logger.Warn("failed to publish conversation summary",
+				zap.String("session_id", sess.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	s.logger.Info("conversation summarized",
+		zap.String("session_id", sess.ID.String()),
+		zap.String("tenant_id", sess.TenantID.String()),
+		zap.String("disposition", string(disposition)),
+	)
+
+	return result, nil
+}
+
+// Wait blocks until every running summary finishes.
+func (s *Summarizer) Wait() {
+	s.running.Wait()
+}
+
+// Shutdown waits for running summaries, giving up when ctx is done.
+func (s *Summarizer) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// transcript renders the whole conversation: the running summary of older
+// turns, if any, followed by the recent turns verbatim.
+func transcript(sess *session.Session) string {
+	var b strings.Builder
+	if sess.Summary != "" {
+		b.WriteString("Summary of the earlier conversation:\n")
+		b.WriteString(sess.Summary)
+		b.WriteString("\n\n")
+	}
+	b.WriteString("Conversation:\n")
+	for _, t := range sess.Turns {
+		fmt.Fprintf(&b, "%s: %s\n", t.Role, t.Content)
+	}
+	if sess.EndReason != "" {
+		fmt.Fprintf(&b, "\nThe conversation ended because of: %s\n", sess.EndReason)
+	}
+	return b.String()
+}
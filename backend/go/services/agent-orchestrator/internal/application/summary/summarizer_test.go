@@ -0,0 +1,177 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/adapter/llm"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/session"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/summary"
+)
+
+// stubLLM answers every completion with a fixed summary.
+type stubLLM struct {
+	mu       sync.Mutex
+	content  string
+	requests []llm.CompletionRequest
+}
+
+func (c *stubLLM) Complete(ctx context.Context, req llm.CompletionRequest) (*llm.Completion, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	return &llm.Completion{Content: c.content, Model: req.Model}, nil
+}
+
+func (c *stubLLM) Provider() string { return "fake" }
+
+type memoryStore struct {
+	mu        sync.Mutex
+	summaries map[uuid.UUID]*summary.Summary
+}
+
+func (m *memoryStore) Save(ctx context.Context, s *summary.Summary) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.summaries[s.ConversationID] = s
+	return nil
+}
+
+func (m *memoryStore) Get(ctx context.Context, id uuid.UUID) (*summary.Summary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.summaries[id]
+	if !ok {
+		return nil, errors.New("summary not found")
+	}
+	return s, nil
+}
+
+// tenantSettings enables summarization for every tenant not listed as off.
+type tenantSettings struct {
+	off map[uuid.UUID]bool
+}
+
+func (t *tenantSettings) SummarizationEnabled(ctx context.Context, tenantID uuid.UUID) (bool, error) {
+	return !t.off[tenantID], nil
+}
+
+type memoryPublisher struct {
+	mu        sync.Mutex
+	summaries []*summary.Summary
+}
+
+func (p *memoryPublisher) PublishConversationSummarized(ctx context.Context, s *summary.Summary) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.summaries = append(p.summaries, s)
+	return nil
+}
+
+type fixture struct {
+	summarizer *Summarizer
+	client     *stubLLM
+	store      *memoryStore
+	settings   *tenantSettings
+	publisher  *memoryPublisher
+}
+
+func newFixture(enabled bool) *fixture {
+	f := &fixture{
+		client:    &stubLLM{content: `{"summary": "Caller booked a table for two.", "disposition": "resolved"}`},
+		store:     &memoryStore{summaries: make(map[uuid.UUID]*summary.Summary)},
+		settings:  &tenantSettings{off: make(map[uuid.UUID]bool)},
+		publisher: &memoryPublisher{},
+	}
+	f.summarizer = NewSummarizer(f.client, f.store, f.settings, f.publisher, Config{
+		Enabled: enabled,
+		Model:   "summary-model",
+	}, zap.NewNop())
+	return f
+}
+
+func endedSession() *session.Session {
+	s := session.New(uuid.New(), "agent-1")
+	s.AddTurn(session.RoleUser, "I'd like a table for two tonight")
+	s.AddTurn(session.RoleAssistant, "Booked for 8pm, see you then")
+	s.End()
+	return s
+}
+
+func TestSummarizer_StoresAndPublishesSummary(t *testing.T) {
+	f := newFixture(true)
+	sess := endedSession()
+
+	f.summarizer.Ended(context.Background(), sess)
+	f.summarizer.Wait()
+
+	got, err := f.store.Get(context.Background(), sess.ID)
+	if err != nil {
+		t.Fatalf("summary not stored: %v", err)
+	}
+	if got.Summary != "Caller booked a table for two." || got.Disposition != summary.DispositionResolved {
+		t.Errorf("summary = %q (%s), want the model's summary resolved", got.Summary, got.Disposition)
+	}
+	if got.TenantID != sess.TenantID || got.AgentID != "agent-1" || got.Model != "summary-model" {
+		t.Errorf("summary = %+v, want it tied to the session", got)
+	}
+
+	if len(f.publisher.summaries) != 1 || f.publisher.summaries[0] != got {
+		t.Errorf("published %d summaries, want the stored one", len(f.publisher.summaries))
+	}
+
+	if len(f.client.requests) != 1 {
+		t.Fatalf("got %d completions, want 1", len(f.client.requests))
+	}
+	input := f.client.requests[0].Messages[1].Content
+	if !strings.Contains(input, "table for two tonight") || !strings.Contains(input, "Booked for 8pm") {
+		t.Errorf("transcript missing turns: %q", input)
+	}
+}
+
+func TestSummarizer_SkipsTenantWithSummarizationOff(t *testing.T) {
+	f := newFixture(true)
+	sess := endedSession()
+	f.settings.off[sess.TenantID] = true
+
+	f.summarizer.Ended(context.Background(), sess)
+	f.summarizer.Wait()
+
+	if _, err := f.store.Get(context.Background(), sess.ID); err == nil {
+		t.Error("summary stored for a tenant with summarization off")
+	}
+	if len(f.client.requests) != 0 || len(f.publisher.summaries) != 0 {
+		t.Error("summarization off should not call the model nor publish")
+	}
+}
+
+func TestSummarizer_GloballyDisabled(t *testing.T) {
+	f := newFixture(false)
+
+	f.summarizer.Ended(context.Background(), endedSession())
+	f.summarizer.Wait()
+
+	if len(f.client.requests) != 0 || len(f.store.summaries) != 0 {
+		t.Error("disabled summarizer should do nothing")
+	}
+}
+
+func TestSummarizer_SkipsEmptyConversation(t *testing.T) {
+	f := newFixture(true)
+	sess := session.New(uuid.New(), "agent-1")
+	sess.End()
+
+	got, err := f.summarizer.Summarize(context.Background(), sess)
+	if err != nil || got != nil {
+		t.Fatalf("Summarize = %v, %v; want nothing for an empty conversation", got, err)
+	}
+	if len(f.client.requests) != 0 {
+		t.Error("empty conversation should not call the model")
+	}
+}
@@ -0,0 +1,45 @@
+// Package agent contains the tenant agent configuration.
+package agent
+
+import "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+
+// Config is the agent configuration of a tenant, as managed by tenant-manager.
+type Config struct {
+	AgentID          string                 `json:"agent_id"`
+	Name             string                 `json:"name"`
+	Description      string                 `json:"description"`
+	SystemPrompt     string                 `json:"system_prompt"`
+	Routing          RoutingConfig          `json:"routing"`
+	Safety           SafetyConfig           `json:"safety"`
+	ConversationFlow ConversationFlowConfig `json:"conversation_flow"`
+
+	// Variants split conversations between versions of the agent, for A/B
+	// tests
+	Variants []Variant `json:"variants,omitempty"`
+
+	// Graph routes each turn from a router agent to specialist agents;
+	// without one the agent answers every turn itself
+	Graph *graph.Graph `json:"graph,omitempty"`
+}
+
+// RoutingConfig represents routing configuration.
+type RoutingConfig struct {
+	CanRoute          bool     `json:"can_route"`
+	AllowedTargets    []string `json:"allowed_targets"`
+	TransferIntents   []string `json:"transfer_intents"`
+	EscalationTrigger string   `json:"escalation_trigger"`
+}
+
+// SafetyConfig represents safety configuration.
+type SafetyConfig struct {
+	ForbiddenTopics   []string `json:"forbidden_topics"`
+	MaxTurns          int      `json:"max_turns"`
+	InactivityTimeout int      `json:"inactivity_timeout_seconds"`
+}
+
+// ConversationFlowConfig represents conversation flow configuration.
+type ConversationFlowConfig struct {
+	MaxRetries        int      `json:"max_retries"`
+	ConfirmationSteps []string `json:"confirmation_steps"`
+	Handoff           bool     `json:"handoff_enabled"`
+}
@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/graph"
+)
+
+var (
+	// ErrDefinitionNotFound is returned for an agent the tenant has not
+	// registered.
+	ErrDefinitionNotFound = errors.New("agent not found")
+	// ErrNameTaken is returned when the tenant already has an agent by the
+	// name.
+	ErrNameTaken = errors.New("agent name already taken")
+)
+
+// Definition is an agent registered in the orchestrator by a tenant, as
+// opposed to the agent configuration managed by tenant-manager.
+type Definition struct {
+	ID           uuid.UUID     `json:"id"`
+	TenantID     uuid.UUID     `json:"tenant_id"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	Model        string        `json:"model"`
+	SystemPrompt string        `json:"system_prompt"`
+	Tools        []string      `json:"tools"` // tools-gateway tool IDs
+	Routing      RoutingConfig `json:"routing"`
+	Safety       SafetyConfig  `json:"safety"`
+	Graph        *graph.Graph  `json:"graph,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// Config returns the registered agent as the configuration of a session.
+func (d *Definition) Config() *Config {
+	return &Config{
+		AgentID:      d.ID.String(),
+		Name:         d.Name,
+		Description:  d.Description,
+		SystemPrompt: d.SystemPrompt,
+		Routing:      d.Routing,
+		Safety:       d.Safety,
+		Graph:        d.Graph,
+	}
+}
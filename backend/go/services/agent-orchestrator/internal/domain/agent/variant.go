@@ -0,0 +1,60 @@
+package agent
+
+import "hash/fnv"
+
+// Variant is one arm of an agent A/B test. Empty fields fall back to the
+// agent defaults.
+type Variant struct {
+	Name         string `json:"name"`
+	Weight       int    `json:"weight"` // relative share of conversations
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	Model        string `json:"model,omitempty"`
+}
+
+// AssignVariant picks a variant for key by weight. The same key always gets
+// the same variant while the variants are unchanged, so no assignment needs to
+// be stored. It returns nil when the agent has no variants with weight.
+func (c *Config) AssignVariant(key string) *Variant {
+	total := 0
+	for _, v := range c.Variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	// The agent is part of the hash so tests on different agents split the
+	// same customers independently
+	h := fnv.New64a()
+	h.Write([]byte(c.AgentID))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	bucket := int(h.Sum64() % uint64(total))
+
+	for i := range c.Variants {
+		v := &c.Variants[i]
+		if v.Weight <= 0 {
+			continue
+		}
+		if bucket < v.Weight {
+			return v
+		}
+		bucket -= v.Weight
+	}
+	return nil
+}
+
+// Variant returns the variant named name, or nil.
+func (c *Config) Variant(name string) *Variant {
+	if name == "" {
+		return nil
+	}
+	for i := range c.Variants {
+		if c.Variants[i].Name == name {
+			return &c.Variants[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestConfig_AssignVariantDistribution(t *testing.T) {
+	config := &Config{
+		AgentID: "agent-receptionist",
+		Variants: []Variant{
+			{Name: "control", Weight: 70},
+			{Name: "friendly", Weight: 20},
+			{Name: "concise", Weight: 10},
+			{Name: "paused", Weight: 0},
+		},
+	}
+
+	const n = 20000
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		counts[config.AssignVariant(fmt.Sprintf("conversation-%d", i)).Name]++
+	}
+
+	for name, share := range map[string]float64{"control": 0.7, "friendly": 0.2, "concise": 0.1} {
+		if got := float64(counts[name]) / n; math.Abs(got-share) > 0.02 {
+			t.Errorf("Variant %s got %.3f of conversations, expected about %.2f", name, got, share)
+		}
+	}
+	if counts["paused"] != 0 {
+		t.Errorf("Expected no conversations for a zero-weight variant, got %d", counts["paused"])
+	}
+}
+
+func TestConfig_AssignVariantIsDeterministic(t *testing.T) {
+	config := &Config{
+		AgentID:  "agent-receptionist",
+		Variants: []Variant{{Name: "a", Weight: 1}, {Name: "b", Weight: 1}},
+	}
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("+5511999990%03d", i)
+		first := config.AssignVariant(key)
+		for j := 0; j < 3; j++ {
+			if again := config.AssignVariant(key); again.Name != first.Name {
+				t.Fatalf("Key %s got variant %s, then %s", key, first.Name, again.Name)
+			}
+		}
+	}
+}
+
+func TestConfig_AssignVariantWithoutVariants(t *testing.T) {
+	if v := (&Config{}).AssignVariant("conversation"); v != nil {
+		t.Errorf("Expected no variant, got %+v", v)
+	}
+	config := &Config{Variants: []Variant{{Name: "off", Weight: 0}}}
+	if v := config.AssignVariant("conversation"); v != nil {
+		t.Errorf("Expected no variant when all weights are zero, got %+v", v)
+	}
+}
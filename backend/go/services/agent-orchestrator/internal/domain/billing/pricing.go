@@ -0,0 +1,108 @@
+// Package billing contains LLM pricing and spend accounting.
+package billing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Currency is the currency of all prices and costs.
+const Currency = "USD"
+
+// Price is the cost of a model in USD per million tokens.
+type Price struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// Cost returns the cost of the given token counts.
+func (p Price) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.Prompt + float64(completionTokens)*p.Completion) / 1_000_000
+}
+
+// PriceTable maps "provider/model" to its price.
+type PriceTable map[string]Price
+
+// DefaultPrices are list prices of the supported models.
+var DefaultPrices = PriceTable{
+	"openai/gpt-4":         {Prompt: 30, Completion: 60},
+	"openai/gpt-4-turbo":   {Prompt: 10, Completion: 30},
+	"openai/gpt-4o":        {Prompt: 2.5, Completion: 10},
+	"openai/gpt-4o-mini":   {Prompt: 0.15, Completion: 0.6},
+	"openai/gpt-3.5-turbo": {Prompt: 0.5, Completion: 1.5},
+}
+
+// ParsePriceTable parses a JSON object of "provider/model" prices, e.g.
+// {"openai/gpt-4o":{"prompt":2.5,"completion":10}}, over the defaults.
+func ParsePriceTable(data string) (PriceTable, error) {
+	table := make(PriceTable, len(DefaultPrices))
+	for k, v := range DefaultPrices {
+		table[k] = v
+	}
+	if strings.TrimSpace(data) == "" {
+		return table, nil
+	}
+
+	var overrides PriceTable
+	if err := json.Unmarshal([]byte(data), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid price table: %w", err)
+	}
+	for k, v := range overrides {
+		table[strings.ToLower(k)] = v
+	}
+	return table, nil
+}
+
+// Lookup returns the price of a provider model. Dated model snapshots such as
+// gpt-4o-2024-08-06 fall back to the longest priced prefix.
+func (t PriceTable) Lookup(provider, model string) (Price, bool) {
+	key := strings.ToLower(provider + "/" + model)
+	if p, ok := t[key]; ok {
+		return p, true
+	}
+
+	var best string
+	for k := range t {
+		if strings.HasPrefix(key, k+"-") && len(k) > len(best) {
+			best = k
+		}
+	}
+	if best == "" {
+		return Price{}, false
+	}
+	return t[best], true
+}
+
+// Charge is the cost of a single LLM call in a conversation.
+type Charge struct {
+	ConversationID   uuid.UUID `json:"conversation_id"`
+	TenantID         uuid.UUID `json:"tenant_id"`
+	AgentID          string    `json:"agent_id"`
+	Variant          string    `json:"variant,omitempty"` // agent variant, if any
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	Cost             float64   `json:"cost"`
+	Priced           bool      `json:"priced"` // false when the model has no price
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TenantSpend is the LLM spend of a tenant in a calendar month.
+type TenantSpend struct {
+	TenantID         uuid.UUID `json:"tenant_id"`
+	Month            string    `json:"month"` // YYYY-MM
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	Cost             float64   `json:"cost"`
+	Currency         string    `json:"currency"`
+}
+
+// MonthOf returns the YYYY-MM accounting month of t.
+func MonthOf(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
@@ -0,0 +1,54 @@
+package billing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrice_Cost(t *testing.T) {
+	p := Price{Prompt: 30, Completion: 60}
+
+	got := p.Cost(1000, 500)
+	if math.Abs(got-0.06) > 1e-9 {
+		t.Errorf("Cost = %f, expected 0.06", got)
+	}
+}
+
+func TestPriceTable_Lookup(t *testing.T) {
+	table := DefaultPrices
+
+	if p, ok := table.Lookup("openai", "gpt-4o"); !ok || p.Prompt != 2.5 {
+		t.Errorf("Expected gpt-4o price, got %+v %v", p, ok)
+	}
+	// Snapshots use the longest matching prefix, not gpt-4
+	if p, ok := table.Lookup("OpenAI", "gpt-4o-mini-2024-07-18"); !ok || p.Prompt != 0.15 {
+		t.Errorf("Expected gpt-4o-mini price for snapshot, got %+v %v", p, ok)
+	}
+	if _, ok := table.Lookup("openai", "unknown-model"); ok {
+		t.Error("Unknown model should not be priced")
+	}
+}
+
+func TestParsePriceTable(t *testing.T) {
+	table, err := ParsePriceTable(`{"OpenAI/gpt-4":{"prompt":1,"completion":2},"acme/small":{"prompt":0.1,"completion":0.2}}`)
+	if err != nil {
+		t.Fatalf("ParsePriceTable failed: %v", err)
+	}
+
+	if p, _ := table.Lookup("openai", "gpt-4"); p.Prompt != 1 {
+		t.Errorf("Expected override, got %+v", p)
+	}
+	if _, ok := table.Lookup("acme", "small"); !ok {
+		t.Error("Expected new model to be priced")
+	}
+	if _, ok := table.Lookup("openai", "gpt-4o"); !ok {
+		t.Error("Defaults should be kept")
+	}
+	if DefaultPrices["openai/gpt-4"].Prompt != 30 {
+		t.Error("Defaults must not be modified")
+	}
+
+	if _, err := ParsePriceTable("not json"); err == nil {
+		t.Error("Expected error for invalid table")
+	}
+}
@@ -0,0 +1,122 @@
+// Package failover defines the LLM provider fallback chains of tenants.
+package failover
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+// ErrInvalidChains is returned for chains that can't be followed, e.g. with
+// an unknown provider.
+var ErrInvalidChains = errors.New("invalid failover chains")
+
+// Reason tells why a call left the primary provider.
+type Reason string
+
+const (
+	ReasonError     Reason = "error"     // the primary failed the call
+	ReasonUnhealthy Reason = "unhealthy" // the primary was skipped as down
+)
+
+// Link is a step of a chain: a provider and, to degrade, the model it serves
+// instead of the requested one, e.g. a cheaper one.
+type Link struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// Chain is the ordered list of providers a call tries, primary first.
+type Chain []Link
+
+// Chains holds the chain of each tenant: its own override, else the one of
+// its plan, else the default.
+type Chains struct {
+	Default Chain                       `json:"default"`
+	Plans   map[entitlements.Plan]Chain `json:"plans,omitempty"`
+	Tenants map[uuid.UUID]Chain         `json:"tenants,omitempty"`
+}
+
+// Resolve returns the chain of a tenant on a plan; plan may be empty when
+// unknown.
+func (c Chains) Resolve(tenantID uuid.UUID, plan entitlements.Plan) Chain {
+	if chain, ok := c.Tenants[tenantID]; ok {
+		return chain
+	}
+	if chain, ok := c.Plans[plan]; ok {
+		return chain
+	}
+	return c.Default
+}
+
+// ParseChains parses chains from JSON, e.g.
+// {"default":[{"provider":"openai"},{"provider":"azure","model":"gpt-4o-mini"}]}.
+// Every provider must be one of providers. Empty data chains the providers
+// in the given order.
+func ParseChains(data string, providers []string) (Chains, error) {
+	known := make(map[string]bool, len(providers))
+	for _, p := range providers {
+		known[p] = true
+	}
+	if strings.TrimSpace(data) == "" {
+		var chains Chains
+		for _, p := range providers {
+			chains.Default = append(chains.Default, Link{Provider: p})
+		}
+		return chains, nil
+	}
+
+	var chains Chains
+	if err := json.Unmarshal([]byte(data), &chains); err != nil {
+		return Chains{}, fmt.Errorf("%w: %v", ErrInvalidChains, err)
+	}
+	if len(chains.Default) == 0 {
+		return Chains{}, fmt.Errorf("%w: a default chain is required", ErrInvalidChains)
+	}
+	if err := chains.Default.validate(known); err != nil {
+		return Chains{}, fmt.Errorf("%w: default: %v", ErrInvalidChains, err)
+	}
+	for plan, chain := range chains.Plans {
+		if !entitlements.IsValidPlan(plan) {
+			return Chains{}, fmt.Errorf("%w: unknown plan %q", ErrInvalidChains, plan)
+		}
+		if err := chain.validate(known); err != nil {
+			return Chains{}, fmt.Errorf("%w: plan %s: %v", ErrInvalidChains, plan, err)
+		}
+	}
+	for tenantID, chain := range chains.Tenants {
+		if err := chain.validate(known); err != nil {
+			return Chains{}, fmt.Errorf("%w: tenant %s: %v", ErrInvalidChains, tenantID, err)
+		}
+	}
+	return chains, nil
+}
+
+func (c Chain) validate(known map[string]bool) error {
+	if len(c) == 0 {
+		return errors.New("empty chain")
+	}
+	for _, link := range c {
+		if !known[link.Provider] {
+			return fmt.Errorf("unknown provider %q", link.Provider)
+		}
+	}
+	return nil
+}
+
+// Event is a call served by another link than the primary of its chain.
+type Event struct {
+	TenantID   uuid.UUID
+	SessionID  uuid.UUID
+	From       Link // the primary, with the requested model
+	To         Link // the link that served the call, with its model
+	Reason     Reason
+	Error      string // the last failure of the primary, if it was tried
+	OccurredAt time.Time
+}
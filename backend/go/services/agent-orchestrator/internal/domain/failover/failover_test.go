@@ -0,0 +1,55 @@
+package failover
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+func TestParseChains(t *testing.T) {
+	providers := []string{"openai", "azure"}
+
+	chains, err := ParseChains("", providers)
+	if err != nil || len(chains.Default) != 2 || chains.Default[1].Provider != "azure" {
+		t.Fatalf("Expected the providers chained in order, got %+v, %v", chains, err)
+	}
+
+	tenantID := uuid.New()
+	chains, err = ParseChains(`{
+		"default": [{"provider": "openai"}],
+		"plans": {"enterprise": [{"provider": "openai"}, {"provider": "azure", "model": "gpt-4o-mini"}]},
+		"tenants": {"`+tenantID.String()+`": [{"provider": "azure"}]}
+	}`, providers)
+	if err != nil {
+		t.Fatalf("ParseChains failed: %v", err)
+	}
+	if got := chains.Resolve(tenantID, entitlements.PlanEnterprise); got[0].Provider != "azure" {
+		t.Errorf("Expected the tenant chain first, got %+v", got)
+	}
+	if got := chains.Resolve(uuid.New(), entitlements.PlanEnterprise); len(got) != 2 || got[1].Model != "gpt-4o-mini" {
+		t.Errorf("Expected the plan chain, got %+v", got)
+	}
+	if got := chains.Resolve(uuid.New(), ""); len(got) != 1 {
+		t.Errorf("Expected the default chain, got %+v", got)
+	}
+}
+
+func TestParseChains_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown provider": `{"default": [{"provider": "anthropic"}]}`,
+		"no default":       `{"plans": {"starter": [{"provider": "openai"}]}}`,
+		"unknown plan":     `{"default": [{"provider": "openai"}], "plans": {"gold": [{"provider": "openai"}]}}`,
+		"empty chain":      `{"default": [{"provider": "openai"}], "plans": {"starter": []}}`,
+		"malformed":        `{"default": `,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseChains(data, []string{"openai", "azure"}); !errors.Is(err, ErrInvalidChains) {
+				t.Errorf("Expected ErrInvalidChains, got %v", err)
+			}
+		})
+	}
+}
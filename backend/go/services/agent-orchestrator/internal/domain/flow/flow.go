@@ -0,0 +1,158 @@
+// Package flow contains the guided confirmation-step state machine.
+package flow
+
+import "github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/guardrail"
+
+// StepStatus is the state of a confirmation step.
+type StepStatus string
+
+const (
+	StepPending   StepStatus = "pending"
+	StepConfirmed StepStatus = "confirmed"
+)
+
+// Step is a value the caller must confirm, e.g. "name" or "order".
+type Step struct {
+	Name    string     `json:"name"`
+	Status  StepStatus `json:"status"`
+	Retries int        `json:"retries"` // re-prompts after unclear answers
+}
+
+// Answer is the caller's answer to a confirmation question.
+type Answer string
+
+const (
+	AnswerYes     Answer = "yes"
+	AnswerNo      Answer = "no"
+	AnswerUnclear Answer = "unclear"
+)
+
+// Outcome tells the agent what to do next after a user message.
+type Outcome string
+
+const (
+	OutcomeAsk       Outcome = "ask"       // ask the caller to confirm the current step
+	OutcomeReprompt  Outcome = "reprompt"  // the answer was unclear, ask again
+	OutcomeCorrect   Outcome = "correct"   // the caller denied, ask for the right value
+	OutcomeCompleted Outcome = "completed" // every step is confirmed
+	OutcomeExhausted Outcome = "exhausted" // retries ran out on the current step
+)
+
+// State tracks the confirmation steps of a session.
+type State struct {
+	Steps      []Step `json:"steps"`
+	Current    int    `json:"current"`
+	MaxRetries int    `json:"max_retries"`
+	Awaiting   bool   `json:"awaiting"` // the current step was asked and awaits an answer
+	Completed  bool   `json:"completed"`
+	Failed     bool   `json:"failed"`
+}
+
+// New creates the state of a flow with the given steps.
+func New(steps []string, maxRetries int) *State {
+	s := &State{MaxRetries: maxRetries}
+	for _, name := range steps {
+		s.Steps = append(s.Steps, Step{Name: name, Status: StepPending})
+	}
+	s.Completed = len(s.Steps) == 0
+	return s
+}
+
+// Active reports whether steps remain to be confirmed.
+func (s *State) Active() bool {
+	return !s.Completed && !s.Failed
+}
+
+// CurrentStep returns the step being confirmed, or nil when the flow is over.
+func (s *State) CurrentStep() *Step {
+	if !s.Active() {
+		return nil
+	}
+	return &s.Steps[s.Current]
+}
+
+// Advance applies a user message to the flow. A message sent before the
+// current step was asked only triggers the question; afterwards it is taken
+// as the answer.
+func (s *State) Advance(text string) Outcome {
+	step := s.CurrentStep()
+	if step == nil {
+		return ""
+	}
+	if !s.Awaiting {
+		s.Awaiting = true
+		return OutcomeAsk
+	}
+
+	switch ClassifyAnswer(text) {
+	case AnswerYes:
+		step.Status = StepConfirmed
+		s.Current++
+		if s.Current == len(s.Steps) {
+			s.Completed = true
+			s.Awaiting = false
+			return OutcomeCompleted
+		}
+		return OutcomeAsk
+	case AnswerNo:
+		// The next message carries the correction, then the step is asked again
+		s.Awaiting = false
+		return OutcomeCorrect
+	default:
+		if step.Retries >= s.MaxRetries {
+			s.Failed = true
+			s.Awaiting = false
+			return OutcomeExhausted
+		}
+		step.Retries++
+		return OutcomeReprompt
+	}
+}
+
+// Status is the externally visible progress of a flow.
+type Status struct {
+	Step       string `json:"step,omitempty"`
+	Index      int    `json:"index"`
+	Total      int    `json:"total"`
+	Retries    int    `json:"retries"`
+	MaxRetries int    `json:"max_retries"`
+	Completed  bool   `json:"completed"`
+	Failed     bool   `json:"failed"`
+}
+
+// Status returns the current progress of the flow.
+func (s *State) Status() Status {
+	status := Status{
+		Index:      s.Current,
+		Total:      len(s.Steps),
+		MaxRetries: s.MaxRetries,
+		Completed:  s.Completed,
+		Failed:     s.Failed,
+	}
+	if s.Current < len(s.Steps) {
+		status.Step = s.Steps[s.Current].Name
+		status.Retries = s.Steps[s.Current].Retries
+	}
+	return status
+}
+
+// Confirmation words in Portuguese, English and Spanish.
+var (
+	yesWords = []string{"sim", "isso", "correto", "certo", "exato", "confirmo", "pode ser", "yes", "yeah", "yep", "correct", "right", "confirm", "si", "claro"}
+	noWords  = []string{"nao", "errado", "incorreto", "no", "nope", "wrong", "incorrect"}
+)
+
+// ClassifyAnswer classifies a yes/no answer. Answers with both or neither are
+// unclear.
+func ClassifyAnswer(text string) Answer {
+	_, yes := guardrail.MatchTopic(text, yesWords)
+	_, no := guardrail.MatchTopic(text, noWords)
+	switch {
+	case yes && !no:
+		return AnswerYes
+	case no && !yes:
+		return AnswerNo
+	default:
+		return AnswerUnclear
+	}
+}
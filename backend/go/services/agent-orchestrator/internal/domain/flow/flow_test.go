@@ -0,0 +1,91 @@
+package flow
+
+import "testing"
+
+func TestClassifyAnswer(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected Answer
+	}{
+		{"Sim, está correto", AnswerYes},
+		{"yes", AnswerYes},
+		{"Não, meu nome é Ana", AnswerNo},
+		{"nope, that's wrong", AnswerNo},
+		{"hmm, what?", AnswerUnclear},
+		{"sim... não, espera", AnswerUnclear},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyAnswer(tt.text); got != tt.expected {
+			t.Errorf("ClassifyAnswer(%q) = %s, expected %s", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestState_Completion(t *testing.T) {
+	s := New([]string{"name", "order"}, 2)
+
+	steps := []struct {
+		text    string
+		outcome Outcome
+		step    string
+	}{
+		{"Oi, quero uma pizza", OutcomeAsk, "name"},   // asks the first step
+		{"sim", OutcomeAsk, "order"},                  // name confirmed, asks order
+		{"não", OutcomeCorrect, "order"},              // denied
+		{"uma calabresa grande", OutcomeAsk, "order"}, // correction, asks again
+		{"isso mesmo", OutcomeCompleted, ""},
+	}
+
+	for i, st := range steps {
+		if got := s.Advance(st.text); got != st.outcome {
+			t.Fatalf("step %d: Advance(%q) = %s, expected %s", i, st.text, got, st.outcome)
+		}
+		if got := s.Status().Step; got != st.step {
+			t.Fatalf("step %d: current step %q, expected %q", i, got, st.step)
+		}
+	}
+
+	if !s.Completed || s.Active() || s.CurrentStep() != nil {
+		t.Errorf("Expected completed flow, got %+v", s)
+	}
+	for _, step := range s.Steps {
+		if step.Status != StepConfirmed {
+			t.Errorf("Step %s not confirmed", step.Name)
+		}
+	}
+	if s.Advance("sim") != "" {
+		t.Error("A completed flow should ignore messages")
+	}
+}
+
+func TestState_RetryExhaustion(t *testing.T) {
+	s := New([]string{"name"}, 2)
+	s.Advance("hello") // asks
+
+	for i := 1; i <= 2; i++ {
+		if got := s.Advance("hmm?"); got != OutcomeReprompt {
+			t.Fatalf("unclear answer %d: expected reprompt, got %s", i, got)
+		}
+		if s.Status().Retries != i {
+			t.Errorf("Expected %d retries, got %d", i, s.Status().Retries)
+		}
+	}
+
+	if got := s.Advance("what?"); got != OutcomeExhausted {
+		t.Fatalf("Expected exhausted after max retries, got %s", got)
+	}
+	status := s.Status()
+	if !status.Failed || status.Completed || status.Step != "name" || status.Retries != 2 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if s.Active() {
+		t.Error("A failed flow should not be active")
+	}
+}
+
+func TestNew_NoSteps(t *testing.T) {
+	if s := New(nil, 3); s.Active() || !s.Completed {
+		t.Error("A flow without steps is complete")
+	}
+}
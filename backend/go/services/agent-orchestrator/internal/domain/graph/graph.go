@@ -0,0 +1,165 @@
+// Package graph contains declarative agent graphs: a router agent hands each
+// turn to specialist agents by intent or condition until one answers.
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxHops bounds the transitions of a turn when the graph sets none.
+const DefaultMaxHops = 5
+
+// ErrInvalidGraph is returned for a graph that can't be run.
+var ErrInvalidGraph = errors.New("invalid agent graph")
+
+// Reason is why a turn moved between nodes.
+type Reason string
+
+const (
+	ReasonKeyword   Reason = "keyword"    // the message has a keyword of the edge
+	ReasonIntent    Reason = "intent"     // the router detected the edge intent
+	ReasonDefault   Reason = "default"    // no other edge of the node held
+	ReasonLoopGuard Reason = "loop_guard" // the transition was refused
+)
+
+// Graph is a set of agent nodes the turns enter at Entry.
+type Graph struct {
+	Entry   string `json:"entry"`
+	Nodes   []Node `json:"nodes"`
+	MaxHops int    `json:"max_hops,omitempty"`
+}
+
+// Node is an agent of the graph. A node with edges routes the turn; one
+// without answers it, as does a router none of whose edges hold. Empty
+// fields fall back to the agent defaults.
+type Node struct {
+	ID           string `json:"id"`
+	Model        string `json:"model,omitempty"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	Timeout      int    `json:"timeout_seconds,omitempty"`
+	Edges        []Edge `json:"edges,omitempty"`
+}
+
+// Edge hands the turn to another node when its condition holds: a keyword
+// in the message or the intent detected by the router. An edge with neither
+// always holds, as the fallback of its node.
+type Edge struct {
+	To       string   `json:"to"`
+	Intent   string   `json:"intent,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// Transition is a turn moving from a node to another.
+type Transition struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	From           string    `json:"from"`
+	To             string    `json:"to"`
+	Reason         Reason    `json:"reason"`
+	Intent         string    `json:"intent,omitempty"`
+	Hop            int       `json:"hop"` // 1 for the first transition of the turn
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// Node returns the node by the ID, or nil.
+func (g *Graph) Node(id string) *Node {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return &g.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// Hops returns the transitions allowed in a turn.
+func (g *Graph) Hops() int {
+	if g.MaxHops > 0 {
+		return g.MaxHops
+	}
+	return DefaultMaxHops
+}
+
+// Validate checks the entry and every edge lead to a node of the graph.
+func (g *Graph) Validate() error {
+	if len(g.Nodes) == 0 {
+		return fmt.Errorf("%w: no nodes", ErrInvalidGraph)
+	}
+	if g.MaxHops < 0 {
+		return fmt.Errorf("%w: max_hops can't be negative", ErrInvalidGraph)
+	}
+
+	ids := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if n.ID == "" {
+			return fmt.Errorf("%w: node without id", ErrInvalidGraph)
+		}
+		if ids[n.ID] {
+			return fmt.Errorf("%w: node %q is defined twice", ErrInvalidGraph, n.ID)
+		}
+		if n.Timeout < 0 {
+			return fmt.Errorf("%w: node %q has a negative timeout", ErrInvalidGraph, n.ID)
+		}
+		ids[n.ID] = true
+	}
+	if !ids[g.Entry] {
+		return fmt.Errorf("%w: entry %q is not a node", ErrInvalidGraph, g.Entry)
+	}
+	for _, n := range g.Nodes {
+		for _, e := range n.Edges {
+			if !ids[e.To] {
+				return fmt.Errorf("%w: node %q has an edge to unknown node %q", ErrInvalidGraph, n.ID, e.To)
+			}
+		}
+	}
+	return nil
+}
+
+// Intents returns the intents the edges of the node route on.
+func (n *Node) Intents() []string {
+	var intents []string
+	for _, e := range n.Edges {
+		if e.Intent != "" {
+			intents = append(intents, e.Intent)
+		}
+	}
+	return intents
+}
+
+// MatchKeywords returns the first edge with a keyword in text, if any.
+func (n *Node) MatchKeywords(text string) *Edge {
+	lower := strings.ToLower(text)
+	for i, e := range n.Edges {
+		for _, k := range e.Keywords {
+			if k != "" && strings.Contains(lower, strings.ToLower(k)) {
+				return &n.Edges[i]
+			}
+		}
+	}
+	return nil
+}
+
+// MatchIntent returns the edge of the intent, if any.
+func (n *Node) MatchIntent(intent string) *Edge {
+	for i, e := range n.Edges {
+		if e.Intent != "" && strings.EqualFold(e.Intent, intent) {
+			return &n.Edges[i]
+		}
+	}
+	return nil
+}
+
+// Fallback returns the unconditional edge of the node, if any.
+func (n *Node) Fallback() *Edge {
+	for i, e := range n.Edges {
+		if e.Intent == "" && len(e.Keywords) == 0 {
+			return &n.Edges[i]
+		}
+	}
+	return nil
+}
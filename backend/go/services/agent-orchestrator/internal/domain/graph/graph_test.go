@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGraph_Validate(t *testing.T) {
+	valid := func() *Graph {
+		return &Graph{
+			Entry: "router",
+			Nodes: []Node{
+				{ID: "router", Edges: []Edge{{To: "billing", Intent: "billing"}}},
+				{ID: "billing"},
+			},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Expected a valid graph, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(g *Graph)
+	}{
+		{name: "no nodes", modify: func(g *Graph) { g.Nodes = nil }},
+		{name: "unknown entry", modify: func(g *Graph) { g.Entry = "sales" }},
+		{name: "unknown edge target", modify: func(g *Graph) { g.Nodes[0].Edges[0].To = "sales" }},
+		{name: "duplicate node", modify: func(g *Graph) { g.Nodes[1].ID = "router" }},
+		{name: "negative timeout", modify: func(g *Graph) { g.Nodes[1].Timeout = -1 }},
+		{name: "negative max hops", modify: func(g *Graph) { g.MaxHops = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := valid()
+			tt.modify(g)
+			if err := g.Validate(); !errors.Is(err, ErrInvalidGraph) {
+				t.Errorf("Expected ErrInvalidGraph, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNode_Match(t *testing.T) {
+	n := Node{ID: "router", Edges: []Edge{
+		{To: "billing", Intent: "Billing", Keywords: []string{"invoice"}},
+		{To: "general"},
+	}}
+
+	if e := n.MatchKeywords("My INVOICE is wrong"); e == nil || e.To != "billing" {
+		t.Errorf("Expected the keyword edge, got %+v", e)
+	}
+	if e := n.MatchIntent("billing"); e == nil || e.To != "billing" {
+		t.Errorf("Expected the intent edge, got %+v", e)
+	}
+	if e := n.Fallback(); e == nil || e.To != "general" {
+		t.Errorf("Expected the fallback edge, got %+v", e)
+	}
+}
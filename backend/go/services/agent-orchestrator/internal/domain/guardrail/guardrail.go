@@ -0,0 +1,116 @@
+// Package guardrail contains conversation safety rules and violations.
+package guardrail
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Kind identifies the rule that was violated.
+type Kind string
+
+const (
+	KindForbiddenTopic Kind = "forbidden_topic"
+	KindMaxTurns       Kind = "max_turns"
+	KindInactivity     Kind = "inactivity"
+)
+
+// Action is how a blocked message is handled.
+type Action string
+
+const (
+	ActionRefuse   Action = "refuse"   // answer with a refusal and continue
+	ActionTransfer Action = "transfer" // hand the caller to a human
+	ActionEnd      Action = "end"      // end the conversation
+)
+
+// ParseAction parses the configurable blocking action, refuse or transfer,
+// defaulting to refuse.
+func ParseAction(s string) Action {
+	if Action(strings.ToLower(strings.TrimSpace(s))) == ActionTransfer {
+		return ActionTransfer
+	}
+	return ActionRefuse
+}
+
+// Violation is a guardrail rule broken in a conversation. It never holds the
+// offending text, only which rule and topic matched.
+type Violation struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	Kind           Kind      `json:"kind"`
+	Topic          string    `json:"topic,omitempty"`
+	Role           string    `json:"role,omitempty"`   // author of the offending message
+	Source         string    `json:"source,omitempty"` // keyword or classifier
+	Action         Action    `json:"action"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// Detection sources.
+const (
+	SourceKeyword    = "keyword"
+	SourceClassifier = "classifier"
+)
+
+// MatchTopic returns the first forbidden topic that appears in text as whole
+// words, ignoring case and accents ("Política" matches "politica").
+func MatchTopic(text string, topics []string) (string, bool) {
+	words := normalizeWords(text)
+	if len(words) == 0 {
+		return "", false
+	}
+
+	for _, topic := range topics {
+		phrase := normalizeWords(topic)
+		if len(phrase) > 0 && containsPhrase(words, phrase) {
+			return topic, true
+		}
+	}
+	return "", false
+}
+
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		match := true
+		for j, w := range phrase {
+			if words[i+j] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeWords lowercases text, strips accents and splits it into words.
+func normalizeWords(text string) []string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if folded, ok := accents[r]; ok {
+			r = folded
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// accents folds the accented letters used in Portuguese and Spanish.
+var accents = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
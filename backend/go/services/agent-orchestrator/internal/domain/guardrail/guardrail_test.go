@@ -0,0 +1,37 @@
+package guardrail
+
+import "testing"
+
+func TestMatchTopic(t *testing.T) {
+	topics := []string{"política", "medical advice", "crypto"}
+
+	tests := []struct {
+		text     string
+		expected string
+		matched  bool
+	}{
+		{"O que você acha da POLÍTICA atual?", "política", true},
+		{"what about politica?", "política", true},
+		{"Can you give me medical advice?", "medical advice", true},
+		{"Can you give me medical   advice!", "medical advice", true},
+		{"medical records and legal advice", "", false}, // words not adjacent
+		{"I love cryptography", "", false},              // whole words only
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		topic, ok := MatchTopic(tt.text, topics)
+		if ok != tt.matched || topic != tt.expected {
+			t.Errorf("MatchTopic(%q) = %q, %v; expected %q, %v", tt.text, topic, ok, tt.expected, tt.matched)
+		}
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	if ParseAction("Transfer") != ActionTransfer {
+		t.Error("Expected transfer")
+	}
+	if ParseAction("") != ActionRefuse || ParseAction("block") != ActionRefuse {
+		t.Error("Expected refuse by default")
+	}
+}
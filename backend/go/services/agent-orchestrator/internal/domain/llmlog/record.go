@@ -0,0 +1,30 @@
+// Package llmlog contains the domain model for logged LLM exchanges.
+package llmlog
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is a redacted chat message sent to the model.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Record is a redacted prompt/response pair kept for debugging and quality review.
+type Record struct {
+	ID               uuid.UUID `json:"id"`
+	TenantID         uuid.UUID `json:"tenant_id"`
+	SessionID        uuid.UUID `json:"session_id"`
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	Messages         []Message `json:"messages"`
+	Response         string    `json:"response,omitempty"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	LatencyMs        int64     `json:"latency_ms"`
+	Error            string    `json:"error,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
@@ -0,0 +1,109 @@
+package llmlog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Redaction pattern names.
+const (
+	PatternEmail = "email"
+	PatternPhone = "phone"
+	PatternCard  = "card"
+)
+
+// pattern masks matches of a regular expression with a placeholder. When valid
+// is set, only matches it accepts are masked.
+type pattern struct {
+	re    *regexp.Regexp
+	mask  string
+	valid func(match string) bool
+}
+
+// patterns are applied in this order. Card numbers must pass the Luhn check,
+// so other long digit runs such as E.164 phone numbers fall through to phone.
+var patterns = []struct {
+	name string
+	pattern
+}{
+	{PatternEmail, pattern{re: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), mask: "[EMAIL]"}},
+	{PatternCard, pattern{re: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`), mask: "[CARD]", valid: luhnValid}},
+	{PatternPhone, pattern{re: regexp.MustCompile(`(?:\+?\d{1,3}[ \-.]?)?\(?\d{2,3}\)?[ \-.]?\d{4,5}[ \-.]?\d{4}\b`), mask: "[PHONE]"}},
+}
+
+// Redactor masks PII in text before it is stored.
+type Redactor struct {
+	patterns []pattern
+}
+
+// NewRedactor creates a redactor for the named patterns (email, phone, card).
+func NewRedactor(names []string) (*Redactor, error) {
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !isKnownPattern(name) {
+			return nil, fmt.Errorf("unknown redaction pattern: %s", name)
+		}
+		enabled[name] = true
+	}
+
+	r := &Redactor{}
+	for _, p := range patterns {
+		if enabled[p.name] {
+			r.patterns = append(r.patterns, p.pattern)
+		}
+	}
+	return r, nil
+}
+
+// Redact returns text with every enabled pattern masked.
+func (r *Redactor) Redact(text string) string {
+	for _, p := range r.patterns {
+		if p.valid == nil {
+			text = p.re.ReplaceAllString(text, p.mask)
+			continue
+		}
+		text = p.re.ReplaceAllStringFunc(text, func(match string) string {
+			if p.valid(match) {
+				return p.mask
+			}
+			return match
+		})
+	}
+	return text
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func isKnownPattern(name string) bool {
+	for _, p := range patterns {
+		if p.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package llmlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactor_Card(t *testing.T) {
+	r, err := NewRedactor([]string{PatternCard})
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+
+	got := r.Redact("My card is 4111 1111 1111 1111, expiring 12/27.")
+	if strings.Contains(got, "4111") {
+		t.Errorf("Card number was not masked: %q", got)
+	}
+	if got != "My card is [CARD], expiring 12/27." {
+		t.Errorf("Unexpected redaction %q", got)
+	}
+
+	// Dashes and no separators are masked too
+	for _, card := range []string{"5500-0000-0000-0004", "4012888888881881"} {
+		if got := r.Redact(card); got != "[CARD]" {
+			t.Errorf("Redact(%q) = %q, expected [CARD]", card, got)
+		}
+	}
+}
+
+func TestRedactor_CardRequiresLuhn(t *testing.T) {
+	r, _ := NewRedactor([]string{PatternCard})
+
+	text := "Order 1234 5678 9012 3456"
+	if got := r.Redact(text); got != text {
+		t.Errorf("Non-Luhn digit run should not be masked as a card, got %q", got)
+	}
+}
+
+func TestRedactor_EmailAndPhone(t *testing.T) {
+	r, err := NewRedactor([]string{"email", " Phone ", "card"})
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"write to ana.souza@example.com.br", "write to [EMAIL]"},
+		{"call +55 11 99988-7766 now", "call [PHONE] now"},
+		{"call (11) 3456-7890", "call [PHONE]"},
+		{"call +5511999887766", "call [PHONE]"},
+		{"nothing to hide", "nothing to hide"},
+	}
+
+	for _, tt := range tests {
+		if got := r.Redact(tt.input); got != tt.expected {
+			t.Errorf("Redact(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestRedactor_OnlyEnabledPatterns(t *testing.T) {
+	r, _ := NewRedactor([]string{PatternEmail})
+
+	text := "ana@example.com 4111 1111 1111 1111"
+	if got := r.Redact(text); got != "[EMAIL] 4111 1111 1111 1111" {
+		t.Errorf("Only emails should be masked, got %q", got)
+	}
+}
+
+func TestNewRedactor_UnknownPattern(t *testing.T) {
+	if _, err := NewRedactor([]string{"email", "ssn"}); err == nil {
+		t.Error("Expected error for unknown pattern")
+	}
+}
@@ -0,0 +1,69 @@
+// Package moderation contains the screening of conversation messages for
+// prompt injection and abusive content.
+package moderation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category is a kind of content moderation flags.
+type Category string
+
+const (
+	CategoryPromptInjection Category = "prompt_injection"
+	CategoryAbuse           Category = "abuse"
+	CategorySelfHarm        Category = "self_harm"
+	CategoryViolence        Category = "violence"
+)
+
+// Action is how a flagged message is handled.
+type Action string
+
+const (
+	ActionSanitize Action = "sanitize" // drop the flagged parts and continue
+	ActionRefuse   Action = "refuse"   // answer with a refusal and continue
+	ActionEscalate Action = "escalate" // hand the caller to a human
+)
+
+// ParseAction parses the configurable moderation action, defaulting to
+// refuse.
+func ParseAction(s string) Action {
+	switch a := Action(strings.ToLower(strings.TrimSpace(s))); a {
+	case ActionSanitize, ActionEscalate:
+		return a
+	}
+	return ActionRefuse
+}
+
+// Result is a provider's verdict on a message.
+type Result struct {
+	Categories []Category
+	// Sanitized is the message without the flagged parts, if the provider
+	// can locate them
+	Sanitized string
+}
+
+// Flagged reports whether the message hit a policy.
+func (r *Result) Flagged() bool {
+	return r != nil && len(r.Categories) > 0
+}
+
+// Flag is a message that hit a moderation policy. Like a guardrail
+// violation, it never holds the text.
+type Flag struct {
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	TenantID       uuid.UUID  `json:"tenant_id"`
+	AgentID        string     `json:"agent_id"`
+	Role           string     `json:"role"` // author of the flagged message
+	Categories     []Category `json:"categories"`
+	Provider       string     `json:"provider"`
+	Action         Action     `json:"action"`
+	OccurredAt     time.Time  `json:"occurred_at"`
+}
+
+// Placeholder replaces a refused or escalated user message in the history,
+// so it never reaches the model.
+const Placeholder = "[message removed by moderation]"
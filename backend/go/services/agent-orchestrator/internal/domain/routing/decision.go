@@ -0,0 +1,57 @@
+// Package routing contains call transfer decisions.
+package routing
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trigger is what caused a transfer decision.
+type Trigger string
+
+const (
+	TriggerIntent     Trigger = "intent"
+	TriggerEscalation Trigger = "escalation_trigger"
+	TriggerGuardrail  Trigger = "guardrail"
+	TriggerFlow       Trigger = "confirmation_failed"
+	TriggerModeration Trigger = "moderation"
+)
+
+// Transfer types accepted by voice-gateway.
+const (
+	TransferQueue    = "queue"
+	TransferAgent    = "agent"
+	TransferExternal = "external"
+)
+
+// Decision is the decision to transfer a conversation and its outcome.
+type Decision struct {
+	ConversationID uuid.UUID `json:"conversation_id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	AgentID        string    `json:"agent_id"`
+	CallID         uuid.UUID `json:"call_id"`
+	Trigger        Trigger   `json:"trigger"`
+	Intent         string    `json:"intent,omitempty"`
+	TransferType   string    `json:"transfer_type,omitempty"`
+	Target         string    `json:"target,omitempty"`
+	Reason         string    `json:"reason"`
+	Transferred    bool      `json:"transferred"` // voice-gateway accepted the transfer
+	Error          string    `json:"error,omitempty"`
+	DecidedAt      time.Time `json:"decided_at"`
+}
+
+// ParseTarget splits an allowed target of the form "type:target", e.g.
+// "queue:support" or "external:+5511999990000". Targets without a known type
+// prefix are queues.
+func ParseTarget(s string) (transferType, target string) {
+	s = strings.TrimSpace(s)
+	if prefix, rest, ok := strings.Cut(s, ":"); ok {
+		switch prefix {
+		case TransferQueue, TransferAgent, TransferExternal:
+			return prefix, rest
+		}
+	}
+	return TransferQueue, s
+}
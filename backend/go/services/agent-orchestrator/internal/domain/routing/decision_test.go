@@ -0,0 +1,22 @@
+package routing
+
+import "testing"
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		input, transferType, target string
+	}{
+		{"queue:support", TransferQueue, "support"},
+		{"agent:maria", TransferAgent, "maria"},
+		{"external:+5511999990000", TransferExternal, "+5511999990000"},
+		{"billing-queue", TransferQueue, "billing-queue"},
+		{"sip:100@pbx", TransferQueue, "sip:100@pbx"},
+	}
+
+	for _, tt := range tests {
+		transferType, target := ParseTarget(tt.input)
+		if transferType != tt.transferType || target != tt.target {
+			t.Errorf("ParseTarget(%q) = %q, %q; expected %q, %q", tt.input, transferType, target, tt.transferType, tt.target)
+		}
+	}
+}
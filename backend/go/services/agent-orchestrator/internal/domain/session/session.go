@@ -0,0 +1,278 @@
+// Package session contains the conversation session domain model.
+package session
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/agent"
+	"github.com/serphona/serphona/backend/go/services/agent-orchestrator/internal/domain/flow"
+)
+
+// ErrVersionConflict is returned when a session is saved over a newer version
+// written by another request or replica.
+var ErrVersionConflict = errors.New("session was updated concurrently")
+
+// Role is the author of a turn.
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Status represents the session lifecycle.
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusEnded  Status = "ended"
+)
+
+// Reasons a session ended other than the caller hanging up.
+const (
+	EndReasonSpendCap   = "spend_cap"
+	EndReasonMaxTurns   = "max_turns"
+	EndReasonInactivity = "inactivity"
+	EndReasonTransfer   = "transfer"
+)
+
+// Turn is a single message in the session history.
+type Turn struct {
+	Role      Role      `json:"role"`
+	Content   string    `json:"content"`
+	Tokens    int       `json:"tokens"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Session represents a conversation between a caller and an agent.
+// Older turns are folded into Summary to keep the history within the model
+// context window; Turns holds only the recent history verbatim.
+type Session struct {
+	ID       uuid.UUID `json:"id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+	AgentID  string    `json:"agent_id"`
+	CallID   uuid.UUID `json:"call_id"` // voice call carrying the session, if any
+	Status   Status    `json:"status"`
+
+	// Version counts the saves of the session; a save must carry the version
+	// it was loaded at, so concurrent updates can't drop each other's turns
+	Version int64 `json:"version"`
+
+	// Agent is the tenant agent configuration at session start, if known
+	Agent *agent.Config `json:"agent,omitempty"`
+
+	// CustomerID identifies a returning customer, e.g. by phone number, so
+	// they get the same agent variant every time
+	CustomerID string `json:"customer_id,omitempty"`
+
+	// Variables are the conversation context (caller number, tenant name,
+	// last call summary...) the system prompt can reference as {{.name}}
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// Variant is the agent variant assigned to the session, if the agent has
+	// any
+	Variant string `json:"variant,omitempty"`
+
+	// Flow tracks the agent confirmation steps, if any
+	Flow *flow.State `json:"flow,omitempty"`
+
+	// Context
+	Summary         string `json:"summary,omitempty"`
+	SummaryTokens   int    `json:"summary_tokens"`
+	SummarizedTurns int    `json:"summarized_turns"` // turns folded into the summary
+	Turns           []Turn `json:"turns"`
+	UserTurns       int    `json:"user_turns"` // user messages, including summarized ones
+
+	// Spend
+	Spend    Spend   `json:"spend"`
+	SpendCap float64 `json:"spend_cap,omitempty"` // USD, 0 means no cap
+
+	// Timestamps
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	EndReason string     `json:"end_reason,omitempty"`
+}
+
+// Spend accumulates the LLM tokens and cost of a session.
+type Spend struct {
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// TotalTokens returns prompt plus completion tokens.
+func (s Spend) TotalTokens() int {
+	return s.PromptTokens + s.CompletionTokens
+}
+
+// New creates a new active session.
+func New(tenantID uuid.UUID, agentID string) *Session {
+	now := time.Now().UTC()
+	return &Session{
+		ID:        uuid.New(),
+		TenantID:  tenantID,
+		AgentID:   agentID,
+		Status:    StatusActive,
+		Turns:     []Turn{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// SetVariables merges context variables into the session. An empty value
+// removes the variable.
+func (s *Session) SetVariables(vars map[string]string) {
+	for name, value := range vars {
+		if value == "" {
+			delete(s.Variables, name)
+			continue
+		}
+		if s.Variables == nil {
+			s.Variables = make(map[string]string)
+		}
+		s.Variables[name] = value
+	}
+}
+
+// AddTurn appends a turn to the history and returns it.
+func (s *Session) AddTurn(role Role, content string) Turn {
+	now := time.Now().UTC()
+	turn := Turn{
+		Role:      role,
+		Content:   content,
+		Tokens:    EstimateTokens(content),
+		CreatedAt: now,
+	}
+	s.Turns = append(s.Turns, turn)
+	if role == RoleUser {
+		s.UserTurns++
+	}
+	s.UpdatedAt = now
+	return turn
+}
+
+// AssignVariant assigns the session an agent variant. A returning customer is
+// assigned by their ID so they keep their variant; anyone else by the session
+// ID.
+func (s *Session) AssignVariant() {
+	if s.Agent == nil {
+		return
+	}
+	key := s.CustomerID
+	if key == "" {
+		key = s.ID.String()
+	}
+	if v := s.Agent.AssignVariant(key); v != nil {
+		s.Variant = v.Name
+	}
+}
+
+// AgentVariant returns the agent variant assigned to the session, or nil.
+func (s *Session) AgentVariant() *agent.Variant {
+	if s.Agent == nil {
+		return nil
+	}
+	return s.Agent.Variant(s.Variant)
+}
+
+// TokenCount returns the tokens used by the summary and the verbatim history.
+func (s *Session) TokenCount() int {
+	total := s.SummaryTokens
+	for _, t := range s.Turns {
+		total += t.Tokens
+	}
+	return total
+}
+
+// Compact replaces all but the last keep turns with the given summary.
+func (s *Session) Compact(summary string, keep int) {
+	if keep < 0 {
+		keep = 0
+	}
+	if keep > len(s.Turns) {
+		keep = len(s.Turns)
+	}
+
+	dropped := len(s.Turns) - keep
+	recent := make([]Turn, keep)
+	copy(recent, s.Turns[dropped:])
+
+	s.Turns = recent
+	s.Summary = summary
+	s.SummaryTokens = EstimateTokens(summary)
+	s.SummarizedTurns += dropped
+	s.UpdatedAt = time.Now().UTC()
+}
+
+// AddSpend adds the tokens and cost of an LLM call.
+func (s *Session) AddSpend(promptTokens, completionTokens int, cost float64) {
+	s.Spend.PromptTokens += promptTokens
+	s.Spend.CompletionTokens += completionTokens
+	s.Spend.Cost += cost
+	s.UpdatedAt = time.Now().UTC()
+}
+
+// SpendCapReached reports whether the session has a cap and reached it.
+func (s *Session) SpendCapReached() bool {
+	return s.SpendCap > 0 && s.Spend.Cost >= s.SpendCap
+}
+
+// End marks the session as ended.
+func (s *Session) End() {
+	now := time.Now().UTC()
+	s.Status = StatusEnded
+	s.EndedAt = &now
+	s.UpdatedAt = now
+}
+
+// EndWithReason marks the session as ended and records why.
+func (s *Session) EndWithReason(reason string) {
+	s.End()
+	s.EndReason = reason
+}
+
+// IsActive checks if the session is still active.
+func (s *Session) IsActive() bool {
+	return s.Status == StatusActive
+}
+
+// Usage reports the token usage of a session against its context budget.
+type Usage struct {
+	SessionID       uuid.UUID `json:"session_id"`
+	TotalTokens     int       `json:"total_tokens"`
+	SummaryTokens   int       `json:"summary_tokens"`
+	HistoryTokens   int       `json:"history_tokens"`
+	MaxTokens       int       `json:"max_tokens"`
+	Turns           int       `json:"turns"`
+	SummarizedTurns int       `json:"summarized_turns"`
+}
+
+// Usage returns the current token usage against maxTokens.
+func (s *Session) Usage(maxTokens int) Usage {
+	total := s.TokenCount()
+	return Usage{
+		SessionID:       s.ID,
+		TotalTokens:     total,
+		SummaryTokens:   s.SummaryTokens,
+		HistoryTokens:   total - s.SummaryTokens,
+		MaxTokens:       maxTokens,
+		Turns:           len(s.Turns),
+		SummarizedTurns: s.SummarizedTurns,
+	}
+}
+
+// EstimateTokens approximates the token count of text at about four
+// characters per token, which is close enough for budgeting the context.
+func EstimateTokens(text string) int {
+	chars := utf8.RuneCountInString(text)
+	if chars == 0 {
+		return 0
+	}
+	return (chars + 3) / 4
+}
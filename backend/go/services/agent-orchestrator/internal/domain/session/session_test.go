@@ -0,0 +1,109 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{"olá, você", 3}, // counts runes, not bytes
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.expected {
+			t.Errorf("EstimateTokens(%q) = %d, expected %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestSession_Compact(t *testing.T) {
+	s := New(uuid.New(), "agent-receptionist")
+	s.AddTurn(RoleUser, "first question")
+	s.AddTurn(RoleAssistant, "first answer")
+	s.AddTurn(RoleUser, "second question")
+
+	s.Compact("asked twice", 1)
+
+	if len(s.Turns) != 1 || s.Turns[0].Content != "second question" {
+		t.Errorf("Expected only the last turn to be kept, got %+v", s.Turns)
+	}
+	if s.SummarizedTurns != 2 {
+		t.Errorf("Expected 2 summarized turns, got %d", s.SummarizedTurns)
+	}
+
+	usage := s.Usage(100)
+	if usage.SummaryTokens != EstimateTokens("asked twice") {
+		t.Errorf("Unexpected summary tokens %d", usage.SummaryTokens)
+	}
+	if usage.TotalTokens != usage.SummaryTokens+usage.HistoryTokens {
+		t.Error("Total tokens should be summary plus history")
+	}
+	if usage.MaxTokens != 100 || usage.Turns != 1 {
+		t.Errorf("Unexpected usage %+v", usage)
+	}
+
+	// Keeping more turns than exist keeps them all
+	s.Compact("still asked twice", 5)
+	if len(s.Turns) != 1 || s.SummarizedTurns != 2 {
+		t.Errorf("Expected history unchanged, got %d turns, %d summarized", len(s.Turns), s.SummarizedTurns)
+	}
+}
+
+func TestSession_SpendCap(t *testing.T) {
+	s := New(uuid.New(), "agent-receptionist")
+	s.AddSpend(100, 50, 0.4)
+	if s.SpendCapReached() {
+		t.Error("Session without a cap should never reach it")
+	}
+
+	s.SpendCap = 1.0
+	s.AddSpend(200, 100, 0.5)
+	if s.SpendCapReached() {
+		t.Error("Cap should not be reached at 0.9")
+	}
+	if s.Spend.TotalTokens() != 450 {
+		t.Errorf("Expected 450 tokens, got %d", s.Spend.TotalTokens())
+	}
+
+	s.AddSpend(10, 10, 0.1)
+	if !s.SpendCapReached() {
+		t.Error("Cap should be reached at exactly 1.0")
+	}
+
+	s.EndWithReason(EndReasonSpendCap)
+	if s.IsActive() || s.EndReason != EndReasonSpendCap || s.EndedAt == nil {
+		t.Errorf("Unexpected ended session %+v", s)
+	}
+}
+
+func TestSession_UserTurns(t *testing.T) {
+	s := New(uuid.New(), "agent-receptionist")
+	s.AddTurn(RoleUser, "hi")
+	s.AddTurn(RoleAssistant, "hello")
+	s.AddTurn(RoleUser, "bye")
+
+	s.Compact("greetings", 0)
+	if s.UserTurns != 2 {
+		t.Errorf("Expected 2 user turns across compaction, got %d", s.UserTurns)
+	}
+}
+
+func TestSession_SetVariables(t *testing.T) {
+	s := New(uuid.New(), "agent-1")
+
+	s.SetVariables(map[string]string{"caller_number": "+5511999887766", "tenant_name": "Clínica Sorriso"})
+	s.SetVariables(map[string]string{"tenant_name": "", "did": "+551133330000"})
+
+	if len(s.Variables) != 2 || s.Variables["caller_number"] != "+5511999887766" || s.Variables["did"] != "+551133330000" {
+		t.Errorf("Expected tenant_name removed and did added, got %v", s.Variables)
+	}
+}
@@ -0,0 +1,75 @@
+// Package summary contains post-call conversation summaries.
+package summary
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Disposition is the outcome of a conversation.
+type Disposition string
+
+const (
+	DispositionResolved    Disposition = "resolved"
+	DispositionUnresolved  Disposition = "unresolved"
+	DispositionFollowUp    Disposition = "follow_up"
+	DispositionTransferred Disposition = "transferred"
+	DispositionAbandoned   Disposition = "abandoned"
+	DispositionOther       Disposition = "other"
+)
+
+// Dispositions lists the outcomes the model may choose from.
+var Dispositions = []Disposition{
+	DispositionResolved,
+	DispositionUnresolved,
+	DispositionFollowUp,
+	DispositionTransferred,
+	DispositionAbandoned,
+	DispositionOther,
+}
+
+// ParseDisposition maps a model answer to a known disposition. Anything
+// unrecognized is DispositionOther.
+func ParseDisposition(s string) Disposition {
+	normalized := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "_")
+	normalized = strings.ReplaceAll(normalized, " ", "_")
+	for _, d := range Dispositions {
+		if string(d) == normalized {
+			return d
+		}
+	}
+	return DispositionOther
+}
+
+// Summary is the post-call summary of a conversation.
+type Summary struct {
+	ConversationID uuid.UUID   `json:"conversation_id"`
+	TenantID       uuid.UUID   `json:"tenant_id"`
+	AgentID        string      `json:"agent_id"`
+	CallID         uuid.UUID   `json:"call_id"`
+	CustomerID     string      `json:"customer_id,omitempty"` // returning customer, if known
+	Variant        string      `json:"variant,omitempty"`     // agent variant, if any
+	Summary        string      `json:"summary"`
+	Disposition    Disposition `json:"disposition"`
+	Model          string      `json:"model"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// Parse reads the summary and disposition from the model output. The model is
+// asked for a JSON object; free text is kept whole as the summary.
+func Parse(content string) (string, Disposition) {
+	content = strings.TrimSpace(content)
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(content, "```json"), "```"), "```")
+
+	var out struct {
+		Summary     string `json:"summary"`
+		Disposition string `json:"disposition"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(trimmed)), &out); err != nil || out.Summary == "" {
+		return content, DispositionOther
+	}
+	return strings.TrimSpace(out.Summary), ParseDisposition(out.Disposition)
+}
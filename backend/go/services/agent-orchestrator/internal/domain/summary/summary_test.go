@@ -0,0 +1,49 @@
+package summary
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		summary     string
+		disposition Disposition
+	}{
+		{
+			name:        "json",
+			content:     `{"summary": "Caller rescheduled the appointment.", "disposition": "resolved"}`,
+			summary:     "Caller rescheduled the appointment.",
+			disposition: DispositionResolved,
+		},
+		{
+			name:        "fenced json",
+			content:     "```json\n{\"summary\": \"Needs a callback.\", \"disposition\": \"Follow-up\"}\n```",
+			summary:     "Needs a callback.",
+			disposition: DispositionFollowUp,
+		},
+		{
+			name:        "unknown disposition",
+			content:     `{"summary": "Asked about prices.", "disposition": "curious"}`,
+			summary:     "Asked about prices.",
+			disposition: DispositionOther,
+		},
+		{
+			name:        "free text",
+			content:     "  The caller asked for the opening hours.  ",
+			summary:     "The caller asked for the opening hours.",
+			disposition: DispositionOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, disposition := Parse(tt.content)
+			if summary != tt.summary {
+				t.Errorf("summary = %q, want %q", summary, tt.summary)
+			}
+			if disposition != tt.disposition {
+				t.Errorf("disposition = %q, want %q", disposition, tt.disposition)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+-- =============================================================================
+-- Migration: 000001_create_agents
+-- Description: Agents registered by each tenant in the orchestrator
+-- =============================================================================
+
+CREATE TABLE agents (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    model VARCHAR(100) NOT NULL,
+    system_prompt TEXT NOT NULL DEFAULT '',
+    tools JSONB NOT NULL DEFAULT '[]',
+    routing JSONB NOT NULL DEFAULT '{}',
+    safety JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- Agents are referenced by name within a tenant; also serves listing
+    CONSTRAINT agents_tenant_name_unique UNIQUE (tenant_id, name)
+);
+
+COMMENT ON TABLE agents IS 'Agent definitions registered through the orchestrator API, scoped per tenant';
+COMMENT ON COLUMN agents.tools IS 'tools-gateway tool IDs the agent may call';
@@ -0,0 +1,8 @@
+-- =============================================================================
+-- Migration: 000002_add_agent_graph
+-- Description: Declarative router to specialist graph of an agent
+-- =============================================================================
+
+ALTER TABLE agents ADD COLUMN graph JSONB;
+
+COMMENT ON COLUMN agents.graph IS 'Nodes a turn is routed through, from a router agent to specialists; NULL for single agents';
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/anomaly"
+)
+
+// ==============================================================================
+// Alert Settings Handlers
+// ==============================================================================
+
+func getAlertSettings(settings *anomaly.GormSettingsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		s, err := settings.Get(c.Request.Context(), tenantID)
+		if err != nil {
+			log.Printf("Failed to load alert settings: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"settings": s, "metrics": anomaly.Metrics})
+	}
+}
+
+func putAlertSettings(settings *anomaly.GormSettingsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		var s anomaly.Settings
+		if err := c.ShouldBindJSON(&s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := s.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := settings.Put(c.Request.Context(), tenantID, &s); err != nil {
+			log.Printf("Failed to store alert settings: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"settings": s})
+	}
+}
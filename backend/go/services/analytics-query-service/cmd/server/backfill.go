@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/rollup"
+)
+
+// runBackfill computes historical rollups:
+//
+//	server backfill-rollups -from 2025-01-01T00:00:00Z [-to ...] [-granularity hourly|daily|all]
+//
+// Buckets already rolled up are recomputed and replaced, so the command can
+// be rerun over overlapping ranges.
+func runBackfill(args []string) {
+	flags := flag.NewFlagSet("backfill-rollups", flag.ExitOnError)
+	fromFlag := flags.String("from", "", "start of the range, RFC 3339 (required)")
+	toFlag := flags.String("to", "", "end of the range, RFC 3339 (default: now)")
+	granularity := flags.String("granularity", "all", "hourly, daily or all")
+	flags.Parse(args)
+
+	from, err := time.Parse(time.RFC3339, *fromFlag)
+	if err != nil {
+		log.Fatalf("Invalid -from: %v", err)
+	}
+	to := time.Now().UTC()
+	if *toFlag != "" {
+		if to, err = time.Parse(time.RFC3339, *toFlag); err != nil {
+			log.Fatalf("Invalid -to: %v", err)
+		}
+	}
+
+	granularities := []rollup.Granularity{rollup.Hourly, rollup.Daily}
+	if *granularity != "all" {
+		g, err := rollup.ParseGranularity(*granularity)
+		if err != nil {
+			log.Fatalf("Invalid -granularity: %v", err)
+		}
+		granularities = []rollup.Granularity{g}
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	chConn, err := openClickHouse()
+	if err != nil {
+		logger.Fatal("Failed to open ClickHouse connection", zap.Error(err))
+	}
+	defer chConn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rollups := rollup.NewService(rollup.NewClickHouseStore(chConn), rollup.DefaultConfig(), logger)
+	for _, g := range granularities {
+		if err := rollups.Backfill(ctx, g, from, to); err != nil {
+			logger.Fatal("Backfill failed", zap.Error(err))
+		}
+	}
+
+	log.Println("Backfill completed")
+}
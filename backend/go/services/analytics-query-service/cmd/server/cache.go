@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/querycache"
+)
+
+// ==============================================================================
+// Query Result Cache
+// ==============================================================================
+
+// cachedResponse buffers a response so it can be cached once written.
+type cachedResponse struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *cachedResponse) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cachedResponse) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// cacheResults serves GET query results from the cache, marking responses
+// with X-Cache, and caches successful ones. ?nocache=true skips the lookup
+// and refreshes the cached result. A nil cache caches nothing.
+func cacheResults(cache *querycache.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.Query("tenant_id")
+		if cache == nil || c.Request.Method != http.MethodGet || tenantID == "" {
+			c.Next()
+			return
+		}
+
+		req := querycache.Request{
+			TenantID: tenantID,
+			Endpoint: c.FullPath(),
+			Params:   c.Request.URL.Query(),
+		}
+		if c.Query("nocache") != "true" {
+			if body, ok := cache.Get(c.Request.Context(), req); ok {
+				c.Header("X-Cache", "HIT")
+				c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Header("X-Cache", "MISS")
+		w := &cachedResponse{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		if w.Status() == http.StatusOK {
+			cache.Set(c.Request.Context(), req, w.body.Bytes())
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/export"
+)
+
+// ==============================================================================
+// Export Destination Handlers
+// ==============================================================================
+
+func getExportDestination(settings *export.GormSettingsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		dest, err := settings.Get(c.Request.Context(), tenantID)
+		if err != nil {
+			writeExportError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dest.Redacted())
+	}
+}
+
+func putExportDestination(settings *export.GormSettingsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		var dest export.Destination
+		if err := c.ShouldBindJSON(&dest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if dest.SecretAccessKey == export.RedactedSecret {
+			current, err := settings.Get(c.Request.Context(), tenantID)
+			if err != nil {
+				writeExportError(c, err)
+				return
+			}
+			dest.SecretAccessKey = current.SecretAccessKey
+		}
+		if err := dest.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := settings.Put(c.Request.Context(), tenantID, &dest); err != nil {
+			writeExportError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dest.Redacted())
+	}
+}
+
+func deleteExportDestination(settings *export.GormSettingsStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		if err := settings.Delete(c.Request.Context(), tenantID); err != nil {
+			writeExportError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// writeExportError maps export errors to HTTP responses.
+func writeExportError(c *gin.Context, err error) {
+	if errors.Is(err, export.ErrNotConfigured) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Printf("Export destination request failed: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+}
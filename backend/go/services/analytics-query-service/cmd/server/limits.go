@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/querylimit"
+)
+
+// ==============================================================================
+// Query Limits
+// ==============================================================================
+
+// limitQueries admits a request to a query endpoint within the tenant's plan
+// limits, answering 429 with Retry-After when the tenant is over them. A nil
+// limiter admits everything. Requests without tenant_id are left to the
+// handler to reject.
+func limitQueries(limiter *querylimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.Query("tenant_id")
+		if limiter == nil || tenantID == "" {
+			c.Next()
+			return
+		}
+
+		release, err := limiter.Acquire(c.Request.Context(), tenantID)
+		var limitErr *querylimit.LimitError
+		if errors.As(err, &limitErr) {
+			seconds := int(math.Ceil(limitErr.RetryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		defer release()
+
+		c.Next()
+	}
+}
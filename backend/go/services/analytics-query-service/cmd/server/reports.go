@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/report"
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/rollup"
+)
+
+// ==============================================================================
+// Saved Query Handlers
+// ==============================================================================
+
+// createReportRequest is the body of POST /reports.
+type createReportRequest struct {
+	Name       string            `json:"name" binding:"required"`
+	Endpoint   string            `json:"endpoint" binding:"required"`
+	Params     map[string]string `json:"params"`
+	Schedule   string            `json:"schedule"`
+	WebhookURL string            `json:"webhook_url"`
+}
+
+func createReport(reports *report.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		var req createReportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		q := &report.SavedQuery{
+			TenantID:   tenantID,
+			Name:       req.Name,
+			Endpoint:   req.Endpoint,
+			Params:     req.Params,
+			Schedule:   req.Schedule,
+			WebhookURL: req.WebhookURL,
+		}
+		if err := reports.Create(c.Request.Context(), q); err != nil {
+			writeReportError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, q)
+	}
+}
+
+func listReports(reports *report.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		queries, err := reports.List(c.Request.Context(), tenantID)
+		if err != nil {
+			writeReportError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"reports": queries, "endpoints": reports.Endpoints()})
+	}
+}
+
+func getReport(reports *report.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		q, err := reports.Get(c.Request.Context(), tenantID, c.Param("id"))
+		if err != nil {
+			writeReportError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, q)
+	}
+}
+
+func deleteReport(reports *report.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		if err := reports.Delete(c.Request.Context(), tenantID, c.Param("id")); err != nil {
+			writeReportError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func runReport(reports *report.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, ok := requireTenant(c)
+		if !ok {
+			return
+		}
+
+		result, err := reports.Run(c.Request.Context(), tenantID, c.Param("id"))
+		if err != nil {
+			writeReportError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// requireTenant reads ?tenant_id=, answering 400 when it is missing.
+func requireTenant(c *gin.Context) (string, bool) {
+	tenantID := c.Query("tenant_id")
+	if tenantID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tenant_id is required"})
+		return "", false
+	}
+	return tenantID, true
+}
+
+// writeReportError maps report errors to HTTP responses.
+func writeReportError(c *gin.Context, err error) {
+	var validationErr *report.ValidationError
+	var rangeErr *rangeError
+	switch {
+	case errors.Is(err, report.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, report.ErrUnknownEndpoint), errors.As(err, &validationErr), errors.As(err, &rangeErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, rollup.ErrQueryTimeout):
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": rollup.ErrQueryTimeout.Error()})
+	default:
+		log.Printf("Saved query request failed: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
@@ -1,11 +1,23 @@
 module github.com/serphona/serphona/backend/go/services/analytics-query-service
 
-go 1.21
+go 1.23
 
 require (
 	github.com/gin-gonic/gin v1.9.1
 	github.com/ClickHouse/clickhouse-go/v2 v2.15.0
+	github.com/google/uuid v1.4.0
+	github.com/prometheus/client_golang v1.17.0
+	github.com/redis/go-redis/v9 v9.3.0
 	go.uber.org/zap v1.26.0
 	gorm.io/gorm v1.25.5
 	gorm.io/driver/postgres v1.5.4
+	github.com/serphona/serphona/backend/go/libs/platform-core v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-entitlements v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-events v0.0.0
 )
+
+replace github.com/serphona/serphona/backend/go/libs/platform-events => ../../libs/platform-events
+
+replace github.com/serphona/serphona/backend/go/libs/platform-core => ../../libs/platform-core
+
+replace github.com/serphona/serphona/backend/go/libs/platform-entitlements => ../../libs/platform-entitlements
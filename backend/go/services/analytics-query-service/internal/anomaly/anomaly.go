@@ -0,0 +1,158 @@
+// Package anomaly alerts when a tenant's call metrics spike. On a schedule it
+// compares the last complete window of each metric against a baseline made
+// of the preceding windows (mean and standard deviation) and fires an alert
+// when the value breaches the z-score or absolute threshold.
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/rollup"
+)
+
+// Metric is a monitored call metric.
+type Metric string
+
+// Monitored metrics.
+const (
+	CallVolume        Metric = "call_volume"
+	AbandonRate       Metric = "abandon_rate"
+	NegativeSentiment Metric = "negative_sentiment_rate"
+)
+
+// Metrics lists every monitored metric.
+var Metrics = []Metric{CallVolume, AbandonRate, NegativeSentiment}
+
+// value returns the metric of a window. Rates need at least minSamples
+// calls to be meaningful; ok is false otherwise.
+func (m Metric) value(a rollup.Aggregate, minSamples uint64) (v float64, ok bool) {
+	switch m {
+	case CallVolume:
+		return float64(a.Calls), true
+	case AbandonRate:
+		return a.AbandonRate(), a.Calls >= minSamples
+	case NegativeSentiment:
+		return a.NegativeSentimentRate(), a.SentimentCount >= minSamples
+	}
+	return 0, false
+}
+
+// minStdDev is the smallest deviation assumed for a metric, so a flat
+// baseline doesn't turn every small change into an infinite z-score.
+func (m Metric) minStdDev(mean float64) float64 {
+	if m == CallVolume {
+		// Call counts are roughly Poisson: variance ≈ mean
+		return math.Max(1, math.Sqrt(mean))
+	}
+	return 0.02
+}
+
+// Threshold decides when a metric is anomalous.
+type Threshold struct {
+	Disabled bool     `json:"disabled,omitempty"`
+	ZScore   float64  `json:"z_score,omitempty"`  // fire when the value is this many deviations above the baseline
+	Absolute *float64 `json:"absolute,omitempty"` // fire when the value exceeds this, whatever the baseline
+}
+
+// Settings are a tenant's alerting settings.
+type Settings struct {
+	Thresholds map[Metric]Threshold `json:"thresholds,omitempty"`
+	WebhookURL string               `json:"webhook_url,omitempty"`
+}
+
+// Validate checks thresholds and the webhook URL.
+func (s *Settings) Validate() error {
+	for m, t := range s.Thresholds {
+		known := false
+		for _, metric := range Metrics {
+			known = known || m == metric
+		}
+		if !known {
+			return fmt.Errorf("unknown metric %q", m)
+		}
+		if t.ZScore < 0 {
+			return fmt.Errorf("%s: z_score must not be negative", m)
+		}
+		if t.Absolute != nil && *t.Absolute < 0 {
+			return fmt.Errorf("%s: absolute must not be negative", m)
+		}
+	}
+	if s.WebhookURL != "" {
+		u, err := url.Parse(s.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook_url must be an http(s) URL")
+		}
+	}
+	return nil
+}
+
+// threshold returns the tenant's threshold for m, falling back to the
+// default z-score.
+func (s *Settings) threshold(m Metric, defaults Threshold) Threshold {
+	t, ok := s.Thresholds[m]
+	if !ok {
+		return defaults
+	}
+	if t.ZScore == 0 {
+		t.ZScore = defaults.ZScore
+	}
+	return t
+}
+
+// Threshold kinds reported in alerts.
+const (
+	ByZScore   = "z_score"
+	ByAbsolute = "absolute"
+)
+
+// Alert is a detected anomaly.
+type Alert struct {
+	TenantID    string    `json:"tenant_id"`
+	Metric      Metric    `json:"metric"`
+	Value       float64   `json:"value"`
+	Baseline    float64   `json:"baseline"`
+	StdDev      float64   `json:"stddev"`
+	ZScore      float64   `json:"z_score"`
+	Threshold   string    `json:"threshold"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// minBaseline is the number of baseline windows needed for the z-score test.
+const minBaseline = 3
+
+// detect checks value against the baseline values. The absolute threshold
+// applies even without a baseline.
+func detect(m Metric, value float64, baseline []float64, t Threshold) (alert Alert, fired bool) {
+	alert.Value = value
+
+	if len(baseline) > 0 {
+		var sum float64
+		for _, v := range baseline {
+			sum += v
+		}
+		mean := sum / float64(len(baseline))
+
+		var sq float64
+		for _, v := range baseline {
+			sq += (v - mean) * (v - mean)
+		}
+		alert.Baseline = mean
+		alert.StdDev = math.Sqrt(sq / float64(len(baseline)))
+		alert.ZScore = (value - mean) / math.Max(alert.StdDev, m.minStdDev(mean))
+	}
+
+	if t.Absolute != nil && value > *t.Absolute {
+		alert.Threshold = ByAbsolute
+		return alert, true
+	}
+	if len(baseline) >= minBaseline && t.ZScore > 0 && alert.ZScore >= t.ZScore {
+		alert.Threshold = ByZScore
+		return alert, true
+	}
+	return alert, false
+}
@@ -0,0 +1,232 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/rollup"
+)
+
+// Source provides tenants' hourly aggregates.
+type Source interface {
+	Tenants(ctx context.Context, g rollup.Granularity, from, to time.Time) ([]string, error)
+	Query(ctx context.Context, g rollup.Granularity, tenantID string, from, to time.Time) ([]rollup.Aggregate, error)
+}
+
+// SettingsStore returns tenants' alerting settings; tenants without
+// settings get the zero Settings.
+type SettingsStore interface {
+	Get(ctx context.Context, tenantID string) (*Settings, error)
+}
+
+// Notifier delivers alerts.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert, settings *Settings) error
+}
+
+// Config controls the evaluator.
+type Config struct {
+	Interval        time.Duration // how often metrics are evaluated
+	Window          time.Duration // size of the evaluated window, in whole hours
+	BaselineWindows int           // windows before the current one forming the baseline
+	MinSamples      uint64        // calls needed in a window to evaluate rates
+	Default         Threshold     // used for metrics a tenant hasn't configured
+}
+
+// DefaultConfig returns the alerting defaults.
+func DefaultConfig() Config {
+	return Config{
+		Interval:        5 * time.Minute,
+		Window:          time.Hour,
+		BaselineWindows: 24,
+		MinSamples:      10,
+		Default:         Threshold{ZScore: 3},
+	}
+}
+
+// Evaluator evaluates every active tenant's metrics.
+type Evaluator struct {
+	source    Source
+	settings  SettingsStore
+	notifiers []Notifier
+	config    Config
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu    sync.Mutex
+	fired map[string]time.Time // tenant/metric -> end of the window last alerted
+}
+
+// NewEvaluator creates an Evaluator.
+func NewEvaluator(source Source, settings SettingsStore, notifiers []Notifier, config Config, logger *zap.Logger) *Evaluator {
+	defaults := DefaultConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Window < time.Hour {
+		config.Window = defaults.Window
+	}
+	config.Window = config.Window.Truncate(time.Hour)
+	if config.BaselineWindows <= 0 {
+		config.BaselineWindows = defaults.BaselineWindows
+	}
+	if config.MinSamples == 0 {
+		config.MinSamples = defaults.MinSamples
+	}
+	if config.Default.ZScore <= 0 {
+		config.Default.ZScore = defaults.Default.ZScore
+	}
+
+	return &Evaluator{
+		source:    source,
+		settings:  settings,
+		notifiers: notifiers,
+		config:    config,
+		logger:    logger,
+		now:       time.Now,
+		fired:     make(map[string]time.Time),
+	}
+}
+
+// Evaluate checks the last complete window of every active tenant and
+// notifies new alerts. An alert fires once per tenant, metric and window.
+func (e *Evaluator) Evaluate(ctx context.Context) ([]Alert, error) {
+	end := rollup.Hourly.Truncate(e.now())
+	start := end.Add(-time.Duration(e.config.BaselineWindows+1) * e.config.Window)
+
+	tenants, err := e.source.Tenants(ctx, rollup.Hourly, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	var alerts []Alert
+	for _, tenantID := range tenants {
+		tenantAlerts, err := e.evaluateTenant(ctx, tenantID, start, end)
+		if err != nil {
+			e.logger.Error("failed to evaluate tenant metrics", zap.String("tenant_id", tenantID), zap.Error(err))
+			continue
+		}
+		alerts = append(alerts, tenantAlerts...)
+	}
+	return alerts, nil
+}
+
+// evaluateTenant checks a tenant's metrics for [start, end).
+func (e *Evaluator) evaluateTenant(ctx context.Context, tenantID string, start, end time.Time) ([]Alert, error) {
+	aggregates, err := e.source.Query(ctx, rollup.Hourly, tenantID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := e.settings.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert settings: %w", err)
+	}
+
+	windows := e.windows(aggregates, start)
+	current, history := windows[len(windows)-1], windows[:len(windows)-1]
+	windowStart := end.Add(-e.config.Window)
+
+	var alerts []Alert
+	for _, m := range Metrics {
+		threshold := settings.threshold(m, e.config.Default)
+		if threshold.Disabled {
+			continue
+		}
+		value, ok := m.value(current, e.config.MinSamples)
+		if !ok {
+			continue
+		}
+
+		var baseline []float64
+		for _, w := range history {
+			if v, ok := m.value(w, e.config.MinSamples); ok {
+				baseline = append(baseline, v)
+			}
+		}
+
+		alert, fired := detect(m, value, baseline, threshold)
+		if !fired || !e.markFired(tenantID, m, end) {
+			continue
+		}
+
+		alert.TenantID = tenantID
+		alert.Metric = m
+		alert.WindowStart = windowStart
+		alert.WindowEnd = end
+		alert.DetectedAt = e.now().UTC()
+		alerts = append(alerts, alert)
+
+		for _, n := range e.notifiers {
+			if err := n.Notify(ctx, alert, settings); err != nil {
+				e.logger.Error("failed to notify anomaly",
+					zap.String("tenant_id", tenantID),
+					zap.String("metric", string(m)),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+	return alerts, nil
+}
+
+// windows sums hourly aggregates into BaselineWindows+1 windows from start;
+// the last one is the current window.
+func (e *Evaluator) windows(aggregates []rollup.Aggregate, start time.Time) []rollup.Aggregate {
+	windows := make([]rollup.Aggregate, e.config.BaselineWindows+1)
+	for _, a := range aggregates {
+		i := int(a.Bucket.Sub(start) / e.config.Window)
+		if i < 0 || i >= len(windows) {
+			continue
+		}
+		w := &windows[i]
+		w.Calls += a.Calls
+		w.DurationSeconds += a.DurationSeconds
+		w.SentimentSum += a.SentimentSum
+		w.SentimentCount += a.SentimentCount
+		w.Conversations += a.Conversations
+		w.Resolved += a.Resolved
+		w.Abandoned += a.Abandoned
+		w.NegativeSentiment += a.NegativeSentiment
+	}
+	return windows
+}
+
+// markFired records an alert for the window ending at end and reports
+// whether it is new.
+func (e *Evaluator) markFired(tenantID string, m Metric, end time.Time) bool {
+	key := tenantID + "/" + string(m)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if last, ok := e.fired[key]; ok && !last.Before(end) {
+		return false
+	}
+	e.fired[key] = end
+	return true
+}
+
+// Run evaluates every Interval until ctx is done.
+func (e *Evaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			alerts, err := e.Evaluate(ctx)
+			if err != nil {
+				e.logger.Error("anomaly evaluation failed", zap.Error(err))
+				continue
+			}
+			if len(alerts) > 0 {
+				e.logger.Info("anomalies detected", zap.Int("alerts", len(alerts)))
+			}
+		}
+	}
+}
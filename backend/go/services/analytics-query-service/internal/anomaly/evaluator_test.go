@@ -0,0 +1,269 @@
+package anomaly
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/rollup"
+)
+
+var now = time.Date(2025, 3, 10, 14, 10, 0, 0, time.UTC)
+
+// series is a tenant's hourly aggregates, oldest first, ending at the last
+// complete hour before now.
+type series map[string][]rollup.Aggregate
+
+func (s series) Tenants(context.Context, rollup.Granularity, time.Time, time.Time) ([]string, error) {
+	var out []string
+	for tenantID := range s {
+		out = append(out, tenantID)
+	}
+	return out, nil
+}
+
+func (s series) Query(_ context.Context, _ rollup.Granularity, tenantID string, from, to time.Time) ([]rollup.Aggregate, error) {
+	var out []rollup.Aggregate
+	for _, a := range s[tenantID] {
+		if !a.Bucket.Before(from) && a.Bucket.Before(to) {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// hourly builds aggregates ending at the last complete hour, from calls and
+// abandoned counts per hour.
+func hourly(calls, abandoned []uint64) []rollup.Aggregate {
+	end := rollup.Hourly.Truncate(now)
+	out := make([]rollup.Aggregate, len(calls))
+	for i := range calls {
+		out[i] = rollup.Aggregate{
+			Bucket:    end.Add(-time.Duration(len(calls)-i) * time.Hour),
+			Calls:     calls[i],
+			Abandoned: abandoned[i],
+		}
+	}
+	return out
+}
+
+// steady returns n hours of normal traffic with a little noise.
+func steady(n int) (calls, abandoned []uint64) {
+	for i := 0; i < n; i++ {
+		calls = append(calls, 40+uint64(i%5))
+		abandoned = append(abandoned, 2+uint64(i%2))
+	}
+	return calls, abandoned
+}
+
+type settingsMap map[string]*Settings
+
+func (s settingsMap) Get(_ context.Context, tenantID string) (*Settings, error) {
+	if settings, ok := s[tenantID]; ok {
+		return settings, nil
+	}
+	return &Settings{}, nil
+}
+
+type recorder struct {
+	alerts []Alert
+}
+
+func (r *recorder) Notify(_ context.Context, alert Alert, _ *Settings) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func newTestEvaluator(src Source, settings SettingsStore, n Notifier) *Evaluator {
+	e := NewEvaluator(src, settings, []Notifier{n}, Config{Window: time.Hour, BaselineWindows: 24, MinSamples: 10}, zap.NewNop())
+	e.now = func() time.Time { return now }
+	return e
+}
+
+func TestVolumeSpikeFiresAlert(t *testing.T) {
+	calls, abandoned := steady(24)
+	calls, abandoned = append(calls, 150), append(abandoned, 3)
+
+	rec := &recorder{}
+	e := newTestEvaluator(series{"tenant-1": hourly(calls, abandoned)}, settingsMap{}, rec)
+
+	alerts, err := e.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if len(alerts) != 1 || len(rec.alerts) != 1 {
+		t.Fatalf("alerts = %+v, want one call volume alert", alerts)
+	}
+
+	a := alerts[0]
+	if a.TenantID != "tenant-1" || a.Metric != CallVolume || a.Threshold != ByZScore || a.Value != 150 {
+		t.Errorf("alert = %+v", a)
+	}
+	if a.Baseline < 40 || a.Baseline > 44 || a.ZScore < 3 {
+		t.Errorf("baseline = %v, z = %v", a.Baseline, a.ZScore)
+	}
+	if !a.WindowEnd.Equal(time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)) || a.WindowEnd.Sub(a.WindowStart) != time.Hour {
+		t.Errorf("window = %v..%v", a.WindowStart, a.WindowEnd)
+	}
+}
+
+func TestAbandonRateSpikeFiresAlert(t *testing.T) {
+	calls, abandoned := steady(24)
+	calls, abandoned = append(calls, 42), append(abandoned, 25)
+
+	rec := &recorder{}
+	e := newTestEvaluator(series{"tenant-1": hourly(calls, abandoned)}, settingsMap{}, rec)
+
+	alerts, err := e.Evaluate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Metric != AbandonRate {
+		t.Fatalf("alerts = %+v, want one abandon rate alert", alerts)
+	}
+}
+
+func TestSteadySeriesDoesNotAlert(t *testing.T) {
+	calls, abandoned := steady(25)
+	e := newTestEvaluator(series{"tenant-1": hourly(calls, abandoned)}, settingsMap{}, &recorder{})
+
+	alerts, err := e.Evaluate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("alerts = %+v, want none", alerts)
+	}
+}
+
+func TestAlertFiresOncePerWindow(t *testing.T) {
+	calls, abandoned := steady(24)
+	calls, abandoned = append(calls, 150), append(abandoned, 3)
+
+	rec := &recorder{}
+	e := newTestEvaluator(series{"tenant-1": hourly(calls, abandoned)}, settingsMap{}, rec)
+
+	for i := 0; i < 3; i++ {
+		if _, err := e.Evaluate(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(rec.alerts) != 1 {
+		t.Errorf("notifications = %d, want 1", len(rec.alerts))
+	}
+}
+
+func TestTenantThresholds(t *testing.T) {
+	calls, abandoned := steady(24)
+	calls, abandoned = append(calls, 150), append(abandoned, 3)
+	limit := 0.01
+
+	tests := []struct {
+		name     string
+		settings *Settings
+		want     []Metric
+	}{
+		{"disabled", &Settings{Thresholds: map[Metric]Threshold{CallVolume: {Disabled: true}}}, nil},
+		{"higher z-score", &Settings{Thresholds: map[Metric]Threshold{CallVolume: {ZScore: 1000}}}, nil},
+		{"absolute", &Settings{Thresholds: map[Metric]Threshold{AbandonRate: {ZScore: 1000, Absolute: &limit}}}, []Metric{CallVolume, AbandonRate}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEvaluator(series{"tenant-1": hourly(calls, abandoned)}, settingsMap{"tenant-1": tt.settings}, &recorder{})
+			alerts, err := e.Evaluate(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(alerts) != len(tt.want) {
+				t.Fatalf("alerts = %+v, want %v", alerts, tt.want)
+			}
+			for i, m := range tt.want {
+				if alerts[i].Metric != m {
+					t.Errorf("alert %d metric = %s, want %s", i, alerts[i].Metric, m)
+				}
+			}
+		})
+	}
+}
+
+func TestRatesNeedEnoughCalls(t *testing.T) {
+	calls, abandoned := steady(24)
+	// 5 of 6 calls abandoned, but too few calls to judge the rate
+	calls, abandoned = append(calls, 6), append(abandoned, 5)
+
+	e := newTestEvaluator(series{"tenant-1": hourly(calls, abandoned)}, settingsMap{}, &recorder{})
+	alerts, err := e.Evaluate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range alerts {
+		if a.Metric == AbandonRate {
+			t.Errorf("abandon rate alert on %v calls", a.Value)
+		}
+	}
+}
+
+type publisherFunc func(ctx context.Context, topic string, event *types.Event) error
+
+func (f publisherFunc) Publish(ctx context.Context, topic string, event *types.Event) error {
+	return f(ctx, topic, event)
+}
+
+func TestNotifiers(t *testing.T) {
+	alert := Alert{TenantID: "tenant-1", Metric: CallVolume, Value: 150, Threshold: ByZScore}
+
+	var topic string
+	var event *types.Event
+	publisher := publisherFunc(func(_ context.Context, tp string, e *types.Event) error {
+		topic, event = tp, e
+		return nil
+	})
+	if err := NewEventNotifier(publisher, "analytics-query-service").Notify(context.Background(), alert, &Settings{}); err != nil {
+		t.Fatalf("EventNotifier.Notify() error = %v", err)
+	}
+	if topic != "analytics.metric.anomaly" || event.TenantID != "tenant-1" {
+		t.Errorf("published %s %+v", topic, event)
+	}
+
+	var got map[string]any
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer webhook.Close()
+
+	n := NewWebhookNotifier(webhook.Client())
+	if err := n.Notify(context.Background(), alert, &Settings{}); err != nil || got != nil {
+		t.Fatalf("Notify() without webhook = %v, posted %v", err, got)
+	}
+	if err := n.Notify(context.Background(), alert, &Settings{WebhookURL: webhook.URL}); err != nil {
+		t.Fatalf("WebhookNotifier.Notify() error = %v", err)
+	}
+	if got["type"] != "analytics.metric.anomaly" || got["metric"] != "call_volume" {
+		t.Errorf("webhook body = %v", got)
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	negative := -1.0
+	invalid := []Settings{
+		{Thresholds: map[Metric]Threshold{"latency": {}}},
+		{Thresholds: map[Metric]Threshold{CallVolume: {ZScore: -1}}},
+		{Thresholds: map[Metric]Threshold{CallVolume: {Absolute: &negative}}},
+		{WebhookURL: "not a url"},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("Validate(%+v) error = nil", s)
+		}
+	}
+	valid := Settings{Thresholds: map[Metric]Threshold{AbandonRate: {ZScore: 2}}, WebhookURL: "https://ops.example.com/hook"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
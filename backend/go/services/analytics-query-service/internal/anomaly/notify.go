@@ -0,0 +1,92 @@
+package anomaly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// Publisher publishes platform events.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, event *types.Event) error
+}
+
+// EventNotifier publishes alerts as metric anomaly events.
+type EventNotifier struct {
+	publisher Publisher
+	source    string
+}
+
+// NewEventNotifier creates an EventNotifier publishing as source.
+func NewEventNotifier(publisher Publisher, source string) *EventNotifier {
+	return &EventNotifier{publisher: publisher, source: source}
+}
+
+// Notify implements Notifier.
+func (n *EventNotifier) Notify(ctx context.Context, alert Alert, _ *Settings) error {
+	event := events.NewEvent(topics.MetricAnomaly, n.source, events.MetricAnomalyEvent{
+		TenantID:    alert.TenantID,
+		Metric:      string(alert.Metric),
+		Value:       alert.Value,
+		Baseline:    alert.Baseline,
+		StdDev:      alert.StdDev,
+		ZScore:      alert.ZScore,
+		Threshold:   alert.Threshold,
+		WindowStart: alert.WindowStart,
+		WindowEnd:   alert.WindowEnd,
+		DetectedAt:  alert.DetectedAt,
+	}).WithTenantID(alert.TenantID)
+
+	return n.publisher.Publish(ctx, topics.MetricAnomaly, event)
+}
+
+// WebhookNotifier posts alerts to the tenant's webhook, if configured.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier.
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookNotifier{client: client}
+}
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert, settings *Settings) error {
+	if settings.WebhookURL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(struct {
+		Type string `json:"type"`
+		Alert
+	}{Type: topics.MetricAnomaly, Alert: alert})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
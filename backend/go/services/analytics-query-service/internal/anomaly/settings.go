@@ -0,0 +1,70 @@
+package anomaly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettingsRecord is the alert_settings row.
+type SettingsRecord struct {
+	TenantID  string `gorm:"primary_key"`
+	Settings  []byte `gorm:"type:jsonb;not null"`
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name
+func (SettingsRecord) TableName() string {
+	return "alert_settings"
+}
+
+// GormSettingsStore implements SettingsStore using PostgreSQL
+type GormSettingsStore struct {
+	db *gorm.DB
+}
+
+// NewGormSettingsStore creates a new PostgreSQL alert settings store
+func NewGormSettingsStore(db *gorm.DB) *GormSettingsStore {
+	return &GormSettingsStore{db: db}
+}
+
+// Get returns a tenant's settings, or empty settings if none are stored
+func (s *GormSettingsStore) Get(ctx context.Context, tenantID string) (*Settings, error) {
+	var row SettingsRecord
+	err := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var settings Settings
+	if err := json.Unmarshal(row.Settings, &settings); err != nil {
+		return nil, fmt.Errorf("failed to decode alert settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// Put validates and stores a tenant's settings
+func (s *GormSettingsStore) Put(ctx context.Context, tenantID string, settings *Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&SettingsRecord{
+		TenantID:  tenantID,
+		Settings:  data,
+		UpdatedAt: time.Now().UTC(),
+	}).Error
+}
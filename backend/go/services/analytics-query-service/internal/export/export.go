@@ -0,0 +1,443 @@
+// Package export copies ingested events to tenants' own warehouses. Events are
+// buffered per tenant and written as NDJSON objects to the tenant's
+// destination (S3, GCS or, for development, the local filesystem).
+//
+// Each batch is committed by a manifest written after its data files.
+// Loaders should load only the files listed in manifests they have not
+// processed yet: data files without a manifest belong to a failed attempt.
+// Batch IDs are derived from the batch's event IDs, so retrying a batch
+// overwrites the same objects instead of creating new ones.
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/ingest"
+)
+
+// Sink stores objects in a destination.
+type Sink interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// DestinationStore returns tenants' export destinations.
+type DestinationStore interface {
+	// Get returns ErrNotConfigured if the tenant has no destination.
+	Get(ctx context.Context, tenantID string) (*Destination, error)
+}
+
+// ErrNotConfigured is returned for tenants without an export destination.
+var ErrNotConfigured = errors.New("export destination not configured")
+
+// Config controls batching.
+type Config struct {
+	MaxEvents     int           // events per batch
+	MaxBytes      int           // NDJSON bytes per batch
+	FlushInterval time.Duration // longest an event waits before export
+	MaxPending    int           // sealed batches kept per tenant while the destination fails
+	CacheTTL      time.Duration // how long destinations are cached
+}
+
+// DefaultConfig returns the export defaults.
+func DefaultConfig() Config {
+	return Config{
+		MaxEvents:     10000,
+		MaxBytes:      16 << 20,
+		FlushInterval: time.Minute,
+		MaxPending:    100,
+		CacheTTL:      time.Minute,
+	}
+}
+
+// Record is one NDJSON line.
+type Record struct {
+	EventID    string          `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	TenantID   string          `json:"tenant_id"`
+	Source     string          `json:"source"`
+	Table      string          `json:"table"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Manifest commits a batch. It is written after every file it lists.
+type Manifest struct {
+	Version       int            `json:"version"`
+	BatchID       string         `json:"batch_id"`
+	TenantID      string         `json:"tenant_id"`
+	CreatedAt     time.Time      `json:"created_at"`
+	MinOccurredAt time.Time      `json:"min_occurred_at"`
+	MaxOccurredAt time.Time      `json:"max_occurred_at"`
+	Files         []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes a data file of a batch.
+type ManifestFile struct {
+	Key    string `json:"key"`
+	Events int    `json:"events"`
+	Bytes  int    `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// batch is a sealed set of records awaiting upload.
+type batch struct {
+	id        string
+	createdAt time.Time
+	records   []Record
+}
+
+// buffer holds a tenant's records.
+type buffer struct {
+	open   []Record
+	bytes  int
+	sealed []batch
+}
+
+// cachedSink is a tenant's resolved destination.
+type cachedSink struct {
+	sink      Sink // nil when the tenant has no destination
+	prefix    string
+	expiresAt time.Time
+}
+
+// Exporter buffers ingested rows and exports them per tenant. It implements
+// ingest.Sink.
+type Exporter struct {
+	destinations DestinationStore
+	newSink      func(tenantID string, d *Destination) (Sink, error)
+	config       Config
+	logger       *zap.Logger
+	now          func() time.Time
+
+	mu      sync.Mutex
+	buffers map[string]*buffer
+	sinks   map[string]cachedSink
+	full    chan struct{}
+
+	flushMu sync.Mutex // serializes flushes
+}
+
+// New creates an Exporter. newSink builds the sink of a destination.
+func New(destinations DestinationStore, newSink func(tenantID string, d *Destination) (Sink, error), config Config, logger *zap.Logger) *Exporter {
+	defaults := DefaultConfig()
+	if config.MaxEvents <= 0 {
+		config.MaxEvents = defaults.MaxEvents
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = defaults.MaxBytes
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+	if config.MaxPending <= 0 {
+		config.MaxPending = defaults.MaxPending
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+
+	return &Exporter{
+		destinations: destinations,
+		newSink:      newSink,
+		config:       config,
+		logger:       logger,
+		now:          time.Now,
+		buffers:      make(map[string]*buffer),
+		sinks:        make(map[string]cachedSink),
+		full:         make(chan struct{}, 1),
+	}
+}
+
+// Write implements ingest.Sink. Rows of tenants without a destination are
+// dropped.
+func (e *Exporter) Write(ctx context.Context, table string, rows []ingest.Row) error {
+	for _, row := range rows {
+		sink, err := e.sinkFor(ctx, row.TenantID)
+		if err != nil {
+			return err
+		}
+		if sink.sink == nil {
+			continue
+		}
+
+		record := Record{
+			EventID:    row.EventID,
+			EventType:  row.EventType,
+			TenantID:   row.TenantID,
+			Source:     row.Source,
+			Table:      table,
+			OccurredAt: row.OccurredAt,
+			Payload:    json.RawMessage(row.Payload),
+		}
+		if !json.Valid(record.Payload) {
+			record.Payload = json.RawMessage("null")
+		}
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", row.EventID, err)
+		}
+
+		e.mu.Lock()
+		buf := e.buffers[row.TenantID]
+		if buf == nil {
+			buf = &buffer{}
+			e.buffers[row.TenantID] = buf
+		}
+		buf.open = append(buf.open, record)
+		buf.bytes += len(line) + 1
+		full := len(buf.open) >= e.config.MaxEvents || buf.bytes >= e.config.MaxBytes
+		e.mu.Unlock()
+
+		if full {
+			select {
+			case e.full <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+// sinkFor returns the tenant's cached sink, resolving it when expired.
+func (e *Exporter) sinkFor(ctx context.Context, tenantID string) (cachedSink, error) {
+	now := e.now()
+
+	e.mu.Lock()
+	cached, ok := e.sinks[tenantID]
+	e.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached, nil
+	}
+
+	cached = cachedSink{expiresAt: now.Add(e.config.CacheTTL)}
+	dest, err := e.destinations.Get(ctx, tenantID)
+	switch {
+	case errors.Is(err, ErrNotConfigured):
+	case err != nil:
+		return cachedSink{}, fmt.Errorf("failed to load export destination of tenant %s: %w", tenantID, err)
+	default:
+		sink, err := e.newSink(tenantID, dest)
+		if err != nil {
+			// A broken destination must not block ingestion; skip the tenant until the cache expires
+			e.logger.Error("invalid export destination", zap.String("tenant_id", tenantID), zap.Error(err))
+			break
+		}
+		cached.sink = sink
+		cached.prefix = strings.Trim(dest.Prefix, "/")
+	}
+
+	e.mu.Lock()
+	e.sinks[tenantID] = cached
+	e.mu.Unlock()
+	return cached, nil
+}
+
+// Flush exports every buffered record.
+func (e *Exporter) Flush(ctx context.Context) error {
+	return e.flush(ctx, false)
+}
+
+// flush seals buffered records into batches and uploads them. With onlyFull
+// set, partial batches stay buffered.
+func (e *Exporter) flush(ctx context.Context, onlyFull bool) error {
+	e.flushMu.Lock()
+	defer e.flushMu.Unlock()
+
+	e.mu.Lock()
+	tenants := make([]string, 0, len(e.buffers))
+	for tenantID, buf := range e.buffers {
+		e.seal(tenantID, buf, onlyFull)
+		if len(buf.sealed) > 0 {
+			tenants = append(tenants, tenantID)
+		}
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	for _, tenantID := range tenants {
+		if err := e.upload(ctx, tenantID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// seal splits the open records into batches of at most MaxEvents and
+// MaxBytes. The caller holds e.mu.
+func (e *Exporter) seal(tenantID string, buf *buffer, onlyFull bool) {
+	now := e.now().UTC()
+
+	var records []Record
+	size := 0
+	for n, record := range buf.open {
+		line, _ := json.Marshal(record)
+		if len(records) > 0 && (len(records) >= e.config.MaxEvents || size+len(line)+1 > e.config.MaxBytes) {
+			buf.sealed = append(buf.sealed, newBatch(records, now))
+			records, size = nil, 0
+		}
+		records = append(records, record)
+		size += len(line) + 1
+
+		last := n == len(buf.open)-1
+		if last && (!onlyFull || len(records) >= e.config.MaxEvents || size >= e.config.MaxBytes) {
+			buf.sealed = append(buf.sealed, newBatch(records, now))
+			records, size = nil, 0
+		}
+	}
+	buf.open, buf.bytes = records, size
+
+	if over := len(buf.sealed) - e.config.MaxPending; over > 0 {
+		dropped := 0
+		for _, b := range buf.sealed[:over] {
+			dropped += len(b.records)
+		}
+		buf.sealed = buf.sealed[over:]
+		e.logger.Error("export backlog full, dropping oldest batches",
+			zap.String("tenant_id", tenantID),
+			zap.Int("batches", over),
+			zap.Int("events", dropped),
+		)
+	}
+}
+
+// newBatch seals records into a batch whose ID is derived from the event IDs.
+func newBatch(records []Record, createdAt time.Time) batch {
+	h := sha256.New()
+	for _, r := range records {
+		h.Write([]byte(r.EventID))
+		h.Write([]byte{'\n'})
+	}
+	return batch{
+		id:        hex.EncodeToString(h.Sum(nil))[:32],
+		createdAt: createdAt,
+		records:   records,
+	}
+}
+
+// upload writes the tenant's sealed batches in order, stopping at the first
+// failure so batches are committed in sequence.
+func (e *Exporter) upload(ctx context.Context, tenantID string) error {
+	sink, err := e.sinkFor(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for {
+		e.mu.Lock()
+		buf := e.buffers[tenantID]
+		if len(buf.sealed) == 0 {
+			if len(buf.open) == 0 {
+				delete(e.buffers, tenantID)
+			}
+			e.mu.Unlock()
+			return nil
+		}
+		b := buf.sealed[0]
+		e.mu.Unlock()
+
+		if sink.sink == nil {
+			// The destination was removed; drop what was buffered for it
+			e.mu.Lock()
+			buf.sealed = buf.sealed[1:]
+			e.mu.Unlock()
+			continue
+		}
+
+		if err := writeBatch(ctx, sink.sink, sink.prefix, tenantID, b); err != nil {
+			return fmt.Errorf("failed to export batch %s of tenant %s: %w", b.id, tenantID, err)
+		}
+
+		e.mu.Lock()
+		buf.sealed = buf.sealed[1:]
+		e.mu.Unlock()
+
+		e.logger.Debug("exported batch",
+			zap.String("tenant_id", tenantID),
+			zap.String("batch_id", b.id),
+			zap.Int("events", len(b.records)),
+		)
+	}
+}
+
+// writeBatch writes a batch's data file and then its manifest.
+func writeBatch(ctx context.Context, sink Sink, prefix, tenantID string, b batch) error {
+	var data []byte
+	manifest := Manifest{
+		Version:   1,
+		BatchID:   b.id,
+		TenantID:  tenantID,
+		CreatedAt: b.createdAt,
+	}
+	for _, r := range b.records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+
+		if manifest.MinOccurredAt.IsZero() || r.OccurredAt.Before(manifest.MinOccurredAt) {
+			manifest.MinOccurredAt = r.OccurredAt
+		}
+		if r.OccurredAt.After(manifest.MaxOccurredAt) {
+			manifest.MaxOccurredAt = r.OccurredAt
+		}
+	}
+
+	key := path.Join(prefix, "events", "dt="+b.createdAt.Format("2006-01-02"), b.id+".ndjson")
+	if err := sink.Put(ctx, key, data); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	manifest.Files = []ManifestFile{{
+		Key:    key,
+		Events: len(b.records),
+		Bytes:  len(data),
+		SHA256: hex.EncodeToString(sum[:]),
+	}}
+	body, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return sink.Put(ctx, path.Join(prefix, "manifests", b.id+".json"), body)
+}
+
+// Run flushes every FlushInterval, and as soon as a tenant fills a batch,
+// until ctx is done.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		onlyFull := false
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-e.full:
+			onlyFull = true
+		}
+
+		if err := e.flush(ctx, onlyFull); err != nil {
+			e.logger.Error("failed to export events", zap.Error(err))
+		}
+	}
+}
+
+// Close exports the remaining records. It is meant to run after the
+// ingestor has flushed, within the shutdown deadline.
+func (e *Exporter) Close(ctx context.Context) error {
+	return e.Flush(ctx)
+}
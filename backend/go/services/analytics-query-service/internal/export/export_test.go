@@ -0,0 +1,257 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/ingest"
+)
+
+// destinations maps tenants to destinations.
+type destinations map[string]*Destination
+
+func (d destinations) Get(_ context.Context, tenantID string) (*Destination, error) {
+	if dest, ok := d[tenantID]; ok {
+		return dest, nil
+	}
+	return nil, ErrNotConfigured
+}
+
+var at = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func rows(tenantID string, n int) []ingest.Row {
+	out := make([]ingest.Row, n)
+	for i := range out {
+		out[i] = ingest.Row{
+			EventID:    fmt.Sprintf("%s-evt-%d", tenantID, i),
+			EventType:  "call.ended",
+			TenantID:   tenantID,
+			Source:     "voice-gateway",
+			OccurredAt: at.Add(time.Duration(i) * time.Minute),
+			Payload:    fmt.Sprintf(`{"n":%d}`, i),
+		}
+	}
+	return out
+}
+
+// newFileExporter exports to FileSinks under a temporary directory.
+func newFileExporter(t *testing.T, config Config) (*Exporter, string) {
+	t.Helper()
+	root := t.TempDir()
+	factory := &SinkFactory{FileRoot: root}
+	e := New(destinations{
+		"tenant-1": {Type: TypeFile, Prefix: "serphona"},
+		"tenant-2": {Type: TypeFile},
+	}, factory.New, config, zap.NewNop())
+	e.now = func() time.Time { return at }
+	return e, root
+}
+
+// readManifests returns the tenant's manifests, by batch ID.
+func readManifests(t *testing.T, dir string) []Manifest {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "manifests", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []Manifest
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].BatchID < out[j].BatchID })
+	return out
+}
+
+func TestExporterBatchesBySize(t *testing.T) {
+	e, root := newFileExporter(t, Config{MaxEvents: 2, MaxBytes: 1 << 20})
+	ctx := context.Background()
+
+	if err := e.Write(ctx, ingest.TableCalls, rows("tenant-1", 5)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// Only full batches are exported before the interval elapses
+	if err := e.flush(ctx, true); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+	dir := filepath.Join(root, "tenant-1", "serphona")
+	if got := len(readManifests(t, dir)); got != 2 {
+		t.Fatalf("manifests after full flush = %d, want 2", got)
+	}
+
+	if err := e.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	manifests := readManifests(t, dir)
+	if len(manifests) != 3 {
+		t.Fatalf("manifests = %d, want 3", len(manifests))
+	}
+
+	events := 0
+	for _, m := range manifests {
+		if m.Files[0].Events > 2 {
+			t.Errorf("batch %s has %d events, want at most 2", m.BatchID, m.Files[0].Events)
+		}
+		events += m.Files[0].Events
+	}
+	if events != 5 {
+		t.Errorf("exported events = %d, want 5", events)
+	}
+}
+
+func TestExporterBatchesByBytes(t *testing.T) {
+	line, _ := json.Marshal(Record{EventID: "tenant-1-evt-0", EventType: "call.ended", TenantID: "tenant-1",
+		Source: "voice-gateway", Table: ingest.TableCalls, OccurredAt: at, Payload: json.RawMessage(`{"n":0}`)})
+	e, root := newFileExporter(t, Config{MaxEvents: 100, MaxBytes: 2*len(line) + 2})
+
+	if err := e.Write(context.Background(), ingest.TableCalls, rows("tenant-1", 5)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range readManifests(t, filepath.Join(root, "tenant-1", "serphona")) {
+		if m.Files[0].Bytes > 2*len(line)+2 {
+			t.Errorf("batch %s is %d bytes, over the limit", m.BatchID, m.Files[0].Bytes)
+		}
+	}
+}
+
+func TestManifestDescribesDataFile(t *testing.T) {
+	e, root := newFileExporter(t, Config{MaxEvents: 10})
+	ctx := context.Background()
+
+	if err := e.Write(ctx, ingest.TableCalls, rows("tenant-2", 3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(root, "tenant-2")
+	manifests := readManifests(t, dir)
+	if len(manifests) != 1 {
+		t.Fatalf("manifests = %d, want 1", len(manifests))
+	}
+	m := manifests[0]
+	if m.Version != 1 || m.TenantID != "tenant-2" || !m.CreatedAt.Equal(at) {
+		t.Errorf("manifest = %+v", m)
+	}
+	if !m.MinOccurredAt.Equal(at) || !m.MaxOccurredAt.Equal(at.Add(2*time.Minute)) {
+		t.Errorf("occurred range = %v..%v", m.MinOccurredAt, m.MaxOccurredAt)
+	}
+
+	file := m.Files[0]
+	if want := "events/dt=2025-03-10/" + m.BatchID + ".ndjson"; file.Key != want {
+		t.Errorf("data key = %q, want %q", file.Key, want)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Key)))
+	if err != nil {
+		t.Fatalf("data file: %v", err)
+	}
+	if file.Bytes != len(data) || file.SHA256 != sha256Hex(data) || file.Events != 3 {
+		t.Errorf("manifest file = %+v, data is %d bytes", file, len(data))
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		if r.Table != ingest.TableCalls || string(r.Payload) == "" {
+			t.Errorf("record = %+v", r)
+		}
+		ids = append(ids, r.EventID)
+	}
+	if strings.Join(ids, ",") != "tenant-2-evt-0,tenant-2-evt-1,tenant-2-evt-2" {
+		t.Errorf("records = %v", ids)
+	}
+}
+
+func TestExporterSkipsTenantsWithoutDestination(t *testing.T) {
+	e, root := newFileExporter(t, Config{})
+
+	if err := e.Write(context.Background(), ingest.TableCalls, rows("tenant-3", 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "tenant-3")); !os.IsNotExist(err) {
+		t.Errorf("tenant without destination was exported (%v)", err)
+	}
+}
+
+// flakySink fails its first puts and records the keys written.
+type flakySink struct {
+	mu       sync.Mutex
+	failures int
+	keys     []string
+}
+
+func (s *flakySink) Put(_ context.Context, key string, _ []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func TestFailedBatchIsRetriedWithSameID(t *testing.T) {
+	sink := &flakySink{failures: 1}
+	e := New(destinations{"tenant-1": {Type: TypeFile}}, func(string, *Destination) (Sink, error) {
+		return sink, nil
+	}, Config{MaxEvents: 10}, zap.NewNop())
+	ctx := context.Background()
+
+	if err := e.Write(ctx, ingest.TableCalls, rows("tenant-1", 2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Flush(ctx); err == nil {
+		t.Fatal("Flush() error = nil, want sink failure")
+	}
+
+	// Events arriving meanwhile go to a new batch
+	if err := e.Write(ctx, ingest.TableCalls, rows("tenant-1", 3)[2:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := newBatch([]Record{{EventID: "tenant-1-evt-0"}, {EventID: "tenant-1-evt-1"}}, at).id
+	if len(sink.keys) != 4 {
+		t.Fatalf("keys = %v, want 2 batches of data+manifest", sink.keys)
+	}
+	if !strings.HasSuffix(sink.keys[1], "manifests/"+want+".json") {
+		t.Errorf("first committed manifest = %s, want batch %s", sink.keys[1], want)
+	}
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Settings is the export_settings row. The destination, credentials
+// included, is stored encrypted.
+type Settings struct {
+	TenantID    string `gorm:"primary_key"`
+	Destination []byte `gorm:"type:bytea;not null"`
+	UpdatedAt   time.Time
+}
+
+// TableName specifies the table name
+func (Settings) TableName() string {
+	return "export_settings"
+}
+
+// GormSettingsStore implements DestinationStore using PostgreSQL
+type GormSettingsStore struct {
+	db     *gorm.DB
+	cipher *Cipher
+}
+
+// NewGormSettingsStore creates a new PostgreSQL export settings store
+func NewGormSettingsStore(db *gorm.DB, c *Cipher) *GormSettingsStore {
+	return &GormSettingsStore{db: db, cipher: c}
+}
+
+// Get returns a tenant's decrypted destination
+func (s *GormSettingsStore) Get(ctx context.Context, tenantID string) (*Destination, error) {
+	var row Settings
+	err := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotConfigured
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := s.cipher.Open(tenantID, row.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt export destination: %w", err)
+	}
+
+	var d Destination
+	if err := json.Unmarshal(plaintext, &d); err != nil {
+		return nil, fmt.Errorf("failed to decode export destination: %w", err)
+	}
+	return &d, nil
+}
+
+// Put validates, encrypts and stores a tenant's destination
+func (s *GormSettingsStore) Put(ctx context.Context, tenantID string, d *Destination) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	sealed, err := s.cipher.Seal(tenantID, plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt export destination: %w", err)
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&Settings{
+		TenantID:    tenantID,
+		Destination: sealed,
+		UpdatedAt:   time.Now().UTC(),
+	}).Error
+}
+
+// Delete removes a tenant's destination
+func (s *GormSettingsStore) Delete(ctx context.Context, tenantID string) error {
+	result := s.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Delete(&Settings{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotConfigured
+	}
+	return nil
+}
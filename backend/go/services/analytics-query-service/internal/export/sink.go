@@ -0,0 +1,277 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Destination types.
+const (
+	TypeS3   = "s3"
+	TypeGCS  = "gcs"
+	TypeFile = "file"
+)
+
+// Destination is where a tenant's events are exported. It is stored
+// encrypted, credentials included.
+type Destination struct {
+	Type            string `json:"type"`
+	Bucket          string `json:"bucket,omitempty"`
+	Prefix          string `json:"prefix,omitempty"`
+	Region          string `json:"region,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"` // overrides the provider's endpoint, e.g. for MinIO
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+}
+
+// Validate checks the destination's required fields.
+func (d *Destination) Validate() error {
+	switch d.Type {
+	case TypeS3, TypeGCS:
+		if d.Bucket == "" {
+			return fmt.Errorf("bucket is required")
+		}
+		if d.AccessKeyID == "" || d.SecretAccessKey == "" {
+			return fmt.Errorf("access_key_id and secret_access_key are required")
+		}
+		if d.Type == TypeS3 && d.Region == "" && d.Endpoint == "" {
+			return fmt.Errorf("region is required")
+		}
+		if d.Endpoint != "" {
+			u, err := url.Parse(d.Endpoint)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("endpoint must be an http(s) URL")
+			}
+		}
+	case TypeFile:
+	default:
+		return fmt.Errorf("unknown destination type %q", d.Type)
+	}
+	if strings.Contains(d.Prefix, "..") {
+		return fmt.Errorf("prefix must not contain ..")
+	}
+	return nil
+}
+
+// RedactedSecret replaces the secret in API responses. Sending it back keeps
+// the stored secret.
+const RedactedSecret = "********"
+
+// Redacted returns a copy without the secret, for API responses.
+func (d Destination) Redacted() Destination {
+	if d.SecretAccessKey != "" {
+		d.SecretAccessKey = RedactedSecret
+	}
+	return d
+}
+
+// SinkFactory builds sinks for destinations.
+type SinkFactory struct {
+	Client   *http.Client
+	FileRoot string // enables file destinations, under FileRoot/<tenant_id>; empty disables them
+}
+
+// New builds the sink of a tenant's destination.
+func (f *SinkFactory) New(tenantID string, d *Destination) (Sink, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch d.Type {
+	case TypeFile:
+		if f.FileRoot == "" {
+			return nil, fmt.Errorf("file destinations are disabled")
+		}
+		return &FileSink{Root: filepath.Join(f.FileRoot, filepath.Base(tenantID))}, nil
+	case TypeGCS:
+		// GCS accepts SigV4-signed requests made with HMAC keys
+		endpoint, region := d.Endpoint, d.Region
+		if endpoint == "" {
+			endpoint = "https://storage.googleapis.com"
+		}
+		if region == "" {
+			region = "auto"
+		}
+		return NewObjectStoreSink(f.Client, endpoint, region, d.Bucket, d.AccessKeyID, d.SecretAccessKey), nil
+	default:
+		endpoint := d.Endpoint
+		if endpoint == "" {
+			endpoint = "https://s3." + d.Region + ".amazonaws.com"
+		}
+		return NewObjectStoreSink(f.Client, endpoint, d.Region, d.Bucket, d.AccessKeyID, d.SecretAccessKey), nil
+	}
+}
+
+// FileSink writes objects under a local directory, for development and tests.
+type FileSink struct {
+	Root string
+}
+
+// Put implements Sink. Objects are written to a temporary file and renamed,
+// so readers never see partial files.
+func (s *FileSink) Put(_ context.Context, key string, data []byte) error {
+	name := filepath.Join(s.Root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// ObjectStoreSink writes objects with S3-compatible PUT requests signed
+// with AWS Signature Version 4, using path-style URLs.
+type ObjectStoreSink struct {
+	client    *http.Client
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	now       func() time.Time
+}
+
+// NewObjectStoreSink creates an ObjectStoreSink.
+func NewObjectStoreSink(client *http.Client, endpoint, region, bucket, accessKey, secretKey string) *ObjectStoreSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &ObjectStoreSink{
+		client:    client,
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		now:       time.Now,
+	}
+}
+
+// Put implements Sink.
+func (s *ObjectStoreSink) Put(ctx context.Context, key string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.endpoint+"/"+s.bucket+"/"+escapePath(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType(key))
+	s.sign(req, data)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("put %s: status %d: %s", key, resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// sign adds the SigV4 headers to req.
+func (s *ObjectStoreSink) sign(req *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("Authorization", signatureV4(req, payloadHash, now, s.region, s.accessKey, s.secretKey))
+}
+
+// signatureV4 returns the Authorization header of req, signing the Host
+// header and every header already set.
+func signatureV4(req *http.Request, payloadHash string, now time.Time, region, accessKey, secretKey string) string {
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	date := now.Format("20060102")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return "AWS4-HMAC-SHA256 Credential=" + accessKey + "/" + scope +
+		", SignedHeaders=" + signedHeaders + ", Signature=" + signature
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapePath escapes an object key as SigV4 expects: every byte except
+// unreserved characters and '/' is percent-encoded.
+func escapePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func contentType(key string) string {
+	if strings.HasSuffix(key, ".ndjson") {
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
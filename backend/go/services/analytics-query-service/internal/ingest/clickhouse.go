@@ -0,0 +1,64 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// ClickHouseStore is the Store backed by ClickHouse. Table names come from
+// the Table constants, never from event data.
+type ClickHouseStore struct {
+	conn driver.Conn
+}
+
+// NewClickHouseStore creates a ClickHouseStore.
+func NewClickHouseStore(conn driver.Conn) *ClickHouseStore {
+	return &ClickHouseStore{conn: conn}
+}
+
+// ExistingIDs implements Store.
+func (s *ClickHouseStore) ExistingIDs(ctx context.Context, table string, ids []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	rows, err := s.conn.Query(ctx, fmt.Sprintf("SELECT event_id FROM %s WHERE event_id IN (?)", table), ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query existing events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan event id: %w", err)
+		}
+		existing[id] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// Insert implements Store.
+func (s *ClickHouseStore) Insert(ctx context.Context, table string, rows []Row) error {
+	batch, err := s.conn.PrepareBatch(ctx, fmt.Sprintf(
+		"INSERT INTO %s (event_id, event_type, tenant_id, source, occurred_at, payload)", table))
+	if err != nil {
+		return fmt.Errorf("failed to prepare batch: %w", err)
+	}
+
+	for _, row := range rows {
+		if err := batch.Append(row.EventID, row.EventType, row.TenantID, row.Source, row.OccurredAt, row.Payload); err != nil {
+			batch.Abort()
+			return fmt.Errorf("failed to append row: %w", err)
+		}
+	}
+
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to send batch: %w", err)
+	}
+	return nil
+}
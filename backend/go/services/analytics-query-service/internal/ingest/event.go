@@ -0,0 +1,169 @@
+// Package ingest populates the ClickHouse analytics tables from the event
+// stream. Events are batched per table and inserted idempotently by event ID,
+// so partitions can be replayed for backfills without duplicating rows.
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// ClickHouse tables populated by the ingestor.
+const (
+	TableCalls        = "call_events"
+	TableInteractions = "interaction_events"
+	TableDecisions    = "decision_events"
+	TableTools        = "tool_events"
+)
+
+// Voice-gateway event types. They are published outside platform-events, on
+// "<prefix>.<type>" topics with their own JSON envelope.
+var voiceGatewayTypes = map[string]string{
+	"call.started":          TableCalls,
+	"call.answered":         TableCalls,
+	"call.ended":            TableCalls,
+	"call.abandoned":        TableCalls,
+	"call.transferred":      TableCalls,
+	"call.queued":           TableCalls,
+	"call.dequeued":         TableCalls,
+	"call.quality_degraded": TableCalls,
+	"voicemail.left":        TableCalls,
+	"provider.timeout":      TableCalls,
+	"stt.transcribed":       TableInteractions,
+	"llm.responded":         TableInteractions,
+	"tts.generated":         TableInteractions,
+	"routing.decision":      TableDecisions,
+}
+
+// platformTypes are event types published through platform-events, where the
+// topic is the event type.
+var platformTypes = map[string]string{
+	topics.InteractionLogged:   TableInteractions,
+	topics.ConversationStarted: TableInteractions,
+	topics.ConversationEnded:   TableInteractions,
+	topics.MessageSent:         TableInteractions,
+	topics.MessageReceived:     TableInteractions,
+	topics.TransferDecided:     TableDecisions,
+	topics.ComplianceViolation: TableDecisions,
+	topics.ToolInvoked:         TableTools,
+	topics.ToolCompleted:       TableTools,
+	topics.ToolFailed:          TableTools,
+}
+
+// Row is an event as stored in its ClickHouse table.
+type Row struct {
+	EventID    string
+	EventType  string
+	TenantID   string
+	Source     string
+	OccurredAt time.Time
+	Payload    string // event data as JSON
+}
+
+// TableFor returns the table that stores events of eventType.
+func TableFor(eventType string) (string, bool) {
+	if table, ok := platformTypes[eventType]; ok {
+		return table, true
+	}
+	table, ok := voiceGatewayTypes[eventType]
+	return table, ok
+}
+
+// EventTypes returns every ingested event type, to subscribe handlers.
+func EventTypes() []string {
+	out := make([]string, 0, len(platformTypes)+len(voiceGatewayTypes))
+	for t := range platformTypes {
+		out = append(out, t)
+	}
+	for t := range voiceGatewayTypes {
+		out = append(out, t)
+	}
+	return out
+}
+
+// Topics returns the Kafka topics carrying ingested events. Voice-gateway
+// topics are prefixed with its KAFKA_TOPIC_PREFIX.
+func Topics(voiceGatewayPrefix string) []string {
+	out := make([]string, 0, len(platformTypes)+len(voiceGatewayTypes))
+	for t := range platformTypes {
+		out = append(out, t)
+	}
+	for t := range voiceGatewayTypes {
+		out = append(out, voiceGatewayPrefix+"."+t)
+	}
+	return out
+}
+
+// ErrUnknownEnvelope is returned for messages that are neither a
+// platform-events event nor a voice-gateway event.
+var ErrUnknownEnvelope = errors.New("unknown event envelope")
+
+// voiceGatewayEnvelope holds the common fields of voice-gateway events.
+type voiceGatewayEnvelope struct {
+	EventID   string    `json:"event_id"`
+	EventType string    `json:"event_type"`
+	Timestamp time.Time `json:"timestamp"`
+	TenantID  string    `json:"tenant_id"`
+}
+
+// Decode converts a Kafka message into an event. It accepts both the
+// platform-events envelope and the voice-gateway one; for the latter the
+// whole message becomes the event data.
+func Decode(data []byte) (*types.Event, error) {
+	event, err := types.FromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if event.Type != "" {
+		return event, nil
+	}
+
+	var env voiceGatewayEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.EventType == "" || env.EventID == "" {
+		return nil, ErrUnknownEnvelope
+	}
+
+	return &types.Event{
+		ID:        env.EventID,
+		Type:      env.EventType,
+		Source:    "voice-gateway",
+		Timestamp: env.Timestamp,
+		TenantID:  env.TenantID,
+		Data:      json.RawMessage(data),
+		Version:   "1.0",
+	}, nil
+}
+
+// toRow converts an event into the row of its table.
+func toRow(event *types.Event) (string, Row, error) {
+	table, ok := TableFor(event.Type)
+	if !ok {
+		return "", Row{}, fmt.Errorf("event type %q is not ingested", event.Type)
+	}
+	if strings.TrimSpace(event.ID) == "" {
+		return "", Row{}, fmt.Errorf("event of type %q has no ID", event.Type)
+	}
+
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		return "", Row{}, fmt.Errorf("failed to encode event data: %w", err)
+	}
+
+	return table, Row{
+		EventID:    event.ID,
+		EventType:  event.Type,
+		TenantID:   event.TenantID,
+		Source:     event.Source,
+		OccurredAt: event.Timestamp.UTC(),
+		Payload:    string(payload),
+	}, nil
+}
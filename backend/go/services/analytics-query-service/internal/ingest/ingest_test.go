@@ -0,0 +1,368 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+	"go.uber.org/zap"
+)
+
+// memStore is an in-memory Store keyed by table.
+type memStore struct {
+	mu     sync.Mutex
+	tables map[string][]Row
+}
+
+func newMemStore() *memStore {
+	return &memStore{tables: make(map[string][]Row)}
+}
+
+func (s *memStore) ExistingIDs(_ context.Context, table string, ids []string) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing := make(map[string]bool)
+	for _, row := range s.tables[table] {
+		for _, id := range ids {
+			if row.EventID == id {
+				existing[id] = true
+			}
+		}
+	}
+	return existing, nil
+}
+
+func (s *memStore) Insert(_ context.Context, table string, rows []Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tables[table] = append(s.tables[table], rows...)
+	return nil
+}
+
+func (s *memStore) rows(table string) []Row {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Row(nil), s.tables[table]...)
+}
+
+var at = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// voiceGatewayMessage builds a message as voice-gateway publishes it.
+func voiceGatewayMessage(t *testing.T, id, eventType string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{
+		"event_id":   id,
+		"event_type": eventType,
+		"timestamp":  at,
+		"tenant_id":  "tenant-1",
+		"call_id":    "call-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// batch returns one event of each ingested kind.
+func batch(t *testing.T) []*types.Event {
+	t.Helper()
+
+	call, err := Decode(voiceGatewayMessage(t, "evt-call", "call.started"))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	platform := func(id, eventType string) *types.Event {
+		e := types.NewEvent(eventType, "agent-orchestrator", map[string]any{"call_id": "call-1"}).WithTenantID("tenant-1")
+		e.ID = id
+		e.Timestamp = at
+		return e
+	}
+
+	return []*types.Event{
+		call,
+		platform("evt-interaction", topics.InteractionLogged),
+		platform("evt-decision", topics.TransferDecided),
+		platform("evt-tool", topics.ToolInvoked),
+	}
+}
+
+func newTestIngestor(store Store, batchSize int) *Ingestor {
+	return New(store, Config{BatchSize: batchSize, FlushInterval: time.Hour}, zap.NewNop())
+}
+
+func TestIngestorBatchProducesRows(t *testing.T) {
+	store := newMemStore()
+	ing := newTestIngestor(store, 100)
+
+	for _, e := range batch(t) {
+		if err := ing.Handle(e); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+	if n := len(store.rows(TableCalls)); n != 0 {
+		t.Fatalf("rows inserted before flush = %d", n)
+	}
+	if err := ing.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := map[string]string{
+		TableCalls:        "evt-call",
+		TableInteractions: "evt-interaction",
+		TableDecisions:    "evt-decision",
+		TableTools:        "evt-tool",
+	}
+	for table, id := range want {
+		rows := store.rows(table)
+		if len(rows) != 1 || rows[0].EventID != id {
+			t.Fatalf("%s rows = %+v, want one row %s", table, rows, id)
+		}
+		if rows[0].TenantID != "tenant-1" || !rows[0].OccurredAt.Equal(at) {
+			t.Errorf("%s row = %+v", table, rows[0])
+		}
+	}
+
+	call := store.rows(TableCalls)[0]
+	if call.Source != "voice-gateway" || call.EventType != "call.started" {
+		t.Errorf("call row = %+v", call)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(call.Payload), &payload); err != nil || payload["call_id"] != "call-1" {
+		t.Errorf("call payload = %s (%v)", call.Payload, err)
+	}
+}
+
+func TestIngestorReplayDoesNotDuplicate(t *testing.T) {
+	store := newMemStore()
+	ing := newTestIngestor(store, 100)
+
+	for _, e := range batch(t) {
+		if err := ing.Handle(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ing.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replay the same events, twice within the same batch
+	for i := 0; i < 2; i++ {
+		for _, e := range batch(t) {
+			if err := ing.Handle(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := ing.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, table := range []string{TableCalls, TableInteractions, TableDecisions, TableTools} {
+		if n := len(store.rows(table)); n != 1 {
+			t.Errorf("%s rows = %d, want 1", table, n)
+		}
+	}
+}
+
+func TestIngestorFlushesAtBatchSize(t *testing.T) {
+	store := newMemStore()
+	ing := newTestIngestor(store, 2)
+
+	events := batch(t)
+	if err := ing.Handle(events[1]); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(store.rows(TableInteractions)); n != 0 {
+		t.Fatalf("rows = %d before batch is full", n)
+	}
+	if err := ing.Handle(events[2]); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.rows(TableInteractions)) != 1 || len(store.rows(TableDecisions)) != 1 {
+		t.Fatal("full batch was not flushed")
+	}
+}
+
+// failingStore fails inserts until healed.
+type failingStore struct {
+	*memStore
+	fail bool
+}
+
+func (s *failingStore) Insert(ctx context.Context, table string, rows []Row) error {
+	if s.fail {
+		return context.DeadlineExceeded
+	}
+	return s.memStore.Insert(ctx, table, rows)
+}
+
+func TestIngestorKeepsRowsOnFailedFlush(t *testing.T) {
+	store := &failingStore{memStore: newMemStore(), fail: true}
+	ing := newTestIngestor(store, 100)
+
+	for _, e := range batch(t) {
+		if err := ing.Handle(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ing.Flush(context.Background()); err == nil {
+		t.Fatal("Flush() error = nil, want insert failure")
+	}
+
+	store.fail = false
+	if err := ing.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if n := len(store.rows(TableTools)); n != 1 {
+		t.Errorf("tool rows = %d, want 1 after retry", n)
+	}
+}
+
+func TestIngestorSkipsUnknownEvents(t *testing.T) {
+	store := newMemStore()
+	ing := newTestIngestor(store, 1)
+
+	if err := ing.Handle(types.NewEvent("billing.invoice", "billing", nil)); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if err := ing.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for table, rows := range store.tables {
+		t.Errorf("unexpected rows in %s: %d", table, len(rows))
+	}
+}
+
+func TestDecodeRejectsUnknownEnvelope(t *testing.T) {
+	if _, err := Decode([]byte(`{"foo":"bar"}`)); err != ErrUnknownEnvelope {
+		t.Fatalf("Decode() error = %v, want ErrUnknownEnvelope", err)
+	}
+}
+
+// recordingSink collects the rows passed to Write.
+type recordingSink struct {
+	rows []Row
+}
+
+func (s *recordingSink) Write(_ context.Context, _ string, rows []Row) error {
+	s.rows = append(s.rows, rows...)
+	return nil
+}
+
+func TestSinksReceiveOnlyNewRows(t *testing.T) {
+	store := newMemStore()
+	sink := &recordingSink{}
+	ing := newTestIngestor(store, 100)
+	ing.AddSink(sink)
+
+	for i := 0; i < 2; i++ {
+		for _, e := range batch(t) {
+			if err := ing.Handle(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := ing.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(sink.rows) != len(batch(t)) {
+		t.Errorf("sink rows = %d, want %d (replayed rows must not reach sinks)", len(sink.rows), len(batch(t)))
+	}
+}
+
+// fixedRegions maps tenants to regions.
+type fixedRegions map[string]residency.Region
+
+func (f fixedRegions) Region(_ context.Context, tenantID string) (residency.Region, error) {
+	return f[tenantID], nil
+}
+
+func TestRegionalStore_EUTenantWritesToEUStore(t *testing.T) {
+	defaultStore, euStore := newMemStore(), newMemStore()
+	store := NewRegionalStore(defaultStore, map[residency.Region]Store{residency.RegionEU: euStore},
+		fixedRegions{"tenant-eu": residency.RegionEU, "tenant-br": residency.RegionBR}, zap.NewNop())
+	ctx := context.Background()
+
+	rows := []Row{
+		{EventID: "evt-1", TenantID: "tenant-eu", EventType: "call.started", OccurredAt: at},
+		{EventID: "evt-2", TenantID: "tenant-us", EventType: "call.started", OccurredAt: at},
+		{EventID: "evt-3", TenantID: "tenant-br", EventType: "call.started", OccurredAt: at},
+	}
+	if err := store.Insert(ctx, TableCalls, rows); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	if eu := euStore.rows(TableCalls); len(eu) != 1 || eu[0].EventID != "evt-1" {
+		t.Errorf("expected the EU tenant's row in the EU store, got %+v", eu)
+	}
+	// BR has no store, so its row goes nowhere rather than to the default
+	if def := defaultStore.rows(TableCalls); len(def) != 1 || def[0].EventID != "evt-2" {
+		t.Errorf("expected only the row without residency in the default store, got %+v", def)
+	}
+
+	existing, err := store.ExistingIDs(ctx, TableCalls, []string{"evt-1", "evt-2", "evt-3"})
+	if err != nil {
+		t.Fatalf("ExistingIDs failed: %v", err)
+	}
+	if !existing["evt-1"] || !existing["evt-2"] || existing["evt-3"] {
+		t.Errorf("unexpected existing ids %v", existing)
+	}
+}
+
+// fixedPolicies maps tenants to redaction policies.
+type fixedPolicies map[string]redact.Policy
+
+func (f fixedPolicies) RedactionPolicy(_ context.Context, tenantID string) (redact.Policy, error) {
+	return f[tenantID], nil
+}
+
+func TestIngestorRedactsPayloads(t *testing.T) {
+	store := newMemStore()
+	ing := newTestIngestor(store, 100)
+	ing.SetRedaction(fixedPolicies{"tenant-1": {Enabled: true}}, redact.New(nil))
+
+	spoken := func(id, tenantID string) *types.Event {
+		e := types.NewEvent(topics.MessageReceived, "agent-orchestrator", map[string]any{
+			"text":  "my card is 4111 1111 1111 1111",
+			"turns": []any{"call me at maria@example.com", 2},
+		}).WithTenantID(tenantID)
+		e.ID = id
+		e.Timestamp = at
+		return e
+	}
+	ing.Handle(spoken("evt-redacted", "tenant-1"))
+	ing.Handle(spoken("evt-plain", "tenant-2"))
+	if err := ing.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	payloads := map[string]string{}
+	for _, row := range store.rows(TableInteractions) {
+		payloads[row.EventID] = row.Payload
+	}
+	var redacted struct {
+		Text  string `json:"text"`
+		Turns []any  `json:"turns"`
+	}
+	if err := json.Unmarshal([]byte(payloads["evt-redacted"]), &redacted); err != nil {
+		t.Fatalf("redacted payload is not JSON: %v", err)
+	}
+	if redacted.Text != "my card is [CARD_NUMBER]" || redacted.Turns[0] != "call me at [EMAIL]" || redacted.Turns[1] != 2.0 {
+		t.Errorf("unexpected redacted payload %s", payloads["evt-redacted"])
+	}
+	// Tenants without redaction keep their payloads
+	if !strings.Contains(payloads["evt-plain"], "4111 1111 1111 1111") {
+		t.Errorf("unexpected payload for a tenant without redaction %s", payloads["evt-plain"])
+	}
+}
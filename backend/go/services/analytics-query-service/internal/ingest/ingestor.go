@@ -0,0 +1,233 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+	"go.uber.org/zap"
+)
+
+// Store persists rows in the analytics tables.
+type Store interface {
+	// ExistingIDs returns which of ids are already stored in table.
+	ExistingIDs(ctx context.Context, table string, ids []string) (map[string]bool, error)
+	// Insert appends rows to table in a single batch.
+	Insert(ctx context.Context, table string, rows []Row) error
+}
+
+// Sink receives the rows written to the store, after duplicates have been
+// dropped, e.g. to export them elsewhere. Errors are logged and do not fail
+// the flush.
+type Sink interface {
+	Write(ctx context.Context, table string, rows []Row) error
+}
+
+// Config controls batching.
+type Config struct {
+	BatchSize     int           // rows buffered before a flush
+	FlushInterval time.Duration // longest a row waits in the buffer
+}
+
+// DefaultConfig returns the batching defaults.
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:     500,
+		FlushInterval: 5 * time.Second,
+	}
+}
+
+// Ingestor buffers events and flushes them to the store in batches. Rows whose
+// event ID is already stored are skipped, which makes redelivery and replays
+// idempotent. Buffered rows are lost if the process dies before a flush;
+// replaying the partition from an earlier offset recovers them.
+type Ingestor struct {
+	store  Store
+	sinks  []Sink
+	config Config
+	logger *zap.Logger
+
+	policies RedactionPolicyResolver // optional; see SetRedaction
+	redactor *redact.Redactor
+
+	mu      sync.Mutex
+	pending []pendingRow
+	queued  map[string]bool // event IDs in pending
+
+	flushMu sync.Mutex // serializes flushes
+}
+
+// pendingRow is a buffered row and its table.
+type pendingRow struct {
+	table string
+	row   Row
+}
+
+// New creates an Ingestor.
+func New(store Store, config Config, logger *zap.Logger) *Ingestor {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultConfig().BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultConfig().FlushInterval
+	}
+
+	return &Ingestor{
+		store:  store,
+		config: config,
+		logger: logger,
+		queued: make(map[string]bool),
+	}
+}
+
+// AddSink registers a sink for inserted rows. It must be called before the
+// ingestor receives events.
+func (i *Ingestor) AddSink(sink Sink) {
+	i.sinks = append(i.sinks, sink)
+}
+
+// Handle buffers an event; it is a platform-events EventHandler. When the
+// buffer reaches the batch size it is flushed before returning, so a failed
+// insert is reported to the consumer, which retries the message.
+func (i *Ingestor) Handle(event *types.Event) error {
+	table, row, err := toRow(event)
+	if err != nil {
+		// Retrying would not help; skip the event
+		i.logger.Warn("skipping event", zap.String("event_id", event.ID), zap.Error(err))
+		return nil
+	}
+
+	i.mu.Lock()
+	if !i.queued[row.EventID] {
+		i.queued[row.EventID] = true
+		i.pending = append(i.pending, pendingRow{table: table, row: row})
+	}
+	full := len(i.pending) >= i.config.BatchSize
+	i.mu.Unlock()
+
+	if full {
+		return i.Flush(context.Background())
+	}
+	return nil
+}
+
+// Flush writes the buffered rows, one batch per table. Rows that could not be
+// written stay buffered for the next flush.
+func (i *Ingestor) Flush(ctx context.Context) error {
+	i.flushMu.Lock()
+	defer i.flushMu.Unlock()
+
+	i.mu.Lock()
+	pending := i.pending
+	i.pending = nil
+	i.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	byTable := make(map[string][]Row)
+	var order []string
+	for _, p := range pending {
+		if _, ok := byTable[p.table]; !ok {
+			order = append(order, p.table)
+		}
+		byTable[p.table] = append(byTable[p.table], p.row)
+	}
+
+	var firstErr error
+	var failed []pendingRow
+	for _, table := range order {
+		rows := byTable[table]
+		if err := i.insertNew(ctx, table, rows); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to flush %d rows to %s: %w", len(rows), table, err)
+			}
+			for _, row := range rows {
+				failed = append(failed, pendingRow{table: table, row: row})
+			}
+			continue
+		}
+	}
+
+	i.mu.Lock()
+	for _, p := range pending {
+		delete(i.queued, p.row.EventID)
+	}
+	for _, p := range failed {
+		i.queued[p.row.EventID] = true
+	}
+	i.pending = append(failed, i.pending...)
+	i.mu.Unlock()
+
+	return firstErr
+}
+
+// insertNew inserts the rows whose event ID is not yet stored.
+func (i *Ingestor) insertNew(ctx context.Context, table string, rows []Row) error {
+	ids := make([]string, len(rows))
+	for n, row := range rows {
+		ids[n] = row.EventID
+	}
+
+	existing, err := i.store.ExistingIDs(ctx, table, ids)
+	if err != nil {
+		return err
+	}
+
+	fresh := rows[:0:0]
+	for _, row := range rows {
+		if !existing[row.EventID] {
+			fresh = append(fresh, row)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	fresh, err = i.redactRows(ctx, fresh)
+	if err != nil {
+		return err
+	}
+	if err := i.store.Insert(ctx, table, fresh); err != nil {
+		return err
+	}
+	for _, sink := range i.sinks {
+		if err := sink.Write(ctx, table, fresh); err != nil {
+			i.logger.Error("sink failed", zap.String("table", table), zap.Error(err))
+		}
+	}
+
+	i.logger.Debug("flushed events",
+		zap.String("table", table),
+		zap.Int("inserted", len(fresh)),
+		zap.Int("duplicates", len(rows)-len(fresh)),
+	)
+	return nil
+}
+
+// Run flushes the buffer every FlushInterval until ctx is done.
+func (i *Ingestor) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := i.Flush(ctx); err != nil {
+				i.logger.Error("failed to flush events", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Close flushes the remaining rows. It is meant to run after the consumer
+// has stopped, within the shutdown deadline.
+func (i *Ingestor) Close(ctx context.Context) error {
+	return i.Flush(ctx)
+}
@@ -0,0 +1,110 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"go.uber.org/zap"
+)
+
+// RedactionPolicyResolver resolves the PII redaction policy of a tenant.
+type RedactionPolicyResolver interface {
+	RedactionPolicy(ctx context.Context, tenantID string) (redact.Policy, error)
+}
+
+// SetRedaction redacts the text of event payloads by each tenant's policy
+// before rows are stored or sent to sinks. It must be called before the
+// ingestor receives events.
+func (i *Ingestor) SetRedaction(policies RedactionPolicyResolver, redactor *redact.Redactor) {
+	i.policies = policies
+	i.redactor = redactor
+}
+
+// redactRows returns rows with their payloads redacted. A policy that can't
+// be resolved fails the flush, so the rows are retried rather than stored
+// unredacted.
+func (i *Ingestor) redactRows(ctx context.Context, rows []Row) ([]Row, error) {
+	if i.policies == nil {
+		return rows, nil
+	}
+
+	policies := make(map[string]redact.Policy)
+	out := make([]Row, len(rows))
+	for n, row := range rows {
+		policy, ok := policies[row.TenantID]
+		if !ok {
+			var err error
+			if policy, err = i.policies.RedactionPolicy(ctx, row.TenantID); err != nil {
+				return nil, fmt.Errorf("failed to resolve redaction policy of tenant %s: %w", row.TenantID, err)
+			}
+			policies[row.TenantID] = policy
+		}
+
+		out[n] = row
+		if !policy.Enabled {
+			continue
+		}
+		payload, err := i.redactPayload(ctx, policy, row.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to redact event %s: %w", row.EventID, err)
+		}
+		out[n].Payload = payload
+	}
+	return out, nil
+}
+
+// redactPayload redacts every string in a JSON payload, keeping it valid JSON.
+func (i *Ingestor) redactPayload(ctx context.Context, policy redact.Policy, payload string) (string, error) {
+	var data any
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		return "", err
+	}
+
+	var walk func(v any) (any, error)
+	walk = func(v any) (any, error) {
+		switch v := v.(type) {
+		case string:
+			redacted, _, err := i.redactor.Redact(ctx, policy, v)
+			if err != nil && redacted == "" {
+				return nil, err
+			}
+			if err != nil {
+				// The rules still applied; only the entity detector failed
+				i.logger.Warn("redaction incomplete", zap.Error(err))
+			}
+			return redacted, nil
+		case map[string]any:
+			for key, value := range v {
+				redacted, err := walk(value)
+				if err != nil {
+					return nil, err
+				}
+				v[key] = redacted
+			}
+			return v, nil
+		case []any:
+			for n, value := range v {
+				redacted, err := walk(value)
+				if err != nil {
+					return nil, err
+				}
+				v[n] = redacted
+			}
+			return v, nil
+		default:
+			return v, nil
+		}
+	}
+
+	redacted, err := walk(data)
+	if err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(redacted)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
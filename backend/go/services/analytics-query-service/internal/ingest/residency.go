@@ -0,0 +1,88 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"go.uber.org/zap"
+)
+
+// RegionResolver returns the data-residency region of a tenant.
+type RegionResolver interface {
+	Region(ctx context.Context, tenantID string) (residency.Region, error)
+}
+
+// RegionalStore is a Store that writes each tenant's rows to the store of its
+// data-residency region, and the rows of tenants without one to the default
+// store.
+type RegionalStore struct {
+	router   *residency.Router[Store]
+	resolver RegionResolver
+	logger   *zap.Logger
+}
+
+// NewRegionalStore creates a RegionalStore. Rows of tenants in a region
+// missing from regions are dropped rather than stored outside it.
+func NewRegionalStore(fallback Store, regions map[residency.Region]Store, resolver RegionResolver, logger *zap.Logger) *RegionalStore {
+	router := residency.NewRouter(fallback)
+	for region, store := range regions {
+		router.Add(region, store)
+	}
+	return &RegionalStore{router: router, resolver: resolver, logger: logger}
+}
+
+// ExistingIDs implements Store, looking in every region.
+func (s *RegionalStore) ExistingIDs(ctx context.Context, table string, ids []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for _, store := range s.router.All() {
+		found, err := store.ExistingIDs(ctx, table, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id := range found {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// Insert implements Store, one batch per region. A failed region fails the
+// insert; the ingestor retries it and skips the rows already stored.
+func (s *RegionalStore) Insert(ctx context.Context, table string, rows []Row) error {
+	regions := make(map[string]residency.Region)
+	byRegion := make(map[residency.Region][]Row)
+	var order []residency.Region
+	for _, row := range rows {
+		region, ok := regions[row.TenantID]
+		if !ok {
+			var err error
+			if region, err = s.resolver.Region(ctx, row.TenantID); err != nil {
+				return fmt.Errorf("failed to resolve data residency of tenant %s: %w", row.TenantID, err)
+			}
+			regions[row.TenantID] = region
+		}
+		if _, ok := byRegion[region]; !ok {
+			order = append(order, region)
+		}
+		byRegion[region] = append(byRegion[region], row)
+	}
+
+	for _, region := range order {
+		store, err := s.router.For(region)
+		if err != nil {
+			// Retrying would not help until the region gets a store
+			s.logger.Error("dropping rows outside their residency region",
+				zap.String("table", table),
+				zap.String("region", string(region)),
+				zap.Int("rows", len(byRegion[region])),
+				zap.Error(err),
+			)
+			continue
+		}
+		if err := store.Insert(ctx, table, byRegion[region]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
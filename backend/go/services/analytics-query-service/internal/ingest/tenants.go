@@ -0,0 +1,132 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+// TenantSettings reads the storage settings and plan of tenants from
+// tenant-manager and caches them. It is a RegionResolver and a
+// RedactionPolicyResolver.
+type TenantSettings struct {
+	baseURL string
+	client  *http.Client
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedSettings
+}
+
+// storageSettings are the tenant settings that decide how rows are stored,
+// and the plan that decides how much the tenant may query them.
+type storageSettings struct {
+	region    residency.Region
+	redaction redact.Policy
+	plan      entitlements.Plan
+}
+
+type cachedSettings struct {
+	settings storageSettings
+	expires  time.Time
+}
+
+// NewTenantSettings creates a TenantSettings for tenant-manager at baseURL.
+func NewTenantSettings(baseURL string, client *http.Client, ttl time.Duration) *TenantSettings {
+	return &TenantSettings{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+		ttl:     ttl,
+		cache:   make(map[string]cachedSettings),
+	}
+}
+
+// Region implements RegionResolver. Unknown tenants have no region.
+func (t *TenantSettings) Region(ctx context.Context, tenantID string) (residency.Region, error) {
+	settings, err := t.get(ctx, tenantID)
+	return settings.region, err
+}
+
+// RedactionPolicy implements RedactionPolicyResolver. Unknown tenants have
+// redaction disabled.
+func (t *TenantSettings) RedactionPolicy(ctx context.Context, tenantID string) (redact.Policy, error) {
+	settings, err := t.get(ctx, tenantID)
+	return settings.redaction, err
+}
+
+// Plan returns the tenant's plan. Unknown tenants, and tenants with a plan
+// this service doesn't know, have no plan.
+func (t *TenantSettings) Plan(ctx context.Context, tenantID string) (entitlements.Plan, error) {
+	settings, err := t.get(ctx, tenantID)
+	return settings.plan, err
+}
+
+func (t *TenantSettings) get(ctx context.Context, tenantID string) (storageSettings, error) {
+	if tenantID == "" {
+		return storageSettings{}, nil
+	}
+
+	t.mu.Lock()
+	cached, ok := t.cache[tenantID]
+	t.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.settings, nil
+	}
+
+	settings, err := t.fetch(ctx, tenantID)
+	if err != nil {
+		return storageSettings{}, err
+	}
+
+	t.mu.Lock()
+	t.cache[tenantID] = cachedSettings{settings: settings, expires: time.Now().Add(t.ttl)}
+	t.mu.Unlock()
+	return settings, nil
+}
+
+func (t *TenantSettings) fetch(ctx context.Context, tenantID string) (storageSettings, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.baseURL+"/api/v1/tenants/"+url.PathEscape(tenantID), nil)
+	if err != nil {
+		return storageSettings{}, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return storageSettings{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return storageSettings{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return storageSettings{}, fmt.Errorf("tenant-manager returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Plan     string `json:"plan"`
+		Settings struct {
+			DataResidency string `json:"data_residency"`
+			Security      struct {
+				Redaction redact.Policy `json:"redaction"`
+			} `json:"security"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return storageSettings{}, fmt.Errorf("failed to decode tenant: %w", err)
+	}
+	region, err := residency.Parse(body.Settings.DataResidency)
+	if err != nil {
+		return storageSettings{}, fmt.Errorf("tenant %s: %w", tenantID, err)
+	}
+	plan, _ := entitlements.ParsePlan(body.Plan)
+	return storageSettings{region: region, redaction: body.Settings.Security.Redaction, plan: plan}, nil
+}
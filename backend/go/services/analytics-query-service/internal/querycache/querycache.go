@@ -0,0 +1,161 @@
+// Package querycache caches the results of dashboard queries, which poll the
+// same ranges over and over, so repeated requests don't reach ClickHouse.
+package querycache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/services/analytics-query-service/internal/rollup"
+)
+
+// Store holds cached results. Get reports a missing key with ok false.
+type Store interface {
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Config configures a Cache.
+type Config struct {
+	// TTL is how long results of complete buckets are kept.
+	TTL time.Duration
+	// CurrentBucketTTL is how long results reaching into the current,
+	// still filling, hourly bucket are kept. Zero never caches them.
+	CurrentBucketTTL time.Duration
+}
+
+// DefaultConfig returns the cache defaults.
+func DefaultConfig() Config {
+	return Config{
+		TTL:              5 * time.Minute,
+		CurrentBucketTTL: 30 * time.Second,
+	}
+}
+
+// Request identifies a cacheable query.
+type Request struct {
+	TenantID string
+	Endpoint string
+	Params   url.Values
+}
+
+// ignoredParams don't change a query's result. tenant_id is part of every
+// key on its own.
+var ignoredParams = map[string]bool{"tenant_id": true, "nocache": true}
+
+// Cache caches query results by (tenant, endpoint, normalized params).
+// Results of a range reaching into the current hourly bucket are keyed by
+// that bucket too, so they are dropped as soon as the next bucket starts,
+// and are kept for CurrentBucketTTL at most. A store failure is a miss.
+type Cache struct {
+	store  Store
+	config Config
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// New creates a Cache.
+func New(store Store, config Config, logger *zap.Logger) *Cache {
+	if config.TTL <= 0 {
+		config.TTL = DefaultConfig().TTL
+	}
+	if config.CurrentBucketTTL < 0 {
+		config.CurrentBucketTTL = 0
+	}
+	return &Cache{
+		store:  store,
+		config: config,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Get returns the cached result of req.
+func (c *Cache) Get(ctx context.Context, req Request) ([]byte, bool) {
+	key, _, ok := c.key(req)
+	if !ok {
+		return nil, false
+	}
+
+	value, found, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.logger.Warn("query cache read failed", zap.String("tenant_id", req.TenantID), zap.Error(err))
+		return nil, false
+	}
+	return value, found
+}
+
+// Set caches the result of req.
+func (c *Cache) Set(ctx context.Context, req Request, value []byte) {
+	key, ttl, ok := c.key(req)
+	if !ok {
+		return
+	}
+
+	if err := c.store.Set(ctx, key, value, ttl); err != nil {
+		c.logger.Warn("query cache write failed", zap.String("tenant_id", req.TenantID), zap.Error(err))
+	}
+}
+
+// key returns req's key and TTL, or false when req is not cacheable.
+func (c *Cache) key(req Request) (string, time.Duration, bool) {
+	if req.TenantID == "" {
+		return "", 0, false
+	}
+
+	key := Key(req.TenantID, req.Endpoint, req.Params)
+	current := rollup.Hourly.Truncate(c.now())
+	if !reachesBucket(req.Params, current) {
+		return key, c.config.TTL, true
+	}
+	if c.config.CurrentBucketTTL == 0 {
+		return "", 0, false
+	}
+	return key + ":" + current.Format(time.RFC3339), min(c.config.TTL, c.config.CurrentBucketTTL), true
+}
+
+// Key is the cache key of a query, without the current bucket. It starts
+// with the tenant, so no tenant can read another's results.
+func Key(tenantID, endpoint string, params url.Values) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if !ignoredParams[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		values := append([]string(nil), params[name]...)
+		sort.Strings(values)
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				h.Write([]byte(name + "=" + v + "\n"))
+			}
+		}
+	}
+	return "analytics:query:" + url.QueryEscape(tenantID) + ":" + url.QueryEscape(endpoint) + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// reachesBucket reports whether the queried range ends after the start of
+// bucket. Ranges without to end now.
+func reachesBucket(params url.Values, bucket time.Time) bool {
+	to := params.Get("to")
+	if to == "" {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339, to)
+	if err != nil {
+		// Rejected by the handler; errors are not cached
+		return false
+	}
+	return t.After(bucket)
+}
@@ -0,0 +1,164 @@
+package querycache
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memStore is an in-memory Store recording TTLs.
+type memStore struct {
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newMemStore() *memStore {
+	return &memStore{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	v, ok := m.values[key]
+	return v, ok, nil
+}
+
+func (m *memStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.values[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+var now = time.Date(2025, 3, 10, 14, 25, 0, 0, time.UTC)
+
+func newTestCache(store Store, config Config) (*Cache, *time.Time) {
+	clock := now
+	c := New(store, config, zap.NewNop())
+	c.now = func() time.Time { return clock }
+	return c, &clock
+}
+
+// pastRange is a range of complete buckets.
+func pastRange() url.Values {
+	return url.Values{"from": {"2025-03-09T00:00:00Z"}, "to": {"2025-03-10T00:00:00Z"}}
+}
+
+func TestCacheHitAndMiss(t *testing.T) {
+	store := newMemStore()
+	c, _ := newTestCache(store, Config{TTL: time.Minute})
+	ctx := context.Background()
+	req := Request{TenantID: "tenant-1", Endpoint: "/api/v1/aggregations/hourly", Params: pastRange()}
+
+	if _, ok := c.Get(ctx, req); ok {
+		t.Fatal("Get() hit on an empty cache")
+	}
+	c.Set(ctx, req, []byte(`{"calls":3}`))
+
+	// Same query with params in another order and the ignored ones
+	same := url.Values{"to": {"2025-03-10T00:00:00Z"}, "from": {"2025-03-09T00:00:00Z"}, "nocache": {"false"}, "tenant_id": {"tenant-1"}}
+	got, ok := c.Get(ctx, Request{TenantID: "tenant-1", Endpoint: req.Endpoint, Params: same})
+	if !ok || string(got) != `{"calls":3}` {
+		t.Fatalf("Get() = %s, %v; want the cached result", got, ok)
+	}
+	for key, ttl := range store.ttls {
+		if ttl != time.Minute {
+			t.Errorf("TTL of %s = %s, want 1m", key, ttl)
+		}
+	}
+
+	misses := []Request{
+		{TenantID: "tenant-2", Endpoint: req.Endpoint, Params: pastRange()},
+		{TenantID: "tenant-1", Endpoint: "/api/v1/aggregations/daily", Params: pastRange()},
+		{TenantID: "tenant-1", Endpoint: req.Endpoint, Params: url.Values{"from": {"2025-03-08T00:00:00Z"}, "to": {"2025-03-10T00:00:00Z"}}},
+	}
+	for _, m := range misses {
+		if _, ok := c.Get(ctx, m); ok {
+			t.Errorf("Get(%+v) hit another query's result", m)
+		}
+	}
+}
+
+func TestCacheCurrentBucket(t *testing.T) {
+	store := newMemStore()
+	c, clock := newTestCache(store, Config{TTL: 5 * time.Minute, CurrentBucketTTL: 30 * time.Second})
+	ctx := context.Background()
+	req := Request{TenantID: "tenant-1", Endpoint: "/api/v1/aggregations/hourly", Params: url.Values{"range": {"24h"}}}
+
+	c.Set(ctx, req, []byte(`{"calls":3}`))
+	if _, ok := c.Get(ctx, req); !ok {
+		t.Fatal("Get() missed within the current bucket")
+	}
+	for key, ttl := range store.ttls {
+		if ttl != 30*time.Second {
+			t.Errorf("TTL of %s = %s, want the current bucket's 30s", key, ttl)
+		}
+	}
+
+	// The next bucket starts: the result no longer holds
+	*clock = time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	if _, ok := c.Get(ctx, req); ok {
+		t.Error("Get() hit a result of the previous bucket")
+	}
+}
+
+func TestCacheBypassesCurrentBucketWithoutTTL(t *testing.T) {
+	store := newMemStore()
+	c, _ := newTestCache(store, Config{TTL: 5 * time.Minute})
+	ctx := context.Background()
+
+	current := []url.Values{
+		{},
+		{"to": {now.Format(time.RFC3339)}},
+		{"from": {"2025-03-10T00:00:00Z"}, "to": {"2025-03-10T14:10:00Z"}},
+	}
+	for _, params := range current {
+		req := Request{TenantID: "tenant-1", Endpoint: "/api/v1/aggregations/hourly", Params: params}
+		c.Set(ctx, req, []byte(`{}`))
+		if _, ok := c.Get(ctx, req); ok {
+			t.Errorf("Get(%v) hit; the current bucket should bypass the cache", params)
+		}
+	}
+	if len(store.values) != 0 {
+		t.Errorf("stored %d results of the current bucket", len(store.values))
+	}
+
+	// A range ending where the current bucket starts is complete
+	past := Request{TenantID: "tenant-1", Endpoint: "/api/v1/aggregations/hourly", Params: url.Values{"to": {"2025-03-10T14:00:00Z"}}}
+	c.Set(ctx, past, []byte(`{}`))
+	if _, ok := c.Get(ctx, past); !ok {
+		t.Error("Get() missed a range of complete buckets")
+	}
+}
+
+func TestKeyStartsWithTenant(t *testing.T) {
+	a := Key("tenant:1", "/x", pastRange())
+	b := Key("tenant", "1:/x", pastRange())
+	if a == b {
+		t.Errorf("Key() collides across tenants: %s", a)
+	}
+	if !strings.HasPrefix(a, "analytics:query:tenant%3A1:") {
+		t.Errorf("Key() = %s, want the escaped tenant first", a)
+	}
+}
+
+func TestCacheStoreFailureIsMiss(t *testing.T) {
+	store := newMemStore()
+	store.err = errors.New("redis down")
+	c, _ := newTestCache(store, Config{TTL: time.Minute})
+	req := Request{TenantID: "tenant-1", Endpoint: "/x", Params: pastRange()}
+
+	c.Set(context.Background(), req, []byte(`{}`))
+	if _, ok := c.Get(context.Background(), req); ok {
+		t.Error("Get() hit with the store down")
+	}
+}
@@ -0,0 +1,136 @@
+// Package querylimit keeps one tenant's dashboards from monopolizing
+// ClickHouse: it caps each tenant's query rate and concurrent queries by
+// plan.
+package querylimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+// Errors wrapped by LimitError.
+var (
+	ErrRateLimited    = errors.New("tenant query rate limit exceeded")
+	ErrTooManyQueries = errors.New("tenant concurrent query limit reached")
+)
+
+// LimitError is returned when a tenant is over one of its limits.
+type LimitError struct {
+	Err        error         // ErrRateLimited or ErrTooManyQueries
+	RetryAfter time.Duration // when the query may be retried
+}
+
+func (e *LimitError) Error() string { return e.Err.Error() }
+
+func (e *LimitError) Unwrap() error { return e.Err }
+
+// PlanProvider provides the plan of a tenant.
+type PlanProvider interface {
+	Plan(ctx context.Context, tenantID string) (entitlements.Plan, error)
+}
+
+// Config configures a Limiter.
+type Config struct {
+	DefaultPlan entitlements.Plan // used when a tenant's plan can't be fetched or is unknown
+}
+
+// bucket is a tenant's token bucket and queries in flight.
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	inFlight int
+}
+
+// Limiter admits a tenant's queries within its plan's
+// LimitAnalyticsQueriesPerMinute, with the whole minute available as a burst,
+// and LimitMaxConcurrentAnalyticsQueries. Limits are per replica.
+type Limiter struct {
+	plans  PlanProvider
+	config Config
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// New creates a Limiter.
+func New(plans PlanProvider, config Config, logger *zap.Logger) *Limiter {
+	if !entitlements.IsValidPlan(config.DefaultPlan) {
+		config.DefaultPlan = entitlements.PlanStarter
+	}
+	return &Limiter{
+		plans:   plans,
+		config:  config,
+		logger:  logger,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Acquire admits a query of the tenant and returns the function that ends
+// it. It fails with a *LimitError when the tenant is over its rate or
+// already has as many queries running as its plan allows.
+func (l *Limiter) Acquire(ctx context.Context, tenantID string) (func(), error) {
+	perMinute, concurrent := l.limits(ctx, tenantID)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.buckets[tenantID]
+	if b == nil {
+		b = &bucket{tokens: float64(perMinute), updated: now}
+		l.buckets[tenantID] = b
+	}
+	if perMinute != entitlements.Unlimited {
+		refill := now.Sub(b.updated).Minutes() * float64(perMinute)
+		b.tokens = math.Min(float64(perMinute), b.tokens+refill)
+		b.updated = now
+	}
+
+	if concurrent != entitlements.Unlimited && b.inFlight >= concurrent {
+		return nil, &LimitError{Err: ErrTooManyQueries, RetryAfter: time.Second}
+	}
+	if perMinute != entitlements.Unlimited {
+		if b.tokens < 1 {
+			wait := time.Duration((1 - b.tokens) / float64(perMinute) * float64(time.Minute))
+			return nil, &LimitError{Err: ErrRateLimited, RetryAfter: wait}
+		}
+		b.tokens--
+	}
+	b.inFlight++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			b.inFlight--
+		})
+	}, nil
+}
+
+// limits returns the tenant's queries per minute and concurrent queries.
+func (l *Limiter) limits(ctx context.Context, tenantID string) (int, int) {
+	plan, err := l.plans.Plan(ctx, tenantID)
+	if err != nil {
+		l.logger.Warn("failed to get tenant plan, using default plan",
+			zap.String("tenant_id", tenantID),
+			zap.String("plan", string(l.config.DefaultPlan)),
+			zap.Error(err),
+		)
+	}
+	if !entitlements.IsValidPlan(plan) {
+		plan = l.config.DefaultPlan
+	}
+	return entitlements.Limit(plan, entitlements.LimitAnalyticsQueriesPerMinute),
+		entitlements.Limit(plan, entitlements.LimitMaxConcurrentAnalyticsQueries)
+}
@@ -0,0 +1,121 @@
+package querylimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+// fakePlans returns plans by tenant, or err for tenants without one.
+type fakePlans map[string]entitlements.Plan
+
+func (f fakePlans) Plan(_ context.Context, tenantID string) (entitlements.Plan, error) {
+	plan, ok := f[tenantID]
+	if !ok {
+		return "", errors.New("tenant-manager unavailable")
+	}
+	return plan, nil
+}
+
+func newTestLimiter(plans fakePlans) (*Limiter, *time.Time) {
+	clock := time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)
+	l := New(plans, Config{}, zap.NewNop())
+	l.now = func() time.Time { return clock }
+	return l, &clock
+}
+
+// admitted counts queries admitted, releasing each right away.
+func admitted(l *Limiter, tenantID string, n int) int {
+	var count int
+	for i := 0; i < n; i++ {
+		release, err := l.Acquire(context.Background(), tenantID)
+		if err != nil {
+			continue
+		}
+		release()
+		count++
+	}
+	return count
+}
+
+func TestAcquireLimitsRatePerTenant(t *testing.T) {
+	l, clock := newTestLimiter(fakePlans{"starter": entitlements.PlanStarter, "other": entitlements.PlanStarter})
+	limit := entitlements.Limit(entitlements.PlanStarter, entitlements.LimitAnalyticsQueriesPerMinute)
+
+	if got := admitted(l, "starter", limit+10); got != limit {
+		t.Fatalf("admitted %d queries, want the plan's %d per minute", got, limit)
+	}
+
+	_, err := l.Acquire(context.Background(), "starter")
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Acquire() error = %v, want ErrRateLimited", err)
+	}
+	if limitErr.RetryAfter <= 0 || limitErr.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %s, want within a minute", limitErr.RetryAfter)
+	}
+
+	// Other tenants have their own budget
+	if got := admitted(l, "other", 1); got != 1 {
+		t.Errorf("other tenant admitted %d, want 1", got)
+	}
+
+	// The budget refills over the minute
+	*clock = clock.Add(30 * time.Second)
+	if got := admitted(l, "starter", limit); got != limit/2 {
+		t.Errorf("after 30s admitted %d, want %d", got, limit/2)
+	}
+}
+
+func TestAcquireLimitsByPlan(t *testing.T) {
+	l, _ := newTestLimiter(fakePlans{"starter": entitlements.PlanStarter, "enterprise": entitlements.PlanEnterprise})
+
+	starter := admitted(l, "starter", 5000)
+	enterprise := admitted(l, "enterprise", 5000)
+	if enterprise != entitlements.Limit(entitlements.PlanEnterprise, entitlements.LimitAnalyticsQueriesPerMinute) {
+		t.Errorf("enterprise admitted %d", enterprise)
+	}
+	if enterprise <= starter {
+		t.Errorf("enterprise admitted %d, starter %d; want enterprise to get more", enterprise, starter)
+	}
+}
+
+func TestAcquireLimitsConcurrentQueries(t *testing.T) {
+	l, _ := newTestLimiter(fakePlans{"starter": entitlements.PlanStarter})
+	limit := entitlements.Limit(entitlements.PlanStarter, entitlements.LimitMaxConcurrentAnalyticsQueries)
+
+	var releases []func()
+	for i := 0; i < limit; i++ {
+		release, err := l.Acquire(context.Background(), "starter")
+		if err != nil {
+			t.Fatalf("Acquire() %d error = %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+	if _, err := l.Acquire(context.Background(), "starter"); !errors.Is(err, ErrTooManyQueries) {
+		t.Fatalf("Acquire() error = %v, want ErrTooManyQueries", err)
+	}
+
+	releases[0]()
+	releases[0]() // releasing twice frees one slot only
+	if _, err := l.Acquire(context.Background(), "starter"); err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	if _, err := l.Acquire(context.Background(), "starter"); !errors.Is(err, ErrTooManyQueries) {
+		t.Errorf("Acquire() error = %v, want ErrTooManyQueries", err)
+	}
+}
+
+func TestAcquireUsesDefaultPlanWhenUnknown(t *testing.T) {
+	l, _ := newTestLimiter(fakePlans{})
+
+	want := entitlements.Limit(entitlements.PlanStarter, entitlements.LimitAnalyticsQueriesPerMinute)
+	if got := admitted(l, "unknown", want+5); got != want {
+		t.Errorf("admitted %d, want the default plan's %d", got, want)
+	}
+}
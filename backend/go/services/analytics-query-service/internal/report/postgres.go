@@ -0,0 +1,80 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GormRepository implements Repository using PostgreSQL
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository creates a new PostgreSQL saved query repository
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// Create inserts a saved query
+func (r *GormRepository) Create(ctx context.Context, q *SavedQuery) error {
+	return r.db.WithContext(ctx).Create(q).Error
+}
+
+// Get finds a tenant's saved query by ID
+func (r *GormRepository) Get(ctx context.Context, tenantID, id string) (*SavedQuery, error) {
+	var q SavedQuery
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&q).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// List returns a tenant's saved queries by name
+func (r *GormRepository) List(ctx context.Context, tenantID string) ([]SavedQuery, error) {
+	var queries []SavedQuery
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("name").Find(&queries).Error
+	return queries, err
+}
+
+// Delete removes a tenant's saved query
+func (r *GormRepository) Delete(ctx context.Context, tenantID, id string) error {
+	result := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&SavedQuery{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListScheduled returns the scheduled queries of every tenant
+func (r *GormRepository) ListScheduled(ctx context.Context) ([]SavedQuery, error) {
+	var queries []SavedQuery
+	err := r.db.WithContext(ctx).Where("schedule <> ''").Find(&queries).Error
+	return queries, err
+}
+
+// Claim sets last_run_at to at if it is still the value q was read with
+func (r *GormRepository) Claim(ctx context.Context, q *SavedQuery, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&SavedQuery{}).
+		Where("id = ? AND last_run_at IS NOT DISTINCT FROM ?", q.ID, q.LastRunAt).
+		Updates(map[string]any{"last_run_at": at, "updated_at": at})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RecordDelivery stores the failure count and next retry of a scheduled query
+func (r *GormRepository) RecordDelivery(ctx context.Context, id string, failures int, retryAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&SavedQuery{}).Where("id = ?", id).
+		Updates(map[string]any{"failures": failures, "retry_at": retryAt}).Error
+}
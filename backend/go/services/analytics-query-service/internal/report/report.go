@@ -0,0 +1,136 @@
+// Package report stores named dashboard queries per tenant and runs them on
+// demand or on a schedule, posting scheduled results to a webhook.
+package report
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SavedQuery is a named query against an analytics endpoint.
+type SavedQuery struct {
+	ID         string     `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID   string     `gorm:"not null;index" json:"tenant_id"`
+	Name       string     `gorm:"not null" json:"name"`
+	Endpoint   string     `gorm:"not null" json:"endpoint"`
+	Params     Params     `gorm:"type:jsonb" json:"params,omitempty"`
+	Schedule   string     `gorm:"not null;default:''" json:"schedule,omitempty"` // Go duration; empty runs on demand only
+	WebhookURL string     `gorm:"not null;default:''" json:"webhook_url,omitempty"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	Failures   int        `gorm:"not null;default:0" json:"failures,omitempty"` // consecutive failed deliveries
+	RetryAt    *time.Time `json:"retry_at,omitempty"`                           // next attempt after a failed delivery
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (SavedQuery) TableName() string {
+	return "saved_queries"
+}
+
+// interval returns the schedule interval, or 0 if not scheduled.
+func (q *SavedQuery) interval() time.Duration {
+	d, _ := time.ParseDuration(q.Schedule)
+	return d
+}
+
+// due reports whether a scheduled query should run at now.
+func (q *SavedQuery) due(now time.Time) bool {
+	interval := q.interval()
+	if interval <= 0 {
+		return false
+	}
+	if q.RetryAt != nil {
+		return !q.RetryAt.After(now)
+	}
+	return q.LastRunAt == nil || !q.LastRunAt.Add(interval).After(now)
+}
+
+// backoff returns the delay before retrying after the given number of
+// consecutive failures.
+func (q *SavedQuery) backoff(failures int) time.Duration {
+	interval := q.interval()
+	delay := retryBackoff
+	for i := 1; i < failures && delay < interval; i++ {
+		delay *= 2
+	}
+	return min(delay, interval)
+}
+
+// Params are the query parameters sent to the endpoint, stored as JSONB.
+type Params map[string]string
+
+// Value implements driver.Valuer.
+func (p Params) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner.
+func (p *Params) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Params", src)
+	}
+	return json.Unmarshal(data, p)
+}
+
+// Result is the outcome of running a saved query.
+type Result struct {
+	ReportID string    `json:"report_id"`
+	Name     string    `json:"name"`
+	Endpoint string    `json:"endpoint"`
+	TenantID string    `json:"tenant_id"`
+	RanAt    time.Time `json:"ran_at"`
+	Data     any       `json:"data"`
+}
+
+// Executor runs an endpoint's query for a tenant.
+type Executor func(ctx context.Context, tenantID string, params map[string]string) (any, error)
+
+// Errors returned by the service.
+var (
+	ErrNotFound        = errors.New("saved query not found")
+	ErrUnknownEndpoint = errors.New("endpoint does not support saved queries")
+)
+
+// ValidationError reports an invalid saved query.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Repository persists saved queries. Every tenant-facing method is scoped by
+// tenant ID; a query of another tenant is reported as ErrNotFound.
+type Repository interface {
+	Create(ctx context.Context, q *SavedQuery) error
+	Get(ctx context.Context, tenantID, id string) (*SavedQuery, error)
+	List(ctx context.Context, tenantID string) ([]SavedQuery, error)
+	Delete(ctx context.Context, tenantID, id string) error
+	// ListScheduled returns the scheduled queries of every tenant.
+	ListScheduled(ctx context.Context) ([]SavedQuery, error)
+	// Claim sets the query's last run to at, provided it still has the
+	// LastRunAt that q was read with. It reports false when another caller
+	// claimed the run first.
+	Claim(ctx context.Context, q *SavedQuery, at time.Time) (bool, error)
+	// RecordDelivery stores the consecutive failure count and the time of the
+	// next retry, nil once a delivery succeeds.
+	RecordDelivery(ctx context.Context, id string, failures int, retryAt *time.Time) error
+}
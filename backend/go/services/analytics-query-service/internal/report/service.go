@@ -0,0 +1,272 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MinSchedule is the shortest allowed schedule interval.
+const MinSchedule = 15 * time.Minute
+
+// retryBackoff is the delay before retrying the first failed delivery. It
+// doubles with each consecutive failure, up to the query's schedule interval.
+const retryBackoff = time.Minute
+
+// Service manages saved queries.
+type Service struct {
+	repo      Repository
+	executors map[string]Executor
+	client    *http.Client
+	logger    *zap.Logger
+	now       func() time.Time
+	blocked   func(net.IP) bool
+	lookupIP  func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewService creates a Service. Endpoints must be registered before use.
+func NewService(repo Repository, logger *zap.Logger) *Service {
+	s := &Service{
+		repo:      repo,
+		executors: make(map[string]Executor),
+		logger:    logger,
+		now:       time.Now,
+		blocked:   isInternal,
+		lookupIP:  net.DefaultResolver.LookupIPAddr,
+	}
+	s.client = newWebhookClient(func(ip net.IP) bool { return s.blocked(ip) })
+	return s
+}
+
+// Register makes endpoint available to saved queries.
+func (s *Service) Register(endpoint string, exec Executor) {
+	s.executors[endpoint] = exec
+}
+
+// Endpoints returns the registered endpoints, sorted.
+func (s *Service) Endpoints() []string {
+	out := make([]string, 0, len(s.executors))
+	for endpoint := range s.executors {
+		out = append(out, endpoint)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Create validates and stores a saved query for q.TenantID.
+func (s *Service) Create(ctx context.Context, q *SavedQuery) error {
+	if err := s.validate(ctx, q); err != nil {
+		return err
+	}
+
+	// The tenant always comes from the saved query, never from its params
+	delete(q.Params, "tenant_id")
+
+	now := s.now().UTC()
+	q.ID = uuid.New().String()
+	q.LastRunAt = nil
+	q.Failures = 0
+	q.RetryAt = nil
+	q.CreatedAt = now
+	q.UpdatedAt = now
+
+	return s.repo.Create(ctx, q)
+}
+
+// validate checks a saved query before it is stored.
+func (s *Service) validate(ctx context.Context, q *SavedQuery) error {
+	if q.TenantID == "" {
+		return &ValidationError{Message: "tenant_id is required"}
+	}
+	if strings.TrimSpace(q.Name) == "" {
+		return &ValidationError{Message: "name is required"}
+	}
+	if _, ok := s.executors[q.Endpoint]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownEndpoint, q.Endpoint)
+	}
+
+	if q.Schedule != "" {
+		interval, err := time.ParseDuration(q.Schedule)
+		if err != nil {
+			return &ValidationError{Message: fmt.Sprintf("invalid schedule %q: use a duration such as 24h", q.Schedule)}
+		}
+		if interval < MinSchedule {
+			return &ValidationError{Message: fmt.Sprintf("schedule must be at least %s", MinSchedule)}
+		}
+		if q.WebhookURL == "" {
+			return &ValidationError{Message: "webhook_url is required for scheduled queries"}
+		}
+	}
+	if q.WebhookURL != "" {
+		u, err := url.Parse(q.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			return &ValidationError{Message: "webhook_url must be an http(s) URL"}
+		}
+		if err := s.checkWebhookHost(ctx, u.Hostname()); err != nil {
+			if errors.Is(err, errInternalAddress) {
+				return &ValidationError{Message: "webhook_url must point to a public address"}
+			}
+			return &ValidationError{Message: fmt.Sprintf("webhook_url host %q cannot be resolved", u.Hostname())}
+		}
+	}
+	return nil
+}
+
+// Get returns a tenant's saved query.
+func (s *Service) Get(ctx context.Context, tenantID, id string) (*SavedQuery, error) {
+	return s.repo.Get(ctx, tenantID, id)
+}
+
+// List returns a tenant's saved queries.
+func (s *Service) List(ctx context.Context, tenantID string) ([]SavedQuery, error) {
+	return s.repo.List(ctx, tenantID)
+}
+
+// Delete removes a tenant's saved query.
+func (s *Service) Delete(ctx context.Context, tenantID, id string) error {
+	return s.repo.Delete(ctx, tenantID, id)
+}
+
+// Run executes a tenant's saved query.
+func (s *Service) Run(ctx context.Context, tenantID, id string) (*Result, error) {
+	q, err := s.repo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, q)
+}
+
+// execute runs q with its stored params, scoped to its tenant.
+func (s *Service) execute(ctx context.Context, q *SavedQuery) (*Result, error) {
+	exec, ok := s.executors[q.Endpoint]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEndpoint, q.Endpoint)
+	}
+
+	params := make(map[string]string, len(q.Params))
+	for k, v := range q.Params {
+		params[k] = v
+	}
+
+	ranAt := s.now().UTC()
+	data, err := exec(ctx, q.TenantID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run saved query %s: %w", q.ID, err)
+	}
+
+	return &Result{
+		ReportID: q.ID,
+		Name:     q.Name,
+		Endpoint: q.Endpoint,
+		TenantID: q.TenantID,
+		RanAt:    ranAt,
+		Data:     data,
+	}, nil
+}
+
+// RunDue runs every scheduled query that is due and posts its result to the
+// query's webhook. Each run is claimed first, so a query runs once even when
+// several replicas call RunDue. A failed delivery is retried with exponential
+// backoff, capped at the schedule interval.
+func (s *Service) RunDue(ctx context.Context) error {
+	queries, err := s.repo.ListScheduled(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list scheduled queries: %w", err)
+	}
+
+	now := s.now().UTC()
+	for i := range queries {
+		q := &queries[i]
+		if !q.due(now) {
+			continue
+		}
+
+		claimed, err := s.repo.Claim(ctx, q, now)
+		if err != nil {
+			return fmt.Errorf("failed to claim run of %s: %w", q.ID, err)
+		}
+		if !claimed {
+			continue // another replica is running it
+		}
+
+		if err := s.deliver(ctx, q); err != nil {
+			failures := q.Failures + 1
+			retryAt := now.Add(q.backoff(failures))
+			s.logger.Warn("scheduled query failed",
+				zap.String("report_id", q.ID),
+				zap.String("tenant_id", q.TenantID),
+				zap.Int("failures", failures),
+				zap.Time("retry_at", retryAt),
+				zap.Error(err),
+			)
+			if err := s.repo.RecordDelivery(ctx, q.ID, failures, &retryAt); err != nil {
+				return fmt.Errorf("failed to record failed run of %s: %w", q.ID, err)
+			}
+			continue
+		}
+		if q.Failures > 0 {
+			if err := s.repo.RecordDelivery(ctx, q.ID, 0, nil); err != nil {
+				return fmt.Errorf("failed to record run of %s: %w", q.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// deliver runs q and posts the result to its webhook.
+func (s *Service) deliver(ctx context.Context, q *SavedQuery) error {
+	result, err := s.execute(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post result: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// RunScheduler calls RunDue every interval until ctx is done.
+func (s *Service) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunDue(ctx); err != nil {
+				s.logger.Error("report scheduler failed", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,429 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// memRepository is an in-memory Repository.
+type memRepository struct {
+	mu      sync.Mutex
+	queries map[string]SavedQuery
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{queries: make(map[string]SavedQuery)}
+}
+
+func (r *memRepository) Create(_ context.Context, q *SavedQuery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries[q.ID] = *q
+	return nil
+}
+
+func (r *memRepository) Get(_ context.Context, tenantID, id string) (*SavedQuery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q, ok := r.queries[id]
+	if !ok || q.TenantID != tenantID {
+		return nil, ErrNotFound
+	}
+	return &q, nil
+}
+
+func (r *memRepository) List(_ context.Context, tenantID string) ([]SavedQuery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []SavedQuery
+	for _, q := range r.queries {
+		if q.TenantID == tenantID {
+			out = append(out, q)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepository) Delete(_ context.Context, tenantID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q, ok := r.queries[id]
+	if !ok || q.TenantID != tenantID {
+		return ErrNotFound
+	}
+	delete(r.queries, id)
+	return nil
+}
+
+func (r *memRepository) ListScheduled(context.Context) ([]SavedQuery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []SavedQuery
+	for _, q := range r.queries {
+		if q.Schedule != "" {
+			out = append(out, q)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepository) Claim(_ context.Context, claimed *SavedQuery, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q := r.queries[claimed.ID]
+	if (q.LastRunAt == nil) != (claimed.LastRunAt == nil) || (q.LastRunAt != nil && !q.LastRunAt.Equal(*claimed.LastRunAt)) {
+		return false, nil
+	}
+	q.LastRunAt = &at
+	r.queries[claimed.ID] = q
+	return true, nil
+}
+
+func (r *memRepository) RecordDelivery(_ context.Context, id string, failures int, retryAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	q := r.queries[id]
+	q.Failures = failures
+	q.RetryAt = retryAt
+	r.queries[id] = q
+	return nil
+}
+
+// call records an executor invocation.
+type call struct {
+	tenantID string
+	params   map[string]string
+}
+
+func newTestService(t *testing.T) (*Service, *[]call) {
+	t.Helper()
+	return newTestServiceWithRepo(t, newMemRepository())
+}
+
+func newTestServiceWithRepo(t *testing.T, repo Repository) (*Service, *[]call) {
+	t.Helper()
+	var calls []call
+	s := NewService(repo, zap.NewNop())
+	// Test webhooks listen on loopback
+	s.blocked = func(ip net.IP) bool { return false }
+	s.Register("/aggregations/hourly", func(_ context.Context, tenantID string, params map[string]string) (any, error) {
+		calls = append(calls, call{tenantID, params})
+		return map[string]any{"tenant": tenantID, "range": params["range"]}, nil
+	})
+	return s, &calls
+}
+
+func TestCreateAndRunRoundTrip(t *testing.T) {
+	s, calls := newTestService(t)
+	ctx := context.Background()
+
+	q := &SavedQuery{
+		TenantID: "tenant-1",
+		Name:     "Last week by hour",
+		Endpoint: "/aggregations/hourly",
+		Params:   Params{"range": "168h", "tenant_id": "tenant-2"},
+	}
+	if err := s.Create(ctx, q); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if q.ID == "" {
+		t.Fatal("Create() did not assign an ID")
+	}
+
+	result, err := s.Run(ctx, "tenant-1", q.ID)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.ReportID != q.ID || result.Name != q.Name || result.TenantID != "tenant-1" {
+		t.Errorf("Run() = %+v", result)
+	}
+
+	if len(*calls) != 1 {
+		t.Fatalf("executor calls = %d, want 1", len(*calls))
+	}
+	got := (*calls)[0]
+	if got.tenantID != "tenant-1" {
+		t.Errorf("executor tenant = %q, want the saved query's tenant", got.tenantID)
+	}
+	if got.params["range"] != "168h" {
+		t.Errorf("executor params = %v", got.params)
+	}
+	if _, ok := got.params["tenant_id"]; ok {
+		t.Error("tenant_id param was not stripped")
+	}
+
+	list, err := s.List(ctx, "tenant-1")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List() = %v, %v", list, err)
+	}
+	if err := s.Delete(ctx, "tenant-1", q.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Run(ctx, "tenant-1", q.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Run() after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSavedQueriesAreTenantScoped(t *testing.T) {
+	s, calls := newTestService(t)
+	ctx := context.Background()
+
+	q := &SavedQuery{TenantID: "tenant-1", Name: "Mine", Endpoint: "/aggregations/hourly"}
+	if err := s.Create(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Get(ctx, "tenant-2", q.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() from other tenant error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Run(ctx, "tenant-2", q.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Run() from other tenant error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "tenant-2", q.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() from other tenant error = %v, want ErrNotFound", err)
+	}
+	if list, _ := s.List(ctx, "tenant-2"); len(list) != 0 {
+		t.Errorf("List() for other tenant = %v", list)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("executor ran %d times for another tenant", len(*calls))
+	}
+}
+
+func TestCreateValidation(t *testing.T) {
+	s, _ := newTestService(t)
+
+	tests := []struct {
+		name string
+		q    SavedQuery
+	}{
+		{"missing name", SavedQuery{TenantID: "t", Endpoint: "/aggregations/hourly"}},
+		{"unknown endpoint", SavedQuery{TenantID: "t", Name: "n", Endpoint: "/metrics/overview"}},
+		{"bad schedule", SavedQuery{TenantID: "t", Name: "n", Endpoint: "/aggregations/hourly", Schedule: "daily", WebhookURL: "https://example.com"}},
+		{"schedule too short", SavedQuery{TenantID: "t", Name: "n", Endpoint: "/aggregations/hourly", Schedule: "1m", WebhookURL: "https://example.com"}},
+		{"schedule without webhook", SavedQuery{TenantID: "t", Name: "n", Endpoint: "/aggregations/hourly", Schedule: "24h"}},
+		{"bad webhook", SavedQuery{TenantID: "t", Name: "n", Endpoint: "/aggregations/hourly", WebhookURL: "ftp://example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Create(context.Background(), &tt.q); err == nil {
+				t.Error("Create() error = nil")
+			}
+		})
+	}
+}
+
+func TestRunDuePostsToWebhook(t *testing.T) {
+	var mu sync.Mutex
+	var received []Result
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result Result
+		if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		mu.Lock()
+		received = append(received, result)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	s, _ := newTestService(t)
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	q := &SavedQuery{TenantID: "tenant-1", Name: "Daily", Endpoint: "/aggregations/hourly", Schedule: "24h", WebhookURL: webhook.URL}
+	if err := s.Create(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.RunDue(ctx); err != nil {
+		t.Fatalf("RunDue() error = %v", err)
+	}
+	// Not due again until the interval has passed
+	now = now.Add(time.Hour)
+	if err := s.RunDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(23 * time.Hour)
+	if err := s.RunDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("webhook deliveries = %d, want 2", len(received))
+	}
+	if received[0].ReportID != q.ID || received[0].TenantID != "tenant-1" {
+		t.Errorf("delivered result = %+v", received[0])
+	}
+}
+
+func TestCreateRejectsInternalWebhooks(t *testing.T) {
+	s := NewService(newMemRepository(), zap.NewNop())
+	s.Register("/aggregations/hourly", func(context.Context, string, map[string]string) (any, error) { return nil, nil })
+	s.lookupIP = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "hooks.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}}, nil
+		case "internal.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}, {IP: net.ParseIP("10.0.3.7")}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://hooks.example.com/reports", false},
+		{"http://203.0.113.10:8080/reports", false},
+		{"http://127.0.0.1:8080/reports", true},
+		{"http://[::1]/reports", true},
+		{"http://10.1.2.3/reports", true},
+		{"http://192.168.0.10/reports", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://0.0.0.0/reports", true},
+		{"https://internal.example.com/reports", true},
+		{"https://unknown.example.com/reports", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			q := &SavedQuery{TenantID: "t", Name: "n", Endpoint: "/aggregations/hourly", Schedule: "24h", WebhookURL: tt.url}
+			err := s.Create(context.Background(), q)
+			var validationErr *ValidationError
+			if tt.wantErr && !errors.As(err, &validationErr) {
+				t.Errorf("Create() error = %v, want a ValidationError", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Create() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDeliverRefusesInternalAddressAtDial(t *testing.T) {
+	var hits int
+	webhook := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hits++ }))
+	defer webhook.Close()
+
+	// A webhook that passed validation but now resolves to loopback
+	s, _ := newTestService(t)
+	s.blocked = isInternal
+	q := &SavedQuery{ID: "r1", TenantID: "t", Name: "n", Endpoint: "/aggregations/hourly", Schedule: "24h", WebhookURL: webhook.URL}
+
+	err := s.deliver(context.Background(), q)
+	if !errors.Is(err, errInternalAddress) {
+		t.Errorf("deliver() error = %v, want errInternalAddress", err)
+	}
+	if hits != 0 {
+		t.Errorf("webhook received %d requests", hits)
+	}
+}
+
+func TestRunDueDeliversOnceAcrossReplicas(t *testing.T) {
+	var mu sync.Mutex
+	var deliveries int
+	release := make(chan struct{})
+	webhook := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		mu.Lock()
+		deliveries++
+		mu.Unlock()
+		<-release
+	}))
+	defer webhook.Close()
+
+	repo := newMemRepository()
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	replicas := make([]*Service, 2)
+	for i := range replicas {
+		replicas[i], _ = newTestServiceWithRepo(t, repo)
+		replicas[i].now = func() time.Time { return now }
+	}
+	q := &SavedQuery{TenantID: "tenant-1", Name: "Daily", Endpoint: "/aggregations/hourly", Schedule: "24h", WebhookURL: webhook.URL}
+	if err := replicas[0].Create(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both replicas list the query before either has run it
+	var wg sync.WaitGroup
+	for _, s := range replicas {
+		wg.Add(1)
+		go func(s *Service) {
+			defer wg.Done()
+			if err := s.RunDue(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}(s)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if deliveries != 1 {
+		t.Errorf("webhook deliveries = %d, want 1", deliveries)
+	}
+}
+
+func TestRunDueBacksOffFailingWebhook(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	status := http.StatusServiceUnavailable
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		w.WriteHeader(status)
+	}))
+	defer webhook.Close()
+
+	repo := newMemRepository()
+	s, _ := newTestServiceWithRepo(t, repo)
+	now := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	q := &SavedQuery{TenantID: "tenant-1", Name: "Daily", Endpoint: "/aggregations/hourly", Schedule: "24h", WebhookURL: webhook.URL}
+	if err := s.Create(ctx, q); err != nil {
+		t.Fatal(err)
+	}
+
+	// Attempts at 0, +1m and +3m; nothing in between
+	for _, step := range []time.Duration{0, 30 * time.Second, 30 * time.Second, time.Minute, time.Minute} {
+		now = now.Add(step)
+		if err := s.RunDue(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+	stored, _ := repo.Get(ctx, "tenant-1", q.ID)
+	if stored.Failures != 3 || stored.RetryAt == nil || !stored.RetryAt.Equal(now.Add(4*time.Minute)) {
+		t.Errorf("after 3 failures: failures = %d, retry_at = %v", stored.Failures, stored.RetryAt)
+	}
+
+	// A successful retry resets the failures and the schedule resumes
+	mu.Lock()
+	status = http.StatusOK
+	mu.Unlock()
+	now = *stored.RetryAt
+	if err := s.RunDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ = repo.Get(ctx, "tenant-1", q.ID)
+	if stored.Failures != 0 || stored.RetryAt != nil {
+		t.Errorf("after success: failures = %d, retry_at = %v", stored.Failures, stored.RetryAt)
+	}
+	if !stored.due(now.Add(24*time.Hour)) || stored.due(now.Add(23*time.Hour)) {
+		t.Error("schedule did not resume from the successful run")
+	}
+}
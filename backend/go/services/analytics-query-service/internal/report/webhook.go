@@ -0,0 +1,76 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// errInternalAddress is returned when a webhook resolves to an address inside
+// the cluster or the host.
+var errInternalAddress = errors.New("webhook address is not public")
+
+// isInternal reports whether ip must not be reached by tenant webhooks:
+// loopback, private, link-local (including cloud metadata), unspecified and
+// multicast addresses.
+func isInternal(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// newWebhookClient returns a client that refuses to connect to addresses
+// rejected by blocked. The check runs on the resolved address of every
+// connection, so DNS rebinding and redirects cannot reach an internal host.
+func newWebhookClient(blocked func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || blocked(ip) {
+				return fmt.Errorf("%w: %s", errInternalAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// No proxy: the dialer must see the webhook's own address
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// checkWebhookHost resolves host and rejects it if any of its addresses is
+// internal.
+func (s *Service) checkWebhookHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if s.blocked(ip) {
+			return errInternalAddress
+		}
+		return nil
+	}
+
+	addrs, err := s.lookupIP(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host %q: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if s.blocked(addr.IP) {
+			return errInternalAddress
+		}
+	}
+	return nil
+}
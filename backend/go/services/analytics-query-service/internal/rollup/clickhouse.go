@@ -0,0 +1,187 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// ErrQueryTimeout is returned when a read runs longer than the store's query
+// timeout.
+var ErrQueryTimeout = errors.New("analytics query timed out")
+
+// timeoutExceeded is ClickHouse's TIMEOUT_EXCEEDED error code.
+const timeoutExceeded = 159
+
+// ClickHouseStore is the Store backed by ClickHouse. Calls, duration and
+// sentiment come from voice-gateway call.ended events, abandonment from
+// call.abandoned events; conversations and resolution from
+// agent.conversation.ended events.
+type ClickHouseStore struct {
+	conn    driver.Conn
+	timeout time.Duration
+}
+
+// NewClickHouseStore creates a ClickHouseStore.
+func NewClickHouseStore(conn driver.Conn) *ClickHouseStore {
+	return &ClickHouseStore{conn: conn}
+}
+
+// SetQueryTimeout bounds every read, on the client and as ClickHouse's
+// max_execution_time, failing it with ErrQueryTimeout. Zero leaves reads
+// unbounded. Rollups are written by the job and are not bounded.
+func (s *ClickHouseStore) SetQueryTimeout(d time.Duration) {
+	s.timeout = d
+}
+
+// aggregateColumns are the columns shared by rollup tables and raw queries.
+const aggregateColumns = "tenant_id, bucket, calls, duration_seconds, sentiment_sum, sentiment_count, conversations, resolved, abandoned, negative_sentiment"
+
+// rawQuery returns the query aggregating raw events of [from, to) into
+// buckets of g. With tenant set, it expects the tenant ID after each range.
+func rawQuery(g Granularity, tenant bool) string {
+	bucket := "toStartOfHour(occurred_at)"
+	if g == Daily {
+		bucket = "toStartOfDay(occurred_at)"
+	}
+	filter := "occurred_at >= ? AND occurred_at < ?"
+	if tenant {
+		filter += " AND tenant_id = ?"
+	}
+
+	return strings.NewReplacer("{bucket}", bucket, "{filter}", filter).Replace(`
+SELECT tenant_id, bucket,
+	sum(calls) AS calls,
+	sum(duration_seconds) AS duration_seconds,
+	sum(sentiment_sum) AS sentiment_sum,
+	sum(sentiment_count) AS sentiment_count,
+	sum(conversations) AS conversations,
+	sum(resolved) AS resolved,
+	sum(abandoned) AS abandoned,
+	sum(negative_sentiment) AS negative_sentiment
+FROM (
+	SELECT tenant_id, {bucket} AS bucket,
+		countIf(event_type = 'call.ended') AS calls,
+		toFloat64(sum(JSONExtractInt(payload, 'duration'))) / 1000 AS duration_seconds,
+		sumIf(JSONExtractFloat(payload, 'metadata', 'sentiment'), JSONHas(payload, 'metadata', 'sentiment')) AS sentiment_sum,
+		countIf(JSONHas(payload, 'metadata', 'sentiment')) AS sentiment_count,
+		toUInt64(0) AS conversations,
+		toUInt64(0) AS resolved,
+		countIf(event_type = 'call.abandoned') AS abandoned,
+		countIf(JSONHas(payload, 'metadata', 'sentiment') AND JSONExtractFloat(payload, 'metadata', 'sentiment') < 0) AS negative_sentiment
+	FROM call_events
+	WHERE event_type IN ('call.ended', 'call.abandoned') AND {filter}
+	GROUP BY tenant_id, bucket
+	UNION ALL
+	SELECT tenant_id, {bucket} AS bucket,
+		toUInt64(0), toFloat64(0), toFloat64(0), toUInt64(0),
+		count(),
+		countIf(JSONExtractString(payload, 'resolution') = 'resolved'),
+		toUInt64(0), toUInt64(0)
+	FROM interaction_events
+	WHERE event_type = 'agent.conversation.ended' AND {filter}
+	GROUP BY tenant_id, bucket
+)
+GROUP BY tenant_id, bucket`)
+}
+
+// Rollup implements Store. Rows are replaced by (tenant_id, bucket), so
+// recomputing a bucket overwrites the previous result.
+func (s *ClickHouseStore) Rollup(ctx context.Context, g Granularity, from, to time.Time) error {
+	query := fmt.Sprintf("INSERT INTO %s (%s) %s", g.Table(), aggregateColumns, rawQuery(g, false))
+	if err := s.conn.Exec(ctx, query, from, to, from, to); err != nil {
+		return fmt.Errorf("failed to compute rollups: %w", err)
+	}
+	return nil
+}
+
+// ReadRollups implements Store.
+func (s *ClickHouseStore) ReadRollups(ctx context.Context, g Granularity, tenantID string, from, to time.Time) ([]Aggregate, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s FINAL WHERE tenant_id = ? AND bucket >= ? AND bucket < ? ORDER BY bucket",
+		aggregateColumns, g.Table())
+	return s.query(ctx, query, tenantID, from, to)
+}
+
+// ReadRaw implements Store.
+func (s *ClickHouseStore) ReadRaw(ctx context.Context, g Granularity, tenantID string, from, to time.Time) ([]Aggregate, error) {
+	return s.query(ctx, rawQuery(g, true)+" ORDER BY bucket", from, to, tenantID, from, to, tenantID)
+}
+
+// query runs a query returning aggregateColumns.
+func (s *ClickHouseStore) query(ctx context.Context, query string, args ...any) ([]Aggregate, error) {
+	ctx, cancel := s.bounded(ctx)
+	defer cancel()
+
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query aggregates: %w", timedOut(ctx, err))
+	}
+	defer rows.Close()
+
+	var out []Aggregate
+	for rows.Next() {
+		var a Aggregate
+		if err := rows.Scan(&a.TenantID, &a.Bucket, &a.Calls, &a.DurationSeconds,
+			&a.SentimentSum, &a.SentimentCount, &a.Conversations, &a.Resolved,
+			&a.Abandoned, &a.NegativeSentiment); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregate: %w", timedOut(ctx, err))
+		}
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read aggregates: %w", timedOut(ctx, err))
+	}
+	return out, nil
+}
+
+// ListTenants implements Store.
+func (s *ClickHouseStore) ListTenants(ctx context.Context, g Granularity, from, to time.Time) ([]string, error) {
+	ctx, cancel := s.bounded(ctx)
+	defer cancel()
+
+	query := fmt.Sprintf("SELECT DISTINCT tenant_id FROM %s FINAL WHERE bucket >= ? AND bucket < ?", g.Table())
+	rows, err := s.conn.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", timedOut(ctx, err))
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", timedOut(ctx, err))
+		}
+		out = append(out, tenantID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", timedOut(ctx, err))
+	}
+	return out, nil
+}
+
+// bounded applies the query timeout to ctx. With a deadline in the context,
+// the driver also sends it to ClickHouse as max_execution_time.
+func (s *ClickHouseStore) bounded(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	return clickhouse.Context(ctx), cancel
+}
+
+// timedOut reports err as ErrQueryTimeout when the query ran out of time,
+// either on the client or on ClickHouse.
+func timedOut(ctx context.Context, err error) error {
+	var exception *clickhouse.Exception
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) ||
+		(errors.As(err, &exception) && exception.Code == timeoutExceeded) {
+		return ErrQueryTimeout
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package rollup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+// slowConn is a driver.Conn whose queries run until their context ends, or
+// fail with err when set.
+type slowConn struct {
+	driver.Conn
+	err error
+}
+
+func (c *slowConn) Query(ctx context.Context, _ string, _ ...any) (driver.Rows, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestClickHouseStoreQueryTimeout(t *testing.T) {
+	store := NewClickHouseStore(&slowConn{})
+	store.SetQueryTimeout(20 * time.Millisecond)
+
+	start := time.Now()
+	_, err := store.ReadRaw(context.Background(), Hourly, "tenant-1", now.Add(-time.Hour), now)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("ReadRaw() error = %v, want ErrQueryTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ReadRaw() took %s, want about the 20ms timeout", elapsed)
+	}
+
+	if _, err := store.ListTenants(context.Background(), Hourly, now.Add(-time.Hour), now); !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("ListTenants() error = %v, want ErrQueryTimeout", err)
+	}
+}
+
+func TestClickHouseStoreServerTimeout(t *testing.T) {
+	store := NewClickHouseStore(&slowConn{err: &clickhouse.Exception{Code: timeoutExceeded, Message: "Timeout exceeded"}})
+	store.SetQueryTimeout(time.Minute)
+
+	_, err := store.ReadRollups(context.Background(), Daily, "tenant-1", now.Add(-48*time.Hour), now)
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("ReadRollups() error = %v, want ErrQueryTimeout", err)
+	}
+}
+
+func TestClickHouseStoreOtherErrorsPassThrough(t *testing.T) {
+	store := NewClickHouseStore(&slowConn{err: errors.New("connection refused")})
+	store.SetQueryTimeout(time.Minute)
+
+	_, err := store.ReadRollups(context.Background(), Daily, "tenant-1", now.Add(-48*time.Hour), now)
+	if err == nil || errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("ReadRollups() error = %v, want the connection error", err)
+	}
+}
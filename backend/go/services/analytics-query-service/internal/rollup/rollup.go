@@ -0,0 +1,119 @@
+// Package rollup precomputes hourly and daily per-tenant aggregates from the
+// raw event tables, so dashboards don't scan raw events on every request.
+// Buckets are UTC. Queries read complete buckets from the rollup tables and
+// aggregate the rest, including the current incomplete bucket, from raw.
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Granularity is the size of a rollup bucket.
+type Granularity string
+
+// Supported granularities.
+const (
+	Hourly Granularity = "hourly"
+	Daily  Granularity = "daily"
+)
+
+// ParseGranularity parses "hourly" or "daily".
+func ParseGranularity(s string) (Granularity, error) {
+	switch g := Granularity(s); g {
+	case Hourly, Daily:
+		return g, nil
+	}
+	return "", fmt.Errorf("unknown granularity %q", s)
+}
+
+// Table returns the rollup table of g.
+func (g Granularity) Table() string {
+	if g == Daily {
+		return "call_rollups_daily"
+	}
+	return "call_rollups_hourly"
+}
+
+// Step returns the bucket size of g.
+func (g Granularity) Step() time.Duration {
+	if g == Daily {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// Truncate returns the start of the bucket containing t.
+func (g Granularity) Truncate(t time.Time) time.Time {
+	return t.UTC().Truncate(g.Step())
+}
+
+// Aggregate holds a tenant's totals for one bucket.
+type Aggregate struct {
+	TenantID          string    `json:"tenant_id"`
+	Bucket            time.Time `json:"bucket"`
+	Calls             uint64    `json:"calls"`
+	DurationSeconds   float64   `json:"duration_seconds"`
+	SentimentSum      float64   `json:"-"`
+	SentimentCount    uint64    `json:"-"`
+	Conversations     uint64    `json:"conversations"`
+	Resolved          uint64    `json:"resolved"`
+	Abandoned         uint64    `json:"abandoned"`
+	NegativeSentiment uint64    `json:"negative_sentiment"` // calls with a sentiment below zero
+}
+
+// AvgDuration returns the average call duration in seconds.
+func (a Aggregate) AvgDuration() float64 {
+	if a.Calls == 0 {
+		return 0
+	}
+	return a.DurationSeconds / float64(a.Calls)
+}
+
+// AvgSentiment returns the average sentiment of calls that reported one.
+func (a Aggregate) AvgSentiment() float64 {
+	if a.SentimentCount == 0 {
+		return 0
+	}
+	return a.SentimentSum / float64(a.SentimentCount)
+}
+
+// AbandonRate returns the share of calls the caller hung up on before
+// reaching an agent.
+func (a Aggregate) AbandonRate() float64 {
+	if a.Calls == 0 {
+		return 0
+	}
+	return float64(a.Abandoned) / float64(a.Calls)
+}
+
+// NegativeSentimentRate returns the share of calls with a sentiment that was
+// negative.
+func (a Aggregate) NegativeSentimentRate() float64 {
+	if a.SentimentCount == 0 {
+		return 0
+	}
+	return float64(a.NegativeSentiment) / float64(a.SentimentCount)
+}
+
+// ResolutionRate returns the share of conversations that ended resolved.
+func (a Aggregate) ResolutionRate() float64 {
+	if a.Conversations == 0 {
+		return 0
+	}
+	return float64(a.Resolved) / float64(a.Conversations)
+}
+
+// Store computes and reads aggregates. Ranges are [from, to) and aligned to
+// buckets of g.
+type Store interface {
+	// Rollup recomputes the rollups of g for every tenant in the range.
+	Rollup(ctx context.Context, g Granularity, from, to time.Time) error
+	// ReadRollups reads a tenant's precomputed aggregates.
+	ReadRollups(ctx context.Context, g Granularity, tenantID string, from, to time.Time) ([]Aggregate, error)
+	// ReadRaw aggregates a tenant's raw events.
+	ReadRaw(ctx context.Context, g Granularity, tenantID string, from, to time.Time) ([]Aggregate, error)
+	// ListTenants returns the tenants with rollups in the range.
+	ListTenants(ctx context.Context, g Granularity, from, to time.Time) ([]string, error)
+}
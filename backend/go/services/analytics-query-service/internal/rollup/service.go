@@ -0,0 +1,169 @@
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config controls the rollup job.
+type Config struct {
+	Interval time.Duration // how often the job runs
+	Lookback int           // complete buckets recomputed per run, to absorb late events
+}
+
+// DefaultConfig returns the rollup defaults.
+func DefaultConfig() Config {
+	return Config{
+		Interval: 5 * time.Minute,
+		Lookback: 3,
+	}
+}
+
+// Service runs the rollup job and answers aggregate queries.
+type Service struct {
+	store  Store
+	config Config
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu         sync.RWMutex
+	watermarks map[Granularity]time.Time // end of the last rolled-up range
+}
+
+// NewService creates a Service.
+func NewService(store Store, config Config, logger *zap.Logger) *Service {
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig().Interval
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = DefaultConfig().Lookback
+	}
+
+	return &Service{
+		store:      store,
+		config:     config,
+		logger:     logger,
+		now:        time.Now,
+		watermarks: make(map[Granularity]time.Time),
+	}
+}
+
+// RunOnce recomputes the last Lookback complete buckets of each granularity.
+func (s *Service) RunOnce(ctx context.Context) error {
+	for _, g := range []Granularity{Hourly, Daily} {
+		end := g.Truncate(s.now())
+		start := end.Add(-time.Duration(s.config.Lookback) * g.Step())
+
+		if err := s.store.Rollup(ctx, g, start, end); err != nil {
+			return fmt.Errorf("failed to roll up %s buckets: %w", g, err)
+		}
+
+		s.mu.Lock()
+		s.watermarks[g] = end
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// Run runs the job now and then every Interval until ctx is done.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(ctx); err != nil {
+			s.logger.Error("rollup job failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Backfill computes the rollups of g for [from, to), one chunk of 24
+// buckets at a time. Incomplete buckets are left to the job.
+func (s *Service) Backfill(ctx context.Context, g Granularity, from, to time.Time) error {
+	from = g.Truncate(from)
+	if limit := g.Truncate(s.now()); to.After(limit) {
+		to = limit
+	}
+
+	chunk := 24 * g.Step()
+	for start := from; start.Before(to); start = start.Add(chunk) {
+		end := start.Add(chunk)
+		if end.After(to) {
+			end = to
+		}
+		if err := s.store.Rollup(ctx, g, start, end); err != nil {
+			return fmt.Errorf("failed to backfill %s rollups from %s: %w", g, start.Format(time.RFC3339), err)
+		}
+		s.logger.Info("backfilled rollups",
+			zap.String("granularity", string(g)),
+			zap.Time("from", start),
+			zap.Time("to", end),
+		)
+	}
+	return nil
+}
+
+// Watermark returns the end of the range last rolled up for g, or the zero
+// time if the job has not run yet.
+func (s *Service) Watermark(g Granularity) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watermarks[g]
+}
+
+// Tenants returns the tenants with rolled-up activity in [from, to).
+func (s *Service) Tenants(ctx context.Context, g Granularity, from, to time.Time) ([]string, error) {
+	return s.store.ListTenants(ctx, g, from, to)
+}
+
+// Query returns a tenant's aggregates for the buckets overlapping
+// [from, to), oldest first. Buckets before the watermark come from the
+// rollups; later ones, such as the current bucket, are aggregated from raw
+// events.
+func (s *Service) Query(ctx context.Context, g Granularity, tenantID string, from, to time.Time) ([]Aggregate, error) {
+	from = g.Truncate(from)
+	if end := g.Truncate(to); end.Before(to) {
+		to = end.Add(g.Step())
+	}
+	if !from.Before(to) {
+		return nil, nil
+	}
+
+	split := s.Watermark(g)
+	if split.Before(from) {
+		split = from
+	}
+	if split.After(to) {
+		split = to
+	}
+
+	var out []Aggregate
+	if from.Before(split) {
+		rolled, err := s.store.ReadRollups(ctx, g, tenantID, from, split)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rollups: %w", err)
+		}
+		out = append(out, rolled...)
+	}
+	if split.Before(to) {
+		raw, err := s.store.ReadRaw(ctx, g, tenantID, split, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate raw events: %w", err)
+		}
+		out = append(out, raw...)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Bucket.Before(out[j].Bucket) })
+	return out, nil
+}
@@ -0,0 +1,166 @@
+package rollup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type span struct {
+	g        Granularity
+	from, to time.Time
+}
+
+// fakeStore records calls and returns one aggregate per bucket, tagged with
+// its source in TenantID.
+type fakeStore struct {
+	rollups []span
+}
+
+func (f *fakeStore) Rollup(_ context.Context, g Granularity, from, to time.Time) error {
+	f.rollups = append(f.rollups, span{g, from, to})
+	return nil
+}
+
+func (f *fakeStore) ReadRollups(_ context.Context, g Granularity, _ string, from, to time.Time) ([]Aggregate, error) {
+	return buckets("rollup", g, from, to), nil
+}
+
+func (f *fakeStore) ReadRaw(_ context.Context, g Granularity, _ string, from, to time.Time) ([]Aggregate, error) {
+	return buckets("raw", g, from, to), nil
+}
+
+func (f *fakeStore) ListTenants(context.Context, Granularity, time.Time, time.Time) ([]string, error) {
+	return []string{"tenant-1"}, nil
+}
+
+func buckets(source string, g Granularity, from, to time.Time) []Aggregate {
+	var out []Aggregate
+	for b := from; b.Before(to); b = b.Add(g.Step()) {
+		out = append(out, Aggregate{TenantID: source, Bucket: b})
+	}
+	return out
+}
+
+var now = time.Date(2025, 3, 10, 14, 25, 0, 0, time.UTC)
+
+func newTestService(store Store) *Service {
+	s := NewService(store, Config{Interval: time.Hour, Lookback: 2}, zap.NewNop())
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestRunOnceRecomputesRecentCompleteBuckets(t *testing.T) {
+	store := &fakeStore{}
+	s := newTestService(store)
+
+	if err := s.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce() error = %v", err)
+	}
+
+	want := []span{
+		{Hourly, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)},
+		{Daily, time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	if len(store.rollups) != len(want) {
+		t.Fatalf("rollups = %v, want %v", store.rollups, want)
+	}
+	for i := range want {
+		if store.rollups[i] != want[i] {
+			t.Errorf("rollup %d = %v, want %v", i, store.rollups[i], want[i])
+		}
+	}
+	if got := s.Watermark(Hourly); !got.Equal(want[0].to) {
+		t.Errorf("Watermark(Hourly) = %v, want %v", got, want[0].to)
+	}
+}
+
+func TestQueryReadsRollupsThenRaw(t *testing.T) {
+	s := newTestService(&fakeStore{})
+	if err := s.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Query(context.Background(), Hourly, "tenant-1", now.Add(-3*time.Hour), now)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	// 11:00-13:00 rolled up; 14:00 is the current incomplete bucket
+	want := []string{"rollup", "rollup", "rollup", "raw"}
+	if len(got) != len(want) {
+		t.Fatalf("Query() = %d buckets, want %d", len(got), len(want))
+	}
+	for i, a := range got {
+		if a.TenantID != want[i] {
+			t.Errorf("bucket %s from %s, want %s", a.Bucket.Format(time.RFC3339), a.TenantID, want[i])
+		}
+	}
+	if first := got[0].Bucket; !first.Equal(time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("first bucket = %v", first)
+	}
+}
+
+func TestQueryFallsBackToRawBeforeFirstRun(t *testing.T) {
+	s := newTestService(&fakeStore{})
+
+	got, err := s.Query(context.Background(), Daily, "tenant-1", now.Add(-48*time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range got {
+		if a.TenantID != "raw" {
+			t.Errorf("bucket %v from %s before the job ran", a.Bucket, a.TenantID)
+		}
+	}
+}
+
+func TestBackfillChunksAndStopsAtCurrentBucket(t *testing.T) {
+	store := &fakeStore{}
+	s := newTestService(store)
+
+	from := time.Date(2025, 3, 8, 6, 30, 0, 0, time.UTC)
+	if err := s.Backfill(context.Background(), Hourly, from, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Backfill() error = %v", err)
+	}
+
+	want := []span{
+		{Hourly, time.Date(2025, 3, 8, 6, 0, 0, 0, time.UTC), time.Date(2025, 3, 9, 6, 0, 0, 0, time.UTC)},
+		{Hourly, time.Date(2025, 3, 9, 6, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC)},
+		{Hourly, time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 14, 0, 0, 0, time.UTC)},
+	}
+	if len(store.rollups) != len(want) {
+		t.Fatalf("rollups = %v, want %v", store.rollups, want)
+	}
+	for i := range want {
+		if store.rollups[i] != want[i] {
+			t.Errorf("chunk %d = %v, want %v", i, store.rollups[i], want[i])
+		}
+	}
+}
+
+func TestAggregateRates(t *testing.T) {
+	a := Aggregate{Calls: 4, DurationSeconds: 200, SentimentSum: 1.5, SentimentCount: 3, Conversations: 4, Resolved: 3,
+		Abandoned: 1, NegativeSentiment: 1}
+
+	if got := a.AvgDuration(); got != 50 {
+		t.Errorf("AvgDuration() = %v", got)
+	}
+	if got := a.AvgSentiment(); got != 0.5 {
+		t.Errorf("AvgSentiment() = %v", got)
+	}
+	if got := a.ResolutionRate(); got != 0.75 {
+		t.Errorf("ResolutionRate() = %v", got)
+	}
+	if got := a.AbandonRate(); got != 0.25 {
+		t.Errorf("AbandonRate() = %v", got)
+	}
+	if got := a.NegativeSentimentRate(); got != 1.0/3 {
+		t.Errorf("NegativeSentimentRate() = %v", got)
+	}
+	if got := (Aggregate{}).ResolutionRate(); got != 0 {
+		t.Errorf("empty ResolutionRate() = %v", got)
+	}
+}
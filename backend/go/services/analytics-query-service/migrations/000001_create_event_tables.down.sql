@@ -0,0 +1,5 @@
+-- Drop tables
+DROP TABLE IF EXISTS call_events;
+DROP TABLE IF EXISTS interaction_events;
+DROP TABLE IF EXISTS decision_events;
+DROP TABLE IF EXISTS tool_events;
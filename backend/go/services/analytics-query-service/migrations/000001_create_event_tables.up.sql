@@ -0,0 +1,63 @@
+-- =============================================================================
+-- Migration: 000001_create_event_tables
+-- Description: Raw event tables populated by the ingestion consumer
+-- =============================================================================
+
+-- ReplacingMergeTree collapses rows with the same key on merge, a second
+-- line of defence behind the ingestor's event ID check.
+
+-- Call lifecycle events
+CREATE TABLE IF NOT EXISTS call_events (
+    event_id String,
+    event_type LowCardinality(String),
+    tenant_id String,
+    source LowCardinality(String),
+    occurred_at DateTime64(3, 'UTC'),
+    payload String,
+    ingested_at DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(ingested_at)
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (tenant_id, event_id);
+
+-- Conversation turns and messages
+CREATE TABLE IF NOT EXISTS interaction_events (
+    event_id String,
+    event_type LowCardinality(String),
+    tenant_id String,
+    source LowCardinality(String),
+    occurred_at DateTime64(3, 'UTC'),
+    payload String,
+    ingested_at DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(ingested_at)
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (tenant_id, event_id);
+
+-- Routing, transfer and compliance decisions
+CREATE TABLE IF NOT EXISTS decision_events (
+    event_id String,
+    event_type LowCardinality(String),
+    tenant_id String,
+    source LowCardinality(String),
+    occurred_at DateTime64(3, 'UTC'),
+    payload String,
+    ingested_at DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(ingested_at)
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (tenant_id, event_id);
+
+-- Tool invocations
+CREATE TABLE IF NOT EXISTS tool_events (
+    event_id String,
+    event_type LowCardinality(String),
+    tenant_id String,
+    source LowCardinality(String),
+    occurred_at DateTime64(3, 'UTC'),
+    payload String,
+    ingested_at DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(ingested_at)
+PARTITION BY toYYYYMM(occurred_at)
+ORDER BY (tenant_id, event_id);
@@ -0,0 +1,3 @@
+-- Drop tables
+DROP TABLE IF EXISTS call_rollups_hourly;
+DROP TABLE IF EXISTS call_rollups_daily;
@@ -0,0 +1,39 @@
+-- =============================================================================
+-- Migration: 000002_create_rollup_tables
+-- Description: Hourly and daily per-tenant aggregates computed by the rollup job
+-- =============================================================================
+
+-- The job recomputes recent buckets on every run; ReplacingMergeTree keeps
+-- the latest computation of each (tenant_id, bucket). Read with FINAL.
+
+-- Hourly buckets (UTC)
+CREATE TABLE IF NOT EXISTS call_rollups_hourly (
+    tenant_id String,
+    bucket DateTime('UTC'),
+    calls UInt64,
+    duration_seconds Float64,
+    sentiment_sum Float64,
+    sentiment_count UInt64,
+    conversations UInt64,
+    resolved UInt64,
+    computed_at DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(computed_at)
+PARTITION BY toYYYYMM(bucket)
+ORDER BY (tenant_id, bucket);
+
+-- Daily buckets (UTC)
+CREATE TABLE IF NOT EXISTS call_rollups_daily (
+    tenant_id String,
+    bucket DateTime('UTC'),
+    calls UInt64,
+    duration_seconds Float64,
+    sentiment_sum Float64,
+    sentiment_count UInt64,
+    conversations UInt64,
+    resolved UInt64,
+    computed_at DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(computed_at)
+PARTITION BY toYYYYMM(bucket)
+ORDER BY (tenant_id, bucket);
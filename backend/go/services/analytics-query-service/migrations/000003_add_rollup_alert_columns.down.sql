@@ -0,0 +1,3 @@
+-- Drop columns
+ALTER TABLE call_rollups_hourly DROP COLUMN IF EXISTS abandoned, DROP COLUMN IF EXISTS negative_sentiment;
+ALTER TABLE call_rollups_daily DROP COLUMN IF EXISTS abandoned, DROP COLUMN IF EXISTS negative_sentiment;
@@ -0,0 +1,12 @@
+-- =============================================================================
+-- Migration: 000003_add_rollup_alert_columns
+-- Description: Abandoned calls and negative sentiment, for anomaly alerts
+-- =============================================================================
+
+ALTER TABLE call_rollups_hourly
+    ADD COLUMN IF NOT EXISTS abandoned UInt64 DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS negative_sentiment UInt64 DEFAULT 0;
+
+ALTER TABLE call_rollups_daily
+    ADD COLUMN IF NOT EXISTS abandoned UInt64 DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS negative_sentiment UInt64 DEFAULT 0;
@@ -11,13 +11,24 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/health"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loglevel"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/adapter/http/handler"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/adapter/http/middleware"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/adapter/oauth"
 	postgresadapter "github.com/serphona/serphona/backend/go/services/auth-gateway/internal/adapter/postgres"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/config"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/serviceclient"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/notification"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/tenant"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/usecase/auth"
 	"go.uber.org/zap"
@@ -26,8 +37,12 @@ import (
 )
 
 func main() {
-	// Initialize logger
-	logger, err := zap.NewProduction()
+	// Initialize logger; the level is set from LOG_LEVEL once the
+	// configuration is loaded and changes on reload
+	logLevel := zap.NewAtomicLevel()
+	logConfig := zap.NewProductionConfig()
+	logConfig.Level = logLevel
+	logger, err := logConfig.Build()
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
@@ -38,6 +53,9 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to load config", zap.Error(err))
 	}
+	if err := logLevel.UnmarshalText([]byte(cfg.Server.LogLevel)); err != nil {
+		logger.Fatal("Invalid log level", zap.Error(err))
+	}
 
 	logger.Info("Starting auth-gateway service",
 		zap.String("env", cfg.Server.Env),
@@ -56,38 +74,110 @@ func main() {
 	}
 
 	// Initialize services
+	jwtKeys, err := initJWTKeys(cfg.JWT)
+	if err != nil {
+		logger.Fatal("Failed to initialize JWT keys", zap.Error(err))
+	}
+	for _, k := range jwtKeys.Expired() {
+		logger.Info("Retired JWT key no longer verifies any token; remove it from JWT_PREVIOUS_KEYS",
+			zap.String("kid", k.ID),
+		)
+	}
+
 	jwtService := jwt.NewService(
-		cfg.JWT.SecretKey,
+		jwtKeys,
 		cfg.JWT.AccessTokenDuration,
 		cfg.JWT.RefreshTokenDuration,
 	)
+	jwtService.SetAudience(cfg.JWT.Audience)
+	jwtService.SetCustomClaimsPolicy(jwt.CustomClaimsPolicy{
+		Allowed:  cfg.JWT.CustomClaimsAllowed,
+		MaxBytes: cfg.JWT.CustomClaimsMaxBytes,
+	})
 
 	userRepo := postgresadapter.NewUserRepository(db)
-	tenantService := tenant.NewService("http://localhost:8081") // TODO: Get from config
+	tenantService := tenant.NewService(cfg.TenantManager.URL)
+
+	// Audit log of privileged actions (best-effort, never blocks the action)
+	auditRepo := postgresadapter.NewAuditRepository(db)
+	auditLogger := audit.NewLogger(auditRepo, audit.Options{
+		OnError: func(e audit.Entry, err error) {
+			logger.Error("Failed to write audit entry",
+				zap.String("action", e.Action),
+				zap.String("actor", e.Actor),
+				zap.String("target_id", e.TargetID),
+				zap.Error(err),
+			)
+		},
+	})
 
 	authUC := auth.NewUseCase(
 		userRepo,
 		jwtService,
 		tenantService,
+		auditLogger,
 		cfg.JWT.AccessTokenDuration,
 	)
 
-	// Register OAuth providers
-	if err := registerOAuthProviders(authUC, cfg.OAuth, logger); err != nil {
-		logger.Error("Failed to register OAuth providers", zap.Error(err))
+	// User emails (welcome on registration), delivered in the background
+	notifier, err := notification.NewService(cfg.Notifications, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize notifications", zap.Error(err))
 	}
+	authUC.SetNotifier(notifier)
+
+	// Tenant of users registering without an invite
+	authUC.SetRegistrationPolicy(registrationPolicy(cfg.Registration))
+
+	// Static claims tenants add to their users' access tokens
+	authUC.SetTokenClaimsSource(tenantService)
+
+	// Client credentials grant for service-to-service calls
+	serviceClientUC := auth.NewServiceClientUseCase(
+		postgresadapter.NewServiceClientRepository(db),
+		jwtService,
+		auditLogger,
+		cfg.JWT.ServiceTokenDuration,
+	)
+
+	// Register OAuth providers
+	authUC.SetOAuthProviders(newOAuthProviders(cfg.OAuth, logger))
 
 	// Initialize HTTP handlers
 	authHandler := handler.NewAuthHandler(authUC, jwtService, logger)
-	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	adminHandler := handler.NewAdminHandler(authUC, auditRepo, logger)
+	serviceClientHandler := handler.NewServiceClientHandler(serviceClientUC, logger)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, logger)
+	if cfg.TenantManager.StatusCheck {
+		// Suspending a tenant blocks its users' tokens within the cache TTL
+		authMiddleware.SetTenantStatus(tenantstatus.NewCache(tenantService, tenantstatus.Config{
+			TTL:      cfg.TenantManager.StatusCacheTTL,
+			FailOpen: cfg.TenantManager.StatusFailOpen,
+		}))
+	}
+
+	// Readiness checks: logins need the database; tenant-manager is not
+	// critical
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatal("Failed to get database handle", zap.Error(err))
+	}
+	checker := health.NewChecker("auth-gateway")
+	checker.Register("database", sqlDB.PingContext, health.Critical())
+	checker.Register("tenant-manager", health.HTTPCheck(nil, cfg.TenantManager.URL+"/health/live"))
 
 	// Setup router
-	router := setupRouter(authHandler, authMiddleware, cfg)
+	buildinfo.Register(prometheus.DefaultRegisterer, "auth-gateway")
+	router := setupRouter(authHandler, adminHandler, serviceClientHandler, authMiddleware, checker, loglevel.New(logLevel, logger), cfg)
 
-	// Start HTTP server
+	// Start HTTP server; oversized request bodies are refused with 413
+	limiter := bodylimit.New(bodylimit.Config{
+		MaxBytes: int64(cfg.Server.MaxBodyBytes),
+		Routes:   cfg.Server.BodyLimitRoutes,
+	})
 	srv := &http.Server{
 		Addr:    fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
-		Handler: router,
+		Handler: limiter.Middleware(router),
 	}
 
 	// Start server in a goroutine
@@ -100,6 +190,19 @@ func main() {
 		}
 	}()
 
+	// SIGHUP re-reads the configuration (.env, CONFIG_FILE, then the
+	// environment) and applies what is safe to change without dropping
+	// connections
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	reloader := config.NewReloader(cfg, logLevel, logger)
+	reloader.OnReload(func(next *config.Config) {
+		authUC.SetOAuthProviders(newOAuthProviders(next.OAuth, logger))
+		tenantService.SetTenantAPIURL(next.TenantManager.URL)
+		authUC.SetRegistrationPolicy(registrationPolicy(next.Registration))
+	})
+	reloader.Watch(reloadCtx)
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -115,6 +218,14 @@ func main() {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	}
 
+	if err := auditLogger.Close(ctx); err != nil {
+		logger.Error("Failed to flush audit log", zap.Error(err))
+	}
+
+	if err := notifier.Close(ctx); err != nil {
+		logger.Error("Failed to deliver queued emails", zap.Error(err))
+	}
+
 	logger.Info("Server exited successfully")
 }
 
@@ -138,17 +249,58 @@ func initDatabase(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	return db, nil
 }
 
+// initJWTKeys builds the signing key set. Refresh tokens live longest, so a
+// retired key keeps verifying for the refresh token duration.
+func initJWTKeys(cfg config.JWTConfig) (*jwt.KeySet, error) {
+	current := jwt.NewHMACKey(cfg.KeyID, []byte(cfg.SecretKey))
+	if cfg.Algorithm != jwt.AlgHS256 {
+		pemBytes, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+		if current, err = jwt.NewPrivateKey(cfg.KeyID, cfg.Algorithm, pemBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	previous := make([]jwt.Key, 0, len(cfg.PreviousKeys))
+	for _, k := range cfg.PreviousKeys {
+		key := jwt.NewHMACKey(k.ID, []byte(k.Secret))
+		if k.Algorithm != jwt.AlgHS256 {
+			var err error
+			if key, err = jwt.NewPublicKey(k.ID, k.Algorithm, []byte(k.PublicKey)); err != nil {
+				return nil, err
+			}
+		}
+		previous = append(previous, key.Retired(k.RetiredAt))
+	}
+
+	return jwt.NewKeySet(current, previous, cfg.RefreshTokenDuration)
+}
+
 // autoMigrate runs database migrations
 func autoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&user.User{},
 		&user.Session{},
 		&user.OAuthState{},
+		&user.Invite{},
+		&serviceclient.ServiceClient{},
+		&postgresadapter.AuditRecord{},
 	)
 }
 
-// registerOAuthProviders registers OAuth providers
-func registerOAuthProviders(authUC *auth.UseCase, cfg config.OAuthConfig, logger *zap.Logger) error {
+// registrationPolicy converts the validated registration configuration
+func registrationPolicy(cfg config.RegistrationConfig) (auth.RegistrationPolicy, uuid.UUID) {
+	defaultTenantID, _ := uuid.Parse(cfg.DefaultTenantID)
+	return auth.RegistrationPolicy(cfg.Policy), defaultTenantID
+}
+
+// newOAuthProviders initializes the enabled OAuth providers. A provider
+// that fails to initialize is logged and left out.
+func newOAuthProviders(cfg config.OAuthConfig, logger *zap.Logger) map[string]auth.OAuthProvider {
+	providers := make(map[string]auth.OAuthProvider)
+
 	// Google OAuth
 	if cfg.Google.Enabled && cfg.Google.ClientID != "" {
 		googleProvider, err := oauth.NewGoogleProvider(
@@ -159,7 +311,7 @@ func registerOAuthProviders(authUC *auth.UseCase, cfg config.OAuthConfig, logger
 		if err != nil {
 			logger.Error("Failed to initialize Google OAuth", zap.Error(err))
 		} else {
-			authUC.RegisterOAuthProvider("google", googleProvider)
+			providers["google"] = googleProvider
 			logger.Info("Google OAuth provider registered")
 		}
 	}
@@ -174,7 +326,7 @@ func registerOAuthProviders(authUC *auth.UseCase, cfg config.OAuthConfig, logger
 		if err != nil {
 			logger.Error("Failed to initialize Microsoft OAuth", zap.Error(err))
 		} else {
-			authUC.RegisterOAuthProvider("microsoft", microsoftProvider)
+			providers["microsoft"] = microsoftProvider
 			logger.Info("Microsoft OAuth provider registered")
 		}
 	}
@@ -191,16 +343,16 @@ func registerOAuthProviders(authUC *auth.UseCase, cfg config.OAuthConfig, logger
 		if err != nil {
 			logger.Error("Failed to initialize Apple OAuth", zap.Error(err))
 		} else {
-			authUC.RegisterOAuthProvider("apple", appleProvider)
+			providers["apple"] = appleProvider
 			logger.Info("Apple OAuth provider registered")
 		}
 	}
 
-	return nil
+	return providers
 }
 
 // setupRouter sets up the Gin router with all routes
-func setupRouter(authHandler *handler.AuthHandler, authMiddleware *middleware.AuthMiddleware, cfg *config.Config) *gin.Engine {
+func setupRouter(authHandler *handler.AuthHandler, adminHandler *handler.AdminHandler, serviceClientHandler *handler.ServiceClientHandler, authMiddleware *middleware.AuthMiddleware, readiness, logLevel http.Handler, cfg *config.Config) *gin.Engine {
 	// Set Gin mode
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -209,6 +361,7 @@ func setupRouter(authHandler *handler.AuthHandler, authMiddleware *middleware.Au
 	router := gin.Default()
 
 	// Middleware
+	router.Use(middleware.RequestID())
 	router.Use(middleware.CORS())
 	router.Use(gin.Recovery())
 
@@ -219,6 +372,21 @@ func setupRouter(authHandler *handler.AuthHandler, authMiddleware *middleware.Au
 			"service": "auth-gateway",
 		})
 	})
+	router.GET("/health/live", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "alive"})
+	})
+	router.GET("/health/ready", gin.WrapH(readiness))
+	router.GET("/version", gin.WrapH(buildinfo.Handler("auth-gateway")))
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Runtime log level for incidents
+	router.PUT("/admin/log-level",
+		authMiddleware.Authenticate(), authMiddleware.RequireRole(auth.RoleSuperadmin), authMiddleware.DenyImpersonated(),
+		gin.WrapH(logLevel),
+	)
+
+	// Public keys for local token verification by other services
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
 
 	// API routes
 	api := router.Group("/api/v1")
@@ -233,15 +401,43 @@ func setupRouter(authHandler *handler.AuthHandler, authMiddleware *middleware.Au
 			// OAuth routes
 			authGroup.GET("/oauth/:provider", authHandler.GetOAuthURL)
 			authGroup.GET("/oauth/:provider/callback", authHandler.HandleOAuthCallback)
+			authGroup.POST("/oauth/complete", authHandler.CompleteOAuthSignup)
 		}
 
+		// Client credentials grant for internal services
+		api.POST("/oauth/token", serviceClientHandler.Token)
+
 		// Protected auth routes
 		protectedAuth := api.Group("/auth")
 		protectedAuth.Use(authMiddleware.Authenticate())
 		{
 			protectedAuth.GET("/me", authHandler.GetCurrentUser)
 			protectedAuth.POST("/logout", authHandler.Logout)
+			protectedAuth.GET("/users", authMiddleware.RequireRole("admin", auth.RoleSuperadmin), adminHandler.ListUsers)
+			protectedAuth.PUT("/users/:id/active", authMiddleware.RequireRole("admin"), authMiddleware.DenyImpersonated(), adminHandler.SetUserActive)
 		}
+
+		// Tenant administration routes (audited)
+		admin := api.Group("/admin")
+		admin.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole("admin"), authMiddleware.DenyImpersonated())
+		{
+			admin.PUT("/users/:id/role", adminHandler.ChangeUserRole)
+			admin.POST("/users/:id/sessions/revoke", adminHandler.RevokeUserSessions)
+			admin.POST("/invites", adminHandler.CreateInvite)
+		}
+
+		// Platform support routes (audited); impersonation cannot be chained
+		superadmin := api.Group("/superadmin")
+		superadmin.Use(authMiddleware.Authenticate(), authMiddleware.RequireRole(auth.RoleSuperadmin), authMiddleware.DenyImpersonated())
+		{
+			superadmin.POST("/users/:id/impersonate", adminHandler.Impersonate)
+			superadmin.POST("/service-clients", serviceClientHandler.CreateClient)
+			superadmin.GET("/service-clients", serviceClientHandler.ListClients)
+			superadmin.DELETE("/service-clients/:clientId", serviceClientHandler.RevokeClient)
+		}
+
+		// Audit log (compliance review)
+		api.GET("/audit", authMiddleware.Authenticate(), authMiddleware.RequireRole("admin"), adminHandler.ListAuditEntries)
 	}
 
 	return router
@@ -9,6 +9,11 @@ require (
 	github.com/golang-jwt/jwt/v5 v5.2.0
 	github.com/google/uuid v1.5.0
 	github.com/joho/godotenv v1.5.1
+	github.com/prometheus/client_golang v1.17.0
+	github.com/serphona/serphona/backend/go/libs/platform-audit v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-auth v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-core v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-notifications v0.0.0
 	go.uber.org/zap v1.26.0
 	golang.org/x/crypto v0.17.0
 	golang.org/x/oauth2 v0.15.0
@@ -52,3 +57,11 @@ require (
 	google.golang.org/protobuf v1.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/serphona/serphona/backend/go/libs/platform-audit => ../../libs/platform-audit
+
+replace github.com/serphona/serphona/backend/go/libs/platform-auth => ../../libs/platform-auth
+
+replace github.com/serphona/serphona/backend/go/libs/platform-core => ../../libs/platform-core
+
+replace github.com/serphona/serphona/backend/go/libs/platform-notifications => ../../libs/platform-notifications
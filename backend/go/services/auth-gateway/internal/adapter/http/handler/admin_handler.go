@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/usecase/auth"
+	"go.uber.org/zap"
+)
+
+// AdminHandler handles tenant administration HTTP requests
+type AdminHandler struct {
+	authUC     *auth.UseCase
+	auditStore audit.Store
+	validator  *validator.Validate
+	logger     *zap.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(authUC *auth.UseCase, auditStore audit.Store, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		authUC:     authUC,
+		auditStore: auditStore,
+		validator:  validator.New(),
+		logger:     logger,
+	}
+}
+
+// ChangeUserRole handles a user role change
+// @Summary Change a user's role
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body auth.ChangeRoleRequest true "New role"
+// @Success 200 {object} auth.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) ChangeUserRole(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid user ID",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	var req auth.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Validation failed",
+			Code:    "VALIDATION_ERROR",
+			Details: formatValidationErrors(err),
+		})
+		return
+	}
+
+	user, err := h.authUC.ChangeUserRole(c.Request.Context(), c.MustGet("tenantID").(uuid.UUID), userID, req)
+	if err != nil {
+		respondUseCaseError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// ListUsers handles listing the users of the caller's tenant. Superadmins
+// may list any tenant with tenant_id.
+// @Summary List the users of a tenant
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param search query string false "Case-insensitive match on email or name"
+// @Param limit query int false "Max users (default 50, max 200)"
+// @Param offset query int false "Users to skip"
+// @Param tenant_id query string false "Tenant to list (superadmin only)"
+// @Success 200 {object} auth.UserListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /auth/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	tenantID := c.MustGet("tenantID").(uuid.UUID)
+	if raw := c.Query("tenant_id"); raw != "" {
+		requested, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "Invalid tenant ID",
+				Code:    "INVALID_REQUEST",
+			})
+			return
+		}
+		if requested != tenantID && c.GetString("role") != auth.RoleSuperadmin {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Message: "Only superadmins can list other tenants",
+				Code:    "FORBIDDEN",
+			})
+			return
+		}
+		tenantID = requested
+	}
+
+	filter := user.ListFilter{Search: c.Query("search")}
+	for name, dst := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Message: "Invalid " + name,
+				Code:    "INVALID_REQUEST",
+			})
+			return
+		}
+		*dst = n
+	}
+
+	users, err := h.authUC.ListUsers(c.Request.Context(), tenantID, filter)
+	if err != nil {
+		respondUseCaseError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// SetUserActive handles deactivating or reactivating a user. Deactivation
+// blocks logins and revokes the user's sessions.
+// @Summary Deactivate or reactivate a user
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body auth.SetUserActiveRequest true "Active flag"
+// @Success 200 {object} auth.UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /auth/users/{id}/active [put]
+func (h *AdminHandler) SetUserActive(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid user ID",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	var req auth.SetUserActiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Validation failed",
+			Code:    "VALIDATION_ERROR",
+			Details: formatValidationErrors(err),
+		})
+		return
+	}
+
+	tenantID := c.MustGet("tenantID").(uuid.UUID)
+	var resp *auth.UserResponse
+	if *req.Active {
+		resp, err = h.authUC.ReactivateUser(c.Request.Context(), tenantID, userID)
+	} else {
+		resp, err = h.authUC.DeactivateUser(c.Request.Context(), tenantID, c.MustGet("userID").(uuid.UUID), userID)
+	}
+	if err != nil {
+		respondUseCaseError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateInvite handles inviting a user to the admin's tenant
+// @Summary Invite a user
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body auth.CreateInviteRequest true "Invitee"
+// @Success 201 {object} auth.InviteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/invites [post]
+func (h *AdminHandler) CreateInvite(c *gin.Context) {
+	var req auth.CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Validation failed",
+			Code:    "VALIDATION_ERROR",
+			Details: formatValidationErrors(err),
+		})
+		return
+	}
+
+	invite, err := h.authUC.CreateInvite(c.Request.Context(), c.MustGet("tenantID").(uuid.UUID), c.MustGet("userID").(uuid.UUID), req)
+	if err != nil {
+		respondUseCaseError(c, h.logger, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// RevokeUserSessions handles revoking all sessions of a user
+// @Summary Revoke a user's sessions
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id}/sessions/revoke [post]
+func (h *AdminHandler) RevokeUserSessions(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid user ID",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.authUC.RevokeUserSessions(c.Request.Context(), c.MustGet("tenantID").(uuid.UUID), userID); err != nil {
+		respondUseCaseError(c, h.logger, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Impersonate handles a superadmin minting a token that acts as a user
+// @Summary Impersonate a user
+// @Description Issues a short-lived access token acting as the user, with an impersonated_by claim. Destructive operations are blocked for this token.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body auth.ImpersonateRequest true "Reason and duration"
+// @Success 200 {object} auth.ImpersonationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /superadmin/users/{id}/impersonate [post]
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid user ID",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	var req auth.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Validation failed",
+			Code:    "VALIDATION_ERROR",
+			Details: formatValidationErrors(err),
+		})
+		return
+	}
+
+	impersonatorID := c.MustGet("userID").(uuid.UUID)
+	resp, err := h.authUC.Impersonate(c.Request.Context(), impersonatorID, userID, req)
+	if err != nil {
+		respondUseCaseError(c, h.logger, err)
+		return
+	}
+
+	h.logger.Warn("Impersonation token issued",
+		zap.String("impersonated_by", impersonatorID.String()),
+		zap.String("user_id", resp.User.ID.String()),
+		zap.String("tenant_id", resp.User.TenantID.String()),
+		zap.String("reason", req.Reason),
+		zap.Time("expires_at", resp.ExpiresAt),
+	)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListAuditEntries handles audit log queries for compliance review.
+// Results are always scoped to the admin's tenant.
+// @Summary Query the audit log
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param actor query string false "Actor user ID"
+// @Param action query string false "Action, e.g. user.role_change"
+// @Param target_id query string false "Target ID"
+// @Param from query string false "RFC 3339 start (inclusive)"
+// @Param to query string false "RFC 3339 end (exclusive)"
+// @Param limit query int false "Max entries (default 100, max 1000)"
+// @Success 200 {object} audit.QueryResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /audit [get]
+func (h *AdminHandler) ListAuditEntries(c *gin.Context) {
+	filter, err := audit.ParseFilter(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: err.Error(),
+			Code:    "INVALID_FILTER",
+		})
+		return
+	}
+	filter.TenantID = c.MustGet("tenantID").(uuid.UUID).String()
+
+	entries, err := h.auditStore.Query(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error("Failed to query audit log", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Message: "Internal server error",
+			Code:    "INTERNAL_ERROR",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, audit.QueryResponse{Items: entries})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/adapter/http/middleware"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/usecase/auth"
+	"go.uber.org/zap"
+)
+
+// tenantUsers lists users by tenant; other operations are not used here
+type tenantUsers struct {
+	user.Repository
+	users []user.User
+}
+
+func (r *tenantUsers) ListByTenant(_ context.Context, tenantID uuid.UUID, filter user.ListFilter) ([]user.User, int64, error) {
+	var matched []user.User
+	for _, u := range r.users {
+		if u.TenantID == tenantID {
+			matched = append(matched, u)
+		}
+	}
+	return matched, int64(len(matched)), nil
+}
+
+// newUsersRouter serves GET /auth/users as setupRouter does
+func newUsersRouter(t *testing.T, repo user.Repository) (*gin.Engine, *jwt.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	keys, err := jwt.NewKeySet(jwt.NewHMACKey("test", []byte("test-secret")), nil, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	jwtService := jwt.NewService(keys, 15*time.Minute, time.Hour)
+	authUC := auth.NewUseCase(repo, jwtService, nil, nil, 15*time.Minute)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, zap.NewNop())
+	adminHandler := NewAdminHandler(authUC, nil, zap.NewNop())
+
+	router := gin.New()
+	router.GET("/auth/users", authMiddleware.Authenticate(), authMiddleware.RequireRole("admin", auth.RoleSuperadmin), adminHandler.ListUsers)
+	return router, jwtService
+}
+
+func listUsers(t *testing.T, router *gin.Engine, jwtService *jwt.Service, tenantID uuid.UUID, role, query string) (*httptest.ResponseRecorder, auth.UserListResponse) {
+	t.Helper()
+	token, err := jwtService.GenerateAccessToken(uuid.New(), tenantID, role+"@example.com", role, nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/users"+query, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var resp auth.UserListResponse
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+func TestListUsers_AdminOnly(t *testing.T) {
+	tenantID := uuid.New()
+	router, jwtService := newUsersRouter(t, &tenantUsers{users: []user.User{{ID: uuid.New(), TenantID: tenantID}}})
+
+	for _, role := range []string{"user", "viewer"} {
+		if rec, _ := listUsers(t, router, jwtService, tenantID, role, ""); rec.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want 403", role, rec.Code)
+		}
+	}
+	if rec, resp := listUsers(t, router, jwtService, tenantID, "admin", ""); rec.Code != http.StatusOK || resp.Total != 1 {
+		t.Errorf("admin: status = %d, total = %d, want 200 with 1 user", rec.Code, resp.Total)
+	}
+}
+
+func TestListUsers_TenantOverride(t *testing.T) {
+	own, other := uuid.New(), uuid.New()
+	router, jwtService := newUsersRouter(t, &tenantUsers{users: []user.User{
+		{ID: uuid.New(), TenantID: own},
+		{ID: uuid.New(), TenantID: other},
+		{ID: uuid.New(), TenantID: other},
+	}})
+
+	// Admins only see their own tenant
+	if rec, _ := listUsers(t, router, jwtService, own, "admin", "?tenant_id="+other.String()); rec.Code != http.StatusForbidden {
+		t.Errorf("admin listing another tenant: status = %d, want 403", rec.Code)
+	}
+	if rec, resp := listUsers(t, router, jwtService, own, "admin", "?tenant_id="+own.String()); rec.Code != http.StatusOK || resp.Total != 1 {
+		t.Errorf("admin listing own tenant: status = %d, total = %d", rec.Code, resp.Total)
+	}
+
+	// Superadmins can list any tenant
+	rec, resp := listUsers(t, router, jwtService, own, auth.RoleSuperadmin, "?tenant_id="+other.String())
+	if rec.Code != http.StatusOK || resp.Total != 2 {
+		t.Fatalf("superadmin: status = %d, total = %d, want 200 with 2 users", rec.Code, resp.Total)
+	}
+	for _, u := range resp.Items {
+		if u.TenantID != other {
+			t.Errorf("user of tenant %s listed", u.TenantID)
+		}
+	}
+
+	if rec, _ := listUsers(t, router, jwtService, own, auth.RoleSuperadmin, "?tenant_id=acme"); rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed tenant_id: status = %d, want 400", rec.Code)
+	}
+	if rec, _ := listUsers(t, router, jwtService, own, "admin", "?limit=-1"); rec.Code != http.StatusBadRequest {
+		t.Errorf("negative limit: status = %d, want 400", rec.Code)
+	}
+}
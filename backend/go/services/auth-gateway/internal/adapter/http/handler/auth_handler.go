@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -241,6 +242,18 @@ func (h *AuthHandler) HandleOAuthCallback(c *gin.Context) {
 	}
 
 	resp, err := h.authUC.HandleOAuthCallback(c.Request.Context(), req)
+	var emailRequired *auth.OAuthEmailRequiredError
+	if errors.As(err, &emailRequired) {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Message: "The provider did not share an email; complete the signup with one",
+			Code:    "OAUTH_EMAIL_REQUIRED",
+			Details: map[string]interface{}{
+				"signupToken": emailRequired.SignupToken,
+				"expiresAt":   emailRequired.ExpiresAt,
+			},
+		})
+		return
+	}
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -249,8 +262,65 @@ func (h *AuthHandler) HandleOAuthCallback(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// CompleteOAuthSignup finishes an OAuth signup whose provider did not share
+// the user's email
+// @Summary Complete OAuth signup
+// @Tags OAuth
+// @Accept json
+// @Produce json
+// @Param request body auth.CompleteOAuthSignupRequest true "Signup token and email"
+// @Success 201 {object} auth.AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /auth/oauth/complete [post]
+func (h *AuthHandler) CompleteOAuthSignup(c *gin.Context) {
+	var req auth.CompleteOAuthSignupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Validation failed",
+			Code:    "VALIDATION_ERROR",
+			Details: formatValidationErrors(err),
+		})
+		return
+	}
+
+	resp, err := h.authUC.CompleteOAuthSignup(c.Request.Context(), req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// JWKS publishes the public keys that verify tokens issued by this service
+// @Summary JSON Web Key Set
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwt.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	// Verifiers refetch on an unknown kid, so a short cache is enough to
+	// pick up rotations
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtSvc.JWKS())
+}
+
 // handleError handles use case errors and converts them to HTTP responses
 func (h *AuthHandler) handleError(c *gin.Context, err error) {
+	respondUseCaseError(c, h.logger, err)
+}
+
+// respondUseCaseError converts a use case error to an HTTP response
+func respondUseCaseError(c *gin.Context, logger *zap.Logger, err error) {
 	switch err {
 	case auth.ErrInvalidCredentials:
 		c.JSON(http.StatusUnauthorized, ErrorResponse{
@@ -267,13 +337,53 @@ func (h *AuthHandler) handleError(c *gin.Context, err error) {
 			Message: "Email already exists",
 			Code:    "EMAIL_EXISTS",
 		})
+	case auth.ErrInvalidRole:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid role",
+			Code:    "INVALID_ROLE",
+		})
+	case auth.ErrImpersonationForbidden:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: "User cannot be impersonated",
+			Code:    "IMPERSONATION_FORBIDDEN",
+		})
+	case auth.ErrCannotDeactivateSelf:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Admins cannot deactivate themselves",
+			Code:    "CANNOT_DEACTIVATE_SELF",
+		})
+	case auth.ErrInviteRequired:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Message: "Registration requires an invite",
+			Code:    "INVITE_REQUIRED",
+		})
+	case auth.ErrInvalidInvite:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid or expired invite",
+			Code:    "INVALID_INVITE",
+		})
+	case auth.ErrTenantNameRequired:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Tenant name is required",
+			Code:    "VALIDATION_ERROR",
+		})
+	case auth.ErrOAuthProfileIncomplete:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "The provider did not identify the user",
+			Code:    "OAUTH_PROFILE_INCOMPLETE",
+		})
+	case auth.ErrInvalidSignupToken:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid or expired signup token",
+			Code:    "INVALID_SIGNUP_TOKEN",
+		})
 	case auth.ErrInvalidToken, auth.ErrSessionNotFound:
 		c.JSON(http.StatusUnauthorized, ErrorResponse{
 			Message: "Invalid or expired token",
 			Code:    "INVALID_TOKEN",
 		})
 	default:
-		h.logger.Error("Internal server error", zap.Error(err))
+		logger.Error("Internal server error", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Message: "Internal server error",
 			Code:    "INTERNAL_ERROR",
@@ -287,14 +397,3 @@ type ErrorResponse struct {
 	Code    string                 `json:"code"`
 	Details map[string]interface{} `json:"details,omitempty"`
 }
-
-// formatValidationErrors formats validator errors
-func formatValidationErrors(err error) map[string]interface{} {
-	details := make(map[string]interface{})
-	if validationErrors, ok := err.(validator.ValidationErrors); ok {
-		for _, e := range validationErrors {
-			details[e.Field()] = e.Tag()
-		}
-	}
-	return details
-}
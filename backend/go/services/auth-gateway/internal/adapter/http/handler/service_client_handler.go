@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/usecase/auth"
+	"go.uber.org/zap"
+)
+
+// ServiceClientHandler handles the client credentials grant used by internal
+// services and the superadmin management of their clients
+type ServiceClientHandler struct {
+	serviceClientUC *auth.ServiceClientUseCase
+	validator       *validator.Validate
+	logger          *zap.Logger
+}
+
+// NewServiceClientHandler creates a new service client handler
+func NewServiceClientHandler(serviceClientUC *auth.ServiceClientUseCase, logger *zap.Logger) *ServiceClientHandler {
+	return &ServiceClientHandler{
+		serviceClientUC: serviceClientUC,
+		validator:       validator.New(),
+		logger:          logger,
+	}
+}
+
+// Token handles the OAuth 2.0 client credentials grant. Credentials may be
+// sent in the body or with HTTP Basic authentication.
+// @Summary Issue a service token
+// @Description Client credentials grant for internal services. The token carries a space-delimited scope claim and a service:<name> subject.
+// @Tags Auth
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Param grant_type formData string true "client_credentials"
+// @Param scope formData string false "Space-delimited scopes; defaults to every scope of the client"
+// @Success 200 {object} auth.ServiceTokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /oauth/token [post]
+func (h *ServiceClientHandler) Token(c *gin.Context) {
+	var req auth.ClientCredentialsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+	if clientID, secret, ok := c.Request.BasicAuth(); ok {
+		req.ClientID, req.ClientSecret = clientID, secret
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Validation failed",
+			Code:    "VALIDATION_ERROR",
+			Details: formatValidationErrors(err),
+		})
+		return
+	}
+
+	resp, err := h.serviceClientUC.IssueToken(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidClient) {
+			h.logger.Warn("Rejected service client credentials", zap.String("client_id", req.ClientID))
+		}
+		h.respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateClient handles registering an internal service
+// @Summary Register a service client
+// @Description Returns the client secret once; only its hash is stored.
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body auth.CreateServiceClientRequest true "Service and allowed scopes"
+// @Success 201 {object} auth.CreateServiceClientResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /superadmin/service-clients [post]
+func (h *ServiceClientHandler) CreateClient(c *gin.Context) {
+	var req auth.CreateServiceClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Invalid request body",
+			Code:    "INVALID_REQUEST",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Validation failed",
+			Code:    "VALIDATION_ERROR",
+			Details: formatValidationErrors(err),
+		})
+		return
+	}
+
+	resp, err := h.serviceClientUC.CreateClient(c.Request.Context(), req)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListClients handles listing the registered service clients
+// @Summary List service clients
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} auth.ServiceClientResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /superadmin/service-clients [get]
+func (h *ServiceClientHandler) ListClients(c *gin.Context) {
+	clients, err := h.serviceClientUC.ListClients(c.Request.Context())
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, clients)
+}
+
+// RevokeClient handles deactivating a service client
+// @Summary Revoke a service client
+// @Description Already issued tokens stay valid until they expire.
+// @Tags Admin
+// @Security BearerAuth
+// @Param clientId path string true "Client ID"
+// @Success 204
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /superadmin/service-clients/{clientId} [delete]
+func (h *ServiceClientHandler) RevokeClient(c *gin.Context) {
+	if err := h.serviceClientUC.RevokeClient(c.Request.Context(), c.Param("clientId")); err != nil {
+		if errors.Is(err, auth.ErrInvalidClient) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Message: "Service client not found",
+				Code:    "SERVICE_CLIENT_NOT_FOUND",
+			})
+			return
+		}
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondError maps service client use case errors to HTTP responses
+func (h *ServiceClientHandler) respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrUnsupportedGrantType):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Only the client_credentials grant is supported",
+			Code:    "UNSUPPORTED_GRANT_TYPE",
+		})
+	case errors.Is(err, auth.ErrInvalidClient):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Message: "Invalid client credentials",
+			Code:    "INVALID_CLIENT",
+		})
+	case errors.Is(err, auth.ErrInvalidScope):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: "Requested scope is not allowed for this client",
+			Code:    "INVALID_SCOPE",
+		})
+	case errors.Is(err, auth.ErrServiceClientInvalid):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Message: err.Error(),
+			Code:    "VALIDATION_ERROR",
+		})
+	default:
+		respondUseCaseError(c, h.logger, err)
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes why a request field was rejected
+type FieldError struct {
+	Message string      `json:"message"`
+	Rule    string      `json:"rule"`
+	Value   interface{} `json:"value,omitempty"` // omitted for secrets and empty values
+}
+
+// sensitiveFields are never echoed back in validation errors
+var sensitiveFields = []string{"password", "secret", "token", "key", "code"}
+
+// formatValidationErrors formats validator errors into a readable message per
+// field, as tenant-manager does, with the rule that failed and, where safe,
+// the rejected value
+func formatValidationErrors(err error) map[string]interface{} {
+	details := make(map[string]interface{})
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		for _, e := range validationErrors {
+			details[e.Field()] = FieldError{
+				Message: validationMessage(e),
+				Rule:    e.Tag(),
+				Value:   rejectedValue(e),
+			}
+		}
+	}
+	return details
+}
+
+// validationMessage returns a human-readable message for a failed rule
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "This field is required"
+	case "email":
+		return "Invalid email format"
+	case "min":
+		return fmt.Sprintf("Must be at least %s", sizeOf(fe))
+	case "max":
+		return fmt.Sprintf("Must be at most %s", sizeOf(fe))
+	case "len":
+		return fmt.Sprintf("Must be exactly %s", sizeOf(fe))
+	case "oneof":
+		return "Must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "url":
+		return "Invalid URL format"
+	case "uuid", "uuid4":
+		return "Invalid UUID format"
+	case "e164":
+		return "Invalid phone number format"
+	default:
+		return "Invalid value"
+	}
+}
+
+// sizeOf describes the size parameter of min, max and len: characters for
+// strings, items for lists and the number itself otherwise
+func sizeOf(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return fe.Param() + " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fe.Param() + " items"
+	default:
+		return fe.Param()
+	}
+}
+
+// rejectedValue returns the value that failed, unless it is empty or the
+// field holds a credential
+func rejectedValue(fe validator.FieldError) interface{} {
+	name := strings.ToLower(fe.Field())
+	for _, s := range sensitiveFields {
+		if strings.Contains(name, s) {
+			return nil
+		}
+	}
+
+	value := reflect.ValueOf(fe.Value())
+	if !value.IsValid() || value.IsZero() {
+		return nil
+	}
+	if value.Kind() == reflect.Pointer {
+		return value.Elem().Interface()
+	}
+	return fe.Value()
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/usecase/auth"
+)
+
+func TestFormatValidationErrors_Messages(t *testing.T) {
+	validate := validator.New()
+
+	tests := []struct {
+		name    string
+		request interface{}
+		field   string
+		rule    string
+		message string
+		value   interface{}
+	}{
+		{"required", &auth.RegisterRequest{Email: "a@b.com", Password: "secret123"}, "Name", "required", "This field is required", nil},
+		{"email", &auth.LoginRequest{Email: "not-an-email", Password: "secret123"}, "Email", "email", "Invalid email format", "not-an-email"},
+		{"min on a string", &auth.ImpersonateRequest{Reason: "short"}, "Reason", "min", "Must be at least 10 characters", "short"},
+		{"max on a number", &auth.ImpersonateRequest{Reason: "support ticket 42", DurationMinutes: 90}, "DurationMinutes", "max", "Must be at most 60", 90},
+		{"oneof", &auth.ChangeRoleRequest{Role: "owner"}, "Role", "oneof", "Must be one of: admin, user, viewer", "owner"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := formatValidationErrors(validate.Struct(tt.request))
+			got, ok := details[tt.field].(FieldError)
+			if !ok {
+				t.Fatalf("Expected an error for %s, got %v", tt.field, details)
+			}
+			if got.Rule != tt.rule || got.Message != tt.message || got.Value != tt.value {
+				t.Errorf("Expected %s %q with value %v, got %+v", tt.rule, tt.message, tt.value, got)
+			}
+		})
+	}
+}
+
+func TestFormatValidationErrors_OmitsSecrets(t *testing.T) {
+	details := formatValidationErrors(validator.New().Struct(&auth.LoginRequest{Email: "a@b.com", Password: "short"}))
+
+	got, ok := details["Password"].(FieldError)
+	if !ok {
+		t.Fatalf("Expected an error for Password, got %v", details)
+	}
+	if got.Message != "Must be at least 8 characters" {
+		t.Errorf("Expected the min message, got %q", got.Message)
+	}
+	if got.Value != nil {
+		t.Errorf("Expected the password left out, got %v", got.Value)
+	}
+}
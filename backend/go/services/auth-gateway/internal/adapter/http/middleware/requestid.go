@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+)
+
+// RequestID puts the X-Request-ID correlation ID on the request context,
+// generating one when absent, and echoes it in the response. Outbound calls
+// made with the request context forward it through requestid.Transport.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = requestid.Bind(c.Writer, c.Request)
+		c.Next()
+	}
+}
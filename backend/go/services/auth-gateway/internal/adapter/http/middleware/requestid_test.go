@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+)
+
+func TestRequestID_SurvivesHTTPHop(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var downstream string
+	tenantManager := httptest.NewServer(requestid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downstream = requestid.FromContext(r.Context())
+	})))
+	defer tenantManager.Close()
+
+	client := &http.Client{Transport: requestid.NewTransport(nil)}
+	router := gin.New()
+	router.Use(RequestID())
+	router.POST("/api/v1/auth/register", func(c *gin.Context) {
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, tenantManager.URL+"/tenants", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("call to tenant-manager failed: %v", err)
+			return
+		}
+		resp.Body.Close()
+		c.Status(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", nil)
+	req.Header.Set(requestid.Header, "req-auth-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if downstream != "req-auth-1" {
+		t.Errorf("expected tenant-manager to see request ID req-auth-1, got %q", downstream)
+	}
+	if got := rec.Header().Get(requestid.Header); got != "req-auth-1" {
+		t.Errorf("expected the response to echo the request ID, got %q", got)
+	}
+}
+
+func TestRequestID_GeneratesMissingID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/health", func(c *gin.Context) {
+		seen = requestid.FromContext(c.Request.Context())
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if seen == "" || rec.Header().Get(requestid.Header) != seen {
+		t.Errorf("expected a generated ID in context and response, got %q and %q", seen, rec.Header().Get(requestid.Header))
+	}
+}
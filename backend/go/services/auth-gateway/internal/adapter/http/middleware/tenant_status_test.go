@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/tenant"
+	"go.uber.org/zap"
+)
+
+// fakeTenantManager serves tenant statuses by ID; unknown tenants are 404
+type fakeTenantManager struct {
+	statuses map[uuid.UUID]string
+	lookups  atomic.Int32
+}
+
+func (f *fakeTenantManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lookups.Add(1)
+	id, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/api/v1/tenants/"))
+	status, ok := f.statuses[id]
+	if err != nil || !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id": id.String(), "status": status})
+}
+
+func newStatusRouter(t *testing.T, tenantManager *fakeTenantManager) (*gin.Engine, *jwt.Service) {
+	t.Helper()
+
+	server := httptest.NewServer(tenantManager)
+	t.Cleanup(server.Close)
+	return newStatusRouterAt(t, server.URL, true)
+}
+
+// newStatusRouterAt serves /api/v1/auth/me checking tenant statuses at
+// tenantManagerURL
+func newStatusRouterAt(t *testing.T, tenantManagerURL string, failOpen bool) (*gin.Engine, *jwt.Service) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	keys, err := jwt.NewKeySet(jwt.NewHMACKey("test", []byte("test-secret")), nil, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	jwtService := jwt.NewService(keys, 15*time.Minute, time.Hour)
+	auth := NewAuthMiddleware(jwtService, zap.NewNop())
+	auth.SetTenantStatus(tenantstatus.NewCache(tenant.NewService(tenantManagerURL), tenantstatus.Config{
+		TTL:      time.Minute,
+		FailOpen: failOpen,
+	}))
+
+	router := gin.New()
+	router.GET("/api/v1/auth/me", auth.Authenticate(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router, jwtService
+}
+
+func getMe(t *testing.T, router *gin.Engine, jwtService *jwt.Service, tenantID uuid.UUID) int {
+	t.Helper()
+	token, err := jwtService.GenerateAccessToken(uuid.New(), tenantID, "a@example.com", "user", nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAuthenticate_TenantStatus(t *testing.T) {
+	active, pending, suspended := uuid.New(), uuid.New(), uuid.New()
+	tenantManager := &fakeTenantManager{statuses: map[uuid.UUID]string{
+		active:    tenant.StatusActive,
+		pending:   tenant.StatusPending,
+		suspended: tenant.StatusSuspended,
+	}}
+	router, jwtService := newStatusRouter(t, tenantManager)
+
+	tests := []struct {
+		name     string
+		tenantID uuid.UUID
+		want     int
+	}{
+		{name: "active", tenantID: active, want: http.StatusOK},
+		{name: "pending", tenantID: pending, want: http.StatusOK},
+		{name: "suspended", tenantID: suspended, want: http.StatusForbidden},
+		{name: "deleted", tenantID: uuid.New(), want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getMe(t, router, jwtService, tt.tenantID); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_TenantStatusCached(t *testing.T) {
+	tenantID := uuid.New()
+	tenantManager := &fakeTenantManager{statuses: map[uuid.UUID]string{tenantID: tenant.StatusActive}}
+	router, jwtService := newStatusRouter(t, tenantManager)
+
+	for i := 0; i < 3; i++ {
+		if got := getMe(t, router, jwtService, tenantID); got != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, got)
+		}
+	}
+	if n := tenantManager.lookups.Load(); n != 1 {
+		t.Errorf("lookups = %d, want 1 within the TTL", n)
+	}
+}
+
+func TestAuthenticate_TenantManagerDown(t *testing.T) {
+	tests := []struct {
+		name     string
+		failOpen bool
+		want     int
+	}{
+		{name: "fail open", failOpen: true, want: http.StatusOK},
+		{name: "fail closed", failOpen: false, want: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, jwtService := newStatusRouterAt(t, "http://127.0.0.1:1", tt.failOpen)
+
+			if got := getMe(t, router, jwtService, uuid.New()); got != tt.want {
+				t.Errorf("status = %d, want %d when the status can't be looked up", got, tt.want)
+			}
+		})
+	}
+}
@@ -103,7 +103,7 @@ func parseAppleIDToken(idToken string) (*auth.OAuthUserInfo, error) {
 	return &auth.OAuthUserInfo{
 		ProviderID: claims.Sub,
 		Email:      claims.Email,
-		Name:       claims.Email, // Apple doesn't always provide name
+		Name:       "", // Apple only sends the name to the client, on the first authorization
 		Verified:   claims.EmailVerified == "true",
 	}, nil
 }
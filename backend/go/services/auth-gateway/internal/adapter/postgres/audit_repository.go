@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"gorm.io/gorm"
+)
+
+// AuditRecord is the audit_log row. The table is append-only: a trigger
+// created by the SQL migration rejects updates and deletes.
+type AuditRecord struct {
+	ID         string    `gorm:"type:uuid;primary_key"`
+	OccurredAt time.Time `gorm:"not null;index"`
+	Actor      string    `gorm:"not null;default:'';index"`
+	ActorRole  string    `gorm:"not null;default:''"`
+	Action     string    `gorm:"not null"`
+	TargetType string    `gorm:"not null"`
+	TargetID   string    `gorm:"not null"`
+	TenantID   string    `gorm:"not null;default:'';index"`
+	Changes    []byte    `gorm:"type:jsonb"`
+	Metadata   []byte    `gorm:"type:jsonb"`
+}
+
+// TableName specifies the table name
+func (AuditRecord) TableName() string {
+	return "audit_log"
+}
+
+// AuditRepository implements audit.Store using PostgreSQL
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new PostgreSQL audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Append inserts a new audit entry
+func (r *AuditRepository) Append(ctx context.Context, e audit.Entry) error {
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Create(&AuditRecord{
+		ID:         e.ID,
+		OccurredAt: e.Timestamp,
+		Actor:      e.Actor,
+		ActorRole:  e.ActorRole,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		TenantID:   e.TenantID,
+		Changes:    changes,
+		Metadata:   metadata,
+	}).Error
+}
+
+// Query retrieves the newest audit entries matching the filter
+func (r *AuditRepository) Query(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	query := r.db.WithContext(ctx).Model(&AuditRecord{})
+
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("occurred_at < ?", filter.To)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = audit.DefaultLimit
+	}
+
+	var records []AuditRecord
+	if err := query.Order("occurred_at DESC, id DESC").Limit(limit).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]audit.Entry, 0, len(records))
+	for _, rec := range records {
+		e := audit.Entry{
+			ID:         rec.ID,
+			Timestamp:  rec.OccurredAt,
+			Actor:      rec.Actor,
+			ActorRole:  rec.ActorRole,
+			Action:     rec.Action,
+			TargetType: rec.TargetType,
+			TargetID:   rec.TargetID,
+			TenantID:   rec.TenantID,
+		}
+		if err := json.Unmarshal(rec.Changes, &e.Changes); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(rec.Metadata, &e.Metadata); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/serviceclient"
+	"gorm.io/gorm"
+)
+
+// ServiceClientRepository implements serviceclient.Repository using PostgreSQL
+type ServiceClientRepository struct {
+	db *gorm.DB
+}
+
+// NewServiceClientRepository creates a new PostgreSQL service client repository
+func NewServiceClientRepository(db *gorm.DB) *ServiceClientRepository {
+	return &ServiceClientRepository{db: db}
+}
+
+// Create creates a new service client
+func (r *ServiceClientRepository) Create(ctx context.Context, c *serviceclient.ServiceClient) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+// GetByClientID retrieves a service client by its public client ID
+func (r *ServiceClientRepository) GetByClientID(ctx context.Context, clientID string) (*serviceclient.ServiceClient, error) {
+	var c serviceclient.ServiceClient
+	err := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// List returns every service client, newest first
+func (r *ServiceClientRepository) List(ctx context.Context) ([]serviceclient.ServiceClient, error) {
+	var clients []serviceclient.ServiceClient
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&clients).Error
+	return clients, err
+}
+
+// Update updates a service client
+func (r *ServiceClientRepository) Update(ctx context.Context, c *serviceclient.ServiceClient) error {
+	return r.db.WithContext(ctx).Save(c).Error
+}
+
+// TouchLastUsed records when the client last obtained a token
+func (r *ServiceClientRepository) TouchLastUsed(ctx context.Context, clientID string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&serviceclient.ServiceClient{}).
+		Where("client_id = ?", clientID).
+		Update("last_used_at", at).Error
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -56,6 +57,32 @@ func (r *UserRepository) GetByProvider(ctx context.Context, provider, providerID
 	return &u, nil
 }
 
+// ListByTenant retrieves a page of the tenant's users
+func (r *UserRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID, filter user.ListFilter) ([]user.User, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&user.User{}).
+		Where("tenant_id = ? AND deleted_at IS NULL", tenantID)
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		query = query.Where("(email ILIKE ? OR name ILIKE ?)", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []user.User
+	err := query.Order("created_at, id").Limit(filter.Limit).Offset(filter.Offset).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// likeEscaper escapes the LIKE wildcards in a search term
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // Update updates a user
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	return r.db.WithContext(ctx).Save(u).Error
@@ -135,3 +162,33 @@ func (r *UserRepository) CleanupExpiredOAuthStates(ctx context.Context) error {
 		Where("expires_at < ?", time.Now()).
 		Delete(&user.OAuthState{}).Error
 }
+
+// CreateInvite creates a new invite
+func (r *UserRepository) CreateInvite(ctx context.Context, invite *user.Invite) error {
+	return r.db.WithContext(ctx).Create(invite).Error
+}
+
+// GetInviteByTokenHash retrieves an invite by the hash of its token
+func (r *UserRepository) GetInviteByTokenHash(ctx context.Context, tokenHash string) (*user.Invite, error) {
+	var invite user.Invite
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&invite).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// AcceptInvite marks an invite accepted, once
+func (r *UserRepository) AcceptInvite(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).
+		Model(&user.Invite{}).
+		Where("id = ? AND accepted_at IS NULL", id).
+		Update("accepted_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -1,27 +1,67 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/reload"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	OAuth    OAuthConfig
-	Redis    RedisConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	OAuth         OAuthConfig
+	Redis         RedisConfig
+	TenantManager TenantManagerConfig
+	Notifications NotificationsConfig
+	Registration  RegistrationConfig
 }
 
-// ServerConfig holds server configuration
+// ServerConfig holds server configuration. Request bodies are capped at
+// MaxBodyBytes, or at the limit BodyLimitRoutes sets for a path prefix.
 type ServerConfig struct {
-	Port string
-	Host string
-	Env  string
+	Port            string
+	Host            string
+	Env             string
+	LogLevel        string
+	MaxBodyBytes    int
+	BodyLimitRoutes map[string]int64
+}
+
+// TenantManagerConfig holds the tenant-manager client configuration. With
+// StatusCheck, requests of suspended or deleted tenants are rejected; their
+// status is cached for StatusCacheTTL. StatusFailOpen lets requests through
+// when the status can't be looked up.
+type TenantManagerConfig struct {
+	URL            string
+	StatusCheck    bool
+	StatusCacheTTL time.Duration
+	StatusFailOpen bool
+}
+
+// NotificationsConfig holds the user email configuration. Provider is
+// "sendgrid" or "noop", which only keeps emails in memory (development).
+type NotificationsConfig struct {
+	Provider       string
+	SendGridAPIKey string
+	EmailFrom      string
+}
+
+// RegistrationConfig decides the tenant of users registering without an
+// invite. Policy is "auto_create_tenant" (a new tenant per registration),
+// "invite_only" (only invited users may register) or "single_tenant" (every
+// user joins DefaultTenantID).
+type RegistrationConfig struct {
+	Policy          string
+	DefaultTenantID string
 }
 
 // DatabaseConfig holds database configuration
@@ -34,11 +74,35 @@ type DatabaseConfig struct {
 	SSLMode  string
 }
 
-// JWTConfig holds JWT configuration
+// JWTConfig holds JWT configuration. New tokens are signed under KeyID with
+// SecretKey (HS256) or the private key in PrivateKeyFile (RS256, ES256);
+// PreviousKeys are retired keys that still verify until every token they
+// signed has expired. CustomClaimsAllowed lists the custom claims tenants may
+// add to access tokens, at most CustomClaimsMaxBytes in total. Audience is the
+// aud claim of user tokens: the services that accept them.
 type JWTConfig struct {
+	Algorithm            string
 	SecretKey            string
+	PrivateKeyFile       string
+	KeyID                string
+	PreviousKeys         []PreviousKey
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+	ServiceTokenDuration time.Duration
+	Audience             []string
+	CustomClaimsAllowed  []string
+	CustomClaimsMaxBytes int
+}
+
+// PreviousKey is a retired JWT signing key, configured as a JSON array in
+// JWT_PREVIOUS_KEYS. HS256 keys need Secret; RS256 and ES256 keys need the
+// PEM-encoded PublicKey.
+type PreviousKey struct {
+	ID        string    `json:"kid"`
+	Algorithm string    `json:"alg"`
+	Secret    string    `json:"secret"`
+	PublicKey string    `json:"public_key"`
+	RetiredAt time.Time `json:"retired_at"`
 }
 
 // OAuthConfig holds OAuth provider configurations
@@ -64,16 +128,37 @@ type RedisConfig struct {
 	DB       int
 }
 
-// Load loads configuration from environment variables
+// Load loads configuration from environment variables. When CONFIG_FILE
+// names a KEY=VALUE file, its values override the environment; the file is
+// read again on every reload.
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := reload.LoadEnvFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	previousKeys, err := parsePreviousKeys(getEnv("JWT_PREVIOUS_KEYS", ""))
+	if err != nil {
+		return nil, err
+	}
+	bodyLimitRoutes, err := bodylimit.ParseRoutes(getEnv("MAX_REQUEST_BODY_ROUTES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MAX_REQUEST_BODY_ROUTES: %w", err)
+	}
+
 	config := &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
-			Env:  getEnv("ENV", "development"),
+			Port:     getEnv("SERVER_PORT", "8080"),
+			Host:     getEnv("SERVER_HOST", "0.0.0.0"),
+			Env:      getEnv("ENV", "development"),
+			LogLevel: getEnv("LOG_LEVEL", "info"),
+			// Auth payloads are small JSON documents
+			MaxBodyBytes:    parseInt(getEnv("MAX_REQUEST_BODY_BYTES", "65536")),
+			BodyLimitRoutes: bodyLimitRoutes,
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -84,9 +169,17 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		JWT: JWTConfig{
+			Algorithm:            getEnv("JWT_ALGORITHM", "HS256"),
 			SecretKey:            getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+			PrivateKeyFile:       getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			KeyID:                getEnv("JWT_KEY_ID", "primary"),
+			PreviousKeys:         previousKeys,
 			AccessTokenDuration:  parseDuration(getEnv("JWT_ACCESS_TOKEN_DURATION", "15m")),
 			RefreshTokenDuration: parseDuration(getEnv("JWT_REFRESH_TOKEN_DURATION", "168h")), // 7 days
+			ServiceTokenDuration: parseDuration(getEnv("JWT_SERVICE_TOKEN_DURATION", "1h")),
+			Audience:             parseList(getEnv("JWT_AUDIENCE", "serphona-api")),
+			CustomClaimsAllowed:  parseList(getEnv("JWT_CUSTOM_CLAIMS_ALLOWED", "")),
+			CustomClaimsMaxBytes: parseInt(getEnv("JWT_CUSTOM_CLAIMS_MAX_BYTES", "1024")),
 		},
 		OAuth: OAuthConfig{
 			Google: OAuthProviderConfig{
@@ -114,6 +207,25 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       0,
 		},
+		TenantManager: TenantManagerConfig{
+			URL:            getEnv("TENANT_MANAGER_URL", "http://localhost:8081"),
+			StatusCheck:    getEnv("TENANT_STATUS_CHECK_ENABLED", "false") == "true",
+			StatusCacheTTL: parseDuration(getEnv("TENANT_STATUS_CACHE_TTL", "30s")),
+			StatusFailOpen: getEnv("TENANT_STATUS_FAIL_OPEN", "true") == "true",
+		},
+		Notifications: NotificationsConfig{
+			Provider:       getEnv("NOTIFICATIONS_PROVIDER", "noop"),
+			SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+			EmailFrom:      getEnv("NOTIFICATIONS_EMAIL_FROM", "Serphona <no-reply@serphona.com>"),
+		},
+		Registration: RegistrationConfig{
+			Policy:          getEnv("REGISTRATION_POLICY", "auto_create_tenant"),
+			DefaultTenantID: getEnv("REGISTRATION_DEFAULT_TENANT_ID", ""),
+		},
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
 	return config, nil
@@ -135,11 +247,50 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-// parseDuration parses duration string
+// parsePreviousKeys parses the JWT_PREVIOUS_KEYS JSON array
+func parsePreviousKeys(s string) ([]PreviousKey, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var keys []PreviousKey
+	if err := json.Unmarshal([]byte(s), &keys); err != nil {
+		return nil, fmt.Errorf("JWT_PREVIOUS_KEYS must be a JSON array of {kid, alg, secret|public_key, retired_at}: %w", err)
+	}
+	for i := range keys {
+		if keys[i].Algorithm == "" {
+			keys[i].Algorithm = "HS256"
+		}
+	}
+	return keys, nil
+}
+
+// parseDuration parses duration string. An unparsable value yields zero,
+// which Validate reports instead of silently falling back to a default.
 func parseDuration(s string) time.Duration {
 	d, err := time.ParseDuration(s)
 	if err != nil {
-		return 15 * time.Minute // default
+		return 0
 	}
 	return d
 }
+
+// parseInt parses a base 10 integer, or returns 0 for Validate to reject
+func parseInt(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseList parses a comma-separated list, dropping empty items
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
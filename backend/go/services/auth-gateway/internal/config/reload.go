@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/reload"
+	"go.uber.org/zap"
+)
+
+// RestartRequired returns the settings that differ in next but only take
+// effect after a restart: the listener and its body limits, the database and Redis connections,
+// the JWT signing keys and the tenant status check.
+func (c *Config) RestartRequired(next *Config) []string {
+	return reload.Changed([]reload.Setting{
+		{Name: "SERVER_HOST", Old: c.Server.Host, New: next.Server.Host},
+		{Name: "SERVER_PORT", Old: c.Server.Port, New: next.Server.Port},
+		{Name: "ENV", Old: c.Server.Env, New: next.Server.Env},
+		{Name: "MAX_REQUEST_BODY_*",
+			Old: fmt.Sprint(c.Server.MaxBodyBytes, c.Server.BodyLimitRoutes),
+			New: fmt.Sprint(next.Server.MaxBodyBytes, next.Server.BodyLimitRoutes)},
+		{Name: "DB_*", Old: c.Database.GetDSN(), New: next.Database.GetDSN()},
+		{Name: "REDIS_*", Old: fmt.Sprint(c.Redis), New: fmt.Sprint(next.Redis)},
+		{Name: "JWT_*", Old: fmt.Sprint(c.JWT), New: fmt.Sprint(next.JWT)},
+		{Name: "TENANT_STATUS_*",
+			Old: fmt.Sprint(c.TenantManager.StatusCheck, c.TenantManager.StatusCacheTTL, c.TenantManager.StatusFailOpen),
+			New: fmt.Sprint(next.TenantManager.StatusCheck, next.TenantManager.StatusCacheTTL, next.TenantManager.StatusFailOpen)},
+	})
+}
+
+// Reloader re-reads the configuration on SIGHUP and applies the settings that
+// are safe to change while serving: the log level here, plus whatever the
+// components registered with OnReload take (OAuth providers, the
+// tenant-manager URL, the registration policy). Settings that need a restart
+// are logged and left as they are.
+type Reloader struct {
+	mu      sync.Mutex
+	running *Config // as loaded at startup; restart-only settings never change
+	level   zap.AtomicLevel
+	apply   []func(cfg *Config)
+	logger  *zap.Logger
+}
+
+// NewReloader creates a reloader for the running configuration. level is the
+// logger's level, changed in place on reload.
+func NewReloader(running *Config, level zap.AtomicLevel, logger *zap.Logger) *Reloader {
+	return &Reloader{
+		running: running,
+		level:   level,
+		logger:  logger,
+	}
+}
+
+// OnReload registers a function applying the reloaded configuration
+func (r *Reloader) OnReload(apply func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apply = append(r.apply, apply)
+}
+
+// Watch reloads the configuration on every SIGHUP until ctx is done
+func (r *Reloader) Watch(ctx context.Context) {
+	reload.Watch(ctx, r.Reload, func(err error) {
+		r.logger.Error("Configuration reload failed, keeping the current configuration", zap.Error(err))
+	})
+}
+
+// Reload loads the configuration again and applies it. An invalid
+// configuration is rejected whole.
+func (r *Reloader) Reload() error {
+	next, err := Load()
+	if err != nil {
+		return err
+	}
+
+	level, err := zap.ParseAtomicLevel(next.Server.LogLevel)
+	if err != nil {
+		return fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if changed := r.running.RestartRequired(next); len(changed) > 0 {
+		r.logger.Warn("Configuration changes ignored until restart", zap.Strings("settings", changed))
+	}
+
+	r.level.SetLevel(level.Level())
+	for _, apply := range r.apply {
+		apply(next)
+	}
+
+	r.logger.Info("Configuration reloaded", zap.String("log_level", next.Server.LogLevel))
+	return nil
+}
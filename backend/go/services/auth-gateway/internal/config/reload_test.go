@@ -0,0 +1,94 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestReloaderSIGHUPChangesLogLevel(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "auth-gateway.env")
+	if err := os.WriteFile(configFile, []byte("LOG_LEVEL=info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", configFile)
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("TENANT_MANAGER_URL", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+	reloaded := make(chan *Config, 1)
+	reloader := NewReloader(cfg, level, zap.NewNop())
+	reloader.OnReload(func(next *Config) { reloaded <- next })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloader.Watch(ctx)
+
+	update := "LOG_LEVEL=debug\nTENANT_MANAGER_URL=http://tenant-manager:8081\n"
+	if err := os.WriteFile(configFile, []byte(update), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case next := <-reloaded:
+		if next.TenantManager.URL != "http://tenant-manager:8081" {
+			t.Errorf("expected the reloaded tenant-manager URL, got %q", next.TenantManager.URL)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("configuration not reloaded after SIGHUP")
+	}
+	if level.Level() != zapcore.DebugLevel {
+		t.Errorf("expected debug level after SIGHUP, got %s", level.Level())
+	}
+}
+
+func TestReloaderKeepsConfigurationOnInvalidReload(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("LOG_LEVEL", "warn")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	level := zap.NewAtomicLevelAt(zapcore.WarnLevel)
+	reloader := NewReloader(cfg, level, zap.NewNop())
+
+	t.Setenv("LOG_LEVEL", "loud")
+	if err := reloader.Reload(); err == nil {
+		t.Fatal("expected an invalid LOG_LEVEL to be rejected")
+	}
+	if level.Level() != zapcore.WarnLevel {
+		t.Errorf("expected the previous level to be kept, got %s", level.Level())
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	running := baseConfig("development")
+	next := baseConfig("development")
+	next.Server.LogLevel = "debug"
+	next.OAuth.Google.Enabled = true
+	if changed := running.RestartRequired(next); len(changed) != 0 {
+		t.Errorf("expected reloadable settings only, got %v", changed)
+	}
+
+	next.Server.Port = "9090"
+	next.Database.Host = "db.internal"
+	changed := running.RestartRequired(next)
+	if len(changed) != 2 || changed[0] != "SERVER_PORT" || changed[1] != "DB_*" {
+		t.Errorf("expected SERVER_PORT and DB_* to need a restart, got %v", changed)
+	}
+}
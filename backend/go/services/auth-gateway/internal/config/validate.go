@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+	"go.uber.org/zap/zapcore"
+)
+
+// minSecretLength is the minimum JWT secret length accepted in production
+const minSecretLength = 32
+
+// defaultSecrets are placeholder values shipped in Load and .env.example
+var defaultSecrets = []string{
+	"your-secret-key-change-in-production",
+	"your-super-secret-jwt-key-change-in-production-min-32-chars",
+}
+
+// ValidationError lists every configuration problem found at once
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// IsProduction reports whether the service runs in production
+func (c *Config) IsProduction() bool {
+	env := strings.ToLower(c.Server.Env)
+	return env == "production" || env == "prod"
+}
+
+// Validate checks the configuration for malformed values. In production it
+// also rejects default or empty secrets and insecure database settings.
+func (c *Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		addf("SERVER_PORT must be a port number, got %q", c.Server.Port)
+	}
+	if _, err := zapcore.ParseLevel(c.Server.LogLevel); err != nil {
+		addf("LOG_LEVEL must be debug, info, warn or error, got %q", c.Server.LogLevel)
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		addf("MAX_REQUEST_BODY_BYTES must be a positive integer")
+	}
+	if c.TenantManager.URL != "" && !validURL(c.TenantManager.URL) {
+		addf("TENANT_MANAGER_URL must be an absolute http(s) URL, got %q", c.TenantManager.URL)
+	}
+
+	switch c.Notifications.Provider {
+	case "noop":
+	case "sendgrid":
+		if c.Notifications.SendGridAPIKey == "" {
+			addf("SENDGRID_API_KEY is required for NOTIFICATIONS_PROVIDER sendgrid")
+		}
+	default:
+		addf("NOTIFICATIONS_PROVIDER must be sendgrid or noop, got %q", c.Notifications.Provider)
+	}
+	if _, err := mail.ParseAddress(c.Notifications.EmailFrom); err != nil {
+		addf("NOTIFICATIONS_EMAIL_FROM must be an email address, got %q", c.Notifications.EmailFrom)
+	}
+
+	switch c.Registration.Policy {
+	case "auto_create_tenant", "invite_only":
+	case "single_tenant":
+		if _, err := uuid.Parse(c.Registration.DefaultTenantID); err != nil {
+			addf("REGISTRATION_DEFAULT_TENANT_ID must be a tenant UUID for REGISTRATION_POLICY single_tenant, got %q", c.Registration.DefaultTenantID)
+		}
+	default:
+		addf("REGISTRATION_POLICY must be auto_create_tenant, invite_only or single_tenant, got %q", c.Registration.Policy)
+	}
+
+	if c.JWT.AccessTokenDuration <= 0 {
+		addf("JWT_ACCESS_TOKEN_DURATION must be a positive duration")
+	}
+	if c.JWT.RefreshTokenDuration <= 0 {
+		addf("JWT_REFRESH_TOKEN_DURATION must be a positive duration")
+	}
+	if c.JWT.AccessTokenDuration > 0 && c.JWT.RefreshTokenDuration > 0 &&
+		c.JWT.AccessTokenDuration >= c.JWT.RefreshTokenDuration {
+		addf("JWT_ACCESS_TOKEN_DURATION (%s) must be shorter than JWT_REFRESH_TOKEN_DURATION (%s)",
+			c.JWT.AccessTokenDuration, c.JWT.RefreshTokenDuration)
+	}
+	if c.JWT.ServiceTokenDuration <= 0 {
+		addf("JWT_SERVICE_TOKEN_DURATION must be a positive duration")
+	}
+	if c.JWT.ServiceTokenDuration > 0 && c.JWT.RefreshTokenDuration > 0 &&
+		c.JWT.ServiceTokenDuration > c.JWT.RefreshTokenDuration {
+		addf("JWT_SERVICE_TOKEN_DURATION (%s) must not exceed JWT_REFRESH_TOKEN_DURATION (%s)",
+			c.JWT.ServiceTokenDuration, c.JWT.RefreshTokenDuration)
+	}
+
+	switch c.JWT.Algorithm {
+	case "HS256":
+	case "RS256", "ES256":
+		if c.JWT.PrivateKeyFile == "" {
+			addf("JWT_PRIVATE_KEY_FILE is required for JWT_ALGORITHM %s", c.JWT.Algorithm)
+		}
+	default:
+		addf("JWT_ALGORITHM must be HS256, RS256 or ES256, got %q", c.JWT.Algorithm)
+	}
+
+	if c.JWT.KeyID == "" {
+		addf("JWT_KEY_ID must not be empty")
+	}
+	seenKeys := map[string]bool{c.JWT.KeyID: true}
+	for i, k := range c.JWT.PreviousKeys {
+		switch {
+		case k.ID == "":
+			addf("JWT_PREVIOUS_KEYS[%d] must have a kid", i)
+		case seenKeys[k.ID]:
+			addf("JWT_PREVIOUS_KEYS[%d] reuses kid %q", i, k.ID)
+		}
+		seenKeys[k.ID] = true
+		switch k.Algorithm {
+		case "HS256":
+			if k.Secret == "" {
+				addf("JWT_PREVIOUS_KEYS[%d] must have a secret", i)
+			}
+		case "RS256", "ES256":
+			if k.PublicKey == "" {
+				addf("JWT_PREVIOUS_KEYS[%d] must have a public_key", i)
+			}
+		default:
+			addf("JWT_PREVIOUS_KEYS[%d] has unsupported alg %q", i, k.Algorithm)
+		}
+		if k.RetiredAt.IsZero() {
+			addf("JWT_PREVIOUS_KEYS[%d] must have a retired_at time", i)
+		}
+	}
+
+	if c.TenantManager.StatusCheck && c.TenantManager.StatusCacheTTL <= 0 {
+		addf("TENANT_STATUS_CACHE_TTL must be a positive duration")
+	}
+
+	for _, name := range c.JWT.CustomClaimsAllowed {
+		if jwt.ReservedClaims[name] {
+			addf("JWT_CUSTOM_CLAIMS_ALLOWED must not include the reserved claim %q", name)
+		}
+	}
+	if c.JWT.CustomClaimsMaxBytes <= 0 {
+		addf("JWT_CUSTOM_CLAIMS_MAX_BYTES must be a positive integer")
+	}
+
+	providers := []struct {
+		name string
+		cfg  OAuthProviderConfig
+	}{
+		{"GOOGLE", c.OAuth.Google},
+		{"MICROSOFT", c.OAuth.Microsoft},
+		{"APPLE", c.OAuth.Apple},
+	}
+	for _, p := range providers {
+		if !p.cfg.Enabled {
+			continue
+		}
+		if p.cfg.ClientID == "" {
+			addf("OAUTH_%s_CLIENT_ID is required when the provider is enabled", p.name)
+		}
+		if p.cfg.ClientSecret == "" {
+			addf("OAUTH_%s_CLIENT_SECRET is required when the provider is enabled", p.name)
+		}
+		if !validURL(p.cfg.RedirectURL) {
+			addf("OAUTH_%s_REDIRECT_URL must be an absolute http(s) URL, got %q", p.name, p.cfg.RedirectURL)
+		}
+	}
+
+	if c.IsProduction() {
+		switch {
+		case c.JWT.Algorithm != "HS256":
+			// Asymmetric keys are checked when loaded
+		case c.JWT.SecretKey == "":
+			addf("JWT_SECRET is required in production")
+		case isDefaultSecret(c.JWT.SecretKey):
+			addf("JWT_SECRET is set to a default placeholder")
+		case len(c.JWT.SecretKey) < minSecretLength:
+			addf("JWT_SECRET must be at least %d characters in production", minSecretLength)
+		}
+		if c.Database.Password == "" || c.Database.Password == "postgres" {
+			addf("DB_PASSWORD must be set to a non-default value in production")
+		}
+		if c.Database.SSLMode == "disable" {
+			addf("DB_SSLMODE must not be disable in production")
+		}
+		for _, p := range providers {
+			if p.cfg.Enabled && strings.HasPrefix(p.cfg.ClientSecret, "your-") {
+				addf("OAUTH_%s_CLIENT_SECRET is set to a default placeholder", p.name)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// isDefaultSecret reports whether s is one of the shipped placeholders
+func isDefaultSecret(s string) bool {
+	for _, d := range defaultSecrets {
+		if s == d {
+			return true
+		}
+	}
+	return false
+}
+
+// validURL reports whether s is an absolute http(s) URL
+func validURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func baseConfig(env string) *Config {
+	return &Config{
+		Server:   ServerConfig{Port: "8080", Host: "0.0.0.0", Env: env, MaxBodyBytes: 65536},
+		Database: DatabaseConfig{Password: "postgres", SSLMode: "disable"},
+		JWT: JWTConfig{
+			Algorithm:            "HS256",
+			SecretKey:            "your-secret-key-change-in-production",
+			KeyID:                "primary",
+			AccessTokenDuration:  15 * time.Minute,
+			RefreshTokenDuration: 168 * time.Hour,
+			ServiceTokenDuration: time.Hour,
+			CustomClaimsMaxBytes: 1024,
+		},
+		Notifications: NotificationsConfig{Provider: "noop", EmailFrom: "Serphona <no-reply@serphona.com>"},
+		Registration:  RegistrationConfig{Policy: "auto_create_tenant"},
+	}
+}
+
+func TestValidateAllowsDefaultsInDevelopment(t *testing.T) {
+	if err := baseConfig("development").Validate(); err != nil {
+		t.Fatalf("expected development defaults to be valid, got %v", err)
+	}
+}
+
+func TestValidateRejectsDefaultsInProduction(t *testing.T) {
+	err := baseConfig("production").Validate()
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	// Every problem is reported at once
+	for _, want := range []string{"JWT_SECRET", "DB_PASSWORD", "DB_SSLMODE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got %v", want, err)
+		}
+	}
+	if len(verr.Problems) != 3 {
+		t.Errorf("expected 3 problems, got %d: %v", len(verr.Problems), verr.Problems)
+	}
+}
+
+func TestValidateAcceptsProductionSecrets(t *testing.T) {
+	cfg := baseConfig("production")
+	cfg.JWT.SecretKey = strings.Repeat("k", minSecretLength)
+	cfg.Database.Password = "s3cret"
+	cfg.Database.SSLMode = "require"
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid production config, got %v", err)
+	}
+
+	cfg.JWT.SecretKey = "short"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "at least") {
+		t.Errorf("expected short secret to be rejected, got %v", err)
+	}
+}
+
+func TestValidateRejectsMalformedValuesInAnyEnvironment(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Server.Port = "http"
+	cfg.JWT.AccessTokenDuration = 0
+	cfg.OAuth.Google = OAuthProviderConfig{Enabled: true, RedirectURL: "not a url"}
+
+	err := cfg.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	// port, access duration, client id, client secret, redirect url
+	if len(verr.Problems) != 5 {
+		t.Errorf("expected 5 problems, got %d: %v", len(verr.Problems), verr.Problems)
+	}
+}
+
+func TestValidateRejectsInvertedTokenDurations(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.JWT.AccessTokenDuration = 24 * time.Hour
+	cfg.JWT.RefreshTokenDuration = time.Hour
+
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "shorter than") {
+		t.Errorf("expected inverted durations to be rejected, got %v", err)
+	}
+}
+
+func TestValidateRejectsServiceTokensOutlivingKeyRetention(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.JWT.ServiceTokenDuration = 30 * 24 * time.Hour
+
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SERVICE_TOKEN_DURATION") {
+		t.Errorf("expected service token duration to be rejected, got %v", err)
+	}
+}
+
+func TestValidateNotifications(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Notifications.Provider = "sendgrid"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "SENDGRID_API_KEY") {
+		t.Errorf("expected sendgrid without an API key to be rejected, got %v", err)
+	}
+
+	cfg.Notifications.SendGridAPIKey = "SG.key"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected sendgrid with an API key to be valid, got %v", err)
+	}
+
+	cfg.Notifications.Provider = "smtp"
+	cfg.Notifications.EmailFrom = "no-reply"
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Errorf("expected provider and sender to be rejected, got %v", err)
+	}
+}
+
+func TestValidateRegistration(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Registration.Policy = "single_tenant"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "REGISTRATION_DEFAULT_TENANT_ID") {
+		t.Errorf("expected single_tenant without a default tenant to be rejected, got %v", err)
+	}
+
+	cfg.Registration.DefaultTenantID = "6f1c1f4e-8a53-4c1b-9d3a-2f7e0b4c9a10"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected single_tenant with a default tenant to be valid, got %v", err)
+	}
+
+	cfg.Registration.Policy = "open"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "REGISTRATION_POLICY") {
+		t.Errorf("expected an unknown policy to be rejected, got %v", err)
+	}
+}
+
+func TestValidateRejectsMalformedPreviousKeys(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.JWT.PreviousKeys = []PreviousKey{
+		{ID: "2026-01", Algorithm: "HS256", Secret: "old-secret", RetiredAt: time.Now()},
+		{ID: "primary", Algorithm: "HS256", Secret: "old-secret", RetiredAt: time.Now()},
+		{ID: "2025-07", Algorithm: "HS256"},
+	}
+
+	var verr *ValidationError
+	if !errors.As(cfg.Validate(), &verr) {
+		t.Fatal("expected *ValidationError")
+	}
+	// Reused kid, then missing secret and retired_at
+	if len(verr.Problems) != 3 {
+		t.Errorf("expected 3 problems, got %d: %v", len(verr.Problems), verr.Problems)
+	}
+}
+
+func TestParsePreviousKeys(t *testing.T) {
+	keys, err := parsePreviousKeys(`[{"kid":"2026-01","secret":"s","retired_at":"2026-10-01T00:00:00Z"}]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// alg defaults to HS256, the only algorithm before asymmetric keys
+	if len(keys) != 1 || keys[0].ID != "2026-01" || keys[0].Algorithm != "HS256" || keys[0].RetiredAt.IsZero() {
+		t.Errorf("unexpected keys: %+v", keys)
+	}
+
+	if _, err := parsePreviousKeys("2026-01:s"); err == nil {
+		t.Error("expected non-JSON value to be rejected")
+	}
+}
+
+func TestValidateAsymmetricAlgorithm(t *testing.T) {
+	cfg := baseConfig("production")
+	cfg.JWT.Algorithm = "RS256"
+	cfg.Database.Password = "s3cret"
+	cfg.Database.SSLMode = "require"
+
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_PRIVATE_KEY_FILE") {
+		t.Fatalf("expected missing private key to be rejected, got %v", err)
+	}
+
+	// The HMAC secret is unused, so its placeholder is not a problem
+	cfg.JWT.PrivateKeyFile = "/run/secrets/jwt.pem"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid RS256 config, got %v", err)
+	}
+
+	cfg.JWT.Algorithm = "none"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_ALGORITHM") {
+		t.Errorf("expected unsupported algorithm to be rejected, got %v", err)
+	}
+}
+
+func TestValidateCustomClaims(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.JWT.CustomClaimsAllowed = []string{"department", "role"}
+	cfg.JWT.CustomClaimsMaxBytes = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected a reserved claim and a zero size limit to be rejected")
+	}
+	for _, want := range []string{`reserved claim "role"`, "JWT_CUSTOM_CLAIMS_MAX_BYTES"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got %v", want, err)
+		}
+	}
+
+	cfg.JWT.CustomClaimsAllowed = []string{"department"}
+	cfg.JWT.CustomClaimsMaxBytes = 512
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected allowed custom claims to be valid, got %v", err)
+	}
+}
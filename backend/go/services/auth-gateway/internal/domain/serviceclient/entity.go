@@ -0,0 +1,43 @@
+package serviceclient
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceClient is an internal service allowed to obtain service tokens
+// through the client credentials grant. Only a bcrypt hash of the secret is
+// stored; the secret itself is shown once, when the client is created.
+type ServiceClient struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ClientID   string    `gorm:"uniqueIndex;not null"`
+	SecretHash string    `gorm:"not null"`
+	Service    string    `gorm:"not null;index"` // e.g. voice-gateway
+	Scopes     string    `gorm:"not null"`       // space-delimited
+	Active     bool      `gorm:"default:true"`
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName specifies the table name
+func (ServiceClient) TableName() string {
+	return "service_clients"
+}
+
+// ScopeList returns the scopes the client may request
+func (c *ServiceClient) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// Allows reports whether the client may request scope
+func (c *ServiceClient) Allows(scope string) bool {
+	for _, s := range c.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
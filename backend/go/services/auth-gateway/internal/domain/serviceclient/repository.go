@@ -0,0 +1,15 @@
+package serviceclient
+
+import (
+	"context"
+	"time"
+)
+
+// Repository defines the interface for service client data access
+type Repository interface {
+	Create(ctx context.Context, client *ServiceClient) error
+	GetByClientID(ctx context.Context, clientID string) (*ServiceClient, error)
+	List(ctx context.Context) ([]ServiceClient, error)
+	Update(ctx context.Context, client *ServiceClient) error
+	TouchLastUsed(ctx context.Context, clientID string, at time.Time) error
+}
@@ -46,11 +46,14 @@ func (Session) TableName() string {
 	return "sessions"
 }
 
-// OAuthState represents temporary OAuth state for verification
+// OAuthState represents temporary OAuth state for verification. A state
+// with a ProviderID is a pending signup waiting for the user's email instead.
 type OAuthState struct {
 	State       string `gorm:"primaryKey"`
 	Provider    string `gorm:"not null"`
 	RedirectURL string
+	ProviderID  string
+	Name        string
 	CreatedAt   time.Time `gorm:"index"`
 	ExpiresAt   time.Time `gorm:"not null;index"`
 }
@@ -59,3 +62,23 @@ type OAuthState struct {
 func (OAuthState) TableName() string {
 	return "oauth_states"
 }
+
+// Invite lets a user register into an existing tenant. Only a SHA-256 hash
+// of the token is stored; the token itself is shown once, when the invite
+// is created.
+type Invite struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TokenHash  string    `gorm:"uniqueIndex;not null"`
+	Email      string    `gorm:"not null;index"`
+	TenantID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	Role       string    `gorm:"not null;default:'user'"`
+	InvitedBy  uuid.UUID `gorm:"type:uuid"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// TableName specifies the table name
+func (Invite) TableName() string {
+	return "invites"
+}
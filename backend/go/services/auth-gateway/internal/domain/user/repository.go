@@ -2,10 +2,18 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// ListFilter selects and pages the users of a tenant
+type ListFilter struct {
+	Search string // case-insensitive match on email or name
+	Limit  int
+	Offset int
+}
+
 // Repository defines the interface for user data access
 type Repository interface {
 	// User operations
@@ -13,6 +21,9 @@ type Repository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByProvider(ctx context.Context, provider, providerID string) (*User, error)
+	// ListByTenant returns a page of the tenant's users, oldest first, and
+	// the number of users matching the filter
+	ListByTenant(ctx context.Context, tenantID uuid.UUID, filter ListFilter) ([]User, int64, error)
 	Update(ctx context.Context, user *User) error
 	Delete(ctx context.Context, id uuid.UUID) error
 
@@ -28,4 +39,10 @@ type Repository interface {
 	GetOAuthState(ctx context.Context, stateStr string) (*OAuthState, error)
 	DeleteOAuthState(ctx context.Context, stateStr string) error
 	CleanupExpiredOAuthStates(ctx context.Context) error
+
+	// Invite operations
+	CreateInvite(ctx context.Context, invite *Invite) error
+	GetInviteByTokenHash(ctx context.Context, tokenHash string) (*Invite, error)
+	// AcceptInvite marks an invite accepted; it fails if it already was
+	AcceptInvite(ctx context.Context, id uuid.UUID, at time.Time) error
 }
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCustomClaims is returned when custom claims are not allowed by
+// the service's policy
+var ErrInvalidCustomClaims = errors.New("invalid custom claims")
+
+// ReservedClaims are the claims the service sets itself. Custom claims can
+// never use these names.
+var ReservedClaims = map[string]bool{
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+	"user_id": true, "email": true, "tenant_id": true, "role": true, "impersonated_by": true,
+	"scope": true, "token_type": true,
+}
+
+// DefaultCustomClaimsMaxBytes bounds the total size of the custom claims of a
+// token when the policy sets no limit
+const DefaultCustomClaimsMaxBytes = 1024
+
+// CustomClaimsPolicy decides which custom claims access tokens may carry.
+// Without allowed names no custom claim is accepted.
+type CustomClaimsPolicy struct {
+	Allowed  []string
+	MaxBytes int
+}
+
+// Validate checks custom claims against the policy. The size counts every
+// name and value.
+func (p CustomClaimsPolicy) Validate(custom map[string]string) error {
+	maxBytes := p.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultCustomClaimsMaxBytes
+	}
+
+	size := 0
+	for name, value := range custom {
+		if ReservedClaims[name] {
+			return fmt.Errorf("%w: %q is a reserved claim", ErrInvalidCustomClaims, name)
+		}
+		if !p.allows(name) {
+			return fmt.Errorf("%w: %q is not an allowed claim", ErrInvalidCustomClaims, name)
+		}
+		size += len(name) + len(value)
+	}
+	if size > maxBytes {
+		return fmt.Errorf("%w: %d bytes exceed the limit of %d", ErrInvalidCustomClaims, size, maxBytes)
+	}
+	return nil
+}
+
+func (p CustomClaimsPolicy) allows(name string) bool {
+	for _, allowed := range p.Allowed {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// claimsFields has the fields of Claims without its JSON methods
+type claimsFields Claims
+
+// MarshalJSON writes the custom claims at the top level of the token,
+// beside the standard ones. Reserved names are skipped, so a custom claim
+// can never replace a claim the service sets.
+func (c Claims) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(claimsFields(c))
+	if err != nil || len(c.Custom) == 0 {
+		return data, err
+	}
+
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for name, value := range c.Custom {
+		if ReservedClaims[name] {
+			continue
+		}
+		if merged[name], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON reads the standard claims and collects the other string
+// claims into Custom
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	var fields claimsFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for name, value := range all {
+		if s, ok := value.(string); ok && !ReservedClaims[name] {
+			if fields.Custom == nil {
+				fields.Custom = make(map[string]string)
+			}
+			fields.Custom[name] = s
+		}
+	}
+
+	*c = Claims(fields)
+	return nil
+}
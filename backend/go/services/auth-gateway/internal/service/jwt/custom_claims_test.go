@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestGenerateAccessToken_CustomClaims(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("k1", []byte("secret")))
+	svc.SetCustomClaimsPolicy(CustomClaimsPolicy{Allowed: []string{"department", "cost_center"}})
+	tenantID := uuid.New()
+
+	token, err := svc.GenerateAccessToken(uuid.New(), tenantID, "a@example.com", "admin", map[string]string{"department": "sales"})
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	// The claim sits at the top level, beside the standard ones
+	raw := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, raw); err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	if raw["department"] != "sales" || raw["tenant_id"] != tenantID.String() {
+		t.Errorf("token claims = %v", raw)
+	}
+
+	claims, err := svc.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	if claims.Custom["department"] != "sales" || claims.TenantID != tenantID || claims.Role != "admin" {
+		t.Errorf("claims = %+v", claims)
+	}
+	if _, ok := claims.Custom["iss"]; ok {
+		t.Error("reserved claims must not be read as custom claims")
+	}
+}
+
+func TestGenerateAccessToken_RejectsInvalidCustomClaims(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("k1", []byte("secret")))
+	// Allowing a reserved name by mistake must not let it through
+	svc.SetCustomClaimsPolicy(CustomClaimsPolicy{Allowed: []string{"department", "role", "tenant_id"}, MaxBytes: 64})
+
+	tests := []struct {
+		name   string
+		custom map[string]string
+	}{
+		{name: "role override", custom: map[string]string{"role": "superadmin"}},
+		{name: "tenant override", custom: map[string]string{"tenant_id": uuid.NewString()}},
+		{name: "registered claim", custom: map[string]string{"sub": "someone-else"}},
+		{name: "not allowed", custom: map[string]string{"plan": "enterprise"}},
+		{name: "too large", custom: map[string]string{"department": strings.Repeat("x", 64)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GenerateAccessToken(uuid.New(), uuid.New(), "a@example.com", "user", tt.custom)
+			if !errors.Is(err, ErrInvalidCustomClaims) {
+				t.Errorf("GenerateAccessToken err = %v, want ErrInvalidCustomClaims", err)
+			}
+			_, err = svc.GenerateImpersonationToken(uuid.New(), uuid.New(), "a@example.com", "user", tt.custom, uuid.New(), accessTTL)
+			if !errors.Is(err, ErrInvalidCustomClaims) {
+				t.Errorf("GenerateImpersonationToken err = %v, want ErrInvalidCustomClaims", err)
+			}
+		})
+	}
+}
+
+func TestGenerateAccessToken_NoCustomClaimsByDefault(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("k1", []byte("secret")))
+
+	if _, err := svc.GenerateAccessToken(uuid.New(), uuid.New(), "a@example.com", "user", map[string]string{"department": "sales"}); !errors.Is(err, ErrInvalidCustomClaims) {
+		t.Errorf("err = %v, want ErrInvalidCustomClaims without an allowlist", err)
+	}
+}
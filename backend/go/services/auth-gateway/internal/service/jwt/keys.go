@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is a signing key identified by the kid header of the tokens it signs
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	// SignKey and VerifyKey are the same secret for HMAC, or the private and
+	// public halves of an asymmetric key
+	SignKey   interface{}
+	VerifyKey interface{}
+	// RetiredAt is when the key stopped signing; zero for the current key
+	RetiredAt time.Time
+}
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+)
+
+// minRSABits is the smallest RSA modulus accepted for RS256
+const minRSABits = 2048
+
+// NewHMACKey creates an HS256 key
+func NewHMACKey(id string, secret []byte) Key {
+	return Key{
+		ID:        id,
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   secret,
+		VerifyKey: secret,
+	}
+}
+
+// NewPrivateKey creates an RS256 or ES256 signing key from a PEM-encoded
+// private key (PKCS#1, PKCS#8 or SEC 1)
+func NewPrivateKey(id, alg string, pemBytes []byte) (Key, error) {
+	switch alg {
+	case AlgRS256:
+		priv, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("key %q: %w", id, err)
+		}
+		if priv.N.BitLen() < minRSABits {
+			return Key{}, fmt.Errorf("key %q: RSA key must be at least %d bits", id, minRSABits)
+		}
+		return Key{ID: id, Method: jwt.SigningMethodRS256, SignKey: priv, VerifyKey: &priv.PublicKey}, nil
+
+	case AlgES256:
+		priv, err := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("key %q: %w", id, err)
+		}
+		if priv.Curve != elliptic.P256() {
+			return Key{}, fmt.Errorf("key %q: ES256 requires a P-256 key", id)
+		}
+		return Key{ID: id, Method: jwt.SigningMethodES256, SignKey: priv, VerifyKey: &priv.PublicKey}, nil
+	}
+
+	return Key{}, fmt.Errorf("key %q: unsupported algorithm %q", id, alg)
+}
+
+// NewPublicKey creates a verify-only RS256 or ES256 key from a PEM-encoded
+// public key, for retired keys whose private half is no longer deployed
+func NewPublicKey(id, alg string, pemBytes []byte) (Key, error) {
+	switch alg {
+	case AlgRS256:
+		pub, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("key %q: %w", id, err)
+		}
+		return Key{ID: id, Method: jwt.SigningMethodRS256, VerifyKey: pub}, nil
+
+	case AlgES256:
+		pub, err := jwt.ParseECPublicKeyFromPEM(pemBytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("key %q: %w", id, err)
+		}
+		if pub.Curve != elliptic.P256() {
+			return Key{}, fmt.Errorf("key %q: ES256 requires a P-256 key", id)
+		}
+		return Key{ID: id, Method: jwt.SigningMethodES256, VerifyKey: pub}, nil
+	}
+
+	return Key{}, fmt.Errorf("key %q: unsupported algorithm %q", id, alg)
+}
+
+// Retired returns a copy of the key marked as retired at t
+func (k Key) Retired(t time.Time) Key {
+	k.RetiredAt = t
+	return k
+}
+
+// KeySet holds the current signing key and the retired keys that still
+// verify. A retired key is dropped once every token it signed has expired,
+// i.e. maxTokenLifetime after it was retired.
+type KeySet struct {
+	current          Key
+	previous         []Key
+	maxTokenLifetime time.Duration
+	now              func() time.Time
+}
+
+// NewKeySet creates a key set. maxTokenLifetime is the longest token
+// duration issued (the refresh token duration).
+func NewKeySet(current Key, previous []Key, maxTokenLifetime time.Duration) (*KeySet, error) {
+	if current.ID == "" {
+		return nil, errors.New("current key must have an ID")
+	}
+	if !current.RetiredAt.IsZero() {
+		return nil, fmt.Errorf("current key %q cannot be retired", current.ID)
+	}
+	if current.SignKey == nil {
+		return nil, fmt.Errorf("current key %q cannot sign", current.ID)
+	}
+
+	seen := map[string]bool{current.ID: true}
+	for _, k := range previous {
+		if k.ID == "" {
+			return nil, errors.New("previous key must have an ID")
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("duplicate key ID %q", k.ID)
+		}
+		if k.RetiredAt.IsZero() {
+			return nil, fmt.Errorf("previous key %q must have a retirement time", k.ID)
+		}
+		seen[k.ID] = true
+	}
+
+	return &KeySet{
+		current:          current,
+		previous:         previous,
+		maxTokenLifetime: maxTokenLifetime,
+		now:              time.Now,
+	}, nil
+}
+
+// Current returns the key used to sign new tokens
+func (ks *KeySet) Current() Key {
+	return ks.current
+}
+
+// Active returns the keys that still verify tokens, current first
+func (ks *KeySet) Active() []Key {
+	keys := []Key{ks.current}
+	now := ks.now()
+	for _, k := range ks.previous {
+		if now.Before(k.RetiredAt.Add(ks.maxTokenLifetime)) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Expired returns the retired keys that no longer verify and can be removed
+// from configuration
+func (ks *KeySet) Expired() []Key {
+	var keys []Key
+	now := ks.now()
+	for _, k := range ks.previous {
+		if !now.Before(k.RetiredAt.Add(ks.maxTokenLifetime)) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Lookup returns the active key with the given ID
+func (ks *KeySet) Lookup(id string) (Key, bool) {
+	for _, k := range ks.Active() {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
@@ -2,6 +2,9 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,109 +14,275 @@ import (
 var (
 	ErrInvalidToken = errors.New("invalid token")
 	ErrExpiredToken = errors.New("token has expired")
+
+	// The reasons a well-signed token is refused. They wrap ErrInvalidToken.
+	ErrInvalidIssuer   = fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	ErrInvalidAudience = fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	ErrWrongTokenType  = fmt.Errorf("%w: wrong token type", ErrInvalidToken)
+)
+
+const (
+	// Issuer identifies this service in the iss claim
+	Issuer = "serphona-auth"
+	// DefaultAudience is the aud claim of user tokens unless SetAudience
+	// names the services they are for
+	DefaultAudience = "serphona-api"
+	// Leeway tolerates clock skew between hosts when checking exp, nbf and iat
+	Leeway = 30 * time.Second
 )
 
-// Claims represents JWT custom claims
+// Token types, in the token_type claim, so an access token can't be used as
+// a refresh token or the other way around
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Claims represents JWT custom claims. ImpersonatedBy is set on tokens a
+// superadmin minted to act as the user. Custom holds the static claims the
+// user's tenant configured, written at the top level of the token.
 type Claims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Email    string    `json:"email"`
-	TenantID uuid.UUID `json:"tenant_id"`
-	Role     string    `json:"role"`
+	UserID         uuid.UUID         `json:"user_id"`
+	Email          string            `json:"email"`
+	TenantID       uuid.UUID         `json:"tenant_id"`
+	Role           string            `json:"role"`
+	ImpersonatedBy *uuid.UUID        `json:"impersonated_by,omitempty"`
+	TokenType      string            `json:"token_type"`
+	Custom         map[string]string `json:"-"`
+	jwt.RegisteredClaims
+}
+
+// Impersonated reports whether the token was minted through impersonation
+func (c *Claims) Impersonated() bool {
+	return c.ImpersonatedBy != nil
+}
+
+// RefreshClaims are the claims of a refresh token; the subject is the user ID.
+// Refresh tokens issued before token types were introduced have no type.
+type RefreshClaims struct {
+	TokenType string `json:"token_type,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ServiceSubjectPrefix prefixes the subject of service tokens, followed by the
+// service name (e.g. "service:voice-gateway")
+const ServiceSubjectPrefix = "service:"
+
+// ServiceClaims are the claims of a service token, issued to internal
+// services through the client credentials grant. Scope is space-delimited.
+type ServiceClaims struct {
+	Scope string `json:"scope"`
 	jwt.RegisteredClaims
 }
 
-// Service handles JWT token generation and validation
+// Service returns the name of the service the token was issued to
+func (c *ServiceClaims) Service() string {
+	return strings.TrimPrefix(c.Subject, ServiceSubjectPrefix)
+}
+
+// Scopes returns the granted scopes
+func (c *ServiceClaims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// Service handles JWT token generation and validation. Tokens are signed with
+// the current key of the key set and verified against any active key.
 type Service struct {
-	secretKey            []byte
+	keys                 *KeySet
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
+	audience             []string
+
+	policyMu     sync.RWMutex
+	customClaims CustomClaimsPolicy
 }
 
 // NewService creates a new JWT service
-func NewService(secretKey string, accessTokenDuration, refreshTokenDuration time.Duration) *Service {
+func NewService(keys *KeySet, accessTokenDuration, refreshTokenDuration time.Duration) *Service {
 	return &Service{
-		secretKey:            []byte(secretKey),
+		keys:                 keys,
 		accessTokenDuration:  accessTokenDuration,
 		refreshTokenDuration: refreshTokenDuration,
+		audience:             []string{DefaultAudience},
+	}
+}
+
+// SetAudience sets the aud claim of user tokens: the services that accept
+// them. Tokens are validated here when their aud names any of them. Call it
+// before issuing tokens; an empty list keeps DefaultAudience.
+func (s *Service) SetAudience(audience []string) {
+	if len(audience) > 0 {
+		s.audience = audience
 	}
 }
 
-// GenerateAccessToken generates a new access token
-func (s *Service) GenerateAccessToken(userID, tenantID uuid.UUID, email, role string) (string, error) {
-	claims := Claims{
-		UserID:   userID,
-		Email:    email,
-		TenantID: tenantID,
-		Role:     role,
+// JWKS returns the public keys that verify tokens issued by this service
+func (s *Service) JWKS() JWKS {
+	return s.keys.JWKS()
+}
+
+// SetCustomClaimsPolicy sets which custom claims access tokens may carry.
+// By default none is accepted.
+func (s *Service) SetCustomClaimsPolicy(policy CustomClaimsPolicy) {
+	s.policyMu.Lock()
+	defer s.policyMu.Unlock()
+	s.customClaims = policy
+}
+
+// ValidateCustomClaims checks custom claims against the custom claims policy
+func (s *Service) ValidateCustomClaims(custom map[string]string) error {
+	if len(custom) == 0 {
+		return nil
+	}
+	s.policyMu.RLock()
+	defer s.policyMu.RUnlock()
+	return s.customClaims.Validate(custom)
+}
+
+// GenerateAccessToken generates a new access token carrying the custom
+// claims, which may be nil. Custom claims the policy rejects fail with
+// ErrInvalidCustomClaims.
+func (s *Service) GenerateAccessToken(userID, tenantID uuid.UUID, email, role string, custom map[string]string) (string, error) {
+	if err := s.ValidateCustomClaims(custom); err != nil {
+		return "", err
+	}
+	claims := s.accessClaims(userID, tenantID, email, role, s.accessTokenDuration)
+	claims.Custom = custom
+	return s.sign(claims)
+}
+
+// GenerateImpersonationToken generates an access token acting as the user on
+// behalf of impersonatorID, valid for ttl. There is no refresh token: the
+// impersonation ends when the token expires.
+func (s *Service) GenerateImpersonationToken(userID, tenantID uuid.UUID, email, role string, custom map[string]string, impersonatorID uuid.UUID, ttl time.Duration) (string, error) {
+	if err := s.ValidateCustomClaims(custom); err != nil {
+		return "", err
+	}
+	claims := s.accessClaims(userID, tenantID, email, role, ttl)
+	claims.Custom = custom
+	claims.ImpersonatedBy = &impersonatorID
+	return s.sign(claims)
+}
+
+// accessClaims builds the claims of an access token valid for ttl
+func (s *Service) accessClaims(userID, tenantID uuid.UUID, email, role string, ttl time.Duration) Claims {
+	now := time.Now()
+	return Claims{
+		UserID:    userID,
+		Email:     email,
+		TenantID:  tenantID,
+		Role:      role,
+		TokenType: TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.accessTokenDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "serphona-auth",
+			Audience:  s.audience,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    Issuer,
 		},
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secretKey)
 }
 
 // GenerateRefreshToken generates a new refresh token
 func (s *Service) GenerateRefreshToken(userID uuid.UUID) (string, error) {
-	claims := jwt.RegisteredClaims{
-		Subject:   userID.String(),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.refreshTokenDuration)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		Issuer:    "serphona-auth",
+	claims := RefreshClaims{
+		TokenType: TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			Audience:  s.audience,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.refreshTokenDuration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    Issuer,
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.secretKey)
+	return s.sign(claims)
 }
 
-// ValidateAccessToken validates an access token and returns the claims
-func (s *Service) ValidateAccessToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return s.secretKey, nil
-	})
+// GenerateServiceToken generates a service token for the named service with
+// the granted scopes, valid for ttl. Its audience is the services the scopes
+// are for, so it is accepted only where it may act.
+func (s *Service) GenerateServiceToken(service string, scopes []string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := ServiceClaims{
+		Scope: strings.Join(scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   ServiceSubjectPrefix + service,
+			Audience:  scopeAudience(scopes),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    Issuer,
+		},
+	}
+
+	return s.sign(claims)
+}
 
+// ValidateServiceToken validates a service token and returns the claims.
+// User access tokens are rejected.
+func (s *Service) ValidateServiceToken(tokenString string) (*ServiceClaims, error) {
+	token, err := s.parse(tokenString, func() jwt.Claims { return &ServiceClaims{} })
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
-		}
+		return nil, validationError(err)
+	}
+
+	claims, ok := token.Claims.(*ServiceClaims)
+	if !ok || !token.Valid || !strings.HasPrefix(claims.Subject, ServiceSubjectPrefix) || claims.Service() == "" {
 		return nil, ErrInvalidToken
 	}
 
+	return claims, nil
+}
+
+// ValidateAccessToken validates an access token and returns the claims. It
+// checks the signature, expiry and not-before within Leeway, the issuer and
+// the audience. Refresh and service tokens are rejected with
+// ErrWrongTokenType: they do not act as the user.
+func (s *Service) ValidateAccessToken(tokenString string) (*Claims, error) {
+	token, err := s.parse(tokenString, func() jwt.Claims { return &Claims{} }, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, validationError(err)
+	}
+
 	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
 		return nil, ErrInvalidToken
 	}
+	if claims.TokenType != TokenTypeAccess || strings.HasPrefix(claims.Subject, ServiceSubjectPrefix) {
+		return nil, ErrWrongTokenType
+	}
+	if !s.accepts(claims.Audience) {
+		return nil, ErrInvalidAudience
+	}
 
 	return claims, nil
 }
 
-// ValidateRefreshToken validates a refresh token and returns the user ID
+// ValidateRefreshToken validates a refresh token and returns the user ID.
+// Access tokens are rejected with ErrWrongTokenType. Refresh tokens issued
+// before token types carry neither a type nor an audience and are accepted
+// until they expire.
 func (s *Service) ValidateRefreshToken(tokenString string) (uuid.UUID, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidToken
-		}
-		return s.secretKey, nil
-	})
-
+	token, err := s.parse(tokenString, func() jwt.Claims { return &RefreshClaims{} }, jwt.WithExpirationRequired())
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return uuid.Nil, ErrExpiredToken
-		}
-		return uuid.Nil, ErrInvalidToken
+		return uuid.Nil, validationError(err)
 	}
 
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*RefreshClaims)
 	if !ok || !token.Valid {
 		return uuid.Nil, ErrInvalidToken
 	}
+	switch claims.TokenType {
+	case TokenTypeRefresh:
+		if !s.accepts(claims.Audience) {
+			return uuid.Nil, ErrInvalidAudience
+		}
+	case "":
+		// Legacy refresh token
+	default:
+		return uuid.Nil, ErrWrongTokenType
+	}
 
 	userID, err := uuid.Parse(claims.Subject)
 	if err != nil {
@@ -122,3 +291,96 @@ func (s *Service) ValidateRefreshToken(tokenString string) (uuid.UUID, error) {
 
 	return userID, nil
 }
+
+// accepts reports whether aud names any audience of this service
+func (s *Service) accepts(aud jwt.ClaimStrings) bool {
+	for _, a := range aud {
+		for _, audience := range s.audience {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scopeAudience returns the services named by scopes of the form
+// "<service>:<permission>", each once
+func scopeAudience(scopes []string) jwt.ClaimStrings {
+	var audience jwt.ClaimStrings
+	seen := make(map[string]bool)
+	for _, scope := range scopes {
+		service, _, ok := strings.Cut(scope, ":")
+		if !ok || service == "" || seen[service] {
+			continue
+		}
+		seen[service] = true
+		audience = append(audience, service)
+	}
+	return audience
+}
+
+// validationError maps a parse or validation failure to the service's errors
+func validationError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrExpiredToken
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return ErrInvalidIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return ErrInvalidAudience
+	default:
+		return ErrInvalidToken
+	}
+}
+
+// sign signs claims with the current key, identifying it in the kid header
+func (s *Service) sign(claims jwt.Claims) (string, error) {
+	key := s.keys.Current()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.SignKey)
+}
+
+// parse verifies a token against the active key named by its kid header.
+// Tokens issued before key rotation carry no kid and are tried against every
+// active key. The token's alg must match the key's, so a token can never pick
+// how its own key is interpreted. The issuer is checked and the time claims
+// are checked with Leeway; opts add checks.
+func (s *Service) parse(tokenString string, newClaims func() jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(tokenString, newClaims())
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Key
+	if kid, _ := unverified.Header["kid"].(string); kid != "" {
+		key, ok := s.keys.Lookup(kid)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		candidates = []Key{key}
+	} else {
+		candidates = s.keys.Active()
+	}
+
+	for i, key := range candidates {
+		options := append([]jwt.ParserOption{
+			jwt.WithValidMethods([]string{key.Method.Alg()}),
+			jwt.WithIssuer(Issuer),
+			jwt.WithLeeway(Leeway),
+		}, opts...)
+		token, err := jwt.ParseWithClaims(tokenString, newClaims(), func(*jwt.Token) (interface{}, error) {
+			return key.VerifyKey, nil
+		}, options...)
+
+		if err == nil || i == len(candidates)-1 {
+			return token, err
+		}
+		if !errors.Is(err, jwt.ErrTokenSignatureInvalid) && !errors.Is(err, jwt.ErrTokenUnverifiable) {
+			return token, err
+		}
+	}
+
+	return nil, ErrInvalidToken
+}
@@ -0,0 +1,419 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	accessTTL  = 15 * time.Minute
+	refreshTTL = 168 * time.Hour
+)
+
+func newTestService(t *testing.T, current Key, previous ...Key) *Service {
+	t.Helper()
+
+	keys, err := NewKeySet(current, previous, refreshTTL)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	return NewService(keys, accessTTL, refreshTTL)
+}
+
+func TestService_SignsWithCurrentKid(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("new-secret")))
+
+	token, err := svc.GenerateAccessToken(uuid.New(), uuid.New(), "a@example.com", "admin", nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	if kid := parsed.Header["kid"]; kid != "2026-10" {
+		t.Errorf("kid = %v, want 2026-10", kid)
+	}
+}
+
+func TestService_VerifiesTokenFromRotatedOutKey(t *testing.T) {
+	oldKey := NewHMACKey("2026-04", []byte("old-secret"))
+	userID := uuid.New()
+
+	// Tokens issued before the rotation
+	before := newTestService(t, oldKey)
+	access, err := before.GenerateAccessToken(userID, uuid.New(), "a@example.com", "agent", nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	refresh, err := before.GenerateRefreshToken(userID)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+
+	after := newTestService(t, NewHMACKey("2026-10", []byte("new-secret")), oldKey.Retired(time.Now()))
+
+	claims, err := after.ValidateAccessToken(access)
+	if err != nil {
+		t.Fatalf("access token from retired key should verify, got %v", err)
+	}
+	if claims.UserID != userID {
+		t.Errorf("UserID = %s, want %s", claims.UserID, userID)
+	}
+
+	if got, err := after.ValidateRefreshToken(refresh); err != nil || got != userID {
+		t.Errorf("refresh token from retired key: got %s, %v", got, err)
+	}
+
+	// New tokens use the new key and verify too
+	fresh, err := after.GenerateRefreshToken(userID)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+	if _, err := after.ValidateRefreshToken(fresh); err != nil {
+		t.Errorf("token from current key should verify, got %v", err)
+	}
+}
+
+func TestService_RejectsKeyPastRetirementWindow(t *testing.T) {
+	oldKey := NewHMACKey("2026-04", []byte("old-secret"))
+	refresh, err := newTestService(t, oldKey).GenerateRefreshToken(uuid.New())
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+
+	retired := oldKey.Retired(time.Now().Add(-refreshTTL - time.Minute))
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("new-secret")), retired)
+
+	if _, err := svc.ValidateRefreshToken(refresh); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken once the key is phased out, got %v", err)
+	}
+	if expired := svc.keys.Expired(); len(expired) != 1 || expired[0].ID != "2026-04" {
+		t.Errorf("Expired() = %v, want the phased-out key", expired)
+	}
+}
+
+func TestService_RejectsUnknownKidAndForgedSignature(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("new-secret")))
+
+	for name, key := range map[string]Key{
+		"unknown kid":    NewHMACKey("rogue", []byte("new-secret")),
+		"wrong secret":   NewHMACKey("2026-10", []byte("guessed")),
+		"retired, wrong": NewHMACKey("2026-04", []byte("old-secret")),
+	} {
+		token, err := newTestService(t, key).GenerateAccessToken(uuid.New(), uuid.New(), "a@example.com", "admin", nil)
+		if err != nil {
+			t.Fatalf("GenerateAccessToken: %v", err)
+		}
+		if _, err := svc.ValidateAccessToken(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+func TestService_AcceptsLegacyTokenWithoutKid(t *testing.T) {
+	secret := []byte("old-secret")
+	claims := jwt.RegisteredClaims{
+		Subject:   uuid.New().String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		Issuer:    Issuer,
+	}
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	svc := newTestService(t,
+		NewHMACKey("2026-10", []byte("new-secret")),
+		NewHMACKey("primary", secret).Retired(time.Now()),
+	)
+
+	if _, err := svc.ValidateRefreshToken(legacy); err != nil {
+		t.Errorf("token without kid signed by an active key should verify, got %v", err)
+	}
+}
+
+func TestService_ExpiredTokenFromRetiredKey(t *testing.T) {
+	oldKey := NewHMACKey("2026-04", []byte("old-secret"))
+	expired := Service{keys: mustKeySet(t, oldKey), accessTokenDuration: -time.Minute, refreshTokenDuration: refreshTTL}
+	token, err := expired.GenerateAccessToken(uuid.New(), uuid.New(), "a@example.com", "admin", nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("new-secret")), oldKey.Retired(time.Now()))
+	if _, err := svc.ValidateAccessToken(token); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("expected ErrExpiredToken, got %v", err)
+	}
+}
+
+func TestNewKeySet_RejectsInvalidKeys(t *testing.T) {
+	current := NewHMACKey("a", []byte("s"))
+
+	tests := map[string][]Key{
+		"duplicate kid":      {NewHMACKey("a", []byte("t")).Retired(time.Now())},
+		"missing retirement": {NewHMACKey("b", []byte("t"))},
+		"missing kid":        {NewHMACKey("", []byte("t")).Retired(time.Now())},
+	}
+	for name, previous := range tests {
+		if _, err := NewKeySet(current, previous, refreshTTL); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestKeySet_JWKSPublishesOnlyAsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+
+	keys, err := NewKeySet(
+		Key{ID: "rsa-1", Method: jwt.SigningMethodRS256, SignKey: rsaKey, VerifyKey: &rsaKey.PublicKey},
+		[]Key{
+			{ID: "ec-1", Method: jwt.SigningMethodES256, SignKey: ecKey, VerifyKey: &ecKey.PublicKey, RetiredAt: time.Now()},
+			NewHMACKey("hmac-1", []byte("secret")).Retired(time.Now()),
+		},
+		refreshTTL,
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	set := keys.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("expected RSA and EC keys only, got %+v", set.Keys)
+	}
+
+	rsaJWK, ecJWK := set.Keys[0], set.Keys[1]
+	if rsaJWK.Kty != "RSA" || rsaJWK.Kid != "rsa-1" || rsaJWK.Alg != "RS256" || rsaJWK.E != "AQAB" || rsaJWK.N == "" {
+		t.Errorf("unexpected RSA JWK: %+v", rsaJWK)
+	}
+	// P-256 coordinates are 32 bytes, 43 base64url characters
+	if ecJWK.Kty != "EC" || ecJWK.Crv != "P-256" || len(ecJWK.X) != 43 || len(ecJWK.Y) != 43 {
+		t.Errorf("unexpected EC JWK: %+v", ecJWK)
+	}
+
+	// A token signed by the RSA key verifies through the service
+	svc := NewService(keys, accessTTL, refreshTTL)
+	token, err := svc.GenerateRefreshToken(uuid.New())
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+	if _, err := svc.ValidateRefreshToken(token); err != nil {
+		t.Errorf("RS256 token should verify, got %v", err)
+	}
+}
+
+func TestService_SignsWithAsymmetricKeys(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgES256} {
+		t.Run(alg, func(t *testing.T) {
+			privPEM, pubPEM := generatePEM(t, alg)
+
+			key, err := NewPrivateKey("asym-1", alg, privPEM)
+			if err != nil {
+				t.Fatalf("NewPrivateKey: %v", err)
+			}
+			userID := uuid.New()
+			token, err := newTestService(t, key).GenerateAccessToken(userID, uuid.New(), "a@example.com", "admin", nil)
+			if err != nil {
+				t.Fatalf("GenerateAccessToken: %v", err)
+			}
+
+			parsed, _, _ := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if parsed.Method.Alg() != alg {
+				t.Errorf("alg = %s, want %s", parsed.Method.Alg(), alg)
+			}
+
+			// After rotation only the public half of the old key is deployed
+			retired, err := NewPublicKey("asym-1", alg, pubPEM)
+			if err != nil {
+				t.Fatalf("NewPublicKey: %v", err)
+			}
+			svc := newTestService(t, NewHMACKey("2026-10", []byte("new-secret")), retired.Retired(time.Now()))
+
+			claims, err := svc.ValidateAccessToken(token)
+			if err != nil || claims.UserID != userID {
+				t.Errorf("token from retired %s key: claims %+v, err %v", alg, claims, err)
+			}
+		})
+	}
+}
+
+func TestNewPrivateKey_RejectsMismatchedKeys(t *testing.T) {
+	rsaPEM, _ := generatePEM(t, AlgRS256)
+	ecPEM, _ := generatePEM(t, AlgES256)
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		alg  string
+		pem  []byte
+	}{
+		{"RSA key as ES256", AlgES256, rsaPEM},
+		{"EC key as RS256", AlgRS256, ecPEM},
+		{"1024-bit RSA", AlgRS256, encodePrivate(t, weak)},
+		{"P-384 as ES256", AlgES256, encodePrivate(t, p384)},
+		{"HMAC algorithm", AlgHS256, rsaPEM},
+	}
+	for _, tt := range tests {
+		if _, err := NewPrivateKey("k", tt.alg, tt.pem); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestService_RejectsAlgorithmConfusion(t *testing.T) {
+	privPEM, pubPEM := generatePEM(t, AlgRS256)
+	rsaKey, err := NewPrivateKey("shared-kid", AlgRS256, privPEM)
+	if err != nil {
+		t.Fatalf("NewPrivateKey: %v", err)
+	}
+	hmacKey := NewHMACKey("shared-kid", []byte("hmac-secret"))
+
+	rsTokens := newTestService(t, rsaKey)
+	hsTokens := newTestService(t, hmacKey)
+
+	// HS256 token signed with the public key, which verifiers can download
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   uuid.New().String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	forged.Header["kid"] = "shared-kid"
+	forgedToken, err := forged.SignedString(pubPEM)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	if _, err := rsTokens.ValidateRefreshToken(forgedToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("RS256 verifier accepted an HS256 token: %v", err)
+	}
+
+	rsToken, err := rsTokens.GenerateRefreshToken(uuid.New())
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+	if _, err := hsTokens.ValidateRefreshToken(rsToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("HS256 verifier accepted an RS256 token: %v", err)
+	}
+
+	hsToken, err := hsTokens.GenerateRefreshToken(uuid.New())
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+	if _, err := rsTokens.ValidateRefreshToken(hsToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("RS256 verifier accepted an HS256 token: %v", err)
+	}
+}
+
+func TestService_ServiceTokensAreNotAccessTokens(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+
+	serviceToken, err := svc.GenerateServiceToken("voice-gateway", []string{"agent:sessions.write", "agent:turns.write"}, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateServiceToken: %v", err)
+	}
+	claims, err := svc.ValidateServiceToken(serviceToken)
+	if err != nil {
+		t.Fatalf("ValidateServiceToken: %v", err)
+	}
+	if claims.Subject != "service:voice-gateway" || claims.Service() != "voice-gateway" {
+		t.Errorf("subject = %q, service = %q", claims.Subject, claims.Service())
+	}
+	if got := claims.Scopes(); len(got) != 2 || got[0] != "agent:sessions.write" || got[1] != "agent:turns.write" {
+		t.Errorf("scopes = %v", got)
+	}
+
+	if _, err := svc.ValidateAccessToken(serviceToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("service token accepted as an access token: %v", err)
+	}
+	if _, err := svc.ValidateRefreshToken(serviceToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("service token accepted as a refresh token: %v", err)
+	}
+
+	accessToken, err := svc.GenerateAccessToken(uuid.New(), uuid.New(), "a@example.com", "admin", nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	if _, err := svc.ValidateServiceToken(accessToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("access token accepted as a service token: %v", err)
+	}
+
+	expired, err := svc.GenerateServiceToken("voice-gateway", []string{"agent:turns.write"}, -time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateServiceToken: %v", err)
+	}
+	if _, err := svc.ValidateServiceToken(expired); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("expected ErrExpiredToken, got %v", err)
+	}
+}
+
+// generatePEM returns a PKCS#8 private key and PKIX public key for alg
+func generatePEM(t *testing.T, alg string) (privPEM, pubPEM []byte) {
+	t.Helper()
+
+	var priv interface{}
+	var pub interface{}
+	switch alg {
+	case AlgRS256:
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("rsa.GenerateKey: %v", err)
+		}
+		priv, pub = k, &k.PublicKey
+	case AlgES256:
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("ecdsa.GenerateKey: %v", err)
+		}
+		priv, pub = k, &k.PublicKey
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	return encodePrivate(t, priv), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func encodePrivate(t *testing.T, priv interface{}) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func mustKeySet(t *testing.T, current Key) *KeySet {
+	t.Helper()
+
+	keys, err := NewKeySet(current, nil, refreshTTL)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	return keys
+}
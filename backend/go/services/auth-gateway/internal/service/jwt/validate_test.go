@@ -0,0 +1,163 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// accessTokenClaims returns valid access token claims to edit
+func accessTokenClaims(svc *Service) Claims {
+	return svc.accessClaims(uuid.New(), uuid.New(), "a@example.com", "admin", accessTTL)
+}
+
+func TestValidateAccessToken_RejectionReasons(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		edit func(c *Claims)
+		want error
+	}{
+		{"expired past the leeway", func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-Leeway - time.Minute)) }, ErrExpiredToken},
+		{"not valid yet", func(c *Claims) { c.NotBefore = jwt.NewNumericDate(now.Add(Leeway + time.Minute)) }, ErrInvalidToken},
+		{"without expiry", func(c *Claims) { c.ExpiresAt = nil }, ErrInvalidToken},
+		{"other issuer", func(c *Claims) { c.Issuer = "evil-auth" }, ErrInvalidIssuer},
+		{"other audience", func(c *Claims) { c.Audience = jwt.ClaimStrings{"billing"} }, ErrInvalidAudience},
+		{"without token type", func(c *Claims) { c.TokenType = "" }, ErrWrongTokenType},
+		{"refresh token type", func(c *Claims) { c.TokenType = TokenTypeRefresh }, ErrWrongTokenType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := accessTokenClaims(svc)
+			tt.edit(&claims)
+			token, err := svc.sign(claims)
+			if err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			if _, err := svc.ValidateAccessToken(token); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateAccessToken_ToleratesClockSkew(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+
+	claims := accessTokenClaims(svc)
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-Leeway / 2))
+	claims.NotBefore = jwt.NewNumericDate(time.Now().Add(Leeway / 2))
+	token, err := svc.sign(claims)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	got, err := svc.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("expected a token within the leeway to verify, got %v", err)
+	}
+	if got.UserID != claims.UserID || got.TokenType != TokenTypeAccess {
+		t.Errorf("claims = %+v", got)
+	}
+}
+
+func TestService_AccessAndRefreshTokensAreNotInterchangeable(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+	userID := uuid.New()
+
+	access, err := svc.GenerateAccessToken(userID, uuid.New(), "a@example.com", "admin", nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	refresh, err := svc.GenerateRefreshToken(userID)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+
+	if _, err := svc.ValidateAccessToken(refresh); !errors.Is(err, ErrWrongTokenType) {
+		t.Errorf("refresh token as access token: expected ErrWrongTokenType, got %v", err)
+	}
+	if _, err := svc.ValidateRefreshToken(access); !errors.Is(err, ErrWrongTokenType) {
+		t.Errorf("access token as refresh token: expected ErrWrongTokenType, got %v", err)
+	}
+	if got, err := svc.ValidateRefreshToken(refresh); err != nil || got != userID {
+		t.Errorf("ValidateRefreshToken = %s, %v; want %s", got, err, userID)
+	}
+}
+
+func TestValidateRefreshToken_RejectionReasons(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+
+	tests := []struct {
+		name   string
+		claims RefreshClaims
+		want   error
+	}{
+		{"other issuer", RefreshClaims{TokenType: TokenTypeRefresh, RegisteredClaims: jwt.RegisteredClaims{
+			Subject: uuid.NewString(), Issuer: "evil-auth", Audience: jwt.ClaimStrings{DefaultAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}}, ErrInvalidIssuer},
+		{"other audience", RefreshClaims{TokenType: TokenTypeRefresh, RegisteredClaims: jwt.RegisteredClaims{
+			Subject: uuid.NewString(), Issuer: Issuer, Audience: jwt.ClaimStrings{"billing"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}}, ErrInvalidAudience},
+		{"expired", RefreshClaims{TokenType: TokenTypeRefresh, RegisteredClaims: jwt.RegisteredClaims{
+			Subject: uuid.NewString(), Issuer: Issuer, Audience: jwt.ClaimStrings{DefaultAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+		}}, ErrExpiredToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := svc.sign(tt.claims)
+			if err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			if _, err := svc.ValidateRefreshToken(token); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestService_AudienceScopesTokens(t *testing.T) {
+	voice := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+	voice.SetAudience([]string{"voice-gateway"})
+	billing := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+	billing.SetAudience([]string{"billing", "serphona-api"})
+
+	token, err := voice.GenerateAccessToken(uuid.New(), uuid.New(), "a@example.com", "admin", nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	claims, err := voice.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("expected the token accepted by its audience, got %v", err)
+	}
+	if len(claims.Audience) != 1 || claims.Audience[0] != "voice-gateway" {
+		t.Errorf("aud = %v, want [voice-gateway]", claims.Audience)
+	}
+	if _, err := billing.ValidateAccessToken(token); !errors.Is(err, ErrInvalidAudience) {
+		t.Errorf("expected ErrInvalidAudience from another audience, got %v", err)
+	}
+}
+
+func TestGenerateServiceToken_AudienceFromScopes(t *testing.T) {
+	svc := newTestService(t, NewHMACKey("2026-10", []byte("secret")))
+
+	token, err := svc.GenerateServiceToken("voice-gateway", []string{"agent-orchestrator:turns.write", "agent-orchestrator:sessions.write", "tenant-manager:agents.read"}, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateServiceToken: %v", err)
+	}
+	claims, err := svc.ValidateServiceToken(token)
+	if err != nil {
+		t.Fatalf("ValidateServiceToken: %v", err)
+	}
+	if got := claims.Audience; len(got) != 2 || got[0] != "agent-orchestrator" || got[1] != "tenant-manager" {
+		t.Errorf("aud = %v, want [agent-orchestrator tenant-manager]", got)
+	}
+}
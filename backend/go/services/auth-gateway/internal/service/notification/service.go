@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	notifications "github.com/serphona/serphona/backend/go/libs/platform-notifications"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/config"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/usecase/auth"
+	"go.uber.org/zap"
+)
+
+// Service sends auth emails through platform-notifications. Sending is
+// best-effort: failures are logged and never fail the calling action.
+type Service struct {
+	client *notifications.Client
+	logger *zap.Logger
+}
+
+// NewService builds the notification client for the configured provider
+func NewService(cfg config.NotificationsConfig, logger *zap.Logger) (*Service, error) {
+	var provider notifications.EmailProvider
+	switch cfg.Provider {
+	case "noop":
+		provider = notifications.NewNoopProvider()
+	case "sendgrid":
+		provider = notifications.NewSendGrid(cfg.SendGridAPIKey, nil)
+	default:
+		return nil, fmt.Errorf("unsupported notifications provider %q", cfg.Provider)
+	}
+
+	client := notifications.New(notifications.Providers{Email: provider}, templates(), notifications.Config{
+		Sender: notifications.Sender{Email: cfg.EmailFrom},
+		OnFailure: func(d notifications.Delivery, err error) {
+			logger.Error("Failed to deliver email",
+				zap.String("template", d.Template),
+				zap.String("tenant_id", d.TenantID),
+				zap.Int("attempts", d.Attempts),
+				zap.Error(err),
+			)
+		},
+	})
+	return &Service{client: client, logger: logger}, nil
+}
+
+// Notify queues an email for delivery
+func (s *Service) Notify(ctx context.Context, req notifications.EmailRequest) {
+	if err := s.client.SendEmail(ctx, req); err != nil {
+		s.logger.Error("Failed to queue email",
+			zap.String("template", req.Template),
+			zap.String("tenant_id", req.TenantID),
+			zap.Error(err),
+		)
+	}
+}
+
+// Close delivers the queued emails until ctx is done
+func (s *Service) Close(ctx context.Context) error {
+	return s.client.Close(ctx)
+}
+
+// templates registers the email templates sent by the auth use cases
+func templates() *notifications.Templates {
+	return notifications.NewTemplates().
+		MustRegister(auth.WelcomeTemplate, notifications.Template{
+			Subject: "Bem-vindo à Serphona, {{.Name}}",
+			Text: `Olá {{.Name}},
+
+Sua conta na Serphona foi criada para {{.Email}}. Você já pode entrar e
+configurar seus agentes de voz.
+
+Equipe Serphona`,
+			HTML: `<p>Olá {{.Name}},</p>
+<p>Sua conta na Serphona foi criada para <strong>{{.Email}}</strong>. Você já pode entrar e configurar seus agentes de voz.</p>
+<p>Equipe Serphona</p>`,
+		})
+}
@@ -0,0 +1,20 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/usecase/auth"
+)
+
+func TestTemplates_Welcome(t *testing.T) {
+	r, err := templates().Render(auth.WelcomeTemplate, map[string]string{"Name": "Ana", "Email": "ana@acme.com"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if r.Subject != "Bem-vindo à Serphona, Ana" {
+		t.Errorf("subject = %q", r.Subject)
+	}
+	if r.HTML == "" {
+		t.Error("expected an HTML body")
+	}
+}
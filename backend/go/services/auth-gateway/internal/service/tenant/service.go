@@ -2,24 +2,35 @@ package tenant
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
 )
 
 // Service handles tenant operations
 type Service struct {
-	tenantAPIURL string
+	tenantAPIURL atomic.Pointer[string]
 	httpClient   *http.Client
 }
 
 // NewService creates a new tenant service
 func NewService(tenantAPIURL string) *Service {
-	return &Service{
-		tenantAPIURL: tenantAPIURL,
-		httpClient:   &http.Client{},
+	s := &Service{
+		httpClient: &http.Client{Transport: requestid.NewTransport(nil)},
 	}
+	s.SetTenantAPIURL(tenantAPIURL)
+	return s
+}
+
+// SetTenantAPIURL points the service at another tenant-manager address.
+// Requests already sent keep the previous one.
+func (s *Service) SetTenantAPIURL(tenantAPIURL string) {
+	s.tenantAPIURL.Store(&tenantAPIURL)
 }
 
 // CreateTenant creates a new tenant via tenant-manager service
@@ -31,9 +42,91 @@ func (s *Service) CreateTenant(ctx context.Context, name string) (uuid.UUID, err
 	// TODO: Call tenant-manager API to create tenant
 	// Example:
 	// req := TenantCreateRequest{Name: name}
-	// resp, err := s.httpClient.Post(s.tenantAPIURL+"/tenants", "application/json", body)
+	// resp, err := s.httpClient.Post(*s.tenantAPIURL.Load()+"/tenants", "application/json", body)
 
 	fmt.Printf("Tenant created with ID: %s (local generation, integrate with tenant-manager later)\n", tenantID)
 
 	return tenantID, nil
 }
+
+// lookupTimeout bounds tenant lookups, which sit on the login and request paths
+const lookupTimeout = 2 * time.Second
+
+// Statuses of a tenant in tenant-manager. A tenant tenant-manager no longer
+// returns was deleted.
+const (
+	StatusActive    = "active"
+	StatusPending   = "pending"
+	StatusSuspended = "suspended"
+	StatusDeleted   = "deleted"
+)
+
+// TenantStatus returns the status of the tenant.
+// GET /api/v1/tenants/{tenant_id}
+func (s *Service) TenantStatus(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/api/v1/tenants/%s", *s.tenantAPIURL.Load(), tenantID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return StatusDeleted, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return body.Status, nil
+}
+
+// TokenClaims returns the custom access token claims the tenant configured
+// in its security settings.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (s *Service) TokenClaims(ctx context.Context, tenantID uuid.UUID) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/api/v1/tenants/%s/settings/effective", *s.tenantAPIURL.Load(), tenantID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Settings struct {
+			Security struct {
+				TokenClaims map[string]string `json:"token_claims"`
+			} `json:"security"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return body.Settings.Security.TokenClaims, nil
+}
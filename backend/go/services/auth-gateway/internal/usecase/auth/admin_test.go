@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+)
+
+func TestListUsers_ScopedToTenant(t *testing.T) {
+	uc, repo, _ := newMemoryUseCase(t)
+	acme, globex := uuid.New(), uuid.New()
+	for _, u := range []*user.User{
+		{ID: uuid.New(), Email: "ana@acme.com", Name: "Ana", TenantID: acme},
+		{ID: uuid.New(), Email: "bruno@acme.com", Name: "Bruno", TenantID: acme},
+		{ID: uuid.New(), Email: "carla@globex.com", Name: "Carla", TenantID: globex},
+	} {
+		repo.users = append(repo.users, u)
+	}
+
+	resp, err := uc.ListUsers(context.Background(), acme, user.ListFilter{})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Items) != 2 {
+		t.Fatalf("got %d of %d users, want 2 of 2", len(resp.Items), resp.Total)
+	}
+	for _, u := range resp.Items {
+		if u.TenantID != acme {
+			t.Errorf("user %s of another tenant listed", u.Email)
+		}
+	}
+	if resp.Limit != DefaultUserPageSize {
+		t.Errorf("limit = %d, want %d", resp.Limit, DefaultUserPageSize)
+	}
+
+	// Search never reaches other tenants
+	resp, err = uc.ListUsers(context.Background(), acme, user.ListFilter{Search: " carla "})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if resp.Total != 0 {
+		t.Errorf("search matched %d users of another tenant", resp.Total)
+	}
+}
+
+func TestListUsers_Pagination(t *testing.T) {
+	uc, repo, _ := newMemoryUseCase(t)
+	tenantID := uuid.New()
+	for i := 0; i < 5; i++ {
+		repo.users = append(repo.users, &user.User{ID: uuid.New(), Email: string(rune('a'+i)) + "@acme.com", TenantID: tenantID})
+	}
+
+	resp, err := uc.ListUsers(context.Background(), tenantID, user.ListFilter{Limit: 2, Offset: 4})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if resp.Total != 5 || len(resp.Items) != 1 || resp.Items[0].Email != "e@acme.com" {
+		t.Errorf("got %+v (total %d), want the last user of 5", resp.Items, resp.Total)
+	}
+
+	resp, err = uc.ListUsers(context.Background(), tenantID, user.ListFilter{Limit: 10_000})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if resp.Limit != MaxUserPageSize {
+		t.Errorf("limit = %d, want it capped at %d", resp.Limit, MaxUserPageSize)
+	}
+}
+
+func TestDeactivateUser_BlocksLogin(t *testing.T) {
+	uc, _, _ := newMemoryUseCase(t)
+	ctx := context.Background()
+
+	registered, err := uc.Register(ctx, registerRequest("ana@example.com"))
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	target := registered.User
+	login := LoginRequest{Email: "ana@example.com", Password: "password123"}
+	session, err := uc.Login(ctx, login)
+	if err != nil {
+		t.Fatalf("Login before deactivation: %v", err)
+	}
+
+	resp, err := uc.DeactivateUser(ctx, target.TenantID, uuid.New(), target.ID)
+	if err != nil {
+		t.Fatalf("DeactivateUser: %v", err)
+	}
+	if resp.Active {
+		t.Error("response should report the user inactive")
+	}
+
+	if _, err := uc.Login(ctx, login); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login after deactivation: err = %v, want ErrInvalidCredentials", err)
+	}
+	// Sessions were revoked, so the refresh token is dead too
+	if _, err := uc.RefreshToken(ctx, RefreshTokenRequest{RefreshToken: session.Tokens.RefreshToken}); err == nil {
+		t.Error("refresh after deactivation succeeded")
+	}
+
+	if _, err := uc.ReactivateUser(ctx, target.TenantID, target.ID); err != nil {
+		t.Fatalf("ReactivateUser: %v", err)
+	}
+	if _, err := uc.Login(ctx, login); err != nil {
+		t.Errorf("Login after reactivation: %v", err)
+	}
+}
+
+func TestDeactivateUser_Authorization(t *testing.T) {
+	uc, repo, _ := newMemoryUseCase(t)
+	ctx := context.Background()
+	admin := &user.User{ID: uuid.New(), TenantID: uuid.New(), Role: "admin", Active: true}
+	other := &user.User{ID: uuid.New(), TenantID: uuid.New(), Role: "user", Active: true}
+	repo.users = append(repo.users, admin, other)
+
+	if _, err := uc.DeactivateUser(ctx, admin.TenantID, admin.ID, admin.ID); !errors.Is(err, ErrCannotDeactivateSelf) {
+		t.Errorf("self: err = %v, want ErrCannotDeactivateSelf", err)
+	}
+	if _, err := uc.DeactivateUser(ctx, admin.TenantID, admin.ID, other.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("other tenant: err = %v, want ErrUserNotFound", err)
+	}
+	if !admin.Active || !other.Active {
+		t.Error("no user should have been deactivated")
+	}
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+)
+
+// fakeUserRepo serves users by ID; other operations are not used here
+type fakeUserRepo struct {
+	user.Repository
+	users map[uuid.UUID]*user.User
+}
+
+func (r *fakeUserRepo) GetByID(_ context.Context, id uuid.UUID) (*user.User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return u, nil
+}
+
+func newImpersonationUseCase(t *testing.T, users ...*user.User) (*UseCase, *jwt.Service, *audit.MemoryStore, *audit.Logger) {
+	t.Helper()
+
+	keys, err := jwt.NewKeySet(jwt.NewHMACKey("test", []byte("test-secret")), nil, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	jwtService := jwt.NewService(keys, 15*time.Minute, time.Hour)
+
+	repo := &fakeUserRepo{users: map[uuid.UUID]*user.User{}}
+	for _, u := range users {
+		repo.users[u.ID] = u
+	}
+
+	store := audit.NewMemoryStore()
+	auditLogger := audit.NewLogger(store, audit.Options{})
+	return NewUseCase(repo, jwtService, nil, auditLogger, 15*time.Minute), jwtService, store, auditLogger
+}
+
+func newUser(role string) *user.User {
+	return &user.User{
+		ID:       uuid.New(),
+		Email:    role + "@example.com",
+		Role:     role,
+		TenantID: uuid.New(),
+		Active:   true,
+	}
+}
+
+func TestImpersonate_TokenCarriesImpersonatedBy(t *testing.T) {
+	support := newUser(RoleSuperadmin)
+	target := newUser("admin")
+	uc, jwtService, store, auditLogger := newImpersonationUseCase(t, support, target)
+
+	resp, err := uc.Impersonate(context.Background(), support.ID, target.ID, ImpersonateRequest{
+		Reason:          "Reproducing ticket #4821",
+		DurationMinutes: 240,
+	})
+	if err != nil {
+		t.Fatalf("Impersonate: %v", err)
+	}
+
+	claims, err := jwtService.ValidateAccessToken(resp.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	if claims.ImpersonatedBy == nil || *claims.ImpersonatedBy != support.ID {
+		t.Fatalf("impersonated_by = %v, want %s", claims.ImpersonatedBy, support.ID)
+	}
+	if claims.UserID != target.ID || claims.TenantID != target.TenantID || claims.Role != "admin" {
+		t.Errorf("token should act as the target user, got %+v", claims)
+	}
+
+	// Requested 4h, capped at the maximum
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl > MaxImpersonationDuration || ttl < MaxImpersonationDuration-time.Minute {
+		t.Errorf("token lifetime = %s, want about %s", ttl, MaxImpersonationDuration)
+	}
+	if resp.ExpiresIn != int(MaxImpersonationDuration.Seconds()) {
+		t.Errorf("ExpiresIn = %d, want %d", resp.ExpiresIn, int(MaxImpersonationDuration.Seconds()))
+	}
+
+	if err := auditLogger.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	entries, _ := store.Query(context.Background(), audit.Filter{Action: audit.ActionUserImpersonate})
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.TargetID != target.ID.String() || e.TenantID != target.TenantID.String() || e.Metadata["reason"] != "Reproducing ticket #4821" {
+		t.Errorf("unexpected audit entry: %+v", e)
+	}
+}
+
+func TestImpersonate_DefaultDuration(t *testing.T) {
+	support, target := newUser(RoleSuperadmin), newUser("user")
+	uc, _, _, _ := newImpersonationUseCase(t, support, target)
+
+	resp, err := uc.Impersonate(context.Background(), support.ID, target.ID, ImpersonateRequest{Reason: "Checking dashboard"})
+	if err != nil {
+		t.Fatalf("Impersonate: %v", err)
+	}
+	if resp.ExpiresIn != int(DefaultImpersonationDuration.Seconds()) {
+		t.Errorf("ExpiresIn = %d, want %d", resp.ExpiresIn, int(DefaultImpersonationDuration.Seconds()))
+	}
+}
+
+func TestImpersonate_Blocked(t *testing.T) {
+	support := newUser(RoleSuperadmin)
+	otherSupport := newUser(RoleSuperadmin)
+	inactive := newUser("user")
+	inactive.Active = false
+	uc, _, store, auditLogger := newImpersonationUseCase(t, support, otherSupport, inactive)
+
+	tests := []struct {
+		name   string
+		target uuid.UUID
+		want   error
+	}{
+		{"another superadmin", otherSupport.ID, ErrImpersonationForbidden},
+		{"self", support.ID, ErrImpersonationForbidden},
+		{"inactive user", inactive.ID, ErrImpersonationForbidden},
+		{"unknown user", uuid.New(), ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.Impersonate(context.Background(), support.ID, tt.target, ImpersonateRequest{Reason: "Reproducing an issue"})
+			if !errors.Is(err, tt.want) || resp != nil {
+				t.Errorf("got %v, %v; want %v", resp, err, tt.want)
+			}
+		})
+	}
+
+	_ = auditLogger.Close(context.Background())
+	if entries, _ := store.Query(context.Background(), audit.Filter{}); len(entries) != 0 {
+		t.Errorf("blocked attempts should not be audited as impersonations, got %d entries", len(entries))
+	}
+}
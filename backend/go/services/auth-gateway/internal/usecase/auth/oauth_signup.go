@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+)
+
+// OAuthSignupExpiry is how long a user has to complete a signup whose
+// provider did not share their email
+const OAuthSignupExpiry = 30 * time.Minute
+
+var (
+	// ErrOAuthProfileIncomplete is returned when a provider does not identify
+	// the user at all
+	ErrOAuthProfileIncomplete = errors.New("oauth profile incomplete")
+	// ErrOAuthEmailRequired is returned when a new user must provide the email
+	// their provider did not share
+	ErrOAuthEmailRequired = errors.New("oauth email required")
+	// ErrInvalidSignupToken is returned for an unknown or expired signup token
+	ErrInvalidSignupToken = errors.New("invalid signup token")
+)
+
+// OAuthEmailRequiredError carries the token that completes a signup with
+// CompleteOAuthSignup
+type OAuthEmailRequiredError struct {
+	SignupToken string
+	ExpiresAt   time.Time
+}
+
+func (e *OAuthEmailRequiredError) Error() string { return ErrOAuthEmailRequired.Error() }
+
+// Unwrap makes errors.Is(err, ErrOAuthEmailRequired) match
+func (e *OAuthEmailRequiredError) Unwrap() error { return ErrOAuthEmailRequired }
+
+// startOAuthSignup stores the provider identity until the user provides an
+// email and returns the error asking for it
+func (uc *UseCase) startOAuthSignup(ctx context.Context, provider string, info *OAuthUserInfo) error {
+	token, err := generateRandomString(32)
+	if err != nil {
+		return err
+	}
+
+	pending := &user.OAuthState{
+		State:      token,
+		Provider:   provider,
+		ProviderID: info.ProviderID,
+		Name:       strings.TrimSpace(info.Name),
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(OAuthSignupExpiry),
+	}
+	if err := uc.userRepo.CreateOAuthState(ctx, pending); err != nil {
+		return err
+	}
+
+	return &OAuthEmailRequiredError{SignupToken: token, ExpiresAt: pending.ExpiresAt}
+}
+
+// CompleteOAuthSignup creates the user of a pending OAuth signup with the
+// email they provided. The email was not confirmed by the provider, so it
+// is never linked to an existing account and the user starts unverified.
+func (uc *UseCase) CompleteOAuthSignup(ctx context.Context, req CompleteOAuthSignupRequest) (*AuthResponse, error) {
+	pending, err := uc.userRepo.GetOAuthState(ctx, req.SignupToken)
+	if err != nil || pending.ProviderID == "" || pending.ExpiresAt.Before(time.Now()) {
+		return nil, ErrInvalidSignupToken
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return nil, ErrOAuthEmailRequired
+	}
+	if existing, err := uc.userRepo.GetByEmail(ctx, email); err == nil && existing != nil {
+		return nil, ErrEmailAlreadyExists
+	}
+
+	// The user may have finished signing up in another tab
+	u, err := uc.userRepo.GetByProvider(ctx, pending.Provider, pending.ProviderID)
+	if err != nil {
+		u, err = uc.createOAuthUser(ctx, pending.Provider, &OAuthUserInfo{
+			ProviderID: pending.ProviderID,
+			Email:      email,
+			Name:       pending.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	uc.userRepo.DeleteOAuthState(ctx, req.SignupToken)
+
+	if !u.Active {
+		return nil, ErrInvalidCredentials
+	}
+	return uc.generateAuthResponse(ctx, u)
+}
+
+// createOAuthUser creates an OAuth user in the tenant given by the
+// registration policy. info must carry an email.
+func (uc *UseCase) createOAuthUser(ctx context.Context, provider string, info *OAuthUserInfo) (*user.User, error) {
+	if info.Email == "" {
+		return nil, ErrOAuthEmailRequired
+	}
+	name := oauthDisplayName(info)
+
+	tenantID, err := uc.newUserTenant(ctx, name+"'s Organization")
+	if err != nil {
+		return nil, err
+	}
+
+	u := &user.User{
+		Email:      info.Email,
+		Name:       name,
+		TenantID:   tenantID,
+		Role:       "user",
+		Provider:   provider,
+		ProviderID: info.ProviderID,
+		Verified:   info.Verified,
+		Active:     true,
+		Password:   "", // No password for OAuth users
+	}
+	if err := uc.userRepo.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// oauthDisplayName is the name shared by the provider or, when it shares
+// none, the local part of the email
+func oauthDisplayName(info *OAuthUserInfo) string {
+	if name := strings.TrimSpace(info.Name); name != "" {
+		return name
+	}
+	if local, _, ok := strings.Cut(info.Email, "@"); ok && local != "" {
+		return local
+	}
+	return "User"
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+)
+
+// fakeOAuthProvider returns info for every code
+type fakeOAuthProvider struct{ info OAuthUserInfo }
+
+func (p *fakeOAuthProvider) GetAuthURL(state string) string {
+	return "https://provider/auth?state=" + state
+}
+
+func (p *fakeOAuthProvider) ExchangeCode(context.Context, string) (*OAuthUserInfo, error) {
+	info := p.info
+	return &info, nil
+}
+
+func newOAuthUseCase(t *testing.T, provider *fakeOAuthProvider) (*UseCase, *memoryUserRepo, *fakeTenants) {
+	t.Helper()
+	uc, repo, tenants := newMemoryUseCase(t)
+	uc.RegisterOAuthProvider("apple", provider)
+	return uc, repo, tenants
+}
+
+// callback runs a full OAuth round trip through the provider
+func callback(t *testing.T, uc *UseCase) (*AuthResponse, error) {
+	t.Helper()
+	resp, err := uc.GetOAuthURL(context.Background(), "apple")
+	if err != nil {
+		t.Fatalf("GetOAuthURL: %v", err)
+	}
+	state := resp.URL[len("https://provider/auth?state="):]
+	return uc.HandleOAuthCallback(context.Background(), OAuthCallbackRequest{Code: "code", State: state})
+}
+
+func TestHandleOAuthCallback_EmailAbsentOnReturn(t *testing.T) {
+	provider := &fakeOAuthProvider{info: OAuthUserInfo{ProviderID: "apple-1", Email: "ana@example.com", Name: "Ana", Verified: true}}
+	uc, repo, tenants := newOAuthUseCase(t, provider)
+
+	first, err := callback(t, uc)
+	if err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+
+	// Apple leaves the email and name out after the first authorization
+	provider.info = OAuthUserInfo{ProviderID: "apple-1"}
+	again, err := callback(t, uc)
+	if err != nil {
+		t.Fatalf("returning login: %v", err)
+	}
+	if again.User.ID != first.User.ID || again.User.Email != "ana@example.com" {
+		t.Errorf("returning login matched %+v, want the user from the first login %+v", again.User, first.User)
+	}
+	if len(repo.users) != 1 || len(tenants.names) != 1 {
+		t.Errorf("returning login created %d users and %d tenants, want 1 of each", len(repo.users), len(tenants.names))
+	}
+}
+
+func TestHandleOAuthCallback_NameAbsent(t *testing.T) {
+	provider := &fakeOAuthProvider{info: OAuthUserInfo{ProviderID: "apple-2", Email: "bruno.lima@example.com"}}
+	uc, _, tenants := newOAuthUseCase(t, provider)
+
+	resp, err := callback(t, uc)
+	if err != nil {
+		t.Fatalf("callback: %v", err)
+	}
+	if resp.User.Name != "bruno.lima" {
+		t.Errorf("name = %q, want the email local part", resp.User.Name)
+	}
+	if len(tenants.names) != 1 || tenants.names[0] != "bruno.lima's Organization" {
+		t.Errorf("tenants = %q", tenants.names)
+	}
+}
+
+func TestHandleOAuthCallback_EmailAbsentOnFirstLogin(t *testing.T) {
+	provider := &fakeOAuthProvider{info: OAuthUserInfo{ProviderID: "apple-3", Name: "Carla"}}
+	uc, repo, tenants := newOAuthUseCase(t, provider)
+
+	_, err := callback(t, uc)
+	var required *OAuthEmailRequiredError
+	if !errors.As(err, &required) || !errors.Is(err, ErrOAuthEmailRequired) {
+		t.Fatalf("err = %v, want OAuthEmailRequiredError", err)
+	}
+	if len(repo.users) != 0 || len(tenants.names) != 0 {
+		t.Fatalf("no user or tenant should exist without an email, got %d users and %d tenants", len(repo.users), len(tenants.names))
+	}
+
+	// The signup token is not an OAuth state
+	if _, err := uc.HandleOAuthCallback(context.Background(), OAuthCallbackRequest{Code: "code", State: required.SignupToken}); err == nil {
+		t.Error("signup token accepted as an OAuth state")
+	}
+
+	resp, err := uc.CompleteOAuthSignup(context.Background(), CompleteOAuthSignupRequest{
+		SignupToken: required.SignupToken,
+		Email:       "carla@example.com",
+	})
+	if err != nil {
+		t.Fatalf("CompleteOAuthSignup: %v", err)
+	}
+	if resp.User.Email != "carla@example.com" || resp.User.Name != "Carla" {
+		t.Errorf("user = %+v", resp.User)
+	}
+	if u := repo.users[0]; u.Provider != "apple" || u.ProviderID != "apple-3" || u.Verified {
+		t.Errorf("user should be linked to the provider and unverified, got %+v", u)
+	}
+
+	// The token is single use
+	if _, err := uc.CompleteOAuthSignup(context.Background(), CompleteOAuthSignupRequest{
+		SignupToken: required.SignupToken,
+		Email:       "carla@example.com",
+	}); !errors.Is(err, ErrInvalidSignupToken) {
+		t.Errorf("reused token: err = %v, want ErrInvalidSignupToken", err)
+	}
+}
+
+func TestCompleteOAuthSignup_ExistingEmail(t *testing.T) {
+	provider := &fakeOAuthProvider{info: OAuthUserInfo{ProviderID: "apple-4"}}
+	uc, repo, _ := newOAuthUseCase(t, provider)
+	repo.users = append(repo.users, &user.User{ID: uuid.New(), Email: "dora@example.com", Provider: "local", Active: true})
+
+	_, err := callback(t, uc)
+	var required *OAuthEmailRequiredError
+	if !errors.As(err, &required) {
+		t.Fatalf("err = %v, want OAuthEmailRequiredError", err)
+	}
+
+	// An email typed by the user must not take over an existing account
+	_, err = uc.CompleteOAuthSignup(context.Background(), CompleteOAuthSignupRequest{
+		SignupToken: required.SignupToken,
+		Email:       "dora@example.com",
+	})
+	if !errors.Is(err, ErrEmailAlreadyExists) {
+		t.Errorf("err = %v, want ErrEmailAlreadyExists", err)
+	}
+	if repo.users[0].ProviderID != "" {
+		t.Error("existing account was linked to the provider")
+	}
+}
+
+func TestHandleOAuthCallback_NoProviderID(t *testing.T) {
+	uc, repo, _ := newOAuthUseCase(t, &fakeOAuthProvider{info: OAuthUserInfo{Email: "eva@example.com"}})
+
+	if _, err := callback(t, uc); !errors.Is(err, ErrOAuthProfileIncomplete) {
+		t.Errorf("err = %v, want ErrOAuthProfileIncomplete", err)
+	}
+	if len(repo.users) != 0 {
+		t.Error("user created without a provider ID")
+	}
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+)
+
+// RegistrationPolicy decides the tenant of users registering without an
+// invite
+type RegistrationPolicy string
+
+const (
+	// RegistrationAutoCreateTenant creates a tenant for every registration
+	RegistrationAutoCreateTenant RegistrationPolicy = "auto_create_tenant"
+	// RegistrationInviteOnly only lets invited users register
+	RegistrationInviteOnly RegistrationPolicy = "invite_only"
+	// RegistrationSingleTenant adds every user to the default tenant
+	RegistrationSingleTenant RegistrationPolicy = "single_tenant"
+)
+
+// InviteExpiry is how long an invite can be accepted
+const InviteExpiry = 7 * 24 * time.Hour
+
+var (
+	ErrInviteRequired     = errors.New("invite required")
+	ErrInvalidInvite      = errors.New("invalid invite")
+	ErrTenantNameRequired = errors.New("tenant name required")
+)
+
+// SetRegistrationPolicy sets who may register and into which tenant.
+// defaultTenantID is the tenant of RegistrationSingleTenant. Invited users
+// always join the tenant of their invite.
+func (uc *UseCase) SetRegistrationPolicy(policy RegistrationPolicy, defaultTenantID uuid.UUID) {
+	uc.policyMu.Lock()
+	defer uc.policyMu.Unlock()
+	uc.policy = policy
+	uc.defaultTenantID = defaultTenantID
+}
+
+// newUserTenant returns the tenant of a user registering without an invite,
+// creating it when the policy says so
+func (uc *UseCase) newUserTenant(ctx context.Context, tenantName string) (uuid.UUID, error) {
+	uc.policyMu.RLock()
+	policy, defaultTenantID := uc.policy, uc.defaultTenantID
+	uc.policyMu.RUnlock()
+
+	switch policy {
+	case RegistrationInviteOnly:
+		return uuid.Nil, ErrInviteRequired
+	case RegistrationSingleTenant:
+		return defaultTenantID, nil
+	default:
+		if strings.TrimSpace(tenantName) == "" {
+			return uuid.Nil, ErrTenantNameRequired
+		}
+		return uc.tenantService.CreateTenant(ctx, tenantName)
+	}
+}
+
+// acceptInvite redeems the invite behind token for email. The invite is
+// spent even if creating the user fails afterwards.
+func (uc *UseCase) acceptInvite(ctx context.Context, token, email string) (*user.Invite, error) {
+	invite, err := uc.userRepo.GetInviteByTokenHash(ctx, hashInviteToken(token))
+	if err != nil || invite.AcceptedAt != nil || invite.ExpiresAt.Before(time.Now()) ||
+		!strings.EqualFold(invite.Email, email) {
+		return nil, ErrInvalidInvite
+	}
+	if err := uc.userRepo.AcceptInvite(ctx, invite.ID, time.Now()); err != nil {
+		return nil, ErrInvalidInvite
+	}
+	return invite, nil
+}
+
+// CreateInvite invites email to join the admin's tenant with the given role.
+// The token in the response is shown only once.
+func (uc *UseCase) CreateInvite(ctx context.Context, tenantID, invitedBy uuid.UUID, req CreateInviteRequest) (*InviteResponse, error) {
+	role := req.Role
+	if role == "" {
+		role = "user"
+	}
+	if !validRoles[role] {
+		return nil, ErrInvalidRole
+	}
+
+	token, err := generateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	invite := &user.Invite{
+		TokenHash: hashInviteToken(token),
+		Email:     strings.TrimSpace(req.Email),
+		TenantID:  tenantID,
+		Role:      role,
+		InvitedBy: invitedBy,
+		ExpiresAt: time.Now().Add(InviteExpiry),
+	}
+	if err := uc.userRepo.CreateInvite(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	uc.auditLogger.Record(ctx, audit.Entry{
+		Action:     audit.ActionUserInvite,
+		TargetType: "invite",
+		TargetID:   invite.ID.String(),
+		TenantID:   tenantID.String(),
+		Metadata:   map[string]string{"email": invite.Email, "role": role},
+	})
+
+	return &InviteResponse{
+		ID:        invite.ID,
+		Email:     invite.Email,
+		Role:      role,
+		Token:     token,
+		ExpiresAt: invite.ExpiresAt,
+	}, nil
+}
+
+// hashInviteToken is the stored form of an invite token. Tokens are random,
+// so a fast hash is enough.
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func registerRequest(email string) RegisterRequest {
+	return RegisterRequest{Email: email, Password: "password123", Name: "Test User", TenantName: "Acme"}
+}
+
+func TestRegister_AutoCreateTenant(t *testing.T) {
+	uc, repo, tenants := newMemoryUseCase(t)
+	uc.SetRegistrationPolicy(RegistrationAutoCreateTenant, uuid.Nil)
+
+	resp, err := uc.Register(context.Background(), registerRequest("ana@example.com"))
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if len(tenants.names) != 1 || tenants.names[0] != "Acme" {
+		t.Errorf("tenants created = %q, want [Acme]", tenants.names)
+	}
+	if resp.User.Role != "user" || repo.users[0].TenantID == uuid.Nil {
+		t.Errorf("unexpected user %+v", repo.users[0])
+	}
+
+	req := registerRequest("bruno@example.com")
+	req.TenantName = ""
+	if _, err := uc.Register(context.Background(), req); !errors.Is(err, ErrTenantNameRequired) {
+		t.Errorf("without a tenant name: err = %v, want ErrTenantNameRequired", err)
+	}
+}
+
+func TestRegister_InviteOnly(t *testing.T) {
+	uc, repo, tenants := newMemoryUseCase(t)
+	uc.SetRegistrationPolicy(RegistrationInviteOnly, uuid.Nil)
+
+	if _, err := uc.Register(context.Background(), registerRequest("ana@example.com")); !errors.Is(err, ErrInviteRequired) {
+		t.Fatalf("without an invite: err = %v, want ErrInviteRequired", err)
+	}
+
+	tenantID, adminID := uuid.New(), uuid.New()
+	invite, err := uc.CreateInvite(context.Background(), tenantID, adminID, CreateInviteRequest{Email: "Ana@Example.com", Role: "admin"})
+	if err != nil {
+		t.Fatalf("CreateInvite: %v", err)
+	}
+
+	other := registerRequest("bruno@example.com")
+	other.InviteToken = invite.Token
+	if _, err := uc.Register(context.Background(), other); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("invite used by another email: err = %v, want ErrInvalidInvite", err)
+	}
+
+	req := registerRequest("ana@example.com")
+	req.TenantName = ""
+	req.InviteToken = invite.Token
+	resp, err := uc.Register(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Register with invite: %v", err)
+	}
+	if resp.User.TenantID != tenantID || resp.User.Role != "admin" {
+		t.Errorf("user = %+v, want tenant %s with role admin", resp.User, tenantID)
+	}
+	if len(tenants.names) != 0 {
+		t.Errorf("tenants created = %q, want none", tenants.names)
+	}
+	if repo.invites[0].AcceptedAt == nil {
+		t.Error("invite not marked accepted")
+	}
+
+	again := registerRequest("ana2@example.com")
+	again.InviteToken = invite.Token
+	if _, err := uc.Register(context.Background(), again); !errors.Is(err, ErrInvalidInvite) {
+		t.Errorf("reused invite: err = %v, want ErrInvalidInvite", err)
+	}
+}
+
+func TestRegister_InviteOnlyRejectsOAuthSignup(t *testing.T) {
+	uc, repo, _ := newOAuthUseCase(t, &fakeOAuthProvider{info: OAuthUserInfo{ProviderID: "apple-1", Email: "ana@example.com"}})
+	uc.SetRegistrationPolicy(RegistrationInviteOnly, uuid.Nil)
+
+	if _, err := callback(t, uc); !errors.Is(err, ErrInviteRequired) {
+		t.Errorf("err = %v, want ErrInviteRequired", err)
+	}
+	if len(repo.users) != 0 {
+		t.Error("user created without an invite")
+	}
+}
+
+func TestRegister_SingleTenant(t *testing.T) {
+	uc, _, tenants := newMemoryUseCase(t)
+	defaultTenantID := uuid.New()
+	uc.SetRegistrationPolicy(RegistrationSingleTenant, defaultTenantID)
+
+	for _, email := range []string{"ana@example.com", "bruno@example.com"} {
+		resp, err := uc.Register(context.Background(), registerRequest(email))
+		if err != nil {
+			t.Fatalf("Register(%s): %v", email, err)
+		}
+		if resp.User.TenantID != defaultTenantID || resp.User.Role != "user" {
+			t.Errorf("user = %+v, want the default tenant with role user", resp.User)
+		}
+	}
+	if len(tenants.names) != 0 {
+		t.Errorf("tenants created = %q, want none", tenants.names)
+	}
+}
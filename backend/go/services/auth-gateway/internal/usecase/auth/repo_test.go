@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+)
+
+// memoryUserRepo keeps users, sessions, OAuth states and invites in memory
+type memoryUserRepo struct {
+	user.Repository
+	users    []*user.User
+	sessions map[string]*user.Session
+	states   map[string]*user.OAuthState
+	invites  []*user.Invite
+}
+
+func (r *memoryUserRepo) Create(_ context.Context, u *user.User) error {
+	u.ID = uuid.New()
+	r.users = append(r.users, u)
+	return nil
+}
+
+func (r *memoryUserRepo) Update(context.Context, *user.User) error { return nil }
+
+func (r *memoryUserRepo) GetByEmail(_ context.Context, email string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryUserRepo) GetByProvider(_ context.Context, provider, providerID string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Provider == provider && u.ProviderID == providerID {
+			return u, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryUserRepo) GetByID(_ context.Context, id uuid.UUID) (*user.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryUserRepo) CreateSession(_ context.Context, session *user.Session) error {
+	r.sessions[session.RefreshToken] = session
+	return nil
+}
+
+func (r *memoryUserRepo) GetSession(_ context.Context, refreshToken string) (*user.Session, error) {
+	s, ok := r.sessions[refreshToken]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return s, nil
+}
+
+func (r *memoryUserRepo) RevokeSession(_ context.Context, refreshToken string) error {
+	if s, ok := r.sessions[refreshToken]; ok {
+		now := time.Now()
+		s.RevokedAt = &now
+	}
+	return nil
+}
+
+func (r *memoryUserRepo) RevokeAllUserSessions(_ context.Context, userID uuid.UUID) error {
+	now := time.Now()
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.RevokedAt == nil {
+			s.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *memoryUserRepo) CreateOAuthState(_ context.Context, state *user.OAuthState) error {
+	r.states[state.State] = state
+	return nil
+}
+
+func (r *memoryUserRepo) GetOAuthState(_ context.Context, state string) (*user.OAuthState, error) {
+	s, ok := r.states[state]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return s, nil
+}
+
+func (r *memoryUserRepo) DeleteOAuthState(_ context.Context, state string) error {
+	delete(r.states, state)
+	return nil
+}
+
+func (r *memoryUserRepo) CreateInvite(_ context.Context, invite *user.Invite) error {
+	invite.ID = uuid.New()
+	r.invites = append(r.invites, invite)
+	return nil
+}
+
+func (r *memoryUserRepo) GetInviteByTokenHash(_ context.Context, tokenHash string) (*user.Invite, error) {
+	for _, invite := range r.invites {
+		if invite.TokenHash == tokenHash {
+			return invite, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryUserRepo) AcceptInvite(_ context.Context, id uuid.UUID, at time.Time) error {
+	for _, invite := range r.invites {
+		if invite.ID == id && invite.AcceptedAt == nil {
+			invite.AcceptedAt = &at
+			return nil
+		}
+	}
+	return errors.New("record not found")
+}
+
+func (r *memoryUserRepo) ListByTenant(_ context.Context, tenantID uuid.UUID, filter user.ListFilter) ([]user.User, int64, error) {
+	var matched []user.User
+	search := strings.ToLower(filter.Search)
+	for _, u := range r.users {
+		if u.TenantID != tenantID {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(u.Email), search) && !strings.Contains(strings.ToLower(u.Name), search) {
+			continue
+		}
+		matched = append(matched, *u)
+	}
+
+	total := int64(len(matched))
+	if filter.Offset >= len(matched) {
+		return nil, total, nil
+	}
+	matched = matched[filter.Offset:]
+	if len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched, total, nil
+}
+
+// fakeTenants records the tenants it creates
+type fakeTenants struct{ names []string }
+
+func (t *fakeTenants) CreateTenant(_ context.Context, name string) (uuid.UUID, error) {
+	t.names = append(t.names, name)
+	return uuid.New(), nil
+}
+
+func newMemoryUseCase(t *testing.T) (*UseCase, *memoryUserRepo, *fakeTenants) {
+	t.Helper()
+
+	keys, err := jwt.NewKeySet(jwt.NewHMACKey("test", []byte("test-secret")), nil, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	repo := &memoryUserRepo{sessions: map[string]*user.Session{}, states: map[string]*user.OAuthState{}}
+	tenants := &fakeTenants{}
+	return NewUseCase(repo, jwt.NewService(keys, 15*time.Minute, time.Hour), tenants, nil, 15*time.Minute), repo, tenants
+}
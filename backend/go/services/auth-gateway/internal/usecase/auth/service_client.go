@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/serviceclient"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// GrantTypeClientCredentials is the only grant accepted by the token endpoint
+const GrantTypeClientCredentials = "client_credentials"
+
+var (
+	ErrInvalidClient        = errors.New("invalid client")
+	ErrInvalidScope         = errors.New("invalid scope")
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+	ErrServiceClientInvalid = errors.New("invalid service client")
+)
+
+var (
+	// serviceNamePattern matches service names such as voice-gateway
+	serviceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,63}$`)
+	// scopePattern matches scopes such as agent-orchestrator:sessions.write
+	scopePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*:[a-z0-9._-]+$`)
+)
+
+// ServiceClientUseCase issues service tokens to internal services through the
+// client credentials grant and manages the registered clients
+type ServiceClientUseCase struct {
+	repo        serviceclient.Repository
+	jwtService  *jwt.Service
+	auditLogger *audit.Logger
+	tokenTTL    time.Duration
+
+	// dummyHash is compared against when the client does not exist, so
+	// unknown and known client IDs take the same time to reject
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+}
+
+// NewServiceClientUseCase creates a new service client use case
+func NewServiceClientUseCase(
+	repo serviceclient.Repository,
+	jwtService *jwt.Service,
+	auditLogger *audit.Logger,
+	tokenTTL time.Duration,
+) *ServiceClientUseCase {
+	return &ServiceClientUseCase{
+		repo:        repo,
+		jwtService:  jwtService,
+		auditLogger: auditLogger,
+		tokenTTL:    tokenTTL,
+	}
+}
+
+// IssueToken verifies the client secret and issues a service token for the
+// requested scopes, which must all be allowed for the client. An empty scope
+// grants every allowed scope.
+func (uc *ServiceClientUseCase) IssueToken(ctx context.Context, req ClientCredentialsRequest) (*ServiceTokenResponse, error) {
+	if req.GrantType != GrantTypeClientCredentials {
+		return nil, ErrUnsupportedGrantType
+	}
+
+	client, err := uc.authenticate(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		scopes = client.ScopeList()
+	}
+	for _, s := range scopes {
+		if !client.Allows(s) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	token, err := uc.jwtService.GenerateServiceToken(client.Service, scopes, uc.tokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Best-effort: bookkeeping must not fail the grant
+	_ = uc.repo.TouchLastUsed(ctx, client.ClientID, time.Now())
+
+	return &ServiceTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(uc.tokenTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// authenticate returns the active client matching the credentials
+func (uc *ServiceClientUseCase) authenticate(ctx context.Context, clientID, secret string) (*serviceclient.ServiceClient, error) {
+	client, err := uc.repo.GetByClientID(ctx, clientID)
+	if err != nil || !client.Active {
+		_ = bcrypt.CompareHashAndPassword(uc.unknownClientHash(), []byte(secret))
+		return nil, ErrInvalidClient
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(client.SecretHash), []byte(secret)); err != nil {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
+
+// unknownClientHash returns the hash compared against for unknown clients
+func (uc *ServiceClientUseCase) unknownClientHash() []byte {
+	uc.dummyHashOnce.Do(func() {
+		uc.dummyHash, _ = bcrypt.GenerateFromPassword([]byte("unknown-client"), bcrypt.DefaultCost)
+	})
+	return uc.dummyHash
+}
+
+// CreateClient registers an internal service and returns its generated
+// credentials. The secret is returned only here; it is stored hashed.
+func (uc *ServiceClientUseCase) CreateClient(ctx context.Context, req CreateServiceClientRequest) (*CreateServiceClientResponse, error) {
+	if !serviceNamePattern.MatchString(req.Service) {
+		return nil, fmt.Errorf("%w: service must be lowercase letters, digits and dashes", ErrServiceClientInvalid)
+	}
+	if len(req.Scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrServiceClientInvalid)
+	}
+	for _, s := range req.Scopes {
+		if !scopePattern.MatchString(s) {
+			return nil, fmt.Errorf("%w: scope %q must look like <service>:<permission>", ErrServiceClientInvalid, s)
+		}
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, err
+	}
+	secret, err := generateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &serviceclient.ServiceClient{
+		ClientID:   "svc_" + hex.EncodeToString(idBytes),
+		SecretHash: string(hash),
+		Service:    req.Service,
+		Scopes:     strings.Join(req.Scopes, " "),
+		Active:     true,
+	}
+	if err := uc.repo.Create(ctx, client); err != nil {
+		return nil, err
+	}
+
+	uc.auditLogger.Record(ctx, audit.Entry{
+		Action:     audit.ActionServiceClientCreate,
+		TargetType: "service_client",
+		TargetID:   client.ClientID,
+		Metadata: map[string]string{
+			"service": client.Service,
+			"scopes":  client.Scopes,
+		},
+	})
+
+	return &CreateServiceClientResponse{
+		ServiceClientResponse: *toServiceClientResponse(client),
+		ClientSecret:          secret,
+	}, nil
+}
+
+// ListClients returns every registered service client
+func (uc *ServiceClientUseCase) ListClients(ctx context.Context) ([]ServiceClientResponse, error) {
+	clients, err := uc.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]ServiceClientResponse, 0, len(clients))
+	for i := range clients {
+		resp = append(resp, *toServiceClientResponse(&clients[i]))
+	}
+	return resp, nil
+}
+
+// RevokeClient deactivates a service client. Tokens already issued stay
+// valid until they expire.
+func (uc *ServiceClientUseCase) RevokeClient(ctx context.Context, clientID string) error {
+	client, err := uc.repo.GetByClientID(ctx, clientID)
+	if err != nil {
+		return ErrInvalidClient
+	}
+	if !client.Active {
+		return nil
+	}
+
+	client.Active = false
+	if err := uc.repo.Update(ctx, client); err != nil {
+		return err
+	}
+
+	uc.auditLogger.Record(ctx, audit.Entry{
+		Action:     audit.ActionServiceClientRevoke,
+		TargetType: "service_client",
+		TargetID:   client.ClientID,
+		Metadata:   map[string]string{"service": client.Service},
+	})
+
+	return nil
+}
+
+// toServiceClientResponse converts a service client to its response
+func toServiceClientResponse(c *serviceclient.ServiceClient) *ServiceClientResponse {
+	return &ServiceClientResponse{
+		ClientID:   c.ClientID,
+		Service:    c.Service,
+		Scopes:     c.ScopeList(),
+		Active:     c.Active,
+		LastUsedAt: c.LastUsedAt,
+		CreatedAt:  c.CreatedAt,
+	}
+}
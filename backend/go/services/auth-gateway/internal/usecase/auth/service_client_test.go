@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/serviceclient"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+)
+
+// fakeServiceClientRepo keeps service clients in memory
+type fakeServiceClientRepo struct {
+	clients map[string]*serviceclient.ServiceClient
+}
+
+func (r *fakeServiceClientRepo) Create(_ context.Context, c *serviceclient.ServiceClient) error {
+	c.CreatedAt = time.Now()
+	r.clients[c.ClientID] = c
+	return nil
+}
+
+func (r *fakeServiceClientRepo) GetByClientID(_ context.Context, clientID string) (*serviceclient.ServiceClient, error) {
+	c, ok := r.clients[clientID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return c, nil
+}
+
+func (r *fakeServiceClientRepo) List(_ context.Context) ([]serviceclient.ServiceClient, error) {
+	var clients []serviceclient.ServiceClient
+	for _, c := range r.clients {
+		clients = append(clients, *c)
+	}
+	return clients, nil
+}
+
+func (r *fakeServiceClientRepo) Update(_ context.Context, c *serviceclient.ServiceClient) error {
+	r.clients[c.ClientID] = c
+	return nil
+}
+
+func (r *fakeServiceClientRepo) TouchLastUsed(_ context.Context, clientID string, at time.Time) error {
+	r.clients[clientID].LastUsedAt = &at
+	return nil
+}
+
+func newServiceClientUseCase(t *testing.T) (*ServiceClientUseCase, *fakeServiceClientRepo, *jwt.Service, *audit.MemoryStore, *audit.Logger) {
+	t.Helper()
+
+	keys, err := jwt.NewKeySet(jwt.NewHMACKey("test", []byte("test-secret")), nil, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	jwtService := jwt.NewService(keys, 15*time.Minute, time.Hour)
+
+	repo := &fakeServiceClientRepo{clients: map[string]*serviceclient.ServiceClient{}}
+	store := audit.NewMemoryStore()
+	auditLogger := audit.NewLogger(store, audit.Options{})
+	return NewServiceClientUseCase(repo, jwtService, auditLogger, 30*time.Minute), repo, jwtService, store, auditLogger
+}
+
+func createVoiceGateway(t *testing.T, uc *ServiceClientUseCase) *CreateServiceClientResponse {
+	t.Helper()
+
+	created, err := uc.CreateClient(context.Background(), CreateServiceClientRequest{
+		Service: "voice-gateway",
+		Scopes:  []string{"agent-orchestrator:sessions.write", "agent-orchestrator:turns.write"},
+	})
+	if err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	return created
+}
+
+func TestServiceClient_StoresOnlyTheSecretHash(t *testing.T) {
+	uc, repo, _, store, auditLogger := newServiceClientUseCase(t)
+	created := createVoiceGateway(t, uc)
+
+	if created.ClientSecret == "" || created.ClientID == "" {
+		t.Fatalf("expected generated credentials, got %+v", created)
+	}
+	stored := repo.clients[created.ClientID]
+	if stored.SecretHash == created.ClientSecret {
+		t.Error("secret stored in plain text")
+	}
+
+	if err := auditLogger.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	entries, _ := store.Query(context.Background(), audit.Filter{Action: audit.ActionServiceClientCreate})
+	if len(entries) != 1 || entries[0].TargetID != created.ClientID {
+		t.Errorf("expected one create audit entry, got %+v", entries)
+	}
+}
+
+func TestServiceClient_IssueTokenVerifiesSecret(t *testing.T) {
+	uc, repo, jwtService, _, _ := newServiceClientUseCase(t)
+	created := createVoiceGateway(t, uc)
+
+	tests := []struct {
+		name string
+		req  ClientCredentialsRequest
+		want error
+	}{
+		{"wrong secret", ClientCredentialsRequest{GrantType: GrantTypeClientCredentials, ClientID: created.ClientID, ClientSecret: "guess"}, ErrInvalidClient},
+		{"unknown client", ClientCredentialsRequest{GrantType: GrantTypeClientCredentials, ClientID: "svc_unknown", ClientSecret: created.ClientSecret}, ErrInvalidClient},
+		{"password grant", ClientCredentialsRequest{GrantType: "password", ClientID: created.ClientID, ClientSecret: created.ClientSecret}, ErrUnsupportedGrantType},
+	}
+	for _, tt := range tests {
+		if _, err := uc.IssueToken(context.Background(), tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	resp, err := uc.IssueToken(context.Background(), ClientCredentialsRequest{
+		GrantType:    GrantTypeClientCredentials,
+		ClientID:     created.ClientID,
+		ClientSecret: created.ClientSecret,
+	})
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	if resp.TokenType != "Bearer" || resp.ExpiresIn != 1800 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	claims, err := jwtService.ValidateServiceToken(resp.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateServiceToken: %v", err)
+	}
+	if claims.Service() != "voice-gateway" || claims.Scope != "agent-orchestrator:sessions.write agent-orchestrator:turns.write" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+	if repo.clients[created.ClientID].LastUsedAt == nil {
+		t.Error("expected last use to be recorded")
+	}
+}
+
+func TestServiceClient_IssueTokenNarrowsScopes(t *testing.T) {
+	uc, _, jwtService, _, _ := newServiceClientUseCase(t)
+	created := createVoiceGateway(t, uc)
+	req := ClientCredentialsRequest{
+		GrantType:    GrantTypeClientCredentials,
+		ClientID:     created.ClientID,
+		ClientSecret: created.ClientSecret,
+	}
+
+	req.Scope = "agent-orchestrator:turns.write"
+	resp, err := uc.IssueToken(context.Background(), req)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	claims, err := jwtService.ValidateServiceToken(resp.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateServiceToken: %v", err)
+	}
+	if claims.Scope != "agent-orchestrator:turns.write" {
+		t.Errorf("scope = %q, want only the requested scope", claims.Scope)
+	}
+
+	req.Scope = "agent-orchestrator:turns.write billing:invoices.write"
+	if _, err := uc.IssueToken(context.Background(), req); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("expected ErrInvalidScope for a scope outside the client's, got %v", err)
+	}
+}
+
+func TestServiceClient_RevokedClientCannotObtainTokens(t *testing.T) {
+	uc, _, _, _, _ := newServiceClientUseCase(t)
+	created := createVoiceGateway(t, uc)
+
+	if err := uc.RevokeClient(context.Background(), created.ClientID); err != nil {
+		t.Fatalf("RevokeClient: %v", err)
+	}
+
+	_, err := uc.IssueToken(context.Background(), ClientCredentialsRequest{
+		GrantType:    GrantTypeClientCredentials,
+		ClientID:     created.ClientID,
+		ClientSecret: created.ClientSecret,
+	})
+	if !errors.Is(err, ErrInvalidClient) {
+		t.Errorf("expected ErrInvalidClient, got %v", err)
+	}
+}
+
+func TestServiceClient_CreateRejectsMalformedScopes(t *testing.T) {
+	uc, _, _, _, _ := newServiceClientUseCase(t)
+
+	for _, req := range []CreateServiceClientRequest{
+		{Service: "Voice Gateway", Scopes: []string{"agent-orchestrator:turns.write"}},
+		{Service: "voice-gateway", Scopes: []string{"admin"}},
+		{Service: "voice-gateway"},
+	} {
+		if _, err := uc.CreateClient(context.Background(), req); !errors.Is(err, ErrServiceClientInvalid) {
+			t.Errorf("%+v: expected ErrServiceClientInvalid, got %v", req, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TokenClaimsSource provides the static claims a tenant adds to the access
+// tokens of its users
+type TokenClaimsSource interface {
+	TokenClaims(ctx context.Context, tenantID uuid.UUID) (map[string]string, error)
+}
+
+// SetTokenClaimsSource enables tenant custom claims in access tokens.
+// Without a source tokens carry only the standard claims.
+func (uc *UseCase) SetTokenClaimsSource(source TokenClaimsSource) {
+	uc.tokenClaims = source
+}
+
+// customClaims returns the custom claims of the tenant. They are best-effort:
+// when the source is unavailable tokens are issued without them. Claims the
+// JWT service rejects fail token issuance instead.
+func (uc *UseCase) customClaims(ctx context.Context, tenantID uuid.UUID) map[string]string {
+	if uc.tokenClaims == nil {
+		return nil
+	}
+	claims, err := uc.tokenClaims.TokenClaims(ctx, tenantID)
+	if err != nil {
+		return nil
+	}
+	return claims
+}
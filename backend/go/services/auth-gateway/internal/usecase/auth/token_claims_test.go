@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
+)
+
+// staticClaims returns the same claims for every tenant, or err
+type staticClaims struct {
+	claims map[string]string
+	err    error
+}
+
+func (s staticClaims) TokenClaims(context.Context, uuid.UUID) (map[string]string, error) {
+	return s.claims, s.err
+}
+
+func TestLogin_TenantCustomClaims(t *testing.T) {
+	uc, _, _ := newMemoryUseCase(t)
+	uc.jwtService.SetCustomClaimsPolicy(jwt.CustomClaimsPolicy{Allowed: []string{"department"}})
+	uc.SetTokenClaimsSource(staticClaims{claims: map[string]string{"department": "sales"}})
+	ctx := context.Background()
+
+	if _, err := uc.Register(ctx, registerRequest("ana@example.com")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	resp, err := uc.Login(ctx, LoginRequest{Email: "ana@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	claims, err := uc.jwtService.ValidateAccessToken(resp.Tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken: %v", err)
+	}
+	if claims.Custom["department"] != "sales" {
+		t.Errorf("custom claims = %v, want department=sales", claims.Custom)
+	}
+}
+
+func TestLogin_TenantCustomClaimsOverridingReserved(t *testing.T) {
+	uc, _, _ := newMemoryUseCase(t)
+	uc.jwtService.SetCustomClaimsPolicy(jwt.CustomClaimsPolicy{Allowed: []string{"department"}})
+	ctx := context.Background()
+
+	if _, err := uc.Register(ctx, registerRequest("ana@example.com")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	uc.SetTokenClaimsSource(staticClaims{claims: map[string]string{"role": RoleSuperadmin}})
+	if _, err := uc.Login(ctx, LoginRequest{Email: "ana@example.com", Password: "password123"}); !errors.Is(err, jwt.ErrInvalidCustomClaims) {
+		t.Errorf("err = %v, want ErrInvalidCustomClaims", err)
+	}
+
+	// An unreachable tenant-manager doesn't block logins
+	uc.SetTokenClaimsSource(staticClaims{err: errors.New("connection refused")})
+	if _, err := uc.Login(ctx, LoginRequest{Email: "ana@example.com", Password: "password123"}); err != nil {
+		t.Errorf("Login without tenant claims: %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package auth
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // LoginRequest represents a login request
 type LoginRequest struct {
@@ -10,10 +14,13 @@ type LoginRequest struct {
 
 // RegisterRequest represents a registration request
 type RegisterRequest struct {
-	Email      string `json:"email" validate:"required,email"`
-	Password   string `json:"password" validate:"required,min=8"`
-	Name       string `json:"name" validate:"required"`
-	TenantName string `json:"tenantName" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+	Name     string `json:"name" validate:"required"`
+	// TenantName names the tenant created for the user; required unless the
+	// user has an invite or the deployment has a single tenant
+	TenantName  string `json:"tenantName"`
+	InviteToken string `json:"inviteToken,omitempty"`
 }
 
 // RefreshTokenRequest represents a refresh token request
@@ -21,6 +28,48 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken" validate:"required"`
 }
 
+// ChangeRoleRequest represents an admin request to change a user's role
+type ChangeRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin user viewer"`
+}
+
+// CreateInviteRequest represents an admin request to invite a user
+type CreateInviteRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=admin user viewer"`
+}
+
+// InviteResponse carries the invite token, shown only once
+type InviteResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SetUserActiveRequest represents an admin request to deactivate or
+// reactivate a user
+type SetUserActiveRequest struct {
+	Active *bool `json:"active" validate:"required"`
+}
+
+// ImpersonateRequest represents a superadmin request to act as a user
+type ImpersonateRequest struct {
+	Reason          string `json:"reason" validate:"required,min=10,max=500"`
+	DurationMinutes int    `json:"durationMinutes" validate:"omitempty,min=1,max=60"`
+}
+
+// ImpersonationResponse carries an access token acting as the user. There is
+// no refresh token.
+type ImpersonationResponse struct {
+	User           UserResponse `json:"user"`
+	AccessToken    string       `json:"accessToken"`
+	ExpiresIn      int          `json:"expiresIn"` // seconds
+	ExpiresAt      time.Time    `json:"expiresAt"`
+	ImpersonatedBy uuid.UUID    `json:"impersonatedBy"`
+}
+
 // AuthResponse represents an authentication response
 type AuthResponse struct {
 	User   UserResponse   `json:"user"`
@@ -34,6 +83,15 @@ type UserResponse struct {
 	Name     string    `json:"name"`
 	Role     string    `json:"role"`
 	TenantID uuid.UUID `json:"tenantId"`
+	Active   bool      `json:"active"`
+}
+
+// UserListResponse is a page of a tenant's users
+type UserListResponse struct {
+	Items  []UserResponse `json:"items"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
 }
 
 // TokensResponse represents authentication tokens
@@ -49,7 +107,56 @@ type OAuthCallbackRequest struct {
 	State string
 }
 
+// CompleteOAuthSignupRequest provides the email a provider did not share
+type CompleteOAuthSignupRequest struct {
+	SignupToken string `json:"signupToken" validate:"required"`
+	Email       string `json:"email" validate:"required,email"`
+}
+
 // OAuthURLResponse represents OAuth authorization URL
 type OAuthURLResponse struct {
 	URL string `json:"url"`
 }
+
+// ClientCredentialsRequest represents an OAuth 2.0 client credentials grant
+// (RFC 6749 section 4.4). Scope is space-delimited; empty requests every
+// scope the client is allowed.
+type ClientCredentialsRequest struct {
+	GrantType    string `form:"grant_type" json:"grant_type" validate:"required"`
+	ClientID     string `form:"client_id" json:"client_id" validate:"required"`
+	ClientSecret string `form:"client_secret" json:"client_secret" validate:"required"`
+	Scope        string `form:"scope" json:"scope"`
+}
+
+// ServiceTokenResponse represents a service token, in the RFC 6749 format
+// expected by OAuth client libraries
+type ServiceTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"` // seconds
+	Scope       string `json:"scope"`
+}
+
+// CreateServiceClientRequest represents a superadmin request to register an
+// internal service
+type CreateServiceClientRequest struct {
+	Service string   `json:"service" validate:"required,max=64"`
+	Scopes  []string `json:"scopes" validate:"required,min=1,dive,required"`
+}
+
+// ServiceClientResponse represents a service client; the secret is never
+// returned after creation
+type ServiceClientResponse struct {
+	ClientID   string     `json:"clientId"`
+	Service    string     `json:"service"`
+	Scopes     []string   `json:"scopes"`
+	Active     bool       `json:"active"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreateServiceClientResponse carries the client secret, shown only once
+type CreateServiceClientResponse struct {
+	ServiceClientResponse
+	ClientSecret string `json:"clientSecret"`
+}
@@ -5,9 +5,13 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	notifications "github.com/serphona/serphona/backend/go/libs/platform-notifications"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/domain/user"
 	"github.com/serphona/serphona/backend/go/services/auth-gateway/internal/service/jwt"
 	"golang.org/x/crypto/bcrypt"
@@ -19,6 +23,7 @@ var (
 	ErrEmailAlreadyExists = errors.New("email already exists")
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrSessionNotFound    = errors.New("session not found")
+	ErrInvalidRole        = errors.New("invalid role")
 )
 
 // UseCase handles authentication business logic
@@ -26,8 +31,21 @@ type UseCase struct {
 	userRepo          user.Repository
 	jwtService        *jwt.Service
 	tenantService     TenantService
+	providersMu       sync.RWMutex
 	oauthProviders    map[string]OAuthProvider
+	auditLogger       *audit.Logger
 	accessTokenExpiry time.Duration
+
+	// Emails to users (optional); see SetNotifier
+	notifier Notifier
+
+	// Tenant custom token claims (optional); see SetTokenClaimsSource
+	tokenClaims TokenClaimsSource
+
+	// Who may register and into which tenant; see SetRegistrationPolicy
+	policyMu        sync.RWMutex
+	policy          RegistrationPolicy
+	defaultTenantID uuid.UUID
 }
 
 // TenantService defines tenant management operations
@@ -35,6 +53,15 @@ type TenantService interface {
 	CreateTenant(ctx context.Context, name string) (uuid.UUID, error)
 }
 
+// Notifier sends emails to users. Sending is best-effort: the notifier
+// reports its own failures and never fails the calling action.
+type Notifier interface {
+	Notify(ctx context.Context, req notifications.EmailRequest)
+}
+
+// WelcomeTemplate is the email sent after a user registers
+const WelcomeTemplate = "auth.welcome"
+
 // OAuthProvider defines OAuth provider interface
 type OAuthProvider interface {
 	GetAuthURL(state string) string
@@ -54,6 +81,7 @@ func NewUseCase(
 	userRepo user.Repository,
 	jwtService *jwt.Service,
 	tenantService TenantService,
+	auditLogger *audit.Logger,
 	accessTokenExpiry time.Duration,
 ) *UseCase {
 	return &UseCase{
@@ -61,15 +89,45 @@ func NewUseCase(
 		jwtService:        jwtService,
 		tenantService:     tenantService,
 		oauthProviders:    make(map[string]OAuthProvider),
+		auditLogger:       auditLogger,
 		accessTokenExpiry: accessTokenExpiry,
 	}
 }
 
 // RegisterOAuthProvider registers an OAuth provider
 func (uc *UseCase) RegisterOAuthProvider(name string, provider OAuthProvider) {
+	uc.providersMu.Lock()
+	defer uc.providersMu.Unlock()
 	uc.oauthProviders[name] = provider
 }
 
+// SetOAuthProviders replaces every registered OAuth provider, e.g. after a
+// configuration reload enabled or disabled some of them
+func (uc *UseCase) SetOAuthProviders(providers map[string]OAuthProvider) {
+	registered := make(map[string]OAuthProvider, len(providers))
+	for name, provider := range providers {
+		registered[name] = provider
+	}
+
+	uc.providersMu.Lock()
+	defer uc.providersMu.Unlock()
+	uc.oauthProviders = registered
+}
+
+// SetNotifier enables user emails such as the welcome email on
+// registration. Without a notifier no email is sent.
+func (uc *UseCase) SetNotifier(notifier Notifier) {
+	uc.notifier = notifier
+}
+
+// oauthProvider returns the registered provider with the given name
+func (uc *UseCase) oauthProvider(name string) (OAuthProvider, bool) {
+	uc.providersMu.RLock()
+	defer uc.providersMu.RUnlock()
+	provider, ok := uc.oauthProviders[name]
+	return provider, ok
+}
+
 // Register registers a new user
 func (uc *UseCase) Register(ctx context.Context, req RegisterRequest) (*AuthResponse, error) {
 	// Check if email already exists
@@ -78,10 +136,21 @@ func (uc *UseCase) Register(ctx context.Context, req RegisterRequest) (*AuthResp
 		return nil, ErrEmailAlreadyExists
 	}
 
-	// Create tenant
-	tenantID, err := uc.tenantService.CreateTenant(ctx, req.TenantName)
-	if err != nil {
-		return nil, err
+	// Invited users join the invite's tenant; the others follow the
+	// registration policy
+	role := "user"
+	var tenantID uuid.UUID
+	if req.InviteToken != "" {
+		invite, err := uc.acceptInvite(ctx, req.InviteToken, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		tenantID, role = invite.TenantID, invite.Role
+	} else {
+		tenantID, err = uc.newUserTenant(ctx, req.TenantName)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Hash password
@@ -96,7 +165,7 @@ func (uc *UseCase) Register(ctx context.Context, req RegisterRequest) (*AuthResp
 		Password: string(hashedPassword),
 		Name:     req.Name,
 		TenantID: tenantID,
-		Role:     "user",
+		Role:     role,
 		Provider: "local",
 		Verified: false,
 		Active:   true,
@@ -106,6 +175,15 @@ func (uc *UseCase) Register(ctx context.Context, req RegisterRequest) (*AuthResp
 		return nil, err
 	}
 
+	if uc.notifier != nil {
+		uc.notifier.Notify(ctx, notifications.EmailRequest{
+			TenantID: newUser.TenantID.String(),
+			To:       newUser.Email,
+			Template: WelcomeTemplate,
+			Data:     map[string]string{"Name": newUser.Name, "Email": newUser.Email},
+		})
+	}
+
 	// Generate tokens
 	return uc.generateAuthResponse(ctx, newUser)
 }
@@ -176,13 +254,7 @@ func (uc *UseCase) GetCurrentUser(ctx context.Context, userID uuid.UUID) (*UserR
 		return nil, ErrUserNotFound
 	}
 
-	return &UserResponse{
-		ID:       u.ID,
-		Email:    u.Email,
-		Name:     u.Name,
-		Role:     u.Role,
-		TenantID: u.TenantID,
-	}, nil
+	return toUserResponse(u), nil
 }
 
 // Logout revokes all sessions for a user
@@ -192,7 +264,7 @@ func (uc *UseCase) Logout(ctx context.Context, userID uuid.UUID) error {
 
 // GetOAuthURL generates OAuth authorization URL
 func (uc *UseCase) GetOAuthURL(ctx context.Context, provider string) (*OAuthURLResponse, error) {
-	oauthProvider, ok := uc.oauthProviders[provider]
+	oauthProvider, ok := uc.oauthProvider(provider)
 	if !ok {
 		return nil, errors.New("provider not supported")
 	}
@@ -219,11 +291,13 @@ func (uc *UseCase) GetOAuthURL(ctx context.Context, provider string) (*OAuthURLR
 	return &OAuthURLResponse{URL: url}, nil
 }
 
-// HandleOAuthCallback handles OAuth callback
+// HandleOAuthCallback handles OAuth callback. When the provider does not
+// share the email of a user signing in for the first time it returns an
+// *OAuthEmailRequiredError; the signup finishes with CompleteOAuthSignup.
 func (uc *UseCase) HandleOAuthCallback(ctx context.Context, req OAuthCallbackRequest) (*AuthResponse, error) {
 	// Verify state
 	oauthState, err := uc.userRepo.GetOAuthState(ctx, req.State)
-	if err != nil {
+	if err != nil || oauthState.ProviderID != "" {
 		return nil, errors.New("invalid state")
 	}
 
@@ -235,7 +309,7 @@ func (uc *UseCase) HandleOAuthCallback(ctx context.Context, req OAuthCallbackReq
 	defer uc.userRepo.DeleteOAuthState(ctx, req.State)
 
 	// Get provider
-	oauthProvider, ok := uc.oauthProviders[oauthState.Provider]
+	oauthProvider, ok := uc.oauthProvider(oauthState.Provider)
 	if !ok {
 		return nil, errors.New("provider not found")
 	}
@@ -245,34 +319,27 @@ func (uc *UseCase) HandleOAuthCallback(ctx context.Context, req OAuthCallbackReq
 	if err != nil {
 		return nil, err
 	}
+	if userInfo.ProviderID == "" {
+		return nil, ErrOAuthProfileIncomplete
+	}
+	userInfo.Email = strings.TrimSpace(userInfo.Email)
 
-	// Try to find existing user
+	// Try to find existing user. Providers such as Apple only share the
+	// email on the first login, so the provider ID is the only reliable key.
 	u, err := uc.userRepo.GetByProvider(ctx, oauthState.Provider, userInfo.ProviderID)
 	if err != nil {
+		if userInfo.Email == "" {
+			return nil, uc.startOAuthSignup(ctx, oauthState.Provider, userInfo)
+		}
+
 		// User doesn't exist, try by email
 		u, err = uc.userRepo.GetByEmail(ctx, userInfo.Email)
 		if err != nil {
 			// Create new user with new tenant
-			tenantID, err := uc.tenantService.CreateTenant(ctx, userInfo.Name+"'s Organization")
+			u, err = uc.createOAuthUser(ctx, oauthState.Provider, userInfo)
 			if err != nil {
 				return nil, err
 			}
-
-			u = &user.User{
-				Email:      userInfo.Email,
-				Name:       userInfo.Name,
-				TenantID:   tenantID,
-				Role:       "user",
-				Provider:   oauthState.Provider,
-				ProviderID: userInfo.ProviderID,
-				Verified:   userInfo.Verified,
-				Active:     true,
-				Password:   "", // No password for OAuth users
-			}
-
-			if err := uc.userRepo.Create(ctx, u); err != nil {
-				return nil, err
-			}
 		} else {
 			// Link OAuth to existing user
 			u.Provider = oauthState.Provider
@@ -294,7 +361,7 @@ func (uc *UseCase) HandleOAuthCallback(ctx context.Context, req OAuthCallbackReq
 // generateAuthResponse creates an auth response with tokens
 func (uc *UseCase) generateAuthResponse(ctx context.Context, u *user.User) (*AuthResponse, error) {
 	// Generate access token
-	accessToken, err := uc.jwtService.GenerateAccessToken(u.ID, u.TenantID, u.Email, u.Role)
+	accessToken, err := uc.jwtService.GenerateAccessToken(u.ID, u.TenantID, u.Email, u.Role, uc.customClaims(ctx, u.TenantID))
 	if err != nil {
 		return nil, err
 	}
@@ -318,13 +385,7 @@ func (uc *UseCase) generateAuthResponse(ctx context.Context, u *user.User) (*Aut
 	}
 
 	return &AuthResponse{
-		User: UserResponse{
-			ID:       u.ID,
-			Email:    u.Email,
-			Name:     u.Name,
-			Role:     u.Role,
-			TenantID: u.TenantID,
-		},
+		User: *toUserResponse(u),
 		Tokens: TokensResponse{
 			AccessToken:  accessToken,
 			RefreshToken: refreshToken,
@@ -0,0 +1,6 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS audit_log_no_update ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+-- Drop tables
+DROP TABLE IF EXISTS audit_log;
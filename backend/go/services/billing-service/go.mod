@@ -1,11 +1,24 @@
 module github.com/serphona/serphona/backend/go/services/billing-service
 
-go 1.21
+go 1.23
 
 require (
 	github.com/gin-gonic/gin v1.9.1
+	github.com/prometheus/client_golang v1.23.2
+	github.com/serphona/serphona/backend/go/libs/platform-core v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-entitlements v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-events v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-notifications v0.0.0
 	github.com/stripe/stripe-go/v76 v76.6.0
 	go.uber.org/zap v1.26.0
 	gorm.io/gorm v1.25.5
 	gorm.io/driver/postgres v1.5.4
 )
+
+replace github.com/serphona/serphona/backend/go/libs/platform-core => ../../libs/platform-core
+
+replace github.com/serphona/serphona/backend/go/libs/platform-entitlements => ../../libs/platform-entitlements
+
+replace github.com/serphona/serphona/backend/go/libs/platform-events => ../../libs/platform-events
+
+replace github.com/serphona/serphona/backend/go/libs/platform-notifications => ../../libs/platform-notifications
@@ -0,0 +1,162 @@
+package credit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Transaction kinds.
+const (
+	KindPurchase = "purchase" // bought through Stripe
+	KindGrant    = "grant"    // given without payment, e.g. initial credits
+	KindUsage    = "usage"    // consumed by metered usage
+)
+
+// Errors returned by the ledger.
+var (
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	// ErrDuplicate is returned for a transaction whose reference was already
+	// applied, so a redelivered event or webhook is not counted twice.
+	ErrDuplicate = errors.New("credit transaction already applied")
+)
+
+// ValidationError reports an invalid request.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Transaction is an entry of a tenant's append-only credit ledger.
+type Transaction struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TenantID     string    `gorm:"not null;index:idx_credit_transactions_tenant,priority:1;uniqueIndex:idx_credit_transactions_reference,priority:1,where:reference <> ''" json:"tenant_id"`
+	Kind         string    `gorm:"not null" json:"kind"`
+	Credits      int64     `gorm:"not null" json:"credits"` // positive added, negative consumed
+	BalanceAfter int64     `gorm:"not null" json:"balance_after"`
+	Reference    string    `gorm:"not null;default:'';uniqueIndex:idx_credit_transactions_reference,priority:2,where:reference <> ''" json:"reference,omitempty"` // what caused it, e.g. an event or checkout session ID
+	ResourceType string    `gorm:"not null;default:''" json:"resource_type,omitempty"`
+	ResourceID   string    `gorm:"not null;default:''" json:"resource_id,omitempty"`
+	CreatedAt    time.Time `gorm:"index:idx_credit_transactions_tenant,priority:2" json:"created_at"`
+}
+
+// TableName specifies the table name
+func (Transaction) TableName() string {
+	return "credit_transactions"
+}
+
+// Balance is a tenant's current credit balance, kept with the ledger.
+type Balance struct {
+	TenantID  string    `gorm:"primaryKey" json:"tenant_id"`
+	Balance   int64     `gorm:"not null;default:0" json:"balance"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (Balance) TableName() string {
+	return "credit_balances"
+}
+
+// Repository persists the credit ledger.
+type Repository interface {
+	// Apply atomically changes the tenant's balance by tx.Credits, appends tx
+	// and sets tx.BalanceAfter. A change taking the balance below zero fails
+	// with ErrInsufficientCredits, and a reference already applied with
+	// ErrDuplicate; neither changes anything.
+	Apply(ctx context.Context, tx *Transaction) error
+	// Balance returns 0 for a tenant without transactions.
+	Balance(ctx context.Context, tenantID string) (int64, error)
+	// ListTransactions returns a tenant's transactions, newest first.
+	ListTransactions(ctx context.Context, tenantID string, limit int) ([]Transaction, error)
+}
+
+// Usage describes what consumed credits.
+type Usage struct {
+	ResourceType string // e.g. tool_call or llm_tokens
+	ResourceID   string
+	Reference    string // makes the consumption idempotent when set
+}
+
+// MaxTransactionsLimit caps a transaction listing.
+const MaxTransactionsLimit = 500
+
+// Service manages tenant credit balances.
+type Service struct {
+	repo     Repository
+	checkout Checkout
+	pricing  Pricing
+	now      func() time.Time
+}
+
+// NewService creates a Service. checkout may be nil when purchases are
+// disabled.
+func NewService(repo Repository, checkout Checkout, pricing Pricing) *Service {
+	return &Service{repo: repo, checkout: checkout, pricing: pricing, now: time.Now}
+}
+
+// AddCredits adds credits to a tenant's balance.
+func (s *Service) AddCredits(ctx context.Context, tenantID string, credits int64, kind, reference string) (*Transaction, error) {
+	if err := checkCredits(tenantID, credits); err != nil {
+		return nil, err
+	}
+	tx := &Transaction{
+		TenantID:  tenantID,
+		Kind:      kind,
+		Credits:   credits,
+		Reference: reference,
+		CreatedAt: s.now().UTC(),
+	}
+	if err := s.repo.Apply(ctx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// ConsumeCredits takes credits from a tenant's balance, or fails with
+// ErrInsufficientCredits without taking any. Concurrent consumptions never
+// take the balance below zero.
+func (s *Service) ConsumeCredits(ctx context.Context, tenantID string, credits int64, usage Usage) (*Transaction, error) {
+	if err := checkCredits(tenantID, credits); err != nil {
+		return nil, err
+	}
+	tx := &Transaction{
+		TenantID:     tenantID,
+		Kind:         KindUsage,
+		Credits:      -credits,
+		Reference:    usage.Reference,
+		ResourceType: usage.ResourceType,
+		ResourceID:   usage.ResourceID,
+		CreatedAt:    s.now().UTC(),
+	}
+	if err := s.repo.Apply(ctx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// GetBalance returns a tenant's credit balance.
+func (s *Service) GetBalance(ctx context.Context, tenantID string) (int64, error) {
+	return s.repo.Balance(ctx, tenantID)
+}
+
+// Transactions returns a tenant's latest ledger entries, newest first.
+func (s *Service) Transactions(ctx context.Context, tenantID string, limit int) ([]Transaction, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.repo.ListTransactions(ctx, tenantID, min(limit, MaxTransactionsLimit))
+}
+
+func checkCredits(tenantID string, credits int64) error {
+	if strings.TrimSpace(tenantID) == "" {
+		return &ValidationError{Message: "tenant is required"}
+	}
+	if credits <= 0 {
+		return &ValidationError{Message: "credits must be positive"}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package credit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// memRepository is an in-memory Repository.
+type memRepository struct {
+	mu           sync.Mutex
+	balances     map[string]int64
+	transactions []Transaction
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{balances: make(map[string]int64)}
+}
+
+func (r *memRepository) Apply(_ context.Context, tx *Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if tx.Reference != "" {
+		for _, existing := range r.transactions {
+			if existing.TenantID == tx.TenantID && existing.Reference == tx.Reference {
+				return ErrDuplicate
+			}
+		}
+	}
+	balance := r.balances[tx.TenantID] + tx.Credits
+	if balance < 0 {
+		return ErrInsufficientCredits
+	}
+	r.balances[tx.TenantID] = balance
+	tx.ID = uint(len(r.transactions) + 1)
+	tx.BalanceAfter = balance
+	r.transactions = append(r.transactions, *tx)
+	return nil
+}
+
+func (r *memRepository) Balance(_ context.Context, tenantID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.balances[tenantID], nil
+}
+
+func (r *memRepository) ListTransactions(_ context.Context, tenantID string, limit int) ([]Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Transaction
+	for i := len(r.transactions) - 1; i >= 0 && len(out) < limit; i-- {
+		if r.transactions[i].TenantID == tenantID {
+			out = append(out, r.transactions[i])
+		}
+	}
+	return out, nil
+}
+
+func TestConsumeCredits_InsufficientBalance(t *testing.T) {
+	repo := newMemRepository()
+	s := NewService(repo, nil, Pricing{})
+	ctx := context.Background()
+
+	if _, err := s.AddCredits(ctx, "tenant-1", 10, KindGrant, ""); err != nil {
+		t.Fatalf("AddCredits failed: %v", err)
+	}
+	if _, err := s.ConsumeCredits(ctx, "tenant-1", 11, Usage{ResourceType: "tool_call"}); !errors.Is(err, ErrInsufficientCredits) {
+		t.Fatalf("expected ErrInsufficientCredits, got %v", err)
+	}
+
+	balance, _ := s.GetBalance(ctx, "tenant-1")
+	if balance != 10 {
+		t.Fatalf("expected a rejected consumption to leave the balance at 10, got %d", balance)
+	}
+
+	tx, err := s.ConsumeCredits(ctx, "tenant-1", 10, Usage{ResourceType: "tool_call"})
+	if err != nil {
+		t.Fatalf("ConsumeCredits failed: %v", err)
+	}
+	if tx.Credits != -10 || tx.BalanceAfter != 0 {
+		t.Errorf("unexpected transaction: %+v", tx)
+	}
+	if txs, _ := s.Transactions(ctx, "tenant-1", 0); len(txs) != 2 {
+		t.Errorf("expected 2 ledger entries, got %d", len(txs))
+	}
+}
+
+func TestConsumeCredits_Concurrent(t *testing.T) {
+	repo := newMemRepository()
+	s := NewService(repo, nil, Pricing{})
+	ctx := context.Background()
+
+	if _, err := s.AddCredits(ctx, "tenant-1", 50, KindGrant, ""); err != nil {
+		t.Fatalf("AddCredits failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	consumed, rejected := 0, 0
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.ConsumeCredits(ctx, "tenant-1", 1, Usage{ResourceType: "tool_call"})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				consumed++
+			case errors.Is(err, ErrInsufficientCredits):
+				rejected++
+			default:
+				t.Errorf("ConsumeCredits failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if consumed != 50 || rejected != 50 {
+		t.Errorf("expected 50 consumed and 50 rejected, got %d and %d", consumed, rejected)
+	}
+	if balance, _ := s.GetBalance(ctx, "tenant-1"); balance != 0 {
+		t.Errorf("expected the balance at 0, got %d", balance)
+	}
+}
+
+func TestConsumeCredits_DuplicateReference(t *testing.T) {
+	s := NewService(newMemRepository(), nil, Pricing{})
+	ctx := context.Background()
+	s.AddCredits(ctx, "tenant-1", 10, KindGrant, "")
+
+	usage := Usage{ResourceType: "tool_call", Reference: "event:1"}
+	if _, err := s.ConsumeCredits(ctx, "tenant-1", 3, usage); err != nil {
+		t.Fatalf("ConsumeCredits failed: %v", err)
+	}
+	if _, err := s.ConsumeCredits(ctx, "tenant-1", 3, usage); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+	if balance, _ := s.GetBalance(ctx, "tenant-1"); balance != 7 {
+		t.Errorf("expected the balance at 7, got %d", balance)
+	}
+}
+
+func TestMetering_Handle(t *testing.T) {
+	s := NewService(newMemRepository(), nil, Pricing{})
+	ctx := context.Background()
+	s.AddCredits(ctx, "tenant-1", 100, KindGrant, "")
+	m := NewMetering(s, Rates{ToolCall: 2, LLMTokensPerCredit: 1000})
+
+	tool := events.NewEvent(topics.ToolCompleted, "tools-gateway", events.ToolCompletedEvent{
+		ToolID: "tool-1", TenantID: "tenant-1", CompletedAt: time.Now(),
+	})
+	llm := events.NewEvent(topics.LLMCost, "agent-orchestrator", events.LLMCostEvent{
+		TenantID: "tenant-1", ConversationID: "conv-1", PromptTokens: 1500, CompletionTokens: 200,
+	})
+	for _, event := range []*types.Event{tool, tool, llm} {
+		if err := m.Handle(event); err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+	}
+
+	// 2 for the tool call, once despite the redelivery, and 2 for 1700 tokens
+	if balance, _ := s.GetBalance(ctx, "tenant-1"); balance != 96 {
+		t.Errorf("expected the balance at 96, got %d", balance)
+	}
+}
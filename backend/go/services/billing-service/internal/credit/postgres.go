@@ -0,0 +1,83 @@
+package credit
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements Repository on PostgreSQL
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository creates a new GormRepository
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// Apply changes the balance and appends the transaction in one database
+// transaction. The balance row is updated with a conditional statement, so
+// concurrent consumptions serialize on it and can't overdraw.
+func (r *GormRepository) Apply(ctx context.Context, t *Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if t.Reference != "" {
+			var count int64
+			if err := tx.Model(&Transaction{}).Where("tenant_id = ? AND reference = ?", t.TenantID, t.Reference).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrDuplicate
+			}
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&Balance{TenantID: t.TenantID, UpdatedAt: t.CreatedAt}).Error; err != nil {
+			return err
+		}
+
+		var balance Balance
+		result := tx.Model(&balance).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "balance"}}}).
+			Where("tenant_id = ? AND balance + ? >= 0", t.TenantID, t.Credits).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", t.Credits),
+				"updated_at": t.CreatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInsufficientCredits
+		}
+
+		t.BalanceAfter = balance.Balance
+		if err := tx.Create(t).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrDuplicate
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Balance returns a tenant's balance
+func (r *GormRepository) Balance(ctx context.Context, tenantID string) (int64, error) {
+	var b Balance
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).First(&b).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return b.Balance, err
+}
+
+// ListTransactions returns a tenant's transactions, newest first
+func (r *GormRepository) ListTransactions(ctx context.Context, tenantID string, limit int) ([]Transaction, error) {
+	var txs []Transaction
+	err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&txs).Error
+	return txs, err
+}
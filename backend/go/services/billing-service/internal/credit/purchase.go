@@ -0,0 +1,117 @@
+package credit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// ErrPurchasesDisabled is returned when no Stripe checkout is configured.
+var ErrPurchasesDisabled = errors.New("credit purchases are not enabled")
+
+// purchaseMetadata marks checkout sessions buying credits.
+const purchaseMetadata = "credit_purchase"
+
+// Checkout starts Stripe Checkout sessions.
+type Checkout interface {
+	NewCheckoutSession(ctx context.Context, params *stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error)
+}
+
+// Pricing is what credits cost.
+type Pricing struct {
+	Currency   string // ISO code, e.g. BRL
+	UnitAmount int64  // price of one credit in the currency's smallest unit
+	MinAmount  int64  // smallest purchase, in the smallest unit; 0 for no minimum
+	MaxAmount  int64  // largest purchase, in the smallest unit; 0 for no maximum
+}
+
+// PurchaseRequest buys credits for a tenant through Stripe Checkout.
+type PurchaseRequest struct {
+	TenantID   string
+	CustomerID string
+	Credits    int64
+	SuccessURL string
+	CancelURL  string
+}
+
+// PurchaseSession is a started credit purchase.
+type PurchaseSession struct {
+	ID       string `json:"session_id"`
+	URL      string `json:"url"`
+	Credits  int64  `json:"credits"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Purchase starts a Stripe Checkout payment for credits. They are added
+// when Stripe reports the session completed and paid.
+func (s *Service) Purchase(ctx context.Context, req PurchaseRequest) (*PurchaseSession, error) {
+	if s.checkout == nil || s.pricing.UnitAmount <= 0 {
+		return nil, ErrPurchasesDisabled
+	}
+	if err := checkCredits(req.TenantID, req.Credits); err != nil {
+		return nil, err
+	}
+	if req.CustomerID == "" || req.SuccessURL == "" || req.CancelURL == "" {
+		return nil, &ValidationError{Message: "customer_id, success_url and cancel_url are required"}
+	}
+	amount := req.Credits * s.pricing.UnitAmount
+	if s.pricing.MinAmount > 0 && amount < s.pricing.MinAmount {
+		return nil, &ValidationError{Message: fmt.Sprintf("purchase must be at least %d", s.pricing.MinAmount)}
+	}
+	if s.pricing.MaxAmount > 0 && amount > s.pricing.MaxAmount {
+		return nil, &ValidationError{Message: fmt.Sprintf("purchase must be at most %d", s.pricing.MaxAmount)}
+	}
+
+	currency := strings.ToLower(s.pricing.Currency)
+	params := &stripe.CheckoutSessionParams{
+		Mode:              stripe.String(string(stripe.CheckoutSessionModePayment)),
+		Customer:          stripe.String(req.CustomerID),
+		ClientReferenceID: stripe.String(req.TenantID),
+		SuccessURL:        stripe.String(req.SuccessURL),
+		CancelURL:         stripe.String(req.CancelURL),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{{
+			Quantity: stripe.Int64(req.Credits),
+			PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+				Currency:   stripe.String(currency),
+				UnitAmount: stripe.Int64(s.pricing.UnitAmount),
+				ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+					Name: stripe.String("Credits"),
+				},
+			},
+		}},
+	}
+	params.AddMetadata(purchaseMetadata, "true")
+	params.AddMetadata("tenant_id", req.TenantID)
+	params.AddMetadata("credits", strconv.FormatInt(req.Credits, 10))
+
+	session, err := s.checkout.NewCheckoutSession(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("stripe request failed: %w", err)
+	}
+	return &PurchaseSession{
+		ID:       session.ID,
+		URL:      session.URL,
+		Credits:  req.Credits,
+		Amount:   amount,
+		Currency: currency,
+	}, nil
+}
+
+// CompletePurchase adds the credits of a completed checkout session. It
+// returns nil for sessions that are not credit purchases or not paid yet,
+// and ErrDuplicate for a session already credited.
+func (s *Service) CompletePurchase(ctx context.Context, session *stripe.CheckoutSession) (*Transaction, error) {
+	if session.Metadata[purchaseMetadata] != "true" || session.PaymentStatus != stripe.CheckoutSessionPaymentStatusPaid {
+		return nil, nil
+	}
+	credits, err := strconv.ParseInt(session.Metadata["credits"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("checkout session %s has invalid credits metadata: %w", session.ID, err)
+	}
+	return s.AddCredits(ctx, session.Metadata["tenant_id"], credits, KindPurchase, "checkout:"+session.ID)
+}
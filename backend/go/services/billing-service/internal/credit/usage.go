@@ -0,0 +1,96 @@
+package credit
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// Rates are the credits metered usage costs. Zero rates meter nothing.
+type Rates struct {
+	ToolCall           int64 // per tool execution
+	LLMTokensPerCredit int64 // prompt and completion tokens one credit buys
+}
+
+// Metering consumes credits for metered usage events.
+type Metering struct {
+	credits *Service
+	rates   Rates
+}
+
+// NewMetering creates a Metering charging credits at rates.
+func NewMetering(credits *Service, rates Rates) *Metering {
+	return &Metering{credits: credits, rates: rates}
+}
+
+// EventTypes returns the metered event types, to subscribe Handle to.
+func (m *Metering) EventTypes() []string {
+	return []string{topics.ToolCompleted, topics.LLMCost}
+}
+
+// Handle consumes the credits of a usage event. The event ID makes it
+// idempotent, so redeliveries are not charged twice. Usage that already
+// happened can't be rejected; a tenant out of credits is only logged.
+func (m *Metering) Handle(event *types.Event) error {
+	tenantID, credits, usage, err := m.price(event)
+	if err != nil {
+		// Retrying would not help; skip the event
+		log.Printf("Skipping usage event %s: %v", event.ID, err)
+		return nil
+	}
+	if credits <= 0 || tenantID == "" {
+		return nil
+	}
+
+	_, err = m.credits.ConsumeCredits(context.Background(), tenantID, credits, usage)
+	switch {
+	case errors.Is(err, ErrDuplicate):
+		return nil
+	case errors.Is(err, ErrInsufficientCredits):
+		log.Printf("Tenant %s has insufficient credits for %s %s", tenantID, usage.ResourceType, usage.ResourceID)
+		return nil
+	default:
+		return err
+	}
+}
+
+// price returns the tenant and credits an event costs.
+func (m *Metering) price(event *types.Event) (string, int64, Usage, error) {
+	usage := Usage{Reference: "event:" + event.ID}
+	switch event.Type {
+	case topics.ToolCompleted:
+		var data events.ToolCompletedEvent
+		if err := codec.DecodeData(event, &data); err != nil {
+			return "", 0, usage, err
+		}
+		usage.ResourceType, usage.ResourceID = "tool_call", data.ToolID
+		return tenantOf(event, data.TenantID), m.rates.ToolCall, usage, nil
+	case topics.LLMCost:
+		var data events.LLMCostEvent
+		if err := codec.DecodeData(event, &data); err != nil {
+			return "", 0, usage, err
+		}
+		usage.ResourceType, usage.ResourceID = "llm_tokens", data.ConversationID
+		if m.rates.LLMTokensPerCredit <= 0 {
+			return "", 0, usage, nil
+		}
+		tokens := int64(data.PromptTokens + data.CompletionTokens)
+		// Any started block of tokens costs a credit
+		credits := (tokens + m.rates.LLMTokensPerCredit - 1) / m.rates.LLMTokensPerCredit
+		return tenantOf(event, data.TenantID), credits, usage, nil
+	default:
+		return "", 0, usage, nil
+	}
+}
+
+func tenantOf(event *types.Event, tenantID string) string {
+	if tenantID != "" {
+		return tenantID
+	}
+	return event.TenantID
+}
@@ -0,0 +1,70 @@
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	notifications "github.com/serphona/serphona/backend/go/libs/platform-notifications"
+	"github.com/stripe/stripe-go/v76"
+)
+
+// PaymentFailedTemplate is the email sent when an invoice payment fails.
+const PaymentFailedTemplate = "billing.payment_failed"
+
+// Mailer queues emails for delivery.
+type Mailer interface {
+	SendEmail(ctx context.Context, req notifications.EmailRequest) error
+}
+
+// Templates returns the billing email templates.
+func Templates() *notifications.Templates {
+	return notifications.NewTemplates().
+		MustRegister(PaymentFailedTemplate, notifications.Template{
+			Subject: "Falha no pagamento da fatura {{.Number}}",
+			Text: `Não conseguimos cobrar {{.Amount}} referente à fatura {{.Number}}.
+{{if .NextAttempt}}
+Faremos uma nova tentativa em {{.NextAttempt}}.{{end}}
+Atualize sua forma de pagamento para evitar a suspensão do serviço:
+{{.URL}}
+
+Equipe Serphona`,
+			HTML: `<p>Não conseguimos cobrar <strong>{{.Amount}}</strong> referente à fatura {{.Number}}.</p>
+{{if .NextAttempt}}<p>Faremos uma nova tentativa em {{.NextAttempt}}.</p>{{end}}
+<p><a href="{{.URL}}">Atualize sua forma de pagamento</a> para evitar a suspensão do serviço.</p>
+<p>Equipe Serphona</p>`,
+		})
+}
+
+// NotifyPaymentFailed emails the customer of inv that its payment failed,
+// with a link to pay it. Invoices without a customer email are skipped.
+func NotifyPaymentFailed(ctx context.Context, mailer Mailer, inv *stripe.Invoice) error {
+	if inv.CustomerEmail == "" {
+		return nil
+	}
+
+	data := map[string]string{
+		"Number":      inv.Number,
+		"Amount":      formatAmount(inv.AmountDue, inv.Currency),
+		"URL":         inv.HostedInvoiceURL,
+		"NextAttempt": "",
+	}
+	if inv.NextPaymentAttempt > 0 {
+		data["NextAttempt"] = time.Unix(inv.NextPaymentAttempt, 0).UTC().Format("02/01/2006")
+	}
+
+	return mailer.SendEmail(ctx, notifications.EmailRequest{
+		TenantID: tenantOf(inv),
+		To:       inv.CustomerEmail,
+		Template: PaymentFailedTemplate,
+		Data:     data,
+	})
+}
+
+// formatAmount formats an amount in the currency's smallest unit, such as
+// 4990 BRL as "BRL 49.90". Zero-decimal currencies are not special-cased;
+// the platform bills in BRL, USD and EUR.
+func formatAmount(amount int64, currency stripe.Currency) string {
+	return fmt.Sprintf("%s %d.%02d", strings.ToUpper(string(currency)), amount/100, amount%100)
+}
@@ -0,0 +1,64 @@
+package invoice
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	notifications "github.com/serphona/serphona/backend/go/libs/platform-notifications"
+	"github.com/stripe/stripe-go/v76"
+)
+
+// recordingMailer keeps the requested emails.
+type recordingMailer struct {
+	requests []notifications.EmailRequest
+}
+
+func (m *recordingMailer) SendEmail(_ context.Context, req notifications.EmailRequest) error {
+	m.requests = append(m.requests, req)
+	return nil
+}
+
+func TestNotifyPaymentFailed(t *testing.T) {
+	mailer := &recordingMailer{}
+	inv := &stripe.Invoice{
+		Number:             "ABC-0001",
+		CustomerEmail:      "billing@acme.com",
+		AmountDue:          4990,
+		Currency:           stripe.CurrencyBRL,
+		HostedInvoiceURL:   "https://invoice.stripe.com/i/abc",
+		NextPaymentAttempt: 1767225600, // 2026-01-01
+		Customer:           &stripe.Customer{Metadata: map[string]string{"tenant_id": "tenant-1"}},
+	}
+
+	if err := NotifyPaymentFailed(context.Background(), mailer, inv); err != nil {
+		t.Fatalf("NotifyPaymentFailed failed: %v", err)
+	}
+	if len(mailer.requests) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.requests))
+	}
+	req := mailer.requests[0]
+	if req.To != "billing@acme.com" || req.TenantID != "tenant-1" || req.Template != PaymentFailedTemplate {
+		t.Errorf("unexpected request %+v", req)
+	}
+
+	r, err := Templates().Render(req.Template, req.Data)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	for _, want := range []string{"BRL 49.90", "ABC-0001", "01/01/2026", "https://invoice.stripe.com/i/abc"} {
+		if !strings.Contains(r.Text, want) {
+			t.Errorf("expected the email to mention %q, got:\n%s", want, r.Text)
+		}
+	}
+}
+
+func TestNotifyPaymentFailed_NoCustomerEmail(t *testing.T) {
+	mailer := &recordingMailer{}
+	if err := NotifyPaymentFailed(context.Background(), mailer, &stripe.Invoice{Number: "ABC-0001"}); err != nil {
+		t.Fatalf("NotifyPaymentFailed failed: %v", err)
+	}
+	if len(mailer.requests) != 0 {
+		t.Errorf("expected no email, got %d", len(mailer.requests))
+	}
+}
@@ -0,0 +1,172 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+var (
+	// ErrNotFound is returned for an invoice Stripe does not know, or one
+	// of another tenant.
+	ErrNotFound = errors.New("invoice not found")
+	// ErrNotFinalized is returned for a draft invoice, which has no PDF yet.
+	ErrNotFinalized = errors.New("invoice is not finalized yet")
+)
+
+// StripeClient is the part of the Stripe API the service uses.
+type StripeClient interface {
+	// GetInvoice returns the invoice with its customer expanded.
+	GetInvoice(ctx context.Context, id string) (*stripe.Invoice, error)
+}
+
+// PDF is an invoice document being downloaded. The caller must close Body.
+type PDF struct {
+	Filename string
+	Size     int64 // -1 when unknown
+	Body     io.ReadCloser
+}
+
+// cachedPDF is the PDF URL of a finalized invoice.
+type cachedPDF struct {
+	tenantID string
+	filename string
+	url      string
+	expires  time.Time
+}
+
+// Service serves invoice documents from Stripe.
+type Service struct {
+	stripe StripeClient
+	client *http.Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedPDF
+}
+
+// NewService creates a Service caching PDF URLs for ttl.
+func NewService(stripe StripeClient, client *http.Client, ttl time.Duration) *Service {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Service{
+		stripe: stripe,
+		client: client,
+		ttl:    ttl,
+		now:    time.Now,
+		cache:  make(map[string]cachedPDF),
+	}
+}
+
+// PDF streams the PDF Stripe renders for a finalized invoice of the tenant.
+// The PDF URL is cached; a cached URL Stripe no longer serves is looked up
+// again once.
+func (s *Service) PDF(ctx context.Context, tenantID, invoiceID string) (*PDF, error) {
+	entry, cached := s.cached(invoiceID)
+	if !cached {
+		var err error
+		if entry, err = s.lookup(ctx, invoiceID); err != nil {
+			return nil, err
+		}
+	}
+	if entry.tenantID != tenantID {
+		return nil, ErrNotFound
+	}
+
+	resp, err := s.download(ctx, entry.url)
+	if err != nil && cached {
+		s.forget(invoiceID)
+		if entry, err = s.lookup(ctx, invoiceID); err != nil {
+			return nil, err
+		}
+		resp, err = s.download(ctx, entry.url)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &PDF{Filename: entry.filename, Size: resp.ContentLength, Body: resp.Body}, nil
+}
+
+// lookup fetches the invoice from Stripe and caches its PDF URL.
+func (s *Service) lookup(ctx context.Context, invoiceID string) (cachedPDF, error) {
+	inv, err := s.stripe.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		var se *stripe.Error
+		if errors.As(err, &se) && se.HTTPStatusCode == http.StatusNotFound {
+			return cachedPDF{}, ErrNotFound
+		}
+		return cachedPDF{}, fmt.Errorf("stripe request failed: %w", err)
+	}
+	if inv.Status == stripe.InvoiceStatusDraft || inv.InvoicePDF == "" {
+		return cachedPDF{}, ErrNotFinalized
+	}
+
+	filename := inv.Number
+	if filename == "" {
+		filename = inv.ID
+	}
+	entry := cachedPDF{
+		tenantID: tenantOf(inv),
+		filename: "invoice-" + filename + ".pdf",
+		url:      inv.InvoicePDF,
+		expires:  s.now().Add(s.ttl),
+	}
+	s.mu.Lock()
+	s.cache[invoiceID] = entry
+	s.mu.Unlock()
+	return entry, nil
+}
+
+func (s *Service) cached(invoiceID string) (cachedPDF, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[invoiceID]
+	if !ok || !s.now().Before(entry.expires) {
+		delete(s.cache, invoiceID)
+		return cachedPDF{}, false
+	}
+	return entry, true
+}
+
+func (s *Service) forget(invoiceID string) {
+	s.mu.Lock()
+	delete(s.cache, invoiceID)
+	s.mu.Unlock()
+}
+
+// download starts fetching a PDF.
+func (s *Service) download(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("invoice PDF download failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("invoice PDF download failed: status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// tenantOf returns the tenant an invoice belongs to: the tenant_id metadata
+// of its subscription, set at subscription creation, or of its customer.
+func tenantOf(inv *stripe.Invoice) string {
+	if inv.SubscriptionDetails != nil && inv.SubscriptionDetails.Metadata["tenant_id"] != "" {
+		return inv.SubscriptionDetails.Metadata["tenant_id"]
+	}
+	if inv.Customer != nil {
+		return inv.Customer.Metadata["tenant_id"]
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// stubStripe is a StripeClient returning canned invoices.
+type stubStripe struct {
+	invoices map[string]*stripe.Invoice
+	calls    int
+}
+
+func (s *stubStripe) GetInvoice(_ context.Context, id string) (*stripe.Invoice, error) {
+	s.calls++
+	inv, ok := s.invoices[id]
+	if !ok {
+		return nil, &stripe.Error{HTTPStatusCode: http.StatusNotFound, Msg: "No such invoice"}
+	}
+	return inv, nil
+}
+
+func newPDFServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/in_123.pdf" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, "%PDF-1.4 invoice")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPDF_StreamsAndCachesURL(t *testing.T) {
+	srv := newPDFServer(t)
+	stub := &stubStripe{invoices: map[string]*stripe.Invoice{
+		"in_123": {
+			ID:                  "in_123",
+			Number:              "ABC-0001",
+			Status:              stripe.InvoiceStatusOpen,
+			InvoicePDF:          srv.URL + "/in_123.pdf",
+			SubscriptionDetails: &stripe.InvoiceSubscriptionDetails{Metadata: map[string]string{"tenant_id": "tenant-1"}},
+		},
+	}}
+	s := NewService(stub, srv.Client(), time.Hour)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		pdf, err := s.PDF(ctx, "tenant-1", "in_123")
+		if err != nil {
+			t.Fatalf("PDF failed: %v", err)
+		}
+		body, _ := io.ReadAll(pdf.Body)
+		pdf.Body.Close()
+		if string(body) != "%PDF-1.4 invoice" {
+			t.Errorf("unexpected body %q", body)
+		}
+		if pdf.Filename != "invoice-ABC-0001.pdf" {
+			t.Errorf("unexpected filename %q", pdf.Filename)
+		}
+	}
+	if stub.calls != 1 {
+		t.Errorf("expected the PDF URL to be cached, Stripe was called %d times", stub.calls)
+	}
+
+	if _, err := s.PDF(ctx, "tenant-2", "in_123"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for another tenant, got %v", err)
+	}
+}
+
+func TestPDF_RefreshesStaleURL(t *testing.T) {
+	srv := newPDFServer(t)
+	inv := &stripe.Invoice{
+		ID:         "in_123",
+		Status:     stripe.InvoiceStatusPaid,
+		InvoicePDF: srv.URL + "/expired.pdf",
+		Customer:   &stripe.Customer{Metadata: map[string]string{"tenant_id": "tenant-1"}},
+	}
+	stub := &stubStripe{invoices: map[string]*stripe.Invoice{"in_123": inv}}
+	s := NewService(stub, srv.Client(), time.Hour)
+	ctx := context.Background()
+
+	if _, err := s.PDF(ctx, "tenant-1", "in_123"); err == nil {
+		t.Fatal("expected the download of a missing PDF to fail")
+	}
+
+	inv.InvoicePDF = srv.URL + "/in_123.pdf"
+	pdf, err := s.PDF(ctx, "tenant-1", "in_123")
+	if err != nil {
+		t.Fatalf("PDF failed: %v", err)
+	}
+	pdf.Body.Close()
+	if pdf.Filename != "invoice-in_123.pdf" {
+		t.Errorf("unexpected filename %q", pdf.Filename)
+	}
+}
+
+func TestPDF_NotFinalized(t *testing.T) {
+	stub := &stubStripe{invoices: map[string]*stripe.Invoice{
+		"in_draft": {ID: "in_draft", Status: stripe.InvoiceStatusDraft},
+	}}
+	s := NewService(stub, nil, time.Hour)
+
+	if _, err := s.PDF(context.Background(), "tenant-1", "in_draft"); !errors.Is(err, ErrNotFinalized) {
+		t.Errorf("expected ErrNotFinalized, got %v", err)
+	}
+	if _, err := s.PDF(context.Background(), "tenant-1", "in_missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
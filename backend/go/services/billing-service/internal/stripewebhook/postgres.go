@@ -0,0 +1,29 @@
+package stripewebhook
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormStore implements Store on PostgreSQL
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore creates a new GormStore
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+// Claim inserts the event unless it is already recorded
+func (s *GormStore) Claim(ctx context.Context, e *ProcessedEvent) (bool, error) {
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(e)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Release deletes the event
+func (s *GormStore) Release(ctx context.Context, eventID string) error {
+	return s.db.WithContext(ctx).Delete(&ProcessedEvent{}, "id = ?", eventID).Error
+}
@@ -0,0 +1,136 @@
+package stripewebhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+var (
+	// ErrInvalidSignature is returned for a payload not signed with the
+	// endpoint secret.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidPayload is returned for an event whose object can't be
+	// decoded as the type its handler expects.
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+	// ErrPayloadTooLarge is returned for a payload over MaxPayloadBytes,
+	// which is refused before its signature is verified.
+	ErrPayloadTooLarge = errors.New("webhook payload too large")
+)
+
+// MaxPayloadBytes caps the size of a webhook payload; Stripe events are far
+// smaller.
+const MaxPayloadBytes = 64 << 10
+
+// Handler handles one Stripe event. Returning an error makes the webhook
+// fail, so Stripe delivers the event again later.
+type Handler func(ctx context.Context, event *stripe.Event) error
+
+// ProcessedEvent records a Stripe event that was handled.
+type ProcessedEvent struct {
+	ID          string    `gorm:"primaryKey" json:"id"`
+	Type        string    `gorm:"not null" json:"type"`
+	ProcessedAt time.Time `gorm:"not null;index" json:"processed_at"`
+}
+
+// TableName specifies the table name
+func (ProcessedEvent) TableName() string {
+	return "stripe_webhook_events"
+}
+
+// Store remembers the events that were handled.
+type Store interface {
+	// Claim records an event as processed, reporting false when it
+	// already was.
+	Claim(ctx context.Context, e *ProcessedEvent) (bool, error)
+	// Release forgets an event whose handling failed, so its redelivery
+	// is handled.
+	Release(ctx context.Context, eventID string) error
+}
+
+// Router verifies Stripe webhooks and dispatches each event, once, to the
+// handler registered for its type.
+type Router struct {
+	secret   string
+	store    Store
+	handlers map[string]Handler
+	now      func() time.Time
+}
+
+// NewRouter creates a Router verifying payloads with the endpoint secret.
+func NewRouter(secret string, store Store) *Router {
+	return &Router{secret: secret, store: store, handlers: make(map[string]Handler), now: time.Now}
+}
+
+// Handle registers h for events of eventType, such as
+// "invoice.payment_succeeded", replacing any handler registered before.
+func (r *Router) Handle(eventType string, h Handler) {
+	r.handlers[eventType] = h
+}
+
+// On registers fn for events of eventType, with the event's object decoded
+// as T, such as stripe.Invoice.
+func On[T any](r *Router, eventType string, fn func(ctx context.Context, object *T) error) {
+	r.Handle(eventType, func(ctx context.Context, event *stripe.Event) error {
+		object := new(T)
+		if err := json.Unmarshal(event.Data.Raw, object); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidPayload, event.Type, err)
+		}
+		return fn(ctx, object)
+	})
+}
+
+// DispatchBody reads a webhook payload of at most MaxPayloadBytes from body
+// and dispatches it. A larger payload is refused with ErrPayloadTooLarge
+// without reading the rest of it, so the signature is never computed over an
+// unbounded buffer.
+func (r *Router) DispatchBody(ctx context.Context, body io.Reader, signature string) error {
+	payload, err := io.ReadAll(io.LimitReader(body, MaxPayloadBytes+1))
+	if err != nil {
+		return fmt.Errorf("%w: failed to read body: %v", ErrInvalidPayload, err)
+	}
+	if len(payload) > MaxPayloadBytes {
+		return ErrPayloadTooLarge
+	}
+	return r.Dispatch(ctx, payload, signature)
+}
+
+// Dispatch verifies a webhook payload against its Stripe-Signature header
+// and hands the event to its handler. Events without a handler and events
+// already handled are acknowledged without doing anything.
+func (r *Router) Dispatch(ctx context.Context, payload []byte, signature string) error {
+	event, err := webhook.ConstructEvent(payload, signature, r.secret)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	h, ok := r.handlers[string(event.Type)]
+	if !ok {
+		log.Printf("Ignoring Stripe webhook %s (%s)", event.ID, event.Type)
+		return nil
+	}
+
+	claimed, err := r.store.Claim(ctx, &ProcessedEvent{ID: event.ID, Type: string(event.Type), ProcessedAt: r.now()})
+	if err != nil {
+		return fmt.Errorf("failed to record webhook %s: %w", event.ID, err)
+	}
+	if !claimed {
+		log.Printf("Skipping redelivered Stripe webhook %s (%s)", event.ID, event.Type)
+		return nil
+	}
+
+	if err := h(ctx, &event); err != nil {
+		if releaseErr := r.store.Release(ctx, event.ID); releaseErr != nil {
+			log.Printf("Failed to release Stripe webhook %s: %v", event.ID, releaseErr)
+		}
+		return fmt.Errorf("webhook %s (%s): %w", event.ID, event.Type, err)
+	}
+	return nil
+}
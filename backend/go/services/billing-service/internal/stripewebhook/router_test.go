@@ -0,0 +1,157 @@
+package stripewebhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+const testSecret = "whsec_test"
+
+// memStore is an in-memory Store.
+type memStore struct {
+	mu     sync.Mutex
+	events map[string]ProcessedEvent
+}
+
+func newMemStore() *memStore {
+	return &memStore{events: make(map[string]ProcessedEvent)}
+}
+
+func (s *memStore) Claim(_ context.Context, e *ProcessedEvent) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[e.ID]; ok {
+		return false, nil
+	}
+	s.events[e.ID] = *e
+	return true, nil
+}
+
+func (s *memStore) Release(_ context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.events, eventID)
+	return nil
+}
+
+// signedEvent returns a payload for an event and its signature header.
+func signedEvent(id, eventType, object string) ([]byte, string) {
+	payload := []byte(fmt.Sprintf(`{"id":%q,"object":"event","type":%q,"api_version":%q,"data":{"object":%s}}`,
+		id, eventType, stripe.APIVersion, object))
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: testSecret})
+	return signed.Payload, signed.Header
+}
+
+func TestDispatch_RoutesByType(t *testing.T) {
+	r := NewRouter(testSecret, newMemStore())
+	var invoices, sessions []string
+	On(r, "invoice.payment_succeeded", func(_ context.Context, inv *stripe.Invoice) error {
+		invoices = append(invoices, inv.ID)
+		return nil
+	})
+	On(r, "checkout.session.completed", func(_ context.Context, s *stripe.CheckoutSession) error {
+		sessions = append(sessions, s.ID)
+		return nil
+	})
+	ctx := context.Background()
+
+	payload, sig := signedEvent("evt_1", "invoice.payment_succeeded", `{"id":"in_1","object":"invoice"}`)
+	if err := r.Dispatch(ctx, payload, sig); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	payload, sig = signedEvent("evt_2", "checkout.session.completed", `{"id":"cs_1","object":"checkout.session"}`)
+	if err := r.Dispatch(ctx, payload, sig); err != nil {
+		t.Fatalf("Dispatch failed: %v", err)
+	}
+	// Unknown types are acknowledged
+	payload, sig = signedEvent("evt_3", "customer.created", `{"id":"cus_1","object":"customer"}`)
+	if err := r.Dispatch(ctx, payload, sig); err != nil {
+		t.Fatalf("expected an unknown type acknowledged, got %v", err)
+	}
+
+	if len(invoices) != 1 || invoices[0] != "in_1" || len(sessions) != 1 || sessions[0] != "cs_1" {
+		t.Errorf("unexpected dispatch: invoices %v, sessions %v", invoices, sessions)
+	}
+}
+
+func TestDispatch_DedupesRedelivery(t *testing.T) {
+	r := NewRouter(testSecret, newMemStore())
+	calls := 0
+	fail := true
+	r.Handle("invoice.paid", func(context.Context, *stripe.Event) error {
+		calls++
+		if fail {
+			return errors.New("database down")
+		}
+		return nil
+	})
+	ctx := context.Background()
+	payload, sig := signedEvent("evt_1", "invoice.paid", `{"id":"in_1","object":"invoice"}`)
+
+	// A failed delivery is handled again when Stripe retries it
+	if err := r.Dispatch(ctx, payload, sig); err == nil {
+		t.Fatal("expected the handler error returned")
+	}
+	fail = false
+	for i := 0; i < 3; i++ {
+		if err := r.Dispatch(ctx, payload, sig); err != nil {
+			t.Fatalf("Dispatch failed: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected the handler called for the failure and once more, got %d calls", calls)
+	}
+}
+
+func TestDispatch_Rejects(t *testing.T) {
+	r := NewRouter(testSecret, newMemStore())
+	On(r, "invoice.paid", func(context.Context, *stripe.Invoice) error { return nil })
+	ctx := context.Background()
+
+	payload, _ := signedEvent("evt_1", "invoice.paid", `{"id":"in_1","object":"invoice"}`)
+	if err := r.Dispatch(ctx, payload, "t=1,v1=bad"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	payload, sig := signedEvent("evt_2", "invoice.paid", `{"id":"in_1","amount_paid":"lots"}`)
+	if err := r.Dispatch(ctx, payload, sig); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("expected ErrInvalidPayload, got %v", err)
+	}
+}
+
+func TestDispatchBody_RejectsOversizedPayloadBeforeVerifying(t *testing.T) {
+	store := newMemStore()
+	r := NewRouter(testSecret, store)
+	var handled int
+	On(r, "invoice.payment_succeeded", func(context.Context, *stripe.Invoice) error {
+		handled++
+		return nil
+	})
+	ctx := context.Background()
+
+	// Correctly signed, so only the size check can refuse it
+	padding := strings.Repeat("x", MaxPayloadBytes)
+	payload, sig := signedEvent("evt_big", "invoice.payment_succeeded", fmt.Sprintf(`{"id":"in_1","object":"invoice","description":%q}`, padding))
+	if err := r.DispatchBody(ctx, bytes.NewReader(payload), sig); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("expected ErrPayloadTooLarge, got %v", err)
+	}
+	if handled != 0 || len(store.events) != 0 {
+		t.Errorf("expected the oversized event not to be handled, handled %d, stored %d", handled, len(store.events))
+	}
+
+	payload, sig = signedEvent("evt_small", "invoice.payment_succeeded", `{"id":"in_2","object":"invoice"}`)
+	if err := r.DispatchBody(ctx, bytes.NewReader(payload), sig); err != nil {
+		t.Fatalf("DispatchBody failed: %v", err)
+	}
+	if handled != 1 {
+		t.Errorf("expected the event handled once, got %d", handled)
+	}
+}
@@ -0,0 +1,179 @@
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrCurrencyChange is returned when a tenant with a subscription in one
+// currency asks for a price in another. Stripe bills a customer in a
+// single currency, so it can't be changed on an existing subscription.
+var ErrCurrencyChange = errors.New("currency can't be changed on an existing subscription")
+
+// ErrUnknownPlan is returned when a request names a plan the catalog
+// doesn't have.
+var ErrUnknownPlan = errors.New("unknown plan")
+
+// PlanPrice is the price of a plan in one currency.
+type PlanPrice struct {
+	Currency      string // ISO code, upper case
+	Amount        int64  // in the currency's smallest unit
+	StripePriceID string
+}
+
+// Plan is a subscription plan and its prices by currency.
+type Plan struct {
+	ID     string
+	Name   string
+	Prices map[string]PlanPrice
+}
+
+// PlanOffer is a plan priced in one currency, as listed to tenants.
+type PlanOffer struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"` // in the currency's smallest unit
+	Currency string `json:"currency"`
+}
+
+// DefaultCountryCurrencies maps ISO country codes to the currency tenants
+// there are billed in. Other countries get the catalog default.
+var DefaultCountryCurrencies = map[string]string{
+	"BR": "BRL",
+	"US": "USD",
+	"AT": "EUR", "BE": "EUR", "CY": "EUR", "DE": "EUR", "EE": "EUR",
+	"ES": "EUR", "FI": "EUR", "FR": "EUR", "GR": "EUR", "HR": "EUR",
+	"IE": "EUR", "IT": "EUR", "LT": "EUR", "LU": "EUR", "LV": "EUR",
+	"MT": "EUR", "NL": "EUR", "PT": "EUR", "SI": "EUR", "SK": "EUR",
+}
+
+// Catalog holds the plans and picks the currency a tenant is billed in.
+type Catalog struct {
+	plans             []Plan
+	defaultCurrency   string
+	countryCurrencies map[string]string
+}
+
+// NewCatalog creates an empty Catalog billing in defaultCurrency where the
+// tenant's country has no currency of its own.
+func NewCatalog(defaultCurrency string) *Catalog {
+	return &Catalog{
+		defaultCurrency:   strings.ToUpper(defaultCurrency),
+		countryCurrencies: DefaultCountryCurrencies,
+	}
+}
+
+// AddPlan adds a plan with its prices. Prices without a Stripe price can
+// be listed but not subscribed to.
+func (c *Catalog) AddPlan(id, name string, prices ...PlanPrice) {
+	plan := Plan{ID: id, Name: name, Prices: make(map[string]PlanPrice, len(prices))}
+	for _, p := range prices {
+		p.Currency = strings.ToUpper(p.Currency)
+		plan.Prices[p.Currency] = p
+	}
+	c.plans = append(c.plans, plan)
+}
+
+// Validate checks that every plan has a Stripe price in the default
+// currency and that no Stripe price belongs to two plans, so a
+// misconfigured catalog is caught at startup instead of by Stripe.
+func (c *Catalog) Validate() error {
+	var errs []error
+	owners := make(map[string]string)
+	for _, plan := range c.plans {
+		if p, ok := plan.Prices[c.defaultCurrency]; !ok || p.StripePriceID == "" {
+			errs = append(errs, fmt.Errorf("plan %q has no Stripe price in %s", plan.ID, c.defaultCurrency))
+		}
+
+		currencies := make([]string, 0, len(plan.Prices))
+		for currency := range plan.Prices {
+			currencies = append(currencies, currency)
+		}
+		sort.Strings(currencies)
+		for _, currency := range currencies {
+			id := plan.Prices[currency].StripePriceID
+			if id == "" {
+				continue
+			}
+			if owner, ok := owners[id]; ok && owner != plan.ID {
+				errs = append(errs, fmt.Errorf("plans %q and %q share Stripe price %q", owner, plan.ID, id))
+				continue
+			}
+			owners[id] = plan.ID
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DefaultCurrency returns the currency of tenants in countries without one.
+func (c *Catalog) DefaultCurrency() string {
+	return c.defaultCurrency
+}
+
+// Currency returns the currency for a tenant in country, an ISO code, or,
+// when the country is unknown, in the country of locale, such as pt-BR.
+func (c *Catalog) Currency(country, locale string) string {
+	if country == "" {
+		country = localeCountry(locale)
+	}
+	if currency, ok := c.countryCurrencies[strings.ToUpper(country)]; ok {
+		return currency
+	}
+	return c.defaultCurrency
+}
+
+// Offers lists the plans priced in currency. Plans without a price in it
+// are left out.
+func (c *Catalog) Offers(currency string) []PlanOffer {
+	currency = strings.ToUpper(currency)
+	offers := []PlanOffer{}
+	for _, plan := range c.plans {
+		if p, ok := plan.Prices[currency]; ok {
+			offers = append(offers, PlanOffer{ID: plan.ID, Name: plan.Name, Amount: p.Amount, Currency: currency})
+		}
+	}
+	return offers
+}
+
+// Price returns the price of a plan in currency.
+func (c *Catalog) Price(planID, currency string) (PlanPrice, error) {
+	currency = strings.ToUpper(currency)
+	for _, plan := range c.plans {
+		if plan.ID != planID {
+			continue
+		}
+		p, ok := plan.Prices[currency]
+		if !ok || p.StripePriceID == "" {
+			return PlanPrice{}, &ValidationError{Message: fmt.Sprintf("plan %q is not available in %s", planID, currency)}
+		}
+		return p, nil
+	}
+	return PlanPrice{}, fmt.Errorf("%w %q", ErrUnknownPlan, planID)
+}
+
+// planOfPrice returns the plan a Stripe price belongs to.
+func (c *Catalog) planOfPrice(stripePriceID string) (string, bool) {
+	for _, plan := range c.plans {
+		for _, p := range plan.Prices {
+			if p.StripePriceID != "" && p.StripePriceID == stripePriceID {
+				return plan.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// localeCountry returns the region of a locale such as pt-BR or pt_BR.
+func localeCountry(locale string) string {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	// Accept-Language may list several locales; the first is preferred
+	locale, _, _ = strings.Cut(locale, ",")
+	locale, _, _ = strings.Cut(locale, ";")
+	parts := strings.Split(strings.TrimSpace(locale), "-")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,133 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func multiCurrencyCatalog() *Catalog {
+	c := NewCatalog("USD")
+	c.AddPlan("pro", "Pro",
+		PlanPrice{Currency: "USD", Amount: 19900, StripePriceID: "price_pro_usd"},
+		PlanPrice{Currency: "BRL", Amount: 99900, StripePriceID: "price_pro_brl"},
+		PlanPrice{Currency: "EUR", Amount: 18500, StripePriceID: "price_pro_eur"},
+	)
+	c.AddPlan("starter", "Starter",
+		PlanPrice{Currency: "USD", Amount: 4900, StripePriceID: "price_starter_usd"},
+	)
+	return c
+}
+
+func TestCatalog_CurrencyByCountry(t *testing.T) {
+	c := multiCurrencyCatalog()
+
+	for _, tc := range []struct {
+		country, locale, want string
+	}{
+		{"BR", "", "BRL"},
+		{"br", "", "BRL"},
+		{"DE", "", "EUR"},
+		{"US", "", "USD"},
+		{"JP", "", "USD"}, // no currency of its own
+		{"", "pt-BR", "BRL"},
+		{"", "fr_FR", "EUR"},
+		{"", "pt-PT,pt;q=0.9,en;q=0.8", "EUR"},
+		{"", "en", "USD"},
+		{"BR", "fr-FR", "BRL"}, // the country wins over the locale
+	} {
+		if got := c.Currency(tc.country, tc.locale); got != tc.want {
+			t.Errorf("Currency(%q, %q) = %s, want %s", tc.country, tc.locale, got, tc.want)
+		}
+	}
+
+	offers := c.Offers("brl")
+	if len(offers) != 1 || offers[0].ID != "pro" || offers[0].Amount != 99900 || offers[0].Currency != "BRL" {
+		t.Errorf("unexpected BRL offers: %+v", offers)
+	}
+}
+
+func TestCreateCheckout_PricesInTenantCurrency(t *testing.T) {
+	stub := newStubStripe()
+	s := NewService(stub, newMemRepository(), multiCurrencyCatalog())
+
+	session, err := s.CreateCheckout(context.Background(), CheckoutRequest{
+		TenantID: "tenant-1", CustomerID: "cus_123", Plan: "pro", Country: "BR",
+		SuccessURL: "https://app/success", CancelURL: "https://app/cancel",
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckout failed: %v", err)
+	}
+	if session.Currency != "BRL" || *stub.checkout.LineItems[0].Price != "price_pro_brl" {
+		t.Errorf("expected the BRL price, got %s and %s", session.Currency, *stub.checkout.LineItems[0].Price)
+	}
+
+	var validationErr *ValidationError
+	if _, err := s.CreateCheckout(context.Background(), CheckoutRequest{
+		TenantID: "tenant-1", CustomerID: "cus_123", Plan: "starter", Country: "BR",
+		SuccessURL: "https://app/success", CancelURL: "https://app/cancel",
+	}); !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError for a plan without a BRL price, got %v", err)
+	}
+}
+
+func TestCreateSubscription_RejectsCurrencyChange(t *testing.T) {
+	stub := newStubStripe()
+	repo := newMemRepository()
+	repo.subscriptions["sub_old"] = Record{ID: "sub_old", TenantID: "tenant-1", Plan: "pro", Status: "active", Currency: "BRL"}
+	s := NewService(stub, repo, multiCurrencyCatalog())
+	ctx := context.Background()
+
+	_, err := s.CreateSubscription(ctx, SubscriptionRequest{TenantID: "tenant-1", CustomerID: "cus_123", Plan: "pro", Currency: "usd"})
+	if !errors.Is(err, ErrCurrencyChange) {
+		t.Fatalf("expected ErrCurrencyChange, got %v", err)
+	}
+	if stub.newSubscription != nil {
+		t.Error("expected no subscription created")
+	}
+
+	// Without an explicit currency, the tenant stays in its own, whatever
+	// its country
+	sub, err := s.CreateSubscription(ctx, SubscriptionRequest{TenantID: "tenant-1", CustomerID: "cus_123", Plan: "pro", Country: "US"})
+	if err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	if sub.Currency != "BRL" || *stub.newSubscription.Items[0].Price != "price_pro_brl" {
+		t.Errorf("expected the BRL price, got %s and %s", sub.Currency, *stub.newSubscription.Items[0].Price)
+	}
+}
+
+func TestCatalog_Validate(t *testing.T) {
+	if err := multiCurrencyCatalog().Validate(); err != nil {
+		t.Fatalf("expected a valid catalog, got %v", err)
+	}
+
+	c := multiCurrencyCatalog()
+	c.AddPlan("team", "Team", PlanPrice{Currency: "BRL", Amount: 49900, StripePriceID: "price_team_brl"})
+	c.AddPlan("business", "Business", PlanPrice{Currency: "USD", Amount: 9900})
+	c.AddPlan("legacy", "Legacy", PlanPrice{Currency: "USD", Amount: 4900, StripePriceID: "price_starter_usd"})
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("expected an error for plans without a default currency price")
+	}
+	for _, want := range []string{`plan "team" has no Stripe price in USD`, `plan "business" has no Stripe price in USD`, `plans "starter" and "legacy" share Stripe price "price_starter_usd"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %q", want, err)
+		}
+	}
+}
+
+func TestCreateSubscription_UnknownPlan(t *testing.T) {
+	stub := newStubStripe()
+	s := NewService(stub, newMemRepository(), multiCurrencyCatalog())
+
+	_, err := s.CreateSubscription(context.Background(), SubscriptionRequest{TenantID: "tenant-1", CustomerID: "cus_123", Plan: "platinum"})
+	if !errors.Is(err, ErrUnknownPlan) {
+		t.Fatalf("expected ErrUnknownPlan, got %v", err)
+	}
+	if stub.newSubscription != nil {
+		t.Error("expected no subscription created")
+	}
+}
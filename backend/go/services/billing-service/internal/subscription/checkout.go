@@ -0,0 +1,204 @@
+package subscription
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// CheckoutRequest starts a Stripe Checkout for a tenant subscribing to a plan.
+type CheckoutRequest struct {
+	TenantID      string
+	CustomerID    string
+	Plan          string
+	SuccessURL    string
+	CancelURL     string
+	Coupon        string // optional Stripe coupon ID
+	PromotionCode string // optional customer-facing promotion code
+	Currency      string // optional; chosen from Country or Locale when empty
+	Country       string // the tenant's ISO country code
+	Locale        string // the tenant's locale, such as pt-BR
+}
+
+// CheckoutSession is a started Stripe Checkout.
+type CheckoutSession struct {
+	ID       string `json:"session_id"`
+	URL      string `json:"url"`
+	Currency string `json:"currency"`
+	Discount string `json:"discount,omitempty"` // the applied code
+}
+
+// SubscriptionRequest subscribes a tenant's customer to a plan directly.
+type SubscriptionRequest struct {
+	TenantID      string
+	CustomerID    string
+	Plan          string
+	Coupon        string
+	PromotionCode string
+	Currency      string
+	Country       string
+	Locale        string
+}
+
+// Subscription is a created Stripe subscription.
+type Subscription struct {
+	ID          string     `json:"subscription_id"`
+	Status      string     `json:"status"`
+	Plan        string     `json:"plan"`
+	Currency    string     `json:"currency"`
+	Discount    string     `json:"discount,omitempty"`
+	TrialEndsAt *time.Time `json:"trial_ends_at,omitempty"`
+}
+
+// CreateCheckout starts a Stripe Checkout subscribing the tenant to a plan,
+// with the coupon or promotion code applied when one is given.
+func (s *Service) CreateCheckout(ctx context.Context, req CheckoutRequest) (*CheckoutSession, error) {
+	if err := requireCustomer(req.TenantID, req.CustomerID); err != nil {
+		return nil, err
+	}
+	if req.SuccessURL == "" || req.CancelURL == "" {
+		return nil, &ValidationError{Message: "success_url and cancel_url are required"}
+	}
+	currency, err := s.currencyFor(ctx, req.TenantID, req.Currency, req.Country, req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	price, err := s.catalog.Price(req.Plan, currency)
+	if err != nil {
+		return nil, err
+	}
+	discount, err := s.ResolveDiscount(ctx, req.Coupon, req.PromotionCode)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &stripe.CheckoutSessionParams{
+		Mode:              stripe.String(string(stripe.CheckoutSessionModeSubscription)),
+		Customer:          stripe.String(req.CustomerID),
+		ClientReferenceID: stripe.String(req.TenantID),
+		SuccessURL:        stripe.String(req.SuccessURL),
+		CancelURL:         stripe.String(req.CancelURL),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{Price: stripe.String(price.StripePriceID), Quantity: stripe.Int64(1)},
+		},
+		// The webhook tracks the trial of the subscription Checkout creates
+		SubscriptionData: &stripe.CheckoutSessionSubscriptionDataParams{
+			Metadata: map[string]string{"tenant_id": req.TenantID, "plan": req.Plan},
+		},
+	}
+	if days := s.trialDays(req.Plan); days > 0 {
+		params.SubscriptionData.TrialPeriodDays = stripe.Int64(int64(days))
+	}
+	if discount != nil {
+		params.Discounts = []*stripe.CheckoutSessionDiscountParams{discountParams(discount)}
+	}
+
+	session, err := s.stripe.NewCheckoutSession(ctx, params)
+	if err != nil {
+		return nil, stripeErr(err)
+	}
+
+	out := &CheckoutSession{ID: session.ID, URL: session.URL, Currency: price.Currency}
+	if discount != nil {
+		out.Discount = discount.Code
+		if err := s.recordRedemption(ctx, req.TenantID, discount, RedemptionCheckout, session.ID); err != nil {
+			log.Printf("Failed to record redemption of %s by tenant %s: %v", discount.Code, req.TenantID, err)
+		}
+	}
+	return out, nil
+}
+
+// CreateSubscription subscribes the tenant's customer to a plan, with the
+// coupon or promotion code applied when one is given. Plans with trial days
+// start in a free trial.
+func (s *Service) CreateSubscription(ctx context.Context, req SubscriptionRequest) (*Subscription, error) {
+	if err := requireCustomer(req.TenantID, req.CustomerID); err != nil {
+		return nil, err
+	}
+	currency, err := s.currencyFor(ctx, req.TenantID, req.Currency, req.Country, req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	price, err := s.catalog.Price(req.Plan, currency)
+	if err != nil {
+		return nil, err
+	}
+	discount, err := s.ResolveDiscount(ctx, req.Coupon, req.PromotionCode)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &stripe.SubscriptionParams{
+		Customer: stripe.String(req.CustomerID),
+		Items: []*stripe.SubscriptionItemsParams{
+			{Price: stripe.String(price.StripePriceID)},
+		},
+	}
+	params.AddMetadata("tenant_id", req.TenantID)
+	params.AddMetadata("plan", req.Plan)
+	days := s.trialDays(req.Plan)
+	if days > 0 {
+		params.TrialPeriodDays = stripe.Int64(int64(days))
+	}
+	if discount != nil {
+		if discount.PromotionCodeID != "" {
+			params.PromotionCode = stripe.String(discount.PromotionCodeID)
+		} else {
+			params.Coupon = stripe.String(discount.CouponID)
+		}
+	}
+
+	sub, err := s.stripe.NewSubscription(ctx, params)
+	if err != nil {
+		return nil, stripeErr(err)
+	}
+
+	if err := s.SyncSubscription(ctx, sub); err != nil {
+		// The webhook or reconciliation stores it later
+		log.Printf("Failed to store subscription %s: %v", sub.ID, err)
+	}
+
+	out := &Subscription{ID: sub.ID, Status: string(sub.Status), Plan: req.Plan, Currency: price.Currency}
+	if sub.Status == stripe.SubscriptionStatusTrialing {
+		trial := &Trial{
+			SubscriptionID: sub.ID,
+			TenantID:       req.TenantID,
+			Plan:           req.Plan,
+			Days:           days,
+			TrialEndsAt:    time.Unix(sub.TrialEnd, 0).UTC(),
+		}
+		out.TrialEndsAt = &trial.TrialEndsAt
+		// Stripe already started the trial; the webhook tracks it on failure
+		if err := s.startTrial(ctx, trial); err != nil {
+			log.Printf("Failed to start trial of subscription %s: %v", sub.ID, err)
+		}
+	}
+	if discount != nil {
+		out.Discount = discount.Code
+		if err := s.recordRedemption(ctx, req.TenantID, discount, RedemptionSubscription, sub.ID); err != nil {
+			log.Printf("Failed to record redemption of %s by tenant %s: %v", discount.Code, req.TenantID, err)
+		}
+	}
+	return out, nil
+}
+
+// discountParams applies d to a checkout session.
+func discountParams(d *Discount) *stripe.CheckoutSessionDiscountParams {
+	if d.PromotionCodeID != "" {
+		return &stripe.CheckoutSessionDiscountParams{PromotionCode: stripe.String(d.PromotionCodeID)}
+	}
+	return &stripe.CheckoutSessionDiscountParams{Coupon: stripe.String(d.CouponID)}
+}
+
+func requireCustomer(tenantID, customerID string) error {
+	if strings.TrimSpace(tenantID) == "" {
+		return &ValidationError{Message: "tenant is required"}
+	}
+	if strings.TrimSpace(customerID) == "" {
+		return &ValidationError{Message: "customer_id is required"}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// Publisher publishes platform events.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, event *types.Event) error
+}
+
+// EventNotifier publishes trials and reconciliations as platform events.
+type EventNotifier struct {
+	publisher Publisher
+	source    string
+}
+
+// NewEventNotifier creates an EventNotifier publishing as source.
+func NewEventNotifier(publisher Publisher, source string) *EventNotifier {
+	return &EventNotifier{publisher: publisher, source: source}
+}
+
+// TrialStarted implements TrialNotifier.
+func (n *EventNotifier) TrialStarted(ctx context.Context, trial Trial) error {
+	return n.publish(ctx, topics.TrialStarted, trial)
+}
+
+// TrialEndingSoon implements TrialNotifier.
+func (n *EventNotifier) TrialEndingSoon(ctx context.Context, trial Trial) error {
+	return n.publish(ctx, topics.TrialEndingSoon, trial)
+}
+
+// Reconciled implements ReconcileNotifier.
+func (n *EventNotifier) Reconciled(ctx context.Context, c Correction) error {
+	event := events.NewEvent(topics.BillingReconciled, n.source, events.BillingReconciledEvent{
+		SubscriptionID: c.SubscriptionID,
+		TenantID:       c.TenantID,
+		Previous:       c.Previous,
+		Changes:        c.Changes,
+		ReconciledAt:   time.Now(),
+	}).WithTenantID(c.TenantID)
+
+	return n.publisher.Publish(ctx, topics.BillingReconciled, event)
+}
+
+func (n *EventNotifier) publish(ctx context.Context, topic string, trial Trial) error {
+	event := events.NewEvent(topic, n.source, events.TrialEventData{
+		SubscriptionID: trial.SubscriptionID,
+		TenantID:       trial.TenantID,
+		Plan:           trial.Plan,
+		TrialDays:      trial.Days,
+		TrialEndsAt:    trial.TrialEndsAt,
+	}).WithTenantID(trial.TenantID)
+
+	return n.publisher.Publish(ctx, topic, event)
+}
@@ -0,0 +1,115 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements Repository on PostgreSQL
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository creates a new GormRepository
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// SaveSubscription upserts a subscription
+func (r *GormRepository) SaveSubscription(ctx context.Context, record *Record) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"tenant_id", "customer_id", "plan", "status", "currency", "seats", "current_period_end", "updated_at"}),
+	}).Create(record).Error
+}
+
+// ListOpenSubscriptions returns the subscriptions that are not canceled
+func (r *GormRepository) ListOpenSubscriptions(ctx context.Context, tenantID string) ([]Record, error) {
+	q := r.db.WithContext(ctx).Where("status <> ?", string(stripe.SubscriptionStatusCanceled))
+	if tenantID != "" {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
+	var records []Record
+	err := q.Find(&records).Error
+	return records, err
+}
+
+// RecordRedemption inserts a redemption
+func (r *GormRepository) RecordRedemption(ctx context.Context, redemption *Redemption) error {
+	return r.db.WithContext(ctx).Create(redemption).Error
+}
+
+// ListRedemptions returns redemptions matching filter, newest first
+func (r *GormRepository) ListRedemptions(ctx context.Context, filter RedemptionFilter) ([]Redemption, error) {
+	q := r.db.WithContext(ctx)
+	if filter.TenantID != "" {
+		q = q.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.Code != "" {
+		q = q.Where("code = ?", filter.Code)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("created_at < ?", filter.To)
+	}
+
+	var redemptions []Redemption
+	err := q.Order("created_at DESC").Find(&redemptions).Error
+	return redemptions, err
+}
+
+// CreateTrial inserts a trial unless the subscription already has one
+func (r *GormRepository) CreateTrial(ctx context.Context, t *Trial) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(t)
+	return result.RowsAffected > 0, result.Error
+}
+
+// TrialsEndingBefore returns active, unreminded trials ending in [from, to)
+func (r *GormRepository) TrialsEndingBefore(ctx context.Context, from, to time.Time) ([]Trial, error) {
+	var trials []Trial
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND reminded_at IS NULL AND trial_ends_at >= ? AND trial_ends_at < ?", TrialActive, from, to).
+		Order("trial_ends_at").Find(&trials).Error
+	return trials, err
+}
+
+// MarkTrialReminded sets reminded_at if it is not set yet
+func (r *GormRepository) MarkTrialReminded(ctx context.Context, subscriptionID string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&Trial{}).
+		Where("subscription_id = ? AND reminded_at IS NULL", subscriptionID).
+		Update("reminded_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ConvertTrial marks an active trial converted
+func (r *GormRepository) ConvertTrial(ctx context.Context, subscriptionID string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&Trial{}).
+		Where("subscription_id = ? AND status = ?", subscriptionID, TrialActive).
+		Updates(map[string]interface{}{"status": TrialConverted, "converted_at": at})
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddSeat inserts a seat unless the user already has one
+func (r *GormRepository) AddSeat(ctx context.Context, seat *Seat) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(seat)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemoveSeat deletes the seat of a user
+func (r *GormRepository) RemoveSeat(ctx context.Context, tenantID, userID string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("tenant_id = ? AND user_id = ?", tenantID, userID).Delete(&Seat{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountSeats counts the seats of a tenant
+func (r *GormRepository) CountSeats(ctx context.Context, tenantID string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&Seat{}).Where("tenant_id = ?", tenantID).Count(&n).Error
+	return n, err
+}
@@ -0,0 +1,100 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// LineItem is a line of a previewed invoice.
+type LineItem struct {
+	Description string    `json:"description"`
+	Amount      int64     `json:"amount"` // in the currency's smallest unit; negative for credits
+	Proration   bool      `json:"proration"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+// Preview is the cost impact of a plan change that was not applied.
+type Preview struct {
+	SubscriptionID string     `json:"subscription_id"`
+	Plan           string     `json:"plan"`
+	Currency       string     `json:"currency"`
+	ProrationDate  time.Time  `json:"proration_date"`
+	Lines          []LineItem `json:"lines"`
+	// Net is the sum of the proration lines: positive is charged, negative
+	// credited on the next invoice.
+	Net       int64 `json:"net"`
+	Total     int64 `json:"total"` // next invoice total, including the plan change
+	AmountDue int64 `json:"amount_due"`
+}
+
+// PreviewChange asks Stripe for the upcoming invoice of subscriptionID as if
+// it switched to plan now, with prorations, without changing it. The new
+// plan is priced in the subscription's currency.
+func (s *Service) PreviewChange(ctx context.Context, subscriptionID, plan string) (*Preview, error) {
+	sub, err := s.stripe.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, stripeErr(err)
+	}
+	currency := string(sub.Currency)
+	if currency == "" {
+		currency = s.catalog.DefaultCurrency()
+	}
+	price, err := s.catalog.Price(plan, currency)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Items == nil || len(sub.Items.Data) == 0 {
+		return nil, &ValidationError{Message: "subscription has no items to change"}
+	}
+	if sub.Customer == nil {
+		return nil, fmt.Errorf("subscription %s has no customer", sub.ID)
+	}
+
+	// The same proration date must be used when the change is applied, for
+	// the amounts to match the preview
+	prorationDate := s.now().UTC().Truncate(time.Second)
+	inv, err := s.stripe.UpcomingInvoice(ctx, &stripe.InvoiceUpcomingParams{
+		Customer:     stripe.String(sub.Customer.ID),
+		Subscription: stripe.String(sub.ID),
+		SubscriptionItems: []*stripe.SubscriptionItemsParams{
+			{ID: stripe.String(sub.Items.Data[0].ID), Price: stripe.String(price.StripePriceID)},
+		},
+		SubscriptionProrationBehavior: stripe.String("create_prorations"),
+		SubscriptionProrationDate:     stripe.Int64(prorationDate.Unix()),
+	})
+	if err != nil {
+		return nil, stripeErr(err)
+	}
+
+	preview := &Preview{
+		SubscriptionID: sub.ID,
+		Plan:           plan,
+		Currency:       string(inv.Currency),
+		ProrationDate:  prorationDate,
+		Lines:          []LineItem{},
+		Total:          inv.Total,
+		AmountDue:      inv.AmountDue,
+	}
+	if inv.Lines != nil {
+		for _, line := range inv.Lines.Data {
+			item := LineItem{
+				Description: line.Description,
+				Amount:      line.Amount,
+				Proration:   line.Proration,
+			}
+			if line.Period != nil {
+				item.PeriodStart = time.Unix(line.Period.Start, 0).UTC()
+				item.PeriodEnd = time.Unix(line.Period.End, 0).UTC()
+			}
+			if line.Proration {
+				preview.Net += line.Amount
+			}
+			preview.Lines = append(preview.Lines, item)
+		}
+	}
+	return preview, nil
+}
@@ -0,0 +1,78 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPreviewChange(t *testing.T) {
+	stub := newStubStripe()
+	s := NewService(stub, newMemRepository(), testCatalog(map[string]string{"starter": "price_starter", "pro": "price_pro"}))
+	now := time.Date(2023, 11, 20, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	preview, err := s.PreviewChange(context.Background(), "sub_123", "pro")
+	if err != nil {
+		t.Fatalf("PreviewChange failed: %v", err)
+	}
+
+	if preview.Net != 7500 {
+		t.Errorf("expected a net charge of 7500, got %d", preview.Net)
+	}
+	if preview.Total != 29100 || preview.Currency != "brl" || len(preview.Lines) != 3 {
+		t.Errorf("unexpected preview: %+v", preview)
+	}
+	if !preview.ProrationDate.Equal(now) {
+		t.Errorf("expected proration date %v, got %v", now, preview.ProrationDate)
+	}
+
+	params := stub.upcoming
+	if params == nil {
+		t.Fatal("upcoming invoice was not requested")
+	}
+	if *params.Customer != "cus_123" || *params.Subscription != "sub_123" {
+		t.Errorf("expected the subscription's upcoming invoice, got customer %s subscription %s", *params.Customer, *params.Subscription)
+	}
+	if len(params.SubscriptionItems) != 1 || *params.SubscriptionItems[0].ID != "si_123" || *params.SubscriptionItems[0].Price != "price_pro" {
+		t.Errorf("expected the item switched to price_pro, got %+v", params.SubscriptionItems)
+	}
+	if *params.SubscriptionProrationDate != now.Unix() {
+		t.Errorf("expected proration date %d, got %d", now.Unix(), *params.SubscriptionProrationDate)
+	}
+}
+
+func TestPreviewChange_Credit(t *testing.T) {
+	stub := newStubStripe()
+	stub.invoice.Lines.Data[1].Amount = 1200
+	s := NewService(stub, newMemRepository(), testCatalog(map[string]string{"free": "price_free"}))
+
+	preview, err := s.PreviewChange(context.Background(), "sub_123", "free")
+	if err != nil {
+		t.Fatalf("PreviewChange failed: %v", err)
+	}
+	if preview.Net != -1250 {
+		t.Errorf("expected a net credit of -1250, got %d", preview.Net)
+	}
+}
+
+func TestPreviewChange_UnknownPlan(t *testing.T) {
+	stub := newStubStripe()
+	s := NewService(stub, newMemRepository(), testCatalog(map[string]string{"pro": "price_pro"}))
+
+	if _, err := s.PreviewChange(context.Background(), "sub_123", "platinum"); !errors.Is(err, ErrUnknownPlan) {
+		t.Fatalf("expected ErrUnknownPlan, got %v", err)
+	}
+	if stub.upcoming != nil {
+		t.Error("expected no upcoming invoice request")
+	}
+}
+
+func TestPreviewChange_SubscriptionNotFound(t *testing.T) {
+	s := NewService(newStubStripe(), newMemRepository(), testCatalog(map[string]string{"pro": "price_pro"}))
+
+	if _, err := s.PreviewChange(context.Background(), "sub_missing", "pro"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// ErrInvalidPromoCode is returned for a coupon or promotion code that does
+// not exist, expired or can't be redeemed anymore.
+var ErrInvalidPromoCode = errors.New("invalid promo code")
+
+// Redemption records a tenant applying a coupon or promotion code.
+type Redemption struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	TenantID        string    `gorm:"not null;index" json:"tenant_id"`
+	Code            string    `gorm:"not null;index" json:"code"` // as the tenant entered it
+	CouponID        string    `gorm:"not null" json:"coupon_id"`
+	PromotionCodeID string    `gorm:"not null;default:''" json:"promotion_code_id,omitempty"`
+	Source          string    `gorm:"not null" json:"source"`    // RedemptionCheckout or RedemptionSubscription
+	SourceID        string    `gorm:"not null" json:"source_id"` // checkout session or subscription ID
+	CreatedAt       time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name
+func (Redemption) TableName() string {
+	return "promo_redemptions"
+}
+
+// Where a code was applied.
+const (
+	RedemptionCheckout     = "checkout_session"
+	RedemptionSubscription = "subscription"
+)
+
+// RedemptionFilter narrows a redemption listing. Zero fields match all.
+type RedemptionFilter struct {
+	TenantID string
+	Code     string
+	From     time.Time
+	To       time.Time
+}
+
+// Discount is a validated coupon or promotion code, ready to apply.
+type Discount struct {
+	Code            string
+	CouponID        string
+	PromotionCodeID string // empty when a coupon was given directly
+}
+
+// ResolveDiscount validates a coupon ID or a customer-facing promotion code
+// against Stripe. At most one may be given; it returns nil when neither is.
+func (s *Service) ResolveDiscount(ctx context.Context, coupon, promotionCode string) (*Discount, error) {
+	coupon, promotionCode = strings.TrimSpace(coupon), strings.TrimSpace(promotionCode)
+	switch {
+	case coupon != "" && promotionCode != "":
+		return nil, &ValidationError{Message: "coupon and promotion_code can't both be given"}
+	case coupon != "":
+		c, err := s.stripe.GetCoupon(ctx, coupon)
+		if err != nil {
+			if err = stripeErr(err); errors.Is(err, ErrNotFound) {
+				return nil, fmt.Errorf("%w: coupon %q does not exist", ErrInvalidPromoCode, coupon)
+			}
+			return nil, err
+		}
+		if !c.Valid {
+			return nil, fmt.Errorf("%w: coupon %q has expired", ErrInvalidPromoCode, coupon)
+		}
+		return &Discount{Code: coupon, CouponID: c.ID}, nil
+	case promotionCode != "":
+		pc, err := s.stripe.FindPromotionCode(ctx, promotionCode)
+		if err != nil {
+			return nil, stripeErr(err)
+		}
+		if err := s.checkPromotionCode(pc, promotionCode); err != nil {
+			return nil, err
+		}
+		return &Discount{Code: promotionCode, CouponID: pc.Coupon.ID, PromotionCodeID: pc.ID}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// checkPromotionCode reports why a promotion code can't be redeemed, if so.
+func (s *Service) checkPromotionCode(pc *stripe.PromotionCode, code string) error {
+	switch {
+	case pc == nil:
+		return fmt.Errorf("%w: promotion code %q does not exist", ErrInvalidPromoCode, code)
+	case !pc.Active, pc.ExpiresAt > 0 && !time.Unix(pc.ExpiresAt, 0).After(s.now()):
+		return fmt.Errorf("%w: promotion code %q has expired", ErrInvalidPromoCode, code)
+	case pc.MaxRedemptions > 0 && pc.TimesRedeemed >= pc.MaxRedemptions:
+		return fmt.Errorf("%w: promotion code %q was fully redeemed", ErrInvalidPromoCode, code)
+	case pc.Coupon == nil || !pc.Coupon.Valid:
+		return fmt.Errorf("%w: promotion code %q has expired", ErrInvalidPromoCode, code)
+	}
+	return nil
+}
+
+// Redemptions returns promo code redemptions matching filter, for reporting.
+func (s *Service) Redemptions(ctx context.Context, filter RedemptionFilter) ([]Redemption, error) {
+	return s.repo.ListRedemptions(ctx, filter)
+}
+
+// recordRedemption stores that tenantID applied d. The discount was already
+// applied in Stripe, so a failure here is only logged by the caller.
+func (s *Service) recordRedemption(ctx context.Context, tenantID string, d *Discount, source, sourceID string) error {
+	return s.repo.RecordRedemption(context.WithoutCancel(ctx), &Redemption{
+		TenantID:        tenantID,
+		Code:            d.Code,
+		CouponID:        d.CouponID,
+		PromotionCodeID: d.PromotionCodeID,
+		Source:          source,
+		SourceID:        sourceID,
+		CreatedAt:       s.now().UTC(),
+	})
+}
@@ -0,0 +1,155 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+func newPromoService() (*Service, *stubStripe, *memRepository) {
+	stub := newStubStripe()
+	stub.coupons["LAUNCH20"] = &stripe.Coupon{ID: "LAUNCH20", PercentOff: 20, Valid: true}
+	stub.coupons["OLD10"] = &stripe.Coupon{ID: "OLD10", PercentOff: 10, Valid: false}
+	stub.promotionCodes["WELCOME"] = &stripe.PromotionCode{
+		ID:     "promo_welcome",
+		Code:   "WELCOME",
+		Active: true,
+		Coupon: &stripe.Coupon{ID: "LAUNCH20", Valid: true},
+	}
+	stub.promotionCodes["SUMMER"] = &stripe.PromotionCode{
+		ID:        "promo_summer",
+		Code:      "SUMMER",
+		Active:    true,
+		ExpiresAt: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Coupon:    &stripe.Coupon{ID: "LAUNCH20", Valid: true},
+	}
+	stub.promotionCodes["VIP"] = &stripe.PromotionCode{
+		ID:             "promo_vip",
+		Code:           "VIP",
+		Active:         true,
+		MaxRedemptions: 5,
+		TimesRedeemed:  5,
+		Coupon:         &stripe.Coupon{ID: "LAUNCH20", Valid: true},
+	}
+
+	repo := newMemRepository()
+	s := NewService(stub, repo, testCatalog(map[string]string{"pro": "price_pro"}))
+	s.now = func() time.Time { return time.Date(2023, 11, 20, 12, 0, 0, 0, time.UTC) }
+	return s, stub, repo
+}
+
+func TestCreateCheckout_WithPromotionCode(t *testing.T) {
+	s, stub, repo := newPromoService()
+
+	session, err := s.CreateCheckout(context.Background(), CheckoutRequest{
+		TenantID:      "tenant-1",
+		CustomerID:    "cus_123",
+		Plan:          "pro",
+		SuccessURL:    "https://app.example.com/billing/success",
+		CancelURL:     "https://app.example.com/billing",
+		PromotionCode: "WELCOME",
+	})
+	if err != nil {
+		t.Fatalf("CreateCheckout failed: %v", err)
+	}
+	if session.ID != "cs_123" || session.Discount != "WELCOME" {
+		t.Errorf("unexpected session: %+v", session)
+	}
+
+	params := stub.checkout
+	if len(params.Discounts) != 1 || params.Discounts[0].PromotionCode == nil || *params.Discounts[0].PromotionCode != "promo_welcome" {
+		t.Fatalf("expected the promotion code applied to the session, got %+v", params.Discounts)
+	}
+	if *params.LineItems[0].Price != "price_pro" || *params.ClientReferenceID != "tenant-1" {
+		t.Errorf("unexpected session params: %+v", params)
+	}
+
+	redemptions, _ := repo.ListRedemptions(context.Background(), RedemptionFilter{TenantID: "tenant-1"})
+	if len(redemptions) != 1 {
+		t.Fatalf("expected 1 redemption, got %d", len(redemptions))
+	}
+	r := redemptions[0]
+	if r.Code != "WELCOME" || r.CouponID != "LAUNCH20" || r.PromotionCodeID != "promo_welcome" ||
+		r.Source != RedemptionCheckout || r.SourceID != "cs_123" {
+		t.Errorf("unexpected redemption: %+v", r)
+	}
+}
+
+func TestCreateSubscription_WithCoupon(t *testing.T) {
+	s, stub, repo := newPromoService()
+
+	sub, err := s.CreateSubscription(context.Background(), SubscriptionRequest{
+		TenantID:   "tenant-1",
+		CustomerID: "cus_123",
+		Plan:       "pro",
+		Coupon:     "LAUNCH20",
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	if sub.ID != "sub_new" || sub.Discount != "LAUNCH20" {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+	if params := stub.newSubscription; params.Coupon == nil || *params.Coupon != "LAUNCH20" || params.PromotionCode != nil {
+		t.Errorf("expected the coupon applied to the subscription, got %+v", params)
+	}
+
+	redemptions, _ := repo.ListRedemptions(context.Background(), RedemptionFilter{Code: "LAUNCH20"})
+	if len(redemptions) != 1 || redemptions[0].Source != RedemptionSubscription || redemptions[0].SourceID != "sub_new" {
+		t.Errorf("unexpected redemptions: %+v", redemptions)
+	}
+}
+
+func TestCreateCheckout_InvalidCodes(t *testing.T) {
+	cases := map[string]CheckoutRequest{
+		"unknown coupon":       {Coupon: "NOPE"},
+		"expired coupon":       {Coupon: "OLD10"},
+		"unknown promotion":    {PromotionCode: "NOPE"},
+		"expired promotion":    {PromotionCode: "SUMMER"},
+		"fully redeemed promo": {PromotionCode: "VIP"},
+	}
+	for name, req := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, stub, repo := newPromoService()
+			req.TenantID, req.CustomerID, req.Plan = "tenant-1", "cus_123", "pro"
+			req.SuccessURL, req.CancelURL = "https://app.example.com/ok", "https://app.example.com/cancel"
+
+			if _, err := s.CreateCheckout(context.Background(), req); !errors.Is(err, ErrInvalidPromoCode) {
+				t.Fatalf("expected ErrInvalidPromoCode, got %v", err)
+			}
+			if stub.checkout != nil {
+				t.Error("expected no checkout session")
+			}
+			if redemptions, _ := repo.ListRedemptions(context.Background(), RedemptionFilter{}); len(redemptions) != 0 {
+				t.Errorf("expected no redemption, got %+v", redemptions)
+			}
+		})
+	}
+}
+
+func TestResolveDiscount_RejectsBothCodes(t *testing.T) {
+	s, _, _ := newPromoService()
+
+	var validationErr *ValidationError
+	if _, err := s.ResolveDiscount(context.Background(), "LAUNCH20", "WELCOME"); !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestCreateSubscription_WithoutCode(t *testing.T) {
+	s, stub, repo := newPromoService()
+
+	sub, err := s.CreateSubscription(context.Background(), SubscriptionRequest{TenantID: "tenant-1", CustomerID: "cus_123", Plan: "pro"})
+	if err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	if sub.Discount != "" || stub.newSubscription.Coupon != nil || stub.newSubscription.PromotionCode != nil {
+		t.Errorf("expected no discount, got %+v", sub)
+	}
+	if redemptions, _ := repo.ListRedemptions(context.Background(), RedemptionFilter{}); len(redemptions) != 0 {
+		t.Errorf("expected no redemption, got %+v", redemptions)
+	}
+}
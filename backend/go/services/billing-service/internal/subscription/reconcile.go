@@ -0,0 +1,163 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// Correction is a local subscription fixed to match Stripe. Previous and
+// Changes hold the old and new values of the fields that drifted; Previous
+// is empty for a subscription missing locally.
+type Correction struct {
+	SubscriptionID string            `json:"subscription_id"`
+	TenantID       string            `json:"tenant_id"`
+	Previous       map[string]string `json:"previous"`
+	Changes        map[string]string `json:"changes"`
+}
+
+// ReconcileNotifier announces corrections.
+type ReconcileNotifier interface {
+	Reconciled(ctx context.Context, c Correction) error
+}
+
+// EnableReconciliation sends corrections to notifier, which may be nil.
+func (s *Service) EnableReconciliation(notifier ReconcileNotifier) {
+	s.reconcileNotifier = notifier
+}
+
+// Reconcile compares the subscriptions Stripe lists with the local ones and
+// corrects the status, plan, seats and period end of those that drifted,
+// such as after a missed webhook. Local subscriptions Stripe no longer lists are
+// looked up one by one, since the listing leaves canceled ones out.
+func (s *Service) Reconcile(ctx context.Context) ([]Correction, error) {
+	local, err := s.repo.ListOpenSubscriptions(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Record, len(local))
+	for i := range local {
+		byID[local[i].ID] = &local[i]
+	}
+
+	var corrections []Correction
+	var correctErr error
+	err = s.stripe.ListSubscriptions(ctx, func(sub *stripe.Subscription) error {
+		existing := byID[sub.ID]
+		delete(byID, sub.ID)
+		c, err := s.correct(ctx, existing, s.recordOf(sub))
+		if c != nil {
+			corrections = append(corrections, *c)
+		}
+		correctErr = err
+		return err
+	})
+	if correctErr != nil {
+		return corrections, correctErr
+	}
+	if err != nil {
+		return corrections, stripeErr(err)
+	}
+
+	for _, existing := range byID {
+		remote := *existing
+		sub, err := s.stripe.GetSubscription(ctx, existing.ID)
+		if err != nil {
+			if err = stripeErr(err); !errors.Is(err, ErrNotFound) {
+				return corrections, err
+			}
+			remote.Status = string(stripe.SubscriptionStatusCanceled)
+			remote.UpdatedAt = s.now()
+		} else {
+			remote = *s.recordOf(sub)
+			if remote.TenantID == "" {
+				remote.TenantID = existing.TenantID
+			}
+		}
+		c, err := s.correct(ctx, existing, &remote)
+		if c != nil {
+			corrections = append(corrections, *c)
+		}
+		if err != nil {
+			return corrections, err
+		}
+	}
+	return corrections, nil
+}
+
+// correct stores remote when it differs from local, and announces it.
+func (s *Service) correct(ctx context.Context, local, remote *Record) (*Correction, error) {
+	if remote.TenantID == "" {
+		return nil, nil
+	}
+	var old Record
+	if local != nil {
+		old = *local
+		remote.CreatedAt = local.CreatedAt
+	}
+	previous, changes := map[string]string{}, map[string]string{}
+	diff := func(field, from, to string) {
+		if from != to {
+			previous[field], changes[field] = from, to
+		}
+	}
+	diff("status", old.Status, remote.Status)
+	diff("plan", old.Plan, remote.Plan)
+	diff("current_period_end", formatTime(old.CurrentPeriodEnd), formatTime(remote.CurrentPeriodEnd))
+	diff("seats", strconv.FormatInt(old.Seats, 10), strconv.FormatInt(remote.Seats, 10))
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if local == nil {
+		previous = map[string]string{}
+	}
+
+	if err := s.repo.SaveSubscription(ctx, remote); err != nil {
+		return nil, fmt.Errorf("failed to correct subscription %s: %w", remote.ID, err)
+	}
+	if changes["plan"] != "" || changes["status"] != "" {
+		if err := s.syncPlan(ctx, remote); err != nil {
+			return nil, err
+		}
+	}
+	c := &Correction{SubscriptionID: remote.ID, TenantID: remote.TenantID, Previous: previous, Changes: changes}
+	if s.reconcileNotifier != nil {
+		if err := s.reconcileNotifier.Reconciled(ctx, *c); err != nil {
+			log.Printf("Failed to announce reconciliation of subscription %s: %v", remote.ID, err)
+		}
+	}
+	return c, nil
+}
+
+// RunReconciliation reconciles every interval until ctx is done.
+func (s *Service) RunReconciliation(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			corrections, err := s.Reconcile(ctx)
+			if err != nil {
+				log.Printf("Billing reconciliation failed: %v", err)
+			}
+			if len(corrections) > 0 {
+				log.Printf("Billing reconciliation corrected %d subscriptions", len(corrections))
+			}
+		}
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
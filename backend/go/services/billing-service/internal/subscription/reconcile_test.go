@@ -0,0 +1,90 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/platformeventstest"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/stripe/stripe-go/v76"
+)
+
+func stripeSubscription(id, tenantID, price string, status stripe.SubscriptionStatus, periodEnd time.Time) *stripe.Subscription {
+	return &stripe.Subscription{
+		ID:               id,
+		Status:           status,
+		Customer:         &stripe.Customer{ID: "cus_" + tenantID},
+		CurrentPeriodEnd: periodEnd.Unix(),
+		Metadata:         map[string]string{"tenant_id": tenantID},
+		Items: &stripe.SubscriptionItemList{Data: []*stripe.SubscriptionItem{
+			{Price: &stripe.Price{ID: price}},
+		}},
+	}
+}
+
+func TestReconcile_CorrectsStaleState(t *testing.T) {
+	periodEnd := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	stub := newStubStripe()
+	stub.listed = []*stripe.Subscription{
+		stripeSubscription("sub_upgraded", "tenant-1", "price_pro", stripe.SubscriptionStatusActive, periodEnd),
+		stripeSubscription("sub_past_due", "tenant-2", "price_starter", stripe.SubscriptionStatusPastDue, periodEnd),
+		stripeSubscription("sub_in_sync", "tenant-3", "price_starter", stripe.SubscriptionStatusActive, periodEnd),
+		stripeSubscription("sub_missed", "tenant-4", "price_pro", stripe.SubscriptionStatusActive, periodEnd),
+		stripeSubscription("sub_canceled", "tenant-5", "price_pro", stripe.SubscriptionStatusCanceled, periodEnd),
+	}
+	repo := newMemRepository()
+	for _, r := range []Record{
+		// Missed an upgrade and a renewal
+		{ID: "sub_upgraded", TenantID: "tenant-1", Plan: "starter", Status: "active", CurrentPeriodEnd: periodEnd.AddDate(0, -1, 0)},
+		// Missed the failed payment
+		{ID: "sub_past_due", TenantID: "tenant-2", Plan: "starter", Status: "active", CurrentPeriodEnd: periodEnd},
+		{ID: "sub_in_sync", TenantID: "tenant-3", Plan: "starter", Status: "active", CurrentPeriodEnd: periodEnd},
+		// Missed the cancellation
+		{ID: "sub_canceled", TenantID: "tenant-5", Plan: "pro", Status: "active", CurrentPeriodEnd: periodEnd},
+	} {
+		repo.subscriptions[r.ID] = r
+	}
+	publisher := platformeventstest.NewFakePublisher()
+	s := NewService(stub, repo, testCatalog(map[string]string{"starter": "price_starter", "pro": "price_pro"}))
+	s.EnableReconciliation(NewEventNotifier(publisher, "billing-service"))
+
+	corrections, err := s.Reconcile(context.Background())
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(corrections) != 4 {
+		t.Fatalf("expected 4 corrections, got %d: %+v", len(corrections), corrections)
+	}
+
+	for id, want := range map[string]Record{
+		"sub_upgraded": {Plan: "pro", Status: "active", CurrentPeriodEnd: periodEnd},
+		"sub_past_due": {Plan: "starter", Status: "past_due", CurrentPeriodEnd: periodEnd},
+		"sub_in_sync":  {Plan: "starter", Status: "active", CurrentPeriodEnd: periodEnd},
+		"sub_missed":   {Plan: "pro", Status: "active", CurrentPeriodEnd: periodEnd},
+		"sub_canceled": {Plan: "pro", Status: "canceled", CurrentPeriodEnd: periodEnd},
+	} {
+		got := repo.subscriptions[id]
+		if got.Plan != want.Plan || got.Status != want.Status || !got.CurrentPeriodEnd.Equal(want.CurrentPeriodEnd) {
+			t.Errorf("%s: expected %s/%s/%s, got %s/%s/%s", id,
+				want.Plan, want.Status, want.CurrentPeriodEnd, got.Plan, got.Status, got.CurrentPeriodEnd)
+		}
+	}
+
+	publisher.AssertPublishedCount(t, topics.BillingReconciled, 4)
+	publisher.AssertPublished(t, topics.BillingReconciled, map[string]any{
+		"subscription_id": "sub_upgraded",
+		"tenant_id":       "tenant-1",
+		"previous":        map[string]string{"plan": "starter", "current_period_end": "2026-03-01T00:00:00Z"},
+		"changes":         map[string]string{"plan": "pro", "current_period_end": "2026-04-01T00:00:00Z"},
+	})
+	publisher.AssertPublished(t, topics.BillingReconciled, map[string]any{
+		"subscription_id": "sub_canceled",
+		"changes":         map[string]string{"status": "canceled"},
+	})
+
+	// Once corrected, nothing drifts
+	if corrections, _ := s.Reconcile(context.Background()); len(corrections) != 0 {
+		t.Errorf("expected no corrections on the second run, got %+v", corrections)
+	}
+}
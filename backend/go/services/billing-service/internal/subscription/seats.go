@@ -0,0 +1,184 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/codec"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+	"github.com/stripe/stripe-go/v76"
+)
+
+// Seat is an active user of a tenant, counted for per-seat plans.
+type Seat struct {
+	TenantID  string    `gorm:"primaryKey" json:"tenant_id"`
+	UserID    string    `gorm:"primaryKey" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (Seat) TableName() string {
+	return "subscription_seats"
+}
+
+// SeatConfig configures per-seat billing.
+type SeatConfig struct {
+	Plans []string // plan IDs billed by active user
+	// MaxUsers returns the most users a plan allows; negative is no limit.
+	// Seats past it are not billed.
+	MaxUsers func(plan string) int
+}
+
+// SeatUsage compares a tenant's active users with the seats it is billed for.
+type SeatUsage struct {
+	TenantID       string `json:"tenant_id"`
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	Plan           string `json:"plan,omitempty"`
+	CurrentSeats   int64  `json:"current_seats"`
+	BilledSeats    int64  `json:"billed_seats"`
+	MaxUsers       int    `json:"max_users"` // -1 when unlimited
+}
+
+// EnableSeats bills the subscriptions of cfg's plans by active user.
+func (s *Service) EnableSeats(cfg SeatConfig) {
+	s.seatPlans = make(map[string]bool, len(cfg.Plans))
+	for _, plan := range cfg.Plans {
+		s.seatPlans[plan] = true
+	}
+	s.maxUsers = cfg.MaxUsers
+}
+
+// UpdateSeats sets the quantity of a subscription's item, prorating the
+// difference for the rest of the period, and stores the result.
+func (s *Service) UpdateSeats(ctx context.Context, subscriptionID string, quantity int64) (*Record, error) {
+	if quantity < 1 {
+		return nil, &ValidationError{Message: "quantity must be at least 1"}
+	}
+
+	sub, err := s.stripe.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, stripeErr(err)
+	}
+	if sub.Items == nil || len(sub.Items.Data) == 0 {
+		return nil, fmt.Errorf("subscription %s has no items", subscriptionID)
+	}
+
+	item := sub.Items.Data[0]
+	if item.Quantity != quantity {
+		if _, err := s.stripe.UpdateSubscriptionItem(ctx, item.ID, &stripe.SubscriptionItemParams{
+			Quantity:          stripe.Int64(quantity),
+			ProrationBehavior: stripe.String("create_prorations"),
+		}); err != nil {
+			return nil, stripeErr(err)
+		}
+		item.Quantity = quantity
+	}
+
+	record := s.recordOf(sub)
+	if record.TenantID != "" {
+		if err := s.repo.SaveSubscription(ctx, record); err != nil {
+			return nil, fmt.Errorf("failed to store subscription %s: %w", sub.ID, err)
+		}
+	}
+	return record, nil
+}
+
+// SeatEventTypes returns the user events that change seats, to subscribe
+// HandleUserEvent to.
+func (s *Service) SeatEventTypes() []string {
+	return []string{topics.UserCreated, topics.UserDeleted}
+}
+
+// HandleUserEvent counts a user added or removed by auth-gateway and brings
+// the tenant's per-seat subscription in line. Redeliveries change nothing.
+func (s *Service) HandleUserEvent(event *types.Event) error {
+	var data struct {
+		UserID   string `json:"user_id"`
+		TenantID string `json:"tenant_id"`
+	}
+	if err := codec.DecodeData(event, &data); err != nil {
+		// Retrying would not help; skip the event
+		log.Printf("Skipping user event %s: %v", event.ID, err)
+		return nil
+	}
+	tenantID := data.TenantID
+	if tenantID == "" {
+		tenantID = event.TenantID
+	}
+	if tenantID == "" || data.UserID == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	var changed bool
+	var err error
+	switch event.Type {
+	case topics.UserCreated:
+		changed, err = s.repo.AddSeat(ctx, &Seat{TenantID: tenantID, UserID: data.UserID, CreatedAt: s.now()})
+	case topics.UserDeleted:
+		changed, err = s.repo.RemoveSeat(ctx, tenantID, data.UserID)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update seats of tenant %s: %w", tenantID, err)
+	}
+	if !changed {
+		return nil
+	}
+	return s.SyncSeats(ctx, tenantID)
+}
+
+// SyncSeats sets the quantity of the tenant's per-seat subscription to its
+// active users, up to its plan's MaxUsers and at least one.
+func (s *Service) SyncSeats(ctx context.Context, tenantID string) error {
+	usage, err := s.Seats(ctx, tenantID)
+	if err != nil || usage.SubscriptionID == "" {
+		return err
+	}
+
+	quantity := max(usage.CurrentSeats, 1)
+	if usage.MaxUsers >= 0 && quantity > int64(usage.MaxUsers) {
+		log.Printf("Tenant %s has %d users, over the %d of plan %s; billing %d", tenantID, usage.CurrentSeats, usage.MaxUsers, usage.Plan, usage.MaxUsers)
+		quantity = int64(max(usage.MaxUsers, 1))
+	}
+	if quantity == usage.BilledSeats {
+		return nil
+	}
+	_, err = s.UpdateSeats(ctx, usage.SubscriptionID, quantity)
+	return err
+}
+
+// Seats returns the tenant's active users and the seats its per-seat
+// subscription bills. SubscriptionID is empty when it has none.
+func (s *Service) Seats(ctx context.Context, tenantID string) (*SeatUsage, error) {
+	current, err := s.repo.CountSeats(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count seats: %w", err)
+	}
+	usage := &SeatUsage{TenantID: tenantID, CurrentSeats: current, MaxUsers: -1}
+
+	open, err := s.repo.ListOpenSubscriptions(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	for _, r := range open {
+		switch stripe.SubscriptionStatus(r.Status) {
+		case stripe.SubscriptionStatusActive, stripe.SubscriptionStatusTrialing:
+		default:
+			continue
+		}
+		if !s.seatPlans[r.Plan] {
+			continue
+		}
+		usage.SubscriptionID, usage.Plan, usage.BilledSeats = r.ID, r.Plan, r.Seats
+		if s.maxUsers != nil {
+			usage.MaxUsers = s.maxUsers(r.Plan)
+		}
+		break
+	}
+	return usage, nil
+}
@@ -0,0 +1,128 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/stripe/stripe-go/v76"
+)
+
+// newSeatService returns a Service billing "pro" per seat, up to 3 users,
+// with tenant-1 on an active pro subscription billed for quantity seats.
+func newSeatService(quantity int64) (*Service, *stubStripe, *memRepository) {
+	stub := newStubStripe()
+	stub.subscription = &stripe.Subscription{
+		ID:       "sub_123",
+		Status:   stripe.SubscriptionStatusActive,
+		Customer: &stripe.Customer{ID: "cus_123"},
+		Metadata: map[string]string{"tenant_id": "tenant-1"},
+		Items: &stripe.SubscriptionItemList{Data: []*stripe.SubscriptionItem{
+			{ID: "si_123", Price: &stripe.Price{ID: "price_pro"}, Quantity: quantity},
+		}},
+	}
+	repo := newMemRepository()
+	repo.subscriptions["sub_123"] = Record{ID: "sub_123", TenantID: "tenant-1", Plan: "pro", Status: "active", Seats: quantity}
+
+	s := NewService(stub, repo, testCatalog(map[string]string{"pro": "price_pro", "starter": "price_starter"}))
+	s.EnableSeats(SeatConfig{
+		Plans:    []string{"pro"},
+		MaxUsers: func(plan string) int { return 3 },
+	})
+	return s, stub, repo
+}
+
+func TestUpdateSeats_ProratesIncrementAndDecrement(t *testing.T) {
+	s, stub, repo := newSeatService(2)
+	ctx := context.Background()
+
+	for _, quantity := range []int64{5, 1} {
+		record, err := s.UpdateSeats(ctx, "sub_123", quantity)
+		if err != nil {
+			t.Fatalf("UpdateSeats(%d) failed: %v", quantity, err)
+		}
+		last := stub.itemUpdates[len(stub.itemUpdates)-1]
+		if *last.Quantity != quantity || *last.ProrationBehavior != "create_prorations" {
+			t.Errorf("expected quantity %d with prorations, got %d and %s", quantity, *last.Quantity, *last.ProrationBehavior)
+		}
+		if record.Seats != quantity || repo.subscriptions["sub_123"].Seats != quantity {
+			t.Errorf("expected %d billed seats stored, got %d", quantity, repo.subscriptions["sub_123"].Seats)
+		}
+	}
+
+	// The same quantity is not sent again
+	if _, err := s.UpdateSeats(ctx, "sub_123", 1); err != nil {
+		t.Fatalf("UpdateSeats failed: %v", err)
+	}
+	if len(stub.itemUpdates) != 2 {
+		t.Errorf("expected 2 item updates, got %d", len(stub.itemUpdates))
+	}
+
+	var validationErr *ValidationError
+	if _, err := s.UpdateSeats(ctx, "sub_123", 0); !errors.As(err, &validationErr) {
+		t.Errorf("expected ValidationError for 0 seats, got %v", err)
+	}
+}
+
+func TestHandleUserEvent_FollowsUsersUpToMaxUsers(t *testing.T) {
+	s, stub, repo := newSeatService(1)
+
+	handle := func(eventType, userID string) {
+		t.Helper()
+		event := events.NewEvent(eventType, "auth-gateway", events.UserCreatedEvent{UserID: userID, TenantID: "tenant-1"})
+		if err := s.HandleUserEvent(event); err != nil {
+			t.Fatalf("HandleUserEvent failed: %v", err)
+		}
+	}
+
+	handle(topics.UserCreated, "user-1")
+	handle(topics.UserCreated, "user-2")
+	handle(topics.UserCreated, "user-2") // redelivered
+	if got := repo.subscriptions["sub_123"].Seats; got != 2 {
+		t.Fatalf("expected 2 billed seats, got %d", got)
+	}
+
+	// Users past the plan's MaxUsers are not billed
+	for i := 3; i <= 5; i++ {
+		handle(topics.UserCreated, fmt.Sprintf("user-%d", i))
+	}
+	usage, err := s.Seats(context.Background(), "tenant-1")
+	if err != nil {
+		t.Fatalf("Seats failed: %v", err)
+	}
+	if usage.CurrentSeats != 5 || usage.BilledSeats != 3 || usage.MaxUsers != 3 {
+		t.Errorf("expected 5 current and 3 billed seats, got %+v", usage)
+	}
+
+	for i := 2; i <= 5; i++ {
+		handle(topics.UserDeleted, fmt.Sprintf("user-%d", i))
+	}
+	if got := repo.subscriptions["sub_123"].Seats; got != 1 {
+		t.Errorf("expected 1 billed seat, got %d", got)
+	}
+	for _, update := range stub.itemUpdates {
+		if *update.ProrationBehavior != "create_prorations" {
+			t.Errorf("expected prorated updates, got %s", *update.ProrationBehavior)
+		}
+	}
+}
+
+func TestHandleUserEvent_IgnoresOtherPlans(t *testing.T) {
+	s, stub, repo := newSeatService(1)
+	sub := repo.subscriptions["sub_123"]
+	sub.Plan = "starter"
+	repo.subscriptions["sub_123"] = sub
+
+	for _, id := range []string{"user-1", "user-2"} {
+		event := events.NewEvent(topics.UserCreated, "auth-gateway", events.UserCreatedEvent{UserID: id, TenantID: "tenant-1"})
+		if err := s.HandleUserEvent(event); err != nil {
+			t.Fatalf("HandleUserEvent failed: %v", err)
+		}
+	}
+	if len(stub.itemUpdates) != 0 {
+		t.Errorf("expected no seat updates for a plan not billed per seat, got %d", len(stub.itemUpdates))
+	}
+}
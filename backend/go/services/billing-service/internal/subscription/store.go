@@ -0,0 +1,104 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// Record is the local copy of a Stripe subscription, kept current by the
+// webhooks and by reconciliation.
+type Record struct {
+	ID               string    `gorm:"primaryKey" json:"subscription_id"`
+	TenantID         string    `gorm:"not null;index" json:"tenant_id"`
+	CustomerID       string    `gorm:"not null" json:"customer_id"`
+	Plan             string    `gorm:"not null" json:"plan"`
+	Status           string    `gorm:"not null;index" json:"status"`
+	Currency         string    `gorm:"not null;default:''" json:"currency"`
+	Seats            int64     `gorm:"not null;default:0" json:"seats"` // quantity billed
+	CurrentPeriodEnd time.Time `json:"current_period_end"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (Record) TableName() string {
+	return "subscriptions"
+}
+
+// SyncSubscription stores the state Stripe reports for a subscription.
+// Subscriptions not created for a tenant are ignored. The plan of an active
+// subscription is queued for tenant-manager when plan sync is enabled.
+func (s *Service) SyncSubscription(ctx context.Context, sub *stripe.Subscription) error {
+	record := s.recordOf(sub)
+	if record.TenantID == "" {
+		return nil
+	}
+	if err := s.repo.SaveSubscription(ctx, record); err != nil {
+		return fmt.Errorf("failed to store subscription %s: %w", sub.ID, err)
+	}
+	return s.syncPlan(ctx, record)
+}
+
+// PlanSyncer queues plan changes for tenant-manager.
+type PlanSyncer interface {
+	Enqueue(ctx context.Context, tenantID, plan string) error
+}
+
+// EnablePlanSync sends the plan of stored subscriptions to syncer.
+func (s *Service) EnablePlanSync(syncer PlanSyncer) {
+	s.planSyncer = syncer
+}
+
+// syncPlan queues the plan of a subscription that grants one. Queuing only
+// writes locally, so it doesn't wait on tenant-manager.
+func (s *Service) syncPlan(ctx context.Context, record *Record) error {
+	if s.planSyncer == nil || record.Plan == "" {
+		return nil
+	}
+	switch stripe.SubscriptionStatus(record.Status) {
+	case stripe.SubscriptionStatusActive, stripe.SubscriptionStatusTrialing:
+	default:
+		return nil
+	}
+	if err := s.planSyncer.Enqueue(ctx, record.TenantID, record.Plan); err != nil {
+		return fmt.Errorf("failed to queue plan sync for tenant %s: %w", record.TenantID, err)
+	}
+	return nil
+}
+
+// recordOf converts a Stripe subscription to a Record.
+func (s *Service) recordOf(sub *stripe.Subscription) *Record {
+	record := &Record{
+		ID:        sub.ID,
+		TenantID:  sub.Metadata["tenant_id"],
+		Plan:      s.planOf(sub),
+		Status:    string(sub.Status),
+		Currency:  strings.ToUpper(string(sub.Currency)),
+		UpdatedAt: s.now(),
+	}
+	if sub.Customer != nil {
+		record.CustomerID = sub.Customer.ID
+	}
+	if sub.Items != nil && len(sub.Items.Data) > 0 {
+		record.Seats = sub.Items.Data[0].Quantity
+	}
+	if sub.CurrentPeriodEnd > 0 {
+		record.CurrentPeriodEnd = time.Unix(sub.CurrentPeriodEnd, 0).UTC()
+	}
+	return record
+}
+
+// planOf returns the plan of a subscription's price, or the plan it was
+// created with when the price is unknown.
+func (s *Service) planOf(sub *stripe.Subscription) string {
+	if sub.Items != nil && len(sub.Items.Data) > 0 && sub.Items.Data[0].Price != nil {
+		if plan, ok := s.catalog.planOfPrice(sub.Items.Data[0].Price.ID); ok {
+			return plan
+		}
+	}
+	return sub.Metadata["plan"]
+}
@@ -0,0 +1,217 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/client"
+)
+
+// ErrNotFound is returned for a subscription Stripe does not know.
+var ErrNotFound = errors.New("subscription not found")
+
+// ValidationError reports an invalid request.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// StripeClient is the part of the Stripe API the service uses.
+type StripeClient interface {
+	GetSubscription(ctx context.Context, id string) (*stripe.Subscription, error)
+	NewSubscription(ctx context.Context, params *stripe.SubscriptionParams) (*stripe.Subscription, error)
+	UpcomingInvoice(ctx context.Context, params *stripe.InvoiceUpcomingParams) (*stripe.Invoice, error)
+	NewCheckoutSession(ctx context.Context, params *stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error)
+	GetCoupon(ctx context.Context, id string) (*stripe.Coupon, error)
+	// FindPromotionCode returns nil when no promotion code has the given code.
+	FindPromotionCode(ctx context.Context, code string) (*stripe.PromotionCode, error)
+	// ListSubscriptions calls fn for every subscription that is not
+	// canceled, fetching them page by page, until fn returns an error.
+	ListSubscriptions(ctx context.Context, fn func(*stripe.Subscription) error) error
+	UpdateSubscriptionItem(ctx context.Context, id string, params *stripe.SubscriptionItemParams) (*stripe.SubscriptionItem, error)
+}
+
+// StripeAPI implements StripeClient, and the Stripe clients of other
+// packages, with the Stripe SDK.
+type StripeAPI struct {
+	api *client.API
+}
+
+// NewStripeClient creates a StripeClient authenticated with secretKey.
+func NewStripeClient(secretKey string) *StripeAPI {
+	return &StripeAPI{api: client.New(secretKey, nil)}
+}
+
+func (s *StripeAPI) GetSubscription(ctx context.Context, id string) (*stripe.Subscription, error) {
+	params := &stripe.SubscriptionParams{}
+	params.Context = ctx
+	return s.api.Subscriptions.Get(id, params)
+}
+
+func (s *StripeAPI) NewSubscription(ctx context.Context, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	params.Context = ctx
+	return s.api.Subscriptions.New(params)
+}
+
+func (s *StripeAPI) UpdateSubscriptionItem(ctx context.Context, id string, params *stripe.SubscriptionItemParams) (*stripe.SubscriptionItem, error) {
+	params.Context = ctx
+	return s.api.SubscriptionItems.Update(id, params)
+}
+
+func (s *StripeAPI) UpcomingInvoice(ctx context.Context, params *stripe.InvoiceUpcomingParams) (*stripe.Invoice, error) {
+	params.Context = ctx
+	return s.api.Invoices.Upcoming(params)
+}
+
+func (s *StripeAPI) NewCheckoutSession(ctx context.Context, params *stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error) {
+	params.Context = ctx
+	return s.api.CheckoutSessions.New(params)
+}
+
+func (s *StripeAPI) GetCoupon(ctx context.Context, id string) (*stripe.Coupon, error) {
+	params := &stripe.CouponParams{}
+	params.Context = ctx
+	return s.api.Coupons.Get(id, params)
+}
+
+func (s *StripeAPI) GetInvoice(ctx context.Context, id string) (*stripe.Invoice, error) {
+	params := &stripe.InvoiceParams{}
+	params.Context = ctx
+	params.AddExpand("customer")
+	return s.api.Invoices.Get(id, params)
+}
+
+func (s *StripeAPI) FindPromotionCode(ctx context.Context, code string) (*stripe.PromotionCode, error) {
+	params := &stripe.PromotionCodeListParams{Code: stripe.String(code)}
+	params.Context = ctx
+	params.Limit = stripe.Int64(1)
+	iter := s.api.PromotionCodes.List(params)
+	if iter.Next() {
+		return iter.PromotionCode(), nil
+	}
+	return nil, iter.Err()
+}
+
+// subscriptionPageSize is how many subscriptions ListSubscriptions fetches
+// per request, the most Stripe allows.
+const subscriptionPageSize = 100
+
+func (s *StripeAPI) ListSubscriptions(ctx context.Context, fn func(*stripe.Subscription) error) error {
+	params := &stripe.SubscriptionListParams{}
+	params.Context = ctx
+	params.Limit = stripe.Int64(subscriptionPageSize)
+	iter := s.api.Subscriptions.List(params)
+	for iter.Next() {
+		if err := fn(iter.Subscription()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// Repository persists subscriptions, promo code redemptions and trials.
+type Repository interface {
+	// SaveSubscription inserts or updates a subscription.
+	SaveSubscription(ctx context.Context, r *Record) error
+	// ListOpenSubscriptions returns the subscriptions that are not
+	// canceled, of tenantID or of all tenants when it is empty.
+	ListOpenSubscriptions(ctx context.Context, tenantID string) ([]Record, error)
+
+	RecordRedemption(ctx context.Context, r *Redemption) error
+	// ListRedemptions returns redemptions matching filter, newest first.
+	ListRedemptions(ctx context.Context, filter RedemptionFilter) ([]Redemption, error)
+
+	// CreateTrial records a trial, reporting false when the subscription's
+	// trial was already recorded.
+	CreateTrial(ctx context.Context, t *Trial) (bool, error)
+	// TrialsEndingBefore returns the active trials ending between from and
+	// to that were not reminded yet.
+	TrialsEndingBefore(ctx context.Context, from, to time.Time) ([]Trial, error)
+	// MarkTrialReminded records the reminder of a trial, reporting false
+	// when it was already reminded.
+	MarkTrialReminded(ctx context.Context, subscriptionID string, at time.Time) (bool, error)
+	// ConvertTrial marks an active trial converted, reporting false when
+	// the subscription has no active trial.
+	ConvertTrial(ctx context.Context, subscriptionID string, at time.Time) (bool, error)
+
+	// AddSeat records an active user, reporting false when it was already
+	// recorded.
+	AddSeat(ctx context.Context, seat *Seat) (bool, error)
+	// RemoveSeat deletes an active user, reporting false when it was not
+	// recorded.
+	RemoveSeat(ctx context.Context, tenantID, userID string) (bool, error)
+	// CountSeats returns the active users of a tenant.
+	CountSeats(ctx context.Context, tenantID string) (int64, error)
+}
+
+// Service manages tenant subscriptions in Stripe.
+type Service struct {
+	stripe  StripeClient
+	repo    Repository
+	catalog *Catalog
+	now     func() time.Time
+
+	trials            TrialConfig
+	trialNotifier     TrialNotifier
+	reconcileNotifier ReconcileNotifier
+	planSyncer        PlanSyncer
+	seatPlans         map[string]bool
+	maxUsers          func(plan string) int
+}
+
+// NewService creates a Service selling the plans of catalog.
+func NewService(stripe StripeClient, repo Repository, catalog *Catalog) *Service {
+	return &Service{stripe: stripe, repo: repo, catalog: catalog, now: time.Now}
+}
+
+// Catalog returns the plans the service sells.
+func (s *Service) Catalog() *Catalog {
+	return s.catalog
+}
+
+// currencyFor returns the currency to bill a new subscription of a tenant
+// in: that of the tenant's open subscriptions, the requested one, or the
+// one of the tenant's country, in this order. Requesting another currency
+// than the tenant's subscriptions are in is ErrCurrencyChange.
+func (s *Service) currencyFor(ctx context.Context, tenantID, requested, country, locale string) (string, error) {
+	open, err := s.repo.ListOpenSubscriptions(ctx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	existing := ""
+	for _, r := range open {
+		if r.Currency != "" {
+			existing = r.Currency
+			break
+		}
+	}
+
+	requested = strings.ToUpper(strings.TrimSpace(requested))
+	switch {
+	case existing != "" && requested != "" && requested != existing:
+		return "", fmt.Errorf("%w: the tenant is billed in %s", ErrCurrencyChange, existing)
+	case existing != "":
+		return existing, nil
+	case requested != "":
+		return requested, nil
+	default:
+		return s.catalog.Currency(country, locale), nil
+	}
+}
+
+// stripeErr maps a Stripe 404 to ErrNotFound.
+func stripeErr(err error) error {
+	var se *stripe.Error
+	if errors.As(err, &se) && se.HTTPStatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return fmt.Errorf("stripe request failed: %w", err)
+}
@@ -0,0 +1,253 @@
+package subscription
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// testCatalog is a USD catalog of plans with the given Stripe prices.
+func testCatalog(prices map[string]string) *Catalog {
+	c := NewCatalog("USD")
+	for plan, price := range prices {
+		c.AddPlan(plan, plan, PlanPrice{Currency: "USD", StripePriceID: price})
+	}
+	return c
+}
+
+// stubStripe is a StripeClient returning canned objects and recording the
+// requests it gets.
+type stubStripe struct {
+	subscription   *stripe.Subscription
+	listed         []*stripe.Subscription // what ListSubscriptions returns
+	invoice        *stripe.Invoice
+	coupons        map[string]*stripe.Coupon
+	promotionCodes map[string]*stripe.PromotionCode
+
+	upcoming        *stripe.InvoiceUpcomingParams
+	checkout        *stripe.CheckoutSessionParams
+	newSubscription *stripe.SubscriptionParams
+	itemUpdates     []*stripe.SubscriptionItemParams
+}
+
+func newStubStripe() *stubStripe {
+	return &stubStripe{
+		subscription: &stripe.Subscription{
+			ID:       "sub_123",
+			Customer: &stripe.Customer{ID: "cus_123"},
+			Items: &stripe.SubscriptionItemList{Data: []*stripe.SubscriptionItem{
+				{ID: "si_123", Price: &stripe.Price{ID: "price_starter"}},
+			}},
+		},
+		invoice: &stripe.Invoice{
+			Currency:  stripe.CurrencyBRL,
+			Total:     29100,
+			AmountDue: 29100,
+			Lines: &stripe.InvoiceLineItemList{Data: []*stripe.InvoiceLineItem{
+				{Description: "Unused time on Starter", Amount: -2450, Proration: true, Period: &stripe.Period{Start: 1700000000, End: 1701000000}},
+				{Description: "Remaining time on Pro", Amount: 9950, Proration: true, Period: &stripe.Period{Start: 1700000000, End: 1701000000}},
+				{Description: "Pro", Amount: 19900},
+			}},
+		},
+		coupons:        map[string]*stripe.Coupon{},
+		promotionCodes: map[string]*stripe.PromotionCode{},
+	}
+}
+
+func (s *stubStripe) GetSubscription(_ context.Context, id string) (*stripe.Subscription, error) {
+	if s.subscription != nil && s.subscription.ID == id {
+		return s.subscription, nil
+	}
+	for _, sub := range s.listed {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return nil, &stripe.Error{HTTPStatusCode: http.StatusNotFound, Msg: "No such subscription"}
+}
+
+func (s *stubStripe) ListSubscriptions(_ context.Context, fn func(*stripe.Subscription) error) error {
+	for _, sub := range s.listed {
+		if sub.Status == stripe.SubscriptionStatusCanceled {
+			continue
+		}
+		if err := fn(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stubStripe) NewSubscription(_ context.Context, params *stripe.SubscriptionParams) (*stripe.Subscription, error) {
+	s.newSubscription = params
+	sub := &stripe.Subscription{ID: "sub_new", Status: stripe.SubscriptionStatusActive, Metadata: params.Metadata}
+	if params.TrialPeriodDays != nil {
+		sub.Status = stripe.SubscriptionStatusTrialing
+		sub.TrialEnd = time.Now().Add(time.Duration(*params.TrialPeriodDays) * 24 * time.Hour).Unix()
+	}
+	return sub, nil
+}
+
+func (s *stubStripe) UpdateSubscriptionItem(_ context.Context, id string, params *stripe.SubscriptionItemParams) (*stripe.SubscriptionItem, error) {
+	s.itemUpdates = append(s.itemUpdates, params)
+	return &stripe.SubscriptionItem{ID: id, Quantity: *params.Quantity}, nil
+}
+
+func (s *stubStripe) UpcomingInvoice(_ context.Context, params *stripe.InvoiceUpcomingParams) (*stripe.Invoice, error) {
+	s.upcoming = params
+	return s.invoice, nil
+}
+
+func (s *stubStripe) NewCheckoutSession(_ context.Context, params *stripe.CheckoutSessionParams) (*stripe.CheckoutSession, error) {
+	s.checkout = params
+	return &stripe.CheckoutSession{ID: "cs_123", URL: "https://checkout.stripe.com/c/pay/cs_123"}, nil
+}
+
+func (s *stubStripe) GetCoupon(_ context.Context, id string) (*stripe.Coupon, error) {
+	c, ok := s.coupons[id]
+	if !ok {
+		return nil, &stripe.Error{HTTPStatusCode: http.StatusNotFound, Msg: "No such coupon"}
+	}
+	return c, nil
+}
+
+func (s *stubStripe) FindPromotionCode(_ context.Context, code string) (*stripe.PromotionCode, error) {
+	return s.promotionCodes[code], nil
+}
+
+// memRepository is an in-memory Repository.
+type memRepository struct {
+	mu            sync.Mutex
+	subscriptions map[string]Record
+	redemptions   []Redemption
+	trials        map[string]*Trial
+	seats         map[Seat]bool // by tenant and user, CreatedAt zero
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{subscriptions: make(map[string]Record), trials: make(map[string]*Trial), seats: make(map[Seat]bool)}
+}
+
+func (r *memRepository) SaveSubscription(_ context.Context, record *Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions[record.ID] = *record
+	return nil
+}
+
+func (r *memRepository) ListOpenSubscriptions(_ context.Context, tenantID string) ([]Record, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Record
+	for _, record := range r.subscriptions {
+		if record.Status != string(stripe.SubscriptionStatusCanceled) && (tenantID == "" || record.TenantID == tenantID) {
+			out = append(out, record)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepository) RecordRedemption(_ context.Context, redemption *Redemption) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	redemption.ID = uint(len(r.redemptions) + 1)
+	r.redemptions = append(r.redemptions, *redemption)
+	return nil
+}
+
+func (r *memRepository) ListRedemptions(_ context.Context, filter RedemptionFilter) ([]Redemption, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Redemption
+	for i := len(r.redemptions) - 1; i >= 0; i-- {
+		redemption := r.redemptions[i]
+		if (filter.TenantID != "" && redemption.TenantID != filter.TenantID) ||
+			(filter.Code != "" && redemption.Code != filter.Code) {
+			continue
+		}
+		out = append(out, redemption)
+	}
+	return out, nil
+}
+
+func (r *memRepository) CreateTrial(_ context.Context, t *Trial) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.trials[t.SubscriptionID]; ok {
+		return false, nil
+	}
+	trial := *t
+	r.trials[t.SubscriptionID] = &trial
+	return true, nil
+}
+
+func (r *memRepository) TrialsEndingBefore(_ context.Context, from, to time.Time) ([]Trial, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Trial
+	for _, t := range r.trials {
+		if t.Status == TrialActive && t.RemindedAt == nil && !t.TrialEndsAt.Before(from) && t.TrialEndsAt.Before(to) {
+			out = append(out, *t)
+		}
+	}
+	return out, nil
+}
+
+func (r *memRepository) MarkTrialReminded(_ context.Context, subscriptionID string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.trials[subscriptionID]
+	if !ok || t.RemindedAt != nil {
+		return false, nil
+	}
+	t.RemindedAt = &at
+	return true, nil
+}
+
+func (r *memRepository) ConvertTrial(_ context.Context, subscriptionID string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.trials[subscriptionID]
+	if !ok || t.Status != TrialActive {
+		return false, nil
+	}
+	t.Status, t.ConvertedAt = TrialConverted, &at
+	return true, nil
+}
+
+func (r *memRepository) AddSeat(_ context.Context, seat *Seat) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := Seat{TenantID: seat.TenantID, UserID: seat.UserID}
+	if r.seats[key] {
+		return false, nil
+	}
+	r.seats[key] = true
+	return true, nil
+}
+
+func (r *memRepository) RemoveSeat(_ context.Context, tenantID, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := Seat{TenantID: tenantID, UserID: userID}
+	if !r.seats[key] {
+		return false, nil
+	}
+	delete(r.seats, key)
+	return true, nil
+}
+
+func (r *memRepository) CountSeats(_ context.Context, tenantID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for seat := range r.seats {
+		if seat.TenantID == tenantID {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,163 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+// Trial states.
+const (
+	TrialActive    = "trialing"
+	TrialConverted = "active"
+)
+
+// Trial is a subscription in, or converted from, a free trial.
+type Trial struct {
+	SubscriptionID string     `gorm:"primaryKey" json:"subscription_id"`
+	TenantID       string     `gorm:"not null;index" json:"tenant_id"`
+	Plan           string     `gorm:"not null" json:"plan"`
+	Days           int        `gorm:"not null" json:"trial_days"`
+	Status         string     `gorm:"not null;index" json:"status"` // TrialActive or TrialConverted
+	TrialEndsAt    time.Time  `gorm:"not null;index" json:"trial_ends_at"`
+	RemindedAt     *time.Time `json:"reminded_at,omitempty"`
+	ConvertedAt    *time.Time `json:"converted_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (Trial) TableName() string {
+	return "subscription_trials"
+}
+
+// TrialConfig configures free trials.
+type TrialConfig struct {
+	Days           map[string]int // plan ID to trial days; plans without one have no trial
+	ReminderBefore time.Duration  // how long before the trial ends to remind the tenant
+	Interval       time.Duration  // how often RunTrialReminders looks for trials ending
+}
+
+// DefaultTrialConfig reminds tenants three days before their trial ends.
+var DefaultTrialConfig = TrialConfig{
+	ReminderBefore: 72 * time.Hour,
+	Interval:       time.Hour,
+}
+
+// TrialNotifier announces trials starting and ending.
+type TrialNotifier interface {
+	TrialStarted(ctx context.Context, trial Trial) error
+	TrialEndingSoon(ctx context.Context, trial Trial) error
+}
+
+// EnableTrials applies cfg's trial days to new subscriptions and sends trial
+// events to notifier, which may be nil.
+func (s *Service) EnableTrials(cfg TrialConfig, notifier TrialNotifier) {
+	if cfg.ReminderBefore <= 0 {
+		cfg.ReminderBefore = DefaultTrialConfig.ReminderBefore
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultTrialConfig.Interval
+	}
+	s.trials = cfg
+	s.trialNotifier = notifier
+}
+
+// trialDays returns the trial days of a plan, 0 for none.
+func (s *Service) trialDays(plan string) int {
+	return s.trials.Days[plan]
+}
+
+// TrackTrial records a subscription Stripe created in trial, such as one
+// started through Checkout. Subscriptions not trialing, or not created by
+// this service, are ignored.
+func (s *Service) TrackTrial(ctx context.Context, sub *stripe.Subscription) error {
+	if sub.Status != stripe.SubscriptionStatusTrialing || sub.Metadata["tenant_id"] == "" {
+		return nil
+	}
+	plan := sub.Metadata["plan"]
+	return s.startTrial(ctx, &Trial{
+		SubscriptionID: sub.ID,
+		TenantID:       sub.Metadata["tenant_id"],
+		Plan:           plan,
+		Days:           s.trialDays(plan),
+		TrialEndsAt:    time.Unix(sub.TrialEnd, 0).UTC(),
+	})
+}
+
+// startTrial records a trial and announces it, once per subscription.
+func (s *Service) startTrial(ctx context.Context, trial *Trial) error {
+	trial.Status = TrialActive
+	trial.CreatedAt = s.now()
+	created, err := s.repo.CreateTrial(ctx, trial)
+	if err != nil {
+		return fmt.Errorf("failed to record trial: %w", err)
+	}
+	if created && s.trialNotifier != nil {
+		if err := s.trialNotifier.TrialStarted(ctx, *trial); err != nil {
+			log.Printf("Failed to announce trial of subscription %s: %v", trial.SubscriptionID, err)
+		}
+	}
+	return nil
+}
+
+// ConvertTrial marks a trialing subscription active, on its first
+// successful payment. It reports whether a trial was converted.
+func (s *Service) ConvertTrial(ctx context.Context, subscriptionID string) (bool, error) {
+	return s.repo.ConvertTrial(ctx, subscriptionID, s.now())
+}
+
+// SendTrialReminders announces the trials ending within ReminderBefore that
+// were not reminded yet, and returns how many it announced.
+func (s *Service) SendTrialReminders(ctx context.Context) (int, error) {
+	now := s.now()
+	due, err := s.repo.TrialsEndingBefore(ctx, now, now.Add(s.trials.ReminderBefore))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, trial := range due {
+		// Claim the reminder first, so concurrent instances send it once
+		claimed, err := s.repo.MarkTrialReminded(ctx, trial.SubscriptionID, now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		trial.RemindedAt = &now
+		if s.trialNotifier != nil {
+			if err := s.trialNotifier.TrialEndingSoon(ctx, trial); err != nil {
+				log.Printf("Failed to remind trial of subscription %s: %v", trial.SubscriptionID, err)
+				continue
+			}
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// RunTrialReminders sends trial reminders every Interval until ctx is done.
+func (s *Service) RunTrialReminders(ctx context.Context) {
+	ticker := time.NewTicker(s.trials.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := s.SendTrialReminders(ctx)
+			if err != nil {
+				log.Printf("Failed to send trial reminders: %v", err)
+				continue
+			}
+			if sent > 0 {
+				log.Printf("Sent %d trial reminders", sent)
+			}
+		}
+	}
+}
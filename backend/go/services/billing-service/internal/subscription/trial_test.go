@@ -0,0 +1,117 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/platformeventstest"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/stripe/stripe-go/v76"
+)
+
+func newTrialService(stub *stubStripe, repo *memRepository, publisher *platformeventstest.FakePublisher) *Service {
+	s := NewService(stub, repo, testCatalog(map[string]string{"starter": "price_starter", "pro": "price_pro"}))
+	s.EnableTrials(TrialConfig{Days: map[string]int{"pro": 14}}, NewEventNotifier(publisher, "billing-service"))
+	return s
+}
+
+func TestCreateSubscription_StartsTrial(t *testing.T) {
+	stub := newStubStripe()
+	repo := newMemRepository()
+	publisher := platformeventstest.NewFakePublisher()
+	s := newTrialService(stub, repo, publisher)
+	ctx := context.Background()
+
+	sub, err := s.CreateSubscription(ctx, SubscriptionRequest{TenantID: "tenant-1", CustomerID: "cus_123", Plan: "pro"})
+	if err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	if got := stub.newSubscription.TrialPeriodDays; got == nil || *got != 14 {
+		t.Fatalf("expected a 14 day trial, got %v", got)
+	}
+	if sub.Status != string(stripe.SubscriptionStatusTrialing) || sub.TrialEndsAt == nil {
+		t.Fatalf("expected a trialing subscription with trial_ends_at, got %+v", sub)
+	}
+	if trial := repo.trials["sub_new"]; trial == nil || trial.Status != TrialActive || trial.TenantID != "tenant-1" {
+		t.Fatalf("expected an active trial recorded, got %+v", trial)
+	}
+	publisher.AssertPublished(t, topics.TrialStarted, map[string]any{
+		"subscription_id": "sub_new",
+		"tenant_id":       "tenant-1",
+		"plan":            "pro",
+		"trial_days":      14,
+	})
+
+	// The webhook for the same subscription does not announce it again
+	if err := s.TrackTrial(ctx, &stripe.Subscription{
+		ID:       "sub_new",
+		Status:   stripe.SubscriptionStatusTrialing,
+		TrialEnd: sub.TrialEndsAt.Unix(),
+		Metadata: map[string]string{"tenant_id": "tenant-1", "plan": "pro"},
+	}); err != nil {
+		t.Fatalf("TrackTrial failed: %v", err)
+	}
+	publisher.AssertPublishedCount(t, topics.TrialStarted, 1)
+
+	converted, err := s.ConvertTrial(ctx, "sub_new")
+	if err != nil || !converted {
+		t.Fatalf("expected the trial converted, got %v, %v", converted, err)
+	}
+	if status := repo.trials["sub_new"].Status; status != TrialConverted {
+		t.Errorf("expected status %s, got %s", TrialConverted, status)
+	}
+}
+
+func TestCreateSubscription_PlanWithoutTrial(t *testing.T) {
+	stub := newStubStripe()
+	publisher := platformeventstest.NewFakePublisher()
+	s := newTrialService(stub, newMemRepository(), publisher)
+
+	sub, err := s.CreateSubscription(context.Background(), SubscriptionRequest{TenantID: "tenant-1", CustomerID: "cus_123", Plan: "starter"})
+	if err != nil {
+		t.Fatalf("CreateSubscription failed: %v", err)
+	}
+	if stub.newSubscription.TrialPeriodDays != nil || sub.TrialEndsAt != nil {
+		t.Errorf("expected no trial, got %+v", sub)
+	}
+	publisher.AssertNotPublished(t, topics.TrialStarted)
+}
+
+func TestSendTrialReminders(t *testing.T) {
+	repo := newMemRepository()
+	publisher := platformeventstest.NewFakePublisher()
+	s := newTrialService(newStubStripe(), repo, publisher)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for id, endsIn := range map[string]time.Duration{
+		"sub_soon":  48 * time.Hour,
+		"sub_later": 10 * 24 * time.Hour,
+		"sub_ended": -time.Hour,
+	} {
+		s.startTrial(ctx, &Trial{SubscriptionID: id, TenantID: "tenant-1", Plan: "pro", Days: 14, TrialEndsAt: now.Add(endsIn)})
+	}
+
+	sent, err := s.SendTrialReminders(ctx)
+	if err != nil {
+		t.Fatalf("SendTrialReminders failed: %v", err)
+	}
+	if sent != 1 {
+		t.Fatalf("expected 1 reminder, got %d", sent)
+	}
+	publisher.AssertPublished(t, topics.TrialEndingSoon, map[string]any{"subscription_id": "sub_soon"})
+
+	// Reminders are sent once
+	if sent, _ := s.SendTrialReminders(ctx); sent != 0 {
+		t.Errorf("expected no repeated reminder, got %d", sent)
+	}
+
+	// Later trials are reminded once they come within the window
+	now = now.Add(8 * 24 * time.Hour)
+	if sent, _ := s.SendTrialReminders(ctx); sent != 1 {
+		t.Errorf("expected the later trial reminded, got %d", sent)
+	}
+	publisher.AssertPublishedCount(t, topics.TrialEndingSoon, 2)
+}
@@ -0,0 +1,63 @@
+package tenantsync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// tenantPlans maps billing plans to tenant-manager's.
+var tenantPlans = map[string]string{
+	"starter":    "starter",
+	"pro":        "professional",
+	"enterprise": "enterprise",
+}
+
+// TenantPlan returns tenant-manager's name of a billing plan.
+func TenantPlan(plan string) string {
+	if tenantPlan, ok := tenantPlans[plan]; ok {
+		return tenantPlan
+	}
+	return plan
+}
+
+// HTTPClient calls tenant-manager's REST API.
+type HTTPClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPClient creates an HTTPClient for tenant-manager at baseURL.
+func NewHTTPClient(baseURL string, client *http.Client) *HTTPClient {
+	return &HTTPClient{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// SetPlan implements TenantManager.
+func (c *HTTPClient) SetPlan(ctx context.Context, tenantID, plan string) error {
+	body, err := json.Marshal(map[string]string{"plan": TenantPlan(plan)})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut,
+		c.baseURL+"/api/v1/tenants/"+url.PathEscape(tenantID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tenant-manager unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("tenant-manager returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
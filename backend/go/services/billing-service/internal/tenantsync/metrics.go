@@ -0,0 +1,37 @@
+package tenantsync
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics reports the state of the queue.
+type Metrics struct {
+	pending  prometheus.Gauge
+	attempts *prometheus.CounterVec
+}
+
+// NewMetrics registers the queue metrics with reg.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	factory := promauto.With(reg)
+	return &Metrics{
+		pending: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: "billing",
+			Subsystem: "tenant_sync",
+			Name:      "pending",
+			Help:      "Plan changes not applied to tenant-manager yet",
+		}),
+		attempts: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "billing",
+			Subsystem: "tenant_sync",
+			Name:      "attempts_total",
+			Help:      "Attempts to apply a plan change to tenant-manager, by result",
+		}, []string{"result"}),
+	}
+}
+
+func (m *Metrics) attempt(result string) {
+	if m != nil {
+		m.attempts.WithLabelValues(result).Inc()
+	}
+}
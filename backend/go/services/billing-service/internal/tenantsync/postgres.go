@@ -0,0 +1,72 @@
+package tenantsync
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormRepository implements Repository on PostgreSQL
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository creates a new GormRepository
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// Enqueue inserts the job, or replaces the tenant's pending one and resets
+// its attempts
+func (r *GormRepository) Enqueue(ctx context.Context, job *Job) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "plan"}, Value: job.Plan},
+			{Column: clause.Column{Name: "version"}, Value: gorm.Expr("tenant_sync_jobs.version + 1")},
+			{Column: clause.Column{Name: "attempts"}, Value: 0},
+			{Column: clause.Column{Name: "next_attempt_at"}, Value: job.NextAttemptAt},
+			{Column: clause.Column{Name: "last_error"}, Value: ""},
+			{Column: clause.Column{Name: "updated_at"}, Value: time.Now()},
+		},
+	}).Create(job).Error
+}
+
+// Due lists the oldest jobs due at now
+func (r *GormRepository) Due(ctx context.Context, now time.Time, limit int) ([]Job, error) {
+	var jobs []Job
+	err := r.db.WithContext(ctx).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// Complete deletes the job if its version is unchanged
+func (r *GormRepository) Complete(ctx context.Context, job Job) error {
+	return r.db.WithContext(ctx).
+		Where("tenant_id = ? AND version = ?", job.TenantID, job.Version).
+		Delete(&Job{}).Error
+}
+
+// Reschedule stores the failed attempt if the job's version is unchanged
+func (r *GormRepository) Reschedule(ctx context.Context, job Job) error {
+	return r.db.WithContext(ctx).Model(&Job{}).
+		Where("tenant_id = ? AND version = ?", job.TenantID, job.Version).
+		Updates(map[string]interface{}{
+			"attempts":        job.Attempts,
+			"next_attempt_at": job.NextAttemptAt,
+			"last_error":      job.LastError,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+// CountPending counts the jobs
+func (r *GormRepository) CountPending(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&Job{}).Count(&count).Error
+	return count, err
+}
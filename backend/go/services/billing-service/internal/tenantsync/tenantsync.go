@@ -0,0 +1,174 @@
+// Package tenantsync applies plan changes to tenant-manager through an
+// outbox, so a subscription change is recorded even when tenant-manager is
+// down, and applied once it is back.
+package tenantsync
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a plan change waiting to be applied in tenant-manager. There is at
+// most one per tenant: a newer plan replaces the one still pending.
+type Job struct {
+	TenantID      string    `gorm:"primaryKey" json:"tenant_id"`
+	Plan          string    `gorm:"not null" json:"plan"`
+	Version       int64     `gorm:"not null;default:1" json:"version"`
+	Attempts      int       `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time `gorm:"not null;index" json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (Job) TableName() string {
+	return "tenant_sync_jobs"
+}
+
+// Repository stores the pending jobs.
+type Repository interface {
+	// Enqueue stores a job, replacing the tenant's pending one.
+	Enqueue(ctx context.Context, job *Job) error
+	// Due lists up to limit jobs to attempt at now.
+	Due(ctx context.Context, now time.Time, limit int) ([]Job, error)
+	// Complete removes a job, unless it was replaced since it was read.
+	Complete(ctx context.Context, job Job) error
+	// Reschedule records a failed attempt, unless the job was replaced since
+	// it was read.
+	Reschedule(ctx context.Context, job Job) error
+	// CountPending counts the jobs not applied yet.
+	CountPending(ctx context.Context) (int64, error)
+}
+
+// TenantManager applies plans to tenants.
+type TenantManager interface {
+	SetPlan(ctx context.Context, tenantID, plan string) error
+}
+
+// Config tunes retries.
+type Config struct {
+	Interval   time.Duration // between runs
+	BatchSize  int
+	MinBackoff time.Duration // after the first failure, doubling up to MaxBackoff
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig is used for the zero fields of a Config.
+var DefaultConfig = Config{
+	Interval:   10 * time.Second,
+	BatchSize:  50,
+	MinBackoff: 30 * time.Second,
+	MaxBackoff: time.Hour,
+}
+
+// Queue records plan changes and applies them to tenant-manager, retrying
+// those that fail.
+type Queue struct {
+	repo    Repository
+	tenants TenantManager
+	metrics *Metrics
+	cfg     Config
+	now     func() time.Time
+}
+
+// NewQueue creates a Queue. metrics may be nil.
+func NewQueue(repo Repository, tenants TenantManager, metrics *Metrics, cfg Config) *Queue {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultConfig.Interval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultConfig.BatchSize
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultConfig.MinBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultConfig.MaxBackoff
+	}
+	return &Queue{repo: repo, tenants: tenants, metrics: metrics, cfg: cfg, now: time.Now}
+}
+
+// Enqueue records that tenantID moved to plan. It only writes to the
+// database, so callers such as webhooks return without waiting for
+// tenant-manager.
+func (q *Queue) Enqueue(ctx context.Context, tenantID, plan string) error {
+	if err := q.repo.Enqueue(ctx, &Job{TenantID: tenantID, Plan: plan, NextAttemptAt: q.now()}); err != nil {
+		return err
+	}
+	q.updatePending(ctx)
+	return nil
+}
+
+// Process attempts the due jobs, and returns how many were applied.
+func (q *Queue) Process(ctx context.Context) (int, error) {
+	jobs, err := q.repo.Due(ctx, q.now(), q.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	defer q.updatePending(ctx)
+
+	applied := 0
+	for _, job := range jobs {
+		if err := q.tenants.SetPlan(ctx, job.TenantID, job.Plan); err != nil {
+			q.metrics.attempt("failed")
+			job.Attempts++
+			job.LastError = err.Error()
+			job.NextAttemptAt = q.now().Add(q.backoff(job.Attempts))
+			log.Printf("Failed to sync plan %s to tenant %s (attempt %d): %v", job.Plan, job.TenantID, job.Attempts, err)
+			if err := q.repo.Reschedule(ctx, job); err != nil {
+				return applied, err
+			}
+			continue
+		}
+		q.metrics.attempt("succeeded")
+		if err := q.repo.Complete(ctx, job); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// Run processes the queue every interval until ctx is done.
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(q.cfg.Interval)
+	defer ticker.Stop()
+
+	q.updatePending(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := q.Process(ctx); err != nil {
+				log.Printf("Tenant sync failed: %v", err)
+			}
+		}
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (q *Queue) backoff(attempts int) time.Duration {
+	wait := q.cfg.MinBackoff
+	for i := 1; i < attempts && wait < q.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > q.cfg.MaxBackoff {
+		wait = q.cfg.MaxBackoff
+	}
+	return wait
+}
+
+func (q *Queue) updatePending(ctx context.Context) {
+	if q.metrics == nil {
+		return
+	}
+	pending, err := q.repo.CountPending(ctx)
+	if err != nil {
+		log.Printf("Failed to count pending tenant syncs: %v", err)
+		return
+	}
+	q.metrics.pending.Set(float64(pending))
+}
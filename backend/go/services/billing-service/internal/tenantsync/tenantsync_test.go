@@ -0,0 +1,141 @@
+package tenantsync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// memRepository is an in-memory Repository for tests
+type memRepository struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{jobs: make(map[string]Job)}
+}
+
+func (r *memRepository) Enqueue(ctx context.Context, job *Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job.Version = r.jobs[job.TenantID].Version + 1
+	r.jobs[job.TenantID] = *job
+	return nil
+}
+
+func (r *memRepository) Due(ctx context.Context, now time.Time, limit int) ([]Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []Job
+	for _, job := range r.jobs {
+		if !job.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, job)
+		}
+	}
+	return due, nil
+}
+
+func (r *memRepository) Complete(ctx context.Context, job Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.jobs[job.TenantID].Version == job.Version {
+		delete(r.jobs, job.TenantID)
+	}
+	return nil
+}
+
+func (r *memRepository) Reschedule(ctx context.Context, job Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.jobs[job.TenantID].Version == job.Version {
+		r.jobs[job.TenantID] = job
+	}
+	return nil
+}
+
+func (r *memRepository) CountPending(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(len(r.jobs)), nil
+}
+
+// flakyTenantManager fails until up is set
+type flakyTenantManager struct {
+	up    bool
+	calls int
+	plans map[string]string
+}
+
+func (m *flakyTenantManager) SetPlan(ctx context.Context, tenantID, plan string) error {
+	m.calls++
+	if !m.up {
+		return errors.New("connection refused")
+	}
+	m.plans[tenantID] = plan
+	return nil
+}
+
+func TestQueue_RetriesUntilTenantManagerRecovers(t *testing.T) {
+	repo := newMemRepository()
+	tenants := &flakyTenantManager{plans: map[string]string{}}
+	metrics := NewMetrics(prometheus.NewRegistry())
+	q := NewQueue(repo, tenants, metrics, Config{MinBackoff: time.Minute, MaxBackoff: 5 * time.Minute})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := q.Enqueue(ctx, "tenant-1", "pro"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.pending); got != 1 {
+		t.Fatalf("expected 1 pending sync, got %v", got)
+	}
+
+	// tenant-manager is down: the job is kept and retried later
+	if applied, err := q.Process(ctx); err != nil || applied != 0 {
+		t.Fatalf("expected nothing applied, got %d, %v", applied, err)
+	}
+	job := repo.jobs["tenant-1"]
+	if job.Attempts != 1 || job.LastError == "" || !job.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected a retry in a minute, got %+v", job)
+	}
+
+	// Not due yet
+	q.Process(ctx)
+	if tenants.calls != 1 {
+		t.Fatalf("expected no attempt before the backoff, got %d calls", tenants.calls)
+	}
+
+	// Still down: the backoff doubles
+	now = now.Add(time.Minute)
+	q.Process(ctx)
+	if job := repo.jobs["tenant-1"]; job.Attempts != 2 || !job.NextAttemptAt.Equal(now.Add(2*time.Minute)) {
+		t.Fatalf("expected a retry in two minutes, got %+v", job)
+	}
+
+	// A newer plan replaces the pending one
+	q.Enqueue(ctx, "tenant-1", "enterprise")
+
+	tenants.up = true
+	if applied, err := q.Process(ctx); err != nil || applied != 1 {
+		t.Fatalf("expected the sync applied, got %d, %v", applied, err)
+	}
+	if plan := tenants.plans["tenant-1"]; plan != "enterprise" {
+		t.Errorf("expected plan enterprise, got %s", plan)
+	}
+	if len(repo.jobs) != 0 {
+		t.Errorf("expected no pending jobs, got %+v", repo.jobs)
+	}
+	if got := testutil.ToFloat64(metrics.pending); got != 0 {
+		t.Errorf("expected no pending syncs, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.attempts.WithLabelValues("failed")); got != 2 {
+		t.Errorf("expected 2 failed attempts, got %v", got)
+	}
+}
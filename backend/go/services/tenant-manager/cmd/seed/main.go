@@ -0,0 +1,50 @@
+// Command seed sets up a demo tenant for local development, with its quota,
+// DID, agent, admin user and Stripe test customer. Running it again reuses
+// what the first run created. It refuses to run against production.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"tenant-manager/internal/seed"
+)
+
+func main() {
+	seeder, err := seed.New(seed.Config{
+		Environment:          getEnv("ENVIRONMENT", "development"),
+		StripeSecretKey:      os.Getenv("STRIPE_SECRET_KEY"),
+		TenantManagerURL:     getEnv("TENANT_MANAGER_URL", "http://localhost:8081"),
+		AgentOrchestratorURL: getEnv("AGENT_ORCHESTRATOR_URL", "http://localhost:8082"),
+		// A service token from auth-gateway, agent-orchestrator refuses anything else
+		AgentOrchestratorToken: os.Getenv("AGENT_ORCHESTRATOR_TOKEN"),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := seeder.Run(ctx)
+	if err != nil {
+		log.Fatalf("Seeding failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
@@ -10,6 +10,12 @@ require (
 	github.com/jackc/pgx/v5 v5.5.3
 	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/prometheus/client_golang v1.18.0
+	github.com/serphona/serphona/backend/go/libs/platform-audit v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-core v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-entitlements v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-errors v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-pagination v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-phone v0.0.0
 	go.uber.org/zap v1.26.0
 	google.golang.org/grpc v1.61.0
 )
@@ -40,6 +46,7 @@ require (
 	github.com/jcmturner/rpc/v2 v2.0.3 // indirect
 	github.com/klauspost/compress v1.18.1 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
+	github.com/nyaruka/phonenumbers v1.4.0 // indirect
 	github.com/pierrec/lz4/v4 v4.1.22 // indirect
 	github.com/prometheus/client_model v0.5.0 // indirect
 	github.com/prometheus/common v0.46.0 // indirect
@@ -53,7 +60,19 @@ require (
 	golang.org/x/sys v0.37.0 // indirect
 	golang.org/x/text v0.30.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240125205218-1f4bbc51befe // indirect
-	google.golang.org/protobuf v1.32.0 // indirect
+	google.golang.org/protobuf v1.34.1 // indirect
 )
 
 replace github.com/serphona/serphona/backend/go/libs/platform-events => ../../libs/platform-events
+
+replace github.com/serphona/serphona/backend/go/libs/platform-entitlements => ../../libs/platform-entitlements
+
+replace github.com/serphona/serphona/backend/go/libs/platform-pagination => ../../libs/platform-pagination
+
+replace github.com/serphona/serphona/backend/go/libs/platform-phone => ../../libs/platform-phone
+
+replace github.com/serphona/serphona/backend/go/libs/platform-errors => ../../libs/platform-errors
+
+replace github.com/serphona/serphona/backend/go/libs/platform-audit => ../../libs/platform-audit
+
+replace github.com/serphona/serphona/backend/go/libs/platform-core => ../../libs/platform-core
@@ -0,0 +1,200 @@
+package crypto
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/domain/tenant"
+)
+
+// ciphertextPrefix marks values sealed by Envelope; the data key version
+// and the base64 ciphertext follow it, e.g. "enc:v2:AbC...".
+const ciphertextPrefix = "enc:v"
+
+// Envelope implements tenant.SecretCipher with envelope encryption: each
+// tenant has its own data keys, stored wrapped by the KMS master key, and
+// secrets are sealed with AES-256-GCM under the tenant's newest data key.
+type Envelope struct {
+	keys tenant.DataKeyRepository
+	kms  KMS
+
+	mu    sync.Mutex
+	aeads map[keyRef]cipher.AEAD // unwrapped data keys
+}
+
+type keyRef struct {
+	tenantID uuid.UUID
+	version  int
+}
+
+var _ tenant.SecretCipher = (*Envelope)(nil)
+
+// NewEnvelope creates a new Envelope.
+func NewEnvelope(keys tenant.DataKeyRepository, kms KMS) *Envelope {
+	return &Envelope{
+		keys:  keys,
+		kms:   kms,
+		aeads: make(map[keyRef]cipher.AEAD),
+	}
+}
+
+// IsCiphertext reports whether value was sealed by an Envelope.
+func IsCiphertext(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+// Encrypt seals plaintext under the tenant's newest data key, creating the
+// tenant's first key if it has none.
+func (e *Envelope) Encrypt(ctx context.Context, tenantID uuid.UUID, plaintext string) (string, error) {
+	key, err := e.keys.Latest(ctx, tenantID)
+	if errors.Is(err, tenant.ErrDataKeyNotFound) {
+		key, err = e.createKey(ctx, tenantID, 1)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load data key: %w", err)
+	}
+
+	aead, err := e.aead(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext), additionalData(key.TenantID, key.Version))
+	if err != nil {
+		return "", err
+	}
+
+	return ciphertextPrefix + strconv.Itoa(key.Version) + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a ciphertext sealed for the tenant under any of its data
+// key versions.
+func (e *Envelope) Decrypt(ctx context.Context, tenantID uuid.UUID, ciphertext string) (string, error) {
+	version, sealed, err := parseCiphertext(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := e.keys.Get(ctx, tenantID, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to load data key v%d: %w", version, err)
+	}
+
+	aead, err := e.aead(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, sealed, additionalData(tenantID, version))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// RotateKey makes a new data key current for the tenant. Older versions
+// are kept so existing ciphertexts still decrypt.
+func (e *Envelope) RotateKey(ctx context.Context, tenantID uuid.UUID) error {
+	next := 1
+	latest, err := e.keys.Latest(ctx, tenantID)
+	switch {
+	case err == nil:
+		next = latest.Version + 1
+	case !errors.Is(err, tenant.ErrDataKeyNotFound):
+		return fmt.Errorf("failed to load data key: %w", err)
+	}
+
+	_, err = e.createKey(ctx, tenantID, next)
+	return err
+}
+
+// createKey generates, wraps and stores a data key version. When another
+// instance created that version first, its key is used instead.
+func (e *Envelope) createKey(ctx context.Context, tenantID uuid.UUID, version int) (*tenant.DataKey, error) {
+	plain := make([]byte, 32)
+	if _, err := rand.Read(plain); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	wrapped, err := e.kms.Wrap(ctx, plain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	key := &tenant.DataKey{
+		TenantID:  tenantID,
+		Version:   version,
+		Wrapped:   wrapped,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = e.keys.Create(ctx, key)
+	if errors.Is(err, tenant.ErrDataKeyExists) {
+		return e.keys.Get(ctx, tenantID, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store data key: %w", err)
+	}
+
+	return key, nil
+}
+
+// aead returns the cipher of a data key, unwrapping it on first use.
+func (e *Envelope) aead(ctx context.Context, key *tenant.DataKey) (cipher.AEAD, error) {
+	ref := keyRef{tenantID: key.TenantID, version: key.Version}
+
+	e.mu.Lock()
+	aead, ok := e.aeads[ref]
+	e.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	plain, err := e.kms.Unwrap(ctx, key.Wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key v%d: %w", key.Version, err)
+	}
+	if aead, err = newAEAD(plain); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.aeads[ref] = aead
+	e.mu.Unlock()
+
+	return aead, nil
+}
+
+// additionalData binds a ciphertext to its tenant and key version, so it
+// can't be moved to another tenant's settings.
+func additionalData(tenantID uuid.UUID, version int) []byte {
+	return []byte(tenantID.String() + ":" + strconv.Itoa(version))
+}
+
+func parseCiphertext(value string) (int, []byte, error) {
+	rest, ok := strings.CutPrefix(value, ciphertextPrefix)
+	if !ok {
+		return 0, nil, errors.New("value is not an encrypted secret")
+	}
+	versionText, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return 0, nil, errors.New("malformed encrypted secret")
+	}
+	version, err := strconv.Atoi(versionText)
+	if err != nil {
+		return 0, nil, fmt.Errorf("malformed data key version: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, nil, fmt.Errorf("malformed encrypted secret: %w", err)
+	}
+	return version, sealed, nil
+}
@@ -0,0 +1,92 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/domain/tenant/tenanttest"
+)
+
+func newEnvelope(t *testing.T) *Envelope {
+	t.Helper()
+	kms, err := NewLocalKMS(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewLocalKMS: %v", err)
+	}
+	return NewEnvelope(tenanttest.NewDataKeys(), kms)
+}
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	e := newEnvelope(t)
+	tenantID := uuid.New()
+
+	sealed, err := e.Encrypt(ctx, tenantID, "whsec_123")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(sealed, "enc:v1:") || strings.Contains(sealed, "whsec_123") {
+		t.Fatalf("ciphertext = %q, want an opaque enc:v1 value", sealed)
+	}
+
+	plain, err := e.Decrypt(ctx, tenantID, sealed)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if plain != "whsec_123" {
+		t.Errorf("Decrypt = %q, want whsec_123", plain)
+	}
+}
+
+func TestEnvelope_OtherTenantCannotDecrypt(t *testing.T) {
+	ctx := context.Background()
+	e := newEnvelope(t)
+	owner, other := uuid.New(), uuid.New()
+
+	sealed, err := e.Encrypt(ctx, owner, "sk-live")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if _, err := e.Encrypt(ctx, other, "x"); err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if _, err := e.Decrypt(ctx, other, sealed); err == nil {
+		t.Error("Decrypt with another tenant succeeded")
+	}
+}
+
+func TestEnvelope_RotateKey(t *testing.T) {
+	ctx := context.Background()
+	e := newEnvelope(t)
+	tenantID := uuid.New()
+
+	before, err := e.Encrypt(ctx, tenantID, "old")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if err := e.RotateKey(ctx, tenantID); err != nil {
+		t.Fatalf("RotateKey: %v", err)
+	}
+	after, err := e.Encrypt(ctx, tenantID, "new")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	if !strings.HasPrefix(after, "enc:v2:") {
+		t.Errorf("ciphertext after rotation = %q, want data key v2", after)
+	}
+	if plain, err := e.Decrypt(ctx, tenantID, before); err != nil || plain != "old" {
+		t.Errorf("Decrypt(v1) = %q, %v; want old", plain, err)
+	}
+}
+
+func TestNewLocalKMS_KeySize(t *testing.T) {
+	if _, err := NewLocalKMS([]byte("short")); err == nil {
+		t.Error("NewLocalKMS accepted a 5-byte key")
+	}
+}
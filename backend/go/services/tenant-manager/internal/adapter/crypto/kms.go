@@ -0,0 +1,75 @@
+// Package crypto provides envelope encryption of tenant secrets.
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// KMS wraps and unwraps data keys with a master key that never leaves it.
+type KMS interface {
+	Wrap(ctx context.Context, key []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// LocalKMS is a KMS holding an AES-256 master key in process memory, for
+// deployments without a managed KMS.
+type LocalKMS struct {
+	aead cipher.AEAD
+}
+
+// NewLocalKMS creates a LocalKMS from a 32-byte master key.
+func NewLocalKMS(masterKey []byte) (*LocalKMS, error) {
+	if len(masterKey) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(masterKey))
+	}
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKMS{aead: aead}, nil
+}
+
+// Wrap encrypts a data key with the master key.
+func (k *LocalKMS) Wrap(_ context.Context, key []byte) ([]byte, error) {
+	return seal(k.aead, key, nil)
+}
+
+// Unwrap decrypts a data key wrapped by Wrap.
+func (k *LocalKMS) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	return open(k.aead, wrapped, nil)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext, prefixing the result with a random nonce.
+func seal(aead cipher.AEAD, plaintext, additional []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// open decrypts what seal produced.
+func open(aead cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
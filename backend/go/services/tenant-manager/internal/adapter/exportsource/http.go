@@ -0,0 +1,106 @@
+// Package exportsource provides the data-export sources backed by other
+// services' APIs.
+package exportsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+
+	"tenant-manager/internal/application/export"
+)
+
+// maxResponseSize bounds what a single source may return.
+const maxResponseSize = 256 << 20
+
+// HTTPSource collects a subject's data with a GET on another service. The
+// URL template may hold {tenant_id} and {customer_id} placeholders; the
+// customer ID is also sent as the customer_id query parameter.
+type HTTPSource struct {
+	name        string
+	urlTemplate string
+	client      *http.Client
+}
+
+// NewHTTPSource creates a new HTTPSource.
+func NewHTTPSource(name, urlTemplate string, timeout time.Duration) *HTTPSource {
+	return &HTTPSource{
+		name:        name,
+		urlTemplate: urlTemplate,
+		client:      &http.Client{Timeout: timeout, Transport: requestid.NewTransport(nil)},
+	}
+}
+
+// NewHTTPSources creates one HTTPSource per name:URL template pair, ordered
+// by name.
+func NewHTTPSources(templates map[string]string, timeout time.Duration) []export.Source {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sources := make([]export.Source, 0, len(names))
+	for _, name := range names {
+		sources = append(sources, NewHTTPSource(name, templates[name], timeout))
+	}
+	return sources
+}
+
+// Name returns the source name.
+func (s *HTTPSource) Name() string {
+	return s.name
+}
+
+// Collect fetches the subject's data.
+func (s *HTTPSource) Collect(ctx context.Context, subject export.Subject) (json.RawMessage, error) {
+	rawURL := strings.NewReplacer(
+		"{tenant_id}", url.PathEscape(subject.TenantID.String()),
+		"{customer_id}", url.PathEscape(subject.CustomerID),
+	).Replace(s.urlTemplate)
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s url: %w", s.name, err)
+	}
+	if subject.CustomerID != "" {
+		q := u.Query()
+		q.Set("customer_id", subject.CustomerID)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Tenant-ID", subject.TenantID.String())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", s.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", s.name, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.name, err)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("%s returned invalid JSON", s.name)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,180 @@
+// Package handler contains HTTP request handlers.
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/application/customer"
+)
+
+// CustomerHandler handles end-customer identity HTTP requests.
+type CustomerHandler struct {
+	service *customer.Service
+	logger  *zap.Logger
+}
+
+// NewCustomerHandler creates a new CustomerHandler.
+func NewCustomerHandler(service *customer.Service, logger *zap.Logger) *CustomerHandler {
+	return &CustomerHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ResolveCustomerRequest represents the request body for resolving a customer.
+type ResolveCustomerRequest struct {
+	// Identifier is the caller's phone number or email address
+	Identifier string `json:"identifier"`
+}
+
+// MergeCustomerRequest represents the request body for merging a duplicate
+// customer into another.
+type MergeCustomerRequest struct {
+	SourceID string `json:"source_id"`
+}
+
+// Resolve handles POST /api/v1/tenants/{id}/customers/resolve
+// @Summary Resolve customer
+// @Description Returns the stable customer known by a phone number or email, creating it on first contact
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Param request body ResolveCustomerRequest true "Identifier to resolve"
+// @Success 200 {object} customer.ResolveResult
+// @Success 201 {object} customer.ResolveResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/customers/resolve [post]
+func (h *CustomerHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format")
+		return
+	}
+
+	var req ResolveCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Identifier == "" {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_request", "identifier is required")
+		return
+	}
+
+	result, err := h.service.ResolveCustomer(r.Context(), tenantID, req.Identifier)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+	respondJSON(w, status, result)
+}
+
+// Get handles GET /api/v1/tenants/{id}/customers/{customer_id}
+// @Summary Get customer
+// @Tags customers
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Param customer_id path string true "Customer ID" format(uuid)
+// @Success 200 {object} customer.CustomerDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/customers/{customer_id} [get]
+func (h *CustomerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenantID, customerID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetCustomer(r.Context(), tenantID, customerID)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Merge handles POST /api/v1/tenants/{id}/customers/{customer_id}/merge
+// @Summary Merge duplicate customer
+// @Description Merges the source customer into this one; the source's identifiers resolve to this customer afterwards
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Param customer_id path string true "Customer ID kept" format(uuid)
+// @Param request body MergeCustomerRequest true "Duplicate to merge"
+// @Success 200 {object} customer.CustomerDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/customers/{customer_id}/merge [post]
+func (h *CustomerHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	tenantID, targetID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req MergeCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	sourceID, err := uuid.Parse(req.SourceID)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid source customer ID format")
+		return
+	}
+
+	result, err := h.service.MergeCustomers(r.Context(), tenantID, targetID, sourceID)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// parseIDs parses the tenant and customer IDs from the URL.
+func (h *CustomerHandler) parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	customerID, err := uuid.Parse(chi.URLParam(r, "customer_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid customer ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, customerID, true
+}
+
+// respondError sends an error response.
+func (h *CustomerHandler) respondError(w http.ResponseWriter, r *http.Request, status int, errCode, message string) {
+	respondJSON(w, status, ErrorResponse{
+		Error:   errCode,
+		Message: message,
+		TraceID: getRequestID(r.Context()),
+	})
+}
+
+// handleServiceError handles errors from the application service.
+func (h *CustomerHandler) handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.HTTPStatus() == http.StatusInternalServerError {
+		h.logger.Error("internal error", zap.Error(err))
+		h.respondError(w, r, http.StatusInternalServerError, string(apperrors.ErrInternal), "An internal error occurred")
+		return
+	}
+
+	h.respondError(w, r, appErr.HTTPStatus(), apperrors.ReasonOf(appErr), appErr.Message)
+}
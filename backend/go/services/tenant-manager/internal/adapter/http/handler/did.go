@@ -0,0 +1,343 @@
+// Package handler contains HTTP request handlers.
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/adapter/http/middleware"
+	"tenant-manager/internal/application/did"
+)
+
+// maxBulkImportBody caps the size of a bulk DID import request.
+const maxBulkImportBody = 1 << 20
+
+// DIDHandler handles DID registration HTTP requests.
+type DIDHandler struct {
+	service   *did.Service
+	logger    *zap.Logger
+	validator *validator.Validate
+}
+
+// NewDIDHandler creates a new DIDHandler.
+func NewDIDHandler(service *did.Service, logger *zap.Logger) *DIDHandler {
+	return &DIDHandler{
+		service:   service,
+		logger:    logger,
+		validator: validator.New(),
+	}
+}
+
+// BulkImportDIDsRequest represents the JSON request body for a bulk DID import.
+type BulkImportDIDsRequest struct {
+	Numbers []string `json:"numbers"`          // E.164 or national format of region
+	Region  string   `json:"region,omitempty"` // ISO 3166-1 alpha-2
+}
+
+// UpdateDIDRequest represents the request body for updating a DID. Omitted
+// fields are left as they are; an empty agent_id or flow_id clears it.
+type UpdateDIDRequest struct {
+	AgentID *string `json:"agent_id,omitempty" validate:"omitempty,max=64"`
+	FlowID  *string `json:"flow_id,omitempty" validate:"omitempty,max=64"`
+	Label   *string `json:"label,omitempty" validate:"omitempty,max=100"`
+	Enabled *bool   `json:"enabled,omitempty"`
+}
+
+// BulkImport handles POST /api/v1/telephony/dids/bulk
+// @Summary Bulk import DIDs
+// @Description Assigns a batch of numbers to the caller's tenant in one transaction and reports the outcome of each. The body is JSON, a CSV file (text/csv) or a multipart form with the CSV in "file"; CSV numbers are read from the first column and the region from the region query parameter.
+// @Tags telephony
+// @Accept json
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Param tenant_id query string false "Tenant to import into (superadmin only)" format(uuid)
+// @Param region query string false "Region of national-format numbers in a CSV"
+// @Param request body BulkImportDIDsRequest true "Numbers to import"
+// @Success 200 {object} did.BulkImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /api/v1/telephony/dids/bulk [post]
+func (h *DIDHandler) BulkImport(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantOf(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkImportBody)
+	req, err := readBulkImport(r)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.respondError(w, r, http.StatusRequestEntityTooLarge, "request_too_large", "Request body is too large")
+		return
+	}
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	result, err := h.service.BulkImport(r.Context(), tenantID, did.BulkImportCommand{
+		Numbers: req.Numbers,
+		Region:  req.Region,
+	})
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// List handles GET /api/v1/telephony/dids
+// @Summary List DIDs
+// @Description Lists the caller's tenant DIDs with the agent or flow each is assigned to
+// @Tags telephony
+// @Produce json
+// @Param tenant_id query string false "Tenant (superadmin only)" format(uuid)
+// @Success 200 {object} did.ListDIDsResult
+// @Failure 401 {object} ErrorResponse
+// @Router /api/v1/telephony/dids [get]
+func (h *DIDHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantOf(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.ListDIDs(r.Context(), tenantID)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Get handles GET /api/v1/telephony/dids/{number}
+// @Summary Get DID
+// @Tags telephony
+// @Produce json
+// @Param number path string true "DID in E.164"
+// @Param tenant_id query string false "Tenant (superadmin only)" format(uuid)
+// @Success 200 {object} did.DIDDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/telephony/dids/{number} [get]
+func (h *DIDHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantOf(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetDID(r.Context(), tenantID, chi.URLParam(r, "number"))
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Update handles PATCH /api/v1/telephony/dids/{number}
+// @Summary Update DID
+// @Description Assigns the DID to an agent or flow, labels it, or enables or disables it
+// @Tags telephony
+// @Accept json
+// @Produce json
+// @Param number path string true "DID in E.164"
+// @Param tenant_id query string false "Tenant (superadmin only)" format(uuid)
+// @Param request body UpdateDIDRequest true "Fields to change"
+// @Success 200 {object} did.DIDDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/telephony/dids/{number} [patch]
+func (h *DIDHandler) Update(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantOf(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateDIDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		respondJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: "Validation failed",
+			Details: validationErrors,
+			TraceID: getRequestID(r.Context()),
+		})
+		return
+	}
+
+	result, err := h.service.UpdateDID(r.Context(), tenantID, chi.URLParam(r, "number"), did.UpdateDIDCommand{
+		AgentID: req.AgentID,
+		FlowID:  req.FlowID,
+		Label:   req.Label,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Delete handles DELETE /api/v1/telephony/dids/{number}
+// @Summary Release DID
+// @Tags telephony
+// @Param number path string true "DID in E.164"
+// @Param tenant_id query string false "Tenant (superadmin only)" format(uuid)
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/telephony/dids/{number} [delete]
+func (h *DIDHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := h.tenantOf(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteDID(r.Context(), tenantID, chi.URLParam(r, "number")); err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Lookup handles GET /api/v1/telephony/dids/lookup/{number}
+// @Summary Look up DID
+// @Description Returns the tenant holding a DID and the agent or flow it is assigned to, for routing inbound calls
+// @Tags telephony
+// @Produce json
+// @Param number path string true "DID in E.164"
+// @Success 200 {object} did.DIDDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/telephony/dids/lookup/{number} [get]
+func (h *DIDHandler) Lookup(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.LookupDID(r.Context(), chi.URLParam(r, "number"))
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// tenantOf returns the tenant of the caller forwarded by the gateway. A
+// superadmin may name another tenant with the tenant_id query parameter.
+func (h *DIDHandler) tenantOf(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	raw := r.Header.Get("X-Tenant-ID")
+	if q := r.URL.Query().Get("tenant_id"); q != "" && r.Header.Get("X-User-Role") == middleware.RoleSuperadmin {
+		raw = q
+	}
+	if raw == "" {
+		h.respondError(w, r, http.StatusUnauthorized, "unauthorized", "Tenant identity is required")
+		return uuid.Nil, false
+	}
+	tenantID, err := uuid.Parse(raw)
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format")
+		return uuid.Nil, false
+	}
+	return tenantID, true
+}
+
+// readBulkImport reads the numbers of a JSON, CSV or multipart request.
+func readBulkImport(r *http.Request) (*BulkImportDIDsRequest, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return readNumbersCSV(r.Body, r.URL.Query().Get("region"))
+	case "multipart/form-data":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, errors.New("CSV file is required in the file field")
+		}
+		defer file.Close()
+		region := r.FormValue("region")
+		if region == "" {
+			region = r.URL.Query().Get("region")
+		}
+		return readNumbersCSV(file, region)
+	default:
+		var req BulkImportDIDsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, err
+			}
+			return nil, errors.New("Invalid request body")
+		}
+		return &req, nil
+	}
+}
+
+// readNumbersCSV reads numbers from the first column of a CSV, skipping
+// blank rows and a header row.
+func readNumbersCSV(body io.Reader, region string) (*BulkImportDIDsRequest, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	req := &BulkImportDIDsRequest{Region: region}
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, err
+			}
+			return nil, errors.New("Invalid CSV file")
+		}
+		number := strings.TrimSpace(record[0])
+		if number == "" || (line == 0 && !strings.ContainsFunc(number, unicode.IsDigit)) {
+			continue
+		}
+		req.Numbers = append(req.Numbers, number)
+	}
+	return req, nil
+}
+
+// respondError sends an error response.
+func (h *DIDHandler) respondError(w http.ResponseWriter, r *http.Request, status int, errCode, message string) {
+	respondJSON(w, status, ErrorResponse{
+		Error:   errCode,
+		Message: message,
+		TraceID: getRequestID(r.Context()),
+	})
+}
+
+// handleServiceError handles errors from the application service.
+func (h *DIDHandler) handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.HTTPStatus() == http.StatusInternalServerError {
+		h.logger.Error("internal error", zap.Error(err))
+		h.respondError(w, r, http.StatusInternalServerError, string(apperrors.ErrInternal), "An internal error occurred")
+		return
+	}
+
+	h.respondError(w, r, appErr.HTTPStatus(), apperrors.ReasonOf(appErr), appErr.Message)
+}
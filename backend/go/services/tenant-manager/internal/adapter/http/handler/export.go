@@ -0,0 +1,204 @@
+// Package handler contains HTTP request handlers.
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/application/export"
+	domainexport "tenant-manager/internal/domain/export"
+)
+
+// ExportHandler handles data-export (subject-access request) HTTP requests.
+type ExportHandler struct {
+	service *export.Service
+	logger  *zap.Logger
+}
+
+// NewExportHandler creates a new ExportHandler.
+func NewExportHandler(service *export.Service, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ExportRequest represents the request body for requesting a data export.
+type ExportRequest struct {
+	// CustomerID limits the export to one end customer of the tenant
+	CustomerID string `json:"customer_id,omitempty"`
+}
+
+// ExportResponse represents an export job in API responses.
+type ExportResponse struct {
+	*export.JobDTO
+	StatusURL   string `json:"status_url"`
+	DownloadURL string `json:"download_url,omitempty"` // set once completed
+}
+
+// Request handles POST /api/v1/tenants/{id}/export-request
+// @Summary Request data export
+// @Description Starts gathering the tenant's (or one customer's) data across services into a downloadable archive
+// @Tags exports
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Param request body ExportRequest false "Export request"
+// @Success 202 {object} ExportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/export-request [post]
+func (h *ExportHandler) Request(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format")
+		return
+	}
+
+	var req ExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+
+	cmd := export.RequestExportCommand{
+		TenantID:   tenantID,
+		CustomerID: req.CustomerID,
+	}
+	if actor, ok := audit.ActorFromContext(r.Context()); ok {
+		cmd.RequestedBy = actor.ID
+	}
+
+	result, err := h.service.RequestExport(r.Context(), cmd)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	resp := toExportResponse(result)
+	w.Header().Set("Location", resp.StatusURL)
+	respondJSON(w, http.StatusAccepted, resp)
+}
+
+// Get handles GET /api/v1/tenants/{id}/export-requests/{job_id}
+// @Summary Get data export
+// @Description Returns the status and progress of a data export
+// @Tags exports
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Param job_id path string true "Export job ID" format(uuid)
+// @Success 200 {object} ExportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/export-requests/{job_id} [get]
+func (h *ExportHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenantID, jobID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.GetExport(r.Context(), tenantID, jobID)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toExportResponse(result))
+}
+
+// Download handles GET /api/v1/tenants/{id}/export-requests/{job_id}/download
+// @Summary Download data export
+// @Description Streams the zip archive of a completed data export
+// @Tags exports
+// @Produce application/zip
+// @Param id path string true "Tenant ID" format(uuid)
+// @Param job_id path string true "Export job ID" format(uuid)
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/export-requests/{job_id}/download [get]
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	tenantID, jobID, ok := h.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	archive, err := h.service.OpenArchive(r.Context(), tenantID, jobID)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "export-"+jobID.String()+".zip"))
+	if _, err := io.Copy(w, archive); err != nil {
+		h.logger.Warn("failed to stream export archive", zap.String("job_id", jobID.String()), zap.Error(err))
+	}
+}
+
+// parseIDs parses the tenant and job IDs from the URL.
+func (h *ExportHandler) parseIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	jobID, err := uuid.Parse(chi.URLParam(r, "job_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid export ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, jobID, true
+}
+
+// respondError sends an error response.
+func (h *ExportHandler) respondError(w http.ResponseWriter, r *http.Request, status int, errCode, message string) {
+	respondJSON(w, status, ErrorResponse{
+		Error:   errCode,
+		Message: message,
+		TraceID: getRequestID(r.Context()),
+	})
+}
+
+// handleServiceError handles errors from the application service.
+func (h *ExportHandler) handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.HTTPStatus() == http.StatusInternalServerError {
+		h.logger.Error("internal error", zap.Error(err))
+		h.respondError(w, r, http.StatusInternalServerError, string(apperrors.ErrInternal), "An internal error occurred")
+		return
+	}
+
+	h.respondError(w, r, appErr.HTTPStatus(), apperrors.ReasonOf(appErr), appErr.Message)
+}
+
+// toExportResponse adds the job's links to its DTO.
+func toExportResponse(j *export.JobDTO) *ExportResponse {
+	statusURL := fmt.Sprintf("/api/v1/tenants/%s/export-requests/%s", j.TenantID, j.ID)
+	resp := &ExportResponse{JobDTO: j, StatusURL: statusURL}
+	if j.Status == string(domainexport.StatusCompleted) {
+		resp.DownloadURL = statusURL + "/download"
+	}
+	return resp
+}
+
+// respondJSON sends a JSON response.
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if data != nil {
+		json.NewEncoder(w).Encode(data)
+	}
+}
@@ -5,66 +5,34 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/health"
 )
 
 // HealthHandler handles health check requests.
 type HealthHandler struct {
-	db    *pgxpool.Pool
-	redis *redis.Client
+	checker *health.Checker
 }
 
-// NewHealthHandler creates a new HealthHandler.
-func NewHealthHandler(db *pgxpool.Pool, redis *redis.Client) *HealthHandler {
-	return &HealthHandler{
-		db:    db,
-		redis: redis,
-	}
-}
-
-// HealthResponse represents the health check response.
-type HealthResponse struct {
-	Status   string            `json:"status"`
-	Services map[string]string `json:"services,omitempty"`
+// NewHealthHandler creates a new HealthHandler. The database is critical;
+// Redis only backs the tenant cache and Kafka only carries events, so their
+// failure degrades the service without taking it out of rotation.
+func NewHealthHandler(db *pgxpool.Pool, redis *redis.Client, kafkaBrokers []string) *HealthHandler {
+	checker := health.NewChecker("tenant-manager")
+	checker.Register("database", health.PingCheck(db), health.Critical())
+	checker.Register("redis", func(ctx context.Context) error {
+		return redis.Ping(ctx).Err()
+	})
+	checker.Register("kafka", health.DialCheck(kafkaBrokers...))
+
+	return &HealthHandler{checker: checker}
 }
 
 // Health handles GET /health
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-
-	response := HealthResponse{
-		Status:   "healthy",
-		Services: make(map[string]string),
-	}
-
-	// Check database
-	if err := h.db.Ping(ctx); err != nil {
-		response.Status = "unhealthy"
-		response.Services["database"] = "down"
-	} else {
-		response.Services["database"] = "up"
-	}
-
-	// Check Redis
-	if err := h.redis.Ping(ctx).Err(); err != nil {
-		response.Status = "unhealthy"
-		response.Services["redis"] = "down"
-	} else {
-		response.Services["redis"] = "up"
-	}
-
-	status := http.StatusOK
-	if response.Status == "unhealthy" {
-		status = http.StatusServiceUnavailable
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(response)
+	h.checker.ServeHTTP(w, r)
 }
 
 // Live handles GET /health/live
@@ -76,5 +44,5 @@ func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
 
 // Ready handles GET /health/ready
 func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
-	h.Health(w, r)
+	h.checker.ServeHTTP(w, r)
 }
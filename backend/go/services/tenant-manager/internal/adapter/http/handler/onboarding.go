@@ -0,0 +1,140 @@
+// Package handler contains HTTP request handlers.
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/application/onboarding"
+	"tenant-manager/internal/application/tenant"
+)
+
+// IdempotencyKeyHeader carries the key that makes a retried onboarding
+// request return the first one's outcome.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// OnboardingHandler handles tenant onboarding HTTP requests.
+type OnboardingHandler struct {
+	service   *onboarding.Service
+	logger    *zap.Logger
+	validator *validator.Validate
+}
+
+// NewOnboardingHandler creates a new OnboardingHandler.
+func NewOnboardingHandler(service *onboarding.Service, logger *zap.Logger) *OnboardingHandler {
+	return &OnboardingHandler{
+		service:   service,
+		logger:    logger,
+		validator: validator.New(),
+	}
+}
+
+// OnboardRequest represents the request body for onboarding a tenant.
+type OnboardRequest struct {
+	Tenant      CreateTenantRequest        `json:"tenant"`
+	Quota       *onboarding.QuotaOverrides `json:"quota,omitempty"`
+	PhoneNumber string                     `json:"phone_number,omitempty"`                            // E.164 or national format of phone_region
+	PhoneRegion string                     `json:"phone_region,omitempty" validate:"omitempty,len=2"` // ISO 3166-1 alpha-2
+	Admin       struct {
+		Email string `json:"email,omitempty" validate:"omitempty,email"` // defaults to the tenant email
+		Name  string `json:"name,omitempty"`
+	} `json:"admin"`
+}
+
+// Onboard handles POST /api/v1/onboarding
+// @Summary Onboard tenant
+// @Description Creates a tenant with its quota, billing customer, phone number and admin user, undoing what was created if a step fails
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Key that makes retries return the first outcome"
+// @Param request body OnboardRequest true "Onboarding request"
+// @Success 201 {object} onboarding.OnboardingDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/onboarding [post]
+func (h *OnboardingHandler) Onboard(w http.ResponseWriter, r *http.Request) {
+	var req OnboardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_request", "Invalid JSON body", nil)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		h.respondError(w, r, http.StatusBadRequest, "validation_error", "Validation failed", validationErrors)
+		return
+	}
+
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		key = uuid.NewString()
+	}
+
+	cmd := onboarding.OnboardCommand{
+		IdempotencyKey: key,
+		Tenant: tenant.CreateTenantCommand{
+			Name:         req.Tenant.Name,
+			Email:        req.Tenant.Email,
+			Phone:        req.Tenant.Phone,
+			Plan:         req.Tenant.Plan,
+			BillingEmail: req.Tenant.BillingEmail,
+			Industry:     req.Tenant.Metadata.Industry,
+			CompanySize:  req.Tenant.Metadata.CompanySize,
+			Website:      req.Tenant.Metadata.Website,
+		},
+		Quota:       req.Quota,
+		PhoneNumber: req.PhoneNumber,
+		PhoneRegion: req.PhoneRegion,
+		Admin: onboarding.AdminCommand{
+			Email: req.Admin.Email,
+			Name:  req.Admin.Name,
+		},
+	}
+
+	result, err := h.service.Onboard(r.Context(), cmd)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	h.logger.Info("tenant onboarded",
+		zap.String("onboarding_id", result.ID.String()),
+		zap.String("tenant_id", result.Resources.TenantID.String()),
+		zap.String("request_id", getRequestID(r.Context())),
+	)
+
+	w.Header().Set(IdempotencyKeyHeader, key)
+	respondJSON(w, http.StatusCreated, result)
+}
+
+// respondError sends an error response.
+func (h *OnboardingHandler) respondError(w http.ResponseWriter, r *http.Request, status int, errCode, message string, details map[string]string) {
+	respondJSON(w, status, ErrorResponse{
+		Error:   errCode,
+		Message: message,
+		Details: details,
+		TraceID: getRequestID(r.Context()),
+	})
+}
+
+// handleServiceError handles errors from the application service.
+func (h *OnboardingHandler) handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.HTTPStatus() == http.StatusInternalServerError {
+		h.logger.Error("internal error", zap.Error(err))
+		h.respondError(w, r, http.StatusInternalServerError, string(apperrors.ErrInternal), "An internal error occurred", nil)
+		return
+	}
+
+	h.respondError(w, r, appErr.HTTPStatus(), apperrors.ReasonOf(appErr), appErr.Message, nil)
+}
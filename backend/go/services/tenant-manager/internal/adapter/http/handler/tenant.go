@@ -6,16 +6,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	pagination "github.com/serphona/serphona/backend/go/libs/platform-pagination"
 	"go.uber.org/zap"
 
-	apperrors "tenant-manager/pkg/errors"
-
 	"tenant-manager/internal/application/tenant"
+	domain "tenant-manager/internal/domain/tenant"
 )
 
 // TenantHandler handles tenant-related HTTP requests.
@@ -55,6 +61,18 @@ type UpdateTenantRequest struct {
 	Email        *string `json:"email,omitempty" validate:"omitempty,email"`
 	Phone        *string `json:"phone,omitempty" validate:"omitempty,e164"`
 	BillingEmail *string `json:"billing_email,omitempty" validate:"omitempty,email"`
+	Plan         *string `json:"plan,omitempty" validate:"omitempty,oneof=starter professional enterprise"`
+
+	DataResidency *string `json:"data_residency,omitempty" validate:"omitempty,oneof=us eu br"`
+
+	// Validated by the application service, including the rule patterns
+	Redaction *redact.Policy `json:"redaction,omitempty"`
+
+	// Validated by the application service
+	TokenClaims *domain.TokenClaims `json:"token_claims,omitempty"`
+
+	// Validated by the application service, including timezone and ranges
+	BusinessHours *domain.BusinessHours `json:"business_hours,omitempty"`
 }
 
 // TenantResponse represents the response for tenant operations.
@@ -81,13 +99,17 @@ type ErrorResponse struct {
 	TraceID string            `json:"trace_id,omitempty"`
 }
 
-// ListTenantsResponse represents the response for listing tenants.
+// ListTenantsResponse represents the response for listing tenants. It keeps
+// the tenants key and always-present page fields this endpoint had before
+// the shared pagination envelope; next_cursor is only set when paginating by
+// cursor.
 type ListTenantsResponse struct {
 	Tenants    []TenantResponse `json:"tenants"`
 	Total      int64            `json:"total"`
 	Page       int              `json:"page"`
 	PageSize   int              `json:"page_size"`
 	TotalPages int              `json:"total_pages"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 // Create handles POST /api/v1/tenants
@@ -182,6 +204,73 @@ func (h *TenantHandler) Get(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, toTenantResponse(result))
 }
 
+// EffectiveSettings handles GET /api/v1/tenants/{id}/settings/effective
+// @Summary Get effective tenant settings
+// @Description Returns the complete settings a tenant runs with: its own settings over its plan defaults over the platform defaults
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Success 200 {object} tenant.EffectiveSettingsDTO
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/settings/effective [get]
+func (h *TenantHandler) EffectiveSettings(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format", nil)
+		return
+	}
+
+	result, err := h.service.GetEffectiveSettings(r.Context(), tenantID)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// ReserveUsageRequest represents the request body for reserving usage.
+type ReserveUsageRequest struct {
+	Calls   int `json:"calls"`
+	Minutes int `json:"minutes"`
+}
+
+// ReserveUsage handles POST /api/v1/tenants/{id}/usage/reserve
+// @Summary Reserve usage
+// @Description Atomically adds calls and minutes to the tenant's monthly usage if they fit its quota; refused reservations leave usage unchanged
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Param request body ReserveUsageRequest true "Usage to reserve"
+// @Success 200 {object} domain.Reservation
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/usage/reserve [post]
+func (h *TenantHandler) ReserveUsage(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format", nil)
+		return
+	}
+
+	var req ReserveUsageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_request", "Invalid JSON body", nil)
+		return
+	}
+
+	result, err := h.service.ReserveUsage(r.Context(), tenantID, req.Calls, req.Minutes)
+	if err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
 // Update handles PUT /api/v1/tenants/{id}
 // @Summary Update tenant
 // @Description Updates an existing tenant
@@ -226,11 +315,16 @@ func (h *TenantHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Update tenant via application service
 	cmd := tenant.UpdateTenantCommand{
-		ID:           tenantID,
-		Name:         req.Name,
-		Email:        req.Email,
-		Phone:        req.Phone,
-		BillingEmail: req.BillingEmail,
+		ID:            tenantID,
+		Name:          req.Name,
+		Email:         req.Email,
+		Phone:         req.Phone,
+		BillingEmail:  req.BillingEmail,
+		Plan:          req.Plan,
+		DataResidency: req.DataResidency,
+		Redaction:     req.Redaction,
+		TokenClaims:   req.TokenClaims,
+		BusinessHours: req.BusinessHours,
 	}
 
 	result, err := h.service.UpdateTenant(ctx, cmd)
@@ -284,13 +378,69 @@ func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Suspend handles POST /api/v1/tenants/{id}/suspend
+// @Summary Suspend tenant
+// @Description Suspends a tenant; the action is recorded in the audit log
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/suspend [post]
+func (h *TenantHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, "suspended", h.service.SuspendTenant)
+}
+
+// Activate handles POST /api/v1/tenants/{id}/activate
+// @Summary Activate tenant
+// @Description Activates a tenant; the action is recorded in the audit log
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID" format(uuid)
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants/{id}/activate [post]
+func (h *TenantHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, "activated", h.service.ActivateTenant)
+}
+
+// changeStatus parses the tenant ID and applies a status transition.
+func (h *TenantHandler) changeStatus(w http.ResponseWriter, r *http.Request, verb string, apply func(context.Context, uuid.UUID) error) {
+	ctx := r.Context()
+
+	tenantID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_id", "Invalid tenant ID format", nil)
+		return
+	}
+
+	if err := apply(ctx, tenantID); err != nil {
+		h.handleServiceError(w, r, err)
+		return
+	}
+
+	h.logger.Info("tenant "+verb,
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("request_id", getRequestID(ctx)),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // List handles GET /api/v1/tenants
 // @Summary List tenants
 // @Description Lists tenants with pagination
 // @Tags tenants
 // @Produce json
 // @Param page query int false "Page number" default(1)
-// @Param page_size query int false "Page size" default(20)
+// @Param page_size query int false "Page size (max 100)" default(20)
+// @Param cursor query string false "Cursor from next_cursor; excludes page"
+// @Param sort_by query string false "name, email, created_at, updated_at or status" default(created_at)
+// @Param sort_order query string false "asc or desc" default(desc)
 // @Param status query string false "Filter by status"
 // @Param search query string false "Search in name/email"
 // @Success 200 {object} ListTenantsResponse
@@ -301,19 +451,16 @@ func (h *TenantHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Parse query parameters
-	query := tenant.ListTenantsQuery{
-		Page:     parseIntQuery(r, "page", 1),
-		PageSize: parseIntQuery(r, "page_size", 20),
-		Status:   r.URL.Query().Get("status"),
-		Search:   r.URL.Query().Get("search"),
+	page, err := pagination.ParseRequest(lenientPageQuery(r.URL.Query()), pagination.Options{DefaultSort: "created_at"})
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, "invalid_pagination", err.Error(), nil)
+		return
 	}
 
-	// Validate pagination
-	if query.PageSize < 1 || query.PageSize > 100 {
-		query.PageSize = 20
-	}
-	if query.Page < 1 {
-		query.Page = 1
+	query := tenant.ListTenantsQuery{
+		Page:   page,
+		Status: r.URL.Query().Get("status"),
+		Search: r.URL.Query().Get("search"),
 	}
 
 	// List tenants via application service
@@ -324,8 +471,8 @@ func (h *TenantHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build response
-	tenants := make([]TenantResponse, len(result.Tenants))
-	for i, t := range result.Tenants {
+	tenants := make([]TenantResponse, len(result.Items))
+	for i, t := range result.Items {
 		tenants[i] = *toTenantResponse(t)
 	}
 
@@ -335,11 +482,26 @@ func (h *TenantHandler) List(w http.ResponseWriter, r *http.Request) {
 		Page:       result.Page,
 		PageSize:   result.PageSize,
 		TotalPages: result.TotalPages,
+		NextCursor: result.NextCursor,
 	}
 
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// lenientPageQuery drops a page or page_size that is not a number in range,
+// so they fall back to their defaults as this endpoint always did instead of
+// failing the request.
+func lenientPageQuery(query url.Values) url.Values {
+	query = maps.Clone(query)
+	if page, err := strconv.Atoi(query.Get("page")); err != nil || page < 1 {
+		query.Del("page")
+	}
+	if size, err := strconv.Atoi(query.Get("page_size")); err != nil || size < 1 || size > pagination.MaxPageSize {
+		query.Del("page_size")
+	}
+	return query
+}
+
 // respondJSON sends a JSON response.
 func (h *TenantHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -362,28 +524,27 @@ func (h *TenantHandler) respondError(w http.ResponseWriter, r *http.Request, sta
 
 // handleServiceError handles errors from the application service.
 func (h *TenantHandler) handleServiceError(w http.ResponseWriter, r *http.Request, err error) {
-	var appErr *apperrors.AppError
-	if errors.As(err, &appErr) {
-		switch appErr.Code {
-		case apperrors.ErrNotFound:
-			h.respondError(w, r, http.StatusNotFound, "not_found", appErr.Message, nil)
-		case apperrors.ErrConflict:
-			h.respondError(w, r, http.StatusConflict, "conflict", appErr.Message, nil)
-		case apperrors.ErrValidation:
-			h.respondError(w, r, http.StatusBadRequest, "validation_error", appErr.Message, nil)
-		case apperrors.ErrUnauthorized:
-			h.respondError(w, r, http.StatusUnauthorized, "unauthorized", appErr.Message, nil)
-		case apperrors.ErrForbidden:
-			h.respondError(w, r, http.StatusForbidden, "forbidden", appErr.Message, nil)
-		default:
-			h.logger.Error("internal error", zap.Error(err))
-			h.respondError(w, r, http.StatusInternalServerError, "internal_error", "An internal error occurred", nil)
-		}
+	appErr, ok := apperrors.As(err)
+	if !ok || appErr.HTTPStatus() == http.StatusInternalServerError {
+		h.logger.Error("internal error", zap.Error(err))
+		h.respondError(w, r, http.StatusInternalServerError, string(apperrors.ErrInternal), "An internal error occurred", nil)
 		return
 	}
 
-	h.logger.Error("unexpected error", zap.Error(err))
-	h.respondError(w, r, http.StatusInternalServerError, "internal_error", "An internal error occurred", nil)
+	h.respondError(w, r, appErr.HTTPStatus(), apperrors.ReasonOf(appErr), appErr.Message, planBlockers(err))
+}
+
+// planBlockers lists, by limit, what keeps a tenant from changing plans.
+func planBlockers(err error) map[string]string {
+	var blocked *domain.PlanChangeError
+	if !errors.As(err, &blocked) {
+		return nil
+	}
+	details := make(map[string]string, len(blocked.Blockers))
+	for _, b := range blocked.Blockers {
+		details[string(b.Limit)] = fmt.Sprintf("using %g, %s plan allows %d", b.Used, blocked.To, b.Max)
+	}
+	return details
 }
 
 // toTenantResponse converts a domain tenant to a response DTO.
@@ -405,22 +566,7 @@ func toTenantResponse(t *tenant.TenantDTO) *TenantResponse {
 // Helper functions
 
 func getRequestID(ctx context.Context) string {
-	if id, ok := ctx.Value("request_id").(string); ok {
-		return id
-	}
-	return ""
-}
-
-func parseIntQuery(r *http.Request, key string, defaultVal int) int {
-	val := r.URL.Query().Get(key)
-	if val == "" {
-		return defaultVal
-	}
-	var result int
-	if _, err := fmt.Sscanf(val, "%d", &result); err != nil {
-		return defaultVal
-	}
-	return result
+	return requestid.FromContext(ctx)
 }
 
 func getValidationMessage(fe validator.FieldError) string {
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/application/tenant"
+	domain "tenant-manager/internal/domain/tenant"
+	"tenant-manager/internal/domain/tenant/tenanttest"
+)
+
+func newListHandler(t *testing.T, names ...string) *TenantHandler {
+	t.Helper()
+
+	var seed []*domain.Tenant
+	for _, name := range names {
+		seed = append(seed, domain.NewTenant(name, name+"@example.com", domain.PlanStarter))
+	}
+	svc := tenant.NewService(tenanttest.NewRepository(seed...), nil, tenanttest.NewCache(), tenanttest.NewEventPublisher(), nil, zap.NewNop())
+	return NewTenantHandler(svc, zap.NewNop())
+}
+
+func listTenants(t *testing.T, h *TenantHandler, query string) (int, map[string]json.RawMessage) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.List(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tenants"+query, nil))
+
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	return rec.Code, body
+}
+
+func TestList_KeepsResponseShape(t *testing.T) {
+	h := newListHandler(t, "acme", "globex", "initech")
+
+	code, body := listTenants(t, h, "?page=2&page_size=2")
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	var tenants []TenantResponse
+	if err := json.Unmarshal(body["tenants"], &tenants); err != nil || len(tenants) != 1 {
+		t.Errorf("expected 1 tenant under tenants, got %s (%v)", body["tenants"], err)
+	}
+	for key, want := range map[string]string{"total": "3", "page": "2", "page_size": "2", "total_pages": "2"} {
+		if got := string(body[key]); got != want {
+			t.Errorf("%s = %s, want %s", key, got, want)
+		}
+	}
+	if _, ok := body["items"]; ok {
+		t.Error("unexpected items key")
+	}
+}
+
+func TestList_InvalidPagingFallsBackToDefaults(t *testing.T) {
+	h := newListHandler(t)
+
+	for _, query := range []string{"?page=0", "?page=abc", "?page_size=0", "?page_size=500", "?page_size=x"} {
+		code, body := listTenants(t, h, query)
+		if code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", query, code)
+			continue
+		}
+		if string(body["page"]) != "1" || string(body["page_size"]) != "20" || string(body["total_pages"]) != "0" {
+			t.Errorf("%s: expected page 1 of 20 with no pages, got page %s page_size %s total_pages %s",
+				query, body["page"], body["page_size"], body["total_pages"])
+		}
+	}
+}
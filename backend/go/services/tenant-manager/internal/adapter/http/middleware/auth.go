@@ -2,9 +2,16 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
+
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
 )
 
+// RoleSuperadmin is the platform operator role.
+const RoleSuperadmin = "superadmin"
+
 // AuthMiddleware handles JWT authentication.
 type AuthMiddleware struct {
 	secret string
@@ -22,7 +29,44 @@ func NewAuthMiddleware(secret, issuer string) *AuthMiddleware {
 // Handle is the middleware handler function.
 func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// For now, just pass through - implement JWT validation here
-		next.ServeHTTP(w, r)
+		// For now, just pass through - implement JWT validation here.
+		// Until then, the identity forwarded by the gateway attributes audit entries.
+		ctx := r.Context()
+		if userID := r.Header.Get("X-User-ID"); userID != "" {
+			ctx = audit.WithActor(ctx, audit.Actor{
+				ID:       userID,
+				Role:     r.Header.Get("X-User-Role"),
+				TenantID: r.Header.Get("X-Tenant-ID"),
+			})
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireRole lets through only requests whose identity, forwarded by the
+// gateway, has the given role.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-User-ID") == "" {
+				respondAuthError(w, apperrors.NewUnauthorizedError("User identity is required"))
+				return
+			}
+			if r.Header.Get("X-User-Role") != role {
+				respondAuthError(w, apperrors.NewForbiddenError("Role "+role+" is required"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// respondAuthError sends an authentication or authorization error.
+func respondAuthError(w http.ResponseWriter, err *apperrors.AppError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.HTTPStatus())
+	json.NewEncoder(w).Encode(map[string]string{
+		"error":   apperrors.ReasonOf(err),
+		"message": err.Message,
 	})
 }
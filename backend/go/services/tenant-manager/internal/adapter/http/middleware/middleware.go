@@ -5,9 +5,13 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"tenant-manager/internal/application/tenant"
 )
@@ -74,7 +78,8 @@ func (m *RecoveryMiddleware) Handle(next http.Handler) http.Handler {
 	})
 }
 
-// CorrelationMiddleware adds correlation ID to requests.
+// CorrelationMiddleware puts the X-Request-ID correlation ID on the request
+// context, generating one when absent, and echoes it in the response.
 type CorrelationMiddleware struct{}
 
 // NewCorrelationMiddleware creates a new correlation middleware.
@@ -85,13 +90,7 @@ func NewCorrelationMiddleware() *CorrelationMiddleware {
 // Handle is the middleware handler function.
 func (m *CorrelationMiddleware) Handle(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestID := r.Header.Get("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
-		}
-		ctx := context.WithValue(r.Context(), "request_id", requestID)
-		w.Header().Set("X-Request-ID", requestID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, requestid.Bind(w, r))
 	})
 }
 
@@ -116,11 +115,43 @@ func GRPCRecoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
-// GRPCCorrelationInterceptor adds correlation ID to gRPC requests.
+// GRPCErrorInterceptor maps application errors returned by gRPC handlers to
+// gRPC status codes. Internal errors are logged and their details are not exposed.
+func GRPCErrorInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return resp, grpcError(logger, info.FullMethod, err)
+	}
+}
+
+// grpcError converts an error into a gRPC status error.
+// Errors that already carry a gRPC status are returned unchanged.
+func grpcError(logger *zap.Logger, method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codes.Code(apperrors.GRPCStatus(err))
+	if code == codes.Internal {
+		logger.Error("gRPC request failed", zap.String("method", method), zap.Error(err))
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	appErr, _ := apperrors.As(err)
+	return status.Error(code, appErr.Message)
+}
+
+// GRPCCorrelationInterceptor puts the x-request-id metadata of gRPC requests
+// on the context, generating an ID when absent.
 func GRPCCorrelationInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		requestID := uuid.New().String()
-		ctx = context.WithValue(ctx, "request_id", requestID)
-		return handler(ctx, req)
+		requestID := requestid.New()
+		if values := metadata.ValueFromIncomingContext(ctx, "x-request-id"); len(values) > 0 && values[0] != "" {
+			requestID = values[0]
+		}
+		return handler(requestid.WithID(ctx, requestID), req)
 	}
 }
@@ -5,18 +5,29 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo"
 
 	httphandler "tenant-manager/internal/adapter/http/handler"
+	"tenant-manager/internal/adapter/http/middleware"
 )
 
 // Config holds router configuration.
 type Config struct {
-	healthHandler    *httphandler.HealthHandler
-	tenantHandler    *httphandler.TenantHandler
-	apiKeyHandler    *httphandler.APIKeyHandler
-	middlewares      []func(http.Handler) http.Handler
-	authMiddleware   func(http.Handler) http.Handler
-	tenantMiddleware func(http.Handler) http.Handler
+	healthHandler     *httphandler.HealthHandler
+	tenantHandler     *httphandler.TenantHandler
+	apiKeyHandler     *httphandler.APIKeyHandler
+	exportHandler     *httphandler.ExportHandler
+	customerHandler   *httphandler.CustomerHandler
+	onboardingHandler *httphandler.OnboardingHandler
+	didHandler        *httphandler.DIDHandler
+	auditHandler      http.Handler
+	logLevelHandler   http.Handler
+	metricsHandler    http.Handler
+	bodyLimit         *bodylimit.Config
+	middlewares       []func(http.Handler) http.Handler
+	authMiddleware    func(http.Handler) http.Handler
+	tenantMiddleware  func(http.Handler) http.Handler
 }
 
 // Option is a router configuration option.
@@ -43,6 +54,65 @@ func WithAPIKeyHandler(h *httphandler.APIKeyHandler) Option {
 	}
 }
 
+// WithExportHandler sets the data export handler.
+func WithExportHandler(h *httphandler.ExportHandler) Option {
+	return func(c *Config) {
+		c.exportHandler = h
+	}
+}
+
+// WithCustomerHandler sets the customer identity handler.
+func WithCustomerHandler(h *httphandler.CustomerHandler) Option {
+	return func(c *Config) {
+		c.customerHandler = h
+	}
+}
+
+// WithOnboardingHandler sets the tenant onboarding handler.
+func WithOnboardingHandler(h *httphandler.OnboardingHandler) Option {
+	return func(c *Config) {
+		c.onboardingHandler = h
+	}
+}
+
+// WithDIDHandler sets the DID registration handler.
+func WithDIDHandler(h *httphandler.DIDHandler) Option {
+	return func(c *Config) {
+		c.didHandler = h
+	}
+}
+
+// WithAuditHandler sets the audit log query handler.
+func WithAuditHandler(h http.Handler) Option {
+	return func(c *Config) {
+		c.auditHandler = h
+	}
+}
+
+// WithLogLevelHandler sets the runtime log level handler, served to
+// superadmins only.
+func WithLogLevelHandler(h http.Handler) Option {
+	return func(c *Config) {
+		c.logLevelHandler = h
+	}
+}
+
+// WithMetricsHandler sets the Prometheus metrics handler, served at /metrics.
+// Its registry should hold buildinfo.Register's serphona_build_info.
+func WithMetricsHandler(h http.Handler) Option {
+	return func(c *Config) {
+		c.metricsHandler = h
+	}
+}
+
+// WithBodyLimit refuses request bodies above the configured limits with 413,
+// before any other middleware or handler reads them.
+func WithBodyLimit(limits bodylimit.Config) Option {
+	return func(c *Config) {
+		c.bodyLimit = &limits
+	}
+}
+
 // WithMiddleware adds global middleware.
 func WithMiddleware(mw ...func(http.Handler) http.Handler) Option {
 	return func(c *Config) {
@@ -73,6 +143,10 @@ func New(opts ...Option) http.Handler {
 
 	r := chi.NewRouter()
 
+	if cfg.bodyLimit != nil {
+		r.Use(bodylimit.New(*cfg.bodyLimit).Middleware)
+	}
+
 	// Apply global middlewares
 	for _, mw := range cfg.middlewares {
 		r.Use(mw)
@@ -85,6 +159,17 @@ func New(opts ...Option) http.Handler {
 		r.Get("/health/ready", cfg.healthHandler.Ready)
 	}
 
+	// Build version (no auth required)
+	r.Method(http.MethodGet, "/version", buildinfo.Handler("tenant-manager"))
+	if cfg.metricsHandler != nil {
+		r.Method(http.MethodGet, "/metrics", cfg.metricsHandler)
+	}
+
+	// Runtime log level for incidents
+	if cfg.logLevelHandler != nil {
+		r.With(middleware.RequireRole(middleware.RoleSuperadmin)).Method(http.MethodPut, "/admin/log-level", cfg.logLevelHandler)
+	}
+
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Tenant routes
@@ -93,8 +178,43 @@ func New(opts ...Option) http.Handler {
 				r.Get("/", cfg.tenantHandler.List)
 				r.Post("/", cfg.tenantHandler.Create)
 				r.Get("/{id}", cfg.tenantHandler.Get)
+				r.Get("/{id}/settings/effective", cfg.tenantHandler.EffectiveSettings)
 				r.Put("/{id}", cfg.tenantHandler.Update)
 				r.Delete("/{id}", cfg.tenantHandler.Delete)
+				r.Post("/{id}/suspend", cfg.tenantHandler.Suspend)
+				r.Post("/{id}/activate", cfg.tenantHandler.Activate)
+				r.Post("/{id}/usage/reserve", cfg.tenantHandler.ReserveUsage)
+
+				// Data exports (subject-access requests)
+				if cfg.exportHandler != nil {
+					r.Post("/{id}/export-request", cfg.exportHandler.Request)
+					r.Get("/{id}/export-requests/{job_id}", cfg.exportHandler.Get)
+					r.Get("/{id}/export-requests/{job_id}/download", cfg.exportHandler.Download)
+				}
+
+				// End-customer identities
+				if cfg.customerHandler != nil {
+					r.Post("/{id}/customers/resolve", cfg.customerHandler.Resolve)
+					r.Get("/{id}/customers/{customer_id}", cfg.customerHandler.Get)
+					r.Post("/{id}/customers/{customer_id}/merge", cfg.customerHandler.Merge)
+				}
+			})
+		}
+
+		// Onboarding (tenant with billing, phone number and admin user)
+		if cfg.onboardingHandler != nil {
+			r.Post("/onboarding", cfg.onboardingHandler.Onboard)
+		}
+
+		// DID registration and assignment
+		if cfg.didHandler != nil {
+			r.Route("/telephony/dids", func(r chi.Router) {
+				r.Get("/", cfg.didHandler.List)
+				r.Post("/bulk", cfg.didHandler.BulkImport)
+				r.Get("/lookup/{number}", cfg.didHandler.Lookup)
+				r.Get("/{number}", cfg.didHandler.Get)
+				r.Patch("/{number}", cfg.didHandler.Update)
+				r.Delete("/{number}", cfg.didHandler.Delete)
 			})
 		}
 
@@ -105,6 +225,11 @@ func New(opts ...Option) http.Handler {
 				r.Post("/", cfg.apiKeyHandler.Create)
 			})
 		}
+
+		// Audit log (compliance review)
+		if cfg.auditHandler != nil {
+			r.Method(http.MethodGet, "/audit", cfg.auditHandler)
+		}
 	})
 
 	return r
@@ -0,0 +1,84 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loglevel"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func putLogLevel(h http.Handler, role string) int {
+	req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"debug","ttl":"15m"}`))
+	req.Header.Set("X-User-ID", "ops-1")
+	req.Header.Set("X-User-Role", role)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestLogLevelRoute(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	core, logs := observer.New(level)
+	logger := zap.New(core)
+	r := New(WithLogLevelHandler(loglevel.New(level, zap.NewNop())))
+
+	if code := putLogLevel(r, "admin"); code != http.StatusForbidden {
+		t.Errorf("expected 403 for a tenant admin, got %d", code)
+	}
+	logger.Debug("before")
+
+	if code := putLogLevel(r, "superadmin"); code != http.StatusOK {
+		t.Fatalf("expected 200 for a superadmin, got %d", code)
+	}
+	logger.Debug("after")
+
+	if logs.FilterMessage("before").Len() != 0 {
+		t.Error("expected no debug log before the level changed")
+	}
+	if logs.FilterMessage("after").Len() != 1 {
+		t.Error("expected debug logs after the level changed")
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	r := New(
+		WithBodyLimit(bodylimit.Config{MaxBytes: 16}),
+		WithLogLevelHandler(loglevel.New(level, zap.NewNop())),
+	)
+
+	if code := putLogLevel(r, "superadmin"); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for a body above the limit, got %d", code)
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("expected the handler not to run, level changed to %v", level.Level())
+	}
+}
+
+func TestVersionRoute(t *testing.T) {
+	version, commit := buildinfo.Version, buildinfo.Commit
+	buildinfo.Version, buildinfo.Commit = "2.3.1", "f00ba47"
+	t.Cleanup(func() { buildinfo.Version, buildinfo.Commit = version, commit })
+
+	rec := httptest.NewRecorder()
+	New().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var info buildinfo.Info
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if info.Service != "tenant-manager" || info.Version != "2.3.1" || info.Commit != "f00ba47" || info.GoVersion == "" {
+		t.Errorf("unexpected build info: %+v", info)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"tenant-manager/internal/domain/onboarding"
 	"tenant-manager/internal/domain/tenant"
 )
 
@@ -60,6 +61,32 @@ func (p *EventPublisher) PublishSettingsUpdated(ctx context.Context, tenantID uu
 	return p.publishEvent("tenant.settings.updated", tenantID.String(), event)
 }
 
+// PublishPlanChanged publishes a plan changed event.
+func (p *EventPublisher) PublishPlanChanged(ctx context.Context, t *tenant.Tenant, previous tenant.Plan) error {
+	event := map[string]interface{}{
+		"tenant_id":     t.ID.String(),
+		"previous_plan": previous,
+		"plan":          t.Plan,
+	}
+	return p.publishEvent("tenant.plan_changed", t.ID.String(), event)
+}
+
+// PublishQuotaThresholdReached publishes a quota threshold reached event.
+func (p *EventPublisher) PublishQuotaThresholdReached(ctx context.Context, threshold tenant.QuotaThreshold) error {
+	return p.publishEvent("quota.threshold_reached", threshold.TenantID.String(), threshold)
+}
+
+// PublishOnboarded publishes a tenant onboarded event with the IDs of the
+// resources created for the tenant.
+func (p *EventPublisher) PublishOnboarded(ctx context.Context, o *onboarding.Onboarding) error {
+	event := map[string]interface{}{
+		"onboarding_id": o.ID.String(),
+		"tenant_id":     o.Resources.TenantID.String(),
+		"resources":     o.Resources,
+	}
+	return p.publishEvent("tenant.onboarded", o.Resources.TenantID.String(), event)
+}
+
 // publishEvent publishes an event to Kafka.
 func (p *EventPublisher) publishEvent(eventType, key string, payload interface{}) error {
 	topic := fmt.Sprintf("%s.%s", p.topicPrefix, eventType)
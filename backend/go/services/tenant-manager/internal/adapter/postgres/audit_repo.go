@@ -0,0 +1,157 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+)
+
+// AuditRepository implements audit.Store on the append-only audit_log table.
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAuditRepository creates a new AuditRepository.
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+// Append inserts a new audit entry.
+func (r *AuditRepository) Append(ctx context.Context, e audit.Entry) error {
+	changesJSON, err := json.Marshal(e.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit changes: %w", err)
+	}
+
+	metadataJSON, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (
+			id, occurred_at, actor, actor_role, action,
+			target_type, target_id, tenant_id, changes, metadata
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9, $10
+		)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		e.ID,
+		e.Timestamp,
+		e.Actor,
+		e.ActorRole,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		e.TenantID,
+		changesJSON,
+		metadataJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// Query returns the newest entries matching the filter.
+func (r *AuditRepository) Query(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.TenantID != "" {
+		add("tenant_id = $%d", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		add("actor = $%d", filter.Actor)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetID != "" {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		add("occurred_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("occurred_at < $%d", filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = audit.DefaultLimit
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT
+			id, occurred_at, actor, actor_role, action,
+			target_type, target_id, tenant_id, changes, metadata
+		FROM audit_log
+		%s
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT $%d
+	`, where, len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]audit.Entry, 0)
+	for rows.Next() {
+		var e audit.Entry
+		var changesJSON, metadataJSON []byte
+
+		if err := rows.Scan(
+			&e.ID,
+			&e.Timestamp,
+			&e.Actor,
+			&e.ActorRole,
+			&e.Action,
+			&e.TargetType,
+			&e.TargetID,
+			&e.TenantID,
+			&changesJSON,
+			&metadataJSON,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+
+		if err := json.Unmarshal(changesJSON, &e.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit changes: %w", err)
+		}
+		if err := json.Unmarshal(metadataJSON, &e.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit metadata: %w", err)
+		}
+
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,168 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tenant-manager/internal/domain/customer"
+)
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint
+// violation.
+const uniqueViolation = "23505"
+
+// CustomerRepository implements customer.Repository on the customers and
+// customer_identifiers tables.
+type CustomerRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCustomerRepository creates a new CustomerRepository.
+func NewCustomerRepository(pool *pgxpool.Pool) *CustomerRepository {
+	return &CustomerRepository{pool: pool}
+}
+
+// Create persists a new customer with its identifiers.
+func (r *CustomerRepository) Create(ctx context.Context, c *customer.Customer) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO customers (id, tenant_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+	`, c.ID, c.TenantID, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	for _, id := range c.Identifiers {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO customer_identifiers (tenant_id, kind, value, customer_id)
+			VALUES ($1, $2, $3, $4)
+		`, c.TenantID, string(id.Kind), id.Value, c.ID)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return customer.ErrIdentifierTaken
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add customer identifier: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit customer: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a customer of the tenant.
+func (r *CustomerRepository) GetByID(ctx context.Context, tenantID, id uuid.UUID) (*customer.Customer, error) {
+	var c customer.Customer
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, tenant_id, merged_into, created_at, updated_at
+		FROM customers
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, id).Scan(&c.ID, &c.TenantID, &c.MergedInto, &c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, customer.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan customer: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT kind, value
+		FROM customer_identifiers
+		WHERE customer_id = $1
+		ORDER BY created_at
+	`, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer identifiers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id customer.Identifier
+		var kind string
+		if err := rows.Scan(&kind, &id.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan customer identifier: %w", err)
+		}
+		id.Kind = customer.IdentifierKind(kind)
+		c.Identifiers = append(c.Identifiers, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list customer identifiers: %w", err)
+	}
+
+	return &c, nil
+}
+
+// FindByIdentifier retrieves the customer of the tenant owning id.
+func (r *CustomerRepository) FindByIdentifier(ctx context.Context, tenantID uuid.UUID, id customer.Identifier) (*customer.Customer, error) {
+	var customerID uuid.UUID
+	err := r.pool.QueryRow(ctx, `
+		SELECT customer_id
+		FROM customer_identifiers
+		WHERE tenant_id = $1 AND kind = $2 AND value = $3
+	`, tenantID, string(id.Kind), id.Value).Scan(&customerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, customer.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find customer identifier: %w", err)
+	}
+
+	return r.GetByID(ctx, tenantID, customerID)
+}
+
+// Merge moves every identifier of source to target and marks source as
+// merged into target.
+func (r *CustomerRepository) Merge(ctx context.Context, tenantID, targetID, sourceID uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE customer_identifiers SET customer_id = $3
+		WHERE tenant_id = $1 AND customer_id = $2
+	`, tenantID, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to move customer identifiers: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE customers SET merged_into = $3, updated_at = NOW()
+		WHERE tenant_id = $1 AND id = $2 AND merged_into IS NULL
+	`, tenantID, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to mark customer merged: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return customer.ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE customers SET updated_at = NOW()
+		WHERE tenant_id = $1 AND id = $2
+	`, tenantID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit merge: %w", err)
+	}
+	return nil
+}
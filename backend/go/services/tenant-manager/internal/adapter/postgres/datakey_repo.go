@@ -0,0 +1,81 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tenant-manager/internal/domain/tenant"
+)
+
+// DataKeyRepository implements tenant.DataKeyRepository on the
+// tenant_data_keys table.
+type DataKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDataKeyRepository creates a new DataKeyRepository.
+func NewDataKeyRepository(pool *pgxpool.Pool) *DataKeyRepository {
+	return &DataKeyRepository{pool: pool}
+}
+
+// Latest returns the newest data key of the tenant.
+func (r *DataKeyRepository) Latest(ctx context.Context, tenantID uuid.UUID) (*tenant.DataKey, error) {
+	query := `
+		SELECT tenant_id, version, wrapped_key, created_at
+		FROM tenant_data_keys
+		WHERE tenant_id = $1
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	return scanDataKey(r.pool.QueryRow(ctx, query, tenantID))
+}
+
+// Get returns a data key version of the tenant.
+func (r *DataKeyRepository) Get(ctx context.Context, tenantID uuid.UUID, version int) (*tenant.DataKey, error) {
+	query := `
+		SELECT tenant_id, version, wrapped_key, created_at
+		FROM tenant_data_keys
+		WHERE tenant_id = $1 AND version = $2
+	`
+
+	return scanDataKey(r.pool.QueryRow(ctx, query, tenantID, version))
+}
+
+// Create persists a new data key version.
+func (r *DataKeyRepository) Create(ctx context.Context, key *tenant.DataKey) error {
+	query := `
+		INSERT INTO tenant_data_keys (tenant_id, version, wrapped_key, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.pool.Exec(ctx, query, key.TenantID, key.Version, key.Wrapped, key.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return tenant.ErrDataKeyExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	return nil
+}
+
+func scanDataKey(row pgx.Row) (*tenant.DataKey, error) {
+	var key tenant.DataKey
+	err := row.Scan(&key.TenantID, &key.Version, &key.Wrapped, &key.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, tenant.ErrDataKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan data key: %w", err)
+	}
+	return &key, nil
+}
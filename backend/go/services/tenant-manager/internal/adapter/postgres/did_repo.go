@@ -0,0 +1,183 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tenant-manager/internal/domain/did"
+)
+
+// DIDRepository implements did.Repository on the dids table.
+type DIDRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewDIDRepository creates a new DIDRepository.
+func NewDIDRepository(pool *pgxpool.Pool) *DIDRepository {
+	return &DIDRepository{pool: pool}
+}
+
+// AssignBatch assigns the numbers no tenant holds to tenantID and returns
+// the owner of each number.
+func (r *DIDRepository) AssignBatch(ctx context.Context, tenantID uuid.UUID, numbers []string) ([]did.Assignment, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Held numbers are skipped rather than failing, so one taken number does
+	// not abort the rest of the batch
+	rows, err := tx.Query(ctx, `
+		INSERT INTO dids (number, tenant_id)
+		SELECT unnest($2::text[]), $1
+		ON CONFLICT (number) DO NOTHING
+		RETURNING number
+	`, tenantID, numbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign DIDs: %w", err)
+	}
+	created := make(map[string]bool, len(numbers))
+	for rows.Next() {
+		var number string
+		if err := rows.Scan(&number); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan assigned DID: %w", err)
+		}
+		created[number] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to assign DIDs: %w", err)
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT number, tenant_id
+		FROM dids
+		WHERE number = ANY($1)
+	`, numbers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DID owners: %w", err)
+	}
+	owners := make(map[string]uuid.UUID, len(numbers))
+	for rows.Next() {
+		var number string
+		var owner uuid.UUID
+		if err := rows.Scan(&number, &owner); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan DID owner: %w", err)
+		}
+		owners[number] = owner
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read DID owners: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit DIDs: %w", err)
+	}
+
+	assignments := make([]did.Assignment, 0, len(numbers))
+	for _, number := range numbers {
+		assignments = append(assignments, did.Assignment{
+			Number:   number,
+			TenantID: owners[number],
+			Created:  created[number],
+		})
+	}
+	return assignments, nil
+}
+
+// didColumns are the columns scanned by scanDID.
+const didColumns = `number, tenant_id, enabled, agent_id, flow_id, label, created_at, updated_at`
+
+// GetByNumber retrieves a DID whatever tenant holds it.
+func (r *DIDRepository) GetByNumber(ctx context.Context, number string) (*did.DID, error) {
+	d, err := scanDID(r.pool.QueryRow(ctx, `
+		SELECT `+didColumns+`
+		FROM dids
+		WHERE number = $1
+	`, number))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, did.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan DID: %w", err)
+	}
+	return d, nil
+}
+
+// ListByTenant retrieves the tenant's DIDs ordered by number.
+func (r *DIDRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*did.DID, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+didColumns+`
+		FROM dids
+		WHERE tenant_id = $1
+		ORDER BY number
+	`, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DIDs: %w", err)
+	}
+	defer rows.Close()
+
+	var dids []*did.DID
+	for rows.Next() {
+		d, err := scanDID(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan DID: %w", err)
+		}
+		dids = append(dids, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list DIDs: %w", err)
+	}
+	return dids, nil
+}
+
+// Update saves the assignment, label and enabled flag of a DID of the tenant.
+func (r *DIDRepository) Update(ctx context.Context, d *did.DID) error {
+	result, err := r.pool.Exec(ctx, `
+		UPDATE dids
+		SET enabled = $3, agent_id = $4, flow_id = $5, label = $6, updated_at = $7
+		WHERE tenant_id = $1 AND number = $2
+	`, d.TenantID, d.Number, d.Enabled, d.AgentID, d.FlowID, d.Label, d.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update DID: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return did.ErrNotFound
+	}
+	return nil
+}
+
+// Delete releases a DID of the tenant.
+func (r *DIDRepository) Delete(ctx context.Context, tenantID uuid.UUID, number string) error {
+	result, err := r.pool.Exec(ctx, `
+		DELETE FROM dids
+		WHERE tenant_id = $1 AND number = $2
+	`, tenantID, number)
+	if err != nil {
+		return fmt.Errorf("failed to delete DID: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return did.ErrNotFound
+	}
+	return nil
+}
+
+// scanDID scans a row of didColumns.
+func scanDID(row pgx.Row) (*did.DID, error) {
+	var d did.DID
+	err := row.Scan(&d.Number, &d.TenantID, &d.Enabled, &d.AgentID, &d.FlowID, &d.Label, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
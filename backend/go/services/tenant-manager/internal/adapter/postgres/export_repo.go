@@ -0,0 +1,140 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tenant-manager/internal/domain/export"
+)
+
+// ExportJobRepository implements export.Repository on the export_jobs table.
+type ExportJobRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewExportJobRepository creates a new ExportJobRepository.
+func NewExportJobRepository(pool *pgxpool.Pool) *ExportJobRepository {
+	return &ExportJobRepository{pool: pool}
+}
+
+// Create persists a new export job.
+func (r *ExportJobRepository) Create(ctx context.Context, job *export.Job) error {
+	sourcesJSON, err := json.Marshal(job.Sources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sources: %w", err)
+	}
+
+	query := `
+		INSERT INTO export_jobs (
+			id, tenant_id, customer_id, requested_by, status,
+			sources, archive_key, error, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9, $10
+		)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		job.ID,
+		job.TenantID,
+		job.CustomerID,
+		job.RequestedBy,
+		string(job.Status),
+		sourcesJSON,
+		job.ArchiveKey,
+		job.Error,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an export job by its ID.
+func (r *ExportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*export.Job, error) {
+	query := `
+		SELECT
+			id, tenant_id, customer_id, requested_by, status,
+			sources, archive_key, error, created_at, updated_at, completed_at
+		FROM export_jobs
+		WHERE id = $1
+	`
+
+	var job export.Job
+	var status string
+	var sourcesJSON []byte
+
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&job.ID,
+		&job.TenantID,
+		&job.CustomerID,
+		&job.RequestedBy,
+		&status,
+		&sourcesJSON,
+		&job.ArchiveKey,
+		&job.Error,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.CompletedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, export.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan export job: %w", err)
+	}
+
+	job.Status = export.Status(status)
+	if err := json.Unmarshal(sourcesJSON, &job.Sources); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sources: %w", err)
+	}
+
+	return &job, nil
+}
+
+// Update saves the status and progress of an export job.
+func (r *ExportJobRepository) Update(ctx context.Context, job *export.Job) error {
+	sourcesJSON, err := json.Marshal(job.Sources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sources: %w", err)
+	}
+
+	query := `
+		UPDATE export_jobs SET
+			status = $2,
+			sources = $3,
+			archive_key = $4,
+			error = $5,
+			updated_at = $6,
+			completed_at = $7
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		job.ID,
+		string(job.Status),
+		sourcesJSON,
+		job.ArchiveKey,
+		job.Error,
+		job.UpdatedAt,
+		job.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update export job: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return export.ErrJobNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package postgres provides PostgreSQL repository implementations.
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"tenant-manager/internal/domain/onboarding"
+)
+
+// OnboardingRepository implements onboarding.Repository on the onboardings
+// table.
+type OnboardingRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOnboardingRepository creates a new OnboardingRepository.
+func NewOnboardingRepository(pool *pgxpool.Pool) *OnboardingRepository {
+	return &OnboardingRepository{pool: pool}
+}
+
+// Create persists a new onboarding.
+func (r *OnboardingRepository) Create(ctx context.Context, o *onboarding.Onboarding) error {
+	stepsJSON, resourcesJSON, err := marshalProgress(o)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO onboardings (
+			id, idempotency_key, status, completed_steps, resources,
+			failed_step, error, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9
+		)
+	`
+
+	_, err = r.pool.Exec(ctx, query,
+		o.ID,
+		o.IdempotencyKey,
+		string(o.Status),
+		stepsJSON,
+		resourcesJSON,
+		string(o.FailedStep),
+		o.Error,
+		o.CreatedAt,
+		o.UpdatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return onboarding.ErrDuplicateKey
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create onboarding: %w", err)
+	}
+
+	return nil
+}
+
+// GetByKey retrieves an onboarding by its idempotency key.
+func (r *OnboardingRepository) GetByKey(ctx context.Context, key string) (*onboarding.Onboarding, error) {
+	query := `
+		SELECT
+			id, idempotency_key, status, completed_steps, resources,
+			failed_step, error, created_at, updated_at, finished_at
+		FROM onboardings
+		WHERE idempotency_key = $1
+	`
+
+	var o onboarding.Onboarding
+	var status, failedStep string
+	var stepsJSON, resourcesJSON []byte
+
+	err := r.pool.QueryRow(ctx, query, key).Scan(
+		&o.ID,
+		&o.IdempotencyKey,
+		&status,
+		&stepsJSON,
+		&resourcesJSON,
+		&failedStep,
+		&o.Error,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+		&o.FinishedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, onboarding.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan onboarding: %w", err)
+	}
+
+	o.Status = onboarding.Status(status)
+	o.FailedStep = onboarding.Step(failedStep)
+	if err := json.Unmarshal(stepsJSON, &o.Completed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal completed steps: %w", err)
+	}
+	if err := json.Unmarshal(resourcesJSON, &o.Resources); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resources: %w", err)
+	}
+
+	return &o, nil
+}
+
+// Update saves the status and progress of an onboarding.
+func (r *OnboardingRepository) Update(ctx context.Context, o *onboarding.Onboarding) error {
+	stepsJSON, resourcesJSON, err := marshalProgress(o)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE onboardings SET
+			status = $2,
+			completed_steps = $3,
+			resources = $4,
+			failed_step = $5,
+			error = $6,
+			updated_at = $7,
+			finished_at = $8
+		WHERE id = $1
+	`
+
+	result, err := r.pool.Exec(ctx, query,
+		o.ID,
+		string(o.Status),
+		stepsJSON,
+		resourcesJSON,
+		string(o.FailedStep),
+		o.Error,
+		o.UpdatedAt,
+		o.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update onboarding: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return onboarding.ErrNotFound
+	}
+
+	return nil
+}
+
+// marshalProgress encodes the JSONB columns of an onboarding.
+func marshalProgress(o *onboarding.Onboarding) (steps, resources []byte, err error) {
+	completed := o.Completed
+	if completed == nil {
+		completed = []onboarding.Step{}
+	}
+	if steps, err = json.Marshal(completed); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal completed steps: %w", err)
+	}
+	if resources, err = json.Marshal(o.Resources); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal resources: %w", err)
+	}
+	return steps, resources, nil
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/domain/tenant"
+)
+
+// secretsMember is the settings JSONB member holding the encrypted secret
+// fields. Everything else stays plain JSON, so it can still be queried.
+const secretsMember = "secrets"
+
+// errNoSecretCipher is returned when settings carrying secrets are saved or
+// loaded by a repository without a cipher; secrets are never stored plain.
+var errNoSecretCipher = errors.New("no secret cipher configured for tenant settings secrets")
+
+// sealedSecrets holds the secret fields of the settings as ciphertexts.
+type sealedSecrets struct {
+	WebhookSecret   string            `json:"webhook_secret,omitempty"`
+	ProviderAPIKeys map[string]string `json:"provider_api_keys,omitempty"`
+}
+
+func (s sealedSecrets) empty() bool {
+	return s.WebhookSecret == "" && len(s.ProviderAPIKeys) == 0
+}
+
+// marshalSettings encodes the settings JSONB column, encrypting the secret
+// fields of the tenant under its data key.
+func marshalSettings(ctx context.Context, secrets tenant.SecretCipher, tenantID uuid.UUID, settings tenant.Settings) ([]byte, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	plain := sealedSecrets{
+		WebhookSecret:   settings.Notifications.WebhookSecret,
+		ProviderAPIKeys: settings.AIAgent.ProviderAPIKeys,
+	}
+	if plain.empty() {
+		return data, nil
+	}
+	if secrets == nil {
+		return nil, errNoSecretCipher
+	}
+
+	sealed, err := convertSecrets(plain, func(value string) (string, error) {
+		return secrets.Encrypt(ctx, tenantID, value)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt settings secrets: %w", err)
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	if members[secretsMember], err = json.Marshal(sealed); err != nil {
+		return nil, fmt.Errorf("failed to marshal settings secrets: %w", err)
+	}
+
+	return json.Marshal(members)
+}
+
+// unmarshalSettings decodes the settings JSONB column, decrypting the
+// secret fields of the tenant.
+func unmarshalSettings(ctx context.Context, secrets tenant.SecretCipher, tenantID uuid.UUID, data []byte, settings *tenant.Settings) error {
+	if err := json.Unmarshal(data, settings); err != nil {
+		return fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	var stored struct {
+		Secrets sealedSecrets `json:"secrets"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to unmarshal settings secrets: %w", err)
+	}
+	if stored.Secrets.empty() {
+		return nil
+	}
+	if secrets == nil {
+		return errNoSecretCipher
+	}
+
+	plain, err := convertSecrets(stored.Secrets, func(value string) (string, error) {
+		return secrets.Decrypt(ctx, tenantID, value)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to decrypt settings secrets: %w", err)
+	}
+
+	settings.Notifications.WebhookSecret = plain.WebhookSecret
+	settings.AIAgent.ProviderAPIKeys = plain.ProviderAPIKeys
+	return nil
+}
+
+// convertSecrets applies convert to every secret value.
+func convertSecrets(in sealedSecrets, convert func(string) (string, error)) (sealedSecrets, error) {
+	var out sealedSecrets
+	var err error
+
+	if in.WebhookSecret != "" {
+		if out.WebhookSecret, err = convert(in.WebhookSecret); err != nil {
+			return sealedSecrets{}, err
+		}
+	}
+	if len(in.ProviderAPIKeys) > 0 {
+		out.ProviderAPIKeys = make(map[string]string, len(in.ProviderAPIKeys))
+		for provider, key := range in.ProviderAPIKeys {
+			if out.ProviderAPIKeys[provider], err = convert(key); err != nil {
+				return sealedSecrets{}, err
+			}
+		}
+	}
+
+	return out, nil
+}
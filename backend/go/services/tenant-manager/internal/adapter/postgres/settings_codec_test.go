@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/adapter/crypto"
+	"tenant-manager/internal/domain/tenant"
+	"tenant-manager/internal/domain/tenant/tenanttest"
+)
+
+func newCipher(t *testing.T) tenant.SecretCipher {
+	t.Helper()
+	kms, err := crypto.NewLocalKMS(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewLocalKMS: %v", err)
+	}
+	return crypto.NewEnvelope(tenanttest.NewDataKeys(), kms)
+}
+
+func secretSettings() tenant.Settings {
+	s := tenant.DefaultSettings()
+	s.Notifications.WebhookURL = "https://hooks.example.com/serphona"
+	s.Notifications.WebhookSecret = "whsec_123"
+	s.AIAgent.ProviderAPIKeys = map[string]string{"openai": "sk-openai", "deepgram": "dg-key"}
+	return s
+}
+
+func TestMarshalSettings_StoresSecretsAsCiphertext(t *testing.T) {
+	ctx := context.Background()
+	secrets := newCipher(t)
+	tenantID := uuid.New()
+
+	data, err := marshalSettings(ctx, secrets, tenantID, secretSettings())
+	if err != nil {
+		t.Fatalf("marshalSettings: %v", err)
+	}
+
+	for _, plain := range []string{"whsec_123", "sk-openai", "dg-key"} {
+		if strings.Contains(string(data), plain) {
+			t.Errorf("stored settings contain plaintext secret %q", plain)
+		}
+	}
+
+	var stored struct {
+		Notifications struct {
+			WebhookURL string `json:"webhook_url"`
+		} `json:"notifications"`
+		Secrets sealedSecrets `json:"secrets"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("stored settings are not JSON: %v", err)
+	}
+	if stored.Notifications.WebhookURL != "https://hooks.example.com/serphona" {
+		t.Errorf("webhook_url = %q, want it stored plain", stored.Notifications.WebhookURL)
+	}
+	if !crypto.IsCiphertext(stored.Secrets.WebhookSecret) {
+		t.Errorf("secrets.webhook_secret = %q, want ciphertext", stored.Secrets.WebhookSecret)
+	}
+	for provider, key := range stored.Secrets.ProviderAPIKeys {
+		if !crypto.IsCiphertext(key) {
+			t.Errorf("secrets.provider_api_keys[%s] = %q, want ciphertext", provider, key)
+		}
+	}
+}
+
+func TestSettingsCodec_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	secrets := newCipher(t)
+	tenantID := uuid.New()
+	want := secretSettings()
+
+	data, err := marshalSettings(ctx, secrets, tenantID, want)
+	if err != nil {
+		t.Fatalf("marshalSettings: %v", err)
+	}
+	var got tenant.Settings
+	if err := unmarshalSettings(ctx, secrets, tenantID, data, &got); err != nil {
+		t.Fatalf("unmarshalSettings: %v", err)
+	}
+
+	if got.Notifications.WebhookSecret != want.Notifications.WebhookSecret {
+		t.Errorf("WebhookSecret = %q, want %q", got.Notifications.WebhookSecret, want.Notifications.WebhookSecret)
+	}
+	if !reflect.DeepEqual(got.AIAgent.ProviderAPIKeys, want.AIAgent.ProviderAPIKeys) {
+		t.Errorf("ProviderAPIKeys = %v, want %v", got.AIAgent.ProviderAPIKeys, want.AIAgent.ProviderAPIKeys)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("settings did not round-trip:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSettingsCodec_WithoutSecrets(t *testing.T) {
+	ctx := context.Background()
+	settings := tenant.DefaultSettings()
+
+	data, err := marshalSettings(ctx, nil, uuid.New(), settings)
+	if err != nil {
+		t.Fatalf("marshalSettings: %v", err)
+	}
+	if strings.Contains(string(data), `"secrets"`) {
+		t.Errorf("settings without secrets stored a secrets member: %s", data)
+	}
+
+	if _, err := marshalSettings(ctx, nil, uuid.New(), secretSettings()); err == nil {
+		t.Error("marshalSettings stored secrets without a cipher")
+	}
+}
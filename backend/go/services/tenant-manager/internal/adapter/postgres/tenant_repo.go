@@ -11,26 +11,31 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	pagination "github.com/serphona/serphona/backend/go/libs/platform-pagination"
+
 	"tenant-manager/internal/domain/tenant"
 )
 
 // TenantRepository implements tenant.Repository using PostgreSQL.
 type TenantRepository struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	secrets tenant.SecretCipher
 }
 
-// NewTenantRepository creates a new TenantRepository.
-func NewTenantRepository(pool *pgxpool.Pool) *TenantRepository {
-	return &TenantRepository{pool: pool}
+// NewTenantRepository creates a new TenantRepository. secrets encrypts the
+// secret settings fields; without it, settings carrying secrets are refused.
+func NewTenantRepository(pool *pgxpool.Pool, secrets tenant.SecretCipher) *TenantRepository {
+	return &TenantRepository{pool: pool, secrets: secrets}
 }
 
 // Create persists a new tenant.
 func (r *TenantRepository) Create(ctx context.Context, t *tenant.Tenant) error {
-	settingsJSON, err := json.Marshal(t.Settings)
+	settingsJSON, err := marshalSettings(ctx, r.secrets, t.ID, t.Settings)
 	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
+		return err
 	}
 
 	metadataJSON, err := json.Marshal(t.Metadata)
@@ -66,6 +71,10 @@ func (r *TenantRepository) Create(ctx context.Context, t *tenant.Tenant) error {
 		t.UpdatedAt,
 	)
 
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "tenants_slug_key" {
+		return tenant.ErrSlugTaken
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "unique constraint") {
 			if strings.Contains(err.Error(), "email") {
@@ -125,9 +134,9 @@ func (r *TenantRepository) GetByEmail(ctx context.Context, email string) (*tenan
 
 // Update updates an existing tenant.
 func (r *TenantRepository) Update(ctx context.Context, t *tenant.Tenant) error {
-	settingsJSON, err := json.Marshal(t.Settings)
+	settingsJSON, err := marshalSettings(ctx, r.secrets, t.ID, t.Settings)
 	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
+		return err
 	}
 
 	metadataJSON, err := json.Marshal(t.Metadata)
@@ -198,8 +207,18 @@ func (r *TenantRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// tenantSort allowlists the columns tenants can be sorted by.
+var tenantSort = pagination.Sort{
+	Columns: map[string]string{
+		"name": "name", "email": "email", "created_at": "created_at", "updated_at": "updated_at", "status": "status",
+	},
+	Default:    "created_at",
+	Tiebreaker: "id",
+}
+
 // List retrieves tenants with pagination and filtering.
-func (r *TenantRepository) List(ctx context.Context, filter tenant.ListFilter) (*tenant.ListResult, error) {
+// A cursor in the filter switches from offset to keyset pagination.
+func (r *TenantRepository) List(ctx context.Context, filter tenant.ListFilter) (*pagination.Page[*tenant.Tenant], error) {
 	// Build WHERE clause
 	var conditions []string
 	var args []interface{}
@@ -225,41 +244,31 @@ func (r *TenantRepository) List(ctx context.Context, filter tenant.ListFilter) (
 		argIndex++
 	}
 
-	whereClause := strings.Join(conditions, " AND ")
-
 	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tenants WHERE %s", whereClause)
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tenants WHERE %s", strings.Join(conditions, " AND "))
 	var total int64
 	err := r.pool.QueryRow(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count tenants: %w", err)
 	}
 
-	// Build ORDER BY
-	sortBy := "created_at"
-	if filter.SortBy != "" {
-		// Validate sort column to prevent SQL injection
-		validSortColumns := map[string]bool{
-			"name": true, "email": true, "created_at": true, "updated_at": true, "status": true,
-		}
-		if validSortColumns[filter.SortBy] {
-			sortBy = filter.SortBy
+	// Position after the cursor or skip previous pages
+	req := filter.Page
+	var limitClause string
+	var limitArgs []interface{}
+	if req.IsCursor() {
+		keyset, keysetArgs, err := tenantSort.Keyset(req, argIndex)
+		if err != nil {
+			return nil, err
 		}
+		conditions = append(conditions, keyset)
+		args = append(args, keysetArgs...)
+		argIndex += len(keysetArgs)
+		limitClause, limitArgs = pagination.KeysetLimit(req, argIndex)
+	} else {
+		limitClause, limitArgs = pagination.OffsetClause(req, argIndex)
 	}
-
-	sortOrder := "DESC"
-	if filter.SortOrder == "asc" {
-		sortOrder = "ASC"
-	}
-
-	// Calculate pagination
-	if filter.PageSize <= 0 {
-		filter.PageSize = 20
-	}
-	if filter.PageNumber <= 0 {
-		filter.PageNumber = 1
-	}
-	offset := (filter.PageNumber - 1) * filter.PageSize
+	args = append(args, limitArgs...)
 
 	// Fetch tenants
 	query := fmt.Sprintf(`
@@ -269,11 +278,9 @@ func (r *TenantRepository) List(ctx context.Context, filter tenant.ListFilter) (
 			created_at, updated_at, deleted_at
 		FROM tenants
 		WHERE %s
-		ORDER BY %s %s
-		LIMIT $%d OFFSET $%d
-	`, whereClause, sortBy, sortOrder, argIndex, argIndex+1)
-
-	args = append(args, filter.PageSize, offset)
+		ORDER BY %s
+		%s
+	`, strings.Join(conditions, " AND "), tenantSort.OrderBy(req), limitClause)
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -283,7 +290,7 @@ func (r *TenantRepository) List(ctx context.Context, filter tenant.ListFilter) (
 
 	var tenants []*tenant.Tenant
 	for rows.Next() {
-		t, err := r.scanTenantFromRows(rows)
+		t, err := r.scanTenantFromRows(ctx, rows)
 		if err != nil {
 			return nil, err
 		}
@@ -294,25 +301,45 @@ func (r *TenantRepository) List(ctx context.Context, filter tenant.ListFilter) (
 		return nil, fmt.Errorf("error iterating tenants: %w", err)
 	}
 
-	totalPages := int(total) / filter.PageSize
-	if int(total)%filter.PageSize > 0 {
-		totalPages++
+	var page pagination.Page[*tenant.Tenant]
+	if req.IsCursor() {
+		page, err = pagination.NewCursorPage(tenants, total, req, tenantCursor(req))
+	} else {
+		// Offset pages also carry a cursor so clients can switch to keyset
+		page, err = pagination.NewPage(tenants, total, req).WithCursor(tenantCursor(req))
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return &tenant.ListResult{
-		Tenants:    tenants,
-		Total:      total,
-		PageSize:   filter.PageSize,
-		PageNumber: filter.PageNumber,
-		TotalPages: totalPages,
-	}, nil
+	return &page, nil
+}
+
+// tenantCursor returns the keyset cursor of a tenant for the sort column.
+func tenantCursor(req pagination.Request) func(*tenant.Tenant) pagination.Cursor {
+	return func(t *tenant.Tenant) pagination.Cursor {
+		var value string
+		switch tenantSort.Column(req.SortBy) {
+		case "name":
+			value = t.Name
+		case "email":
+			value = t.Email
+		case "status":
+			value = string(t.Status)
+		case "updated_at":
+			value = t.UpdatedAt.Format(time.RFC3339Nano)
+		default:
+			value = t.CreatedAt.Format(time.RFC3339Nano)
+		}
+		return tenantSort.CursorFor(req, value, t.ID.String())
+	}
 }
 
 // UpdateSettings updates only the tenant settings.
 func (r *TenantRepository) UpdateSettings(ctx context.Context, id uuid.UUID, settings tenant.Settings) error {
-	settingsJSON, err := json.Marshal(settings)
+	settingsJSON, err := marshalSettings(ctx, r.secrets, id, settings)
 	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
+		return err
 	}
 
 	query := `
@@ -334,6 +361,47 @@ func (r *TenantRepository) UpdateSettings(ctx context.Context, id uuid.UUID, set
 	return nil
 }
 
+// RotateSecretsKey rotates the tenant's data key and re-encrypts the
+// secrets in its settings under the new key.
+func (r *TenantRepository) RotateSecretsKey(ctx context.Context, id uuid.UUID) error {
+	if r.secrets == nil {
+		return errNoSecretCipher
+	}
+	if err := r.secrets.RotateKey(ctx, id); err != nil {
+		return fmt.Errorf("failed to rotate data key: %w", err)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var stored []byte
+	err = tx.QueryRow(ctx, `SELECT settings FROM tenants WHERE id = $1 FOR UPDATE`, id).Scan(&stored)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("tenant not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	var settings tenant.Settings
+	if err := unmarshalSettings(ctx, r.secrets, id, stored, &settings); err != nil {
+		return err
+	}
+	settingsJSON, err := marshalSettings(ctx, r.secrets, id, settings)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE tenants SET settings = $2 WHERE id = $1`, id, settingsJSON); err != nil {
+		return fmt.Errorf("failed to update settings: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
 // GetQuota retrieves the quota for a tenant.
 func (r *TenantRepository) GetQuota(ctx context.Context, tenantID uuid.UUID) (*tenant.Quota, error) {
 	query := `
@@ -426,6 +494,59 @@ func (r *TenantRepository) IncrementUsage(ctx context.Context, tenantID uuid.UUI
 	return nil
 }
 
+// ReserveUsage adds calls and minutes to the tenant's usage if the result
+// stays within its quota. The check and the increment are one conditional
+// UPDATE, so concurrent reservations can't overshoot the quota together.
+func (r *TenantRepository) ReserveUsage(ctx context.Context, tenantID uuid.UUID, calls, minutes int) (*tenant.Reservation, error) {
+	query := `
+		UPDATE tenant_quotas SET
+			used_calls = used_calls + $2,
+			used_minutes = used_minutes + $3
+		WHERE tenant_id = $1
+			AND (max_calls_per_month = -1 OR used_calls + $2 <= max_calls_per_month)
+			AND (max_minutes_per_month = -1 OR used_minutes + $3 <= max_minutes_per_month)
+		RETURNING max_calls_per_month, max_minutes_per_month, used_calls, used_minutes
+	`
+
+	var q tenant.Quota
+	err := r.pool.QueryRow(ctx, query, tenantID, calls, minutes).Scan(
+		&q.MaxCallsPerMonth,
+		&q.MaxMinutesPerMonth,
+		&q.UsedCalls,
+		&q.UsedMinutes,
+	)
+	if err == nil {
+		return q.Reservation(true), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve usage: %w", err)
+	}
+
+	// Refused, or the tenant has no quota
+	current, err := r.GetQuota(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return current.Reservation(false), nil
+}
+
+// MarkThresholdReached records that a quota threshold fired in its period.
+// The primary key makes concurrent marks of the same threshold fire once.
+func (r *TenantRepository) MarkThresholdReached(ctx context.Context, threshold tenant.QuotaThreshold) (bool, error) {
+	query := `
+		INSERT INTO quota_thresholds_reached (tenant_id, period_reset_at, resource, percent, reached_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := r.pool.Exec(ctx, query, threshold.TenantID, threshold.Period, threshold.Resource, threshold.Percent)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark quota threshold: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
 // ExistsBySlug checks if a tenant with the given slug exists.
 func (r *TenantRepository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM tenants WHERE slug = $1 AND deleted_at IS NULL)`
@@ -439,6 +560,29 @@ func (r *TenantRepository) ExistsBySlug(ctx context.Context, slug string) (bool,
 	return exists, nil
 }
 
+// TakenSlugs returns which of the slugs are held by a tenant. Deleted tenants
+// count, as the unique constraint on slug covers them too.
+func (r *TenantRepository) TakenSlugs(ctx context.Context, slugs []string) (map[string]bool, error) {
+	rows, err := r.pool.Query(ctx, `SELECT slug FROM tenants WHERE slug = ANY($1)`, slugs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool, len(slugs))
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, fmt.Errorf("failed to scan slug: %w", err)
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating slugs: %w", err)
+	}
+	return taken, nil
+}
+
 // ExistsByEmail checks if a tenant with the given email exists.
 func (r *TenantRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM tenants WHERE email = $1 AND deleted_at IS NULL)`
@@ -485,8 +629,8 @@ func (r *TenantRepository) scanTenant(ctx context.Context, row pgx.Row) (*tenant
 	t.Status = tenant.Status(status)
 	t.Plan = tenant.Plan(plan)
 
-	if err := json.Unmarshal(settingsJSON, &t.Settings); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+	if err := unmarshalSettings(ctx, r.secrets, t.ID, settingsJSON, &t.Settings); err != nil {
+		return nil, err
 	}
 
 	if err := json.Unmarshal(metadataJSON, &t.Metadata); err != nil {
@@ -497,7 +641,7 @@ func (r *TenantRepository) scanTenant(ctx context.Context, row pgx.Row) (*tenant
 }
 
 // scanTenantFromRows scans a tenant from rows.
-func (r *TenantRepository) scanTenantFromRows(rows pgx.Rows) (*tenant.Tenant, error) {
+func (r *TenantRepository) scanTenantFromRows(ctx context.Context, rows pgx.Rows) (*tenant.Tenant, error) {
 	var t tenant.Tenant
 	var status, plan string
 	var settingsJSON, metadataJSON []byte
@@ -526,8 +670,8 @@ func (r *TenantRepository) scanTenantFromRows(rows pgx.Rows) (*tenant.Tenant, er
 	t.Status = tenant.Status(status)
 	t.Plan = tenant.Plan(plan)
 
-	if err := json.Unmarshal(settingsJSON, &t.Settings); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+	if err := unmarshalSettings(ctx, r.secrets, t.ID, settingsJSON, &t.Settings); err != nil {
+		return nil, err
 	}
 
 	if err := json.Unmarshal(metadataJSON, &t.Metadata); err != nil {
@@ -0,0 +1,119 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestPool connects to the migrated database in TEST_DATABASE_URL.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// seedQuota creates a tenant whose quota allows maxCalls calls, usedCalls of
+// them already used, and unlimited minutes.
+func seedQuota(t *testing.T, pool *pgxpool.Pool, maxCalls, usedCalls int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	id := uuid.New()
+	slug := "reserve-" + id.String()[:8]
+	if _, err := pool.Exec(ctx, `INSERT INTO tenants (id, name, slug, email) VALUES ($1, $2, $2, $3)`,
+		id, slug, slug+"@example.com"); err != nil {
+		t.Fatalf("failed to create tenant: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Exec(context.Background(), `DELETE FROM tenants WHERE id = $1`, id)
+	})
+
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO tenant_quotas (tenant_id, max_calls_per_month, max_minutes_per_month, used_calls)
+		VALUES ($1, $2, -1, $3)`, id, maxCalls, usedCalls); err != nil {
+		t.Fatalf("failed to create quota: %v", err)
+	}
+	return id
+}
+
+// reserveInParallel runs n concurrent reservations of calls calls each and
+// returns how many were allowed.
+func reserveInParallel(t *testing.T, repo *TenantRepository, tenantID uuid.UUID, n, calls int) int {
+	t.Helper()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	start := make(chan struct{})
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			got, err := repo.ReserveUsage(context.Background(), tenantID, calls, 1)
+			if err != nil {
+				t.Errorf("ReserveUsage: %v", err)
+				return
+			}
+			if got.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return allowed
+}
+
+func TestTenantRepository_ReserveUsageStopsAtLimit(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewTenantRepository(pool, nil)
+
+	tests := []struct {
+		name        string
+		max, used   int
+		calls       int
+		wantAllowed int
+		wantUsed    int
+	}{
+		{"fills the quota exactly", 100, 90, 1, 10, 100},
+		{"never goes over the limit", 100, 90, 3, 3, 99},
+		{"refuses at the limit", 100, 100, 1, 0, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenantID := seedQuota(t, pool, tt.max, tt.used)
+
+			if allowed := reserveInParallel(t, repo, tenantID, 50, tt.calls); allowed != tt.wantAllowed {
+				t.Errorf("allowed reservations = %d, want %d", allowed, tt.wantAllowed)
+			}
+			stored, err := repo.GetQuota(context.Background(), tenantID)
+			if err != nil {
+				t.Fatalf("GetQuota: %v", err)
+			}
+			if stored.UsedCalls != tt.wantUsed {
+				t.Errorf("used calls = %d, want %d", stored.UsedCalls, tt.wantUsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package storage provides file storage implementations.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileStore stores export archives on the local filesystem (or a mounted
+// volume).
+type FileStore struct {
+	basePath string
+}
+
+// NewFileStore creates a new filesystem-based store rooted at basePath.
+func NewFileStore(basePath string) *FileStore {
+	return &FileStore{basePath: basePath}
+}
+
+// Create opens the file stored under key for writing, replacing it if it
+// exists.
+func (s *FileStore) Create(ctx context.Context, key string) (io.WriteCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file: %w", err)
+	}
+	return f, nil
+}
+
+// Open opens the file stored under key for reading.
+func (s *FileStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return f, nil
+}
+
+// path resolves key inside basePath, never outside it.
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.basePath, filepath.Clean("/"+key))
+}
@@ -0,0 +1,37 @@
+package customer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/domain/customer"
+)
+
+// CustomerDTO is the data transfer object for a customer.
+type CustomerDTO struct {
+	ID          uuid.UUID             `json:"id"`
+	TenantID    uuid.UUID             `json:"tenant_id"`
+	Identifiers []customer.Identifier `json:"identifiers"`
+	MergedInto  *uuid.UUID            `json:"merged_into,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// ResolveResult is the outcome of resolving an identifier to a customer.
+type ResolveResult struct {
+	Customer *CustomerDTO `json:"customer"`
+	Created  bool         `json:"created"` // first contact of this customer
+}
+
+// toDTO converts a domain customer to a DTO.
+func toDTO(c *customer.Customer) *CustomerDTO {
+	return &CustomerDTO{
+		ID:          c.ID,
+		TenantID:    c.TenantID,
+		Identifiers: c.Identifiers,
+		MergedInto:  c.MergedInto,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
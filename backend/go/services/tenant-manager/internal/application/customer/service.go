@@ -0,0 +1,150 @@
+// Package customer contains the application layer for end-customer identity
+// resolution.
+package customer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/customer"
+)
+
+// Service implements customer identity use cases.
+type Service struct {
+	repo        customer.Repository
+	auditLogger *audit.Logger
+	logger      *zap.Logger
+}
+
+// NewService creates a new customer service.
+func NewService(repo customer.Repository, auditLogger *audit.Logger, logger *zap.Logger) *Service {
+	return &Service{
+		repo:        repo,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// ResolveCustomer returns the stable customer ID of the tenant's customer
+// known by identifier (a phone number or email address), creating the
+// customer on first contact.
+func (s *Service) ResolveCustomer(ctx context.Context, tenantID uuid.UUID, identifier string) (*ResolveResult, error) {
+	id, err := customer.ParseIdentifier(identifier)
+	if err != nil {
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+
+	existing, err := s.find(ctx, tenantID, id)
+	if err == nil {
+		return &ResolveResult{Customer: toDTO(existing)}, nil
+	}
+	if !errors.Is(err, customer.ErrNotFound) {
+		return nil, err
+	}
+
+	c := customer.NewCustomer(tenantID, id)
+	err = s.repo.Create(ctx, c)
+	if errors.Is(err, customer.ErrIdentifierTaken) {
+		// A concurrent resolve created the customer first
+		existing, err := s.find(ctx, tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+		return &ResolveResult{Customer: toDTO(existing)}, nil
+	}
+	if err != nil {
+		s.logger.Error("failed to create customer", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to create customer")
+	}
+
+	s.logger.Info("customer created",
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("customer_id", c.ID.String()),
+		zap.String("kind", string(id.Kind)),
+	)
+
+	return &ResolveResult{Customer: toDTO(c), Created: true}, nil
+}
+
+// GetCustomer retrieves a customer of the tenant.
+func (s *Service) GetCustomer(ctx context.Context, tenantID, id uuid.UUID) (*CustomerDTO, error) {
+	c, err := s.get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(c), nil
+}
+
+// MergeCustomers merges the duplicate customer sourceID into targetID. The
+// target keeps its ID and gains every identifier of the source, so later
+// resolves of those identifiers return the target.
+func (s *Service) MergeCustomers(ctx context.Context, tenantID, targetID, sourceID uuid.UUID) (*CustomerDTO, error) {
+	if targetID == sourceID {
+		return nil, apperrors.NewValidationError("cannot merge a customer into itself")
+	}
+
+	target, err := s.get(ctx, tenantID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.get(ctx, tenantID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsMerged() {
+		return nil, apperrors.NewConflictError(fmt.Sprintf("customer %s was merged into %s", target.ID, *target.MergedInto))
+	}
+	if source.IsMerged() {
+		return nil, apperrors.NewConflictError(fmt.Sprintf("customer %s was already merged into %s", source.ID, *source.MergedInto))
+	}
+
+	if err := s.repo.Merge(ctx, tenantID, targetID, sourceID); err != nil {
+		s.logger.Error("failed to merge customers", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to merge customers")
+	}
+
+	s.auditLogger.Record(ctx, audit.Entry{
+		Action:     "customer.merged",
+		TargetType: "customer",
+		TargetID:   targetID.String(),
+		TenantID:   tenantID.String(),
+		Metadata:   map[string]string{"source_id": sourceID.String()},
+	})
+
+	target.Identifiers = append(target.Identifiers, source.Identifiers...)
+	target.UpdatedAt = time.Now().UTC()
+	return toDTO(target), nil
+}
+
+// find looks up the owner of an identifier.
+func (s *Service) find(ctx context.Context, tenantID uuid.UUID, id customer.Identifier) (*customer.Customer, error) {
+	c, err := s.repo.FindByIdentifier(ctx, tenantID, id)
+	if errors.Is(err, customer.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		s.logger.Error("failed to find customer", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to resolve customer")
+	}
+	return c, nil
+}
+
+// get loads a customer of the tenant.
+func (s *Service) get(ctx context.Context, tenantID, id uuid.UUID) (*customer.Customer, error) {
+	c, err := s.repo.GetByID(ctx, tenantID, id)
+	if errors.Is(err, customer.ErrNotFound) {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("customer with id %s not found", id))
+	}
+	if err != nil {
+		s.logger.Error("failed to get customer", zap.String("customer_id", id.String()), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get customer")
+	}
+	return c, nil
+}
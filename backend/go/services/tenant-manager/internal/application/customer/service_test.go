@@ -0,0 +1,173 @@
+package customer
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/customer"
+)
+
+// memRepo is an in-memory customer.Repository.
+type memRepo struct {
+	mu          sync.Mutex
+	customers   map[uuid.UUID]*customer.Customer
+	identifiers map[string]uuid.UUID // tenant/kind/value -> customer
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{
+		customers:   make(map[uuid.UUID]*customer.Customer),
+		identifiers: make(map[string]uuid.UUID),
+	}
+}
+
+func identifierKey(tenantID uuid.UUID, id customer.Identifier) string {
+	return tenantID.String() + "/" + string(id.Kind) + "/" + id.Value
+}
+
+func (r *memRepo) Create(_ context.Context, c *customer.Customer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range c.Identifiers {
+		if _, ok := r.identifiers[identifierKey(c.TenantID, id)]; ok {
+			return customer.ErrIdentifierTaken
+		}
+	}
+	for _, id := range c.Identifiers {
+		r.identifiers[identifierKey(c.TenantID, id)] = c.ID
+	}
+	saved := *c
+	r.customers[c.ID] = &saved
+	return nil
+}
+
+func (r *memRepo) GetByID(_ context.Context, tenantID, id uuid.UUID) (*customer.Customer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.customers[id]
+	if !ok || c.TenantID != tenantID {
+		return nil, customer.ErrNotFound
+	}
+	found := *c
+	return &found, nil
+}
+
+func (r *memRepo) FindByIdentifier(ctx context.Context, tenantID uuid.UUID, id customer.Identifier) (*customer.Customer, error) {
+	r.mu.Lock()
+	customerID, ok := r.identifiers[identifierKey(tenantID, id)]
+	r.mu.Unlock()
+	if !ok {
+		return nil, customer.ErrNotFound
+	}
+	return r.GetByID(ctx, tenantID, customerID)
+}
+
+func (r *memRepo) Merge(_ context.Context, tenantID, targetID, sourceID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	target, source := r.customers[targetID], r.customers[sourceID]
+	for _, id := range source.Identifiers {
+		r.identifiers[identifierKey(tenantID, id)] = targetID
+	}
+	target.Identifiers = append(target.Identifiers, source.Identifiers...)
+	source.Identifiers = nil
+	source.MergedInto = &targetID
+	return nil
+}
+
+func newTestService() *Service {
+	return NewService(newMemRepo(), nil, zap.NewNop())
+}
+
+func TestResolveCustomer_CreatesNewCustomer(t *testing.T) {
+	svc := newTestService()
+	tenantID := uuid.New()
+
+	result, err := svc.ResolveCustomer(context.Background(), tenantID, "+55 11 99999-0000")
+	if err != nil {
+		t.Fatalf("ResolveCustomer: %v", err)
+	}
+	if !result.Created {
+		t.Error("first contact should create the customer")
+	}
+	want := customer.Identifier{Kind: customer.KindPhone, Value: "+5511999990000"}
+	if len(result.Customer.Identifiers) != 1 || result.Customer.Identifiers[0] != want {
+		t.Errorf("identifiers = %+v, want [%+v]", result.Customer.Identifiers, want)
+	}
+}
+
+func TestResolveCustomer_ReturnsExistingCustomer(t *testing.T) {
+	svc := newTestService()
+	tenantID := uuid.New()
+
+	first, err := svc.ResolveCustomer(context.Background(), tenantID, "+5511999990000")
+	if err != nil {
+		t.Fatalf("ResolveCustomer: %v", err)
+	}
+
+	// Same number, formatted differently
+	again, err := svc.ResolveCustomer(context.Background(), tenantID, "+55 (11) 99999-0000")
+	if err != nil {
+		t.Fatalf("ResolveCustomer: %v", err)
+	}
+	if again.Created {
+		t.Error("repeat caller should not create a customer")
+	}
+	if again.Customer.ID != first.Customer.ID {
+		t.Errorf("repeat caller resolved to %s, want %s", again.Customer.ID, first.Customer.ID)
+	}
+
+	// Another tenant's caller with the same number is a different customer
+	other, err := svc.ResolveCustomer(context.Background(), uuid.New(), "+5511999990000")
+	if err != nil {
+		t.Fatalf("ResolveCustomer: %v", err)
+	}
+	if !other.Created || other.Customer.ID == first.Customer.ID {
+		t.Error("customers must not be shared across tenants")
+	}
+}
+
+func TestResolveCustomer_RejectsInvalidIdentifier(t *testing.T) {
+	svc := newTestService()
+
+	if _, err := svc.ResolveCustomer(context.Background(), uuid.New(), "anonymous"); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
+
+func TestMergeCustomers(t *testing.T) {
+	svc := newTestService()
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	phone, _ := svc.ResolveCustomer(ctx, tenantID, "+5511999990000")
+	email, _ := svc.ResolveCustomer(ctx, tenantID, "ana@example.com")
+
+	merged, err := svc.MergeCustomers(ctx, tenantID, phone.Customer.ID, email.Customer.ID)
+	if err != nil {
+		t.Fatalf("MergeCustomers: %v", err)
+	}
+	if len(merged.Identifiers) != 2 {
+		t.Errorf("merged identifiers = %+v, want phone and email", merged.Identifiers)
+	}
+
+	// The duplicate's email now resolves to the kept customer
+	result, err := svc.ResolveCustomer(ctx, tenantID, "ana@example.com")
+	if err != nil {
+		t.Fatalf("ResolveCustomer: %v", err)
+	}
+	if result.Customer.ID != phone.Customer.ID {
+		t.Errorf("email resolved to %s, want %s", result.Customer.ID, phone.Customer.ID)
+	}
+
+	if _, err := svc.MergeCustomers(ctx, tenantID, phone.Customer.ID, email.Customer.ID); err == nil {
+		t.Error("merging an already merged customer should fail")
+	}
+	if _, err := svc.MergeCustomers(ctx, tenantID, phone.Customer.ID, phone.Customer.ID); err == nil {
+		t.Error("merging a customer into itself should fail")
+	}
+}
@@ -0,0 +1,90 @@
+package did
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/domain/did"
+)
+
+// MaxBulkImport is the most numbers one bulk import may carry.
+const MaxBulkImport = 1000
+
+// Outcome of importing one number.
+const (
+	StatusCreated = "created" // assigned to the tenant by this import
+	StatusExists  = "exists"  // already the tenant's
+	StatusFailed  = "failed"
+)
+
+// Reasons a number failed to import.
+const (
+	ReasonInvalidNumber     = "invalid_number"
+	ReasonDuplicate         = "duplicate" // repeated earlier in the batch
+	ReasonAssignedToAnother = "assigned_to_another_tenant"
+)
+
+// BulkImportCommand is a batch of numbers to assign to a tenant.
+type BulkImportCommand struct {
+	Numbers []string
+	Region  string // ISO 3166-1 alpha-2 of numbers in national format
+}
+
+// NumberResult is the outcome of one number of a bulk import.
+type NumberResult struct {
+	Input   string `json:"input"`
+	Number  string `json:"number,omitempty"` // E.164, when the input is valid
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// BulkImportResult is the outcome of a bulk import, one result per input
+// number in input order.
+type BulkImportResult struct {
+	Created int            `json:"created"`
+	Existed int            `json:"existed"`
+	Failed  int            `json:"failed"`
+	Results []NumberResult `json:"results"`
+}
+
+// UpdateDIDCommand changes a DID of the tenant. Nil fields are left as they
+// are; an empty agent_id or flow_id clears the assignment.
+type UpdateDIDCommand struct {
+	AgentID *string
+	FlowID  *string
+	Label   *string
+	Enabled *bool
+}
+
+// DIDDTO is the data transfer object for a DID.
+type DIDDTO struct {
+	DID       string    `json:"did"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	Enabled   bool      `json:"enabled"`
+	AgentID   string    `json:"agent_id,omitempty"`
+	FlowID    string    `json:"flow_id,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ListDIDsResult lists the DIDs of a tenant.
+type ListDIDsResult struct {
+	DIDs []*DIDDTO `json:"dids"`
+}
+
+// toDTO converts a domain DID to a DTO.
+func toDTO(d *did.DID) *DIDDTO {
+	return &DIDDTO{
+		DID:       d.Number,
+		TenantID:  d.TenantID,
+		Enabled:   d.Enabled,
+		AgentID:   d.AgentID,
+		FlowID:    d.FlowID,
+		Label:     d.Label,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+}
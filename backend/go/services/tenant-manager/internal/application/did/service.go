@@ -0,0 +1,268 @@
+// Package did contains the application layer for DID registration.
+package did
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	phone "github.com/serphona/serphona/backend/go/libs/platform-phone"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/did"
+)
+
+// Service implements DID registration use cases.
+type Service struct {
+	repo        did.Repository
+	auditLogger *audit.Logger
+	logger      *zap.Logger
+}
+
+// NewService creates a new DID service.
+func NewService(repo did.Repository, auditLogger *audit.Logger, logger *zap.Logger) *Service {
+	return &Service{
+		repo:        repo,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// BulkImport normalizes each number to E.164 and assigns the valid ones to
+// the tenant in a single transaction. Invalid numbers, repeats within the
+// batch and numbers held by another tenant fail on their own without
+// affecting the rest; numbers the tenant already holds are reported as
+// existing, so a batch can be retried.
+func (s *Service) BulkImport(ctx context.Context, tenantID uuid.UUID, cmd BulkImportCommand) (*BulkImportResult, error) {
+	if len(cmd.Numbers) == 0 {
+		return nil, apperrors.NewValidationError("at least one number is required")
+	}
+	if len(cmd.Numbers) > MaxBulkImport {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("at most %d numbers can be imported at once, got %d", MaxBulkImport, len(cmd.Numbers)))
+	}
+
+	result := &BulkImportResult{Results: make([]NumberResult, len(cmd.Numbers))}
+	pending := make(map[string]int, len(cmd.Numbers)) // E.164 -> index of its result
+	var numbers []string
+	for i, input := range cmd.Numbers {
+		r := &result.Results[i]
+		r.Input = input
+
+		number, err := phone.Normalize(input, cmd.Region)
+		if err != nil {
+			r.Status, r.Reason, r.Message = StatusFailed, ReasonInvalidNumber, err.Error()
+			continue
+		}
+		r.Number = number
+		if first, ok := pending[number]; ok {
+			r.Status, r.Reason = StatusFailed, ReasonDuplicate
+			r.Message = "same number as entry " + strconv.Itoa(first+1)
+			continue
+		}
+		pending[number] = i
+		numbers = append(numbers, number)
+	}
+
+	if len(numbers) > 0 {
+		assignments, err := s.repo.AssignBatch(ctx, tenantID, numbers)
+		if err != nil {
+			s.logger.Error("failed to assign DIDs", zap.String("tenant_id", tenantID.String()), zap.Error(err))
+			return nil, apperrors.NewInternalError("failed to import DIDs")
+		}
+		for _, a := range assignments {
+			r := &result.Results[pending[a.Number]]
+			switch {
+			case a.Created:
+				r.Status = StatusCreated
+			case a.TenantID == tenantID:
+				r.Status = StatusExists
+			default:
+				r.Status, r.Reason = StatusFailed, ReasonAssignedToAnother
+				r.Message = "number is assigned to another tenant"
+			}
+		}
+	}
+
+	for _, r := range result.Results {
+		switch r.Status {
+		case StatusCreated:
+			result.Created++
+		case StatusExists:
+			result.Existed++
+		default:
+			result.Failed++
+		}
+	}
+
+	s.logger.Info("DIDs imported",
+		zap.String("tenant_id", tenantID.String()),
+		zap.Int("created", result.Created),
+		zap.Int("existed", result.Existed),
+		zap.Int("failed", result.Failed),
+	)
+	if result.Created > 0 {
+		s.auditLogger.Record(ctx, audit.Entry{
+			Action:     "did.bulk_imported",
+			TargetType: "tenant",
+			TargetID:   tenantID.String(),
+			TenantID:   tenantID.String(),
+			Metadata: map[string]string{
+				"created": strconv.Itoa(result.Created),
+				"failed":  strconv.Itoa(result.Failed),
+			},
+		})
+	}
+
+	return result, nil
+}
+
+// LookupDID returns a DID and its assignment whatever tenant holds it, for
+// routing calls to the number.
+func (s *Service) LookupDID(ctx context.Context, number string) (*DIDDTO, error) {
+	d, err := s.lookup(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(d), nil
+}
+
+// GetDID retrieves a DID of the tenant.
+func (s *Service) GetDID(ctx context.Context, tenantID uuid.UUID, number string) (*DIDDTO, error) {
+	d, err := s.get(ctx, tenantID, number)
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(d), nil
+}
+
+// lookup loads a DID whatever tenant holds it.
+func (s *Service) lookup(ctx context.Context, number string) (*did.DID, error) {
+	normalized, err := normalize(number)
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.repo.GetByNumber(ctx, normalized)
+	if errors.Is(err, did.ErrNotFound) {
+		return nil, notFound(normalized)
+	}
+	if err != nil {
+		s.logger.Error("failed to look up DID", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to look up DID")
+	}
+	return d, nil
+}
+
+// get loads a DID of the tenant.
+func (s *Service) get(ctx context.Context, tenantID uuid.UUID, number string) (*did.DID, error) {
+	d, err := s.lookup(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if d.TenantID != tenantID {
+		// Other tenants' numbers are indistinguishable from unknown ones
+		return nil, notFound(d.Number)
+	}
+	return d, nil
+}
+
+// ListDIDs retrieves the tenant's DIDs.
+func (s *Service) ListDIDs(ctx context.Context, tenantID uuid.UUID) (*ListDIDsResult, error) {
+	dids, err := s.repo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		s.logger.Error("failed to list DIDs", zap.String("tenant_id", tenantID.String()), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to list DIDs")
+	}
+	result := &ListDIDsResult{DIDs: make([]*DIDDTO, 0, len(dids))}
+	for _, d := range dids {
+		result.DIDs = append(result.DIDs, toDTO(d))
+	}
+	return result, nil
+}
+
+// UpdateDID assigns a DID of the tenant to an agent or flow, labels it or
+// enables or disables it.
+func (s *Service) UpdateDID(ctx context.Context, tenantID uuid.UUID, number string, cmd UpdateDIDCommand) (*DIDDTO, error) {
+	d, err := s.get(ctx, tenantID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.AgentID != nil {
+		d.AgentID = *cmd.AgentID
+	}
+	if cmd.FlowID != nil {
+		d.FlowID = *cmd.FlowID
+	}
+	if cmd.Label != nil {
+		d.Label = *cmd.Label
+	}
+	if cmd.Enabled != nil {
+		d.Enabled = *cmd.Enabled
+	}
+	d.UpdatedAt = time.Now().UTC()
+
+	err = s.repo.Update(ctx, d)
+	if errors.Is(err, did.ErrNotFound) {
+		// Released concurrently
+		return nil, notFound(d.Number)
+	}
+	if err != nil {
+		s.logger.Error("failed to update DID", zap.String("did", d.Number), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to update DID")
+	}
+
+	s.auditLogger.Record(ctx, audit.Entry{
+		Action:     "did.updated",
+		TargetType: "did",
+		TargetID:   d.Number,
+		TenantID:   tenantID.String(),
+		Metadata: map[string]string{
+			"agent_id": d.AgentID,
+			"flow_id":  d.FlowID,
+			"enabled":  strconv.FormatBool(d.Enabled),
+		},
+	})
+	return toDTO(d), nil
+}
+
+// DeleteDID releases a DID of the tenant.
+func (s *Service) DeleteDID(ctx context.Context, tenantID uuid.UUID, number string) error {
+	normalized, err := normalize(number)
+	if err != nil {
+		return err
+	}
+	err = s.repo.Delete(ctx, tenantID, normalized)
+	if errors.Is(err, did.ErrNotFound) {
+		return notFound(normalized)
+	}
+	if err != nil {
+		s.logger.Error("failed to delete DID", zap.String("did", normalized), zap.Error(err))
+		return apperrors.NewInternalError("failed to delete DID")
+	}
+
+	s.auditLogger.Record(ctx, audit.Entry{
+		Action:     "did.deleted",
+		TargetType: "did",
+		TargetID:   normalized,
+		TenantID:   tenantID.String(),
+	})
+	return nil
+}
+
+// normalize parses a DID given in E.164.
+func normalize(number string) (string, error) {
+	normalized, err := phone.Normalize(number, "")
+	if err != nil {
+		return "", apperrors.NewValidationError(err.Error())
+	}
+	return normalized, nil
+}
+
+func notFound(number string) error {
+	return apperrors.NewNotFoundError(fmt.Sprintf("DID %s not found", number))
+}
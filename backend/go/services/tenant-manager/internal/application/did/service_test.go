@@ -0,0 +1,264 @@
+package did
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/did"
+)
+
+// memRepo is an in-memory did.Repository.
+type memRepo struct {
+	mu      sync.Mutex
+	dids    map[string]*did.DID
+	batches int
+	err     error
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{dids: make(map[string]*did.DID)}
+}
+
+func (r *memRepo) add(number string, tenantID uuid.UUID) {
+	r.dids[number] = &did.DID{Number: number, TenantID: tenantID, Enabled: true}
+}
+
+func (r *memRepo) owner(number string) uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d, ok := r.dids[number]; ok {
+		return d.TenantID
+	}
+	return uuid.Nil
+}
+
+func (r *memRepo) AssignBatch(_ context.Context, tenantID uuid.UUID, numbers []string) ([]did.Assignment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.batches++
+	var assignments []did.Assignment
+	for _, n := range numbers {
+		d, ok := r.dids[n]
+		if !ok {
+			r.add(n, tenantID)
+			d = r.dids[n]
+		}
+		assignments = append(assignments, did.Assignment{Number: n, TenantID: d.TenantID, Created: !ok})
+	}
+	return assignments, nil
+}
+
+func (r *memRepo) GetByNumber(_ context.Context, number string) (*did.DID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.dids[number]
+	if !ok {
+		return nil, did.ErrNotFound
+	}
+	found := *d
+	return &found, nil
+}
+
+func (r *memRepo) ListByTenant(_ context.Context, tenantID uuid.UUID) ([]*did.DID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var dids []*did.DID
+	for _, d := range r.dids {
+		if d.TenantID == tenantID {
+			found := *d
+			dids = append(dids, &found)
+		}
+	}
+	return dids, nil
+}
+
+func (r *memRepo) Update(_ context.Context, d *did.DID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.dids[d.Number]; !ok || existing.TenantID != d.TenantID {
+		return did.ErrNotFound
+	}
+	saved := *d
+	r.dids[d.Number] = &saved
+	return nil
+}
+
+func (r *memRepo) Delete(_ context.Context, tenantID uuid.UUID, number string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.dids[number]; !ok || existing.TenantID != tenantID {
+		return did.ErrNotFound
+	}
+	delete(r.dids, number)
+	return nil
+}
+
+func TestBulkImport_MixedBatch(t *testing.T) {
+	repo := newMemRepo()
+	svc := NewService(repo, nil, zap.NewNop())
+	tenantID, otherID := uuid.New(), uuid.New()
+	repo.add("+551140000001", tenantID)
+	repo.add("+551140000002", otherID)
+
+	result, err := svc.BulkImport(context.Background(), tenantID, BulkImportCommand{
+		Region: "BR",
+		Numbers: []string{
+			"+55 11 4000-0000", // new
+			"(11) 4000-0003",   // new, national format
+			"+551140000000",    // same as the first
+			"+551140000001",    // already the tenant's
+			"+551140000002",    // another tenant's
+			"not a number",
+			"",
+		},
+	})
+	if err != nil {
+		t.Fatalf("BulkImport failed: %v", err)
+	}
+
+	want := []struct{ number, status, reason string }{
+		{"+551140000000", StatusCreated, ""},
+		{"+551140000003", StatusCreated, ""},
+		{"+551140000000", StatusFailed, ReasonDuplicate},
+		{"+551140000001", StatusExists, ""},
+		{"+551140000002", StatusFailed, ReasonAssignedToAnother},
+		{"", StatusFailed, ReasonInvalidNumber},
+		{"", StatusFailed, ReasonInvalidNumber},
+	}
+	if len(result.Results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(result.Results))
+	}
+	for i, w := range want {
+		got := result.Results[i]
+		if got.Number != w.number || got.Status != w.status || got.Reason != w.reason {
+			t.Errorf("result %d (%q): got %s %s %s, want %s %s %s", i, got.Input, got.Number, got.Status, got.Reason, w.number, w.status, w.reason)
+		}
+	}
+	if result.Created != 2 || result.Existed != 1 || result.Failed != 4 {
+		t.Errorf("expected 2 created, 1 existed and 4 failed, got %+v", result)
+	}
+
+	if repo.batches != 1 {
+		t.Errorf("expected one batch assignment, got %d", repo.batches)
+	}
+	if repo.owner("+551140000002") != otherID {
+		t.Error("expected another tenant's number to stay with it")
+	}
+	if repo.owner("+551140000003") != tenantID {
+		t.Error("expected the national-format number assigned to the tenant")
+	}
+}
+
+func TestBulkImport_RetryReportsExisting(t *testing.T) {
+	svc := NewService(newMemRepo(), nil, zap.NewNop())
+	tenantID := uuid.New()
+	cmd := BulkImportCommand{Numbers: []string{"+14155550100", "+14155550101"}}
+
+	if _, err := svc.BulkImport(context.Background(), tenantID, cmd); err != nil {
+		t.Fatalf("first BulkImport failed: %v", err)
+	}
+	result, err := svc.BulkImport(context.Background(), tenantID, cmd)
+	if err != nil {
+		t.Fatalf("second BulkImport failed: %v", err)
+	}
+	if result.Created != 0 || result.Existed != 2 || result.Failed != 0 {
+		t.Errorf("expected both numbers reported existing, got %+v", result)
+	}
+}
+
+func TestBulkImport_BatchSize(t *testing.T) {
+	repo := newMemRepo()
+	svc := NewService(repo, nil, zap.NewNop())
+
+	tests := map[string]int{"empty": 0, "over the cap": MaxBulkImport + 1}
+	for name, size := range tests {
+		t.Run(name, func(t *testing.T) {
+			numbers := strings.Split(strings.Repeat("+14155550100,", size), ",")[:size]
+			_, err := svc.BulkImport(context.Background(), uuid.New(), BulkImportCommand{Numbers: numbers})
+			appErr, ok := apperrors.As(err)
+			if !ok || appErr.Code != apperrors.ErrValidation {
+				t.Errorf("expected a validation error, got %v", err)
+			}
+		})
+	}
+	if repo.batches != 0 {
+		t.Errorf("expected no assignment, got %d batches", repo.batches)
+	}
+}
+
+func TestBulkImport_RepositoryError(t *testing.T) {
+	repo := newMemRepo()
+	repo.err = errors.New("connection refused")
+	svc := NewService(repo, nil, zap.NewNop())
+
+	_, err := svc.BulkImport(context.Background(), uuid.New(), BulkImportCommand{Numbers: []string{"+14155550100"}})
+	if appErr, ok := apperrors.As(err); !ok || appErr.Code != apperrors.ErrInternal {
+		t.Errorf("expected an internal error, got %v", err)
+	}
+}
+
+func TestUpdateDID_AssignsAgent(t *testing.T) {
+	repo := newMemRepo()
+	svc := NewService(repo, nil, zap.NewNop())
+	tenantID := uuid.New()
+	repo.add("+551140001000", tenantID)
+
+	agentID, label := "sales-agent", "Sales line"
+	updated, err := svc.UpdateDID(context.Background(), tenantID, "+551140001000", UpdateDIDCommand{AgentID: &agentID, Label: &label})
+	if err != nil {
+		t.Fatalf("UpdateDID failed: %v", err)
+	}
+	if updated.AgentID != agentID || updated.Label != label || !updated.Enabled {
+		t.Errorf("expected the DID assigned to %s and labeled, got %+v", agentID, updated)
+	}
+
+	found, err := svc.LookupDID(context.Background(), "+551140001000")
+	if err != nil {
+		t.Fatalf("LookupDID failed: %v", err)
+	}
+	if found.TenantID != tenantID || found.AgentID != agentID || found.FlowID != "" {
+		t.Errorf("expected the lookup to return the assignment, got %+v", found)
+	}
+
+	// An empty agent_id clears the assignment
+	none := ""
+	cleared, err := svc.UpdateDID(context.Background(), tenantID, "+551140001000", UpdateDIDCommand{AgentID: &none})
+	if err != nil {
+		t.Fatalf("UpdateDID failed: %v", err)
+	}
+	if cleared.AgentID != "" || cleared.Label != label {
+		t.Errorf("expected the agent cleared and the label kept, got %+v", cleared)
+	}
+}
+
+func TestDIDs_OtherTenantNotFound(t *testing.T) {
+	repo := newMemRepo()
+	svc := NewService(repo, nil, zap.NewNop())
+	ownerID, otherID := uuid.New(), uuid.New()
+	repo.add("+551140001000", ownerID)
+
+	agentID := "intruder-agent"
+	_, err := svc.UpdateDID(context.Background(), otherID, "+551140001000", UpdateDIDCommand{AgentID: &agentID})
+	if appErr, ok := apperrors.As(err); !ok || appErr.Code != apperrors.ErrNotFound {
+		t.Errorf("expected not found updating another tenant's DID, got %v", err)
+	}
+	if _, err := svc.GetDID(context.Background(), otherID, "+551140001000"); err == nil {
+		t.Error("expected another tenant's DID hidden")
+	}
+	if err := svc.DeleteDID(context.Background(), otherID, "+551140001000"); err == nil {
+		t.Error("expected another tenant unable to release the DID")
+	}
+	if repo.owner("+551140001000") != ownerID {
+		t.Error("expected the DID to stay with its tenant")
+	}
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/domain/export"
+)
+
+// RequestExportCommand represents the command to request a data export.
+type RequestExportCommand struct {
+	TenantID    uuid.UUID `json:"tenant_id"`
+	CustomerID  string    `json:"customer_id,omitempty"`
+	RequestedBy string    `json:"requested_by,omitempty"`
+}
+
+// JobDTO is the data transfer object for an export job.
+type JobDTO struct {
+	ID          uuid.UUID               `json:"id"`
+	TenantID    uuid.UUID               `json:"tenant_id"`
+	CustomerID  string                  `json:"customer_id,omitempty"`
+	Status      string                  `json:"status"`
+	Progress    int                     `json:"progress"` // percentage of sources collected
+	Sources     []export.SourceProgress `json:"sources"`
+	Error       string                  `json:"error,omitempty"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+	CompletedAt *time.Time              `json:"completed_at,omitempty"`
+}
+
+// toJobDTO converts a domain job to a DTO.
+func toJobDTO(j *export.Job) *JobDTO {
+	return &JobDTO{
+		ID:          j.ID,
+		TenantID:    j.TenantID,
+		CustomerID:  j.CustomerID,
+		Status:      string(j.Status),
+		Progress:    j.Progress(),
+		Sources:     append([]export.SourceProgress(nil), j.Sources...),
+		Error:       j.Error,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+		CompletedAt: j.CompletedAt,
+	}
+}
@@ -0,0 +1,276 @@
+// Package export contains the application layer for tenant data exports
+// (GDPR subject-access requests).
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/export"
+	"tenant-manager/internal/domain/tenant"
+)
+
+// Subject identifies whose data an export gathers.
+type Subject struct {
+	TenantID   uuid.UUID
+	CustomerID string // empty gathers the whole tenant
+}
+
+// Source collects the data one service holds about a subject.
+type Source interface {
+	// Name identifies the source and names its file in the archive.
+	Name() string
+
+	// Collect returns the subject's data as JSON.
+	Collect(ctx context.Context, subject Subject) (json.RawMessage, error)
+}
+
+// ArchiveStore stores the archives produced by export jobs.
+type ArchiveStore interface {
+	// Create opens a new archive under key for writing.
+	Create(ctx context.Context, key string) (io.WriteCloser, error)
+
+	// Open opens the archive stored under key for reading.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Service implements data export use cases. Jobs run in the background, one
+// source at a time, saving progress after each source.
+type Service struct {
+	repo        export.Repository
+	tenants     tenant.Repository
+	archives    ArchiveStore
+	sources     []Source
+	timeout     time.Duration
+	auditLogger *audit.Logger
+	logger      *zap.Logger
+	running     sync.WaitGroup
+}
+
+// NewService creates a new export service. The tenant record is always
+// exported; sources adds the data held by other services.
+func NewService(
+	repo export.Repository,
+	tenants tenant.Repository,
+	archives ArchiveStore,
+	sources []Source,
+	timeout time.Duration,
+	auditLogger *audit.Logger,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		repo:        repo,
+		tenants:     tenants,
+		archives:    archives,
+		sources:     append([]Source{&tenantSource{repo: tenants}}, sources...),
+		timeout:     timeout,
+		auditLogger: auditLogger,
+		logger:      logger,
+	}
+}
+
+// RequestExport creates an export job and starts it in the background.
+func (s *Service) RequestExport(ctx context.Context, cmd RequestExportCommand) (*JobDTO, error) {
+	if _, err := s.tenants.GetByID(ctx, cmd.TenantID); err != nil {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("tenant with id %s not found", cmd.TenantID))
+	}
+
+	names := make([]string, len(s.sources))
+	for i, src := range s.sources {
+		names[i] = src.Name()
+	}
+
+	job := export.NewJob(cmd.TenantID, cmd.CustomerID, cmd.RequestedBy, names)
+	if err := s.repo.Create(ctx, job); err != nil {
+		s.logger.Error("failed to create export job", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to create export job")
+	}
+
+	metadata := map[string]string{}
+	if cmd.CustomerID != "" {
+		metadata["customer_id"] = cmd.CustomerID
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		Action:     "tenant.export_requested",
+		TargetType: "export_job",
+		TargetID:   job.ID.String(),
+		TenantID:   cmd.TenantID.String(),
+		Metadata:   metadata,
+	})
+
+	dto := toJobDTO(job)
+
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.run(job)
+	}()
+
+	return dto, nil
+}
+
+// GetExport retrieves an export job of the tenant.
+func (s *Service) GetExport(ctx context.Context, tenantID, jobID uuid.UUID) (*JobDTO, error) {
+	job, err := s.getJob(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return toJobDTO(job), nil
+}
+
+// OpenArchive opens the archive of a completed export job. The caller must
+// close it.
+func (s *Service) OpenArchive(ctx context.Context, tenantID, jobID uuid.UUID) (io.ReadCloser, error) {
+	job, err := s.getJob(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != export.StatusCompleted {
+		return nil, apperrors.NewConflictError(fmt.Sprintf("export %s is %s", job.ID, job.Status))
+	}
+
+	archive, err := s.archives.Open(ctx, job.ArchiveKey)
+	if err != nil {
+		s.logger.Error("failed to open export archive", zap.String("job_id", job.ID.String()), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to open export archive")
+	}
+	return archive, nil
+}
+
+// Wait blocks until every running export job finishes.
+func (s *Service) Wait() {
+	s.running.Wait()
+}
+
+// getJob loads a job, hiding jobs of other tenants.
+func (s *Service) getJob(ctx context.Context, tenantID, jobID uuid.UUID) (*export.Job, error) {
+	job, err := s.repo.GetByID(ctx, jobID)
+	if errors.Is(err, export.ErrJobNotFound) || (err == nil && job.TenantID != tenantID) {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("export with id %s not found", jobID))
+	}
+	if err != nil {
+		s.logger.Error("failed to get export job", zap.String("job_id", jobID.String()), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get export job")
+	}
+	return job, nil
+}
+
+// run collects every source into the job's archive.
+func (s *Service) run(job *export.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	logger := s.logger.With(zap.String("job_id", job.ID.String()), zap.String("tenant_id", job.TenantID.String()))
+
+	job.Start()
+	s.save(ctx, job, logger)
+
+	key := fmt.Sprintf("%s/%s.zip", job.TenantID, job.ID)
+	if err := s.writeArchive(ctx, job, key, logger); err != nil {
+		logger.Error("export failed", zap.Error(err))
+		job.Fail(err)
+	} else {
+		job.Complete(key)
+	}
+	s.save(ctx, job, logger)
+}
+
+// writeArchive writes one <source>.json file per collected source plus a
+// manifest listing what each source returned.
+func (s *Service) writeArchive(ctx context.Context, job *export.Job, key string, logger *zap.Logger) error {
+	w, err := s.archives.Create(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer w.Close()
+
+	zw := zip.NewWriter(w)
+	subject := Subject{TenantID: job.TenantID, CustomerID: job.CustomerID}
+
+	collected := 0
+	for _, src := range s.sources {
+		data, err := src.Collect(ctx, subject)
+		if err == nil {
+			err = writeFile(zw, src.Name()+".json", data)
+		}
+		if err != nil {
+			logger.Warn("export source failed", zap.String("source", src.Name()), zap.Error(err))
+		} else {
+			collected++
+		}
+		job.RecordSource(src.Name(), err)
+		s.save(ctx, job, logger)
+	}
+	if collected == 0 {
+		return errors.New("no source could be collected")
+	}
+
+	manifest, err := json.MarshalIndent(map[string]any{
+		"job_id":       job.ID,
+		"tenant_id":    job.TenantID,
+		"customer_id":  job.CustomerID,
+		"generated_at": time.Now().UTC(),
+		"sources":      job.Sources,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := writeFile(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to close archive: %w", err)
+	}
+	return nil
+}
+
+// save persists job progress. Failures are logged, since the job keeps
+// running either way.
+func (s *Service) save(ctx context.Context, job *export.Job, logger *zap.Logger) {
+	if err := s.repo.Update(context.WithoutCancel(ctx), job); err != nil {
+		logger.Warn("failed to save export progress", zap.Error(err))
+	}
+}
+
+func writeFile(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// tenantSource exports the tenant record held by this service.
+type tenantSource struct {
+	repo tenant.Repository
+}
+
+func (s *tenantSource) Name() string {
+	return "tenant"
+}
+
+func (s *tenantSource) Collect(ctx context.Context, subject Subject) (json.RawMessage, error) {
+	t, err := s.repo.GetByID(ctx, subject.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(t, "", "  ")
+}
@@ -0,0 +1,278 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/export"
+	"tenant-manager/internal/domain/tenant"
+)
+
+// tenantRepo is a tenant.Repository holding a single tenant.
+type tenantRepo struct {
+	tenant.Repository
+	tenant *tenant.Tenant
+}
+
+func (r *tenantRepo) GetByID(_ context.Context, id uuid.UUID) (*tenant.Tenant, error) {
+	if r.tenant == nil || r.tenant.ID != id {
+		return nil, errors.New("tenant not found")
+	}
+	return r.tenant, nil
+}
+
+// jobRepo keeps every saved version of each job.
+type jobRepo struct {
+	mu      sync.Mutex
+	history map[uuid.UUID][]export.Job
+}
+
+func newJobRepo() *jobRepo {
+	return &jobRepo{history: make(map[uuid.UUID][]export.Job)}
+}
+
+func (r *jobRepo) Create(ctx context.Context, job *export.Job) error {
+	return r.Update(ctx, job)
+}
+
+func (r *jobRepo) GetByID(_ context.Context, id uuid.UUID) (*export.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := r.history[id]
+	if len(versions) == 0 {
+		return nil, export.ErrJobNotFound
+	}
+	job := versions[len(versions)-1]
+	return &job, nil
+}
+
+func (r *jobRepo) Update(_ context.Context, job *export.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := *job
+	saved.Sources = append([]export.SourceProgress(nil), job.Sources...)
+	r.history[job.ID] = append(r.history[job.ID], saved)
+	return nil
+}
+
+// memArchives keeps archives in memory.
+type memArchives struct {
+	mu    sync.Mutex
+	files map[string]*bytes.Buffer
+}
+
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+func (a *memArchives) Create(_ context.Context, key string) (io.WriteCloser, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	buf := &bytes.Buffer{}
+	a.files[key] = buf
+	return nopCloser{buf}, nil
+}
+
+func (a *memArchives) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	buf, ok := a.files[key]
+	if !ok {
+		return nil, errors.New("archive not found")
+	}
+	return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+}
+
+// stubSource returns canned data and records the subjects it was asked for.
+type stubSource struct {
+	name     string
+	data     string
+	err      error
+	subjects []Subject
+}
+
+func (s *stubSource) Name() string { return s.name }
+
+func (s *stubSource) Collect(_ context.Context, subject Subject) (json.RawMessage, error) {
+	s.subjects = append(s.subjects, subject)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return json.RawMessage(s.data), nil
+}
+
+func newTestService(t *testing.T, sources ...Source) (*Service, *jobRepo, *tenant.Tenant) {
+	t.Helper()
+	tn := tenant.NewTenant("Acme", "ops@acme.com", tenant.PlanProfessional)
+	repo := newJobRepo()
+	archives := &memArchives{files: make(map[string]*bytes.Buffer)}
+	svc := NewService(repo, &tenantRepo{tenant: tn}, archives, sources, time.Minute, nil, zap.NewNop())
+	return svc, repo, tn
+}
+
+func readArchive(t *testing.T, svc *Service, tenantID, jobID uuid.UUID) map[string]string {
+	t.Helper()
+	rc, err := svc.OpenArchive(context.Background(), tenantID, jobID)
+	if err != nil {
+		t.Fatalf("OpenArchive: %v", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestRequestExport_CollectsEverySourceIntoArchive(t *testing.T) {
+	calls := &stubSource{name: "calls", data: `[{"id":"c1"}]`}
+	billing := &stubSource{name: "billing", data: `{"invoices":[]}`}
+	svc, repo, tn := newTestService(t, calls, billing)
+
+	job, err := svc.RequestExport(context.Background(), RequestExportCommand{
+		TenantID:   tn.ID,
+		CustomerID: "cust-42",
+	})
+	if err != nil {
+		t.Fatalf("RequestExport: %v", err)
+	}
+	if job.Status != string(export.StatusPending) {
+		t.Errorf("new job status = %s, want pending", job.Status)
+	}
+	svc.Wait()
+
+	got, err := svc.GetExport(context.Background(), tn.ID, job.ID)
+	if err != nil {
+		t.Fatalf("GetExport: %v", err)
+	}
+	if got.Status != string(export.StatusCompleted) || got.Progress != 100 {
+		t.Fatalf("job = %s at %d%%, want completed at 100%%", got.Status, got.Progress)
+	}
+
+	subject := Subject{TenantID: tn.ID, CustomerID: "cust-42"}
+	if len(calls.subjects) != 1 || calls.subjects[0] != subject {
+		t.Errorf("calls collected for %v, want %v", calls.subjects, subject)
+	}
+
+	files := readArchive(t, svc, tn.ID, job.ID)
+	for _, name := range []string{"tenant.json", "calls.json", "billing.json", "manifest.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("archive missing %s", name)
+		}
+	}
+	if files["calls.json"] != calls.data {
+		t.Errorf("calls.json = %s, want %s", files["calls.json"], calls.data)
+	}
+
+	// Progress was saved after each source
+	var progress []int
+	for _, v := range repo.history[job.ID] {
+		progress = append(progress, v.Progress())
+	}
+	want := []int{0, 0, 33, 66, 100, 100}
+	if len(progress) != len(want) {
+		t.Fatalf("saved progress = %v, want %v", progress, want)
+	}
+	for i := range want {
+		if progress[i] != want[i] {
+			t.Fatalf("saved progress = %v, want %v", progress, want)
+		}
+	}
+}
+
+func TestRequestExport_FailingSourceDoesNotFailJob(t *testing.T) {
+	calls := &stubSource{name: "calls", data: `[]`}
+	events := &stubSource{name: "events", err: errors.New("analytics unavailable")}
+	svc, _, tn := newTestService(t, calls, events)
+
+	job, err := svc.RequestExport(context.Background(), RequestExportCommand{TenantID: tn.ID})
+	if err != nil {
+		t.Fatalf("RequestExport: %v", err)
+	}
+	svc.Wait()
+
+	got, err := svc.GetExport(context.Background(), tn.ID, job.ID)
+	if err != nil {
+		t.Fatalf("GetExport: %v", err)
+	}
+	if got.Status != string(export.StatusCompleted) {
+		t.Fatalf("job status = %s, want completed", got.Status)
+	}
+	for _, s := range got.Sources {
+		if s.Name == "events" && (s.Status != export.StatusFailed || s.Error != "analytics unavailable") {
+			t.Errorf("events source = %+v, want failed with its error", s)
+		}
+	}
+
+	files := readArchive(t, svc, tn.ID, job.ID)
+	if _, ok := files["events.json"]; ok {
+		t.Error("archive should not hold the failed source")
+	}
+}
+
+func TestRequestExport_FailsWhenNothingCollected(t *testing.T) {
+	svc, _, tn := newTestService(t)
+	svc.sources = []Source{&stubSource{name: "calls", err: errors.New("timeout")}}
+
+	job, err := svc.RequestExport(context.Background(), RequestExportCommand{TenantID: tn.ID})
+	if err != nil {
+		t.Fatalf("RequestExport: %v", err)
+	}
+	svc.Wait()
+
+	got, _ := svc.GetExport(context.Background(), tn.ID, job.ID)
+	if got.Status != string(export.StatusFailed) {
+		t.Fatalf("job status = %s, want failed", got.Status)
+	}
+	if _, err := svc.OpenArchive(context.Background(), tn.ID, job.ID); err == nil {
+		t.Error("failed export should not be downloadable")
+	}
+}
+
+func TestRequestExport_UnknownTenant(t *testing.T) {
+	svc, _, _ := newTestService(t)
+
+	if _, err := svc.RequestExport(context.Background(), RequestExportCommand{TenantID: uuid.New()}); err == nil {
+		t.Fatal("expected error for unknown tenant")
+	}
+}
+
+func TestGetExport_HidesOtherTenantsJobs(t *testing.T) {
+	svc, _, tn := newTestService(t)
+
+	job, err := svc.RequestExport(context.Background(), RequestExportCommand{TenantID: tn.ID})
+	if err != nil {
+		t.Fatalf("RequestExport: %v", err)
+	}
+	svc.Wait()
+
+	if _, err := svc.GetExport(context.Background(), uuid.New(), job.ID); err == nil {
+		t.Fatal("expected not found for another tenant")
+	}
+}
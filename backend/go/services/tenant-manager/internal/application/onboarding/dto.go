@@ -0,0 +1,98 @@
+package onboarding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	phone "github.com/serphona/serphona/backend/go/libs/platform-phone"
+
+	tenantapp "tenant-manager/internal/application/tenant"
+	"tenant-manager/internal/domain/onboarding"
+)
+
+// OnboardCommand represents the command to onboard a tenant.
+type OnboardCommand struct {
+	// IdempotencyKey identifies the request; repeating it returns the outcome
+	// of the first run instead of onboarding again
+	IdempotencyKey string `json:"-"`
+
+	Tenant tenantapp.CreateTenantCommand `json:"tenant"`
+
+	// Quota overrides the plan's default limits
+	Quota *QuotaOverrides `json:"quota,omitempty"`
+
+	// PhoneNumber is the DID to register for the tenant, if any. It is
+	// registered in E.164 form.
+	PhoneNumber string `json:"phone_number,omitempty"`
+
+	// PhoneRegion is the country (ISO 3166-1 alpha-2) of a PhoneNumber
+	// written without a country code
+	PhoneRegion string `json:"phone_region,omitempty"`
+
+	Admin AdminCommand `json:"admin"`
+}
+
+// QuotaOverrides replaces some of the plan's quota limits; -1 is unlimited.
+type QuotaOverrides struct {
+	MaxAPIKeys         *int `json:"max_api_keys,omitempty"`
+	MaxUsers           *int `json:"max_users,omitempty"`
+	MaxCallsPerMonth   *int `json:"max_calls_per_month,omitempty"`
+	MaxMinutesPerMonth *int `json:"max_minutes_per_month,omitempty"`
+	MaxStorageGB       *int `json:"max_storage_gb,omitempty"`
+}
+
+// AdminCommand describes the tenant's first admin user.
+type AdminCommand struct {
+	Email string `json:"email,omitempty"` // defaults to the tenant email
+	Name  string `json:"name,omitempty"`
+}
+
+// Validate validates the onboard command.
+func (cmd OnboardCommand) Validate() error {
+	if strings.TrimSpace(cmd.IdempotencyKey) == "" {
+		return errors.New("idempotency key is required")
+	}
+	if err := cmd.Tenant.Validate(); err != nil {
+		return err
+	}
+	if cmd.PhoneNumber != "" {
+		if _, err := phone.Normalize(cmd.PhoneNumber, cmd.PhoneRegion); err != nil {
+			return fmt.Errorf("phone_number: %w", err)
+		}
+	}
+	if q := cmd.Quota; q != nil {
+		for _, limit := range []*int{q.MaxAPIKeys, q.MaxUsers, q.MaxCallsPerMonth, q.MaxMinutesPerMonth, q.MaxStorageGB} {
+			if limit != nil && *limit < -1 {
+				return errors.New("quota limits must be -1 (unlimited) or greater")
+			}
+		}
+	}
+	return nil
+}
+
+// OnboardingDTO is the data transfer object for an onboarding.
+type OnboardingDTO struct {
+	ID         uuid.UUID            `json:"id"`
+	Status     string               `json:"status"`
+	Resources  onboarding.Resources `json:"resources"`
+	FailedStep string               `json:"failed_step,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	CreatedAt  time.Time            `json:"created_at"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+}
+
+// toDTO converts a domain onboarding to a DTO.
+func toDTO(o *onboarding.Onboarding) *OnboardingDTO {
+	return &OnboardingDTO{
+		ID:         o.ID,
+		Status:     string(o.Status),
+		Resources:  o.Resources,
+		FailedStep: string(o.FailedStep),
+		Error:      o.Error,
+		CreatedAt:  o.CreatedAt,
+		FinishedAt: o.FinishedAt,
+	}
+}
@@ -0,0 +1,331 @@
+// Package onboarding contains the application layer for tenant onboarding:
+// the saga that creates a tenant and everything it needs to take calls.
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	phone "github.com/serphona/serphona/backend/go/libs/platform-phone"
+	"go.uber.org/zap"
+
+	tenantapp "tenant-manager/internal/application/tenant"
+	"tenant-manager/internal/domain/onboarding"
+	"tenant-manager/internal/domain/tenant"
+)
+
+// BillingCustomers manages the tenants' customers in the billing provider
+// (Stripe).
+type BillingCustomers interface {
+	// CreateCustomer creates the tenant's billing customer and returns its ID.
+	// Calls with the same idempotency key create a single customer.
+	CreateCustomer(ctx context.Context, t *tenant.Tenant, idempotencyKey string) (string, error)
+
+	// DeleteCustomer deletes a billing customer; deleting a missing one
+	// succeeds.
+	DeleteCustomer(ctx context.Context, customerID string) error
+}
+
+// PhoneNumbers registers the DIDs that route calls to tenants.
+type PhoneNumbers interface {
+	// Register assigns the DID to the tenant. Registering a DID the tenant
+	// already holds succeeds.
+	Register(ctx context.Context, tenantID uuid.UUID, did, idempotencyKey string) error
+
+	// Release unassigns the DID from the tenant; releasing a DID the tenant
+	// doesn't hold succeeds.
+	Release(ctx context.Context, tenantID uuid.UUID, did string) error
+}
+
+// AdminUsers creates the tenants' users in the identity provider.
+type AdminUsers interface {
+	// CreateAdmin creates an admin user of the tenant and returns its ID.
+	// Calls with the same idempotency key create a single user.
+	CreateAdmin(ctx context.Context, tenantID uuid.UUID, email, name, idempotencyKey string) (string, error)
+
+	// DeleteUser deletes a user of the tenant; deleting a missing one
+	// succeeds.
+	DeleteUser(ctx context.Context, tenantID uuid.UUID, userID string) error
+}
+
+// step is one step of the saga and the compensation that undoes it.
+type step struct {
+	name onboarding.Step
+	run  func(ctx context.Context, o *onboarding.Onboarding) error
+	undo func(ctx context.Context, o *onboarding.Onboarding) error
+}
+
+// Service implements the onboarding saga. Steps run in order and the
+// onboarding is saved after each one; when a step fails, the completed ones
+// are undone in reverse order.
+type Service struct {
+	repo       onboarding.Repository
+	tenants    *tenantapp.Service
+	tenantRepo tenant.Repository
+	billing    BillingCustomers
+	numbers    PhoneNumbers
+	admins     AdminUsers
+	events     onboarding.EventPublisher
+	logger     *zap.Logger
+}
+
+// NewService creates a new onboarding service.
+func NewService(
+	repo onboarding.Repository,
+	tenants *tenantapp.Service,
+	tenantRepo tenant.Repository,
+	billing BillingCustomers,
+	numbers PhoneNumbers,
+	admins AdminUsers,
+	events onboarding.EventPublisher,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		repo:       repo,
+		tenants:    tenants,
+		tenantRepo: tenantRepo,
+		billing:    billing,
+		numbers:    numbers,
+		admins:     admins,
+		events:     events,
+		logger:     logger,
+	}
+}
+
+// Onboard creates a tenant with its quota, billing customer, phone number
+// and admin user, undoing what was created if any of them fails. Repeating
+// a completed onboarding's idempotency key returns its result again.
+func (s *Service) Onboard(ctx context.Context, cmd OnboardCommand) (*OnboardingDTO, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, apperrors.NewValidationError(err.Error())
+	}
+
+	o := onboarding.New(cmd.IdempotencyKey)
+	if err := s.repo.Create(ctx, o); err != nil {
+		if errors.Is(err, onboarding.ErrDuplicateKey) {
+			return s.previous(ctx, cmd.IdempotencyKey)
+		}
+		s.logger.Error("failed to create onboarding", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to start onboarding")
+	}
+
+	steps := s.steps(cmd)
+	for _, st := range steps {
+		err := st.run(ctx, o)
+		if err == nil {
+			o.CompleteStep(st.name)
+			err = s.repo.Update(ctx, o)
+		}
+		if err != nil {
+			s.logger.Error("onboarding step failed",
+				zap.String("onboarding_id", o.ID.String()),
+				zap.String("step", string(st.name)),
+				zap.Error(err),
+			)
+			o.Fail(st.name, err)
+			s.compensate(context.WithoutCancel(ctx), o, steps)
+			return nil, stepError(st.name, err)
+		}
+	}
+
+	o.Finish()
+	if err := s.repo.Update(ctx, o); err != nil {
+		s.logger.Error("failed to save completed onboarding", zap.String("onboarding_id", o.ID.String()), zap.Error(err))
+	}
+
+	if err := s.events.PublishOnboarded(ctx, o); err != nil {
+		s.logger.Error("failed to publish tenant onboarded event", zap.Error(err))
+		// Non-critical error, continue
+	}
+
+	return toDTO(o), nil
+}
+
+// previous returns the outcome of the onboarding already run under key.
+func (s *Service) previous(ctx context.Context, key string) (*OnboardingDTO, error) {
+	o, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		s.logger.Error("failed to get onboarding", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to get onboarding")
+	}
+
+	switch o.Status {
+	case onboarding.StatusCompleted:
+		return toDTO(o), nil
+	case onboarding.StatusRunning:
+		return nil, apperrors.NewConflictError(fmt.Sprintf("onboarding %s is still running", o.ID))
+	default:
+		return nil, apperrors.NewConflictError(fmt.Sprintf(
+			"onboarding %s failed at step %s and was %s; retry with a new idempotency key",
+			o.ID, o.FailedStep, strings.ReplaceAll(string(o.Status), "_", " "),
+		))
+	}
+}
+
+// compensate undoes the failed step, which may have partly succeeded, and the
+// completed ones in reverse order. A compensation that fails leaves its step
+// listed as completed, for manual cleanup, and the others are still undone.
+func (s *Service) compensate(ctx context.Context, o *onboarding.Onboarding, steps []step) {
+	var failed error
+	for i := len(steps) - 1; i >= 0; i-- {
+		st := steps[i]
+		if !o.Done(st.name) && st.name != o.FailedStep {
+			continue
+		}
+		if err := st.undo(ctx, o); err != nil {
+			s.logger.Error("failed to compensate onboarding step",
+				zap.String("onboarding_id", o.ID.String()),
+				zap.String("step", string(st.name)),
+				zap.Error(err),
+			)
+			failed = errors.Join(failed, fmt.Errorf("%s: %w", st.name, err))
+			continue
+		}
+		o.UndoStep(st.name)
+	}
+
+	if failed != nil {
+		o.CompensationFailed(failed)
+	} else {
+		o.RolledBack()
+	}
+	if err := s.repo.Update(ctx, o); err != nil {
+		s.logger.Error("failed to save compensated onboarding", zap.String("onboarding_id", o.ID.String()), zap.Error(err))
+	}
+}
+
+// steps returns the saga for cmd. Each step skips work already reflected in
+// the onboarding or the tenant, and passes the onboarding's step key to the
+// other services, so running it twice creates nothing twice.
+func (s *Service) steps(cmd OnboardCommand) []step {
+	steps := []step{
+		{name: onboarding.StepTenant, run: func(ctx context.Context, o *onboarding.Onboarding) error {
+			if o.Resources.TenantID != uuid.Nil {
+				return nil
+			}
+			t, err := s.tenants.CreateTenant(ctx, cmd.Tenant)
+			if err != nil {
+				return err
+			}
+			o.Resources.TenantID = t.ID
+			return nil
+		}, undo: func(ctx context.Context, o *onboarding.Onboarding) error {
+			if o.Resources.TenantID == uuid.Nil {
+				return nil
+			}
+			err := s.tenants.DeleteTenant(ctx, o.Resources.TenantID)
+			switch apperrors.CodeOf(err) {
+			case apperrors.ErrNotFound, apperrors.ErrValidation:
+				return nil // already deleted
+			}
+			return err
+		}},
+		{name: onboarding.StepQuota, run: func(ctx context.Context, o *onboarding.Onboarding) error {
+			t, err := s.tenantRepo.GetByID(ctx, o.Resources.TenantID)
+			if err != nil {
+				return err
+			}
+			return s.tenantRepo.UpdateQuota(ctx, cmd.Quota.apply(tenant.NewQuota(t.ID, t.Plan)))
+		}, undo: func(context.Context, *onboarding.Onboarding) error {
+			return nil // the quota goes with the tenant
+		}},
+		{name: onboarding.StepBillingCustomer, run: func(ctx context.Context, o *onboarding.Onboarding) error {
+			t, err := s.tenantRepo.GetByID(ctx, o.Resources.TenantID)
+			if err != nil {
+				return err
+			}
+			if t.StripeID == "" {
+				id, err := s.billing.CreateCustomer(ctx, t, o.StepKey(onboarding.StepBillingCustomer))
+				if err != nil {
+					return err
+				}
+				// Recorded before linking, so a failed link still deletes it
+				o.Resources.BillingCustomerID = id
+				t.StripeID = id
+				if err := s.tenantRepo.Update(ctx, t); err != nil {
+					return err
+				}
+			}
+			o.Resources.BillingCustomerID = t.StripeID
+			return nil
+		}, undo: func(ctx context.Context, o *onboarding.Onboarding) error {
+			if o.Resources.BillingCustomerID == "" {
+				return nil
+			}
+			return s.billing.DeleteCustomer(ctx, o.Resources.BillingCustomerID)
+		}},
+	}
+
+	if cmd.PhoneNumber != "" {
+		did, _ := phone.Normalize(cmd.PhoneNumber, cmd.PhoneRegion)
+		steps = append(steps, step{name: onboarding.StepPhoneNumber, run: func(ctx context.Context, o *onboarding.Onboarding) error {
+			// Recorded first: a failed call may still have registered it
+			o.Resources.PhoneNumber = did
+			return s.numbers.Register(ctx, o.Resources.TenantID, did, o.StepKey(onboarding.StepPhoneNumber))
+		}, undo: func(ctx context.Context, o *onboarding.Onboarding) error {
+			if o.Resources.PhoneNumber == "" {
+				return nil
+			}
+			return s.numbers.Release(ctx, o.Resources.TenantID, o.Resources.PhoneNumber)
+		}})
+	}
+
+	adminEmail := cmd.Admin.Email
+	if adminEmail == "" {
+		adminEmail = cmd.Tenant.Email
+	}
+	steps = append(steps, step{name: onboarding.StepAdminUser, run: func(ctx context.Context, o *onboarding.Onboarding) error {
+		if o.Resources.AdminUserID != "" {
+			return nil
+		}
+		id, err := s.admins.CreateAdmin(ctx, o.Resources.TenantID, adminEmail, cmd.Admin.Name, o.StepKey(onboarding.StepAdminUser))
+		if err != nil {
+			return err
+		}
+		o.Resources.AdminUserID = id
+		return nil
+	}, undo: func(ctx context.Context, o *onboarding.Onboarding) error {
+		if o.Resources.AdminUserID == "" {
+			return nil
+		}
+		return s.admins.DeleteUser(ctx, o.Resources.TenantID, o.Resources.AdminUserID)
+	}})
+
+	return steps
+}
+
+// apply replaces the limits of q that are overridden.
+func (q *QuotaOverrides) apply(quota *tenant.Quota) *tenant.Quota {
+	if q == nil {
+		return quota
+	}
+	for _, o := range []struct {
+		override *int
+		limit    *int
+	}{
+		{q.MaxAPIKeys, &quota.MaxAPIKeys},
+		{q.MaxUsers, &quota.MaxUsers},
+		{q.MaxCallsPerMonth, &quota.MaxCallsPerMonth},
+		{q.MaxMinutesPerMonth, &quota.MaxMinutesPerMonth},
+		{q.MaxStorageGB, &quota.MaxStorageGB},
+	} {
+		if o.override != nil {
+			*o.limit = *o.override
+		}
+	}
+	return quota
+}
+
+// stepError is what the caller gets when a step failed: client errors of the
+// tenant service (e.g. an email already in use) as they are, anything else
+// as an internal error naming the step.
+func stepError(name onboarding.Step, err error) error {
+	if appErr, ok := apperrors.As(err); ok && appErr.Code != apperrors.ErrInternal {
+		return appErr
+	}
+	return apperrors.NewInternalError(fmt.Sprintf("onboarding failed at step %s", name))
+}
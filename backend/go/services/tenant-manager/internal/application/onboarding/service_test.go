@@ -0,0 +1,403 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	tenantapp "tenant-manager/internal/application/tenant"
+	"tenant-manager/internal/domain/onboarding"
+	"tenant-manager/internal/domain/tenant"
+	"tenant-manager/internal/domain/tenant/tenanttest"
+)
+
+// onboardingRepo keeps onboardings in memory, keyed by idempotency key.
+type onboardingRepo struct {
+	mu    sync.Mutex
+	byKey map[string]onboarding.Onboarding
+}
+
+func (r *onboardingRepo) Create(_ context.Context, o *onboarding.Onboarding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byKey[o.IdempotencyKey]; ok {
+		return onboarding.ErrDuplicateKey
+	}
+	r.byKey[o.IdempotencyKey] = clone(o)
+	return nil
+}
+
+func (r *onboardingRepo) GetByKey(_ context.Context, key string) (*onboarding.Onboarding, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.byKey[key]
+	if !ok {
+		return nil, onboarding.ErrNotFound
+	}
+	return &o, nil
+}
+
+func (r *onboardingRepo) Update(_ context.Context, o *onboarding.Onboarding) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byKey[o.IdempotencyKey] = clone(o)
+	return nil
+}
+
+func clone(o *onboarding.Onboarding) onboarding.Onboarding {
+	c := *o
+	c.Completed = append([]onboarding.Step(nil), o.Completed...)
+	return c
+}
+
+// provisioner fakes the billing provider, the DID registry and the identity
+// provider, recording what exists and every call made.
+type provisioner struct {
+	mu        sync.Mutex
+	customers map[string]string // idempotency key -> customer ID
+	numbers   map[string]uuid.UUID
+	users     map[string]string // idempotency key -> user ID
+	calls     []string
+	failOn    map[string]error
+}
+
+func newProvisioner() *provisioner {
+	return &provisioner{
+		customers: make(map[string]string),
+		numbers:   make(map[string]uuid.UUID),
+		users:     make(map[string]string),
+		failOn:    make(map[string]error),
+	}
+}
+
+func (p *provisioner) call(name string) error {
+	p.calls = append(p.calls, name)
+	return p.failOn[name]
+}
+
+func (p *provisioner) CreateCustomer(_ context.Context, t *tenant.Tenant, key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("CreateCustomer"); err != nil {
+		return "", err
+	}
+	if _, ok := p.customers[key]; !ok {
+		p.customers[key] = "cus_" + t.Slug
+	}
+	return p.customers[key], nil
+}
+
+func (p *provisioner) DeleteCustomer(_ context.Context, customerID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DeleteCustomer"); err != nil {
+		return err
+	}
+	for key, id := range p.customers {
+		if id == customerID {
+			delete(p.customers, key)
+		}
+	}
+	return nil
+}
+
+func (p *provisioner) Register(_ context.Context, tenantID uuid.UUID, did, _ string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("Register"); err != nil {
+		return err
+	}
+	p.numbers[did] = tenantID
+	return nil
+}
+
+func (p *provisioner) Release(_ context.Context, tenantID uuid.UUID, did string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("Release"); err != nil {
+		return err
+	}
+	if p.numbers[did] == tenantID {
+		delete(p.numbers, did)
+	}
+	return nil
+}
+
+func (p *provisioner) CreateAdmin(_ context.Context, _ uuid.UUID, email, _, key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("CreateAdmin"); err != nil {
+		return "", err
+	}
+	if _, ok := p.users[key]; !ok {
+		p.users[key] = "user-" + email
+	}
+	return p.users[key], nil
+}
+
+func (p *provisioner) DeleteUser(_ context.Context, _ uuid.UUID, userID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call("DeleteUser"); err != nil {
+		return err
+	}
+	for key, id := range p.users {
+		if id == userID {
+			delete(p.users, key)
+		}
+	}
+	return nil
+}
+
+// onboardedEvents records published onboarded events.
+type onboardedEvents struct {
+	mu     sync.Mutex
+	events []onboarding.Onboarding
+}
+
+func (e *onboardedEvents) PublishOnboarded(_ context.Context, o *onboarding.Onboarding) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, clone(o))
+	return nil
+}
+
+// fixture is an onboarding service wired to in-memory ports.
+type fixture struct {
+	svc     *Service
+	repo    *onboardingRepo
+	tenants *tenanttest.Repository
+	ext     *provisioner
+	events  *onboardedEvents
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		repo:    &onboardingRepo{byKey: make(map[string]onboarding.Onboarding)},
+		tenants: tenanttest.NewRepository(),
+		ext:     newProvisioner(),
+		events:  &onboardedEvents{},
+	}
+	tenants := tenantapp.NewService(f.tenants, nil, tenanttest.NewCache(), tenanttest.NewEventPublisher(), nil, zap.NewNop())
+	f.svc = NewService(f.repo, tenants, f.tenants, f.ext, f.ext, f.ext, f.events, zap.NewNop())
+	return f
+}
+
+func onboardCommand(key string) OnboardCommand {
+	calls := 5000
+	return OnboardCommand{
+		IdempotencyKey: key,
+		Tenant: tenantapp.CreateTenantCommand{
+			Name:  "Acme Corp",
+			Email: "ops@acme.com",
+			Plan:  "professional",
+		},
+		Quota:       &QuotaOverrides{MaxCallsPerMonth: &calls},
+		PhoneNumber: "+5511999990000",
+		Admin:       AdminCommand{Name: "Ada"},
+	}
+}
+
+func TestOnboard(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	dto, err := f.svc.Onboard(ctx, onboardCommand("key-1"))
+	if err != nil {
+		t.Fatalf("Onboard: %v", err)
+	}
+	if dto.Status != string(onboarding.StatusCompleted) {
+		t.Errorf("status = %s, want completed", dto.Status)
+	}
+	want := onboarding.Resources{
+		TenantID:          dto.Resources.TenantID,
+		BillingCustomerID: "cus_acme-corp",
+		PhoneNumber:       "+5511999990000",
+		AdminUserID:       "user-ops@acme.com",
+	}
+	if dto.Resources != want || want.TenantID == uuid.Nil {
+		t.Errorf("resources = %+v, want %+v", dto.Resources, want)
+	}
+
+	stored, ok := f.tenants.Stored(dto.Resources.TenantID)
+	if !ok || stored.StripeID != "cus_acme-corp" {
+		t.Errorf("tenant = %+v, want linked to the billing customer", stored)
+	}
+	quota, err := f.tenants.GetQuota(ctx, dto.Resources.TenantID)
+	if err != nil || quota.MaxCallsPerMonth != 5000 {
+		t.Errorf("quota = %+v (%v), want 5000 calls per month", quota, err)
+	}
+	if quota.MaxUsers != tenant.NewQuota(dto.Resources.TenantID, tenant.PlanProfessional).MaxUsers {
+		t.Errorf("max users = %d, want the plan default", quota.MaxUsers)
+	}
+	if owner := f.ext.numbers["+5511999990000"]; owner != dto.Resources.TenantID {
+		t.Errorf("DID owner = %s, want %s", owner, dto.Resources.TenantID)
+	}
+
+	if len(f.events.events) != 1 || f.events.events[0].Resources != want {
+		t.Errorf("onboarded events = %+v, want one with the created resources", f.events.events)
+	}
+}
+
+func TestOnboard_SameKeyReturnsFirstResult(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	first, err := f.svc.Onboard(ctx, onboardCommand("key-1"))
+	if err != nil {
+		t.Fatalf("Onboard: %v", err)
+	}
+	again, err := f.svc.Onboard(ctx, onboardCommand("key-1"))
+	if err != nil {
+		t.Fatalf("repeated Onboard: %v", err)
+	}
+
+	if again.ID != first.ID || again.Resources != first.Resources {
+		t.Errorf("repeated onboarding = %+v, want %+v", again, first)
+	}
+	if got := f.tenants.Calls("Create"); got != 1 {
+		t.Errorf("tenants created = %d, want 1", got)
+	}
+	if len(f.ext.calls) != 3 || len(f.events.events) != 1 {
+		t.Errorf("calls = %v, events = %d; want one run", f.ext.calls, len(f.events.events))
+	}
+}
+
+func TestOnboard_FailureCompensatesCompletedSteps(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.ext.failOn["Register"] = errors.New("number not available")
+
+	_, err := f.svc.Onboard(ctx, onboardCommand("key-1"))
+	if apperrors.CodeOf(err) != apperrors.ErrInternal {
+		t.Fatalf("err = %v, want internal error", err)
+	}
+
+	// Undone in reverse order, without creating the admin user
+	wantCalls := []string{"CreateCustomer", "Register", "Release", "DeleteCustomer"}
+	if fmt.Sprint(f.ext.calls) != fmt.Sprint(wantCalls) {
+		t.Errorf("calls = %v, want %v", f.ext.calls, wantCalls)
+	}
+	if len(f.ext.customers) != 0 || len(f.ext.numbers) != 0 || len(f.ext.users) != 0 {
+		t.Errorf("left over: customers %v, numbers %v, users %v", f.ext.customers, f.ext.numbers, f.ext.users)
+	}
+
+	o, _ := f.repo.GetByKey(ctx, "key-1")
+	if o.Status != onboarding.StatusRolledBack || o.FailedStep != onboarding.StepPhoneNumber || len(o.Completed) != 0 {
+		t.Errorf("onboarding = %+v, want rolled back at phone_number", o)
+	}
+	if _, ok := f.tenants.Stored(o.Resources.TenantID); !ok {
+		t.Fatal("tenant record missing, want it soft-deleted")
+	}
+	if _, err := f.tenants.GetByID(ctx, o.Resources.TenantID); err == nil {
+		t.Error("tenant still readable, want it deleted")
+	}
+	if len(f.events.events) != 0 {
+		t.Errorf("onboarded events = %d, want none", len(f.events.events))
+	}
+
+	// The key keeps its outcome
+	if _, err := f.svc.Onboard(ctx, onboardCommand("key-1")); apperrors.CodeOf(err) != apperrors.ErrConflict {
+		t.Errorf("repeated Onboard err = %v, want conflict", err)
+	}
+}
+
+func TestOnboard_FailedCompensationIsRecorded(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	f.ext.failOn["CreateAdmin"] = errors.New("identity provider down")
+	f.ext.failOn["DeleteCustomer"] = errors.New("billing provider down")
+
+	if _, err := f.svc.Onboard(ctx, onboardCommand("key-1")); err == nil {
+		t.Fatal("Onboard succeeded, want error")
+	}
+
+	o, _ := f.repo.GetByKey(ctx, "key-1")
+	if o.Status != onboarding.StatusFailed {
+		t.Errorf("status = %s, want failed", o.Status)
+	}
+	// Only the billing customer is left for manual cleanup
+	if len(o.Completed) != 1 || o.Completed[0] != onboarding.StepBillingCustomer {
+		t.Errorf("completed steps = %v, want [billing_customer]", o.Completed)
+	}
+	if len(f.ext.numbers) != 0 {
+		t.Errorf("numbers = %v, want the DID released", f.ext.numbers)
+	}
+}
+
+func TestOnboard_TenantConflictIsReturned(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	if _, err := f.svc.Onboard(ctx, onboardCommand("key-1")); err != nil {
+		t.Fatalf("Onboard: %v", err)
+	}
+
+	_, err := f.svc.Onboard(ctx, onboardCommand("key-2"))
+	if apperrors.CodeOf(err) != apperrors.ErrConflict {
+		t.Fatalf("err = %v, want email conflict", err)
+	}
+	if got := len(f.ext.customers); got != 1 {
+		t.Errorf("billing customers = %d, want 1", got)
+	}
+}
+
+func TestOnboard_NormalizesPhoneNumber(t *testing.T) {
+	tests := []struct {
+		number, region string
+	}{
+		{"(11) 99999-0000", "BR"},
+		{"011 99999 0000", "BR"},
+		{"+55 11 99999-0000", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			f := newFixture()
+			cmd := onboardCommand("key-1")
+			cmd.PhoneNumber, cmd.PhoneRegion = tt.number, tt.region
+
+			dto, err := f.svc.Onboard(context.Background(), cmd)
+			if err != nil {
+				t.Fatalf("Onboard: %v", err)
+			}
+			if dto.Resources.PhoneNumber != "+5511999990000" {
+				t.Errorf("phone number = %q, want +5511999990000", dto.Resources.PhoneNumber)
+			}
+			if owner := f.ext.numbers["+5511999990000"]; owner != dto.Resources.TenantID {
+				t.Errorf("numbers = %v, want the DID registered in E.164", f.ext.numbers)
+			}
+		})
+	}
+}
+
+func TestOnboard_InvalidPhoneNumberIsRejected(t *testing.T) {
+	tests := []struct {
+		number, region string
+	}{
+		{"(11) 99999-0000", ""},
+		{"+55 11 9999", ""},
+		{"0800-FLOWERS", "US"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			f := newFixture()
+			cmd := onboardCommand("key-1")
+			cmd.PhoneNumber, cmd.PhoneRegion = tt.number, tt.region
+
+			_, err := f.svc.Onboard(context.Background(), cmd)
+			if apperrors.CodeOf(err) != apperrors.ErrValidation {
+				t.Fatalf("err = %v, want a validation error", err)
+			}
+			if len(f.ext.customers) != 0 || len(f.ext.numbers) != 0 {
+				t.Errorf("nothing should be created, got customers %v numbers %v", f.ext.customers, f.ext.numbers)
+			}
+		})
+	}
+}
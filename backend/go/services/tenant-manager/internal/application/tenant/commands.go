@@ -3,10 +3,16 @@ package tenant
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+
+	"tenant-manager/internal/domain/tenant"
 )
 
 // CreateTenantCommand represents the command to create a tenant.
@@ -58,6 +64,15 @@ type UpdateTenantCommand struct {
 	Email        *string   `json:"email,omitempty"`
 	Phone        *string   `json:"phone,omitempty"`
 	BillingEmail *string   `json:"billing_email,omitempty"`
+	Plan         *string   `json:"plan,omitempty"`
+
+	DataResidency *string        `json:"data_residency,omitempty"`
+	Redaction     *redact.Policy `json:"redaction,omitempty"`
+
+	// Replaces every custom claim; an empty map removes them
+	TokenClaims *tenant.TokenClaims `json:"token_claims,omitempty"`
+
+	BusinessHours *tenant.BusinessHours `json:"business_hours,omitempty"`
 }
 
 // Validate validates the update tenant command.
@@ -88,6 +103,34 @@ func (cmd UpdateTenantCommand) Validate() error {
 		}
 	}
 
+	if cmd.Plan != nil && !isValidPlan(*cmd.Plan) {
+		return errors.New("invalid plan, must be one of: starter, professional, enterprise")
+	}
+
+	if cmd.DataResidency != nil {
+		if _, err := residency.Parse(*cmd.DataResidency); err != nil {
+			return err
+		}
+	}
+
+	if cmd.Redaction != nil {
+		if err := cmd.Redaction.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if cmd.TokenClaims != nil {
+		if err := cmd.TokenClaims.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if cmd.BusinessHours != nil {
+		if err := cmd.BusinessHours.Validate(); err != nil {
+			return fmt.Errorf("invalid business hours: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -100,10 +143,6 @@ func isValidEmail(email string) bool {
 }
 
 func isValidPlan(plan string) bool {
-	validPlans := map[string]bool{
-		"starter":      true,
-		"professional": true,
-		"enterprise":   true,
-	}
-	return validPlans[strings.ToLower(plan)]
+	_, err := entitlements.ParsePlan(plan)
+	return err == nil
 }
@@ -9,6 +9,14 @@ import (
 	"tenant-manager/internal/domain/tenant"
 )
 
+// EffectiveSettingsDTO is the data transfer object for the resolved settings
+// of a tenant.
+type EffectiveSettingsDTO struct {
+	TenantID uuid.UUID       `json:"tenant_id"`
+	Plan     string          `json:"plan"`
+	Settings tenant.Settings `json:"settings"`
+}
+
 // TenantDTO is the data transfer object for tenant.
 type TenantDTO struct {
 	ID           uuid.UUID       `json:"id"`
@@ -0,0 +1,128 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/tenant"
+)
+
+// PlanBilling moves the tenant's subscription in the billing provider to
+// its new plan.
+type PlanBilling interface {
+	ChangePlan(ctx context.Context, t *tenant.Tenant, previous tenant.Plan) error
+}
+
+// SetPlanChanges attaches what plan changes need beyond the repository:
+// the live usage a downgrade must fit and the billing provider to notify.
+// Without usage, only the usage tracked by the quota is checked.
+func (s *Service) SetPlanChanges(usage tenant.UsageReader, billing PlanBilling) {
+	s.usage = usage
+	s.billing = billing
+}
+
+// ChangePlan moves a tenant to newPlan. A downgrade is refused while the
+// tenant's current usage exceeds a limit of the new plan, with an error
+// listing every limit in the way. The quota moves to the new plan's
+// defaults, keeping this period's usage.
+func (s *Service) ChangePlan(ctx context.Context, id uuid.UUID, newPlan string) (*TenantDTO, error) {
+	if !isValidPlan(newPlan) {
+		return nil, apperrors.NewValidationError("invalid plan, must be one of: starter, professional, enterprise")
+	}
+
+	tenantEntity, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.NewNotFoundError(fmt.Sprintf("tenant with id %s not found", id))
+	}
+	if tenantEntity.Status == tenant.StatusDeleted {
+		return nil, apperrors.NewValidationError("cannot change the plan of a deleted tenant")
+	}
+
+	plan := tenant.Plan(newPlan)
+	if plan == tenantEntity.Plan {
+		return toDTO(tenantEntity), nil
+	}
+
+	before := toDTO(tenantEntity)
+	previous := tenantEntity.Plan
+	if err := s.checkPlanChange(ctx, tenantEntity, plan); err != nil {
+		return nil, err
+	}
+
+	tenantEntity.ChangePlan(plan)
+	tenantEntity.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Update(ctx, tenantEntity); err != nil {
+		s.logger.Error("failed to change tenant plan", zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to change tenant plan")
+	}
+	s.recordAudit(ctx, audit.ActionTenantPlanChange, before, toDTO(tenantEntity))
+
+	s.applyPlanChange(ctx, tenantEntity, previous)
+
+	if err := s.cache.Invalidate(ctx, tenantEntity.ID); err != nil {
+		s.logger.Warn("failed to invalidate cache", zap.Error(err))
+	}
+
+	return toDTO(tenantEntity), nil
+}
+
+// checkPlanChange refuses moving the tenant to plan while its current usage
+// doesn't fit the plan's limits.
+func (s *Service) checkPlanChange(ctx context.Context, t *tenant.Tenant, plan tenant.Plan) error {
+	var usage tenant.PlanUsage
+
+	quota, err := s.repo.GetQuota(ctx, t.ID)
+	if err == nil && quota != nil {
+		usage.StorageGB = quota.UsedStorageGB
+	}
+
+	if s.usage != nil {
+		if usage.ActiveUsers, err = s.usage.ActiveUsers(ctx, t.ID); err != nil {
+			s.logger.Error("failed to read active users", zap.Error(err))
+			return apperrors.NewInternalError("failed to read tenant usage")
+		}
+		if usage.ConcurrentCalls, err = s.usage.ConcurrentCalls(ctx, t.ID); err != nil {
+			s.logger.Error("failed to read concurrent calls", zap.Error(err))
+			return apperrors.NewInternalError("failed to read tenant usage")
+		}
+	}
+
+	err = t.CheckPlanChange(plan, usage)
+	var blocked *tenant.PlanChangeError
+	if errors.As(err, &blocked) {
+		return apperrors.NewAppError(apperrors.ErrConflict, "plan change blocked by current usage", blocked).
+			WithReason("plan_change_blocked")
+	}
+	return err
+}
+
+// applyPlanChange moves the quota limits to the tenant's new plan, keeping
+// this period's usage, notifies billing and publishes the plan change. The
+// tenant is already saved, so failures here are logged, not returned.
+func (s *Service) applyPlanChange(ctx context.Context, t *tenant.Tenant, previous tenant.Plan) {
+	quota := tenant.NewQuota(t.ID, t.Plan)
+	if current, err := s.repo.GetQuota(ctx, t.ID); err == nil && current != nil {
+		quota.WithUsageOf(current)
+	}
+	if err := s.repo.UpdateQuota(ctx, quota); err != nil {
+		s.logger.Error("failed to update tenant quota", zap.Error(err))
+	}
+
+	if s.billing != nil {
+		if err := s.billing.ChangePlan(ctx, t, previous); err != nil {
+			s.logger.Error("failed to notify billing of plan change",
+				zap.String("tenant_id", t.ID.String()), zap.Error(err))
+		}
+	}
+
+	if err := s.eventPublisher.PublishPlanChanged(ctx, t, previous); err != nil {
+		s.logger.Error("failed to publish tenant plan changed event", zap.Error(err))
+	}
+}
@@ -3,16 +3,19 @@ package tenant
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gosimple/slug"
+	audit "github.com/serphona/serphona/backend/go/libs/platform-audit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	pagination "github.com/serphona/serphona/backend/go/libs/platform-pagination"
 	"go.uber.org/zap"
 
 	"tenant-manager/internal/domain/tenant"
-	apperrors "tenant-manager/pkg/errors"
 )
 
 // APIKeyRepository defines the interface for API key operations.
@@ -27,7 +30,13 @@ type Service struct {
 	apiKeyRepo     APIKeyRepository
 	cache          tenant.Cache
 	eventPublisher tenant.EventPublisher
+	auditLogger    *audit.Logger
 	logger         *zap.Logger
+
+	usage   tenant.UsageReader // (optional); see SetPlanChanges
+	billing PlanBilling        // (optional); see SetPlanChanges
+
+	quotaThresholds []int // percents of a limit warned at; see SetQuotaThresholds
 }
 
 // NewService creates a new tenant service.
@@ -36,6 +45,7 @@ func NewService(
 	apiKeyRepo APIKeyRepository,
 	cache tenant.Cache,
 	eventPublisher tenant.EventPublisher,
+	auditLogger *audit.Logger,
 	logger *zap.Logger,
 ) *Service {
 	return &Service{
@@ -43,10 +53,19 @@ func NewService(
 		apiKeyRepo:     apiKeyRepo,
 		cache:          cache,
 		eventPublisher: eventPublisher,
+		auditLogger:    auditLogger,
 		logger:         logger,
+
+		quotaThresholds: tenant.DefaultQuotaThresholds,
 	}
 }
 
+// SetQuotaThresholds sets the shares of a quota limit, in percent, that
+// tenants are warned at. None disables the warnings.
+func (s *Service) SetQuotaThresholds(percents []int) {
+	s.quotaThresholds = percents
+}
+
 // CreateTenant creates a new tenant.
 func (s *Service) CreateTenant(ctx context.Context, cmd CreateTenantCommand) (*TenantDTO, error) {
 	// Validate command
@@ -65,25 +84,7 @@ func (s *Service) CreateTenant(ctx context.Context, cmd CreateTenantCommand) (*T
 	}
 
 	// Create tenant entity
-	tenantEntity := tenant.NewTenant(cmd.Name, cmd.Email, tenant.Plan(cmd.Plan))
-
-	// Generate slug
-	baseSlug := slug.Make(cmd.Name)
-	tenantSlug := baseSlug
-	counter := 1
-	for {
-		exists, err := s.repo.ExistsBySlug(ctx, tenantSlug)
-		if err != nil {
-			s.logger.Error("failed to check slug existence", zap.Error(err))
-			return nil, apperrors.NewInternalError("failed to generate slug")
-		}
-		if !exists {
-			break
-		}
-		tenantSlug = fmt.Sprintf("%s-%d", baseSlug, counter)
-		counter++
-	}
-	tenantEntity.Slug = tenantSlug
+	tenantEntity := tenant.NewTenant(cmd.Name, cmd.Email, tenant.Plan(normalizeString(cmd.Plan)))
 
 	// Set optional fields
 	if cmd.Phone != "" {
@@ -102,12 +103,36 @@ func (s *Service) CreateTenant(ctx context.Context, cmd CreateTenantCommand) (*T
 		}
 	}
 
-	// Persist tenant
-	if err := s.repo.Create(ctx, tenantEntity); err != nil {
+	// Persist tenant under a free slug, picking another one when a
+	// concurrent create took it first
+	baseSlug := baseSlugOf(cmd.Name)
+	for attempt := 1; ; attempt++ {
+		tenantEntity.Slug, err = s.freeSlug(ctx, baseSlug)
+		if err != nil {
+			s.logger.Error("failed to check slug existence", zap.Error(err))
+			return nil, apperrors.NewInternalError("failed to generate slug")
+		}
+
+		err = s.repo.Create(ctx, tenantEntity)
+		if !errors.Is(err, tenant.ErrSlugTaken) || attempt == maxCreateAttempts {
+			break
+		}
+		s.logger.Info("tenant slug taken concurrently, retrying",
+			zap.String("slug", tenantEntity.Slug),
+			zap.Int("attempt", attempt),
+		)
+	}
+	if err != nil {
 		s.logger.Error("failed to create tenant", zap.Error(err))
 		return nil, apperrors.NewInternalError("failed to create tenant")
 	}
 
+	// Create default quota from plan entitlements
+	if err := s.repo.UpdateQuota(ctx, tenant.NewQuota(tenantEntity.ID, tenantEntity.Plan)); err != nil {
+		s.logger.Error("failed to create tenant quota", zap.Error(err))
+		// Continue anyway, quota can be backfilled
+	}
+
 	// Activate tenant immediately (or keep as pending based on business logic)
 	tenantEntity.Activate()
 	if err := s.repo.Update(ctx, tenantEntity); err != nil {
@@ -132,11 +157,36 @@ func (s *Service) CreateTenant(ctx context.Context, cmd CreateTenantCommand) (*T
 
 // GetTenant retrieves a tenant by ID.
 func (s *Service) GetTenant(ctx context.Context, id uuid.UUID) (*TenantDTO, error) {
+	tenantEntity, err := s.getTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDTO(tenantEntity), nil
+}
+
+// GetEffectiveSettings retrieves the complete settings of a tenant, with
+// what it left unset resolved from its plan and the platform defaults.
+func (s *Service) GetEffectiveSettings(ctx context.Context, id uuid.UUID) (*EffectiveSettingsDTO, error) {
+	tenantEntity, err := s.getTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EffectiveSettingsDTO{
+		TenantID: tenantEntity.ID,
+		Plan:     string(tenantEntity.Plan),
+		Settings: tenantEntity.EffectiveSettings(),
+	}, nil
+}
+
+// getTenant retrieves a tenant by ID, from the cache when possible.
+func (s *Service) getTenant(ctx context.Context, id uuid.UUID) (*tenant.Tenant, error) {
 	// Try cache first
 	cacheKey := fmt.Sprintf("tenant:%s", id)
 	cached, err := s.cache.Get(ctx, cacheKey)
 	if err == nil && cached != nil {
-		return toDTO(cached), nil
+		return cached, nil
 	}
 
 	// Fetch from repository
@@ -151,7 +201,7 @@ func (s *Service) GetTenant(ctx context.Context, id uuid.UUID) (*TenantDTO, erro
 		s.logger.Warn("failed to cache tenant", zap.Error(err))
 	}
 
-	return toDTO(tenantEntity), nil
+	return tenantEntity, nil
 }
 
 // GetTenantBySlug retrieves a tenant by slug.
@@ -207,6 +257,29 @@ func (s *Service) UpdateTenant(ctx context.Context, cmd UpdateTenantCommand) (*T
 	if cmd.BillingEmail != nil {
 		tenantEntity.BillingEmail = *cmd.BillingEmail
 	}
+	if cmd.BusinessHours != nil {
+		tenantEntity.Settings.Telephony.BusinessHours = *cmd.BusinessHours
+	}
+	if cmd.DataResidency != nil {
+		region, _ := residency.Parse(*cmd.DataResidency)
+		if err := tenantEntity.SetDataResidency(region); err != nil {
+			return nil, apperrors.NewConflictError(err.Error())
+		}
+	}
+	if cmd.Redaction != nil {
+		tenantEntity.Settings.Security.Redaction = *cmd.Redaction
+	}
+	if cmd.TokenClaims != nil {
+		tenantEntity.Settings.Security.TokenClaims = *cmd.TokenClaims
+	}
+	previousPlan := tenantEntity.Plan
+	planChanged := cmd.Plan != nil && tenant.Plan(*cmd.Plan) != previousPlan
+	if planChanged {
+		if err := s.checkPlanChange(ctx, tenantEntity, tenant.Plan(*cmd.Plan)); err != nil {
+			return nil, err
+		}
+		tenantEntity.ChangePlan(tenant.Plan(*cmd.Plan))
+	}
 
 	tenantEntity.UpdatedAt = time.Now().UTC()
 
@@ -216,6 +289,10 @@ func (s *Service) UpdateTenant(ctx context.Context, cmd UpdateTenantCommand) (*T
 		return nil, apperrors.NewInternalError("failed to update tenant")
 	}
 
+	if planChanged {
+		s.applyPlanChange(ctx, tenantEntity, previousPlan)
+	}
+
 	// Invalidate cache
 	if err := s.cache.Invalidate(ctx, tenantEntity.ID); err != nil {
 		s.logger.Warn("failed to invalidate cache", zap.Error(err))
@@ -248,6 +325,10 @@ func (s *Service) DeleteTenant(ctx context.Context, id uuid.UUID) error {
 		return apperrors.NewInternalError("failed to delete tenant")
 	}
 
+	before := toDTO(tenantEntity)
+	tenantEntity.SoftDelete()
+	s.recordAudit(ctx, audit.ActionTenantDelete, before, toDTO(tenantEntity))
+
 	// Invalidate cache
 	if err := s.cache.Invalidate(ctx, id); err != nil {
 		s.logger.Warn("failed to invalidate cache", zap.Error(err))
@@ -270,11 +351,8 @@ func (s *Service) ListTenants(ctx context.Context, query ListTenantsQuery) (*Lis
 
 	// Build filter
 	filter := tenant.ListFilter{
-		PageSize:   query.PageSize,
-		PageNumber: query.Page,
-		Search:     query.Search,
-		SortBy:     "created_at",
-		SortOrder:  "desc",
+		Search: query.Search,
+		Page:   query.Page,
 	}
 
 	if query.Status != "" {
@@ -284,24 +362,17 @@ func (s *Service) ListTenants(ctx context.Context, query ListTenantsQuery) (*Lis
 
 	// Fetch from repository
 	result, err := s.repo.List(ctx, filter)
+	if errors.Is(err, pagination.ErrInvalidRequest) {
+		return nil, apperrors.NewValidationError(err.Error())
+	}
 	if err != nil {
 		s.logger.Error("failed to list tenants", zap.Error(err))
 		return nil, apperrors.NewInternalError("failed to list tenants")
 	}
 
 	// Convert to DTOs
-	tenants := make([]*TenantDTO, len(result.Tenants))
-	for i, t := range result.Tenants {
-		tenants[i] = toDTO(t)
-	}
-
-	return &ListTenantsResult{
-		Tenants:    tenants,
-		Total:      result.Total,
-		Page:       result.PageNumber,
-		PageSize:   result.PageSize,
-		TotalPages: result.TotalPages,
-	}, nil
+	page := pagination.Map(*result, toDTO)
+	return &page, nil
 }
 
 // ActivateTenant activates a tenant.
@@ -315,10 +386,12 @@ func (s *Service) ActivateTenant(ctx context.Context, id uuid.UUID) error {
 		return nil // Already active
 	}
 
+	before := toDTO(tenantEntity)
 	tenantEntity.Activate()
 	if err := s.repo.Update(ctx, tenantEntity); err != nil {
 		return apperrors.NewInternalError("failed to activate tenant")
 	}
+	s.recordAudit(ctx, audit.ActionTenantActivate, before, toDTO(tenantEntity))
 
 	// Invalidate cache
 	s.cache.Invalidate(ctx, id)
@@ -340,10 +413,12 @@ func (s *Service) SuspendTenant(ctx context.Context, id uuid.UUID) error {
 		return nil // Already suspended
 	}
 
+	before := toDTO(tenantEntity)
 	tenantEntity.Suspend()
 	if err := s.repo.Update(ctx, tenantEntity); err != nil {
 		return apperrors.NewInternalError("failed to suspend tenant")
 	}
+	s.recordAudit(ctx, audit.ActionTenantSuspend, before, toDTO(tenantEntity))
 
 	// Invalidate cache
 	s.cache.Invalidate(ctx, id)
@@ -354,6 +429,54 @@ func (s *Service) SuspendTenant(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// ReserveUsage reserves calls and minutes of the tenant's monthly quota, e.g.
+// before voice-gateway accepts a call. The reservation is refused, without
+// changing usage, when it would exceed the quota.
+func (s *Service) ReserveUsage(ctx context.Context, id uuid.UUID, calls, minutes int) (*tenant.Reservation, error) {
+	if calls < 0 || minutes < 0 || calls+minutes == 0 {
+		return nil, apperrors.NewValidationError("calls and minutes must not be negative, and one must be positive")
+	}
+
+	reservation, err := s.repo.ReserveUsage(ctx, id, calls, minutes)
+	if err != nil {
+		s.logger.Error("failed to reserve usage", zap.String("tenant_id", id.String()), zap.Error(err))
+		return nil, apperrors.NewInternalError("failed to reserve usage")
+	}
+	if reservation.Allowed {
+		s.warnQuotaThresholds(ctx, id)
+	}
+
+	return reservation, nil
+}
+
+// warnQuotaThresholds publishes quota.threshold_reached for each threshold
+// the tenant's usage has reached, once per threshold and period.
+func (s *Service) warnQuotaThresholds(ctx context.Context, id uuid.UUID) {
+	if len(s.quotaThresholds) == 0 {
+		return
+	}
+
+	quota, err := s.repo.GetQuota(ctx, id)
+	if err != nil {
+		s.logger.Warn("failed to read quota for threshold warnings", zap.Error(err))
+		return
+	}
+
+	for _, threshold := range quota.ReachedThresholds(s.quotaThresholds) {
+		first, err := s.repo.MarkThresholdReached(ctx, threshold)
+		if err != nil {
+			s.logger.Warn("failed to mark quota threshold", zap.Error(err))
+			continue
+		}
+		if !first {
+			continue
+		}
+		if err := s.eventPublisher.PublishQuotaThresholdReached(ctx, threshold); err != nil {
+			s.logger.Error("failed to publish quota threshold event", zap.Error(err))
+		}
+	}
+}
+
 // ValidateAPIKey validates an API key and returns the tenant ID.
 func (s *Service) ValidateAPIKey(ctx context.Context, apiKey string) (*uuid.UUID, error) {
 	if apiKey == "" {
@@ -378,6 +501,18 @@ func (s *Service) ValidateAPIKey(ctx context.Context, apiKey string) (*uuid.UUID
 	return tenantID, nil
 }
 
+// recordAudit records a privileged action on a tenant with the before/after
+// diff. Audit writes are best-effort and never fail the action.
+func (s *Service) recordAudit(ctx context.Context, action string, before, after *TenantDTO) {
+	s.auditLogger.Record(ctx, audit.Entry{
+		Action:     action,
+		TargetType: "tenant",
+		TargetID:   after.ID.String(),
+		TenantID:   after.ID.String(),
+		Changes:    audit.Diff(before, after),
+	})
+}
+
 // toDTO converts a domain tenant to a DTO.
 func toDTO(t *tenant.Tenant) *TenantDTO {
 	return &TenantDTO{
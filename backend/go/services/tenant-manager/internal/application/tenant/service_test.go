@@ -0,0 +1,684 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	pagination "github.com/serphona/serphona/backend/go/libs/platform-pagination"
+	"go.uber.org/zap"
+
+	"tenant-manager/internal/domain/tenant"
+	"tenant-manager/internal/domain/tenant/tenanttest"
+)
+
+// fixture is a service wired to the in-memory tenant ports.
+type fixture struct {
+	svc    *Service
+	repo   *tenanttest.Repository
+	cache  *tenanttest.Cache
+	events *tenanttest.EventPublisher
+}
+
+func newFixture(seed ...*tenant.Tenant) *fixture {
+	f := &fixture{
+		repo:   tenanttest.NewRepository(seed...),
+		cache:  tenanttest.NewCache(),
+		events: tenanttest.NewEventPublisher(),
+	}
+	f.svc = NewService(f.repo, nil, f.cache, f.events, nil, zap.NewNop())
+	return f
+}
+
+// seedTenant returns an active tenant with the given name, slug and email.
+func seedTenant(name, slug, email string) *tenant.Tenant {
+	t := tenant.NewTenant(name, email, tenant.PlanStarter)
+	t.Slug = slug
+	t.Activate()
+	return t
+}
+
+func eventTypes(events []tenanttest.Event) []string {
+	types := make([]string, len(events))
+	for i, e := range events {
+		types[i] = e.Type
+	}
+	return types
+}
+
+func TestCreateTenant(t *testing.T) {
+	f := newFixture()
+
+	dto, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{
+		Name:  "Acme Corp",
+		Email: "ops@acme.com",
+		Plan:  "Professional",
+	})
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if dto.Slug != "acme-corp" || dto.Status != string(tenant.StatusActive) || dto.Plan != "professional" {
+		t.Errorf("tenant = %+v, want active professional tenant acme-corp", dto)
+	}
+
+	stored, ok := f.repo.Stored(dto.ID)
+	if !ok || stored.Email != "ops@acme.com" {
+		t.Fatalf("stored tenant = %+v, want persisted tenant", stored)
+	}
+	quota, err := f.repo.GetQuota(context.Background(), dto.ID)
+	if err != nil || quota.MaxCallsPerMonth != tenant.NewQuota(dto.ID, tenant.PlanProfessional).MaxCallsPerMonth {
+		t.Errorf("quota = %+v (%v), want professional plan quota", quota, err)
+	}
+	if got := eventTypes(f.events.Events()); len(got) != 1 || got[0] != tenanttest.EventCreated {
+		t.Errorf("events = %v, want [created]", got)
+	}
+
+	// Served from the cache afterwards
+	if _, err := f.svc.GetTenant(context.Background(), dto.ID); err != nil {
+		t.Fatalf("GetTenant: %v", err)
+	}
+	if n := f.repo.Calls("GetByID"); n != 0 {
+		t.Errorf("GetByID calls = %d, want 0 for a cached tenant", n)
+	}
+}
+
+func TestCreateTenant_SlugCollision(t *testing.T) {
+	f := newFixture(
+		seedTenant("Acme", "acme", "a@acme.com"),
+		seedTenant("Acme", "acme-1", "b@acme.com"),
+	)
+
+	dto, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{Name: "Acme", Email: "c@acme.com", Plan: "starter"})
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if dto.Slug != "acme-2" {
+		t.Errorf("slug = %q, want acme-2", dto.Slug)
+	}
+	if n := f.repo.Calls("TakenSlugs"); n != 1 {
+		t.Errorf("TakenSlugs calls = %d, want 1", n)
+	}
+}
+
+func TestCreateTenant_SlugOfDeletedTenantIsNotReused(t *testing.T) {
+	deleted := seedTenant("Acme", "acme", "old@acme.com")
+	deleted.SoftDelete()
+	f := newFixture(deleted)
+
+	dto, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{Name: "Acme", Email: "new@acme.com", Plan: "starter"})
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if dto.Slug != "acme-1" {
+		t.Errorf("slug = %q, want acme-1", dto.Slug)
+	}
+}
+
+func TestCreateTenant_HeavySlugCollision(t *testing.T) {
+	var seed []*tenant.Tenant
+	for n := 0; n < slugBatchSize*maxSlugBatches; n++ {
+		s := "acme"
+		if n > 0 {
+			s = fmt.Sprintf("acme-%d", n)
+		}
+		seed = append(seed, seedTenant("Acme", s, fmt.Sprintf("%d@acme.com", n)))
+	}
+	f := newFixture(seed...)
+
+	dto, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{Name: "Acme", Email: "new@acme.com", Plan: "starter"})
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if !regexp.MustCompile(`^acme-[0-9a-f]{6}$`).MatchString(dto.Slug) {
+		t.Errorf("slug = %q, want acme with a random suffix", dto.Slug)
+	}
+	if n := f.repo.Calls("TakenSlugs"); n != maxSlugBatches {
+		t.Errorf("TakenSlugs calls = %d, want %d", n, maxSlugBatches)
+	}
+}
+
+func TestCreateTenant_ConcurrentSlugCollision(t *testing.T) {
+	f := newFixture()
+
+	// Another create takes each chosen slug between the check and the insert
+	races := 0
+	f.repo.OnCreate(func(created *tenant.Tenant) {
+		if races < 2 {
+			races++
+			f.repo.Seed(seedTenant("Acme", created.Slug, fmt.Sprintf("race%d@acme.com", races)))
+		}
+	})
+
+	dto, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{Name: "Acme", Email: "new@acme.com", Plan: "starter"})
+	if err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+	if dto.Slug != "acme-2" {
+		t.Errorf("slug = %q, want acme-2 after losing two races", dto.Slug)
+	}
+	if n := f.repo.Calls("Create"); n != 3 {
+		t.Errorf("Create calls = %d, want 3", n)
+	}
+}
+
+func TestCreateTenant_ConcurrentCreatesGetDistinctSlugs(t *testing.T) {
+	f := newFixture()
+
+	const n = 20
+	slugs := make([]string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dto, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{Name: "Acme", Email: fmt.Sprintf("%d@acme.com", i), Plan: "starter"})
+			if err != nil {
+				t.Errorf("CreateTenant %d: %v", i, err)
+				return
+			}
+			slugs[i] = dto.Slug
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, s := range slugs {
+		if s != "" && seen[s] {
+			t.Errorf("slug %q assigned twice", s)
+		}
+		seen[s] = true
+	}
+}
+
+func TestCreateTenant_GivesUpOnPersistentSlugCollision(t *testing.T) {
+	f := newFixture()
+	f.repo.FailOn("Create", tenant.ErrSlugTaken)
+
+	_, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{Name: "Acme", Email: "new@acme.com", Plan: "starter"})
+	if apperrors.CodeOf(err) != apperrors.ErrInternal {
+		t.Errorf("error = %v, want internal error", err)
+	}
+	if n := f.repo.Calls("Create"); n != maxCreateAttempts {
+		t.Errorf("Create calls = %d, want %d", n, maxCreateAttempts)
+	}
+}
+
+func TestBaseSlugOf(t *testing.T) {
+	if got := baseSlugOf("Café & Cia"); got != "cafe-and-cia" {
+		t.Errorf("baseSlugOf = %q, want cafe-and-cia", got)
+	}
+	if got := baseSlugOf("!!"); got != fallbackSlug {
+		t.Errorf("baseSlugOf = %q, want %s", got, fallbackSlug)
+	}
+	if got := baseSlugOf(strings.Repeat("a", 100)); len(got)+7 > maxSlugLength {
+		t.Errorf("baseSlugOf length = %d, leaves no room for a suffix", len(got))
+	}
+}
+
+func TestCreateTenant_Errors(t *testing.T) {
+	storeDown := errors.New("connection refused")
+
+	tests := []struct {
+		name   string
+		cmd    CreateTenantCommand
+		failOn string
+		want   apperrors.ErrorCode
+	}{
+		{"invalid command", CreateTenantCommand{Name: "A", Email: "x@acme.com", Plan: "starter"}, "", apperrors.ErrValidation},
+		{"email taken", CreateTenantCommand{Name: "Other", Email: "ops@acme.com", Plan: "starter"}, "", apperrors.ErrConflict},
+		{"email check fails", CreateTenantCommand{Name: "Other", Email: "new@acme.com", Plan: "starter"}, "ExistsByEmail", apperrors.ErrInternal},
+		{"slug check fails", CreateTenantCommand{Name: "Other", Email: "new@acme.com", Plan: "starter"}, "TakenSlugs", apperrors.ErrInternal},
+		{"create fails", CreateTenantCommand{Name: "Other", Email: "new@acme.com", Plan: "starter"}, "Create", apperrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(seedTenant("Acme", "acme", "ops@acme.com"))
+			if tt.failOn != "" {
+				f.repo.FailOn(tt.failOn, storeDown)
+			}
+
+			_, err := f.svc.CreateTenant(context.Background(), tt.cmd)
+			if got := apperrors.CodeOf(err); got != tt.want {
+				t.Errorf("error code = %s (%v), want %s", got, err, tt.want)
+			}
+			if events := f.events.Events(); len(events) != 0 {
+				t.Errorf("events = %v, want none", eventTypes(events))
+			}
+		})
+	}
+}
+
+func TestCreateTenant_SideEffectFailuresAreNotFatal(t *testing.T) {
+	f := newFixture()
+	f.cache.FailOn("Set", errors.New("redis down"))
+	f.events.FailOn("PublishCreated", errors.New("kafka down"))
+	f.repo.FailOn("UpdateQuota", errors.New("quota table locked"))
+
+	if _, err := f.svc.CreateTenant(context.Background(), CreateTenantCommand{Name: "Acme", Email: "ops@acme.com", Plan: "starter"}); err != nil {
+		t.Fatalf("CreateTenant: %v", err)
+	}
+}
+
+func TestUpdateTenant(t *testing.T) {
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	f.repo.UpdateQuota(context.Background(), tenant.NewQuota(existing.ID, tenant.PlanStarter).WithUsageOf(&tenant.Quota{UsedCalls: 42}))
+
+	name, email, plan := "Acme Inc", "team@acme.com", "enterprise"
+	dto, err := f.svc.UpdateTenant(context.Background(), UpdateTenantCommand{ID: existing.ID, Name: &name, Email: &email, Plan: &plan})
+	if err != nil {
+		t.Fatalf("UpdateTenant: %v", err)
+	}
+	if dto.Name != name || dto.Email != email || dto.Plan != plan || dto.Slug != "acme" {
+		t.Errorf("tenant = %+v, want renamed enterprise tenant keeping its slug", dto)
+	}
+
+	quota, err := f.repo.GetQuota(context.Background(), existing.ID)
+	if err != nil {
+		t.Fatalf("GetQuota: %v", err)
+	}
+	if quota.UsedCalls != 42 || quota.MaxCallsPerMonth != tenant.NewQuota(existing.ID, tenant.PlanEnterprise).MaxCallsPerMonth {
+		t.Errorf("quota = %+v, want enterprise limits keeping 42 used calls", quota)
+	}
+	if got := f.cache.Invalidated(); len(got) != 1 || got[0] != existing.ID {
+		t.Errorf("invalidated = %v, want [%s]", got, existing.ID)
+	}
+	if got := eventTypes(f.events.Events()); len(got) != 2 || got[0] != tenanttest.EventPlanChanged || got[1] != tenanttest.EventUpdated {
+		t.Errorf("events = %v, want [plan_changed updated]", got)
+	}
+}
+
+func TestUpdateTenant_Errors(t *testing.T) {
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	taken := "taken@acme.com"
+
+	tests := []struct {
+		name   string
+		cmd    UpdateTenantCommand
+		failOn string
+		want   apperrors.ErrorCode
+	}{
+		{"missing id", UpdateTenantCommand{}, "", apperrors.ErrValidation},
+		{"unknown tenant", UpdateTenantCommand{ID: uuid.New()}, "", apperrors.ErrNotFound},
+		{"email taken", UpdateTenantCommand{ID: existing.ID, Email: &taken}, "", apperrors.ErrConflict},
+		{"update fails", UpdateTenantCommand{ID: existing.ID}, "Update", apperrors.ErrInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(existing, seedTenant("Other", "other", taken))
+			if tt.failOn != "" {
+				f.repo.FailOn(tt.failOn, errors.New("connection refused"))
+			}
+
+			_, err := f.svc.UpdateTenant(context.Background(), tt.cmd)
+			if got := apperrors.CodeOf(err); got != tt.want {
+				t.Errorf("error code = %s (%v), want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteTenant(t *testing.T) {
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	ctx := context.Background()
+
+	if _, err := f.svc.GetTenant(ctx, existing.ID); err != nil {
+		t.Fatalf("GetTenant: %v", err)
+	}
+	if err := f.svc.DeleteTenant(ctx, existing.ID); err != nil {
+		t.Fatalf("DeleteTenant: %v", err)
+	}
+
+	stored, _ := f.repo.Stored(existing.ID)
+	if stored.Status != tenant.StatusDeleted || stored.DeletedAt == nil {
+		t.Errorf("stored tenant = %+v, want soft-deleted", stored)
+	}
+	if got := eventTypes(f.events.Events()); len(got) != 1 || got[0] != tenanttest.EventDeleted {
+		t.Errorf("events = %v, want [deleted]", got)
+	}
+
+	// The cached copy is gone, so the tenant is no longer found
+	if _, err := f.svc.GetTenant(ctx, existing.ID); apperrors.CodeOf(err) != apperrors.ErrNotFound {
+		t.Errorf("GetTenant after delete = %v, want not found", err)
+	}
+	if err := f.svc.DeleteTenant(ctx, existing.ID); apperrors.CodeOf(err) != apperrors.ErrNotFound {
+		t.Errorf("second DeleteTenant = %v, want not found", err)
+	}
+}
+
+func TestDeleteTenant_RepositoryFails(t *testing.T) {
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	f.repo.FailOn("Delete", errors.New("connection refused"))
+
+	if err := f.svc.DeleteTenant(context.Background(), existing.ID); apperrors.CodeOf(err) != apperrors.ErrInternal {
+		t.Errorf("DeleteTenant = %v, want internal error", err)
+	}
+	if events := f.events.Events(); len(events) != 0 {
+		t.Errorf("events = %v, want none", eventTypes(events))
+	}
+}
+
+func TestListTenants(t *testing.T) {
+	suspended := seedTenant("Globex", "globex", "ops@globex.com")
+	suspended.Suspend()
+	deleted := seedTenant("Initech", "initech", "ops@initech.com")
+	deleted.SoftDelete()
+	f := newFixture(
+		seedTenant("Acme", "acme", "ops@acme.com"),
+		seedTenant("Acme Labs", "acme-labs", "labs@acme.com"),
+		seedTenant("Umbrella", "umbrella", "ops@umbrella.com"),
+		suspended,
+		deleted,
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query ListTenantsQuery
+		want  []string
+		total int64
+	}{
+		{"all live tenants by name", ListTenantsQuery{Page: pagination.Request{Page: 1, PageSize: 10, SortBy: "name", SortOrder: pagination.OrderAsc}},
+			[]string{"Acme", "Acme Labs", "Globex", "Umbrella"}, 4},
+		{"second page", ListTenantsQuery{Page: pagination.Request{Page: 2, PageSize: 3, SortBy: "name", SortOrder: pagination.OrderAsc}},
+			[]string{"Umbrella"}, 4},
+		{"by status", ListTenantsQuery{Page: pagination.Request{Page: 1, PageSize: 10}, Status: "suspended"},
+			[]string{"Globex"}, 1},
+		{"by search", ListTenantsQuery{Page: pagination.Request{Page: 1, PageSize: 10, SortBy: "name", SortOrder: pagination.OrderAsc}, Search: "ACME"},
+			[]string{"Acme", "Acme Labs"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := f.svc.ListTenants(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("ListTenants: %v", err)
+			}
+			var names []string
+			for _, item := range page.Items {
+				names = append(names, item.Name)
+			}
+			if page.Total != tt.total || len(names) != len(tt.want) {
+				t.Fatalf("names = %v (total %d), want %v (total %d)", names, page.Total, tt.want, tt.total)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Errorf("names = %v, want %v", names, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestListTenants_Errors(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	if _, err := f.svc.ListTenants(ctx, ListTenantsQuery{Page: pagination.Request{Page: 0, PageSize: 10}}); apperrors.CodeOf(err) != apperrors.ErrValidation {
+		t.Errorf("invalid page = %v, want validation error", err)
+	}
+
+	f.repo.FailOn("List", errors.New("connection refused"))
+	if _, err := f.svc.ListTenants(ctx, ListTenantsQuery{Page: pagination.Request{Page: 1, PageSize: 10}}); apperrors.CodeOf(err) != apperrors.ErrInternal {
+		t.Errorf("failing repository = %v, want internal error", err)
+	}
+
+	f.repo.FailOn("List", pagination.ErrInvalidRequest)
+	if _, err := f.svc.ListTenants(ctx, ListTenantsQuery{Page: pagination.Request{Page: 1, PageSize: 10}}); apperrors.CodeOf(err) != apperrors.ErrValidation {
+		t.Errorf("invalid cursor = %v, want validation error", err)
+	}
+}
+
+func TestGetEffectiveSettings(t *testing.T) {
+	stored := seedTenant("Acme", "acme", "ops@acme.com")
+	stored.Settings = tenant.Settings{}
+	stored.Settings.AIAgent.DefaultLanguage = "pt-BR"
+	f := newFixture(stored)
+
+	dto, err := f.svc.GetEffectiveSettings(context.Background(), stored.ID)
+	if err != nil {
+		t.Fatalf("GetEffectiveSettings: %v", err)
+	}
+	if dto.TenantID != stored.ID || dto.Plan != string(tenant.PlanStarter) {
+		t.Errorf("dto = %+v, want starter tenant %s", dto, stored.ID)
+	}
+	if dto.Settings.AIAgent.DefaultLanguage != "pt-BR" || dto.Settings.Telephony.MaxConcurrentCalls != 5 {
+		t.Errorf("settings = %+v, want tenant language with starter call limit", dto.Settings)
+	}
+
+	if _, err := f.svc.GetEffectiveSettings(context.Background(), uuid.New()); apperrors.CodeOf(err) != apperrors.ErrNotFound {
+		t.Errorf("err = %v, want not found", err)
+	}
+}
+
+// fakeUsage is a tenant.UsageReader with fixed usage.
+type fakeUsage struct {
+	users, calls int
+}
+
+func (u fakeUsage) ActiveUsers(context.Context, uuid.UUID) (int, error)     { return u.users, nil }
+func (u fakeUsage) ConcurrentCalls(context.Context, uuid.UUID) (int, error) { return u.calls, nil }
+
+// fakeBilling records the plan changes billing was notified of.
+type fakeBilling struct {
+	mu      sync.Mutex
+	changes []string
+}
+
+func (b *fakeBilling) ChangePlan(_ context.Context, t *tenant.Tenant, previous tenant.Plan) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.changes = append(b.changes, string(previous)+"->"+string(t.Plan))
+	return nil
+}
+
+func TestChangePlan_Upgrade(t *testing.T) {
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	f.repo.UpdateQuota(context.Background(), tenant.NewQuota(existing.ID, tenant.PlanStarter).WithUsageOf(&tenant.Quota{UsedCalls: 900}))
+	billing := &fakeBilling{}
+	f.svc.SetPlanChanges(fakeUsage{users: 5, calls: 5}, billing)
+
+	dto, err := f.svc.ChangePlan(context.Background(), existing.ID, "professional")
+	if err != nil {
+		t.Fatalf("ChangePlan: %v", err)
+	}
+	if dto.Plan != "professional" {
+		t.Errorf("plan = %s, want professional", dto.Plan)
+	}
+
+	quota, _ := f.repo.GetQuota(context.Background(), existing.ID)
+	if want := tenant.NewQuota(existing.ID, tenant.PlanProfessional); quota.MaxUsers != want.MaxUsers || quota.UsedCalls != 900 {
+		t.Errorf("quota = %+v, want professional limits keeping 900 used calls", quota)
+	}
+	if len(billing.changes) != 1 || billing.changes[0] != "starter->professional" {
+		t.Errorf("billing changes = %v, want [starter->professional]", billing.changes)
+	}
+	events := f.events.Events()
+	if len(events) != 1 || events[0].Type != tenanttest.EventPlanChanged || events[0].Previous != tenant.PlanStarter {
+		t.Errorf("events = %+v, want one plan_changed from starter", events)
+	}
+}
+
+func TestChangePlan_DowngradeBlockedByUsage(t *testing.T) {
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	existing.ChangePlan(tenant.PlanEnterprise)
+	f := newFixture(existing)
+	f.repo.UpdateQuota(context.Background(), tenant.NewQuota(existing.ID, tenant.PlanEnterprise).WithUsageOf(&tenant.Quota{UsedStorageGB: 3}))
+	billing := &fakeBilling{}
+	f.svc.SetPlanChanges(fakeUsage{users: 40, calls: 12}, billing)
+
+	_, err := f.svc.ChangePlan(context.Background(), existing.ID, "starter")
+	if apperrors.CodeOf(err) != apperrors.ErrConflict || apperrors.ReasonOf(err) != "plan_change_blocked" {
+		t.Fatalf("err = %v, want plan_change_blocked conflict", err)
+	}
+
+	var blocked *tenant.PlanChangeError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("err = %v, want it to carry a *tenant.PlanChangeError", err)
+	}
+	limits := make([]string, len(blocked.Blockers))
+	for i, b := range blocked.Blockers {
+		limits[i] = string(b.Limit)
+	}
+	if strings.Join(limits, ",") != "max_users,max_concurrent_calls" {
+		t.Errorf("blockers = %v, want users and concurrent calls but not storage", limits)
+	}
+	for _, want := range []string{"max_users is 40", "max_concurrent_calls is 12"} {
+		if !strings.Contains(blocked.Error(), want) {
+			t.Errorf("error %q does not mention %q", blocked.Error(), want)
+		}
+	}
+
+	stored, _ := f.repo.GetByID(context.Background(), existing.ID)
+	if stored.Plan != tenant.PlanEnterprise {
+		t.Errorf("stored plan = %s, want enterprise kept", stored.Plan)
+	}
+	if len(billing.changes) != 0 || len(f.events.Events()) != 0 {
+		t.Errorf("blocked downgrade notified billing %v or published %v", billing.changes, f.events.Events())
+	}
+}
+
+func TestReserveUsage_AtLimit(t *testing.T) {
+	ctx := context.Background()
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	quota := tenant.NewQuota(existing.ID, tenant.PlanStarter)
+	quota.UsedCalls = quota.MaxCallsPerMonth - 1
+	f.repo.UpdateQuota(ctx, quota)
+
+	got, err := f.svc.ReserveUsage(ctx, existing.ID, 1, 0)
+	if err != nil {
+		t.Fatalf("ReserveUsage: %v", err)
+	}
+	if !got.Allowed || got.RemainingCalls != 0 {
+		t.Errorf("reservation = %+v, want allowed with no calls remaining", got)
+	}
+
+	got, err = f.svc.ReserveUsage(ctx, existing.ID, 1, 0)
+	if err != nil {
+		t.Fatalf("ReserveUsage: %v", err)
+	}
+	if got.Allowed || got.RemainingCalls != 0 {
+		t.Errorf("reservation past the limit = %+v, want refused", got)
+	}
+	if stored, _ := f.repo.GetQuota(ctx, existing.ID); stored.UsedCalls != quota.MaxCallsPerMonth {
+		t.Errorf("used calls = %d, want exactly the limit %d", stored.UsedCalls, quota.MaxCallsPerMonth)
+	}
+}
+
+func TestReserveUsage_OverLimitLeavesUsage(t *testing.T) {
+	ctx := context.Background()
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	quota := tenant.NewQuota(existing.ID, tenant.PlanStarter)
+	quota.UsedMinutes = quota.MaxMinutesPerMonth - 5
+	f.repo.UpdateQuota(ctx, quota)
+
+	got, err := f.svc.ReserveUsage(ctx, existing.ID, 1, 10)
+	if err != nil {
+		t.Fatalf("ReserveUsage: %v", err)
+	}
+	if got.Allowed || got.RemainingMinutes != 5 {
+		t.Errorf("reservation = %+v, want refused with 5 minutes remaining", got)
+	}
+	if stored, _ := f.repo.GetQuota(ctx, existing.ID); stored.UsedCalls != 0 || stored.UsedMinutes != quota.UsedMinutes {
+		t.Errorf("quota = %+v, want usage unchanged by the refused reservation", stored)
+	}
+
+	if _, err := f.svc.ReserveUsage(ctx, existing.ID, -1, 0); apperrors.CodeOf(err) != apperrors.ErrValidation {
+		t.Errorf("negative reservation = %v, want validation error", err)
+	}
+}
+
+// The fake repository serializes reservations; the conditional UPDATE doing
+// it in Postgres is covered by the postgres package's integration tests.
+func TestReserveUsage_ConcurrentBurstStopsAtLimit(t *testing.T) {
+	ctx := context.Background()
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	quota := tenant.NewQuota(existing.ID, tenant.PlanStarter)
+	quota.UsedCalls = quota.MaxCallsPerMonth - 10
+	f.repo.UpdateQuota(ctx, quota)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := f.svc.ReserveUsage(ctx, existing.ID, 1, 1)
+			if err != nil {
+				t.Errorf("ReserveUsage: %v", err)
+				return
+			}
+			if got.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 10 {
+		t.Errorf("allowed reservations = %d, want the 10 left in the quota", allowed)
+	}
+	if stored, _ := f.repo.GetQuota(ctx, existing.ID); stored.UsedCalls != quota.MaxCallsPerMonth {
+		t.Errorf("used calls = %d, want %d", stored.UsedCalls, quota.MaxCallsPerMonth)
+	}
+}
+
+func TestReserveUsage_WarnsOncePerThreshold(t *testing.T) {
+	ctx := context.Background()
+	existing := seedTenant("Acme", "acme", "ops@acme.com")
+	f := newFixture(existing)
+	quota := tenant.NewQuota(existing.ID, tenant.PlanStarter)
+	quota.UsedCalls = quota.MaxCallsPerMonth*80/100 - 10
+	f.repo.UpdateQuota(ctx, quota)
+
+	for i := 0; i < 100; i++ {
+		if _, err := f.svc.ReserveUsage(ctx, existing.ID, 1, 0); err != nil {
+			t.Fatalf("ReserveUsage: %v", err)
+		}
+	}
+
+	var warnings []tenant.QuotaThreshold
+	for _, e := range f.events.Events() {
+		if e.Type == tenanttest.EventQuotaThreshold {
+			warnings = append(warnings, *e.Threshold)
+		}
+	}
+	if len(warnings) != 1 || warnings[0].Percent != 80 || warnings[0].Resource != tenant.QuotaResourceCalls {
+		t.Fatalf("warnings = %+v, want one 80%% calls warning", warnings)
+	}
+	if w := warnings[0]; w.Used != quota.MaxCallsPerMonth*80/100 || w.Period != quota.ResetAt {
+		t.Errorf("warning = %+v, want it at the first reservation reaching 80%% of this period", w)
+	}
+
+	// A new period warns again
+	next := tenant.NewQuota(existing.ID, tenant.PlanStarter)
+	next.UsedCalls = quota.MaxCallsPerMonth * 80 / 100
+	next.ResetAt = quota.ResetAt.AddDate(0, 1, 0)
+	f.repo.UpdateQuota(ctx, next)
+	if _, err := f.svc.ReserveUsage(ctx, existing.ID, 1, 0); err != nil {
+		t.Fatalf("ReserveUsage: %v", err)
+	}
+	if got := len(f.events.Events()); got != 2 {
+		t.Errorf("events after the period reset = %d, want a second warning", got)
+	}
+}
@@ -0,0 +1,81 @@
+package tenant
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/gosimple/slug"
+)
+
+const (
+	// maxSlugLength is the size of the slug column.
+	maxSlugLength = 100
+
+	// slugBatchSize is how many numbered candidates are checked per lookup.
+	slugBatchSize = 10
+
+	// maxSlugBatches bounds the numbered candidates; past them the slug gets
+	// a random suffix instead.
+	maxSlugBatches = 3
+
+	// maxCreateAttempts bounds the creates retried after another tenant
+	// took the chosen slug concurrently.
+	maxCreateAttempts = 5
+
+	// randomSuffixBytes is the size of the random suffix, in bytes.
+	randomSuffixBytes = 3
+
+	// fallbackSlug is used for names without any URL-friendly character.
+	fallbackSlug = "tenant"
+)
+
+// baseSlugOf returns the URL-friendly form of a tenant name, short enough to
+// take any suffix within the slug column.
+func baseSlugOf(name string) string {
+	base := slug.Make(name)
+	if base == "" {
+		base = fallbackSlug
+	}
+	// Room for "-" and the longest suffix: a random one or a number
+	room := maxSlugLength - 1 - max(2*randomSuffixBytes, len(fmt.Sprint(slugBatchSize*maxSlugBatches)))
+	if len(base) > room {
+		base = strings.TrimRight(base[:room], "-")
+	}
+	return base
+}
+
+// freeSlug returns the first free slug of base, base-1, base-2... checking
+// the numbered candidates a batch at a time. After maxSlugBatches batches it
+// gives up on numbers and appends a random suffix, so it always terminates;
+// the unique constraint catches the unlikely collision of a random suffix.
+func (s *Service) freeSlug(ctx context.Context, base string) (string, error) {
+	for batch := 0; batch < maxSlugBatches; batch++ {
+		candidates := make([]string, slugBatchSize)
+		for i := range candidates {
+			if n := batch*slugBatchSize + i; n > 0 {
+				candidates[i] = fmt.Sprintf("%s-%d", base, n)
+			} else {
+				candidates[i] = base
+			}
+		}
+
+		taken, err := s.repo.TakenSlugs(ctx, candidates)
+		if err != nil {
+			return "", err
+		}
+		for _, c := range candidates {
+			if !taken[c] {
+				return c, nil
+			}
+		}
+	}
+
+	suffix := make([]byte, randomSuffixBytes)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate slug suffix: %w", err)
+	}
+	return base + "-" + hex.EncodeToString(suffix), nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
 )
 
 // Config represents the application configuration.
@@ -19,6 +20,9 @@ type Config struct {
 	Kafka    KafkaConfig
 	JWT      JWTConfig
 	Metrics  MetricsConfig
+	Export   ExportConfig
+	Secrets  SecretsConfig
+	Quota    QuotaConfig
 }
 
 // ServerConfig represents server configuration.
@@ -30,6 +34,18 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration `envconfig:"SERVER_WRITE_TIMEOUT" default:"10s"`
 	IdleTimeout     time.Duration `envconfig:"SERVER_IDLE_TIMEOUT" default:"120s"`
 	ShutdownTimeout time.Duration `envconfig:"SERVER_SHUTDOWN_TIMEOUT" default:"30s"`
+	// Request bodies above MaxBodyBytes are refused with 413. BodyLimitRoutes
+	// overrides it by path prefix ("/prefix=bytes,..."); DID imports carry
+	// up to a thousand numbers as CSV.
+	MaxBodyBytes    int64  `envconfig:"MAX_REQUEST_BODY_BYTES" default:"262144"`
+	BodyLimitRoutes string `envconfig:"MAX_REQUEST_BODY_ROUTES" default:"/api/v1/telephony/dids/bulk=1048576"`
+}
+
+// BodyLimit returns the request body limits of the server.
+func (c ServerConfig) BodyLimit() bodylimit.Config {
+	// Validate rejects malformed routes
+	routes, _ := bodylimit.ParseRoutes(c.BodyLimitRoutes)
+	return bodylimit.Config{MaxBytes: c.MaxBodyBytes, Routes: routes}
 }
 
 // DatabaseConfig represents database configuration.
@@ -68,11 +84,36 @@ type MetricsConfig struct {
 	Port int `envconfig:"METRICS_PORT" default:"9091"`
 }
 
+// ExportConfig represents data-export (subject-access request) configuration.
+type ExportConfig struct {
+	ArchivePath   string        `envconfig:"EXPORT_ARCHIVE_PATH" default:"/var/lib/tenant-manager/exports"`
+	JobTimeout    time.Duration `envconfig:"EXPORT_JOB_TIMEOUT" default:"30m"`
+	SourceTimeout time.Duration `envconfig:"EXPORT_SOURCE_TIMEOUT" default:"2m"`
+	// Service endpoints gathered into each export, e.g.
+	// "calls:http://voice-gateway:8080/api/v1/tenants/{tenant_id}/calls"
+	Sources map[string]string `envconfig:"EXPORT_SOURCES"`
+}
+
+// SecretsConfig represents the encryption of secrets in tenant settings.
+type SecretsConfig struct {
+	// Base64 AES-256 master key wrapping the per-tenant data keys
+	MasterKey string `envconfig:"SECRETS_MASTER_KEY"`
+}
+
+// QuotaConfig represents quota usage configuration.
+type QuotaConfig struct {
+	// Percents of a quota limit at which tenants are warned, once per period
+	WarningThresholds []int `envconfig:"QUOTA_WARNING_THRESHOLDS" default:"80,95"`
+}
+
 // Load loads the configuration from environment variables.
 func Load() (*Config, error) {
 	var cfg Config
 	if err := envconfig.Process("", &cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+)
+
+// minSecretLength is the minimum JWT secret length accepted in production.
+const minSecretLength = 32
+
+// defaultSecrets are placeholder values shipped in .env.example.
+var defaultSecrets = []string{
+	"your-super-secret-jwt-key-change-in-production-min-32-chars",
+}
+
+// ValidationError lists every configuration problem found at once.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// IsProduction reports whether the service runs in production.
+func (c *Config) IsProduction() bool {
+	env := strings.ToLower(c.Environment)
+	return env == "production" || env == "prod"
+}
+
+// Validate checks the configuration for malformed URLs and nonsensical
+// durations. In production it also rejects default or weak secrets.
+func (c *Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if !validURL(c.Database.URL, "postgres", "postgresql") {
+		addf("DATABASE_URL must be a postgres:// URL")
+	}
+	if !validURL(c.Redis.URL, "redis", "rediss") {
+		addf("REDIS_URL must be a redis:// URL, got %q", c.Redis.URL)
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		{"DATABASE_CONN_MAX_LIFE", c.Database.ConnMaxLife},
+		{"REDIS_CACHE_TTL", c.Redis.CacheTTL},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			addf("%s must be a positive duration, got %v", d.name, d.value)
+		}
+	}
+
+	if c.Server.MaxBodyBytes <= 0 {
+		addf("MAX_REQUEST_BODY_BYTES must be positive, got %d", c.Server.MaxBodyBytes)
+	}
+	if _, err := bodylimit.ParseRoutes(c.Server.BodyLimitRoutes); err != nil {
+		addf("MAX_REQUEST_BODY_ROUTES: %v", err)
+	}
+
+	if c.Database.MaxOpenConns <= 0 {
+		addf("DATABASE_MAX_OPEN_CONNS must be positive, got %d", c.Database.MaxOpenConns)
+	}
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		addf("DATABASE_MAX_IDLE_CONNS (%d) must not exceed DATABASE_MAX_OPEN_CONNS (%d)",
+			c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+
+	for name, tmpl := range c.Export.Sources {
+		if !validURL(tmpl, "http", "https") {
+			addf("EXPORT_SOURCES entry %q must be an http(s) URL, got %q", name, tmpl)
+		}
+	}
+
+	for _, percent := range c.Quota.WarningThresholds {
+		if percent <= 0 || percent >= 100 {
+			addf("QUOTA_WARNING_THRESHOLDS entries must be between 1 and 99, got %d", percent)
+		}
+	}
+
+	if c.Secrets.MasterKey != "" {
+		if key, err := base64.StdEncoding.DecodeString(c.Secrets.MasterKey); err != nil || len(key) != 32 {
+			addf("SECRETS_MASTER_KEY must be a base64-encoded 32-byte key")
+		}
+	}
+
+	if c.IsProduction() {
+		if c.Secrets.MasterKey == "" {
+			addf("SECRETS_MASTER_KEY is required in production")
+		}
+		switch {
+		case c.JWT.Secret == "":
+			addf("JWT_SECRET is required in production")
+		case isDefaultSecret(c.JWT.Secret):
+			addf("JWT_SECRET is set to a default placeholder")
+		case len(c.JWT.Secret) < minSecretLength:
+			addf("JWT_SECRET must be at least %d characters in production", minSecretLength)
+		}
+		if u, err := url.Parse(c.Database.URL); err == nil {
+			if pw, _ := u.User.Password(); pw == "" || pw == "postgres" {
+				addf("DATABASE_URL must use a non-default password in production")
+			}
+			if u.Query().Get("sslmode") == "disable" {
+				addf("DATABASE_URL must not use sslmode=disable in production")
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// isDefaultSecret reports whether s is one of the shipped placeholders.
+func isDefaultSecret(s string) bool {
+	for _, d := range defaultSecrets {
+		if s == d {
+			return true
+		}
+	}
+	return false
+}
+
+// validURL reports whether s parses as an absolute URL with one of schemes.
+func validURL(s string, schemes ...string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return true
+		}
+	}
+	return false
+}
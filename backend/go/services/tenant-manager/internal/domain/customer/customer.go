@@ -0,0 +1,121 @@
+// Package customer contains the end-customer identity domain model.
+package customer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when a customer or identifier does not exist.
+	ErrNotFound = errors.New("customer not found")
+
+	// ErrIdentifierTaken is returned when an identifier already belongs to
+	// another customer of the tenant.
+	ErrIdentifierTaken = errors.New("identifier already belongs to a customer")
+
+	// ErrInvalidIdentifier is returned for identifiers that are neither a
+	// phone number nor an email address.
+	ErrInvalidIdentifier = errors.New("identifier must be a phone number or email address")
+)
+
+// IdentifierKind is the kind of contact an identifier is.
+type IdentifierKind string
+
+const (
+	KindPhone IdentifierKind = "phone"
+	KindEmail IdentifierKind = "email"
+)
+
+// Identifier is a normalized phone number or email address that identifies a
+// customer within a tenant.
+type Identifier struct {
+	Kind  IdentifierKind `json:"kind"`
+	Value string         `json:"value"`
+}
+
+// ParseIdentifier normalizes raw into an Identifier. Emails are lowercased;
+// phone numbers keep only digits and a leading "+", so "+55 (11) 99999-0000"
+// and "+5511999990000" resolve to the same customer.
+func ParseIdentifier(raw string) (Identifier, error) {
+	raw = strings.TrimSpace(raw)
+
+	if strings.Contains(raw, "@") {
+		local, domain, ok := strings.Cut(raw, "@")
+		if !ok || local == "" || !strings.Contains(domain, ".") || strings.ContainsAny(raw, " \t") {
+			return Identifier{}, ErrInvalidIdentifier
+		}
+		return Identifier{Kind: KindEmail, Value: strings.ToLower(raw)}, nil
+	}
+
+	var b strings.Builder
+	for i, r := range raw {
+		switch {
+		case unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+		default:
+			return Identifier{}, ErrInvalidIdentifier
+		}
+	}
+	value := b.String()
+	if digits := strings.TrimPrefix(value, "+"); len(digits) < 3 {
+		return Identifier{}, ErrInvalidIdentifier
+	}
+	return Identifier{Kind: KindPhone, Value: value}, nil
+}
+
+// Customer is an end customer of a tenant (a caller), stable across calls
+// and channels.
+type Customer struct {
+	ID          uuid.UUID    `json:"id"`
+	TenantID    uuid.UUID    `json:"tenant_id"`
+	Identifiers []Identifier `json:"identifiers"`
+	MergedInto  *uuid.UUID   `json:"merged_into,omitempty"` // set on duplicates merged into another customer
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// NewCustomer creates a customer known by id.
+func NewCustomer(tenantID uuid.UUID, id Identifier) *Customer {
+	now := time.Now().UTC()
+	return &Customer{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		Identifiers: []Identifier{id},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsMerged reports whether the customer was merged into another one.
+func (c *Customer) IsMerged() bool {
+	return c.MergedInto != nil
+}
+
+// Repository defines the interface for customer persistence.
+// This is a port in hexagonal architecture - implementations are adapters.
+type Repository interface {
+	// Create persists a new customer with its identifiers, returning
+	// ErrIdentifierTaken if another customer of the tenant already has one.
+	Create(ctx context.Context, c *Customer) error
+
+	// GetByID retrieves a customer of the tenant, returning ErrNotFound if
+	// missing.
+	GetByID(ctx context.Context, tenantID, id uuid.UUID) (*Customer, error)
+
+	// FindByIdentifier retrieves the customer of the tenant owning id,
+	// returning ErrNotFound if none does.
+	FindByIdentifier(ctx context.Context, tenantID uuid.UUID, id Identifier) (*Customer, error)
+
+	// Merge moves every identifier of source to target and marks source as
+	// merged into target, atomically.
+	Merge(ctx context.Context, tenantID, targetID, sourceID uuid.UUID) error
+}
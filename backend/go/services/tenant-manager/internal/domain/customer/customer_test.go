@@ -0,0 +1,36 @@
+package customer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseIdentifier(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Identifier
+	}{
+		{"+55 (11) 99999-0000", Identifier{Kind: KindPhone, Value: "+5511999990000"}},
+		{"+5511999990000", Identifier{Kind: KindPhone, Value: "+5511999990000"}},
+		{"1000", Identifier{Kind: KindPhone, Value: "1000"}},
+		{" Ana.Silva@Example.COM ", Identifier{Kind: KindEmail, Value: "ana.silva@example.com"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseIdentifier(tt.raw)
+		if err != nil {
+			t.Errorf("ParseIdentifier(%q) error: %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseIdentifier(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParseIdentifier_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "anonymous", "12", "55+11", "@example.com", "ana@localhost"} {
+		if _, err := ParseIdentifier(raw); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("ParseIdentifier(%q) error = %v, want ErrInvalidIdentifier", raw, err)
+		}
+	}
+}
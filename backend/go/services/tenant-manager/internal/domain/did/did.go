@@ -0,0 +1,58 @@
+// Package did contains the DID (inbound phone number) domain model.
+package did
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned when a DID does not exist.
+var ErrNotFound = errors.New("DID not found")
+
+// DID is an inbound phone number held by a tenant. Calls to it go to the
+// assigned agent, when set, and otherwise through the tenant's routing
+// rules.
+type DID struct {
+	Number    string // E.164
+	TenantID  uuid.UUID
+	Enabled   bool
+	AgentID   string // agent answering calls to the number
+	FlowID    string // flow the agent runs on these calls
+	Label     string // e.g. "Sales line"
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Assignment is the owner of a DID after an import.
+type Assignment struct {
+	Number   string    // E.164
+	TenantID uuid.UUID // tenant holding the number
+	Created  bool      // assigned by this import
+}
+
+// Repository defines the interface for DID persistence.
+// This is a port in hexagonal architecture - implementations are adapters.
+type Repository interface {
+	// AssignBatch assigns to the tenant every number no tenant holds yet, in
+	// a single transaction, and returns the owner of each number. Numbers
+	// already held, by this or another tenant, are left as they are.
+	AssignBatch(ctx context.Context, tenantID uuid.UUID, numbers []string) ([]Assignment, error)
+
+	// GetByNumber retrieves a DID whatever tenant holds it, returning
+	// ErrNotFound if no tenant does.
+	GetByNumber(ctx context.Context, number string) (*DID, error)
+
+	// ListByTenant retrieves the tenant's DIDs ordered by number.
+	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*DID, error)
+
+	// Update saves the assignment, label and enabled flag of a DID of the
+	// tenant, returning ErrNotFound if the tenant does not hold it.
+	Update(ctx context.Context, d *DID) error
+
+	// Delete releases a DID of the tenant, returning ErrNotFound if the
+	// tenant does not hold it.
+	Delete(ctx context.Context, tenantID uuid.UUID, number string) error
+}
@@ -0,0 +1,135 @@
+// Package export contains the data-export (subject-access request) domain model.
+package export
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrJobNotFound is returned when an export job does not exist.
+var ErrJobNotFound = errors.New("export job not found")
+
+// Status represents the state of an export job.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// SourceProgress tracks the collection of one data source within a job.
+type SourceProgress struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Job is an asynchronous export of a tenant's (or one of its customers')
+// data gathered across services into a single archive.
+type Job struct {
+	ID          uuid.UUID        `json:"id"`
+	TenantID    uuid.UUID        `json:"tenant_id"`
+	CustomerID  string           `json:"customer_id,omitempty"` // empty exports the whole tenant
+	RequestedBy string           `json:"requested_by,omitempty"`
+	Status      Status           `json:"status"`
+	Sources     []SourceProgress `json:"sources"`
+	ArchiveKey  string           `json:"archive_key,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// NewJob creates a pending job collecting the given sources.
+func NewJob(tenantID uuid.UUID, customerID, requestedBy string, sources []string) *Job {
+	now := time.Now().UTC()
+	progress := make([]SourceProgress, len(sources))
+	for i, name := range sources {
+		progress[i] = SourceProgress{Name: name, Status: StatusPending}
+	}
+	return &Job{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		CustomerID:  customerID,
+		RequestedBy: requestedBy,
+		Status:      StatusPending,
+		Sources:     progress,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Start marks the job as running.
+func (j *Job) Start() {
+	j.Status = StatusRunning
+	j.UpdatedAt = time.Now().UTC()
+}
+
+// RecordSource records the outcome of collecting the named source.
+func (j *Job) RecordSource(name string, err error) {
+	for i := range j.Sources {
+		if j.Sources[i].Name != name {
+			continue
+		}
+		j.Sources[i].Status = StatusCompleted
+		if err != nil {
+			j.Sources[i].Status = StatusFailed
+			j.Sources[i].Error = err.Error()
+		}
+	}
+	j.UpdatedAt = time.Now().UTC()
+}
+
+// Complete marks the job as completed with its archive stored under key.
+// Sources that failed are listed in the job but don't fail it.
+func (j *Job) Complete(key string) {
+	now := time.Now().UTC()
+	j.Status = StatusCompleted
+	j.ArchiveKey = key
+	j.UpdatedAt = now
+	j.CompletedAt = &now
+}
+
+// Fail marks the job as failed.
+func (j *Job) Fail(err error) {
+	now := time.Now().UTC()
+	j.Status = StatusFailed
+	j.Error = err.Error()
+	j.UpdatedAt = now
+	j.CompletedAt = &now
+}
+
+// Progress returns the percentage of sources already collected.
+func (j *Job) Progress() int {
+	if j.Status == StatusCompleted {
+		return 100
+	}
+	if len(j.Sources) == 0 {
+		return 0
+	}
+	done := 0
+	for _, s := range j.Sources {
+		if s.Status == StatusCompleted || s.Status == StatusFailed {
+			done++
+		}
+	}
+	return done * 100 / len(j.Sources)
+}
+
+// Repository defines the interface for export job persistence.
+// This is a port in hexagonal architecture - implementations are adapters.
+type Repository interface {
+	// Create persists a new job.
+	Create(ctx context.Context, job *Job) error
+
+	// GetByID retrieves a job by its ID, returning ErrJobNotFound if missing.
+	GetByID(ctx context.Context, id uuid.UUID) (*Job, error)
+
+	// Update saves the job's status and progress.
+	Update(ctx context.Context, job *Job) error
+}
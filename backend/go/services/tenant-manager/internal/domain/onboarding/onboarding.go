@@ -0,0 +1,165 @@
+// Package onboarding contains the tenant onboarding saga domain model.
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound is returned when an onboarding does not exist.
+	ErrNotFound = errors.New("onboarding not found")
+
+	// ErrDuplicateKey is returned by Repository.Create when an onboarding
+	// with the same idempotency key already exists.
+	ErrDuplicateKey = errors.New("onboarding idempotency key already used")
+)
+
+// Status represents the state of an onboarding.
+type Status string
+
+const (
+	StatusRunning    Status = "running"
+	StatusCompleted  Status = "completed"
+	StatusRolledBack Status = "rolled_back" // a step failed and every completed step was undone
+	StatusFailed     Status = "failed"      // a step failed and undoing the completed ones failed too
+)
+
+// Step is one step of the onboarding saga.
+type Step string
+
+const (
+	StepTenant          Step = "tenant"
+	StepQuota           Step = "quota"
+	StepBillingCustomer Step = "billing_customer"
+	StepPhoneNumber     Step = "phone_number"
+	StepAdminUser       Step = "admin_user"
+)
+
+// Resources are the IDs of what an onboarding created.
+type Resources struct {
+	TenantID          uuid.UUID `json:"tenant_id"`
+	BillingCustomerID string    `json:"billing_customer_id,omitempty"`
+	PhoneNumber       string    `json:"phone_number,omitempty"`
+	AdminUserID       string    `json:"admin_user_id,omitempty"`
+}
+
+// Onboarding is one run of the saga that sets up a usable tenant: the tenant
+// and its quota, the billing customer, the phone number and the first admin
+// user. Its progress is saved after every step, so a failed run knows what
+// to undo and a repeated request with the same idempotency key gets the
+// same outcome.
+type Onboarding struct {
+	ID             uuid.UUID  `json:"id"`
+	IdempotencyKey string     `json:"idempotency_key"`
+	Status         Status     `json:"status"`
+	Completed      []Step     `json:"completed_steps"`
+	Resources      Resources  `json:"resources"`
+	FailedStep     Step       `json:"failed_step,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// New creates a running onboarding for the idempotency key.
+func New(idempotencyKey string) *Onboarding {
+	now := time.Now().UTC()
+	return &Onboarding{
+		ID:             uuid.New(),
+		IdempotencyKey: idempotencyKey,
+		Status:         StatusRunning,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Done reports whether step already completed.
+func (o *Onboarding) Done(step Step) bool {
+	for _, s := range o.Completed {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// StepKey is the idempotency key sent to the service performing step, so a
+// retried call never creates a resource twice.
+func (o *Onboarding) StepKey(step Step) string {
+	return o.ID.String() + ":" + string(step)
+}
+
+// CompleteStep records that step completed.
+func (o *Onboarding) CompleteStep(step Step) {
+	if !o.Done(step) {
+		o.Completed = append(o.Completed, step)
+	}
+	o.UpdatedAt = time.Now().UTC()
+}
+
+// UndoStep records that the effects of step were undone.
+func (o *Onboarding) UndoStep(step Step) {
+	for i, s := range o.Completed {
+		if s == step {
+			o.Completed = append(o.Completed[:i], o.Completed[i+1:]...)
+			break
+		}
+	}
+	o.UpdatedAt = time.Now().UTC()
+}
+
+// Fail records the step that failed, before its predecessors are undone.
+func (o *Onboarding) Fail(step Step, err error) {
+	o.FailedStep = step
+	o.Error = err.Error()
+	o.UpdatedAt = time.Now().UTC()
+}
+
+// Finish marks the onboarding as completed.
+func (o *Onboarding) Finish() {
+	o.finish(StatusCompleted)
+}
+
+// RolledBack marks the failed onboarding as fully undone.
+func (o *Onboarding) RolledBack() {
+	o.finish(StatusRolledBack)
+}
+
+// CompensationFailed marks the failed onboarding as partially undone; the
+// steps still listed as completed need manual cleanup.
+func (o *Onboarding) CompensationFailed(err error) {
+	o.Error += "; compensation failed: " + err.Error()
+	o.finish(StatusFailed)
+}
+
+func (o *Onboarding) finish(status Status) {
+	now := time.Now().UTC()
+	o.Status = status
+	o.UpdatedAt = now
+	o.FinishedAt = &now
+}
+
+// Repository defines the interface for onboarding persistence.
+// This is a port in hexagonal architecture - implementations are adapters.
+type Repository interface {
+	// Create persists a new onboarding, returning ErrDuplicateKey if its
+	// idempotency key was already used.
+	Create(ctx context.Context, o *Onboarding) error
+
+	// GetByKey retrieves an onboarding by its idempotency key, returning
+	// ErrNotFound if missing.
+	GetByKey(ctx context.Context, key string) (*Onboarding, error)
+
+	// Update saves the onboarding's status and progress.
+	Update(ctx context.Context, o *Onboarding) error
+}
+
+// EventPublisher defines the interface for publishing onboarding events.
+type EventPublisher interface {
+	// PublishOnboarded publishes a tenant onboarded event.
+	PublishOnboarded(ctx context.Context, o *Onboarding) error
+}
@@ -2,9 +2,16 @@
 package tenant
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/businesshours"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
 )
 
 // Status represents the tenant's current status.
@@ -54,6 +61,9 @@ type Settings struct {
 	Notifications NotificationSettings `json:"notifications"`
 	// Security settings
 	Security SecuritySettings `json:"security"`
+	// Region where recordings, transcripts and events are stored; the
+	// services' default storage when unset
+	DataResidency residency.Region `json:"data_residency,omitempty"`
 }
 
 // TelephonySettings contains telephony configuration.
@@ -65,6 +75,92 @@ type TelephonySettings struct {
 	MaxConcurrentCalls   int      `json:"max_concurrent_calls"`
 	CallerIDNumber       string   `json:"caller_id_number,omitempty"`
 	SIPTrunkID           string   `json:"sip_trunk_id,omitempty"`
+
+	// Billing rounding of call detail records, "minimum/increment" in
+	// seconds (e.g. "6/6", "60/60"); the gateway's default when unset
+	CDRRounding string `json:"cdr_rounding,omitempty"`
+
+	// Agent routing
+	DefaultAgentID string        `json:"default_agent_id,omitempty"`
+	RoutingRules   []RoutingRule `json:"routing_rules,omitempty"`
+
+	// Opening hours
+	BusinessHours BusinessHours `json:"business_hours"`
+
+	// Overrides of the voice-gateway feature flags
+	FeatureFlags FeatureFlagOverrides `json:"feature_flags"`
+}
+
+// FeatureFlagOverrides toggles voice-gateway features for the tenant. Unset
+// flags keep the gateway's global default.
+type FeatureFlagOverrides struct {
+	CallRecording        *bool `json:"call_recording,omitempty"`
+	TranscriptionStorage *bool `json:"transcription_storage,omitempty"`
+	AudioStreaming       *bool `json:"audio_streaming,omitempty"`
+
+	// Frames of caller audio the jitter buffer holds before STT; 0 disables it
+	JitterBufferDepth *int `json:"jitter_buffer_depth,omitempty"`
+}
+
+// Closed actions for calls received outside business hours.
+const (
+	ClosedActionHangup    = "hangup"
+	ClosedActionVoicemail = "voicemail"
+	ClosedActionAgent     = "agent"
+)
+
+// BusinessHours defines when a tenant accepts calls. The schedule and IsOpen
+// are shared with voice-gateway through platform-core/businesshours.
+type BusinessHours struct {
+	businesshours.Schedule
+	ClosedAction       string `json:"closed_action,omitempty"`       // hangup, voicemail, agent
+	ClosedAnnouncement string `json:"closed_announcement,omitempty"` // media URI, e.g. "sound:closed"
+	AfterHoursAgentID  string `json:"after_hours_agent_id,omitempty"`
+}
+
+// TimeRange represents an opening interval within a day.
+type TimeRange = businesshours.TimeRange
+
+// Validate checks the schedule and the closed action before the business
+// hours are saved.
+func (b BusinessHours) Validate() error {
+	if err := b.Schedule.Validate(); err != nil {
+		return err
+	}
+
+	switch b.ClosedAction {
+	case "", ClosedActionHangup, ClosedActionVoicemail:
+	case ClosedActionAgent:
+		if b.AfterHoursAgentID == "" {
+			return errors.New("after_hours_agent_id is required when closed_action is agent")
+		}
+	default:
+		return errors.New("invalid closed_action, must be one of: hangup, voicemail, agent")
+	}
+
+	return nil
+}
+
+// RoutingRule selects an agent for calls matching its conditions.
+// Rules are evaluated in ascending priority order; the first match wins.
+type RoutingRule struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Priority   int               `json:"priority"`
+	AgentID    string            `json:"agent_id"`
+	Enabled    bool              `json:"enabled"`
+	Conditions RoutingConditions `json:"conditions"`
+}
+
+// RoutingConditions contains the conditions a call must satisfy for a rule to match.
+type RoutingConditions struct {
+	DIDs             []string          `json:"dids,omitempty"`
+	CallerPrefixes   []string          `json:"caller_prefixes,omitempty"`
+	Weekdays         []string          `json:"weekdays,omitempty"`   // "mon", "tue", ...
+	StartTime        string            `json:"start_time,omitempty"` // "HH:MM"
+	EndTime          string            `json:"end_time,omitempty"`   // "HH:MM"
+	Timezone         string            `json:"timezone,omitempty"`   // IANA name
+	CallerAttributes map[string]string `json:"caller_attributes,omitempty"`
 }
 
 // AIAgentSettings contains AI agent configuration.
@@ -73,9 +169,17 @@ type AIAgentSettings struct {
 	DefaultVoice        string            `json:"default_voice"`
 	SpeechModel         string            `json:"speech_model"`
 	MaxConversationMin  int               `json:"max_conversation_min"`
-	CustomPrompts       map[string]string `json:"custom_prompts,omitempty"`
+	CustomPrompts       map[string]string `json:"custom_prompts,omitempty"` // by name; "fallback" is played when a provider fails mid-call
 	EnableSentiment     bool              `json:"enable_sentiment"`
 	EnableSummarization bool              `json:"enable_summarization"`
+
+	// Description of the business (what it sells, its policies...) given to
+	// the agents' prompts as business_context
+	BusinessContext string `json:"business_context,omitempty"`
+
+	// API keys of the speech and LLM providers, by provider name. Stored
+	// encrypted; never exposed in JSON.
+	ProviderAPIKeys map[string]string `json:"-"`
 }
 
 // NotificationSettings contains notification preferences.
@@ -96,6 +200,41 @@ type SecuritySettings struct {
 	AllowedDomains    []string       `json:"allowed_domains,omitempty"`
 	SessionTimeoutMin int            `json:"session_timeout_min"`
 	PasswordPolicy    PasswordPolicy `json:"password_policy"`
+
+	// PII redaction of stored transcripts and events
+	Redaction redact.Policy `json:"redaction"`
+
+	// Static claims auth-gateway adds to the access tokens of the tenant's
+	// users. auth-gateway only accepts the names on its allowlist.
+	TokenClaims TokenClaims `json:"token_claims,omitempty"`
+}
+
+// TokenClaims maps claim names to their values.
+type TokenClaims map[string]string
+
+// Limits of the custom token claims of a tenant.
+const (
+	MaxTokenClaims         = 10
+	MaxTokenClaimValueSize = 256
+)
+
+var tokenClaimName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// Validate checks the shape of the claims before they are saved. Whether a
+// name may be used at all is decided by auth-gateway when it issues tokens.
+func (c TokenClaims) Validate() error {
+	if len(c) > MaxTokenClaims {
+		return fmt.Errorf("at most %d token claims are allowed", MaxTokenClaims)
+	}
+	for name, value := range c {
+		if !tokenClaimName.MatchString(name) {
+			return fmt.Errorf("invalid token claim name %q, must be lowercase letters, digits and underscores", name)
+		}
+		if len(value) > MaxTokenClaimValueSize {
+			return fmt.Errorf("token claim %q exceeds %d bytes", name, MaxTokenClaimValueSize)
+		}
+	}
+	return nil
 }
 
 // PasswordPolicy defines password requirements.
@@ -139,6 +278,34 @@ type Quota struct {
 	ResetAt            time.Time `json:"reset_at"`
 }
 
+// Reservation is the outcome of reserving usage against a quota.
+type Reservation struct {
+	Allowed          bool `json:"allowed"`
+	RemainingCalls   int  `json:"remaining_calls"`   // -1 when unlimited
+	RemainingMinutes int  `json:"remaining_minutes"` // -1 when unlimited
+}
+
+// Quota resources that thresholds are tracked for.
+const (
+	QuotaResourceCalls   = "calls"
+	QuotaResourceMinutes = "minutes"
+)
+
+// DefaultQuotaThresholds are the shares of a quota limit, in percent, that
+// tenants are warned at before hitting it.
+var DefaultQuotaThresholds = []int{80, 95}
+
+// QuotaThreshold is a share of a quota limit that a tenant's usage reached
+// in the period ending at Period.
+type QuotaThreshold struct {
+	TenantID uuid.UUID `json:"tenant_id"`
+	Resource string    `json:"resource"` // calls, minutes
+	Percent  int       `json:"percent"`
+	Used     int       `json:"used"`
+	Max      int       `json:"max"`
+	Period   time.Time `json:"period_reset_at"`
+}
+
 // Usage represents current usage statistics.
 type Usage struct {
 	TenantID      uuid.UUID `json:"tenant_id"`
@@ -150,10 +317,10 @@ type Usage struct {
 	APIRequests   int64     `json:"api_requests"`
 }
 
-// NewTenant creates a new Tenant with default values.
+// NewTenant creates a new Tenant with default values for its plan.
 func NewTenant(name, email string, plan Plan) *Tenant {
 	now := time.Now().UTC()
-	return &Tenant{
+	t := &Tenant{
 		ID:        uuid.New(),
 		Name:      name,
 		Email:     email,
@@ -164,6 +331,129 @@ func NewTenant(name, email string, plan Plan) *Tenant {
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	t.applyPlanEntitlements()
+	return t
+}
+
+// ChangePlan moves the tenant to plan and aligns its settings with what the
+// plan allows.
+func (t *Tenant) ChangePlan(plan Plan) {
+	t.Plan = plan
+	t.applyPlanEntitlements()
+}
+
+// SetDataResidency pins the tenant's data to region. A region can be set
+// once; moving to another would leave the data already stored behind.
+func (t *Tenant) SetDataResidency(region residency.Region) error {
+	if err := residency.ValidateChange(t.Settings.DataResidency, region); err != nil {
+		return err
+	}
+	t.Settings.DataResidency = region
+	return nil
+}
+
+// applyPlanEntitlements aligns the default settings with what the plan allows.
+func (t *Tenant) applyPlanEntitlements() {
+	t.Settings.applyEntitlements(t.Plan)
+}
+
+// applyEntitlements sets the settings derived from what plan allows.
+func (s *Settings) applyEntitlements(plan Plan) {
+	p := entitlements.Plan(plan)
+
+	s.Telephony.MaxConcurrentCalls = entitlements.Limit(p, entitlements.LimitMaxConcurrentCalls)
+	s.Telephony.RecordingEnabled = entitlements.Can(p, entitlements.FeatureCallRecording)
+	s.Telephony.TranscriptionEnabled = entitlements.Can(p, entitlements.FeatureTranscription)
+	s.Security.MFARequired = entitlements.Can(p, entitlements.FeatureMFAEnforced)
+}
+
+// WithUsageOf carries the usage and reset date of a previous quota over to q,
+// so changing plans changes the limits only.
+func (q *Quota) WithUsageOf(previous *Quota) *Quota {
+	q.UsedCalls = previous.UsedCalls
+	q.UsedMinutes = previous.UsedMinutes
+	q.UsedStorageGB = previous.UsedStorageGB
+	q.ResetAt = previous.ResetAt
+	return q
+}
+
+// Fits reports whether adding calls and minutes keeps usage within the quota.
+func (q *Quota) Fits(calls, minutes int) bool {
+	return withinLimit(q.UsedCalls+calls, q.MaxCallsPerMonth) &&
+		withinLimit(q.UsedMinutes+minutes, q.MaxMinutesPerMonth)
+}
+
+// Reservation returns the outcome of a reservation against q: whether it was
+// allowed and what remains of the quota afterwards.
+func (q *Quota) Reservation(allowed bool) *Reservation {
+	return &Reservation{
+		Allowed:          allowed,
+		RemainingCalls:   remaining(q.UsedCalls, q.MaxCallsPerMonth),
+		RemainingMinutes: remaining(q.UsedMinutes, q.MaxMinutesPerMonth),
+	}
+}
+
+// ReachedThresholds returns the thresholds, in percent of each limit, that
+// usage has reached. Unlimited resources have none.
+func (q *Quota) ReachedThresholds(percents []int) []QuotaThreshold {
+	resources := []struct {
+		name      string
+		used, max int
+	}{
+		{QuotaResourceCalls, q.UsedCalls, q.MaxCallsPerMonth},
+		{QuotaResourceMinutes, q.UsedMinutes, q.MaxMinutesPerMonth},
+	}
+
+	var reached []QuotaThreshold
+	for _, r := range resources {
+		if r.max <= 0 {
+			continue
+		}
+		for _, percent := range percents {
+			if r.used*100 >= r.max*percent {
+				reached = append(reached, QuotaThreshold{
+					TenantID: q.TenantID,
+					Resource: r.name,
+					Percent:  percent,
+					Used:     r.used,
+					Max:      r.max,
+					Period:   q.ResetAt,
+				})
+			}
+		}
+	}
+	return reached
+}
+
+func withinLimit(used, max int) bool {
+	return max == entitlements.Unlimited || used <= max
+}
+
+func remaining(used, max int) int {
+	if max == entitlements.Unlimited {
+		return entitlements.Unlimited
+	}
+	if used >= max {
+		return 0
+	}
+	return max - used
+}
+
+// NewQuota creates the default quota for a tenant plan. Unlimited limits are
+// stored as entitlements.Unlimited (-1). Usage resets at the start of next month.
+func NewQuota(tenantID uuid.UUID, plan Plan) *Quota {
+	p := entitlements.Plan(plan)
+	now := time.Now().UTC()
+
+	return &Quota{
+		TenantID:           tenantID,
+		MaxAPIKeys:         entitlements.Limit(p, entitlements.LimitMaxAPIKeys),
+		MaxUsers:           entitlements.Limit(p, entitlements.LimitMaxUsers),
+		MaxCallsPerMonth:   entitlements.Limit(p, entitlements.LimitMaxCallsPerMonth),
+		MaxMinutesPerMonth: entitlements.Limit(p, entitlements.LimitMaxMinutesPerMonth),
+		MaxStorageGB:       entitlements.Limit(p, entitlements.LimitMaxStorageGB),
+		ResetAt:            time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC),
+	}
 }
 
 // DefaultSettings returns default tenant settings.
@@ -225,6 +515,11 @@ func (t *Tenant) IsActive() bool {
 	return t.Status == StatusActive
 }
 
+// IsOpen returns true if the tenant is within business hours at the given instant.
+func (t *Tenant) IsOpen(at time.Time) bool {
+	return t.Settings.Telephony.BusinessHours.IsOpen(at)
+}
+
 // CanMakeCalls returns true if the tenant can make calls.
 func (t *Tenant) CanMakeCalls() bool {
 	return t.IsActive() && t.Settings.Telephony.MaxConcurrentCalls > 0
@@ -0,0 +1,260 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/businesshours"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+)
+
+func newTenantWithHours(timezone string) *Tenant {
+	ranges := []TimeRange{{Start: "08:00", End: "17:00"}}
+
+	t := NewTenant("Acme", "ops@acme.com", PlanProfessional)
+	t.Settings.Telephony.BusinessHours = BusinessHours{Schedule: businesshours.Schedule{
+		Enabled:  true,
+		Timezone: timezone,
+		Weekly: map[string][]TimeRange{
+			"mon": ranges,
+			"tue": ranges,
+			"wed": ranges,
+			"thu": ranges,
+			"fri": ranges,
+			"sat": {{Start: "10:00", End: "14:00"}},
+		},
+		Holidays: []string{"2025-01-01"},
+	}}
+	return t
+}
+
+func TestTenant_IsOpen(t *testing.T) {
+	tenant := newTenantWithHours("America/Sao_Paulo")
+
+	// Monday 2025-01-06 09:00 BRT
+	if !tenant.IsOpen(time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Should be open on Monday morning")
+	}
+
+	// Wednesday 2025-01-01 12:00 BRT
+	if tenant.IsOpen(time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Error("Should be closed on holiday")
+	}
+
+	if !NewTenant("Acme", "ops@acme.com", PlanStarter).IsOpen(time.Date(2025, 1, 5, 3, 0, 0, 0, time.UTC)) {
+		t.Error("Tenant without business hours should always be open")
+	}
+}
+
+func TestBusinessHours_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(b *BusinessHours)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(b *BusinessHours) {}},
+		{name: "overnight range", mutate: func(b *BusinessHours) {
+			b.Weekly["fri"] = []TimeRange{{Start: "22:00", End: "06:00"}}
+		}},
+		{name: "invalid timezone", mutate: func(b *BusinessHours) { b.Timezone = "Mars/Olympus" }, wantErr: true},
+		{name: "invalid range", mutate: func(b *BusinessHours) {
+			b.Weekly["mon"] = []TimeRange{{Start: "8:00am", End: "17:00"}}
+		}, wantErr: true},
+		{name: "invalid closed action", mutate: func(b *BusinessHours) { b.ClosedAction = "transfer" }, wantErr: true},
+		{name: "agent without after-hours agent", mutate: func(b *BusinessHours) { b.ClosedAction = ClosedActionAgent }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bh := newTenantWithHours("America/Sao_Paulo").Settings.Telephony.BusinessHours
+			tt.mutate(&bh)
+			if err := bh.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenClaims_Validate(t *testing.T) {
+	tooMany := TokenClaims{}
+	for i := 0; i <= MaxTokenClaims; i++ {
+		tooMany[fmt.Sprintf("claim_%d", i)] = "x"
+	}
+
+	tests := []struct {
+		name    string
+		claims  TokenClaims
+		wantErr bool
+	}{
+		{name: "empty", claims: nil},
+		{name: "valid", claims: TokenClaims{"department": "sales", "cost_center": "cc-42"}},
+		{name: "uppercase name", claims: TokenClaims{"Department": "sales"}, wantErr: true},
+		{name: "namespaced name", claims: TokenClaims{"https://acme.com/dept": "sales"}, wantErr: true},
+		{name: "value too long", claims: TokenClaims{"department": strings.Repeat("x", MaxTokenClaimValueSize+1)}, wantErr: true},
+		{name: "too many claims", claims: tooMany, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.claims.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTenant_PlanEntitlements(t *testing.T) {
+	tests := []struct {
+		plan          Plan
+		maxCalls      int
+		recording     bool
+		transcription bool
+		mfa           bool
+	}{
+		{PlanStarter, 5, false, false, false},
+		{PlanProfessional, 25, true, true, false},
+		{PlanEnterprise, 200, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.plan), func(t *testing.T) {
+			tenant := NewTenant("Acme", "ops@acme.com", tt.plan)
+			telephony := tenant.Settings.Telephony
+
+			if telephony.MaxConcurrentCalls != tt.maxCalls {
+				t.Errorf("Expected MaxConcurrentCalls %d, got %d", tt.maxCalls, telephony.MaxConcurrentCalls)
+			}
+			if telephony.RecordingEnabled != tt.recording {
+				t.Errorf("Expected RecordingEnabled %v, got %v", tt.recording, telephony.RecordingEnabled)
+			}
+			if telephony.TranscriptionEnabled != tt.transcription {
+				t.Errorf("Expected TranscriptionEnabled %v, got %v", tt.transcription, telephony.TranscriptionEnabled)
+			}
+			if tenant.Settings.Security.MFARequired != tt.mfa {
+				t.Errorf("Expected MFARequired %v, got %v", tt.mfa, tenant.Settings.Security.MFARequired)
+			}
+		})
+	}
+}
+
+func TestNewQuota(t *testing.T) {
+	tenant := NewTenant("Acme", "ops@acme.com", PlanProfessional)
+	quota := NewQuota(tenant.ID, tenant.Plan)
+
+	if quota.TenantID != tenant.ID {
+		t.Errorf("Expected TenantID %s, got %s", tenant.ID, quota.TenantID)
+	}
+	if quota.MaxAPIKeys != 10 || quota.MaxUsers != 25 || quota.MaxCallsPerMonth != 10000 ||
+		quota.MaxMinutesPerMonth != 20000 || quota.MaxStorageGB != 50 {
+		t.Errorf("Unexpected professional quota: %+v", quota)
+	}
+	if !quota.ResetAt.After(time.Now()) || quota.ResetAt.Day() != 1 {
+		t.Errorf("Expected reset on the first day of next month, got %s", quota.ResetAt)
+	}
+
+	enterprise := NewQuota(tenant.ID, PlanEnterprise)
+	if enterprise.MaxCallsPerMonth != -1 || enterprise.MaxUsers != -1 {
+		t.Errorf("Expected unlimited enterprise quota, got %+v", enterprise)
+	}
+}
+
+func TestTenant_ChangePlan(t *testing.T) {
+	tenant := NewTenant("Acme", "ops@acme.com", PlanStarter)
+	previous := NewQuota(tenant.ID, tenant.Plan)
+	previous.UsedCalls = 120
+
+	tenant.ChangePlan(PlanProfessional)
+	quota := NewQuota(tenant.ID, tenant.Plan).WithUsageOf(previous)
+
+	if tenant.Plan != PlanProfessional {
+		t.Errorf("Expected plan %s, got %s", PlanProfessional, tenant.Plan)
+	}
+	if quota.MaxCallsPerMonth != 10000 || quota.UsedCalls != 120 || !quota.ResetAt.Equal(previous.ResetAt) {
+		t.Errorf("Expected professional limits with the usage kept, got %+v", quota)
+	}
+}
+
+func TestTenant_SetDataResidency(t *testing.T) {
+	tenant := NewTenant("Acme", "ops@acme.com", PlanEnterprise)
+
+	if err := tenant.SetDataResidency(residency.RegionEU); err != nil {
+		t.Fatalf("Expected the region to be set, got %v", err)
+	}
+	if err := tenant.SetDataResidency(residency.RegionUS); !errors.Is(err, residency.ErrCrossBoundary) {
+		t.Errorf("Expected ErrCrossBoundary moving EU data to US, got %v", err)
+	}
+	if tenant.Settings.DataResidency != residency.RegionEU {
+		t.Errorf("Expected the region to stay eu, got %q", tenant.Settings.DataResidency)
+	}
+}
+
+func TestTenant_EffectiveSettings(t *testing.T) {
+	t.Run("unset settings fall back to the plan defaults", func(t *testing.T) {
+		tenant := &Tenant{Plan: PlanEnterprise}
+
+		settings := tenant.EffectiveSettings()
+
+		if settings.Telephony.MaxConcurrentCalls != 200 || !settings.Security.MFARequired {
+			t.Errorf("Expected the enterprise entitlements, got %+v", settings)
+		}
+		if settings.AIAgent.DefaultLanguage != "en-US" || settings.Security.PasswordPolicy.MinLength != 8 {
+			t.Errorf("Expected the platform defaults, got %+v", settings)
+		}
+	})
+
+	t.Run("plan defaults win over platform defaults", func(t *testing.T) {
+		tenant := &Tenant{Plan: PlanStarter}
+		tenant.Settings.AIAgent.DefaultLanguage = "pt-BR"
+
+		settings := tenant.EffectiveSettings()
+		platform := DefaultSettings()
+
+		if settings.Telephony.MaxConcurrentCalls != 5 || platform.Telephony.MaxConcurrentCalls == 5 {
+			t.Errorf("Expected the starter call limit over the platform one, got %d", settings.Telephony.MaxConcurrentCalls)
+		}
+		if settings.Telephony.RecordingEnabled || !platform.Telephony.RecordingEnabled {
+			t.Error("Expected recording off on starter over the platform default")
+		}
+	})
+
+	t.Run("explicit settings win over defaults", func(t *testing.T) {
+		tenant := &Tenant{Plan: PlanProfessional}
+		tenant.Settings.Telephony.MaxConcurrentCalls = 3
+		tenant.Settings.Telephony.AllowedCountries = []string{"BR"}
+		tenant.Settings.AIAgent.DefaultVoice = "pt-BR-Neural"
+		tenant.Settings.Security.SessionTimeoutMin = 15
+
+		settings := tenant.EffectiveSettings()
+
+		telephony := settings.Telephony
+		if telephony.MaxConcurrentCalls != 3 || len(telephony.AllowedCountries) != 1 || telephony.AllowedCountries[0] != "BR" {
+			t.Errorf("Expected the tenant telephony settings, got %+v", telephony)
+		}
+		// A section the tenant set keeps its flags, even when off
+		if telephony.RecordingEnabled || telephony.TranscriptionEnabled {
+			t.Error("Expected the tenant to keep recording and transcription off")
+		}
+		if telephony.DefaultCountryCode != "US" {
+			t.Errorf("Expected the unset country code from the defaults, got %q", telephony.DefaultCountryCode)
+		}
+		if settings.AIAgent.DefaultVoice != "pt-BR-Neural" || settings.AIAgent.DefaultLanguage != "en-US" {
+			t.Errorf("Expected the tenant voice with the default language, got %+v", settings.AIAgent)
+		}
+		if settings.Security.SessionTimeoutMin != 15 || settings.Security.PasswordPolicy.MinLength != 8 {
+			t.Errorf("Expected the tenant session timeout with the default password policy, got %+v", settings.Security)
+		}
+	})
+
+	t.Run("a new tenant resolves to its own settings", func(t *testing.T) {
+		tenant := NewTenant("Acme", "ops@acme.com", PlanProfessional)
+
+		settings := tenant.EffectiveSettings()
+
+		if settings.Telephony.MaxConcurrentCalls != 25 || settings.AIAgent.SpeechModel != "whisper-large" {
+			t.Errorf("Expected the new tenant settings unchanged, got %+v", settings)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+// PlanUsage is what a tenant uses right now that the limits of a new plan
+// must still fit.
+type PlanUsage struct {
+	ActiveUsers     int
+	ConcurrentCalls int
+	StorageGB       float64
+}
+
+// UsageReader reads the live usage of a tenant that its quota doesn't
+// track, from the services owning it.
+type UsageReader interface {
+	// ActiveUsers returns the number of active users of the tenant.
+	ActiveUsers(ctx context.Context, tenantID uuid.UUID) (int, error)
+
+	// ConcurrentCalls returns the number of calls the tenant has in progress.
+	ConcurrentCalls(ctx context.Context, tenantID uuid.UUID) (int, error)
+}
+
+// PlanBlocker is a limit of the new plan that current usage exceeds.
+type PlanBlocker struct {
+	Limit entitlements.LimitKey `json:"limit"`
+	Used  float64               `json:"used"`
+	Max   int                   `json:"max"`
+}
+
+// PlanChangeError is returned when a tenant's usage doesn't fit the plan it
+// is moving to. It lists every limit in the way.
+type PlanChangeError struct {
+	From     Plan
+	To       Plan
+	Blockers []PlanBlocker
+}
+
+func (e *PlanChangeError) Error() string {
+	reasons := make([]string, len(e.Blockers))
+	for i, b := range e.Blockers {
+		reasons[i] = fmt.Sprintf("%s is %g, %s allows %d", b.Limit, b.Used, e.To, b.Max)
+	}
+	return fmt.Sprintf("cannot change plan from %s to %s: %s", e.From, e.To, strings.Join(reasons, "; "))
+}
+
+// CheckPlanChange reports whether the tenant can move to plan with its
+// current usage. Upgrades always fit; a downgrade is refused with a
+// *PlanChangeError while usage exceeds a limit of the new plan.
+func (t *Tenant) CheckPlanChange(plan Plan, usage PlanUsage) error {
+	p := entitlements.Plan(plan)
+	limits := []struct {
+		key  entitlements.LimitKey
+		used float64
+	}{
+		{entitlements.LimitMaxUsers, float64(usage.ActiveUsers)},
+		{entitlements.LimitMaxConcurrentCalls, float64(usage.ConcurrentCalls)},
+		{entitlements.LimitMaxStorageGB, usage.StorageGB},
+	}
+
+	var blockers []PlanBlocker
+	for _, l := range limits {
+		max := entitlements.Limit(p, l.key)
+		if max != entitlements.Unlimited && l.used > float64(max) {
+			blockers = append(blockers, PlanBlocker{Limit: l.key, Used: l.used, Max: max})
+		}
+	}
+	if len(blockers) > 0 {
+		return &PlanChangeError{From: t.Plan, To: plan, Blockers: blockers}
+	}
+
+	return nil
+}
@@ -3,10 +3,16 @@ package tenant
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	pagination "github.com/serphona/serphona/backend/go/libs/platform-pagination"
 )
 
+// ErrSlugTaken is returned by Repository.Create when another tenant, deleted
+// or not, already holds the slug, e.g. one created concurrently.
+var ErrSlugTaken = errors.New("tenant slug already taken")
+
 // Repository defines the interface for tenant persistence.
 // This is a port in hexagonal architecture - implementations are adapters.
 type Repository interface {
@@ -29,7 +35,7 @@ type Repository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 
 	// List retrieves tenants with pagination and filtering.
-	List(ctx context.Context, filter ListFilter) (*ListResult, error)
+	List(ctx context.Context, filter ListFilter) (*pagination.Page[*Tenant], error)
 
 	// UpdateSettings updates only the tenant settings.
 	UpdateSettings(ctx context.Context, id uuid.UUID, settings Settings) error
@@ -43,31 +49,31 @@ type Repository interface {
 	// IncrementUsage increments usage counters for a tenant.
 	IncrementUsage(ctx context.Context, tenantID uuid.UUID, calls, minutes int) error
 
+	// ReserveUsage adds calls and minutes to the tenant's usage only if the
+	// result stays within its quota, checked and applied atomically.
+	ReserveUsage(ctx context.Context, tenantID uuid.UUID, calls, minutes int) (*Reservation, error)
+
+	// MarkThresholdReached records that a quota threshold fired in its
+	// period. It returns false if it had already fired.
+	MarkThresholdReached(ctx context.Context, threshold QuotaThreshold) (bool, error)
+
 	// ExistsBySlug checks if a tenant with the given slug exists.
 	ExistsBySlug(ctx context.Context, slug string) (bool, error)
 
+	// TakenSlugs returns which of the slugs are held by a tenant, deleted or
+	// not, in a single lookup.
+	TakenSlugs(ctx context.Context, slugs []string) (map[string]bool, error)
+
 	// ExistsByEmail checks if a tenant with the given email exists.
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 }
 
 // ListFilter contains filter options for listing tenants.
 type ListFilter struct {
-	Status     *Status `json:"status,omitempty"`
-	Plan       *Plan   `json:"plan,omitempty"`
-	Search     string  `json:"search,omitempty"` // Search in name, email
-	PageSize   int     `json:"page_size"`
-	PageNumber int     `json:"page_number"`
-	SortBy     string  `json:"sort_by"`
-	SortOrder  string  `json:"sort_order"` // asc, desc
-}
-
-// ListResult contains the result of a list operation.
-type ListResult struct {
-	Tenants    []*Tenant `json:"tenants"`
-	Total      int64     `json:"total"`
-	PageSize   int       `json:"page_size"`
-	PageNumber int       `json:"page_number"`
-	TotalPages int       `json:"total_pages"`
+	Status *Status            `json:"status,omitempty"`
+	Plan   *Plan              `json:"plan,omitempty"`
+	Search string             `json:"search,omitempty"` // Search in name, email
+	Page   pagination.Request `json:"page"`             // offset or cursor, sort_by and sort_order
 }
 
 // Cache defines the interface for tenant caching.
@@ -110,4 +116,10 @@ type EventPublisher interface {
 
 	// PublishSettingsUpdated publishes a settings updated event.
 	PublishSettingsUpdated(ctx context.Context, tenantID uuid.UUID, settings *Settings) error
+
+	// PublishPlanChanged publishes a plan changed event.
+	PublishPlanChanged(ctx context.Context, tenant *Tenant, previous Plan) error
+
+	// PublishQuotaThresholdReached publishes a quota threshold reached event.
+	PublishQuotaThresholdReached(ctx context.Context, threshold QuotaThreshold) error
 }
@@ -0,0 +1,53 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrDataKeyNotFound is returned by DataKeyRepository when the tenant has no
+// data key of the requested version, or none at all.
+var ErrDataKeyNotFound = errors.New("tenant data key not found")
+
+// ErrDataKeyExists is returned by DataKeyRepository.Create when the version
+// is already taken, e.g. by a key created concurrently.
+var ErrDataKeyExists = errors.New("tenant data key version already exists")
+
+// SecretCipher encrypts the secret fields of a tenant's settings (webhook
+// secret, provider API keys) under a data key of the tenant's own. Each
+// ciphertext names the key version that sealed it, so ciphertexts stay
+// readable after RotateKey.
+type SecretCipher interface {
+	// Encrypt seals plaintext under the tenant's current data key.
+	Encrypt(ctx context.Context, tenantID uuid.UUID, plaintext string) (string, error)
+
+	// Decrypt opens a ciphertext made by Encrypt for the same tenant.
+	Decrypt(ctx context.Context, tenantID uuid.UUID, ciphertext string) (string, error)
+
+	// RotateKey makes a new data key current for the tenant.
+	RotateKey(ctx context.Context, tenantID uuid.UUID) error
+}
+
+// DataKey is a version of a tenant's data key, as stored: wrapped by the
+// master key, never in plain.
+type DataKey struct {
+	TenantID  uuid.UUID
+	Version   int
+	Wrapped   []byte
+	CreatedAt time.Time
+}
+
+// DataKeyRepository defines the interface for data key persistence.
+type DataKeyRepository interface {
+	// Latest returns the newest data key of the tenant.
+	Latest(ctx context.Context, tenantID uuid.UUID) (*DataKey, error)
+
+	// Get returns a data key version of the tenant.
+	Get(ctx context.Context, tenantID uuid.UUID, version int) (*DataKey, error)
+
+	// Create persists a new data key version.
+	Create(ctx context.Context, key *DataKey) error
+}
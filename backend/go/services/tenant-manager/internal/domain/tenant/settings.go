@@ -0,0 +1,46 @@
+package tenant
+
+import "reflect"
+
+// PlanDefaults returns the settings a tenant on plan gets when it sets
+// nothing: the platform defaults, with what the plan allows or limits
+// layered over them.
+func PlanDefaults(plan Plan) Settings {
+	s := DefaultSettings()
+	s.applyEntitlements(plan)
+	return s
+}
+
+// EffectiveSettings returns the complete settings the tenant runs with: its
+// explicit settings layered over the defaults of its plan, which are
+// layered over the platform defaults.
+//
+// A zero value means unset. Booleans can't tell "off" from unset, so they
+// are explicit within any settings section the tenant set something in;
+// a section left entirely zero falls back to the defaults as a whole.
+func (t *Tenant) EffectiveSettings() Settings {
+	effective := PlanDefaults(t.Plan)
+	overlay(reflect.ValueOf(&effective).Elem(), reflect.ValueOf(t.Settings))
+	return effective
+}
+
+// overlay copies the set fields of the src struct over dst.
+func overlay(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		from, to := src.Field(i), dst.Field(i)
+		if !to.CanSet() {
+			continue
+		}
+		switch {
+		case from.Kind() == reflect.Struct:
+			if !from.IsZero() {
+				overlay(to, from)
+			}
+		case from.Kind() == reflect.Bool:
+			// The enclosing section is set, so its flags are explicit
+			to.Set(from)
+		case !from.IsZero():
+			to.Set(from)
+		}
+	}
+}
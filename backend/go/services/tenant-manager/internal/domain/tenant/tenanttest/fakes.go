@@ -0,0 +1,306 @@
+package tenanttest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"tenant-manager/internal/domain/tenant"
+)
+
+// ErrCacheMiss is returned for keys that are not cached.
+var ErrCacheMiss = errors.New("key not found in cache")
+
+// Cache is an in-memory tenant.Cache.
+type Cache struct {
+	mu          sync.Mutex
+	tenants     map[string]*tenant.Tenant
+	settings    map[uuid.UUID]*tenant.Settings
+	invalidated []uuid.UUID
+	hooks
+}
+
+// NewCache creates an empty cache.
+func NewCache() *Cache {
+	return &Cache{
+		tenants:  make(map[string]*tenant.Tenant),
+		settings: make(map[uuid.UUID]*tenant.Settings),
+		hooks:    newHooks(),
+	}
+}
+
+// FailOn makes every call to method return err until it is set to nil.
+func (c *Cache) FailOn(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[method] = err
+}
+
+// Calls returns how many times method was called.
+func (c *Cache) Calls(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+// Invalidated returns the tenants invalidated so far, in order.
+func (c *Cache) Invalidated() []uuid.UUID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]uuid.UUID(nil), c.invalidated...)
+}
+
+// Get retrieves a tenant from cache.
+func (c *Cache) Get(ctx context.Context, key string) (*tenant.Tenant, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("Get"); err != nil {
+		return nil, err
+	}
+	t, ok := c.tenants[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return clone(t), nil
+}
+
+// Set stores a tenant in cache.
+func (c *Cache) Set(ctx context.Context, key string, t *tenant.Tenant) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("Set"); err != nil {
+		return err
+	}
+	c.tenants[key] = clone(t)
+	return nil
+}
+
+// Delete removes a tenant from cache.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("Delete"); err != nil {
+		return err
+	}
+	delete(c.tenants, key)
+	return nil
+}
+
+// GetSettings retrieves settings from cache.
+func (c *Cache) GetSettings(ctx context.Context, tenantID uuid.UUID) (*tenant.Settings, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("GetSettings"); err != nil {
+		return nil, err
+	}
+	s, ok := c.settings[tenantID]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	found := *s
+	return &found, nil
+}
+
+// SetSettings stores settings in cache.
+func (c *Cache) SetSettings(ctx context.Context, tenantID uuid.UUID, settings *tenant.Settings) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.call("SetSettings"); err != nil {
+		return err
+	}
+	saved := *settings
+	c.settings[tenantID] = &saved
+	return nil
+}
+
+// Invalidate removes all cached data for a tenant.
+func (c *Cache) Invalidate(ctx context.Context, tenantID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidated = append(c.invalidated, tenantID)
+	if err := c.call("Invalidate"); err != nil {
+		return err
+	}
+	for key, t := range c.tenants {
+		if t.ID == tenantID {
+			delete(c.tenants, key)
+		}
+	}
+	delete(c.settings, tenantID)
+	return nil
+}
+
+// Event types recorded by EventPublisher.
+const (
+	EventCreated         = "created"
+	EventUpdated         = "updated"
+	EventDeleted         = "deleted"
+	EventActivated       = "activated"
+	EventSuspended       = "suspended"
+	EventSettingsUpdated = "settings_updated"
+	EventPlanChanged     = "plan_changed"
+	EventQuotaThreshold  = "quota_threshold_reached"
+)
+
+// Event is a tenant event recorded by EventPublisher.
+type Event struct {
+	Type     string
+	TenantID uuid.UUID
+	Tenant   *tenant.Tenant   // nil for deleted and settings events
+	Settings *tenant.Settings // settings events only
+	Previous tenant.Plan      // plan events only
+
+	Threshold *tenant.QuotaThreshold // quota threshold events only
+}
+
+// EventPublisher is a tenant.EventPublisher that records what it publishes.
+// Failed publishes are not recorded.
+type EventPublisher struct {
+	mu     sync.Mutex
+	events []Event
+	hooks
+}
+
+// NewEventPublisher creates an event recorder.
+func NewEventPublisher() *EventPublisher {
+	return &EventPublisher{hooks: newHooks()}
+}
+
+// FailOn makes every call to method return err until it is set to nil.
+func (p *EventPublisher) FailOn(method string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures[method] = err
+}
+
+// Events returns the published events, in order.
+func (p *EventPublisher) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Event(nil), p.events...)
+}
+
+// PublishCreated publishes a tenant created event.
+func (p *EventPublisher) PublishCreated(ctx context.Context, t *tenant.Tenant) error {
+	return p.record("PublishCreated", Event{Type: EventCreated, TenantID: t.ID, Tenant: clone(t)})
+}
+
+// PublishUpdated publishes a tenant updated event.
+func (p *EventPublisher) PublishUpdated(ctx context.Context, t *tenant.Tenant) error {
+	return p.record("PublishUpdated", Event{Type: EventUpdated, TenantID: t.ID, Tenant: clone(t)})
+}
+
+// PublishDeleted publishes a tenant deleted event.
+func (p *EventPublisher) PublishDeleted(ctx context.Context, tenantID uuid.UUID) error {
+	return p.record("PublishDeleted", Event{Type: EventDeleted, TenantID: tenantID})
+}
+
+// PublishActivated publishes a tenant activated event.
+func (p *EventPublisher) PublishActivated(ctx context.Context, t *tenant.Tenant) error {
+	return p.record("PublishActivated", Event{Type: EventActivated, TenantID: t.ID, Tenant: clone(t)})
+}
+
+// PublishSuspended publishes a tenant suspended event.
+func (p *EventPublisher) PublishSuspended(ctx context.Context, t *tenant.Tenant) error {
+	return p.record("PublishSuspended", Event{Type: EventSuspended, TenantID: t.ID, Tenant: clone(t)})
+}
+
+// PublishSettingsUpdated publishes a settings updated event.
+func (p *EventPublisher) PublishSettingsUpdated(ctx context.Context, tenantID uuid.UUID, settings *tenant.Settings) error {
+	saved := *settings
+	return p.record("PublishSettingsUpdated", Event{Type: EventSettingsUpdated, TenantID: tenantID, Settings: &saved})
+}
+
+// PublishPlanChanged publishes a plan changed event.
+func (p *EventPublisher) PublishPlanChanged(ctx context.Context, t *tenant.Tenant, previous tenant.Plan) error {
+	return p.record("PublishPlanChanged", Event{Type: EventPlanChanged, TenantID: t.ID, Tenant: clone(t), Previous: previous})
+}
+
+// PublishQuotaThresholdReached publishes a quota threshold reached event.
+func (p *EventPublisher) PublishQuotaThresholdReached(ctx context.Context, threshold tenant.QuotaThreshold) error {
+	return p.record("PublishQuotaThresholdReached", Event{Type: EventQuotaThreshold, TenantID: threshold.TenantID, Threshold: &threshold})
+}
+
+func (p *EventPublisher) record(method string, e Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.call(method); err != nil {
+		return err
+	}
+	p.events = append(p.events, e)
+	return nil
+}
+
+// Compile-time checks that the fakes implement the tenant ports.
+var (
+	_ tenant.Repository        = (*Repository)(nil)
+	_ tenant.Cache             = (*Cache)(nil)
+	_ tenant.EventPublisher    = (*EventPublisher)(nil)
+	_ tenant.DataKeyRepository = (*DataKeys)(nil)
+)
+
+// DataKeys is an in-memory tenant.DataKeyRepository.
+type DataKeys struct {
+	mu   sync.Mutex
+	keys map[uuid.UUID][]tenant.DataKey // by tenant, in version order
+	hooks
+}
+
+// NewDataKeys creates an empty data key store.
+func NewDataKeys() *DataKeys {
+	return &DataKeys{keys: make(map[uuid.UUID][]tenant.DataKey), hooks: newHooks()}
+}
+
+// FailOn makes every call to method return err until it is set to nil.
+func (d *DataKeys) FailOn(method string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures[method] = err
+}
+
+// Latest returns the newest data key of the tenant.
+func (d *DataKeys) Latest(ctx context.Context, tenantID uuid.UUID) (*tenant.DataKey, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.call("Latest"); err != nil {
+		return nil, err
+	}
+	keys := d.keys[tenantID]
+	if len(keys) == 0 {
+		return nil, tenant.ErrDataKeyNotFound
+	}
+	key := keys[len(keys)-1]
+	return &key, nil
+}
+
+// Get returns a data key version of the tenant.
+func (d *DataKeys) Get(ctx context.Context, tenantID uuid.UUID, version int) (*tenant.DataKey, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.call("Get"); err != nil {
+		return nil, err
+	}
+	for _, key := range d.keys[tenantID] {
+		if key.Version == version {
+			return &key, nil
+		}
+	}
+	return nil, tenant.ErrDataKeyNotFound
+}
+
+// Create persists a new data key version.
+func (d *DataKeys) Create(ctx context.Context, key *tenant.DataKey) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.call("Create"); err != nil {
+		return err
+	}
+	keys := d.keys[key.TenantID]
+	if len(keys) > 0 && keys[len(keys)-1].Version >= key.Version {
+		return tenant.ErrDataKeyExists
+	}
+	d.keys[key.TenantID] = append(keys, *key)
+	return nil
+}
@@ -0,0 +1,427 @@
+// Package tenanttest provides in-memory implementations of the tenant ports
+// for tests. It is the canonical way to unit-test the tenant application
+// service without Postgres, Redis or Kafka:
+//
+//	repo := tenanttest.NewRepository(seed...)
+//	cache, events := tenanttest.NewCache(), tenanttest.NewEventPublisher()
+//	svc := tenantapp.NewService(repo, nil, cache, events, nil, zap.NewNop())
+//
+// Every fake is safe for concurrent use, counts calls per method and can be
+// told to fail a method with FailOn.
+package tenanttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	pagination "github.com/serphona/serphona/backend/go/libs/platform-pagination"
+
+	"tenant-manager/internal/domain/tenant"
+)
+
+// ErrNotFound is returned for missing or deleted tenants and quotas, like the
+// Postgres repository does.
+var ErrNotFound = errors.New("tenant not found")
+
+// ErrCursorUnsupported is returned when listing with a cursor; the in-memory
+// repository only pages by offset.
+var ErrCursorUnsupported = fmt.Errorf("%w: in-memory repository only pages by offset", pagination.ErrInvalidRequest)
+
+// hooks counts calls and injects errors per method name.
+type hooks struct {
+	calls    map[string]int
+	failures map[string]error
+}
+
+func newHooks() hooks {
+	return hooks{calls: make(map[string]int), failures: make(map[string]error)}
+}
+
+// call records a call to method and returns its injected error, if any. The
+// caller must hold the fake's lock.
+func (h hooks) call(method string) error {
+	h.calls[method]++
+	return h.failures[method]
+}
+
+// Repository is an in-memory tenant.Repository. Like the Postgres one,
+// deleted tenants are kept but invisible to reads, slugs are unique across
+// all tenants, and stored tenants are copied in and out so callers can't
+// change them behind its back.
+type Repository struct {
+	mu       sync.Mutex
+	tenants  map[uuid.UUID]*tenant.Tenant
+	quotas   map[uuid.UUID]*tenant.Quota
+	reached  map[string]bool // quota thresholds fired, by tenant/period/resource/percent
+	onCreate func(*tenant.Tenant)
+	hooks
+}
+
+// NewRepository creates a repository seeded with tenants.
+func NewRepository(seed ...*tenant.Tenant) *Repository {
+	r := &Repository{
+		tenants: make(map[uuid.UUID]*tenant.Tenant),
+		quotas:  make(map[uuid.UUID]*tenant.Quota),
+		reached: make(map[string]bool),
+		hooks:   newHooks(),
+	}
+	r.Seed(seed...)
+	return r
+}
+
+// Seed stores tenants as they are, without any checks.
+func (r *Repository) Seed(tenants ...*tenant.Tenant) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range tenants {
+		r.tenants[t.ID] = clone(t)
+	}
+}
+
+// FailOn makes every call to method return err until it is set to nil.
+// method is the name of a tenant.Repository method, e.g. "ExistsBySlug".
+func (r *Repository) FailOn(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures[method] = err
+}
+
+// OnCreate runs fn before each Create is applied, without holding the
+// repository lock, e.g. to seed a competing tenant and simulate a concurrent
+// create.
+func (r *Repository) OnCreate(fn func(*tenant.Tenant)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onCreate = fn
+}
+
+// Calls returns how many times method was called.
+func (r *Repository) Calls(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls[method]
+}
+
+// Stored returns a copy of a tenant as stored, including deleted ones.
+func (r *Repository) Stored(id uuid.UUID) (*tenant.Tenant, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tenants[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(t), true
+}
+
+// Create persists a new tenant.
+func (r *Repository) Create(ctx context.Context, t *tenant.Tenant) error {
+	r.mu.Lock()
+	onCreate := r.onCreate
+	r.mu.Unlock()
+	if onCreate != nil {
+		onCreate(t)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("Create"); err != nil {
+		return err
+	}
+	if _, ok := r.tenants[t.ID]; ok {
+		return fmt.Errorf("tenant %s already exists", t.ID)
+	}
+	for _, existing := range r.tenants {
+		if existing.Slug == t.Slug {
+			return tenant.ErrSlugTaken
+		}
+	}
+	r.tenants[t.ID] = clone(t)
+	return nil
+}
+
+// GetByID retrieves a tenant by its ID.
+func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*tenant.Tenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("GetByID"); err != nil {
+		return nil, err
+	}
+	return r.find(func(t *tenant.Tenant) bool { return t.ID == id })
+}
+
+// GetBySlug retrieves a tenant by its slug.
+func (r *Repository) GetBySlug(ctx context.Context, slug string) (*tenant.Tenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("GetBySlug"); err != nil {
+		return nil, err
+	}
+	return r.find(func(t *tenant.Tenant) bool { return t.Slug == slug })
+}
+
+// GetByEmail retrieves a tenant by its email.
+func (r *Repository) GetByEmail(ctx context.Context, email string) (*tenant.Tenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("GetByEmail"); err != nil {
+		return nil, err
+	}
+	return r.find(func(t *tenant.Tenant) bool { return t.Email == email })
+}
+
+// Update updates an existing tenant.
+func (r *Repository) Update(ctx context.Context, t *tenant.Tenant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("Update"); err != nil {
+		return err
+	}
+	stored, ok := r.tenants[t.ID]
+	if !ok || stored.DeletedAt != nil {
+		return ErrNotFound
+	}
+	r.tenants[t.ID] = clone(t)
+	return nil
+}
+
+// Delete soft-deletes a tenant.
+func (r *Repository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("Delete"); err != nil {
+		return err
+	}
+	t, ok := r.tenants[id]
+	if !ok || t.DeletedAt != nil {
+		return ErrNotFound
+	}
+	t.SoftDelete()
+	return nil
+}
+
+// List retrieves tenants with offset pagination and filtering, newest first
+// unless the request sorts otherwise.
+func (r *Repository) List(ctx context.Context, filter tenant.ListFilter) (*pagination.Page[*tenant.Tenant], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("List"); err != nil {
+		return nil, err
+	}
+	req := filter.Page
+	if req.IsCursor() {
+		return nil, ErrCursorUnsupported
+	}
+
+	search := strings.ToLower(filter.Search)
+	var matches []*tenant.Tenant
+	for _, t := range r.tenants {
+		switch {
+		case t.DeletedAt != nil:
+		case filter.Status != nil && t.Status != *filter.Status:
+		case filter.Plan != nil && t.Plan != *filter.Plan:
+		case search != "" && !strings.Contains(strings.ToLower(t.Name), search) && !strings.Contains(strings.ToLower(t.Email), search):
+		default:
+			matches = append(matches, clone(t))
+		}
+	}
+
+	less := sortKey(req.SortBy)
+	asc := strings.EqualFold(string(req.SortOrder), string(pagination.OrderAsc))
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if !asc {
+			a, b = b, a
+		}
+		if less(a, b) != less(b, a) {
+			return less(a, b)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+
+	total := int64(len(matches))
+	start := min(req.Offset(), len(matches))
+	end := min(start+req.PageSize, len(matches))
+	page := pagination.NewPage(matches[start:end], total, req)
+	return &page, nil
+}
+
+// UpdateSettings updates only the tenant settings.
+func (r *Repository) UpdateSettings(ctx context.Context, id uuid.UUID, settings tenant.Settings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("UpdateSettings"); err != nil {
+		return err
+	}
+	t, ok := r.tenants[id]
+	if !ok || t.DeletedAt != nil {
+		return ErrNotFound
+	}
+	t.Settings = settings
+	t.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// GetQuota retrieves the quota for a tenant.
+func (r *Repository) GetQuota(ctx context.Context, tenantID uuid.UUID) (*tenant.Quota, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("GetQuota"); err != nil {
+		return nil, err
+	}
+	q, ok := r.quotas[tenantID]
+	if !ok {
+		return nil, errors.New("quota not found")
+	}
+	found := *q
+	return &found, nil
+}
+
+// UpdateQuota updates the quota for a tenant.
+func (r *Repository) UpdateQuota(ctx context.Context, quota *tenant.Quota) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("UpdateQuota"); err != nil {
+		return err
+	}
+	saved := *quota
+	r.quotas[quota.TenantID] = &saved
+	return nil
+}
+
+// IncrementUsage increments usage counters for a tenant.
+func (r *Repository) IncrementUsage(ctx context.Context, tenantID uuid.UUID, calls, minutes int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("IncrementUsage"); err != nil {
+		return err
+	}
+	if q, ok := r.quotas[tenantID]; ok {
+		q.UsedCalls += calls
+		q.UsedMinutes += minutes
+	}
+	return nil
+}
+
+// ReserveUsage adds calls and minutes to the tenant's usage if they fit
+// its quota.
+func (r *Repository) ReserveUsage(ctx context.Context, tenantID uuid.UUID, calls, minutes int) (*tenant.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("ReserveUsage"); err != nil {
+		return nil, err
+	}
+	q, ok := r.quotas[tenantID]
+	if !ok {
+		return nil, errors.New("quota not found")
+	}
+	if !q.Fits(calls, minutes) {
+		return q.Reservation(false), nil
+	}
+	q.UsedCalls += calls
+	q.UsedMinutes += minutes
+	return q.Reservation(true), nil
+}
+
+// MarkThresholdReached records that a quota threshold fired in its period.
+func (r *Repository) MarkThresholdReached(ctx context.Context, threshold tenant.QuotaThreshold) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("MarkThresholdReached"); err != nil {
+		return false, err
+	}
+	key := fmt.Sprintf("%s/%s/%s/%d", threshold.TenantID, threshold.Period.Format(time.RFC3339), threshold.Resource, threshold.Percent)
+	if r.reached[key] {
+		return false, nil
+	}
+	r.reached[key] = true
+	return true, nil
+}
+
+// ExistsBySlug checks if a tenant with the given slug exists.
+func (r *Repository) ExistsBySlug(ctx context.Context, slug string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("ExistsBySlug"); err != nil {
+		return false, err
+	}
+	_, err := r.find(func(t *tenant.Tenant) bool { return t.Slug == slug })
+	return err == nil, nil
+}
+
+// TakenSlugs returns which of the slugs are held by a tenant, deleted or not.
+func (r *Repository) TakenSlugs(ctx context.Context, slugs []string) (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("TakenSlugs"); err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool)
+	for _, t := range r.tenants {
+		for _, s := range slugs {
+			if t.Slug == s {
+				taken[s] = true
+			}
+		}
+	}
+	return taken, nil
+}
+
+// ExistsByEmail checks if a tenant with the given email exists.
+func (r *Repository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.call("ExistsByEmail"); err != nil {
+		return false, err
+	}
+	_, err := r.find(func(t *tenant.Tenant) bool { return t.Email == email })
+	return err == nil, nil
+}
+
+// find returns a copy of the first live tenant matching. The caller must hold
+// the lock.
+func (r *Repository) find(match func(*tenant.Tenant) bool) (*tenant.Tenant, error) {
+	for _, t := range r.tenants {
+		if t.DeletedAt == nil && match(t) {
+			return clone(t), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// sortKey returns the ascending order of a sort_by name, matching the
+// columns the Postgres repository allows; anything else sorts by creation.
+func sortKey(sortBy string) func(a, b *tenant.Tenant) bool {
+	switch sortBy {
+	case "name":
+		return func(a, b *tenant.Tenant) bool { return a.Name < b.Name }
+	case "email":
+		return func(a, b *tenant.Tenant) bool { return a.Email < b.Email }
+	case "status":
+		return func(a, b *tenant.Tenant) bool { return a.Status < b.Status }
+	case "updated_at":
+		return func(a, b *tenant.Tenant) bool { return a.UpdatedAt.Before(b.UpdatedAt) }
+	default:
+		return func(a, b *tenant.Tenant) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	}
+}
+
+// clone deep-copies a tenant through its JSON form, as a round trip through
+// the database would.
+func clone(t *tenant.Tenant) *tenant.Tenant {
+	data, err := json.Marshal(t)
+	if err != nil {
+		panic(fmt.Sprintf("tenanttest: failed to copy tenant: %v", err))
+	}
+	var c tenant.Tenant
+	if err := json.Unmarshal(data, &c); err != nil {
+		panic(fmt.Sprintf("tenanttest: failed to copy tenant: %v", err))
+	}
+	return &c
+}
@@ -0,0 +1,230 @@
+// Package seed sets up a demo tenant for local development: the tenant with
+// its quota, billing customer (a Stripe test customer), DID and admin user,
+// through the onboarding saga, and an agent in the agent-orchestrator
+// registry. Every step is idempotent, so seeding again leaves the
+// environment as it is.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OnboardingKey is the idempotency key of the demo onboarding. Repeating it
+// returns the tenant created by the first run.
+const OnboardingKey = "seed-demo-tenant"
+
+// ErrProduction is returned when seeding is attempted against production.
+var ErrProduction = errors.New("refusing to seed a production environment")
+
+// Config configures the seeder.
+type Config struct {
+	Environment          string // ENVIRONMENT of the target platform
+	StripeSecretKey      string // checked to be a test key, if set
+	TenantManagerURL     string
+	AgentOrchestratorURL string
+	// Service token for agent-orchestrator, with the
+	// agent-orchestrator:sessions.write scope
+	AgentOrchestratorToken string
+	Timeout                time.Duration
+}
+
+// Result lists what the demo environment is made of.
+type Result struct {
+	OnboardingID      uuid.UUID `json:"onboarding_id"`
+	TenantID          uuid.UUID `json:"tenant_id"`
+	BillingCustomerID string    `json:"billing_customer_id,omitempty"`
+	PhoneNumber       string    `json:"phone_number,omitempty"`
+	AdminUserID       string    `json:"admin_user_id,omitempty"`
+	AgentID           uuid.UUID `json:"agent_id"`
+	AgentCreated      bool      `json:"agent_created"`
+}
+
+// demoOnboarding is the onboarding request of the demo tenant.
+var demoOnboarding = map[string]any{
+	"tenant": map[string]any{
+		"name":  "Serphona Demo",
+		"email": "demo@serphona.local",
+		"plan":  "professional",
+		"metadata": map[string]string{
+			"industry": "demo",
+		},
+	},
+	"quota": map[string]int{
+		"max_calls_per_month":   1000,
+		"max_minutes_per_month": 5000,
+	},
+	"phone_number": "+5511400000000",
+	"admin": map[string]string{
+		"email": "admin@serphona.local",
+		"name":  "Demo Admin",
+	},
+}
+
+// DemoAgentName names the demo agent in the registry.
+const DemoAgentName = "demo-receptionist"
+
+// demoAgent is the registry request of the demo agent.
+var demoAgent = map[string]any{
+	"name":          DemoAgentName,
+	"description":   "Receptionist of the demo tenant",
+	"model":         "gpt-4o-mini",
+	"system_prompt": "You are the receptionist of Serphona Demo. Greet callers, find out what they need and help them briefly.",
+}
+
+// Seeder creates the demo environment through the services' APIs.
+type Seeder struct {
+	config Config
+	client *http.Client
+}
+
+// New creates a seeder. It fails for a production environment or a live
+// Stripe key.
+func New(config Config) (*Seeder, error) {
+	switch strings.ToLower(config.Environment) {
+	case "production", "prod":
+		return nil, fmt.Errorf("%w: ENVIRONMENT is %s", ErrProduction, config.Environment)
+	}
+	if config.StripeSecretKey != "" && !strings.HasPrefix(config.StripeSecretKey, "sk_test_") {
+		return nil, fmt.Errorf("%w: STRIPE_SECRET_KEY is not a test key", ErrProduction)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+	return &Seeder{config: config, client: &http.Client{Timeout: config.Timeout}}, nil
+}
+
+// Run seeds the demo environment, reusing whatever a previous run created.
+func (s *Seeder) Run(ctx context.Context) (*Result, error) {
+	var onboarded struct {
+		ID        uuid.UUID `json:"id"`
+		Resources struct {
+			TenantID          uuid.UUID `json:"tenant_id"`
+			BillingCustomerID string    `json:"billing_customer_id"`
+			PhoneNumber       string    `json:"phone_number"`
+			AdminUserID       string    `json:"admin_user_id"`
+		} `json:"resources"`
+	}
+	header := http.Header{"Idempotency-Key": {OnboardingKey}}
+	if err := s.do(ctx, http.MethodPost, s.config.TenantManagerURL+"/api/v1/onboarding", header, demoOnboarding, &onboarded); err != nil {
+		return nil, fmt.Errorf("onboard demo tenant: %w", err)
+	}
+
+	result := &Result{
+		OnboardingID:      onboarded.ID,
+		TenantID:          onboarded.Resources.TenantID,
+		BillingCustomerID: onboarded.Resources.BillingCustomerID,
+		PhoneNumber:       onboarded.Resources.PhoneNumber,
+		AdminUserID:       onboarded.Resources.AdminUserID,
+	}
+
+	agentID, created, err := s.ensureAgent(ctx, result.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("create demo agent: %w", err)
+	}
+	result.AgentID, result.AgentCreated = agentID, created
+	return result, nil
+}
+
+// ensureAgent creates the demo agent unless the tenant already has it.
+func (s *Seeder) ensureAgent(ctx context.Context, tenantID uuid.UUID) (uuid.UUID, bool, error) {
+	agentsURL := fmt.Sprintf("%s/api/v1/tenants/%s/agents", s.config.AgentOrchestratorURL, tenantID)
+
+	if id, ok, err := s.findAgent(ctx, agentsURL); err != nil || ok {
+		return id, false, err
+	}
+
+	var created struct {
+		ID uuid.UUID `json:"id"`
+	}
+	err := s.do(ctx, http.MethodPost, agentsURL, s.agentHeader(), demoAgent, &created)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusConflict {
+		// Created concurrently by another run
+		id, _, err := s.findAgent(ctx, agentsURL)
+		return id, false, err
+	}
+	return created.ID, err == nil, err
+}
+
+func (s *Seeder) findAgent(ctx context.Context, agentsURL string) (uuid.UUID, bool, error) {
+	var list struct {
+		Agents []struct {
+			ID   uuid.UUID `json:"id"`
+			Name string    `json:"name"`
+		} `json:"agents"`
+	}
+	if err := s.do(ctx, http.MethodGet, agentsURL, s.agentHeader(), nil, &list); err != nil {
+		return uuid.Nil, false, err
+	}
+	for _, a := range list.Agents {
+		if a.Name == DemoAgentName {
+			return a.ID, true, nil
+		}
+	}
+	return uuid.Nil, false, nil
+}
+
+// agentHeader authenticates requests to agent-orchestrator.
+func (s *Seeder) agentHeader() http.Header {
+	if s.config.AgentOrchestratorToken == "" {
+		return nil
+	}
+	return http.Header{"Authorization": {"Bearer " + s.config.AgentOrchestratorToken}}
+}
+
+// statusError is a response with an unexpected status.
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.code, e.body)
+}
+
+// do sends a request as the platform operator and decodes the JSON response
+// into out.
+func (s *Seeder) do(ctx context.Context, method, url string, header http.Header, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// The identity the gateway forwards for the platform operator
+	req.Header.Set("X-User-ID", "seed")
+	req.Header.Set("X-User-Role", "superadmin")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &statusError{code: resp.StatusCode, body: strings.TrimSpace(string(data))}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
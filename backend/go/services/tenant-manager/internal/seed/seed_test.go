@@ -0,0 +1,136 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testServiceToken is the token the fake agent registry requires.
+const testServiceToken = "service-token"
+
+// platform fakes the onboarding API and the agent registry: an idempotency
+// key onboards once and agent names are unique per tenant.
+type platform struct {
+	mu          sync.Mutex
+	onboardings map[string]map[string]any
+	agents      map[uuid.UUID][]map[string]any
+}
+
+func newPlatform() *platform {
+	return &platform{onboardings: make(map[string]map[string]any), agents: make(map[uuid.UUID][]map[string]any)}
+}
+
+func (p *platform) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if r.Header.Get("X-User-Role") != "superadmin" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if r.URL.Path == "/api/v1/onboarding" {
+		key := r.Header.Get("Idempotency-Key")
+		o, ok := p.onboardings[key]
+		if !ok {
+			o = map[string]any{
+				"id":     uuid.New(),
+				"status": "completed",
+				"resources": map[string]any{
+					"tenant_id":           uuid.New(),
+					"billing_customer_id": "cus_test_" + uuid.NewString()[:8],
+					"phone_number":        "+5511400000000",
+					"admin_user_id":       uuid.NewString(),
+				},
+			}
+			p.onboardings[key] = o
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(o)
+		return
+	}
+
+	// /api/v1/tenants/{id}/agents
+	if r.Header.Get("Authorization") != "Bearer "+testServiceToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	tenantID := uuid.MustParse(parts[3])
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]any{"agents": p.agents[tenantID]})
+	case http.MethodPost:
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, a := range p.agents[tenantID] {
+			if a["name"] == req["name"] {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		}
+		req["id"] = uuid.New()
+		p.agents[tenantID] = append(p.agents[tenantID], req)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(req)
+	}
+}
+
+func TestRun_Idempotent(t *testing.T) {
+	p := newPlatform()
+	server := httptest.NewServer(p)
+	defer server.Close()
+
+	seeder, err := New(Config{
+		Environment:            "development",
+		StripeSecretKey:        "sk_test_123",
+		TenantManagerURL:       server.URL,
+		AgentOrchestratorURL:   server.URL,
+		AgentOrchestratorToken: testServiceToken,
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	first, err := seeder.Run(context.Background())
+	if err != nil {
+		t.Fatalf("first Run failed: %v", err)
+	}
+	if first.TenantID == uuid.Nil || first.AgentID == uuid.Nil || !first.AgentCreated {
+		t.Fatalf("Expected the demo tenant and agent created, got %+v", first)
+	}
+
+	second, err := seeder.Run(context.Background())
+	if err != nil {
+		t.Fatalf("second Run failed: %v", err)
+	}
+	if second.TenantID != first.TenantID || second.AgentID != first.AgentID || second.AgentCreated {
+		t.Errorf("Expected the second run to reuse %+v, got %+v", first, second)
+	}
+	if len(p.onboardings) != 1 || len(p.agents[first.TenantID]) != 1 {
+		t.Errorf("Expected 1 onboarding and 1 agent, got %d and %d", len(p.onboardings), len(p.agents[first.TenantID]))
+	}
+}
+
+func TestNew_RefusesProduction(t *testing.T) {
+	tests := map[string]Config{
+		"production environment": {Environment: "production"},
+		"prod environment":       {Environment: "PROD"},
+		"live stripe key":        {Environment: "development", StripeSecretKey: "sk_live_123"},
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(config); !errors.Is(err, ErrProduction) {
+				t.Errorf("Expected ErrProduction, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+-- =============================================================================
+-- Migration: 000002_create_audit_log
+-- Description: Append-only audit log of privileged actions
+-- =============================================================================
+
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    actor_role VARCHAR(50) NOT NULL DEFAULT '',
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(255) NOT NULL,
+    tenant_id VARCHAR(255) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT 'null',
+    metadata JSONB NOT NULL DEFAULT 'null'
+);
+
+-- Indexes for compliance review filters
+CREATE INDEX idx_audit_log_tenant ON audit_log(tenant_id, occurred_at DESC);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor, occurred_at DESC);
+CREATE INDEX idx_audit_log_occurred_at ON audit_log(occurred_at DESC);
+
+-- =============================================================================
+-- Append-only: reject updates and deletes
+-- =============================================================================
+CREATE OR REPLACE FUNCTION audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION audit_log_append_only();
+
+COMMENT ON TABLE audit_log IS 'Append-only audit trail of privileged actions';
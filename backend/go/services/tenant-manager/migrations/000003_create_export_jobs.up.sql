@@ -0,0 +1,25 @@
+-- =============================================================================
+-- Migration: 000003_create_export_jobs
+-- Description: Data-export (subject-access request) jobs and their progress
+-- =============================================================================
+
+CREATE TABLE export_jobs (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    customer_id VARCHAR(255) NOT NULL DEFAULT '',
+    requested_by VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    sources JSONB NOT NULL DEFAULT '[]',
+    archive_key VARCHAR(500) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT export_jobs_status_check CHECK (status IN ('pending', 'running', 'completed', 'failed'))
+);
+
+CREATE INDEX idx_export_jobs_tenant ON export_jobs(tenant_id, created_at DESC);
+
+COMMENT ON TABLE export_jobs IS 'Asynchronous exports of tenant or customer data across services';
+COMMENT ON COLUMN export_jobs.sources IS 'Collection status of each source service';
@@ -0,0 +1,32 @@
+-- =============================================================================
+-- Migration: 000004_create_customers
+-- Description: End customers of each tenant and the identifiers they contact us by
+-- =============================================================================
+
+CREATE TABLE customers (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    merged_into UUID REFERENCES customers(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_customers_tenant ON customers(tenant_id);
+
+CREATE TABLE customer_identifiers (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- One customer per phone number or email within a tenant
+    PRIMARY KEY (tenant_id, kind, value),
+    CONSTRAINT customer_identifiers_kind_check CHECK (kind IN ('phone', 'email'))
+);
+
+CREATE INDEX idx_customer_identifiers_customer ON customer_identifiers(customer_id);
+
+COMMENT ON TABLE customers IS 'End customers (callers) of each tenant, stable across calls and channels';
+COMMENT ON COLUMN customers.merged_into IS 'Customer this duplicate was merged into';
+COMMENT ON TABLE customer_identifiers IS 'Normalized phone numbers and emails resolving to a customer';
@@ -0,0 +1,25 @@
+-- =============================================================================
+-- Migration: 000005_create_onboardings
+-- Description: Tenant onboarding sagas, their progress and created resources
+-- =============================================================================
+
+CREATE TABLE onboardings (
+    id UUID PRIMARY KEY,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    completed_steps JSONB NOT NULL DEFAULT '[]',
+    resources JSONB NOT NULL DEFAULT '{}',
+    failed_step VARCHAR(50) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT onboardings_status_check CHECK (status IN ('running', 'completed', 'rolled_back', 'failed'))
+);
+
+CREATE INDEX idx_onboardings_status ON onboardings(status) WHERE status <> 'completed';
+
+COMMENT ON TABLE onboardings IS 'Tenant onboarding sagas, keyed by the request idempotency key';
+COMMENT ON COLUMN onboardings.completed_steps IS 'Steps done and not undone; left over on failed sagas for manual cleanup';
+COMMENT ON COLUMN onboardings.resources IS 'IDs of the tenant, billing customer, phone number and admin user created';
@@ -0,0 +1,16 @@
+-- =============================================================================
+-- Migration: 000006_create_tenant_data_keys
+-- Description: Per-tenant data keys encrypting the secrets in tenant settings
+-- =============================================================================
+
+CREATE TABLE tenant_data_keys (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    wrapped_key BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (tenant_id, version)
+);
+
+COMMENT ON TABLE tenant_data_keys IS 'Data keys of each tenant, wrapped by the KMS master key; older versions decrypt secrets sealed before a rotation';
+COMMENT ON COLUMN tenants.settings IS 'Tenant settings; the secret fields are kept encrypted under the "secrets" member';
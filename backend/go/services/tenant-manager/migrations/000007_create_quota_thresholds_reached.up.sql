@@ -0,0 +1,17 @@
+-- =============================================================================
+-- Migration: 000007_create_quota_thresholds_reached
+-- Description: Quota warning thresholds already fired in each usage period
+-- =============================================================================
+
+CREATE TABLE quota_thresholds_reached (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    period_reset_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resource VARCHAR(20) NOT NULL,
+    percent INTEGER NOT NULL,
+    reached_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (tenant_id, period_reset_at, resource, percent)
+);
+
+COMMENT ON TABLE quota_thresholds_reached IS 'One row per quota.threshold_reached event, so each threshold warns once per period';
+COMMENT ON COLUMN quota_thresholds_reached.period_reset_at IS 'reset_at of the quota period the threshold was reached in';
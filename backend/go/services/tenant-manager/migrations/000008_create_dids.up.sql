@@ -0,0 +1,18 @@
+-- =============================================================================
+-- Migration: 000008_create_dids
+-- Description: Inbound phone numbers (DIDs) and the tenant each routes to
+-- =============================================================================
+
+CREATE TABLE dids (
+    number VARCHAR(16) PRIMARY KEY,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT dids_number_e164_check CHECK (number ~ '^\+[1-9][0-9]{1,14}$')
+);
+
+CREATE INDEX idx_dids_tenant ON dids(tenant_id);
+
+COMMENT ON TABLE dids IS 'Inbound phone numbers; a number belongs to at most one tenant';
+COMMENT ON COLUMN dids.number IS 'Phone number in E.164';
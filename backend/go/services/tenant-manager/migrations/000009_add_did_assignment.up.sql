@@ -0,0 +1,13 @@
+-- =============================================================================
+-- Migration: 000009_add_did_assignment
+-- Description: Agent or flow each DID routes to, and a label to tell numbers apart
+-- =============================================================================
+
+ALTER TABLE dids
+    ADD COLUMN agent_id VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN flow_id VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN label VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+COMMENT ON COLUMN dids.agent_id IS 'Agent answering calls to the number; empty to use the tenant routing rules';
+COMMENT ON COLUMN dids.flow_id IS 'Flow the agent runs on calls to the number; empty for the agent default';
@@ -8,6 +8,13 @@ import (
 
 // New creates a new logger with the specified log level and environment.
 func New(logLevel, environment string) (*zap.Logger, error) {
+	logger, _, err := NewWithLevel(logLevel, environment)
+	return logger, err
+}
+
+// NewWithLevel creates a new logger like New and also returns its level,
+// which can be changed while the logger is in use.
+func NewWithLevel(logLevel, environment string) (*zap.Logger, zap.AtomicLevel, error) {
 	var config zap.Config
 
 	if environment == "production" {
@@ -24,5 +31,6 @@ func New(logLevel, environment string) (*zap.Logger, error) {
 	}
 	config.Level = zap.NewAtomicLevelAt(level)
 
-	return config.Build()
+	logger, err := config.Build()
+	return logger, config.Level, err
 }
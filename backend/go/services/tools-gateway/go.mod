@@ -1,11 +1,22 @@
 module github.com/serphona/serphona/backend/go/services/tools-gateway
 
-go 1.21
+go 1.23
 
 require (
 	github.com/gin-gonic/gin v1.9.1
 	github.com/google/uuid v1.4.0
+	github.com/prometheus/client_golang v1.17.0
+	github.com/serphona/serphona/backend/go/libs/platform-core v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-entitlements v0.0.0
+	github.com/serphona/serphona/backend/go/libs/platform-events v0.0.0
 	go.uber.org/zap v1.26.0
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/gorm v1.25.5
 	gorm.io/driver/postgres v1.5.4
 )
+
+replace github.com/serphona/serphona/backend/go/libs/platform-core => ../../libs/platform-core
+
+replace github.com/serphona/serphona/backend/go/libs/platform-entitlements => ../../libs/platform-entitlements
+
+replace github.com/serphona/serphona/backend/go/libs/platform-events => ../../libs/platform-events
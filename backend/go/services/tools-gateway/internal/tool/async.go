@@ -0,0 +1,290 @@
+package tool
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Headers sent with an async call, telling the tool API where to post its
+// result.
+const (
+	HeaderCallbackURL = "X-Callback-URL"
+	HeaderExecutionID = "X-Execution-ID"
+)
+
+// Errors returned for async executions.
+var (
+	ErrAsyncDisabled        = errors.New("async execution is not enabled")
+	ErrInvalidCallbackToken = errors.New("invalid callback token")
+	ErrExecutionCompleted   = errors.New("tool execution already completed")
+)
+
+// AsyncConfig configures async executions.
+type AsyncConfig struct {
+	CallbackBaseURL string        // public URL of the gateway; without it tools can only be polled
+	Timeout         time.Duration // how long an execution may stay pending
+	PollInterval    time.Duration // between requests to a status URL
+}
+
+// DefaultAsyncConfig holds the defaults for zero AsyncConfig fields.
+var DefaultAsyncConfig = AsyncConfig{
+	Timeout:      15 * time.Minute,
+	PollInterval: 5 * time.Second,
+}
+
+// Notifier is told when an async execution finishes.
+type Notifier interface {
+	Notify(ctx context.Context, e *Execution) error
+}
+
+// Callback is the result a tool API posts for an async execution.
+type Callback struct {
+	StatusCode int             `json:"status_code"` // defaults to 200
+	Response   json.RawMessage `json:"response"`
+	Error      string          `json:"error"` // set when the tool failed
+}
+
+// EnableAsync allows async executions. notifier may be nil.
+func (s *Service) EnableAsync(cfg AsyncConfig, notifier Notifier) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultAsyncConfig.Timeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultAsyncConfig.PollInterval
+	}
+	cfg.CallbackBaseURL = strings.TrimSuffix(cfg.CallbackBaseURL, "/")
+	s.async = &cfg
+	s.notifier = notifier
+}
+
+// ExecuteAsync starts a call of a tenant's tool and returns its pending
+// execution without waiting for the result. The tool API either answers
+// right away, answers 202 with a Location to poll, or answers 202 and later
+// posts the result to the callback URL it was sent. The execution is
+// completed, and the notifier told, when the result arrives or the async
+// timeout passes.
+func (s *Service) ExecuteAsync(ctx context.Context, tenantID, id, agentID string, version int, input json.RawMessage) (*Execution, error) {
+	if s.async == nil {
+		return nil, ErrAsyncDisabled
+	}
+	t, err := s.Get(ctx, tenantID, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	e := s.newExecution(t, agentID, "", input)
+	e.Async = true
+	if err := s.runnable(t); err != nil {
+		s.fail(ctx, e, err)
+		return nil, err
+	}
+	if int64(len(input)) > s.executor.MaxInputBytes() {
+		s.fail(ctx, e, ErrInputTooLarge)
+		return nil, ErrInputTooLarge
+	}
+
+	token, err := newCallbackToken()
+	if err != nil {
+		return nil, err
+	}
+	e.Outcome = OutcomePending
+	e.CallbackToken = token
+	if err := s.repo.RecordExecution(ctx, e); err != nil {
+		return nil, fmt.Errorf("failed to record execution: %w", err)
+	}
+
+	go s.runAsync(context.WithoutCancel(ctx), t, *e, input)
+	return e, nil
+}
+
+// Callback completes a pending async execution with the result its tool API
+// posted. token must be the one sent in the execution's callback URL.
+func (s *Service) Callback(ctx context.Context, id, token string, cb Callback) (*Execution, error) {
+	e, err := s.repo.FindExecution(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !e.Async || e.CallbackToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(e.CallbackToken)) != 1 {
+		return nil, ErrInvalidCallbackToken
+	}
+	if e.Outcome != OutcomePending {
+		return nil, ErrExecutionCompleted
+	}
+	t, err := s.Get(ctx, e.TenantID, e.ToolID, e.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if cb.Error != "" {
+		return s.complete(ctx, t, *e, nil, fmt.Errorf("tool reported an error: %s", cb.Error))
+	}
+	if cb.StatusCode == 0 {
+		cb.StatusCode = http.StatusOK
+	}
+	return s.complete(ctx, t, *e, &Result{StatusCode: cb.StatusCode, Response: asJSON(cb.Response)}, nil)
+}
+
+// Execution returns a tenant's execution, to check on an async one.
+func (s *Service) Execution(ctx context.Context, tenantID, id string) (*Execution, error) {
+	return s.repo.GetExecution(ctx, tenantID, id)
+}
+
+// runAsync calls the tool API of a pending execution and follows up on a
+// 202 by polling its Location or waiting for the callback.
+func (s *Service) runAsync(ctx context.Context, t *Tool, e Execution, input json.RawMessage) {
+	deadline := e.CreatedAt.Add(s.async.Timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	// The response is mapped once the final result arrives, not on the 202
+	call := *t
+	call.ResponseMapping = nil
+	call.Headers = make(Headers, len(t.Headers)+2)
+	for k, v := range t.Headers {
+		call.Headers[k] = v
+	}
+	call.Headers[HeaderExecutionID] = e.ID
+	if s.async.CallbackBaseURL != "" {
+		call.Headers[HeaderCallbackURL] = s.callbackURL(e)
+	}
+
+	result, err := s.executor.Execute(ctx, &call, input)
+	switch {
+	case err != nil || result.StatusCode != http.StatusAccepted:
+		s.completeAsync(ctx, t, e, result, err)
+	case result.location != "":
+		result, err = s.poll(ctx, t, result.location)
+		s.completeAsync(ctx, t, e, result, err)
+	default:
+		// The result comes through the callback; give up on it at the deadline
+		time.AfterFunc(time.Until(deadline), func() {
+			s.completeAsync(context.Background(), t, e, nil, ErrTimeout)
+		})
+	}
+}
+
+// poll requests a status URL until it stops answering 202.
+func (s *Service) poll(ctx context.Context, t *Tool, location string) (*Result, error) {
+	base, err := url.Parse(t.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tool url: %w", err)
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status url %q: %w", location, err)
+	}
+	status := base.ResolveReference(ref)
+	if status.Scheme != "http" && status.Scheme != "https" {
+		return nil, fmt.Errorf("invalid status url %q", location)
+	}
+
+	probe := &Tool{
+		ID:       t.ID,
+		TenantID: t.TenantID,
+		Definition: Definition{
+			Method:           http.MethodGet,
+			URL:              status.String(),
+			TimeoutMs:        t.TimeoutMs,
+			MaxResponseBytes: t.MaxResponseBytes,
+		},
+	}
+	// The tool's credentials only go to the host they were configured for
+	if strings.EqualFold(status.Host, base.Host) {
+		probe.Headers = t.Headers
+	}
+
+	ticker := time.NewTicker(s.async.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ErrTimeout
+		case <-ticker.C:
+		}
+		result, err := s.executor.Execute(ctx, probe, nil)
+		if err != nil {
+			return nil, err
+		}
+		if result.StatusCode != http.StatusAccepted {
+			return result, nil
+		}
+	}
+}
+
+// completeAsync completes an execution from the background, where there is
+// no caller to return errors to.
+func (s *Service) completeAsync(ctx context.Context, t *Tool, e Execution, result *Result, err error) {
+	if _, err := s.complete(ctx, t, e, result, err); err != nil && !errors.Is(err, ErrExecutionCompleted) {
+		log.Printf("Failed to complete execution %s of tool %s: %v", e.ID, e.ToolID, err)
+	}
+}
+
+// complete stores the result of a pending execution and notifies about it.
+// It returns ErrExecutionCompleted when a callback, poll or expiry got there
+// first.
+func (s *Service) complete(ctx context.Context, t *Tool, e Execution, result *Result, err error) (*Execution, error) {
+	ctx = context.WithoutCancel(ctx)
+	if err == nil {
+		var response json.RawMessage
+		if response, err = mapResponse(t, result.StatusCode, result.Response); err == nil {
+			e.Response = Schema(response)
+		}
+		e.StatusCode = result.StatusCode
+	}
+
+	now := s.now().UTC()
+	e.Outcome = outcomeOf(err)
+	if err != nil {
+		e.Error = err.Error()
+	}
+	e.LatencyMs = now.Sub(e.CreatedAt).Milliseconds()
+	e.CompletedAt = &now
+
+	done, saveErr := s.repo.CompleteExecution(ctx, &e)
+	if saveErr != nil {
+		return nil, fmt.Errorf("failed to record execution result: %w", saveErr)
+	}
+	if !done {
+		return nil, ErrExecutionCompleted
+	}
+	if s.notifier != nil {
+		if err := s.notifier.Notify(ctx, &e); err != nil {
+			log.Printf("Failed to notify completion of execution %s: %v", e.ID, err)
+		}
+	}
+	return &e, nil
+}
+
+// fail records an execution that was rejected before the call.
+func (s *Service) fail(ctx context.Context, e *Execution, err error) {
+	e.Outcome = outcomeOf(err)
+	e.Error = err.Error()
+	if auditErr := s.repo.RecordExecution(context.WithoutCancel(ctx), e); auditErr != nil {
+		log.Printf("Failed to record execution %s of tool %s: %v", e.ID, e.ToolID, auditErr)
+	}
+}
+
+// callbackURL is where the tool API of e posts its result.
+func (s *Service) callbackURL(e Execution) string {
+	return s.async.CallbackBaseURL + "/api/v1/executions/" + url.PathEscape(e.ID) +
+		"/callback?token=" + url.QueryEscape(e.CallbackToken)
+}
+
+// newCallbackToken returns a random token authenticating a callback.
+func newCallbackToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate callback token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,181 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/platformeventstest"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+)
+
+// newAsyncService returns a Service with async executions publishing to a
+// fake publisher.
+func newAsyncService(repo Repository) (*Service, *platformeventstest.FakePublisher) {
+	publisher := platformeventstest.NewFakePublisher()
+	s := NewService(repo, NewExecutor(nil, Limits{}))
+	s.EnableAsync(AsyncConfig{
+		CallbackBaseURL: "https://gateway.example.com/",
+		Timeout:         time.Minute,
+		PollInterval:    10 * time.Millisecond,
+	}, NewEventNotifier(publisher, "tools-gateway"))
+	return s, publisher
+}
+
+// waitForOutcome polls the execution until it leaves the pending outcome.
+func waitForOutcome(t *testing.T, repo *memRepository, id string) *Execution {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		e, err := repo.FindExecution(context.Background(), id)
+		if err != nil {
+			t.Fatalf("FindExecution failed: %v", err)
+		}
+		if e.Outcome != OutcomePending {
+			return e
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("execution %s still pending", id)
+	return nil
+}
+
+func TestExecuteAsync_CallbackCompletesExecution(t *testing.T) {
+	callbacks := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callbacks <- r.Header.Get(HeaderCallbackURL)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	repo := newMemRepository()
+	s, publisher := newAsyncService(repo)
+	tool := createStubTool(t, s, srv.URL)
+
+	e, err := s.ExecuteAsync(context.Background(), "tenant-1", tool.ID, "agent-1", 0, json.RawMessage(`{"q":"hi"}`))
+	if err != nil {
+		t.Fatalf("ExecuteAsync failed: %v", err)
+	}
+	if e.ID == "" || e.Outcome != OutcomePending {
+		t.Fatalf("expected a pending execution, got %+v", e)
+	}
+
+	var callback *url.URL
+	select {
+	case raw := <-callbacks:
+		if callback, err = url.Parse(raw); err != nil {
+			t.Fatalf("invalid callback url %q: %v", raw, err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool API was not called")
+	}
+	if want := "/api/v1/executions/" + e.ID + "/callback"; callback.Host != "gateway.example.com" || callback.Path != want {
+		t.Fatalf("expected callback to gateway.example.com%s, got %s", want, callback)
+	}
+
+	if _, err := s.Callback(context.Background(), e.ID, "wrong", Callback{}); !errors.Is(err, ErrInvalidCallbackToken) {
+		t.Fatalf("expected ErrInvalidCallbackToken, got %v", err)
+	}
+	publisher.AssertNotPublished(t, topics.ToolCompleted)
+
+	done, err := s.Callback(context.Background(), e.ID, callback.Query().Get("token"), Callback{Response: json.RawMessage(`{"answer":42}`)})
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	if done.Outcome != OutcomeSuccess || done.StatusCode != http.StatusOK || done.CompletedAt == nil {
+		t.Fatalf("expected a completed execution, got %+v", done)
+	}
+
+	stored, err := s.Execution(context.Background(), "tenant-1", e.ID)
+	if err != nil {
+		t.Fatalf("Execution failed: %v", err)
+	}
+	if stored.Outcome != OutcomeSuccess || string(stored.Response) != `{"answer":42}` {
+		t.Fatalf("expected the callback result stored, got %+v", stored)
+	}
+
+	publisher.AssertPublished(t, topics.ToolCompleted, map[string]any{
+		"tool_id":   tool.ID,
+		"tenant_id": "tenant-1",
+		"agent_id":  "agent-1",
+		"result": map[string]any{
+			"execution_id": e.ID,
+			"version":      1,
+			"outcome":      OutcomeSuccess,
+			"status_code":  200,
+			"response":     map[string]any{"answer": 42},
+		},
+	})
+
+	if _, err := s.Callback(context.Background(), e.ID, callback.Query().Get("token"), Callback{}); !errors.Is(err, ErrExecutionCompleted) {
+		t.Fatalf("expected ErrExecutionCompleted for a second callback, got %v", err)
+	}
+	publisher.AssertPublishedCount(t, topics.ToolCompleted, 1)
+}
+
+func TestExecuteAsync_PollsStatusURL(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/run", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/status/1")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/status/1", func(w http.ResponseWriter, r *http.Request) {
+		if polls++; polls < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Write([]byte(`{"done":true}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	repo := newMemRepository()
+	s, publisher := newAsyncService(repo)
+	tool := createStubTool(t, s, srv.URL+"/run")
+
+	e, err := s.ExecuteAsync(context.Background(), "tenant-1", tool.ID, "", 0, nil)
+	if err != nil {
+		t.Fatalf("ExecuteAsync failed: %v", err)
+	}
+
+	done := waitForOutcome(t, repo, e.ID)
+	if done.Outcome != OutcomeSuccess || string(done.Response) != `{"done":true}` {
+		t.Fatalf("expected the polled result stored, got %+v", done)
+	}
+	publisher.AssertPublished(t, topics.ToolCompleted, map[string]any{"tool_id": tool.ID})
+}
+
+func TestExecuteAsync_ImmediateResponseCompletes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	repo := newMemRepository()
+	s, _ := newAsyncService(repo)
+	tool := createStubTool(t, s, srv.URL)
+
+	e, err := s.ExecuteAsync(context.Background(), "tenant-1", tool.ID, "", 0, nil)
+	if err != nil {
+		t.Fatalf("ExecuteAsync failed: %v", err)
+	}
+
+	done := waitForOutcome(t, repo, e.ID)
+	if done.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected the upstream status stored, got %+v", done)
+	}
+}
+
+func TestExecuteAsync_Disabled(t *testing.T) {
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+	if _, err := s.ExecuteAsync(context.Background(), "tenant-1", "tool-1", "", 0, nil); !errors.Is(err, ErrAsyncDisabled) {
+		t.Fatalf("expected ErrAsyncDisabled, got %v", err)
+	}
+}
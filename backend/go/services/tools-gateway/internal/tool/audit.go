@@ -0,0 +1,180 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Execution outcomes recorded in the audit log.
+const (
+	OutcomeSuccess          = "success"
+	OutcomeError            = "error"
+	OutcomeTimeout          = "tool_timeout"
+	OutcomeResponseTooLarge = "response_too_large"
+	OutcomeInputTooLarge    = "input_too_large"
+	OutcomeVersionRetired   = "version_retired"
+	OutcomeInvalidOutput    = "invalid_output"
+	OutcomePending          = "pending" // async execution awaiting its result
+)
+
+// redacted replaces secret values in audit output.
+const redacted = "[REDACTED]"
+
+// secretNames are substrings of header, query parameter and input field names
+// whose values are never shown in audit output.
+var secretNames = []string{"authorization", "cookie", "token", "secret", "password", "api-key", "api_key", "apikey", "signature"}
+
+// ErrExecutionNotFound is returned for an unknown execution.
+var ErrExecutionNotFound = errors.New("tool execution not found")
+
+// Execution is the audit record of one tool call.
+type Execution struct {
+	ID         string    `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID   string    `gorm:"not null;index:idx_tool_executions_tool,priority:1" json:"tenant_id"`
+	ToolID     string    `gorm:"type:uuid;not null;index:idx_tool_executions_tool,priority:2" json:"tool_id"`
+	Version    int       `gorm:"not null" json:"version"`
+	AgentID    string    `gorm:"not null;default:''" json:"agent_id,omitempty"`
+	ReplayOf   string    `gorm:"not null;default:''" json:"replay_of,omitempty"` // execution this one replayed
+	Method     string    `gorm:"not null" json:"method"`
+	URL        string    `gorm:"not null" json:"url"`                 // stored redacted
+	Headers    Headers   `gorm:"type:jsonb" json:"headers,omitempty"` // stored redacted
+	Input      Schema    `gorm:"type:jsonb" json:"input,omitempty"`   // kept for replay; redacted on output
+	StatusCode int       `gorm:"not null;default:0" json:"status_code,omitempty"`
+	LatencyMs  int64     `gorm:"not null;default:0" json:"latency_ms"`
+	Outcome    string    `gorm:"not null;index" json:"outcome"`
+	Error      string    `gorm:"not null;default:''" json:"error,omitempty"`
+	CreatedAt  time.Time `gorm:"index:idx_tool_executions_tool,priority:3" json:"created_at"`
+
+	// Async executions keep their result, as the caller fetches it later
+	Async         bool       `gorm:"not null;default:false" json:"async,omitempty"`
+	Response      Schema     `gorm:"type:jsonb" json:"response,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CallbackToken string     `gorm:"not null;default:''" json:"-"`
+}
+
+// TableName specifies the table name
+func (Execution) TableName() string {
+	return "tool_executions"
+}
+
+// Redacted returns a copy of e safe to show: secret input fields are masked.
+// Headers and URL are redacted when the execution is recorded.
+func (e Execution) Redacted() Execution {
+	e.Input = redactJSON(e.Input)
+	return e
+}
+
+// ExecutionFilter narrows an execution listing. Zero fields match all.
+type ExecutionFilter struct {
+	Outcome string
+	AgentID string
+	Version int
+	From    time.Time
+	To      time.Time
+	Limit   int
+}
+
+// MaxExecutionsLimit caps an execution listing.
+const MaxExecutionsLimit = 500
+
+// outcomeOf maps an execution error to its recorded outcome.
+func outcomeOf(err error) string {
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.Is(err, ErrTimeout):
+		return OutcomeTimeout
+	case errors.Is(err, ErrResponseTooLarge):
+		return OutcomeResponseTooLarge
+	case errors.Is(err, ErrInputTooLarge):
+		return OutcomeInputTooLarge
+	case errors.Is(err, ErrVersionRetired):
+		return OutcomeVersionRetired
+	case errors.Is(err, ErrInvalidOutput):
+		return OutcomeInvalidOutput
+	default:
+		return OutcomeError
+	}
+}
+
+// isSecret reports whether a header, parameter or field name holds a secret.
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, secret := range secretNames {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders masks secret header values.
+func redactHeaders(h Headers) Headers {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(Headers, len(h))
+	for k, v := range h {
+		if isSecret(k) {
+			v = redacted
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactURL masks user info and secret query parameters.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+	q := u.Query()
+	for k := range q {
+		if isSecret(k) {
+			q.Set(k, redacted)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// redactJSON masks the values of secret object fields at any depth.
+func redactJSON(data Schema) Schema {
+	if len(data) == 0 {
+		return data
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			if isSecret(k) {
+				v[k] = redacted
+			} else {
+				v[k] = redactValue(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return v
+}
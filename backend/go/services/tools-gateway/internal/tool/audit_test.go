@@ -0,0 +1,108 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExecute_RecordsRedactedAudit(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	repo := newMemRepository()
+	s := NewService(repo, NewExecutor(nil, Limits{}))
+	tool := &Tool{TenantID: "tenant-1", Name: "crm", Definition: Definition{
+		Method:  http.MethodPost,
+		URL:     srv.URL + "/lookup?api_key=k3y&region=br",
+		Headers: Headers{"Authorization": "Bearer s3cr3t", "Accept": "application/json"},
+	}}
+	if err := s.Create(ctx, tool); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	result, err := s.Execute(ctx, "tenant-1", tool.ID, "agent-7", 0, []byte(`{"customer":"42","auth":{"password":"hunter2"}}`))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	executions, err := s.Executions(ctx, "tenant-1", tool.ID, ExecutionFilter{AgentID: "agent-7"})
+	if err != nil {
+		t.Fatalf("Executions failed: %v", err)
+	}
+	if len(executions) != 1 {
+		t.Fatalf("Expected 1 execution, got %d", len(executions))
+	}
+	e := executions[0].Redacted()
+	if e.ID != result.ExecutionID || e.Outcome != OutcomeSuccess || e.StatusCode != http.StatusOK || e.Version != 1 {
+		t.Errorf("Unexpected audit record %+v", e)
+	}
+
+	for _, leak := range []string{"s3cr3t", "k3y", "hunter2"} {
+		if strings.Contains(e.URL, leak) || strings.Contains(e.Headers["Authorization"], leak) || strings.Contains(string(e.Input), leak) {
+			t.Errorf("Secret %q leaked into the audit record %+v", leak, e)
+		}
+	}
+	if e.Headers["Accept"] != "application/json" || !strings.Contains(e.URL, "region=br") || !strings.Contains(string(e.Input), `"customer":"42"`) {
+		t.Errorf("Expected non-secret values to be kept, got %+v", e)
+	}
+
+	if failed, _ := s.Executions(ctx, "tenant-1", tool.ID, ExecutionFilter{Outcome: OutcomeTimeout}); len(failed) != 0 {
+		t.Errorf("Expected the outcome filter to exclude the success, got %d", len(failed))
+	}
+}
+
+func TestReplay_UsesCurrentToolConfig(t *testing.T) {
+	ctx := context.Background()
+	var inputs []string
+	v2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		inputs = append(inputs, string(body))
+		w.Write([]byte(`{"version":2}`))
+	}))
+	defer v2.Close()
+
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+	tool := createStubTool(t, s, "http://127.0.0.1:1")
+
+	// The original call fails against version 1
+	if _, err := s.Execute(ctx, "tenant-1", tool.ID, "agent-7", 0, []byte(`{"q":1}`)); err == nil {
+		t.Fatal("Expected the call to the closed port to fail")
+	}
+	original, _ := s.Executions(ctx, "tenant-1", tool.ID, ExecutionFilter{})
+	if len(original) != 1 || original[0].Outcome != OutcomeError {
+		t.Fatalf("Expected a failed execution, got %+v", original)
+	}
+
+	updateStubTool(t, s, tool, v2.URL)
+	result, err := s.Replay(ctx, "tenant-1", original[0].ID)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if result.Version != 2 || string(result.Response) != `{"version":2}` {
+		t.Errorf("Expected the replay to run version 2, got %d %s", result.Version, result.Response)
+	}
+	if len(inputs) != 1 || inputs[0] != `{"q":1}` {
+		t.Errorf("Expected the original input to be replayed, got %v", inputs)
+	}
+
+	executions, _ := s.Executions(ctx, "tenant-1", tool.ID, ExecutionFilter{})
+	if len(executions) != 2 || executions[0].ReplayOf != original[0].ID || executions[0].AgentID != "agent-7" {
+		t.Errorf("Expected the replay to be recorded, got %+v", executions)
+	}
+
+	if _, err := s.Replay(ctx, "tenant-2", original[0].ID); !errors.Is(err, ErrExecutionNotFound) {
+		t.Errorf("Expected another tenant's replay to fail, got %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCaptureNotFound is returned when a tenant has no capture.
+var ErrCaptureNotFound = errors.New("no traffic capture for tenant")
+
+// CaptureConfig bounds traffic captures.
+type CaptureConfig struct {
+	MaxEntries   int           // most calls recorded by one capture
+	MaxDuration  time.Duration // longest a capture may record
+	Retention    time.Duration // how long a finished capture stays retrievable
+	MaxBodyBytes int           // bodies are truncated past this size
+}
+
+// DefaultCaptureConfig is used for capture settings left at zero.
+var DefaultCaptureConfig = CaptureConfig{
+	MaxEntries:   100,
+	MaxDuration:  time.Hour,
+	Retention:    24 * time.Hour,
+	MaxBodyBytes: 16 << 10,
+}
+
+// CaptureSession is a tenant's capture: the tool calls made while it
+// recorded, redacted.
+type CaptureSession struct {
+	TenantID   string         `json:"tenant_id"`
+	StartedAt  time.Time      `json:"started_at"`
+	ExpiresAt  time.Time      `json:"expires_at"` // recording stops here
+	MaxEntries int            `json:"max_entries"`
+	Dropped    int            `json:"dropped"` // calls not recorded once full
+	Entries    []CaptureEntry `json:"entries"`
+}
+
+// CaptureEntry is one recorded tool call, in the spirit of a HAR entry.
+type CaptureEntry struct {
+	StartedAt   time.Time         `json:"started_at"`
+	TimeMs      int64             `json:"time_ms"`
+	ToolID      string            `json:"tool_id"`
+	ToolVersion int               `json:"tool_version"`
+	Request     CapturedRequest   `json:"request"`
+	Response    *CapturedResponse `json:"response,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// CapturedRequest is the request of a recorded call.
+type CapturedRequest struct {
+	Method        string  `json:"method"`
+	URL           string  `json:"url"`
+	Headers       Headers `json:"headers,omitempty"`
+	Body          string  `json:"body,omitempty"`
+	BodyTruncated bool    `json:"body_truncated,omitempty"`
+}
+
+// CapturedResponse is the response of a recorded call.
+type CapturedResponse struct {
+	StatusCode    int     `json:"status_code"`
+	Headers       Headers `json:"headers,omitempty"`
+	Body          string  `json:"body,omitempty"`
+	BodyTruncated bool    `json:"body_truncated,omitempty"`
+}
+
+// Capture records the wire traffic of tool calls for tenants that started a
+// capture, to debug flaky integrations. Secrets in URLs, headers and JSON
+// bodies are redacted before anything is kept. Captures stop recording at
+// their expiry or once full, and are discarded after the retention period.
+// It is safe for concurrent use.
+type Capture struct {
+	config CaptureConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*CaptureSession
+}
+
+// NewCapture creates a Capture. Zero settings take their DefaultCaptureConfig
+// value.
+func NewCapture(config CaptureConfig) *Capture {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultCaptureConfig.MaxEntries
+	}
+	if config.MaxDuration <= 0 {
+		config.MaxDuration = DefaultCaptureConfig.MaxDuration
+	}
+	if config.Retention <= 0 {
+		config.Retention = DefaultCaptureConfig.Retention
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = DefaultCaptureConfig.MaxBodyBytes
+	}
+	return &Capture{config: config, now: time.Now, sessions: make(map[string]*CaptureSession)}
+}
+
+// Start begins a capture for a tenant, replacing any previous one. A zero
+// duration or maxEntries takes the configured maximum.
+func (c *Capture) Start(tenantID string, duration time.Duration, maxEntries int) (*CaptureSession, error) {
+	if duration < 0 || duration > c.config.MaxDuration {
+		return nil, &ValidationError{Message: fmt.Sprintf("duration must be at most %s", c.config.MaxDuration)}
+	}
+	if maxEntries < 0 || maxEntries > c.config.MaxEntries {
+		return nil, &ValidationError{Message: fmt.Sprintf("max_entries must be at most %d", c.config.MaxEntries)}
+	}
+	if duration == 0 {
+		duration = c.config.MaxDuration
+	}
+	if maxEntries == 0 {
+		maxEntries = c.config.MaxEntries
+	}
+
+	now := c.now().UTC()
+	s := &CaptureSession{
+		TenantID:   tenantID,
+		StartedAt:  now,
+		ExpiresAt:  now.Add(duration),
+		MaxEntries: maxEntries,
+		Entries:    []CaptureEntry{},
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	c.sessions[tenantID] = s
+	return s.copy(), nil
+}
+
+// Get returns a tenant's capture.
+func (c *Capture) Get(tenantID string) (*CaptureSession, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune()
+	s, ok := c.sessions[tenantID]
+	if !ok {
+		return nil, ErrCaptureNotFound
+	}
+	return s.copy(), nil
+}
+
+// Clear discards a tenant's capture, stopping it if still recording.
+func (c *Capture) Clear(tenantID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.sessions[tenantID]; !ok {
+		return ErrCaptureNotFound
+	}
+	delete(c.sessions, tenantID)
+	return nil
+}
+
+// recording returns a tenant's capture while it records.
+func (c *Capture) recording(tenantID string) *CaptureSession {
+	s, ok := c.sessions[tenantID]
+	if !ok || !c.now().Before(s.ExpiresAt) {
+		return nil
+	}
+	return s
+}
+
+// active reports whether calls of a tenant are being recorded.
+func (c *Capture) active(tenantID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recording(tenantID) != nil
+}
+
+// record adds the call of t sent as req to the tenant's capture. resp and
+// body are nil when no response arrived.
+func (c *Capture) record(t *Tool, req *http.Request, start time.Time, resp *http.Response, body []byte, callErr error) {
+	entry := CaptureEntry{
+		StartedAt:   start.UTC(),
+		TimeMs:      time.Since(start).Milliseconds(),
+		ToolID:      t.ID,
+		ToolVersion: t.Version,
+		Request: CapturedRequest{
+			Method:  req.Method,
+			URL:     redactURL(req.URL.String()),
+			Headers: redactHeaders(flattenHeader(req.Header)),
+		},
+	}
+	entry.Request.Body, entry.Request.BodyTruncated = c.captureBody(requestBody(req))
+	if resp != nil {
+		entry.Response = &CapturedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(flattenHeader(resp.Header)),
+		}
+		entry.Response.Body, entry.Response.BodyTruncated = c.captureBody(body)
+	}
+	if callErr != nil {
+		entry.Error = callErr.Error()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.recording(t.TenantID)
+	if s == nil {
+		return
+	}
+	if len(s.Entries) >= s.MaxEntries {
+		s.Dropped++
+		return
+	}
+	s.Entries = append(s.Entries, entry)
+}
+
+// captureBody redacts secret JSON fields of a body and truncates it.
+func (c *Capture) captureBody(body []byte) (string, bool) {
+	if len(body) == 0 {
+		return "", false
+	}
+	body = redactJSON(Schema(body))
+	if len(body) > c.config.MaxBodyBytes {
+		return string(body[:c.config.MaxBodyBytes]), true
+	}
+	return string(body), false
+}
+
+// prune discards captures past their retention. Callers hold c.mu.
+func (c *Capture) prune() {
+	now := c.now()
+	for tenantID, s := range c.sessions {
+		if now.After(s.ExpiresAt.Add(c.config.Retention)) {
+			delete(c.sessions, tenantID)
+		}
+	}
+}
+
+// copy returns a snapshot of s that later records don't change.
+func (s *CaptureSession) copy() *CaptureSession {
+	out := *s
+	out.Entries = append([]CaptureEntry{}, s.Entries...)
+	return &out
+}
+
+// requestBody returns the body req was sent with.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	r, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+	body, _ := io.ReadAll(r)
+	return body
+}
+
+// flattenHeader joins repeated header values.
+func flattenHeader(h http.Header) Headers {
+	out := make(Headers, len(h))
+	for k, values := range h {
+		out[k] = strings.Join(values, ", ")
+	}
+	return out
+}
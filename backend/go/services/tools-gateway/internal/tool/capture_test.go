@@ -0,0 +1,150 @@
+package tool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCaptureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cr3t"})
+		w.Write([]byte(`{"ok":true,"access_token":"tok-123"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSecretTool(url string) *Tool {
+	tool := newStubTool(url + "/lookup?api_key=k-123&q=pizza")
+	tool.Headers = Headers{"Authorization": "Bearer abc", "X-Trace": "t-1"}
+	return tool
+}
+
+func TestCapture_OffByDefault(t *testing.T) {
+	srv := newCaptureServer(t)
+
+	// Without EnableCapture nothing is recorded, even for a started capture
+	capture := NewCapture(CaptureConfig{})
+	e := NewExecutor(srv.Client(), Limits{})
+	if _, err := capture.Start("tenant-1", 0, 0); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if _, err := e.Execute(context.Background(), newSecretTool(srv.URL), []byte(`{}`)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if s, _ := capture.Get("tenant-1"); len(s.Entries) != 0 {
+		t.Errorf("Expected no entries without capture enabled, got %d", len(s.Entries))
+	}
+
+	// Enabled, a tenant without a started capture is not recorded
+	capture = NewCapture(CaptureConfig{})
+	e.EnableCapture(capture)
+	if _, err := e.Execute(context.Background(), newSecretTool(srv.URL), []byte(`{}`)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if _, err := capture.Get("tenant-1"); err != ErrCaptureNotFound {
+		t.Errorf("Expected ErrCaptureNotFound for a tenant that never started a capture, got %v", err)
+	}
+}
+
+func TestCapture_RecordsRedactedTraffic(t *testing.T) {
+	srv := newCaptureServer(t)
+	capture := NewCapture(CaptureConfig{})
+	e := NewExecutor(srv.Client(), Limits{})
+	e.EnableCapture(capture)
+
+	if _, err := capture.Start("tenant-1", 10*time.Minute, 0); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if _, err := e.Execute(context.Background(), newSecretTool(srv.URL), []byte(`{"password":"hunter2","q":"pizza"}`)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	s, err := capture.Get("tenant-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(s.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(s.Entries))
+	}
+	entry := s.Entries[0]
+
+	if got := entry.Request.Headers["Authorization"]; got != redacted {
+		t.Errorf("Expected the Authorization header redacted, got %q", got)
+	}
+	if got := entry.Request.Headers["X-Trace"]; got != "t-1" {
+		t.Errorf("Expected other headers kept, got %q", got)
+	}
+	if strings.Contains(entry.Request.URL, "k-123") || !strings.Contains(entry.Request.URL, "q=pizza") {
+		t.Errorf("Expected only the api_key redacted in the URL, got %q", entry.Request.URL)
+	}
+	if strings.Contains(entry.Request.Body, "hunter2") || !strings.Contains(entry.Request.Body, "pizza") {
+		t.Errorf("Expected secret body fields redacted, got %q", entry.Request.Body)
+	}
+
+	if entry.Response == nil || entry.Response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the response recorded, got %+v", entry.Response)
+	}
+	if got := entry.Response.Headers["Set-Cookie"]; got != redacted {
+		t.Errorf("Expected the Set-Cookie header redacted, got %q", got)
+	}
+	if strings.Contains(entry.Response.Body, "tok-123") {
+		t.Errorf("Expected the token redacted from the response body, got %q", entry.Response.Body)
+	}
+}
+
+func TestCapture_StopsWhenFullOrExpired(t *testing.T) {
+	srv := newCaptureServer(t)
+	capture := NewCapture(CaptureConfig{Retention: time.Hour})
+	now := time.Now()
+	capture.now = func() time.Time { return now }
+	e := NewExecutor(srv.Client(), Limits{})
+	e.EnableCapture(capture)
+
+	if _, err := capture.Start("tenant-1", time.Minute, 2); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := e.Execute(context.Background(), newStubTool(srv.URL), nil); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+	}
+	s, _ := capture.Get("tenant-1")
+	if len(s.Entries) != 2 || s.Dropped != 1 {
+		t.Errorf("Expected 2 entries and 1 dropped, got %d and %d", len(s.Entries), s.Dropped)
+	}
+
+	// Past its window the capture stops recording but stays retrievable
+	now = now.Add(2 * time.Minute)
+	capture.Clear("tenant-1")
+	capture.Start("tenant-1", time.Minute, 0)
+	now = now.Add(2 * time.Minute)
+	if _, err := e.Execute(context.Background(), newStubTool(srv.URL), nil); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if s, err := capture.Get("tenant-1"); err != nil || len(s.Entries) != 0 {
+		t.Errorf("Expected an expired capture to record nothing, got %v", err)
+	}
+
+	// Past its retention it is discarded
+	now = now.Add(2 * time.Hour)
+	if _, err := capture.Get("tenant-1"); err != ErrCaptureNotFound {
+		t.Errorf("Expected the capture discarded after retention, got %v", err)
+	}
+}
+
+func TestCapture_StartRejectsLimitsAboveConfig(t *testing.T) {
+	capture := NewCapture(CaptureConfig{MaxEntries: 10, MaxDuration: time.Hour})
+
+	if _, err := capture.Start("tenant-1", 2*time.Hour, 0); err == nil {
+		t.Error("Expected an error for a duration above the maximum")
+	}
+	if _, err := capture.Start("tenant-1", 0, 11); err == nil {
+		t.Error("Expected an error for max_entries above the maximum")
+	}
+}
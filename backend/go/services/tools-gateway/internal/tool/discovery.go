@@ -0,0 +1,127 @@
+package tool
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrAgentAccessNotFound is returned for an agent without a tool allowlist.
+var ErrAgentAccessNotFound = errors.New("agent has no tool allowlist")
+
+// Tags are free-form tool labels, stored as a JSONB array.
+type Tags []string
+
+// Value implements driver.Valuer.
+func (t Tags) Value() (driver.Value, error) {
+	if t == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan implements sql.Scanner.
+func (t *Tags) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil || data == nil {
+		*t = nil
+		return err
+	}
+	return json.Unmarshal(data, t)
+}
+
+// normalizeTags lowercases, trims and deduplicates tags.
+func normalizeTags(tags []string) Tags {
+	out := make(Tags, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// ListFilter narrows a tool listing. Zero fields match all.
+type ListFilter struct {
+	Category string
+	Tags     []string // a tool must have every tag
+}
+
+// matches reports whether t passes the filter.
+func (f ListFilter) matches(t *Tool) bool {
+	if f.Category != "" && t.Category != strings.ToLower(strings.TrimSpace(f.Category)) {
+		return false
+	}
+	for _, tag := range normalizeTags(f.Tags) {
+		if !slices.Contains(t.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// AgentAccess is an agent's tool allowlist. An agent may call a tool listed
+// by ID or name, or any tool in an allowed category.
+type AgentAccess struct {
+	TenantID   string    `gorm:"primaryKey" json:"tenant_id"`
+	AgentID    string    `gorm:"primaryKey" json:"agent_id"`
+	Tools      Tags      `gorm:"type:jsonb" json:"tools"`
+	Categories Tags      `gorm:"type:jsonb" json:"categories"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name
+func (AgentAccess) TableName() string {
+	return "agent_tool_access"
+}
+
+// allows reports whether the allowlist covers t.
+func (a *AgentAccess) allows(t *Tool) bool {
+	return slices.Contains(a.Tools, t.ID) ||
+		slices.Contains(a.Tools, t.Name) ||
+		(t.Category != "" && slices.Contains(a.Categories, t.Category))
+}
+
+// SetAgentAccess replaces an agent's tool allowlist.
+func (s *Service) SetAgentAccess(ctx context.Context, a *AgentAccess) error {
+	if strings.TrimSpace(a.AgentID) == "" {
+		return &ValidationError{Message: "agent id is required"}
+	}
+	a.Categories = normalizeTags(a.Categories)
+	a.UpdatedAt = s.now().UTC()
+	return s.repo.SaveAgentAccess(ctx, a)
+}
+
+// AgentAccess returns an agent's tool allowlist.
+func (s *Service) AgentAccess(ctx context.Context, tenantID, agentID string) (*AgentAccess, error) {
+	return s.repo.GetAgentAccess(ctx, tenantID, agentID)
+}
+
+// AgentTools returns the tenant's tools matching filter that the agent is
+// allowed to call. An agent without an allowlist may call none.
+func (s *Service) AgentTools(ctx context.Context, tenantID, agentID string, filter ListFilter) ([]Tool, error) {
+	access, err := s.repo.GetAgentAccess(ctx, tenantID, agentID)
+	if errors.Is(err, ErrAgentAccessNotFound) {
+		return []Tool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tools, err := s.repo.List(ctx, tenantID, filter)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]Tool, 0, len(tools))
+	for i := range tools {
+		if access.allows(&tools[i]) {
+			allowed = append(allowed, tools[i])
+		}
+	}
+	return allowed, nil
+}
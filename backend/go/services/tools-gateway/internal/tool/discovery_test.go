@@ -0,0 +1,91 @@
+package tool
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// createTaggedTool registers a tool for tenant-1 with a category and tags.
+func createTaggedTool(t *testing.T, s *Service, name, category string, tags ...string) *Tool {
+	t.Helper()
+
+	tool := &Tool{TenantID: "tenant-1", Name: name, Category: category, Tags: tags,
+		Definition: Definition{Method: http.MethodGet, URL: "https://api.example.com/" + name}}
+	if err := s.Create(context.Background(), tool); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	return tool
+}
+
+// names returns the names of tools.
+func names(tools []Tool) []string {
+	out := make([]string, len(tools))
+	for i, t := range tools {
+		out[i] = t.Name
+	}
+	return out
+}
+
+func TestList_CategoryAndTags(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+	createTaggedTool(t, s, "crm_lookup", "CRM", "customer", "read")
+	createTaggedTool(t, s, "crm_update", "crm", "customer", "write")
+	createTaggedTool(t, s, "weather", "utilities", "read")
+
+	tools, _ := s.List(ctx, "tenant-1", ListFilter{Category: "crm"})
+	if got := names(tools); len(got) != 2 || got[0] != "crm_lookup" || got[1] != "crm_update" {
+		t.Errorf("Expected the crm tools, got %v", got)
+	}
+
+	tools, _ = s.List(ctx, "tenant-1", ListFilter{Tags: []string{"Read", "customer"}})
+	if got := names(tools); len(got) != 1 || got[0] != "crm_lookup" {
+		t.Errorf("Expected only the tool with every tag, got %v", got)
+	}
+}
+
+func TestAgentTools_Allowlist(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+	lookup := createTaggedTool(t, s, "crm_lookup", "crm", "read")
+	createTaggedTool(t, s, "crm_update", "crm", "write")
+	createTaggedTool(t, s, "weather", "utilities", "read")
+	createTaggedTool(t, s, "calendar", "scheduling", "write")
+
+	// No allowlist means no tools
+	tools, err := s.AgentTools(ctx, "tenant-1", "agent-1", ListFilter{})
+	if err != nil {
+		t.Fatalf("AgentTools failed: %v", err)
+	}
+	if len(tools) != 0 {
+		t.Errorf("Expected no tools without an allowlist, got %v", names(tools))
+	}
+
+	if err := s.SetAgentAccess(ctx, &AgentAccess{
+		TenantID:   "tenant-1",
+		AgentID:    "agent-1",
+		Tools:      Tags{lookup.ID, "calendar"},
+		Categories: Tags{"Utilities"},
+	}); err != nil {
+		t.Fatalf("SetAgentAccess failed: %v", err)
+	}
+
+	tools, _ = s.AgentTools(ctx, "tenant-1", "agent-1", ListFilter{})
+	if got := names(tools); len(got) != 3 || got[0] != "calendar" || got[1] != "crm_lookup" || got[2] != "weather" {
+		t.Errorf("Expected the tools allowed by ID, name and category, got %v", got)
+	}
+
+	tools, _ = s.AgentTools(ctx, "tenant-1", "agent-1", ListFilter{Tags: []string{"read"}})
+	if got := names(tools); len(got) != 2 || got[0] != "crm_lookup" || got[1] != "weather" {
+		t.Errorf("Expected the filter to apply within the allowlist, got %v", got)
+	}
+
+	// Allowlists are per agent and per tenant
+	if tools, _ := s.AgentTools(ctx, "tenant-1", "agent-2", ListFilter{}); len(tools) != 0 {
+		t.Errorf("Expected another agent to see no tools, got %v", names(tools))
+	}
+	if tools, _ := s.AgentTools(ctx, "tenant-2", "agent-1", ListFilter{}); len(tools) != 0 {
+		t.Errorf("Expected another tenant's agent to see no tools, got %v", names(tools))
+	}
+}
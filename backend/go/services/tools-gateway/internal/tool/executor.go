@@ -0,0 +1,281 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// readChunk is the size of each read from a tool response.
+const readChunk = 32 << 10
+
+// Limits bound what a tool execution may send and receive.
+type Limits struct {
+	MaxInputBytes    int64         // largest accepted input payload
+	MaxResponseBytes int64         // default response cap; a tool may set a lower one
+	Timeout          time.Duration // default response timeout; a tool may set its own
+	TotalBytesBudget int64         // response bytes buffered across all executions at once
+}
+
+// DefaultLimits are used for limits left at zero.
+var DefaultLimits = Limits{
+	MaxInputBytes:    64 << 10,
+	MaxResponseBytes: 1 << 20,
+	Timeout:          10 * time.Second,
+	TotalBytesBudget: 64 << 20,
+}
+
+// Executor calls tool APIs. It is safe for concurrent use.
+type Executor struct {
+	client   *http.Client
+	limits   Limits
+	inFlight atomic.Int64 // response bytes currently buffered
+	capture  *Capture     // nil until EnableCapture
+}
+
+// NewExecutor creates an Executor. Zero limits take their DefaultLimits value.
+func NewExecutor(client *http.Client, limits Limits) *Executor {
+	if client == nil {
+		client = &http.Client{}
+	}
+	if limits.MaxInputBytes <= 0 {
+		limits.MaxInputBytes = DefaultLimits.MaxInputBytes
+	}
+	if limits.MaxResponseBytes <= 0 {
+		limits.MaxResponseBytes = DefaultLimits.MaxResponseBytes
+	}
+	if limits.Timeout <= 0 {
+		limits.Timeout = DefaultLimits.Timeout
+	}
+	if limits.TotalBytesBudget <= 0 {
+		limits.TotalBytesBudget = DefaultLimits.TotalBytesBudget
+	}
+	return &Executor{client: client, limits: limits}
+}
+
+// EnableCapture records the traffic of tenants that start a capture on c.
+// Without it, no traffic is ever recorded.
+func (e *Executor) EnableCapture(c *Capture) {
+	e.capture = c
+}
+
+// MaxInputBytes returns the largest accepted input payload.
+func (e *Executor) MaxInputBytes() int64 {
+	return e.limits.MaxInputBytes
+}
+
+// MaxResponseBytes returns the default response cap.
+func (e *Executor) MaxResponseBytes() int64 {
+	return e.limits.MaxResponseBytes
+}
+
+// Execute calls t with input as the JSON request body, or split into
+// parameters and body when the tool has params. The response must arrive
+// within the tool's timeout and fit in its response cap.
+func (e *Executor) Execute(ctx context.Context, t *Tool, input json.RawMessage) (*Result, error) {
+	if int64(len(input)) > e.limits.MaxInputBytes {
+		return nil, ErrInputTooLarge
+	}
+
+	timeout := e.limits.Timeout
+	if t.TimeoutMs > 0 {
+		timeout = time.Duration(t.TimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := newRequest(ctx, t, input)
+	if err != nil {
+		return nil, err
+	}
+
+	capturing := e.capture != nil && e.capture.active(t.TenantID)
+
+	start := time.Now()
+	resp, err := e.client.Do(req)
+	if err != nil {
+		err = e.timeoutErr(ctx, err)
+		if capturing {
+			e.capture.record(t, req, start, nil, nil, err)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	maxBytes := e.limits.MaxResponseBytes
+	if t.MaxResponseBytes > 0 {
+		maxBytes = min(t.MaxResponseBytes, maxBytes)
+	}
+	data, err := e.readLimited(resp.Body, maxBytes)
+	if err != nil {
+		err = e.timeoutErr(ctx, err)
+	}
+	if capturing {
+		e.capture.record(t, req, start, resp, data, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := mapResponse(t, resp.StatusCode, asJSON(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		ToolID:     t.ID,
+		StatusCode: resp.StatusCode,
+		Response:   response,
+		LatencyMs:  time.Since(start).Milliseconds(),
+		location:   resp.Header.Get("Location"),
+	}, nil
+}
+
+// mapResponse applies t's response mapping to a successful response and
+// checks the output schema. Other responses are returned as is.
+func mapResponse(t *Tool, statusCode int, response json.RawMessage) (json.RawMessage, error) {
+	m := t.ResponseMapping
+	if m == nil || m.IsZero() || statusCode/100 != 2 {
+		return response, nil
+	}
+	mapped, err := m.Apply(response)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSchema(t.OutputSchema, mapped); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+	return mapped, nil
+}
+
+// newRequest builds the HTTP request of a call of t with input.
+func newRequest(ctx context.Context, t *Tool, input json.RawMessage) (*http.Request, error) {
+	target, body := t.URL, input
+	header := make(http.Header)
+	if len(t.Params) > 0 {
+		var fields map[string]json.RawMessage
+		if len(input) > 0 {
+			if err := json.Unmarshal(input, &fields); err != nil {
+				return nil, &ValidationError{Message: "input must be a JSON object"}
+			}
+		}
+
+		// Path values are substituted escaped, before the URL is parsed
+		query := url.Values{}
+		for name, in := range t.Params {
+			raw, ok := fields[name]
+			if !ok {
+				if in == ParamPath {
+					return nil, &ValidationError{Message: fmt.Sprintf("input is missing path parameter %q", name)}
+				}
+				continue
+			}
+			value := paramValue(raw)
+			switch in {
+			case ParamPath:
+				target = strings.ReplaceAll(target, "{"+name+"}", url.PathEscape(value))
+			case ParamQuery:
+				query.Set(name, value)
+			case ParamHeader:
+				header.Set(name, value)
+			}
+		}
+
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tool url: %w", err)
+		}
+		q := u.Query()
+		for name, values := range query {
+			q[name] = values
+		}
+		u.RawQuery = q.Encode()
+		target, body = u.String(), fields["body"]
+	}
+
+	var reader io.Reader
+	if len(body) > 0 && t.Method != http.MethodGet && t.Method != http.MethodDelete {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, t.Method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tool request: %w", err)
+	}
+	req.Header = header
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range t.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// paramValue returns a JSON string unquoted and any other value as is.
+func paramValue(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// readLimited reads r in chunks, failing with ErrResponseTooLarge once it
+// passes maxBytes or the executor's total budget would be exceeded.
+func (e *Executor) readLimited(r io.Reader, maxBytes int64) ([]byte, error) {
+	var reserved int64
+	defer func() { e.inFlight.Add(-reserved) }()
+
+	// One extra byte tells a response of exactly maxBytes from a larger one
+	r = io.LimitReader(r, maxBytes+1)
+
+	var buf bytes.Buffer
+	chunk := make([]byte, readChunk)
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			if int64(buf.Len()+n) > maxBytes {
+				return nil, ErrResponseTooLarge
+			}
+			reserved += int64(n)
+			if e.inFlight.Add(int64(n)) > e.limits.TotalBytesBudget {
+				return nil, ErrResponseTooLarge
+			}
+			buf.Write(chunk[:n])
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// timeoutErr reports err as ErrTimeout when the tool's deadline passed.
+func (e *Executor) timeoutErr(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return fmt.Errorf("tool request failed: %w", err)
+}
+
+// asJSON returns data as is when it is valid JSON, or as a JSON string.
+func asJSON(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return json.RawMessage("null")
+	}
+	if json.Valid(data) {
+		return data
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
@@ -0,0 +1,105 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newStubTool(url string) *Tool {
+	return &Tool{ID: "tool-1", TenantID: "tenant-1", Name: "stub", Definition: Definition{Method: http.MethodPost, URL: url}}
+}
+
+func TestExecute_ReturnsResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	e := NewExecutor(srv.Client(), Limits{})
+	res, err := e.Execute(context.Background(), newStubTool(srv.URL), []byte(`{"q":1}`))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if res.StatusCode != http.StatusOK || string(res.Response) != `{"ok":true}` {
+		t.Errorf("Unexpected result %d %s", res.StatusCode, res.Response)
+	}
+}
+
+func TestExecute_OversizedResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+	defer srv.Close()
+
+	e := NewExecutor(srv.Client(), Limits{MaxResponseBytes: 1024})
+	if _, err := e.Execute(context.Background(), newStubTool(srv.URL), nil); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge, got %v", err)
+	}
+
+	// A per-tool cap can only lower the gateway's
+	tool := newStubTool(srv.URL)
+	tool.MaxResponseBytes = 100
+	e = NewExecutor(srv.Client(), Limits{MaxResponseBytes: 4096})
+	if _, err := e.Execute(context.Background(), tool, nil); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge with the tool cap, got %v", err)
+	}
+	if e.inFlight.Load() != 0 {
+		t.Errorf("Expected the budget to be released, %d bytes still reserved", e.inFlight.Load())
+	}
+}
+
+func TestExecute_TotalBytesBudget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 512)))
+	}))
+	defer srv.Close()
+
+	e := NewExecutor(srv.Client(), Limits{MaxResponseBytes: 1024, TotalBytesBudget: 1024})
+	e.inFlight.Add(768) // other executions hold most of the budget
+	if _, err := e.Execute(context.Background(), newStubTool(srv.URL), nil); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge, got %v", err)
+	}
+
+	e.inFlight.Add(-768)
+	if _, err := e.Execute(context.Background(), newStubTool(srv.URL), nil); err != nil {
+		t.Fatalf("Expected the execution to fit the released budget: %v", err)
+	}
+}
+
+func TestExecute_SlowBody(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"partial":`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	tool := newStubTool(srv.URL)
+	tool.TimeoutMs = 50
+	e := NewExecutor(srv.Client(), Limits{Timeout: time.Minute})
+
+	start := time.Now()
+	if _, err := e.Execute(context.Background(), tool, nil); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the per-tool timeout to apply, took %v", elapsed)
+	}
+}
+
+func TestExecute_InputTooLarge(t *testing.T) {
+	e := NewExecutor(nil, Limits{MaxInputBytes: 8})
+	if _, err := e.Execute(context.Background(), newStubTool("http://127.0.0.1:1"), []byte(`{"q":"too long"}`)); !errors.Is(err, ErrInputTooLarge) {
+		t.Fatalf("Expected ErrInputTooLarge, got %v", err)
+	}
+}
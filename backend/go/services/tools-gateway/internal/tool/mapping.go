@@ -0,0 +1,214 @@
+package tool
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ErrInvalidOutput is returned when a tool response can't be mapped or does
+// not match the tool's output schema.
+var ErrInvalidOutput = errors.New("tool response does not match its output")
+
+// ResponseMapping reshapes a successful upstream response before it reaches
+// the agent. Either Fields or Template is set.
+type ResponseMapping struct {
+	// Fields maps output fields to JSONPath expressions into the response,
+	// e.g. {"name": "$.data.customer.name", "ids": "$.items[*].id"}.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Template is a Go template rendering the output JSON, with the decoded
+	// response as dot and a json function to encode values.
+	Template string `json:"template,omitempty"`
+}
+
+// IsZero reports whether no mapping is configured.
+func (m ResponseMapping) IsZero() bool {
+	return len(m.Fields) == 0 && m.Template == ""
+}
+
+// Value implements driver.Valuer.
+func (m ResponseMapping) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner.
+func (m *ResponseMapping) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil || data == nil {
+		*m = ResponseMapping{}
+		return err
+	}
+	return json.Unmarshal(data, m)
+}
+
+// validate checks the mapping's expressions parse.
+func (m ResponseMapping) validate() error {
+	if len(m.Fields) > 0 && m.Template != "" {
+		return &ValidationError{Message: "response_mapping must set fields or template, not both"}
+	}
+	for field, path := range m.Fields {
+		if _, err := parseJSONPath(path); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("response_mapping field %q: %v", field, err)}
+		}
+	}
+	if m.Template != "" {
+		if _, err := m.parseTemplate(); err != nil {
+			return &ValidationError{Message: fmt.Sprintf("response_mapping template: %v", err)}
+		}
+	}
+	return nil
+}
+
+func (m ResponseMapping) parseTemplate() (*template.Template, error) {
+	return template.New("response").
+		Option("missingkey=zero").
+		Funcs(template.FuncMap{"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		}}).
+		Parse(m.Template)
+}
+
+// Apply maps a decoded JSON response to the output JSON.
+func (m ResponseMapping) Apply(response []byte) (json.RawMessage, error) {
+	var doc any
+	if err := json.Unmarshal(response, &doc); err != nil {
+		return nil, fmt.Errorf("%w: response is not JSON", ErrInvalidOutput)
+	}
+
+	if m.Template != "" {
+		tmpl, err := m.parseTemplate()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, doc); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+		}
+		if !json.Valid(buf.Bytes()) {
+			return nil, fmt.Errorf("%w: template did not render JSON", ErrInvalidOutput)
+		}
+		return buf.Bytes(), nil
+	}
+
+	out := make(map[string]any, len(m.Fields))
+	for field, path := range m.Fields {
+		steps, err := parseJSONPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+		}
+		out[field] = evalJSONPath(doc, steps)
+	}
+	return json.Marshal(out)
+}
+
+// pathStep is one step of a JSONPath: a key, an index or a wildcard.
+type pathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses the JSONPath subset $.key, $['key'], $[0] and $[*].
+func parseJSONPath(path string) ([]pathStep, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(path), "$")
+	if !ok {
+		return nil, fmt.Errorf("path %q must start with $", path)
+	}
+
+	var steps []pathStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("path %q has an empty key", path)
+			}
+			if key == "*" {
+				steps = append(steps, pathStep{wildcard: true})
+			} else {
+				steps = append(steps, pathStep{key: key})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q has an unclosed [", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, pathStep{key: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("path %q has an invalid index %q", path, inner)
+				}
+				steps = append(steps, pathStep{index: index, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("path %q has an unexpected %q", path, rest[0])
+		}
+	}
+	return steps, nil
+}
+
+// evalJSONPath follows steps through doc. Missing values are nil; a wildcard
+// collects the rest of the path over every element.
+func evalJSONPath(doc any, steps []pathStep) any {
+	for i, step := range steps {
+		switch {
+		case step.wildcard:
+			var items []any
+			switch v := doc.(type) {
+			case []any:
+				items = v
+			case map[string]any:
+				for _, item := range v {
+					items = append(items, item)
+				}
+			default:
+				return nil
+			}
+			out := make([]any, 0, len(items))
+			for _, item := range items {
+				out = append(out, evalJSONPath(item, steps[i+1:]))
+			}
+			return out
+		case step.isIndex:
+			arr, ok := doc.([]any)
+			if !ok {
+				return nil
+			}
+			index := step.index
+			if index < 0 {
+				index += len(arr)
+			}
+			if index < 0 || index >= len(arr) {
+				return nil
+			}
+			doc = arr[index]
+		default:
+			obj, ok := doc.(map[string]any)
+			if !ok {
+				return nil
+			}
+			doc = obj[step.key]
+		}
+	}
+	return doc
+}
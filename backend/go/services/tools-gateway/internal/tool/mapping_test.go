@@ -0,0 +1,122 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const upstreamCustomer = `{
+	"data": {
+		"customer": {
+			"id": "c-42",
+			"profile": {"full_name": "Ana Souza", "emails": [{"value": "ana@example.com"}, {"value": "ana@work.example"}]},
+			"orders": [{"id": "o-1", "total": 10.5}, {"id": "o-2", "total": 99}]
+		}
+	},
+	"meta": {"request_id": "r-1"}
+}`
+
+const flatCustomerSchema = `{
+	"type": "object",
+	"required": ["id", "name", "email"],
+	"properties": {
+		"id": {"type": "string"},
+		"name": {"type": "string"},
+		"email": {"type": "string"},
+		"order_ids": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+// newUpstream serves body as the upstream response.
+func newUpstream(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestResponseMapping_JSONPathFlattens(t *testing.T) {
+	srv := newUpstream(t, upstreamCustomer)
+	tool := newStubTool(srv.URL)
+	tool.OutputSchema = Schema(flatCustomerSchema)
+	tool.ResponseMapping = &ResponseMapping{Fields: map[string]string{
+		"id":        "$.data.customer.id",
+		"name":      "$.data.customer.profile.full_name",
+		"email":     "$.data.customer.profile.emails[0].value",
+		"order_ids": "$.data.customer.orders[*].id",
+	}}
+
+	res, err := NewExecutor(srv.Client(), Limits{}).Execute(context.Background(), tool, nil)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	var got map[string]any
+	json.Unmarshal(res.Response, &got)
+	want := map[string]any{
+		"id":        "c-42",
+		"name":      "Ana Souza",
+		"email":     "ana@example.com",
+		"order_ids": []any{"o-1", "o-2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestResponseMapping_Template(t *testing.T) {
+	srv := newUpstream(t, upstreamCustomer)
+	tool := newStubTool(srv.URL)
+	tool.OutputSchema = Schema(flatCustomerSchema)
+	tool.ResponseMapping = &ResponseMapping{Template: `{{with .data.customer}}{"id": {{json .id}}, "name": {{json .profile.full_name}}, "email": {{json (index .profile.emails 1).value}}}{{end}}`}
+
+	res, err := NewExecutor(srv.Client(), Limits{}).Execute(context.Background(), tool, nil)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if want := `{"id": "c-42", "name": "Ana Souza", "email": "ana@work.example"}`; string(res.Response) != want {
+		t.Errorf("Expected %s, got %s", want, res.Response)
+	}
+}
+
+func TestResponseMapping_ValidatesOutputSchema(t *testing.T) {
+	srv := newUpstream(t, upstreamCustomer)
+	tool := newStubTool(srv.URL)
+	tool.OutputSchema = Schema(flatCustomerSchema)
+	tool.ResponseMapping = &ResponseMapping{Fields: map[string]string{
+		"id":   "$.data.customer.id",
+		"name": "$.data.customer.profile.full_name",
+		// email is missing upstream and required by the output schema
+		"email": "$.data.customer.email",
+	}}
+
+	_, err := NewExecutor(srv.Client(), Limits{}).Execute(context.Background(), tool, nil)
+	if !errors.Is(err, ErrInvalidOutput) {
+		t.Fatalf("Expected ErrInvalidOutput, got %v", err)
+	}
+}
+
+func TestResponseMapping_Validate(t *testing.T) {
+	for name, m := range map[string]ResponseMapping{
+		"both set":      {Fields: map[string]string{"a": "$.a"}, Template: `{}`},
+		"no root":       {Fields: map[string]string{"a": "data.a"}},
+		"bad index":     {Fields: map[string]string{"a": "$.items[x]"}},
+		"bad template":  {Template: `{{.a`},
+		"unclosed step": {Fields: map[string]string{"a": "$.items[0"}},
+	} {
+		if err := m.validate(); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+	if err := (ResponseMapping{Fields: map[string]string{"a": "$['odd key'][0].b", "all": "$.*"}}).validate(); err != nil {
+		t.Errorf("Expected a valid mapping, got %v", err)
+	}
+}
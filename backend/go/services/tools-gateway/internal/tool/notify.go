@@ -0,0 +1,62 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-events/events"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/topics"
+	"github.com/serphona/serphona/backend/go/libs/platform-events/types"
+)
+
+// Publisher publishes platform events.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, event *types.Event) error
+}
+
+// EventNotifier publishes finished async executions as tool completed events.
+type EventNotifier struct {
+	publisher Publisher
+	source    string
+}
+
+// NewEventNotifier creates an EventNotifier publishing as source.
+func NewEventNotifier(publisher Publisher, source string) *EventNotifier {
+	return &EventNotifier{publisher: publisher, source: source}
+}
+
+// Notify implements Notifier.
+func (n *EventNotifier) Notify(ctx context.Context, e *Execution) error {
+	result := map[string]interface{}{
+		"execution_id": e.ID,
+		"version":      e.Version,
+		"outcome":      e.Outcome,
+		"status_code":  e.StatusCode,
+	}
+	if len(e.Response) > 0 {
+		var response interface{}
+		if err := json.Unmarshal(e.Response, &response); err == nil {
+			result["response"] = response
+		}
+	}
+	if e.Error != "" {
+		result["error"] = e.Error
+	}
+
+	completedAt := time.Now().UTC()
+	if e.CompletedAt != nil {
+		completedAt = *e.CompletedAt
+	}
+	event := events.NewEvent(topics.ToolCompleted, n.source, events.ToolCompletedEvent{
+		ToolID:      e.ToolID,
+		TenantID:    e.TenantID,
+		AgentID:     e.AgentID,
+		Action:      e.Method,
+		Result:      result,
+		Duration:    time.Duration(e.LatencyMs) * time.Millisecond,
+		CompletedAt: completedAt,
+	}).WithTenantID(e.TenantID)
+
+	return n.publisher.Publish(ctx, topics.ToolCompleted, event)
+}
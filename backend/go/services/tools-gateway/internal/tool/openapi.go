@@ -0,0 +1,392 @@
+package tool
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxRefDepth bounds $ref resolution, so recursive schemas terminate.
+const maxRefDepth = 16
+
+// OpenAPIImport selects an operation of an OpenAPI 3 spec to import as a tool.
+type OpenAPIImport struct {
+	Spec        []byte // JSON or YAML
+	OperationID string
+	Name        string // defaults to the operation ID
+	ServerURL   string // one of the spec's servers; defaults to the first
+	AuthScheme  string // security scheme name; defaults to the operation's first when a credential is given
+	Credential  string // API key, bearer token or "user:password" for basic auth
+}
+
+type openAPISpec struct {
+	OpenAPI    string                     `yaml:"openapi"`
+	Servers    []openAPIServer            `yaml:"servers"`
+	Paths      map[string]openAPIPathItem `yaml:"paths"`
+	Security   []map[string][]string      `yaml:"security"`
+	Components struct {
+		Schemas         map[string]any                   `yaml:"schemas"`
+		Parameters      map[string]openAPIParameter      `yaml:"parameters"`
+		SecuritySchemes map[string]openAPISecurityScheme `yaml:"securitySchemes"`
+	} `yaml:"components"`
+}
+
+type openAPIServer struct {
+	URL       string `yaml:"url"`
+	Variables map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter `yaml:"parameters"`
+	Get        *openAPIOperation  `yaml:"get"`
+	Post       *openAPIOperation  `yaml:"post"`
+	Put        *openAPIOperation  `yaml:"put"`
+	Patch      *openAPIOperation  `yaml:"patch"`
+	Delete     *openAPIOperation  `yaml:"delete"`
+}
+
+// operations returns the path's operations by HTTP method.
+func (p openAPIPathItem) operations() map[string]*openAPIOperation {
+	return map[string]*openAPIOperation{
+		http.MethodGet:    p.Get,
+		http.MethodPost:   p.Post,
+		http.MethodPut:    p.Put,
+		http.MethodPatch:  p.Patch,
+		http.MethodDelete: p.Delete,
+	}
+}
+
+type openAPIOperation struct {
+	OperationID string                 `yaml:"operationId"`
+	Summary     string                 `yaml:"summary"`
+	Description string                 `yaml:"description"`
+	Parameters  []openAPIParameter     `yaml:"parameters"`
+	RequestBody *openAPIBody           `yaml:"requestBody"`
+	Responses   map[string]openAPIBody `yaml:"responses"`
+	Security    *[]map[string][]string `yaml:"security"` // nil inherits the spec's
+}
+
+type openAPIParameter struct {
+	Ref         string `yaml:"$ref"`
+	Name        string `yaml:"name"`
+	In          string `yaml:"in"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+	Schema      any    `yaml:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool `yaml:"required"`
+	Content  map[string]struct {
+		Schema any `yaml:"schema"`
+	} `yaml:"content"`
+}
+
+// jsonSchema returns the body's JSON media type schema, if any.
+func (b *openAPIBody) jsonSchema() (any, bool) {
+	if b == nil {
+		return nil, false
+	}
+	for mediaType, content := range b.Content {
+		if strings.HasPrefix(mediaType, "application/json") || strings.HasSuffix(mediaType, "+json") {
+			return content.Schema, content.Schema != nil
+		}
+	}
+	return nil, false
+}
+
+type openAPISecurityScheme struct {
+	Type   string `yaml:"type"`
+	Scheme string `yaml:"scheme"`
+	In     string `yaml:"in"`
+	Name   string `yaml:"name"`
+}
+
+// FromOpenAPI derives a tool definition from an operation of an OpenAPI 3
+// spec: method, URL, parameters, input and output schemas and, when a
+// credential is given, the authentication headers or query parameter.
+func FromOpenAPI(req OpenAPIImport) (*Tool, error) {
+	var spec openAPISpec
+	if err := yaml.Unmarshal(req.Spec, &spec); err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("spec is not valid JSON or YAML: %v", err)}
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, &ValidationError{Message: "spec must be OpenAPI 3"}
+	}
+	if req.OperationID == "" {
+		return nil, &ValidationError{Message: "operation_id is required"}
+	}
+
+	path, method, item, op := spec.findOperation(req.OperationID)
+	if op == nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("operation %q not found in spec", req.OperationID)}
+	}
+
+	server, err := spec.server(req.ServerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tool{
+		Name:        req.Name,
+		Description: op.Summary,
+		Definition: Definition{
+			Method:  method,
+			URL:     strings.TrimSuffix(server, "/") + path,
+			Headers: Headers{},
+			Params:  Params{},
+		},
+	}
+	if t.Name == "" {
+		t.Name = op.OperationID
+	}
+	if t.Description == "" {
+		t.Description = op.Description
+	}
+
+	input, err := spec.inputSchema(t, item, op)
+	if err != nil {
+		return nil, err
+	}
+	t.InputSchema = input
+
+	if schema, ok := spec.outputSchema(op); ok {
+		out, err := json.Marshal(spec.resolve(schema, 0))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode output schema: %w", err)
+		}
+		t.OutputSchema = out
+	}
+
+	if err := spec.applyAuth(t, op, req.AuthScheme, req.Credential); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// findOperation looks an operation up by ID.
+func (s *openAPISpec) findOperation(id string) (string, string, openAPIPathItem, *openAPIOperation) {
+	for path, item := range s.Paths {
+		for method, op := range item.operations() {
+			if op != nil && op.OperationID == id {
+				return path, method, item, op
+			}
+		}
+	}
+	return "", "", openAPIPathItem{}, nil
+}
+
+// server returns the selected server URL with its variables at their defaults.
+func (s *openAPISpec) server(selected string) (string, error) {
+	if len(s.Servers) == 0 {
+		if selected == "" {
+			return "", &ValidationError{Message: "spec has no servers; server_url is required"}
+		}
+		return checkServerURL(selected)
+	}
+
+	server := s.Servers[0]
+	if selected != "" {
+		found := false
+		for _, candidate := range s.Servers {
+			if candidate.URL == selected {
+				server, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			return "", &ValidationError{Message: fmt.Sprintf("server %q is not in the spec", selected)}
+		}
+	}
+
+	u := server.URL
+	for name, v := range server.Variables {
+		u = strings.ReplaceAll(u, "{"+name+"}", v.Default)
+	}
+	return checkServerURL(u)
+}
+
+func checkServerURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", &ValidationError{Message: fmt.Sprintf("server url %q must be an absolute http or https URL", raw)}
+	}
+	return raw, nil
+}
+
+// inputSchema builds an object schema with a property per parameter and a
+// "body" property for the request body, recording where each is sent.
+func (s *openAPISpec) inputSchema(t *Tool, item openAPIPathItem, op *openAPIOperation) (Schema, error) {
+	properties := map[string]any{}
+	var required []string
+
+	// Operation parameters override path-level ones with the same name and location
+	params := map[string]openAPIParameter{}
+	for _, p := range append(append([]openAPIParameter{}, item.Parameters...), op.Parameters...) {
+		p = s.resolveParameter(p)
+		if p.Name == "" {
+			continue
+		}
+		params[p.In+":"+p.Name] = p
+	}
+
+	for _, p := range params {
+		switch p.In {
+		case ParamPath, ParamQuery, ParamHeader:
+		default:
+			continue // cookie parameters are not supported
+		}
+		t.Params[p.Name] = p.In
+
+		schema, _ := s.resolve(p.Schema, 0).(map[string]any)
+		property := map[string]any{}
+		for k, v := range schema {
+			property[k] = v
+		}
+		if p.Description != "" {
+			property["description"] = p.Description
+		}
+		properties[p.Name] = property
+		if p.Required || p.In == ParamPath {
+			required = append(required, p.Name)
+		}
+	}
+
+	if schema, ok := op.RequestBody.jsonSchema(); ok {
+		properties["body"] = s.resolve(schema, 0)
+		if op.RequestBody.Required {
+			required = append(required, "body")
+		}
+	}
+
+	input := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		input["required"] = required
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode input schema: %w", err)
+	}
+	return data, nil
+}
+
+// outputSchema returns the JSON schema of the operation's success response.
+func (s *openAPISpec) outputSchema(op *openAPIOperation) (any, bool) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	codes = append(codes, "default")
+
+	for _, code := range codes {
+		if resp, ok := op.Responses[code]; ok {
+			if schema, ok := resp.jsonSchema(); ok {
+				return schema, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// applyAuth sends the credential as the selected security scheme requires.
+func (s *openAPISpec) applyAuth(t *Tool, op *openAPIOperation, name, credential string) error {
+	if name == "" && credential == "" {
+		return nil
+	}
+	if name == "" {
+		requirements := s.Security
+		if op.Security != nil {
+			requirements = *op.Security
+		}
+		for _, requirement := range requirements {
+			for scheme := range requirement {
+				name = scheme
+				break
+			}
+			if name != "" {
+				break
+			}
+		}
+		if name == "" {
+			return &ValidationError{Message: "operation has no security requirement; auth_scheme is required"}
+		}
+	}
+	if credential == "" {
+		return &ValidationError{Message: "credential is required for the auth scheme"}
+	}
+
+	scheme, ok := s.Components.SecuritySchemes[name]
+	if !ok {
+		return &ValidationError{Message: fmt.Sprintf("security scheme %q not found in spec", name)}
+	}
+
+	switch {
+	case scheme.Type == "apiKey" && scheme.In == ParamHeader:
+		t.Headers[scheme.Name] = credential
+	case scheme.Type == "apiKey" && scheme.In == ParamQuery:
+		u, err := url.Parse(t.URL)
+		if err != nil {
+			return fmt.Errorf("failed to parse tool url: %w", err)
+		}
+		q := u.Query()
+		q.Set(scheme.Name, credential)
+		u.RawQuery = q.Encode()
+		t.URL = u.String()
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+		t.Headers["Authorization"] = "Bearer " + credential
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		t.Headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credential))
+	default:
+		return &ValidationError{Message: fmt.Sprintf("security scheme %q of type %q is not supported", name, scheme.Type)}
+	}
+	return nil
+}
+
+// resolveParameter follows a parameter $ref into components.
+func (s *openAPISpec) resolveParameter(p openAPIParameter) openAPIParameter {
+	if p.Ref == "" {
+		return p
+	}
+	name, _ := strings.CutPrefix(p.Ref, "#/components/parameters/")
+	return s.Components.Parameters[name]
+}
+
+// resolve returns schema with its local $refs to components/schemas inlined.
+func (s *openAPISpec) resolve(schema any, depth int) any {
+	switch v := schema.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			name, found := strings.CutPrefix(ref, "#/components/schemas/")
+			target, exists := s.Components.Schemas[name]
+			if !found || !exists || depth >= maxRefDepth {
+				return map[string]any{}
+			}
+			return s.resolve(target, depth+1)
+		}
+		out := make(map[string]any, len(v))
+		for k, field := range v {
+			out[k] = s.resolve(field, depth)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = s.resolve(item, depth)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,215 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const petstoreSpec = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://api.petstore.example/v1
+  - url: https://{region}.petstore.example/v1
+    variables:
+      region:
+        default: sa
+security:
+  - apiKeyAuth: []
+paths:
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get:
+      operationId: getPet
+      summary: Find a pet by ID
+      parameters:
+        - name: include
+          in: query
+          description: Related data to include
+          schema:
+            type: string
+            enum: [owner, visits]
+      responses:
+        200:
+          description: The pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    put:
+      operationId: updatePet
+      security:
+        - bearerAuth: []
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '204':
+          description: Updated
+components:
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: string
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        tags:
+          type: array
+          items:
+            type: string
+  securitySchemes:
+    apiKeyAuth:
+      type: apiKey
+      in: header
+      name: X-Api-Key
+    bearerAuth:
+      type: http
+      scheme: bearer
+`
+
+// decodeJSON decodes a schema for comparison.
+func decodeJSON(t *testing.T, data []byte) any {
+	t.Helper()
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", data, err)
+	}
+	return v
+}
+
+func TestFromOpenAPI_GetOperation(t *testing.T) {
+	tool, err := FromOpenAPI(OpenAPIImport{
+		Spec:        []byte(petstoreSpec),
+		OperationID: "getPet",
+		ServerURL:   "https://{region}.petstore.example/v1",
+		Credential:  "k3y",
+	})
+	if err != nil {
+		t.Fatalf("FromOpenAPI failed: %v", err)
+	}
+
+	if tool.Name != "getPet" || tool.Description != "Find a pet by ID" {
+		t.Errorf("Unexpected name and description %q %q", tool.Name, tool.Description)
+	}
+	if tool.Method != http.MethodGet || tool.URL != "https://sa.petstore.example/v1/pets/{petId}" {
+		t.Errorf("Unexpected request %s %s", tool.Method, tool.URL)
+	}
+	if want := (Params{"petId": ParamPath, "include": ParamQuery}); !reflect.DeepEqual(tool.Params, want) {
+		t.Errorf("Expected params %v, got %v", want, tool.Params)
+	}
+	if tool.Headers["X-Api-Key"] != "k3y" {
+		t.Errorf("Expected the spec's default security scheme to be applied, got headers %v", tool.Headers)
+	}
+
+	wantInput := decodeJSON(t, []byte(`{
+		"type": "object",
+		"required": ["petId"],
+		"properties": {
+			"petId": {"type": "string"},
+			"include": {"type": "string", "enum": ["owner", "visits"], "description": "Related data to include"}
+		}
+	}`))
+	if got := decodeJSON(t, tool.InputSchema); !reflect.DeepEqual(got, wantInput) {
+		t.Errorf("Unexpected input schema %s", tool.InputSchema)
+	}
+
+	wantOutput := decodeJSON(t, []byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {"name": {"type": "string"}, "tags": {"type": "array", "items": {"type": "string"}}}
+	}`))
+	if got := decodeJSON(t, tool.OutputSchema); !reflect.DeepEqual(got, wantOutput) {
+		t.Errorf("Unexpected output schema %s", tool.OutputSchema)
+	}
+}
+
+func TestFromOpenAPI_BodyAndBearer(t *testing.T) {
+	tool, err := FromOpenAPI(OpenAPIImport{
+		Spec:        []byte(petstoreSpec),
+		OperationID: "updatePet",
+		Name:        "update_pet",
+		Credential:  "t0ken",
+	})
+	if err != nil {
+		t.Fatalf("FromOpenAPI failed: %v", err)
+	}
+
+	if tool.Name != "update_pet" || tool.Method != http.MethodPut || tool.URL != "https://api.petstore.example/v1/pets/{petId}" {
+		t.Errorf("Unexpected tool %s %s %s", tool.Name, tool.Method, tool.URL)
+	}
+	if tool.Headers["Authorization"] != "Bearer t0ken" {
+		t.Errorf("Expected the operation's bearer scheme, got headers %v", tool.Headers)
+	}
+	input := decodeJSON(t, tool.InputSchema).(map[string]any)
+	if !reflect.DeepEqual(input["required"], []any{"body", "petId"}) {
+		t.Errorf("Expected body and petId to be required, got %v", input["required"])
+	}
+	body := input["properties"].(map[string]any)["body"].(map[string]any)
+	if body["type"] != "object" || body["properties"] == nil {
+		t.Errorf("Expected the resolved Pet schema as body, got %v", body)
+	}
+	if len(tool.OutputSchema) != 0 {
+		t.Errorf("Expected no output schema for a 204 response, got %s", tool.OutputSchema)
+	}
+}
+
+func TestFromOpenAPI_Errors(t *testing.T) {
+	for name, req := range map[string]OpenAPIImport{
+		"unknown operation": {Spec: []byte(petstoreSpec), OperationID: "deletePet"},
+		"unknown server":    {Spec: []byte(petstoreSpec), OperationID: "getPet", ServerURL: "https://evil.example"},
+		"unknown scheme":    {Spec: []byte(petstoreSpec), OperationID: "getPet", AuthScheme: "oauth", Credential: "x"},
+		"swagger 2":         {Spec: []byte(`{"swagger": "2.0"}`), OperationID: "getPet"},
+	} {
+		if _, err := FromOpenAPI(req); err == nil {
+			t.Errorf("%s: expected an error", name)
+		} else if _, ok := err.(*ValidationError); !ok {
+			t.Errorf("%s: expected a ValidationError, got %T", name, err)
+		}
+	}
+}
+
+func TestImport_ExecutesWithParams(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"name":"Rex"}`))
+	}))
+	defer srv.Close()
+
+	spec := strings.Replace(petstoreSpec, "https://api.petstore.example/v1", srv.URL+"/v1", 1)
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+	tool, err := s.Import(context.Background(), "tenant-1", OpenAPIImport{Spec: []byte(spec), OperationID: "getPet", Credential: "k3y"})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if tool.ID == "" || tool.TenantID != "tenant-1" || tool.Version != 1 {
+		t.Errorf("Expected a stored tool, got %+v", tool)
+	}
+
+	if _, err := s.Execute(context.Background(), "tenant-1", tool.ID, "", 0, []byte(`{"petId":"a/b","include":"owner"}`)); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got.URL.EscapedPath() != "/v1/pets/a%2Fb" || got.URL.Query().Get("include") != "owner" || got.Header.Get("X-Api-Key") != "k3y" {
+		t.Errorf("Unexpected request %s %v", got.URL, got.Header)
+	}
+}
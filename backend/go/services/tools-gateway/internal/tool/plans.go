@@ -0,0 +1,89 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+// TenantPlans reads the plan of tenants from tenant-manager and caches it, so
+// plan entitlements never depend on what the client sends.
+type TenantPlans struct {
+	baseURL string
+	client  *http.Client
+	ttl     time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedPlan
+}
+
+type cachedPlan struct {
+	plan    entitlements.Plan
+	expires time.Time
+}
+
+// NewTenantPlans creates a TenantPlans for tenant-manager at baseURL.
+func NewTenantPlans(baseURL string, client *http.Client, ttl time.Duration) *TenantPlans {
+	return &TenantPlans{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+		ttl:     ttl,
+		cache:   make(map[string]cachedPlan),
+	}
+}
+
+// Plan returns the tenant's plan. Unknown tenants, and tenants with a plan
+// this service doesn't know, have no plan.
+func (p *TenantPlans) Plan(ctx context.Context, tenantID string) (entitlements.Plan, error) {
+	p.mu.Lock()
+	cached, ok := p.cache[tenantID]
+	p.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.plan, nil
+	}
+
+	plan, err := p.fetch(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	p.cache[tenantID] = cachedPlan{plan: plan, expires: time.Now().Add(p.ttl)}
+	p.mu.Unlock()
+	return plan, nil
+}
+
+func (p *TenantPlans) fetch(ctx context.Context, tenantID string) (entitlements.Plan, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/api/v1/tenants/"+url.PathEscape(tenantID), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("tenant-manager returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Plan string `json:"plan"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode tenant: %w", err)
+	}
+	plan, _ := entitlements.ParsePlan(body.Plan)
+	return plan, nil
+}
@@ -0,0 +1,62 @@
+package tool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+)
+
+func TestTenantPlans_ReadsPlanFromTenantManager(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/api/v1/tenants/t-starter":
+			w.Write([]byte(`{"id":"t-starter","plan":"Starter"}`))
+		case "/api/v1/tenants/t-legacy":
+			w.Write([]byte(`{"id":"t-legacy","plan":"gold"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	plans := NewTenantPlans(srv.URL, srv.Client(), time.Minute)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		tenantID string
+		want     entitlements.Plan
+	}{
+		{"t-starter", entitlements.PlanStarter},
+		{"t-legacy", ""},  // a plan this service doesn't know
+		{"t-missing", ""}, // unknown tenant
+	} {
+		plan, err := plans.Plan(ctx, tt.tenantID)
+		if err != nil {
+			t.Fatalf("Plan(%s) failed: %v", tt.tenantID, err)
+		}
+		if plan != tt.want {
+			t.Errorf("Plan(%s) = %q, want %q", tt.tenantID, plan, tt.want)
+		}
+	}
+
+	if _, err := plans.Plan(ctx, "t-starter"); err != nil || requests != 3 {
+		t.Errorf("Expected the plan served from cache, got %d requests (err %v)", requests, err)
+	}
+}
+
+func TestTenantPlans_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if _, err := NewTenantPlans(srv.URL, srv.Client(), time.Minute).Plan(context.Background(), "t-1"); err == nil {
+		t.Error("Expected an error when tenant-manager is unavailable")
+	}
+}
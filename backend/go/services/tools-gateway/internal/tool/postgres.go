@@ -0,0 +1,221 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// GormRepository implements Repository using PostgreSQL
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository creates a new PostgreSQL tool repository
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// Create inserts a tool and its first version
+func (r *GormRepository) Create(ctx context.Context, t *Tool, v *Version) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(t).Error; err != nil {
+			return err
+		}
+		return tx.Create(v).Error
+	})
+}
+
+// Get finds a tenant's tool by ID
+func (r *GormRepository) Get(ctx context.Context, tenantID, id string) (*Tool, error) {
+	var t Tool
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// List returns a tenant's tools matching filter by name
+func (r *GormRepository) List(ctx context.Context, tenantID string, filter ListFilter) ([]Tool, error) {
+	q := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID)
+	if filter.Category != "" {
+		q = q.Where("category = ?", strings.ToLower(strings.TrimSpace(filter.Category)))
+	}
+	if tags := normalizeTags(filter.Tags); len(tags) > 0 {
+		data, err := json.Marshal(tags)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where("tags @> ?::jsonb", string(data))
+	}
+
+	var tools []Tool
+	err := q.Order("name").Find(&tools).Error
+	return tools, err
+}
+
+// Count returns the number of tools a tenant has registered
+func (r *GormRepository) Count(ctx context.Context, tenantID string) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&Tool{}).Where("tenant_id = ?", tenantID).Count(&count).Error
+	return int(count), err
+}
+
+// Update saves a tenant's tool and inserts its new version
+func (r *GormRepository) Update(ctx context.Context, t *Tool, v *Version) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Tool{}).
+			Where("tenant_id = ? AND id = ?", t.TenantID, t.ID).
+			Select("*").Omit("id", "tenant_id", "created_at").
+			Updates(t)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Create(v).Error
+	})
+}
+
+// Delete removes a tenant's tool and its versions
+func (r *GormRepository) Delete(ctx context.Context, tenantID, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&Tool{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Where("tenant_id = ? AND tool_id = ?", tenantID, id).Delete(&Version{}).Error
+	})
+}
+
+// GetVersion finds a version of a tenant's tool
+func (r *GormRepository) GetVersion(ctx context.Context, tenantID, id string, version int) (*Version, error) {
+	var v Version
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND tool_id = ? AND version = ?", tenantID, id, version).
+		First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListVersions returns the versions of a tenant's tool, newest first
+func (r *GormRepository) ListVersions(ctx context.Context, tenantID, id string) ([]Version, error) {
+	var versions []Version
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND tool_id = ?", tenantID, id).
+		Order("version DESC").
+		Find(&versions).Error
+	return versions, err
+}
+
+// Deprecate stores a version's deprecation and sunset times
+func (r *GormRepository) Deprecate(ctx context.Context, v *Version) error {
+	result := r.db.WithContext(ctx).Model(&Version{}).
+		Where("tenant_id = ? AND tool_id = ? AND version = ?", v.TenantID, v.ToolID, v.Version).
+		Updates(map[string]any{"deprecated_at": v.DeprecatedAt, "sunset_at": v.SunsetAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionNotFound
+	}
+	return nil
+}
+
+// RecordExecution inserts an audit record
+func (r *GormRepository) RecordExecution(ctx context.Context, e *Execution) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+// GetExecution finds a tenant's execution by ID
+func (r *GormRepository) GetExecution(ctx context.Context, tenantID, id string) (*Execution, error) {
+	var e Execution
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).First(&e).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListExecutions returns a tool's executions matching filter, newest first
+func (r *GormRepository) ListExecutions(ctx context.Context, tenantID, toolID string, filter ExecutionFilter) ([]Execution, error) {
+	q := r.db.WithContext(ctx).Where("tenant_id = ? AND tool_id = ?", tenantID, toolID)
+	if filter.Outcome != "" {
+		q = q.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.AgentID != "" {
+		q = q.Where("agent_id = ?", filter.AgentID)
+	}
+	if filter.Version > 0 {
+		q = q.Where("version = ?", filter.Version)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("created_at < ?", filter.To)
+	}
+
+	var executions []Execution
+	err := q.Order("created_at DESC").Limit(filter.Limit).Find(&executions).Error
+	return executions, err
+}
+
+// FindExecution finds an execution by ID in any tenant
+func (r *GormRepository) FindExecution(ctx context.Context, id string) (*Execution, error) {
+	var e Execution
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&e).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrExecutionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// CompleteExecution stores the result of an execution still pending
+func (r *GormRepository) CompleteExecution(ctx context.Context, e *Execution) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&Execution{}).
+		Where("id = ? AND outcome = ?", e.ID, OutcomePending).
+		Select("outcome", "error", "status_code", "latency_ms", "response", "completed_at").
+		Updates(e)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetAgentAccess finds an agent's tool allowlist
+func (r *GormRepository) GetAgentAccess(ctx context.Context, tenantID, agentID string) (*AgentAccess, error) {
+	var a AgentAccess
+	err := r.db.WithContext(ctx).Where("tenant_id = ? AND agent_id = ?", tenantID, agentID).First(&a).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAgentAccessNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// SaveAgentAccess inserts or replaces an agent's tool allowlist
+func (r *GormRepository) SaveAgentAccess(ctx context.Context, a *AgentAccess) error {
+	return r.db.WithContext(ctx).Save(a).Error
+}
@@ -0,0 +1,116 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// validateSchema checks data against the JSON Schema subset tools use: type,
+// enum, required, properties and items. Other keywords are ignored.
+func validateSchema(schema Schema, data []byte) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	var s map[string]any
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return checkSchema(s, v, "$")
+}
+
+func checkSchema(schema map[string]any, v any, path string) error {
+	if t, ok := schema["type"]; ok && !matchesType(t, v) {
+		return fmt.Errorf("%s must be of type %v", path, t)
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+			return fmt.Errorf("%s must be one of %v", path, enum)
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if key, _ := name.(string); key != "" {
+					if _, present := v[key]; !present {
+						return fmt.Errorf("%s.%s is required", path, key)
+					}
+				}
+			}
+		}
+		if properties, ok := schema["properties"].(map[string]any); ok {
+			for key, sub := range properties {
+				subSchema, ok := sub.(map[string]any)
+				field, present := v[key]
+				if !ok || !present {
+					continue
+				}
+				if err := checkSchema(subSchema, field, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := checkSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchesType reports whether v has the schema type, or one of a type list.
+func matchesType(t any, v any) bool {
+	switch t := t.(type) {
+	case string:
+		return isType(t, v)
+	case []any:
+		for _, name := range t {
+			if s, ok := name.(string); ok && isType(s, v) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func isType(name string, v any) bool {
+	switch name {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	default:
+		return true
+	}
+}
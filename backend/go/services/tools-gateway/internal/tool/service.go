@@ -0,0 +1,306 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// allowedMethods are the HTTP methods a tool may use.
+var allowedMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// DefaultMigrationWindow is how long a deprecated version keeps running when
+// no migration window is given.
+const DefaultMigrationWindow = 7 * 24 * time.Hour
+
+// Service manages and executes tools.
+type Service struct {
+	repo     Repository
+	executor *Executor
+	async    *AsyncConfig // nil until EnableAsync
+	notifier Notifier
+	now      func() time.Time
+}
+
+// NewService creates a Service.
+func NewService(repo Repository, executor *Executor) *Service {
+	return &Service{repo: repo, executor: executor, now: time.Now}
+}
+
+// Executor returns the executor tools run on.
+func (s *Service) Executor() *Executor {
+	return s.executor
+}
+
+// Create validates and stores a tool for t.TenantID as its version 1.
+func (s *Service) Create(ctx context.Context, t *Tool) error {
+	if err := s.validate(t); err != nil {
+		return err
+	}
+
+	t.Category = strings.ToLower(strings.TrimSpace(t.Category))
+	t.Tags = normalizeTags(t.Tags)
+	now := s.now().UTC()
+	t.ID = uuid.New().String()
+	t.Version = 1
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	return s.repo.Create(ctx, t, newVersion(t))
+}
+
+// Import creates a tool for tenantID from an operation of an OpenAPI 3 spec.
+func (s *Service) Import(ctx context.Context, tenantID string, req OpenAPIImport) (*Tool, error) {
+	t, err := FromOpenAPI(req)
+	if err != nil {
+		return nil, err
+	}
+	t.TenantID = tenantID
+	if err := s.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Get returns a tenant's tool at the given version, or at its latest version
+// when version is 0.
+func (s *Service) Get(ctx context.Context, tenantID, id string, version int) (*Tool, error) {
+	t, err := s.repo.Get(ctx, tenantID, id)
+	if err != nil || version == 0 || version == t.Version {
+		return t, err
+	}
+
+	v, err := s.repo.GetVersion(ctx, tenantID, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return t.at(v), nil
+}
+
+// Versions returns the versions of a tenant's tool, newest first.
+func (s *Service) Versions(ctx context.Context, tenantID, id string) ([]Version, error) {
+	if _, err := s.repo.Get(ctx, tenantID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListVersions(ctx, tenantID, id)
+}
+
+// Deprecate marks a version of a tenant's tool as deprecated. It keeps running
+// for the migration window, DefaultMigrationWindow when window is 0, and is
+// rejected after it. The latest version can't be deprecated.
+func (s *Service) Deprecate(ctx context.Context, tenantID, id string, version int, window time.Duration) (*Version, error) {
+	if window < 0 {
+		return nil, &ValidationError{Message: "migration window must not be negative"}
+	}
+	if window == 0 {
+		window = DefaultMigrationWindow
+	}
+
+	t, err := s.repo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if version == t.Version {
+		return nil, &ValidationError{Message: "the latest version can't be deprecated"}
+	}
+	v, err := s.repo.GetVersion(ctx, tenantID, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	if v.DeprecatedAt == nil {
+		v.DeprecatedAt = &now
+	}
+	sunset := now.Add(window)
+	v.SunsetAt = &sunset
+	if err := s.repo.Deprecate(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// List returns a tenant's tools matching filter.
+func (s *Service) List(ctx context.Context, tenantID string, filter ListFilter) ([]Tool, error) {
+	return s.repo.List(ctx, tenantID, filter)
+}
+
+// Count returns the number of tools a tenant has registered.
+func (s *Service) Count(ctx context.Context, tenantID string) (int, error) {
+	return s.repo.Count(ctx, tenantID)
+}
+
+// Update validates t and stores it as a new latest version of a tenant's
+// tool. Earlier versions are kept for executions pinned to them.
+func (s *Service) Update(ctx context.Context, t *Tool) error {
+	existing, err := s.repo.Get(ctx, t.TenantID, t.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validate(t); err != nil {
+		return err
+	}
+
+	t.Category = strings.ToLower(strings.TrimSpace(t.Category))
+	t.Tags = normalizeTags(t.Tags)
+	t.Version = existing.Version + 1
+	t.CreatedAt = existing.CreatedAt
+	t.UpdatedAt = s.now().UTC()
+	return s.repo.Update(ctx, t, newVersion(t))
+}
+
+// Delete removes a tenant's tool.
+func (s *Service) Delete(ctx context.Context, tenantID, id string) error {
+	return s.repo.Delete(ctx, tenantID, id)
+}
+
+// Execute runs a tenant's tool with input, pinned to the given version or to
+// the latest one when version is 0, on behalf of agentID. The definition is
+// resolved once, so an update while the call is in flight does not change it.
+// Every call of an existing tool is recorded in the audit log.
+func (s *Service) Execute(ctx context.Context, tenantID, id, agentID string, version int, input json.RawMessage) (*Result, error) {
+	t, err := s.Get(ctx, tenantID, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, t, agentID, "", input)
+}
+
+// Replay runs the input of a recorded execution again against the tool's
+// current version, for debugging. The replay is recorded as a new execution.
+func (s *Service) Replay(ctx context.Context, tenantID, executionID string) (*Result, error) {
+	e, err := s.repo.GetExecution(ctx, tenantID, executionID)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.Get(ctx, tenantID, e.ToolID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, t, e.AgentID, e.ID, json.RawMessage(e.Input))
+}
+
+// Executions returns a tenant's audit records for a tool, newest first.
+func (s *Service) Executions(ctx context.Context, tenantID, toolID string, filter ExecutionFilter) ([]Execution, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	filter.Limit = min(filter.Limit, MaxExecutionsLimit)
+	return s.repo.ListExecutions(ctx, tenantID, toolID, filter)
+}
+
+// run executes t and records the execution.
+func (s *Service) run(ctx context.Context, t *Tool, agentID, replayOf string, input json.RawMessage) (*Result, error) {
+	e := s.newExecution(t, agentID, replayOf, input)
+
+	var result *Result
+	var err error
+	if err = s.runnable(t); err == nil {
+		result, err = s.executor.Execute(ctx, t, input)
+	}
+
+	e.Outcome = outcomeOf(err)
+	if err != nil {
+		e.Error = err.Error()
+	} else {
+		e.StatusCode = result.StatusCode
+		e.LatencyMs = result.LatencyMs
+	}
+	// The call already happened; a lost audit record must not hide its result
+	if auditErr := s.repo.RecordExecution(context.WithoutCancel(ctx), e); auditErr != nil {
+		log.Printf("Failed to record execution %s of tool %s: %v", e.ID, t.ID, auditErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.ExecutionID = e.ID
+	result.Version = t.Version
+	result.Deprecated = t.DeprecatedAt != nil
+	return result, nil
+}
+
+// newExecution starts the audit record of a call of t.
+func (s *Service) newExecution(t *Tool, agentID, replayOf string, input json.RawMessage) *Execution {
+	return &Execution{
+		ID:        uuid.New().String(),
+		TenantID:  t.TenantID,
+		ToolID:    t.ID,
+		Version:   t.Version,
+		AgentID:   agentID,
+		ReplayOf:  replayOf,
+		Method:    t.Method,
+		URL:       redactURL(t.URL),
+		Headers:   redactHeaders(t.Headers),
+		Input:     Schema(input),
+		CreatedAt: s.now().UTC(),
+	}
+}
+
+// runnable returns ErrVersionRetired once t's version is past its sunset.
+func (s *Service) runnable(t *Tool) error {
+	if t.SunsetAt != nil && !t.SunsetAt.After(s.now()) {
+		return ErrVersionRetired
+	}
+	return nil
+}
+
+// newVersion snapshots t's definition as its current version.
+func newVersion(t *Tool) *Version {
+	return &Version{
+		ToolID:     t.ID,
+		Version:    t.Version,
+		TenantID:   t.TenantID,
+		Definition: t.Definition,
+		CreatedAt:  t.UpdatedAt,
+	}
+}
+
+func (s *Service) validate(t *Tool) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return &ValidationError{Message: "name is required"}
+	}
+	t.Method = strings.ToUpper(t.Method)
+	if !allowedMethods[t.Method] {
+		return &ValidationError{Message: "method must be one of GET, POST, PUT, PATCH or DELETE"}
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &ValidationError{Message: "url must be an absolute http or https URL"}
+	}
+	for name, in := range t.Params {
+		if in != ParamPath && in != ParamQuery && in != ParamHeader {
+			return &ValidationError{Message: fmt.Sprintf("param %q must be sent in path, query or header", name)}
+		}
+	}
+	if t.TimeoutMs < 0 {
+		return &ValidationError{Message: "timeout_ms must not be negative"}
+	}
+	if t.MaxResponseBytes < 0 {
+		return &ValidationError{Message: "max_response_bytes must not be negative"}
+	}
+	if len(t.InputSchema) > 0 && !json.Valid(t.InputSchema) {
+		return &ValidationError{Message: "input_schema must be valid JSON"}
+	}
+	if len(t.OutputSchema) > 0 && !json.Valid(t.OutputSchema) {
+		return &ValidationError{Message: "output_schema must be valid JSON"}
+	}
+	if t.ResponseMapping != nil {
+		if err := t.ResponseMapping.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
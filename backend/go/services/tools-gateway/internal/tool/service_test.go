@@ -0,0 +1,359 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memRepository is an in-memory Repository.
+type memRepository struct {
+	mu         sync.Mutex
+	tools      map[string]Tool
+	versions   map[string][]Version
+	executions []Execution
+	access     map[string]AgentAccess
+}
+
+func newMemRepository() *memRepository {
+	return &memRepository{
+		tools:    make(map[string]Tool),
+		versions: make(map[string][]Version),
+		access:   make(map[string]AgentAccess),
+	}
+}
+
+func (r *memRepository) Create(_ context.Context, t *Tool, v *Version) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tools[t.ID] = *t
+	r.versions[t.ID] = []Version{*v}
+	return nil
+}
+
+func (r *memRepository) Get(_ context.Context, tenantID, id string) (*Tool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tools[id]
+	if !ok || t.TenantID != tenantID {
+		return nil, ErrNotFound
+	}
+	return &t, nil
+}
+
+func (r *memRepository) List(_ context.Context, tenantID string, filter ListFilter) ([]Tool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Tool
+	for _, t := range r.tools {
+		if t.TenantID == tenantID && filter.matches(&t) {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (r *memRepository) Count(ctx context.Context, tenantID string) (int, error) {
+	tools, err := r.List(ctx, tenantID, ListFilter{})
+	return len(tools), err
+}
+
+func (r *memRepository) Update(_ context.Context, t *Tool, v *Version) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.tools[t.ID]
+	if !ok || existing.TenantID != t.TenantID {
+		return ErrNotFound
+	}
+	for _, other := range r.versions[t.ID] {
+		if other.Version == v.Version {
+			return errors.New("duplicate version")
+		}
+	}
+	r.tools[t.ID] = *t
+	r.versions[t.ID] = append(r.versions[t.ID], *v)
+	return nil
+}
+
+func (r *memRepository) Delete(_ context.Context, tenantID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tools[id]
+	if !ok || t.TenantID != tenantID {
+		return ErrNotFound
+	}
+	delete(r.tools, id)
+	delete(r.versions, id)
+	return nil
+}
+
+func (r *memRepository) GetVersion(_ context.Context, tenantID, id string, version int) (*Version, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range r.versions[id] {
+		if v.TenantID == tenantID && v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+func (r *memRepository) ListVersions(_ context.Context, tenantID, id string) ([]Version, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Version
+	for _, v := range r.versions[id] {
+		if v.TenantID == tenantID {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version > out[j].Version })
+	return out, nil
+}
+
+func (r *memRepository) Deprecate(_ context.Context, v *Version) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := r.versions[v.ToolID]
+	for i := range versions {
+		if versions[i].TenantID == v.TenantID && versions[i].Version == v.Version {
+			versions[i].DeprecatedAt = v.DeprecatedAt
+			versions[i].SunsetAt = v.SunsetAt
+			return nil
+		}
+	}
+	return ErrVersionNotFound
+}
+
+func (r *memRepository) RecordExecution(_ context.Context, e *Execution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.executions = append(r.executions, *e)
+	return nil
+}
+
+func (r *memRepository) GetExecution(_ context.Context, tenantID, id string) (*Execution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.executions {
+		if e.TenantID == tenantID && e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, ErrExecutionNotFound
+}
+
+func (r *memRepository) ListExecutions(_ context.Context, tenantID, toolID string, filter ExecutionFilter) ([]Execution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Execution
+	for i := len(r.executions) - 1; i >= 0 && len(out) < filter.Limit; i-- {
+		e := r.executions[i]
+		if e.TenantID != tenantID || e.ToolID != toolID ||
+			(filter.Outcome != "" && e.Outcome != filter.Outcome) ||
+			(filter.AgentID != "" && e.AgentID != filter.AgentID) ||
+			(filter.Version > 0 && e.Version != filter.Version) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (r *memRepository) FindExecution(_ context.Context, id string) (*Execution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.executions {
+		if e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, ErrExecutionNotFound
+}
+
+func (r *memRepository) CompleteExecution(_ context.Context, e *Execution) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.executions {
+		if existing.ID == e.ID && existing.Outcome == OutcomePending {
+			r.executions[i] = *e
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memRepository) GetAgentAccess(_ context.Context, tenantID, agentID string) (*AgentAccess, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a, ok := r.access[tenantID+"/"+agentID]
+	if !ok {
+		return nil, ErrAgentAccessNotFound
+	}
+	return &a, nil
+}
+
+func (r *memRepository) SaveAgentAccess(_ context.Context, a *AgentAccess) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.access[a.TenantID+"/"+a.AgentID] = *a
+	return nil
+}
+
+// createStubTool registers a tool calling url for tenant-1.
+func createStubTool(t *testing.T, s *Service, url string) *Tool {
+	t.Helper()
+
+	tool := &Tool{TenantID: "tenant-1", Name: "stub", Definition: Definition{Method: http.MethodPost, URL: url}}
+	if err := s.Create(context.Background(), tool); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	return tool
+}
+
+// updateStubTool points the tool at url as a new version.
+func updateStubTool(t *testing.T, s *Service, tool *Tool, url string) {
+	t.Helper()
+
+	next := &Tool{ID: tool.ID, TenantID: tool.TenantID, Name: tool.Name, Definition: Definition{Method: http.MethodPost, URL: url}}
+	if err := s.Update(context.Background(), next); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+}
+
+func TestUpdate_CreatesVersion(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+
+	tool := createStubTool(t, s, "http://v1.example.com")
+	updateStubTool(t, s, tool, "http://v2.example.com")
+
+	latest, err := s.Get(ctx, "tenant-1", tool.ID, 0)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if latest.Version != 2 || latest.URL != "http://v2.example.com" {
+		t.Errorf("Expected version 2 as latest, got %d %s", latest.Version, latest.URL)
+	}
+
+	first, err := s.Get(ctx, "tenant-1", tool.ID, 1)
+	if err != nil {
+		t.Fatalf("Get of version 1 failed: %v", err)
+	}
+	if first.Version != 1 || first.URL != "http://v1.example.com" {
+		t.Errorf("Expected the version 1 definition, got %d %s", first.Version, first.URL)
+	}
+
+	versions, err := s.Versions(ctx, "tenant-1", tool.ID)
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 1 {
+		t.Errorf("Expected versions 2 and 1, got %+v", versions)
+	}
+
+	if _, err := s.Get(ctx, "tenant-1", tool.ID, 3); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Expected ErrVersionNotFound, got %v", err)
+	}
+}
+
+func TestExecute_InFlightKeepsPinnedVersion(t *testing.T) {
+	ctx := context.Background()
+
+	called := make(chan struct{})
+	release := make(chan struct{})
+	v1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(called)
+		<-release
+		w.Write([]byte(`{"version":1}`))
+	}))
+	defer v1.Close()
+	v2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":2}`))
+	}))
+	defer v2.Close()
+
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+	tool := createStubTool(t, s, v1.URL)
+
+	type outcome struct {
+		result *Result
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := s.Execute(ctx, "tenant-1", tool.ID, "", 1, nil)
+		done <- outcome{result, err}
+	}()
+
+	// Update while the version 1 call is in flight
+	<-called
+	updateStubTool(t, s, tool, v2.URL)
+	close(release)
+
+	got := <-done
+	if got.err != nil {
+		t.Fatalf("Execute failed: %v", got.err)
+	}
+	if got.result.Version != 1 || string(got.result.Response) != `{"version":1}` {
+		t.Errorf("Expected the in-flight call to finish on version 1, got %d %s", got.result.Version, got.result.Response)
+	}
+
+	// New executions can pin the updated version
+	pinned, err := s.Execute(ctx, "tenant-1", tool.ID, "", 2, nil)
+	if err != nil {
+		t.Fatalf("Execute of version 2 failed: %v", err)
+	}
+	if pinned.Version != 2 || string(pinned.Response) != `{"version":2}` {
+		t.Errorf("Expected version 2, got %d %s", pinned.Version, pinned.Response)
+	}
+}
+
+func TestDeprecate_MigrationWindow(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewService(newMemRepository(), NewExecutor(nil, Limits{}))
+	s.now = func() time.Time { return now }
+
+	tool := createStubTool(t, s, srv.URL)
+	if _, err := s.Deprecate(ctx, "tenant-1", tool.ID, 1, time.Hour); err == nil {
+		t.Fatal("Expected the latest version not to be deprecated")
+	}
+	updateStubTool(t, s, tool, srv.URL)
+
+	v, err := s.Deprecate(ctx, "tenant-1", tool.ID, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("Deprecate failed: %v", err)
+	}
+	if !v.SunsetAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected sunset at %v, got %v", now.Add(time.Hour), v.SunsetAt)
+	}
+
+	// Within the migration window the version still runs, flagged as deprecated
+	result, err := s.Execute(ctx, "tenant-1", tool.ID, "", 1, nil)
+	if err != nil {
+		t.Fatalf("Execute within the migration window failed: %v", err)
+	}
+	if !result.Deprecated {
+		t.Error("Expected the result to flag the deprecated version")
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := s.Execute(ctx, "tenant-1", tool.ID, "", 1, nil); !errors.Is(err, ErrVersionRetired) {
+		t.Fatalf("Expected ErrVersionRetired after the window, got %v", err)
+	}
+	if _, err := s.Execute(ctx, "tenant-1", tool.ID, "", 0, nil); err != nil {
+		t.Fatalf("Expected the latest version to keep running: %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+// Package tool stores the external APIs a tenant registers as tools and
+// executes them within request size, response size and time limits.
+package tool
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tool is an external API an agent can call. Its definition is the one of
+// its latest version.
+type Tool struct {
+	ID          string `gorm:"type:uuid;primary_key" json:"id"`
+	TenantID    string `gorm:"not null;index" json:"tenant_id"`
+	Name        string `gorm:"not null" json:"name"`
+	Description string `gorm:"not null;default:''" json:"description,omitempty"`
+	Category    string `gorm:"not null;default:'';index" json:"category,omitempty"`
+	Tags        Tags   `gorm:"type:jsonb" json:"tags,omitempty"`
+	Version     int    `gorm:"not null;default:1" json:"version"`
+	Definition
+	DeprecatedAt *time.Time `gorm:"-" json:"deprecated_at,omitempty"` // set when a deprecated version is returned
+	SunsetAt     *time.Time `gorm:"-" json:"sunset_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Definition is how a tool is called. It is immutable within a version.
+type Definition struct {
+	Method           string  `gorm:"not null" json:"method"`
+	URL              string  `gorm:"not null" json:"url"`
+	Headers          Headers `gorm:"type:jsonb" json:"headers,omitempty"`
+	Params           Params  `gorm:"type:jsonb" json:"params,omitempty"` // input fields sent outside the body
+	InputSchema      Schema  `gorm:"type:jsonb" json:"input_schema,omitempty"`
+	OutputSchema     Schema  `gorm:"type:jsonb" json:"output_schema,omitempty"`
+	TimeoutMs        int     `gorm:"not null;default:0" json:"timeout_ms,omitempty"`         // 0 uses the gateway default
+	MaxResponseBytes int64   `gorm:"not null;default:0" json:"max_response_bytes,omitempty"` // 0 uses the gateway default
+
+	ResponseMapping *ResponseMapping `gorm:"type:jsonb" json:"response_mapping,omitempty"` // applied to 2xx responses
+}
+
+// TableName specifies the table name
+func (Tool) TableName() string {
+	return "tools"
+}
+
+// Version is a snapshot of a tool's definition. Every update creates a new
+// version, so executions pinned to an older one keep their behavior.
+type Version struct {
+	ToolID   string `gorm:"type:uuid;primaryKey" json:"tool_id"`
+	Version  int    `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	TenantID string `gorm:"not null;index" json:"tenant_id"`
+	Definition
+	DeprecatedAt *time.Time `json:"deprecated_at,omitempty"`
+	SunsetAt     *time.Time `json:"sunset_at,omitempty"` // end of the migration window; the version can't run after it
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name
+func (Version) TableName() string {
+	return "tool_versions"
+}
+
+// at returns t as it was at version v.
+func (t Tool) at(v *Version) *Tool {
+	t.Version = v.Version
+	t.Definition = v.Definition
+	t.DeprecatedAt = v.DeprecatedAt
+	t.SunsetAt = v.SunsetAt
+	return &t
+}
+
+// Headers are the HTTP headers sent with every call, stored as JSONB.
+type Headers map[string]string
+
+// Value implements driver.Valuer.
+func (h Headers) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(h)
+}
+
+// Scan implements sql.Scanner.
+func (h *Headers) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil || data == nil {
+		*h = nil
+		return err
+	}
+	return json.Unmarshal(data, h)
+}
+
+// Parameter locations of input fields sent outside the request body.
+const (
+	ParamPath   = "path"
+	ParamQuery  = "query"
+	ParamHeader = "header"
+)
+
+// Params maps input fields to the parameter location they are sent in, stored
+// as JSONB. When a tool has params, its body is the input's "body" field;
+// otherwise the whole input is the body.
+type Params map[string]string
+
+// Value implements driver.Valuer.
+func (p Params) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner.
+func (p *Params) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil || data == nil {
+		*p = nil
+		return err
+	}
+	return json.Unmarshal(data, p)
+}
+
+// Schema is a JSON schema, stored as JSONB.
+type Schema json.RawMessage
+
+// MarshalJSON implements json.Marshaler.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if len(s) == 0 {
+		return []byte("null"), nil
+	}
+	return s, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	*s = append((*s)[:0], data...)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (s Schema) Value() (driver.Value, error) {
+	if len(s) == 0 {
+		return []byte(`{"type":"object"}`), nil
+	}
+	return []byte(s), nil
+}
+
+// Scan implements sql.Scanner.
+func (s *Schema) Scan(src any) error {
+	data, err := scanBytes(src)
+	if err != nil {
+		return err
+	}
+	*s = append((*s)[:0], data...)
+	return nil
+}
+
+func scanBytes(src any) ([]byte, error) {
+	switch v := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("cannot scan %T into a JSON column", src)
+	}
+}
+
+// Result is the outcome of executing a tool.
+type Result struct {
+	ExecutionID string          `json:"execution_id"` // audit record of the call
+	ToolID      string          `json:"tool_id"`
+	Version     int             `json:"version"`
+	Deprecated  bool            `json:"deprecated,omitempty"` // the version is in its migration window
+	StatusCode  int             `json:"status_code"`
+	Response    json.RawMessage `json:"response"`
+	LatencyMs   int64           `json:"latency_ms"`
+
+	location string // Location header, where an async tool reports progress
+}
+
+// Errors returned by the service.
+var (
+	ErrNotFound         = errors.New("tool not found")
+	ErrVersionNotFound  = errors.New("tool version not found")
+	ErrVersionRetired   = errors.New("tool version is past its migration window")
+	ErrInputTooLarge    = errors.New("tool input exceeds the maximum size")
+	ErrResponseTooLarge = errors.New("tool response exceeds the maximum size")
+	ErrTimeout          = errors.New("tool did not respond in time")
+)
+
+// ValidationError reports an invalid tool.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Repository persists tools and their versions. Every method is scoped by
+// tenant ID; a tool of another tenant is reported as ErrNotFound.
+type Repository interface {
+	// Create inserts a tool together with its first version.
+	Create(ctx context.Context, t *Tool, v *Version) error
+	Get(ctx context.Context, tenantID, id string) (*Tool, error)
+	// List returns the tenant's tools matching filter, by name.
+	List(ctx context.Context, tenantID string, filter ListFilter) ([]Tool, error)
+	Count(ctx context.Context, tenantID string) (int, error)
+	// Update saves a tool and inserts its new latest version. It fails if
+	// the version already exists, so concurrent updates can't both win.
+	Update(ctx context.Context, t *Tool, v *Version) error
+	// Delete removes a tool and all its versions.
+	Delete(ctx context.Context, tenantID, id string) error
+	GetVersion(ctx context.Context, tenantID, id string, version int) (*Version, error)
+	ListVersions(ctx context.Context, tenantID, id string) ([]Version, error)
+	// Deprecate stores a version's deprecation and sunset times.
+	Deprecate(ctx context.Context, v *Version) error
+
+	// RecordExecution appends an execution to the audit log.
+	RecordExecution(ctx context.Context, e *Execution) error
+	GetExecution(ctx context.Context, tenantID, id string) (*Execution, error)
+	// ListExecutions returns a tool's executions matching filter, newest first.
+	ListExecutions(ctx context.Context, tenantID, toolID string, filter ExecutionFilter) ([]Execution, error)
+	// FindExecution looks an execution up by ID alone, for callbacks from
+	// tool APIs that carry no tenant; the callback token authenticates them.
+	FindExecution(ctx context.Context, id string) (*Execution, error)
+	// CompleteExecution stores the result of a pending execution. It reports
+	// false when the execution was no longer pending.
+	CompleteExecution(ctx context.Context, e *Execution) (bool, error)
+
+	// GetAgentAccess returns ErrAgentAccessNotFound for an agent without an allowlist.
+	GetAgentAccess(ctx context.Context, tenantID, agentID string) (*AgentAccess, error)
+	SaveAgentAccess(ctx context.Context, a *AgentAccess) error
+}
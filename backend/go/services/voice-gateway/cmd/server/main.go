@@ -10,10 +10,40 @@ import (
 	"syscall"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	obsconfig "github.com/serphona/backend/go/libs/platform-observability/config"
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+	authclient "github.com/serphona/serphona/backend/go/libs/platform-auth/client"
+	authjwt "github.com/serphona/serphona/backend/go/libs/platform-auth/jwt"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/health"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loadshed"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loglevel"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/shutdown"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"voice-gateway/internal/adapter/agent"
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/events"
+	httpadapter "voice-gateway/internal/adapter/http"
+	"voice-gateway/internal/adapter/metrics"
+	redisadapter "voice-gateway/internal/adapter/redis"
+	"voice-gateway/internal/adapter/storage"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/adapter/tts"
+	"voice-gateway/internal/application/audio"
+	callservice "voice-gateway/internal/application/call"
+	conversationservice "voice-gateway/internal/application/conversation"
+	queueservice "voice-gateway/internal/application/queue"
+	routingservice "voice-gateway/internal/application/routing"
+	voicemailservice "voice-gateway/internal/application/voicemail"
 	"voice-gateway/internal/config"
+	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/cdr"
 )
 
 func main() {
@@ -25,7 +55,7 @@ func main() {
 	}
 
 	// Initialize logger
-	log, err := initLogger(cfg.LogLevel, cfg.Environment)
+	log, logLevel, err := initLogger(cfg.LogLevel, cfg.Environment)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -41,30 +71,289 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Distributed tracing
+	shutdownTracing, err := tracing.Setup(ctx, &obsconfig.Config{
+		ServiceName:     cfg.ServiceName,
+		ServiceVersion:  cfg.Version,
+		Environment:     cfg.Environment,
+		TracingEnabled:  cfg.Tracing.Enabled,
+		TracingEndpoint: cfg.Tracing.Endpoint,
+		TracingSampler:  cfg.Tracing.Sampler,
+	})
+	if err != nil {
+		log.Fatal("failed to initialize tracing", zap.Error(err))
+	}
+
+	// Resources closed in order on shutdown
+	closers := shutdown.NewRegistry()
+	closers.OnClose = func(phase shutdown.Phase, name string, err error) {
+		if err != nil {
+			log.Error("shutdown error", zap.Stringer("phase", phase), zap.String("resource", name), zap.Error(err))
+			return
+		}
+		log.Info("closed", zap.Stringer("phase", phase), zap.String("resource", name))
+	}
+	closers.Register(shutdown.PhaseTelemetry, "tracing", shutdown.Func(shutdownTracing))
+
+	// Kafka producer for events
+	var topics events.TopicMapper
+	if len(cfg.Kafka.TopicGroups) > 0 {
+		topics = events.GroupedTopics(cfg.Kafka.TopicPrefix, cfg.Kafka.TopicGroups)
+	}
+	eventPublisher, err := events.NewPublisher(cfg.Kafka.Brokers, cfg.Kafka.TopicPrefix, events.PublisherOptions{
+		Compression:     cfg.Kafka.Compression,
+		MaxMessageBytes: cfg.Kafka.MaxMessageBytes,
+		Topics:          topics,
+	}, log)
+	if err != nil {
+		log.Fatal("failed to create event publisher", zap.Error(err))
+	}
+	closers.RegisterCloser(shutdown.PhasePublishers, "kafka-publisher", eventPublisher)
+
+	// Call state in Redis behind a circuit breaker; with the in-memory
+	// fallback, calls in progress survive a brief Redis outage
+	redisClient, err := redisadapter.NewClient(ctx, cfg.Redis.URL, cfg.Redis.Password, cfg.Redis.DB, log)
+	if err != nil {
+		log.Fatal("failed to connect to redis", zap.Error(err))
+	}
+	closers.RegisterCloser(shutdown.PhaseStorage, "redis", redisClient)
+
+	callState := redisadapter.NewResilientCallStateRepository(
+		redisadapter.NewCallStateRepository(redisClient, cfg.Redis.CallStateTTL),
+		redisadapter.ResilienceOptions{
+			FailureThreshold: cfg.Redis.BreakerFailureThreshold,
+			Cooldown:         cfg.Redis.BreakerCooldown,
+			Fallback:         cfg.Redis.CallStateFallback,
+			TTL:              cfg.Redis.CallStateTTL,
+		},
+		log,
+	)
+	callState.SetStateChangeHandler(func(ctx context.Context, change redisadapter.StateChange) {
+		go publishStateChange(context.WithoutCancel(ctx), eventPublisher, change, log)
+	})
+
+	// Readiness checks: calls cannot be answered without Asterisk or tenant
+	// configuration; without the agent, turns fall back to the canned message.
+	// Redis is only critical without the in-memory fallback.
+	var redisCheckOpts []health.Option
+	if !cfg.Redis.CallStateFallback {
+		redisCheckOpts = append(redisCheckOpts, health.Critical())
+	}
+	checker := health.NewChecker(cfg.ServiceName)
+	checker.Register("asterisk-ari", health.HTTPCheck(nil, cfg.Asterisk.ARIURL), health.Critical())
+	checker.Register("tenant-manager", health.HTTPCheck(nil, cfg.TenantManager.URL+"/health/live"), health.Critical())
+	checker.Register("agent-orchestrator", health.HTTPCheck(nil, cfg.AgentOrchestrator.URL+"/health"))
+	checker.Register("kafka", health.DialCheck(cfg.Kafka.Brokers...))
+	checker.Register("redis", callState.Check, redisCheckOpts...)
+
+	// Clients
+	ariClient := asterisk.NewARIClient(cfg.Asterisk.ARIURL, cfg.Asterisk.ARIUsername, cfg.Asterisk.ARIPassword, cfg.Asterisk.ARIAppName, log)
+	tenantClient := tenant.NewClient(cfg.TenantManager.URL, log)
+	agentClient := agent.NewClient(cfg.AgentOrchestrator.URL, log)
+	agentClient.SetTokenSource(authclient.NewServiceTokenSource(
+		cfg.AgentOrchestrator.AuthURL,
+		cfg.AgentOrchestrator.ClientID,
+		cfg.AgentOrchestrator.ClientSecret,
+		"agent-orchestrator:sessions.write",
+		"agent-orchestrator:turns.write",
+	))
+
+	// Feature flags: tenant settings override the instance-wide defaults
+	featureFlags := tenant.NewFeatureFlagResolver(tenantClient, tenant.FeatureFlags{
+		CallRecording:        cfg.FeatureFlags.EnableCallRecording,
+		TranscriptionStorage: cfg.FeatureFlags.EnableTranscriptionStorage,
+		AudioStreaming:       cfg.FeatureFlags.EnableAudioStreaming,
+		JitterBufferDepth:    cfg.Audio.JitterBufferDepth,
+	}, cfg.TenantManager.FeatureFlagsCacheTTL, log)
+
+	// Agent configs are cached and evicted as soon as tenant-manager reports
+	// an update; the TTL bounds staleness if an update event is missed
+	agentConfigs := tenant.NewAgentConfigCache(tenantClient, cfg.TenantManager.AgentConfigCacheTTL, log)
+	configConsumer, err := events.NewConfigConsumer(cfg.Kafka.Brokers, cfg.Kafka.GroupID, cfg.Kafka.TopicPrefix, agentConfigs, log)
+	if err != nil {
+		log.Warn("agent config updates unavailable, relying on cache TTL", zap.Error(err))
+	} else {
+		configConsumer.Start()
+		closers.RegisterCloser(shutdown.PhaseConsumers, "agent-config-consumer", configConsumer)
+	}
+
 	// TODO: Initialize components
-	// - Redis client for call state
-	// - Kafka producer for events
-	// - Asterisk ARI client
-	// - Tenant manager client
-	// - Agent orchestrator client
+	// - Asterisk AMI client (fallback, when cfg.FeatureFlags.EnableAMIFallback)
 	// - STT/TTS providers
-	// - Call service
 	// - Conversation manager
+	sttProviders := map[string]stt.Provider{}
+	ttsProviders := map[string]tts.Provider{}
+
+	// Call service
+	callService := callservice.NewService(ariClient, callState, eventPublisher, sttProviders, ttsProviders,
+		cfg.Call.MaxConcurrentCalls,
+		call.QualityThresholds{
+			MinMOS:           cfg.CallQuality.MinMOS,
+			MaxJitterMs:      cfg.CallQuality.MaxJitterMs,
+			MaxPacketLossPct: cfg.CallQuality.MaxPacketLossPct,
+			MaxLatencyMs:     cfg.CallQuality.MaxLatencyMs,
+		},
+		log,
+	)
+	callService.SetConversation(agentClient, callservice.ProviderTimeouts{
+		STT:   cfg.ProviderTimeout.STT,
+		TTS:   cfg.ProviderTimeout.TTS,
+		Agent: cfg.ProviderTimeout.Agent,
+	}, cfg.ProviderTimeout.FallbackMessage)
+	callService.SetFallback(tenantClient, callservice.FallbackPolicy{
+		Retries:       cfg.ProviderTimeout.FallbackRetries,
+		TransferQueue: cfg.ProviderTimeout.FallbackTransferQueue,
+	})
+	callService.SetTurnLatency(eventPublisher, cfg.Call.TurnLatencyBudget)
+	callService.SetFeatureFlags(featureFlags)
+	callService.SetMonitoring(ariClient)
+	callService.SetTransfers(ariClient, cfg.Asterisk.TransferEndpoint)
+	defaultCodec, err := audio.ParseCodec(cfg.Audio.DefaultCodec)
+	if err != nil {
+		log.Fatal("invalid default audio codec", zap.String("codec", cfg.Audio.DefaultCodec), zap.Error(err))
+	}
+	callService.SetCodecDetection(ariClient, defaultCodec)
+	callService.SetJitterBuffer(callservice.JitterBufferConfig{
+		FrameSize:     cfg.Audio.FrameSize,
+		FrameDuration: cfg.Audio.FrameDuration,
+		Depth:         cfg.Audio.JitterBufferDepth,
+	})
+	if cfg.Greeting.Enabled {
+		callService.SetGreeting(agentConfigs, tts.NewCache(cfg.Greeting.CacheSize), cfg.Greeting.MediaBaseURL)
+	}
+
+	// Conversations still open on shutdown are ended before the publisher flushes
+	conversationManager := conversationservice.NewManager(eventPublisher, log)
+	closers.Register(shutdown.PhaseConsumers, "conversations", conversationManager.Shutdown)
+	callService.SetConversationManager(conversationManager, cfg.Call.MaxConversationTurns, cfg.Call.MaxTurnsPrompt)
+	callService.SetCustomerResolver(tenantClient)
+	callService.SetBusinessContext(tenantClient)
+	callService.SetNumberRegion(cfg.Call.NumberRegion)
+	callService.SetDestinationPolicy(tenantClient)
+	callService.SetCallerIDs(tenantClient)
+
+	// Call detail records, billed by the tenant's rounding rule
+	if cfg.CDR.Enabled {
+		defaultRounding, err := cdr.ParseRounding(cfg.CDR.DefaultRounding)
+		if err != nil {
+			log.Fatal("invalid default cdr rounding", zap.String("rounding", cfg.CDR.DefaultRounding), zap.Error(err))
+		}
+		callService.SetCDRs(redisadapter.NewCDRRepository(redisClient, cfg.CDR.Retention), eventPublisher, tenantClient, defaultRounding)
+	}
+
+	// Hold queue for calls received while at capacity
+	if cfg.Queue.Enabled {
+		queueManager := queueservice.NewManager(ariClient, eventPublisher, cfg.Queue.MaxWait, cfg.Queue.AnnounceInterval, cfg.Queue.MOHClass, log)
+		callService.SetQueue(queueManager)
+		go queueManager.Run(ctx)
+	}
+
+	// Voicemail, stored in the region of tenants with data residency
+	regionStores := make(map[residency.Region]*storage.FileStore)
+	for name, path := range cfg.Voicemail.RegionStoragePaths {
+		region, err := residency.Parse(name)
+		if err != nil || region == residency.RegionUnset {
+			log.Fatal("invalid voicemail storage region", zap.String("region", name), zap.Error(err))
+		}
+		regionStores[region] = storage.NewFileStore(path)
+	}
+	voicemailService := voicemailservice.NewService(
+		ariClient,
+		tenantClient,
+		redisadapter.NewCallStateRepository(redisClient, cfg.Redis.CallStateTTL),
+		redisadapter.NewVoicemailRepository(redisClient, cfg.Voicemail.Retention),
+		storage.NewRegionalStore(storage.NewFileStore(cfg.Voicemail.StoragePath), regionStores),
+		eventPublisher,
+		nil,
+		voicemailservice.Config{
+			Prompt:      cfg.Voicemail.Prompt,
+			Format:      cfg.Voicemail.Format,
+			MaxDuration: cfg.Voicemail.MaxDuration,
+			MaxSilence:  cfg.Voicemail.MaxSilence,
+			Language:    cfg.Voicemail.Language,
+			SampleRate:  cfg.Audio.SampleRate,
+		},
+		log,
+	)
+	voicemailService.SetFeatureFlags(featureFlags)
+	var redactionSealer *redact.Sealer
+	if cfg.Redaction.Key != "" {
+		if redactionSealer, err = redact.NewSealer(cfg.Redaction.Key); err != nil {
+			log.Fatal("invalid redaction key", zap.Error(err))
+		}
+	}
+	voicemailService.SetRedaction(tenantClient, redact.New(nil), redactionSealer)
+	callService.SetVoicemail(voicemailService)
+
+	routingService := routingservice.NewService(tenantClient, eventPublisher, log)
+	eventDedup := redisadapter.NewEventDeduplicator(redisClient, cfg.Redis.EventDedupTTL)
 
 	// Metrics server (separate port for Prometheus scraping)
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle(cfg.Metrics.Path, promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
 	metricsServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Metrics.Port),
-		Handler: promhttp.Handler(),
+		Handler: metricsMux,
+	}
+
+	// Overloaded replicas refuse new API work instead of slowing every call
+	shedder := httpadapter.NewShedder(loadShedConfig(cfg.LoadShed), loadshed.WithShedHandler(func(r *http.Request, reason loadshed.Reason) {
+		metrics.IncRequestShed(reason)
+	}))
+	metrics.ObserveLoadShedder(shedder)
+
+	// Management API tokens, issued by auth-gateway
+	tokenVerifier, err := newTokenVerifier(cfg.Auth)
+	if err != nil {
+		log.Fatal("failed to create token verifier", zap.Error(err))
+	}
+
+	// Suspending a tenant blocks its users' tokens within the cache TTL
+	var tenantStatus *tenantstatus.Cache
+	if cfg.TenantManager.StatusCheck {
+		tenantStatus = tenantstatus.NewCache(tenantClient, tenantstatus.Config{
+			TTL:      cfg.TenantManager.StatusCacheTTL,
+			FailOpen: cfg.TenantManager.StatusFailOpen,
+		})
 	}
 
 	// HTTP server for management API
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler:      buildHTTPRouter(log),
+		Handler:      httpadapter.NewRouter(callService, routingService, voicemailService, eventDedup, checker, loglevel.New(logLevel, log), shedder, bodylimit.New(bodyLimitConfig(cfg.Server)), tokenVerifier, tenantStatus, cfg.Asterisk.WebhookSecret, log),
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
+	closers.Register(shutdown.PhaseServers, "metrics-server", metricsServer.Shutdown)
+	closers.Register(shutdown.PhaseServers, "http-server", httpServer.Shutdown)
+
+	// SIGHUP re-reads the configuration (CONFIG_FILE, then the environment)
+	// and applies what is safe to change without dropping calls
+	reloader := config.NewReloader(cfg, logLevel, log)
+	reloader.OnReload(func(next *config.Config) {
+		featureFlags.SetDefaults(tenant.FeatureFlags{
+			CallRecording:        next.FeatureFlags.EnableCallRecording,
+			TranscriptionStorage: next.FeatureFlags.EnableTranscriptionStorage,
+			AudioStreaming:       next.FeatureFlags.EnableAudioStreaming,
+			JitterBufferDepth:    next.Audio.JitterBufferDepth,
+		})
+		callService.SetMaxConcurrentCalls(next.Call.MaxConcurrentCalls)
+		shedder.SetConfig(loadShedConfig(next.LoadShed))
+		callService.SetProviderTimeouts(callservice.ProviderTimeouts{
+			STT:   next.ProviderTimeout.STT,
+			TTS:   next.ProviderTimeout.TTS,
+			Agent: next.ProviderTimeout.Agent,
+		}, next.ProviderTimeout.FallbackMessage)
+		callService.SetFallbackPolicy(callservice.FallbackPolicy{
+			Retries:       next.ProviderTimeout.FallbackRetries,
+			TransferQueue: next.ProviderTimeout.FallbackTransferQueue,
+		})
+		callService.SetTurnLatencyBudget(next.Call.TurnLatencyBudget)
+		tenantClient.SetBaseURL(next.TenantManager.URL)
+		agentClient.SetBaseURL(next.AgentOrchestrator.URL)
+	})
+	reloader.Watch(ctx)
 
 	// Start servers
 	errChan := make(chan error, 2)
@@ -96,27 +385,66 @@ func main() {
 		log.Info("received shutdown signal", zap.String("signal", sig.String()))
 	}
 
-	// Graceful shutdown
-	log.Info("shutting down servers...")
+	// Graceful shutdown: servers, then publisher flush, then telemetry
+	log.Info("shutting down...")
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
 
-	// Shutdown HTTP server
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		log.Error("http server shutdown error", zap.Error(err))
+	if err := closers.Shutdown(shutdownCtx); err != nil {
+		log.Error("shutdown completed with errors", zap.Error(err))
 	}
 
-	// Shutdown metrics server
-	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
-		log.Error("metrics server shutdown error", zap.Error(err))
-	}
+	log.Info("voice-gateway stopped")
+}
 
-	log.Info("servers stopped")
+// publishStateChange publishes the call state store degrading or recovering.
+func publishStateChange(ctx context.Context, publisher *events.Publisher, change redisadapter.StateChange, log *zap.Logger) {
+	var err error
+	if change.Degraded {
+		err = publisher.PublishStateStoreDegraded(ctx, "redis", change.Cause.Error(), change.Fallback)
+	} else {
+		err = publisher.PublishStateStoreRecovered(ctx, "redis", change.Downtime, change.Reconciled, change.Fallback)
+	}
+	if err != nil {
+		log.Error("failed to publish state store event", zap.Bool("degraded", change.Degraded), zap.Error(err))
+	}
 }
 
 // initLogger initializes the logger with the specified level and environment.
-func initLogger(logLevel, environment string) (*zap.Logger, error) {
+// The returned level can be changed while the logger is in use.
+// newTokenVerifier verifies tokens with the shared secret, the JWKS, or both,
+// and requires the configured audience.
+func newTokenVerifier(c config.AuthConfig) (*authjwt.Verifier, error) {
+	var opts []authjwt.VerifierOption
+	if c.Audience != "" {
+		opts = append(opts, authjwt.WithAudience(c.Audience))
+	}
+	if c.JWTSecret != "" {
+		opts = append(opts, authjwt.WithHMACSecret(c.JWTSecret))
+	}
+	if c.JWKSURL != "" {
+		opts = append(opts, authjwt.WithJWKS(authjwt.NewJWKSClient(c.JWKSURL, nil)))
+	}
+	return authjwt.NewVerifier(opts...)
+}
+
+// loadShedConfig converts the configured overload ceiling.
+func loadShedConfig(c config.LoadShedConfig) loadshed.Config {
+	return loadshed.Config{
+		MaxInFlight:  c.MaxInFlight,
+		MaxQueueWait: c.MaxQueueWait,
+		RetryAfter:   c.RetryAfter,
+	}
+}
+
+func bodyLimitConfig(c config.ServerConfig) bodylimit.Config {
+	// Validate rejects malformed routes
+	routes, _ := bodylimit.ParseRoutes(c.BodyLimitRoutes)
+	return bodylimit.Config{MaxBytes: c.MaxBodyBytes, Routes: routes}
+}
+
+func initLogger(logLevel, environment string) (*zap.Logger, zap.AtomicLevel, error) {
 	var config zap.Config
 
 	if environment == "production" {
@@ -133,31 +461,6 @@ func initLogger(logLevel, environment string) (*zap.Logger, error) {
 	}
 	config.Level = level
 
-	return config.Build()
-}
-
-// buildHTTPRouter builds the HTTP router for the management API.
-func buildHTTPRouter(logger *zap.Logger) http.Handler {
-	mux := http.NewServeMux()
-
-	// Health check endpoints
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"healthy"}`))
-	})
-
-	mux.HandleFunc("/health/live", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"alive"}`))
-	})
-
-	mux.HandleFunc("/health/ready", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ready"}`))
-	})
-
-	return mux
+	logger, err := config.Build()
+	return logger, level, err
 }
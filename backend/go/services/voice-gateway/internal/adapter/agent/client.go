@@ -7,28 +7,81 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+	httpclient "github.com/serphona/serphona/backend/go/libs/platform-httpclient"
 	"go.uber.org/zap"
 )
 
 // Client is an HTTP client for agent-orchestrator service.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL    atomic.Pointer[string] // swapped on config reload
+	httpClient *httpclient.Client
+	tokens     TokenSource
 	logger     *zap.Logger
 }
 
+// TokenSource provides the service token agent-orchestrator requires.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
 // NewClient creates a new agent orchestrator client.
 func NewClient(baseURL string, logger *zap.Logger) *Client {
-	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+	c := &Client{logger: logger}
+	c.httpClient = httpclient.New(httpclient.Config{
+		Timeout: 30 * time.Second,
+		Breaker: &httpclient.BreakerConfig{},
+		// Client spans, and the caller's X-Request-ID and service token on
+		// every call
+		Wrap: func(rt http.RoundTripper) http.RoundTripper {
+			return tracing.Transport(requestid.NewTransport(&tokenTransport{client: c, next: rt}))
 		},
-		logger: logger,
+	})
+	c.SetBaseURL(baseURL)
+	return c
+}
+
+// SetTokenSource sends a service token from tokens on every call. Call it
+// before the client is used.
+func (c *Client) SetTokenSource(tokens TokenSource) {
+	c.tokens = tokens
+}
+
+// SetBaseURL points the client at another agent-orchestrator address. Requests
+// already started keep the previous address.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL.Store(&baseURL)
+}
+
+// tokenTransport authenticates requests with the client's token source.
+type tokenTransport struct {
+	client *Client
+	next   http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.client.tokens == nil {
+		return t.next.RoundTrip(req)
 	}
+
+	token, err := t.client.tokens.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service token: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.next.RoundTrip(req)
+}
+
+// base returns the current agent-orchestrator address.
+func (c *Client) base() string {
+	return *c.baseURL.Load()
 }
 
 // CreateConversationRequest represents a conversation creation request.
@@ -48,9 +101,10 @@ type ConversationResponse struct {
 	CreatedAt      string    `json:"created_at"`
 }
 
-// CreateConversation creates a new conversation with an agent.
+// CreateConversation creates a new conversation with an agent, starting from
+// the given context variables.
 // POST /api/v1/conversations
-func (c *Client) CreateConversation(ctx context.Context, tenantID uuid.UUID, agentID string) (*ConversationResponse, error) {
+func (c *Client) CreateConversation(ctx context.Context, tenantID uuid.UUID, agentID string, variables map[string]string) (*ConversationResponse, error) {
 	req := CreateConversationRequest{
 		TenantID: tenantID,
 		AgentID:  agentID,
@@ -59,13 +113,16 @@ func (c *Client) CreateConversation(ctx context.Context, tenantID uuid.UUID, age
 			"call_initiated": time.Now().UTC().Format(time.RFC3339),
 		},
 	}
+	for name, value := range variables {
+		req.InitialState[name] = value
+	}
 
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/conversations", c.baseURL)
+	url := fmt.Sprintf("%s/api/v1/conversations", c.base())
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -103,14 +160,13 @@ type SubmitTurnRequest struct {
 
 // TurnResponse represents an agent's response to a turn.
 type TurnResponse struct {
-	ConversationID uuid.UUID              `json:"conversation_id"`
-	TurnID         uuid.UUID              `json:"turn_id"`
-	AgentResponse  string                 `json:"agent_response"`
-	Intent         string                 `json:"intent,omitempty"`
-	Action         string                 `json:"action,omitempty"`
-	ActionParams   map[string]interface{} `json:"action_params,omitempty"`
-	State          string                 `json:"state"`
-	FinishReason   string                 `json:"finish_reason,omitempty"`
+	ConversationID uuid.UUID       `json:"conversation_id"`
+	TurnID         uuid.UUID       `json:"turn_id"`
+	AgentResponse  string          `json:"agent_response"`
+	Intent         string          `json:"intent,omitempty"`
+	Action         *actions.Action `json:"action,omitempty"` // validated before it is executed
+	State          string          `json:"state"`
+	FinishReason   string          `json:"finish_reason,omitempty"`
 }
 
 // SubmitTurn submits a user message and gets agent response.
@@ -126,7 +182,7 @@ func (c *Client) SubmitTurn(ctx context.Context, conversationID uuid.UUID, userM
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/conversations/%s/turns", c.baseURL, conversationID)
+	url := fmt.Sprintf("%s/api/v1/conversations/%s/turns", c.base(), conversationID)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -151,7 +207,7 @@ func (c *Client) SubmitTurn(ctx context.Context, conversationID uuid.UUID, userM
 	c.logger.Debug("turn submitted",
 		zap.String("conversation_id", conversationID.String()),
 		zap.String("intent", turnResp.Intent),
-		zap.String("action", turnResp.Action),
+		zap.Any("action", turnResp.Action),
 	)
 
 	return &turnResp, nil
@@ -160,7 +216,7 @@ func (c *Client) SubmitTurn(ctx context.Context, conversationID uuid.UUID, userM
 // GetAgentResponse gets the current agent for a conversation.
 // GET /api/v1/conversations/{conversation_id}/agent
 func (c *Client) GetAgentResponse(ctx context.Context, conversationID uuid.UUID) (*ConversationResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/conversations/%s/agent", c.baseURL, conversationID)
+	url := fmt.Sprintf("%s/api/v1/conversations/%s/agent", c.base(), conversationID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -185,6 +241,32 @@ func (c *Client) GetAgentResponse(ctx context.Context, conversationID uuid.UUID)
 	return &agentResp, nil
 }
 
+// CancelTurn stops the agent reply in progress, e.g. when the caller hangs up.
+// POST /api/v1/conversations/{conversation_id}/cancel
+func (c *Client) CancelTurn(ctx context.Context, conversationID uuid.UUID) error {
+	url := fmt.Sprintf("%s/api/v1/conversations/%s/cancel", c.base(), conversationID)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	c.logger.Debug("turn cancelled",
+		zap.String("conversation_id", conversationID.String()),
+	)
+
+	return nil
+}
+
 // EndConversationRequest represents a conversation end request.
 type EndConversationRequest struct {
 	Reason string `json:"reason,omitempty"`
@@ -202,7 +284,7 @@ func (c *Client) EndConversation(ctx context.Context, conversationID uuid.UUID,
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/conversations/%s/end", c.baseURL, conversationID)
+	url := fmt.Sprintf("%s/api/v1/conversations/%s/end", c.base(), conversationID)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -244,7 +326,7 @@ func (c *Client) UpdateContext(ctx context.Context, conversationID uuid.UUID, co
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/conversations/%s/context", c.baseURL, conversationID)
+	url := fmt.Sprintf("%s/api/v1/conversations/%s/context", c.base(), conversationID)
 	httpReq, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -0,0 +1,464 @@
+package asterisk
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrAMINotConnected is returned when an AMI action is sent without an active connection.
+var ErrAMINotConnected = errors.New("ami not connected")
+
+// AMIMessage represents a response or event received from the Asterisk Manager Interface.
+type AMIMessage map[string]string
+
+// Get returns the value of a message field. Keys are case-insensitive.
+func (m AMIMessage) Get(key string) string {
+	return m[textproto.CanonicalMIMEHeaderKey(key)]
+}
+
+// IsEvent returns true if the message is an AMI event.
+func (m AMIMessage) IsEvent() bool {
+	return m.Get("Event") != ""
+}
+
+// IsSuccess returns true if the message is a successful AMI response.
+func (m AMIMessage) IsSuccess() bool {
+	return strings.EqualFold(m.Get("Response"), "Success")
+}
+
+// AMIChannelStatus represents the status of a channel as reported by AMI.
+type AMIChannelStatus struct {
+	Channel      string
+	UniqueID     string
+	State        string
+	CallerNumber string
+	CallerName   string
+	Duration     time.Duration
+}
+
+// AMIClient is a minimal Asterisk Manager Interface client used as a
+// degraded-mode fallback when the ARI WebSocket is unavailable.
+//
+// Supported operations in fallback mode: Status, Hangup, Originate and
+// event streaming. Media operations (playback, bridges, recording) require ARI.
+type AMIClient struct {
+	addr     string
+	username string
+	password string
+	logger   *zap.Logger
+
+	conn   net.Conn
+	writer *bufio.Writer
+	mu     sync.Mutex // guards conn and writer
+
+	pending  map[string]pendingAction
+	pendMu   sync.Mutex
+	events   chan AMIMessage
+	actionID uint64
+
+	dial           func(ctx context.Context) (net.Conn, error)
+	reconnectDelay time.Duration
+	maxReconnects  int
+	dialTimeout    time.Duration
+	actionTimeout  time.Duration
+}
+
+// pendingAction is an action waiting for its response on the connection it
+// was sent on.
+type pendingAction struct {
+	conn net.Conn
+	ch   chan AMIMessage
+}
+
+// NewAMIClient creates a new Asterisk AMI client.
+func NewAMIClient(host string, port int, username, password string, logger *zap.Logger) *AMIClient {
+	c := &AMIClient{
+		addr:           net.JoinHostPort(host, strconv.Itoa(port)),
+		username:       username,
+		password:       password,
+		logger:         logger,
+		pending:        make(map[string]pendingAction),
+		events:         make(chan AMIMessage, 100),
+		reconnectDelay: 5 * time.Second,
+		maxReconnects:  10,
+		dialTimeout:    10 * time.Second,
+		actionTimeout:  10 * time.Second,
+	}
+	c.dial = func(ctx context.Context) (net.Conn, error) {
+		dialer := net.Dialer{Timeout: c.dialTimeout}
+		return dialer.DialContext(ctx, "tcp", c.addr)
+	}
+	return c
+}
+
+// Connect establishes the TCP connection to AMI and logs in.
+func (c *AMIClient) Connect(ctx context.Context) error {
+	c.logger.Info("connecting to Asterisk AMI", zap.String("addr", c.addr))
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to AMI: %w", err)
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(conn))
+
+	// Read banner: "Asterisk Call Manager/X.Y.Z"
+	banner, err := reader.ReadLine()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to read AMI banner: %w", err)
+	}
+	if !strings.HasPrefix(banner, "Asterisk Call Manager") {
+		conn.Close()
+		return fmt.Errorf("unexpected AMI banner: %s", banner)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.writer = bufio.NewWriter(conn)
+	c.mu.Unlock()
+
+	go c.readLoop(conn, reader)
+
+	resp, err := c.sendAction(ctx, "Login", map[string]string{
+		"Username": c.username,
+		"Secret":   c.password,
+		"Events":   "call",
+	})
+	if err != nil {
+		c.Close()
+		return fmt.Errorf("failed to login to AMI: %w", err)
+	}
+	if !resp.IsSuccess() {
+		c.Close()
+		return fmt.Errorf("AMI login rejected: %s", resp.Get("Message"))
+	}
+
+	c.logger.Info("connected to Asterisk AMI")
+	return nil
+}
+
+// Close closes the AMI connection.
+func (c *AMIClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	c.writer = nil
+	return err
+}
+
+// IsConnected returns true if there is an active AMI connection.
+func (c *AMIClient) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn != nil
+}
+
+// Hangup hangs up a channel identified by its unique ID (the ARI channel ID).
+func (c *AMIClient) Hangup(ctx context.Context, channelID string) error {
+	status, err := c.Status(ctx, channelID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.sendAction(ctx, "Hangup", map[string]string{
+		"Channel": status.Channel,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hangup channel: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("hangup failed: %s", resp.Get("Message"))
+	}
+
+	c.logger.Info("channel hung up via AMI", zap.String("channel_id", channelID))
+	return nil
+}
+
+// Status returns the status of a channel identified by its unique ID.
+func (c *AMIClient) Status(ctx context.Context, channelID string) (*AMIChannelStatus, error) {
+	statuses, err := c.ListChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range statuses {
+		if s.UniqueID == channelID {
+			return s, nil
+		}
+	}
+
+	return nil, fmt.Errorf("channel not found: %s", channelID)
+}
+
+// ListChannels returns the status of all active channels.
+func (c *AMIClient) ListChannels(ctx context.Context) ([]*AMIChannelStatus, error) {
+	msgs, err := c.sendListAction(ctx, "Status", nil, "StatusComplete")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel status: %w", err)
+	}
+
+	statuses := make([]*AMIChannelStatus, 0, len(msgs))
+	for _, m := range msgs {
+		if m.Get("Event") != "Status" {
+			continue
+		}
+		seconds, _ := strconv.Atoi(m.Get("Seconds"))
+		statuses = append(statuses, &AMIChannelStatus{
+			Channel:      m.Get("Channel"),
+			UniqueID:     m.Get("Uniqueid"),
+			State:        m.Get("ChannelStateDesc"),
+			CallerNumber: m.Get("CallerIDNum"),
+			CallerName:   m.Get("CallerIDName"),
+			Duration:     time.Duration(seconds) * time.Second,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Originate originates a new call from endpoint into the given dialplan context/extension.
+func (c *AMIClient) Originate(ctx context.Context, endpoint, dialplanContext, extension, callerID string) error {
+	resp, err := c.sendAction(ctx, "Originate", map[string]string{
+		"Channel":  endpoint,
+		"Context":  dialplanContext,
+		"Exten":    extension,
+		"Priority": "1",
+		"CallerID": callerID,
+		"Async":    "true",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to originate call: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("originate failed: %s", resp.Get("Message"))
+	}
+
+	c.logger.Info("call originated via AMI",
+		zap.String("endpoint", endpoint),
+		zap.String("extension", extension),
+	)
+	return nil
+}
+
+// ListenForEvents listens for AMI events with automatic reconnection.
+func (c *AMIClient) ListenForEvents(ctx context.Context, handler func(AMIMessage) error) error {
+	c.logger.Info("starting AMI event listener")
+
+	reconnectAttempts := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		// Ensure connection
+		if !c.IsConnected() {
+			if err := c.Connect(ctx); err != nil {
+				c.logger.Error("failed to connect to AMI", zap.Error(err))
+				reconnectAttempts++
+
+				if reconnectAttempts >= c.maxReconnects {
+					return fmt.Errorf("max reconnection attempts reached: %d", c.maxReconnects)
+				}
+
+				c.logger.Info("retrying AMI connection",
+					zap.Int("attempt", reconnectAttempts),
+					zap.Duration("delay", c.reconnectDelay),
+				)
+
+				select {
+				case <-time.After(c.reconnectDelay):
+					continue
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			reconnectAttempts = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-c.events:
+			if !ok {
+				return nil
+			}
+			if event == nil {
+				// Connection dropped; loop will reconnect
+				continue
+			}
+
+			if err := handler(event); err != nil {
+				c.logger.Error("AMI event handler error",
+					zap.String("event_type", event.Get("Event")),
+					zap.Error(err),
+				)
+				// Continue processing other events even if handler fails
+			}
+		}
+	}
+}
+
+// sendAction sends an AMI action and waits for its response.
+func (c *AMIClient) sendAction(ctx context.Context, action string, fields map[string]string) (AMIMessage, error) {
+	msgs, err := c.sendListAction(ctx, action, fields, "")
+	if err != nil {
+		return nil, err
+	}
+	return msgs[0], nil
+}
+
+// sendListAction sends an AMI action and collects messages until completeEvent
+// is received. If completeEvent is empty, only the response is returned.
+func (c *AMIClient) sendListAction(ctx context.Context, action string, fields map[string]string, completeEvent string) ([]AMIMessage, error) {
+	id := strconv.FormatUint(atomic.AddUint64(&c.actionID, 1), 10)
+	ch := make(chan AMIMessage, 64)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Action: %s\r\nActionID: %s\r\n", action, id)
+	for k, v := range fields {
+		fmt.Fprintf(&sb, "%s: %s\r\n", k, v)
+	}
+	sb.WriteString("\r\n")
+
+	c.mu.Lock()
+	if c.writer == nil {
+		c.mu.Unlock()
+		return nil, ErrAMINotConnected
+	}
+
+	// The action belongs to the current connection: only that connection's
+	// read loop answers or fails it
+	c.pendMu.Lock()
+	c.pending[id] = pendingAction{conn: c.conn, ch: ch}
+	c.pendMu.Unlock()
+
+	defer func() {
+		c.pendMu.Lock()
+		delete(c.pending, id)
+		c.pendMu.Unlock()
+	}()
+
+	_, err := c.writer.WriteString(sb.String())
+	if err == nil {
+		err = c.writer.Flush()
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to send AMI action: %w", err)
+	}
+
+	timer := time.NewTimer(c.actionTimeout)
+	defer timer.Stop()
+
+	var msgs []AMIMessage
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return nil, ErrAMINotConnected
+			}
+			msgs = append(msgs, msg)
+
+			if !msg.IsEvent() && !msg.IsSuccess() {
+				return msgs, nil
+			}
+			if completeEvent == "" || msg.Get("Event") == completeEvent {
+				return msgs, nil
+			}
+		case <-timer.C:
+			return nil, fmt.Errorf("AMI action %s timed out", action)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// readLoop reads messages from AMI and dispatches responses and events.
+func (c *AMIClient) readLoop(conn net.Conn, reader *textproto.Reader) {
+	for {
+		header, err := reader.ReadMIMEHeader()
+		if err != nil {
+			c.logger.Error("failed to read AMI message", zap.Error(err))
+
+			// Only reset state if this loop still owns the connection
+			c.mu.Lock()
+			if c.conn == conn {
+				c.conn = nil
+				c.writer = nil
+			}
+			c.mu.Unlock()
+			conn.Close()
+
+			// Fail the actions sent on this connection; actions sent after a
+			// reconnect belong to the new connection's read loop
+			c.pendMu.Lock()
+			for id, p := range c.pending {
+				if p.conn == conn {
+					close(p.ch)
+					delete(c.pending, id)
+				}
+			}
+			c.pendMu.Unlock()
+
+			// Wake up the event listener so it reconnects
+			select {
+			case c.events <- nil:
+			default:
+			}
+			return
+		}
+
+		msg := make(AMIMessage, len(header))
+		for k, v := range header {
+			if len(v) > 0 {
+				msg[k] = v[0]
+			}
+		}
+
+		// Route responses (and list events) to the waiting action
+		if id := msg.Get("ActionID"); id != "" {
+			c.pendMu.Lock()
+			p, waiting := c.pending[id]
+			c.pendMu.Unlock()
+			if waiting && p.conn == conn {
+				select {
+				case p.ch <- msg:
+				case <-time.After(c.actionTimeout):
+					c.logger.Warn("AMI action response dropped", zap.String("action_id", id))
+				}
+				continue
+			}
+		}
+
+		if msg.IsEvent() {
+			select {
+			case c.events <- msg:
+			default:
+				c.logger.Warn("AMI event buffer full, dropping event",
+					zap.String("event_type", msg.Get("Event")),
+				)
+			}
+		}
+	}
+}
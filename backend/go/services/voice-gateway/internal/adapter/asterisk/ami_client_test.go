@@ -0,0 +1,226 @@
+package asterisk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeAMI is the Asterisk side of a net.Pipe connection.
+type fakeAMI struct {
+	conn   net.Conn
+	reader *textproto.Reader
+}
+
+// newFakeAMI greets the client with the AMI banner.
+func newFakeAMI(t *testing.T, conn net.Conn) *fakeAMI {
+	t.Helper()
+
+	f := &fakeAMI{conn: conn, reader: textproto.NewReader(bufio.NewReader(conn))}
+	go fmt.Fprint(conn, "Asterisk Call Manager/7.0.3\r\n")
+	return f
+}
+
+// readAction reads the next action sent by the client.
+func (f *fakeAMI) readAction(t *testing.T) AMIMessage {
+	t.Helper()
+
+	header, err := f.reader.ReadMIMEHeader()
+	if err != nil {
+		t.Errorf("fake AMI failed to read action: %v", err)
+		return nil
+	}
+	msg := make(AMIMessage, len(header))
+	for k, v := range header {
+		msg[k] = v[0]
+	}
+	return msg
+}
+
+// reply writes a message with the given fields.
+func (f *fakeAMI) reply(fields ...string) {
+	var msg string
+	for i := 0; i+1 < len(fields); i += 2 {
+		msg += fields[i] + ": " + fields[i+1] + "\r\n"
+	}
+	fmt.Fprint(f.conn, msg+"\r\n")
+}
+
+// newPipeAMIClient returns a client that dials the fake servers in order.
+func newPipeAMIClient(t *testing.T, servers int) (*AMIClient, <-chan *fakeAMI) {
+	t.Helper()
+
+	c := NewAMIClient("asterisk", 5038, "user", "secret", zap.NewNop())
+	c.actionTimeout = 2 * time.Second
+
+	fakes := make(chan *fakeAMI, servers)
+	dialed := 0
+	c.dial = func(ctx context.Context) (net.Conn, error) {
+		if dialed == servers {
+			return nil, fmt.Errorf("no fake AMI server left")
+		}
+		dialed++
+
+		client, server := net.Pipe()
+		t.Cleanup(func() { server.Close() })
+		fakes <- newFakeAMI(t, server)
+		return client, nil
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, fakes
+}
+
+// acceptLogin answers the client's Login action.
+func (f *fakeAMI) acceptLogin(t *testing.T) {
+	t.Helper()
+
+	login := f.readAction(t)
+	if login.Get("Action") != "Login" || login.Get("Username") != "user" {
+		t.Errorf("Expected Login for user, got %v", login)
+	}
+	f.reply("Response", "Success", "ActionID", login.Get("ActionID"), "Message", "Authentication accepted")
+}
+
+func TestAMIClient_CorrelatesResponsesByActionID(t *testing.T) {
+	ctx := context.Background()
+	c, fakes := newPipeAMIClient(t, 1)
+
+	go func() {
+		f := <-fakes
+		f.acceptLogin(t)
+
+		// Answer two in-flight actions in reverse order
+		first, second := f.readAction(t), f.readAction(t)
+		for _, action := range []AMIMessage{second, first} {
+			f.reply("Response", "Success", "ActionID", action.Get("ActionID"), "Message", action.Get("Command"))
+		}
+	}()
+
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	results := make(chan error, 2)
+	for _, command := range []string{"core show uptime", "core show version"} {
+		go func(command string) {
+			resp, err := c.sendAction(ctx, "Command", map[string]string{"Command": command})
+			if err == nil && resp.Get("Message") != command {
+				err = fmt.Errorf("action %q got the response to %q", command, resp.Get("Message"))
+			}
+			results <- err
+		}(command)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestAMIClient_ReconnectKeepsActionsOfNewConnection(t *testing.T) {
+	ctx := context.Background()
+	c, fakes := newPipeAMIClient(t, 2)
+
+	go func() { (<-fakes).acceptLogin(t) }()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	c.mu.Lock()
+	oldConn := c.conn
+	c.mu.Unlock()
+
+	// Reconnect; the new Login is pending when the old connection dies
+	loginPending := make(chan *fakeAMI)
+	release := make(chan struct{})
+	go func() {
+		f := <-fakes
+		login := f.readAction(t)
+		loginPending <- f
+		<-release
+		f.reply("Response", "Success", "ActionID", login.Get("ActionID"))
+	}()
+
+	connected := make(chan error, 1)
+	go func() { connected <- c.Connect(ctx) }()
+
+	f := <-loginPending
+	oldConn.Close()
+	select {
+	case event := <-c.events:
+		if event != nil {
+			t.Fatalf("Expected the old read loop to signal the dropped connection, got %v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the old read loop to exit")
+	}
+	close(release)
+
+	if err := <-connected; err != nil {
+		t.Fatalf("Expected the login on the new connection to survive the old one closing: %v", err)
+	}
+
+	// Actions keep working on the new connection
+	go func() {
+		ping := f.readAction(t)
+		f.reply("Response", "Success", "ActionID", ping.Get("ActionID"), "Ping", "Pong")
+	}()
+	resp, err := c.sendAction(ctx, "Ping", nil)
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if resp.Get("Ping") != "Pong" {
+		t.Errorf("Unexpected ping response %v", resp)
+	}
+}
+
+func TestAMIClient_ListChannelsCollectsListEvents(t *testing.T) {
+	ctx := context.Background()
+	c, fakes := newPipeAMIClient(t, 1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f := <-fakes
+		f.acceptLogin(t)
+
+		status := f.readAction(t)
+		id := status.Get("ActionID")
+		f.reply("Response", "Success", "ActionID", id, "EventList", "start")
+		f.reply("Event", "Newchannel", "Channel", "PJSIP/other-00000002")
+		f.reply("Event", "Status", "ActionID", id, "Channel", "PJSIP/trunk-00000001", "Uniqueid", "1700000000.1", "ChannelStateDesc", "Up", "CallerIDNum", "+5511999887766", "Seconds", "42")
+		f.reply("Event", "StatusComplete", "ActionID", id, "Items", "1")
+	}()
+
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	statuses, err := c.ListChannels(ctx)
+	if err != nil {
+		t.Fatalf("ListChannels failed: %v", err)
+	}
+	<-done
+
+	if len(statuses) != 1 {
+		t.Fatalf("Expected 1 channel, got %d", len(statuses))
+	}
+	s := statuses[0]
+	if s.Channel != "PJSIP/trunk-00000001" || s.UniqueID != "1700000000.1" || s.State != "Up" || s.Duration != 42*time.Second {
+		t.Errorf("Unexpected channel status %+v", s)
+	}
+
+	// The event without an ActionID is delivered to the event stream
+	select {
+	case event := <-c.events:
+		if event.Get("Event") != "Newchannel" {
+			t.Errorf("Expected the Newchannel event, got %v", event)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the unrelated event on the event stream")
+	}
+}
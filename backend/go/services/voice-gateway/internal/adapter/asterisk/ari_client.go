@@ -5,7 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -130,13 +133,19 @@ func (c *ARIClient) PlaybackStart(ctx context.Context, channelID string, media s
 		return "", fmt.Errorf("playback failed with status: %d", resp.StatusCode)
 	}
 
+	var playback ARIPlayback
+	if err := json.NewDecoder(resp.Body).Decode(&playback); err != nil || playback.ID == "" {
+		// Fall back to a generated playback ID if the response has no body
+		playback.ID = fmt.Sprintf("playback-%d", time.Now().Unix())
+	}
+
 	c.logger.Info("playback started",
 		zap.String("channel_id", channelID),
 		zap.String("media", media),
+		zap.String("playback_id", playback.ID),
 	)
 
-	// Return a generated playback ID (in real ARI, this comes from response)
-	return fmt.Sprintf("playback-%d", time.Now().Unix()), nil
+	return playback.ID, nil
 }
 
 // HangupChannel hangs up a channel.
@@ -163,7 +172,14 @@ func (c *ARIClient) HangupChannel(ctx context.Context, channelID string) error {
 	return nil
 }
 
-// CreateBridge creates a new mixing bridge.
+// ARIBridge represents an Asterisk bridge.
+type ARIBridge struct {
+	ID         string   `json:"id"`
+	BridgeType string   `json:"bridge_type"`
+	Channels   []string `json:"channels"`
+}
+
+// CreateBridge creates a new bridge of the given type (e.g. "mixing", "holding").
 func (c *ARIClient) CreateBridge(ctx context.Context, bridgeType string) (string, error) {
 	url := fmt.Sprintf("%s/bridges?type=%s", c.baseURL, bridgeType)
 
@@ -183,10 +199,18 @@ func (c *ARIClient) CreateBridge(ctx context.Context, bridgeType string) (string
 		return "", fmt.Errorf("create bridge failed with status: %d", resp.StatusCode)
 	}
 
-	c.logger.Info("bridge created", zap.String("type", bridgeType))
+	var bridge ARIBridge
+	if err := json.NewDecoder(resp.Body).Decode(&bridge); err != nil || bridge.ID == "" {
+		// Fall back to a generated bridge ID if the response has no body
+		bridge.ID = fmt.Sprintf("bridge-%d", time.Now().Unix())
+	}
+
+	c.logger.Info("bridge created",
+		zap.String("type", bridgeType),
+		zap.String("bridge_id", bridge.ID),
+	)
 
-	// Return a generated bridge ID (in real ARI, parse from response)
-	return fmt.Sprintf("bridge-%d", time.Now().Unix()), nil
+	return bridge.ID, nil
 }
 
 // AddChannelToBridge adds a channel to a bridge.
@@ -216,14 +240,189 @@ func (c *ARIClient) AddChannelToBridge(ctx context.Context, bridgeID, channelID
 	return nil
 }
 
+// RemoveChannelFromBridge removes a channel from a bridge.
+func (c *ARIClient) RemoveChannelFromBridge(ctx context.Context, bridgeID, channelID string) error {
+	url := fmt.Sprintf("%s/bridges/%s/removeChannel?channel=%s", c.baseURL, bridgeID, channelID)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to remove channel from bridge: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remove channel failed with status: %d", resp.StatusCode)
+	}
+
+	c.logger.Info("channel removed from bridge",
+		zap.String("bridge_id", bridgeID),
+		zap.String("channel_id", channelID),
+	)
+	return nil
+}
+
+// DestroyBridge shuts down a bridge.
+func (c *ARIClient) DestroyBridge(ctx context.Context, bridgeID string) error {
+	url := fmt.Sprintf("%s/bridges/%s", c.baseURL, bridgeID)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to destroy bridge: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("destroy bridge failed with status: %d", resp.StatusCode)
+	}
+
+	c.logger.Info("bridge destroyed", zap.String("bridge_id", bridgeID))
+	return nil
+}
+
+// SnoopChannel creates a snoop channel on a channel and places it in the Stasis
+// app. spy sets the directions of the channel's audio the snoop channel hears
+// and whisper the directions it injects audio into ("none", "in", "out" or
+// "both"). It returns the snoop channel ID.
+func (c *ARIClient) SnoopChannel(ctx context.Context, channelID, spy, whisper string) (string, error) {
+	url := fmt.Sprintf("%s/channels/%s/snoop?spy=%s&whisper=%s&app=%s", c.baseURL, channelID, spy, whisper, c.appName)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to snoop channel: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("snoop channel failed with status: %d", resp.StatusCode)
+	}
+
+	var snoop ARIChannel
+	if err := json.NewDecoder(resp.Body).Decode(&snoop); err != nil || snoop.ID == "" {
+		return "", fmt.Errorf("snoop channel returned no channel: %w", err)
+	}
+
+	c.logger.Info("snoop channel created",
+		zap.String("channel_id", channelID),
+		zap.String("snoop_channel_id", snoop.ID),
+		zap.String("spy", spy),
+		zap.String("whisper", whisper),
+	)
+
+	return snoop.ID, nil
+}
+
+// OriginateChannel dials endpoint (e.g. "PJSIP/+5511977665544@trunk") and
+// places the new channel in the Stasis app, presenting callerID. It returns
+// the new channel ID.
+func (c *ARIClient) OriginateChannel(ctx context.Context, endpoint, callerID string) (string, error) {
+	params := url.Values{}
+	params.Set("endpoint", endpoint)
+	params.Set("app", c.appName)
+	if callerID != "" {
+		params.Set("callerId", callerID)
+	}
+	reqURL := fmt.Sprintf("%s/channels?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to originate channel: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("originate channel failed with status: %d", resp.StatusCode)
+	}
+
+	var channel ARIChannel
+	if err := json.NewDecoder(resp.Body).Decode(&channel); err != nil || channel.ID == "" {
+		return "", fmt.Errorf("originate channel returned no channel: %w", err)
+	}
+
+	c.logger.Info("channel originated",
+		zap.String("endpoint", endpoint),
+		zap.String("channel_id", channel.ID),
+	)
+
+	return channel.ID, nil
+}
+
+// StartBridgeMOH starts music on hold on a bridge.
+func (c *ARIClient) StartBridgeMOH(ctx context.Context, bridgeID, mohClass string) error {
+	url := fmt.Sprintf("%s/bridges/%s/moh", c.baseURL, bridgeID)
+	if mohClass != "" {
+		url = fmt.Sprintf("%s?mohClass=%s", url, mohClass)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to start music on hold: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("start music on hold failed with status: %d", resp.StatusCode)
+	}
+
+	c.logger.Info("music on hold started",
+		zap.String("bridge_id", bridgeID),
+		zap.String("moh_class", mohClass),
+	)
+	return nil
+}
+
 // ARIEvent represents an event from Asterisk ARI.
 type ARIEvent struct {
 	Type      string                 `json:"type"`
 	Timestamp string                 `json:"timestamp"`
 	Channel   *ARIChannel            `json:"channel,omitempty"`
+	Playback  *ARIPlayback           `json:"playback,omitempty"`
+	Recording *ARILiveRecording      `json:"recording,omitempty"`
 	Data      map[string]interface{} `json:"-"`
 }
 
+// ARIPlayback represents a playback in ARI events.
+type ARIPlayback struct {
+	ID        string `json:"id"`
+	MediaURI  string `json:"media_uri"`
+	TargetURI string `json:"target_uri"` // e.g. "channel:<channel_id>"
+	State     string `json:"state"`
+}
+
+// ChannelID returns the channel ID the playback targets, if any.
+func (p *ARIPlayback) ChannelID() string {
+	return strings.TrimPrefix(p.TargetURI, "channel:")
+}
+
 // ARIChannel represents a channel in ARI events.
 type ARIChannel struct {
 	ID     string `json:"id"`
@@ -335,3 +534,219 @@ func (c *ARIClient) GetChannelInfo(ctx context.Context, channelID string) (*ARIC
 
 	return &channel, nil
 }
+
+// ChannelCodec returns the native audio format of a channel as Asterisk
+// reports it, e.g. "(ulaw)".
+func (c *ARIClient) ChannelCodec(ctx context.Context, channelID string) (string, error) {
+	params := url.Values{}
+	params.Set("variable", "CHANNEL(audionativeformat)")
+	reqURL := fmt.Sprintf("%s/channels/%s/variable?%s", c.baseURL, channelID, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get channel codec: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("get channel codec failed with status: %d", resp.StatusCode)
+	}
+
+	var variable struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&variable); err != nil {
+		return "", fmt.Errorf("failed to decode channel codec: %w", err)
+	}
+
+	return variable.Value, nil
+}
+
+// RTPStatistics represents RTP statistics for a channel as reported by ARI.
+// Jitter and round-trip time values are expressed in seconds.
+type RTPStatistics struct {
+	TxCount         int     `json:"txcount"`
+	RxCount         int     `json:"rxcount"`
+	TxJitter        float64 `json:"txjitter"`
+	RxJitter        float64 `json:"rxjitter"`
+	RemoteMaxJitter float64 `json:"remote_maxjitter"`
+	LocalMaxJitter  float64 `json:"local_maxjitter"`
+	TxPacketLoss    int     `json:"txploss"`
+	RxPacketLoss    int     `json:"rxploss"`
+	RTT             float64 `json:"rtt"`
+	MaxRTT          float64 `json:"maxrtt"`
+	ChannelID       string  `json:"channel_uniqueid"`
+}
+
+// GetRTPStatistics retrieves RTP statistics for a channel.
+// Must be called before the channel is hung up.
+func (c *ARIClient) GetRTPStatistics(ctx context.Context, channelID string) (*RTPStatistics, error) {
+	url := fmt.Sprintf("%s/channels/%s/rtp_statistics", c.baseURL, channelID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rtp statistics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("channel not found: %s", channelID)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get rtp statistics failed with status: %d", resp.StatusCode)
+	}
+
+	var stats RTPStatistics
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to decode rtp statistics: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// RecordOptions configures a channel recording.
+type RecordOptions struct {
+	Name        string        // recording name, unique per stored recording
+	Format      string        // e.g. "wav"
+	MaxDuration time.Duration // 0 means no limit
+	MaxSilence  time.Duration // 0 means no limit
+	Beep        bool          // play a beep before recording
+	TerminateOn string        // DTMF that stops the recording, e.g. "#"
+}
+
+// ARILiveRecording represents a recording in progress or finished in ARI events.
+type ARILiveRecording struct {
+	Name      string `json:"name"`
+	Format    string `json:"format"`
+	State     string `json:"state"` // queued, recording, paused, done, failed, canceled
+	TargetURI string `json:"target_uri"`
+	Duration  int    `json:"duration,omitempty"` // seconds
+	Cause     string `json:"cause,omitempty"`
+}
+
+// ChannelID returns the channel ID the recording targets, if any.
+func (r *ARILiveRecording) ChannelID() string {
+	return strings.TrimPrefix(r.TargetURI, "channel:")
+}
+
+// RecordChannel starts recording audio from a channel.
+func (c *ARIClient) RecordChannel(ctx context.Context, channelID string, opts RecordOptions) (*ARILiveRecording, error) {
+	params := url.Values{}
+	params.Set("name", opts.Name)
+	params.Set("format", opts.Format)
+	params.Set("ifExists", "overwrite")
+	params.Set("beep", strconv.FormatBool(opts.Beep))
+	if opts.MaxDuration > 0 {
+		params.Set("maxDurationSeconds", strconv.Itoa(int(opts.MaxDuration.Seconds())))
+	}
+	if opts.MaxSilence > 0 {
+		params.Set("maxSilenceSeconds", strconv.Itoa(int(opts.MaxSilence.Seconds())))
+	}
+	if opts.TerminateOn != "" {
+		params.Set("terminateOn", opts.TerminateOn)
+	}
+
+	reqURL := fmt.Sprintf("%s/channels/%s/record?%s", c.baseURL, channelID, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start recording: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("record failed with status: %d", resp.StatusCode)
+	}
+
+	var recording ARILiveRecording
+	if err := json.NewDecoder(resp.Body).Decode(&recording); err != nil || recording.Name == "" {
+		// Fall back to the requested values if the response has no body
+		recording = ARILiveRecording{
+			Name:      opts.Name,
+			Format:    opts.Format,
+			State:     "queued",
+			TargetURI: "channel:" + channelID,
+		}
+	}
+
+	c.logger.Info("recording started",
+		zap.String("channel_id", channelID),
+		zap.String("name", recording.Name),
+	)
+
+	return &recording, nil
+}
+
+// GetStoredRecordingFile downloads the audio of a stored recording.
+func (c *ARIClient) GetStoredRecordingFile(ctx context.Context, name string) ([]byte, error) {
+	url := fmt.Sprintf("%s/recordings/stored/%s/file", c.baseURL, name)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recording: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("recording not found: %s", name)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get recording failed with status: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	return data, nil
+}
+
+// DeleteStoredRecording deletes a stored recording from Asterisk.
+func (c *ARIClient) DeleteStoredRecording(ctx context.Context, name string) error {
+	url := fmt.Sprintf("%s/recordings/stored/%s", c.baseURL, name)
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete recording: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("delete recording failed with status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EventAgentConfigUpdated is published by tenant-manager when a tenant's agent
+// config changes. The payload carries the tenant_id; the message key is the
+// tenant ID as well.
+const EventAgentConfigUpdated = "tenant.agent_config_updated"
+
+// ConfigInvalidator evicts the cached config of a tenant.
+type ConfigInvalidator interface {
+	Invalidate(tenantID uuid.UUID)
+}
+
+// ConfigConsumer evicts cached agent configs when tenant-manager reports an
+// update. Every instance must see every update, so each one joins its own
+// consumer group and only reads updates published after it started.
+type ConfigConsumer struct {
+	group       sarama.ConsumerGroup
+	topic       string
+	invalidator ConfigInvalidator
+	logger      *zap.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewConfigConsumer creates a consumer of agent config updates. groupID is
+// suffixed with a random instance ID.
+func NewConfigConsumer(brokers []string, groupID, topicPrefix string, invalidator ConfigInvalidator, logger *zap.Logger) (*ConfigConsumer, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	config.Consumer.Return.Errors = true
+
+	instanceGroup := fmt.Sprintf("%s-config-%s", groupID, uuid.NewString())
+	group, err := sarama.NewConsumerGroup(brokers, instanceGroup, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
+	}
+
+	c := newConfigConsumer(topicPrefix, invalidator, logger)
+	c.group = group
+	return c, nil
+}
+
+func newConfigConsumer(topicPrefix string, invalidator ConfigInvalidator, logger *zap.Logger) *ConfigConsumer {
+	return &ConfigConsumer{
+		topic:       fmt.Sprintf("%s.%s", topicPrefix, EventAgentConfigUpdated),
+		invalidator: invalidator,
+		logger:      logger,
+		done:        make(chan struct{}),
+	}
+}
+
+// Start consumes updates in the background until Close.
+func (c *ConfigConsumer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	go func() {
+		for err := range c.group.Errors() {
+			c.logger.Warn("agent config consumer error", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		defer close(c.done)
+		for ctx.Err() == nil {
+			// Consume returns on rebalance; rejoin until closed
+			if err := c.group.Consume(ctx, []string{c.topic}, c); err != nil {
+				if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+					return
+				}
+				c.logger.Warn("agent config consumer stopped, rejoining", zap.Error(err))
+			}
+		}
+	}()
+
+	c.logger.Info("agent config consumer started", zap.String("topic", c.topic))
+}
+
+// Close stops consuming and leaves the consumer group.
+func (c *ConfigConsumer) Close() error {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+	return c.group.Close()
+}
+
+// Setup implements sarama.ConsumerGroupHandler.
+func (c *ConfigConsumer) Setup(sarama.ConsumerGroupSession) error { return nil }
+
+// Cleanup implements sarama.ConsumerGroupHandler.
+func (c *ConfigConsumer) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+// ConsumeClaim implements sarama.ConsumerGroupHandler.
+func (c *ConfigConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		c.handle(msg)
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
+
+// handle evicts the config of the tenant named by the message. Malformed
+// messages are logged and skipped.
+func (c *ConfigConsumer) handle(msg *sarama.ConsumerMessage) {
+	var payload struct {
+		TenantID string `json:"tenant_id"`
+	}
+	raw := string(msg.Key)
+	if err := json.Unmarshal(msg.Value, &payload); err == nil && payload.TenantID != "" {
+		raw = payload.TenantID
+	}
+
+	tenantID, err := uuid.Parse(raw)
+	if err != nil {
+		c.logger.Warn("agent config update without a valid tenant ID",
+			zap.String("topic", msg.Topic),
+			zap.Int64("offset", msg.Offset),
+		)
+		return
+	}
+
+	c.invalidator.Invalidate(tenantID)
+}
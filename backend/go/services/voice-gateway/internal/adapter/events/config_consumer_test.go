@@ -0,0 +1,42 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type recordingInvalidator struct {
+	evicted []uuid.UUID
+}
+
+func (r *recordingInvalidator) Invalidate(tenantID uuid.UUID) {
+	r.evicted = append(r.evicted, tenantID)
+}
+
+func TestConfigConsumerSubscribesToPrefixedTopic(t *testing.T) {
+	c := newConfigConsumer("serphona", &recordingInvalidator{}, zap.NewNop())
+
+	if c.topic != "serphona.tenant.agent_config_updated" {
+		t.Errorf("topic = %q, want serphona.tenant.agent_config_updated", c.topic)
+	}
+}
+
+func TestConfigConsumerEvictsUpdatedTenant(t *testing.T) {
+	invalidator := &recordingInvalidator{}
+	c := newConfigConsumer("serphona", invalidator, zap.NewNop())
+
+	fromPayload, fromKey := uuid.New(), uuid.New()
+	c.handle(&sarama.ConsumerMessage{Value: []byte(`{"tenant_id":"` + fromPayload.String() + `"}`)})
+	c.handle(&sarama.ConsumerMessage{Key: []byte(fromKey.String()), Value: []byte(`{}`)})
+	c.handle(&sarama.ConsumerMessage{Value: []byte(`not json`)})
+
+	if len(invalidator.evicted) != 2 {
+		t.Fatalf("evicted %v, want the two valid tenants", invalidator.evicted)
+	}
+	if invalidator.evicted[0] != fromPayload || invalidator.evicted[1] != fromKey {
+		t.Errorf("evicted %v, want [%s %s]", invalidator.evicted, fromPayload, fromKey)
+	}
+}
@@ -4,6 +4,7 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,22 +13,59 @@ import (
 	"go.uber.org/zap"
 
 	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/cdr"
+	"voice-gateway/internal/domain/routing"
+	"voice-gateway/internal/domain/voicemail"
 )
 
+// DefaultMaxMessageBytes matches the broker's default message.max.bytes.
+const DefaultMaxMessageBytes = 1000000
+
+// ErrEventTooLarge is returned when an event exceeds the configured message
+// size limit. The event is rejected locally instead of being sent to Kafka.
+var ErrEventTooLarge = errors.New("event exceeds max message size")
+
+// PublisherOptions tunes the Kafka producer.
+type PublisherOptions struct {
+	// Compression is one of none, gzip, snappy, lz4 or zstd. Empty means snappy.
+	Compression string
+	// MaxMessageBytes caps key + value + headers before compression. Zero
+	// means DefaultMaxMessageBytes.
+	MaxMessageBytes int
+	// Topics maps event types to topics. Nil means PrefixTopics.
+	Topics TopicMapper
+}
+
 // Publisher publishes events to Kafka.
 type Publisher struct {
-	producer    sarama.SyncProducer
-	topicPrefix string
-	logger      *zap.Logger
+	producer        sarama.SyncProducer
+	topics          TopicMapper
+	maxMessageBytes int
+	logger          *zap.Logger
 }
 
 // NewPublisher creates a new Kafka event publisher.
-func NewPublisher(brokers []string, topicPrefix string, logger *zap.Logger) (*Publisher, error) {
+func NewPublisher(brokers []string, topicPrefix string, opts PublisherOptions, logger *zap.Logger) (*Publisher, error) {
+	codec, err := CompressionCodec(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+	maxBytes := opts.MaxMessageBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxMessageBytes
+	}
+	topics := opts.Topics
+	if topics == nil {
+		topics = PrefixTopics(topicPrefix)
+	}
+
 	config := sarama.NewConfig()
 	config.Producer.Return.Successes = true
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Producer.Retry.Max = 3
 	config.Producer.Idempotent = true
+	config.Producer.Compression = codec
+	config.Producer.MaxMessageBytes = maxBytes
 	config.Net.MaxOpenRequests = 1
 
 	producer, err := sarama.NewSyncProducer(brokers, config)
@@ -35,15 +73,55 @@ func NewPublisher(brokers []string, topicPrefix string, logger *zap.Logger) (*Pu
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
-	logger.Info("kafka producer created", zap.Strings("brokers", brokers))
+	logger.Info("kafka producer created",
+		zap.Strings("brokers", brokers),
+		zap.String("compression", opts.Compression),
+		zap.Int("max_message_bytes", maxBytes),
+	)
 
 	return &Publisher{
-		producer:    producer,
-		topicPrefix: topicPrefix,
-		logger:      logger,
+		producer:        producer,
+		topics:          topics,
+		maxMessageBytes: maxBytes,
+		logger:          logger,
 	}, nil
 }
 
+// NewPublisherWithProducer creates a publisher on an existing producer, e.g. a
+// sarama mock producer in tests. Events are capped at DefaultMaxMessageBytes
+// and published to PrefixTopics(topicPrefix) unless SetTopics changes it.
+func NewPublisherWithProducer(producer sarama.SyncProducer, topicPrefix string, logger *zap.Logger) *Publisher {
+	return &Publisher{
+		producer:        producer,
+		topics:          PrefixTopics(topicPrefix),
+		maxMessageBytes: DefaultMaxMessageBytes,
+		logger:          logger,
+	}
+}
+
+// SetTopics changes how event types map to topics.
+func (p *Publisher) SetTopics(topics TopicMapper) {
+	p.topics = topics
+}
+
+// CompressionCodec maps a KAFKA_COMPRESSION value to a sarama codec.
+func CompressionCodec(name string) (sarama.CompressionCodec, error) {
+	switch name {
+	case "", "snappy":
+		return sarama.CompressionSnappy, nil
+	case "none":
+		return sarama.CompressionNone, nil
+	case "gzip":
+		return sarama.CompressionGZIP, nil
+	case "lz4":
+		return sarama.CompressionLZ4, nil
+	case "zstd":
+		return sarama.CompressionZSTD, nil
+	default:
+		return sarama.CompressionNone, fmt.Errorf("unsupported Kafka compression %q (use none, gzip, snappy, lz4 or zstd)", name)
+	}
+}
+
 // Close closes the Kafka producer.
 func (p *Publisher) Close() error {
 	return p.producer.Close()
@@ -57,9 +135,12 @@ type CallEvent struct {
 	CallID         uuid.UUID              `json:"call_id"`
 	TenantID       uuid.UUID              `json:"tenant_id"`
 	ConversationID *uuid.UUID             `json:"conversation_id,omitempty"`
+	ParentCallID   *uuid.UUID             `json:"parent_call_id,omitempty"`   // the call a transfer leg was placed for
+	RelatedCallIDs []uuid.UUID            `json:"related_call_ids,omitempty"` // transfer legs placed for the call
 	Direction      string                 `json:"direction"`
 	CallerNumber   string                 `json:"caller_number"`
 	CalleeNumber   string                 `json:"callee_number"`
+	CustomerID     string                 `json:"customer_id,omitempty"`
 	State          string                 `json:"state"`
 	Duration       int64                  `json:"duration,omitempty"` // milliseconds
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
@@ -68,16 +149,18 @@ type CallEvent struct {
 // PublishCallStarted publishes a call.started event.
 func (p *Publisher) PublishCallStarted(ctx context.Context, c *call.Call) error {
 	event := CallEvent{
-		EventID:      uuid.New().String(),
-		EventType:    "call.started",
-		Timestamp:    time.Now().UTC(),
-		CallID:       c.ID,
-		TenantID:     c.TenantID,
-		Direction:    string(c.Direction),
-		CallerNumber: c.CallerNumber,
-		CalleeNumber: c.CalleeNumber,
-		State:        string(c.State),
-		Metadata:     c.Metadata,
+		EventID:        uuid.New().String(),
+		EventType:      "call.started",
+		Timestamp:      time.Now().UTC(),
+		CallID:         c.ID,
+		TenantID:       c.TenantID,
+		ParentCallID:   c.ParentCallID,
+		RelatedCallIDs: c.RelatedCallIDs,
+		Direction:      string(c.Direction),
+		CallerNumber:   c.CallerNumber,
+		CalleeNumber:   c.CalleeNumber,
+		State:          string(c.State),
+		Metadata:       c.Metadata,
 	}
 
 	return p.publishEvent(ctx, "call.started", c.ID.String(), event)
@@ -92,9 +175,12 @@ func (p *Publisher) PublishCallAnswered(ctx context.Context, c *call.Call) error
 		CallID:         c.ID,
 		TenantID:       c.TenantID,
 		ConversationID: &c.ConversationID,
+		ParentCallID:   c.ParentCallID,
+		RelatedCallIDs: c.RelatedCallIDs,
 		Direction:      string(c.Direction),
 		CallerNumber:   c.CallerNumber,
 		CalleeNumber:   c.CalleeNumber,
+		CustomerID:     c.CustomerID,
 		State:          string(c.State),
 		Metadata:       c.Metadata,
 	}
@@ -116,9 +202,12 @@ func (p *Publisher) PublishCallEnded(ctx context.Context, c *call.Call) error {
 		CallID:         c.ID,
 		TenantID:       c.TenantID,
 		ConversationID: &c.ConversationID,
+		ParentCallID:   c.ParentCallID,
+		RelatedCallIDs: c.RelatedCallIDs,
 		Direction:      string(c.Direction),
 		CallerNumber:   c.CallerNumber,
 		CalleeNumber:   c.CalleeNumber,
+		CustomerID:     c.CustomerID,
 		State:          string(c.State),
 		Duration:       duration,
 		Metadata:       c.Metadata,
@@ -127,6 +216,66 @@ func (p *Publisher) PublishCallEnded(ctx context.Context, c *call.Call) error {
 	return p.publishEvent(ctx, "call.ended", c.ID.String(), event)
 }
 
+// AbandonEvent represents a caller hanging up before reaching an agent.
+type AbandonEvent struct {
+	EventID         string    `json:"event_id"`
+	EventType       string    `json:"event_type"`
+	Timestamp       time.Time `json:"timestamp"`
+	CallID          uuid.UUID `json:"call_id"`
+	TenantID        uuid.UUID `json:"tenant_id"`
+	CallerNumber    string    `json:"caller_number"`
+	CalleeNumber    string    `json:"callee_number"`
+	Stage           string    `json:"stage"` // before_answer, before_agent, in_queue
+	TimeToAbandonMs int64     `json:"time_to_abandon_ms"`
+}
+
+// PublishCallAbandoned publishes a call.abandoned event.
+func (p *Publisher) PublishCallAbandoned(ctx context.Context, c *call.Call, stage string, timeToAbandon time.Duration) error {
+	event := AbandonEvent{
+		EventID:         uuid.New().String(),
+		EventType:       "call.abandoned",
+		Timestamp:       time.Now().UTC(),
+		CallID:          c.ID,
+		TenantID:        c.TenantID,
+		CallerNumber:    c.CallerNumber,
+		CalleeNumber:    c.CalleeNumber,
+		Stage:           stage,
+		TimeToAbandonMs: timeToAbandon.Milliseconds(),
+	}
+
+	return p.publishEvent(ctx, "call.abandoned", c.ID.String(), event)
+}
+
+// ConversationEndedEvent represents the end of a conversation on a call.
+type ConversationEndedEvent struct {
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	Timestamp      time.Time `json:"timestamp"`
+	CallID         uuid.UUID `json:"call_id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	AgentID        string    `json:"agent_id"`
+	TurnCount      int       `json:"turn_count"`
+	Reason         string    `json:"reason"` // ended, max_turns, shutdown
+}
+
+// PublishConversationEnded publishes a conversation.ended event.
+func (p *Publisher) PublishConversationEnded(ctx context.Context, callID, tenantID, conversationID uuid.UUID, agentID string, turnCount int, reason string) error {
+	event := ConversationEndedEvent{
+		EventID:        uuid.New().String(),
+		EventType:      "conversation.ended",
+		Timestamp:      time.Now().UTC(),
+		CallID:         callID,
+		TenantID:       tenantID,
+		ConversationID: conversationID,
+		AgentID:        agentID,
+		TurnCount:      turnCount,
+		Reason:         reason,
+	}
+
+	return p.publishEvent(ctx, "conversation.ended", callID.String(), event)
+}
+
 // TranscriptionEvent represents a speech transcription event.
 type TranscriptionEvent struct {
 	EventID        string    `json:"event_id"`
@@ -228,32 +377,245 @@ func (p *Publisher) PublishTTSGenerated(ctx context.Context, callID, tenantID, c
 
 // TransferEvent represents a call transfer event.
 type TransferEvent struct {
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Timestamp      time.Time  `json:"timestamp"`
+	CallID         uuid.UUID  `json:"call_id"`
+	TenantID       uuid.UUID  `json:"tenant_id"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	LegCallID      *uuid.UUID `json:"leg_call_id,omitempty"` // the call placed to the target of an external transfer
+	TransferType   string     `json:"transfer_type"`         // queue, agent, external
+	TransferTarget string     `json:"transfer_target"`
+	Reason         string     `json:"reason,omitempty"`
+}
+
+// PublishCallTransferred publishes a call.transferred event. leg is the call
+// placed to the target, or nil when the transfer placed none.
+func (p *Publisher) PublishCallTransferred(ctx context.Context, c, leg *call.Call, transferType, target, reason string) error {
+	event := TransferEvent{
+		EventID:        uuid.New().String(),
+		EventType:      "call.transferred",
+		Timestamp:      time.Now().UTC(),
+		CallID:         c.ID,
+		TenantID:       c.TenantID,
+		ConversationID: c.ConversationID,
+		TransferType:   transferType,
+		TransferTarget: target,
+		Reason:         reason,
+	}
+	if leg != nil {
+		event.LegCallID = &leg.ID
+	}
+
+	return p.publishEvent(ctx, "call.transferred", c.ID.String(), event)
+}
+
+// QualityEvent represents a call quality degradation event.
+type QualityEvent struct {
+	EventID        string       `json:"event_id"`
+	EventType      string       `json:"event_type"`
+	Timestamp      time.Time    `json:"timestamp"`
+	CallID         uuid.UUID    `json:"call_id"`
+	TenantID       uuid.UUID    `json:"tenant_id"`
+	ConversationID uuid.UUID    `json:"conversation_id"`
+	Quality        call.Quality `json:"quality"`
+	Breached       []string     `json:"breached"` // mos, jitter, packet_loss, latency
+}
+
+// PublishCallQualityDegraded publishes a call.quality_degraded event.
+func (p *Publisher) PublishCallQualityDegraded(ctx context.Context, c *call.Call, q call.Quality, breached []string) error {
+	event := QualityEvent{
+		EventID:        uuid.New().String(),
+		EventType:      "call.quality_degraded",
+		Timestamp:      time.Now().UTC(),
+		CallID:         c.ID,
+		TenantID:       c.TenantID,
+		ConversationID: c.ConversationID,
+		Quality:        q,
+		Breached:       breached,
+	}
+
+	return p.publishEvent(ctx, "call.quality_degraded", c.ID.String(), event)
+}
+
+// TurnLatencyEvent reports the speech-to-speech latency of a conversation
+// turn and its breakdown, in milliseconds.
+type TurnLatencyEvent struct {
 	EventID        string    `json:"event_id"`
 	EventType      string    `json:"event_type"`
 	Timestamp      time.Time `json:"timestamp"`
 	CallID         uuid.UUID `json:"call_id"`
 	TenantID       uuid.UUID `json:"tenant_id"`
 	ConversationID uuid.UUID `json:"conversation_id"`
-	TransferType   string    `json:"transfer_type"` // queue, agent, external
-	TransferTarget string    `json:"transfer_target"`
-	Reason         string    `json:"reason,omitempty"`
+	TurnID         uuid.UUID `json:"turn_id"`
+	STTProvider    string    `json:"stt_provider"`
+	TTSProvider    string    `json:"tts_provider"`
+	STTMs          int64     `json:"stt_ms"`
+	LLMMs          int64     `json:"llm_ms"`
+	TTSMs          int64     `json:"tts_ms"`
+	OverheadMs     int64     `json:"overhead_ms"`
+	TotalMs        int64     `json:"total_ms"`
+	BudgetMs       int64     `json:"budget_ms,omitempty"`
+	OverBudget     bool      `json:"over_budget"`
 }
 
-// PublishCallTransferred publishes a call.transferred event.
-func (p *Publisher) PublishCallTransferred(ctx context.Context, callID, tenantID, conversationID uuid.UUID, transferType, target, reason string) error {
-	event := TransferEvent{
+// PublishTurnLatency publishes a turn.latency event.
+func (p *Publisher) PublishTurnLatency(ctx context.Context, c *call.Call, l call.TurnLatency) error {
+	event := TurnLatencyEvent{
 		EventID:        uuid.New().String(),
-		EventType:      "call.transferred",
+		EventType:      "turn.latency",
 		Timestamp:      time.Now().UTC(),
-		CallID:         callID,
-		TenantID:       tenantID,
-		ConversationID: conversationID,
-		TransferType:   transferType,
-		TransferTarget: target,
-		Reason:         reason,
+		CallID:         c.ID,
+		TenantID:       c.TenantID,
+		ConversationID: c.ConversationID,
+		TurnID:         l.TurnID,
+		STTProvider:    c.STTProvider,
+		TTSProvider:    c.TTSProvider,
+		STTMs:          l.STT.Milliseconds(),
+		LLMMs:          l.LLM.Milliseconds(),
+		TTSMs:          l.TTS.Milliseconds(),
+		OverheadMs:     l.Overhead().Milliseconds(),
+		TotalMs:        l.Total.Milliseconds(),
+		BudgetMs:       l.Budget.Milliseconds(),
+		OverBudget:     l.OverBudget(),
+	}
+
+	return p.publishEvent(ctx, "turn.latency", c.ID.String(), event)
+}
+
+// RoutingEvent represents an agent routing decision event.
+type RoutingEvent struct {
+	EventID      string    `json:"event_id"`
+	EventType    string    `json:"event_type"`
+	Timestamp    time.Time `json:"timestamp"`
+	CallID       uuid.UUID `json:"call_id"`
+	TenantID     uuid.UUID `json:"tenant_id"`
+	DID          string    `json:"did"`
+	CallerNumber string    `json:"caller_number"`
+	AgentID      string    `json:"agent_id"`
+	RuleID       string    `json:"rule_id,omitempty"`
+	RuleName     string    `json:"rule_name,omitempty"`
+	FlowID       string    `json:"flow_id,omitempty"`
+	Assigned     bool      `json:"assigned,omitempty"` // picked by the DID's assignment
+	Fallback     bool      `json:"fallback"`
+	Closed       bool      `json:"closed"`
+}
+
+// PublishRoutingDecision publishes a routing.decision event.
+func (p *Publisher) PublishRoutingDecision(ctx context.Context, c *call.Call, decision routing.Decision) error {
+	event := RoutingEvent{
+		EventID:      uuid.New().String(),
+		EventType:    "routing.decision",
+		Timestamp:    time.Now().UTC(),
+		CallID:       c.ID,
+		TenantID:     c.TenantID,
+		DID:          c.CalleeNumber,
+		CallerNumber: c.CallerNumber,
+		AgentID:      decision.AgentID,
+		RuleID:       decision.RuleID,
+		RuleName:     decision.RuleName,
+		FlowID:       decision.FlowID,
+		Assigned:     decision.Assigned,
+		Fallback:     decision.Fallback,
+		Closed:       decision.Closed,
+	}
+
+	return p.publishEvent(ctx, "routing.decision", c.ID.String(), event)
+}
+
+// QueueEvent represents a hold-queue event.
+type QueueEvent struct {
+	EventID   string    `json:"event_id"`
+	EventType string    `json:"event_type"`
+	Timestamp time.Time `json:"timestamp"`
+	CallID    uuid.UUID `json:"call_id"`
+	TenantID  uuid.UUID `json:"tenant_id"`
+	Position  int       `json:"position,omitempty"`
+	WaitTime  int64     `json:"wait_time,omitempty"` // milliseconds
+	Reason    string    `json:"reason,omitempty"`    // answered, timeout, abandoned
+}
+
+// PublishCallQueued publishes a call.queued event.
+func (p *Publisher) PublishCallQueued(ctx context.Context, callID, tenantID uuid.UUID, position int) error {
+	event := QueueEvent{
+		EventID:   uuid.New().String(),
+		EventType: "call.queued",
+		Timestamp: time.Now().UTC(),
+		CallID:    callID,
+		TenantID:  tenantID,
+		Position:  position,
 	}
 
-	return p.publishEvent(ctx, "call.transferred", callID.String(), event)
+	return p.publishEvent(ctx, "call.queued", callID.String(), event)
+}
+
+// PublishCallDequeued publishes a call.dequeued event.
+func (p *Publisher) PublishCallDequeued(ctx context.Context, callID, tenantID uuid.UUID, waitTime time.Duration, reason string) error {
+	event := QueueEvent{
+		EventID:   uuid.New().String(),
+		EventType: "call.dequeued",
+		Timestamp: time.Now().UTC(),
+		CallID:    callID,
+		TenantID:  tenantID,
+		WaitTime:  waitTime.Milliseconds(),
+		Reason:    reason,
+	}
+
+	return p.publishEvent(ctx, "call.dequeued", callID.String(), event)
+}
+
+// VoicemailEvent represents a voicemail event.
+type VoicemailEvent struct {
+	EventID      string    `json:"event_id"`
+	EventType    string    `json:"event_type"`
+	Timestamp    time.Time `json:"timestamp"`
+	VoicemailID  uuid.UUID `json:"voicemail_id"`
+	CallID       uuid.UUID `json:"call_id"`
+	TenantID     uuid.UUID `json:"tenant_id"`
+	CallerNumber string    `json:"caller_number"`
+	Reason       string    `json:"reason"`
+	RecordingURI string    `json:"recording_uri"`
+	Transcript   string    `json:"transcript,omitempty"`
+	Duration     int64     `json:"duration"` // milliseconds
+}
+
+// PublishVoicemailLeft publishes a voicemail.left event.
+func (p *Publisher) PublishVoicemailLeft(ctx context.Context, v *voicemail.Voicemail) error {
+	event := VoicemailEvent{
+		EventID:      uuid.New().String(),
+		EventType:    "voicemail.left",
+		Timestamp:    time.Now().UTC(),
+		VoicemailID:  v.ID,
+		CallID:       v.CallID,
+		TenantID:     v.TenantID,
+		CallerNumber: v.CallerNumber,
+		Reason:       v.Reason,
+		RecordingURI: v.RecordingURI,
+		Transcript:   v.Transcript,
+		Duration:     v.Duration.Milliseconds(),
+	}
+
+	return p.publishEvent(ctx, "voicemail.left", v.CallID.String(), event)
+}
+
+// CDREvent carries the call detail record of an ended call.
+type CDREvent struct {
+	EventID   string    `json:"event_id"`
+	EventType string    `json:"event_type"`
+	Timestamp time.Time `json:"timestamp"`
+	cdr.Record
+}
+
+// PublishCDRCreated publishes a cdr.created event.
+func (p *Publisher) PublishCDRCreated(ctx context.Context, record *cdr.Record) error {
+	event := CDREvent{
+		EventID:   uuid.New().String(),
+		EventType: "cdr.created",
+		Timestamp: time.Now().UTC(),
+		Record:    *record,
+	}
+
+	return p.publishEvent(ctx, "cdr.created", record.CallID.String(), event)
 }
 
 // ErrorEvent represents an error event.
@@ -286,19 +648,164 @@ func (p *Publisher) PublishError(ctx context.Context, callID, tenantID uuid.UUID
 	return p.publishEvent(ctx, fmt.Sprintf("error.%s", errorType), callID.String(), event)
 }
 
+// ProviderTimeoutEvent represents a provider call that exceeded its time budget.
+type ProviderTimeoutEvent struct {
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	Timestamp      time.Time `json:"timestamp"`
+	CallID         uuid.UUID `json:"call_id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	Component      string    `json:"component"` // stt, tts, agent
+	Provider       string    `json:"provider"`
+	BudgetMs       int64     `json:"budget_ms"`
+}
+
+// PublishProviderTimeout publishes a provider timeout event.
+func (p *Publisher) PublishProviderTimeout(ctx context.Context, c *call.Call, component, provider string, budget time.Duration) error {
+	event := ProviderTimeoutEvent{
+		EventID:        uuid.New().String(),
+		EventType:      "provider.timeout",
+		Timestamp:      time.Now().UTC(),
+		CallID:         c.ID,
+		TenantID:       c.TenantID,
+		ConversationID: c.ConversationID,
+		Component:      component,
+		Provider:       provider,
+		BudgetMs:       budget.Milliseconds(),
+	}
+
+	return p.publishEvent(ctx, "provider.timeout", c.ID.String(), event)
+}
+
+// MonitorEvent represents a supervisor starting to monitor a call.
+type MonitorEvent struct {
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	Timestamp      time.Time `json:"timestamp"`
+	CallID         uuid.UUID `json:"call_id"`
+	TenantID       uuid.UUID `json:"tenant_id"`
+	ConversationID uuid.UUID `json:"conversation_id"`
+	MonitorID      uuid.UUID `json:"monitor_id"`
+	SupervisorID   string    `json:"supervisor_id"`
+	Mode           string    `json:"mode"` // spy, whisper, barge
+}
+
+// PublishCallMonitorStarted publishes a call.monitor_started event for auditing.
+func (p *Publisher) PublishCallMonitorStarted(ctx context.Context, c *call.Call, m *call.Monitor) error {
+	event := MonitorEvent{
+		EventID:        uuid.New().String(),
+		EventType:      "call.monitor_started",
+		Timestamp:      time.Now().UTC(),
+		CallID:         c.ID,
+		TenantID:       c.TenantID,
+		ConversationID: c.ConversationID,
+		MonitorID:      m.ID,
+		SupervisorID:   m.SupervisorID,
+		Mode:           string(m.Mode),
+	}
+
+	return p.publishEvent(ctx, "call.monitor_started", c.ID.String(), event)
+}
+
+// DestinationBlockedEvent represents an outbound dial refused because the
+// destination country is not in the tenant's allowlist.
+type DestinationBlockedEvent struct {
+	EventID     string    `json:"event_id"`
+	EventType   string    `json:"event_type"`
+	Timestamp   time.Time `json:"timestamp"`
+	CallID      uuid.UUID `json:"call_id"`
+	TenantID    uuid.UUID `json:"tenant_id"`
+	Destination string    `json:"destination"` // E.164
+	Country     string    `json:"country"`     // ISO 3166-1 alpha-2
+}
+
+// PublishDestinationBlocked publishes a call.destination_blocked event, so
+// toll-fraud attempts can be alerted on.
+func (p *Publisher) PublishDestinationBlocked(ctx context.Context, c *call.Call, destination, country string) error {
+	event := DestinationBlockedEvent{
+		EventID:     uuid.New().String(),
+		EventType:   "call.destination_blocked",
+		Timestamp:   time.Now().UTC(),
+		CallID:      c.ID,
+		TenantID:    c.TenantID,
+		Destination: destination,
+		Country:     country,
+	}
+
+	return p.publishEvent(ctx, "call.destination_blocked", c.ID.String(), event)
+}
+
+// StateStoreEvent represents the call state store degrading or recovering.
+type StateStoreEvent struct {
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Timestamp  time.Time `json:"timestamp"`
+	Store      string    `json:"store"`
+	Fallback   bool      `json:"fallback"` // calls continue from in-memory state
+	Reason     string    `json:"reason,omitempty"`
+	DowntimeMs int64     `json:"downtime_ms,omitempty"`
+	Reconciled int       `json:"reconciled,omitempty"` // calls written back after recovery
+}
+
+// PublishStateStoreDegraded publishes a state_store.degraded event.
+func (p *Publisher) PublishStateStoreDegraded(ctx context.Context, store, reason string, fallback bool) error {
+	event := StateStoreEvent{
+		EventID:   uuid.New().String(),
+		EventType: "state_store.degraded",
+		Timestamp: time.Now().UTC(),
+		Store:     store,
+		Fallback:  fallback,
+		Reason:    reason,
+	}
+
+	return p.publishEvent(ctx, "state_store.degraded", store, event)
+}
+
+// PublishStateStoreRecovered publishes a state_store.recovered event.
+func (p *Publisher) PublishStateStoreRecovered(ctx context.Context, store string, downtime time.Duration, reconciled int, fallback bool) error {
+	event := StateStoreEvent{
+		EventID:    uuid.New().String(),
+		EventType:  "state_store.recovered",
+		Timestamp:  time.Now().UTC(),
+		Store:      store,
+		Fallback:   fallback,
+		DowntimeMs: downtime.Milliseconds(),
+		Reconciled: reconciled,
+	}
+
+	return p.publishEvent(ctx, "state_store.recovered", store, event)
+}
+
 // publishEvent publishes an event to Kafka.
 func (p *Publisher) publishEvent(ctx context.Context, eventType, key string, payload interface{}) error {
-	topic := fmt.Sprintf("%s.%s", p.topicPrefix, eventType)
+	topic := p.topics(eventType)
 
 	value, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	// Size is checked before compression, like the broker's own limit on
+	// uncompressed batches, so the caller gets a clear error instead of a
+	// generic rejection after retries
+	if size := len(key) + len(value) + len(EventTypeHeader) + len(eventType); size > p.maxMessageBytes {
+		p.logger.Error("event too large to publish",
+			zap.String("event_type", eventType),
+			zap.Int("size", size),
+			zap.Int("max_message_bytes", p.maxMessageBytes),
+		)
+		return fmt.Errorf("%w: %s event for key %s is %d bytes, over the %d byte limit (KAFKA_MAX_MESSAGE_BYTES)",
+			ErrEventTooLarge, eventType, key, size, p.maxMessageBytes)
+	}
+
 	msg := &sarama.ProducerMessage{
 		Topic: topic,
 		Key:   sarama.StringEncoder(key),
 		Value: sarama.ByteEncoder(value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(EventTypeHeader), Value: []byte(eventType)},
+		},
 	}
 
 	partition, offset, err := p.producer.SendMessage(msg)
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestPublishRejectsOversizedEventBeforeSending(t *testing.T) {
+	// No expectations: any SendMessage call fails the test
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	p := NewPublisherWithProducer(producer, "serphona", zap.NewNop())
+
+	callID := uuid.New()
+	text := strings.Repeat("a", DefaultMaxMessageBytes)
+	err := p.PublishLLMResponded(context.Background(), callID, uuid.New(), uuid.New(), "agent-1", text, time.Second)
+
+	if !errors.Is(err, ErrEventTooLarge) {
+		t.Fatalf("expected ErrEventTooLarge, got %v", err)
+	}
+	for _, want := range []string{"llm.responded", callID.String(), "1000000 byte limit", "KAFKA_MAX_MESSAGE_BYTES"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+}
+
+func TestPublishSendsEventWithinLimit(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "serphona.llm.responded" {
+			t.Errorf("unexpected topic %q", msg.Topic)
+		}
+		return nil
+	})
+	p := NewPublisherWithProducer(producer, "serphona", zap.NewNop())
+
+	err := p.PublishLLMResponded(context.Background(), uuid.New(), uuid.New(), uuid.New(), "agent-1", "Olá!", time.Second)
+	if err != nil {
+		t.Fatalf("expected event to be published, got %v", err)
+	}
+}
+
+func TestPublishStateStoreDegraded(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "serphona.state_store.degraded" {
+			t.Errorf("unexpected topic %q", msg.Topic)
+		}
+		value, _ := msg.Value.Encode()
+		var event StateStoreEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+		if event.Store != "redis" || !event.Fallback || event.Reason != "connection refused" {
+			t.Errorf("unexpected event %+v", event)
+		}
+		return nil
+	})
+	p := NewPublisherWithProducer(producer, "serphona", zap.NewNop())
+
+	if err := p.PublishStateStoreDegraded(context.Background(), "redis", "connection refused", true); err != nil {
+		t.Fatalf("expected event to be published, got %v", err)
+	}
+}
+
+func TestCompressionCodec(t *testing.T) {
+	tests := map[string]sarama.CompressionCodec{
+		"":       sarama.CompressionSnappy,
+		"none":   sarama.CompressionNone,
+		"gzip":   sarama.CompressionGZIP,
+		"snappy": sarama.CompressionSnappy,
+		"lz4":    sarama.CompressionLZ4,
+		"zstd":   sarama.CompressionZSTD,
+	}
+	for name, want := range tests {
+		got, err := CompressionCodec(name)
+		if err != nil || got != want {
+			t.Errorf("CompressionCodec(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+
+	if _, err := CompressionCodec("brotli"); err == nil {
+		t.Error("expected unknown codec to be rejected")
+	}
+}
+
+func TestTopicMappers(t *testing.T) {
+	grouped := GroupedTopics("serphona", map[string]string{
+		"call.*":           "calls",
+		"call.quality_*":   "quality",
+		"call.transferred": "transfers",
+		"error.*":          "errors",
+		"state_store.*":    "platform",
+		"voicemail.left":   "voicemail",
+	})
+
+	tests := []struct {
+		mapper    TopicMapper
+		eventType string
+		want      string
+	}{
+		{PrefixTopics("serphona"), "call.started", "serphona.call.started"},
+		{PrefixTopics("serphona"), "error.stt", "serphona.error.stt"},
+		{grouped, "call.started", "serphona.calls"},
+		{grouped, "call.ended", "serphona.calls"},
+		{grouped, "call.transferred", "serphona.transfers"},
+		{grouped, "call.quality_degraded", "serphona.quality"},
+		{grouped, "error.tts_timeout", "serphona.errors"},
+		{grouped, "voicemail.left", "serphona.voicemail"},
+		{grouped, "llm.responded", "serphona.llm.responded"},
+	}
+	for _, tt := range tests {
+		if got := tt.mapper(tt.eventType); got != tt.want {
+			t.Errorf("topic for %s = %q, want %q", tt.eventType, got, tt.want)
+		}
+	}
+}
+
+func TestPublishToGroupedTopic(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "serphona.platform" {
+			t.Errorf("unexpected topic %q", msg.Topic)
+		}
+		if len(msg.Headers) != 1 || string(msg.Headers[0].Key) != EventTypeHeader || string(msg.Headers[0].Value) != "state_store.recovered" {
+			t.Errorf("expected the event type header, got %+v", msg.Headers)
+		}
+		return nil
+	})
+	p := NewPublisherWithProducer(producer, "serphona", zap.NewNop())
+	p.SetTopics(GroupedTopics("serphona", map[string]string{"state_store.*": "platform"}))
+
+	if err := p.PublishStateStoreRecovered(context.Background(), "redis", time.Minute, 3, true); err != nil {
+		t.Fatalf("expected event to be published, got %v", err)
+	}
+}
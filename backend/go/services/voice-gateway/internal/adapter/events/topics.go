@@ -0,0 +1,50 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EventTypeHeader is the message header carrying the event type, so
+// consumers of a topic shared by several event types can tell them apart
+// without decoding the payload.
+const EventTypeHeader = "event_type"
+
+// TopicMapper returns the topic an event type is published to.
+type TopicMapper func(eventType string) string
+
+// PrefixTopics publishes every event type to its own topic,
+// <prefix>.<eventType>.
+func PrefixTopics(prefix string) TopicMapper {
+	return func(eventType string) string {
+		return fmt.Sprintf("%s.%s", prefix, eventType)
+	}
+}
+
+// GroupedTopics publishes related event types to shared topics. groups maps
+// an event type, or a family of them such as "call.*", to a topic name under
+// prefix: {"call.*": "calls"} publishes call.started and call.ended to
+// <prefix>.calls. An exact event type wins over a family, and a longer family
+// over a shorter one; event types in no group get their own topic, as with
+// PrefixTopics.
+func GroupedTopics(prefix string, groups map[string]string) TopicMapper {
+	fallback := PrefixTopics(prefix)
+	return func(eventType string) string {
+		if topic, ok := groups[eventType]; ok {
+			return fmt.Sprintf("%s.%s", prefix, topic)
+		}
+
+		best := ""
+		for pattern := range groups {
+			family, ok := strings.CutSuffix(pattern, "*")
+			if ok && strings.HasPrefix(eventType, family) && len(family) > len(best) {
+				best = family
+			}
+		}
+		if best != "" {
+			return fmt.Sprintf("%s.%s", prefix, groups[best+"*"])
+		}
+
+		return fallback(eventType)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+import "net/http"
+
+// RoleSuperadmin is the platform operator role forwarded by the gateway.
+const RoleSuperadmin = "superadmin"
+
+// RequireSuperadmin lets through only requests the gateway authenticated as
+// a superadmin.
+func RequireSuperadmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User-ID") == "" {
+			writeError(w, r, http.StatusUnauthorized, CodeUnauthorized, "user identity is required")
+			return
+		}
+		if r.Header.Get("X-User-Role") != RoleSuperadmin {
+			writeError(w, r, http.StatusForbidden, CodeForbidden, "superadmin role is required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
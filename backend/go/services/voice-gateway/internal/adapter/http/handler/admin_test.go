@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loglevel"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func putLogLevel(h http.Handler, role, body string) int {
+	req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(body))
+	req.Header.Set("X-User-ID", "ops-1")
+	req.Header.Set("X-User-Role", role)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestLogLevelEndpoint_EnablesDebugLogs(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	core, logs := observer.New(level)
+	logger := zap.New(core)
+	h := RequireSuperadmin(loglevel.New(level, zap.NewNop()))
+
+	if code := putLogLevel(h, RoleSuperadmin, `{"level":"debug","ttl":"10m"}`); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+
+	logger.Debug("call state dump")
+	if logs.FilterMessage("call state dump").Len() != 1 {
+		t.Error("Expected debug logs after raising the level")
+	}
+}
+
+func TestLogLevelEndpoint_RequiresSuperadmin(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	h := RequireSuperadmin(loglevel.New(level, zap.NewNop()))
+
+	if code := putLogLevel(h, "admin", `{"level":"debug"}`); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a tenant admin, got %d", code)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level":"debug"}`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without an identity, got %d", rec.Code)
+	}
+
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("Expected the level unchanged, got %s", level.Level())
+	}
+}
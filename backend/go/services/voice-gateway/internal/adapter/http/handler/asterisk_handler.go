@@ -2,7 +2,9 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -10,20 +12,67 @@ import (
 
 	"voice-gateway/internal/adapter/asterisk"
 	callservice "voice-gateway/internal/application/call"
+	routingservice "voice-gateway/internal/application/routing"
+	voicemailservice "voice-gateway/internal/application/voicemail"
+	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/routing"
+	"voice-gateway/internal/domain/voicemail"
 )
 
+// ARIEventHandlerFunc handles a single decoded ARI event.
+type ARIEventHandlerFunc func(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent)
+
+// EventDeduplicator records ARI events so that redelivered events are skipped.
+type EventDeduplicator interface {
+	// FirstSeen marks the event key as seen and reports whether it was new.
+	FirstSeen(ctx context.Context, key string) (bool, error)
+
+	// Forget removes the event key so that a redelivery is processed again.
+	Forget(ctx context.Context, key string) error
+}
+
 // AsteriskHandler handles Asterisk ARI webhook events.
 type AsteriskHandler struct {
-	callService *callservice.Service
-	logger      *zap.Logger
+	callService      *callservice.Service
+	routingService   *routingservice.Service
+	voicemailService *voicemailservice.Service
+	dedup            EventDeduplicator // optional; events are not deduplicated when nil
+	handlers         map[string]ARIEventHandlerFunc
+	logger           *zap.Logger
 }
 
 // NewAsteriskHandler creates a new Asterisk webhook handler.
-func NewAsteriskHandler(callService *callservice.Service, logger *zap.Logger) *AsteriskHandler {
-	return &AsteriskHandler{
-		callService: callService,
-		logger:      logger,
+func NewAsteriskHandler(
+	callService *callservice.Service,
+	routingService *routingservice.Service,
+	voicemailService *voicemailservice.Service,
+	dedup EventDeduplicator,
+	logger *zap.Logger,
+) *AsteriskHandler {
+	h := &AsteriskHandler{
+		callService:      callService,
+		routingService:   routingService,
+		voicemailService: voicemailService,
+		dedup:            dedup,
+		handlers:         make(map[string]ARIEventHandlerFunc),
+		logger:           logger,
 	}
+
+	h.Register("StasisStart", h.handleStasisStart)
+	h.Register("StasisEnd", h.handleStasisEnd)
+	h.Register("ChannelAnswered", h.handleChannelAnswered)
+	h.Register("ChannelHangupRequest", h.handleChannelHangup)
+	h.Register("ChannelDestroyed", h.handleChannelDestroyed)
+	h.Register("PlaybackFinished", h.handlePlaybackFinished)
+	h.Register("RecordingFinished", h.handleRecordingFinished)
+	h.Register("RecordingFailed", h.handleRecordingFailed)
+
+	return h
+}
+
+// Register sets the handler for an ARI event type, replacing any existing one.
+func (h *AsteriskHandler) Register(eventType string, fn ARIEventHandlerFunc) {
+	h.handlers[eventType] = fn
 }
 
 // HandleARIEvent handles POST /asterisk/events (ARI webhook).
@@ -31,7 +80,7 @@ func (h *AsteriskHandler) HandleARIEvent(w http.ResponseWriter, r *http.Request)
 	var event asterisk.ARIEvent
 	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
 		h.logger.Error("failed to decode ARI event", zap.Error(err))
-		h.respondError(w, http.StatusBadRequest, "invalid event format")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidEvent, "invalid event format")
 		return
 	}
 
@@ -40,28 +89,102 @@ func (h *AsteriskHandler) HandleARIEvent(w http.ResponseWriter, r *http.Request)
 		zap.String("timestamp", event.Timestamp),
 	)
 
-	// Route event based on type
-	switch event.Type {
-	case "StasisStart":
-		h.handleStasisStart(w, r, &event)
-	case "StasisEnd":
-		h.handleStasisEnd(w, r, &event)
-	case "ChannelAnswered":
-		h.handleChannelAnswered(w, r, &event)
-	case "ChannelHangupRequest":
-		h.handleChannelHangup(w, r, &event)
-	case "ChannelDestroyed":
-		h.handleChannelDestroyed(w, r, &event)
-	default:
+	fn, ok := h.handlers[event.Type]
+	if !ok {
 		h.logger.Debug("unhandled ARI event type", zap.String("type", event.Type))
 		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// ARI may redeliver events after a reconnect
+	key, duplicate := h.isDuplicate(r.Context(), &event)
+	if duplicate {
+		h.logger.Info("ignoring duplicate ARI event",
+			zap.String("type", event.Type),
+			zap.String("timestamp", event.Timestamp),
+		)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	fn(rec, r, &event)
+
+	// A failed event must not be skipped as a duplicate when redelivered
+	if key != "" && rec.status >= http.StatusBadRequest {
+		if err := h.dedup.Forget(context.WithoutCancel(r.Context()), key); err != nil {
+			h.logger.Warn("failed to forget failed ARI event",
+				zap.String("type", event.Type),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// isDuplicate reports whether the event was already processed, and the dedup
+// key it was recorded under, if any. Events are processed when the dedup
+// store fails, since dropping them would lose calls.
+func (h *AsteriskHandler) isDuplicate(ctx context.Context, event *asterisk.ARIEvent) (string, bool) {
+	if h.dedup == nil || event.Timestamp == "" {
+		return "", false
+	}
+
+	key := fmt.Sprintf("%s:%s:%s", event.Type, eventChannelID(event), event.Timestamp)
+	firstSeen, err := h.dedup.FirstSeen(ctx, key)
+	if err != nil {
+		h.logger.Warn("failed to deduplicate ARI event",
+			zap.String("type", event.Type),
+			zap.Error(err),
+		)
+		return "", false
+	}
+	if !firstSeen {
+		return "", true
+	}
+
+	return key, false
+}
+
+// statusRecorder captures the status an event handler responded with.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status before sending it.
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write sends the body, implying status 200 if none was set.
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// eventChannelID returns the channel an event refers to, if any.
+func eventChannelID(event *asterisk.ARIEvent) string {
+	switch {
+	case event.Channel != nil:
+		return event.Channel.ID
+	case event.Playback != nil:
+		return event.Playback.ChannelID()
+	case event.Recording != nil:
+		return event.Recording.ChannelID()
+	default:
+		return ""
 	}
 }
 
 // handleStasisStart handles incoming call events.
 func (h *AsteriskHandler) handleStasisStart(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent) {
 	if event.Channel == nil {
-		h.respondError(w, http.StatusBadRequest, "channel is required")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidEvent, "channel is required")
 		return
 	}
 
@@ -70,18 +193,38 @@ func (h *AsteriskHandler) handleStasisStart(w http.ResponseWriter, r *http.Reque
 	callerNumber := event.Channel.Caller.Number
 	calleeNumber := event.Channel.Connected.Number
 
-	// TODO: Lookup tenant by DID (callee number)
-	// For now, use a placeholder tenant ID
-	tenantID := uuid.New()
+	// The call belongs to the tenant holding the dialed DID
+	did, err := h.callService.NormalizeDID(calleeNumber)
+	if err != nil {
+		h.logger.Warn("invalid dialed number",
+			zap.Error(err),
+			zap.String("channel_id", channelID),
+		)
+		status, code := serviceErrorStatus(err)
+		writeError(w, r, status, code, "invalid dialed number")
+		return
+	}
+	tenantID, err := h.routingService.ResolveTenant(r.Context(), did)
+	if err != nil {
+		h.logger.Error("failed to resolve tenant by DID",
+			zap.Error(err),
+			zap.String("channel_id", channelID),
+			zap.String("did", did),
+		)
+		status, code := serviceErrorStatus(err)
+		writeError(w, r, status, code, "failed to resolve the dialed number")
+		return
+	}
 
 	// Handle incoming call
-	call, err := h.callService.HandleIncomingCall(r.Context(), channelID, callerNumber, calleeNumber, tenantID)
+	call, err := h.callService.HandleIncomingCall(r.Context(), channelID, callerNumber, did, tenantID)
 	if err != nil {
 		h.logger.Error("failed to handle incoming call",
 			zap.Error(err),
 			zap.String("channel_id", channelID),
 		)
-		h.respondError(w, http.StatusInternalServerError, "failed to handle call")
+		status, code := serviceErrorStatus(err)
+		writeError(w, r, status, code, "failed to handle call")
 		return
 	}
 
@@ -93,11 +236,107 @@ func (h *AsteriskHandler) handleStasisStart(w http.ResponseWriter, r *http.Reque
 	// Auto-answer the call
 	if err := h.callService.AnswerCall(r.Context(), call.ID); err != nil {
 		h.logger.Error("failed to answer call", zap.Error(err))
+		w.WriteHeader(http.StatusOK)
+		return
 	}
 
+	// Wait in the hold queue when at capacity
+	queued, err := h.callService.QueueIfBusy(r.Context(), call.ID)
+	if err != nil {
+		h.logger.Error("failed to queue call", zap.Error(err))
+	}
+	if queued {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	h.RouteCall(r.Context(), call)
+
 	w.WriteHeader(http.StatusOK)
 }
 
+// RouteCall selects an agent for an answered call and starts the conversation.
+// It is also used for calls leaving the hold queue.
+func (h *AsteriskHandler) RouteCall(ctx context.Context, c *call.Call) {
+	decision, err := h.routingService.SelectAgent(ctx, c, nil)
+	if err != nil {
+		h.logger.Error("failed to select agent",
+			zap.Error(err),
+			zap.String("call_id", c.ID.String()),
+		)
+		h.startVoicemail(ctx, c.ID, voicemail.ReasonAgentUnavailable)
+		return
+	}
+
+	// Outside business hours
+	if decision.Closed {
+		h.handleClosed(ctx, c.ID, decision)
+		return
+	}
+
+	if err := h.callService.StartConversation(ctx, c.ID, decision.AgentID); err != nil {
+		h.logger.Error("failed to start conversation", zap.Error(err))
+	}
+}
+
+// handleClosed handles calls received outside business hours.
+func (h *AsteriskHandler) handleClosed(ctx context.Context, callID uuid.UUID, decision *routing.Decision) {
+	h.logger.Info("call received outside business hours",
+		zap.String("call_id", callID.String()),
+		zap.String("closed_action", decision.ClosedAction),
+	)
+
+	switch decision.ClosedAction {
+	case routing.ClosedActionAgent:
+		if decision.Announcement != "" {
+			if err := h.callService.PlayAnnouncement(ctx, callID, decision.Announcement, false); err != nil {
+				h.logger.Error("failed to play closed announcement", zap.Error(err))
+			}
+		}
+		if err := h.callService.StartConversation(ctx, callID, decision.AgentID); err != nil {
+			h.logger.Error("failed to start after-hours conversation", zap.Error(err))
+		}
+	case routing.ClosedActionVoicemail:
+		if decision.Announcement != "" {
+			if err := h.callService.PlayAnnouncement(ctx, callID, decision.Announcement, false); err != nil {
+				h.logger.Error("failed to play closed announcement", zap.Error(err))
+			}
+		}
+		h.startVoicemail(ctx, callID, voicemail.ReasonClosed)
+	default:
+		if decision.Announcement == "" {
+			if err := h.callService.EndCall(ctx, callID); err != nil {
+				h.logger.Error("failed to end closed call", zap.Error(err))
+			}
+			return
+		}
+		if err := h.callService.PlayAnnouncement(ctx, callID, decision.Announcement, true); err != nil {
+			h.logger.Error("failed to play closed announcement", zap.Error(err))
+			if err := h.callService.EndCall(ctx, callID); err != nil {
+				h.logger.Error("failed to end closed call", zap.Error(err))
+			}
+		}
+	}
+}
+
+// startVoicemail records a voicemail, ending the call if voicemail can't be recorded.
+func (h *AsteriskHandler) startVoicemail(ctx context.Context, callID uuid.UUID, reason string) {
+	started, err := h.voicemailService.Start(ctx, callID, reason)
+	if err != nil {
+		h.logger.Error("failed to start voicemail",
+			zap.Error(err),
+			zap.String("call_id", callID.String()),
+		)
+	}
+	if started {
+		return
+	}
+
+	if err := h.callService.EndCall(ctx, callID); err != nil {
+		h.logger.Error("failed to end call", zap.Error(err))
+	}
+}
+
 // handleStasisEnd handles when a channel leaves the Stasis application.
 func (h *AsteriskHandler) handleStasisEnd(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent) {
 	if event.Channel == nil {
@@ -126,10 +365,23 @@ func (h *AsteriskHandler) handleChannelAnswered(w http.ResponseWriter, r *http.R
 		zap.String("channel_id", event.Channel.ID),
 	)
 
-	// TODO: Trigger conversation start
-	// - Get tenant config
-	// - Select agent
-	// - Start STT/TTS loop
+	// Greet the caller before listening. Agent selection and conversation
+	// start happen on StasisStart after the call is answered by the gateway.
+	c, err := h.callService.GetCallByChannelID(r.Context(), event.Channel.ID)
+	if err != nil {
+		h.logger.Warn("answered channel has no call",
+			zap.String("channel_id", event.Channel.ID),
+			zap.Error(err),
+		)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if _, err := h.callService.PlayGreeting(r.Context(), c.ID); err != nil {
+		h.logger.Error("failed to play greeting",
+			zap.String("call_id", c.ID.String()),
+			zap.Error(err),
+		)
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -147,9 +399,12 @@ func (h *AsteriskHandler) handleChannelHangup(w http.ResponseWriter, r *http.Req
 		zap.String("channel_id", channelID),
 	)
 
-	// TODO: Find call by channel ID and end it
-	// callID := lookupCallByChannelID(channelID)
-	// h.callService.EndCall(r.Context(), callID)
+	if err := h.callService.HandleCallerHangup(r.Context(), channelID); err != nil {
+		h.logger.Error("failed to end call after caller hangup",
+			zap.Error(err),
+			zap.String("channel_id", channelID),
+		)
+	}
 
 	w.WriteHeader(http.StatusOK)
 }
@@ -171,12 +426,67 @@ func (h *AsteriskHandler) handleChannelDestroyed(w http.ResponseWriter, r *http.
 	w.WriteHeader(http.StatusOK)
 }
 
-// respondError writes an error response.
-func (h *AsteriskHandler) respondError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(ErrorResponse{
-		Error:   http.StatusText(status),
-		Message: message,
-	})
+// handlePlaybackFinished handles when a playback on a channel finishes.
+func (h *AsteriskHandler) handlePlaybackFinished(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent) {
+	if event.Playback == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	channelID := event.Playback.ChannelID()
+
+	if err := h.callService.HandlePlaybackFinished(r.Context(), channelID, event.Playback.ID); err != nil {
+		h.logger.Error("failed to handle playback finished",
+			zap.Error(err),
+			zap.String("channel_id", channelID),
+		)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleRecordingFinished stores finished voicemails and ends the call.
+func (h *AsteriskHandler) handleRecordingFinished(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent) {
+	if event.Recording == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	vm, err := h.voicemailService.HandleRecordingFinished(r.Context(), event.Recording)
+	if err != nil {
+		h.logger.Error("failed to handle voicemail recording",
+			zap.Error(err),
+			zap.String("recording", event.Recording.Name),
+		)
+	}
+
+	if vm != nil {
+		if err := h.callService.EndCall(r.Context(), vm.CallID); err != nil {
+			h.logger.Error("failed to end call after voicemail", zap.Error(err))
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleRecordingFailed ends calls whose voicemail recording failed.
+func (h *AsteriskHandler) handleRecordingFailed(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent) {
+	if event.Recording == nil || !voicemail.IsVoicemailRecording(event.Recording.Name) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	h.logger.Error("voicemail recording failed",
+		zap.String("recording", event.Recording.Name),
+		zap.String("cause", event.Recording.Cause),
+	)
+
+	c, err := h.callService.GetCallByChannelID(r.Context(), event.Recording.ChannelID())
+	if err == nil {
+		if err := h.callService.EndCall(r.Context(), c.ID); err != nil {
+			h.logger.Error("failed to end call", zap.Error(err))
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
 }
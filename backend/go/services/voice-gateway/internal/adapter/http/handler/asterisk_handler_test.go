@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/tenant"
+	callservice "voice-gateway/internal/application/call"
+	routingservice "voice-gateway/internal/application/routing"
+	"voice-gateway/internal/domain/call"
+)
+
+// memoryDedup is an in-memory EventDeduplicator.
+type memoryDedup struct {
+	seen map[string]bool
+	err  error
+	mu   sync.Mutex
+}
+
+func newMemoryDedup() *memoryDedup {
+	return &memoryDedup{seen: make(map[string]bool)}
+}
+
+func (d *memoryDedup) FirstSeen(ctx context.Context, key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.err != nil {
+		return false, d.err
+	}
+	if d.seen[key] {
+		return false, nil
+	}
+	d.seen[key] = true
+	return true, nil
+}
+
+func (d *memoryDedup) Forget(ctx context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seen, key)
+	return nil
+}
+
+const stasisStartEvent = `{
+	"type": "StasisStart",
+	"timestamp": "2024-05-10T12:00:00.000-0300",
+	"channel": {"id": "1715353200.42", "caller": {"number": "+5511999887766"}}
+}`
+
+// newCountingHandler returns a handler whose StasisStart handler counts invocations.
+func newCountingHandler(dedup EventDeduplicator) (*AsteriskHandler, *int) {
+	h := NewAsteriskHandler(nil, nil, nil, dedup, zap.NewNop())
+
+	count := 0
+	h.Register("StasisStart", func(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent) {
+		count++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return h, &count
+}
+
+func postEvent(h *AsteriskHandler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/asterisk/events", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.HandleARIEvent(rec, req)
+	return rec
+}
+
+func TestHandleARIEvent_DuplicateStasisStart(t *testing.T) {
+	h, count := newCountingHandler(newMemoryDedup())
+
+	for i := 0; i < 3; i++ {
+		if rec := postEvent(h, stasisStartEvent); rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for delivery %d, got %d", i+1, rec.Code)
+		}
+	}
+
+	if *count != 1 {
+		t.Errorf("Expected StasisStart to be handled once, got %d", *count)
+	}
+}
+
+func TestHandleARIEvent_FailedEventIsRedelivered(t *testing.T) {
+	h := NewAsteriskHandler(nil, nil, nil, newMemoryDedup(), zap.NewNop())
+
+	attempts := 0
+	h.Register("StasisStart", func(w http.ResponseWriter, r *http.Request, event *asterisk.ARIEvent) {
+		attempts++
+		if attempts == 1 {
+			writeError(w, r, http.StatusInternalServerError, CodeInternal, "failed to handle incoming call")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if rec := postEvent(h, stasisStartEvent); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected the first delivery to fail, got %d", rec.Code)
+	}
+	for i := 0; i < 2; i++ {
+		postEvent(h, stasisStartEvent)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected the redelivery after a failure to be handled once more, got %d attempts", attempts)
+	}
+}
+
+func TestHandleARIEvent_DistinctEventsAreHandled(t *testing.T) {
+	h, count := newCountingHandler(newMemoryDedup())
+
+	postEvent(h, stasisStartEvent)
+	postEvent(h, strings.Replace(stasisStartEvent, "12:00:00.000", "12:00:01.000", 1))
+	postEvent(h, strings.Replace(stasisStartEvent, "1715353200.42", "1715353200.43", 1))
+
+	if *count != 3 {
+		t.Errorf("Expected 3 distinct StasisStart events to be handled, got %d", *count)
+	}
+}
+
+func TestHandleARIEvent_DedupFailureProcessesEvent(t *testing.T) {
+	dedup := newMemoryDedup()
+	dedup.err = errors.New("redis unavailable")
+	h, count := newCountingHandler(dedup)
+
+	postEvent(h, stasisStartEvent)
+	postEvent(h, stasisStartEvent)
+
+	if *count != 2 {
+		t.Errorf("Expected events to be handled when dedup fails, got %d", *count)
+	}
+}
+
+func TestHandleARIEvent_WithoutDedup(t *testing.T) {
+	h, count := newCountingHandler(nil)
+
+	postEvent(h, stasisStartEvent)
+	postEvent(h, stasisStartEvent)
+
+	if *count != 2 {
+		t.Errorf("Expected every delivery to be handled without dedup, got %d", *count)
+	}
+}
+
+func TestHandleARIEvent_UnknownType(t *testing.T) {
+	dedup := newMemoryDedup()
+	h, count := newCountingHandler(dedup)
+
+	rec := postEvent(h, `{"type": "ChannelVarset", "timestamp": "2024-05-10T12:00:00.000-0300"}`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+	if *count != 0 {
+		t.Errorf("Expected no handler to run, got %d", *count)
+	}
+	if len(dedup.seen) != 0 {
+		t.Error("Unhandled events should not be recorded")
+	}
+}
+
+// stubARI answers every channel.
+type stubARI struct{}
+
+func (stubARI) AnswerChannel(ctx context.Context, channelID string) error { return nil }
+func (stubARI) HangupChannel(ctx context.Context, channelID string) error { return nil }
+func (stubARI) PlaybackStart(ctx context.Context, channelID, media string) (string, error) {
+	return "playback-1", nil
+}
+func (stubARI) GetRTPStatistics(ctx context.Context, channelID string) (*asterisk.RTPStatistics, error) {
+	return &asterisk.RTPStatistics{}, nil
+}
+
+// memoryCalls is an in-memory call state store.
+type memoryCalls struct {
+	mu    sync.Mutex
+	calls map[uuid.UUID]call.Call
+}
+
+func (m *memoryCalls) Save(ctx context.Context, c *call.Call) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[c.ID] = *c
+	return nil
+}
+
+func (m *memoryCalls) Get(ctx context.Context, callID uuid.UUID) (*call.Call, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.calls[callID]
+	if !ok {
+		return nil, call.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (m *memoryCalls) GetByChannelID(ctx context.Context, channelID string) (*call.Call, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.calls {
+		if c.ChannelID == channelID {
+			return &c, nil
+		}
+	}
+	return nil, call.ErrNotFound
+}
+
+func (m *memoryCalls) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*call.Call, error) {
+	return nil, nil
+}
+
+func (m *memoryCalls) CountActive(ctx context.Context) (int64, error) { return 0, nil }
+
+func (m *memoryCalls) MarkEnded(ctx context.Context, callID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+// newStasisHandler returns a handler whose tenant-manager knows dids and
+// sends every other call to the default agent, and the calls it creates.
+// Each call publishes call.started, call.answered and routing.decision.
+func newStasisHandler(t *testing.T, dids map[string]tenant.DIDInfo, calls int) (*AsteriskHandler, *memoryCalls, *[]events.RoutingEvent) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if number, ok := strings.CutPrefix(r.URL.Path, "/api/v1/telephony/dids/lookup/"); ok {
+			info, found := dids[number]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(info)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"settings": map[string]any{
+				"telephony": map[string]any{"default_agent_id": "default-agent"},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	var decisions []events.RoutingEvent
+	producer := mocks.NewSyncProducer(t, nil)
+	t.Cleanup(func() { producer.Close() })
+	for i := 0; i < 3*calls; i++ {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			value, _ := msg.Value.Encode()
+			var event events.RoutingEvent
+			if err := json.Unmarshal(value, &event); err == nil && event.EventType == "routing.decision" {
+				decisions = append(decisions, event)
+			}
+			return nil
+		})
+	}
+	publisher := events.NewPublisherWithProducer(producer, "serphona", zap.NewNop())
+
+	store := &memoryCalls{calls: make(map[uuid.UUID]call.Call)}
+	callService := callservice.NewService(stubARI{}, store, publisher, nil, nil, 10, call.QualityThresholds{}, zap.NewNop())
+	callService.SetNumberRegion("BR")
+	routingService := routingservice.NewService(tenant.NewClient(server.URL, zap.NewNop()), publisher, zap.NewNop())
+	return NewAsteriskHandler(callService, routingService, nil, nil, zap.NewNop()), store, &decisions
+}
+
+func stasisStartTo(did string) string {
+	return `{"type": "StasisStart", "channel": {"id": "1715353200.42", ` +
+		`"caller": {"number": "+5511999887766"}, "connected": {"number": "` + did + `"}}}`
+}
+
+func TestHandleStasisStart_UsesDIDTenantAndAssignment(t *testing.T) {
+	tenantID := uuid.New()
+	h, store, decisions := newStasisHandler(t, map[string]tenant.DIDInfo{
+		"+551140001000": {DID: "+551140001000", TenantID: tenantID, Enabled: true, AgentID: "sales-agent", FlowID: "qualify-lead"},
+	}, 1)
+
+	// Sent in the trunk's national format
+	if rec := postEvent(h, stasisStartTo("(11) 4000-1000")); rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	if len(store.calls) != 1 {
+		t.Fatalf("Expected one call, got %d", len(store.calls))
+	}
+	for _, c := range store.calls {
+		if c.TenantID != tenantID || c.CalleeNumber != "+551140001000" {
+			t.Errorf("Expected a call of tenant %s to +551140001000, got tenant %s to %s", tenantID, c.TenantID, c.CalleeNumber)
+		}
+		if c.AgentID != "sales-agent" {
+			t.Errorf("Expected the assigned agent to answer, got %q", c.AgentID)
+		}
+	}
+	if len(*decisions) != 1 {
+		t.Fatalf("Expected one routing decision, got %d", len(*decisions))
+	}
+	if d := (*decisions)[0]; d.AgentID != "sales-agent" || d.FlowID != "qualify-lead" || !d.Assigned {
+		t.Errorf("Expected the DID's agent and flow, got %+v", d)
+	}
+}
+
+func TestHandleStasisStart_RejectsUnknownDID(t *testing.T) {
+	h, store, _ := newStasisHandler(t, map[string]tenant.DIDInfo{
+		"+551140002000": {DID: "+551140002000", TenantID: uuid.New(), Enabled: false, AgentID: "sales-agent"},
+	}, 0)
+
+	for _, did := range []string{"+551140009000", "+551140002000"} {
+		rec := postEvent(h, stasisStartTo(did))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected status 404, got %d", did, rec.Code)
+		}
+		if resp := decodeError(t, rec); resp.Error != "did_not_found" {
+			t.Errorf("%s: expected code did_not_found, got %s", did, resp.Error)
+		}
+	}
+	if len(store.calls) != 0 {
+		t.Errorf("Expected no call for an unknown DID, got %d", len(store.calls))
+	}
+}
@@ -2,13 +2,16 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	callservice "voice-gateway/internal/application/call"
+	"voice-gateway/internal/domain/call"
 )
 
 // CallHandler handles call-related HTTP requests.
@@ -49,20 +52,18 @@ func (h *CallHandler) GetCall(w http.ResponseWriter, r *http.Request) {
 	// Extract call_id from URL path
 	callIDStr := r.PathValue("call_id")
 	if callIDStr == "" {
-		h.respondError(w, http.StatusBadRequest, "call_id is required")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "call_id is required")
 		return
 	}
 
 	callID, err := uuid.Parse(callIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid call_id format")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid call_id format")
 		return
 	}
 
-	// Get call from service
-	call, err := h.callService.GetCallState(r.Context(), callID)
-	if err != nil {
-		h.respondError(w, http.StatusNotFound, "call not found")
+	call, ok := h.callOf(w, r, callID)
+	if !ok {
 		return
 	}
 
@@ -86,7 +87,7 @@ func (h *CallHandler) GetCall(w http.ResponseWriter, r *http.Request) {
 		response.Duration = call.Duration.Milliseconds()
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	writeJSON(w, http.StatusOK, response)
 }
 
 // EndCallRequest represents an end call request.
@@ -98,64 +99,182 @@ type EndCallRequest struct {
 func (h *CallHandler) EndCall(w http.ResponseWriter, r *http.Request) {
 	callIDStr := r.PathValue("call_id")
 	if callIDStr == "" {
-		h.respondError(w, http.StatusBadRequest, "call_id is required")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "call_id is required")
 		return
 	}
 
 	callID, err := uuid.Parse(callIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid call_id format")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid call_id format")
+		return
+	}
+
+	if _, ok := h.callOf(w, r, callID); !ok {
 		return
 	}
 
 	// End call
 	if err := h.callService.EndCall(r.Context(), callID); err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to end call")
+		writeServiceError(w, r, h.logger, err, "failed to end call")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, map[string]string{"status": "call ended"})
+	writeJSON(w, http.StatusOK, map[string]string{"status": "call ended"})
 }
 
 // TransferCallRequest represents a transfer call request.
 type TransferCallRequest struct {
-	Type   string `json:"type"`   // "queue", "agent", "external"
-	Target string `json:"target"` // queue name, agent ID, or phone number
-	Reason string `json:"reason,omitempty"`
+	Type    string `json:"type"`   // "queue", "agent", "external"
+	Target  string `json:"target"` // queue name, agent ID, or phone number
+	Reason  string `json:"reason,omitempty"`
+	FromDID string `json:"from_did,omitempty"` // caller ID of an external transfer; a DID of the tenant
 }
 
 // TransferCall handles POST /api/v1/calls/{call_id}/transfer
 func (h *CallHandler) TransferCall(w http.ResponseWriter, r *http.Request) {
 	callIDStr := r.PathValue("call_id")
 	if callIDStr == "" {
-		h.respondError(w, http.StatusBadRequest, "call_id is required")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "call_id is required")
 		return
 	}
 
 	callID, err := uuid.Parse(callIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid call_id format")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid call_id format")
 		return
 	}
 
 	var req TransferCallRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid request body")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid request body")
 		return
 	}
 
 	if req.Type == "" || req.Target == "" {
-		h.respondError(w, http.StatusBadRequest, "type and target are required")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "type and target are required")
+		return
+	}
+
+	if _, ok := h.callOf(w, r, callID); !ok {
 		return
 	}
 
 	// Transfer call
-	if err := h.callService.TransferCall(r.Context(), callID, req.Type, req.Target, req.Reason); err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to transfer call")
+	if err := h.callService.TransferCall(r.Context(), callID, req.Type, req.Target, req.Reason, req.FromDID); err != nil {
+		writeServiceError(w, r, h.logger, err, "failed to transfer call")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "call transferred"})
+}
+
+// MonitorCallRequest represents a spy, whisper or barge request.
+type MonitorCallRequest struct {
+	ChannelID string `json:"channel_id"` // the supervisor's channel in the Stasis app
+}
+
+// MonitorCallResponse represents a started monitor session.
+type MonitorCallResponse struct {
+	MonitorID string `json:"monitor_id"`
+	CallID    string `json:"call_id"`
+	Mode      string `json:"mode"`
+	BridgeID  string `json:"bridge_id"`
+}
+
+// monitorFunc starts a monitor session in one of the monitoring modes.
+type monitorFunc func(ctx context.Context, callID uuid.UUID, supervisor call.Supervisor) (*call.Monitor, error)
+
+// SpyCall handles POST /api/v1/calls/{call_id}/spy
+func (h *CallHandler) SpyCall(w http.ResponseWriter, r *http.Request) {
+	h.monitorCall(w, r, h.callService.SpyCall)
+}
+
+// WhisperCall handles POST /api/v1/calls/{call_id}/whisper
+func (h *CallHandler) WhisperCall(w http.ResponseWriter, r *http.Request) {
+	h.monitorCall(w, r, h.callService.WhisperCall)
+}
+
+// BargeCall handles POST /api/v1/calls/{call_id}/barge
+func (h *CallHandler) BargeCall(w http.ResponseWriter, r *http.Request) {
+	h.monitorCall(w, r, h.callService.BargeCall)
+}
+
+// monitorCall starts a monitor session for the authenticated supervisor.
+func (h *CallHandler) monitorCall(w http.ResponseWriter, r *http.Request, monitor monitorFunc) {
+	callID, err := uuid.Parse(r.PathValue("call_id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid call_id format")
+		return
+	}
+
+	var req MonitorCallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid request body")
+		return
+	}
+	if req.ChannelID == "" {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "channel_id is required")
+		return
+	}
+
+	caller, ok := CallerFromContext(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, CodeUnauthorized, "user identity is required")
+		return
+	}
+	supervisor := call.Supervisor{
+		ID:        caller.UserID,
+		TenantID:  caller.TenantID,
+		Role:      caller.Role,
+		ChannelID: req.ChannelID,
+	}
+
+	m, err := monitor(r.Context(), callID, supervisor)
+	if err != nil {
+		writeServiceError(w, r, h.logger, err, "failed to monitor call")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, MonitorCallResponse{
+		MonitorID: m.ID.String(),
+		CallID:    m.CallID.String(),
+		Mode:      string(m.Mode),
+		BridgeID:  m.BridgeID,
+	})
+}
+
+// callOf fetches a call of the caller's tenant. Calls of other tenants are
+// answered as not found, so their IDs are not revealed.
+func (h *CallHandler) callOf(w http.ResponseWriter, r *http.Request, callID uuid.UUID) (*call.Call, bool) {
+	c, err := h.callService.GetCallState(r.Context(), callID)
+	if err != nil {
+		writeServiceError(w, r, h.logger, err, "failed to get call")
+		return nil, false
+	}
+	if caller, _ := CallerFromContext(r.Context()); !caller.CanAccess(c.TenantID) {
+		writeError(w, r, http.StatusNotFound, CodeCallNotFound, "call not found")
+		return nil, false
+	}
+	return c, true
+}
+
+// GetTTSAudio handles GET /media/tts/{file}, serving cached synthesized audio
+// to Asterisk.
+func (h *CallHandler) GetTTSAudio(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutSuffix(r.PathValue("file"), ".wav")
+	if !ok {
+		writeError(w, r, http.StatusNotFound, CodeInvalidRequest, "audio not found")
+		return
+	}
+
+	audio, ok := h.callService.CachedAudio(key)
+	if !ok {
+		writeError(w, r, http.StatusNotFound, CodeInvalidRequest, "audio not found")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, map[string]string{"status": "call transferred"})
+	w.Header().Set("Content-Type", "audio/wav")
+	w.Write(audio)
 }
 
 // ListCallsRequest represents a list calls request (via query params).
@@ -168,20 +287,20 @@ type ListCallsResponse struct {
 func (h *CallHandler) ListCalls(w http.ResponseWriter, r *http.Request) {
 	tenantIDStr := r.PathValue("tenant_id")
 	if tenantIDStr == "" {
-		h.respondError(w, http.StatusBadRequest, "tenant_id is required")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "tenant_id is required")
 		return
 	}
 
 	tenantID, err := uuid.Parse(tenantIDStr)
 	if err != nil {
-		h.respondError(w, http.StatusBadRequest, "invalid tenant_id format")
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid tenant_id format")
 		return
 	}
 
 	// List calls
 	calls, err := h.callService.ListActiveCalls(r.Context(), tenantID)
 	if err != nil {
-		h.respondError(w, http.StatusInternalServerError, "failed to list calls")
+		writeServiceError(w, r, h.logger, err, "failed to list calls")
 		return
 	}
 
@@ -210,26 +329,5 @@ func (h *CallHandler) ListCalls(w http.ResponseWriter, r *http.Request) {
 		response.Calls = append(response.Calls, callResp)
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
-}
-
-// ErrorResponse represents an error response.
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
-
-// respondJSON writes a JSON response.
-func (h *CallHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
-}
-
-// respondError writes an error response.
-func (h *CallHandler) respondError(w http.ResponseWriter, status int, message string) {
-	h.respondJSON(w, status, ErrorResponse{
-		Error:   http.StatusText(status),
-		Message: message,
-	})
+	writeJSON(w, http.StatusOK, response)
 }
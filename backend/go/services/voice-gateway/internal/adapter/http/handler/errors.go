@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+	"go.uber.org/zap"
+)
+
+// Error codes returned in the error envelope.
+const (
+	CodeInvalidRequest      = "invalid_request"
+	CodeInvalidEvent        = "invalid_event"
+	CodeCallNotFound        = "call_not_found"
+	CodeCallNotActive       = "call_not_active"
+	CodeTenantLimitExceeded = "tenant_limit_exceeded"
+	CodeUnauthorized        = "unauthorized"
+	CodeForbidden           = "forbidden"
+	CodeInternal            = "internal_error"
+)
+
+// ErrorResponse represents an error response.
+type ErrorResponse struct {
+	Error   string `json:"error"` // machine-readable error code
+	Message string `json:"message"`
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+type traceIDKey struct{}
+
+// WithTraceID returns a context carrying the request trace ID.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceID returns the request trace ID from the context, if any.
+func TraceID(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeError writes an error envelope with the request trace ID.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	writeJSON(w, status, ErrorResponse{
+		Error:   code,
+		Message: message,
+		TraceID: TraceID(r.Context()),
+	})
+}
+
+// serviceErrorStatus maps an application error to an HTTP status and error code.
+func serviceErrorStatus(err error) (int, string) {
+	return apperrors.HTTPStatus(err), apperrors.ReasonOf(err)
+}
+
+// writeServiceError writes the error envelope for an application error.
+// Internal errors are logged and their details are not exposed.
+func writeServiceError(w http.ResponseWriter, r *http.Request, logger *zap.Logger, err error, message string) {
+	status, code := serviceErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		logger.Error(message,
+			zap.Error(err),
+			zap.String("trace_id", TraceID(r.Context())),
+		)
+	} else {
+		message = err.Error()
+	}
+
+	writeError(w, r, status, code, message)
+}
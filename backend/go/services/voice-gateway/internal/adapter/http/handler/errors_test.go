@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/domain/call"
+)
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) ErrorResponse {
+	t.Helper()
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	return resp
+}
+
+func TestWriteServiceError_Codes(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", fmt.Errorf("failed to get call: %w", call.ErrNotFound), http.StatusNotFound, CodeCallNotFound},
+		{"not active", fmt.Errorf("%w: state is ended", call.ErrNotActive), http.StatusConflict, CodeCallNotActive},
+		{"limit exceeded", fmt.Errorf("%w: maximum of 10 calls reached", call.ErrLimitExceeded), http.StatusTooManyRequests, CodeTenantLimitExceeded},
+		{"internal", errors.New("redis: connection refused"), http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/calls/x", nil)
+			req = req.WithContext(WithTraceID(req.Context(), "trace-123"))
+			rec := httptest.NewRecorder()
+
+			writeServiceError(rec, req, zap.NewNop(), tt.err, "failed to get call")
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+
+			resp := decodeError(t, rec)
+			if resp.Error != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, resp.Error)
+			}
+			if resp.TraceID != "trace-123" {
+				t.Errorf("Expected trace ID trace-123, got %q", resp.TraceID)
+			}
+		})
+	}
+}
+
+func TestWriteServiceError_HidesInternalDetails(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/calls/x", nil)
+	rec := httptest.NewRecorder()
+
+	writeServiceError(rec, req, zap.NewNop(), errors.New("dial tcp 10.0.0.5:6379: refused"), "failed to end call")
+
+	resp := decodeError(t, rec)
+	if resp.Message != "failed to end call" {
+		t.Errorf("Expected generic message, got %q", resp.Message)
+	}
+}
+
+func TestCallHandler_InvalidCallID(t *testing.T) {
+	h := NewCallHandler(nil, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/calls/not-a-uuid", nil)
+	req.SetPathValue("call_id", "not-a-uuid")
+	req = req.WithContext(WithTraceID(req.Context(), "trace-456"))
+	rec := httptest.NewRecorder()
+
+	h.GetCall(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+
+	resp := decodeError(t, rec)
+	if resp.Error != CodeInvalidRequest {
+		t.Errorf("Expected code %s, got %s", CodeInvalidRequest, resp.Error)
+	}
+	if resp.TraceID != "trace-456" {
+		t.Errorf("Expected trace ID trace-456, got %q", resp.TraceID)
+	}
+}
+
+func TestHandleARIEvent_InvalidEvent(t *testing.T) {
+	h := NewAsteriskHandler(nil, nil, nil, nil, zap.NewNop())
+
+	rec := postEvent(h, `{"type": `)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+	if resp := decodeError(t, rec); resp.Error != CodeInvalidEvent {
+		t.Errorf("Expected code %s, got %s", CodeInvalidEvent, resp.Error)
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
+	authjwt "github.com/serphona/serphona/backend/go/libs/platform-auth/jwt"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
+	"go.uber.org/zap"
+)
+
+// Error codes of tenant-scoped requests.
+const (
+	// CodeTenantMismatch is returned when a caller addresses another tenant.
+	CodeTenantMismatch = "tenant_mismatch"
+	// CodeTenantSuspended is returned for tokens of suspended or deleted tenants.
+	CodeTenantSuspended = "tenant_suspended"
+	// CodeTenantStatusUnavailable is returned when the tenant status can't be
+	// looked up and the status check doesn't fail open.
+	CodeTenantStatusUnavailable = "tenant_status_unavailable"
+)
+
+// TokenValidator validates a user access token. *authjwt.Verifier from
+// platform-auth implements it.
+type TokenValidator interface {
+	Validate(ctx context.Context, token string) (*types.Claims, error)
+}
+
+// Caller is the authenticated user of a management request.
+type Caller struct {
+	UserID   string
+	Role     string
+	TenantID uuid.UUID
+}
+
+// IsSuperadmin reports whether the caller is a platform operator, who may
+// act on any tenant.
+func (c Caller) IsSuperadmin() bool {
+	return c.Role == RoleSuperadmin
+}
+
+// CanAccess reports whether the caller may act on tenantID.
+func (c Caller) CanAccess(tenantID uuid.UUID) bool {
+	return c.IsSuperadmin() || c.TenantID == tenantID
+}
+
+type callerKey struct{}
+
+// WithCaller returns a context carrying the authenticated caller.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the authenticated caller, if any.
+func CallerFromContext(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
+
+// RequireTenant authenticates management requests with the bearer token and
+// puts the caller on the request context. When the route has a {tenant_id},
+// it must be the caller's tenant unless the caller is a superadmin. With a
+// tenantStatus cache, tokens of suspended or deleted tenants are rejected even
+// though they are still valid; nil skips the check. It must wrap the route
+// handler so the path values are already set.
+func RequireTenant(tokens TokenValidator, tenantStatus *tenantstatus.Cache, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller, err := authenticate(r, tokens)
+			if err != nil {
+				writeError(w, r, http.StatusUnauthorized, CodeUnauthorized, authMessage(err))
+				return
+			}
+			if tenantStatus != nil && !tenantActive(w, r, tenantStatus, caller, logger) {
+				return
+			}
+			if !tenantAllowed(w, r, caller) {
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithCaller(r.Context(), caller)))
+		})
+	}
+}
+
+// authenticate validates the bearer token and reads the caller from it.
+func authenticate(r *http.Request, tokens TokenValidator) (Caller, error) {
+	token, err := authjwt.ExtractTokenFromHeader(r.Header.Get("Authorization"))
+	if err != nil {
+		return Caller{}, err
+	}
+	claims, err := tokens.Validate(r.Context(), token)
+	if err != nil {
+		return Caller{}, err
+	}
+	return callerFromClaims(claims)
+}
+
+// callerFromClaims reads the caller from validated token claims.
+func callerFromClaims(claims *types.Claims) (Caller, error) {
+	tenantID, err := uuid.Parse(claims.TenantID)
+	if err != nil || claims.UserID == "" {
+		return Caller{}, autherrors.ErrInvalidToken
+	}
+	return Caller{UserID: claims.UserID, Role: claims.Role, TenantID: tenantID}, nil
+}
+
+// tenantActive rejects callers whose tenant is suspended or deleted. Whether
+// a caller gets through when the status can't be looked up is up to the
+// cache's FailOpen.
+func tenantActive(w http.ResponseWriter, r *http.Request, tenantStatus *tenantstatus.Cache, caller Caller, logger *zap.Logger) bool {
+	blocked, err := tenantStatus.Blocked(r.Context(), caller.TenantID)
+	if err != nil {
+		logger.Warn("failed to check tenant status",
+			zap.String("tenant_id", caller.TenantID.String()),
+			zap.Bool("blocked", blocked),
+			zap.Error(err),
+		)
+		if blocked {
+			writeError(w, r, http.StatusServiceUnavailable, CodeTenantStatusUnavailable, "tenant status is unavailable")
+			return false
+		}
+	}
+	if blocked {
+		writeError(w, r, http.StatusForbidden, CodeTenantSuspended, "tenant is suspended")
+		return false
+	}
+	return true
+}
+
+// tenantAllowed rejects a path tenant the caller may not access.
+func tenantAllowed(w http.ResponseWriter, r *http.Request, caller Caller) bool {
+	pathTenant := r.PathValue("tenant_id")
+	if pathTenant == "" {
+		return true
+	}
+	tenantID, err := uuid.Parse(pathTenant)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid tenant_id format")
+		return false
+	}
+	if !caller.CanAccess(tenantID) {
+		writeError(w, r, http.StatusForbidden, CodeTenantMismatch, "tenant_id does not match the authenticated tenant")
+		return false
+	}
+	return true
+}
+
+// authMessage describes a failed authentication without token details.
+func authMessage(err error) string {
+	switch {
+	case errors.Is(err, autherrors.ErrMissingToken):
+		return "missing authentication token"
+	case errors.Is(err, autherrors.ErrTokenExpired):
+		return "authentication token has expired"
+	case errors.Is(err, autherrors.ErrInvalidAudience):
+		return "authentication token is not meant for this service"
+	default:
+		return "invalid authentication token"
+	}
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	autherrors "github.com/serphona/serphona/backend/go/libs/platform-auth/errors"
+	authjwt "github.com/serphona/serphona/backend/go/libs/platform-auth/jwt"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/types"
+	"go.uber.org/zap"
+)
+
+const (
+	tenantA = "6f1c7a52-2f4e-4a83-9c1e-0d7f3b1a5e01"
+	tenantB = "9b2d4e61-7a3c-4f18-8e2b-5c6a1d0f7b02"
+)
+
+// staticTokens accepts the tokens it knows
+type staticTokens map[string]*types.Claims
+
+func (s staticTokens) Validate(_ context.Context, token string) (*types.Claims, error) {
+	claims, ok := s[token]
+	if !ok {
+		return nil, autherrors.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+var tokens = staticTokens{
+	"admin-a":    {UserID: "user-a", Role: "admin", TenantID: tenantA},
+	"superadmin": {UserID: "ops-1", Role: RoleSuperadmin, TenantID: tenantB},
+}
+
+// tenantRouter serves a tenant-scoped route that reports the caller's tenant
+func tenantRouter() http.Handler {
+	return tenantStatusRouter(nil)
+}
+
+// tenantStatusRouter is tenantRouter checking tenant statuses in tenantStatus
+func tenantStatusRouter(tenantStatus *tenantstatus.Cache) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/tenants/{tenant_id}/calls", RequireTenant(tokens, tenantStatus, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, _ := CallerFromContext(r.Context())
+		w.Write([]byte(caller.TenantID.String()))
+	})))
+	return mux
+}
+
+func getTenantCalls(h http.Handler, tenantID, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/"+tenantID+"/calls", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequireTenant_RejectsTenantMismatch(t *testing.T) {
+	rec := getTenantCalls(tenantRouter(), tenantB, "admin-a")
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d", rec.Code)
+	}
+	if resp := decodeError(t, rec); resp.Error != CodeTenantMismatch {
+		t.Errorf("Expected code %s, got %s", CodeTenantMismatch, resp.Error)
+	}
+}
+
+func TestRequireTenant_PutsCallerOnContext(t *testing.T) {
+	rec := getTenantCalls(tenantRouter(), tenantA, "admin-a")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if rec.Body.String() != tenantA {
+		t.Errorf("Expected caller tenant %s, got %s", tenantA, rec.Body.String())
+	}
+}
+
+func TestRequireTenant_SuperadminAccessesAnyTenant(t *testing.T) {
+	if rec := getTenantCalls(tenantRouter(), tenantA, "superadmin"); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a superadmin, got %d", rec.Code)
+	}
+}
+
+// tenantStatuses serves fixed tenant statuses, or fails with err
+type tenantStatuses struct {
+	statuses map[uuid.UUID]string
+	err      error
+}
+
+func (s tenantStatuses) TenantStatus(_ context.Context, tenantID uuid.UUID) (string, error) {
+	return s.statuses[tenantID], s.err
+}
+
+func TestRequireTenant_RejectsSuspendedTenants(t *testing.T) {
+	source := tenantStatuses{statuses: map[uuid.UUID]string{
+		uuid.MustParse(tenantA): tenantstatus.StatusSuspended,
+		uuid.MustParse(tenantB): tenantstatus.StatusActive,
+	}}
+	router := tenantStatusRouter(tenantstatus.NewCache(source, tenantstatus.Config{TTL: time.Minute}))
+
+	rec := getTenantCalls(router, tenantA, "admin-a")
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a suspended tenant, got %d", rec.Code)
+	}
+	if resp := decodeError(t, rec); resp.Error != CodeTenantSuspended {
+		t.Errorf("Expected code %s, got %s", CodeTenantSuspended, resp.Error)
+	}
+
+	if rec := getTenantCalls(router, tenantA, "superadmin"); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a superadmin of an active tenant, got %d", rec.Code)
+	}
+}
+
+func TestRequireTenant_TenantStatusUnavailable(t *testing.T) {
+	source := tenantStatuses{err: errors.New("connection refused")}
+
+	tests := []struct {
+		name     string
+		failOpen bool
+		want     int
+	}{
+		{name: "fail open", failOpen: true, want: http.StatusOK},
+		{name: "fail closed", failOpen: false, want: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := tenantStatusRouter(tenantstatus.NewCache(source, tenantstatus.Config{TTL: time.Minute, FailOpen: tt.failOpen}))
+
+			if rec := getTenantCalls(router, tenantA, "admin-a"); rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRequireTenant_RejectsMissingAndInvalidTokens(t *testing.T) {
+	for name, token := range map[string]string{"missing": "", "invalid": "forged"} {
+		t.Run(name, func(t *testing.T) {
+			rec := getTenantCalls(tenantRouter(), tenantA, token)
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("Expected status 401, got %d", rec.Code)
+			}
+			if resp := decodeError(t, rec); resp.Error != CodeUnauthorized {
+				t.Errorf("Expected code %s, got %s", CodeUnauthorized, resp.Error)
+			}
+		})
+	}
+}
+
+func TestRequireTenant_EnforcesAudience(t *testing.T) {
+	const secret = "test-secret-with-at-least-32-chars!!"
+	verifier, err := authjwt.NewVerifier(authjwt.WithHMACSecret(secret), authjwt.WithAudience("voice-gateway"))
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/v1/tenants/{tenant_id}/calls", RequireTenant(verifier, nil, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	sign := func(aud ...string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, types.Claims{
+			UserID:   "user-a",
+			Role:     "admin",
+			TenantID: tenantA,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Audience:  aud,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatalf("SignedString: %v", err)
+		}
+		return token
+	}
+
+	rec := getTenantCalls(mux, tenantA, sign("frontend"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected status 401 for a token meant for another service, got %d", rec.Code)
+	}
+	if resp := decodeError(t, rec); resp.Message != "authentication token is not meant for this service" {
+		t.Errorf("Expected the audience message, got %q", resp.Message)
+	}
+
+	if rec := getTenantCalls(mux, tenantA, sign("serphona-api", "voice-gateway")); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a token naming voice-gateway, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	voicemailservice "voice-gateway/internal/application/voicemail"
+)
+
+const (
+	defaultVoicemailLimit = 50
+	maxVoicemailLimit     = 200
+)
+
+// VoicemailHandler handles voicemail-related HTTP requests.
+type VoicemailHandler struct {
+	voicemailService *voicemailservice.Service
+	logger           *zap.Logger
+}
+
+// NewVoicemailHandler creates a new voicemail handler.
+func NewVoicemailHandler(voicemailService *voicemailservice.Service, logger *zap.Logger) *VoicemailHandler {
+	return &VoicemailHandler{
+		voicemailService: voicemailService,
+		logger:           logger,
+	}
+}
+
+// VoicemailResponse represents a voicemail in API responses.
+type VoicemailResponse struct {
+	VoicemailID  string    `json:"voicemail_id"`
+	CallID       string    `json:"call_id"`
+	TenantID     string    `json:"tenant_id"`
+	CallerNumber string    `json:"caller_number"`
+	CalleeNumber string    `json:"callee_number"`
+	Reason       string    `json:"reason"`
+	RecordingURI string    `json:"recording_uri"`
+	Transcript   string    `json:"transcript,omitempty"`
+	Duration     int64     `json:"duration"` // seconds
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ListVoicemailsResponse represents a paginated list of voicemails.
+type ListVoicemailsResponse struct {
+	Voicemails []VoicemailResponse `json:"voicemails"`
+	Total      int                 `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+// ListVoicemails handles GET /api/v1/tenants/{tenant_id}/voicemails?limit=50&offset=0
+func (h *VoicemailHandler) ListVoicemails(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := uuid.Parse(r.PathValue("tenant_id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid tenant_id format")
+		return
+	}
+
+	limit := parseIntQuery(r, "limit", defaultVoicemailLimit)
+	if limit < 1 || limit > maxVoicemailLimit {
+		limit = defaultVoicemailLimit
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	voicemails, total, err := h.voicemailService.ListVoicemails(r.Context(), tenantID, limit, offset)
+	if err != nil {
+		writeServiceError(w, r, h.logger, err, "failed to list voicemails")
+		return
+	}
+
+	response := ListVoicemailsResponse{
+		Voicemails: make([]VoicemailResponse, 0, len(voicemails)),
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	for _, v := range voicemails {
+		response.Voicemails = append(response.Voicemails, VoicemailResponse{
+			VoicemailID:  v.ID.String(),
+			CallID:       v.CallID.String(),
+			TenantID:     v.TenantID.String(),
+			CallerNumber: v.CallerNumber,
+			CalleeNumber: v.CalleeNumber,
+			Reason:       v.Reason,
+			RecordingURI: v.RecordingURI,
+			Transcript:   v.Transcript,
+			Duration:     int64(v.Duration.Seconds()),
+			CreatedAt:    v.CreatedAt,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// OriginalTranscriptResponse is the unredacted transcript of a voicemail.
+type OriginalTranscriptResponse struct {
+	VoicemailID string `json:"voicemail_id"`
+	Transcript  string `json:"transcript"`
+}
+
+// GetOriginalTranscript handles GET /api/v1/tenants/{tenant_id}/voicemails/{voicemail_id}/transcript/original.
+// Only admins of the tenant can read it.
+func (h *VoicemailHandler) GetOriginalTranscript(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := uuid.Parse(r.PathValue("tenant_id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid tenant_id format")
+		return
+	}
+	voicemailID, err := uuid.Parse(r.PathValue("voicemail_id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid voicemail_id format")
+		return
+	}
+
+	// RequireTenant has checked that the caller may access the tenant
+	caller, ok := CallerFromContext(r.Context())
+	if !ok {
+		writeError(w, r, http.StatusUnauthorized, CodeUnauthorized, "user identity is required")
+		return
+	}
+
+	transcript, err := h.voicemailService.OriginalTranscript(r.Context(), tenantID, voicemailID, caller.Role)
+	if err != nil {
+		writeServiceError(w, r, h.logger, err, "failed to read original transcript")
+		return
+	}
+
+	h.logger.Info("original voicemail transcript read",
+		zap.String("voicemail_id", voicemailID.String()),
+		zap.String("user_id", caller.UserID),
+	)
+
+	writeJSON(w, http.StatusOK, OriginalTranscriptResponse{
+		VoicemailID: voicemailID.String(),
+		Transcript:  transcript,
+	})
+}
+
+// parseIntQuery parses an integer query parameter, returning defaultVal when absent or invalid.
+func parseIntQuery(r *http.Request, key string, defaultVal int) int {
+	val, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil {
+		return defaultVal
+	}
+	return val
+}
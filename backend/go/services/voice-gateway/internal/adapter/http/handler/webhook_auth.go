@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// WebhookSecretHeader carries the shared secret of the Asterisk webhooks.
+const WebhookSecretHeader = "X-Webhook-Secret"
+
+// RequireWebhookSecret admits only requests carrying the shared secret in
+// X-Webhook-Secret, compared in constant time. The Asterisk webhooks are
+// internal: they must be reachable only from the telephony network, and the
+// secret keeps anything else on it from injecting call events. Without a
+// configured secret every request is rejected.
+func RequireWebhookSecret(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := r.Header.Get(WebhookSecretHeader)
+			if secret == "" || subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+				writeError(w, r, http.StatusUnauthorized, CodeUnauthorized, "invalid webhook secret")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
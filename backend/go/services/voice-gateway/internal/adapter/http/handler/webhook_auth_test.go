@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postARIEvent(h http.Handler, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/asterisk/events", strings.NewReader(`{"type":"StasisStart"}`))
+	if secret != "" {
+		req.Header.Set(WebhookSecretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequireWebhookSecret(t *testing.T) {
+	var served int
+	h := RequireWebhookSecret("s3cret")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		secret string
+		status int
+	}{
+		{"missing secret", "", http.StatusUnauthorized},
+		{"wrong secret", "guess", http.StatusUnauthorized},
+		{"secret prefix", "s3cre", http.StatusUnauthorized},
+		{"shared secret", "s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postARIEvent(h, tt.secret)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if rec.Code == http.StatusUnauthorized {
+				if resp := decodeError(t, rec); resp.Error != CodeUnauthorized {
+					t.Errorf("Expected code %s, got %s", CodeUnauthorized, resp.Error)
+				}
+			}
+		})
+	}
+	if served != 1 {
+		t.Errorf("Expected only the authenticated event handled, got %d", served)
+	}
+}
+
+func TestRequireWebhookSecret_RejectsAllWithoutSecret(t *testing.T) {
+	h := RequireWebhookSecret("")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no event handled without a configured secret")
+	}))
+
+	if rec := postARIEvent(h, "anything"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
@@ -3,37 +3,102 @@ package http
 
 import (
 	"net/http"
-
+	"strings"
+
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loadshed"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
 	"go.uber.org/zap"
 
 	"voice-gateway/internal/adapter/http/handler"
 	callservice "voice-gateway/internal/application/call"
+	routingservice "voice-gateway/internal/application/routing"
+	voicemailservice "voice-gateway/internal/application/voicemail"
 )
 
 // NewRouter creates a new HTTP router with all routes configured.
-func NewRouter(callService *callservice.Service, logger *zap.Logger) http.Handler {
+func NewRouter(
+	callService *callservice.Service,
+	routingService *routingservice.Service,
+	voicemailService *voicemailservice.Service,
+	eventDedup handler.EventDeduplicator,
+	readiness http.Handler,
+	logLevel http.Handler,
+	shedder *loadshed.Shedder,
+	limiter *bodylimit.Limiter,
+	tokens handler.TokenValidator,
+	tenantStatus *tenantstatus.Cache,
+	webhookSecret string,
+	logger *zap.Logger,
+) http.Handler {
 	mux := http.NewServeMux()
 
 	// Create handlers
 	callHandler := handler.NewCallHandler(callService, logger)
-	asteriskHandler := handler.NewAsteriskHandler(callService, logger)
+	voicemailHandler := handler.NewVoicemailHandler(voicemailService, logger)
+	asteriskHandler := handler.NewAsteriskHandler(callService, routingService, voicemailService, eventDedup, logger)
+
+	// Calls leaving the hold queue are routed like new calls
+	callService.SetDequeueHandler(asteriskHandler.RouteCall)
 
 	// Health check endpoints
 	mux.HandleFunc("GET /health", healthHandler)
 	mux.HandleFunc("GET /health/live", livenessHandler)
-	mux.HandleFunc("GET /health/ready", readinessHandler)
-
-	// Call management API
-	mux.HandleFunc("GET /api/v1/calls/{call_id}", callHandler.GetCall)
-	mux.HandleFunc("DELETE /api/v1/calls/{call_id}", callHandler.EndCall)
-	mux.HandleFunc("POST /api/v1/calls/{call_id}/transfer", callHandler.TransferCall)
-	mux.HandleFunc("GET /api/v1/tenants/{tenant_id}/calls", callHandler.ListCalls)
+	mux.Handle("GET /health/ready", readiness)
+	mux.Handle("GET /version", buildinfo.Handler("voice-gateway"))
+
+	// Call management API, authenticated with the caller's token and scoped
+	// to the caller's tenant
+	tenantScoped := handler.RequireTenant(tokens, tenantStatus, logger)
+	api := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, tenantScoped(h))
+	}
+	api("GET /api/v1/calls/{call_id}", callHandler.GetCall)
+	api("DELETE /api/v1/calls/{call_id}", callHandler.EndCall)
+	api("POST /api/v1/calls/{call_id}/transfer", callHandler.TransferCall)
+	api("POST /api/v1/calls/{call_id}/spy", callHandler.SpyCall)
+	api("POST /api/v1/calls/{call_id}/whisper", callHandler.WhisperCall)
+	api("POST /api/v1/calls/{call_id}/barge", callHandler.BargeCall)
+	api("GET /api/v1/tenants/{tenant_id}/calls", callHandler.ListCalls)
+	api("GET /api/v1/tenants/{tenant_id}/voicemails", voicemailHandler.ListVoicemails)
+	api("GET /api/v1/tenants/{tenant_id}/voicemails/{voicemail_id}/transcript/original", voicemailHandler.GetOriginalTranscript)
+
+	// Runtime log level for incidents
+	mux.Handle("PUT /admin/log-level", handler.RequireSuperadmin(logLevel))
+
+	// Internal endpoints, called by Asterisk only. They carry no user token and
+	// must be reachable from the telephony network alone; the webhooks also
+	// check the shared secret.
+	mux.HandleFunc("GET "+callservice.GreetingMediaPath+"{file}", callHandler.GetTTSAudio)
+	mux.Handle("POST /asterisk/events", handler.RequireWebhookSecret(webhookSecret)(http.HandlerFunc(asteriskHandler.HandleARIEvent)))
+
+	// Apply middleware; oversized bodies are refused before reaching handlers
+	return tracing.Middleware()(traceMiddleware(loggingMiddleware(logger)(shedder.Middleware(limiter.Middleware(corsMiddleware(mux))))))
+}
 
-	// Asterisk ARI webhooks
-	mux.HandleFunc("POST /asterisk/events", asteriskHandler.HandleARIEvent)
+// NewShedder creates the API load shedder. Health checks, Asterisk webhooks
+// and media, and hanging up or transferring a call in progress are critical:
+// shedding them would break live calls.
+func NewShedder(config loadshed.Config, opts ...loadshed.Option) *loadshed.Shedder {
+	return loadshed.New(config, append(opts, loadshed.WithCritical(criticalRequest))...)
+}
 
-	// Apply middleware
-	return loggingMiddleware(logger)(corsMiddleware(mux))
+// criticalRequest reports whether a request serves a call in progress or the
+// health of the service.
+func criticalRequest(r *http.Request) bool {
+	path := r.URL.Path
+	switch {
+	case strings.HasPrefix(path, "/health"),
+		path == "/asterisk/events",
+		strings.HasPrefix(path, callservice.GreetingMediaPath):
+		return true
+	case strings.HasPrefix(path, "/api/v1/calls/"):
+		return r.Method == http.MethodDelete || (r.Method == http.MethodPost && strings.HasSuffix(path, "/transfer"))
+	}
+	return false
 }
 
 // healthHandler handles general health checks.
@@ -50,14 +115,6 @@ func livenessHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(`{"status":"alive"}`))
 }
 
-// readinessHandler handles Kubernetes readiness probes.
-func readinessHandler(w http.ResponseWriter, r *http.Request) {
-	// TODO: Check dependencies (Redis, Kafka, Asterisk)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"ready"}`))
-}
-
 // loggingMiddleware logs HTTP requests.
 func loggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -66,18 +123,37 @@ func loggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
 				zap.String("remote_addr", r.RemoteAddr),
+				zap.String("request_id", requestid.FromContext(r.Context())),
+				zap.String("trace_id", handler.TraceID(r.Context())),
 			)
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// traceMiddleware puts the X-Request-ID correlation ID (generated when
+// absent) and the active span's trace ID on the request context so error
+// responses can be correlated with traces. Without a span (tracing disabled
+// and no incoming traceparent) the trace ID falls back to the request ID.
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = requestid.Bind(w, r)
+
+		traceID, _ := tracing.IDs(r.Context())
+		if traceID == "" {
+			traceID = requestid.FromContext(r.Context())
+		}
+
+		next.ServeHTTP(w, r.WithContext(handler.WithTraceID(r.Context(), traceID)))
+	})
+}
+
 // corsMiddleware adds CORS headers.
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
 
 		// Handle preflight requests
 		if r.Method == http.MethodOptions {
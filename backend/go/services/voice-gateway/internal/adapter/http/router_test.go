@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loadshed"
+	"go.opentelemetry.io/otel/trace"
+
+	"voice-gateway/internal/adapter/http/handler"
+)
+
+func TestTraceMiddleware_UsesSpanTraceID(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	var got string
+	h := traceMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = handler.TraceID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	req = req.WithContext(trace.ContextWithSpanContext(req.Context(), sc))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got != sc.TraceID().String() {
+		t.Errorf("Expected trace ID %s, got %q", sc.TraceID(), got)
+	}
+	if rec.Header().Get("X-Request-ID") != "req-123" {
+		t.Errorf("Expected X-Request-ID to be echoed, got %q", rec.Header().Get("X-Request-ID"))
+	}
+}
+
+func TestTraceMiddleware_FallsBackToRequestID(t *testing.T) {
+	var got string
+	h := traceMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = handler.TraceID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-456")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "req-456" {
+		t.Errorf("Expected trace ID req-456 without an active span, got %q", got)
+	}
+}
+
+func TestCriticalRequest(t *testing.T) {
+	tests := []struct {
+		method, path string
+		critical     bool
+	}{
+		{http.MethodGet, "/health/ready", true},
+		{http.MethodPost, "/asterisk/events", true},
+		{http.MethodDelete, "/api/v1/calls/abc", true},
+		{http.MethodPost, "/api/v1/calls/abc/transfer", true},
+		{http.MethodGet, "/api/v1/calls/abc", false},
+		{http.MethodPost, "/api/v1/calls/abc/spy", false},
+		{http.MethodGet, "/api/v1/tenants/abc/calls", false},
+	}
+	for _, tt := range tests {
+		if got := criticalRequest(httptest.NewRequest(tt.method, tt.path, nil)); got != tt.critical {
+			t.Errorf("%s %s: expected critical=%v, got %v", tt.method, tt.path, tt.critical, got)
+		}
+	}
+}
+
+func TestShedder_KeepsCallControlAboveCeiling(t *testing.T) {
+	shedder := NewShedder(loadshed.Config{MaxInFlight: 1})
+	release, _, _ := shedder.Acquire(context.Background(), false)
+	defer release()
+
+	h := shedder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tenants/abc/calls", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected a listing shed with Retry-After, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/calls/abc", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected a hangup to pass above the ceiling, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,385 @@
+// Package metrics provides Prometheus metrics for the voice-gateway service.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/serphona/backend/go/libs/platform-observability/slo"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/buildinfo"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/loadshed"
+
+	"voice-gateway/internal/domain/call"
+)
+
+const namespace = "voice_gateway"
+
+// Registry is the dedicated registry served by the metrics server. Besides the
+// voice-gateway metrics it holds the Go runtime and process collectors and
+// the build info.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+// slis records the voice-gateway events of the platform SLIs.
+var slis = slo.NewRecorder(Registry, "voice-gateway")
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	buildinfo.Register(Registry, "voice-gateway")
+}
+
+// providerLatencyBuckets covers fast streaming results up to the provider budgets.
+var providerLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 4, 8, 15}
+
+// turnLatencyBuckets covers speech-to-speech turns around the 1.5s budget.
+var turnLatencyBuckets = []float64{0.25, 0.5, 0.75, 1, 1.25, 1.5, 2, 3, 5, 8, 15}
+
+var (
+	callsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "calls",
+		Name:      "total",
+		Help:      "Number of call state transitions by direction and state.",
+	}, []string{"tenant_id", "direction", "state"})
+
+	activeCalls = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "calls",
+		Name:      "active",
+		Help:      "Number of calls in progress.",
+	}, []string{"tenant_id"})
+
+	callDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "call",
+		Name:      "duration_seconds",
+		Help:      "Duration of answered calls from answer to hangup.",
+		Buckets:   []float64{10, 30, 60, 120, 300, 600, 900, 1800, 3600},
+	}, []string{"tenant_id", "direction"})
+
+	transfersTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "calls",
+		Name:      "transfers_total",
+		Help:      "Number of call transfers by type.",
+	}, []string{"tenant_id", "type"})
+
+	abandonsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "calls",
+		Name:      "abandoned_total",
+		Help:      "Number of calls the caller hung up before reaching an agent, by stage.",
+	}, []string{"tenant_id", "stage"})
+
+	errorsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors_total",
+		Help:      "Number of errors by component (stt, tts, agent, asterisk).",
+	}, []string{"tenant_id", "component"})
+
+	sttLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "stt",
+		Name:      "latency_seconds",
+		Help:      "Time to transcribe a caller utterance.",
+		Buckets:   providerLatencyBuckets,
+	}, []string{"tenant_id", "provider"})
+
+	ttsLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "tts",
+		Name:      "latency_seconds",
+		Help:      "Time to synthesize an agent response.",
+		Buckets:   providerLatencyBuckets,
+	}, []string{"tenant_id", "provider"})
+
+	llmLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "latency_seconds",
+		Help:      "Time for the agent orchestrator to answer a conversation turn.",
+		Buckets:   providerLatencyBuckets,
+	}, []string{"tenant_id"})
+
+	turnLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "turn",
+		Name:      "latency_seconds",
+		Help:      "Speech-to-speech latency of a conversation turn, from the caller audio reaching STT to the reply audio.",
+		Buckets:   turnLatencyBuckets,
+	}, []string{"tenant_id"})
+
+	turnOverBudget = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "turn",
+		Name:      "over_budget_total",
+		Help:      "Conversation turns slower than the turn latency budget.",
+	}, []string{"tenant_id"})
+
+	callMOS = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "call_quality",
+		Name:      "mos",
+		Help:      "Estimated Mean Opinion Score per call.",
+		Buckets:   []float64{1, 1.5, 2, 2.5, 3, 3.5, 4, 4.25, 4.5},
+	}, []string{"tenant_id"})
+
+	callJitter = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "call_quality",
+		Name:      "jitter_milliseconds",
+		Help:      "RTP jitter per call in milliseconds.",
+		Buckets:   []float64{1, 5, 10, 20, 30, 50, 75, 100, 200},
+	}, []string{"tenant_id"})
+
+	callPacketLoss = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "call_quality",
+		Name:      "packet_loss_percent",
+		Help:      "RTP packet loss per call in percent.",
+		Buckets:   []float64{0, 0.5, 1, 2, 3, 5, 10, 20},
+	}, []string{"tenant_id"})
+
+	callLatency = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "call_quality",
+		Name:      "latency_milliseconds",
+		Help:      "One-way RTP latency per call in milliseconds.",
+		Buckets:   []float64{10, 25, 50, 100, 150, 200, 300, 500, 1000},
+	}, []string{"tenant_id"})
+
+	callQualityDegraded = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "call_quality",
+		Name:      "degraded_total",
+		Help:      "Number of calls whose quality breached a threshold.",
+	}, []string{"tenant_id", "metric"})
+
+	audioBufferUtilization = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "audio_buffer",
+		Name:      "utilization_ratio",
+		Help:      "Fill level of bounded audio buffers after each write, as a fraction of their capacity.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 0.75, 0.9, 1},
+	}, []string{"stream"})
+
+	audioBufferDropped = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "audio_buffer",
+		Name:      "dropped_bytes_total",
+		Help:      "Bytes of audio discarded because a bounded buffer was full.",
+	}, []string{"stream"})
+
+	audioBufferBlocked = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "audio_buffer",
+		Name:      "blocked_writes_total",
+		Help:      "Writes that waited for a full bounded buffer to drain.",
+	}, []string{"stream"})
+
+	jitterLateFrames = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jitter_buffer",
+		Name:      "late_frames_total",
+		Help:      "Audio frames discarded because they arrived after their slot was played.",
+	}, []string{"stream"})
+
+	jitterDroppedFrames = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jitter_buffer",
+		Name:      "dropped_frames_total",
+		Help:      "Audio frames never played, by reason (lost: skipped after waiting the buffer depth, overflow: discarded by a full buffer).",
+	}, []string{"stream", "reason"})
+
+	requestsShed = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_shed_total",
+		Help:      "API requests refused with 503 under overload, by reason (overloaded, queue_timeout).",
+	}, []string{"reason"})
+)
+
+// MaxTenantLabels bounds the cardinality of the tenant_id label. Tenants seen
+// after the limit is reached are reported as OtherTenant.
+const MaxTenantLabels = 500
+
+// OtherTenant is the tenant_id label for tenants beyond MaxTenantLabels.
+const OtherTenant = "other"
+
+var tenantLabels = struct {
+	sync.Mutex
+	seen map[string]struct{}
+}{seen: make(map[string]struct{})}
+
+// tenantLabel returns the tenant_id label value for tenantID.
+func tenantLabel(tenantID string) string {
+	tenantLabels.Lock()
+	defer tenantLabels.Unlock()
+
+	if _, ok := tenantLabels.seen[tenantID]; ok {
+		return tenantID
+	}
+	if len(tenantLabels.seen) >= MaxTenantLabels {
+		return OtherTenant
+	}
+	tenantLabels.seen[tenantID] = struct{}{}
+	return tenantID
+}
+
+// CallStarted records a new call and counts it as active.
+func CallStarted(c *call.Call) {
+	activeCalls.WithLabelValues(tenantLabel(c.TenantID.String())).Inc()
+	ObserveCallState(c)
+}
+
+// ObserveCallState counts the transition of c into its current state.
+func ObserveCallState(c *call.Call) {
+	callsTotal.WithLabelValues(tenantLabel(c.TenantID.String()), string(c.Direction), string(c.State)).Inc()
+}
+
+// CallEnded records a finished call: it leaves the active gauge and, if it was
+// answered, its duration is observed.
+func CallEnded(c *call.Call) {
+	tenant := tenantLabel(c.TenantID.String())
+	activeCalls.WithLabelValues(tenant).Dec()
+	ObserveCallState(c)
+
+	if c.AnsweredAt != nil {
+		callDuration.WithLabelValues(tenant, string(c.Direction)).Observe(c.Duration.Seconds())
+	}
+}
+
+// IncTransfer counts a call transfer of the given type (queue, external).
+func IncTransfer(tenantID, transferType string) {
+	transfersTotal.WithLabelValues(tenantLabel(tenantID), transferType).Inc()
+}
+
+// IncAbandoned counts a call abandoned at a stage (before_answer,
+// before_agent, in_queue).
+func IncAbandoned(tenantID, stage string) {
+	abandonsTotal.WithLabelValues(tenantLabel(tenantID), stage).Inc()
+}
+
+// IncError counts an error in a component (stt, tts, agent, asterisk).
+func IncError(tenantID, component string) {
+	errorsTotal.WithLabelValues(tenantLabel(tenantID), component).Inc()
+}
+
+// ObserveSTTLatency records the time an STT provider took to transcribe.
+func ObserveSTTLatency(tenantID, provider string, d time.Duration) {
+	sttLatency.WithLabelValues(tenantLabel(tenantID), provider).Observe(d.Seconds())
+	slis.ObserveLatency(slo.STTLatency, d)
+}
+
+// ObserveTTSLatency records the time a TTS provider took to synthesize.
+func ObserveTTSLatency(tenantID, provider string, d time.Duration) {
+	ttsLatency.WithLabelValues(tenantLabel(tenantID), provider).Observe(d.Seconds())
+}
+
+// ObserveLLMLatency records the time the agent orchestrator took to answer.
+func ObserveLLMLatency(tenantID string, d time.Duration) {
+	llmLatency.WithLabelValues(tenantLabel(tenantID)).Observe(d.Seconds())
+}
+
+// ObserveTurn records a turn answered in d, for the turn latency SLI.
+func ObserveTurn(d time.Duration) {
+	slis.ObserveLatency(slo.TurnLatency, d)
+}
+
+// ObserveTurnLatency records the speech-to-speech latency of a turn and
+// counts it when it went over its budget.
+func ObserveTurnLatency(tenantID string, l call.TurnLatency) {
+	tenant := tenantLabel(tenantID)
+	turnLatency.WithLabelValues(tenant).Observe(l.Total.Seconds())
+	if l.OverBudget() {
+		turnOverBudget.WithLabelValues(tenant).Inc()
+	}
+}
+
+// IncTurnFailed records a turn that failed or was answered with the fallback
+// message, which misses the turn latency SLI.
+func IncTurnFailed() {
+	slis.Observe(slo.TurnLatency, false)
+}
+
+// ObserveAnswer records an attempt to answer a call, for the answer SLI.
+func ObserveAnswer(ok bool) {
+	slis.Observe(slo.AnswerSuccess, ok)
+}
+
+// ObserveCallQuality records quality measurements for a finished call.
+func ObserveCallQuality(tenantID string, q call.Quality) {
+	tenant := tenantLabel(tenantID)
+	callMOS.WithLabelValues(tenant).Observe(q.MOS)
+	callJitter.WithLabelValues(tenant).Observe(q.JitterMs)
+	callPacketLoss.WithLabelValues(tenant).Observe(q.PacketLossPct)
+	callLatency.WithLabelValues(tenant).Observe(q.LatencyMs)
+}
+
+// IncCallQualityDegraded increments the degraded counter for each breached metric.
+func IncCallQualityDegraded(tenantID string, breached []string) {
+	tenant := tenantLabel(tenantID)
+	for _, metric := range breached {
+		callQualityDegraded.WithLabelValues(tenant, metric).Inc()
+	}
+}
+
+// ObserveAudioBuffer records the fill level of a bounded audio buffer of a
+// stream (stt, tts).
+func ObserveAudioBuffer(stream string, used, capacity int) {
+	if capacity > 0 {
+		audioBufferUtilization.WithLabelValues(stream).Observe(float64(used) / float64(capacity))
+	}
+}
+
+// AddAudioBufferDropped counts audio discarded by a full buffer of a stream.
+func AddAudioBufferDropped(stream string, bytes int) {
+	audioBufferDropped.WithLabelValues(stream).Add(float64(bytes))
+}
+
+// IncAudioBufferBlocked counts a write that waited for a full buffer of a
+// stream to drain.
+func IncAudioBufferBlocked(stream string) {
+	audioBufferBlocked.WithLabelValues(stream).Inc()
+}
+
+// IncJitterLateFrame counts a frame of a stream that arrived too late to be
+// played.
+func IncJitterLateFrame(stream string) {
+	jitterLateFrames.WithLabelValues(stream).Inc()
+}
+
+// AddJitterDroppedFrames counts frames of a stream the jitter buffer gave up
+// on, by reason (lost, overflow).
+func AddJitterDroppedFrames(stream, reason string, frames int) {
+	jitterDroppedFrames.WithLabelValues(stream, reason).Add(float64(frames))
+}
+
+// IncRequestShed counts an API request refused under overload.
+func IncRequestShed(reason loadshed.Reason) {
+	requestsShed.WithLabelValues(string(reason)).Inc()
+}
+
+// ObserveLoadShedder reports the requests in flight and queued of the API
+// load shedder.
+func ObserveLoadShedder(s *loadshed.Shedder) {
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "API requests being served.",
+	}, func() float64 { return float64(s.Stats().InFlight) })
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_queued",
+		Help:      "API requests waiting for a slot under overload.",
+	}, func() float64 { return float64(s.Stats().Queued) })
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	dto "github.com/prometheus/client_model/go"
+
+	"voice-gateway/internal/domain/call"
+)
+
+// histogramSamples returns the sample count and sum of a histogram series
+// in Registry matching the given labels.
+func histogramSamples(t *testing.T, name string, labels map[string]string) (uint64, float64) {
+	t.Helper()
+
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather failed: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if matchLabels(m, labels) {
+				return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return 0, 0
+}
+
+func matchLabels(m *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, lp := range m.GetLabel() {
+		if v, ok := labels[lp.GetName()]; ok && v == lp.GetValue() {
+			matched++
+		}
+	}
+	return matched == len(labels)
+}
+
+func TestCallEndedObservesDuration(t *testing.T) {
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999990000", "+551133330000")
+	labels := map[string]string{"tenant_id": c.TenantID.String(), "direction": "inbound"}
+
+	CallStarted(c)
+	c.Answer()
+	answeredAt := c.AnsweredAt.Add(-90 * time.Second)
+	c.AnsweredAt = &answeredAt
+	c.End()
+	CallEnded(c)
+
+	count, sum := histogramSamples(t, "voice_gateway_call_duration_seconds", labels)
+	if count != 1 {
+		t.Fatalf("expected 1 duration sample, got %d", count)
+	}
+	if sum < 90 {
+		t.Errorf("expected duration of at least 90s, got %.1fs", sum)
+	}
+}
+
+func TestCallEndedSkipsUnansweredCalls(t *testing.T) {
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999990000", "+551133330000")
+
+	CallStarted(c)
+	c.End()
+	CallEnded(c)
+
+	count, _ := histogramSamples(t, "voice_gateway_call_duration_seconds", map[string]string{"tenant_id": c.TenantID.String()})
+	if count != 0 {
+		t.Errorf("expected no duration sample for an unanswered call, got %d", count)
+	}
+}
+
+// counterValue returns the value of a counter series in Registry matching
+// the given labels.
+func counterValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather failed: %v", err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			if matchLabels(m, labels) {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestTurnSLICountsFastAndSlowTurns(t *testing.T) {
+	labels := map[string]string{"service": "voice-gateway", "sli": "turn_latency"}
+	total := counterValue(t, "serphona_sli_events_total", labels)
+	good := counterValue(t, "serphona_sli_good_events_total", labels)
+
+	ObserveTurn(900 * time.Millisecond)
+	ObserveTurn(3 * time.Second)
+	IncTurnFailed()
+
+	if got := counterValue(t, "serphona_sli_events_total", labels) - total; got != 3 {
+		t.Errorf("expected 3 turn events, got %v", got)
+	}
+	if got := counterValue(t, "serphona_sli_good_events_total", labels) - good; got != 1 {
+		t.Errorf("expected only the fast turn to be good, got %v good events", got)
+	}
+}
+
+func TestObserveTurnLatencyPerTenant(t *testing.T) {
+	tenantID := uuid.New().String()
+	labels := map[string]string{"tenant_id": tenantID}
+
+	ObserveTurnLatency(tenantID, call.TurnLatency{Total: 900 * time.Millisecond, Budget: 1500 * time.Millisecond})
+	ObserveTurnLatency(tenantID, call.TurnLatency{Total: 2 * time.Second, Budget: 1500 * time.Millisecond})
+
+	count, sum := histogramSamples(t, "voice_gateway_turn_latency_seconds", labels)
+	if count != 2 || sum < 2.89 || sum > 2.91 {
+		t.Errorf("expected 2 samples summing 2.9s, got %d summing %v", count, sum)
+	}
+	if got := counterValue(t, "voice_gateway_turn_over_budget_total", labels); got != 1 {
+		t.Errorf("expected only the slow turn over budget, got %v", got)
+	}
+}
+
+func TestTenantLabelIsBounded(t *testing.T) {
+	tenantLabels.Lock()
+	saved := tenantLabels.seen
+	tenantLabels.seen = make(map[string]struct{})
+	tenantLabels.Unlock()
+	defer func() {
+		tenantLabels.Lock()
+		tenantLabels.seen = saved
+		tenantLabels.Unlock()
+	}()
+
+	for i := 0; i < MaxTenantLabels; i++ {
+		id := fmt.Sprintf("tenant-%d", i)
+		if got := tenantLabel(id); got != id {
+			t.Fatalf("expected %s below the limit, got %s", id, got)
+		}
+	}
+
+	if got := tenantLabel("one-too-many"); got != OtherTenant {
+		t.Errorf("expected %q beyond the limit, got %q", OtherTenant, got)
+	}
+	// Tenants already seen keep their label
+	if got := tenantLabel("tenant-0"); got != "tenant-0" {
+		t.Errorf("expected known tenant to keep its label, got %q", got)
+	}
+}
@@ -0,0 +1,124 @@
+package redis
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState is the state of a circuit breaker.
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // requests go to Redis
+	breakerOpen                         // requests skip Redis until the cooldown ends
+	breakerHalfOpen                     // one probe request is in flight
+)
+
+// circuitBreaker stops calling Redis after consecutive failures so that an
+// outage costs one fast local decision per request instead of a network
+// timeout. After the cooldown a single probe is let through; its outcome
+// closes the circuit or restarts the cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu            sync.Mutex
+	state         breakerState
+	failures      int
+	openedAt      time.Time
+	degradedSince time.Time
+	lastErr       error
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a request may go to Redis, and whether it is the
+// probe that decides if the circuit closes.
+func (b *circuitBreaker) allow() (allowed, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false, false
+		}
+		b.state = breakerHalfOpen
+		return true, true
+	case breakerHalfOpen:
+		return false, false
+	default:
+		return true, false
+	}
+}
+
+// success records a request that reached Redis. It reports whether the
+// request closed an open circuit and for how long the store was degraded.
+func (b *circuitBreaker) success() (recovered bool, downtime time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	if b.state == breakerClosed {
+		return false, 0
+	}
+
+	b.state = breakerClosed
+	b.lastErr = nil
+	return true, b.now().Sub(b.degradedSince)
+}
+
+// failure records a request that could not reach Redis and reports whether it
+// opened the circuit. A failed probe reopens it without reporting it again.
+func (b *circuitBreaker) failure(err error) (opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastErr = err
+	switch b.state {
+	case breakerHalfOpen:
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		return false
+	case breakerOpen:
+		return false
+	}
+
+	b.failures++
+	if b.failures < b.threshold {
+		return false
+	}
+
+	b.state = breakerOpen
+	b.openedAt = b.now()
+	b.degradedSince = b.openedAt
+	return true
+}
+
+// abandon returns a half-open circuit to open when the probe ended without an
+// answer from Redis, so that the next cooldown lets another probe through.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// degraded reports whether the circuit is not closed, with the error that
+// last kept it open.
+func (b *circuitBreaker) degraded() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state != breakerClosed, b.lastErr
+}
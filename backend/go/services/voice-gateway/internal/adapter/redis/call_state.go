@@ -4,6 +4,7 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -27,6 +28,11 @@ func NewCallStateRepository(client *redis.Client, ttl time.Duration) *CallStateR
 	}
 }
 
+// Ping checks that Redis is reachable.
+func (r *CallStateRepository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 // Save stores a call state in Redis.
 func (r *CallStateRepository) Save(ctx context.Context, c *call.Call) error {
 	key := fmt.Sprintf("call:%s", c.ID)
@@ -62,7 +68,7 @@ func (r *CallStateRepository) Get(ctx context.Context, callID uuid.UUID) (*call.
 
 	data, err := r.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
-		return nil, fmt.Errorf("call not found")
+		return nil, call.ErrNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get call: %w", err)
@@ -82,7 +88,7 @@ func (r *CallStateRepository) GetByChannelID(ctx context.Context, channelID stri
 
 	callIDStr, err := r.client.Get(ctx, channelKey).Result()
 	if err == redis.Nil {
-		return nil, fmt.Errorf("call not found for channel")
+		return nil, fmt.Errorf("channel %s: %w", channelID, call.ErrNotFound)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get channel index: %w", err)
@@ -158,10 +164,72 @@ func (r *CallStateRepository) UpdateState(ctx context.Context, callID uuid.UUID,
 		return err
 	}
 
+	if !c.CanTransition(state) {
+		return fmt.Errorf("%w: %s to %s", call.ErrInvalidTransition, c.State, state)
+	}
 	c.State = state
 	return r.Save(ctx, c)
 }
 
+// markEndedAttempts bounds the retries of MarkEnded when the call changes
+// while it is being marked.
+const markEndedAttempts = 3
+
+// MarkEnded moves a call to the ended state with a compare-and-set on the
+// stored state. It returns false, leaving the call untouched, when the call
+// has already ended, so only one of the paths ending a call goes on to clean
+// it up.
+func (r *CallStateRepository) MarkEnded(ctx context.Context, callID uuid.UUID) (bool, error) {
+	key := fmt.Sprintf("call:%s", callID)
+
+	for attempt := 0; attempt < markEndedAttempts; attempt++ {
+		marked := false
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			data, err := tx.Get(ctx, key).Bytes()
+			if err == redis.Nil {
+				return call.ErrNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get call: %w", err)
+			}
+
+			var c call.Call
+			if err := json.Unmarshal(data, &c); err != nil {
+				return fmt.Errorf("failed to unmarshal call: %w", err)
+			}
+			if c.IsEnded() {
+				return nil
+			}
+
+			if err := c.End(); err != nil {
+				return err
+			}
+			if data, err = json.Marshal(&c); err != nil {
+				return fmt.Errorf("failed to marshal call: %w", err)
+			}
+			// Fails with redis.TxFailedErr if the call changed since it was read
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, r.ttl)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			marked = true
+			return nil
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		return marked, nil
+	}
+
+	return false, fmt.Errorf("failed to mark call ended: %w", redis.TxFailedErr)
+}
+
 // CountActive returns the number of active calls.
 func (r *CallStateRepository) CountActive(ctx context.Context) (int64, error) {
 	pattern := "call:*"
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"voice-gateway/internal/domain/cdr"
+)
+
+// ErrCDRNotFound is returned for a record missing or past retention.
+var ErrCDRNotFound = errors.New("cdr not found")
+
+// CDRRepository implements call detail record persistence using Redis.
+type CDRRepository struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewCDRRepository creates a new Redis-based CDR repository. Records are
+// kept for retention.
+func NewCDRRepository(client *redis.Client, retention time.Duration) *CDRRepository {
+	return &CDRRepository{
+		client:    client,
+		retention: retention,
+	}
+}
+
+// Save stores a record and indexes it by tenant, ordered by end time.
+func (r *CDRRepository) Save(ctx context.Context, record *cdr.Record) error {
+	key := fmt.Sprintf("cdr:%s", record.ID)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cdr: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, data, r.retention).Err(); err != nil {
+		return fmt.Errorf("failed to save cdr: %w", err)
+	}
+
+	tenantKey := fmt.Sprintf("cdrs:tenant:%s", record.TenantID)
+	member := redis.Z{
+		Score:  float64(record.EndTime.UnixMilli()),
+		Member: record.ID.String(),
+	}
+	if err := r.client.ZAdd(ctx, tenantKey, member).Err(); err != nil {
+		return fmt.Errorf("failed to add to tenant index: %w", err)
+	}
+	r.client.Expire(ctx, tenantKey, r.retention)
+
+	return nil
+}
+
+// Get retrieves a record by ID.
+func (r *CDRRepository) Get(ctx context.Context, id uuid.UUID) (*cdr.Record, error) {
+	data, err := r.client.Get(ctx, fmt.Sprintf("cdr:%s", id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrCDRNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cdr: %w", err)
+	}
+
+	var record cdr.Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cdr: %w", err)
+	}
+	return &record, nil
+}
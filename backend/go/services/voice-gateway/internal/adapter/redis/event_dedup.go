@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EventDeduplicator remembers recently processed events in Redis so that
+// redelivered events can be ignored.
+type EventDeduplicator struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewEventDeduplicator creates a new Redis-based event deduplicator.
+func NewEventDeduplicator(client *redis.Client, ttl time.Duration) *EventDeduplicator {
+	return &EventDeduplicator{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// FirstSeen marks the event key as seen and reports whether it was new.
+// Keys expire after the TTL, which only needs to cover the redelivery window.
+func (d *EventDeduplicator) FirstSeen(ctx context.Context, key string) (bool, error) {
+	ok, err := d.client.SetNX(ctx, fmt.Sprintf("ari:event:%s", key), 1, d.ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to mark event as seen: %w", err)
+	}
+	return ok, nil
+}
+
+// Forget removes the event key so that a redelivery is processed again.
+func (d *EventDeduplicator) Forget(ctx context.Context, key string) error {
+	if err := d.client.Del(ctx, fmt.Sprintf("ari:event:%s", key)).Err(); err != nil {
+		return fmt.Errorf("failed to forget event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,487 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/domain/call"
+)
+
+const (
+	// DefaultBreakerFailureThreshold is the number of consecutive Redis
+	// failures that open the circuit.
+	DefaultBreakerFailureThreshold = 5
+	// DefaultBreakerCooldown is how long the circuit stays open before Redis
+	// is tried again.
+	DefaultBreakerCooldown = 10 * time.Second
+)
+
+// ErrStateStoreUnavailable is returned while Redis is unavailable and the
+// in-memory fallback is disabled or doesn't know the call.
+var ErrStateStoreUnavailable = errors.New("call state store unavailable")
+
+// CallStateStore is the call state storage wrapped by ResilientCallStateRepository.
+type CallStateStore interface {
+	Ping(ctx context.Context) error
+	Save(ctx context.Context, c *call.Call) error
+	Get(ctx context.Context, callID uuid.UUID) (*call.Call, error)
+	GetByChannelID(ctx context.Context, channelID string) (*call.Call, error)
+	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*call.Call, error)
+	CountActive(ctx context.Context) (int64, error)
+	MarkEnded(ctx context.Context, callID uuid.UUID) (bool, error)
+}
+
+// ResilienceOptions configures ResilientCallStateRepository.
+type ResilienceOptions struct {
+	// FailureThreshold is the number of consecutive failures that open the
+	// circuit. Zero means DefaultBreakerFailureThreshold.
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before Redis is probed.
+	// Zero means DefaultBreakerCooldown.
+	Cooldown time.Duration
+	// Fallback keeps call state in memory so calls handled by this replica
+	// continue while Redis is unavailable.
+	Fallback bool
+	// TTL expires in-memory entries like the Redis keys.
+	TTL time.Duration
+}
+
+// StateChange describes the store degrading to memory or recovering.
+type StateChange struct {
+	Degraded bool
+	Cause    error // error that opened the circuit; set when degraded
+	Fallback bool  // whether calls continue from memory
+	// Set on recovery
+	Downtime   time.Duration
+	Reconciled int // calls saved in memory during the outage and written back
+}
+
+// StateChangeHandler is called synchronously when the store degrades or
+// recovers, so it must not block.
+type StateChangeHandler func(ctx context.Context, change StateChange)
+
+// localCall is a call state kept in memory.
+type localCall struct {
+	call      call.Call
+	expiresAt time.Time
+	pending   bool   // saved only in memory; written back once Redis recovers
+	version   uint64 // bumped on every save so reconciliation can detect newer writes
+}
+
+// ResilientCallStateRepository keeps call operations working during a brief
+// Redis outage. A circuit breaker stops waiting on Redis after repeated
+// failures and, with the fallback enabled, call state is served from memory:
+// calls handled by this replica continue, but other replicas don't see their
+// updates until Redis recovers and the pending state is reconciled.
+type ResilientCallStateRepository struct {
+	primary  CallStateStore
+	breaker  *circuitBreaker
+	fallback bool
+	ttl      time.Duration
+	onChange StateChangeHandler
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	local   map[uuid.UUID]*localCall
+	version uint64
+}
+
+// NewResilientCallStateRepository wraps a call state store with a circuit
+// breaker and an optional in-memory fallback.
+func NewResilientCallStateRepository(primary CallStateStore, opts ResilienceOptions, logger *zap.Logger) *ResilientCallStateRepository {
+	threshold := opts.FailureThreshold
+	if threshold <= 0 {
+		threshold = DefaultBreakerFailureThreshold
+	}
+	cooldown := opts.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+
+	return &ResilientCallStateRepository{
+		primary:  primary,
+		breaker:  newCircuitBreaker(threshold, cooldown),
+		fallback: opts.Fallback,
+		ttl:      opts.TTL,
+		logger:   logger,
+		local:    make(map[uuid.UUID]*localCall),
+	}
+}
+
+// SetStateChangeHandler sets the handler told when the store degrades or recovers.
+func (r *ResilientCallStateRepository) SetStateChangeHandler(handler StateChangeHandler) {
+	r.onChange = handler
+}
+
+// Save stores a call state in Redis. While Redis is unavailable and the
+// fallback is enabled, the state is kept in memory and saved later.
+func (r *ResilientCallStateRepository) Save(ctx context.Context, c *call.Call) error {
+	err := r.do(ctx, func() error { return r.primary.Save(ctx, c) })
+	if err == nil {
+		r.remember(c, false)
+		return nil
+	}
+	if !r.fallback || ctx.Err() != nil {
+		return err
+	}
+
+	r.remember(c, true)
+	r.logger.Debug("call state saved in memory while redis is unavailable",
+		zap.String("call_id", c.ID.String()),
+		zap.Error(err),
+	)
+	return nil
+}
+
+// Get retrieves a call by ID.
+func (r *ResilientCallStateRepository) Get(ctx context.Context, callID uuid.UUID) (*call.Call, error) {
+	var c *call.Call
+	err := r.do(ctx, func() (err error) {
+		c, err = r.primary.Get(ctx, callID)
+		return err
+	})
+
+	return r.resolve(c, err, func(lc *localCall) bool { return lc.call.ID == callID })
+}
+
+// GetByChannelID retrieves a call by Asterisk channel ID.
+func (r *ResilientCallStateRepository) GetByChannelID(ctx context.Context, channelID string) (*call.Call, error) {
+	var c *call.Call
+	err := r.do(ctx, func() (err error) {
+		c, err = r.primary.GetByChannelID(ctx, channelID)
+		return err
+	})
+
+	return r.resolve(c, err, func(lc *localCall) bool { return lc.call.ChannelID == channelID })
+}
+
+// ListByTenant lists the calls of a tenant. While Redis is unavailable only
+// the calls known to this replica are listed.
+func (r *ResilientCallStateRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*call.Call, error) {
+	var calls []*call.Call
+	err := r.do(ctx, func() (err error) {
+		calls, err = r.primary.ListByTenant(ctx, tenantID)
+		return err
+	})
+	if err != nil && (!r.fallback || ctx.Err() != nil) {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pruneLocked()
+	if err != nil {
+		calls = nil
+	}
+
+	// State saved only in memory is newer than what Redis has
+	listed := make(map[uuid.UUID]int, len(calls))
+	for i, c := range calls {
+		listed[c.ID] = i
+	}
+	for _, lc := range r.local {
+		if lc.call.TenantID != tenantID || (err == nil && !lc.pending) {
+			continue
+		}
+		if i, ok := listed[lc.call.ID]; ok {
+			calls[i] = lc.copy()
+		} else {
+			calls = append(calls, lc.copy())
+		}
+	}
+
+	return calls, nil
+}
+
+// MarkEnded moves a call to the ended state unless it has already ended, and
+// reports whether it did. While Redis is unavailable the compare-and-set runs
+// on the state in memory, which only guards the paths on this replica.
+func (r *ResilientCallStateRepository) MarkEnded(ctx context.Context, callID uuid.UUID) (bool, error) {
+	var marked bool
+	err := r.do(ctx, func() (err error) {
+		marked, err = r.primary.MarkEnded(ctx, callID)
+		return err
+	})
+	if err == nil || errors.Is(err, call.ErrNotFound) || !r.fallback || ctx.Err() != nil {
+		return marked, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lc := r.findLocked(func(lc *localCall) bool { return lc.call.ID == callID })
+	if lc == nil {
+		return false, err
+	}
+	if lc.call.IsEnded() {
+		return false, nil
+	}
+
+	ended := lc.copy()
+	if err := ended.End(); err != nil {
+		return false, err
+	}
+	r.storeLocked(ended, true)
+	return true, nil
+}
+
+// CountActive returns the number of active calls. While Redis is unavailable
+// only the calls in progress on this replica are counted.
+func (r *ResilientCallStateRepository) CountActive(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.do(ctx, func() (err error) {
+		count, err = r.primary.CountActive(ctx)
+		return err
+	})
+	if err == nil {
+		return count, nil
+	}
+	if !r.fallback || ctx.Err() != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pruneLocked()
+	count = 0
+	for _, lc := range r.local {
+		if !lc.call.IsEnded() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Check reports Redis health for the readiness probe. It pings Redis through
+// the circuit breaker, so probes also detect recovery when there is no call
+// traffic, and retries writing back state saved during an outage.
+func (r *ResilientCallStateRepository) Check(ctx context.Context) error {
+	err := r.do(ctx, func() error { return r.primary.Ping(ctx) })
+	if err != nil {
+		if r.fallback {
+			return fmt.Errorf("redis unavailable, serving call state from memory (%d pending): %w", r.Pending(), err)
+		}
+		return fmt.Errorf("redis unavailable: %w", err)
+	}
+
+	if r.Pending() > 0 {
+		if _, err := r.Reconcile(ctx); err != nil {
+			return fmt.Errorf("redis reachable, but pending call state could not be written back: %w", err)
+		}
+	}
+	return nil
+}
+
+// Pending returns the number of calls saved only in memory.
+func (r *ResilientCallStateRepository) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := 0
+	for _, lc := range r.local {
+		if lc.pending {
+			pending++
+		}
+	}
+	return pending
+}
+
+// Reconcile writes the call state saved only in memory back to Redis and
+// returns how many calls were written. It stops at the first failure.
+func (r *ResilientCallStateRepository) Reconcile(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	r.pruneLocked()
+	type pendingCall struct {
+		call    call.Call
+		version uint64
+	}
+	var pending []pendingCall
+	for _, lc := range r.local {
+		if lc.pending {
+			pending = append(pending, pendingCall{call: lc.call, version: lc.version})
+		}
+	}
+	r.mu.Unlock()
+
+	reconciled := 0
+	for _, p := range pending {
+		if err := r.primary.Save(ctx, &p.call); err != nil {
+			r.logger.Warn("failed to write back call state saved during redis outage",
+				zap.String("call_id", p.call.ID.String()),
+				zap.Int("reconciled", reconciled),
+				zap.Int("pending", len(pending)-reconciled),
+				zap.Error(err),
+			)
+			return reconciled, err
+		}
+		reconciled++
+
+		// A newer save during reconciliation stays pending
+		r.mu.Lock()
+		if lc, ok := r.local[p.call.ID]; ok && lc.version == p.version {
+			lc.pending = false
+		}
+		r.mu.Unlock()
+	}
+
+	return reconciled, nil
+}
+
+// do runs op against Redis unless the circuit is open. Failures other than a
+// missing call count towards opening the circuit; a cancelled request says
+// nothing about Redis and is ignored, except that a cancelled probe leaves the
+// circuit open for another cooldown. After the cooldown Redis is probed with
+// a ping, and the state saved during the outage is written back before op
+// runs, so op never reads state older than what this replica has.
+func (r *ResilientCallStateRepository) do(ctx context.Context, op func() error) error {
+	allowed, probe := r.breaker.allow()
+	if !allowed {
+		_, cause := r.breaker.degraded()
+		return fmt.Errorf("%w: circuit open: %v", ErrStateStoreUnavailable, cause)
+	}
+	if probe {
+		err := r.primary.Ping(ctx)
+		if err != nil && ctx.Err() != nil {
+			r.breaker.abandon()
+			return err
+		}
+		if err := r.record(ctx, err); err != nil {
+			return err
+		}
+	}
+
+	return r.record(ctx, op())
+}
+
+// record feeds the outcome of a Redis request to the circuit breaker.
+func (r *ResilientCallStateRepository) record(ctx context.Context, err error) error {
+	if err != nil && !errors.Is(err, call.ErrNotFound) {
+		if ctx.Err() != nil {
+			return err
+		}
+		if r.breaker.failure(err) {
+			r.degrade(ctx, err)
+		}
+		return err
+	}
+
+	if recovered, downtime := r.breaker.success(); recovered {
+		r.recover(ctx, downtime)
+	}
+	return err
+}
+
+// degrade reports the circuit opening.
+func (r *ResilientCallStateRepository) degrade(ctx context.Context, cause error) {
+	r.logger.Error("redis unavailable, call state store degraded",
+		zap.Bool("fallback", r.fallback),
+		zap.Error(cause),
+	)
+
+	if r.onChange != nil {
+		r.onChange(ctx, StateChange{Degraded: true, Cause: cause, Fallback: r.fallback})
+	}
+}
+
+// recover writes back the state saved during the outage and reports the
+// circuit closing.
+func (r *ResilientCallStateRepository) recover(ctx context.Context, downtime time.Duration) {
+	reconciled, err := r.Reconcile(ctx)
+
+	r.logger.Info("redis recovered, call state store reconciled",
+		zap.Duration("downtime", downtime),
+		zap.Int("reconciled", reconciled),
+		zap.Int("pending", r.Pending()),
+		zap.NamedError("reconcile_error", err),
+	)
+
+	if r.onChange != nil {
+		r.onChange(ctx, StateChange{Downtime: downtime, Reconciled: reconciled, Fallback: r.fallback})
+	}
+}
+
+// resolve picks the call to return from a Redis lookup: state saved only in
+// memory wins over Redis, and memory answers for Redis while it is down.
+func (r *ResilientCallStateRepository) resolve(c *call.Call, err error, match func(*localCall) bool) (*call.Call, error) {
+	if !r.fallback {
+		return c, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lc := r.findLocked(match)
+	switch {
+	case lc != nil && (lc.pending || (err != nil && !errors.Is(err, call.ErrNotFound))):
+		return lc.copy(), nil
+	case err != nil:
+		return nil, err
+	}
+
+	r.storeLocked(c, false)
+	return c, nil
+}
+
+// remember keeps a copy of the call in memory when the fallback is enabled.
+func (r *ResilientCallStateRepository) remember(c *call.Call, pending bool) {
+	if !r.fallback {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pruneLocked()
+	r.storeLocked(c, pending)
+}
+
+func (r *ResilientCallStateRepository) storeLocked(c *call.Call, pending bool) {
+	r.version++
+	stored := *c
+	stored.Metadata = maps.Clone(c.Metadata)
+
+	lc := &localCall{call: stored, pending: pending, version: r.version}
+	if r.ttl > 0 {
+		lc.expiresAt = time.Now().Add(r.ttl)
+	}
+	r.local[c.ID] = lc
+}
+
+func (r *ResilientCallStateRepository) findLocked(match func(*localCall) bool) *localCall {
+	for _, lc := range r.local {
+		if match(lc) && !lc.expired(time.Now()) {
+			return lc
+		}
+	}
+	return nil
+}
+
+// pruneLocked drops expired entries, like Redis expiring the keys.
+func (r *ResilientCallStateRepository) pruneLocked() {
+	now := time.Now()
+	for id, lc := range r.local {
+		if lc.expired(now) {
+			delete(r.local, id)
+		}
+	}
+}
+
+func (lc *localCall) expired(now time.Time) bool {
+	return !lc.expiresAt.IsZero() && now.After(lc.expiresAt)
+}
+
+// copy returns a copy of the call that callers may modify.
+func (lc *localCall) copy() *call.Call {
+	c := lc.call
+	c.Metadata = maps.Clone(lc.call.Metadata)
+	return &c
+}
@@ -0,0 +1,461 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/domain/call"
+)
+
+var errRedisDown = errors.New("dial tcp 127.0.0.1:6379: connect: connection refused")
+
+// flakyStore is an in-memory call state store that can be taken down.
+type flakyStore struct {
+	mu    sync.Mutex
+	calls map[uuid.UUID]call.Call
+	down  bool
+	hits  int // requests that reached the store
+}
+
+func newFlakyStore() *flakyStore {
+	return &flakyStore{calls: make(map[uuid.UUID]call.Call)}
+}
+
+func (s *flakyStore) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *flakyStore) reach() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hits++
+	if s.down {
+		return errRedisDown
+	}
+	return nil
+}
+
+func (s *flakyStore) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.reach()
+}
+
+func (s *flakyStore) Save(ctx context.Context, c *call.Call) error {
+	if err := s.reach(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[c.ID] = *c
+	return nil
+}
+
+func (s *flakyStore) Get(ctx context.Context, callID uuid.UUID) (*call.Call, error) {
+	if err := s.reach(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.calls[callID]
+	if !ok {
+		return nil, call.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (s *flakyStore) GetByChannelID(ctx context.Context, channelID string) (*call.Call, error) {
+	if err := s.reach(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.calls {
+		if c.ChannelID == channelID {
+			return &c, nil
+		}
+	}
+	return nil, call.ErrNotFound
+}
+
+func (s *flakyStore) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*call.Call, error) {
+	if err := s.reach(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []*call.Call
+	for _, c := range s.calls {
+		if c.TenantID == tenantID {
+			calls = append(calls, &c)
+		}
+	}
+	return calls, nil
+}
+
+func (s *flakyStore) CountActive(ctx context.Context) (int64, error) {
+	if err := s.reach(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.calls)), nil
+}
+
+func (s *flakyStore) MarkEnded(ctx context.Context, callID uuid.UUID) (bool, error) {
+	if err := s.reach(); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.calls[callID]
+	if !ok {
+		return false, call.ErrNotFound
+	}
+	if c.IsEnded() {
+		return false, nil
+	}
+	c.End()
+	s.calls[callID] = c
+	return true, nil
+}
+
+func (s *flakyStore) stored(callID uuid.UUID) (call.Call, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.calls[callID]
+	return c, ok
+}
+
+// testClock is a settable clock for the circuit breaker.
+type testClock struct{ now time.Time }
+
+func (c *testClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newResilientRepo(store *flakyStore, fallback bool) (*ResilientCallStateRepository, *testClock, *[]StateChange) {
+	repo := NewResilientCallStateRepository(store, ResilienceOptions{
+		FailureThreshold: 2,
+		Cooldown:         10 * time.Second,
+		Fallback:         fallback,
+		TTL:              time.Hour,
+	}, zap.NewNop())
+
+	clock := &testClock{now: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)}
+	repo.breaker.now = func() time.Time { return clock.now }
+
+	changes := &[]StateChange{}
+	repo.SetStateChangeHandler(func(ctx context.Context, change StateChange) {
+		*changes = append(*changes, change)
+	})
+	return repo, clock, changes
+}
+
+func newTestCall() *call.Call {
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	c.ChannelID = "channel-" + c.ID.String()
+	c.State = call.StateRinging
+	return c
+}
+
+func TestResilientCallState_ContinuesCallsDuringOutage(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, _, changes := newResilientRepo(store, true)
+
+	// A call in progress when Redis goes down
+	inProgress := newTestCall()
+	if err := repo.Save(ctx, inProgress); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	store.setDown(true)
+
+	// A new call arriving during the outage
+	incoming := newTestCall()
+	if err := repo.Save(ctx, incoming); err != nil {
+		t.Fatalf("Save during outage should fall back to memory: %v", err)
+	}
+	if count, err := repo.CountActive(ctx); err != nil || count != 2 {
+		t.Errorf("CountActive = %d, %v; expected the 2 local calls", count, err)
+	}
+
+	got, err := repo.Get(ctx, inProgress.ID)
+	if err != nil || got.ChannelID != inProgress.ChannelID {
+		t.Fatalf("Get during outage = %v, %v; expected the call in progress", got, err)
+	}
+	got.Answer()
+	if err := repo.Save(ctx, got); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	byChannel, err := repo.GetByChannelID(ctx, incoming.ChannelID)
+	if err != nil || byChannel.ID != incoming.ID {
+		t.Errorf("GetByChannelID during outage = %v, %v", byChannel, err)
+	}
+	if repo.Pending() != 2 {
+		t.Errorf("Pending = %d, expected 2 calls to reconcile", repo.Pending())
+	}
+
+	if len(*changes) != 1 || !(*changes)[0].Degraded || !errors.Is((*changes)[0].Cause, errRedisDown) || !(*changes)[0].Fallback {
+		t.Errorf("Expected one degraded state change, got %+v", *changes)
+	}
+}
+
+func TestResilientCallState_MarkEnded(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("redis", func(t *testing.T) {
+		store := newFlakyStore()
+		repo, _, _ := newResilientRepo(store, true)
+		c := newTestCall()
+		if err := repo.Save(ctx, c); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		if marked, err := repo.MarkEnded(ctx, c.ID); err != nil || !marked {
+			t.Fatalf("MarkEnded = %v, %v; expected the call marked", marked, err)
+		}
+		if marked, err := repo.MarkEnded(ctx, c.ID); err != nil || marked {
+			t.Errorf("MarkEnded again = %v, %v; expected the ended call left alone", marked, err)
+		}
+		if stored, _ := store.stored(c.ID); stored.State != call.StateEnded {
+			t.Errorf("Expected ended state in redis, got %s", stored.State)
+		}
+	})
+
+	t.Run("during outage", func(t *testing.T) {
+		store := newFlakyStore()
+		repo, _, _ := newResilientRepo(store, true)
+		c := newTestCall()
+		if err := repo.Save(ctx, c); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		store.setDown(true)
+
+		if marked, err := repo.MarkEnded(ctx, c.ID); err != nil || !marked {
+			t.Fatalf("MarkEnded during outage = %v, %v; expected the call marked in memory", marked, err)
+		}
+		if marked, err := repo.MarkEnded(ctx, c.ID); err != nil || marked {
+			t.Errorf("MarkEnded again = %v, %v; expected the ended call left alone", marked, err)
+		}
+		if got, err := repo.Get(ctx, c.ID); err != nil || got.State != call.StateEnded {
+			t.Errorf("Get during outage = %v, %v; expected the ended call", got, err)
+		}
+		if repo.Pending() != 1 {
+			t.Errorf("Pending = %d, expected the ended call to reconcile", repo.Pending())
+		}
+	})
+
+	t.Run("unknown call during outage", func(t *testing.T) {
+		store := newFlakyStore()
+		repo, _, _ := newResilientRepo(store, true)
+		store.setDown(true)
+
+		if _, err := repo.MarkEnded(ctx, uuid.New()); !errors.Is(err, errRedisDown) {
+			t.Errorf("Expected the redis error, got %v", err)
+		}
+	})
+}
+
+func TestResilientCallState_CircuitSkipsRedisUntilCooldown(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, clock, changes := newResilientRepo(store, true)
+	store.setDown(true)
+
+	for i := 0; i < 5; i++ {
+		repo.Save(ctx, newTestCall())
+	}
+	if store.hits != 2 {
+		t.Errorf("Expected Redis to be tried until the circuit opens (2), got %d requests", store.hits)
+	}
+
+	// A failed probe after the cooldown reopens the circuit without a new event
+	clock.advance(10 * time.Second)
+	repo.Save(ctx, newTestCall())
+	repo.Save(ctx, newTestCall())
+	if store.hits != 3 {
+		t.Errorf("Expected a single probe after the cooldown, got %d requests", store.hits)
+	}
+	if len(*changes) != 1 {
+		t.Errorf("Expected the outage to be reported once, got %+v", *changes)
+	}
+}
+
+func TestResilientCallState_CancelledProbeReopensCircuit(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, clock, changes := newResilientRepo(store, true)
+	store.setDown(true)
+
+	for i := 0; i < 2; i++ {
+		repo.Save(ctx, newTestCall())
+	}
+	store.setDown(false)
+
+	// The probe's request is cancelled before Redis answers
+	clock.advance(10 * time.Second)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := repo.Check(cancelled); err == nil {
+		t.Fatal("Expected the cancelled probe to fail")
+	}
+
+	// The next cooldown probes again and finds Redis back
+	clock.advance(10 * time.Second)
+	if err := repo.Check(ctx); err != nil {
+		t.Fatalf("Expected Redis to be probed again after the cooldown: %v", err)
+	}
+	if len(*changes) != 2 || (*changes)[1].Degraded {
+		t.Errorf("Expected the recovery to be reported, got %+v", *changes)
+	}
+}
+
+func TestResilientCallState_ReconcilesOnRecovery(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, clock, changes := newResilientRepo(store, true)
+
+	c := newTestCall()
+	repo.Save(ctx, c)
+	store.setDown(true)
+
+	c.Answer()
+	repo.Save(ctx, c)
+	repo.Save(ctx, c) // opens the circuit
+	incoming := newTestCall()
+	repo.Save(ctx, incoming)
+
+	if stored, _ := store.stored(c.ID); stored.State != call.StateRinging {
+		t.Fatalf("Redis should still have the state from before the outage, got %s", stored.State)
+	}
+
+	store.setDown(false)
+	clock.advance(30 * time.Second)
+
+	// The first request after the cooldown probes Redis and reconciles
+	got, err := repo.Get(ctx, c.ID)
+	if err != nil || got.State != call.StateAnswered {
+		t.Fatalf("Get after recovery = %v, %v; expected answered", got, err)
+	}
+	if stored, _ := store.stored(c.ID); stored.State != call.StateAnswered {
+		t.Errorf("Expected answered state written back to Redis, got %s", stored.State)
+	}
+	if _, ok := store.stored(incoming.ID); !ok {
+		t.Error("Expected call created during the outage to be written back to Redis")
+	}
+	if repo.Pending() != 0 {
+		t.Errorf("Pending = %d after reconciliation", repo.Pending())
+	}
+
+	if len(*changes) != 2 {
+		t.Fatalf("Expected degraded and recovered changes, got %+v", *changes)
+	}
+	recovered := (*changes)[1]
+	if recovered.Degraded || recovered.Reconciled != 2 || recovered.Downtime != 30*time.Second {
+		t.Errorf("Unexpected recovery change %+v", recovered)
+	}
+}
+
+func TestResilientCallState_WithoutFallback(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, _, changes := newResilientRepo(store, false)
+	store.setDown(true)
+
+	c := newTestCall()
+	if err := repo.Save(ctx, c); !errors.Is(err, errRedisDown) {
+		t.Errorf("Save = %v, expected the Redis error", err)
+	}
+	repo.Save(ctx, c)
+
+	// Once open, the circuit fails fast
+	if _, err := repo.Get(ctx, c.ID); !errors.Is(err, ErrStateStoreUnavailable) {
+		t.Errorf("Get = %v, expected ErrStateStoreUnavailable", err)
+	}
+	if store.hits != 2 {
+		t.Errorf("Expected no Redis requests once open, got %d", store.hits)
+	}
+	if len(*changes) != 1 || (*changes)[0].Fallback {
+		t.Errorf("Expected a degraded change without fallback, got %+v", *changes)
+	}
+}
+
+func TestResilientCallState_NotFoundKeepsCircuitClosed(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, _, changes := newResilientRepo(store, true)
+
+	for i := 0; i < 5; i++ {
+		if _, err := repo.Get(ctx, uuid.New()); !errors.Is(err, call.ErrNotFound) {
+			t.Fatalf("Get = %v, expected ErrNotFound", err)
+		}
+	}
+
+	if len(*changes) != 0 {
+		t.Errorf("Missing calls should not open the circuit, got %+v", *changes)
+	}
+}
+
+func TestResilientCallState_Check(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, clock, _ := newResilientRepo(store, true)
+
+	if err := repo.Check(ctx); err != nil {
+		t.Fatalf("Check = %v, expected healthy", err)
+	}
+
+	store.setDown(true)
+	repo.Save(ctx, newTestCall())
+	repo.Save(ctx, newTestCall())
+
+	err := repo.Check(ctx)
+	if err == nil || !strings.Contains(err.Error(), "serving call state from memory (2 pending)") {
+		t.Errorf("Check = %v, expected degraded report", err)
+	}
+
+	// Readiness probes detect recovery even without call traffic
+	store.setDown(false)
+	clock.advance(10 * time.Second)
+	if err := repo.Check(ctx); err != nil {
+		t.Errorf("Check after recovery = %v", err)
+	}
+	if repo.Pending() != 0 {
+		t.Errorf("Pending = %d, expected the probe to reconcile", repo.Pending())
+	}
+}
+
+func TestResilientCallState_ListByTenantPrefersPendingState(t *testing.T) {
+	ctx := context.Background()
+	store := newFlakyStore()
+	repo, _, _ := newResilientRepo(store, true)
+
+	c := newTestCall()
+	repo.Save(ctx, c)
+	store.setDown(true)
+	c.Answer()
+	repo.Save(ctx, c)
+
+	// Redis answers again before the circuit opened, so nothing reconciled yet
+	store.setDown(false)
+	calls, err := repo.ListByTenant(ctx, c.TenantID)
+	if err != nil || len(calls) != 1 || calls[0].State != call.StateAnswered {
+		t.Errorf("ListByTenant = %v, %v; expected the pending answered state", calls, err)
+	}
+
+	store.setDown(true)
+	calls, err = repo.ListByTenant(ctx, c.TenantID)
+	if err != nil || len(calls) != 1 || calls[0].State != call.StateAnswered {
+		t.Errorf("ListByTenant during outage = %v, %v", calls, err)
+	}
+}
@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"voice-gateway/internal/domain/voicemail"
+)
+
+// VoicemailRepository implements voicemail persistence using Redis.
+type VoicemailRepository struct {
+	client    *redis.Client
+	retention time.Duration
+}
+
+// NewVoicemailRepository creates a new Redis-based voicemail repository.
+func NewVoicemailRepository(client *redis.Client, retention time.Duration) *VoicemailRepository {
+	return &VoicemailRepository{
+		client:    client,
+		retention: retention,
+	}
+}
+
+// Save stores a voicemail and indexes it by tenant, newest first.
+func (r *VoicemailRepository) Save(ctx context.Context, v *voicemail.Voicemail) error {
+	key := fmt.Sprintf("voicemail:%s", v.ID)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal voicemail: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, data, r.retention).Err(); err != nil {
+		return fmt.Errorf("failed to save voicemail: %w", err)
+	}
+
+	tenantKey := fmt.Sprintf("voicemails:tenant:%s", v.TenantID)
+	member := redis.Z{
+		Score:  float64(v.CreatedAt.UnixMilli()),
+		Member: v.ID.String(),
+	}
+	if err := r.client.ZAdd(ctx, tenantKey, member).Err(); err != nil {
+		return fmt.Errorf("failed to add to tenant index: %w", err)
+	}
+	r.client.Expire(ctx, tenantKey, r.retention)
+
+	return nil
+}
+
+// Get retrieves a voicemail by ID.
+func (r *VoicemailRepository) Get(ctx context.Context, id uuid.UUID) (*voicemail.Voicemail, error) {
+	key := fmt.Sprintf("voicemail:%s", id)
+
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, voicemail.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get voicemail: %w", err)
+	}
+
+	var v voicemail.Voicemail
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal voicemail: %w", err)
+	}
+
+	return &v, nil
+}
+
+// ListByTenant lists a page of voicemails for a tenant, newest first, and
+// returns the total number of voicemails.
+func (r *VoicemailRepository) ListByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*voicemail.Voicemail, int, error) {
+	tenantKey := fmt.Sprintf("voicemails:tenant:%s", tenantID)
+
+	total, err := r.client.ZCard(ctx, tenantKey).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count tenant voicemails: %w", err)
+	}
+
+	start := int64(offset)
+	stop := start + int64(limit) - 1
+
+	ids, err := r.client.ZRevRange(ctx, tenantKey, start, stop).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list tenant voicemails: %w", err)
+	}
+
+	voicemails := make([]*voicemail.Voicemail, 0, len(ids))
+	for _, idStr := range ids {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			continue // Skip invalid IDs
+		}
+
+		v, err := r.Get(ctx, id)
+		if err != nil {
+			continue // Skip voicemails past retention
+		}
+
+		voicemails = append(voicemails, v)
+	}
+
+	return voicemails, int(total), nil
+}
@@ -0,0 +1,81 @@
+// Package storage provides audio storage implementations.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+)
+
+// FileStore stores audio files on the local filesystem (or a mounted volume).
+type FileStore struct {
+	basePath string
+}
+
+// NewFileStore creates a new filesystem-based store rooted at basePath.
+func NewFileStore(basePath string) *FileStore {
+	return &FileStore{
+		basePath: basePath,
+	}
+}
+
+// Save writes data under key and returns the URI of the stored file.
+func (s *FileStore) Save(ctx context.Context, key string, data []byte) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.basePath, filepath.Clean("/"+key))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return "file://" + path, nil
+}
+
+// Open reads the file stored under key.
+func (s *FileStore) Open(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.basePath, filepath.Clean("/"+key)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return data, nil
+}
+
+// RegionalStore stores each tenant's audio in the FileStore of its
+// data-residency region.
+type RegionalStore struct {
+	router *residency.Router[*FileStore]
+}
+
+// NewRegionalStore creates a RegionalStore using fallback for tenants without
+// a region. Tenants in a region missing from regions can't store audio.
+func NewRegionalStore(fallback *FileStore, regions map[residency.Region]*FileStore) *RegionalStore {
+	router := residency.NewRouter(fallback)
+	for region, store := range regions {
+		router.Add(region, store)
+	}
+	return &RegionalStore{router: router}
+}
+
+// Save writes data under key in the store of region and returns its URI.
+func (s *RegionalStore) Save(ctx context.Context, region residency.Region, key string, data []byte) (string, error) {
+	store, err := s.router.For(region)
+	if err != nil {
+		return "", err
+	}
+	return store.Save(ctx, key, data)
+}
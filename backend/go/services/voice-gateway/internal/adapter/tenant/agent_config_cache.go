@@ -0,0 +1,94 @@
+package tenant
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultAgentConfigCacheTTL is how long an agent config is reused when no
+// update event evicts it first.
+const DefaultAgentConfigCacheTTL = 5 * time.Minute
+
+// cachedAgentConfig is a fetched agent config and when it expires.
+type cachedAgentConfig struct {
+	config    *AgentConfig
+	expiresAt time.Time
+}
+
+// AgentConfigCache caches the agent config of each tenant. Entries expire
+// after the TTL and are evicted as soon as the tenant's agent config is
+// updated, so new calls pick up changes without a restart.
+type AgentConfigCache struct {
+	client *Client
+	ttl    time.Duration
+	now    func() time.Time
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedAgentConfig
+}
+
+// NewAgentConfigCache creates a cache in front of the tenant-manager client.
+// A zero ttl means DefaultAgentConfigCacheTTL.
+func NewAgentConfigCache(client *Client, ttl time.Duration, logger *zap.Logger) *AgentConfigCache {
+	if ttl <= 0 {
+		ttl = DefaultAgentConfigCacheTTL
+	}
+
+	return &AgentConfigCache{
+		client: client,
+		ttl:    ttl,
+		now:    time.Now,
+		logger: logger,
+		cache:  make(map[uuid.UUID]cachedAgentConfig),
+	}
+}
+
+// GetAgentConfig returns the cached agent config of a tenant, fetching it
+// when missing or expired. If tenant-manager can't be reached, an expired
+// config is reused rather than failing the call.
+func (c *AgentConfigCache) GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*AgentConfig, error) {
+	c.mu.Lock()
+	cached, ok := c.cache[tenantID]
+	c.mu.Unlock()
+
+	now := c.now()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.config, nil
+	}
+
+	config, err := c.client.GetAgentConfig(ctx, tenantID)
+	if err != nil {
+		if ok {
+			c.logger.Warn("failed to refresh agent config, using cached config",
+				zap.String("tenant_id", tenantID.String()),
+				zap.Error(err),
+			)
+			return cached.config, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[tenantID] = cachedAgentConfig{config: config, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return config, nil
+}
+
+// Invalidate evicts the cached agent config of a tenant. The next call
+// fetches it again.
+func (c *AgentConfigCache) Invalidate(tenantID uuid.UUID) {
+	c.mu.Lock()
+	_, ok := c.cache[tenantID]
+	delete(c.cache, tenantID)
+	c.mu.Unlock()
+
+	if ok {
+		c.logger.Info("agent config evicted", zap.String("tenant_id", tenantID.String()))
+	}
+}
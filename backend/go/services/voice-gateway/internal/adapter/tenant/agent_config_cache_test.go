@@ -0,0 +1,118 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// newAgentConfigServer serves an agent config whose system prompt names the
+// request number, so refetches are visible.
+func newAgentConfigServer(t *testing.T, requests *int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"agent_id":"agent-receptionist","system_prompt":"prompt v%d"}`, *requests)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAgentConfigCache_ExpiresAfterTTL(t *testing.T) {
+	var requests int
+	server := newAgentConfigServer(t, &requests)
+
+	cache := NewAgentConfigCache(NewClient(server.URL, zap.NewNop()), time.Minute, zap.NewNop())
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	tenantID := uuid.New()
+	for i := 0; i < 3; i++ {
+		if _, err := cache.GetAgentConfig(context.Background(), tenantID); err != nil {
+			t.Fatalf("GetAgentConfig failed: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the cached config to be reused, got %d requests", requests)
+	}
+
+	now = now.Add(2 * time.Minute)
+	config, err := cache.GetAgentConfig(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("GetAgentConfig failed: %v", err)
+	}
+	if requests != 2 || config.SystemPrompt != "prompt v2" {
+		t.Errorf("Expected the expired config to be refetched, got %q after %d requests", config.SystemPrompt, requests)
+	}
+}
+
+func TestAgentConfigCache_InvalidateEvictsTenant(t *testing.T) {
+	var requests int
+	server := newAgentConfigServer(t, &requests)
+
+	cache := NewAgentConfigCache(NewClient(server.URL, zap.NewNop()), time.Hour, zap.NewNop())
+
+	updated, other := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{updated, other} {
+		if _, err := cache.GetAgentConfig(context.Background(), id); err != nil {
+			t.Fatalf("GetAgentConfig failed: %v", err)
+		}
+	}
+
+	cache.Invalidate(updated)
+
+	config, err := cache.GetAgentConfig(context.Background(), updated)
+	if err != nil {
+		t.Fatalf("GetAgentConfig failed: %v", err)
+	}
+	if config.SystemPrompt != "prompt v3" {
+		t.Errorf("Expected the updated prompt after eviction, got %q", config.SystemPrompt)
+	}
+
+	if _, err := cache.GetAgentConfig(context.Background(), other); err != nil {
+		t.Fatalf("GetAgentConfig failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected other tenants to stay cached, got %d requests", requests)
+	}
+}
+
+func TestAgentConfigCache_KeepsStaleConfigWhenUnreachable(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"agent_id":"agent-receptionist"}`))
+	}))
+	defer server.Close()
+
+	cache := NewAgentConfigCache(NewClient(server.URL, zap.NewNop()), time.Minute, zap.NewNop())
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	tenantID := uuid.New()
+	if _, err := cache.GetAgentConfig(context.Background(), tenantID); err != nil {
+		t.Fatalf("GetAgentConfig failed: %v", err)
+	}
+
+	healthy = false
+	now = now.Add(2 * time.Minute)
+	config, err := cache.GetAgentConfig(context.Background(), tenantID)
+	if err != nil || config.AgentID != "agent-receptionist" {
+		t.Errorf("Expected the stale config while tenant-manager is down, got %+v, %v", config, err)
+	}
+
+	if _, err := cache.GetAgentConfig(context.Background(), uuid.New()); err == nil {
+		t.Error("Expected error for an uncached tenant while tenant-manager is down")
+	}
+}
@@ -2,32 +2,65 @@
 package tenant
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/serphona/backend/go/libs/platform-observability/tracing"
+	httpclient "github.com/serphona/serphona/backend/go/libs/platform-httpclient"
 	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+
+	"voice-gateway/internal/domain/routing"
 )
 
+// ErrDIDNotFound is returned by LookupDID for numbers no tenant holds.
+var ErrDIDNotFound = errors.New("DID not found")
+
 // Client is an HTTP client for tenant-manager service.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL    atomic.Pointer[string] // swapped on config reload
+	httpClient *httpclient.Client
 	logger     *zap.Logger
 }
 
 // NewClient creates a new tenant manager client.
 func NewClient(baseURL string, logger *zap.Logger) *Client {
-	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
+	c := &Client{
+		httpClient: httpclient.New(httpclient.Config{
 			Timeout: 10 * time.Second,
-		},
+			Breaker: &httpclient.BreakerConfig{},
+			// Client spans, and the caller's X-Request-ID on every call
+			Wrap: func(rt http.RoundTripper) http.RoundTripper {
+				return tracing.Transport(requestid.NewTransport(rt))
+			},
+		}),
 		logger: logger,
 	}
+	c.SetBaseURL(baseURL)
+	return c
+}
+
+// SetBaseURL points the client at another tenant-manager address. Requests
+// already started keep the previous address.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL.Store(&baseURL)
+}
+
+// base returns the current tenant-manager address.
+func (c *Client) base() string {
+	return *c.baseURL.Load()
 }
 
 // DIDInfo represents DID lookup information.
@@ -35,12 +68,16 @@ type DIDInfo struct {
 	DID      string    `json:"did"`
 	TenantID uuid.UUID `json:"tenant_id"`
 	Enabled  bool      `json:"enabled"`
+	AgentID  string    `json:"agent_id,omitempty"` // agent answering calls to the number
+	FlowID   string    `json:"flow_id,omitempty"`  // flow the agent runs on these calls
+	Label    string    `json:"label,omitempty"`
 }
 
-// LookupDID looks up a DID to find the associated tenant.
+// LookupDID looks up a DID to find the associated tenant and the agent or
+// flow the number is assigned to.
 // GET /api/v1/telephony/dids/lookup/{phone_number}
 func (c *Client) LookupDID(ctx context.Context, phoneNumber string) (*DIDInfo, error) {
-	url := fmt.Sprintf("%s/api/v1/telephony/dids/lookup/%s", c.baseURL, phoneNumber)
+	url := fmt.Sprintf("%s/api/v1/telephony/dids/lookup/%s", c.base(), phoneNumber)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -54,7 +91,7 @@ func (c *Client) LookupDID(ctx context.Context, phoneNumber string) (*DIDInfo, e
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("DID not found: %s", phoneNumber)
+		return nil, fmt.Errorf("%w: %s", ErrDIDNotFound, phoneNumber)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -87,7 +124,7 @@ type ProviderSettings struct {
 // GetProviderSettings retrieves provider settings for a tenant.
 // GET /api/v1/tenants/{tenant_id}/telephony/provider-settings
 func (c *Client) GetProviderSettings(ctx context.Context, tenantID uuid.UUID) (*ProviderSettings, error) {
-	url := fmt.Sprintf("%s/api/v1/tenants/%s/telephony/provider-settings", c.baseURL, tenantID)
+	url := fmt.Sprintf("%s/api/v1/tenants/%s/telephony/provider-settings", c.base(), tenantID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -128,6 +165,20 @@ type AgentConfig struct {
 	Routing          RoutingConfig          `json:"routing"`
 	Safety           SafetyConfig           `json:"safety"`
 	ConversationFlow ConversationFlowConfig `json:"conversation_flow"`
+	Greeting         GreetingConfig         `json:"greeting"`
+}
+
+// Greeting modes.
+const (
+	GreetingModeNone   = "none"   // no greeting; the caller speaks first
+	GreetingModeStatic = "static" // the configured text
+	GreetingModeAgent  = "agent"  // the agent's first turn, falling back to the text
+)
+
+// GreetingConfig represents the greeting played when a call is answered.
+type GreetingConfig struct {
+	Mode string `json:"mode"` // none, static, agent; empty means static
+	Text string `json:"text"`
 }
 
 // VoiceConfig represents voice configuration.
@@ -164,7 +215,7 @@ type ConversationFlowConfig struct {
 // GetAgentConfig retrieves agent configuration for a tenant.
 // GET /api/v1/tenants/{tenant_id}/agent-config
 func (c *Client) GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*AgentConfig, error) {
-	url := fmt.Sprintf("%s/api/v1/tenants/%s/agent-config", c.baseURL, tenantID)
+	url := fmt.Sprintf("%s/api/v1/tenants/%s/agent-config", c.base(), tenantID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -195,10 +246,226 @@ func (c *Client) GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*Agent
 	return &config, nil
 }
 
+// GetRoutingSettings retrieves the agent routing rules from the tenant telephony settings.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetRoutingSettings(ctx context.Context, tenantID uuid.UUID) (*routing.Settings, error) {
+	var body struct {
+		Settings struct {
+			Telephony routing.Settings `json:"telephony"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("routing settings retrieved",
+		zap.String("tenant_id", tenantID.String()),
+		zap.Int("rules", len(body.Settings.Telephony.Rules)),
+	)
+
+	return &body.Settings.Telephony, nil
+}
+
+// GetAllowedCountries retrieves the countries (ISO 3166-1 alpha-2) the
+// tenant's calls may dial, from the tenant telephony settings.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetAllowedCountries(ctx context.Context, tenantID uuid.UUID) ([]string, error) {
+	var body struct {
+		Settings struct {
+			Telephony struct {
+				AllowedCountries []string `json:"allowed_countries"`
+			} `json:"telephony"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return nil, err
+	}
+
+	return body.Settings.Telephony.AllowedCountries, nil
+}
+
+// GetCallerIDNumber retrieves the tenant's default outbound caller ID from the
+// tenant telephony settings; empty when it has none.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetCallerIDNumber(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	var body struct {
+		Settings struct {
+			Telephony struct {
+				CallerIDNumber string `json:"caller_id_number"`
+			} `json:"telephony"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return "", err
+	}
+
+	return body.Settings.Telephony.CallerIDNumber, nil
+}
+
+// GetCDRRounding retrieves the tenant's call billing rounding rule (e.g.
+// "6/6") from the tenant telephony settings; empty when it has none.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetCDRRounding(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	var body struct {
+		Settings struct {
+			Telephony struct {
+				CDRRounding string `json:"cdr_rounding"`
+			} `json:"telephony"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return "", err
+	}
+
+	return body.Settings.Telephony.CDRRounding, nil
+}
+
+// GetFallbackMessage retrieves the message the tenant's callers hear when a
+// provider fails mid-call, from the "fallback" custom prompt of the tenant AI
+// agent settings; empty when it has none.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetFallbackMessage(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	var body struct {
+		Settings struct {
+			AIAgent struct {
+				CustomPrompts map[string]string `json:"custom_prompts"`
+			} `json:"ai_agent"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return "", err
+	}
+
+	return body.Settings.AIAgent.CustomPrompts["fallback"], nil
+}
+
+// RecordingSettings represents the tenant recording and transcription settings.
+type RecordingSettings struct {
+	Plan                 entitlements.Plan `json:"-"`
+	DataResidency        residency.Region  `json:"-"`
+	RecordingEnabled     bool              `json:"recording_enabled"`
+	TranscriptionEnabled bool              `json:"transcription_enabled"`
+}
+
+// GetRecordingSettings retrieves the recording settings from the tenant telephony
+// settings, along with the tenant plan used to check entitlements and the
+// region its recordings must be stored in.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetRecordingSettings(ctx context.Context, tenantID uuid.UUID) (*RecordingSettings, error) {
+	var body struct {
+		Plan     entitlements.Plan `json:"plan"`
+		Settings struct {
+			Telephony     RecordingSettings `json:"telephony"`
+			DataResidency residency.Region  `json:"data_residency"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return nil, err
+	}
+
+	settings := body.Settings.Telephony
+	settings.Plan = body.Plan
+	settings.DataResidency = body.Settings.DataResidency
+
+	return &settings, nil
+}
+
+// GetRedactionPolicy retrieves the PII redaction policy from the tenant
+// security settings.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetRedactionPolicy(ctx context.Context, tenantID uuid.UUID) (redact.Policy, error) {
+	var body struct {
+		Settings struct {
+			Security struct {
+				Redaction redact.Policy `json:"redaction"`
+			} `json:"security"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return redact.Policy{}, err
+	}
+
+	return body.Settings.Security.Redaction, nil
+}
+
+// ResolveCustomer returns the stable ID of the tenant's customer known by
+// identifier (a phone number or email address); tenant-manager creates the
+// customer on first contact.
+// POST /api/v1/tenants/{tenant_id}/customers/resolve
+func (c *Client) ResolveCustomer(ctx context.Context, tenantID uuid.UUID, identifier string) (string, error) {
+	url := fmt.Sprintf("%s/api/v1/tenants/%s/customers/resolve", c.base(), tenantID)
+
+	payload, err := json.Marshal(map[string]string{"identifier": identifier})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Customer struct {
+			ID string `json:"id"`
+		} `json:"customer"`
+		Created bool `json:"created"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	c.logger.Debug("customer resolved",
+		zap.String("tenant_id", tenantID.String()),
+		zap.String("customer_id", body.Customer.ID),
+		zap.Bool("created", body.Created),
+	)
+
+	return body.Customer.ID, nil
+}
+
+// getEffectiveSettings decodes the tenant's effective settings into v:
+// its own settings resolved against its plan and the platform defaults by
+// tenant-manager, so unset values never reach the gateway as zero values.
+func (c *Client) getEffectiveSettings(ctx context.Context, tenantID uuid.UUID, v interface{}) error {
+	url := fmt.Sprintf("%s/api/v1/tenants/%s/settings/effective", c.base(), tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
 // GetTenantInfo retrieves basic tenant information.
 // GET /api/v1/tenants/{tenant_id}
 func (c *Client) GetTenantInfo(ctx context.Context, tenantID uuid.UUID) (map[string]interface{}, error) {
-	url := fmt.Sprintf("%s/api/v1/tenants/%s", c.baseURL, tenantID)
+	url := fmt.Sprintf("%s/api/v1/tenants/%s", c.base(), tenantID)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
@@ -222,3 +489,88 @@ func (c *Client) GetTenantInfo(ctx context.Context, tenantID uuid.UUID) (map[str
 
 	return tenantInfo, nil
 }
+
+// TenantStatus returns the tenant's status; a tenant tenant-manager no longer
+// returns is tenantstatus.StatusDeleted.
+// GET /api/v1/tenants/{tenant_id}
+func (c *Client) TenantStatus(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	url := fmt.Sprintf("%s/api/v1/tenants/%s", c.base(), tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return tenantstatus.StatusDeleted, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return body.Status, nil
+}
+
+// BusinessContext is what a tenant tells its agents about itself.
+type BusinessContext struct {
+	Name        string
+	Description string
+	Timezone    string // IANA name of the tenant's business hours; empty means UTC
+}
+
+// GetBusinessContext retrieves the tenant name, the description of its
+// business from the AI agent settings and the timezone of its business hours.
+// GET /api/v1/tenants/{tenant_id}
+func (c *Client) GetBusinessContext(ctx context.Context, tenantID uuid.UUID) (*BusinessContext, error) {
+	url := fmt.Sprintf("%s/api/v1/tenants/%s", c.base(), tenantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Name     string `json:"name"`
+		Settings struct {
+			AIAgent struct {
+				BusinessContext string `json:"business_context"`
+			} `json:"ai_agent"`
+			Telephony struct {
+				BusinessHours struct {
+					Timezone string `json:"timezone"`
+				} `json:"business_hours"`
+			} `json:"telephony"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &BusinessContext{
+		Name:        body.Name,
+		Description: body.Settings.AIAgent.BusinessContext,
+		Timezone:    body.Settings.Telephony.BusinessHours.Timezone,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/serphona/serphona/backend/go/libs/platform-auth/tenantstatus"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/requestid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestClient_PropagatesTraceparent(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	provider := sdktrace.NewTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer provider.Shutdown(context.Background())
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"agent_id":"agent-receptionist"}`))
+	}))
+	defer server.Close()
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "handle call")
+	defer span.End()
+
+	client := NewClient(server.URL, zap.NewNop())
+	if _, err := client.GetAgentConfig(ctx, uuid.New()); err != nil {
+		t.Fatalf("GetAgentConfig failed: %v", err)
+	}
+
+	// traceparent: version-traceid-parentid-flags
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 {
+		t.Fatalf("Expected a W3C traceparent header, got %q", traceparent)
+	}
+
+	sc := trace.SpanContextFromContext(ctx)
+	if parts[1] != sc.TraceID().String() {
+		t.Errorf("Expected trace ID %s to be propagated, got %s", sc.TraceID(), parts[1])
+	}
+	if parts[2] == sc.SpanID().String() {
+		t.Error("Expected the parent to be the client span, not the caller span")
+	}
+	if parts[3] != "01" {
+		t.Errorf("Expected sampled flag, got %s", parts[3])
+	}
+}
+
+func TestClient_ForwardsRequestID(t *testing.T) {
+	var forwarded string
+	upstream := httptest.NewServer(requestid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = requestid.FromContext(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"agent_id":"agent-receptionist"}`))
+	})))
+	defer upstream.Close()
+
+	// An incoming request to voice-gateway calls tenant-manager with its context
+	client := NewClient(upstream.URL, zap.NewNop())
+	gateway := requestid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := client.GetAgentConfig(r.Context(), uuid.New()); err != nil {
+			t.Errorf("GetAgentConfig failed: %v", err)
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/asterisk/events", nil)
+	req.Header.Set(requestid.Header, "req-voice-1")
+	gateway.ServeHTTP(httptest.NewRecorder(), req)
+
+	if forwarded != "req-voice-1" {
+		t.Errorf("Expected tenant-manager to see request ID req-voice-1, got %q", forwarded)
+	}
+}
+
+func TestClient_ReadsEffectiveSettings(t *testing.T) {
+	tenantID := uuid.New()
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tenant_id":"` + tenantID.String() + `","plan":"professional","settings":{` +
+			`"telephony":{"recording_enabled":true,"transcription_enabled":false},"data_residency":"eu"}}`))
+	}))
+	defer server.Close()
+
+	settings, err := NewClient(server.URL, zap.NewNop()).GetRecordingSettings(context.Background(), tenantID)
+	if err != nil {
+		t.Fatalf("GetRecordingSettings failed: %v", err)
+	}
+
+	if want := "/api/v1/tenants/" + tenantID.String() + "/settings/effective"; path != want {
+		t.Errorf("Expected request to %s, got %s", want, path)
+	}
+	if settings.Plan != "professional" || settings.DataResidency != "eu" || !settings.RecordingEnabled || settings.TranscriptionEnabled {
+		t.Errorf("Unexpected recording settings: %+v", settings)
+	}
+}
+
+func TestClient_TenantStatus(t *testing.T) {
+	suspended := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tenants/"+suspended.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"` + suspended.String() + `","status":"suspended"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, zap.NewNop())
+	if status, err := client.TenantStatus(context.Background(), suspended); err != nil || status != tenantstatus.StatusSuspended {
+		t.Errorf("Expected suspended, got %q (%v)", status, err)
+	}
+	if status, err := client.TenantStatus(context.Background(), uuid.New()); err != nil || status != tenantstatus.StatusDeleted {
+		t.Errorf("Expected an unknown tenant to be deleted, got %q (%v)", status, err)
+	}
+}
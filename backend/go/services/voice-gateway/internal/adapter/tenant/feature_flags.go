@@ -0,0 +1,157 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultFeatureFlagsCacheTTL is how long resolved tenant flags are reused.
+const DefaultFeatureFlagsCacheTTL = time.Minute
+
+// FeatureFlags are the voice-gateway features enabled for a tenant.
+type FeatureFlags struct {
+	CallRecording        bool
+	TranscriptionStorage bool
+	AudioStreaming       bool
+
+	// Frames of caller audio held by the jitter buffer; 0 disables it
+	JitterBufferDepth int
+}
+
+// FeatureFlagOverrides are the tenant settings that override the global
+// defaults. Unset flags keep the default.
+type FeatureFlagOverrides struct {
+	CallRecording        *bool `json:"call_recording,omitempty"`
+	TranscriptionStorage *bool `json:"transcription_storage,omitempty"`
+	AudioStreaming       *bool `json:"audio_streaming,omitempty"`
+	JitterBufferDepth    *int  `json:"jitter_buffer_depth,omitempty"`
+}
+
+// Apply returns the defaults with the tenant overrides applied.
+func (o FeatureFlagOverrides) Apply(defaults FeatureFlags) FeatureFlags {
+	flags := defaults
+	if o.CallRecording != nil {
+		flags.CallRecording = *o.CallRecording
+	}
+	if o.TranscriptionStorage != nil {
+		flags.TranscriptionStorage = *o.TranscriptionStorage
+	}
+	if o.AudioStreaming != nil {
+		flags.AudioStreaming = *o.AudioStreaming
+	}
+	if o.JitterBufferDepth != nil && *o.JitterBufferDepth >= 0 {
+		flags.JitterBufferDepth = *o.JitterBufferDepth
+	}
+	return flags
+}
+
+// GetFeatureFlagOverrides retrieves the feature flag overrides from the tenant
+// telephony settings.
+// GET /api/v1/tenants/{tenant_id}/settings/effective
+func (c *Client) GetFeatureFlagOverrides(ctx context.Context, tenantID uuid.UUID) (*FeatureFlagOverrides, error) {
+	var body struct {
+		Settings struct {
+			Telephony struct {
+				FeatureFlags FeatureFlagOverrides `json:"feature_flags"`
+			} `json:"telephony"`
+		} `json:"settings"`
+	}
+	if err := c.getEffectiveSettings(ctx, tenantID, &body); err != nil {
+		return nil, err
+	}
+
+	return &body.Settings.Telephony.FeatureFlags, nil
+}
+
+// cachedFlags is a resolved set of flags and when it expires.
+type cachedFlags struct {
+	flags     FeatureFlags
+	expiresAt time.Time
+}
+
+// FeatureFlagResolver resolves the feature flags of a tenant from the global
+// defaults and the tenant overrides, caching the result per tenant.
+type FeatureFlagResolver struct {
+	client   *Client
+	defaults FeatureFlags
+	ttl      time.Duration
+	now      func() time.Time
+	logger   *zap.Logger
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedFlags
+}
+
+// NewFeatureFlagResolver creates a resolver. A zero ttl means
+// DefaultFeatureFlagsCacheTTL.
+func NewFeatureFlagResolver(client *Client, defaults FeatureFlags, ttl time.Duration, logger *zap.Logger) *FeatureFlagResolver {
+	if ttl <= 0 {
+		ttl = DefaultFeatureFlagsCacheTTL
+	}
+
+	return &FeatureFlagResolver{
+		client:   client,
+		defaults: defaults,
+		ttl:      ttl,
+		now:      time.Now,
+		logger:   logger,
+		cache:    make(map[uuid.UUID]cachedFlags),
+	}
+}
+
+// GetFeatureFlags returns the flags of a tenant: tenant overrides win over the
+// global defaults. If tenant-manager can't be reached, the last resolved flags
+// are reused; with none cached, the defaults are returned with the error so
+// callers can decide whether the defaults are safe to act on.
+func (r *FeatureFlagResolver) GetFeatureFlags(ctx context.Context, tenantID uuid.UUID) (FeatureFlags, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[tenantID]
+	defaults := r.defaults
+	r.mu.Unlock()
+
+	now := r.now()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.flags, nil
+	}
+
+	overrides, err := r.client.GetFeatureFlagOverrides(ctx, tenantID)
+	if err != nil {
+		if ok {
+			r.logger.Warn("failed to refresh tenant feature flags, using cached flags",
+				zap.String("tenant_id", tenantID.String()),
+				zap.Error(err),
+			)
+			return cached.flags, nil
+		}
+		return defaults, fmt.Errorf("failed to get tenant feature flags: %w", err)
+	}
+
+	flags := overrides.Apply(defaults)
+
+	r.mu.Lock()
+	r.cache[tenantID] = cachedFlags{flags: flags, expiresAt: now.Add(r.ttl)}
+	r.mu.Unlock()
+
+	return flags, nil
+}
+
+// Defaults returns the global default flags.
+func (r *FeatureFlagResolver) Defaults() FeatureFlags {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.defaults
+}
+
+// SetDefaults replaces the global default flags. Cached tenant flags were
+// resolved from the old defaults, so they are dropped.
+func (r *FeatureFlagResolver) SetDefaults(defaults FeatureFlags) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaults = defaults
+	r.cache = make(map[uuid.UUID]cachedFlags)
+}
@@ -0,0 +1,120 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// newFlagsServer serves tenant settings with the given feature flag overrides
+// and counts the requests.
+func newFlagsServer(t *testing.T, featureFlags string, requests *int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"plan":"pro","settings":{"telephony":{"feature_flags":` + featureFlags + `}}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFeatureFlagResolver_TenantOverrideWins(t *testing.T) {
+	var requests int
+	server := newFlagsServer(t, `{"call_recording":false,"audio_streaming":true}`, &requests)
+
+	defaults := FeatureFlags{CallRecording: true, TranscriptionStorage: true, AudioStreaming: false}
+	resolver := NewFeatureFlagResolver(NewClient(server.URL, zap.NewNop()), defaults, time.Minute, zap.NewNop())
+
+	flags, err := resolver.GetFeatureFlags(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("GetFeatureFlags failed: %v", err)
+	}
+
+	if flags.CallRecording {
+		t.Error("Expected the tenant to disable call recording over the global default")
+	}
+	if !flags.AudioStreaming {
+		t.Error("Expected the tenant to enable audio streaming over the global default")
+	}
+	if !flags.TranscriptionStorage {
+		t.Error("Expected transcription storage without an override to keep the global default")
+	}
+}
+
+func TestFeatureFlagResolver_JitterBufferDepth(t *testing.T) {
+	defaults := FeatureFlags{AudioStreaming: true, JitterBufferDepth: 3}
+
+	for _, tt := range []struct {
+		overrides string
+		want      int
+	}{
+		{`{}`, 3},
+		{`{"jitter_buffer_depth":8}`, 8},
+		{`{"jitter_buffer_depth":0}`, 0},
+		{`{"jitter_buffer_depth":-1}`, 3},
+	} {
+		var requests int
+		server := newFlagsServer(t, tt.overrides, &requests)
+		resolver := NewFeatureFlagResolver(NewClient(server.URL, zap.NewNop()), defaults, time.Minute, zap.NewNop())
+
+		flags, err := resolver.GetFeatureFlags(context.Background(), uuid.New())
+		if err != nil {
+			t.Fatalf("GetFeatureFlags failed: %v", err)
+		}
+		if flags.JitterBufferDepth != tt.want {
+			t.Errorf("overrides %s: jitter buffer depth = %d, want %d", tt.overrides, flags.JitterBufferDepth, tt.want)
+		}
+	}
+}
+
+func TestFeatureFlagResolver_CachesPerTenant(t *testing.T) {
+	var requests int
+	server := newFlagsServer(t, `{}`, &requests)
+
+	resolver := NewFeatureFlagResolver(NewClient(server.URL, zap.NewNop()), FeatureFlags{CallRecording: true}, time.Minute, zap.NewNop())
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	tenantID := uuid.New()
+	for i := 0; i < 3; i++ {
+		if _, err := resolver.GetFeatureFlags(context.Background(), tenantID); err != nil {
+			t.Fatalf("GetFeatureFlags failed: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected cached flags to be reused, got %d requests", requests)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := resolver.GetFeatureFlags(context.Background(), tenantID); err != nil {
+		t.Fatalf("GetFeatureFlags failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected expired flags to be refreshed, got %d requests", requests)
+	}
+}
+
+func TestFeatureFlagResolver_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	defaults := FeatureFlags{CallRecording: true, AudioStreaming: true}
+	resolver := NewFeatureFlagResolver(NewClient(server.URL, zap.NewNop()), defaults, time.Minute, zap.NewNop())
+
+	flags, err := resolver.GetFeatureFlags(context.Background(), uuid.New())
+	if err == nil {
+		t.Fatal("Expected error when tenant-manager is unavailable")
+	}
+	if flags != defaults {
+		t.Errorf("Expected the global defaults, got %+v", flags)
+	}
+}
@@ -0,0 +1,75 @@
+package tts
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// Cache keeps synthesized audio in memory so common phrases, such as agent
+// greetings, are synthesized once. The least recently used entries are
+// evicted beyond maxEntries.
+type Cache struct {
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List // front is the most recently used
+	maxEntries int
+}
+
+// cacheEntry is a cached synthesis result.
+type cacheEntry struct {
+	key   string
+	audio []byte
+}
+
+// NewCache creates a cache holding up to maxEntries synthesis results.
+func NewCache(maxEntries int) *Cache {
+	return &Cache{
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		maxEntries: maxEntries,
+	}
+}
+
+// CacheKey identifies the audio a provider synthesizes for text with config.
+// It is URL-safe, so it can name the audio served to Asterisk.
+func CacheKey(provider, text string, config SynthesizeConfig) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%g|%g|%d|%s|%s",
+		provider, config.Language, config.VoiceID, config.Gender,
+		config.SpeechRate, config.Pitch, config.SampleRate, config.AudioEncoding, text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached audio for key.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).audio, true
+}
+
+// Put caches audio under key.
+func (c *Cache) Put(key string, audio []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).audio = audio
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, audio: audio})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
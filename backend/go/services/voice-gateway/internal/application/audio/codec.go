@@ -0,0 +1,211 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Codec is the audio codec of a call leg, named as Asterisk names formats.
+type Codec string
+
+const (
+	CodecULaw   Codec = "ulaw"   // G.711 μ-law, 8 kHz
+	CodecALaw   Codec = "alaw"   // G.711 A-law, 8 kHz
+	CodecOpus   Codec = "opus"   // Opus, 48 kHz
+	CodecSLIN   Codec = "slin"   // signed linear PCM, 8 kHz
+	CodecSLIN16 Codec = "slin16" // signed linear PCM, 16 kHz
+)
+
+// ErrUnsupportedCodec is returned for a codec the gateway can't decode.
+var ErrUnsupportedCodec = errors.New("unsupported audio codec")
+
+// codecRates are the sample rates of the supported codecs.
+var codecRates = map[Codec]int{
+	CodecULaw:   8000,
+	CodecALaw:   8000,
+	CodecOpus:   48000,
+	CodecSLIN:   8000,
+	CodecSLIN16: 16000,
+}
+
+// ParseCodec parses a channel's native format as reported by Asterisk, e.g.
+// "(ulaw)" or "(opus|ulaw)", where the first format is the one in use.
+func ParseCodec(format string) (Codec, error) {
+	format = strings.Trim(strings.TrimSpace(format), "()")
+	if i := strings.IndexAny(format, "|,"); i >= 0 {
+		format = format[:i]
+	}
+
+	codec := Codec(strings.ToLower(strings.TrimSpace(format)))
+	if _, ok := codecRates[codec]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedCodec, format)
+	}
+	return codec, nil
+}
+
+// SampleRate returns the sample rate of the codec in Hz.
+func (c Codec) SampleRate() int {
+	return codecRates[c]
+}
+
+// Pipeline stages, in the order they run.
+const (
+	StageULawDecode = "ulaw_decode"
+	StageALawDecode = "alaw_decode"
+	StageOpusDecode = "opus_decode"
+	StageResample   = "resample"
+)
+
+// Pipeline decodes a call's audio into the 16-bit PCM an STT provider
+// requires.
+type Pipeline struct {
+	Codec  Codec
+	Target AudioFormat
+	Stages []string
+
+	opus      *StreamConverter
+	resampler *PCMConverter
+}
+
+// NewDecodePipeline selects the stages that turn audio in codec into target,
+// which must be PCM.
+func NewDecodePipeline(codec Codec, target AudioFormat, logger *zap.Logger) (*Pipeline, error) {
+	rate, ok := codecRates[codec]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCodec, codec)
+	}
+	if target.Encoding != "pcm" {
+		return nil, fmt.Errorf("decode target must be pcm, got %q", target.Encoding)
+	}
+
+	p := &Pipeline{Codec: codec, Target: target}
+	switch codec {
+	case CodecULaw:
+		p.Stages = append(p.Stages, StageULawDecode)
+	case CodecALaw:
+		p.Stages = append(p.Stages, StageALawDecode)
+	case CodecOpus:
+		p.Stages = append(p.Stages, StageOpusDecode)
+		p.opus = NewStreamConverter(
+			AudioFormat{SampleRate: rate, Channels: 1, Encoding: "opus"},
+			AudioFormat{SampleRate: rate, Channels: 1, BitDepth: 16, Encoding: "pcm"},
+			logger,
+		)
+	}
+	if target.SampleRate != rate {
+		p.Stages = append(p.Stages, StageResample)
+		p.resampler = NewPCMConverter(logger)
+	}
+
+	return p, nil
+}
+
+// Reader returns the audio of r decoded by the pipeline.
+func (p *Pipeline) Reader(ctx context.Context, r io.Reader) (io.Reader, error) {
+	var err error
+	switch p.Codec {
+	case CodecULaw:
+		r = &transformReader{source: r, transform: decodeG711(ulawTable)}
+	case CodecALaw:
+		r = &transformReader{source: r, transform: decodeG711(alawTable)}
+	case CodecOpus:
+		if r, err = p.opus.Convert(ctx, r); err != nil {
+			return nil, fmt.Errorf("failed to decode opus: %w", err)
+		}
+	}
+
+	if p.resampler != nil {
+		from, to := p.Codec.SampleRate(), p.Target.SampleRate
+		r = &transformReader{source: r, transform: func(pcm []byte) ([]byte, error) {
+			return p.resampler.Resample(pcm, from, to)
+		}}
+	}
+	return r, nil
+}
+
+// G.711 expansion tables, indexed by the encoded byte.
+var ulawTable, alawTable [256]int16
+
+func init() {
+	for i := range 256 {
+		ulawTable[i] = ulawToLinear(byte(i))
+		alawTable[i] = alawToLinear(byte(i))
+	}
+}
+
+// ulawToLinear expands a G.711 μ-law sample.
+func ulawToLinear(u byte) int16 {
+	u = ^u
+	t := (int16(u&0x0F) << 3) + 0x84
+	t <<= (u & 0x70) >> 4
+	if u&0x80 != 0 {
+		return 0x84 - t
+	}
+	return t - 0x84
+}
+
+// alawToLinear expands a G.711 A-law sample.
+func alawToLinear(a byte) int16 {
+	a ^= 0x55
+	t := int16(a&0x0F) << 4
+	switch seg := (a & 0x70) >> 4; seg {
+	case 0:
+		t += 8
+	case 1:
+		t += 0x108
+	default:
+		t += 0x108
+		t <<= seg - 1
+	}
+	if a&0x80 != 0 {
+		return t
+	}
+	return -t
+}
+
+// decodeG711 expands 8-bit G.711 samples into 16-bit little-endian PCM.
+func decodeG711(table [256]int16) func([]byte) ([]byte, error) {
+	return func(encoded []byte) ([]byte, error) {
+		pcm := make([]byte, 2*len(encoded))
+		for i, b := range encoded {
+			binary.LittleEndian.PutUint16(pcm[2*i:], uint16(table[b]))
+		}
+		return pcm, nil
+	}
+}
+
+// transformReader applies transform to the audio read from source, a read
+// at a time.
+type transformReader struct {
+	source    io.Reader
+	transform func([]byte) ([]byte, error)
+	in        [4096]byte
+	out       []byte
+	err       error
+}
+
+func (t *transformReader) Read(p []byte) (int, error) {
+	for len(t.out) == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+		n, err := t.source.Read(t.in[:])
+		t.err = err
+		if n > 0 {
+			if t.out, err = t.transform(t.in[:n]); err != nil {
+				t.err = err
+				return 0, err
+			}
+		}
+	}
+
+	n := copy(p, t.out)
+	t.out = t.out[n:]
+	return n, nil
+}
@@ -0,0 +1,95 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestParseCodec(t *testing.T) {
+	tests := map[string]Codec{
+		"(ulaw)":      CodecULaw,
+		"alaw":        CodecALaw,
+		"(opus|ulaw)": CodecOpus,
+		"(SLIN16)":    CodecSLIN16,
+	}
+	for format, want := range tests {
+		if got, err := ParseCodec(format); err != nil || got != want {
+			t.Errorf("ParseCodec(%q) = %q, %v; want %q", format, got, err, want)
+		}
+	}
+
+	for _, format := range []string{"(g729)", "gsm", ""} {
+		if _, err := ParseCodec(format); !errors.Is(err, ErrUnsupportedCodec) {
+			t.Errorf("ParseCodec(%q): expected ErrUnsupportedCodec, got %v", format, err)
+		}
+	}
+}
+
+func TestNewDecodePipeline(t *testing.T) {
+	stt := AudioFormat{SampleRate: 16000, Channels: 1, BitDepth: 16, Encoding: "pcm"}
+
+	tests := []struct {
+		codec  Codec
+		stages []string
+	}{
+		{CodecULaw, []string{StageULawDecode, StageResample}},
+		{CodecALaw, []string{StageALawDecode, StageResample}},
+		{CodecOpus, []string{StageOpusDecode, StageResample}},
+		{CodecSLIN16, nil},
+	}
+	for _, tt := range tests {
+		p, err := NewDecodePipeline(tt.codec, stt, zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewDecodePipeline(%s) failed: %v", tt.codec, err)
+		}
+		if !slices.Equal(p.Stages, tt.stages) {
+			t.Errorf("%s stages = %v, want %v", tt.codec, p.Stages, tt.stages)
+		}
+	}
+
+	if _, err := NewDecodePipeline("g729", stt, zap.NewNop()); !errors.Is(err, ErrUnsupportedCodec) {
+		t.Errorf("Expected ErrUnsupportedCodec, got %v", err)
+	}
+}
+
+func TestPipeline_DecodesULaw(t *testing.T) {
+	p, err := NewDecodePipeline(CodecULaw, AudioFormat{SampleRate: 8000, Channels: 1, BitDepth: 16, Encoding: "pcm"}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewDecodePipeline failed: %v", err)
+	}
+
+	r, err := p.Reader(context.Background(), strings.NewReader("\xff\x00\x80\x7f"))
+	if err != nil {
+		t.Fatalf("Reader failed: %v", err)
+	}
+	pcm, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+
+	want := []int16{0, -32124, 32124, 0}
+	if len(pcm) != 2*len(want) {
+		t.Fatalf("Decoded %d bytes, want 16-bit samples: %d", len(pcm), 2*len(want))
+	}
+	for i, sample := range want {
+		if got := int16(binary.LittleEndian.Uint16(pcm[2*i:])); got != sample {
+			t.Errorf("sample %d = %d, want %d", i, got, sample)
+		}
+	}
+}
+
+func TestALawToLinear(t *testing.T) {
+	tests := map[byte]int16{0xD5: 8, 0x55: -8, 0xAA: 32256, 0x2A: -32256}
+	for encoded, want := range tests {
+		if got := alawToLinear(encoded); got != want {
+			t.Errorf("alawToLinear(%#x) = %d, want %d", encoded, got, want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package audio
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"voice-gateway/internal/adapter/metrics"
+)
+
+// DefaultFrameDuration is the cadence frames are played at when a
+// JitterBuffer doesn't set one: 20 ms, the usual RTP packetization time.
+const DefaultFrameDuration = 20 * time.Millisecond
+
+// jitterCapacity bounds a jitter buffer at this many times its depth; past it
+// the oldest frames are dropped.
+const jitterCapacity = 4
+
+// Frame is a packet of audio numbered by its RTP sequence number.
+type Frame struct {
+	Seq     uint16
+	Payload []byte
+}
+
+// JitterBufferOptions configures a JitterBuffer.
+type JitterBufferOptions struct {
+	// Depth is how many frames are held before playout starts, and how many
+	// frames after a missing one must arrive before it is given up on. A
+	// deeper buffer absorbs more jitter at the cost of Depth frames of
+	// latency. Values below 1 mean 1.
+	Depth int
+	// FrameDuration is the cadence frames are played at. Zero means
+	// DefaultFrameDuration.
+	FrameDuration time.Duration
+	// Stream labels the buffer metrics (e.g. "stt").
+	Stream string
+}
+
+// JitterBuffer reorders frames that arrive out of order or unevenly and
+// plays them back in sequence at a steady cadence. Frames that arrive after
+// their slot was played are discarded as late.
+type JitterBuffer struct {
+	opts JitterBufferOptions
+
+	mu       sync.Mutex
+	frames   map[uint16][]byte // by sequence number
+	next     uint16            // sequence number of the next frame to play
+	playing  bool              // prefilled, playout started
+	draining bool              // no more frames coming; gaps are skipped
+}
+
+// NewJitterBuffer creates an empty jitter buffer.
+func NewJitterBuffer(opts JitterBufferOptions) *JitterBuffer {
+	if opts.Depth < 1 {
+		opts.Depth = 1
+	}
+	if opts.FrameDuration <= 0 {
+		opts.FrameDuration = DefaultFrameDuration
+	}
+	return &JitterBuffer{
+		opts:   opts,
+		frames: make(map[uint16][]byte),
+	}
+}
+
+// seqBefore reports whether sequence number a comes before b, allowing for
+// the 16-bit wraparound of RTP sequence numbers.
+func seqBefore(a, b uint16) bool {
+	return int16(a-b) < 0
+}
+
+// Push adds a frame. Duplicates are ignored.
+func (jb *JitterBuffer) Push(f Frame) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+
+	if jb.playing && seqBefore(f.Seq, jb.next) {
+		metrics.IncJitterLateFrame(jb.opts.Stream)
+		return
+	}
+	if _, dup := jb.frames[f.Seq]; dup {
+		return
+	}
+	if !jb.playing && (len(jb.frames) == 0 || seqBefore(f.Seq, jb.next)) {
+		jb.next = f.Seq
+	}
+	jb.frames[f.Seq] = f.Payload
+
+	if !jb.playing && len(jb.frames) >= jb.opts.Depth {
+		jb.playing = true
+	}
+	if over := len(jb.frames) - jb.opts.Depth*jitterCapacity; over > 0 {
+		for range over {
+			jb.next = jb.oldest()
+			delete(jb.frames, jb.next)
+			jb.next++
+		}
+		metrics.AddJitterDroppedFrames(jb.opts.Stream, "overflow", over)
+	}
+}
+
+// oldest returns the lowest buffered sequence number. The buffer must not
+// be empty.
+func (jb *JitterBuffer) oldest() uint16 {
+	first := true
+	var oldest uint16
+	for seq := range jb.frames {
+		if first || seqBefore(seq, oldest) {
+			oldest, first = seq, false
+		}
+	}
+	return oldest
+}
+
+// Pop returns the next frame to play. It returns false while the buffer is
+// prefilling, when it ran dry, and while a missing frame is still being
+// waited for; once Depth later frames arrived, the missing ones are counted
+// as lost and skipped.
+func (jb *JitterBuffer) Pop() ([]byte, bool) {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+
+	if (!jb.playing && !jb.draining) || len(jb.frames) == 0 {
+		return nil, false
+	}
+
+	payload, ok := jb.frames[jb.next]
+	if !ok {
+		if !jb.draining && len(jb.frames) < jb.opts.Depth {
+			return nil, false
+		}
+		oldest := jb.oldest()
+		metrics.AddJitterDroppedFrames(jb.opts.Stream, "lost", int(oldest-jb.next))
+		jb.next = oldest
+		payload = jb.frames[oldest]
+	}
+	delete(jb.frames, jb.next)
+	jb.next++
+	jb.playing = true
+	return payload, true
+}
+
+// Len returns the number of buffered frames.
+func (jb *JitterBuffer) Len() int {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	return len(jb.frames)
+}
+
+// Close marks the end of the input: Pop plays the remaining frames without
+// prefilling or waiting for missing ones.
+func (jb *JitterBuffer) Close() {
+	jb.mu.Lock()
+	defer jb.mu.Unlock()
+	jb.draining = true
+}
+
+// Run pushes the frames from in and plays one frame per FrameDuration on the
+// returned channel. Once in is closed the remaining frames are played and
+// the channel is closed; it is also closed when ctx is done.
+func (jb *JitterBuffer) Run(ctx context.Context, in <-chan Frame) <-chan []byte {
+	out := make(chan []byte)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(jb.opts.FrameDuration)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case f, ok := <-in:
+				if !ok {
+					jb.Close()
+					in = nil
+					continue
+				}
+				jb.Push(f)
+			case <-ticker.C:
+				payload, ok := jb.Pop()
+				if !ok {
+					if in == nil {
+						return
+					}
+					continue
+				}
+				select {
+				case out <- payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// Dejitter splits source into frames of frameSize bytes, numbered in the
+// order they are read, and returns them played through a jitter buffer. A
+// read error of source is returned by the reader after the audio read
+// before it.
+func Dejitter(ctx context.Context, source io.Reader, frameSize int, opts JitterBufferOptions) io.Reader {
+	chunks, errs := NewChunkReader(source, frameSize).ReadChunks(ctx)
+
+	frames := make(chan Frame)
+	go func() {
+		defer close(frames)
+
+		var seq uint16
+		for chunk := range chunks {
+			select {
+			case frames <- Frame{Seq: seq, Payload: chunk}:
+			case <-ctx.Done():
+				return
+			}
+			seq++
+		}
+	}()
+
+	pr, pw := io.Pipe()
+	go func() {
+		for payload := range NewJitterBuffer(opts).Run(ctx, frames) {
+			if _, err := pw.Write(payload); err != nil {
+				return // the reader was closed
+			}
+		}
+
+		var err error
+		select {
+		case err = <-errs:
+		case <-ctx.Done():
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
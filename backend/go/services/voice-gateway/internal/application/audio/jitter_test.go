@@ -0,0 +1,154 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// popAll pops every frame the buffer is ready to play.
+func popAll(jb *JitterBuffer) string {
+	var got []byte
+	for {
+		payload, ok := jb.Pop()
+		if !ok {
+			return string(got)
+		}
+		got = append(got, payload...)
+	}
+}
+
+func TestJitterBuffer_ReordersFrames(t *testing.T) {
+	jb := NewJitterBuffer(JitterBufferOptions{Depth: 3, Stream: "test"})
+
+	jb.Push(Frame{Seq: 11, Payload: []byte("b")})
+	jb.Push(Frame{Seq: 10, Payload: []byte("a")})
+	if got := popAll(jb); got != "" {
+		t.Fatalf("played %q while prefilling, want nothing", got)
+	}
+
+	jb.Push(Frame{Seq: 13, Payload: []byte("d")})
+	jb.Push(Frame{Seq: 12, Payload: []byte("c")})
+	jb.Push(Frame{Seq: 12, Payload: []byte("c")}) // duplicate
+	if got := popAll(jb); got != "abcd" {
+		t.Errorf("played %q, want %q", got, "abcd")
+	}
+
+	// A frame whose slot was played is late and discarded
+	jb.Push(Frame{Seq: 11, Payload: []byte("x")})
+	jb.Push(Frame{Seq: 14, Payload: []byte("e")})
+	if got := popAll(jb); got != "e" {
+		t.Errorf("played %q, want %q", got, "e")
+	}
+}
+
+func TestJitterBuffer_WaitsDepthForMissingFrame(t *testing.T) {
+	jb := NewJitterBuffer(JitterBufferOptions{Depth: 2, Stream: "test"})
+
+	jb.Push(Frame{Seq: 0, Payload: []byte("a")})
+	jb.Push(Frame{Seq: 2, Payload: []byte("c")})
+	if got := popAll(jb); got != "a" {
+		t.Fatalf("played %q, want %q before the gap", got, "a")
+	}
+
+	// Frame 1 is still waited for with fewer than Depth frames behind it
+	jb.Push(Frame{Seq: 3, Payload: []byte("d")})
+	if got := popAll(jb); got != "cd" {
+		t.Errorf("played %q, want frame 1 skipped once Depth frames arrived", got)
+	}
+}
+
+func TestJitterBuffer_SequenceWraparound(t *testing.T) {
+	jb := NewJitterBuffer(JitterBufferOptions{Depth: 3, Stream: "test"})
+
+	jb.Push(Frame{Seq: 0, Payload: []byte("c")})
+	jb.Push(Frame{Seq: 65535, Payload: []byte("b")})
+	jb.Push(Frame{Seq: 65534, Payload: []byte("a")})
+	if got := popAll(jb); got != "abc" {
+		t.Errorf("played %q, want %q", got, "abc")
+	}
+}
+
+func TestJitterBuffer_Overflow(t *testing.T) {
+	jb := NewJitterBuffer(JitterBufferOptions{Depth: 1, Stream: "test"})
+
+	for seq := range uint16(6) {
+		jb.Push(Frame{Seq: seq, Payload: []byte{'a' + byte(seq)}})
+	}
+	if jb.Len() != 4 {
+		t.Fatalf("Len = %d, want 4", jb.Len())
+	}
+	if got := popAll(jb); got != "cdef" {
+		t.Errorf("played %q, want the oldest frames dropped", got)
+	}
+}
+
+func TestJitterBuffer_RunPacesOrderedOutput(t *testing.T) {
+	const frameDuration = 20 * time.Millisecond
+	jb := NewJitterBuffer(JitterBufferOptions{Depth: 3, FrameDuration: frameDuration, Stream: "test"})
+
+	in := make(chan Frame, 8)
+	for _, seq := range []uint16{2, 0, 1, 4, 3, 6, 5} {
+		in <- Frame{Seq: seq, Payload: []byte{'a' + byte(seq)}}
+	}
+	close(in)
+
+	var got []byte
+	var times []time.Time
+	for payload := range jb.Run(context.Background(), in) {
+		got = append(got, payload...)
+		times = append(times, time.Now())
+	}
+
+	if string(got) != "abcdefg" {
+		t.Fatalf("played %q, want %q", got, "abcdefg")
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < frameDuration/2 {
+			t.Errorf("frame %d played %s after the previous one, want about %s", i, gap, frameDuration)
+		}
+	}
+}
+
+func TestJitterBuffer_RunStopsOnContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := NewJitterBuffer(JitterBufferOptions{FrameDuration: time.Millisecond}).Run(ctx, make(chan Frame))
+	cancel()
+
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("played a frame from empty input")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run didn't stop when the context was cancelled")
+	}
+}
+
+func TestDejitter(t *testing.T) {
+	source := bytes.Repeat([]byte("0123456789"), 5)
+	r := Dejitter(context.Background(), bytes.NewReader(source), 8, JitterBufferOptions{Depth: 2, FrameDuration: time.Millisecond})
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if !bytes.Equal(got, source) {
+		t.Errorf("read %q, want %q", got, source)
+	}
+}
+
+func TestDejitter_SourceError(t *testing.T) {
+	errSource := errors.New("socket reset")
+	source := io.MultiReader(bytes.NewReader([]byte("abcd")), iotest.ErrReader(errSource))
+	r := Dejitter(context.Background(), source, 4, JitterBufferOptions{FrameDuration: time.Millisecond})
+
+	got, err := io.ReadAll(r)
+	if string(got) != "abcd" || !errors.Is(err, errSource) {
+		t.Errorf("read %q, %v; want the audio before the error, then the error", got, err)
+	}
+}
@@ -4,11 +4,16 @@ package audio
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/metrics"
 )
 
 // Processor handles audio stream processing.
@@ -29,38 +34,175 @@ func NewProcessor(sampleRate, channels, bufferSize int, logger *zap.Logger) *Pro
 	}
 }
 
-// AudioBuffer manages an audio buffer for streaming.
+// OverflowPolicy decides what a bounded AudioBuffer does with audio that
+// doesn't fit.
+type OverflowPolicy string
+
+const (
+	// OverflowDropOldest discards the oldest buffered audio to make room, so
+	// a slow consumer hears the caller late rather than never.
+	OverflowDropOldest OverflowPolicy = "drop_oldest"
+	// OverflowBlock makes the writer wait until the reader frees room,
+	// pushing the slowdown back to the producer.
+	OverflowBlock OverflowPolicy = "block"
+)
+
+// errBufferClosed is returned by writes to a closed AudioBuffer.
+var errBufferClosed = errors.New("buffer closed")
+
+// AudioBufferOptions bounds an AudioBuffer.
+type AudioBufferOptions struct {
+	// MaxSize caps the buffered bytes. Zero means unbounded.
+	MaxSize int
+	// Policy applies when a write doesn't fit. Empty means OverflowDropOldest.
+	Policy OverflowPolicy
+	// Stream labels the buffer metrics (e.g. "stt", "tts").
+	Stream string
+}
+
+// AudioBuffer manages an audio buffer for streaming. A bounded buffer keeps
+// its audio in a ring of MaxSize bytes.
 type AudioBuffer struct {
-	buffer *bytes.Buffer
+	buffer *bytes.Buffer // unbounded mode
+	opts   AudioBufferOptions
+
+	// Bounded mode: ring[start:start+size], wrapping around
+	ring  []byte
+	start int
+	size  int
+
 	mu     sync.RWMutex
+	space  *sync.Cond // signalled when a read frees room or the buffer closes
 	closed bool
 }
 
-// NewAudioBuffer creates a new audio buffer.
+// NewAudioBuffer creates a new unbounded audio buffer.
 func NewAudioBuffer() *AudioBuffer {
-	return &AudioBuffer{
-		buffer: new(bytes.Buffer),
+	return NewBoundedAudioBuffer(AudioBufferOptions{})
+}
+
+// NewBoundedAudioBuffer creates an audio buffer that holds at most
+// opts.MaxSize bytes.
+func NewBoundedAudioBuffer(opts AudioBufferOptions) *AudioBuffer {
+	if opts.Policy == "" {
+		opts.Policy = OverflowDropOldest
 	}
+
+	ab := &AudioBuffer{opts: opts}
+	if opts.MaxSize > 0 {
+		ab.ring = make([]byte, opts.MaxSize)
+	} else {
+		ab.buffer = new(bytes.Buffer)
+	}
+	ab.space = sync.NewCond(&ab.mu)
+	return ab
 }
 
-// Write writes audio data to the buffer.
+// Write writes audio data to the buffer. When a bounded buffer is full it
+// drops the oldest audio or waits for room, depending on the policy; a
+// blocked write returns an error if the buffer is closed meanwhile.
 func (ab *AudioBuffer) Write(p []byte) (n int, err error) {
 	ab.mu.Lock()
 	defer ab.mu.Unlock()
 
 	if ab.closed {
-		return 0, fmt.Errorf("buffer closed")
+		return 0, errBufferClosed
+	}
+	if ab.ring == nil {
+		return ab.buffer.Write(p)
 	}
 
-	return ab.buffer.Write(p)
+	if ab.opts.Policy == OverflowBlock {
+		n, err = ab.writeBlocking(p)
+	} else {
+		n = ab.writeDroppingOldest(p)
+	}
+	metrics.ObserveAudioBuffer(ab.opts.Stream, ab.size, len(ab.ring))
+	return n, err
 }
 
-// Read reads audio data from the buffer.
+// writeDroppingOldest writes p, discarding the oldest audio that doesn't fit.
+func (ab *AudioBuffer) writeDroppingOldest(p []byte) int {
+	n := len(p)
+	dropped := 0
+
+	// Only the newest MaxSize bytes of a write larger than the buffer survive
+	if len(p) > len(ab.ring) {
+		dropped += len(p) - len(ab.ring)
+		p = p[len(p)-len(ab.ring):]
+	}
+	if over := ab.size + len(p) - len(ab.ring); over > 0 {
+		ab.discard(over)
+		dropped += over
+	}
+	ab.put(p)
+
+	if dropped > 0 {
+		metrics.AddAudioBufferDropped(ab.opts.Stream, dropped)
+	}
+	return n
+}
+
+// writeBlocking writes p as room frees up. It must be called with mu held.
+func (ab *AudioBuffer) writeBlocking(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		free := len(ab.ring) - ab.size
+		if free == 0 {
+			metrics.IncAudioBufferBlocked(ab.opts.Stream)
+			for free == 0 && !ab.closed {
+				ab.space.Wait()
+				free = len(ab.ring) - ab.size
+			}
+			if ab.closed {
+				return n, errBufferClosed
+			}
+		}
+
+		chunk := p[:min(free, len(p))]
+		ab.put(chunk)
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// put appends p, which must fit, to the ring.
+func (ab *AudioBuffer) put(p []byte) {
+	end := (ab.start + ab.size) % len(ab.ring)
+	copied := copy(ab.ring[end:], p)
+	copy(ab.ring, p[copied:])
+	ab.size += len(p)
+}
+
+// discard drops the oldest n buffered bytes of the ring.
+func (ab *AudioBuffer) discard(n int) {
+	ab.start = (ab.start + n) % len(ab.ring)
+	ab.size -= n
+}
+
+// Read reads audio data from the buffer. It returns io.EOF when the buffer
+// is empty.
 func (ab *AudioBuffer) Read(p []byte) (n int, err error) {
-	ab.mu.RLock()
-	defer ab.mu.RUnlock()
+	ab.mu.Lock()
+	defer ab.mu.Unlock()
+
+	if ab.ring == nil {
+		return ab.buffer.Read(p)
+	}
+	if ab.size == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
 
-	return ab.buffer.Read(p)
+	n = min(len(p), ab.size)
+	copied := copy(p[:n], ab.ring[ab.start:])
+	copy(p[copied:n], ab.ring)
+	ab.discard(n)
+	ab.space.Broadcast()
+	return n, nil
 }
 
 // Len returns the current buffer length.
@@ -68,15 +210,31 @@ func (ab *AudioBuffer) Len() int {
 	ab.mu.RLock()
 	defer ab.mu.RUnlock()
 
-	return ab.buffer.Len()
+	if ab.ring == nil {
+		return ab.buffer.Len()
+	}
+	return ab.size
+}
+
+// Utilization returns the fill level of a bounded buffer as a fraction of
+// its capacity, or 0 for an unbounded one.
+func (ab *AudioBuffer) Utilization() float64 {
+	ab.mu.RLock()
+	defer ab.mu.RUnlock()
+
+	if ab.ring == nil {
+		return 0
+	}
+	return float64(ab.size) / float64(len(ab.ring))
 }
 
-// Close closes the buffer.
+// Close closes the buffer and releases writers waiting for room.
 func (ab *AudioBuffer) Close() error {
 	ab.mu.Lock()
 	defer ab.mu.Unlock()
 
 	ab.closed = true
+	ab.space.Broadcast()
 	return nil
 }
 
@@ -85,7 +243,12 @@ func (ab *AudioBuffer) Reset() {
 	ab.mu.Lock()
 	defer ab.mu.Unlock()
 
-	ab.buffer.Reset()
+	if ab.ring == nil {
+		ab.buffer.Reset()
+		return
+	}
+	ab.start, ab.size = 0, 0
+	ab.space.Broadcast()
 }
 
 // StreamConverter converts audio streams between formats.
@@ -129,10 +292,15 @@ func (sc *StreamConverter) Convert(ctx context.Context, input io.Reader) (io.Rea
 	return input, nil
 }
 
+// ErrReadTimeout is reported when the audio source produces nothing within
+// the read timeout of a ChunkReader.
+var ErrReadTimeout = errors.New("audio source read timed out")
+
 // ChunkReader reads audio data in fixed-size chunks.
 type ChunkReader struct {
-	source    io.Reader
-	chunkSize int
+	source      io.Reader
+	chunkSize   int
+	readTimeout time.Duration
 }
 
 // NewChunkReader creates a new chunk reader.
@@ -143,47 +311,104 @@ func NewChunkReader(source io.Reader, chunkSize int) *ChunkReader {
 	}
 }
 
-// ReadChunk reads one chunk of audio data.
+// SetReadTimeout bounds how long ReadChunks waits for each chunk, so a
+// stalled source doesn't hang the reader forever. Zero means no timeout.
+func (cr *ChunkReader) SetReadTimeout(timeout time.Duration) {
+	cr.readTimeout = timeout
+}
+
+// ReadChunk reads one chunk of audio data. The last chunk of a stream may be
+// shorter than the chunk size; after it ReadChunk returns io.EOF. A read
+// error returns the audio read before it along with the error.
 func (cr *ChunkReader) ReadChunk() ([]byte, error) {
 	chunk := make([]byte, cr.chunkSize)
 	n, err := io.ReadFull(cr.source, chunk)
-	if err != nil && err != io.ErrUnexpectedEOF {
-		return nil, err
+	if err == io.ErrUnexpectedEOF {
+		err = nil
 	}
 
-	return chunk[:n], nil
+	return chunk[:n], err
 }
 
-// ReadChunks returns a channel that yields audio chunks.
-func (cr *ChunkReader) ReadChunks(ctx context.Context) <-chan []byte {
+// ReadChunks returns a channel that yields audio chunks, including a short
+// final chunk, and a channel that reports the error that stopped reading,
+// if any. Both are closed when reading stops: at the end of the stream, on
+// a read error or timeout, or when ctx is done.
+func (cr *ChunkReader) ReadChunks(ctx context.Context) (<-chan []byte, <-chan error) {
 	chunks := make(chan []byte, 10)
+	errs := make(chan error, 1)
 
 	go func() {
+		defer close(errs)
 		defer close(chunks)
 
 		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				chunk, err := cr.ReadChunk()
-				if err != nil {
-					if err != io.EOF {
-						// Log error but don't send
-					}
-					return
-				}
-
+			chunk, err := cr.readChunk(ctx)
+			if len(chunk) > 0 {
 				select {
 				case chunks <- chunk:
 				case <-ctx.Done():
 					return
 				}
 			}
+
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					errs <- err
+				}
+				return
+			}
+		}
+	}()
+
+	return chunks, errs
+}
+
+// readDeadliner is a source that can time out its own reads, like a
+// net.Conn.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// readChunk reads one chunk within the read timeout. A source that can't
+// time out its reads is read in the background; when it stalls, the
+// abandoned read returns whenever the source does.
+func (cr *ChunkReader) readChunk(ctx context.Context) ([]byte, error) {
+	if cr.readTimeout <= 0 {
+		return cr.ReadChunk()
+	}
+
+	if d, ok := cr.source.(readDeadliner); ok {
+		if err := d.SetReadDeadline(time.Now().Add(cr.readTimeout)); err == nil {
+			chunk, err := cr.ReadChunk()
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				err = fmt.Errorf("%w after %s: %w", ErrReadTimeout, cr.readTimeout, err)
+			}
+			return chunk, err
 		}
+	}
+
+	type result struct {
+		chunk []byte
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		chunk, err := cr.ReadChunk()
+		done <- result{chunk, err}
 	}()
 
-	return chunks
+	timer := time.NewTimer(cr.readTimeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-done:
+		return r.chunk, r.err
+	case <-timer.C:
+		return nil, fmt.Errorf("%w after %s", ErrReadTimeout, cr.readTimeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // PCMConverter provides PCM audio conversion utilities.
@@ -0,0 +1,212 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func readAll(t *testing.T, ab *AudioBuffer) []byte {
+	t.Helper()
+
+	got, err := io.ReadAll(ab)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	return got
+}
+
+func TestAudioBuffer_Unbounded(t *testing.T) {
+	ab := NewAudioBuffer()
+
+	for _, p := range [][]byte{[]byte("hello "), []byte("world")} {
+		if _, err := ab.Write(p); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if ab.Len() != 11 || ab.Utilization() != 0 {
+		t.Errorf("Len = %d, Utilization = %g; want 11 and 0", ab.Len(), ab.Utilization())
+	}
+	if got := readAll(t, ab); string(got) != "hello world" {
+		t.Errorf("Read %q, want %q", got, "hello world")
+	}
+}
+
+func TestAudioBuffer_DropOldest(t *testing.T) {
+	ab := NewBoundedAudioBuffer(AudioBufferOptions{MaxSize: 8, Policy: OverflowDropOldest, Stream: "stt"})
+
+	if _, err := ab.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if ab.Utilization() != 0.75 {
+		t.Errorf("Utilization = %g, want 0.75", ab.Utilization())
+	}
+
+	// Reading some audio moves the ring start so the next write wraps around
+	p := make([]byte, 2)
+	if n, _ := ab.Read(p); n != 2 || string(p) != "ab" {
+		t.Fatalf("Read %q, want %q", p[:n], "ab")
+	}
+
+	n, err := ab.Write([]byte("ghijkl"))
+	if err != nil || n != 6 {
+		t.Fatalf("Write = %d, %v; want the whole write accepted", n, err)
+	}
+	if ab.Len() != 8 {
+		t.Errorf("Len = %d, want the buffer full at 8", ab.Len())
+	}
+	if got := readAll(t, ab); string(got) != "efghijkl" {
+		t.Errorf("Read %q, want the oldest audio dropped: %q", got, "efghijkl")
+	}
+
+	// A write larger than the buffer keeps only its newest audio
+	if _, err := ab.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if got := readAll(t, ab); string(got) != "23456789" {
+		t.Errorf("Read %q, want %q", got, "23456789")
+	}
+}
+
+func TestAudioBuffer_Block(t *testing.T) {
+	ab := NewBoundedAudioBuffer(AudioBufferOptions{MaxSize: 4, Policy: OverflowBlock, Stream: "tts"})
+
+	if _, err := ab.Write([]byte("abcd")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := ab.Write([]byte("efgh"))
+		written <- err
+	}()
+
+	select {
+	case err := <-written:
+		t.Fatalf("Write into a full buffer should block, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The reader drains the buffer a chunk at a time, unblocking the writer
+	var got bytes.Buffer
+	p := make([]byte, 2)
+	deadline := time.After(time.Second)
+	for got.Len() < 8 {
+		n, err := ab.Read(p)
+		if err != nil && !errors.Is(err, io.EOF) {
+			t.Fatalf("Read failed: %v", err)
+		}
+		got.Write(p[:n])
+
+		select {
+		case <-deadline:
+			t.Fatalf("Timed out draining the buffer, read %q", got.String())
+		default:
+		}
+		if n == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	if err := <-written; err != nil {
+		t.Fatalf("Blocked write failed: %v", err)
+	}
+	if got.String() != "abcdefgh" {
+		t.Errorf("Read %q, want no audio lost: %q", got.String(), "abcdefgh")
+	}
+}
+
+func TestAudioBuffer_CloseReleasesBlockedWriter(t *testing.T) {
+	ab := NewBoundedAudioBuffer(AudioBufferOptions{MaxSize: 2, Policy: OverflowBlock})
+	if _, err := ab.Write([]byte("ab")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := ab.Write([]byte("c"))
+		written <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	ab.Close()
+
+	select {
+	case err := <-written:
+		if !errors.Is(err, errBufferClosed) {
+			t.Errorf("Expected the blocked write to fail with the buffer closed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not release the blocked writer")
+	}
+}
+
+// collect drains the channels of ReadChunks.
+func collect(t *testing.T, chunks <-chan []byte, errs <-chan error) ([]string, error) {
+	t.Helper()
+
+	var got []string
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				return got, <-errs
+			}
+			got = append(got, string(chunk))
+		case <-timeout:
+			t.Fatalf("ReadChunks did not stop, read %v", got)
+		}
+	}
+}
+
+func TestChunkReader_TruncatedStream(t *testing.T) {
+	// One byte at a time, so the short final chunk comes from several reads
+	cr := NewChunkReader(iotest.OneByteReader(strings.NewReader("abcdefghij")), 4)
+
+	chunks, errs := cr.ReadChunks(context.Background())
+	got, err := collect(t, chunks, errs)
+	if err != nil {
+		t.Fatalf("Expected the stream to end cleanly, got %v", err)
+	}
+	if strings.Join(got, ",") != "abcd,efgh,ij" {
+		t.Errorf("Chunks = %v, want the short final chunk kept", got)
+	}
+}
+
+func TestChunkReader_ReadError(t *testing.T) {
+	errSource := errors.New("rtp: socket closed")
+	source := io.MultiReader(strings.NewReader("abcdef"), iotest.ErrReader(errSource))
+	cr := NewChunkReader(source, 4)
+
+	chunks, errs := cr.ReadChunks(context.Background())
+	got, err := collect(t, chunks, errs)
+	if !errors.Is(err, errSource) {
+		t.Fatalf("Expected the read error to be reported, got %v", err)
+	}
+	if strings.Join(got, ",") != "abcd,ef" {
+		t.Errorf("Chunks = %v, want the audio read before the error", got)
+	}
+}
+
+func TestChunkReader_StalledSource(t *testing.T) {
+	source, writer := io.Pipe()
+	defer writer.Close()
+	go writer.Write([]byte("abcd")) // then nothing more
+
+	cr := NewChunkReader(source, 4)
+	cr.SetReadTimeout(50 * time.Millisecond)
+
+	chunks, errs := cr.ReadChunks(context.Background())
+	got, err := collect(t, chunks, errs)
+	if !errors.Is(err, ErrReadTimeout) {
+		t.Fatalf("Expected ErrReadTimeout, got %v", err)
+	}
+	if strings.Join(got, ",") != "abcd" {
+		t.Errorf("Chunks = %v, want the audio read before the stall", got)
+	}
+}
@@ -0,0 +1,95 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+)
+
+// CallerIDSource resolves the numbers a tenant may present as caller ID on
+// the calls it dials.
+type CallerIDSource interface {
+	LookupDID(ctx context.Context, phoneNumber string) (*tenant.DIDInfo, error)
+	GetCallerIDNumber(ctx context.Context, tenantID uuid.UUID) (string, error)
+}
+
+var _ CallerIDSource = (*tenant.Client)(nil)
+
+// SetCallerIDs lets outbound legs present a caller ID the tenant picks: a
+// DID requested with the transfer or the tenant's default caller ID, as long
+// as the tenant owns it. Without it, legs present the number the call came
+// in on and requested caller IDs are rejected.
+func (s *Service) SetCallerIDs(source CallerIDSource) {
+	s.callerIDs = source
+}
+
+// selectCallerID returns the caller ID of an outbound leg of c. A requested
+// fromDID must be a DID the tenant owns and has enabled; otherwise the
+// tenant's default caller ID is used when it owns it, and the number the
+// call came in on when it doesn't.
+func (s *Service) selectCallerID(ctx context.Context, c *call.Call, fromDID string) (string, error) {
+	fallback := c.CalleeNumber
+	if c.Direction == call.DirectionOutbound {
+		fallback = c.CallerNumber
+	}
+
+	if fromDID != "" {
+		did, err := s.normalizeNumber("caller ID", fromDID)
+		if err != nil {
+			return "", err
+		}
+		if s.callerIDs == nil {
+			return "", fmt.Errorf("%w: %s can't be verified", call.ErrCallerIDNotOwned, did)
+		}
+		if err := s.checkCallerID(ctx, c.TenantID, did); err != nil {
+			return "", err
+		}
+		return did, nil
+	}
+
+	if s.callerIDs == nil {
+		return fallback, nil
+	}
+	number, err := s.callerIDs.GetCallerIDNumber(ctx, c.TenantID)
+	if err != nil {
+		s.logger.Warn("failed to get default caller ID, using the call's number", zap.String("tenant_id", c.TenantID.String()), zap.Error(err))
+		return fallback, nil
+	}
+	if number == "" {
+		return fallback, nil
+	}
+	did, err := s.normalizeNumber("caller ID", number)
+	if err == nil {
+		err = s.checkCallerID(ctx, c.TenantID, did)
+	}
+	if err != nil {
+		s.logger.Warn("default caller ID can't be used, using the call's number",
+			zap.String("tenant_id", c.TenantID.String()),
+			zap.String("caller_id", number),
+			zap.Error(err),
+		)
+		return fallback, nil
+	}
+	return did, nil
+}
+
+// checkCallerID verifies that the tenant owns did and has it enabled.
+func (s *Service) checkCallerID(ctx context.Context, tenantID uuid.UUID, did string) error {
+	info, err := s.callerIDs.LookupDID(ctx, did)
+	if errors.Is(err, tenant.ErrDIDNotFound) {
+		return fmt.Errorf("%w: %s", call.ErrCallerIDNotOwned, did)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up caller ID: %w", err)
+	}
+	if info.TenantID != tenantID || !info.Enabled {
+		return fmt.Errorf("%w: %s", call.ErrCallerIDNotOwned, did)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package call
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/redis"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/cdr"
+)
+
+// CDRStore persists call detail records.
+type CDRStore interface {
+	Save(ctx context.Context, record *cdr.Record) error
+}
+
+// CDRPublisher publishes cdr.created events.
+type CDRPublisher interface {
+	PublishCDRCreated(ctx context.Context, record *cdr.Record) error
+}
+
+// CDRRoundingResolver resolves a tenant's billing rounding rule; empty when
+// the tenant has none.
+type CDRRoundingResolver interface {
+	GetCDRRounding(ctx context.Context, tenantID uuid.UUID) (string, error)
+}
+
+var (
+	_ CDRStore            = (*redis.CDRRepository)(nil)
+	_ CDRPublisher        = (*events.Publisher)(nil)
+	_ CDRRoundingResolver = (*tenant.Client)(nil)
+)
+
+// SetCDRs generates a call detail record when a call ends, persisting it
+// and publishing cdr.created. Talk time is billed by the tenant's rounding
+// rule, or by fallback for tenants without a valid one.
+func (s *Service) SetCDRs(store CDRStore, publisher CDRPublisher, rules CDRRoundingResolver, fallback cdr.Rounding) {
+	s.cdrStore = store
+	s.cdrPublisher = publisher
+	s.cdrRules = rules
+	s.cdrRounding = fallback
+}
+
+// recordCDR records the detail record of an ended call.
+func (s *Service) recordCDR(ctx context.Context, c *call.Call) {
+	if s.cdrStore == nil {
+		return
+	}
+
+	record, err := cdr.New(c, s.roundingOf(ctx, c.TenantID))
+	if err != nil {
+		s.logger.Error("failed to build cdr", zap.String("call_id", c.ID.String()), zap.Error(err))
+		return
+	}
+
+	if err := s.cdrStore.Save(ctx, record); err != nil {
+		s.logger.Error("failed to store cdr",
+			zap.String("call_id", c.ID.String()),
+			zap.String("cdr_id", record.ID.String()),
+			zap.Error(err),
+		)
+	}
+	if err := s.cdrPublisher.PublishCDRCreated(ctx, record); err != nil {
+		s.logger.Error("failed to publish cdr created event",
+			zap.String("call_id", c.ID.String()),
+			zap.String("cdr_id", record.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// roundingOf returns the billing rounding rule of a tenant.
+func (s *Service) roundingOf(ctx context.Context, tenantID uuid.UUID) cdr.Rounding {
+	if s.cdrRules == nil {
+		return s.cdrRounding
+	}
+
+	rule, err := s.cdrRules.GetCDRRounding(ctx, tenantID)
+	if err != nil {
+		s.logger.Warn("failed to resolve tenant cdr rounding, using default",
+			zap.String("tenant_id", tenantID.String()),
+			zap.Error(err),
+		)
+		return s.cdrRounding
+	}
+	if rule == "" {
+		return s.cdrRounding
+	}
+
+	rounding, err := cdr.ParseRounding(rule)
+	if err != nil {
+		s.logger.Warn("invalid tenant cdr rounding, using default",
+			zap.String("tenant_id", tenantID.String()),
+			zap.Error(err),
+		)
+		return s.cdrRounding
+	}
+	return rounding
+}
@@ -0,0 +1,99 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"voice-gateway/internal/domain/cdr"
+)
+
+// fakeCDRs stores and publishes records in memory, with per-tenant rounding
+// rules.
+type fakeCDRs struct {
+	rules     map[uuid.UUID]string
+	rulesErr  error
+	stored    []*cdr.Record
+	published []*cdr.Record
+}
+
+func (f *fakeCDRs) Save(ctx context.Context, record *cdr.Record) error {
+	f.stored = append(f.stored, record)
+	return nil
+}
+
+func (f *fakeCDRs) PublishCDRCreated(ctx context.Context, record *cdr.Record) error {
+	f.published = append(f.published, record)
+	return nil
+}
+
+func (f *fakeCDRs) GetCDRRounding(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	return f.rules[tenantID], f.rulesErr
+}
+
+func TestEndCall_RecordsCDR(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		rulesErr error
+		want     int64
+		rounding string
+	}{
+		{"tenant per minute", "60/60", nil, 120, "60/60"},
+		{"tenant per 6 seconds", "6/6", nil, 66, "6/6"},
+		{"no tenant rule", "", nil, 90, "30/30"},
+		{"invalid tenant rule", "minute", nil, 90, "30/30"},
+		{"tenant-manager down", "60/60", errors.New("connection refused"), 90, "30/30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceFixture(10)
+			c := f.answeredCall(t)
+			answeredAt := time.Now().UTC().Add(-61 * time.Second)
+			c.AnsweredAt = &answeredAt
+			f.store.calls[c.ID] = *c
+
+			cdrs := &fakeCDRs{rules: map[uuid.UUID]string{c.TenantID: tt.rule}, rulesErr: tt.rulesErr}
+			f.service.SetCDRs(cdrs, cdrs, cdrs, cdr.Rounding{Minimum: 30, Increment: 30})
+
+			if err := f.service.EndCall(context.Background(), c.ID); err != nil {
+				t.Fatalf("EndCall failed: %v", err)
+			}
+
+			if len(cdrs.stored) != 1 || len(cdrs.published) != 1 || cdrs.stored[0] != cdrs.published[0] {
+				t.Fatalf("Expected one record stored and published, got %d and %d", len(cdrs.stored), len(cdrs.published))
+			}
+			record := cdrs.stored[0]
+			if record.CallID != c.ID || record.TenantID != c.TenantID || record.Disposition != cdr.DispositionAnswered {
+				t.Errorf("Unexpected record: %+v", record)
+			}
+			if record.BillableSeconds != tt.want || record.Rounding != tt.rounding {
+				t.Errorf("Expected just over 61s of talk billed as %ds by %s, got %ds by %s", tt.want, tt.rounding, record.BillableSeconds, record.Rounding)
+			}
+		})
+	}
+}
+
+func TestEndCall_RecordsCDROnce(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.ringingCall(t)
+	cdrs := &fakeCDRs{}
+	f.service.SetCDRs(cdrs, cdrs, cdrs, cdr.PerSecond)
+
+	for range 2 {
+		if err := f.service.EndCall(context.Background(), c.ID); err != nil {
+			t.Fatalf("EndCall failed: %v", err)
+		}
+	}
+
+	if len(cdrs.published) != 1 {
+		t.Fatalf("Expected one record for a call ended twice, got %d", len(cdrs.published))
+	}
+	if record := cdrs.published[0]; record.Disposition != cdr.DispositionNoAnswer || record.BillableSeconds != 0 {
+		t.Errorf("Expected an unanswered call billed nothing, got %s billing %ds", record.Disposition, record.BillableSeconds)
+	}
+}
@@ -0,0 +1,86 @@
+package call
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/application/audio"
+	"voice-gateway/internal/domain/call"
+)
+
+// defaultSTTSampleRate is the PCM sample rate caller audio is decoded to when
+// the STT config doesn't ask for one.
+const defaultSTTSampleRate = 16000
+
+// CodecDetector reports the native audio format of a channel.
+type CodecDetector interface {
+	ChannelCodec(ctx context.Context, channelID string) (string, error)
+}
+
+var _ CodecDetector = (*asterisk.ARIClient)(nil)
+
+// SetCodecDetection detects the codec of each incoming call, rejects calls
+// whose codec can't be decoded and decodes caller audio from it into the PCM
+// the STT provider requires. Calls whose codec can't be detected are assumed
+// to use fallback.
+func (s *Service) SetCodecDetection(detector CodecDetector, fallback audio.Codec) {
+	s.codecDetector = detector
+	s.fallbackCodec = fallback
+}
+
+// negotiateCodec records the codec of a new call in its metadata.
+func (s *Service) negotiateCodec(ctx context.Context, c *call.Call) error {
+	if s.codecDetector == nil {
+		return nil
+	}
+
+	codec := s.fallbackCodec
+	format, err := s.codecDetector.ChannelCodec(ctx, c.ChannelID)
+	if err != nil {
+		s.logger.Warn("failed to detect channel codec, assuming fallback",
+			zap.String("channel_id", c.ChannelID),
+			zap.String("codec", string(codec)),
+			zap.Error(err),
+		)
+	} else if codec, err = audio.ParseCodec(format); err != nil {
+		return fmt.Errorf("%w: %v", call.ErrUnsupportedCodec, err)
+	}
+
+	c.Metadata["codec"] = string(codec)
+	return nil
+}
+
+// decodeForSTT decodes caller audio from the call's codec into PCM at the
+// sample rate of config, which is updated to describe the decoded audio.
+// Audio of calls without a negotiated codec is passed through as is.
+func (s *Service) decodeForSTT(ctx context.Context, c *call.Call, in io.Reader, config stt.StreamConfig) (io.Reader, stt.StreamConfig, error) {
+	codec, _ := c.Metadata["codec"].(string)
+	if codec == "" {
+		return in, config, nil
+	}
+
+	if config.SampleRate == 0 {
+		config.SampleRate = defaultSTTSampleRate
+	}
+	pipeline, err := audio.NewDecodePipeline(audio.Codec(codec), audio.AudioFormat{
+		SampleRate: config.SampleRate,
+		Channels:   1,
+		BitDepth:   16,
+		Encoding:   "pcm",
+	}, s.logger)
+	if err != nil {
+		return nil, config, fmt.Errorf("%w: %v", call.ErrUnsupportedCodec, err)
+	}
+
+	decoded, err := pipeline.Reader(ctx, in)
+	if err != nil {
+		return nil, config, err
+	}
+	config.Encoding = "pcm"
+	return decoded, config, nil
+}
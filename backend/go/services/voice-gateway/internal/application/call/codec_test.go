@@ -0,0 +1,121 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama/mocks"
+	"github.com/google/uuid"
+
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tts"
+	"voice-gateway/internal/application/audio"
+	"voice-gateway/internal/domain/call"
+)
+
+// fakeCodecDetector reports a fixed channel format.
+type fakeCodecDetector struct {
+	format string
+	err    error
+}
+
+func (d *fakeCodecDetector) ChannelCodec(ctx context.Context, channelID string) (string, error) {
+	return d.format, d.err
+}
+
+// capturingSTT records the audio and config it was asked to transcribe.
+type capturingSTT struct {
+	config stt.StreamConfig
+	audio  []byte
+}
+
+func (p *capturingSTT) StreamTranscribe(ctx context.Context, audioStream io.Reader, config stt.StreamConfig) (<-chan stt.Result, error) {
+	p.config = config
+	p.audio, _ = io.ReadAll(audioStream)
+
+	results := make(chan stt.Result)
+	close(results)
+	return results, nil
+}
+
+func (p *capturingSTT) Close() error { return nil }
+func (p *capturingSTT) Name() string { return "capturing-stt" }
+
+func TestHandleIncomingCall_NegotiatesCodec(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector *fakeCodecDetector
+		want     audio.Codec
+	}{
+		{"ulaw", &fakeCodecDetector{format: "(ulaw)"}, audio.CodecULaw},
+		{"opus", &fakeCodecDetector{format: "(opus)"}, audio.CodecOpus},
+		{"detection fails", &fakeCodecDetector{err: errARIUnavailable}, audio.CodecALaw},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceFixture(10)
+			f.service.SetCodecDetection(tt.detector, audio.CodecALaw)
+
+			c, err := f.service.HandleIncomingCall(context.Background(), "channel-1", "+5511999887766", "+5511988776655", uuid.New())
+			if err != nil {
+				t.Fatalf("HandleIncomingCall failed: %v", err)
+			}
+
+			if got := f.store.stored(t, c.ID).Metadata["codec"]; got != string(tt.want) {
+				t.Errorf("Expected codec %s in the call metadata, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHandleIncomingCall_RejectsUnsupportedCodec(t *testing.T) {
+	f := newServiceFixture(10)
+	f.service.SetCodecDetection(&fakeCodecDetector{format: "(g729)"}, audio.CodecULaw)
+
+	_, err := f.service.HandleIncomingCall(context.Background(), "channel-1", "+5511999887766", "+5511988776655", uuid.New())
+	if !errors.Is(err, call.ErrUnsupportedCodec) {
+		t.Fatalf("Expected ErrUnsupportedCodec, got %v", err)
+	}
+	if len(f.store.calls) != 0 {
+		t.Error("No call should be saved for an unsupported codec")
+	}
+	f.publisher.expect(t)
+}
+
+func TestProcessTurn_DecodesCallCodec(t *testing.T) {
+	tests := []struct {
+		codec audio.Codec
+		audio string
+		want  int // bytes of PCM reaching STT
+	}{
+		{audio.CodecULaw, "\xff\xff\xff\xff", 8}, // 8-bit samples expand to 16-bit PCM
+		{audio.CodecOpus, "opus-frame", 10},      // decoded by the stream converter
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.codec), func(t *testing.T) {
+			producer := mocks.NewSyncProducer(t, nil)
+			defer producer.Close()
+
+			sttProvider := &capturingSTT{}
+			s := newTurnService(t, producer, sttProvider, &fakeTTS{}, newAgentServer(t, 0))
+			c := newTurnCall()
+			c.Metadata["codec"] = string(tt.codec)
+
+			if _, err := s.ProcessTurn(context.Background(), c, strings.NewReader(tt.audio), stt.StreamConfig{Encoding: string(tt.codec)}, tts.SynthesizeConfig{}); err != nil {
+				t.Fatalf("ProcessTurn failed: %v", err)
+			}
+
+			if sttProvider.config.Encoding != "pcm" || sttProvider.config.SampleRate != 16000 {
+				t.Errorf("Expected 16 kHz PCM to reach STT, got %s at %d Hz", sttProvider.config.Encoding, sttProvider.config.SampleRate)
+			}
+			if len(sttProvider.audio) != tt.want {
+				t.Errorf("STT received %d bytes, want %d", len(sttProvider.audio), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package call
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+)
+
+// BusinessContextResolver resolves what a tenant tells its agents about
+// itself.
+type BusinessContextResolver interface {
+	GetBusinessContext(ctx context.Context, tenantID uuid.UUID) (*tenant.BusinessContext, error)
+}
+
+var _ BusinessContextResolver = (*tenant.Client)(nil)
+
+// SetBusinessContext adds the tenant's name and business description to the
+// conversation context, with the call time in the tenant's timezone.
+func (s *Service) SetBusinessContext(resolver BusinessContextResolver) {
+	s.businessContexts = resolver
+}
+
+// buildContext sets the context the agent gets about the call. The call's own
+// details are always there; the tenant's are left out when they can't be
+// resolved, since the conversation can go on without them.
+func (s *Service) buildContext(ctx context.Context, c *call.Call) {
+	c.Context = call.NewConversationContext(c, time.UTC)
+	if s.businessContexts == nil {
+		return
+	}
+
+	business, err := s.businessContexts.GetBusinessContext(ctx, c.TenantID)
+	if err != nil {
+		s.logger.Warn("failed to resolve business context",
+			zap.String("call_id", c.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	c.Context.TenantName = business.Name
+	c.Context.BusinessContext = business.Description
+	if business.Timezone != "" {
+		loc, err := time.LoadLocation(business.Timezone)
+		if err != nil {
+			s.logger.Warn("invalid tenant timezone, using UTC",
+				zap.String("call_id", c.ID.String()),
+				zap.String("timezone", business.Timezone),
+			)
+			return
+		}
+		c.Context.LocalTime = c.CreatedAt.In(loc)
+	}
+}
+
+// turnContext returns the conversation context sent with each turn, so the
+// orchestrator has it whichever turn reaches it first.
+func turnContext(c *call.Call) map[string]interface{} {
+	if c.Context == nil {
+		return nil
+	}
+
+	vars := c.Context.Variables()
+	turnCtx := make(map[string]interface{}, len(vars))
+	for name, value := range vars {
+		turnCtx[name] = value
+	}
+	return turnCtx
+}
@@ -0,0 +1,104 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/agent"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/adapter/tts"
+)
+
+type fakeBusinessContexts struct {
+	business *tenant.BusinessContext
+	err      error
+}
+
+func (f *fakeBusinessContexts) GetBusinessContext(ctx context.Context, tenantID uuid.UUID) (*tenant.BusinessContext, error) {
+	return f.business, f.err
+}
+
+// contextAgentServer answers agent turns, recording the context each carried.
+func contextAgentServer(t *testing.T, received *[]map[string]string) *agent.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Context map[string]string `json:"context"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode turn: %v", err)
+		}
+		*received = append(*received, req.Context)
+		w.Write([]byte(`{"agent_response":"Olá!"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return agent.NewClient(server.URL, zap.NewNop())
+}
+
+func TestStartConversation_ContextReachesAgent(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture(10)
+	f.service.sttProviders = map[string]stt.Provider{"slow": &slowSTT{}}
+	f.service.ttsProviders = map[string]tts.Provider{"fake": &fakeTTS{}}
+	var received []map[string]string
+	f.service.SetConversation(contextAgentServer(t, &received), ProviderTimeouts{}, testFallback)
+	f.service.SetBusinessContext(&fakeBusinessContexts{business: &tenant.BusinessContext{
+		Name:        "Clínica Sorriso",
+		Description: "Clínica odontológica em São Paulo.",
+		Timezone:    "America/Sao_Paulo",
+	}})
+
+	c := f.answeredCall(t)
+	if err := f.service.StartConversation(ctx, c.ID, "agent-1"); err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+	stored := f.store.stored(t, c.ID)
+	if stored.Context == nil || stored.Context.TenantName != "Clínica Sorriso" || stored.Context.LocalTime.Location().String() != "America/Sao_Paulo" {
+		t.Fatalf("Expected the tenant's context on the call, got %+v", stored.Context)
+	}
+
+	stored.STTProvider, stored.TTSProvider = "slow", "fake"
+	if _, err := f.service.ProcessTurn(ctx, &stored, strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{}); err != nil {
+		t.Fatalf("ProcessTurn failed: %v", err)
+	}
+
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 agent turn, got %d", len(received))
+	}
+	turnCtx := received[0]
+	if turnCtx["caller_number"] != c.CallerNumber || turnCtx["did"] != c.CalleeNumber {
+		t.Errorf("Expected caller %s on DID %s, got %v", c.CallerNumber, c.CalleeNumber, turnCtx)
+	}
+	if turnCtx["tenant_name"] != "Clínica Sorriso" || turnCtx["business_context"] != "Clínica odontológica em São Paulo." {
+		t.Errorf("Expected the tenant's business context, got %v", turnCtx)
+	}
+	if turnCtx["local_time"] == "" {
+		t.Errorf("Expected the call time, got %v", turnCtx)
+	}
+}
+
+func TestStartConversation_BusinessContextFailureIsNotFatal(t *testing.T) {
+	f := newServiceFixture(10)
+	f.service.SetBusinessContext(&fakeBusinessContexts{err: errors.New("tenant-manager unavailable")})
+	c := f.answeredCall(t)
+
+	if err := f.service.StartConversation(context.Background(), c.ID, "agent-1"); err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+
+	stored := f.store.stored(t, c.ID)
+	if stored.Context == nil || stored.Context.CallerNumber != c.CallerNumber || stored.Context.TenantName != "" {
+		t.Errorf("Expected the call's own context only, got %+v", stored.Context)
+	}
+}
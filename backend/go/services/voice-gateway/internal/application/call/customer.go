@@ -0,0 +1,51 @@
+package call
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+)
+
+// CustomerResolver resolves a phone number to the tenant's stable customer ID.
+type CustomerResolver interface {
+	ResolveCustomer(ctx context.Context, tenantID uuid.UUID, identifier string) (string, error)
+}
+
+var _ CustomerResolver = (*tenant.Client)(nil)
+
+// SetCustomerResolver makes conversations recognize repeat callers by
+// resolving the remote party's number to a customer when they start.
+func (s *Service) SetCustomerResolver(resolver CustomerResolver) {
+	s.customers = resolver
+}
+
+// resolveCustomer sets the call's customer from the remote party's number.
+// Failures are logged and leave the customer unknown, since the conversation
+// can go on without it.
+func (s *Service) resolveCustomer(ctx context.Context, c *call.Call) {
+	if s.customers == nil || c.CustomerID != "" {
+		return
+	}
+
+	number := c.CallerNumber
+	if c.Direction == call.DirectionOutbound {
+		number = c.CalleeNumber
+	}
+	if number == "" {
+		return
+	}
+
+	customerID, err := s.customers.ResolveCustomer(ctx, c.TenantID, number)
+	if err != nil {
+		s.logger.Warn("failed to resolve customer",
+			zap.String("call_id", c.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	c.CustomerID = customerID
+}
@@ -0,0 +1,68 @@
+package call
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	phone "github.com/serphona/serphona/backend/go/libs/platform-phone"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+)
+
+// DestinationPolicy resolves the countries a tenant's calls may dial.
+type DestinationPolicy interface {
+	GetAllowedCountries(ctx context.Context, tenantID uuid.UUID) ([]string, error)
+}
+
+var _ DestinationPolicy = (*tenant.Client)(nil)
+
+// SetDestinationPolicy restricts the numbers calls dial out to, such as
+// external transfer targets, to the countries in the tenant's telephony
+// settings, so a leaked key or a misled agent can't run up charges on
+// high-cost destinations. Without it, any valid number can be dialed.
+func (s *Service) SetDestinationPolicy(policy DestinationPolicy) {
+	s.destinations = policy
+}
+
+// authorizeDestination converts number to E.164 and checks that the tenant
+// of c may dial its country. Blocked destinations publish
+// call.destination_blocked.
+func (s *Service) authorizeDestination(ctx context.Context, c *call.Call, number string) (string, error) {
+	e164, err := s.normalizeNumber("destination", number)
+	if err != nil {
+		return "", err
+	}
+	if s.destinations == nil {
+		return e164, nil
+	}
+
+	country, err := phone.Country(e164)
+	if err != nil {
+		return "", fmt.Errorf("%w: destination %v", call.ErrInvalidNumber, err)
+	}
+	// Fails closed: not knowing the allowlist must not open every country
+	allowed, err := s.destinations.GetAllowedCountries(ctx, c.TenantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get allowed countries: %w", err)
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(strings.TrimSpace(a), country) {
+			return e164, nil
+		}
+	}
+
+	if err := s.eventPublisher.PublishDestinationBlocked(ctx, c, e164, country); err != nil {
+		s.logger.Error("failed to publish destination blocked event", zap.Error(err))
+	}
+	s.logger.Warn("outbound destination blocked",
+		zap.String("call_id", c.ID.String()),
+		zap.String("tenant_id", c.TenantID.String()),
+		zap.String("country", country),
+	)
+
+	return "", fmt.Errorf("%w: %s is not in the tenant's allowed countries", call.ErrDestinationDenied, country)
+}
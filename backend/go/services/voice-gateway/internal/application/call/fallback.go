@@ -0,0 +1,184 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+)
+
+// ErrorTypeProviderFailure is the error type of the error.provider_failure
+// event published when a provider fails mid-turn.
+const ErrorTypeProviderFailure = "provider_failure"
+
+// FallbackPolicy decides what happens when provider failures keep a call from
+// being answered.
+type FallbackPolicy struct {
+	// Retries is how many consecutive failed turns are answered with the
+	// fallback message, letting the caller try again. The next failure is
+	// answered with the fallback message and a transfer to a human.
+	Retries int
+	// TransferQueue is the queue the call is transferred to; empty means the
+	// channel's default escalation destination.
+	TransferQueue string
+}
+
+// FallbackMessageResolver resolves a tenant's fallback message; empty when
+// the tenant has none.
+type FallbackMessageResolver interface {
+	GetFallbackMessage(ctx context.Context, tenantID uuid.UUID) (string, error)
+}
+
+var _ FallbackMessageResolver = (*tenant.Client)(nil)
+
+// providerFailureError reports a provider call that failed within its budget.
+type providerFailureError struct {
+	component string
+	provider  string
+	err       error
+}
+
+func (e *providerFailureError) Error() string {
+	return fmt.Sprintf("%s provider %s failed: %v", e.component, e.provider, e.err)
+}
+
+func (e *providerFailureError) Unwrap() error {
+	return e.err
+}
+
+// SetFallback answers turns a provider failed with the tenant's fallback
+// message, or with the PROVIDER_FALLBACK_MESSAGE for tenants without one, and
+// transfers the call to a human once the failures exceed the policy's
+// retries.
+func (s *Service) SetFallback(messages FallbackMessageResolver, policy FallbackPolicy) {
+	s.fallbackMessages = messages
+	s.SetFallbackPolicy(policy)
+}
+
+// SetFallbackPolicy changes the fallback retries and transfer queue. Calls
+// keep the failed turns they already counted.
+func (s *Service) SetFallbackPolicy(policy FallbackPolicy) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.fallbackPolicy = &policy
+}
+
+// fallbackSettings returns the current fallback policy; nil when failed
+// turns are never transferred.
+func (s *Service) fallbackSettings() *FallbackPolicy {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.fallbackPolicy
+}
+
+// fallbackMessageFor returns the fallback message of the call's tenant, or
+// the global one when the tenant has none or it can't be resolved.
+func (s *Service) fallbackMessageFor(ctx context.Context, c *call.Call) string {
+	_, fallbackMessage := s.turnSettings()
+	if s.fallbackMessages == nil {
+		return fallbackMessage
+	}
+
+	message, err := s.fallbackMessages.GetFallbackMessage(ctx, c.TenantID)
+	if err != nil {
+		s.logger.Warn("failed to resolve tenant fallback message, using default",
+			zap.String("tenant_id", c.TenantID.String()),
+			zap.Error(err),
+		)
+		return fallbackMessage
+	}
+	if message == "" {
+		return fallbackMessage
+	}
+	return message
+}
+
+// reportProviderError logs and publishes a provider error: provider.timeout
+// when it ran out of budget, error.provider_failure otherwise. It returns
+// false for errors that don't come from a provider.
+func (s *Service) reportProviderError(ctx context.Context, c *call.Call, err error) bool {
+	var timeoutErr *providerTimeoutError
+	if errors.As(err, &timeoutErr) {
+		s.logger.Warn("provider timed out",
+			zap.String("call_id", c.ID.String()),
+			zap.String("component", timeoutErr.component),
+			zap.String("provider", timeoutErr.provider),
+			zap.Duration("budget", timeoutErr.budget),
+		)
+
+		if err := s.eventPublisher.PublishProviderTimeout(ctx, c, timeoutErr.component, timeoutErr.provider, timeoutErr.budget); err != nil {
+			s.logger.Error("failed to publish provider timeout event", zap.Error(err))
+		}
+		return true
+	}
+
+	var failureErr *providerFailureError
+	if !errors.As(err, &failureErr) {
+		return false
+	}
+
+	s.logger.Warn("provider failed",
+		zap.String("call_id", c.ID.String()),
+		zap.String("component", failureErr.component),
+		zap.String("provider", failureErr.provider),
+		zap.Error(failureErr.err),
+	)
+
+	var conversationID *uuid.UUID
+	if c.ConversationID != uuid.Nil {
+		conversationID = &c.ConversationID
+	}
+	if err := s.eventPublisher.PublishError(ctx, c.ID, c.TenantID, conversationID, ErrorTypeProviderFailure, err.Error(), failureErr.component); err != nil {
+		s.logger.Error("failed to publish provider failure event", zap.Error(err))
+	}
+	return true
+}
+
+// escalation counts a failed turn of the call and returns the transfer to a
+// human once the consecutive failures exceed the fallback retries; nil while
+// the caller can still retry. The count restarts after a transfer.
+func (s *Service) escalation(c *call.Call) *actions.Action {
+	policy := s.fallbackSettings()
+	if policy == nil {
+		return nil
+	}
+
+	s.turnMu.Lock()
+	defer s.turnMu.Unlock()
+
+	if s.failedTurns == nil {
+		s.failedTurns = make(map[uuid.UUID]int)
+	}
+	s.failedTurns[c.ID]++
+	if s.failedTurns[c.ID] <= policy.Retries {
+		return nil
+	}
+	delete(s.failedTurns, c.ID)
+
+	s.logger.Warn("provider failures exceeded fallback retries, transferring call",
+		zap.String("call_id", c.ID.String()),
+		zap.Int("retries", policy.Retries),
+		zap.String("queue", policy.TransferQueue),
+	)
+
+	params := actions.TransferParams{Reason: ErrorTypeProviderFailure}
+	if policy.TransferQueue != "" {
+		params.TransferType, params.Target = actions.TransferQueue, policy.TransferQueue
+	}
+	return actions.Transfer(params)
+}
+
+// resetFailedTurns forgets the failed turns of a call, once a turn is
+// answered or the call ends.
+func (s *Service) resetFailedTurns(callID uuid.UUID) {
+	s.turnMu.Lock()
+	defer s.turnMu.Unlock()
+	delete(s.failedTurns, callID)
+}
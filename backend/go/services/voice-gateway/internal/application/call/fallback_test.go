@@ -0,0 +1,170 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"go.uber.org/zap"
+
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	"voice-gateway/internal/adapter/agent"
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/adapter/tts"
+)
+
+// newFlakyAgentServer serves agent turns that fail with a 500 while failing
+// is set, and are answered without an action otherwise.
+func newFlakyAgentServer(t *testing.T, failing *atomic.Bool) *agent.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "model overloaded", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"agent_response":"Claro, posso ajudar."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return agent.NewClient(server.URL, zap.NewNop())
+}
+
+// expectFailureEvent expects one error.provider_failure event for the component.
+func expectFailureEvent(producer *mocks.SyncProducer, component string) {
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "serphona.error.provider_failure" {
+			return fmt.Errorf("unexpected topic %s", msg.Topic)
+		}
+
+		value, _ := msg.Value.Encode()
+		var event events.ErrorEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+		if event.Component != component || event.ErrorType != ErrorTypeProviderFailure {
+			return fmt.Errorf("expected a %s failure of %s, got %s of %s", ErrorTypeProviderFailure, component, event.ErrorType, event.Component)
+		}
+		return nil
+	})
+}
+
+func TestProcessTurn_AgentErrorPlaysFallback(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	expectFailureEvent(producer, ComponentAgent)
+
+	var failing atomic.Bool
+	failing.Store(true)
+	ttsProvider := &fakeTTS{}
+	s := newTurnService(t, producer, &slowSTT{}, ttsProvider, newFlakyAgentServer(t, &failing))
+
+	result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if err != nil {
+		t.Fatalf("Expected the fallback message, got error: %v", err)
+	}
+	if !result.Fallback || result.Response != testFallback {
+		t.Errorf("Expected fallback response, got %+v", result)
+	}
+	if len(ttsProvider.texts) != 1 || ttsProvider.texts[0] != testFallback {
+		t.Errorf("Expected the fallback message synthesized, got %q", ttsProvider.texts)
+	}
+	audio, _ := io.ReadAll(result.Audio)
+	if string(audio) != testFallback {
+		t.Errorf("Expected the fallback message played, got %q", audio)
+	}
+	if result.Action != nil {
+		t.Errorf("Expected no transfer without a fallback policy, got %+v", result.Action)
+	}
+}
+
+func TestProcessTurn_TransfersAfterFallbackRetries(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	s := newTurnService(t, producer, &slowSTT{}, &fakeTTS{}, newFlakyAgentServer(t, &failing))
+	s.SetFallback(nil, FallbackPolicy{Retries: 1, TransferQueue: "humanos"})
+	c := newTurnCall()
+
+	turn := func() *TurnResult {
+		t.Helper()
+		result, err := s.ProcessTurn(context.Background(), c, strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+		if err != nil {
+			t.Fatalf("ProcessTurn failed: %v", err)
+		}
+		return result
+	}
+
+	expectFailureEvent(producer, ComponentAgent)
+	if result := turn(); !result.Fallback || result.Action != nil {
+		t.Fatalf("Expected the first failure retried, got %+v", result)
+	}
+
+	// An answered turn restarts the count
+	failing.Store(false)
+	if result := turn(); result.Fallback {
+		t.Fatalf("Expected an answered turn, got %+v", result)
+	}
+	failing.Store(true)
+	expectFailureEvent(producer, ComponentAgent)
+	if result := turn(); result.Action != nil {
+		t.Fatalf("Expected the count restarted after an answered turn, got %+v", result.Action)
+	}
+
+	expectFailureEvent(producer, ComponentAgent)
+	result := turn()
+	if !result.Fallback || result.Response != testFallback {
+		t.Errorf("Expected the fallback message with the transfer, got %+v", result)
+	}
+	if result.Action == nil || result.Action.Type != actions.TypeTransfer {
+		t.Fatalf("Expected a transfer once retries ran out, got %+v", result.Action)
+	}
+	if p := result.Action.Transfer; p.TransferType != actions.TransferQueue || p.Target != "humanos" || p.Reason != ErrorTypeProviderFailure {
+		t.Errorf("Unexpected transfer %+v", p)
+	}
+	if err := result.Action.Validate(); err != nil {
+		t.Errorf("Transfer doesn't follow the action schema: %v", err)
+	}
+}
+
+func TestProcessTurn_TenantFallbackMessage(t *testing.T) {
+	const tenantFallback = "Um momento, por favor, estamos com instabilidade."
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"settings":{"ai_agent":{"custom_prompts":{"fallback":%q}}}}`, tenantFallback)
+	}))
+	defer server.Close()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	expectFailureEvent(producer, ComponentAgent)
+
+	var failing atomic.Bool
+	failing.Store(true)
+	ttsProvider := &fakeTTS{}
+	s := newTurnService(t, producer, &slowSTT{}, ttsProvider, newFlakyAgentServer(t, &failing))
+	s.SetFallback(tenant.NewClient(server.URL, zap.NewNop()), FallbackPolicy{Retries: 2})
+
+	result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if err != nil {
+		t.Fatalf("ProcessTurn failed: %v", err)
+	}
+	if result.Response != tenantFallback || len(ttsProvider.texts) != 1 || ttsProvider.texts[0] != tenantFallback {
+		t.Errorf("Expected the tenant fallback message played, got %q (synthesized %q)", result.Response, ttsProvider.texts)
+	}
+}
@@ -0,0 +1,149 @@
+package call
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/adapter/tts"
+	"voice-gateway/internal/domain/call"
+)
+
+// AgentConfigResolver resolves the agent configuration of a tenant.
+type AgentConfigResolver interface {
+	GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*tenant.AgentConfig, error)
+}
+
+var (
+	_ AgentConfigResolver = (*tenant.Client)(nil)
+	_ AgentConfigResolver = (*tenant.AgentConfigCache)(nil)
+)
+
+// GreetingMediaPath is the HTTP path under which synthesized greetings are
+// served to Asterisk, followed by the cache key and the ".wav" extension
+// Asterisk uses to pick the format.
+const GreetingMediaPath = "/media/tts/"
+
+// SetGreeting enables the agent greeting played when a call is answered.
+// Synthesized greetings are cached and served to Asterisk from mediaBaseURL,
+// the gateway address Asterisk can reach.
+func (s *Service) SetGreeting(agents AgentConfigResolver, cache *tts.Cache, mediaBaseURL string) {
+	s.agentConfigs = agents
+	s.ttsCache = cache
+	s.mediaBaseURL = strings.TrimSuffix(mediaBaseURL, "/")
+}
+
+// PlayGreeting synthesizes the greeting of the call's agent and starts playing
+// it. It returns false without playing when greetings are disabled or the
+// agent has none.
+func (s *Service) PlayGreeting(ctx context.Context, callID uuid.UUID) (bool, error) {
+	if s.agentConfigs == nil {
+		return false, nil
+	}
+
+	c, err := s.callStateRepo.Get(ctx, callID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	agentConfig, err := s.agentConfigs.GetAgentConfig(ctx, c.TenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get agent config: %w", err)
+	}
+
+	text := s.greetingText(ctx, c, agentConfig.Greeting)
+	if text == "" {
+		return false, nil
+	}
+
+	providerName := agentConfig.Voice.Provider
+	if providerName == "" {
+		providerName = c.TTSProvider
+	}
+	provider, err := s.GetTTSProvider(providerName)
+	if err != nil {
+		return false, err
+	}
+
+	config := tts.SynthesizeConfig{
+		Language:      agentConfig.Voice.Language,
+		VoiceID:       agentConfig.Voice.VoiceID,
+		SpeechRate:    agentConfig.Voice.Rate,
+		Pitch:         agentConfig.Voice.Pitch,
+		AudioEncoding: string(tts.AudioFormatWAV),
+	}
+	key, err := s.cachedSynthesis(ctx, c, provider, text, config)
+	if err != nil {
+		return false, err
+	}
+
+	media := "sound:" + s.mediaBaseURL + GreetingMediaPath + key + ".wav"
+	if _, err := s.asteriskClient.PlaybackStart(ctx, c.ChannelID, media); err != nil {
+		return false, fmt.Errorf("failed to play greeting: %w", err)
+	}
+
+	s.logger.Info("greeting started",
+		zap.String("call_id", callID.String()),
+		zap.String("tts_provider", provider.Name()),
+	)
+
+	return true, nil
+}
+
+// greetingText returns the text to greet the caller with. In agent mode it is
+// the agent's first turn; if the agent can't answer, the configured text is used.
+func (s *Service) greetingText(ctx context.Context, c *call.Call, greeting tenant.GreetingConfig) string {
+	switch greeting.Mode {
+	case tenant.GreetingModeNone:
+		return ""
+	case tenant.GreetingModeAgent:
+		if s.agentClient == nil || c.ConversationID == uuid.Nil {
+			return greeting.Text
+		}
+		reply, err := s.submitTurn(ctx, c, "")
+		if err != nil || reply.AgentResponse == "" {
+			s.logger.Warn("failed to get agent greeting, using the configured text",
+				zap.String("call_id", c.ID.String()),
+				zap.Error(err),
+			)
+			return greeting.Text
+		}
+		return reply.AgentResponse
+	default:
+		return greeting.Text
+	}
+}
+
+// cachedSynthesis synthesizes text unless the same audio is cached and
+// returns its cache key.
+func (s *Service) cachedSynthesis(ctx context.Context, c *call.Call, provider tts.Provider, text string, config tts.SynthesizeConfig) (string, error) {
+	key := tts.CacheKey(provider.Name(), text, config)
+	if _, ok := s.ttsCache.Get(key); ok {
+		return key, nil
+	}
+
+	audio, err := s.synthesize(ctx, c, provider, text, config)
+	if err != nil {
+		return "", fmt.Errorf("failed to synthesize greeting: %w", err)
+	}
+	data, err := io.ReadAll(audio)
+	if err != nil {
+		return "", fmt.Errorf("failed to read greeting audio: %w", err)
+	}
+
+	s.ttsCache.Put(key, data)
+	return key, nil
+}
+
+// CachedAudio returns synthesized audio by cache key, for Asterisk to fetch.
+func (s *Service) CachedAudio(key string) ([]byte, bool) {
+	if s.ttsCache == nil {
+		return nil, false
+	}
+	return s.ttsCache.Get(key)
+}
@@ -0,0 +1,98 @@
+package call
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/adapter/tts"
+)
+
+// fakeAgentConfigs returns the same agent configuration for every tenant.
+type fakeAgentConfigs struct {
+	config tenant.AgentConfig
+}
+
+func (f *fakeAgentConfigs) GetAgentConfig(ctx context.Context, tenantID uuid.UUID) (*tenant.AgentConfig, error) {
+	config := f.config
+	return &config, nil
+}
+
+func newGreetingFixture(greeting tenant.GreetingConfig) (*serviceFixture, *fakeTTS) {
+	f := newServiceFixture(10)
+	provider := &fakeTTS{}
+	f.service.ttsProviders = map[string]tts.Provider{"fake": provider}
+	f.service.SetGreeting(&fakeAgentConfigs{config: tenant.AgentConfig{
+		Voice:    tenant.VoiceConfig{Provider: "fake", Language: "pt-BR"},
+		Greeting: greeting,
+	}}, tts.NewCache(10), "http://voice-gateway:8080/")
+	return f, provider
+}
+
+func TestPlayGreeting_PlaysOnAnswer(t *testing.T) {
+	ctx := context.Background()
+	f, provider := newGreetingFixture(tenant.GreetingConfig{Text: "Olá, como posso ajudar?"})
+	c := f.answeredCall(t)
+
+	played, err := f.service.PlayGreeting(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("PlayGreeting failed: %v", err)
+	}
+	if !played {
+		t.Fatal("Expected the greeting to play")
+	}
+
+	if len(f.ari.played) != 1 {
+		t.Fatalf("Expected one playback, got %v", f.ari.played)
+	}
+	media := f.ari.played[0]
+	prefix := "sound:http://voice-gateway:8080" + GreetingMediaPath
+	if !strings.HasPrefix(media, prefix) || !strings.HasSuffix(media, ".wav") {
+		t.Fatalf("Expected the greeting served by the gateway, got %s", media)
+	}
+
+	key := strings.TrimSuffix(strings.TrimPrefix(media, prefix), ".wav")
+	audio, ok := f.service.CachedAudio(key)
+	if !ok || string(audio) != "Olá, como posso ajudar?" {
+		t.Errorf("Expected the synthesized greeting to be served, got %q", audio)
+	}
+	if len(provider.texts) != 1 {
+		t.Errorf("Expected one synthesis, got %v", provider.texts)
+	}
+}
+
+func TestPlayGreeting_CachesCommonGreetings(t *testing.T) {
+	ctx := context.Background()
+	f, provider := newGreetingFixture(tenant.GreetingConfig{Mode: tenant.GreetingModeStatic, Text: "Olá!"})
+
+	for i := 0; i < 3; i++ {
+		c := f.answeredCall(t)
+		if _, err := f.service.PlayGreeting(ctx, c.ID); err != nil {
+			t.Fatalf("PlayGreeting failed: %v", err)
+		}
+	}
+
+	if len(f.ari.played) != 3 {
+		t.Errorf("Expected a playback per call, got %v", f.ari.played)
+	}
+	if len(provider.texts) != 1 {
+		t.Errorf("Expected the greeting to be synthesized once, got %d", len(provider.texts))
+	}
+}
+
+func TestPlayGreeting_Disabled(t *testing.T) {
+	ctx := context.Background()
+	f, _ := newGreetingFixture(tenant.GreetingConfig{Mode: tenant.GreetingModeNone, Text: "Olá!"})
+	c := f.answeredCall(t)
+
+	played, err := f.service.PlayGreeting(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("PlayGreeting failed: %v", err)
+	}
+	if played || len(f.ari.played) != 0 {
+		t.Errorf("Expected no greeting, got %v", f.ari.played)
+	}
+}
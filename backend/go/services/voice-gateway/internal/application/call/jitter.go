@@ -0,0 +1,55 @@
+package call
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"voice-gateway/internal/application/audio"
+	"voice-gateway/internal/domain/call"
+)
+
+// JitterBufferConfig configures the jitter buffer caller audio is played
+// through before STT.
+type JitterBufferConfig struct {
+	FrameSize     int           // bytes of caller audio per frame, in the call's codec
+	FrameDuration time.Duration // cadence frames are played at
+	Depth         int           // frames held back; 0 disables the buffer
+}
+
+// SetJitterBuffer plays caller audio to STT through a jitter buffer, so
+// uneven network delivery reaches the provider at a steady cadence. With
+// feature flags set, the depth is the tenant's resolved JitterBufferDepth
+// instead of cfg.Depth.
+func (s *Service) SetJitterBuffer(cfg JitterBufferConfig) {
+	s.jitterBuffer = cfg
+}
+
+// jitterBufferDepth returns the jitter buffer depth of the call's tenant.
+func (s *Service) jitterBufferDepth(ctx context.Context, c *call.Call) int {
+	if s.featureFlags == nil {
+		return s.jitterBuffer.Depth
+	}
+	// On error the flags hold the global defaults
+	flags, _ := s.featureFlags.GetFeatureFlags(ctx, c.TenantID)
+	return flags.JitterBufferDepth
+}
+
+// dejitter plays caller audio through the jitter buffer, unless it is
+// disabled for the call's tenant. stop releases the buffer.
+func (s *Service) dejitter(ctx context.Context, c *call.Call, in io.Reader) (out io.Reader, stop func()) {
+	if s.jitterBuffer.FrameSize <= 0 {
+		return in, func() {}
+	}
+	depth := s.jitterBufferDepth(ctx, c)
+	if depth <= 0 {
+		return in, func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return audio.Dejitter(ctx, in, s.jitterBuffer.FrameSize, audio.JitterBufferOptions{
+		Depth:         depth,
+		FrameDuration: s.jitterBuffer.FrameDuration,
+		Stream:        "stt",
+	}), cancel
+}
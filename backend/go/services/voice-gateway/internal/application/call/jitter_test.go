@@ -0,0 +1,64 @@
+package call
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama/mocks"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/adapter/tts"
+)
+
+func TestProcessTurn_JitterBufferPerTenant(t *testing.T) {
+	const frameDuration = 5 * time.Millisecond
+	source := "0123456789abcdefghijklmn" // 6 frames of 4 bytes
+
+	tests := []struct {
+		name      string
+		overrides string
+		paced     bool
+	}{
+		{"global depth", `{}`, true},
+		{"tenant depth", `{"jitter_buffer_depth":4}`, true},
+		{"tenant disables", `{"jitter_buffer_depth":0}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"settings":{"telephony":{"feature_flags":` + tt.overrides + `}}}`))
+			}))
+			defer server.Close()
+
+			producer := mocks.NewSyncProducer(t, nil)
+			defer producer.Close()
+
+			sttProvider := &capturingSTT{}
+			s := newTurnService(t, producer, sttProvider, &fakeTTS{}, newAgentServer(t, 0))
+			defaults := tenant.FeatureFlags{AudioStreaming: true, JitterBufferDepth: 2}
+			s.SetFeatureFlags(tenant.NewFeatureFlagResolver(tenant.NewClient(server.URL, zap.NewNop()), defaults, time.Minute, zap.NewNop()))
+			s.SetJitterBuffer(JitterBufferConfig{FrameSize: 4, FrameDuration: frameDuration, Depth: 2})
+
+			start := time.Now()
+			if _, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader(source), stt.StreamConfig{}, tts.SynthesizeConfig{}); err != nil {
+				t.Fatalf("ProcessTurn failed: %v", err)
+			}
+			elapsed := time.Since(start)
+
+			if string(sttProvider.audio) != source {
+				t.Errorf("STT received %q, want %q", sttProvider.audio, source)
+			}
+			if tt.paced && elapsed < 5*frameDuration {
+				t.Errorf("Expected 6 frames paced %s apart, the turn took %s", frameDuration, elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package call
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/metrics"
+	"voice-gateway/internal/domain/call"
+)
+
+// TurnLatencyPublisher publishes turn.latency events.
+type TurnLatencyPublisher interface {
+	PublishTurnLatency(ctx context.Context, c *call.Call, l call.TurnLatency) error
+}
+
+var _ TurnLatencyPublisher = (*events.Publisher)(nil)
+
+// SetTurnLatency publishes a turn.latency event with the latency breakdown of
+// every answered turn, and flags the turns slower than budget.
+func (s *Service) SetTurnLatency(publisher TurnLatencyPublisher, budget time.Duration) {
+	s.latencyPublisher = publisher
+	s.SetTurnLatencyBudget(budget)
+}
+
+// SetTurnLatencyBudget changes the speech-to-speech latency budget of a turn.
+// Zero flags no turn.
+func (s *Service) SetTurnLatencyBudget(budget time.Duration) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.latencyBudget = budget
+}
+
+// turnLatencyBudget returns the current turn latency budget.
+func (s *Service) turnLatencyBudget() time.Duration {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.latencyBudget
+}
+
+// reportLatency records the latency of an answered turn, warning when it went
+// over its budget.
+func (s *Service) reportLatency(ctx context.Context, c *call.Call, l call.TurnLatency) {
+	metrics.ObserveTurn(l.Total)
+	metrics.ObserveTurnLatency(c.TenantID.String(), l)
+
+	if l.OverBudget() {
+		s.logger.Warn("turn over latency budget",
+			zap.String("call_id", c.ID.String()),
+			zap.String("turn_id", l.TurnID.String()),
+			zap.Duration("total", l.Total),
+			zap.Duration("budget", l.Budget),
+			zap.Duration("stt", l.STT),
+			zap.Duration("llm", l.LLM),
+			zap.Duration("tts", l.TTS),
+		)
+	}
+
+	if s.latencyPublisher == nil {
+		return
+	}
+	if err := s.latencyPublisher.PublishTurnLatency(ctx, c, l); err != nil {
+		s.logger.Error("failed to publish turn latency", zap.String("call_id", c.ID.String()), zap.Error(err))
+	}
+}
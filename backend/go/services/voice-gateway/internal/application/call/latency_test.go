@@ -0,0 +1,96 @@
+package call
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama/mocks"
+	"github.com/google/uuid"
+
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tts"
+	"voice-gateway/internal/domain/call"
+)
+
+// fakeLatencyPublisher records the turn.latency events.
+type fakeLatencyPublisher struct {
+	latencies []call.TurnLatency
+}
+
+func (p *fakeLatencyPublisher) PublishTurnLatency(ctx context.Context, c *call.Call, l call.TurnLatency) error {
+	p.latencies = append(p.latencies, l)
+	return nil
+}
+
+func TestProcessTurn_LatencyIsSumOfStages(t *testing.T) {
+	const (
+		stageDelay = 15 * time.Millisecond
+		tolerance  = 10 * time.Millisecond
+	)
+
+	for _, tt := range []struct {
+		name       string
+		budget     time.Duration
+		overBudget bool
+	}{
+		{"within budget", time.Second, false},
+		{"over budget", 2 * stageDelay, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := mocks.NewSyncProducer(t, nil)
+			defer producer.Close()
+
+			s := newTurnService(t, producer, &slowSTT{delay: stageDelay}, &fakeTTS{delay: stageDelay}, newAgentServer(t, stageDelay))
+			publisher := &fakeLatencyPublisher{}
+			s.SetTurnLatency(publisher, tt.budget)
+
+			result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+			if err != nil {
+				t.Fatalf("ProcessTurn failed: %v", err)
+			}
+
+			l := result.Latency
+			if l.TurnID == uuid.Nil {
+				t.Error("Expected the turn to have an ID")
+			}
+			for stage, d := range map[string]time.Duration{"stt": l.STT, "llm": l.LLM, "tts": l.TTS} {
+				if d < stageDelay {
+					t.Errorf("%s took %s, want at least %s", stage, d, stageDelay)
+				}
+			}
+			if sum := l.STT + l.LLM + l.TTS; l.Total < sum || l.Total-sum > tolerance {
+				t.Errorf("Total %s should equal the stages' sum %s within %s", l.Total, sum, tolerance)
+			}
+			if l.Budget != tt.budget || l.OverBudget() != tt.overBudget {
+				t.Errorf("Expected over budget %v against %s, got %v against %s", tt.overBudget, tt.budget, l.OverBudget(), l.Budget)
+			}
+
+			if len(publisher.latencies) != 1 || publisher.latencies[0] != l {
+				t.Errorf("Expected the turn latency published once, got %+v", publisher.latencies)
+			}
+		})
+	}
+}
+
+func TestProcessTurn_NoLatencyForFallback(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndSucceed() // provider.timeout
+	defer producer.Close()
+
+	s := newTurnService(t, producer, &slowSTT{delay: time.Second}, &fakeTTS{}, newAgentServer(t, 0))
+	publisher := &fakeLatencyPublisher{}
+	s.SetTurnLatency(publisher, time.Second)
+
+	result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if err != nil {
+		t.Fatalf("ProcessTurn failed: %v", err)
+	}
+	if !result.Fallback {
+		t.Fatal("Expected the fallback message")
+	}
+	if len(publisher.latencies) != 0 {
+		t.Errorf("Expected no turn latency for a fallback turn, got %+v", publisher.latencies)
+	}
+}
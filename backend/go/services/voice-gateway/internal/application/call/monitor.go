@@ -0,0 +1,132 @@
+package call
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/domain/call"
+)
+
+// MonitorBridge is the subset of the Asterisk ARI client used to let
+// supervisors listen to and talk into calls.
+type MonitorBridge interface {
+	CreateBridge(ctx context.Context, bridgeType string) (string, error)
+	AddChannelToBridge(ctx context.Context, bridgeID, channelID string) error
+	DestroyBridge(ctx context.Context, bridgeID string) error
+	SnoopChannel(ctx context.Context, channelID, spy, whisper string) (string, error)
+}
+
+var _ MonitorBridge = (*asterisk.ARIClient)(nil)
+
+// SetMonitoring enables supervisor spy, whisper and barge on active calls.
+func (s *Service) SetMonitoring(bridge MonitorBridge) {
+	s.monitorBridge = bridge
+}
+
+// SpyCall lets a supervisor listen to a call without being heard.
+func (s *Service) SpyCall(ctx context.Context, callID uuid.UUID, supervisor call.Supervisor) (*call.Monitor, error) {
+	return s.monitorCall(ctx, callID, supervisor, call.MonitorSpy)
+}
+
+// WhisperCall lets a supervisor listen to a call and talk to the agent without
+// the caller hearing.
+func (s *Service) WhisperCall(ctx context.Context, callID uuid.UUID, supervisor call.Supervisor) (*call.Monitor, error) {
+	return s.monitorCall(ctx, callID, supervisor, call.MonitorWhisper)
+}
+
+// BargeCall joins a supervisor to a call, heard by both parties.
+func (s *Service) BargeCall(ctx context.Context, callID uuid.UUID, supervisor call.Supervisor) (*call.Monitor, error) {
+	return s.monitorCall(ctx, callID, supervisor, call.MonitorBarge)
+}
+
+// monitorCall adds the supervisor's channel to the call. Barging into a call
+// that is already bridged joins the call bridge; otherwise the supervisor is
+// bridged with a snoop channel on the caller's channel whose directions
+// depend on the mode.
+func (s *Service) monitorCall(ctx context.Context, callID uuid.UUID, supervisor call.Supervisor, mode call.MonitorMode) (*call.Monitor, error) {
+	if s.monitorBridge == nil {
+		return nil, call.ErrMonitorDisabled
+	}
+
+	c, err := s.callStateRepo.Get(ctx, callID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	if !supervisor.CanMonitor(c) {
+		return nil, call.ErrMonitorForbidden
+	}
+	if !c.IsActive() {
+		return nil, call.ErrNotActive
+	}
+
+	m := call.NewMonitor(c, supervisor, mode)
+
+	if mode == call.MonitorBarge && c.BridgeID != "" {
+		if err := s.monitorBridge.AddChannelToBridge(ctx, c.BridgeID, supervisor.ChannelID); err != nil {
+			return nil, fmt.Errorf("failed to add supervisor to call bridge: %w", err)
+		}
+		m.BridgeID = c.BridgeID
+	} else if err := s.snoop(ctx, c, m); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventPublisher.PublishCallMonitorStarted(ctx, c, m); err != nil {
+		s.logger.Error("failed to publish call monitor started event", zap.Error(err))
+	}
+
+	s.logger.Info("call monitoring started",
+		zap.String("call_id", callID.String()),
+		zap.String("monitor_id", m.ID.String()),
+		zap.String("supervisor_id", supervisor.ID),
+		zap.String("mode", string(mode)),
+	)
+
+	return m, nil
+}
+
+// snoop bridges the supervisor's channel with a snoop channel on the call.
+func (s *Service) snoop(ctx context.Context, c *call.Call, m *call.Monitor) error {
+	spy, whisper := m.Mode.Snoop()
+	snoopID, err := s.monitorBridge.SnoopChannel(ctx, c.ChannelID, spy, whisper)
+	if err != nil {
+		return fmt.Errorf("failed to snoop call channel: %w", err)
+	}
+	m.SnoopChannelID = snoopID
+
+	bridgeID, err := s.monitorBridge.CreateBridge(ctx, "mixing")
+	if err != nil {
+		s.hangupSnoop(ctx, snoopID)
+		return fmt.Errorf("failed to create monitor bridge: %w", err)
+	}
+
+	for _, channelID := range []string{snoopID, m.SupervisorChannelID} {
+		if err := s.monitorBridge.AddChannelToBridge(ctx, bridgeID, channelID); err != nil {
+			if destroyErr := s.monitorBridge.DestroyBridge(ctx, bridgeID); destroyErr != nil {
+				s.logger.Warn("failed to destroy monitor bridge",
+					zap.String("bridge_id", bridgeID),
+					zap.Error(destroyErr),
+				)
+			}
+			s.hangupSnoop(ctx, snoopID)
+			return fmt.Errorf("failed to add channel to monitor bridge: %w", err)
+		}
+	}
+	m.BridgeID = bridgeID
+
+	return nil
+}
+
+// hangupSnoop hangs up a snoop channel left over from a failed monitor setup.
+func (s *Service) hangupSnoop(ctx context.Context, snoopID string) {
+	if err := s.asteriskClient.HangupChannel(ctx, snoopID); err != nil {
+		s.logger.Warn("failed to hang up snoop channel",
+			zap.String("snoop_channel_id", snoopID),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,175 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"voice-gateway/internal/domain/call"
+)
+
+// mockBridge records the ARI bridge and snoop operations of monitoring.
+type mockBridge struct {
+	addErr error
+
+	snoops    [][3]string         // channel, spy, whisper
+	bridged   map[string][]string // bridge ID -> channels
+	destroyed []string
+}
+
+func newMockBridge() *mockBridge {
+	return &mockBridge{bridged: make(map[string][]string)}
+}
+
+func (m *mockBridge) CreateBridge(ctx context.Context, bridgeType string) (string, error) {
+	return fmt.Sprintf("%s-bridge-%d", bridgeType, len(m.bridged)+1), nil
+}
+
+func (m *mockBridge) AddChannelToBridge(ctx context.Context, bridgeID, channelID string) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.bridged[bridgeID] = append(m.bridged[bridgeID], channelID)
+	return nil
+}
+
+func (m *mockBridge) DestroyBridge(ctx context.Context, bridgeID string) error {
+	m.destroyed = append(m.destroyed, bridgeID)
+	return nil
+}
+
+func (m *mockBridge) SnoopChannel(ctx context.Context, channelID, spy, whisper string) (string, error) {
+	m.snoops = append(m.snoops, [3]string{channelID, spy, whisper})
+	return "snoop-" + channelID, nil
+}
+
+func supervisorOf(c *call.Call) call.Supervisor {
+	return call.Supervisor{ID: "user-1", TenantID: c.TenantID, Role: "supervisor", ChannelID: "supervisor-channel"}
+}
+
+func TestMonitorCall_SnoopModes(t *testing.T) {
+	tests := []struct {
+		name    string
+		monitor func(s *Service, ctx context.Context, callID uuid.UUID, sup call.Supervisor) (*call.Monitor, error)
+		mode    call.MonitorMode
+		whisper string
+	}{
+		{"spy", (*Service).SpyCall, call.MonitorSpy, "none"},
+		{"whisper", (*Service).WhisperCall, call.MonitorWhisper, "in"},
+		{"barge without call bridge", (*Service).BargeCall, call.MonitorBarge, "both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceFixture(10)
+			bridge := newMockBridge()
+			f.service.SetMonitoring(bridge)
+			c := f.answeredCall(t)
+
+			m, err := tt.monitor(f.service, context.Background(), c.ID, supervisorOf(c))
+			if err != nil {
+				t.Fatalf("monitor failed: %v", err)
+			}
+
+			if len(bridge.snoops) != 1 || bridge.snoops[0] != [3]string{c.ChannelID, "both", tt.whisper} {
+				t.Fatalf("Expected a snoop on %s with whisper %q, got %v", c.ChannelID, tt.whisper, bridge.snoops)
+			}
+			channels := bridge.bridged[m.BridgeID]
+			if len(channels) != 2 || channels[0] != "snoop-"+c.ChannelID || channels[1] != "supervisor-channel" {
+				t.Errorf("Expected the snoop and supervisor channels in %s, got %v", m.BridgeID, channels)
+			}
+			if m.Mode != tt.mode || m.SnoopChannelID != "snoop-"+c.ChannelID || m.SupervisorID != "user-1" {
+				t.Errorf("Unexpected monitor %+v", m)
+			}
+			f.publisher.expect(t, "call.monitor_started")
+		})
+	}
+}
+
+func TestBargeCall_JoinsCallBridge(t *testing.T) {
+	f := newServiceFixture(10)
+	bridge := newMockBridge()
+	f.service.SetMonitoring(bridge)
+	c := f.answeredCall(t)
+	c.BridgeID = "call-bridge"
+	f.store.calls[c.ID] = *c
+
+	m, err := f.service.BargeCall(context.Background(), c.ID, supervisorOf(c))
+	if err != nil {
+		t.Fatalf("BargeCall failed: %v", err)
+	}
+
+	if len(bridge.snoops) != 0 {
+		t.Errorf("Expected no snoop when barging into the call bridge, got %v", bridge.snoops)
+	}
+	if channels := bridge.bridged["call-bridge"]; len(channels) != 1 || channels[0] != "supervisor-channel" {
+		t.Errorf("Expected the supervisor in the call bridge, got %v", channels)
+	}
+	if m.BridgeID != "call-bridge" || m.SnoopChannelID != "" {
+		t.Errorf("Unexpected monitor %+v", m)
+	}
+	f.publisher.expect(t, "call.monitor_started")
+}
+
+func TestMonitorCall_Forbidden(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *call.Supervisor)
+	}{
+		{"other tenant", func(s *call.Supervisor) { s.TenantID = uuid.New() }},
+		{"not a supervisor", func(s *call.Supervisor) { s.Role = "user" }},
+		{"anonymous", func(s *call.Supervisor) { s.ID = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceFixture(10)
+			bridge := newMockBridge()
+			f.service.SetMonitoring(bridge)
+			c := f.answeredCall(t)
+
+			supervisor := supervisorOf(c)
+			tt.modify(&supervisor)
+
+			if _, err := f.service.SpyCall(context.Background(), c.ID, supervisor); !errors.Is(err, call.ErrMonitorForbidden) {
+				t.Fatalf("Expected ErrMonitorForbidden, got %v", err)
+			}
+			if len(bridge.snoops) != 0 || len(bridge.bridged) != 0 {
+				t.Errorf("Expected no audio routing, got snoops=%v bridged=%v", bridge.snoops, bridge.bridged)
+			}
+			f.publisher.expect(t)
+		})
+	}
+}
+
+func TestMonitorCall_CleansUpOnFailure(t *testing.T) {
+	f := newServiceFixture(10)
+	bridge := newMockBridge()
+	bridge.addErr = errors.New("channel not in stasis")
+	f.service.SetMonitoring(bridge)
+	c := f.answeredCall(t)
+
+	if _, err := f.service.WhisperCall(context.Background(), c.ID, supervisorOf(c)); err == nil {
+		t.Fatal("Expected WhisperCall to fail")
+	}
+
+	if len(bridge.destroyed) != 1 {
+		t.Errorf("Expected the monitor bridge to be destroyed, got %v", bridge.destroyed)
+	}
+	if len(f.ari.hungUp) != 1 || f.ari.hungUp[0] != "snoop-"+c.ChannelID {
+		t.Errorf("Expected the snoop channel to be hung up, got %v", f.ari.hungUp)
+	}
+	f.publisher.expect(t)
+}
+
+func TestMonitorCall_Disabled(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.answeredCall(t)
+
+	if _, err := f.service.SpyCall(context.Background(), c.ID, supervisorOf(c)); !errors.Is(err, call.ErrMonitorDisabled) {
+		t.Fatalf("Expected ErrMonitorDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package call
+
+import (
+	"fmt"
+	"strings"
+
+	phone "github.com/serphona/serphona/backend/go/libs/platform-phone"
+
+	"voice-gateway/internal/domain/call"
+)
+
+// withheldCallerIDs are the caller numbers trunks send when the caller hides
+// the number. Those calls are accepted with the caller number as sent.
+var withheldCallerIDs = map[string]bool{
+	"":            true,
+	"anonymous":   true,
+	"private":     true,
+	"restricted":  true,
+	"unavailable": true,
+	"unknown":     true,
+}
+
+// SetNumberRegion sets the country (ISO 3166-1 alpha-2, e.g. "BR") of the
+// numbers the trunk sends without a country code. Without it, only numbers
+// in international format are accepted.
+func (s *Service) SetNumberRegion(region string) {
+	s.numberRegion = region
+}
+
+// normalizeNumbers converts the numbers of an incoming call to E.164, so
+// calls, DID lookups and events always carry the same form of a number.
+func (s *Service) normalizeNumbers(callerNumber, calleeNumber string) (caller, callee string, err error) {
+	if callee, err = s.normalizeNumber("callee", calleeNumber); err != nil {
+		return "", "", err
+	}
+	if withheldCallerIDs[strings.ToLower(strings.TrimSpace(callerNumber))] {
+		return callerNumber, callee, nil
+	}
+	if caller, err = s.normalizeNumber("caller", callerNumber); err != nil {
+		return "", "", err
+	}
+	return caller, callee, nil
+}
+
+// NormalizeDID converts a dialed number to E.164 like HandleIncomingCall does,
+// so the DID can be looked up before the call is created.
+func (s *Service) NormalizeDID(number string) (string, error) {
+	return s.normalizeNumber("callee", number)
+}
+
+func (s *Service) normalizeNumber(party, number string) (string, error) {
+	e164, err := phone.Normalize(number, s.numberRegion)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s %v", call.ErrInvalidNumber, party, err)
+	}
+	return e164, nil
+}
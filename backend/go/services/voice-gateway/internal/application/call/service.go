@@ -4,63 +4,237 @@ package call
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"voice-gateway/internal/adapter/agent"
 	"voice-gateway/internal/adapter/asterisk"
 	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/metrics"
 	"voice-gateway/internal/adapter/redis"
 	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tenant"
 	"voice-gateway/internal/adapter/tts"
+	"voice-gateway/internal/application/audio"
+	conversationservice "voice-gateway/internal/application/conversation"
+	queueservice "voice-gateway/internal/application/queue"
+	voicemailservice "voice-gateway/internal/application/voicemail"
 	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/cdr"
+	"voice-gateway/internal/domain/queue"
+	"voice-gateway/internal/domain/voicemail"
 )
 
+// ARIClient is the subset of the Asterisk ARI client used for call control.
+type ARIClient interface {
+	AnswerChannel(ctx context.Context, channelID string) error
+	HangupChannel(ctx context.Context, channelID string) error
+	PlaybackStart(ctx context.Context, channelID string, media string) (string, error)
+	GetRTPStatistics(ctx context.Context, channelID string) (*asterisk.RTPStatistics, error)
+}
+
+// CallStateRepository persists call state shared across gateway instances.
+type CallStateRepository interface {
+	Save(ctx context.Context, c *call.Call) error
+	Get(ctx context.Context, callID uuid.UUID) (*call.Call, error)
+	GetByChannelID(ctx context.Context, channelID string) (*call.Call, error)
+	ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*call.Call, error)
+	CountActive(ctx context.Context) (int64, error)
+	MarkEnded(ctx context.Context, callID uuid.UUID) (bool, error)
+}
+
+// EventPublisher publishes call lifecycle events.
+type EventPublisher interface {
+	PublishCallStarted(ctx context.Context, c *call.Call) error
+	PublishCallAnswered(ctx context.Context, c *call.Call) error
+	PublishCallEnded(ctx context.Context, c *call.Call) error
+	PublishCallAbandoned(ctx context.Context, c *call.Call, stage string, timeToAbandon time.Duration) error
+	PublishCallTransferred(ctx context.Context, c, leg *call.Call, transferType, target, reason string) error
+	PublishCallQualityDegraded(ctx context.Context, c *call.Call, q call.Quality, breached []string) error
+	PublishProviderTimeout(ctx context.Context, c *call.Call, component, provider string, budget time.Duration) error
+	PublishError(ctx context.Context, callID, tenantID uuid.UUID, conversationID *uuid.UUID, errorType, errorMessage, component string) error
+	PublishCallMonitorStarted(ctx context.Context, c *call.Call, m *call.Monitor) error
+	PublishDestinationBlocked(ctx context.Context, c *call.Call, destination, country string) error
+}
+
+// FeatureFlagResolver resolves the feature flags of a call's tenant.
+type FeatureFlagResolver interface {
+	GetFeatureFlags(ctx context.Context, tenantID uuid.UUID) (tenant.FeatureFlags, error)
+}
+
+var (
+	_ ARIClient           = (*asterisk.ARIClient)(nil)
+	_ CallStateRepository = (*redis.CallStateRepository)(nil)
+	_ EventPublisher      = (*events.Publisher)(nil)
+	_ FeatureFlagResolver = (*tenant.FeatureFlagResolver)(nil)
+)
+
+// DequeueHandler routes a call that left the hold queue because capacity freed up.
+type DequeueHandler func(ctx context.Context, c *call.Call)
+
 // Service orchestrates call lifecycle and interactions.
 type Service struct {
 	// Infrastructure
-	asteriskClient *asterisk.ARIClient
-	callStateRepo  *redis.CallStateRepository
-	eventPublisher *events.Publisher
+	asteriskClient ARIClient
+	amiClient      *asterisk.AMIClient // optional fallback when ARI is unavailable
+	callStateRepo  CallStateRepository
+	eventPublisher EventPublisher
 	logger         *zap.Logger
 
+	// Hold queue (optional); when nil, calls over capacity are rejected
+	queueManager   *queueservice.Manager
+	dequeueHandler DequeueHandler
+
+	// Voicemail (optional); when nil, calls that can't be handled are ended
+	voicemailService *voicemailservice.Service
+
+	// Per-tenant feature flags (optional); when nil, audio is always streamed
+	featureFlags FeatureFlagResolver
+
+	// Repeat caller recognition (optional); see SetCustomerResolver
+	customers CustomerResolver
+
+	// Tenant details in the agent's conversation context (optional); see SetBusinessContext
+	businessContexts BusinessContextResolver
+
+	// Supervisor monitoring (optional); see SetMonitoring
+	monitorBridge MonitorBridge
+
+	// Codec negotiation (optional); see SetCodecDetection
+	codecDetector CodecDetector
+	fallbackCodec audio.Codec
+
+	// Jitter buffer before STT (optional); see SetJitterBuffer
+	jitterBuffer JitterBufferConfig
+
+	// Call detail records (optional); see SetCDRs
+	cdrStore     CDRStore
+	cdrPublisher CDRPublisher
+	cdrRules     CDRRoundingResolver
+	cdrRounding  cdr.Rounding
+
+	// External transfers (optional); see SetTransfers
+	transferBridge   TransferBridge
+	transferEndpoint string
+
 	// Providers
 	sttProviders map[string]stt.Provider
 	ttsProviders map[string]tts.Provider
 
+	// Conversation turns (optional); see SetConversation
+	agentClient      *agent.Client
+	providerTimeouts ProviderTimeouts // guarded by settingsMu
+	fallbackMessage  string           // guarded by settingsMu
+	latencyBudget    time.Duration    // guarded by settingsMu; see SetTurnLatency
+	latencyPublisher TurnLatencyPublisher
+	settingsMu       sync.RWMutex
+	turnCancels      map[uuid.UUID]context.CancelFunc // call ID -> in-flight turn
+	failedTurns      map[uuid.UUID]int                // call ID -> consecutive turns a provider failed
+	turnMu           sync.Mutex
+
+	// Fallback on provider errors (optional); see SetFallback
+	fallbackMessages FallbackMessageResolver
+	fallbackPolicy   *FallbackPolicy // guarded by settingsMu
+
+	// Greeting on answer (optional); see SetGreeting
+	agentConfigs AgentConfigResolver
+	ttsCache     *tts.Cache
+	mediaBaseURL string
+
+	// Turn limit (optional); see SetConversationManager
+	conversations  *conversationservice.Manager
+	maxTurns       int
+	maxTurnsPrompt string
+
+	// Numbers without a country code (optional); see SetNumberRegion
+	numberRegion string
+
+	// Outbound destination allowlist (optional); see SetDestinationPolicy
+	destinations DestinationPolicy
+
+	// Outbound caller ID selection (optional); see SetCallerIDs
+	callerIDs CallerIDSource
+
 	// Configuration
-	maxConcurrentCalls int
+	maxConcurrentCalls atomic.Int64 // see SetMaxConcurrentCalls
+	qualityThresholds  call.QualityThresholds
 }
 
 // NewService creates a new call service.
 func NewService(
-	asteriskClient *asterisk.ARIClient,
-	callStateRepo *redis.CallStateRepository,
-	eventPublisher *events.Publisher,
+	asteriskClient ARIClient,
+	callStateRepo CallStateRepository,
+	eventPublisher EventPublisher,
 	sttProviders map[string]stt.Provider,
 	ttsProviders map[string]tts.Provider,
 	maxConcurrentCalls int,
+	qualityThresholds call.QualityThresholds,
 	logger *zap.Logger,
 ) *Service {
-	return &Service{
-		asteriskClient:     asteriskClient,
-		callStateRepo:      callStateRepo,
-		eventPublisher:     eventPublisher,
-		sttProviders:       sttProviders,
-		ttsProviders:       ttsProviders,
-		maxConcurrentCalls: maxConcurrentCalls,
-		logger:             logger,
+	s := &Service{
+		asteriskClient:    asteriskClient,
+		callStateRepo:     callStateRepo,
+		eventPublisher:    eventPublisher,
+		sttProviders:      sttProviders,
+		ttsProviders:      ttsProviders,
+		qualityThresholds: qualityThresholds,
+		logger:            logger,
 	}
+	s.SetMaxConcurrentCalls(maxConcurrentCalls)
+	return s
+}
+
+// SetMaxConcurrentCalls changes the concurrent call limit. Calls already
+// active are kept; the limit applies to new calls.
+func (s *Service) SetMaxConcurrentCalls(n int) {
+	s.maxConcurrentCalls.Store(int64(n))
+}
+
+// SetAMIFallback enables AMI as a degraded-mode fallback for call control.
+func (s *Service) SetAMIFallback(amiClient *asterisk.AMIClient) {
+	s.amiClient = amiClient
+}
+
+// SetQueue enables the hold queue for calls received while at capacity.
+func (s *Service) SetQueue(queueManager *queueservice.Manager) {
+	s.queueManager = queueManager
+	queueManager.SetTimeoutHandler(s.handleQueueTimeout)
+}
+
+// SetVoicemail enables voicemail for calls that time out in the hold queue.
+func (s *Service) SetVoicemail(voicemailService *voicemailservice.Service) {
+	s.voicemailService = voicemailService
+}
+
+// SetFeatureFlags makes conversation turns depend on the tenant's resolved
+// audio streaming flag.
+func (s *Service) SetFeatureFlags(resolver FeatureFlagResolver) {
+	s.featureFlags = resolver
+}
+
+// SetDequeueHandler sets the handler that routes calls leaving the hold queue.
+func (s *Service) SetDequeueHandler(handler DequeueHandler) {
+	s.dequeueHandler = handler
 }
 
 // HandleIncomingCall handles a new incoming call from Asterisk.
 func (s *Service) HandleIncomingCall(ctx context.Context, channelID, callerNumber, calleeNumber string, tenantID uuid.UUID) (*call.Call, error) {
-	// Check concurrent call limit
+	callerNumber, calleeNumber, err := s.normalizeNumbers(callerNumber, calleeNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check concurrent call limit; with the hold queue enabled the call is
+	// accepted and queued after it is answered
 	activeCount, err := s.callStateRepo.CountActive(ctx)
 	if err != nil {
 		s.logger.Error("failed to count active calls", zap.Error(err))
-	} else if activeCount >= int64(s.maxConcurrentCalls) {
-		return nil, fmt.Errorf("maximum concurrent calls reached: %d", s.maxConcurrentCalls)
+	} else if limit := s.maxConcurrentCalls.Load(); activeCount >= limit && s.queueManager == nil {
+		return nil, fmt.Errorf("%w: maximum of %d calls reached", call.ErrLimitExceeded, limit)
 	}
 
 	// Create call entity
@@ -68,11 +242,17 @@ func (s *Service) HandleIncomingCall(ctx context.Context, channelID, callerNumbe
 	c.ChannelID = channelID
 	c.State = call.StateRinging
 
+	if err := s.negotiateCodec(ctx, c); err != nil {
+		return nil, err
+	}
+
 	// Save initial state
 	if err := s.callStateRepo.Save(ctx, c); err != nil {
 		return nil, fmt.Errorf("failed to save call state: %w", err)
 	}
 
+	metrics.CallStarted(c)
+
 	// Publish call started event
 	if err := s.eventPublisher.PublishCallStarted(ctx, c); err != nil {
 		s.logger.Error("failed to publish call started event", zap.Error(err))
@@ -91,20 +271,28 @@ func (s *Service) HandleIncomingCall(ctx context.Context, channelID, callerNumbe
 func (s *Service) AnswerCall(ctx context.Context, callID uuid.UUID) error {
 	c, err := s.callStateRepo.Get(ctx, callID)
 	if err != nil {
-		return fmt.Errorf("call not found: %w", err)
+		return fmt.Errorf("failed to get call: %w", err)
+	}
+
+	// Only ringing calls can be answered
+	if err := c.Answer(); err != nil {
+		return err
 	}
 
 	// Answer via Asterisk ARI
 	if err := s.asteriskClient.AnswerChannel(ctx, c.ChannelID); err != nil {
+		metrics.ObserveAnswer(false)
 		return fmt.Errorf("failed to answer channel: %w", err)
 	}
+	metrics.ObserveAnswer(true)
 
 	// Update call state
-	c.Answer()
 	if err := s.callStateRepo.Save(ctx, c); err != nil {
 		return fmt.Errorf("failed to update call state: %w", err)
 	}
 
+	metrics.ObserveCallState(c)
+
 	// Publish call answered event
 	if err := s.eventPublisher.PublishCallAnswered(ctx, c); err != nil {
 		s.logger.Error("failed to publish call answered event", zap.Error(err))
@@ -118,22 +306,127 @@ func (s *Service) AnswerCall(ctx context.Context, callID uuid.UUID) error {
 	return nil
 }
 
+// QueueIfBusy places an answered call in the hold queue when the gateway is at
+// capacity or other calls are already waiting. It returns true if the call was queued.
+func (s *Service) QueueIfBusy(ctx context.Context, callID uuid.UUID) (bool, error) {
+	if s.queueManager == nil {
+		return false, nil
+	}
+
+	if s.queueManager.Len() == 0 {
+		activeCount, err := s.callStateRepo.CountActive(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to count active calls: %w", err)
+		}
+		// The count includes this call
+		if activeCount <= s.maxConcurrentCalls.Load() {
+			return false, nil
+		}
+	}
+
+	c, err := s.callStateRepo.Get(ctx, callID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	if err := c.Queue(); err != nil {
+		return false, err
+	}
+	if err := s.callStateRepo.Save(ctx, c); err != nil {
+		return false, fmt.Errorf("failed to update call state: %w", err)
+	}
+	metrics.ObserveCallState(c)
+
+	if _, err := s.queueManager.Enqueue(ctx, c); err != nil {
+		return false, fmt.Errorf("failed to enqueue call: %w", err)
+	}
+
+	return true, nil
+}
+
+// dequeueNext routes the longest-waiting queued call once capacity frees up.
+func (s *Service) dequeueNext(ctx context.Context) {
+	entry, ok := s.queueManager.Dequeue(ctx)
+	if !ok {
+		return
+	}
+
+	c, err := s.callStateRepo.Get(ctx, entry.CallID)
+	if err != nil {
+		s.logger.Error("dequeued call not found",
+			zap.String("call_id", entry.CallID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if err := c.Dequeue(); err != nil {
+		s.logger.Warn("dequeued call is no longer queued",
+			zap.String("call_id", c.ID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	if err := s.callStateRepo.Save(ctx, c); err != nil {
+		s.logger.Error("failed to update dequeued call state", zap.Error(err))
+		return
+	}
+
+	if s.dequeueHandler != nil {
+		s.dequeueHandler(ctx, c)
+	}
+}
+
+// handleQueueTimeout handles calls that exceeded the max queue wait.
+func (s *Service) handleQueueTimeout(ctx context.Context, entry queue.Entry) {
+	if s.voicemailService != nil {
+		started, err := s.voicemailService.Start(ctx, entry.CallID, voicemail.ReasonQueueTimeout)
+		if err != nil {
+			s.logger.Error("failed to start voicemail for timed out queued call",
+				zap.String("call_id", entry.CallID.String()),
+				zap.Error(err),
+			)
+		}
+		if started {
+			return
+		}
+	}
+
+	if err := s.EndCall(ctx, entry.CallID); err != nil {
+		s.logger.Error("failed to end timed out queued call",
+			zap.String("call_id", entry.CallID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
 // StartConversation initiates AI conversation on an answered call.
 func (s *Service) StartConversation(ctx context.Context, callID uuid.UUID, agentID string) error {
 	c, err := s.callStateRepo.Get(ctx, callID)
 	if err != nil {
-		return fmt.Errorf("call not found: %w", err)
+		return fmt.Errorf("failed to get call: %w", err)
 	}
 
 	if !c.IsActive() {
-		return fmt.Errorf("call is not in active state: %s", c.State)
+		return fmt.Errorf("%w: state is %s", call.ErrNotActive, c.State)
+	}
+	if err := c.Activate(); err != nil {
+		return err
 	}
 
-	// Generate conversation ID
+	// Generate conversation ID; tracked conversations are bound to the turn limit
 	conversationID := uuid.New()
+	if s.conversations != nil {
+		conv, err := s.conversations.CreateConversation(ctx, c.ID, c.TenantID, agentID, s.maxTurns)
+		if err != nil {
+			return fmt.Errorf("failed to create conversation: %w", err)
+		}
+		conversationID = conv.ID
+	}
 	c.ConversationID = conversationID
 	c.AgentID = agentID
-	c.Activate()
+	s.resolveCustomer(ctx, c)
+	s.buildContext(ctx, c)
 
 	// TODO: Initialize conversation with agent-orchestrator
 	// - Create conversation session
@@ -143,75 +436,196 @@ func (s *Service) StartConversation(ctx context.Context, callID uuid.UUID, agent
 	if err := s.callStateRepo.Save(ctx, c); err != nil {
 		return fmt.Errorf("failed to update call state: %w", err)
 	}
+	metrics.ObserveCallState(c)
 
 	s.logger.Info("conversation started",
 		zap.String("call_id", callID.String()),
 		zap.String("conversation_id", conversationID.String()),
 		zap.String("agent_id", agentID),
+		zap.String("customer_id", c.CustomerID),
 	)
 
 	return nil
 }
 
-// TransferCall transfers a call to a queue or external number.
-func (s *Service) TransferCall(ctx context.Context, callID uuid.UUID, transferType, target, reason string) error {
+// TransferCall transfers a call to a queue or external number. With
+// transfers enabled (see SetTransfers), an external transfer dials the number
+// as a leg of the call and bridges it with the caller, presenting fromDID as
+// the caller ID when set (see SetCallerIDs).
+func (s *Service) TransferCall(ctx context.Context, callID uuid.UUID, transferType, target, reason, fromDID string) error {
 	c, err := s.callStateRepo.Get(ctx, callID)
 	if err != nil {
-		return fmt.Errorf("call not found: %w", err)
+		return fmt.Errorf("failed to get call: %w", err)
 	}
 
-	// TODO: Implement transfer via Asterisk ARI
-	// - For queue: transfer to queue
-	// - For external: originate new call and bridge
+	var callerID string
+	if transferType == TransferExternal {
+		if target, err = s.authorizeDestination(ctx, c, target); err != nil {
+			return err
+		}
+		if callerID, err = s.selectCallerID(ctx, c, fromDID); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Transfer(); err != nil {
+		return err
+	}
+
+	// TODO: Implement queue transfers via Asterisk ARI
+	var leg *call.Call
+	if transferType == TransferExternal && s.transferBridge != nil {
+		if leg, err = s.bridgeLeg(ctx, c, target, callerID); err != nil {
+			return err
+		}
+	}
 
-	c.Transfer()
 	if err := s.callStateRepo.Save(ctx, c); err != nil {
 		return fmt.Errorf("failed to update call state: %w", err)
 	}
 
+	metrics.ObserveCallState(c)
+	metrics.IncTransfer(c.TenantID.String(), transferType)
+
 	// Publish transfer event
-	if err := s.eventPublisher.PublishCallTransferred(ctx, callID, c.TenantID, c.ConversationID, transferType, target, reason); err != nil {
+	if err := s.eventPublisher.PublishCallTransferred(ctx, c, leg, transferType, target, reason); err != nil {
 		s.logger.Error("failed to publish transfer event", zap.Error(err))
 	}
 
-	s.logger.Info("call transferred",
+	fields := []zap.Field{
 		zap.String("call_id", callID.String()),
 		zap.String("type", transferType),
 		zap.String("target", target),
-	)
+	}
+	if leg != nil {
+		fields = append(fields, zap.String("leg_call_id", leg.ID.String()))
+	}
+	s.logger.Info("call transferred", fields...)
 
 	return nil
 }
 
-// EndCall ends an active call.
+// HandleCallerHangup ends the call of a channel the caller hung up. A caller
+// hanging up before reaching an agent abandons the call, which publishes
+// call.abandoned with the stage and the time since the call arrived.
+func (s *Service) HandleCallerHangup(ctx context.Context, channelID string) error {
+	c, err := s.callStateRepo.GetByChannelID(ctx, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to get call: %w", err)
+	}
+
+	// Already ended by the gateway, e.g. after an announcement
+	if c.IsEnded() {
+		return nil
+	}
+
+	// Hanging up after leaving a voicemail is not abandoning
+	_, leftVoicemail := c.Metadata["voicemail_recording"]
+	if stage, ok := c.AbandonStage(); ok && !leftVoicemail {
+		timeToAbandon := time.Since(c.CreatedAt)
+		metrics.IncAbandoned(c.TenantID.String(), stage)
+
+		if err := s.eventPublisher.PublishCallAbandoned(ctx, c, stage, timeToAbandon); err != nil {
+			s.logger.Error("failed to publish call abandoned event", zap.Error(err))
+		}
+
+		s.logger.Info("call abandoned",
+			zap.String("call_id", c.ID.String()),
+			zap.String("stage", stage),
+			zap.Duration("time_to_abandon", timeToAbandon),
+		)
+	}
+
+	return s.EndCall(ctx, c.ID)
+}
+
+// EndCall ends an active call. Ending a call that has already ended does
+// nothing, so the paths that end a call may race.
 func (s *Service) EndCall(ctx context.Context, callID uuid.UUID) error {
 	c, err := s.callStateRepo.Get(ctx, callID)
 	if err != nil {
-		return fmt.Errorf("call not found: %w", err)
+		return fmt.Errorf("failed to get call: %w", err)
+	}
+
+	// Hangup, StasisEnd and ChannelDestroyed all end the call; only the first
+	// to mark it ended cleans up and publishes call.ended
+	if c.IsEnded() {
+		return nil
+	}
+	marked, err := s.callStateRepo.MarkEnded(ctx, callID)
+	if err != nil {
+		return fmt.Errorf("failed to mark call ended: %w", err)
+	}
+	if !marked {
+		return nil
+	}
+
+	// Stop waiting on providers for a caller who is gone
+	s.abortTurn(ctx, c)
+	s.resetFailedTurns(c.ID)
+	s.endConversation(ctx, c, conversationservice.EndReasonEnded)
+
+	// A caller hanging up while queued leaves the queue without freeing capacity
+	wasQueued := c.IsQueued()
+	if wasQueued && s.queueManager != nil {
+		s.queueManager.Remove(ctx, c.TenantID, c.ID)
+	}
+
+	// Collect RTP quality before the channel goes away
+	quality, qualityErr := s.collectQuality(ctx, c.ChannelID)
+	if qualityErr != nil {
+		s.logger.Warn("failed to collect call quality",
+			zap.String("call_id", callID.String()),
+			zap.Error(qualityErr),
+		)
+	} else {
+		c.SetQuality(quality)
 	}
 
 	// Hangup via Asterisk
 	if err := s.asteriskClient.HangupChannel(ctx, c.ChannelID); err != nil {
 		s.logger.Error("failed to hangup channel", zap.Error(err))
+		metrics.IncError(c.TenantID.String(), ComponentAsterisk)
+
+		// Fall back to AMI when ARI is unavailable
+		if s.amiClient != nil {
+			if err := s.amiClient.Hangup(ctx, c.ChannelID); err != nil {
+				s.logger.Error("failed to hangup channel via AMI fallback", zap.Error(err))
+			}
+		}
 		// Continue to update state even if hangup fails
 	}
 
 	// Update call state
-	c.End()
+	if err := c.End(); err != nil {
+		return err
+	}
 	if err := s.callStateRepo.Save(ctx, c); err != nil {
 		return fmt.Errorf("failed to update call state: %w", err)
 	}
 
+	metrics.CallEnded(c)
+
 	// Publish call ended event
 	if err := s.eventPublisher.PublishCallEnded(ctx, c); err != nil {
 		s.logger.Error("failed to publish call ended event", zap.Error(err))
 	}
+	s.recordCDR(ctx, c)
+
+	if qualityErr == nil {
+		s.reportQuality(ctx, c, quality)
+	}
 
 	s.logger.Info("call ended",
 		zap.String("call_id", callID.String()),
 		zap.Duration("duration", c.Duration),
 	)
 
+	// Hand the freed capacity to the next queued call
+	if s.queueManager != nil && !wasQueued {
+		s.dequeueNext(ctx)
+	}
+
 	// Cleanup state after some time (async)
 	go func() {
 		// Wait a bit before cleanup to allow event processing
@@ -222,6 +636,114 @@ func (s *Service) EndCall(ctx context.Context, callID uuid.UUID) error {
 	return nil
 }
 
+// collectQuality pulls RTP statistics from Asterisk and converts them into call quality.
+func (s *Service) collectQuality(ctx context.Context, channelID string) (call.Quality, error) {
+	stats, err := s.asteriskClient.GetRTPStatistics(ctx, channelID)
+	if err != nil {
+		return call.Quality{}, err
+	}
+
+	jitterMs := stats.RxJitter * 1000
+	if tx := stats.TxJitter * 1000; tx > jitterMs {
+		jitterMs = tx
+	}
+
+	packetLossPct := 0.0
+	if total := stats.RxCount + stats.RxPacketLoss; total > 0 {
+		packetLossPct = float64(stats.RxPacketLoss) / float64(total) * 100
+	}
+
+	// RTT covers both directions; latency is one-way
+	latencyMs := stats.RTT * 1000 / 2
+
+	return call.NewQuality(jitterMs, packetLossPct, latencyMs), nil
+}
+
+// reportQuality exports quality metrics and publishes a degradation event when thresholds are breached.
+func (s *Service) reportQuality(ctx context.Context, c *call.Call, q call.Quality) {
+	tenantID := c.TenantID.String()
+	metrics.ObserveCallQuality(tenantID, q)
+
+	breached := q.Degraded(s.qualityThresholds)
+	if len(breached) == 0 {
+		return
+	}
+
+	metrics.IncCallQualityDegraded(tenantID, breached)
+
+	if err := s.eventPublisher.PublishCallQualityDegraded(ctx, c, q, breached); err != nil {
+		s.logger.Error("failed to publish call quality degraded event", zap.Error(err))
+	}
+
+	s.logger.Warn("call quality degraded",
+		zap.String("call_id", c.ID.String()),
+		zap.Float64("mos", q.MOS),
+		zap.Strings("breached", breached),
+	)
+}
+
+// GetChannelStatus retrieves channel status via AMI fallback.
+func (s *Service) GetChannelStatus(ctx context.Context, callID uuid.UUID) (*asterisk.AMIChannelStatus, error) {
+	if s.amiClient == nil {
+		return nil, fmt.Errorf("ami fallback not enabled")
+	}
+
+	c, err := s.callStateRepo.Get(ctx, callID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	return s.amiClient.Status(ctx, c.ChannelID)
+}
+
+// PlayAnnouncement plays media on a call. If hangupAfter is true, the call is
+// ended when Asterisk reports the playback as finished.
+func (s *Service) PlayAnnouncement(ctx context.Context, callID uuid.UUID, media string, hangupAfter bool) error {
+	c, err := s.callStateRepo.Get(ctx, callID)
+	if err != nil {
+		return fmt.Errorf("failed to get call: %w", err)
+	}
+
+	playbackID, err := s.asteriskClient.PlaybackStart(ctx, c.ChannelID, media)
+	if err != nil {
+		return fmt.Errorf("failed to play announcement: %w", err)
+	}
+
+	if hangupAfter {
+		c.Metadata["hangup_after_playback"] = playbackID
+		if err := s.callStateRepo.Save(ctx, c); err != nil {
+			return fmt.Errorf("failed to update call state: %w", err)
+		}
+	}
+
+	s.logger.Info("announcement started",
+		zap.String("call_id", callID.String()),
+		zap.String("media", media),
+		zap.Bool("hangup_after", hangupAfter),
+	)
+
+	return nil
+}
+
+// HandlePlaybackFinished ends the call if it was waiting for the given playback to finish.
+func (s *Service) HandlePlaybackFinished(ctx context.Context, channelID, playbackID string) error {
+	c, err := s.callStateRepo.GetByChannelID(ctx, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to get call: %w", err)
+	}
+
+	if pending, ok := c.Metadata["hangup_after_playback"].(string); !ok || pending != playbackID {
+		return nil
+	}
+
+	return s.EndCall(ctx, c.ID)
+}
+
+// GetCallByChannelID retrieves a call by its Asterisk channel ID.
+func (s *Service) GetCallByChannelID(ctx context.Context, channelID string) (*call.Call, error) {
+	return s.callStateRepo.GetByChannelID(ctx, channelID)
+}
+
 // GetCallState retrieves current call state.
 func (s *Service) GetCallState(ctx context.Context, callID uuid.UUID) (*call.Call, error) {
 	return s.callStateRepo.Get(ctx, callID)
@@ -0,0 +1,1051 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/redis"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+)
+
+var errARIUnavailable = errors.New("ari: connection refused")
+
+// mockARI records channel operations and fails them on demand.
+type mockARI struct {
+	answerErr error
+	hangupErr error
+	statsErr  error
+	stats     asterisk.RTPStatistics
+
+	answered []string
+	hungUp   []string
+	played   []string
+}
+
+func (m *mockARI) AnswerChannel(ctx context.Context, channelID string) error {
+	if m.answerErr != nil {
+		return m.answerErr
+	}
+	m.answered = append(m.answered, channelID)
+	return nil
+}
+
+func (m *mockARI) HangupChannel(ctx context.Context, channelID string) error {
+	if m.hangupErr != nil {
+		return m.hangupErr
+	}
+	m.hungUp = append(m.hungUp, channelID)
+	return nil
+}
+
+func (m *mockARI) PlaybackStart(ctx context.Context, channelID string, media string) (string, error) {
+	m.played = append(m.played, media)
+	return "playback-1", nil
+}
+
+func (m *mockARI) GetRTPStatistics(ctx context.Context, channelID string) (*asterisk.RTPStatistics, error) {
+	if m.statsErr != nil {
+		return nil, m.statsErr
+	}
+	stats := m.stats
+	return &stats, nil
+}
+
+// fakeCallStore keeps call state in memory and records every saved state.
+type fakeCallStore struct {
+	mu      sync.Mutex
+	calls   map[uuid.UUID]call.Call
+	saved   []call.State
+	saveErr error
+	down    error // fails every request, like Redis being unreachable
+	active  int64
+}
+
+func newFakeCallStore() *fakeCallStore {
+	return &fakeCallStore{calls: make(map[uuid.UUID]call.Call)}
+}
+
+func (f *fakeCallStore) Ping(ctx context.Context) error {
+	return f.down
+}
+
+func (f *fakeCallStore) Save(ctx context.Context, c *call.Call) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down != nil {
+		return f.down
+	}
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.calls[c.ID] = *c
+	f.saved = append(f.saved, c.State)
+	return nil
+}
+
+func (f *fakeCallStore) Get(ctx context.Context, callID uuid.UUID) (*call.Call, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down != nil {
+		return nil, f.down
+	}
+	c, ok := f.calls[callID]
+	if !ok {
+		return nil, call.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (f *fakeCallStore) GetByChannelID(ctx context.Context, channelID string) (*call.Call, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down != nil {
+		return nil, f.down
+	}
+	for _, c := range f.calls {
+		if c.ChannelID == channelID {
+			return &c, nil
+		}
+	}
+	return nil, call.ErrNotFound
+}
+
+func (f *fakeCallStore) ListByTenant(ctx context.Context, tenantID uuid.UUID) ([]*call.Call, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down != nil {
+		return nil, f.down
+	}
+	var calls []*call.Call
+	for _, c := range f.calls {
+		if c.TenantID == tenantID {
+			calls = append(calls, &c)
+		}
+	}
+	return calls, nil
+}
+
+func (f *fakeCallStore) CountActive(ctx context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down != nil {
+		return 0, f.down
+	}
+	return f.active, nil
+}
+
+func (f *fakeCallStore) MarkEnded(ctx context.Context, callID uuid.UUID) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.down != nil {
+		return false, f.down
+	}
+	c, ok := f.calls[callID]
+	if !ok {
+		return false, call.ErrNotFound
+	}
+	if c.IsEnded() {
+		return false, nil
+	}
+	c.End()
+	f.calls[callID] = c
+	return true, nil
+}
+
+// stored returns the persisted state of a call.
+func (f *fakeCallStore) stored(t *testing.T, callID uuid.UUID) call.Call {
+	t.Helper()
+
+	c, ok := f.calls[callID]
+	if !ok {
+		t.Fatalf("Call %s was not persisted", callID)
+	}
+	return c
+}
+
+// publishedEvent is a call event captured by fakePublisher.
+type publishedEvent struct {
+	eventType string
+	callID    uuid.UUID
+	transfer  [3]string  // type, target, reason
+	leg       *uuid.UUID // call placed by the transfer
+	stage     string     // abandon stage
+}
+
+// fakePublisher captures published call events.
+type fakePublisher struct {
+	err    error
+	events []publishedEvent
+}
+
+func (f *fakePublisher) record(eventType string, callID uuid.UUID) error {
+	f.events = append(f.events, publishedEvent{eventType: eventType, callID: callID})
+	return f.err
+}
+
+func (f *fakePublisher) PublishCallStarted(ctx context.Context, c *call.Call) error {
+	return f.record("call.started", c.ID)
+}
+
+func (f *fakePublisher) PublishCallAnswered(ctx context.Context, c *call.Call) error {
+	return f.record("call.answered", c.ID)
+}
+
+func (f *fakePublisher) PublishCallEnded(ctx context.Context, c *call.Call) error {
+	return f.record("call.ended", c.ID)
+}
+
+func (f *fakePublisher) PublishCallAbandoned(ctx context.Context, c *call.Call, stage string, timeToAbandon time.Duration) error {
+	f.events = append(f.events, publishedEvent{eventType: "call.abandoned", callID: c.ID, stage: stage})
+	return f.err
+}
+
+func (f *fakePublisher) PublishCallTransferred(ctx context.Context, c, leg *call.Call, transferType, target, reason string) error {
+	event := publishedEvent{
+		eventType: "call.transferred",
+		callID:    c.ID,
+		transfer:  [3]string{transferType, target, reason},
+	}
+	if leg != nil {
+		event.leg = &leg.ID
+	}
+	f.events = append(f.events, event)
+	return f.err
+}
+
+func (f *fakePublisher) PublishCallQualityDegraded(ctx context.Context, c *call.Call, q call.Quality, breached []string) error {
+	return f.record("call.quality_degraded", c.ID)
+}
+
+func (f *fakePublisher) PublishProviderTimeout(ctx context.Context, c *call.Call, component, provider string, budget time.Duration) error {
+	return f.record("provider.timeout", c.ID)
+}
+
+func (f *fakePublisher) PublishError(ctx context.Context, callID, tenantID uuid.UUID, conversationID *uuid.UUID, errorType, errorMessage, component string) error {
+	return f.record("error."+errorType, callID)
+}
+
+func (f *fakePublisher) PublishCallMonitorStarted(ctx context.Context, c *call.Call, m *call.Monitor) error {
+	return f.record("call.monitor_started", c.ID)
+}
+
+func (f *fakePublisher) PublishDestinationBlocked(ctx context.Context, c *call.Call, destination, country string) error {
+	return f.record("call.destination_blocked", c.ID)
+}
+
+func (f *fakePublisher) PublishConversationEnded(ctx context.Context, callID, tenantID, conversationID uuid.UUID, agentID string, turnCount int, reason string) error {
+	return f.record("conversation.ended", callID)
+}
+
+func (f *fakePublisher) types() []string {
+	types := make([]string, len(f.events))
+	for i, e := range f.events {
+		types[i] = e.eventType
+	}
+	return types
+}
+
+func (f *fakePublisher) expect(t *testing.T, eventTypes ...string) {
+	t.Helper()
+
+	got := f.types()
+	if len(got) != len(eventTypes) {
+		t.Fatalf("Expected events %v, got %v", eventTypes, got)
+	}
+	for i := range eventTypes {
+		if got[i] != eventTypes[i] {
+			t.Fatalf("Expected events %v, got %v", eventTypes, got)
+		}
+	}
+}
+
+type serviceFixture struct {
+	service   *Service
+	ari       *mockARI
+	store     *fakeCallStore
+	publisher *fakePublisher
+}
+
+func newServiceFixture(maxConcurrentCalls int) *serviceFixture {
+	f := &serviceFixture{
+		ari:       &mockARI{},
+		store:     newFakeCallStore(),
+		publisher: &fakePublisher{},
+	}
+	f.service = NewService(f.ari, f.store, f.publisher, nil, nil, maxConcurrentCalls, call.QualityThresholds{MinMOS: 3.5}, zap.NewNop())
+	return f
+}
+
+// ringingCall persists a new inbound call as if HandleIncomingCall created it.
+func (f *serviceFixture) ringingCall(t *testing.T) *call.Call {
+	t.Helper()
+
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	c.ChannelID = "channel-" + c.ID.String()
+	c.State = call.StateRinging
+	f.store.calls[c.ID] = *c
+	return c
+}
+
+func (f *serviceFixture) answeredCall(t *testing.T) *call.Call {
+	t.Helper()
+
+	c := f.ringingCall(t)
+	c.Answer()
+	f.store.calls[c.ID] = *c
+	return c
+}
+
+func TestHandleIncomingCall(t *testing.T) {
+	f := newServiceFixture(10)
+	tenantID := uuid.New()
+
+	c, err := f.service.HandleIncomingCall(context.Background(), "channel-1", "+5511999887766", "+5511988776655", tenantID)
+	if err != nil {
+		t.Fatalf("HandleIncomingCall failed: %v", err)
+	}
+
+	stored := f.store.stored(t, c.ID)
+	if stored.State != call.StateRinging || stored.ChannelID != "channel-1" || stored.TenantID != tenantID {
+		t.Errorf("Unexpected persisted call: state=%s channel=%s tenant=%s", stored.State, stored.ChannelID, stored.TenantID)
+	}
+	if stored.Direction != call.DirectionInbound || stored.CallerNumber != "+5511999887766" {
+		t.Errorf("Unexpected call details: %s from %s", stored.Direction, stored.CallerNumber)
+	}
+	f.publisher.expect(t, "call.started")
+}
+
+func TestHandleIncomingCall_LimitExceeded(t *testing.T) {
+	f := newServiceFixture(2)
+	f.store.active = 2
+
+	_, err := f.service.HandleIncomingCall(context.Background(), "channel-1", "+5511999887766", "+5511988776655", uuid.New())
+	if !errors.Is(err, call.ErrLimitExceeded) {
+		t.Fatalf("Expected ErrLimitExceeded, got %v", err)
+	}
+
+	if len(f.store.saved) != 0 {
+		t.Errorf("Rejected call should not be persisted, saved %v", f.store.saved)
+	}
+	f.publisher.expect(t)
+}
+
+func TestHandleIncomingCall_NormalizesNumbers(t *testing.T) {
+	f := newServiceFixture(10)
+	f.service.SetNumberRegion("BR")
+
+	tests := []struct {
+		caller, callee         string
+		wantCaller, wantCallee string
+	}{
+		{"(11) 99988-7766", "11 98877-6655", "+5511999887766", "+5511988776655"},
+		{"011 99988 7766", "5511988776655", "+5511999887766", "+5511988776655"},
+		{"+55 11 99988-7766", "+55 (11) 98877-6655", "+5511999887766", "+5511988776655"},
+		{"anonymous", "1140001000", "anonymous", "+551140001000"},
+	}
+
+	for i, tt := range tests {
+		c, err := f.service.HandleIncomingCall(context.Background(), fmt.Sprintf("channel-%d", i), tt.caller, tt.callee, uuid.New())
+		if err != nil {
+			t.Fatalf("HandleIncomingCall(%q, %q) failed: %v", tt.caller, tt.callee, err)
+		}
+		stored := f.store.stored(t, c.ID)
+		if stored.CallerNumber != tt.wantCaller || stored.CalleeNumber != tt.wantCallee {
+			t.Errorf("HandleIncomingCall(%q, %q) stored %q -> %q, want %q -> %q",
+				tt.caller, tt.callee, stored.CallerNumber, stored.CalleeNumber, tt.wantCaller, tt.wantCallee)
+		}
+	}
+}
+
+func TestHandleIncomingCall_InvalidNumber(t *testing.T) {
+	f := newServiceFixture(10)
+	f.service.SetNumberRegion("BR")
+
+	tests := []struct{ caller, callee string }{
+		{"+5511999887766", "9988"},
+		{"+5511999887766", "s"},
+		{"+999 1234 5678", "+5511988776655"},
+	}
+
+	for _, tt := range tests {
+		_, err := f.service.HandleIncomingCall(context.Background(), "channel-1", tt.caller, tt.callee, uuid.New())
+		if !errors.Is(err, call.ErrInvalidNumber) {
+			t.Errorf("HandleIncomingCall(%q, %q): expected ErrInvalidNumber, got %v", tt.caller, tt.callee, err)
+		}
+	}
+
+	if len(f.store.saved) != 0 {
+		t.Errorf("Rejected calls should not be persisted, saved %v", f.store.saved)
+	}
+	f.publisher.expect(t)
+}
+
+func TestHandleIncomingCall_SaveFails(t *testing.T) {
+	f := newServiceFixture(10)
+	f.store.saveErr = errors.New("redis: connection refused")
+
+	if _, err := f.service.HandleIncomingCall(context.Background(), "channel-1", "+5511999887766", "+5511988776655", uuid.New()); err == nil {
+		t.Fatal("Expected error when call state can't be saved")
+	}
+
+	f.publisher.expect(t)
+}
+
+func TestAnswerCall(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.ringingCall(t)
+
+	if err := f.service.AnswerCall(context.Background(), c.ID); err != nil {
+		t.Fatalf("AnswerCall failed: %v", err)
+	}
+
+	if len(f.ari.answered) != 1 || f.ari.answered[0] != c.ChannelID {
+		t.Errorf("Expected channel %s to be answered, got %v", c.ChannelID, f.ari.answered)
+	}
+	stored := f.store.stored(t, c.ID)
+	if stored.State != call.StateAnswered || stored.AnsweredAt == nil {
+		t.Errorf("Expected answered state with timestamp, got %s", stored.State)
+	}
+	f.publisher.expect(t, "call.answered")
+}
+
+func TestAnswerCall_ARIFailure(t *testing.T) {
+	f := newServiceFixture(10)
+	f.ari.answerErr = errARIUnavailable
+	c := f.ringingCall(t)
+
+	err := f.service.AnswerCall(context.Background(), c.ID)
+	if !errors.Is(err, errARIUnavailable) {
+		t.Fatalf("Expected ARI error, got %v", err)
+	}
+
+	if len(f.store.saved) != 0 {
+		t.Errorf("Unanswered call should not change state, saved %v", f.store.saved)
+	}
+	if stored := f.store.stored(t, c.ID); stored.State != call.StateRinging {
+		t.Errorf("Expected call to stay ringing, got %s", stored.State)
+	}
+	f.publisher.expect(t)
+}
+
+func TestAnswerCall_NotFound(t *testing.T) {
+	f := newServiceFixture(10)
+
+	err := f.service.AnswerCall(context.Background(), uuid.New())
+	if !errors.Is(err, call.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	if len(f.ari.answered) != 0 {
+		t.Error("No channel should be answered for an unknown call")
+	}
+}
+
+func TestAnswerCall_AlreadyAnswered(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.answeredCall(t)
+	f.ari.answered = nil
+
+	err := f.service.AnswerCall(context.Background(), c.ID)
+	if !errors.Is(err, call.ErrInvalidTransition) {
+		t.Fatalf("Expected ErrInvalidTransition, got %v", err)
+	}
+	if len(f.ari.answered) != 0 {
+		t.Error("An answered call's channel should not be answered again")
+	}
+}
+
+func TestAnswerCall_PublishFailureIsNotFatal(t *testing.T) {
+	f := newServiceFixture(10)
+	f.publisher.err = errors.New("kafka: broker unavailable")
+	c := f.ringingCall(t)
+
+	if err := f.service.AnswerCall(context.Background(), c.ID); err != nil {
+		t.Fatalf("AnswerCall should succeed when the event can't be published: %v", err)
+	}
+
+	if stored := f.store.stored(t, c.ID); stored.State != call.StateAnswered {
+		t.Errorf("Expected answered state, got %s", stored.State)
+	}
+}
+
+func TestTransferCall(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.answeredCall(t)
+
+	if err := f.service.TransferCall(context.Background(), c.ID, "queue", "support", "customer request", ""); err != nil {
+		t.Fatalf("TransferCall failed: %v", err)
+	}
+
+	if stored := f.store.stored(t, c.ID); stored.State != call.StateTransferred {
+		t.Errorf("Expected transferred state, got %s", stored.State)
+	}
+	f.publisher.expect(t, "call.transferred")
+	event := f.publisher.events[0]
+	if event.callID != c.ID || event.transfer != [3]string{"queue", "support", "customer request"} {
+		t.Errorf("Unexpected transfer event %+v", event)
+	}
+}
+
+// fakeTransferBridge originates channels and records bridged channels.
+type fakeTransferBridge struct {
+	originateErr error
+	dialed       []string
+	callerIDs    []string
+	bridges      map[string][]string
+}
+
+func (b *fakeTransferBridge) OriginateChannel(ctx context.Context, endpoint, callerID string) (string, error) {
+	if b.originateErr != nil {
+		return "", b.originateErr
+	}
+	b.dialed = append(b.dialed, endpoint)
+	b.callerIDs = append(b.callerIDs, callerID)
+	return "leg-channel", nil
+}
+
+func (b *fakeTransferBridge) CreateBridge(ctx context.Context, bridgeType string) (string, error) {
+	return "transfer-bridge", nil
+}
+
+func (b *fakeTransferBridge) AddChannelToBridge(ctx context.Context, bridgeID, channelID string) error {
+	if b.bridges == nil {
+		b.bridges = make(map[string][]string)
+	}
+	b.bridges[bridgeID] = append(b.bridges[bridgeID], channelID)
+	return nil
+}
+
+func TestTransferCall_External(t *testing.T) {
+	f := newServiceFixture(10)
+	bridge := &fakeTransferBridge{}
+	f.service.SetTransfers(bridge, "PJSIP/%s@trunk")
+	c := f.answeredCall(t)
+
+	if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "escalation", ""); err != nil {
+		t.Fatalf("TransferCall failed: %v", err)
+	}
+
+	if len(bridge.dialed) != 1 || bridge.dialed[0] != "PJSIP/+5511977665544@trunk" {
+		t.Fatalf("Expected the target to be dialed once, got %v", bridge.dialed)
+	}
+
+	parent := f.store.stored(t, c.ID)
+	if parent.State != call.StateTransferred {
+		t.Errorf("Expected transferred state, got %s", parent.State)
+	}
+	if len(parent.RelatedCallIDs) != 1 {
+		t.Fatalf("Expected the parent to list one leg, got %v", parent.RelatedCallIDs)
+	}
+
+	leg := f.store.stored(t, parent.RelatedCallIDs[0])
+	if leg.ParentCallID == nil || *leg.ParentCallID != c.ID {
+		t.Errorf("Expected the leg to reference parent %s, got %v", c.ID, leg.ParentCallID)
+	}
+	if leg.Direction != call.DirectionOutbound || leg.CalleeNumber != "+5511977665544" || leg.ConversationID != c.ConversationID {
+		t.Errorf("Unexpected leg %+v", leg)
+	}
+	if got := bridge.bridges["transfer-bridge"]; len(got) != 2 || got[0] != c.ChannelID || got[1] != "leg-channel" {
+		t.Errorf("Expected caller and leg bridged, got %v", got)
+	}
+
+	f.publisher.expect(t, "call.started", "call.transferred")
+	if started := f.publisher.events[0]; started.callID != leg.ID {
+		t.Errorf("Expected call.started for the leg, got %+v", started)
+	}
+	if transferred := f.publisher.events[1]; transferred.callID != c.ID || transferred.leg == nil || *transferred.leg != leg.ID {
+		t.Errorf("Expected call.transferred to reference leg %s, got %+v", leg.ID, transferred)
+	}
+}
+
+// fakeDestinations allows the same countries to every tenant.
+type fakeDestinations struct {
+	countries []string
+	err       error
+}
+
+func (d fakeDestinations) GetAllowedCountries(ctx context.Context, tenantID uuid.UUID) ([]string, error) {
+	return d.countries, d.err
+}
+
+func TestTransferCall_DestinationCountry(t *testing.T) {
+	t.Run("allowed", func(t *testing.T) {
+		f := newServiceFixture(10)
+		bridge := &fakeTransferBridge{}
+		f.service.SetTransfers(bridge, "PJSIP/%s@trunk")
+		f.service.SetNumberRegion("BR")
+		f.service.SetDestinationPolicy(fakeDestinations{countries: []string{"br", "US"}})
+		c := f.answeredCall(t)
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "(11) 97766-5544", "", ""); err != nil {
+			t.Fatalf("TransferCall failed: %v", err)
+		}
+		if len(bridge.dialed) != 1 || bridge.dialed[0] != "PJSIP/+5511977665544@trunk" {
+			t.Fatalf("Expected the E.164 target to be dialed, got %v", bridge.dialed)
+		}
+		f.publisher.expect(t, "call.started", "call.transferred")
+	})
+
+	t.Run("blocked", func(t *testing.T) {
+		f := newServiceFixture(10)
+		bridge := &fakeTransferBridge{}
+		f.service.SetTransfers(bridge, "PJSIP/%s@trunk")
+		f.service.SetDestinationPolicy(fakeDestinations{countries: []string{"BR", "US"}})
+		c := f.answeredCall(t)
+
+		err := f.service.TransferCall(context.Background(), c.ID, "external", "+1 876 555 0100", "", "")
+		if !errors.Is(err, call.ErrDestinationDenied) {
+			t.Fatalf("Expected ErrDestinationDenied for Jamaica, got %v", err)
+		}
+		if len(bridge.dialed) != 0 {
+			t.Errorf("Blocked destination should not be dialed, got %v", bridge.dialed)
+		}
+		if stored := f.store.stored(t, c.ID); stored.State != call.StateAnswered {
+			t.Errorf("Expected the call to stay answered, got %s", stored.State)
+		}
+		f.publisher.expect(t, "call.destination_blocked")
+	})
+
+	t.Run("allowlist unavailable", func(t *testing.T) {
+		f := newServiceFixture(10)
+		bridge := &fakeTransferBridge{}
+		f.service.SetTransfers(bridge, "PJSIP/%s@trunk")
+		f.service.SetDestinationPolicy(fakeDestinations{err: errors.New("tenant-manager unavailable")})
+		c := f.answeredCall(t)
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", ""); err == nil {
+			t.Fatal("Expected the transfer to be refused when the allowlist can't be read")
+		}
+		if len(bridge.dialed) != 0 {
+			t.Errorf("Expected nothing dialed, got %v", bridge.dialed)
+		}
+		f.publisher.expect(t)
+	})
+}
+
+// fakeCallerIDs holds the DIDs of tenants and their default caller IDs.
+type fakeCallerIDs struct {
+	dids     map[string]tenant.DIDInfo
+	defaults map[uuid.UUID]string
+}
+
+func (f fakeCallerIDs) LookupDID(ctx context.Context, phoneNumber string) (*tenant.DIDInfo, error) {
+	info, ok := f.dids[phoneNumber]
+	if !ok {
+		return nil, tenant.ErrDIDNotFound
+	}
+	return &info, nil
+}
+
+func (f fakeCallerIDs) GetCallerIDNumber(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	return f.defaults[tenantID], nil
+}
+
+func TestTransferCall_CallerID(t *testing.T) {
+	setup := func(t *testing.T) (*serviceFixture, *fakeTransferBridge, *call.Call, fakeCallerIDs) {
+		f := newServiceFixture(10)
+		bridge := &fakeTransferBridge{}
+		f.service.SetTransfers(bridge, "PJSIP/%s@trunk")
+		c := f.answeredCall(t)
+		ids := fakeCallerIDs{
+			dids: map[string]tenant.DIDInfo{
+				"+551140001000": {DID: "+551140001000", TenantID: c.TenantID, Enabled: true},
+				"+551140002000": {DID: "+551140002000", TenantID: c.TenantID, Enabled: false},
+				"+551140009000": {DID: "+551140009000", TenantID: uuid.New(), Enabled: true},
+			},
+			defaults: map[uuid.UUID]string{},
+		}
+		f.service.SetCallerIDs(ids)
+		return f, bridge, c, ids
+	}
+
+	leg := func(t *testing.T, f *serviceFixture, c *call.Call) call.Call {
+		t.Helper()
+		parent := f.store.stored(t, c.ID)
+		if len(parent.RelatedCallIDs) != 1 {
+			t.Fatalf("Expected one leg, got %v", parent.RelatedCallIDs)
+		}
+		return f.store.stored(t, parent.RelatedCallIDs[0])
+	}
+
+	t.Run("owned DID", func(t *testing.T) {
+		f, bridge, c, _ := setup(t)
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", "+55 11 4000-1000"); err != nil {
+			t.Fatalf("TransferCall failed: %v", err)
+		}
+		if got := leg(t, f, c).CallerNumber; got != "+551140001000" {
+			t.Errorf("Expected the requested DID as caller ID, got %q", got)
+		}
+		if len(bridge.callerIDs) != 1 || bridge.callerIDs[0] != "+551140001000" {
+			t.Errorf("Expected the leg originated with the requested DID, got %v", bridge.callerIDs)
+		}
+	})
+
+	for name, did := range map[string]string{
+		"DID of another tenant": "+551140009000",
+		"disabled DID":          "+551140002000",
+		"unknown number":        "+551140003000",
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, bridge, c, _ := setup(t)
+
+			err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", did)
+			if !errors.Is(err, call.ErrCallerIDNotOwned) {
+				t.Fatalf("Expected ErrCallerIDNotOwned, got %v", err)
+			}
+			if len(bridge.dialed) != 0 {
+				t.Errorf("Expected nothing dialed, got %v", bridge.dialed)
+			}
+			f.publisher.expect(t)
+		})
+	}
+
+	t.Run("tenant default", func(t *testing.T) {
+		f, _, c, ids := setup(t)
+		ids.defaults[c.TenantID] = "+551140001000"
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", ""); err != nil {
+			t.Fatalf("TransferCall failed: %v", err)
+		}
+		if got := leg(t, f, c).CallerNumber; got != "+551140001000" {
+			t.Errorf("Expected the tenant default caller ID, got %q", got)
+		}
+	})
+
+	t.Run("no default falls back to the called DID", func(t *testing.T) {
+		f, _, c, _ := setup(t)
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", ""); err != nil {
+			t.Fatalf("TransferCall failed: %v", err)
+		}
+		if got := leg(t, f, c).CallerNumber; got != c.CalleeNumber {
+			t.Errorf("Expected the call's DID %q as caller ID, got %q", c.CalleeNumber, got)
+		}
+	})
+
+	t.Run("unowned default falls back to the called DID", func(t *testing.T) {
+		f, _, c, ids := setup(t)
+		ids.defaults[c.TenantID] = "+551140009000"
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", ""); err != nil {
+			t.Fatalf("TransferCall failed: %v", err)
+		}
+		if got := leg(t, f, c).CallerNumber; got != c.CalleeNumber {
+			t.Errorf("Expected the call's DID %q as caller ID, got %q", c.CalleeNumber, got)
+		}
+	})
+}
+
+func TestTransferCall_Failures(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		f := newServiceFixture(10)
+
+		err := f.service.TransferCall(context.Background(), uuid.New(), "queue", "support", "", "")
+		if !errors.Is(err, call.ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+		f.publisher.expect(t)
+	})
+
+	t.Run("ringing call", func(t *testing.T) {
+		f := newServiceFixture(10)
+		c := f.ringingCall(t)
+
+		err := f.service.TransferCall(context.Background(), c.ID, "queue", "support", "", "")
+		if !errors.Is(err, call.ErrInvalidTransition) {
+			t.Fatalf("Expected ErrInvalidTransition, got %v", err)
+		}
+		if stored := f.store.stored(t, c.ID); stored.State != call.StateRinging {
+			t.Errorf("Expected the call to keep ringing, got state %s", stored.State)
+		}
+		f.publisher.expect(t)
+	})
+
+	t.Run("save fails", func(t *testing.T) {
+		f := newServiceFixture(10)
+		c := f.answeredCall(t)
+		f.store.saveErr = errors.New("redis: connection refused")
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", ""); err == nil {
+			t.Fatal("Expected error when call state can't be saved")
+		}
+		f.publisher.expect(t)
+	})
+
+	t.Run("originate fails", func(t *testing.T) {
+		f := newServiceFixture(10)
+		f.service.SetTransfers(&fakeTransferBridge{originateErr: errARIUnavailable}, "PJSIP/%s@trunk")
+		c := f.answeredCall(t)
+
+		if err := f.service.TransferCall(context.Background(), c.ID, "external", "+5511977665544", "", ""); !errors.Is(err, errARIUnavailable) {
+			t.Fatalf("Expected ARI error, got %v", err)
+		}
+		if stored := f.store.stored(t, c.ID); stored.State != call.StateAnswered || len(stored.RelatedCallIDs) != 0 {
+			t.Errorf("Expected the call untouched, got state %s with legs %v", stored.State, stored.RelatedCallIDs)
+		}
+		f.publisher.expect(t)
+	})
+}
+
+func TestEndCall(t *testing.T) {
+	f := newServiceFixture(10)
+	f.ari.stats = asterisk.RTPStatistics{RxCount: 1000, RxJitter: 0.005, RTT: 0.04}
+	c := f.answeredCall(t)
+
+	if err := f.service.EndCall(context.Background(), c.ID); err != nil {
+		t.Fatalf("EndCall failed: %v", err)
+	}
+
+	if len(f.ari.hungUp) != 1 || f.ari.hungUp[0] != c.ChannelID {
+		t.Errorf("Expected channel %s to be hung up, got %v", c.ChannelID, f.ari.hungUp)
+	}
+	stored := f.store.stored(t, c.ID)
+	if stored.State != call.StateEnded || stored.EndedAt == nil {
+		t.Errorf("Expected ended state with timestamp, got %s", stored.State)
+	}
+	if _, ok := stored.Metadata["quality"]; !ok {
+		t.Error("Expected call quality to be persisted")
+	}
+	f.publisher.expect(t, "call.ended")
+}
+
+func TestEndCall_QualityDegraded(t *testing.T) {
+	f := newServiceFixture(10)
+	f.ari.stats = asterisk.RTPStatistics{RxCount: 800, RxPacketLoss: 200, RxJitter: 0.08, RTT: 0.6}
+	c := f.answeredCall(t)
+
+	if err := f.service.EndCall(context.Background(), c.ID); err != nil {
+		t.Fatalf("EndCall failed: %v", err)
+	}
+
+	f.publisher.expect(t, "call.ended", "call.quality_degraded")
+}
+
+func TestEndCall_ARIFailure(t *testing.T) {
+	f := newServiceFixture(10)
+	f.ari.hangupErr = errARIUnavailable
+	f.ari.statsErr = errARIUnavailable
+	c := f.answeredCall(t)
+
+	if err := f.service.EndCall(context.Background(), c.ID); err != nil {
+		t.Fatalf("EndCall should end the call even if ARI fails: %v", err)
+	}
+
+	stored := f.store.stored(t, c.ID)
+	if stored.State != call.StateEnded {
+		t.Errorf("Expected ended state, got %s", stored.State)
+	}
+	if _, ok := stored.Metadata["quality"]; ok {
+		t.Error("No quality should be recorded without RTP statistics")
+	}
+	f.publisher.expect(t, "call.ended")
+}
+
+func TestEndCall_NotFound(t *testing.T) {
+	f := newServiceFixture(10)
+
+	err := f.service.EndCall(context.Background(), uuid.New())
+	if !errors.Is(err, call.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	if len(f.ari.hungUp) != 0 {
+		t.Error("No channel should be hung up for an unknown call")
+	}
+	f.publisher.expect(t)
+}
+
+func TestEndCall_AlreadyEnded(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.answeredCall(t)
+
+	for i := 0; i < 2; i++ {
+		if err := f.service.EndCall(context.Background(), c.ID); err != nil {
+			t.Fatalf("EndCall #%d failed: %v", i+1, err)
+		}
+	}
+
+	if len(f.ari.hungUp) != 1 {
+		t.Errorf("Expected the channel to be hung up once, got %v", f.ari.hungUp)
+	}
+	f.publisher.expect(t, "call.ended")
+}
+
+func TestEndCall_Concurrent(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.answeredCall(t)
+
+	// Hangup and StasisEnd ending the same call at once
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- f.service.EndCall(context.Background(), c.ID)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("EndCall failed: %v", err)
+		}
+	}
+	if stored := f.store.stored(t, c.ID); stored.State != call.StateEnded {
+		t.Errorf("Expected ended state, got %s", stored.State)
+	}
+	f.publisher.expect(t, "call.ended")
+}
+
+func TestCallSurvivesRedisOutage(t *testing.T) {
+	f := newServiceFixture(10)
+	state := redis.NewResilientCallStateRepository(f.store, redis.ResilienceOptions{
+		FailureThreshold: 1,
+		Cooldown:         time.Hour,
+		Fallback:         true,
+		TTL:              time.Hour,
+	}, zap.NewNop())
+	f.service.callStateRepo = state
+	ctx := context.Background()
+
+	inProgress, err := f.service.HandleIncomingCall(ctx, "channel-1", "+5511999887766", "+5511988776655", uuid.New())
+	if err != nil {
+		t.Fatalf("HandleIncomingCall failed: %v", err)
+	}
+
+	f.store.down = errors.New("redis: connection refused")
+
+	// New calls are accepted and calls in progress continue
+	incoming, err := f.service.HandleIncomingCall(ctx, "channel-2", "+5511977665544", "+5511988776655", uuid.New())
+	if err != nil {
+		t.Fatalf("Incoming call dropped during Redis outage: %v", err)
+	}
+	for _, c := range []*call.Call{inProgress, incoming} {
+		if err := f.service.AnswerCall(ctx, c.ID); err != nil {
+			t.Fatalf("AnswerCall failed during Redis outage: %v", err)
+		}
+	}
+	if err := f.service.EndCall(ctx, inProgress.ID); err != nil {
+		t.Fatalf("EndCall failed during Redis outage: %v", err)
+	}
+
+	if c, err := f.service.GetCallByChannelID(ctx, "channel-2"); err != nil || c.State != call.StateAnswered {
+		t.Errorf("Expected answered call from memory, got %v, %v", c, err)
+	}
+	if state.Pending() != 2 {
+		t.Errorf("Expected 2 calls pending reconciliation, got %d", state.Pending())
+	}
+	f.publisher.expect(t, "call.started", "call.started", "call.answered", "call.answered", "call.ended")
+}
+
+func TestHandleCallerHangup_Abandoned(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(f *serviceFixture, t *testing.T) *call.Call
+		stage string
+	}{
+		{"before answer", (*serviceFixture).ringingCall, call.AbandonBeforeAnswer},
+		{"answered before agent", (*serviceFixture).answeredCall, call.AbandonBeforeAgent},
+		{"in queue", func(f *serviceFixture, t *testing.T) *call.Call {
+			c := f.answeredCall(t)
+			c.Queue()
+			f.store.calls[c.ID] = *c
+			return c
+		}, call.AbandonInQueue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newServiceFixture(10)
+			c := tt.setup(f, t)
+
+			if err := f.service.HandleCallerHangup(context.Background(), c.ChannelID); err != nil {
+				t.Fatalf("HandleCallerHangup failed: %v", err)
+			}
+
+			f.publisher.expect(t, "call.abandoned", "call.ended")
+			if stage := f.publisher.events[0].stage; stage != tt.stage {
+				t.Errorf("Expected stage %s, got %s", tt.stage, stage)
+			}
+			if stored := f.store.stored(t, c.ID); stored.State != call.StateEnded {
+				t.Errorf("Expected the call to end, got state %s", stored.State)
+			}
+		})
+	}
+}
+
+func TestHandleCallerHangup_NotAbandoned(t *testing.T) {
+	f := newServiceFixture(10)
+	c := f.answeredCall(t)
+	c.Activate()
+	f.store.calls[c.ID] = *c
+
+	if err := f.service.HandleCallerHangup(context.Background(), c.ChannelID); err != nil {
+		t.Fatalf("HandleCallerHangup failed: %v", err)
+	}
+
+	f.publisher.expect(t, "call.ended")
+}
+
+// fakeCustomers resolves every number to a fixed customer.
+type fakeCustomers struct {
+	customerID  string
+	err         error
+	identifiers []string
+}
+
+func (f *fakeCustomers) ResolveCustomer(ctx context.Context, tenantID uuid.UUID, identifier string) (string, error) {
+	f.identifiers = append(f.identifiers, identifier)
+	return f.customerID, f.err
+}
+
+func TestStartConversation_ResolvesCaller(t *testing.T) {
+	f := newServiceFixture(10)
+	customers := &fakeCustomers{customerID: "customer-1"}
+	f.service.SetCustomerResolver(customers)
+	c := f.answeredCall(t)
+
+	if err := f.service.StartConversation(context.Background(), c.ID, "agent-1"); err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+
+	if len(customers.identifiers) != 1 || customers.identifiers[0] != c.CallerNumber {
+		t.Errorf("Expected the caller number to be resolved, got %v", customers.identifiers)
+	}
+	if stored := f.store.stored(t, c.ID); stored.CustomerID != "customer-1" {
+		t.Errorf("Expected customer-1 on the call, got %q", stored.CustomerID)
+	}
+}
+
+func TestStartConversation_CustomerResolutionFailureIsNotFatal(t *testing.T) {
+	f := newServiceFixture(10)
+	f.service.SetCustomerResolver(&fakeCustomers{err: errors.New("tenant-manager unavailable")})
+	c := f.answeredCall(t)
+
+	if err := f.service.StartConversation(context.Background(), c.ID, "agent-1"); err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+
+	stored := f.store.stored(t, c.ID)
+	if stored.State != call.StateActive || stored.CustomerID != "" {
+		t.Errorf("Expected an active call with unknown customer, got state=%s customer=%q", stored.State, stored.CustomerID)
+	}
+}
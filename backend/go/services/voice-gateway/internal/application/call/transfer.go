@@ -0,0 +1,74 @@
+package call
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/metrics"
+	"voice-gateway/internal/domain/call"
+)
+
+// TransferExternal is the transfer type that dials an external number.
+const TransferExternal = "external"
+
+// TransferBridge is the subset of the Asterisk ARI client used to dial the
+// target of an external transfer and bridge it with the caller.
+type TransferBridge interface {
+	OriginateChannel(ctx context.Context, endpoint, callerID string) (string, error)
+	CreateBridge(ctx context.Context, bridgeType string) (string, error)
+	AddChannelToBridge(ctx context.Context, bridgeID, channelID string) error
+}
+
+var _ TransferBridge = (*asterisk.ARIClient)(nil)
+
+// SetTransfers enables external transfers, which dial the target through
+// endpointFormat (e.g. "PJSIP/%s@trunk", with the number in place of %s) and
+// bridge the new leg with the caller. Without it, external transfers only
+// mark the call as transferred.
+func (s *Service) SetTransfers(bridge TransferBridge, endpointFormat string) {
+	s.transferBridge = bridge
+	s.transferEndpoint = endpointFormat
+}
+
+// bridgeLeg originates the leg that transfers c to number, presenting
+// callerID, and bridges it with the caller's channel, reusing the call's
+// bridge if it has one. The leg is saved linked to c as its parent.
+func (s *Service) bridgeLeg(ctx context.Context, c *call.Call, number, callerID string) (*call.Call, error) {
+	leg := c.NewLeg(number)
+	leg.CallerNumber = callerID
+
+	channelID, err := s.transferBridge.OriginateChannel(ctx, fmt.Sprintf(s.transferEndpoint, number), leg.CallerNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to originate transfer leg: %w", err)
+	}
+	leg.ChannelID = channelID
+
+	if c.BridgeID == "" {
+		bridgeID, err := s.transferBridge.CreateBridge(ctx, "mixing")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transfer bridge: %w", err)
+		}
+		if err := s.transferBridge.AddChannelToBridge(ctx, bridgeID, c.ChannelID); err != nil {
+			return nil, fmt.Errorf("failed to add caller to transfer bridge: %w", err)
+		}
+		c.BridgeID = bridgeID
+	}
+	if err := s.transferBridge.AddChannelToBridge(ctx, c.BridgeID, leg.ChannelID); err != nil {
+		return nil, fmt.Errorf("failed to add transfer leg to bridge: %w", err)
+	}
+	leg.BridgeID = c.BridgeID
+
+	if err := s.callStateRepo.Save(ctx, leg); err != nil {
+		return nil, fmt.Errorf("failed to save transfer leg: %w", err)
+	}
+	metrics.ObserveCallState(leg)
+
+	if err := s.eventPublisher.PublishCallStarted(ctx, leg); err != nil {
+		s.logger.Error("failed to publish call started event", zap.Error(err))
+	}
+
+	return leg, nil
+}
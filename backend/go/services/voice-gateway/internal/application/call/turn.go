@@ -0,0 +1,441 @@
+package call
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	actions "github.com/serphona/serphona/backend/go/libs/platform-actions"
+
+	"voice-gateway/internal/adapter/agent"
+	"voice-gateway/internal/adapter/metrics"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tts"
+	conversationservice "voice-gateway/internal/application/conversation"
+	"voice-gateway/internal/domain/call"
+)
+
+// Provider components reported on provider.timeout events and in the
+// errors_total metric.
+const (
+	ComponentSTT      = "stt"
+	ComponentTTS      = "tts"
+	ComponentAgent    = "agent"
+	ComponentAsterisk = "asterisk"
+)
+
+// ProviderTimeouts bounds each provider call within a conversation turn.
+// A zero budget leaves the call bounded only by the turn context.
+type ProviderTimeouts struct {
+	STT   time.Duration
+	TTS   time.Duration
+	Agent time.Duration
+}
+
+// TurnResult is the outcome of a conversation turn.
+type TurnResult struct {
+	Transcript string
+	Response   string
+	Audio      io.Reader
+	Action     *actions.Action  // validated agent action to carry out, if any
+	Fallback   bool             // true when the response is the fallback message after a provider error
+	Ended      bool             // true when the turn reached the conversation's turn limit and the call is ending
+	Latency    call.TurnLatency // speech-to-speech latency breakdown of an answered turn
+}
+
+// providerTimeoutError reports a provider call that exceeded its budget.
+type providerTimeoutError struct {
+	component string
+	provider  string
+	budget    time.Duration
+}
+
+func (e *providerTimeoutError) Error() string {
+	return fmt.Sprintf("%s provider %s timed out after %s", e.component, e.provider, e.budget)
+}
+
+// SetConversation enables conversation turns through the agent orchestrator.
+func (s *Service) SetConversation(agentClient *agent.Client, timeouts ProviderTimeouts, fallbackMessage string) {
+	s.agentClient = agentClient
+	s.SetProviderTimeouts(timeouts, fallbackMessage)
+}
+
+// SetProviderTimeouts changes the provider time budgets and the fallback
+// message. Turns already running keep the previous budgets.
+func (s *Service) SetProviderTimeouts(timeouts ProviderTimeouts, fallbackMessage string) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.providerTimeouts = timeouts
+	s.fallbackMessage = fallbackMessage
+}
+
+// turnSettings returns the current provider time budgets and fallback message.
+func (s *Service) turnSettings() (ProviderTimeouts, string) {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+	return s.providerTimeouts, s.fallbackMessage
+}
+
+// SetConversationManager tracks conversations so a call is ended once its
+// conversation reaches maxTurns. The prompt is played before hanging up; when
+// empty, the call is ended right away.
+func (s *Service) SetConversationManager(manager *conversationservice.Manager, maxTurns int, prompt string) {
+	s.conversations = manager
+	s.maxTurns = maxTurns
+	s.maxTurnsPrompt = prompt
+}
+
+// ProcessTurn transcribes the caller audio, submits it to the agent and
+// synthesizes the reply. Each provider call runs within its own budget; when
+// one times out a provider.timeout event is published, when it fails an
+// error.provider_failure event, and the fallback message is synthesized
+// instead. The turn is cancelled if the caller hangs up.
+func (s *Service) ProcessTurn(ctx context.Context, c *call.Call, audio io.Reader, sttConfig stt.StreamConfig, ttsConfig tts.SynthesizeConfig) (*TurnResult, error) {
+	if s.agentClient == nil {
+		return nil, fmt.Errorf("conversation not enabled")
+	}
+	if !s.audioStreamingEnabled(ctx, c) {
+		return nil, call.ErrStreamingDisabled
+	}
+
+	sttProvider, err := s.GetSTTProvider(c.STTProvider)
+	if err != nil {
+		return nil, err
+	}
+	ttsProvider, err := s.GetTTSProvider(c.TTSProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	audio, stopJitter := s.dejitter(ctx, c, audio)
+	defer stopJitter()
+
+	audio, sttConfig, err = s.decodeForSTT(ctx, c, audio, sttConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	turnCtx, done := s.startTurn(ctx, c.ID)
+	defer done()
+	start := time.Now()
+
+	result := &TurnResult{}
+	latency := call.TurnLatency{TurnID: uuid.New(), Budget: s.turnLatencyBudget()}
+
+	result.Transcript, err = s.transcribe(turnCtx, c, sttProvider, audio, sttConfig)
+	latency.STT = time.Since(start)
+	if err != nil {
+		return s.turnFailed(ctx, turnCtx, c, ttsProvider, ttsConfig, result, err)
+	}
+	if result.Transcript == "" {
+		return result, nil
+	}
+
+	stage := time.Now()
+	reply, err := s.submitTurn(turnCtx, c, result.Transcript)
+	latency.LLM = time.Since(stage)
+	if err != nil {
+		return s.turnFailed(ctx, turnCtx, c, ttsProvider, ttsConfig, result, err)
+	}
+	result.Response = reply.AgentResponse
+	result.Action = s.checkAction(c, reply.Action)
+
+	stage = time.Now()
+	result.Audio, err = s.synthesize(turnCtx, c, ttsProvider, result.Response, ttsConfig)
+	latency.TTS = time.Since(stage)
+	if err != nil {
+		return s.turnFailed(ctx, turnCtx, c, ttsProvider, ttsConfig, result, err)
+	}
+
+	s.resetFailedTurns(c.ID)
+	latency.Total = time.Since(start)
+	result.Latency = latency
+	s.reportLatency(ctx, c, latency)
+	result.Ended = s.countTurn(ctx, c)
+	return result, nil
+}
+
+// countTurn records a completed turn. When the conversation reaches its turn
+// limit, the conversation is ended and the call hangs up after the max turns
+// prompt, with end_reason max_turns. It returns true if the call is ending.
+func (s *Service) countTurn(ctx context.Context, c *call.Call) bool {
+	if s.conversations == nil {
+		return false
+	}
+
+	if err := s.conversations.AddTurn(c.ConversationID); err != nil {
+		// Conversations started on another instance are not tracked here
+		s.logger.Debug("conversation turn not tracked",
+			zap.String("call_id", c.ID.String()),
+			zap.Error(err),
+		)
+		return false
+	}
+	if s.conversations.IsActive(c.ConversationID) {
+		return false
+	}
+
+	s.logger.Info("conversation reached max turns, ending call",
+		zap.String("call_id", c.ID.String()),
+		zap.String("conversation_id", c.ConversationID.String()),
+	)
+
+	s.endConversation(ctx, c, conversationservice.EndReasonMaxTurns)
+	if err := s.endAfterPrompt(ctx, c.ID, conversationservice.EndReasonMaxTurns); err != nil {
+		s.logger.Error("failed to end call at max turns",
+			zap.String("call_id", c.ID.String()),
+			zap.Error(err),
+		)
+	}
+	return true
+}
+
+// endAfterPrompt records why the call ends and hangs up once the max turns
+// prompt finishes playing, or right away without a prompt.
+func (s *Service) endAfterPrompt(ctx context.Context, callID uuid.UUID, reason string) error {
+	c, err := s.callStateRepo.Get(ctx, callID)
+	if err != nil {
+		return fmt.Errorf("failed to get call: %w", err)
+	}
+
+	c.Metadata["end_reason"] = reason
+	if err := s.callStateRepo.Save(ctx, c); err != nil {
+		return fmt.Errorf("failed to update call state: %w", err)
+	}
+
+	if s.maxTurnsPrompt != "" {
+		err := s.PlayAnnouncement(ctx, callID, s.maxTurnsPrompt, true)
+		if err == nil {
+			return nil
+		}
+		s.logger.Warn("failed to play max turns prompt, ending call", zap.Error(err))
+	}
+
+	return s.EndCall(ctx, callID)
+}
+
+// endConversation ends the call's tracked conversation, if any, publishing
+// conversation.ended.
+func (s *Service) endConversation(ctx context.Context, c *call.Call, reason string) {
+	if s.conversations == nil || c.ConversationID == uuid.Nil {
+		return
+	}
+
+	// Already ended, e.g. at the turn limit, or tracked by another instance
+	if err := s.conversations.EndConversation(ctx, c.ConversationID, reason); err != nil {
+		s.logger.Debug("conversation not ended",
+			zap.String("call_id", c.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// audioStreamingEnabled reports whether the call's tenant has audio streaming
+// enabled. If the flags can't be resolved, the resolver's defaults apply.
+func (s *Service) audioStreamingEnabled(ctx context.Context, c *call.Call) bool {
+	if s.featureFlags == nil {
+		return true
+	}
+
+	flags, err := s.featureFlags.GetFeatureFlags(ctx, c.TenantID)
+	if err != nil {
+		s.logger.Warn("failed to resolve tenant feature flags, using defaults",
+			zap.String("tenant_id", c.TenantID.String()),
+			zap.Error(err),
+		)
+	}
+	return flags.AudioStreaming
+}
+
+// turnFailed publishes provider errors and answers with the fallback message,
+// along with a transfer to a human once the call's failed turns exceed the
+// fallback retries. Other errors, including a hangup, are returned as is.
+func (s *Service) turnFailed(ctx, turnCtx context.Context, c *call.Call, ttsProvider tts.Provider, ttsConfig tts.SynthesizeConfig, result *TurnResult, err error) (*TurnResult, error) {
+	// A turn the caller hung up on is not held against the service
+	if turnCtx.Err() != nil {
+		return nil, err
+	}
+	metrics.IncTurnFailed()
+
+	if !s.reportProviderError(ctx, c, err) {
+		return nil, err
+	}
+
+	fallbackMessage := s.fallbackMessageFor(ctx, c)
+	if fallbackMessage == "" {
+		return nil, err
+	}
+
+	audio, fallbackErr := s.synthesize(turnCtx, c, ttsProvider, fallbackMessage, ttsConfig)
+	if fallbackErr != nil {
+		return nil, fmt.Errorf("failed to synthesize fallback message: %w", fallbackErr)
+	}
+
+	result.Response = fallbackMessage
+	result.Audio = audio
+	result.Action = s.escalation(c)
+	result.Fallback = true
+
+	return result, nil
+}
+
+// transcribe runs the caller audio through STT within the STT budget and joins the final results.
+func (s *Service) transcribe(ctx context.Context, c *call.Call, provider stt.Provider, audio io.Reader, config stt.StreamConfig) (string, error) {
+	timeouts, _ := s.turnSettings()
+	ctx, cancel := withBudget(ctx, timeouts.STT)
+	defer cancel()
+
+	start := time.Now()
+	results, err := provider.StreamTranscribe(ctx, audio, config)
+	if err != nil {
+		return "", s.providerError(ctx, c, ComponentSTT, provider.Name(), timeouts.STT, err)
+	}
+
+	var parts []string
+	for {
+		select {
+		case <-ctx.Done():
+			return "", s.providerError(ctx, c, ComponentSTT, provider.Name(), timeouts.STT, ctx.Err())
+		case result, ok := <-results:
+			if !ok {
+				metrics.ObserveSTTLatency(c.TenantID.String(), provider.Name(), time.Since(start))
+				return strings.Join(parts, " "), nil
+			}
+			if result.Error != nil {
+				return "", s.providerError(ctx, c, ComponentSTT, provider.Name(), timeouts.STT, result.Error)
+			}
+			if result.IsFinal && result.Transcript != "" {
+				parts = append(parts, result.Transcript)
+			}
+		}
+	}
+}
+
+// supportedActions are the agent actions a call can carry out.
+var supportedActions = map[actions.Type]bool{
+	actions.TypeTransfer: true,
+	actions.TypeEndCall:  true,
+}
+
+// checkAction returns the agent action if it follows the action schema and
+// the call can carry it out. Anything else is rejected, so the orchestrator
+// can never trigger an unsupported action; the reply is still spoken.
+func (s *Service) checkAction(c *call.Call, a *actions.Action) *actions.Action {
+	if a == nil {
+		return nil
+	}
+	err := a.Validate()
+	if err == nil && !supportedActions[a.Type] {
+		err = fmt.Errorf("%w: %s not supported on calls", actions.ErrUnknownType, a.Type)
+	}
+	if err != nil {
+		s.logger.Warn("rejected agent action",
+			zap.String("call_id", c.ID.String()),
+			zap.String("action", string(a.Type)),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return a
+}
+
+// submitTurn sends the transcript to the agent orchestrator within the agent budget.
+func (s *Service) submitTurn(ctx context.Context, c *call.Call, transcript string) (*agent.TurnResponse, error) {
+	timeouts, _ := s.turnSettings()
+	ctx, cancel := withBudget(ctx, timeouts.Agent)
+	defer cancel()
+
+	start := time.Now()
+	reply, err := s.agentClient.SubmitTurn(ctx, c.ConversationID, transcript, turnContext(c))
+	if err != nil {
+		return nil, s.providerError(ctx, c, ComponentAgent, "agent-orchestrator", timeouts.Agent, err)
+	}
+	metrics.ObserveLLMLatency(c.TenantID.String(), time.Since(start))
+	return reply, nil
+}
+
+// synthesize converts text to speech within the TTS budget.
+func (s *Service) synthesize(ctx context.Context, c *call.Call, provider tts.Provider, text string, config tts.SynthesizeConfig) (io.Reader, error) {
+	timeouts, _ := s.turnSettings()
+	ctx, cancel := withBudget(ctx, timeouts.TTS)
+	defer cancel()
+
+	start := time.Now()
+	audio, err := provider.Synthesize(ctx, text, config)
+	if err != nil {
+		return nil, s.providerError(ctx, c, ComponentTTS, provider.Name(), timeouts.TTS, err)
+	}
+	metrics.ObserveTTSLatency(c.TenantID.String(), provider.Name(), time.Since(start))
+	return audio, nil
+}
+
+// providerError counts the failure and wraps err as a timeout when the
+// provider context hit its deadline, or as a provider failure otherwise.
+func (s *Service) providerError(ctx context.Context, c *call.Call, component, provider string, budget time.Duration, err error) error {
+	metrics.IncError(c.TenantID.String(), component)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &providerTimeoutError{component: component, provider: provider, budget: budget}
+	}
+	return &providerFailureError{component: component, provider: provider, err: err}
+}
+
+// startTurn derives a turn context that is cancelled when the call ends.
+func (s *Service) startTurn(ctx context.Context, callID uuid.UUID) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	s.turnMu.Lock()
+	if s.turnCancels == nil {
+		s.turnCancels = make(map[uuid.UUID]context.CancelFunc)
+	}
+	s.turnCancels[callID] = cancel
+	s.turnMu.Unlock()
+
+	return ctx, func() {
+		s.turnMu.Lock()
+		delete(s.turnCancels, callID)
+		s.turnMu.Unlock()
+		cancel()
+	}
+}
+
+// cancelTurn cancels the in-flight turn of a call, e.g. when the caller hangs
+// up. It reports whether there was one.
+func (s *Service) cancelTurn(callID uuid.UUID) bool {
+	s.turnMu.Lock()
+	cancel, ok := s.turnCancels[callID]
+	s.turnMu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// abortTurn cancels the in-flight turn of a call and tells the orchestrator
+// to stop generating its reply, so a caller who hung up spends no more tokens.
+func (s *Service) abortTurn(ctx context.Context, c *call.Call) {
+	if !s.cancelTurn(c.ID) || s.agentClient == nil || c.ConversationID == uuid.Nil {
+		return
+	}
+	if err := s.agentClient.CancelTurn(ctx, c.ConversationID); err != nil {
+		s.logger.Warn("failed to cancel agent turn",
+			zap.String("call_id", c.ID.String()),
+			zap.String("conversation_id", c.ConversationID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// withBudget bounds ctx by the budget; a zero budget only adds cancellation.
+func withBudget(ctx context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
+	if budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, budget)
+}
@@ -0,0 +1,455 @@
+package call
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/agent"
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/adapter/tts"
+	conversationservice "voice-gateway/internal/application/conversation"
+	"voice-gateway/internal/domain/call"
+)
+
+const testFallback = "Desculpe, estou com dificuldades no momento."
+
+// slowSTT returns a transcript after delay, or gives up when ctx is done.
+type slowSTT struct {
+	delay time.Duration
+}
+
+func (p *slowSTT) StreamTranscribe(ctx context.Context, audioStream io.Reader, config stt.StreamConfig) (<-chan stt.Result, error) {
+	results := make(chan stt.Result, 1)
+	go func() {
+		defer close(results)
+		select {
+		case <-ctx.Done():
+		case <-time.After(p.delay):
+			results <- stt.Result{Transcript: "quero falar com o suporte", IsFinal: true}
+		}
+	}()
+	return results, nil
+}
+
+func (p *slowSTT) Close() error { return nil }
+func (p *slowSTT) Name() string { return "slow-stt" }
+
+// fakeTTS synthesizes text as its own bytes after delay.
+type fakeTTS struct {
+	delay time.Duration
+	texts []string
+}
+
+func (p *fakeTTS) Synthesize(ctx context.Context, text string, config tts.SynthesizeConfig) (io.Reader, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(p.delay):
+	}
+	p.texts = append(p.texts, text)
+	return strings.NewReader(text), nil
+}
+
+func (p *fakeTTS) StreamSynthesize(ctx context.Context, text string, config tts.SynthesizeConfig) (io.ReadCloser, error) {
+	audio, err := p.Synthesize(ctx, text, config)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(audio), nil
+}
+
+func (p *fakeTTS) Close() error { return nil }
+func (p *fakeTTS) Name() string { return "fake-tts" }
+
+// newAgentServer serves agent turns asking for a transfer after delay.
+func newAgentServer(t *testing.T, delay time.Duration) *agent.Client {
+	t.Helper()
+	return newActionServer(t, delay, `{"type":"transfer","transfer":{"transfer_type":"queue","target":"support","reason":"intent: speak_to_human"}}`)
+}
+
+// newActionServer serves agent turns with the raw action JSON after delay.
+func newActionServer(t *testing.T, delay time.Duration, action string) *agent.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(delay):
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"agent_response":"Vou transferir você.","action":%s}`, action)
+	}))
+	t.Cleanup(server.Close)
+
+	return agent.NewClient(server.URL, zap.NewNop())
+}
+
+func newTurnService(t *testing.T, producer sarama.SyncProducer, sttProvider stt.Provider, ttsProvider tts.Provider, agentClient *agent.Client) *Service {
+	t.Helper()
+
+	logger := zap.NewNop()
+	s := &Service{
+		eventPublisher: events.NewPublisherWithProducer(producer, "serphona", logger),
+		sttProviders:   map[string]stt.Provider{"slow": sttProvider},
+		ttsProviders:   map[string]tts.Provider{"fake": ttsProvider},
+		logger:         logger,
+	}
+	s.SetConversation(agentClient, ProviderTimeouts{
+		STT:   50 * time.Millisecond,
+		TTS:   50 * time.Millisecond,
+		Agent: 50 * time.Millisecond,
+	}, testFallback)
+
+	return s
+}
+
+func newTurnCall() *call.Call {
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	c.ConversationID = uuid.New()
+	c.STTProvider = "slow"
+	c.TTSProvider = "fake"
+	return c
+}
+
+// expectTimeoutEvent expects one provider.timeout event for the component.
+func expectTimeoutEvent(producer *mocks.SyncProducer, component string) {
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Topic != "serphona.provider.timeout" {
+			return fmt.Errorf("unexpected topic %s", msg.Topic)
+		}
+
+		value, _ := msg.Value.Encode()
+		var event events.ProviderTimeoutEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+		if event.Component != component {
+			return fmt.Errorf("expected timeout of %s, got %s", component, event.Component)
+		}
+		if event.BudgetMs != 50 {
+			return fmt.Errorf("expected budget of 50ms, got %d", event.BudgetMs)
+		}
+		return nil
+	})
+}
+
+func TestProcessTurn(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	ttsProvider := &fakeTTS{}
+	s := newTurnService(t, producer, &slowSTT{}, ttsProvider, newAgentServer(t, 0))
+
+	result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if err != nil {
+		t.Fatalf("ProcessTurn failed: %v", err)
+	}
+
+	if result.Fallback {
+		t.Error("Expected no fallback")
+	}
+	if result.Transcript != "quero falar com o suporte" {
+		t.Errorf("Unexpected transcript %q", result.Transcript)
+	}
+	if result.Response != "Vou transferir você." || result.Action == nil || result.Action.Transfer.Target != "support" {
+		t.Errorf("Unexpected agent reply %q (%+v)", result.Response, result.Action)
+	}
+	if audio, _ := io.ReadAll(result.Audio); string(audio) != result.Response {
+		t.Errorf("Expected synthesized reply, got %q", audio)
+	}
+}
+
+func TestProcessTurn_RejectsInvalidActions(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+	}{
+		{"unknown type", `{"type":"launch_missiles"}`},
+		{"missing params", `{"type":"transfer"}`},
+		{"malformed params", `{"type":"transfer","transfer":{"transfer_type":"sip","target":"x","reason":"r"}}`},
+		{"unsupported on calls", `{"type":"tool_call","tool_call":{"name":"lookup_order","arguments":{"id":"42"}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			producer := mocks.NewSyncProducer(t, nil)
+			defer producer.Close()
+
+			s := newTurnService(t, producer, &slowSTT{}, &fakeTTS{}, newActionServer(t, 0, tt.action))
+			result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+			if err != nil {
+				t.Fatalf("ProcessTurn failed: %v", err)
+			}
+			if result.Action != nil {
+				t.Errorf("Expected the action to be rejected, got %+v", result.Action)
+			}
+			if result.Response != "Vou transferir você." || result.Fallback {
+				t.Errorf("Expected the reply to be spoken anyway, got %q", result.Response)
+			}
+		})
+	}
+}
+
+func TestProcessTurn_TenantDisablesStreaming(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"settings":{"telephony":{"feature_flags":{"audio_streaming":false}}}}`))
+	}))
+	defer server.Close()
+
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	ttsProvider := &fakeTTS{}
+	s := newTurnService(t, producer, &slowSTT{}, ttsProvider, newAgentServer(t, 0))
+	defaults := tenant.FeatureFlags{CallRecording: true, TranscriptionStorage: true, AudioStreaming: true}
+	s.SetFeatureFlags(tenant.NewFeatureFlagResolver(tenant.NewClient(server.URL, zap.NewNop()), defaults, time.Minute, zap.NewNop()))
+
+	_, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if !errors.Is(err, call.ErrStreamingDisabled) {
+		t.Fatalf("Expected the tenant override to disable streaming over the global default, got %v", err)
+	}
+	if len(ttsProvider.texts) != 0 {
+		t.Errorf("Expected no audio to be synthesized, got %q", ttsProvider.texts)
+	}
+}
+
+func TestProcessTurn_SlowSTT(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	expectTimeoutEvent(producer, ComponentSTT)
+
+	ttsProvider := &fakeTTS{}
+	s := newTurnService(t, producer, &slowSTT{delay: time.Second}, ttsProvider, newAgentServer(t, 0))
+
+	start := time.Now()
+	result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if err != nil {
+		t.Fatalf("ProcessTurn should fall back on timeout: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Turn should be bounded by the STT budget, took %s", elapsed)
+	}
+	if !result.Fallback || result.Response != testFallback {
+		t.Errorf("Expected fallback message, got %q", result.Response)
+	}
+	if len(ttsProvider.texts) != 1 || ttsProvider.texts[0] != testFallback {
+		t.Errorf("Expected fallback to be synthesized, got %v", ttsProvider.texts)
+	}
+}
+
+func TestProcessTurn_SlowAgent(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	expectTimeoutEvent(producer, ComponentAgent)
+
+	ttsProvider := &fakeTTS{}
+	s := newTurnService(t, producer, &slowSTT{}, ttsProvider, newAgentServer(t, time.Second))
+
+	result, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if err != nil {
+		t.Fatalf("ProcessTurn should fall back on timeout: %v", err)
+	}
+
+	if !result.Fallback || result.Response != testFallback {
+		t.Errorf("Expected fallback message, got %q", result.Response)
+	}
+	if result.Transcript == "" {
+		t.Error("Expected transcript to be kept on agent timeout")
+	}
+}
+
+func TestProcessTurn_SlowTTS(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+	expectTimeoutEvent(producer, ComponentTTS)
+
+	s := newTurnService(t, producer, &slowSTT{}, &fakeTTS{delay: time.Second}, newAgentServer(t, 0))
+
+	_, err := s.ProcessTurn(context.Background(), newTurnCall(), strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+	if err == nil {
+		t.Fatal("Expected error when the fallback can't be synthesized either")
+	}
+
+	var timeoutErr *providerTimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.component != ComponentTTS {
+		t.Errorf("Expected TTS timeout error, got %v", err)
+	}
+}
+
+func TestProcessTurn_HangupCancels(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	ttsProvider := &fakeTTS{}
+	s := newTurnService(t, producer, &slowSTT{delay: time.Second}, ttsProvider, newAgentServer(t, 0))
+	s.providerTimeouts.STT = 5 * time.Second
+
+	c := newTurnCall()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := s.ProcessTurn(context.Background(), c, strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+		errCh <- err
+	}()
+
+	// Wait for the turn to start, then hang up
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.turnMu.Lock()
+		_, started := s.turnCancels[c.ID]
+		s.turnMu.Unlock()
+		if started || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	s.cancelTurn(c.ID)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected cancellation error, got %v", err)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Turn was not cancelled on hangup")
+	}
+
+	if len(ttsProvider.texts) != 0 {
+		t.Error("No fallback should be played to a caller who hung up")
+	}
+	if len(s.turnCancels) != 0 {
+		t.Error("Finished turn should be unregistered")
+	}
+}
+
+func TestProcessTurn_MaxTurnsEndsCall(t *testing.T) {
+	ctx := context.Background()
+	f := newServiceFixture(10)
+	f.service.sttProviders = map[string]stt.Provider{"slow": &slowSTT{}}
+	f.service.ttsProviders = map[string]tts.Provider{"fake": &fakeTTS{}}
+	f.service.SetConversation(newAgentServer(t, 0), ProviderTimeouts{}, testFallback)
+	f.service.SetConversationManager(conversationservice.NewManager(f.publisher, zap.NewNop()), 2, "sound:goodbye")
+
+	c := f.answeredCall(t)
+	if err := f.service.StartConversation(ctx, c.ID, "agent-1"); err != nil {
+		t.Fatalf("StartConversation failed: %v", err)
+	}
+	stored := f.store.stored(t, c.ID)
+	c = &stored
+	c.STTProvider = "slow"
+	c.TTSProvider = "fake"
+
+	for turn := 1; turn <= 2; turn++ {
+		result, err := f.service.ProcessTurn(ctx, c, strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+		if err != nil {
+			t.Fatalf("ProcessTurn %d failed: %v", turn, err)
+		}
+		if result.Ended != (turn == 2) {
+			t.Fatalf("Turn %d: expected Ended=%v, got %v", turn, turn == 2, result.Ended)
+		}
+	}
+
+	if len(f.ari.played) != 1 || f.ari.played[0] != "sound:goodbye" {
+		t.Fatalf("Expected the max turns prompt to play, got %v", f.ari.played)
+	}
+	if len(f.ari.hungUp) != 0 {
+		t.Fatal("Expected the call to stay up until the prompt finishes")
+	}
+
+	// Asterisk reports the prompt as finished
+	if err := f.service.HandlePlaybackFinished(ctx, c.ChannelID, "playback-1"); err != nil {
+		t.Fatalf("HandlePlaybackFinished failed: %v", err)
+	}
+
+	stored = f.store.stored(t, c.ID)
+	if stored.State != call.StateEnded {
+		t.Errorf("Expected the call to end, got state %s", stored.State)
+	}
+	if stored.Metadata["end_reason"] != conversationservice.EndReasonMaxTurns {
+		t.Errorf("Expected end_reason max_turns, got %v", stored.Metadata["end_reason"])
+	}
+	if len(f.ari.hungUp) != 1 || f.ari.hungUp[0] != c.ChannelID {
+		t.Errorf("Expected the caller's channel to be hung up, got %v", f.ari.hungUp)
+	}
+	f.publisher.expect(t, "conversation.ended", "call.ended")
+}
+
+func TestAbortTurn_CancelsAgentTurn(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	defer producer.Close()
+
+	// The agent answers turns slowly and records cancellations
+	cancelled := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cancel") {
+			cancelled <- r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			fmt.Fprint(w, `{"agent_response":"Olá"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	s := newTurnService(t, producer, &slowSTT{}, &fakeTTS{}, agent.NewClient(server.URL, zap.NewNop()))
+	s.providerTimeouts.Agent = 5 * time.Second
+
+	c := newTurnCall()
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := s.ProcessTurn(context.Background(), c, strings.NewReader("audio"), stt.StreamConfig{}, tts.SynthesizeConfig{})
+		errCh <- err
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.turnMu.Lock()
+		_, started := s.turnCancels[c.ID]
+		s.turnMu.Unlock()
+		if started || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	s.abortTurn(context.Background(), c)
+
+	select {
+	case path := <-cancelled:
+		if want := "/api/v1/conversations/" + c.ConversationID.String() + "/cancel"; path != want {
+			t.Errorf("Expected cancel on %s, got %s", want, path)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Agent turn was not cancelled")
+	}
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation error, got %v", err)
+	}
+
+	// Without a turn in flight there is nothing to cancel
+	s.abortTurn(context.Background(), c)
+	select {
+	case <-cancelled:
+		t.Error("Expected no cancel without a turn in flight")
+	default:
+	}
+}
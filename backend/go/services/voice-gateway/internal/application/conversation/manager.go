@@ -12,16 +12,30 @@ import (
 	"voice-gateway/internal/adapter/events"
 )
 
+// End reasons reported on conversation.ended events.
+const (
+	EndReasonEnded    = "ended"
+	EndReasonMaxTurns = "max_turns"
+	EndReasonShutdown = "shutdown"
+)
+
+// EventPublisher publishes conversation lifecycle events.
+type EventPublisher interface {
+	PublishConversationEnded(ctx context.Context, callID, tenantID, conversationID uuid.UUID, agentID string, turnCount int, reason string) error
+}
+
+var _ EventPublisher = (*events.Publisher)(nil)
+
 // Manager manages active conversations and their state.
 type Manager struct {
 	conversations  map[uuid.UUID]*Conversation
 	mu             sync.RWMutex
 	logger         *zap.Logger
-	eventPublisher *events.Publisher
+	eventPublisher EventPublisher
 }
 
 // NewManager creates a new conversation manager.
-func NewManager(eventPublisher *events.Publisher, logger *zap.Logger) *Manager {
+func NewManager(eventPublisher EventPublisher, logger *zap.Logger) *Manager {
 	return &Manager{
 		conversations:  make(map[uuid.UUID]*Conversation),
 		logger:         logger,
@@ -137,27 +151,65 @@ func (m *Manager) GetContext(conversationID uuid.UUID, key string) (interface{},
 	return value, nil
 }
 
-// EndConversation marks a conversation as ended and removes it.
-func (m *Manager) EndConversation(conversationID uuid.UUID) error {
+// EndConversation marks a conversation as ended, removes it and publishes
+// conversation.ended with the reason.
+func (m *Manager) EndConversation(ctx context.Context, conversationID uuid.UUID, reason string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	conv, ok := m.conversations[conversationID]
+	if ok {
+		conv.Active = false
+		delete(m.conversations, conversationID)
+	}
+	m.mu.Unlock()
+
 	if !ok {
 		return fmt.Errorf("conversation not found: %s", conversationID)
 	}
 
-	conv.Active = false
-	delete(m.conversations, conversationID)
-
 	m.logger.Info("conversation ended",
 		zap.String("conversation_id", conversationID.String()),
 		zap.Int("total_turns", conv.TurnCount),
+		zap.String("reason", reason),
 	)
 
+	m.publishEnded(ctx, conv, reason)
 	return nil
 }
 
+// Shutdown ends every conversation, publishing conversation.ended for each,
+// and clears the manager. It stops publishing when ctx is done and returns
+// the context error with the number of conversations left without an event.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	conversations := make([]*Conversation, 0, len(m.conversations))
+	for _, conv := range m.conversations {
+		conv.Active = false
+		conversations = append(conversations, conv)
+	}
+	m.conversations = make(map[uuid.UUID]*Conversation)
+	m.mu.Unlock()
+
+	for i, conv := range conversations {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%d conversations ended without an event: %w", len(conversations)-i, err)
+		}
+		m.publishEnded(ctx, conv, EndReasonShutdown)
+	}
+
+	m.logger.Info("conversations ended on shutdown", zap.Int("count", len(conversations)))
+	return nil
+}
+
+// publishEnded publishes conversation.ended; failures are only logged.
+func (m *Manager) publishEnded(ctx context.Context, conv *Conversation, reason string) {
+	if err := m.eventPublisher.PublishConversationEnded(ctx, conv.CallID, conv.TenantID, conv.ID, conv.AgentID, conv.TurnCount, reason); err != nil {
+		m.logger.Error("failed to publish conversation ended event",
+			zap.String("conversation_id", conv.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
 // IsActive checks if a conversation is still active.
 func (m *Manager) IsActive(conversationID uuid.UUID) bool {
 	m.mu.RLock()
@@ -0,0 +1,92 @@
+package conversation
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// endedEvent is a conversation.ended event captured by fakePublisher.
+type endedEvent struct {
+	conversationID uuid.UUID
+	reason         string
+}
+
+// fakePublisher captures conversation.ended events.
+type fakePublisher struct {
+	mu     sync.Mutex
+	events []endedEvent
+}
+
+func (f *fakePublisher) PublishConversationEnded(ctx context.Context, callID, tenantID, conversationID uuid.UUID, agentID string, turnCount int, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, endedEvent{conversationID: conversationID, reason: reason})
+	return nil
+}
+
+func TestManager_ShutdownEndsEveryConversation(t *testing.T) {
+	ctx := context.Background()
+	publisher := &fakePublisher{}
+	m := NewManager(publisher, zap.NewNop())
+
+	started := make(map[uuid.UUID]*Conversation)
+	for i := 0; i < 3; i++ {
+		conv, err := m.CreateConversation(ctx, uuid.New(), uuid.New(), "agent-1", 10)
+		if err != nil {
+			t.Fatalf("CreateConversation failed: %v", err)
+		}
+		started[conv.ID] = conv
+	}
+
+	if err := m.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if len(publisher.events) != len(started) {
+		t.Fatalf("Expected %d conversation.ended events, got %d", len(started), len(publisher.events))
+	}
+	for _, e := range publisher.events {
+		conv, ok := started[e.conversationID]
+		if !ok {
+			t.Fatalf("Unexpected event for conversation %s", e.conversationID)
+		}
+		if e.reason != EndReasonShutdown {
+			t.Errorf("Expected reason %q, got %q", EndReasonShutdown, e.reason)
+		}
+		if conv.Active {
+			t.Errorf("Expected conversation %s to be inactive", conv.ID)
+		}
+		delete(started, e.conversationID)
+	}
+	if active := m.ListActiveConversations(); len(active) != 0 {
+		t.Errorf("Expected no conversations left, got %d", len(active))
+	}
+}
+
+func TestManager_ShutdownRespectsDeadline(t *testing.T) {
+	publisher := &fakePublisher{}
+	m := NewManager(publisher, zap.NewNop())
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.CreateConversation(context.Background(), uuid.New(), uuid.New(), "agent-1", 10); err != nil {
+			t.Fatalf("CreateConversation failed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := m.Shutdown(ctx); err == nil {
+		t.Fatal("Expected Shutdown to report the expired context")
+	}
+	if len(publisher.events) != 0 {
+		t.Errorf("Expected no events after the deadline, got %d", len(publisher.events))
+	}
+	if active := m.ListActiveConversations(); len(active) != 0 {
+		t.Errorf("Expected the conversations to be cleared, got %d", len(active))
+	}
+}
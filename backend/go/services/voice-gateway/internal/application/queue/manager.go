@@ -0,0 +1,325 @@
+// Package queue provides the call hold-queue use cases.
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/queue"
+)
+
+// Dequeue reasons reported on call.dequeued events.
+const (
+	ReasonAnswered  = "answered"  // capacity freed up
+	ReasonTimeout   = "timeout"   // max wait exceeded
+	ReasonAbandoned = "abandoned" // caller hung up while waiting
+)
+
+// TimeoutHandler is called for each call that exceeds the max wait.
+type TimeoutHandler func(ctx context.Context, entry queue.Entry)
+
+// Manager keeps a hold queue per tenant. Queued channels wait in a per-tenant
+// ARI holding bridge with music on hold and hear their position periodically.
+type Manager struct {
+	asteriskClient *asterisk.ARIClient
+	eventPublisher *events.Publisher
+	logger         *zap.Logger
+
+	// Configuration
+	maxWait          time.Duration
+	announceInterval time.Duration
+	mohClass         string
+
+	queues    map[uuid.UUID]*queue.Queue
+	bridges   map[uuid.UUID]string // tenant ID -> holding bridge ID
+	onTimeout TimeoutHandler
+	mu        sync.Mutex
+}
+
+// NewManager creates a new hold-queue manager.
+func NewManager(
+	asteriskClient *asterisk.ARIClient,
+	eventPublisher *events.Publisher,
+	maxWait time.Duration,
+	announceInterval time.Duration,
+	mohClass string,
+	logger *zap.Logger,
+) *Manager {
+	return &Manager{
+		asteriskClient:   asteriskClient,
+		eventPublisher:   eventPublisher,
+		logger:           logger,
+		maxWait:          maxWait,
+		announceInterval: announceInterval,
+		mohClass:         mohClass,
+		queues:           make(map[uuid.UUID]*queue.Queue),
+		bridges:          make(map[uuid.UUID]string),
+	}
+}
+
+// SetTimeoutHandler sets the handler for calls that exceed the max wait.
+func (m *Manager) SetTimeoutHandler(handler TimeoutHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onTimeout = handler
+}
+
+// Enqueue places an answered call in its tenant queue and returns its position.
+func (m *Manager) Enqueue(ctx context.Context, c *call.Call) (int, error) {
+	bridgeID, err := m.holdingBridge(ctx, c.TenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := m.asteriskClient.AddChannelToBridge(ctx, bridgeID, c.ChannelID); err != nil {
+		return 0, fmt.Errorf("failed to add channel to holding bridge: %w", err)
+	}
+
+	m.mu.Lock()
+	q := m.queueFor(c.TenantID)
+	position := q.Enqueue(queue.Entry{
+		CallID:     c.ID,
+		TenantID:   c.TenantID,
+		ChannelID:  c.ChannelID,
+		EnqueuedAt: time.Now().UTC(),
+	})
+	m.mu.Unlock()
+
+	if err := m.eventPublisher.PublishCallQueued(ctx, c.ID, c.TenantID, position); err != nil {
+		m.logger.Error("failed to publish call queued event", zap.Error(err))
+	}
+
+	m.logger.Info("call queued",
+		zap.String("call_id", c.ID.String()),
+		zap.String("tenant_id", c.TenantID.String()),
+		zap.Int("position", position),
+	)
+
+	m.announcePosition(ctx, c.ChannelID, position)
+
+	return position, nil
+}
+
+// Dequeue takes the longest-waiting call across all tenants out of its queue.
+// It returns false if no call is waiting.
+func (m *Manager) Dequeue(ctx context.Context) (queue.Entry, bool) {
+	m.mu.Lock()
+	q := queue.Oldest(m.queues)
+	if q == nil {
+		m.mu.Unlock()
+		return queue.Entry{}, false
+	}
+	entry, _ := q.Dequeue()
+	m.mu.Unlock()
+
+	m.release(ctx, entry, ReasonAnswered)
+
+	return entry, true
+}
+
+// Remove takes a call out of its queue without routing it, e.g. on hangup.
+func (m *Manager) Remove(ctx context.Context, tenantID, callID uuid.UUID) bool {
+	m.mu.Lock()
+	q, ok := m.queues[tenantID]
+	if !ok {
+		m.mu.Unlock()
+		return false
+	}
+	entry, ok := q.Remove(callID)
+	m.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	m.release(ctx, entry, ReasonAbandoned)
+	return true
+}
+
+// Len returns the total number of queued calls across all tenants.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	total := 0
+	for _, q := range m.queues {
+		total += q.Len()
+	}
+	return total
+}
+
+// Position returns the 1-based position of a call in its tenant queue, or 0 if not queued.
+func (m *Manager) Position(tenantID, callID uuid.UUID) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	q, ok := m.queues[tenantID]
+	if !ok {
+		return 0
+	}
+	return q.Position(callID)
+}
+
+// Run expires calls past the max wait and announces positions until ctx is cancelled.
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.announceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.expire(ctx)
+			m.announce(ctx)
+		}
+	}
+}
+
+// expire removes calls that waited longer than the max wait and hands them to the timeout handler.
+func (m *Manager) expire(ctx context.Context) {
+	now := time.Now().UTC()
+
+	m.mu.Lock()
+	var expired []queue.Entry
+	for _, q := range m.queues {
+		expired = append(expired, q.Expire(now, m.maxWait)...)
+	}
+	onTimeout := m.onTimeout
+	m.mu.Unlock()
+
+	for _, entry := range expired {
+		m.release(ctx, entry, ReasonTimeout)
+
+		m.logger.Info("queued call exceeded max wait",
+			zap.String("call_id", entry.CallID.String()),
+			zap.Duration("wait", entry.Wait(now)),
+		)
+
+		if onTimeout != nil {
+			onTimeout(ctx, entry)
+		}
+	}
+}
+
+// announce plays the current position to every queued caller.
+func (m *Manager) announce(ctx context.Context) {
+	m.mu.Lock()
+	var entries []queue.Entry
+	for _, q := range m.queues {
+		entries = append(entries, q.Entries()...)
+	}
+	m.mu.Unlock()
+
+	for _, entry := range entries {
+		position := m.Position(entry.TenantID, entry.CallID)
+		if position == 0 {
+			continue
+		}
+		m.announcePosition(ctx, entry.ChannelID, position)
+	}
+}
+
+// announcePosition plays "you are number N in line" on a channel.
+func (m *Manager) announcePosition(ctx context.Context, channelID string, position int) {
+	media := fmt.Sprintf("sound:queue-thereare,number:%d", position)
+	if position == 1 {
+		media = "sound:queue-youarenext"
+	}
+
+	if _, err := m.asteriskClient.PlaybackStart(ctx, channelID, media); err != nil {
+		m.logger.Warn("failed to announce queue position",
+			zap.String("channel_id", channelID),
+			zap.Int("position", position),
+			zap.Error(err),
+		)
+	}
+}
+
+// release removes the channel from the holding bridge and publishes call.dequeued.
+func (m *Manager) release(ctx context.Context, entry queue.Entry, reason string) {
+	m.mu.Lock()
+	bridgeID := m.bridges[entry.TenantID]
+	m.mu.Unlock()
+
+	if bridgeID != "" && reason != ReasonAbandoned {
+		if err := m.asteriskClient.RemoveChannelFromBridge(ctx, bridgeID, entry.ChannelID); err != nil {
+			m.logger.Error("failed to remove channel from holding bridge",
+				zap.String("call_id", entry.CallID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	wait := entry.Wait(time.Now().UTC())
+	if err := m.eventPublisher.PublishCallDequeued(ctx, entry.CallID, entry.TenantID, wait, reason); err != nil {
+		m.logger.Error("failed to publish call dequeued event", zap.Error(err))
+	}
+
+	m.logger.Info("call dequeued",
+		zap.String("call_id", entry.CallID.String()),
+		zap.String("reason", reason),
+		zap.Duration("wait", wait),
+	)
+}
+
+// holdingBridge returns the tenant holding bridge, creating it with music on hold if needed.
+// The bridge is created without holding m.mu so a slow Asterisk doesn't stall
+// the queues of other tenants; if another caller created one meanwhile, that
+// one wins and ours is destroyed.
+func (m *Manager) holdingBridge(ctx context.Context, tenantID uuid.UUID) (string, error) {
+	m.mu.Lock()
+	bridgeID, ok := m.bridges[tenantID]
+	m.mu.Unlock()
+	if ok {
+		return bridgeID, nil
+	}
+
+	bridgeID, err := m.asteriskClient.CreateBridge(ctx, "holding")
+	if err != nil {
+		return "", fmt.Errorf("failed to create holding bridge: %w", err)
+	}
+
+	if err := m.asteriskClient.StartBridgeMOH(ctx, bridgeID, m.mohClass); err != nil {
+		m.logger.Warn("failed to start music on hold",
+			zap.String("bridge_id", bridgeID),
+			zap.Error(err),
+		)
+	}
+
+	m.mu.Lock()
+	existing, ok := m.bridges[tenantID]
+	if !ok {
+		m.bridges[tenantID] = bridgeID
+	}
+	m.mu.Unlock()
+
+	if ok {
+		if err := m.asteriskClient.DestroyBridge(ctx, bridgeID); err != nil {
+			m.logger.Warn("failed to destroy duplicate holding bridge",
+				zap.String("bridge_id", bridgeID),
+				zap.Error(err),
+			)
+		}
+		return existing, nil
+	}
+	return bridgeID, nil
+}
+
+// queueFor returns the tenant queue, creating it if needed. Callers must hold m.mu.
+func (m *Manager) queueFor(tenantID uuid.UUID) *queue.Queue {
+	q, ok := m.queues[tenantID]
+	if !ok {
+		q = queue.New(tenantID)
+		m.queues[tenantID] = q
+	}
+	return q
+}
@@ -0,0 +1,135 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/asterisk"
+)
+
+// fakeBridgeARI serves the ARI bridge endpoints. CreateBridge calls hold, if
+// set, with the number of the request before answering.
+type fakeBridgeARI struct {
+	hold      func(n int32)
+	requests  atomic.Int32
+	created   atomic.Int32
+	mu        sync.Mutex
+	destroyed []string
+}
+
+func (f *fakeBridgeARI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/bridges":
+		n := f.requests.Add(1)
+		if f.hold != nil {
+			f.hold(n)
+		}
+		id := f.created.Add(1)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{"id":"bridge-%d","bridge_type":"holding"}`, id)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/bridges/"):
+		f.mu.Lock()
+		f.destroyed = append(f.destroyed, strings.TrimPrefix(r.URL.Path, "/bridges/"))
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func newTestManager(t *testing.T, ari *fakeBridgeARI) *Manager {
+	t.Helper()
+
+	server := httptest.NewServer(ari)
+	t.Cleanup(server.Close)
+
+	logger := zap.NewNop()
+	return NewManager(asterisk.NewARIClient(server.URL, "user", "pass", "serphona", logger), nil, time.Minute, 30*time.Second, "default", logger)
+}
+
+func TestHoldingBridge_SlowCreateDoesNotBlockOtherTenants(t *testing.T) {
+	release := make(chan struct{})
+	ari := &fakeBridgeARI{hold: func(n int32) {
+		if n == 1 {
+			<-release
+		}
+	}}
+	m := newTestManager(t, ari)
+	ctx := context.Background()
+
+	// The first tenant's bridge is stuck in Asterisk
+	slow := make(chan error, 1)
+	go func() {
+		_, err := m.holdingBridge(ctx, uuid.New())
+		slow <- err
+	}()
+	for ari.requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.holdingBridge(ctx, uuid.New())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("holdingBridge failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a slow bridge of one tenant not to block another tenant")
+	}
+
+	close(release)
+	if err := <-slow; err != nil {
+		t.Fatalf("holdingBridge failed: %v", err)
+	}
+}
+
+func TestHoldingBridge_ConcurrentCreateKeepsOne(t *testing.T) {
+	// Both creations reach Asterisk before either is answered
+	allIn := make(chan struct{})
+	ari := &fakeBridgeARI{hold: func(n int32) {
+		if n == 2 {
+			close(allIn)
+		}
+		<-allIn
+	}}
+	m := newTestManager(t, ari)
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	results := make(chan string, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			bridgeID, err := m.holdingBridge(ctx, tenantID)
+			if err != nil {
+				t.Errorf("holdingBridge failed: %v", err)
+			}
+			results <- bridgeID
+		}()
+	}
+
+	first, second := <-results, <-results
+	if first != second {
+		t.Errorf("Expected both callers to get the same bridge, got %s and %s", first, second)
+	}
+	if len(ari.destroyed) != 1 || ari.destroyed[0] == first {
+		t.Errorf("Expected the losing bridge to be destroyed, got %v (kept %s)", ari.destroyed, first)
+	}
+	if got := m.bridges[tenantID]; got != first {
+		t.Errorf("Expected tenant bridge %s, got %s", first, got)
+	}
+}
@@ -0,0 +1,110 @@
+// Package routing provides agent selection use cases.
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/routing"
+)
+
+// Service selects the agent that should handle a call.
+type Service struct {
+	tenantClient   *tenant.Client
+	eventPublisher *events.Publisher
+	logger         *zap.Logger
+}
+
+// NewService creates a new routing service.
+func NewService(tenantClient *tenant.Client, eventPublisher *events.Publisher, logger *zap.Logger) *Service {
+	return &Service{
+		tenantClient:   tenantClient,
+		eventPublisher: eventPublisher,
+		logger:         logger,
+	}
+}
+
+// SelectAgent evaluates the tenant routing rules for a call and returns the
+// decision. A call to a DID assigned to an agent goes to that agent.
+func (s *Service) SelectAgent(ctx context.Context, c *call.Call, callerAttributes map[string]string) (*routing.Decision, error) {
+	settings, err := s.tenantClient.GetRoutingSettings(ctx, c.TenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get routing settings: %w", err)
+	}
+
+	decision := settings.Select(routing.Request{
+		DID:              c.CalleeNumber,
+		CallerNumber:     c.CallerNumber,
+		Time:             time.Now(),
+		CallerAttributes: callerAttributes,
+		Assignment:       s.didAssignment(ctx, c),
+	})
+
+	if decision.AgentID == "" {
+		return nil, fmt.Errorf("no agent available for tenant: %s", c.TenantID)
+	}
+
+	// Publish routing decision event
+	if err := s.eventPublisher.PublishRoutingDecision(ctx, c, decision); err != nil {
+		s.logger.Error("failed to publish routing decision event", zap.Error(err))
+	}
+
+	s.logger.Info("agent selected",
+		zap.String("call_id", c.ID.String()),
+		zap.String("agent_id", decision.AgentID),
+		zap.String("rule_id", decision.RuleID),
+		zap.String("flow_id", decision.FlowID),
+		zap.Bool("assigned", decision.Assigned),
+		zap.Bool("fallback", decision.Fallback),
+	)
+
+	return &decision, nil
+}
+
+// ResolveTenant returns the tenant holding the dialed DID, which owns the
+// incoming call. Numbers no tenant holds, or that are disabled, fail with
+// call.ErrUnknownDID.
+func (s *Service) ResolveTenant(ctx context.Context, did string) (uuid.UUID, error) {
+	info, err := s.tenantClient.LookupDID(ctx, did)
+	if errors.Is(err, tenant.ErrDIDNotFound) {
+		return uuid.Nil, fmt.Errorf("%w: %s", call.ErrUnknownDID, did)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to look up DID: %w", err)
+	}
+	if !info.Enabled {
+		return uuid.Nil, fmt.Errorf("%w: %s is disabled", call.ErrUnknownDID, did)
+	}
+	return info.TenantID, nil
+}
+
+// didAssignment returns the agent or flow the dialed DID is assigned to. A
+// failed lookup leaves the call to the routing rules rather than failing it.
+func (s *Service) didAssignment(ctx context.Context, c *call.Call) routing.Assignment {
+	if c.Direction != call.DirectionInbound || c.CalleeNumber == "" {
+		return routing.Assignment{}
+	}
+	info, err := s.tenantClient.LookupDID(ctx, c.CalleeNumber)
+	if err != nil {
+		if !errors.Is(err, tenant.ErrDIDNotFound) {
+			s.logger.Warn("failed to look up DID assignment, using routing rules",
+				zap.String("call_id", c.ID.String()),
+				zap.Error(err),
+			)
+		}
+		return routing.Assignment{}
+	}
+	// Only the tenant holding the number decides where its calls go
+	if info.TenantID != c.TenantID || !info.Enabled {
+		return routing.Assignment{}
+	}
+	return routing.Assignment{AgentID: info.AgentID, FlowID: info.FlowID, Label: info.Label}
+}
@@ -0,0 +1,93 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/call"
+)
+
+// newTenantManager fakes the routing settings of every tenant, whose rules
+// send all calls to the default agent, and the DID lookup.
+func newTenantManager(t *testing.T, dids map[string]tenant.DIDInfo) *tenant.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if number, ok := strings.CutPrefix(r.URL.Path, "/api/v1/telephony/dids/lookup/"); ok {
+			info, found := dids[number]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(info)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"settings": map[string]any{
+				"telephony": map[string]any{"default_agent_id": "default-agent"},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return tenant.NewClient(server.URL, zap.NewNop())
+}
+
+// newTestService returns a service expecting a routing decision event for
+// each of decisions calls.
+func newTestService(t *testing.T, dids map[string]tenant.DIDInfo, decisions int) *Service {
+	t.Helper()
+	producer := mocks.NewSyncProducer(t, nil)
+	t.Cleanup(func() { producer.Close() })
+	for i := 0; i < decisions; i++ {
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(*sarama.ProducerMessage) error { return nil })
+	}
+	publisher := events.NewPublisherWithProducer(producer, "serphona", zap.NewNop())
+	return NewService(newTenantManager(t, dids), publisher, zap.NewNop())
+}
+
+func TestSelectAgent_DIDAssignedAgent(t *testing.T) {
+	tenantID, otherID := uuid.New(), uuid.New()
+	dids := map[string]tenant.DIDInfo{
+		"+551140001000": {DID: "+551140001000", TenantID: tenantID, Enabled: true, AgentID: "sales-agent", FlowID: "qualify-lead"},
+		"+551140002000": {DID: "+551140002000", TenantID: tenantID, Enabled: true},
+		"+551140003000": {DID: "+551140003000", TenantID: otherID, Enabled: true, AgentID: "foreign-agent"},
+		"+551140004000": {DID: "+551140004000", TenantID: tenantID, Enabled: false, AgentID: "sales-agent"},
+	}
+
+	tests := []struct {
+		name     string
+		did      string
+		agent    string
+		flow     string
+		assigned bool
+	}{
+		{"assigned agent", "+551140001000", "sales-agent", "qualify-lead", true},
+		{"unassigned DID", "+551140002000", "default-agent", "", false},
+		{"another tenant's DID", "+551140003000", "default-agent", "", false},
+		{"disabled DID", "+551140004000", "default-agent", "", false},
+		{"unknown DID", "+551140009000", "default-agent", "", false},
+	}
+	svc := newTestService(t, dids, len(tests))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := call.NewCall(tenantID, call.DirectionInbound, "+5511999887766", tt.did)
+			decision, err := svc.SelectAgent(context.Background(), c, nil)
+			if err != nil {
+				t.Fatalf("SelectAgent failed: %v", err)
+			}
+			if decision.AgentID != tt.agent || decision.FlowID != tt.flow || decision.Assigned != tt.assigned {
+				t.Errorf("Expected %s running %q (assigned %v), got %+v", tt.agent, tt.flow, tt.assigned, decision)
+			}
+		})
+	}
+}
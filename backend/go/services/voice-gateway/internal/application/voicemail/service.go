@@ -0,0 +1,364 @@
+// Package voicemail provides voicemail recording and delivery use cases.
+package voicemail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+	"github.com/serphona/serphona/backend/go/libs/platform-entitlements"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/events"
+	"voice-gateway/internal/adapter/redis"
+	"voice-gateway/internal/adapter/storage"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/adapter/tenant"
+	"voice-gateway/internal/domain/voicemail"
+)
+
+// Config represents voicemail recording configuration.
+type Config struct {
+	Prompt      string        // ARI media URI played before recording
+	Format      string        // recording format, e.g. "wav"
+	MaxDuration time.Duration // maximum message length
+	MaxSilence  time.Duration // silence that ends the recording
+	Language    string        // transcription language
+	SampleRate  int           // recording sample rate in Hz
+}
+
+// FeatureFlagResolver resolves the feature flags of a call's tenant. It has the
+// same method set as the call service's FeatureFlagResolver, which can't be
+// imported here without a cycle.
+type FeatureFlagResolver interface {
+	GetFeatureFlags(ctx context.Context, tenantID uuid.UUID) (tenant.FeatureFlags, error)
+}
+
+// RedactionPolicyResolver resolves the PII redaction policy of a tenant.
+type RedactionPolicyResolver interface {
+	GetRedactionPolicy(ctx context.Context, tenantID uuid.UUID) (redact.Policy, error)
+}
+
+// Service records voicemails, stores the audio and publishes voicemail.left.
+type Service struct {
+	asteriskClient *asterisk.ARIClient
+	tenantClient   *tenant.Client
+	callStateRepo  *redis.CallStateRepository
+	voicemailRepo  *redis.VoicemailRepository
+	store          *storage.RegionalStore
+	eventPublisher *events.Publisher
+	sttProvider    stt.Provider            // optional; no transcript when nil
+	featureFlags   FeatureFlagResolver     // optional; see SetFeatureFlags
+	redaction      RedactionPolicyResolver // optional; see SetRedaction
+	redactor       *redact.Redactor
+	sealer         *redact.Sealer
+	config         Config
+	logger         *zap.Logger
+}
+
+// NewService creates a new voicemail service.
+func NewService(
+	asteriskClient *asterisk.ARIClient,
+	tenantClient *tenant.Client,
+	callStateRepo *redis.CallStateRepository,
+	voicemailRepo *redis.VoicemailRepository,
+	store *storage.RegionalStore,
+	eventPublisher *events.Publisher,
+	sttProvider stt.Provider,
+	config Config,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		asteriskClient: asteriskClient,
+		tenantClient:   tenantClient,
+		callStateRepo:  callStateRepo,
+		voicemailRepo:  voicemailRepo,
+		store:          store,
+		eventPublisher: eventPublisher,
+		sttProvider:    sttProvider,
+		config:         config,
+		logger:         logger,
+	}
+}
+
+// SetFeatureFlags makes recording and transcription also depend on the
+// tenant's resolved call recording and transcription storage flags.
+func (s *Service) SetFeatureFlags(resolver FeatureFlagResolver) {
+	s.featureFlags = resolver
+}
+
+// SetRedaction redacts transcripts by the tenant's policy before they are
+// stored. Originals are kept, encrypted with sealer, for tenants that ask for
+// it; sealer may be nil to never keep them.
+func (s *Service) SetRedaction(policies RedactionPolicyResolver, redactor *redact.Redactor, sealer *redact.Sealer) {
+	s.redaction = policies
+	s.redactor = redactor
+	s.sealer = sealer
+}
+
+// Start prompts the caller and starts recording a voicemail. It returns false
+// without recording when the tenant has recording disabled or its plan does not
+// include call recording.
+func (s *Service) Start(ctx context.Context, callID uuid.UUID, reason string) (bool, error) {
+	c, err := s.callStateRepo.Get(ctx, callID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	settings, err := s.tenantClient.GetRecordingSettings(ctx, c.TenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get recording settings: %w", err)
+	}
+
+	if !settings.RecordingEnabled || !entitlements.Can(settings.Plan, entitlements.FeatureCallRecording) {
+		s.logger.Info("voicemail skipped, recording not enabled for tenant",
+			zap.String("call_id", callID.String()),
+			zap.String("tenant_id", c.TenantID.String()),
+			zap.String("plan", string(settings.Plan)),
+		)
+		return false, nil
+	}
+	transcribe := settings.TranscriptionEnabled && entitlements.Can(settings.Plan, entitlements.FeatureTranscription)
+
+	if s.featureFlags != nil {
+		flags, err := s.featureFlags.GetFeatureFlags(ctx, c.TenantID)
+		if err != nil {
+			s.logger.Warn("failed to resolve tenant feature flags, using defaults",
+				zap.String("tenant_id", c.TenantID.String()),
+				zap.Error(err),
+			)
+		}
+		if !flags.CallRecording {
+			s.logger.Info("voicemail skipped, call recording disabled by feature flag",
+				zap.String("call_id", callID.String()),
+				zap.String("tenant_id", c.TenantID.String()),
+			)
+			return false, nil
+		}
+		transcribe = transcribe && flags.TranscriptionStorage
+	}
+
+	// ARI runs channel operations in order, so recording starts after the prompt
+	if s.config.Prompt != "" {
+		if _, err := s.asteriskClient.PlaybackStart(ctx, c.ChannelID, s.config.Prompt); err != nil {
+			s.logger.Warn("failed to play voicemail prompt", zap.Error(err))
+		}
+	}
+
+	name := voicemail.RecordingName(c.ID)
+	if _, err := s.asteriskClient.RecordChannel(ctx, c.ChannelID, asterisk.RecordOptions{
+		Name:        name,
+		Format:      s.config.Format,
+		MaxDuration: s.config.MaxDuration,
+		MaxSilence:  s.config.MaxSilence,
+		Beep:        true,
+		TerminateOn: "#",
+	}); err != nil {
+		return false, fmt.Errorf("failed to start voicemail recording: %w", err)
+	}
+
+	c.Metadata["voicemail_recording"] = name
+	c.Metadata["voicemail_reason"] = reason
+	c.Metadata["voicemail_transcribe"] = transcribe
+	c.Metadata["voicemail_residency"] = string(settings.DataResidency)
+	if err := s.callStateRepo.Save(ctx, c); err != nil {
+		return false, fmt.Errorf("failed to update call state: %w", err)
+	}
+
+	s.logger.Info("voicemail recording started",
+		zap.String("call_id", callID.String()),
+		zap.String("reason", reason),
+	)
+
+	return true, nil
+}
+
+// HandleRecordingFinished stores a finished voicemail recording and publishes
+// voicemail.left. It returns nil if the recording is not a voicemail.
+func (s *Service) HandleRecordingFinished(ctx context.Context, recording *asterisk.ARILiveRecording) (*voicemail.Voicemail, error) {
+	if !voicemail.IsVoicemailRecording(recording.Name) {
+		return nil, nil
+	}
+
+	c, err := s.callStateRepo.GetByChannelID(ctx, recording.ChannelID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call: %w", err)
+	}
+
+	if name, _ := c.Metadata["voicemail_recording"].(string); name != recording.Name {
+		return nil, nil
+	}
+
+	reason, _ := c.Metadata["voicemail_reason"].(string)
+	transcribe, _ := c.Metadata["voicemail_transcribe"].(bool)
+	region, _ := c.Metadata["voicemail_residency"].(string)
+
+	format := recording.Format
+	if format == "" {
+		format = s.config.Format
+	}
+
+	v := voicemail.NewVoicemail(c, reason, recording.Name, format, time.Duration(recording.Duration)*time.Second)
+
+	if err := s.storeRecording(ctx, v, residency.Region(region), transcribe); err != nil {
+		return nil, err
+	}
+
+	if err := s.voicemailRepo.Save(ctx, v); err != nil {
+		return nil, fmt.Errorf("failed to save voicemail: %w", err)
+	}
+
+	if err := s.eventPublisher.PublishVoicemailLeft(ctx, v); err != nil {
+		s.logger.Error("failed to publish voicemail left event", zap.Error(err))
+	}
+
+	s.logger.Info("voicemail left",
+		zap.String("voicemail_id", v.ID.String()),
+		zap.String("call_id", v.CallID.String()),
+		zap.String("recording_uri", v.RecordingURI),
+	)
+
+	return v, nil
+}
+
+// storeRecording moves the recording from Asterisk to the audio store of the
+// tenant's region and optionally transcribes it.
+func (s *Service) storeRecording(ctx context.Context, v *voicemail.Voicemail, region residency.Region, transcribe bool) error {
+	audio, err := s.asteriskClient.GetStoredRecordingFile(ctx, v.RecordingName)
+	if err != nil {
+		return fmt.Errorf("failed to download recording: %w", err)
+	}
+
+	uri, err := s.store.Save(ctx, region, v.StorageKey(), audio)
+	if err != nil {
+		return fmt.Errorf("failed to store recording: %w", err)
+	}
+	v.RecordingURI = uri
+
+	if transcribe && s.sttProvider != nil {
+		transcript, err := s.transcribe(ctx, audio, v.Format)
+		if err != nil {
+			// A missing transcript should not lose the message
+			s.logger.Warn("failed to transcribe voicemail",
+				zap.String("voicemail_id", v.ID.String()),
+				zap.Error(err),
+			)
+		} else {
+			v.Transcript = transcript
+			if err := s.redactTranscript(ctx, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The audio now lives in the store
+	if err := s.asteriskClient.DeleteStoredRecording(ctx, v.RecordingName); err != nil {
+		s.logger.Warn("failed to delete recording from asterisk",
+			zap.String("recording", v.RecordingName),
+			zap.Error(err),
+		)
+	}
+
+	return nil
+}
+
+// redactTranscript replaces the PII in the transcript by the tenant's policy.
+// Without a policy the transcript is dropped rather than stored unredacted.
+func (s *Service) redactTranscript(ctx context.Context, v *voicemail.Voicemail) error {
+	if s.redaction == nil {
+		return nil
+	}
+
+	policy, err := s.redaction.GetRedactionPolicy(ctx, v.TenantID)
+	if err != nil {
+		s.logger.Warn("failed to get redaction policy, dropping transcript",
+			zap.String("voicemail_id", v.ID.String()),
+			zap.Error(err),
+		)
+		v.Transcript = ""
+		return nil
+	}
+
+	original := v.Transcript
+	redacted, spans, err := s.redactor.Redact(ctx, policy, original)
+	if err != nil {
+		// The rules still applied; only the entity detector failed
+		s.logger.Warn("redaction incomplete",
+			zap.String("voicemail_id", v.ID.String()),
+			zap.Error(err),
+		)
+		if redacted == "" {
+			v.Transcript = ""
+			return nil
+		}
+	}
+	v.Transcript = redacted
+
+	if len(spans) > 0 && policy.KeepOriginal && s.sealer != nil {
+		sealed, err := s.sealer.Seal(v.TenantID.String(), original)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt original transcript: %w", err)
+		}
+		v.TranscriptOriginal = sealed
+	}
+
+	return nil
+}
+
+// OriginalTranscript returns the unredacted transcript of a voicemail to an
+// admin of its tenant.
+func (s *Service) OriginalTranscript(ctx context.Context, tenantID, voicemailID uuid.UUID, role string) (string, error) {
+	if !redact.ElevatedRoles[role] {
+		return "", voicemail.ErrOriginalForbidden
+	}
+
+	v, err := s.voicemailRepo.Get(ctx, voicemailID)
+	if err != nil {
+		return "", err
+	}
+	if v.TenantID != tenantID {
+		return "", voicemail.ErrNotFound
+	}
+	if v.TranscriptOriginal == "" || s.sealer == nil {
+		return "", voicemail.ErrNoOriginal
+	}
+
+	return s.sealer.Open(tenantID.String(), v.TranscriptOriginal)
+}
+
+// transcribe runs the recording through the STT provider and joins the final results.
+func (s *Service) transcribe(ctx context.Context, audio []byte, format string) (string, error) {
+	results, err := s.sttProvider.StreamTranscribe(ctx, bytes.NewReader(audio), stt.StreamConfig{
+		Language:        s.config.Language,
+		SampleRate:      s.config.SampleRate,
+		Encoding:        format,
+		MaxAlternatives: 1,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+	for result := range results {
+		if result.Error != nil {
+			return "", result.Error
+		}
+		if result.IsFinal && result.Transcript != "" {
+			parts = append(parts, result.Transcript)
+		}
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// ListVoicemails lists a page of voicemails for a tenant and returns the total count.
+func (s *Service) ListVoicemails(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*voicemail.Voicemail, int, error) {
+	return s.voicemailRepo.ListByTenant(ctx, tenantID, limit, offset)
+}
@@ -0,0 +1,293 @@
+package voicemail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/redact"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/residency"
+
+	"voice-gateway/internal/adapter/asterisk"
+	"voice-gateway/internal/adapter/storage"
+	"voice-gateway/internal/adapter/stt"
+	"voice-gateway/internal/domain/call"
+	"voice-gateway/internal/domain/voicemail"
+)
+
+var testAudio = []byte("RIFF....WAVEfmt voicemail-audio")
+
+// fakeSTT returns a fixed transcript split in two final results.
+type fakeSTT struct {
+	calls  int
+	err    error
+	finals []string // replaces the fixed transcript when set
+}
+
+func (f *fakeSTT) StreamTranscribe(ctx context.Context, audioStream io.Reader, config stt.StreamConfig) (<-chan stt.Result, error) {
+	f.calls++
+
+	data, _ := io.ReadAll(audioStream)
+	if string(data) != string(testAudio) {
+		return nil, errors.New("unexpected audio")
+	}
+
+	results := make(chan stt.Result, 3)
+	if f.err != nil {
+		results <- stt.Result{Error: f.err}
+	} else if f.finals != nil {
+		for _, final := range f.finals {
+			results <- stt.Result{Transcript: final, IsFinal: true}
+		}
+	} else {
+		results <- stt.Result{Transcript: "please call", IsFinal: false}
+		results <- stt.Result{Transcript: "please call me back", IsFinal: true}
+		results <- stt.Result{Transcript: "tomorrow", IsFinal: true}
+	}
+	close(results)
+	return results, nil
+}
+
+func (f *fakeSTT) Close() error { return nil }
+func (f *fakeSTT) Name() string { return "fake" }
+
+// fakeARI serves the ARI recording endpoints used by the voicemail flow.
+type fakeARI struct {
+	recordQuery map[string]string
+	deleted     []string
+}
+
+func (f *fakeARI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/record"):
+		f.recordQuery = map[string]string{}
+		for key := range r.URL.Query() {
+			f.recordQuery[key] = r.URL.Query().Get(key)
+		}
+		channelID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/channels/"), "/record")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"name":"`+f.recordQuery["name"]+`","format":"wav","state":"queued","target_uri":"channel:`+channelID+`"}`)
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/file"):
+		w.WriteHeader(http.StatusOK)
+		w.Write(testAudio)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/recordings/stored/"):
+		f.deleted = append(f.deleted, strings.TrimPrefix(r.URL.Path, "/recordings/stored/"))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestService(t *testing.T, ari *fakeARI, provider stt.Provider) (*Service, string) {
+	t.Helper()
+
+	server := httptest.NewServer(ari)
+	t.Cleanup(server.Close)
+
+	logger := zap.NewNop()
+	basePath := t.TempDir()
+
+	return &Service{
+		asteriskClient: asterisk.NewARIClient(server.URL, "user", "pass", "serphona", logger),
+		store:          storage.NewRegionalStore(storage.NewFileStore(basePath), nil),
+		sttProvider:    provider,
+		config: Config{
+			Format:      "wav",
+			MaxDuration: 2 * time.Minute,
+			MaxSilence:  10 * time.Second,
+			Language:    "pt-BR",
+			SampleRate:  8000,
+		},
+		logger: logger,
+	}, basePath
+}
+
+func TestRecordThenStore(t *testing.T) {
+	ctx := context.Background()
+	ari := &fakeARI{}
+	provider := &fakeSTT{}
+	s, basePath := newTestService(t, ari, provider)
+
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	c.ChannelID = "chan-1"
+
+	// Record
+	recording, err := s.asteriskClient.RecordChannel(ctx, c.ChannelID, asterisk.RecordOptions{
+		Name:        voicemail.RecordingName(c.ID),
+		Format:      s.config.Format,
+		MaxDuration: s.config.MaxDuration,
+		MaxSilence:  s.config.MaxSilence,
+		Beep:        true,
+		TerminateOn: "#",
+	})
+	if err != nil {
+		t.Fatalf("RecordChannel failed: %v", err)
+	}
+
+	expectedQuery := map[string]string{
+		"name":               voicemail.RecordingName(c.ID),
+		"format":             "wav",
+		"beep":               "true",
+		"terminateOn":        "#",
+		"maxDurationSeconds": "120",
+		"maxSilenceSeconds":  "10",
+		"ifExists":           "overwrite",
+	}
+	for key, expected := range expectedQuery {
+		if got := ari.recordQuery[key]; got != expected {
+			t.Errorf("Expected record param %s=%s, got %s", key, expected, got)
+		}
+	}
+	if recording.ChannelID() != c.ChannelID {
+		t.Errorf("Expected recording on channel %s, got %s", c.ChannelID, recording.ChannelID())
+	}
+
+	// Store
+	v := voicemail.NewVoicemail(c, voicemail.ReasonClosed, recording.Name, recording.Format, 5*time.Second)
+	if err := s.storeRecording(ctx, v, residency.RegionUnset, true); err != nil {
+		t.Fatalf("storeRecording failed: %v", err)
+	}
+
+	if !strings.HasPrefix(v.RecordingURI, "file://"+basePath) {
+		t.Errorf("Expected recording URI under %s, got %s", basePath, v.RecordingURI)
+	}
+
+	stored, err := os.ReadFile(strings.TrimPrefix(v.RecordingURI, "file://"))
+	if err != nil {
+		t.Fatalf("Stored audio not found: %v", err)
+	}
+	if string(stored) != string(testAudio) {
+		t.Error("Stored audio does not match the recording")
+	}
+
+	if v.Transcript != "please call me back tomorrow" {
+		t.Errorf("Expected transcript of final results, got %q", v.Transcript)
+	}
+
+	if len(ari.deleted) != 1 || ari.deleted[0] != recording.Name {
+		t.Errorf("Expected recording %s to be deleted from Asterisk, got %v", recording.Name, ari.deleted)
+	}
+}
+
+func TestStoreRecording_TranscriptionDisabled(t *testing.T) {
+	ctx := context.Background()
+	provider := &fakeSTT{}
+	s, _ := newTestService(t, &fakeARI{}, provider)
+
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	v := voicemail.NewVoicemail(c, voicemail.ReasonQueueTimeout, voicemail.RecordingName(c.ID), "wav", time.Second)
+
+	if err := s.storeRecording(ctx, v, residency.RegionUnset, false); err != nil {
+		t.Fatalf("storeRecording failed: %v", err)
+	}
+
+	if provider.calls != 0 {
+		t.Error("STT should not be called when transcription is disabled")
+	}
+	if v.Transcript != "" {
+		t.Errorf("Expected empty transcript, got %q", v.Transcript)
+	}
+	if v.RecordingURI == "" {
+		t.Error("Recording should be stored even without transcription")
+	}
+}
+
+func TestStoreRecording_TranscriptionFailureKeepsRecording(t *testing.T) {
+	ctx := context.Background()
+	provider := &fakeSTT{err: errors.New("stt unavailable")}
+	s, _ := newTestService(t, &fakeARI{}, provider)
+
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	v := voicemail.NewVoicemail(c, voicemail.ReasonAgentUnavailable, voicemail.RecordingName(c.ID), "wav", time.Second)
+
+	if err := s.storeRecording(ctx, v, residency.RegionUnset, true); err != nil {
+		t.Fatalf("storeRecording should not fail on STT errors: %v", err)
+	}
+
+	if v.RecordingURI == "" {
+		t.Error("Recording should be stored when transcription fails")
+	}
+	if v.Transcript != "" {
+		t.Errorf("Expected empty transcript, got %q", v.Transcript)
+	}
+}
+
+func TestStoreRecording_EUTenantWritesToEUStore(t *testing.T) {
+	ctx := context.Background()
+	s, defaultPath := newTestService(t, &fakeARI{}, nil)
+	euPath := t.TempDir()
+	s.store = storage.NewRegionalStore(storage.NewFileStore(defaultPath), map[residency.Region]*storage.FileStore{
+		residency.RegionEU: storage.NewFileStore(euPath),
+	})
+
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+4930123456", "+4930654321")
+	v := voicemail.NewVoicemail(c, voicemail.ReasonClosed, voicemail.RecordingName(c.ID), "wav", time.Second)
+	if err := s.storeRecording(ctx, v, residency.RegionEU, false); err != nil {
+		t.Fatalf("storeRecording failed: %v", err)
+	}
+	if !strings.HasPrefix(v.RecordingURI, "file://"+euPath) {
+		t.Errorf("Expected recording URI under the EU store %s, got %s", euPath, v.RecordingURI)
+	}
+
+	// A region without a store is refused rather than written elsewhere
+	v = voicemail.NewVoicemail(c, voicemail.ReasonClosed, voicemail.RecordingName(c.ID), "wav", time.Second)
+	if err := s.storeRecording(ctx, v, residency.RegionBR, false); !errors.Is(err, residency.ErrCrossBoundary) {
+		t.Errorf("Expected ErrCrossBoundary for a region without a store, got %v", err)
+	}
+	if entries, _ := os.ReadDir(defaultPath); len(entries) != 0 {
+		t.Errorf("Expected nothing in the default store, got %d entries", len(entries))
+	}
+}
+
+// fixedPolicy is the redaction policy of every tenant.
+type fixedPolicy redact.Policy
+
+func (p fixedPolicy) GetRedactionPolicy(ctx context.Context, tenantID uuid.UUID) (redact.Policy, error) {
+	return redact.Policy(p), nil
+}
+
+func TestStoreRecording_RedactsSpokenCardNumber(t *testing.T) {
+	ctx := context.Background()
+	provider := &fakeSTT{finals: []string{"my card is 4111 1111 1111 1111", "please call me back"}}
+	s, _ := newTestService(t, &fakeARI{}, provider)
+	sealer, err := redact.NewSealer("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	if err != nil {
+		t.Fatalf("NewSealer failed: %v", err)
+	}
+	s.SetRedaction(fixedPolicy{Enabled: true, KeepOriginal: true}, redact.New(nil), sealer)
+
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	v := voicemail.NewVoicemail(c, voicemail.ReasonClosed, voicemail.RecordingName(c.ID), "wav", 5*time.Second)
+	if err := s.storeRecording(ctx, v, residency.RegionUnset, true); err != nil {
+		t.Fatalf("storeRecording failed: %v", err)
+	}
+
+	// The message is still stored, with the card number redacted
+	if v.RecordingURI == "" {
+		t.Error("Recording should be stored")
+	}
+	if v.Transcript != "my card is [CARD_NUMBER] please call me back" {
+		t.Errorf("Expected the card number redacted, got %q", v.Transcript)
+	}
+	if strings.Contains(v.TranscriptOriginal, "4111") {
+		t.Error("The original transcript must be stored encrypted")
+	}
+	original, err := sealer.Open(c.TenantID.String(), v.TranscriptOriginal)
+	if err != nil || original != "my card is 4111 1111 1111 1111 please call me back" {
+		t.Errorf("Expected the original kept encrypted, got %q, %v", original, err)
+	}
+
+	// Only admins read originals
+	if _, err := s.OriginalTranscript(ctx, c.TenantID, v.ID, "user"); !errors.Is(err, voicemail.ErrOriginalForbidden) {
+		t.Errorf("Expected ErrOriginalForbidden, got %v", err)
+	}
+}
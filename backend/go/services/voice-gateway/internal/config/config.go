@@ -2,9 +2,11 @@
 package config
 
 import (
+	"os"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/serphona/serphona/backend/go/libs/platform-core/reload"
 )
 
 // Config represents the application configuration.
@@ -15,6 +17,8 @@ type Config struct {
 	LogLevel    string `envconfig:"LOG_LEVEL" default:"info"`
 
 	Server            ServerConfig
+	Auth              AuthConfig
+	LoadShed          LoadShedConfig
 	Asterisk          AsteriskConfig
 	Redis             RedisConfig
 	Kafka             KafkaConfig
@@ -22,7 +26,15 @@ type Config struct {
 	AgentOrchestrator AgentOrchestratorConfig
 	Audio             AudioConfig
 	Call              CallConfig
+	ProviderTimeout   ProviderTimeoutConfig
+	CallQuality       CallQualityConfig
+	Queue             QueueConfig
+	Voicemail         VoicemailConfig
+	CDR               CDRConfig
+	Redaction         RedactionConfig
+	Greeting          GreetingConfig
 	Metrics           MetricsConfig
+	Tracing           TracingConfig
 	HealthCheck       HealthCheckConfig
 	FeatureFlags      FeatureFlagsConfig
 }
@@ -36,6 +48,31 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration `envconfig:"SERVER_WRITE_TIMEOUT" default:"30s"`
 	IdleTimeout     time.Duration `envconfig:"SERVER_IDLE_TIMEOUT" default:"120s"`
 	ShutdownTimeout time.Duration `envconfig:"SERVER_SHUTDOWN_TIMEOUT" default:"30s"`
+	// Request bodies above MaxBodyBytes are refused with 413. BodyLimitRoutes
+	// overrides it by path prefix ("/prefix=bytes,...").
+	MaxBodyBytes    int64  `envconfig:"MAX_REQUEST_BODY_BYTES" default:"65536"`
+	BodyLimitRoutes string `envconfig:"MAX_REQUEST_BODY_ROUTES"`
+}
+
+// AuthConfig represents how management API tokens are verified: with the
+// secret shared with auth-gateway (HS256), its JWKS (RS256/ES256), or both
+// while migrating.
+type AuthConfig struct {
+	JWTSecret string `envconfig:"JWT_SECRET"`
+	JWKSURL   string `envconfig:"JWKS_URL"` // e.g. http://auth-gateway:8080/.well-known/jwks.json
+
+	// Audience tokens must name in their aud claim; empty accepts any
+	Audience string `envconfig:"JWT_AUDIENCE"`
+}
+
+// LoadShedConfig represents the overload ceiling of the management API. Past
+// MaxInFlight requests, new non-critical requests wait up to MaxQueueWait for
+// a slot and are then refused with 503. Asterisk webhooks and the control of
+// calls in progress are never refused.
+type LoadShedConfig struct {
+	MaxInFlight  int           `envconfig:"LOAD_SHED_MAX_IN_FLIGHT" default:"500"` // 0 disables shedding
+	MaxQueueWait time.Duration `envconfig:"LOAD_SHED_MAX_QUEUE_WAIT" default:"100ms"`
+	RetryAfter   time.Duration `envconfig:"LOAD_SHED_RETRY_AFTER" default:"2s"`
 }
 
 // AsteriskConfig represents Asterisk connection configuration.
@@ -46,6 +83,13 @@ type AsteriskConfig struct {
 	ARIPassword string `envconfig:"ASTERISK_ARI_PASSWORD" required:"true"`
 	ARIAppName  string `envconfig:"ASTERISK_ARI_APP_NAME" default:"serphona"`
 
+	// Shared secret the ARI webhooks must carry in X-Webhook-Secret. The
+	// webhooks are internal-only and rejected without it.
+	WebhookSecret string `envconfig:"ASTERISK_WEBHOOK_SECRET" required:"true"`
+
+	// Endpoint external transfers dial, with the number in place of %s
+	TransferEndpoint string `envconfig:"ASTERISK_TRANSFER_ENDPOINT" default:"PJSIP/%s@trunk"`
+
 	// AMI Configuration (optional, for fallback)
 	AMIHost     string `envconfig:"ASTERISK_AMI_HOST"`
 	AMIPort     int    `envconfig:"ASTERISK_AMI_PORT" default:"5038"`
@@ -55,10 +99,16 @@ type AsteriskConfig struct {
 
 // RedisConfig represents Redis configuration.
 type RedisConfig struct {
-	URL          string        `envconfig:"REDIS_URL" default:"redis://localhost:6379"`
-	Password     string        `envconfig:"REDIS_PASSWORD"`
-	DB           int           `envconfig:"REDIS_DB" default:"0"`
-	CallStateTTL time.Duration `envconfig:"REDIS_CALL_STATE_TTL" default:"1h"`
+	URL           string        `envconfig:"REDIS_URL" default:"redis://localhost:6379"`
+	Password      string        `envconfig:"REDIS_PASSWORD"`
+	DB            int           `envconfig:"REDIS_DB" default:"0"`
+	CallStateTTL  time.Duration `envconfig:"REDIS_CALL_STATE_TTL" default:"1h"`
+	EventDedupTTL time.Duration `envconfig:"REDIS_EVENT_DEDUP_TTL" default:"5m"`
+
+	// Circuit breaker and in-memory fallback for call state during Redis outages
+	CallStateFallback       bool          `envconfig:"REDIS_CALL_STATE_FALLBACK" default:"true"`
+	BreakerFailureThreshold int           `envconfig:"REDIS_BREAKER_FAILURE_THRESHOLD" default:"5"`
+	BreakerCooldown         time.Duration `envconfig:"REDIS_BREAKER_COOLDOWN" default:"10s"`
 }
 
 // KafkaConfig represents Kafka configuration.
@@ -67,18 +117,43 @@ type KafkaConfig struct {
 	TopicPrefix       string   `envconfig:"KAFKA_TOPIC_PREFIX" default:"serphona"`
 	GroupID           string   `envconfig:"KAFKA_GROUP_ID" default:"voice-gateway"`
 	EnableIdempotence bool     `envconfig:"KAFKA_ENABLE_IDEMPOTENCE" default:"true"`
+	Compression       string   `envconfig:"KAFKA_COMPRESSION" default:"snappy"`
+	MaxMessageBytes   int      `envconfig:"KAFKA_MAX_MESSAGE_BYTES" default:"1000000"`
+
+	// Shared topics for related events, e.g. "call.*:calls,error.*:errors";
+	// event types in no group get their own <prefix>.<type> topic
+	TopicGroups map[string]string `envconfig:"KAFKA_TOPIC_GROUPS"`
 }
 
 // TenantManagerConfig represents tenant-manager client configuration.
 type TenantManagerConfig struct {
 	URL     string        `envconfig:"TENANT_MANAGER_URL" required:"true"`
 	Timeout time.Duration `envconfig:"TENANT_MANAGER_TIMEOUT" default:"10s"`
+
+	// How long per-tenant feature flag overrides are cached
+	FeatureFlagsCacheTTL time.Duration `envconfig:"TENANT_FEATURE_FLAGS_CACHE_TTL" default:"1m"`
+
+	// How long agent configs are cached; updates evict them sooner
+	AgentConfigCacheTTL time.Duration `envconfig:"TENANT_AGENT_CONFIG_CACHE_TTL" default:"5m"`
+
+	// Reject management requests of suspended or deleted tenants. Statuses
+	// are cached for StatusCacheTTL; StatusFailOpen lets requests through
+	// when the status can't be looked up.
+	StatusCheck    bool          `envconfig:"TENANT_STATUS_CHECK_ENABLED" default:"false"`
+	StatusCacheTTL time.Duration `envconfig:"TENANT_STATUS_CACHE_TTL" default:"30s"`
+	StatusFailOpen bool          `envconfig:"TENANT_STATUS_FAIL_OPEN" default:"true"`
 }
 
 // AgentOrchestratorConfig represents agent-orchestrator client configuration.
 type AgentOrchestratorConfig struct {
 	URL     string        `envconfig:"AGENT_ORCHESTRATOR_URL" required:"true"`
 	Timeout time.Duration `envconfig:"AGENT_ORCHESTRATOR_TIMEOUT" default:"30s"`
+
+	// Client credentials exchanged at auth-gateway for the service token
+	// agent-orchestrator requires
+	AuthURL      string `envconfig:"AUTH_GATEWAY_URL"`
+	ClientID     string `envconfig:"SERVICE_CLIENT_ID"`
+	ClientSecret string `envconfig:"SERVICE_CLIENT_SECRET"`
 }
 
 // AudioConfig represents audio processing configuration.
@@ -87,6 +162,18 @@ type AudioConfig struct {
 	Channels   int    `envconfig:"AUDIO_CHANNELS" default:"1"`
 	Format     string `envconfig:"AUDIO_FORMAT" default:"pcm"`
 	BufferSize int    `envconfig:"AUDIO_BUFFER_SIZE" default:"8192"`
+
+	// Caller audio is cut into frames of FrameSize bytes (20 ms of G.711 by
+	// default) and played to STT through a jitter buffer, one frame per
+	// FrameDuration. JitterBufferDepth frames are held back to absorb
+	// network jitter, adding as much latency; tenants may override it, and
+	// 0 disables the jitter buffer.
+	FrameSize         int           `envconfig:"AUDIO_FRAME_SIZE" default:"160"`
+	FrameDuration     time.Duration `envconfig:"AUDIO_FRAME_DURATION" default:"20ms"`
+	JitterBufferDepth int           `envconfig:"AUDIO_JITTER_BUFFER_DEPTH" default:"3"`
+
+	// Codec assumed for calls whose channel codec can't be detected
+	DefaultCodec string `envconfig:"AUDIO_DEFAULT_CODEC" default:"ulaw"`
 }
 
 // CallConfig represents call handling configuration.
@@ -95,6 +182,77 @@ type CallConfig struct {
 	CallTimeout          time.Duration `envconfig:"CALL_TIMEOUT" default:"30m"`
 	SilenceTimeout       time.Duration `envconfig:"SILENCE_TIMEOUT" default:"5s"`
 	MaxConversationTurns int           `envconfig:"MAX_CONVERSATION_TURNS" default:"100"`
+	MaxTurnsPrompt       string        `envconfig:"MAX_CONVERSATION_TURNS_PROMPT" default:"sound:goodbye"` // played before hanging up at the turn limit
+	NumberRegion         string        `envconfig:"CALL_NUMBER_REGION" default:"BR"`                       // country of numbers the trunk sends without a country code
+	TurnLatencyBudget    time.Duration `envconfig:"TURN_LATENCY_BUDGET" default:"1500ms"`                  // speech-to-speech turns slower than this are flagged
+}
+
+// ProviderTimeoutConfig represents the time budget of each provider call within
+// a conversation turn.
+type ProviderTimeoutConfig struct {
+	STT             time.Duration `envconfig:"PROVIDER_TIMEOUT_STT" default:"10s"`
+	TTS             time.Duration `envconfig:"PROVIDER_TIMEOUT_TTS" default:"5s"`
+	Agent           time.Duration `envconfig:"PROVIDER_TIMEOUT_AGENT" default:"8s"`
+	FallbackMessage string        `envconfig:"PROVIDER_FALLBACK_MESSAGE" default:"Desculpe, estou com dificuldades no momento. Pode repetir, por favor?"`
+
+	// Consecutive failed turns answered with the fallback message before the
+	// call is transferred to a human; an empty queue means the channel's
+	// default escalation destination
+	FallbackRetries       int    `envconfig:"PROVIDER_FALLBACK_RETRIES" default:"2"`
+	FallbackTransferQueue string `envconfig:"PROVIDER_FALLBACK_TRANSFER_QUEUE"`
+}
+
+// CallQualityConfig represents call quality thresholds.
+type CallQualityConfig struct {
+	MinMOS           float64 `envconfig:"CALL_QUALITY_MIN_MOS" default:"3.5"`
+	MaxJitterMs      float64 `envconfig:"CALL_QUALITY_MAX_JITTER_MS" default:"30"`
+	MaxPacketLossPct float64 `envconfig:"CALL_QUALITY_MAX_PACKET_LOSS_PCT" default:"5"`
+	MaxLatencyMs     float64 `envconfig:"CALL_QUALITY_MAX_LATENCY_MS" default:"300"`
+}
+
+// QueueConfig represents hold-queue configuration.
+type QueueConfig struct {
+	Enabled          bool          `envconfig:"QUEUE_ENABLED" default:"true"`
+	MaxWait          time.Duration `envconfig:"QUEUE_MAX_WAIT" default:"5m"`
+	AnnounceInterval time.Duration `envconfig:"QUEUE_ANNOUNCE_INTERVAL" default:"30s"`
+	MOHClass         string        `envconfig:"QUEUE_MOH_CLASS" default:"default"`
+}
+
+// VoicemailConfig represents voicemail configuration.
+type VoicemailConfig struct {
+	Prompt      string        `envconfig:"VOICEMAIL_PROMPT" default:"sound:vm-intro"`
+	Format      string        `envconfig:"VOICEMAIL_FORMAT" default:"wav"`
+	MaxDuration time.Duration `envconfig:"VOICEMAIL_MAX_DURATION" default:"2m"`
+	MaxSilence  time.Duration `envconfig:"VOICEMAIL_MAX_SILENCE" default:"10s"`
+	Language    string        `envconfig:"VOICEMAIL_TRANSCRIPT_LANGUAGE" default:"pt-BR"`
+	StoragePath string        `envconfig:"VOICEMAIL_STORAGE_PATH" default:"/var/lib/voice-gateway/voicemail"`
+	// Storage of tenants pinned to a data-residency region, e.g.
+	// "eu:/mnt/eu/voicemail,br:/mnt/br/voicemail"
+	RegionStoragePaths map[string]string `envconfig:"VOICEMAIL_REGION_STORAGE_PATHS"`
+	Retention          time.Duration     `envconfig:"VOICEMAIL_RETENTION" default:"720h"`
+}
+
+// CDRConfig represents call detail record generation.
+type CDRConfig struct {
+	Enabled bool `envconfig:"CDR_ENABLED" default:"true"`
+	// Billing rounding rule ("minimum/increment" in seconds) of tenants that
+	// don't set one, e.g. "6/6" per 6 seconds or "60/60" per minute
+	DefaultRounding string        `envconfig:"CDR_DEFAULT_ROUNDING" default:"6/6"`
+	Retention       time.Duration `envconfig:"CDR_RETENTION" default:"8760h"`
+}
+
+// RedactionConfig represents PII redaction of stored transcripts.
+type RedactionConfig struct {
+	// Base64 32-byte key encrypting the originals tenants keep; originals are
+	// not kept without it
+	Key string `envconfig:"REDACTION_KEY"`
+}
+
+// GreetingConfig represents the agent greeting played on answer.
+type GreetingConfig struct {
+	Enabled      bool   `envconfig:"GREETING_ENABLED" default:"true"`
+	MediaBaseURL string `envconfig:"GREETING_MEDIA_BASE_URL" default:"http://voice-gateway:8080"` // gateway address reachable from Asterisk
+	CacheSize    int    `envconfig:"TTS_CACHE_SIZE" default:"256"`                                // synthesized phrases kept in memory
 }
 
 // MetricsConfig represents metrics configuration.
@@ -103,23 +261,44 @@ type MetricsConfig struct {
 	Path string `envconfig:"METRICS_PATH" default:"/metrics"`
 }
 
+// TracingConfig represents distributed tracing configuration.
+type TracingConfig struct {
+	Enabled  bool    `envconfig:"TRACING_ENABLED" default:"true"`
+	Endpoint string  `envconfig:"TRACING_ENDPOINT" default:"http://tempo:4317"`
+	Sampler  float64 `envconfig:"TRACING_SAMPLER" default:"1.0"` // fraction of new traces sampled
+}
+
 // HealthCheckConfig represents health check configuration.
 type HealthCheckConfig struct {
 	Interval time.Duration `envconfig:"HEALTH_CHECK_INTERVAL" default:"30s"`
 }
 
-// FeatureFlagsConfig represents feature flags.
+// FeatureFlagsConfig represents feature flags. Call recording, transcription
+// storage and audio streaming are global defaults that tenant settings can
+// override.
 type FeatureFlagsConfig struct {
 	EnableCallRecording        bool `envconfig:"ENABLE_CALL_RECORDING" default:"true"`
 	EnableTranscriptionStorage bool `envconfig:"ENABLE_TRANSCRIPTION_STORAGE" default:"true"`
 	EnableAudioStreaming       bool `envconfig:"ENABLE_AUDIO_STREAMING" default:"true"`
+	EnableAMIFallback          bool `envconfig:"ENABLE_AMI_FALLBACK" default:"false"`
 }
 
-// Load loads the configuration from environment variables.
+// Load loads the configuration from environment variables. When CONFIG_FILE
+// names a KEY=VALUE file, its values override the environment; the file is
+// read again on every reload.
 func Load() (*Config, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := reload.LoadEnvFile(path); err != nil {
+			return nil, err
+		}
+	}
+
 	var cfg Config
 	if err := envconfig.Process("", &cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/reload"
+	"go.uber.org/zap"
+)
+
+// RestartRequired returns the settings that differ in next but only take
+// effect after a restart: listeners and connections opened at startup.
+func (c *Config) RestartRequired(next *Config) []string {
+	return reload.Changed([]reload.Setting{
+		{Name: "SERVER_HOST", Old: c.Server.Host, New: next.Server.Host},
+		{Name: "SERVER_PORT", Old: strconv.Itoa(c.Server.Port), New: strconv.Itoa(next.Server.Port)},
+		{Name: "SERVER_GRPC_PORT", Old: strconv.Itoa(c.Server.GRPCPort), New: strconv.Itoa(next.Server.GRPCPort)},
+		{Name: "MAX_REQUEST_BODY_BYTES", Old: strconv.FormatInt(c.Server.MaxBodyBytes, 10), New: strconv.FormatInt(next.Server.MaxBodyBytes, 10)},
+		{Name: "MAX_REQUEST_BODY_ROUTES", Old: c.Server.BodyLimitRoutes, New: next.Server.BodyLimitRoutes},
+		{Name: "METRICS_PORT", Old: strconv.Itoa(c.Metrics.Port), New: strconv.Itoa(next.Metrics.Port)},
+		{Name: "METRICS_PATH", Old: c.Metrics.Path, New: next.Metrics.Path},
+		{Name: "ASTERISK_ARI_URL", Old: c.Asterisk.ARIURL, New: next.Asterisk.ARIURL},
+		{Name: "ASTERISK_ARI_USERNAME", Old: c.Asterisk.ARIUsername, New: next.Asterisk.ARIUsername},
+		{Name: "ASTERISK_ARI_PASSWORD", Old: c.Asterisk.ARIPassword, New: next.Asterisk.ARIPassword},
+		{Name: "ASTERISK_ARI_APP_NAME", Old: c.Asterisk.ARIAppName, New: next.Asterisk.ARIAppName},
+		{Name: "REDIS_URL", Old: c.Redis.URL, New: next.Redis.URL},
+		{Name: "REDIS_PASSWORD", Old: c.Redis.Password, New: next.Redis.Password},
+		{Name: "REDIS_DB", Old: strconv.Itoa(c.Redis.DB), New: strconv.Itoa(next.Redis.DB)},
+		{Name: "KAFKA_BROKERS", Old: strings.Join(c.Kafka.Brokers, ","), New: strings.Join(next.Kafka.Brokers, ",")},
+		{Name: "KAFKA_TOPIC_PREFIX", Old: c.Kafka.TopicPrefix, New: next.Kafka.TopicPrefix},
+		{Name: "ENVIRONMENT", Old: c.Environment, New: next.Environment},
+	})
+}
+
+// Reloader re-reads the configuration on SIGHUP and applies the settings that
+// are safe to change while calls are up: the log level here, plus whatever
+// the components registered with OnReload take (feature flags, call limits,
+// provider endpoints and timeouts). Settings that need a restart are logged
+// and left as they are.
+type Reloader struct {
+	mu      sync.Mutex
+	running *Config // as loaded at startup; restart-only settings never change
+	level   zap.AtomicLevel
+	apply   []func(cfg *Config)
+	logger  *zap.Logger
+}
+
+// NewReloader creates a reloader for the running configuration. level is the
+// logger's level, changed in place on reload.
+func NewReloader(running *Config, level zap.AtomicLevel, logger *zap.Logger) *Reloader {
+	return &Reloader{
+		running: running,
+		level:   level,
+		logger:  logger,
+	}
+}
+
+// OnReload registers a function applying the reloaded configuration.
+func (r *Reloader) OnReload(apply func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apply = append(r.apply, apply)
+}
+
+// Watch reloads the configuration on every SIGHUP until ctx is done.
+func (r *Reloader) Watch(ctx context.Context) {
+	reload.Watch(ctx, r.Reload, func(err error) {
+		r.logger.Error("configuration reload failed, keeping the current configuration", zap.Error(err))
+	})
+}
+
+// Reload loads the configuration again and applies it. An invalid
+// configuration is rejected whole.
+func (r *Reloader) Reload() error {
+	next, err := Load()
+	if err != nil {
+		return err
+	}
+
+	level, err := zap.ParseAtomicLevel(next.LogLevel)
+	if err != nil {
+		return fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if changed := r.running.RestartRequired(next); len(changed) > 0 {
+		r.logger.Warn("configuration changes ignored until restart", zap.Strings("settings", changed))
+	}
+
+	r.level.SetLevel(level.Level())
+	for _, apply := range r.apply {
+		apply(next)
+	}
+
+	r.logger.Info("configuration reloaded", zap.String("log_level", next.LogLevel))
+	return nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// setRequiredEnv sets the settings Load requires.
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("ASTERISK_ARI_URL", "http://localhost:8088/ari")
+	t.Setenv("ASTERISK_ARI_USERNAME", "serphona")
+	t.Setenv("ASTERISK_ARI_PASSWORD", "secret")
+	t.Setenv("TENANT_MANAGER_URL", "http://localhost:8081")
+	t.Setenv("AGENT_ORCHESTRATOR_URL", "http://localhost:8082")
+	t.Setenv("AUTH_GATEWAY_URL", "http://localhost:8080")
+	t.Setenv("SERVICE_CLIENT_ID", "voice-gateway")
+	t.Setenv("SERVICE_CLIENT_SECRET", "secret")
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("ASTERISK_WEBHOOK_SECRET", "secret")
+}
+
+func TestReloader_SIGHUPChangesLogLevel(t *testing.T) {
+	setRequiredEnv(t)
+	configFile := filepath.Join(t.TempDir(), "voice-gateway.env")
+	if err := os.WriteFile(configFile, []byte("LOG_LEVEL=info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", configFile)
+	t.Setenv("LOG_LEVEL", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+	var applied []*Config
+	reloader := NewReloader(cfg, level, zap.NewNop())
+	reloader.OnReload(func(next *Config) { applied = append(applied, next) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloader.Watch(ctx)
+
+	if err := os.WriteFile(configFile, []byte("LOG_LEVEL=debug\nMAX_CONCURRENT_CALLS=5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for level.Level() != zapcore.DebugLevel {
+		if time.Now().After(deadline) {
+			t.Fatalf("log level = %s after SIGHUP, want debug", level.Level())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reloader.mu.Lock()
+	defer reloader.mu.Unlock()
+	if len(applied) != 1 || applied[0].Call.MaxConcurrentCalls != 5 {
+		t.Errorf("reloaded config not handed to the registered components: %+v", applied)
+	}
+}
+
+func TestReloader_LogsRestartRequiredSettings(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SERVER_PORT", "8080")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	core, logs := observer.New(zapcore.WarnLevel)
+	reloader := NewReloader(cfg, zap.NewAtomicLevel(), zap.New(core))
+
+	t.Setenv("SERVER_PORT", "9999")
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	entries := logs.FilterMessage("configuration changes ignored until restart").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d restart warnings, want 1", len(entries))
+	}
+	settings, _ := entries[0].ContextMap()["settings"].([]interface{})
+	if len(settings) != 1 || settings[0] != "SERVER_PORT" {
+		t.Errorf("restart warning lists %v, want [SERVER_PORT]", entries[0].ContextMap()["settings"])
+	}
+}
+
+func TestReloader_RejectsInvalidConfiguration(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("LOG_LEVEL", "info")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	reloader := NewReloader(cfg, level, zap.NewNop())
+
+	t.Setenv("LOG_LEVEL", "loud")
+	if err := reloader.Reload(); err == nil {
+		t.Fatal("expected error for an invalid log level")
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("log level = %s, want the previous level kept", level.Level())
+	}
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/bodylimit"
+	phone "github.com/serphona/serphona/backend/go/libs/platform-phone"
+
+	"voice-gateway/internal/domain/cdr"
+)
+
+// defaultSecrets are placeholder values shipped in .env.example.
+var defaultSecrets = []string{
+	"asterisk_secret",
+	"admin_secret",
+	"your-secret-key",
+	"webhook_secret",
+	"service_secret",
+}
+
+// ValidationError lists every configuration problem found at once.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// urlSetting is a URL to validate against the accepted schemes.
+type urlSetting struct {
+	name    string
+	value   string
+	schemes []string
+}
+
+// durationSetting is a duration that must be positive.
+type durationSetting struct {
+	name  string
+	value time.Duration
+}
+
+// IsProduction reports whether the service runs in production.
+func (c *Config) IsProduction() bool {
+	env := strings.ToLower(c.Environment)
+	return env == "production" || env == "prod"
+}
+
+// Validate checks the configuration for malformed URLs and nonsensical
+// durations. In production it also rejects default or empty credentials.
+func (c *Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	urls := []urlSetting{
+		{"ASTERISK_ARI_URL", c.Asterisk.ARIURL, []string{"http", "https"}},
+		{"TENANT_MANAGER_URL", c.TenantManager.URL, []string{"http", "https"}},
+		{"AGENT_ORCHESTRATOR_URL", c.AgentOrchestrator.URL, []string{"http", "https"}},
+		{"AUTH_GATEWAY_URL", c.AgentOrchestrator.AuthURL, []string{"http", "https"}},
+		{"REDIS_URL", c.Redis.URL, []string{"redis", "rediss"}},
+	}
+	if c.Auth.JWKSURL != "" {
+		urls = append(urls, urlSetting{"JWKS_URL", c.Auth.JWKSURL, []string{"http", "https"}})
+	}
+	if c.Tracing.Enabled {
+		urls = append(urls, urlSetting{"TRACING_ENDPOINT", c.Tracing.Endpoint, []string{"http", "https"}})
+	}
+	for _, u := range urls {
+		if !validURL(u.value, u.schemes...) {
+			addf("%s must be an absolute %s URL, got %q", u.name, strings.Join(u.schemes, "/"), u.value)
+		}
+	}
+
+	durations := []durationSetting{
+		{"SERVER_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"SERVER_SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout},
+		{"TENANT_MANAGER_TIMEOUT", c.TenantManager.Timeout},
+		{"AGENT_ORCHESTRATOR_TIMEOUT", c.AgentOrchestrator.Timeout},
+		{"CALL_TIMEOUT", c.Call.CallTimeout},
+		{"SILENCE_TIMEOUT", c.Call.SilenceTimeout},
+		{"PROVIDER_TIMEOUT_STT", c.ProviderTimeout.STT},
+		{"PROVIDER_TIMEOUT_TTS", c.ProviderTimeout.TTS},
+		{"PROVIDER_TIMEOUT_AGENT", c.ProviderTimeout.Agent},
+		{"REDIS_CALL_STATE_TTL", c.Redis.CallStateTTL},
+		{"REDIS_BREAKER_COOLDOWN", c.Redis.BreakerCooldown},
+		{"VOICEMAIL_MAX_DURATION", c.Voicemail.MaxDuration},
+		{"HEALTH_CHECK_INTERVAL", c.HealthCheck.Interval},
+		{"AUDIO_FRAME_DURATION", c.Audio.FrameDuration},
+	}
+	if c.Queue.Enabled {
+		durations = append(durations, durationSetting{"QUEUE_MAX_WAIT", c.Queue.MaxWait})
+	}
+	if c.TenantManager.StatusCheck {
+		durations = append(durations, durationSetting{"TENANT_STATUS_CACHE_TTL", c.TenantManager.StatusCacheTTL})
+	}
+	if c.CDR.Enabled {
+		durations = append(durations, durationSetting{"CDR_RETENTION", c.CDR.Retention})
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			addf("%s must be a positive duration, got %s", d.name, d.value)
+		}
+	}
+
+	if c.ProviderTimeout.FallbackRetries < 0 {
+		addf("PROVIDER_FALLBACK_RETRIES must not be negative, got %d", c.ProviderTimeout.FallbackRetries)
+	}
+	if c.Call.TurnLatencyBudget < 0 {
+		addf("TURN_LATENCY_BUDGET must not be negative, got %s", c.Call.TurnLatencyBudget)
+	}
+	if c.Call.SilenceTimeout > 0 && c.Call.SilenceTimeout >= c.Call.CallTimeout {
+		addf("SILENCE_TIMEOUT (%s) must be shorter than CALL_TIMEOUT (%s)", c.Call.SilenceTimeout, c.Call.CallTimeout)
+	}
+	if c.Redis.CallStateTTL > 0 && c.Redis.CallStateTTL < c.Call.CallTimeout {
+		addf("REDIS_CALL_STATE_TTL (%s) must not be shorter than CALL_TIMEOUT (%s)", c.Redis.CallStateTTL, c.Call.CallTimeout)
+	}
+	if c.Tracing.Sampler < 0 || c.Tracing.Sampler > 1 {
+		addf("TRACING_SAMPLER must be between 0 and 1, got %g", c.Tracing.Sampler)
+	}
+	switch c.Kafka.Compression {
+	case "none", "gzip", "snappy", "lz4", "zstd":
+	default:
+		addf("KAFKA_COMPRESSION must be one of none, gzip, snappy, lz4 or zstd, got %q", c.Kafka.Compression)
+	}
+	if c.Kafka.MaxMessageBytes <= 0 {
+		addf("KAFKA_MAX_MESSAGE_BYTES must be positive, got %d", c.Kafka.MaxMessageBytes)
+	}
+	for pattern, topic := range c.Kafka.TopicGroups {
+		if pattern == "" || topic == "" || strings.Contains(strings.TrimSuffix(pattern, "*"), "*") {
+			addf("KAFKA_TOPIC_GROUPS entry %q:%q must map an event type or a family ending in * to a topic", pattern, topic)
+		}
+	}
+	if c.Redis.BreakerFailureThreshold <= 0 {
+		addf("REDIS_BREAKER_FAILURE_THRESHOLD must be positive, got %d", c.Redis.BreakerFailureThreshold)
+	}
+	if c.CDR.Enabled {
+		if _, err := cdr.ParseRounding(c.CDR.DefaultRounding); err != nil {
+			addf("CDR_DEFAULT_ROUNDING: %v", err)
+		}
+	}
+	if c.Audio.FrameSize <= 0 {
+		addf("AUDIO_FRAME_SIZE must be positive, got %d", c.Audio.FrameSize)
+	}
+	if c.Audio.JitterBufferDepth < 0 {
+		addf("AUDIO_JITTER_BUFFER_DEPTH must not be negative, got %d", c.Audio.JitterBufferDepth)
+	}
+	if c.Call.MaxConcurrentCalls <= 0 {
+		addf("MAX_CONCURRENT_CALLS must be positive, got %d", c.Call.MaxConcurrentCalls)
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		addf("MAX_REQUEST_BODY_BYTES must be positive, got %d", c.Server.MaxBodyBytes)
+	}
+	if _, err := bodylimit.ParseRoutes(c.Server.BodyLimitRoutes); err != nil {
+		addf("MAX_REQUEST_BODY_ROUTES: %v", err)
+	}
+	if c.LoadShed.MaxInFlight < 0 {
+		addf("LOAD_SHED_MAX_IN_FLIGHT must not be negative, got %d", c.LoadShed.MaxInFlight)
+	}
+	if c.LoadShed.MaxQueueWait < 0 {
+		addf("LOAD_SHED_MAX_QUEUE_WAIT must not be negative, got %s", c.LoadShed.MaxQueueWait)
+	}
+	if c.Asterisk.WebhookSecret == "" {
+		addf("ASTERISK_WEBHOOK_SECRET is required to authenticate the Asterisk webhooks")
+	}
+	if c.Auth.JWTSecret == "" && c.Auth.JWKSURL == "" {
+		addf("JWT_SECRET or JWKS_URL is required to authenticate the management API")
+	}
+	if c.AgentOrchestrator.ClientID == "" || c.AgentOrchestrator.ClientSecret == "" {
+		addf("SERVICE_CLIENT_ID and SERVICE_CLIENT_SECRET are required to authenticate to agent-orchestrator")
+	}
+	if _, ok := phone.CallingCode(c.Call.NumberRegion); !ok {
+		addf("CALL_NUMBER_REGION must be an ISO 3166-1 alpha-2 country code, got %q", c.Call.NumberRegion)
+	}
+
+	if c.IsProduction() {
+		if c.Asterisk.ARIPassword == "" || isDefaultSecret(c.Asterisk.ARIPassword) {
+			addf("ASTERISK_ARI_PASSWORD must be set to a non-default value in production")
+		}
+		if c.Auth.JWTSecret != "" && isDefaultSecret(c.Auth.JWTSecret) {
+			addf("JWT_SECRET must be set to a non-default value in production")
+		}
+		if isDefaultSecret(c.AgentOrchestrator.ClientSecret) {
+			addf("SERVICE_CLIENT_SECRET must be set to a non-default value in production")
+		}
+		if isDefaultSecret(c.Asterisk.WebhookSecret) {
+			addf("ASTERISK_WEBHOOK_SECRET must be set to a non-default value in production")
+		}
+		if c.FeatureFlags.EnableAMIFallback && (c.Asterisk.AMIPassword == "" || isDefaultSecret(c.Asterisk.AMIPassword)) {
+			addf("ASTERISK_AMI_PASSWORD must be set to a non-default value in production when AMI fallback is enabled")
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// isDefaultSecret reports whether s is one of the shipped placeholders.
+func isDefaultSecret(s string) bool {
+	for _, d := range defaultSecrets {
+		if s == d {
+			return true
+		}
+	}
+	return false
+}
+
+// validURL reports whether s parses as an absolute URL with one of schemes.
+func validURL(s string, schemes ...string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return true
+		}
+	}
+	return false
+}
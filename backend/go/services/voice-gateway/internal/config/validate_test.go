@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func baseConfig(env string) *Config {
+	return &Config{
+		Environment: env,
+		Server: ServerConfig{
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			MaxBodyBytes:    65536,
+		},
+		Auth: AuthConfig{JWTSecret: "your-secret-key"},
+		Asterisk: AsteriskConfig{
+			ARIURL:        "http://localhost:8088/ari",
+			ARIUsername:   "serphona",
+			ARIPassword:   "asterisk_secret",
+			WebhookSecret: "webhook_secret",
+		},
+		Redis:             RedisConfig{URL: "redis://localhost:6379", CallStateTTL: time.Hour, BreakerFailureThreshold: 5, BreakerCooldown: 10 * time.Second},
+		Kafka:             KafkaConfig{Compression: "snappy", MaxMessageBytes: 1000000},
+		TenantManager:     TenantManagerConfig{URL: "http://localhost:8081", Timeout: 10 * time.Second},
+		AgentOrchestrator: AgentOrchestratorConfig{
+			URL:          "http://localhost:8082",
+			Timeout:      30 * time.Second,
+			AuthURL:      "http://localhost:8080",
+			ClientID:     "voice-gateway",
+			ClientSecret: "service_secret",
+		},
+		Call: CallConfig{
+			MaxConcurrentCalls: 1000,
+			CallTimeout:        30 * time.Minute,
+			SilenceTimeout:     5 * time.Second,
+			NumberRegion:       "BR",
+		},
+		ProviderTimeout: ProviderTimeoutConfig{STT: 10 * time.Second, TTS: 5 * time.Second, Agent: 8 * time.Second},
+		Queue:           QueueConfig{Enabled: true, MaxWait: 5 * time.Minute},
+		Voicemail:       VoicemailConfig{MaxDuration: 2 * time.Minute},
+		Tracing:         TracingConfig{Enabled: true, Endpoint: "http://tempo:4317", Sampler: 1},
+		HealthCheck:     HealthCheckConfig{Interval: 30 * time.Second},
+		CDR:             CDRConfig{Enabled: true, DefaultRounding: "6/6", Retention: 8760 * time.Hour},
+		Audio:           AudioConfig{FrameSize: 160, FrameDuration: 20 * time.Millisecond, JitterBufferDepth: 3},
+	}
+}
+
+func TestValidateAllowsDefaultsInDevelopment(t *testing.T) {
+	if err := baseConfig("development").Validate(); err != nil {
+		t.Fatalf("expected development defaults to be valid, got %v", err)
+	}
+}
+
+func TestValidateRejectsDefaultCredentialsInProduction(t *testing.T) {
+	cfg := baseConfig("production")
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "ASTERISK_ARI_PASSWORD") {
+		t.Fatalf("expected default ARI password to be rejected, got %v", err)
+	}
+
+	cfg.Asterisk.ARIPassword = "a-real-password"
+	cfg.Asterisk.WebhookSecret = "a-real-webhook-secret"
+	cfg.Auth.JWTSecret = "a-real-jwt-secret"
+	cfg.AgentOrchestrator.ClientSecret = "a-real-client-secret"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid production config, got %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.TenantManager.URL = "tenant-manager:8081"
+	cfg.Redis.URL = "localhost:6379"
+	cfg.ProviderTimeout.Agent = 0
+	cfg.Tracing.Sampler = 1.5
+
+	err := cfg.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	if len(verr.Problems) != 4 {
+		t.Errorf("expected 4 problems, got %d: %v", len(verr.Problems), verr.Problems)
+	}
+	for _, want := range []string{"TENANT_MANAGER_URL", "REDIS_URL", "PROVIDER_TIMEOUT_AGENT", "TRACING_SAMPLER"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got %v", want, err)
+		}
+	}
+}
+
+func TestValidateRejectsInconsistentCallTimeouts(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Call.SilenceTimeout = time.Hour
+	cfg.Redis.CallStateTTL = 10 * time.Minute
+
+	err := cfg.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Errorf("expected silence and call-state problems, got %v", err)
+	}
+}
+
+func TestValidateRejectsUnknownKafkaCompression(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Kafka.Compression = "brotli"
+	cfg.Kafka.MaxMessageBytes = 0
+
+	err := cfg.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Fatalf("expected compression and message size problems, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "KAFKA_COMPRESSION") {
+		t.Errorf("expected error to mention KAFKA_COMPRESSION, got %v", err)
+	}
+}
+
+func TestValidateKafkaTopicGroups(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Kafka.TopicGroups = map[string]string{"call.*": "calls", "error.*": "errors"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid topic groups, got %v", err)
+	}
+
+	cfg.Kafka.TopicGroups = map[string]string{"*.started": "starts", "call.*": ""}
+	err := cfg.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Fatalf("expected a problem per bad group, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "KAFKA_TOPIC_GROUPS") {
+		t.Errorf("expected error to mention KAFKA_TOPIC_GROUPS, got %v", err)
+	}
+}
+
+func TestValidateRequiresTokenVerification(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Auth = AuthConfig{}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET or JWKS_URL") {
+		t.Fatalf("expected a missing token key source to be rejected, got %v", err)
+	}
+
+	cfg.Auth.JWKSURL = "http://auth-gateway:8080/.well-known/jwks.json"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected JWKS_URL alone to be valid, got %v", err)
+	}
+}
+
+func TestValidateRequiresWebhookSecret(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.Asterisk.WebhookSecret = ""
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "ASTERISK_WEBHOOK_SECRET") {
+		t.Fatalf("expected a missing webhook secret to be rejected, got %v", err)
+	}
+}
+
+func TestValidateRejectsInvalidCDRRounding(t *testing.T) {
+	cfg := baseConfig("development")
+	cfg.CDR.DefaultRounding = "per-minute"
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "CDR_DEFAULT_ROUNDING") {
+		t.Errorf("expected error to mention CDR_DEFAULT_ROUNDING, got %v", err)
+	}
+}
@@ -2,9 +2,27 @@
 package call
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+)
+
+// Domain errors. Reasons are the error codes returned to API clients.
+var (
+	ErrNotFound          = apperrors.NewNotFoundError("call not found").WithReason("call_not_found")
+	ErrNotActive         = apperrors.NewConflictError("call is not active").WithReason("call_not_active")
+	ErrLimitExceeded     = apperrors.NewTooManyRequestsError("concurrent call limit exceeded").WithReason("tenant_limit_exceeded")
+	ErrStreamingDisabled = apperrors.NewForbiddenError("audio streaming is disabled for the tenant").WithReason("audio_streaming_disabled")
+	ErrMonitorForbidden  = apperrors.NewForbiddenError("only supervisors of the call's tenant can monitor it").WithReason("monitor_forbidden")
+	ErrMonitorDisabled   = apperrors.NewConflictError("call monitoring is not available").WithReason("monitor_disabled")
+	ErrUnsupportedCodec  = apperrors.NewBadRequestError("the call's audio codec is not supported").WithReason("unsupported_codec")
+	ErrInvalidTransition = apperrors.NewConflictError("invalid call state transition").WithReason("invalid_call_transition")
+	ErrInvalidNumber     = apperrors.NewBadRequestError("invalid phone number").WithReason("invalid_phone_number")
+	ErrDestinationDenied = apperrors.NewForbiddenError("calls to the destination country are not allowed").WithReason("destination_not_allowed")
+	ErrCallerIDNotOwned  = apperrors.NewForbiddenError("caller ID is not a DID of the tenant").WithReason("caller_id_not_owned")
+	ErrUnknownDID        = apperrors.NewNotFoundError("the dialed number is not an enabled DID").WithReason("did_not_found")
 )
 
 // Call represents a phone call in the system.
@@ -15,10 +33,16 @@ type Call struct {
 	ChannelID      string    `json:"channel_id"` // Asterisk channel ID
 	BridgeID       string    `json:"bridge_id"`  // Asterisk bridge ID
 
+	// Legs: a call placed by transferring another links to it as its parent,
+	// and the parent lists the legs it was bridged with
+	ParentCallID   *uuid.UUID  `json:"parent_call_id,omitempty"`
+	RelatedCallIDs []uuid.UUID `json:"related_call_ids,omitempty"`
+
 	// Call details
-	Direction    Direction `json:"direction"`     // inbound, outbound
-	CallerNumber string    `json:"caller_number"` // E.164 format
-	CalleeNumber string    `json:"callee_number"` // E.164 format
+	Direction    Direction `json:"direction"`             // inbound, outbound
+	CallerNumber string    `json:"caller_number"`         // E.164 format
+	CalleeNumber string    `json:"callee_number"`         // E.164 format
+	CustomerID   string    `json:"customer_id,omitempty"` // resolved by tenant-manager when the conversation starts
 
 	// State
 	State State `json:"state"`
@@ -28,6 +52,9 @@ type Call struct {
 	DIDID   *uuid.UUID `json:"did_id,omitempty"`
 	AgentID string     `json:"agent_id"`
 
+	// Context handed to the agent, set when the conversation starts
+	Context *ConversationContext `json:"context,omitempty"`
+
 	// Providers
 	STTProvider string `json:"stt_provider"`
 	TTSProvider string `json:"tts_provider"`
@@ -58,11 +85,19 @@ const (
 	StateAnswered    State = "answered"
 	StateActive      State = "active"
 	StateHold        State = "hold"
+	StateQueued      State = "queued"
 	StateTransferred State = "transferred"
 	StateEnded       State = "ended"
 	StateError       State = "error"
 )
 
+// Abandon stages: where the caller was when they hung up before reaching an agent.
+const (
+	AbandonBeforeAnswer = "before_answer" // still ringing
+	AbandonBeforeAgent  = "before_agent"  // answered, no conversation started
+	AbandonInQueue      = "in_queue"      // waiting in the hold queue
+)
+
 // NewCall creates a new Call.
 func NewCall(tenantID uuid.UUID, direction Direction, callerNumber, calleeNumber string) *Call {
 	now := time.Now().UTC()
@@ -78,51 +113,130 @@ func NewCall(tenantID uuid.UUID, direction Direction, callerNumber, calleeNumber
 	}
 }
 
-// Answer marks the call as answered.
-func (c *Call) Answer() {
+// NewLeg creates the outbound leg that transfers the call to number. The leg
+// belongs to the same conversation, presents the tenant's number and is
+// linked to the call in both directions.
+func (c *Call) NewLeg(number string) *Call {
+	tenantNumber := c.CalleeNumber
+	if c.Direction == DirectionOutbound {
+		tenantNumber = c.CallerNumber
+	}
+
+	leg := NewCall(c.TenantID, DirectionOutbound, tenantNumber, number)
+	leg.ConversationID = c.ConversationID
+	parentID := c.ID
+	leg.ParentCallID = &parentID
+	c.RelatedCallIDs = append(c.RelatedCallIDs, leg.ID)
+	return leg
+}
+
+// transitions lists the states each state can move to. A caller can hang up
+// at any point, so every live state can end; ended and error are final.
+var transitions = map[State][]State{
+	StateRinging:     {StateAnswered, StateEnded, StateError},
+	StateAnswered:    {StateActive, StateQueued, StateTransferred, StateEnded, StateError},
+	StateQueued:      {StateAnswered, StateEnded, StateError},
+	StateActive:      {StateHold, StateTransferred, StateEnded, StateError},
+	StateHold:        {StateActive, StateTransferred, StateEnded, StateError},
+	StateTransferred: {StateEnded, StateError},
+}
+
+// CanTransition reports whether the call can move from its state to state.
+func (c *Call) CanTransition(state State) bool {
+	for _, next := range transitions[c.State] {
+		if next == state {
+			return true
+		}
+	}
+	return false
+}
+
+// transition moves the call to state, or returns ErrInvalidTransition
+// leaving it unchanged.
+func (c *Call) transition(state State) error {
+	if !c.CanTransition(state) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, c.State, state)
+	}
+	c.State = state
+	return nil
+}
+
+// Answer marks a ringing call as answered.
+func (c *Call) Answer() error {
+	if c.State != StateRinging {
+		return fmt.Errorf("%w: %s call is not ringing", ErrInvalidTransition, c.State)
+	}
+	if err := c.transition(StateAnswered); err != nil {
+		return err
+	}
 	now := time.Now().UTC()
 	c.AnsweredAt = &now
-	c.State = StateAnswered
+	return nil
 }
 
-// Activate marks the call as active (conversation started).
-func (c *Call) Activate() {
-	c.State = StateActive
+// Activate marks an answered call as active (conversation started).
+func (c *Call) Activate() error {
+	if c.State == StateHold {
+		return fmt.Errorf("%w: resume a held call instead", ErrInvalidTransition)
+	}
+	return c.transition(StateActive)
 }
 
-// Hold puts the call on hold.
-func (c *Call) Hold() {
-	c.State = StateHold
+// Hold puts an active call on hold.
+func (c *Call) Hold() error {
+	return c.transition(StateHold)
 }
 
 // Resume resumes a held call.
-func (c *Call) Resume() {
-	c.State = StateActive
+func (c *Call) Resume() error {
+	if c.State != StateHold {
+		return fmt.Errorf("%w: %s call is not on hold", ErrInvalidTransition, c.State)
+	}
+	return c.transition(StateActive)
+}
+
+// Queue places an answered call in the hold queue while waiting for capacity.
+func (c *Call) Queue() error {
+	return c.transition(StateQueued)
+}
+
+// Dequeue takes the call out of the hold queue, ready to start a conversation.
+func (c *Call) Dequeue() error {
+	if c.State != StateQueued {
+		return fmt.Errorf("%w: %s call is not queued", ErrInvalidTransition, c.State)
+	}
+	return c.transition(StateAnswered)
 }
 
 // Transfer marks the call as transferred.
-func (c *Call) Transfer() {
-	c.State = StateTransferred
+func (c *Call) Transfer() error {
+	return c.transition(StateTransferred)
 }
 
 // End ends the call.
-func (c *Call) End() {
+func (c *Call) End() error {
+	if err := c.transition(StateEnded); err != nil {
+		return err
+	}
 	now := time.Now().UTC()
 	c.EndedAt = &now
-	c.State = StateEnded
 
 	if c.AnsweredAt != nil {
 		c.Duration = now.Sub(*c.AnsweredAt)
 	}
+	return nil
 }
 
 // SetError sets the call to error state.
-func (c *Call) SetError() {
-	c.State = StateError
+func (c *Call) SetError() error {
+	if err := c.transition(StateError); err != nil {
+		return err
+	}
 	if c.EndedAt == nil {
 		now := time.Now().UTC()
 		c.EndedAt = &now
 	}
+	return nil
 }
 
 // IsActive returns true if the call is in an active state.
@@ -130,7 +244,27 @@ func (c *Call) IsActive() bool {
 	return c.State == StateActive || c.State == StateAnswered || c.State == StateHold
 }
 
+// IsQueued returns true if the call is waiting in the hold queue.
+func (c *Call) IsQueued() bool {
+	return c.State == StateQueued
+}
+
 // IsEnded returns true if the call has ended.
 func (c *Call) IsEnded() bool {
 	return c.State == StateEnded || c.State == StateError
 }
+
+// AbandonStage returns the stage at which a caller hanging up now abandons
+// the call, or false if the call already reached an agent or has ended.
+func (c *Call) AbandonStage() (string, bool) {
+	switch c.State {
+	case StateRinging:
+		return AbandonBeforeAnswer, true
+	case StateAnswered:
+		return AbandonBeforeAgent, true
+	case StateQueued:
+		return AbandonInQueue, true
+	default:
+		return "", false
+	}
+}
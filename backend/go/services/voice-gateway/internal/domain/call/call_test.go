@@ -1,6 +1,7 @@
 package call
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -113,6 +114,24 @@ func TestCall_HoldAndResume(t *testing.T) {
 	}
 }
 
+func TestCall_QueueAndDequeue(t *testing.T) {
+	call := NewCall(uuid.New(), DirectionInbound, "+5511999887766", "+5511988776655")
+	call.Answer()
+
+	call.Queue()
+	if !call.IsQueued() {
+		t.Errorf("After Queue, state should be %s, got %s", StateQueued, call.State)
+	}
+	if call.IsActive() {
+		t.Error("Queued call should not be active")
+	}
+
+	call.Dequeue()
+	if call.State != StateAnswered {
+		t.Errorf("After Dequeue, state should be %s, got %s", StateAnswered, call.State)
+	}
+}
+
 func TestCall_Transfer(t *testing.T) {
 	call := NewCall(uuid.New(), DirectionInbound, "+5511999887766", "+5511988776655")
 	call.Answer()
@@ -147,6 +166,7 @@ func TestCall_IsActive(t *testing.T) {
 		{"Active", StateActive, true},
 		{"Answered", StateAnswered, true},
 		{"Hold", StateHold, true},
+		{"Queued", StateQueued, false},
 		{"Ringing", StateRinging, false},
 		{"Ended", StateEnded, false},
 		{"Error", StateError, false},
@@ -200,3 +220,60 @@ func TestCall_Metadata(t *testing.T) {
 		t.Errorf("Expected Metadata[%s] = %v, got %v", key, value, call.Metadata[key])
 	}
 }
+
+func TestCall_IllegalTransitions(t *testing.T) {
+	methods := []struct {
+		name  string
+		apply func(*Call) error
+		legal []State // states the method may be called in
+	}{
+		{"Answer", (*Call).Answer, []State{StateRinging}},
+		{"Activate", (*Call).Activate, []State{StateAnswered}},
+		{"Hold", (*Call).Hold, []State{StateActive}},
+		{"Resume", (*Call).Resume, []State{StateHold}},
+		{"Queue", (*Call).Queue, []State{StateAnswered}},
+		{"Dequeue", (*Call).Dequeue, []State{StateQueued}},
+		{"Transfer", (*Call).Transfer, []State{StateAnswered, StateActive, StateHold}},
+		{"End", (*Call).End, []State{StateRinging, StateAnswered, StateQueued, StateActive, StateHold, StateTransferred}},
+		{"SetError", (*Call).SetError, []State{StateRinging, StateAnswered, StateQueued, StateActive, StateHold, StateTransferred}},
+	}
+	states := []State{StateRinging, StateAnswered, StateQueued, StateActive, StateHold, StateTransferred, StateEnded, StateError}
+
+	for _, m := range methods {
+		for _, from := range states {
+			legal := false
+			for _, s := range m.legal {
+				legal = legal || s == from
+			}
+			if legal {
+				continue
+			}
+
+			t.Run(m.name+" from "+string(from), func(t *testing.T) {
+				c := NewCall(uuid.New(), DirectionInbound, "+5511999887766", "+5511988776655")
+				c.State = from
+
+				err := m.apply(c)
+				if !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("Expected ErrInvalidTransition, got %v", err)
+				}
+				if c.State != from || c.AnsweredAt != nil || c.EndedAt != nil {
+					t.Errorf("Expected the call unchanged in %s, got state %s", from, c.State)
+				}
+			})
+		}
+	}
+}
+
+func TestCall_LegalTransitions(t *testing.T) {
+	c := NewCall(uuid.New(), DirectionInbound, "+5511999887766", "+5511988776655")
+
+	for _, step := range []func() error{c.Answer, c.Queue, c.Dequeue, c.Activate, c.Hold, c.Resume, c.Transfer, c.End} {
+		if err := step(); err != nil {
+			t.Fatalf("Expected a legal transition from %s, got %v", c.State, err)
+		}
+	}
+	if c.State != StateEnded {
+		t.Errorf("Expected the call to end, got state %s", c.State)
+	}
+}
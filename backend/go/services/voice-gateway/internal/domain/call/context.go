@@ -0,0 +1,54 @@
+package call
+
+import "time"
+
+// ConversationContext is what the agent knows about a call when its
+// conversation starts. It reaches the agent orchestrator as variables the
+// agent's prompts can reference, e.g. {{.caller_number}}.
+type ConversationContext struct {
+	CallerNumber    string    `json:"caller_number"` // the remote party, whichever the direction
+	DID             string    `json:"did"`           // the tenant's number on the call
+	Direction       Direction `json:"direction"`
+	TenantName      string    `json:"tenant_name,omitempty"`
+	BusinessContext string    `json:"business_context,omitempty"` // the tenant's description of its business
+	LocalTime       time.Time `json:"local_time"`                 // call start in the tenant's timezone
+	CustomerID      string    `json:"customer_id,omitempty"`
+}
+
+// NewConversationContext builds the context of a call from its parties,
+// with its start time in loc.
+func NewConversationContext(c *Call, loc *time.Location) *ConversationContext {
+	cc := &ConversationContext{
+		CallerNumber: c.CallerNumber,
+		DID:          c.CalleeNumber,
+		Direction:    c.Direction,
+		LocalTime:    c.CreatedAt.In(loc),
+		CustomerID:   c.CustomerID,
+	}
+	if c.Direction == DirectionOutbound {
+		cc.CallerNumber, cc.DID = c.CalleeNumber, c.CallerNumber
+	}
+	return cc
+}
+
+// Variables returns the context as prompt variables, leaving out the unknown
+// ones.
+func (cc *ConversationContext) Variables() map[string]string {
+	vars := map[string]string{
+		"caller_number":    cc.CallerNumber,
+		"did":              cc.DID,
+		"direction":        string(cc.Direction),
+		"tenant_name":      cc.TenantName,
+		"business_context": cc.BusinessContext,
+		"customer_id":      cc.CustomerID,
+	}
+	if !cc.LocalTime.IsZero() {
+		vars["local_time"] = cc.LocalTime.Format(time.RFC3339)
+	}
+	for name, value := range vars {
+		if value == "" {
+			delete(vars, name)
+		}
+	}
+	return vars
+}
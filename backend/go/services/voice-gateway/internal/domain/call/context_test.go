@@ -0,0 +1,55 @@
+package call
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewConversationContext(t *testing.T) {
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+
+	inbound := NewCall(uuid.New(), DirectionInbound, "+5511999887766", "+551133330000")
+	inbound.CreatedAt = time.Date(2026, 10, 16, 13, 30, 0, 0, time.UTC)
+	cc := NewConversationContext(inbound, saoPaulo)
+	if cc.CallerNumber != "+5511999887766" || cc.DID != "+551133330000" {
+		t.Errorf("Inbound: expected caller +5511999887766 on DID +551133330000, got %s on %s", cc.CallerNumber, cc.DID)
+	}
+	if got := cc.LocalTime.Format(time.RFC3339); got != "2026-10-16T10:30:00-03:00" {
+		t.Errorf("Expected the call start in the tenant's timezone, got %s", got)
+	}
+
+	outbound := NewCall(uuid.New(), DirectionOutbound, "+551133330000", "+5511999887766")
+	cc = NewConversationContext(outbound, time.UTC)
+	if cc.CallerNumber != "+5511999887766" || cc.DID != "+551133330000" {
+		t.Errorf("Outbound: expected the dialed customer on DID +551133330000, got %s on %s", cc.CallerNumber, cc.DID)
+	}
+}
+
+func TestConversationContext_Variables(t *testing.T) {
+	cc := &ConversationContext{
+		CallerNumber: "+5511999887766",
+		DID:          "+551133330000",
+		Direction:    DirectionInbound,
+		TenantName:   "Clínica Sorriso",
+		LocalTime:    time.Date(2026, 10, 16, 10, 30, 0, 0, time.FixedZone("BRT", -3*60*60)),
+	}
+
+	want := map[string]string{
+		"caller_number": "+5511999887766",
+		"did":           "+551133330000",
+		"direction":     "inbound",
+		"tenant_name":   "Clínica Sorriso",
+		"local_time":    "2026-10-16T10:30:00-03:00",
+	}
+	vars := cc.Variables()
+	if len(vars) != len(want) {
+		t.Errorf("Expected the unknown variables left out, got %v", vars)
+	}
+	for name, value := range want {
+		if vars[name] != value {
+			t.Errorf("%s = %q, want %q", name, vars[name], value)
+		}
+	}
+}
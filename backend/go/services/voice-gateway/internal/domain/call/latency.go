@@ -0,0 +1,29 @@
+package call
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TurnLatency is the speech-to-speech latency of a conversation turn: the
+// time from the caller audio reaching STT to the reply audio being ready,
+// broken down by stage.
+type TurnLatency struct {
+	TurnID uuid.UUID     // correlates the turn across its events and logs
+	STT    time.Duration // caller audio to final transcript
+	LLM    time.Duration // agent orchestrator round trip
+	TTS    time.Duration // reply text to audio
+	Total  time.Duration // the whole turn, including the work between stages
+	Budget time.Duration // zero means the turn has no budget
+}
+
+// Overhead returns the part of the turn spent outside the three stages.
+func (l TurnLatency) Overhead() time.Duration {
+	return l.Total - l.STT - l.LLM - l.TTS
+}
+
+// OverBudget reports whether the turn took longer than its budget.
+func (l TurnLatency) OverBudget() bool {
+	return l.Budget > 0 && l.Total > l.Budget
+}
@@ -0,0 +1,76 @@
+package call
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MonitorMode is how a supervisor joins a call.
+type MonitorMode string
+
+const (
+	MonitorSpy     MonitorMode = "spy"     // listen only
+	MonitorWhisper MonitorMode = "whisper" // listen and speak to the agent only
+	MonitorBarge   MonitorMode = "barge"   // listen and speak to both parties
+)
+
+// Snoop returns the ARI snoop directions for the mode on the caller's channel.
+// Audio whispered "in" is heard as if the caller spoke, so only the agent hears
+// it; "both" also plays it to the caller.
+func (m MonitorMode) Snoop() (spy, whisper string) {
+	switch m {
+	case MonitorWhisper:
+		return "both", "in"
+	case MonitorBarge:
+		return "both", "both"
+	default:
+		return "both", "none"
+	}
+}
+
+// supervisorRoles are the roles allowed to monitor calls of their tenant.
+var supervisorRoles = map[string]bool{
+	"supervisor": true,
+	"admin":      true,
+}
+
+// Supervisor identifies the user monitoring a call, as forwarded by the API
+// gateway.
+type Supervisor struct {
+	ID        string
+	TenantID  uuid.UUID
+	Role      string
+	ChannelID string // the supervisor's channel, already in the Stasis app
+}
+
+// CanMonitor returns true if the supervisor may monitor the call.
+func (s Supervisor) CanMonitor(c *Call) bool {
+	return s.ID != "" && supervisorRoles[s.Role] && s.TenantID == c.TenantID
+}
+
+// Monitor represents a supervisor session on a call.
+type Monitor struct {
+	ID                  uuid.UUID   `json:"id"`
+	CallID              uuid.UUID   `json:"call_id"`
+	TenantID            uuid.UUID   `json:"tenant_id"`
+	SupervisorID        string      `json:"supervisor_id"`
+	SupervisorChannelID string      `json:"supervisor_channel_id"`
+	Mode                MonitorMode `json:"mode"`
+	BridgeID            string      `json:"bridge_id"`
+	SnoopChannelID      string      `json:"snoop_channel_id,omitempty"` // empty when barging into the call bridge
+	StartedAt           time.Time   `json:"started_at"`
+}
+
+// NewMonitor creates a monitor session for a call.
+func NewMonitor(c *Call, supervisor Supervisor, mode MonitorMode) *Monitor {
+	return &Monitor{
+		ID:                  uuid.New(),
+		CallID:              c.ID,
+		TenantID:            c.TenantID,
+		SupervisorID:        supervisor.ID,
+		SupervisorChannelID: supervisor.ChannelID,
+		Mode:                mode,
+		StartedAt:           time.Now().UTC(),
+	}
+}
@@ -0,0 +1,89 @@
+package call
+
+// Quality represents the telephony quality measured for a call.
+type Quality struct {
+	JitterMs      float64 `json:"jitter_ms"`
+	PacketLossPct float64 `json:"packet_loss_pct"`
+	LatencyMs     float64 `json:"latency_ms"`
+	MOS           float64 `json:"mos"`
+}
+
+// QualityThresholds defines the limits beyond which call quality is considered degraded.
+type QualityThresholds struct {
+	MinMOS           float64
+	MaxJitterMs      float64
+	MaxPacketLossPct float64
+	MaxLatencyMs     float64
+}
+
+// NewQuality builds a Quality from raw measurements and estimates its MOS.
+func NewQuality(jitterMs, packetLossPct, latencyMs float64) Quality {
+	return Quality{
+		JitterMs:      jitterMs,
+		PacketLossPct: packetLossPct,
+		LatencyMs:     latencyMs,
+		MOS:           EstimateMOS(jitterMs, packetLossPct, latencyMs),
+	}
+}
+
+// EstimateMOS estimates the Mean Opinion Score (1.0 - 4.5) using a simplified
+// ITU-T G.107 E-model based on jitter, packet loss and one-way latency.
+func EstimateMOS(jitterMs, packetLossPct, latencyMs float64) float64 {
+	effectiveLatency := latencyMs + 2*jitterMs + 10
+
+	var r float64
+	if effectiveLatency < 160 {
+		r = 93.2 - effectiveLatency/40
+	} else {
+		r = 93.2 - (effectiveLatency-120)/10
+	}
+	r -= 2.5 * packetLossPct
+
+	if r < 0 {
+		return 1.0
+	}
+	if r > 100 {
+		r = 100
+	}
+
+	mos := 1 + 0.035*r + 0.000007*r*(r-60)*(100-r)
+	if mos < 1.0 {
+		mos = 1.0
+	}
+	if mos > 4.5 {
+		mos = 4.5
+	}
+	return mos
+}
+
+// Degraded returns the names of the metrics that breach the given thresholds.
+// Zero-valued thresholds are ignored.
+func (q Quality) Degraded(t QualityThresholds) []string {
+	var breached []string
+	if t.MinMOS > 0 && q.MOS < t.MinMOS {
+		breached = append(breached, "mos")
+	}
+	if t.MaxJitterMs > 0 && q.JitterMs > t.MaxJitterMs {
+		breached = append(breached, "jitter")
+	}
+	if t.MaxPacketLossPct > 0 && q.PacketLossPct > t.MaxPacketLossPct {
+		breached = append(breached, "packet_loss")
+	}
+	if t.MaxLatencyMs > 0 && q.LatencyMs > t.MaxLatencyMs {
+		breached = append(breached, "latency")
+	}
+	return breached
+}
+
+// SetQuality attaches quality measurements to the call metadata.
+func (c *Call) SetQuality(q Quality) {
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]interface{})
+	}
+	c.Metadata["quality"] = map[string]interface{}{
+		"jitter_ms":       q.JitterMs,
+		"packet_loss_pct": q.PacketLossPct,
+		"latency_ms":      q.LatencyMs,
+		"mos":             q.MOS,
+	}
+}
@@ -0,0 +1,55 @@
+package call
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEstimateMOS(t *testing.T) {
+	good := EstimateMOS(5, 0, 20)
+	if good < 4.0 || good > 4.5 {
+		t.Errorf("Expected MOS between 4.0 and 4.5 for a clean call, got %f", good)
+	}
+
+	bad := EstimateMOS(80, 10, 400)
+	if bad >= good {
+		t.Errorf("Expected degraded MOS lower than %f, got %f", good, bad)
+	}
+
+	if worst := EstimateMOS(500, 100, 2000); worst != 1.0 {
+		t.Errorf("Expected MOS floor 1.0, got %f", worst)
+	}
+}
+
+func TestQuality_Degraded(t *testing.T) {
+	thresholds := QualityThresholds{
+		MinMOS:           3.5,
+		MaxJitterMs:      30,
+		MaxPacketLossPct: 5,
+		MaxLatencyMs:     300,
+	}
+
+	if breached := NewQuality(5, 0, 20).Degraded(thresholds); len(breached) != 0 {
+		t.Errorf("Expected no breached thresholds, got %v", breached)
+	}
+
+	breached := NewQuality(50, 0, 20).Degraded(thresholds)
+	if len(breached) != 1 || breached[0] != "jitter" {
+		t.Errorf("Expected only jitter to be breached, got %v", breached)
+	}
+
+	if breached := NewQuality(50, 50, 500).Degraded(QualityThresholds{}); len(breached) != 0 {
+		t.Errorf("Zero thresholds should be ignored, got %v", breached)
+	}
+}
+
+func TestCall_SetQuality(t *testing.T) {
+	call := NewCall(uuid.New(), DirectionInbound, "+5511999887766", "+5511988776655")
+
+	call.SetQuality(NewQuality(5, 1, 20))
+
+	if _, ok := call.Metadata["quality"]; !ok {
+		t.Error("Quality should be attached to call metadata")
+	}
+}
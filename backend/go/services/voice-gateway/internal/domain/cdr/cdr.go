@@ -0,0 +1,137 @@
+// Package cdr contains the call detail record domain model.
+package cdr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"voice-gateway/internal/domain/call"
+)
+
+// Dispositions: how a call ended.
+const (
+	DispositionAnswered = "answered"  // the call was answered
+	DispositionNoAnswer = "no_answer" // the caller hung up before the call was answered
+	DispositionFailed   = "failed"    // the call ended in error before being answered
+)
+
+// ErrCallNotEnded is returned when building a record for a call still in
+// progress.
+var ErrCallNotEnded = errors.New("call has not ended")
+
+// Rounding is a billing rounding rule, written "minimum/increment" in
+// seconds: a call is billed at least Minimum seconds, then in steps of
+// Increment seconds, rounding up. "6/6" bills per 6 seconds, "60/60" per
+// minute and "60/6" a first minute, then per 6 seconds.
+type Rounding struct {
+	Minimum   int64
+	Increment int64
+}
+
+// PerSecond bills every started second.
+var PerSecond = Rounding{Minimum: 1, Increment: 1}
+
+// ParseRounding parses a rounding rule such as "6/6" or "60/60".
+func ParseRounding(s string) (Rounding, error) {
+	minimum, increment, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rounding{}, fmt.Errorf("invalid rounding rule %q: want minimum/increment in seconds, e.g. 6/6", s)
+	}
+
+	r := Rounding{}
+	var err error
+	if r.Minimum, err = strconv.ParseInt(strings.TrimSpace(minimum), 10, 64); err != nil || r.Minimum < 1 {
+		return Rounding{}, fmt.Errorf("invalid rounding rule %q: minimum must be a positive number of seconds", s)
+	}
+	if r.Increment, err = strconv.ParseInt(strings.TrimSpace(increment), 10, 64); err != nil || r.Increment < 1 {
+		return Rounding{}, fmt.Errorf("invalid rounding rule %q: increment must be a positive number of seconds", s)
+	}
+	return r, nil
+}
+
+// String returns the rule as "minimum/increment".
+func (r Rounding) String() string {
+	return fmt.Sprintf("%d/%d", r.Minimum, r.Increment)
+}
+
+// Billable returns the seconds billed for talk time d. No talk time bills
+// nothing.
+func (r Rounding) Billable(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds <= r.Minimum {
+		return r.Minimum
+	}
+	steps := (seconds - r.Minimum + r.Increment - 1) / r.Increment
+	return r.Minimum + steps*r.Increment
+}
+
+// Record is the call detail record of an ended call.
+type Record struct {
+	ID       uuid.UUID `json:"id"`
+	CallID   uuid.UUID `json:"call_id"`
+	TenantID uuid.UUID `json:"tenant_id"`
+
+	Direction    call.Direction `json:"direction"`
+	CallerNumber string         `json:"caller_number"`
+	CalleeNumber string         `json:"callee_number"`
+	DID          string         `json:"did"` // the tenant's number on the call
+	DIDID        *uuid.UUID     `json:"did_id,omitempty"`
+
+	StartTime  time.Time  `json:"start_time"`
+	AnswerTime *time.Time `json:"answer_time,omitempty"`
+	EndTime    time.Time  `json:"end_time"`
+
+	DurationSeconds int64  `json:"duration_seconds"` // start to end
+	BillableSeconds int64  `json:"billable_seconds"` // talk time, rounded by Rounding
+	Rounding        string `json:"rounding"`
+	Disposition     string `json:"disposition"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// New builds the record of an ended call, billing its talk time by rounding.
+func New(c *call.Call, rounding Rounding) (*Record, error) {
+	if c.EndedAt == nil {
+		return nil, ErrCallNotEnded
+	}
+
+	r := &Record{
+		ID:              uuid.New(),
+		CallID:          c.ID,
+		TenantID:        c.TenantID,
+		Direction:       c.Direction,
+		CallerNumber:    c.CallerNumber,
+		CalleeNumber:    c.CalleeNumber,
+		DID:             c.CalleeNumber,
+		DIDID:           c.DIDID,
+		StartTime:       c.CreatedAt,
+		AnswerTime:      c.AnsweredAt,
+		EndTime:         *c.EndedAt,
+		DurationSeconds: int64(c.EndedAt.Sub(c.CreatedAt) / time.Second),
+		Rounding:        rounding.String(),
+		CreatedAt:       time.Now().UTC(),
+	}
+	if c.Direction == call.DirectionOutbound {
+		r.DID = c.CallerNumber
+	}
+
+	switch {
+	case c.AnsweredAt != nil:
+		r.Disposition = DispositionAnswered
+		r.BillableSeconds = rounding.Billable(c.EndedAt.Sub(*c.AnsweredAt))
+	case c.State == call.StateError:
+		r.Disposition = DispositionFailed
+	default:
+		r.Disposition = DispositionNoAnswer
+	}
+	return r, nil
+}
@@ -0,0 +1,130 @@
+package cdr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"voice-gateway/internal/domain/call"
+)
+
+func TestParseRounding(t *testing.T) {
+	for _, s := range []string{"6/6", "60/60", "60/6", " 1 / 1 "} {
+		if _, err := ParseRounding(s); err != nil {
+			t.Errorf("ParseRounding(%q) failed: %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "6", "0/6", "6/0", "-1/1", "a/b"} {
+		if _, err := ParseRounding(s); err == nil {
+			t.Errorf("ParseRounding(%q) accepted an invalid rule", s)
+		}
+	}
+}
+
+func TestRounding_Billable(t *testing.T) {
+	tests := []struct {
+		rule string
+		talk time.Duration
+		want int64
+	}{
+		{"1/1", 0, 0},
+		{"1/1", 300 * time.Millisecond, 1},
+		{"1/1", 61500 * time.Millisecond, 62},
+		{"6/6", 1 * time.Second, 6},
+		{"6/6", 6 * time.Second, 6},
+		{"6/6", 7 * time.Second, 12},
+		{"6/6", 61 * time.Second, 66},
+		{"60/60", 5 * time.Second, 60},
+		{"60/60", 60 * time.Second, 60},
+		{"60/60", 61 * time.Second, 120},
+		{"60/6", 30 * time.Second, 60},
+		{"60/6", 61 * time.Second, 66},
+		{"60/6", 67 * time.Second, 72},
+	}
+
+	for _, tt := range tests {
+		rounding, err := ParseRounding(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRounding(%q) failed: %v", tt.rule, err)
+		}
+		if got := rounding.Billable(tt.talk); got != tt.want {
+			t.Errorf("%s: Billable(%s) = %d, want %d", tt.rule, tt.talk, got, tt.want)
+		}
+	}
+}
+
+func TestNew_AnsweredCall(t *testing.T) {
+	didID := uuid.New()
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	c.DIDID = &didID
+	start := c.CreatedAt
+	answered := start.Add(10 * time.Second)
+	ended := answered.Add(95*time.Second + 200*time.Millisecond)
+	c.State, c.AnsweredAt, c.EndedAt = call.StateEnded, &answered, &ended
+
+	r, err := New(c, Rounding{Minimum: 60, Increment: 6})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if r.CallID != c.ID || r.TenantID != c.TenantID || r.ID == uuid.Nil {
+		t.Errorf("Unexpected identifiers: %+v", r)
+	}
+	if r.Direction != call.DirectionInbound || r.CallerNumber != "+5511999887766" || r.CalleeNumber != "+5511988776655" {
+		t.Errorf("Unexpected parties: %s from %s to %s", r.Direction, r.CallerNumber, r.CalleeNumber)
+	}
+	if r.DID != "+5511988776655" || r.DIDID == nil || *r.DIDID != didID {
+		t.Errorf("Expected the dialed number as DID, got %s (%v)", r.DID, r.DIDID)
+	}
+	if !r.StartTime.Equal(start) || r.AnswerTime == nil || !r.AnswerTime.Equal(answered) || !r.EndTime.Equal(ended) {
+		t.Errorf("Unexpected times: start %s, answer %v, end %s", r.StartTime, r.AnswerTime, r.EndTime)
+	}
+	if r.DurationSeconds != 105 {
+		t.Errorf("DurationSeconds = %d, want 105", r.DurationSeconds)
+	}
+	if r.BillableSeconds != 96 || r.Rounding != "60/6" {
+		t.Errorf("Expected 95.2s of talk billed as 96s by 60/6, got %ds by %s", r.BillableSeconds, r.Rounding)
+	}
+	if r.Disposition != DispositionAnswered {
+		t.Errorf("Disposition = %s, want %s", r.Disposition, DispositionAnswered)
+	}
+}
+
+func TestNew_Dispositions(t *testing.T) {
+	ended := time.Now().UTC()
+
+	unanswered := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	unanswered.State, unanswered.EndedAt = call.StateEnded, &ended
+
+	failed := call.NewCall(uuid.New(), call.DirectionOutbound, "+5511988776655", "+5511999887766")
+	failed.State, failed.EndedAt = call.StateError, &ended
+
+	for _, tt := range []struct {
+		c    *call.Call
+		want string
+		did  string
+	}{
+		{unanswered, DispositionNoAnswer, "+5511988776655"},
+		{failed, DispositionFailed, "+5511988776655"}, // outbound: the caller ID is the tenant's number
+	} {
+		r, err := New(tt.c, PerSecond)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		if r.Disposition != tt.want || r.BillableSeconds != 0 || r.AnswerTime != nil {
+			t.Errorf("Expected %s with nothing billed, got %s billing %ds", tt.want, r.Disposition, r.BillableSeconds)
+		}
+		if r.DID != tt.did {
+			t.Errorf("DID = %s, want %s", r.DID, tt.did)
+		}
+	}
+}
+
+func TestNew_CallInProgress(t *testing.T) {
+	c := call.NewCall(uuid.New(), call.DirectionInbound, "+5511999887766", "+5511988776655")
+	if _, err := New(c, PerSecond); !errors.Is(err, ErrCallNotEnded) {
+		t.Errorf("Expected ErrCallNotEnded, got %v", err)
+	}
+}
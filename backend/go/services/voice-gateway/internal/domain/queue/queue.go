@@ -0,0 +1,134 @@
+// Package queue contains the call hold-queue domain model.
+package queue
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entry represents a call waiting in a queue.
+type Entry struct {
+	CallID     uuid.UUID `json:"call_id"`
+	TenantID   uuid.UUID `json:"tenant_id"`
+	ChannelID  string    `json:"channel_id"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// Wait returns how long the entry has been waiting.
+func (e Entry) Wait(now time.Time) time.Duration {
+	return now.Sub(e.EnqueuedAt)
+}
+
+// Queue is a FIFO of calls waiting for capacity within a tenant.
+type Queue struct {
+	TenantID uuid.UUID
+	entries  []Entry
+}
+
+// New creates an empty queue for a tenant.
+func New(tenantID uuid.UUID) *Queue {
+	return &Queue{TenantID: tenantID}
+}
+
+// Enqueue appends an entry and returns its 1-based position.
+// Enqueuing a call that is already queued keeps its current position.
+func (q *Queue) Enqueue(e Entry) int {
+	if pos := q.Position(e.CallID); pos > 0 {
+		return pos
+	}
+	q.entries = append(q.entries, e)
+	return len(q.entries)
+}
+
+// Dequeue removes and returns the entry at the head of the queue.
+func (q *Queue) Dequeue() (Entry, bool) {
+	if len(q.entries) == 0 {
+		return Entry{}, false
+	}
+	e := q.entries[0]
+	q.entries = q.entries[1:]
+	return e, true
+}
+
+// Peek returns the entry at the head of the queue without removing it.
+func (q *Queue) Peek() (Entry, bool) {
+	if len(q.entries) == 0 {
+		return Entry{}, false
+	}
+	return q.entries[0], true
+}
+
+// Remove removes a call from the queue, e.g. when the caller hangs up.
+func (q *Queue) Remove(callID uuid.UUID) (Entry, bool) {
+	for i, e := range q.entries {
+		if e.CallID == callID {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Position returns the 1-based position of a call, or 0 if it is not queued.
+func (q *Queue) Position(callID uuid.UUID) int {
+	for i, e := range q.entries {
+		if e.CallID == callID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Len returns the number of queued calls.
+func (q *Queue) Len() int {
+	return len(q.entries)
+}
+
+// Entries returns a copy of the queued entries in order.
+func (q *Queue) Entries() []Entry {
+	entries := make([]Entry, len(q.entries))
+	copy(entries, q.entries)
+	return entries
+}
+
+// Expire removes and returns the entries that have waited at least maxWait.
+// A zero maxWait disables expiry.
+func (q *Queue) Expire(now time.Time, maxWait time.Duration) []Entry {
+	if maxWait <= 0 {
+		return nil
+	}
+
+	var expired []Entry
+	kept := q.entries[:0]
+	for _, e := range q.entries {
+		if e.Wait(now) >= maxWait {
+			expired = append(expired, e)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	q.entries = kept
+
+	return expired
+}
+
+// Oldest returns the queue whose head has waited the longest, or nil if all
+// queues are empty. It is used to share freed capacity fairly across tenants.
+func Oldest(queues map[uuid.UUID]*Queue) *Queue {
+	var oldest *Queue
+	var oldestAt time.Time
+
+	for _, q := range queues {
+		head, ok := q.Peek()
+		if !ok {
+			continue
+		}
+		if oldest == nil || head.EnqueuedAt.Before(oldestAt) {
+			oldest = q
+			oldestAt = head.EnqueuedAt
+		}
+	}
+
+	return oldest
+}
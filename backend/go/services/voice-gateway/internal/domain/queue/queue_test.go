@@ -0,0 +1,157 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newEntry(tenantID uuid.UUID, at time.Time) Entry {
+	return Entry{
+		CallID:     uuid.New(),
+		TenantID:   tenantID,
+		ChannelID:  "channel-" + uuid.NewString(),
+		EnqueuedAt: at,
+	}
+}
+
+func TestQueue_EnqueueDequeueOrder(t *testing.T) {
+	tenantID := uuid.New()
+	q := New(tenantID)
+	now := time.Now()
+
+	first := newEntry(tenantID, now)
+	second := newEntry(tenantID, now.Add(time.Second))
+	third := newEntry(tenantID, now.Add(2*time.Second))
+
+	if pos := q.Enqueue(first); pos != 1 {
+		t.Errorf("Expected position 1, got %d", pos)
+	}
+	if pos := q.Enqueue(second); pos != 2 {
+		t.Errorf("Expected position 2, got %d", pos)
+	}
+	if pos := q.Enqueue(third); pos != 3 {
+		t.Errorf("Expected position 3, got %d", pos)
+	}
+
+	// Re-enqueueing keeps the original position
+	if pos := q.Enqueue(second); pos != 2 {
+		t.Errorf("Expected duplicate to keep position 2, got %d", pos)
+	}
+	if q.Len() != 3 {
+		t.Errorf("Expected 3 entries, got %d", q.Len())
+	}
+
+	for _, expected := range []Entry{first, second, third} {
+		e, ok := q.Dequeue()
+		if !ok {
+			t.Fatal("Expected an entry")
+		}
+		if e.CallID != expected.CallID {
+			t.Errorf("Expected call %s, got %s", expected.CallID, e.CallID)
+		}
+	}
+
+	if _, ok := q.Dequeue(); ok {
+		t.Error("Expected empty queue")
+	}
+}
+
+func TestQueue_RemoveUpdatesPositions(t *testing.T) {
+	tenantID := uuid.New()
+	q := New(tenantID)
+	now := time.Now()
+
+	first := newEntry(tenantID, now)
+	second := newEntry(tenantID, now)
+	third := newEntry(tenantID, now)
+	q.Enqueue(first)
+	q.Enqueue(second)
+	q.Enqueue(third)
+
+	if _, ok := q.Remove(first.CallID); !ok {
+		t.Fatal("Expected first call to be removed")
+	}
+	if _, ok := q.Remove(first.CallID); ok {
+		t.Error("Removing twice should fail")
+	}
+
+	if pos := q.Position(second.CallID); pos != 1 {
+		t.Errorf("Expected second call at position 1, got %d", pos)
+	}
+	if pos := q.Position(third.CallID); pos != 2 {
+		t.Errorf("Expected third call at position 2, got %d", pos)
+	}
+	if pos := q.Position(first.CallID); pos != 0 {
+		t.Errorf("Removed call should have position 0, got %d", pos)
+	}
+}
+
+func TestQueue_Expire(t *testing.T) {
+	tenantID := uuid.New()
+	q := New(tenantID)
+	now := time.Now()
+	maxWait := 5 * time.Minute
+
+	old := newEntry(tenantID, now.Add(-6*time.Minute))
+	exact := newEntry(tenantID, now.Add(-5*time.Minute))
+	recent := newEntry(tenantID, now.Add(-time.Minute))
+	q.Enqueue(old)
+	q.Enqueue(exact)
+	q.Enqueue(recent)
+
+	expired := q.Expire(now, maxWait)
+	if len(expired) != 2 {
+		t.Fatalf("Expected 2 expired entries, got %d", len(expired))
+	}
+	if expired[0].CallID != old.CallID || expired[1].CallID != exact.CallID {
+		t.Error("Expired entries should be returned in queue order")
+	}
+
+	if q.Len() != 1 {
+		t.Fatalf("Expected 1 remaining entry, got %d", q.Len())
+	}
+	if pos := q.Position(recent.CallID); pos != 1 {
+		t.Errorf("Expected recent call at position 1, got %d", pos)
+	}
+
+	// Zero max wait disables expiry
+	if expired := q.Expire(now.Add(time.Hour), 0); len(expired) != 0 {
+		t.Errorf("Expected no expiry with zero max wait, got %d", len(expired))
+	}
+}
+
+func TestOldest(t *testing.T) {
+	now := time.Now()
+
+	tenantA := uuid.New()
+	tenantB := uuid.New()
+	tenantC := uuid.New()
+
+	queues := map[uuid.UUID]*Queue{
+		tenantA: New(tenantA),
+		tenantB: New(tenantB),
+		tenantC: New(tenantC),
+	}
+	queues[tenantA].Enqueue(newEntry(tenantA, now.Add(-time.Minute)))
+	queues[tenantB].Enqueue(newEntry(tenantB, now.Add(-3*time.Minute)))
+
+	oldest := Oldest(queues)
+	if oldest == nil || oldest.TenantID != tenantB {
+		t.Fatal("Expected tenant B queue to be the oldest")
+	}
+
+	queues[tenantB].Dequeue()
+
+	oldest = Oldest(queues)
+	if oldest == nil || oldest.TenantID != tenantA {
+		t.Fatal("Expected tenant A queue after tenant B is drained")
+	}
+
+	queues[tenantA].Dequeue()
+
+	if Oldest(queues) != nil {
+		t.Error("Expected nil when all queues are empty")
+	}
+}
@@ -0,0 +1,22 @@
+package routing
+
+import "github.com/serphona/serphona/backend/go/libs/platform-core/businesshours"
+
+// Closed actions define what happens to calls received outside business hours.
+const (
+	ClosedActionHangup    = "hangup"    // play announcement and hang up
+	ClosedActionVoicemail = "voicemail" // play announcement and record voicemail
+	ClosedActionAgent     = "agent"     // route to the after-hours agent
+)
+
+// BusinessHours represents the tenant opening hours. The schedule and IsOpen
+// are shared with tenant-manager through platform-core/businesshours.
+type BusinessHours struct {
+	businesshours.Schedule
+	ClosedAction       string `json:"closed_action,omitempty"`       // hangup, voicemail, agent
+	ClosedAnnouncement string `json:"closed_announcement,omitempty"` // ARI media URI, e.g. "sound:closed"
+	AfterHoursAgentID  string `json:"after_hours_agent_id,omitempty"`
+}
+
+// TimeRange represents an opening interval within a day.
+type TimeRange = businesshours.TimeRange
@@ -0,0 +1,57 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/businesshours"
+)
+
+func weekdayHours(timezone string) BusinessHours {
+	ranges := []TimeRange{{Start: "09:00", End: "18:00"}}
+	return BusinessHours{Schedule: businesshours.Schedule{
+		Enabled:  true,
+		Timezone: timezone,
+		Weekly: map[string][]TimeRange{
+			"mon": ranges,
+			"tue": ranges,
+			"wed": ranges,
+			"thu": ranges,
+			"fri": ranges,
+		},
+		Holidays: []string{"2025-12-25"},
+	}}
+}
+
+func TestSelect_Closed(t *testing.T) {
+	settings := Settings{
+		DefaultAgentID: "default-agent",
+		BusinessHours:  weekdayHours("UTC"),
+	}
+	settings.BusinessHours.ClosedAnnouncement = "sound:closed"
+
+	// Saturday
+	at := time.Date(2025, 1, 11, 12, 0, 0, 0, time.UTC)
+
+	decision := settings.Select(Request{Time: at})
+	if !decision.Closed {
+		t.Fatal("Decision should be closed on Saturday")
+	}
+	if decision.ClosedAction != ClosedActionHangup {
+		t.Errorf("Expected default closed action %s, got %s", ClosedActionHangup, decision.ClosedAction)
+	}
+	if decision.Announcement != "sound:closed" {
+		t.Errorf("Expected announcement sound:closed, got %s", decision.Announcement)
+	}
+	if decision.AgentID != "" {
+		t.Errorf("Hangup action should not select an agent, got %s", decision.AgentID)
+	}
+
+	settings.BusinessHours.ClosedAction = ClosedActionAgent
+	settings.BusinessHours.AfterHoursAgentID = "after-hours-agent"
+
+	decision = settings.Select(Request{Time: at})
+	if decision.AgentID != "after-hours-agent" {
+		t.Errorf("Expected after-hours-agent, got %s", decision.AgentID)
+	}
+}
@@ -0,0 +1,232 @@
+// Package routing contains the agent routing domain model.
+package routing
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/serphona/serphona/backend/go/libs/platform-core/businesshours"
+)
+
+// Settings represents the tenant-configured routing settings.
+type Settings struct {
+	DefaultAgentID string        `json:"default_agent_id"`
+	Rules          []Rule        `json:"routing_rules"`
+	BusinessHours  BusinessHours `json:"business_hours"`
+}
+
+// Rule represents a routing rule. Rules are evaluated in ascending priority
+// order and the first matching rule selects the agent.
+type Rule struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Priority   int        `json:"priority"`
+	AgentID    string     `json:"agent_id"`
+	Enabled    bool       `json:"enabled"`
+	Conditions Conditions `json:"conditions"`
+}
+
+// Conditions represents the conditions a call must satisfy for a rule to match.
+// Empty conditions always match.
+type Conditions struct {
+	DIDs             []string          `json:"dids,omitempty"`              // E.164 numbers dialed
+	CallerPrefixes   []string          `json:"caller_prefixes,omitempty"`   // e.g. "+55", "+5511"
+	Weekdays         []string          `json:"weekdays,omitempty"`          // "mon", "tue", ...
+	StartTime        string            `json:"start_time,omitempty"`        // "HH:MM"
+	EndTime          string            `json:"end_time,omitempty"`          // "HH:MM" (exclusive)
+	Timezone         string            `json:"timezone,omitempty"`          // IANA name, defaults to UTC
+	CallerAttributes map[string]string `json:"caller_attributes,omitempty"` // exact match
+}
+
+// Request represents the call attributes used to select an agent.
+type Request struct {
+	DID              string
+	CallerNumber     string
+	Time             time.Time
+	CallerAttributes map[string]string
+
+	// Agent or flow the DID itself is assigned to, if any
+	Assignment Assignment
+}
+
+// Assignment is the agent or flow a DID is assigned to in tenant-manager.
+type Assignment struct {
+	AgentID string
+	FlowID  string
+	Label   string
+}
+
+// Decision represents the outcome of agent selection.
+type Decision struct {
+	AgentID  string `json:"agent_id"`
+	RuleID   string `json:"rule_id,omitempty"`
+	RuleName string `json:"rule_name,omitempty"`
+	Fallback bool   `json:"fallback"`
+
+	// Set when the DID's own assignment picked the agent or flow
+	FlowID   string `json:"flow_id,omitempty"`
+	Assigned bool   `json:"assigned,omitempty"`
+
+	// Set when the call arrives outside business hours
+	Closed       bool   `json:"closed"`
+	ClosedAction string `json:"closed_action,omitempty"`
+	Announcement string `json:"announcement,omitempty"`
+}
+
+// Select returns the agent the DID is assigned to, if any, and otherwise
+// evaluates the rules in priority order and returns the first match, falling
+// back to the default agent when no rule matches. A flow assigned to the DID
+// is kept whichever agent is picked. Calls outside business hours are
+// resolved according to the closed action instead.
+func (s Settings) Select(req Request) Decision {
+	if !s.BusinessHours.IsOpen(req.Time) {
+		return s.closedDecision()
+	}
+
+	assigned := req.Assignment
+	if assigned.AgentID != "" {
+		return Decision{AgentID: assigned.AgentID, FlowID: assigned.FlowID, Assigned: true}
+	}
+
+	decision := s.selectRule(req)
+	if assigned.FlowID != "" {
+		decision.FlowID, decision.Assigned = assigned.FlowID, true
+	}
+	return decision
+}
+
+// selectRule returns the agent of the first matching rule or the default
+// agent.
+func (s Settings) selectRule(req Request) Decision {
+	rules := make([]Rule, len(s.Rules))
+	copy(rules, s.Rules)
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority < rules[j].Priority
+	})
+
+	for _, rule := range rules {
+		if !rule.Enabled || rule.AgentID == "" {
+			continue
+		}
+		if rule.Conditions.Matches(req) {
+			return Decision{
+				AgentID:  rule.AgentID,
+				RuleID:   rule.ID,
+				RuleName: rule.Name,
+			}
+		}
+	}
+
+	return Decision{
+		AgentID:  s.DefaultAgentID,
+		Fallback: true,
+	}
+}
+
+// closedDecision builds the decision for calls outside business hours.
+func (s Settings) closedDecision() Decision {
+	bh := s.BusinessHours
+
+	action := bh.ClosedAction
+	if action == "" {
+		action = ClosedActionHangup
+	}
+
+	decision := Decision{
+		Closed:       true,
+		ClosedAction: action,
+		Announcement: bh.ClosedAnnouncement,
+	}
+
+	if action == ClosedActionAgent {
+		decision.AgentID = bh.AfterHoursAgentID
+		if decision.AgentID == "" {
+			decision.AgentID = s.DefaultAgentID
+			decision.Fallback = true
+		}
+	}
+
+	return decision
+}
+
+// Matches returns true if the request satisfies all conditions.
+func (c Conditions) Matches(req Request) bool {
+	if len(c.DIDs) > 0 && !containsString(c.DIDs, req.DID) {
+		return false
+	}
+
+	if len(c.CallerPrefixes) > 0 && !hasAnyPrefix(req.CallerNumber, c.CallerPrefixes) {
+		return false
+	}
+
+	for key, value := range c.CallerAttributes {
+		if req.CallerAttributes[key] != value {
+			return false
+		}
+	}
+
+	if len(c.Weekdays) > 0 || c.StartTime != "" || c.EndTime != "" {
+		if !c.matchesTime(req.Time) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesTime checks weekday and time-of-day conditions in the rule timezone.
+func (c Conditions) matchesTime(t time.Time) bool {
+	loc := time.UTC
+	if c.Timezone != "" {
+		l, err := time.LoadLocation(c.Timezone)
+		if err != nil {
+			return false
+		}
+		loc = l
+	}
+	local := t.In(loc)
+
+	if len(c.Weekdays) > 0 {
+		day := strings.ToLower(local.Weekday().String()[:3])
+		if !containsString(c.Weekdays, day) {
+			return false
+		}
+	}
+
+	minutes := local.Hour()*60 + local.Minute()
+
+	if c.StartTime != "" {
+		start, ok := businesshours.ParseClock(c.StartTime)
+		if !ok || minutes < start {
+			return false
+		}
+	}
+
+	if c.EndTime != "" {
+		end, ok := businesshours.ParseClock(c.EndTime)
+		if !ok || minutes >= end {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
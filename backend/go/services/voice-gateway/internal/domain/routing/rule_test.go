@@ -0,0 +1,162 @@
+package routing
+
+import (
+	"testing"
+	"time"
+)
+
+func testSettings() Settings {
+	return Settings{
+		DefaultAgentID: "default-agent",
+		Rules: []Rule{
+			{
+				ID:       "after-hours",
+				Name:     "After hours",
+				Priority: 20,
+				AgentID:  "after-hours-agent",
+				Enabled:  true,
+			},
+			{
+				ID:       "business-hours",
+				Name:     "Business hours",
+				Priority: 10,
+				AgentID:  "business-agent",
+				Enabled:  true,
+				Conditions: Conditions{
+					Weekdays:  []string{"mon", "tue", "wed", "thu", "fri"},
+					StartTime: "09:00",
+					EndTime:   "18:00",
+					Timezone:  "America/Sao_Paulo",
+				},
+			},
+			{
+				ID:       "sales-did",
+				Name:     "Sales line",
+				Priority: 1,
+				AgentID:  "sales-agent",
+				Enabled:  true,
+				Conditions: Conditions{
+					DIDs: []string{"+551140001000"},
+				},
+			},
+		},
+	}
+}
+
+func TestSelect_DIDRouting(t *testing.T) {
+	settings := testSettings()
+
+	// Wednesday 03:00 in São Paulo, outside business hours
+	at := time.Date(2025, 1, 8, 6, 0, 0, 0, time.UTC)
+
+	decision := settings.Select(Request{DID: "+551140001000", CallerNumber: "+5511999887766", Time: at})
+	if decision.AgentID != "sales-agent" {
+		t.Errorf("Expected sales-agent for sales DID, got %s", decision.AgentID)
+	}
+	if decision.Fallback {
+		t.Error("DID match should not be a fallback decision")
+	}
+}
+
+func TestSelect_BusinessHours(t *testing.T) {
+	settings := testSettings()
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected string
+	}{
+		{
+			name:     "weekday within hours",
+			at:       time.Date(2025, 1, 8, 13, 0, 0, 0, time.UTC), // Wed 10:00 BRT
+			expected: "business-agent",
+		},
+		{
+			name:     "weekday before opening",
+			at:       time.Date(2025, 1, 8, 11, 59, 0, 0, time.UTC), // Wed 08:59 BRT
+			expected: "after-hours-agent",
+		},
+		{
+			name:     "closing time is exclusive",
+			at:       time.Date(2025, 1, 8, 21, 0, 0, 0, time.UTC), // Wed 18:00 BRT
+			expected: "after-hours-agent",
+		},
+		{
+			name:     "weekend",
+			at:       time.Date(2025, 1, 11, 13, 0, 0, 0, time.UTC), // Sat 10:00 BRT
+			expected: "after-hours-agent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := settings.Select(Request{DID: "+551140002000", Time: tt.at})
+			if decision.AgentID != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, decision.AgentID)
+			}
+		})
+	}
+}
+
+func TestSelect_FallbackToDefault(t *testing.T) {
+	settings := Settings{
+		DefaultAgentID: "default-agent",
+		Rules: []Rule{
+			{ID: "disabled", Priority: 1, AgentID: "other-agent", Enabled: false},
+			{
+				ID:       "vip",
+				Priority: 2,
+				AgentID:  "vip-agent",
+				Enabled:  true,
+				Conditions: Conditions{
+					CallerAttributes: map[string]string{"tier": "vip"},
+				},
+			},
+		},
+	}
+
+	decision := settings.Select(Request{CallerNumber: "+5511999887766", Time: time.Now()})
+	if decision.AgentID != "default-agent" {
+		t.Errorf("Expected default-agent, got %s", decision.AgentID)
+	}
+	if !decision.Fallback {
+		t.Error("Decision should be marked as fallback")
+	}
+
+	decision = settings.Select(Request{CallerAttributes: map[string]string{"tier": "vip"}, Time: time.Now()})
+	if decision.AgentID != "vip-agent" {
+		t.Errorf("Expected vip-agent, got %s", decision.AgentID)
+	}
+}
+
+func TestSelect_DIDAssignment(t *testing.T) {
+	settings := testSettings()
+	settings.BusinessHours = weekdayHours("America/Sao_Paulo")
+	open := time.Date(2025, 1, 8, 13, 0, 0, 0, time.UTC) // Wed 10:00 BRT
+
+	// The DID's agent wins over the sales rule matching the same DID
+	decision := settings.Select(Request{
+		DID:        "+551140001000",
+		Time:       open,
+		Assignment: Assignment{AgentID: "support-agent", FlowID: "triage"},
+	})
+	if decision.AgentID != "support-agent" || decision.FlowID != "triage" || !decision.Assigned {
+		t.Errorf("Expected the assigned agent and flow, got %+v", decision)
+	}
+	if decision.RuleID != "" || decision.Fallback {
+		t.Errorf("Assigned decision should not come from a rule, got %+v", decision)
+	}
+
+	// A flow alone keeps the agent picked by the rules
+	decision = settings.Select(Request{DID: "+551140001000", Time: open, Assignment: Assignment{FlowID: "triage"}})
+	if decision.AgentID != "sales-agent" || decision.FlowID != "triage" {
+		t.Errorf("Expected sales-agent running the triage flow, got %+v", decision)
+	}
+
+	// Business hours still apply
+	closed := time.Date(2025, 1, 11, 13, 0, 0, 0, time.UTC) // Sat 10:00 BRT
+	decision = settings.Select(Request{DID: "+551140001000", Time: closed, Assignment: Assignment{AgentID: "support-agent"}})
+	if !decision.Closed || decision.AgentID != "" {
+		t.Errorf("Expected a closed decision outside business hours, got %+v", decision)
+	}
+}
@@ -0,0 +1,86 @@
+// Package voicemail contains the voicemail domain model.
+package voicemail
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "github.com/serphona/serphona/backend/go/libs/platform-errors"
+
+	"voice-gateway/internal/domain/call"
+)
+
+// Reasons a call is sent to voicemail.
+const (
+	ReasonClosed           = "closed"            // outside business hours
+	ReasonQueueTimeout     = "queue_timeout"     // max wait in the hold queue exceeded
+	ReasonAgentUnavailable = "agent_unavailable" // no agent could be selected
+)
+
+// Errors returned by the voicemail use cases.
+var (
+	ErrNotFound          = apperrors.NewNotFoundError("voicemail not found").WithReason("voicemail_not_found")
+	ErrOriginalForbidden = apperrors.NewForbiddenError("only admins of the voicemail's tenant can read its original transcript").WithReason("original_transcript_forbidden")
+	ErrNoOriginal        = apperrors.NewNotFoundError("no original transcript is kept for the voicemail").WithReason("original_transcript_not_found")
+)
+
+// recordingPrefix prefixes the Asterisk recording name of voicemails.
+const recordingPrefix = "voicemail-"
+
+// Voicemail represents a message left by a caller.
+type Voicemail struct {
+	ID           uuid.UUID `json:"id"`
+	CallID       uuid.UUID `json:"call_id"`
+	TenantID     uuid.UUID `json:"tenant_id"`
+	CallerNumber string    `json:"caller_number"`
+	CalleeNumber string    `json:"callee_number"`
+	Reason       string    `json:"reason"`
+
+	// Recording
+	RecordingName string        `json:"recording_name"` // Asterisk stored recording name
+	RecordingURI  string        `json:"recording_uri"`  // location of the stored audio
+	Format        string        `json:"format"`
+	Duration      time.Duration `json:"duration"`
+
+	// Transcript is empty when transcription is disabled or fails, and has
+	// PII replaced by placeholders when the tenant redacts it
+	Transcript string `json:"transcript,omitempty"`
+	// TranscriptOriginal is the unredacted transcript, encrypted, when the
+	// tenant keeps originals
+	TranscriptOriginal string `json:"transcript_original,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewVoicemail creates a new Voicemail for a call.
+func NewVoicemail(c *call.Call, reason, recordingName, format string, duration time.Duration) *Voicemail {
+	return &Voicemail{
+		ID:            uuid.New(),
+		CallID:        c.ID,
+		TenantID:      c.TenantID,
+		CallerNumber:  c.CallerNumber,
+		CalleeNumber:  c.CalleeNumber,
+		Reason:        reason,
+		RecordingName: recordingName,
+		Format:        format,
+		Duration:      duration,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// RecordingName returns the Asterisk recording name for a call's voicemail.
+func RecordingName(callID uuid.UUID) string {
+	return recordingPrefix + callID.String()
+}
+
+// IsVoicemailRecording returns true if the recording name belongs to a voicemail.
+func IsVoicemailRecording(name string) bool {
+	return strings.HasPrefix(name, recordingPrefix)
+}
+
+// StorageKey returns the key under which the audio is stored.
+func (v *Voicemail) StorageKey() string {
+	return fmt.Sprintf("%s/%s.%s", v.TenantID, v.ID, v.Format)
+}